@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/ohare93/juggle/internal/cli"
@@ -13,7 +12,6 @@ var version = "dev"
 func main() {
 	cli.SetVersion(version)
 	if err := cli.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ReportError(err))
 	}
 }
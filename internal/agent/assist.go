@@ -0,0 +1,13 @@
+package agent
+
+import (
+	_ "embed"
+)
+
+//go:embed assist_prompt.md
+var AssistPromptTemplate string
+
+// GetAssistPromptTemplate returns the embedded planning assistant prompt template.
+func GetAssistPromptTemplate() string {
+	return AssistPromptTemplate
+}
@@ -0,0 +1,13 @@
+package agent
+
+import (
+	_ "embed"
+)
+
+//go:embed estimate_prompt.md
+var EstimatePromptTemplate string
+
+// GetEstimatePromptTemplate returns the embedded estimation prompt template.
+func GetEstimatePromptTemplate() string {
+	return EstimatePromptTemplate
+}
@@ -0,0 +1,13 @@
+package agent
+
+import (
+	_ "embed"
+)
+
+//go:embed plan_prompt.md
+var PlanPromptTemplate string
+
+// GetPlanPromptTemplate returns the embedded planning prompt template.
+func GetPlanPromptTemplate() string {
+	return PlanPromptTemplate
+}
@@ -13,3 +13,12 @@ var PromptTemplate string
 func GetPromptTemplate() string {
 	return PromptTemplate
 }
+
+//go:embed prompt_skeleton.tmpl
+var PromptSkeleton string
+
+// GetPromptSkeleton returns the embedded default agent prompt skeleton, the
+// Go template that arranges the prompt's sections.
+func GetPromptSkeleton() string {
+	return PromptSkeleton
+}
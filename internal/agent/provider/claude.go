@@ -62,11 +62,9 @@ func (c *ClaudeProvider) Run(opts RunOptions) (*RunResult, error) {
 	return c.runHeadless(opts)
 }
 
-// runHeadless executes Claude in headless mode (-p flag, captured output)
-func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
-	result := &RunResult{}
-
-	// Build command arguments
+// args builds the command arguments shared by both modes, everything but
+// the prompt
+func (c *ClaudeProvider) args(opts RunOptions) []string {
 	args := []string{
 		"--disable-slash-commands",
 	}
@@ -88,9 +86,23 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	} else {
 		args = append(args, flag)
 	}
+	return args
+}
+
+// Describe returns the claude command a run with opts executes
+func (c *ClaudeProvider) Describe(opts RunOptions) string {
+	if opts.Mode == ModeInteractive {
+		return shellJoin("claude", c.args(opts)) + " " + promptPlaceholder(opts.Prompt)
+	}
+	return shellJoin("claude", append(c.args(opts), "-p", "-")) + " < " + promptPlaceholder(opts.Prompt)
+}
+
+// runHeadless executes Claude in headless mode (-p flag, captured output)
+func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
 
 	// Headless mode: read prompt from stdin
-	args = append(args, "-p", "-")
+	args := append(c.args(opts), "-p", "-")
 
 	// Create context with timeout if specified
 	var ctx context.Context
@@ -141,11 +153,11 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		streamOutput(stdout, &outputBuf, os.Stdout)
+		streamOutput(stdout, &outputBuf, outputWriter(opts.Stdout, os.Stdout))
 	}()
 	go func() {
 		defer wg.Done()
-		streamOutput(stderr, &outputBuf, os.Stderr)
+		streamOutput(stderr, &outputBuf, outputWriter(opts.Stderr, os.Stderr))
 	}()
 
 	// Wait for command to complete
@@ -169,7 +181,7 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	}
 
 	// Parse completion signals from output
-	parseSignals(result)
+	parseSignals(result, opts.Signals)
 
 	return result, nil
 }
@@ -178,31 +190,8 @@ func (c *ClaudeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 func (c *ClaudeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 	result := &RunResult{}
 
-	// Build command arguments
-	args := []string{
-		"--disable-slash-commands",
-	}
-
-	// Append system prompt if provided
-	if opts.SystemPrompt != "" {
-		args = append(args, "--append-system-prompt", opts.SystemPrompt)
-	}
-
-	// Set model if provided
-	if opts.Model != "" {
-		args = append(args, "--model", c.MapModel(opts.Model))
-	}
-
-	// Set permission mode
-	flag, value := c.MapPermission(opts.Permission)
-	if value != "" {
-		args = append(args, flag, value)
-	} else {
-		args = append(args, flag)
-	}
-
 	// Interactive mode: pass prompt as argument
-	args = append(args, opts.Prompt)
+	args := append(c.args(opts), opts.Prompt)
 
 	// Create context with timeout if specified
 	var ctx context.Context
@@ -249,44 +238,10 @@ func (c *ClaudeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 	return result, nil
 }
 
-// parseSignals checks the output for COMPLETE/CONTINUE/BLOCKED signals
-func parseSignals(result *RunResult) {
-	// Check for COMPLETE signal (with optional commit message)
-	// Format: <promise>COMPLETE</promise> or <promise>COMPLETE: commit message</promise>
-	if idx := strings.Index(result.Output, "<promise>COMPLETE"); idx != -1 {
-		endIdx := strings.Index(result.Output[idx:], "</promise>")
-		if endIdx != -1 {
-			result.Complete = true
-			content := result.Output[idx+len("<promise>COMPLETE"):idx+endIdx]
-			if strings.HasPrefix(content, ":") {
-				result.CommitMessage = strings.TrimSpace(content[1:])
-			}
-		}
-	}
-
-	// Check for CONTINUE signal (with optional commit message)
-	// Format: <promise>CONTINUE</promise> or <promise>CONTINUE: commit message</promise>
-	if idx := strings.Index(result.Output, "<promise>CONTINUE"); idx != -1 {
-		endIdx := strings.Index(result.Output[idx:], "</promise>")
-		if endIdx != -1 {
-			result.Continue = true
-			content := result.Output[idx+len("<promise>CONTINUE"):idx+endIdx]
-			if strings.HasPrefix(content, ":") {
-				result.CommitMessage = strings.TrimSpace(content[1:])
-			}
-		}
-	}
-
-	// Check for BLOCKED signal
-	// Format: <promise>BLOCKED: reason</promise>
-	if idx := strings.Index(result.Output, "<promise>BLOCKED:"); idx != -1 {
-		endIdx := strings.Index(result.Output[idx:], "</promise>")
-		if endIdx != -1 {
-			reason := strings.TrimSpace(result.Output[idx+len("<promise>BLOCKED:") : idx+endIdx])
-			result.Blocked = true
-			result.BlockedReason = reason
-		}
-	}
+// parseSignals checks the output for COMPLETE/CONTINUE/BLOCKED signals and
+// rate limit indicators
+func parseSignals(result *RunResult, opts SignalOptions) {
+	ApplySignals(result, opts)
 
 	// Check for rate limit indicators
 	parseRateLimit(result)
@@ -39,8 +39,12 @@ func Detect(cliOverride, projectProvider, globalProvider string) Type {
 	return TypeClaude
 }
 
-// IsAvailable checks if a provider's binary is available in PATH
+// IsAvailable checks if a provider's binary is available in PATH.
+// The HTTP, Ollama and shell providers need no binary and are always available.
 func IsAvailable(p Type) bool {
+	if p == TypeHTTP || p == TypeOllama || p == TypeShell {
+		return true
+	}
 	binary := BinaryName(p)
 	if binary == "" {
 		return false
@@ -66,6 +70,12 @@ func Get(providerType Type) Provider {
 	switch providerType {
 	case TypeOpenCode:
 		return NewOpenCodeProvider()
+	case TypeHTTP:
+		return NewHTTPProvider(HTTPConfig{})
+	case TypeOllama:
+		return NewOllamaProvider(OllamaConfig{})
+	case TypeShell:
+		return NewShellProvider(ShellConfig{})
 	case TypeClaude:
 		fallthrough
 	default:
@@ -101,5 +111,8 @@ func ValidProviders() []string {
 	return []string{
 		string(TypeClaude),
 		string(TypeOpenCode),
+		string(TypeHTTP),
+		string(TypeOllama),
+		string(TypeShell),
 	}
 }
@@ -0,0 +1,332 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTP API formats supported by HTTPProvider
+const (
+	HTTPFormatAnthropic = "anthropic" // Anthropic Messages API (default)
+	HTTPFormatOpenAI    = "openai"    // OpenAI-compatible chat completions API
+)
+
+const (
+	defaultAnthropicEndpoint = "https://api.anthropic.com/v1/messages"
+	defaultOpenAIEndpoint    = "https://api.openai.com/v1/chat/completions"
+	defaultHTTPMaxTokens     = 8192
+)
+
+// HTTPConfig configures the HTTP provider
+type HTTPConfig struct {
+	Endpoint  string            // API URL (default depends on Format)
+	Format    string            // "anthropic" (default) or "openai"
+	Model     string            // Model used when a run doesn't request one
+	Headers   map[string]string // Extra request headers; $VAR references are expanded from the environment
+	MaxTokens int               // Maximum tokens per response (default 8192)
+}
+
+// HTTPProvider implements Provider by calling an LLM HTTP API directly
+// instead of shelling out to an agent CLI. The response is streamed to
+// stdout line by line, so it shows up in the TUI's agent output like CLI
+// output does. It has no tools: the model can only answer with text.
+type HTTPProvider struct {
+	config HTTPConfig
+	client *http.Client
+	out    io.Writer
+}
+
+// NewHTTPProvider creates a new HTTP provider
+func NewHTTPProvider(config HTTPConfig) *HTTPProvider {
+	if config.Format == "" {
+		config.Format = HTTPFormatAnthropic
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = defaultAnthropicEndpoint
+		if config.Format == HTTPFormatOpenAI {
+			config.Endpoint = defaultOpenAIEndpoint
+		}
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = defaultHTTPMaxTokens
+	}
+	return &HTTPProvider{
+		config: config,
+		client: http.DefaultClient,
+		out:    os.Stdout,
+	}
+}
+
+// Type returns TypeHTTP
+func (h *HTTPProvider) Type() Type {
+	return TypeHTTP
+}
+
+// MapModel converts canonical model name to an API model ID.
+// Only the Anthropic format has known IDs; anything else passes through.
+func (h *HTTPProvider) MapModel(canonical string) string {
+	if h.config.Format != HTTPFormatAnthropic {
+		return canonical
+	}
+	switch canonical {
+	case "haiku", "small":
+		return "claude-3-5-haiku-latest"
+	case "sonnet", "medium":
+		return "claude-sonnet-4-5"
+	case "opus", "large":
+		return "claude-opus-4-5"
+	default:
+		return canonical
+	}
+}
+
+// MapPermission returns empty strings: the API can't edit files, so
+// permission modes don't apply
+func (h *HTTPProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	return "", ""
+}
+
+// Run sends the prompt to the API and streams the response. Interactive
+// mode runs the same way since there is no terminal UI to hand over to.
+func (h *HTTPProvider) Run(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+	out := outputWriter(opts.Stdout, h.out)
+
+	body, err := h.requestBody(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	// Create context with timeout if specified
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+	} else {
+		ctx = context.Background()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range h.headers() {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
+			return result, nil
+		}
+		result.ExitCode = 1
+		result.Error = fmt.Errorf("request to %s failed: %w", h.config.Endpoint, err)
+		return result, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, ScannerMaxBufSize))
+		result.Output = string(data)
+		result.ExitCode = 1
+		result.Error = fmt.Errorf("API returned %s", resp.Status)
+		fmt.Fprintln(out, strings.TrimSpace(result.Output))
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests:
+			result.RateLimited = true
+			result.RetryAfter = parseRetryAfterHeader(resp.Header.Get("Retry-After"), time.Now())
+		case resp.StatusCode >= 500:
+			// 529 and other server errors are handled like Claude's
+			// exhausted overload retries: wait, then restart the iteration
+			result.OverloadExhausted = true
+		}
+		return result, nil
+	}
+
+	var outputBuf strings.Builder
+	streamErr := h.streamResponse(resp.Body, &outputBuf, out, result)
+	result.Output = outputBuf.String()
+	if streamErr != nil && result.Error == nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			result.TimedOut = true
+			result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
+			return result, nil
+		}
+		result.ExitCode = 1
+		result.Error = fmt.Errorf("failed to read response: %w", streamErr)
+	}
+
+	ApplySignals(result, opts.Signals)
+
+	return result, nil
+}
+
+// model returns the model a run requests: the run's, else the configured
+// one, else the medium model
+func (h *HTTPProvider) model(opts RunOptions) string {
+	model := h.config.Model
+	if opts.Model != "" {
+		model = h.MapModel(opts.Model)
+	}
+	if model == "" {
+		model = h.MapModel("sonnet")
+	}
+	return model
+}
+
+// Describe returns the request a run with opts sends
+func (h *HTTPProvider) Describe(opts RunOptions) string {
+	return fmt.Sprintf("POST %s (%s format, model %s) with %s", h.config.Endpoint, h.config.Format, h.model(opts), promptPlaceholder(opts.Prompt))
+}
+
+// requestBody builds the streaming request for the configured API format
+func (h *HTTPProvider) requestBody(opts RunOptions) ([]byte, error) {
+	model := h.model(opts)
+
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+
+	if h.config.Format == HTTPFormatOpenAI {
+		messages := make([]message, 0, 2)
+		if opts.SystemPrompt != "" {
+			messages = append(messages, message{Role: "system", Content: opts.SystemPrompt})
+		}
+		messages = append(messages, message{Role: "user", Content: opts.Prompt})
+		return json.Marshal(map[string]interface{}{
+			"model":      model,
+			"stream":     true,
+			"max_tokens": h.config.MaxTokens,
+			"messages":   messages,
+		})
+	}
+
+	body := map[string]interface{}{
+		"model":      model,
+		"stream":     true,
+		"max_tokens": h.config.MaxTokens,
+		"messages":   []message{{Role: "user", Content: opts.Prompt}},
+	}
+	if opts.SystemPrompt != "" {
+		body["system"] = opts.SystemPrompt
+	}
+	return json.Marshal(body)
+}
+
+// headers returns the request headers: format defaults (API key from the
+// usual environment variable) overridden by configured headers
+func (h *HTTPProvider) headers() map[string]string {
+	headers := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "text/event-stream",
+	}
+	if h.config.Format == HTTPFormatOpenAI {
+		if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+			headers["Authorization"] = "Bearer " + key
+		}
+	} else {
+		headers["anthropic-version"] = "2023-06-01"
+		if key := os.Getenv("ANTHROPIC_API_KEY"); key != "" {
+			headers["x-api-key"] = key
+		}
+	}
+	for key, value := range h.config.Headers {
+		headers[key] = os.ExpandEnv(value)
+	}
+	return headers
+}
+
+// streamResponse reads server-sent events, writing text deltas to the
+// output as they arrive. Error events sent mid-stream are mapped onto the
+// rate limit and overload flags.
+func (h *HTTPProvider) streamResponse(body io.Reader, buf *strings.Builder, out io.Writer, result *RunResult) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, ScannerInitialBufSize), ScannerMaxBufSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Text string `json:"text"`
+			} `json:"delta"`
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Error *struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			continue // Ignore events we can't parse (e.g. keep-alives)
+		}
+
+		if event.Error != nil {
+			result.ExitCode = 1
+			result.Error = fmt.Errorf("API error: %s: %s", event.Error.Type, event.Error.Message)
+			switch event.Error.Type {
+			case "rate_limit_error":
+				result.RateLimited = true
+			case "overloaded_error", "api_error":
+				result.OverloadExhausted = true
+			}
+			break
+		}
+
+		text := event.Delta.Text
+		for _, choice := range event.Choices {
+			text += choice.Delta.Content
+		}
+		if text != "" {
+			buf.WriteString(text)
+			fmt.Fprint(out, text)
+		}
+	}
+
+	if buf.Len() > 0 && !strings.HasSuffix(buf.String(), "\n") {
+		buf.WriteString("\n")
+		fmt.Fprintln(out)
+	}
+	return scanner.Err()
+}
+
+// parseRetryAfterHeader parses a Retry-After header given in seconds or as
+// an HTTP date. Returns 0 if the header is missing or invalid.
+func parseRetryAfterHeader(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPProvider_StreamsAnthropicResponse(t *testing.T) {
+	t.Setenv("TEST_HTTP_TOKEN", "secret")
+
+	var gotBody map[string]interface{}
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Token")
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &gotBody)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, text := range []string{"Working on it\n", "<promise>COMPLETE: ", "feat: done</promise>"} {
+			delta, _ := json.Marshal(map[string]interface{}{
+				"type":  "content_block_delta",
+				"delta": map[string]string{"type": "text_delta", "text": text},
+			})
+			fmt.Fprintf(w, "event: content_block_delta\ndata: %s\n\n", delta)
+		}
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(HTTPConfig{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"X-Token": "$TEST_HTTP_TOKEN"},
+	})
+	var out strings.Builder
+	p.out = &out
+
+	result, err := p.Run(RunOptions{Prompt: "do it", Model: "opus", SystemPrompt: "be brief"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if gotHeader != "secret" {
+		t.Errorf("expected header expanded from env, got %q", gotHeader)
+	}
+	if gotBody["model"] != "claude-opus-4-5" || gotBody["system"] != "be brief" || gotBody["stream"] != true {
+		t.Errorf("unexpected request body: %v", gotBody)
+	}
+	if !result.Complete || result.CommitMessage != "feat: done" {
+		t.Errorf("expected COMPLETE signal with commit message, got %+v", result)
+	}
+	if result.Error != nil || result.RateLimited {
+		t.Errorf("expected clean run, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "Working on it\n") {
+		t.Errorf("expected streamed output, got %q", out.String())
+	}
+}
+
+func TestHTTPProvider_StreamsOpenAIResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"<promise>BLOCKED: \"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"needs a key</promise>\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(HTTPConfig{Endpoint: server.URL, Format: HTTPFormatOpenAI, Model: "gpt-test"})
+	p.out = io.Discard
+
+	result, err := p.Run(RunOptions{Prompt: "do it"})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !result.Blocked || result.BlockedReason != "needs a key" {
+		t.Errorf("expected BLOCKED signal, got %+v", result)
+	}
+}
+
+func TestHTTPProvider_MapsErrorStatuses(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		retryAfter   string
+		wantRate     bool
+		wantOverload bool
+		wantWait     time.Duration
+	}{
+		{"rate limited", http.StatusTooManyRequests, "30", true, false, 30 * time.Second},
+		{"overloaded", 529, "", false, true, 0},
+		{"server error", http.StatusBadGateway, "", false, true, 0},
+		{"bad request", http.StatusBadRequest, "", false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"type":"error"}`)
+			}))
+			defer server.Close()
+
+			p := NewHTTPProvider(HTTPConfig{Endpoint: server.URL})
+			p.out = io.Discard
+
+			result, err := p.Run(RunOptions{Prompt: "do it"})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if result.Error == nil || result.ExitCode == 0 {
+				t.Errorf("expected error result, got %+v", result)
+			}
+			if result.RateLimited != tt.wantRate || result.OverloadExhausted != tt.wantOverload {
+				t.Errorf("RateLimited=%v OverloadExhausted=%v, want %v/%v", result.RateLimited, result.OverloadExhausted, tt.wantRate, tt.wantOverload)
+			}
+			if result.RetryAfter != tt.wantWait {
+				t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package provider
+
+const defaultOllamaEndpoint = "http://localhost:11434/v1/chat/completions"
+
+// OllamaConfig configures the Ollama provider
+type OllamaConfig struct {
+	Endpoint string            // Chat completions URL (default: local Ollama server)
+	Model    string            // Model used when a size has no entry in Models
+	Models   map[string]string // Model per size: "small", "medium", "large"
+}
+
+// OllamaProvider implements Provider for local models served by Ollama.
+// It talks to Ollama's OpenAI-compatible API through HTTPProvider, mapping
+// the canonical model sizes onto locally pulled models.
+type OllamaProvider struct {
+	*HTTPProvider
+	config OllamaConfig
+}
+
+// NewOllamaProvider creates a new Ollama provider
+func NewOllamaProvider(config OllamaConfig) *OllamaProvider {
+	if config.Endpoint == "" {
+		config.Endpoint = defaultOllamaEndpoint
+	}
+	o := &OllamaProvider{config: config}
+	o.HTTPProvider = NewHTTPProvider(HTTPConfig{
+		Endpoint: config.Endpoint,
+		Format:   HTTPFormatOpenAI,
+		Model:    o.MapModel("medium"),
+	})
+	return o
+}
+
+// Type returns TypeOllama
+func (o *OllamaProvider) Type() Type {
+	return TypeOllama
+}
+
+// MapModel converts a canonical model name to a local model: the model
+// configured for its size, else the default model. Other names pass through.
+func (o *OllamaProvider) MapModel(canonical string) string {
+	var size string
+	switch canonical {
+	case "haiku", "small":
+		size = "small"
+	case "sonnet", "medium":
+		size = "medium"
+	case "opus", "large":
+		size = "large"
+	default:
+		return canonical
+	}
+	if model := o.config.Models[size]; model != "" {
+		return model
+	}
+	if o.config.Model != "" {
+		return o.config.Model
+	}
+	return canonical
+}
+
+// Run maps the requested model and sends the prompt to Ollama
+func (o *OllamaProvider) Run(opts RunOptions) (*RunResult, error) {
+	if opts.Model != "" {
+		opts.Model = o.MapModel(opts.Model)
+	}
+	return o.HTTPProvider.Run(opts)
+}
+
+// Describe returns the request a run with opts sends to Ollama
+func (o *OllamaProvider) Describe(opts RunOptions) string {
+	if opts.Model != "" {
+		opts.Model = o.MapModel(opts.Model)
+	}
+	return o.HTTPProvider.Describe(opts)
+}
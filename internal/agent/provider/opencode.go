@@ -61,12 +61,9 @@ func (o *OpenCodeProvider) Run(opts RunOptions) (*RunResult, error) {
 	return o.runHeadless(opts)
 }
 
-// runHeadless executes OpenCode in headless mode (opencode run "prompt")
-func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
-	result := &RunResult{}
-
-	// OpenCode uses: opencode run "prompt"
-	args := []string{"run"}
+// args builds the model and agent arguments shared by both modes
+func (o *OpenCodeProvider) args(opts RunOptions) []string {
+	args := []string{}
 
 	// Set model if provided
 	if opts.Model != "" {
@@ -75,10 +72,24 @@ func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 
 	// Set agent (permission mode equivalent)
 	flag, value := o.MapPermission(opts.Permission)
-	args = append(args, flag, value)
+	return append(args, flag, value)
+}
 
-	// OpenCode takes prompt as argument, not stdin
-	args = append(args, opts.Prompt)
+// Describe returns the opencode command a run with opts executes
+func (o *OpenCodeProvider) Describe(opts RunOptions) string {
+	if opts.Mode == ModeInteractive {
+		return shellJoin("opencode", append(o.args(opts), "--prompt")) + " " + promptPlaceholder(opts.Prompt)
+	}
+	return shellJoin("opencode", append([]string{"run"}, o.args(opts)...)) + " " + promptPlaceholder(opts.Prompt)
+}
+
+// runHeadless executes OpenCode in headless mode (opencode run "prompt")
+func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
+	result := &RunResult{}
+
+	// OpenCode uses: opencode run "prompt"
+	// It takes the prompt as an argument, not stdin
+	args := append(append([]string{"run"}, o.args(opts)...), opts.Prompt)
 
 	// Create context with timeout if specified
 	var ctx context.Context
@@ -117,11 +128,11 @@ func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		streamOutput(stdout, &outputBuf, os.Stdout)
+		streamOutput(stdout, &outputBuf, outputWriter(opts.Stdout, os.Stdout))
 	}()
 	go func() {
 		defer wg.Done()
-		streamOutput(stderr, &outputBuf, os.Stderr)
+		streamOutput(stderr, &outputBuf, outputWriter(opts.Stderr, os.Stderr))
 	}()
 
 	// Wait for command to complete
@@ -144,7 +155,7 @@ func (o *OpenCodeProvider) runHeadless(opts RunOptions) (*RunResult, error) {
 	}
 
 	// Parse signals - same format as Claude since the prompt instructs the LLM
-	parseSignals(result)
+	parseSignals(result, opts.Signals)
 
 	// Parse rate limits with OpenCode-specific patterns
 	o.parseRateLimit(result)
@@ -157,16 +168,7 @@ func (o *OpenCodeProvider) runInteractive(opts RunOptions) (*RunResult, error) {
 	result := &RunResult{}
 
 	// OpenCode interactive mode - no "run" subcommand
-	args := []string{}
-
-	// Set model if provided
-	if opts.Model != "" {
-		args = append(args, "--model", o.MapModel(opts.Model))
-	}
-
-	// Set agent (permission mode equivalent)
-	flag, value := o.MapPermission(opts.Permission)
-	args = append(args, flag, value)
+	args := o.args(opts)
 
 	// Pass prompt via --prompt flag
 	if opts.Prompt != "" {
@@ -1,8 +1,11 @@
 // Package provider defines the interface and implementations for AI agent backends.
-// It supports multiple agent CLIs (Claude Code, OpenCode) through a common abstraction.
+// It supports multiple agent CLIs (Claude Code, OpenCode), a direct HTTP API
+// runner, local models through Ollama and arbitrary shell commands through a
+// common abstraction.
 package provider
 
 import (
+	"io"
 	"time"
 )
 
@@ -14,6 +17,12 @@ const (
 	TypeClaude Type = "claude"
 	// TypeOpenCode is the OpenCode CLI provider
 	TypeOpenCode Type = "opencode"
+	// TypeHTTP calls an LLM HTTP API directly instead of a CLI
+	TypeHTTP Type = "http"
+	// TypeOllama runs local models through an Ollama server
+	TypeOllama Type = "ollama"
+	// TypeShell runs a configured shell command with the prompt on stdin
+	TypeShell Type = "shell"
 )
 
 // String returns the string representation
@@ -23,7 +32,7 @@ func (p Type) String() string {
 
 // IsValid returns true if the provider type is known
 func (p Type) IsValid() bool {
-	return p == TypeClaude || p == TypeOpenCode
+	return p == TypeClaude || p == TypeOpenCode || p == TypeHTTP || p == TypeOllama || p == TypeShell
 }
 
 // RunMode defines how the agent should be executed
@@ -57,6 +66,9 @@ type RunOptions struct {
 	SystemPrompt string         // optional additional system prompt
 	Model        string         // canonical model name (e.g., "opus", "sonnet", "haiku")
 	WorkingDir   string         // working directory for command execution
+	Signals      SignalOptions  // how COMPLETE/CONTINUE/BLOCKED signals are parsed from output
+	Stdout       io.Writer      // where headless output is streamed (nil = os.Stdout)
+	Stderr       io.Writer      // where headless error output is streamed (nil = os.Stderr)
 }
 
 // RunResult represents the outcome of a single agent run (provider-agnostic)
@@ -92,5 +104,22 @@ type Provider interface {
 	MapPermission(mode PermissionMode) (flag, value string)
 }
 
+// Describer is implemented by providers that can say what a run would
+// execute without running it, for agent run --dry-run
+type Describer interface {
+	// Describe returns the command or request a run with opts executes,
+	// with the prompt left out
+	Describe(opts RunOptions) string
+}
+
+// Describe returns what running p with opts would execute, or just the
+// provider type when p can't say
+func Describe(p Provider, opts RunOptions) string {
+	if d, ok := p.(Describer); ok {
+		return d.Describe(opts)
+	}
+	return p.Type().String()
+}
+
 // AutonomousSystemPrompt is appended to force autonomous operation in headless mode
 const AutonomousSystemPrompt = `CRITICAL: You are an autonomous agent. DO NOT ask questions. DO NOT summarize. DO NOT wait for confirmation. START WORKING IMMEDIATELY. Execute the workflow in prompt.md without any preamble.`
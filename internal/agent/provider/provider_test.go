@@ -1,6 +1,8 @@
 package provider
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -144,6 +146,9 @@ func TestType_IsValid(t *testing.T) {
 	}{
 		{TypeClaude, true},
 		{TypeOpenCode, true},
+		{TypeHTTP, true},
+		{TypeOllama, true},
+		{TypeShell, true},
 		{Type("invalid"), false},
 		{Type(""), false},
 	}
@@ -261,7 +266,7 @@ func TestParseSignals(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			result := &RunResult{Output: tc.output}
-			parseSignals(result)
+			parseSignals(result, SignalOptions{})
 
 			if result.Complete != tc.wantComplete {
 				t.Errorf("Complete = %v, want %v", result.Complete, tc.wantComplete)
@@ -330,13 +335,49 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestDescribe(t *testing.T) {
+	opts := RunOptions{Prompt: "do the work", Model: "medium", Permission: PermissionPlan, SystemPrompt: "Be brief"}
+	interactive := opts
+	interactive.Mode = ModeInteractive
+
+	tests := []struct {
+		name string
+		p    Provider
+		opts RunOptions
+		want string
+	}{
+		{"claude headless", NewClaudeProvider(), opts,
+			"claude --disable-slash-commands --append-system-prompt 'Be brief' --model sonnet --permission-mode plan -p - < <prompt, 11 characters>"},
+		{"claude interactive", NewClaudeProvider(), interactive,
+			"claude --disable-slash-commands --append-system-prompt 'Be brief' --model sonnet --permission-mode plan <prompt, 11 characters>"},
+		{"opencode headless", NewOpenCodeProvider(), opts,
+			"opencode run --model anthropic/claude-sonnet-4-5 --agent plan <prompt, 11 characters>"},
+		{"opencode interactive", NewOpenCodeProvider(), interactive,
+			"opencode --model anthropic/claude-sonnet-4-5 --agent plan --prompt <prompt, 11 characters>"},
+		{"shell", NewShellProvider(ShellConfig{Command: "aider --yes"}), opts,
+			"sh -c 'aider --yes' < <prompt, 11 characters>"},
+		{"http", NewHTTPProvider(HTTPConfig{}), opts,
+			"POST https://api.anthropic.com/v1/messages (anthropic format, model claude-sonnet-4-5) with <prompt, 11 characters>"},
+		{"ollama", NewOllamaProvider(OllamaConfig{Models: map[string]string{"medium": "qwen2.5-coder"}}), opts,
+			"POST http://localhost:11434/v1/chat/completions (openai format, model qwen2.5-coder) with <prompt, 11 characters>"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Describe(tc.p, tc.opts); got != tc.want {
+				t.Errorf("Describe() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
 func TestValidProviders(t *testing.T) {
 	providers := ValidProviders()
-	if len(providers) != 2 {
-		t.Fatalf("expected 2 providers, got %d", len(providers))
+	if len(providers) != 5 {
+		t.Fatalf("expected 5 providers, got %d", len(providers))
 	}
 
-	// Check both providers are present
+	// Check all providers are present
 	found := make(map[string]bool)
 	for _, p := range providers {
 		found[p] = true
@@ -348,6 +389,12 @@ func TestValidProviders(t *testing.T) {
 	if !found["opencode"] {
 		t.Error("expected 'opencode' in valid providers")
 	}
+	if !found["http"] {
+		t.Error("expected 'http' in valid providers")
+	}
+	if !found["ollama"] || !found["shell"] {
+		t.Error("expected 'ollama' and 'shell' in valid providers")
+	}
 }
 
 func TestOpenCodeProvider_ParseRateLimit(t *testing.T) {
@@ -405,3 +452,77 @@ func TestOpenCodeProvider_ParseRateLimitWithRetryAfter(t *testing.T) {
 		t.Errorf("expected RetryAfter=30s, got %v", result.RetryAfter)
 	}
 }
+
+func TestSignalCorpus(t *testing.T) {
+	tests := []struct {
+		file         string
+		wantComplete bool
+		wantContinue bool
+		wantBlocked  bool
+		wantDetail   string // Commit message or blocked reason
+		wantStrict   bool   // Whether strict detection also finds a signal
+	}{
+		{"plain_complete.txt", true, false, false, "feat: add login form validation", true},
+		{"fenced_complete.txt", true, false, false, "", true},
+		{"bold_continue.txt", false, true, false, "fix: cap retry backoff at 5 minutes", true},
+		{"inline_code_blocked.txt", false, false, true, "STRIPE_TEST_KEY is not set in the environment", true},
+		{"lowercase_complete.txt", true, false, false, "", false},
+		{"escaped_complete.txt", true, false, false, "refactor: split store into read and write paths", false},
+		{"multiline_blocked.txt", false, false, true, "unclear whether archived balls should be migrated", false},
+		{"json_complete.txt", true, false, false, "docs: document the sync command", false},
+		{"json_blocked.txt", false, false, true, "tests need a running Postgres", false},
+		{"no_signal.txt", false, false, false, "", false},
+		{"mentions_only.txt", false, false, false, "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "signals", tc.file))
+			if err != nil {
+				t.Fatalf("failed to read corpus file: %v", err)
+			}
+
+			result := &RunResult{Output: string(data)}
+			ApplySignals(result, SignalOptions{})
+			if result.Complete != tc.wantComplete {
+				t.Errorf("Complete = %v, want %v", result.Complete, tc.wantComplete)
+			}
+			if result.Continue != tc.wantContinue {
+				t.Errorf("Continue = %v, want %v", result.Continue, tc.wantContinue)
+			}
+			if result.Blocked != tc.wantBlocked {
+				t.Errorf("Blocked = %v, want %v", result.Blocked, tc.wantBlocked)
+			}
+			detail := result.CommitMessage
+			if result.Blocked {
+				detail = result.BlockedReason
+			}
+			if detail != tc.wantDetail {
+				t.Errorf("detail = %q, want %q", detail, tc.wantDetail)
+			}
+
+			if found := len(FindSignals(string(data), SignalOptions{Strict: true})) > 0; found != tc.wantStrict {
+				t.Errorf("strict found signal = %v, want %v", found, tc.wantStrict)
+			}
+		})
+	}
+}
+
+func TestFindSignals_ExtraTags(t *testing.T) {
+	output := "line one\n<signal>CONTINUE</signal>\n<promise>COMPLETE</promise>"
+
+	matches := FindSignals(output, SignalOptions{Tags: []string{"Signal"}})
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+	if matches[0].Kind != SignalContinue || matches[0].Line != 2 {
+		t.Errorf("first match = %+v, want CONTINUE on line 2", matches[0])
+	}
+	if matches[1].Kind != SignalComplete || matches[1].Line != 3 {
+		t.Errorf("second match = %+v, want COMPLETE on line 3", matches[1])
+	}
+
+	if matches := FindSignals(output, SignalOptions{}); len(matches) != 1 {
+		t.Errorf("expected only the promise tag without extra tags, got %+v", matches)
+	}
+}
@@ -15,6 +15,14 @@ const (
 	ScannerMaxBufSize = 1024 * 1024
 )
 
+// outputWriter returns w, or fallback if w is nil
+func outputWriter(w, fallback io.Writer) io.Writer {
+	if w != nil {
+		return w
+	}
+	return fallback
+}
+
 // streamOutput reads from reader and writes to both buffer and writer.
 // This is shared between providers for consistent output handling.
 func streamOutput(reader io.Reader, buf *strings.Builder, writer io.Writer) {
@@ -29,3 +37,22 @@ func streamOutput(reader io.Reader, buf *strings.Builder, writer io.Writer) {
 		fmt.Fprintln(writer, line)
 	}
 }
+
+// shellJoin renders a command line, quoting the arguments a shell would
+// split or expand
+func shellJoin(name string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`!*?&;|<>()[]{}#~") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// promptPlaceholder stands in for the prompt in a described command
+func promptPlaceholder(prompt string) string {
+	return fmt.Sprintf("<prompt, %d characters>", len(prompt))
+}
@@ -0,0 +1,212 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Shell provider signal modes
+const (
+	ShellSignalsPromise  = "promise"   // Read <promise> signals from the output (default)
+	ShellSignalsExitCode = "exit_code" // Read the outcome from the exit code
+)
+
+// Exit codes the shell provider understands in exit_code signal mode
+const (
+	ShellExitComplete = 0 // All work done
+	ShellExitBlocked  = 2 // Blocked; the last output line is the reason
+	ShellExitContinue = 3 // One ball done, more remain
+)
+
+// ShellConfig configures the shell provider
+type ShellConfig struct {
+	Command string // Run with sh -c; the prompt is written to its stdin
+	Signals string // "promise" (default) or "exit_code"
+}
+
+// ShellProvider implements Provider by running any command, so tools
+// without a dedicated provider (aider, a local script, a wrapper around
+// another API) can drive the agent loop. The command gets the prompt on
+// stdin and the run options as JUGGLE_* environment variables.
+type ShellProvider struct {
+	config ShellConfig
+	out    io.Writer
+}
+
+// NewShellProvider creates a new shell provider
+func NewShellProvider(config ShellConfig) *ShellProvider {
+	if config.Signals == "" {
+		config.Signals = ShellSignalsPromise
+	}
+	return &ShellProvider{
+		config: config,
+		out:    os.Stdout,
+	}
+}
+
+// Type returns TypeShell
+func (s *ShellProvider) Type() Type {
+	return TypeShell
+}
+
+// MapModel passes the model through; the command decides what it means
+func (s *ShellProvider) MapModel(canonical string) string {
+	return canonical
+}
+
+// MapPermission returns empty strings: the permission mode is passed to
+// the command in JUGGLE_PERMISSION instead of a flag
+func (s *ShellProvider) MapPermission(mode PermissionMode) (flag, value string) {
+	return "", ""
+}
+
+// Describe returns the command a run with opts executes
+func (s *ShellProvider) Describe(opts RunOptions) string {
+	return shellJoin("sh", []string{"-c", s.config.Command}) + " < " + promptPlaceholder(opts.Prompt)
+}
+
+// Run executes the configured command. Interactive mode runs the same way
+// with the command's output streamed to the terminal.
+func (s *ShellProvider) Run(opts RunOptions) (*RunResult, error) {
+	if strings.TrimSpace(s.config.Command) == "" {
+		return nil, fmt.Errorf("no shell command configured (set one with 'juggle config shell set --command')")
+	}
+	result := &RunResult{}
+
+	// Create context with timeout if specified
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+	} else {
+		ctx = context.Background()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.config.Command)
+	if opts.WorkingDir != "" {
+		cmd.Dir = opts.WorkingDir
+	}
+	cmd.Stdin = strings.NewReader(opts.Prompt)
+	cmd.Env = append(os.Environ(),
+		"JUGGLE_MODEL="+opts.Model,
+		"JUGGLE_PERMISSION="+string(opts.Permission),
+		"JUGGLE_SYSTEM_PROMPT="+opts.SystemPrompt,
+		"JUGGLE_MODE="+string(opts.Mode),
+	)
+
+	var outputBuf strings.Builder
+	var mu sync.Mutex
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start shell command: %w", err)
+	}
+
+	// Both streams write to one buffer, so each line is appended under the
+	// lock as it arrives, keeping the output in the order it was written
+	var wg sync.WaitGroup
+	wg.Add(2)
+	streams := []struct {
+		reader io.Reader
+		writer io.Writer
+	}{
+		{stdout, outputWriter(opts.Stdout, s.out)},
+		{stderr, outputWriter(opts.Stderr, s.out)},
+	}
+	for _, stream := range streams {
+		go func(reader io.Reader, writer io.Writer) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(reader)
+			scanner.Buffer(make([]byte, ScannerInitialBufSize), ScannerMaxBufSize)
+			for scanner.Scan() {
+				line := scanner.Text()
+				mu.Lock()
+				outputBuf.WriteString(line)
+				outputBuf.WriteString("\n")
+				fmt.Fprintln(writer, line)
+				mu.Unlock()
+			}
+		}(stream.reader, stream.writer)
+	}
+
+	// The pipes must be read to the end before Wait closes them. On a
+	// timeout they're closed here instead, since commands the shell started
+	// can outlive it and keep them open.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			stdout.Close()
+			stderr.Close()
+		case <-done:
+		}
+	}()
+	wg.Wait()
+	close(done)
+	err = cmd.Wait()
+	result.Output = outputBuf.String()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		result.TimedOut = true
+		result.Error = fmt.Errorf("iteration timed out after %v", opts.Timeout)
+		return result, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		result.ExitCode = 1
+		result.Error = fmt.Errorf("shell command failed: %w", err)
+		return result, nil
+	}
+
+	if s.config.Signals == ShellSignalsExitCode {
+		s.applyExitCode(result)
+		return result, nil
+	}
+
+	if result.ExitCode != 0 {
+		result.Error = fmt.Errorf("shell command exited with code %d", result.ExitCode)
+	}
+	parseSignals(result, opts.Signals)
+	return result, nil
+}
+
+// applyExitCode sets the signal fields from the command's exit code
+func (s *ShellProvider) applyExitCode(result *RunResult) {
+	switch result.ExitCode {
+	case ShellExitComplete:
+		result.Complete = true
+	case ShellExitContinue:
+		result.Continue = true
+	case ShellExitBlocked:
+		result.Blocked = true
+		result.BlockedReason = lastLine(result.Output)
+	default:
+		result.Error = fmt.Errorf("shell command exited with code %d", result.ExitCode)
+	}
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
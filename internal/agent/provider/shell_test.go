@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShellProvider_PromiseSignals(t *testing.T) {
+	p := NewShellProvider(ShellConfig{
+		Command: `read prompt; echo "got: $prompt ($JUGGLE_MODEL)"; echo "<promise>COMPLETE: feat: shell</promise>"`,
+	})
+	var out strings.Builder
+	p.out = &out
+
+	result, err := p.Run(RunOptions{Prompt: "do it", Model: "haiku", WorkingDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !result.Complete || result.CommitMessage != "feat: shell" || result.Error != nil {
+		t.Errorf("expected COMPLETE signal with commit message, got %+v", result)
+	}
+	if !strings.Contains(out.String(), "got: do it (haiku)") {
+		t.Errorf("expected prompt and model passed to the command, got %q", out.String())
+	}
+}
+
+func TestShellProvider_ExitCodeSignals(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		check   func(*RunResult) bool
+	}{
+		{"complete", "exit 0", func(r *RunResult) bool { return r.Complete && r.Error == nil }},
+		{"continue", "exit 3", func(r *RunResult) bool { return r.Continue && r.Error == nil }},
+		{"blocked", "echo working; echo needs a token; exit 2", func(r *RunResult) bool {
+			return r.Blocked && r.BlockedReason == "needs a token"
+		}},
+		{"failed", "exit 1", func(r *RunResult) bool { return r.Error != nil && r.ExitCode == 1 }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewShellProvider(ShellConfig{Command: tc.command, Signals: ShellSignalsExitCode})
+			p.out = io.Discard
+			result, err := p.Run(RunOptions{Prompt: "x"})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if !tc.check(result) {
+				t.Errorf("unexpected result: %+v", result)
+			}
+		})
+	}
+}
+
+func TestShellProvider_Timeout(t *testing.T) {
+	p := NewShellProvider(ShellConfig{Command: "sleep 5"})
+	p.out = io.Discard
+	result, err := p.Run(RunOptions{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !result.TimedOut {
+		t.Errorf("expected a timeout, got %+v", result)
+	}
+}
+
+func TestShellProvider_NoCommand(t *testing.T) {
+	if _, err := NewShellProvider(ShellConfig{}).Run(RunOptions{}); err == nil {
+		t.Error("expected an error without a command")
+	}
+}
+
+func TestOllamaProvider_MapModel(t *testing.T) {
+	p := NewOllamaProvider(OllamaConfig{
+		Model:  "llama3.1",
+		Models: map[string]string{"large": "qwen2.5-coder:32b"},
+	})
+	tests := map[string]string{
+		"opus":       "qwen2.5-coder:32b",
+		"large":      "qwen2.5-coder:32b",
+		"sonnet":     "llama3.1",
+		"haiku":      "llama3.1",
+		"mistral:7b": "mistral:7b",
+	}
+	for canonical, want := range tests {
+		if got := p.MapModel(canonical); got != want {
+			t.Errorf("MapModel(%q) = %q, want %q", canonical, got, want)
+		}
+	}
+	if p.Type() != TypeOllama {
+		t.Errorf("Type() = %v, want TypeOllama", p.Type())
+	}
+}
@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultSignalTag is the tag agents wrap signals in, e.g. <promise>COMPLETE</promise>
+const DefaultSignalTag = "promise"
+
+// SignalKind is the kind of promise signal an agent emitted
+type SignalKind string
+
+const (
+	// SignalComplete means the agent finished all its work
+	SignalComplete SignalKind = "COMPLETE"
+	// SignalContinue means one ball is done and more remain
+	SignalContinue SignalKind = "CONTINUE"
+	// SignalBlocked means the agent cannot proceed
+	SignalBlocked SignalKind = "BLOCKED"
+)
+
+// Signal forms reported in SignalMatch.Form
+const (
+	SignalFormTag  = "tag"  // <promise>KEYWORD[: detail]</promise>
+	SignalFormJSON = "json" // {"promise": "KEYWORD", "message": "..."}
+)
+
+// SignalOptions configures how promise signals are found in agent output.
+// The zero value tolerantly parses <promise> tags.
+type SignalOptions struct {
+	Strict bool     // Only accept the exact, case-sensitive <promise>KEYWORD</promise> form
+	Tags   []string // Extra tag names accepted alongside "promise"
+}
+
+// SignalMatch is one signal found in agent output
+type SignalMatch struct {
+	Kind   SignalKind `json:"kind"`
+	Detail string     `json:"detail,omitempty"` // Commit message, or the reason for BLOCKED
+	Line   int        `json:"line"`             // Line the signal starts on (1-based)
+	Form   string     `json:"form"`             // SignalFormTag or SignalFormJSON
+	Raw    string     `json:"raw"`              // Signal as it appeared in the output
+
+	offset int
+}
+
+// signalFormatting is markdown that agents wrap around or inside signals
+const signalFormatting = "*_`~"
+
+// signalDetailKeys are the JSON keys read for a signal's detail, in order
+var signalDetailKeys = []string{"message", "commit_message", "commit", "reason"}
+
+// markupUnescaper undoes markdown and HTML escaping of the tag brackets
+var markupUnescaper = strings.NewReplacer(`\<`, "<", `\>`, ">", "&lt;", "<", "&gt;", ">")
+
+// tags returns the accepted tag names, the default first
+func (o SignalOptions) tags() []string {
+	tags := []string{DefaultSignalTag}
+	seen := map[string]bool{DefaultSignalTag: true}
+	for _, tag := range o.Tags {
+		tag = strings.TrimSpace(tag)
+		if !o.Strict {
+			tag = strings.ToLower(tag)
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// FindSignals returns every signal in output, in order of appearance.
+//
+// Unless opts.Strict is set, tags are matched case-insensitively, may be
+// escaped or wrapped in markdown (code fences, bold, inline code), and the
+// JSON form {"promise": "COMPLETE", "message": "..."} is also accepted.
+func FindSignals(output string, opts SignalOptions) []SignalMatch {
+	var matches []SignalMatch
+	if opts.Strict {
+		matches = findStrictSignals(output, opts.tags())
+	} else {
+		output = markupUnescaper.Replace(output)
+		matches = append(findTagSignals(output, opts.tags()), findJSONSignals(output, opts.tags())...)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].offset < matches[j].offset
+	})
+	for i := range matches {
+		matches[i].Line = strings.Count(output[:matches[i].offset], "\n") + 1
+	}
+	return matches
+}
+
+// ApplySignals sets the signal fields of result from its output and returns
+// the matches found. The first signal of each kind wins.
+func ApplySignals(result *RunResult, opts SignalOptions) []SignalMatch {
+	matches := FindSignals(result.Output, opts)
+	seen := make(map[SignalKind]bool)
+	for _, match := range matches {
+		if seen[match.Kind] {
+			continue
+		}
+		seen[match.Kind] = true
+
+		switch match.Kind {
+		case SignalComplete:
+			result.Complete = true
+			if match.Detail != "" {
+				result.CommitMessage = match.Detail
+			}
+		case SignalContinue:
+			result.Continue = true
+			if match.Detail != "" {
+				result.CommitMessage = match.Detail
+			}
+		case SignalBlocked:
+			result.Blocked = true
+			result.BlockedReason = match.Detail
+		}
+	}
+	return matches
+}
+
+// findStrictSignals matches only the exact <tag>KEYWORD[: detail]</tag> form
+func findStrictSignals(output string, tags []string) []SignalMatch {
+	var matches []SignalMatch
+	for _, tag := range tags {
+		pattern := regexp.MustCompile(`(?s)<` + regexp.QuoteMeta(tag) + `>(COMPLETE|CONTINUE|BLOCKED)(.*?)</` + regexp.QuoteMeta(tag) + `>`)
+		for _, loc := range pattern.FindAllStringSubmatchIndex(output, -1) {
+			kind := SignalKind(output[loc[2]:loc[3]])
+			rest := output[loc[4]:loc[5]]
+			hasDetail := strings.HasPrefix(rest, ":")
+			if kind == SignalBlocked && !hasDetail {
+				continue
+			}
+			match := SignalMatch{Kind: kind, Form: SignalFormTag, Raw: output[loc[0]:loc[1]], offset: loc[0]}
+			if hasDetail {
+				match.Detail = strings.TrimSpace(rest[1:])
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// findTagSignals matches <tag>...</tag> case-insensitively, allowing
+// whitespace inside the brackets and markdown around the keyword
+func findTagSignals(output string, tags []string) []SignalMatch {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = regexp.QuoteMeta(tag)
+	}
+	alternatives := strings.Join(names, "|")
+	pattern := regexp.MustCompile(`(?is)<\s*(?:` + alternatives + `)\s*>(.*?)<\s*/\s*(?:` + alternatives + `)\s*>`)
+
+	var matches []SignalMatch
+	for _, loc := range pattern.FindAllStringSubmatchIndex(output, -1) {
+		kind, detail, ok := parseSignalText(output[loc[2]:loc[3]])
+		if !ok {
+			continue
+		}
+		matches = append(matches, SignalMatch{
+			Kind:   kind,
+			Detail: detail,
+			Form:   SignalFormTag,
+			Raw:    output[loc[0]:loc[1]],
+			offset: loc[0],
+		})
+	}
+	return matches
+}
+
+// jsonSignalPattern finds flat JSON objects; the tag key is checked after decoding
+var jsonSignalPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// findJSONSignals matches flat JSON objects with a tag key, such as
+// {"promise": "BLOCKED", "reason": "needs an API key"}
+func findJSONSignals(output string, tags []string) []SignalMatch {
+	var matches []SignalMatch
+	for _, loc := range jsonSignalPattern.FindAllStringIndex(output, -1) {
+		raw := output[loc[0]:loc[1]]
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+			continue
+		}
+
+		value, ok := jsonString(fields, tags...)
+		if !ok {
+			continue
+		}
+		kind, detail, ok := parseSignalText(value)
+		if !ok {
+			continue
+		}
+		if extra, ok := jsonString(fields, signalDetailKeys...); ok && strings.TrimSpace(extra) != "" {
+			detail = strings.TrimSpace(extra)
+		}
+		matches = append(matches, SignalMatch{
+			Kind:   kind,
+			Detail: detail,
+			Form:   SignalFormJSON,
+			Raw:    raw,
+			offset: loc[0],
+		})
+	}
+	return matches
+}
+
+// jsonString returns the first string value whose key matches one of keys,
+// ignoring case
+func jsonString(fields map[string]any, keys ...string) (string, bool) {
+	for _, key := range keys {
+		for name, value := range fields {
+			if !strings.EqualFold(name, key) {
+				continue
+			}
+			if s, ok := value.(string); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseSignalText parses "KEYWORD" or "KEYWORD: detail", ignoring case and
+// surrounding markdown. BLOCKED is accepted without a reason.
+func parseSignalText(text string) (SignalKind, string, bool) {
+	text = unwrapFormatting(text)
+	keyword, rest, hasDetail := strings.Cut(text, ":")
+	keyword = strings.Trim(keyword, signalFormatting+" \t")
+
+	var kind SignalKind
+	switch strings.ToUpper(keyword) {
+	case string(SignalComplete):
+		kind = SignalComplete
+	case string(SignalContinue):
+		kind = SignalContinue
+	case string(SignalBlocked):
+		kind = SignalBlocked
+	default:
+		return "", "", false
+	}
+
+	if !hasDetail {
+		return kind, "", true
+	}
+	// Drop markup closing the keyword, as in "**BLOCKED:** reason"
+	detail := strings.TrimLeft(rest, "*_~ \t")
+	return kind, strings.TrimSpace(detail), true
+}
+
+// unwrapFormatting strips whitespace and matching markdown from both ends,
+// so "**COMPLETE**", "`COMPLETE`" and a fenced COMPLETE all become COMPLETE
+func unwrapFormatting(text string) string {
+	text = strings.TrimSpace(text)
+	for len(text) > 1 && text[0] == text[len(text)-1] && strings.IndexByte(signalFormatting, text[0]) != -1 {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+	return text
+}
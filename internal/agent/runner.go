@@ -77,6 +77,19 @@ func (r *ProviderRunner) Run(opts RunOptions) (*RunResult, error) {
 	return p.Run(opts)
 }
 
+// Describe returns the command Run executes for opts, with the model
+// overrides applied, without running it
+func (r *ProviderRunner) Describe(opts RunOptions) string {
+	p := r.Provider
+	if p == nil {
+		p = provider.NewClaudeProvider()
+	}
+	if opts.Model != "" && r.ModelOverrides != nil {
+		opts.Model = provider.ApplyModelOverrides(opts.Model, r.ModelOverrides, p)
+	}
+	return provider.Describe(p, opts)
+}
+
 // DefaultRunner is the package-level runner used for agent operations.
 // It uses Claude by default but can be configured to use other providers.
 var DefaultRunner Runner = &ProviderRunner{
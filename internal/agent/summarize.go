@@ -0,0 +1,13 @@
+package agent
+
+import (
+	_ "embed"
+)
+
+//go:embed summarize_prompt.md
+var SummarizePromptTemplate string
+
+// GetSummarizePromptTemplate returns the embedded context summarization prompt template.
+func GetSummarizePromptTemplate() string {
+	return SummarizePromptTemplate
+}
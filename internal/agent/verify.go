@@ -0,0 +1,13 @@
+package agent
+
+import (
+	_ "embed"
+)
+
+//go:embed verify_prompt.md
+var VerifyPromptTemplate string
+
+// GetVerifyPromptTemplate returns the embedded verification prompt template.
+func GetVerifyPromptTemplate() string {
+	return VerifyPromptTemplate
+}
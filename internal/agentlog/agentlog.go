@@ -0,0 +1,263 @@
+// Package agentlog writes agent output to log files as it streams, and reads
+// those logs back a page at a time.
+//
+// Each agent run gets its own timestamped log under .juggle/agent-logs/.
+// A log that grows past the size limit is rotated: the current file is
+// renamed to <name>.1 (shifting older parts up) and a fresh file started,
+// keeping at most MaxFiles rotated parts. Logs older than the retention
+// period are removed when a new log is created.
+package agentlog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirName is the directory under .juggle that holds agent logs
+const DirName = "agent-logs"
+
+// Defaults used when an Options field is zero
+const (
+	DefaultMaxSize   = 10 * 1024 * 1024    // Rotate after 10MB
+	DefaultMaxFiles  = 5                   // Rotated parts kept per run
+	DefaultRetention = 30 * 24 * time.Hour // Logs removed after 30 days
+)
+
+// maxPartialLine is how much of an unfinished line is held back before
+// it's written anyway
+const maxPartialLine = 1024 * 1024
+
+// Options configures rotation and retention
+type Options struct {
+	MaxSize   int64                    // Bytes written before rotating (0 = DefaultMaxSize)
+	MaxFiles  int                      // Rotated parts kept per run (0 = DefaultMaxFiles)
+	Retention time.Duration            // How long logs are kept (0 = DefaultRetention)
+	Filter    func(line string) string // Applied to each line before it's written, e.g. to scrub secrets
+}
+
+func (o Options) maxSize() int64 {
+	if o.MaxSize > 0 {
+		return o.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+func (o Options) maxFiles() int {
+	if o.MaxFiles > 0 {
+		return o.MaxFiles
+	}
+	return DefaultMaxFiles
+}
+
+func (o Options) retention() time.Duration {
+	if o.Retention > 0 {
+		return o.Retention
+	}
+	return DefaultRetention
+}
+
+// Writer is an io.WriteCloser that appends to a run's log, rotating it by
+// size. Output is written a line at a time so Filter sees whole lines;
+// a trailing partial line is written on Close. Safe for concurrent use, so
+// stdout and stderr can share one Writer.
+type Writer struct {
+	mu      sync.Mutex
+	path    string
+	opts    Options
+	file    *os.File
+	size    int64
+	partial []byte
+}
+
+// Create starts a new log in dir for a run of sessionID started at start,
+// first removing logs older than the retention period
+func Create(dir, sessionID string, start time.Time, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create agent log directory: %w", err)
+	}
+	if err := Prune(dir, start.Add(-opts.retention())); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s-%s.log", sessionID, start.Format("20060102-150405.000"))
+	path := filepath.Join(dir, name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create agent log: %w", err)
+	}
+	return &Writer{path: path, opts: opts, file: file, size: info.Size()}, nil
+}
+
+// Path returns the path of the log's current (newest) file
+func (w *Writer) Path() string {
+	return w.path
+}
+
+// Write appends p to the log, writing complete lines and holding back any
+// partial line until it's finished
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.partial[:i])
+		w.partial = w.partial[i+1:]
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	// Don't hold back a runaway line forever
+	if len(w.partial) > maxPartialLine {
+		line := string(w.partial)
+		w.partial = nil
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes any partial line and closes the log
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	var err error
+	if len(w.partial) > 0 {
+		err = w.writeLine(string(w.partial))
+		w.partial = nil
+	}
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	return err
+}
+
+// writeLine writes one line, rotating first if it would take the file over
+// the size limit. A single line longer than the limit still goes in whole.
+func (w *Writer) writeLine(line string) error {
+	if w.opts.Filter != nil {
+		line = w.opts.Filter(line)
+	}
+	data := line + "\n"
+	if w.size > 0 && w.size+int64(len(data)) > w.opts.maxSize() {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.file.WriteString(data)
+	w.size += int64(n)
+	return err
+}
+
+// rotate shifts the log's parts up by one, dropping the oldest past
+// MaxFiles, and starts a fresh file
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	maxFiles := w.opts.maxFiles()
+	_ = os.Remove(partPath(w.path, maxFiles))
+	for n := maxFiles - 1; n >= 1; n-- {
+		if err := os.Rename(partPath(w.path, n), partPath(w.path, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate agent log: %w", err)
+		}
+	}
+	if err := os.Rename(w.path, partPath(w.path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate agent log: %w", err)
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to rotate agent log: %w", err)
+	}
+	w.file = file
+	w.size = 0
+	return nil
+}
+
+// partPath returns the path of a log's nth rotated part
+func partPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Parts returns the files making up the log at path, oldest first: the
+// rotated parts from highest number down, then path itself
+func Parts(path string) ([]string, error) {
+	matches, err := filepath.Glob(globEscape(path) + ".*")
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int, 0, len(matches))
+	for _, match := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(match, path+"."))
+		if err == nil && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+
+	parts := make([]string, 0, len(numbers)+1)
+	for _, n := range numbers {
+		parts = append(parts, partPath(path, n))
+	}
+	if _, err := os.Stat(path); err == nil {
+		parts = append(parts, path)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("agent log not found: %s", path)
+	}
+	return parts, nil
+}
+
+// Prune removes log files in dir last written before cutoff
+func Prune(dir string, cutoff time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read agent log directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.Contains(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+	return nil
+}
+
+// globEscape escapes glob metacharacters in path
+func globEscape(path string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return replacer.Replace(path)
+}
@@ -0,0 +1,143 @@
+package agentlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testStart = time.Date(2026, 10, 15, 14, 22, 33, 0, time.UTC)
+
+func TestWriter_WritesWholeLinesThroughFilter(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(dir, "auth", testStart, Options{
+		Filter: func(line string) string { return strings.ReplaceAll(line, "sk-secret", "[REDACTED]") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	if filepath.Base(w.Path()) != "auth-20261015-142233.000.log" {
+		t.Errorf("Unexpected log name %s", filepath.Base(w.Path()))
+	}
+
+	// A secret split across writes is still filtered
+	fmt.Fprint(w, "key: sk-se")
+	fmt.Fprint(w, "cret\nsecond")
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close log: %v", err)
+	}
+
+	data, err := os.ReadFile(w.Path())
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if got := string(data); got != "key: [REDACTED]\nsecond\n" {
+		t.Errorf("Unexpected log contents %q", got)
+	}
+}
+
+func TestWriter_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(dir, "auth", testStart, Options{MaxSize: 20, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	for i := 1; i <= 8; i++ {
+		fmt.Fprintf(w, "line %d\n", i) // 7 bytes each, so 2 lines per file
+	}
+	w.Close()
+
+	parts, err := Parts(w.Path())
+	if err != nil {
+		t.Fatalf("Failed to list parts: %v", err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("Expected current file plus 2 rotated parts, got %v", parts)
+	}
+
+	// The oldest part was dropped, so the log starts at line 3
+	r, err := Open(w.Path())
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	defer r.Close()
+	lines, err := r.Page(0, r.Lines())
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	want := "line 3,line 4,line 5,line 6,line 7,line 8"
+	if got := strings.Join(lines, ","); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestReader_Pages(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.log")
+	var content strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	content.WriteString(strings.Repeat("x", 100*1024)) // Long final line without a newline
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	defer r.Close()
+	if r.Lines() != 1001 {
+		t.Fatalf("Expected 1001 lines, got %d", r.Lines())
+	}
+
+	page, err := r.Page(500, 3)
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if strings.Join(page, ",") != "line 500,line 501,line 502" {
+		t.Errorf("Unexpected page %v", page)
+	}
+
+	page, err = r.Page(999, 10)
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if len(page) != 2 || page[0] != "line 999" || len(page[1]) != 100*1024 {
+		t.Errorf("Unexpected last page: %d lines", len(page))
+	}
+}
+
+func TestPrune_RemovesOldLogs(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "auth-old.log")
+	oldPart := old + ".1"
+	recent := filepath.Join(dir, "auth-recent.log")
+	for _, path := range []string{old, oldPart, recent} {
+		if err := os.WriteFile(path, []byte("output\n"), 0644); err != nil {
+			t.Fatalf("Failed to write log: %v", err)
+		}
+	}
+	longAgo := time.Now().AddDate(0, 0, -40)
+	for _, path := range []string{old, oldPart} {
+		if err := os.Chtimes(path, longAgo, longAgo); err != nil {
+			t.Fatalf("Failed to age log: %v", err)
+		}
+	}
+
+	if err := Prune(dir, time.Now().Add(-DefaultRetention)); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	for _, path := range []string{old, oldPart} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed", filepath.Base(path))
+		}
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("Expected recent log kept: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package agentlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// lineRef locates a line within a log's parts
+type lineRef struct {
+	part   int   // Index into Reader.files
+	offset int64 // Byte offset of the line's start in that part
+	length int   // Length of the line, without its newline
+}
+
+// Reader reads a log a page at a time. Open scans the log once to find
+// where each line starts; Page then reads just the lines asked for, so a
+// log of any size can be paged through without loading it into memory.
+type Reader struct {
+	path  string
+	files []*os.File
+	lines []lineRef
+}
+
+// Open indexes the log at path, including its rotated parts
+func Open(path string) (*Reader, error) {
+	parts, err := Parts(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reader{path: path}
+	for i, part := range parts {
+		file, err := os.Open(part)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to open agent log: %w", err)
+		}
+		r.files = append(r.files, file)
+		if err := r.index(i, file); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("failed to read agent log: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// index records where each line in part starts
+func (r *Reader) index(part int, file *os.File) error {
+	reader := bufio.NewReaderSize(file, 64*1024)
+	var offset int64
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		length := len(chunk)
+		// Lines longer than the buffer come back in pieces
+		for err == bufio.ErrBufferFull {
+			chunk, err = reader.ReadSlice('\n')
+			length += len(chunk)
+		}
+		if length > 0 {
+			lineLength := length
+			if err == nil {
+				lineLength-- // Drop the newline
+			}
+			r.lines = append(r.lines, lineRef{part: part, offset: offset, length: lineLength})
+			offset += int64(length)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Path returns the path the log was opened from
+func (r *Reader) Path() string {
+	return r.path
+}
+
+// Lines returns the number of lines in the log
+func (r *Reader) Lines() int {
+	return len(r.lines)
+}
+
+// Page returns up to count lines starting at line start (zero-based)
+func (r *Reader) Page(start, count int) ([]string, error) {
+	if start < 0 {
+		start = 0
+	}
+	end := start + count
+	if end > len(r.lines) {
+		end = len(r.lines)
+	}
+	page := make([]string, 0, max(end-start, 0))
+	for i := start; i < end; i++ {
+		ref := r.lines[i]
+		buf := make([]byte, ref.length)
+		if _, err := r.files[ref.part].ReadAt(buf, ref.offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read agent log: %w", err)
+		}
+		page = append(page, strings.TrimSuffix(string(buf), "\r"))
+	}
+	return page, nil
+}
+
+// Close closes the log's files
+func (r *Reader) Close() error {
+	var err error
+	for _, file := range r.files {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	r.files = nil
+	return err
+}
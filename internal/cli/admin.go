@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compactDryRun bool
+	fsckRepair    bool
+)
+
+var adminCmd = &cobra.Command{
+	Use:     "admin",
+	Aliases: []string{"store"},
+	Short:   "Maintain the project's juggle store",
+	Long: `Maintain the project's juggle store: compact balls.jsonl, and check it
+for corruption. Also available as 'juggle store'.`,
+}
+
+var adminCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Rewrite balls.jsonl with one validated line per ball, sorted by ID",
+	Long: `Rewrite .juggle/balls.jsonl so it holds exactly one line per ball, sorted by ID.
+
+When a ball appears on more than one line (an appended update, or a bad
+merge), the last line wins. Blank lines are dropped. Every line is validated
+first, and the file is left untouched if any line is invalid. The new file is
+synced to disk and then renamed over the old one, so a crash leaves one or
+the other.
+
+The store is also compacted automatically when balls.jsonl grows past the
+project's threshold (1 MB by default) and by a quarter since it was last
+compacted. Set the threshold with 'juggle admin compact threshold'.
+
+Examples:
+  juggle admin compact                    # Compact and show before/after stats
+  juggle admin compact --dry-run          # Show what compaction would do
+  juggle admin compact threshold 512K     # Compact automatically past 512 KB
+  juggle admin compact threshold off      # Only compact by hand`,
+	Args: cobra.NoArgs,
+	RunE: runAdminCompact,
+}
+
+var adminCompactThresholdCmd = &cobra.Command{
+	Use:   "threshold [size|off]",
+	Short: "Show or set the balls.jsonl size that triggers automatic compaction",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runAdminCompactThreshold,
+}
+
+var adminFsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check balls.jsonl for corrupt lines, duplicate IDs and orphaned session tags",
+	Long: `Check the project's balls for problems, and repair them with --repair:
+
+  corrupt_line           A line that isn't a valid ball, e.g. one cut short by
+                         a crash. Repair drops it.
+  duplicate_id           A ball on more than one line. Repair keeps the last
+                         line, as compaction does.
+  orphaned_session_tag   A tag naming a deleted session (one agent history
+                         has runs on, or whose directory has lost its session
+                         file). Repair removes the tag.
+
+A repair copies balls.jsonl to balls.jsonl.fsck.bak before rewriting it, so
+dropped lines can still be recovered by hand. With the bolt store backend
+only session tags are checked. The exit code is 4 while problems remain.
+
+Examples:
+  juggle store fsck                # Report problems
+  juggle store fsck --repair       # Report and fix them
+  juggle store fsck --json         # Problems as JSON, for scripts`,
+	Args: cobra.NoArgs,
+	RunE: runAdminFsck,
+}
+
+func init() {
+	adminCompactCmd.Flags().BoolVar(&compactDryRun, "dry-run", false, "Show the stats without rewriting the file")
+	adminFsckCmd.Flags().BoolVar(&fsckRepair, "repair", false, "Fix the problems found (balls.jsonl is backed up first)")
+
+	adminCompactCmd.AddCommand(adminCompactThresholdCmd)
+	adminCmd.AddCommand(adminCompactCmd, adminFsckCmd)
+	rootCmd.AddCommand(adminCmd)
+}
+
+func runAdminCompact(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	// Not NewStoreForCommand: an automatic compaction first would leave
+	// nothing to report
+	store, err := session.NewStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	last, err := store.LastCompaction()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	stats, err := store.Compact(compactDryRun)
+	if err != nil {
+		return validationErrorf("balls.jsonl was not compacted: %v", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	switch {
+	case compactDryRun:
+		fmt.Println("Dry run: balls.jsonl was not rewritten")
+	case stats.Rewritten:
+		fmt.Println("✓ Compacted balls.jsonl")
+	default:
+		fmt.Println("balls.jsonl is already compact")
+	}
+	fmt.Printf("  Size:  %s → %s\n", session.FormatByteSize(stats.BytesBefore), session.FormatByteSize(stats.BytesAfter))
+	fmt.Printf("  Lines: %d → %d", stats.LinesBefore, stats.LinesAfter)
+	var dropped []string
+	if stats.Duplicates > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d superseded", stats.Duplicates))
+	}
+	if stats.BlankLines > 0 {
+		dropped = append(dropped, fmt.Sprintf("%d blank", stats.BlankLines))
+	}
+	if len(dropped) > 0 {
+		fmt.Printf(" (%s)", strings.Join(dropped, ", "))
+	}
+	fmt.Println()
+	if last != nil {
+		kind := "by hand"
+		if last.Automatic {
+			kind = "automatically"
+		}
+		fmt.Printf("  Previously compacted %s at %s\n", kind, last.CompactedAt.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func runAdminFsck(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	// Not NewStoreForCommand, which may compact first and drop duplicates
+	// before they can be reported
+	store, err := session.NewStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize store: %w", err)
+	}
+	report, err := store.Fsck(fsckRepair)
+	if err != nil {
+		return err
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range report.Issues {
+			where := ""
+			if issue.Line > 0 {
+				where = fmt.Sprintf("line %d: ", issue.Line)
+			}
+			if issue.BallID != "" {
+				where += issue.BallID + ": "
+			}
+			fmt.Printf("%-21s %s%s\n", issue.Kind, where, issue.Detail)
+		}
+		switch {
+		case len(report.Issues) == 0:
+			fmt.Printf("✓ No problems found (%d balls, %d lines)\n", report.Balls, report.Lines)
+		case report.Repaired:
+			fmt.Printf("\n✓ Repaired %d problem(s)", len(report.Issues))
+			if report.Backup != "" {
+				fmt.Printf("; the old file is at %s", report.Backup)
+			}
+			fmt.Println()
+		default:
+			fmt.Printf("\n%d problem(s). Fix them with: juggle store fsck --repair\n", len(report.Issues))
+		}
+	}
+
+	if len(report.Issues) > 0 && !report.Repaired {
+		err := validationErrorf("found %d problem(s) in the store", len(report.Issues))
+		if GlobalOpts.JSONOutput {
+			// The report is the output; only the exit code is left to report
+			cliErr := ClassifyError(err)
+			cliErr.reported = true
+			return cliErr
+		}
+		return err
+	}
+	return nil
+}
+
+func runAdminCompactThreshold(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if len(args) == 0 {
+		threshold, err := session.GetProjectCompactThreshold(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load compaction threshold: %w", err)
+		}
+		if threshold == 0 {
+			fmt.Println("Automatic compaction: off")
+		} else {
+			fmt.Printf("Automatic compaction: past %s\n", session.FormatByteSize(threshold))
+		}
+		return nil
+	}
+
+	var threshold int64
+	if !strings.EqualFold(args[0], "off") {
+		if threshold, err = session.ParseByteSize(args[0]); err != nil {
+			return validationErrorf("%v", err)
+		}
+		if threshold == 0 {
+			return validationErrorf("threshold must be more than 0 (use \"off\" to turn automatic compaction off)")
+		}
+	}
+	if err := session.UpdateProjectCompactThreshold(cwd, threshold); err != nil {
+		return fmt.Errorf("failed to save compaction threshold: %w", err)
+	}
+	if threshold == 0 {
+		fmt.Println("Automatic compaction turned off")
+	} else {
+		fmt.Printf("Set automatic compaction threshold: %s\n", session.FormatByteSize(threshold))
+	}
+	return nil
+}
+
+// autoCompact compacts the store's balls file if it has grown past the
+// project's threshold. Failures are warnings: the command goes on with the
+// file as it is.
+func autoCompact(store *session.Store) {
+	threshold, err := session.GetProjectCompactThreshold(store.ProjectDir())
+	if err != nil {
+		return
+	}
+	stats, err := store.CompactIfNeeded(threshold)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: automatic compaction of balls.jsonl failed: %v\n", err)
+		return
+	}
+	if stats != nil && stats.Rewritten {
+		fmt.Fprintf(os.Stderr, "Compacted balls.jsonl: %s → %s\n",
+			session.FormatByteSize(stats.BytesBefore), session.FormatByteSize(stats.BytesAfter))
+	}
+}
@@ -3,9 +3,11 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -13,6 +15,8 @@ import (
 
 	"github.com/ohare93/juggle/internal/agent"
 	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/agentlog"
+	"github.com/ohare93/juggle/internal/profile"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/vcs"
 	"github.com/spf13/cobra"
@@ -36,12 +40,18 @@ var (
 	agentModel         string
 	agentDelay         int    // Delay between iterations in minutes (overrides config)
 	agentFuzz          int    // +/- variance in delay minutes (overrides config)
-	agentProvider      string // Agent provider (claude, opencode)
+	agentProvider      string // Agent provider (claude, opencode, http, ollama, shell)
 	agentIgnoreLock    bool   // Skip lock acquisition
 	agentClearProgress bool   // Clear session progress before running
 	agentPickBall      bool   // Interactive ball selection
+	agentYes           bool   // Skip the budget preview before a run
 	agentMessage       string // Message to append to agent prompt
 	agentMessageFlag   bool   // Track if -m flag was provided (for interactive mode)
+	agentIgnoreQuiet   bool   // Run even during configured quiet hours
+	agentSkipBrownout  bool   // Run even if the session is paused after a brownout
+	agentSafeMode      bool   // Snapshot the working tree and offer a revert if the run fails
+	agentLabel         string // Label recorded with the run in agent history
+	agentWorktree      bool   // Work in the session's git worktree, off the main working tree
 
 	// Refine command flags
 	refineProvider string // Agent provider for refine command
@@ -82,6 +92,28 @@ When Claude returns a rate limit error (429 or overloaded), the agent
 automatically waits with exponential backoff before retrying. If Claude
 specifies a retry-after time, that time is used instead.
 
+Brownout:
+When the agent crashes several times in a row (see brownout_threshold in
+the global config, default 3), the session is paused for a cool-down period
+(brownout_cooldown_minutes, default 30) instead of retrying forever. Runs for
+the paused session refuse to start until the cool-down ends, while other
+sessions are unaffected. Use --ignore-brownout to resume early.
+
+Safe Mode:
+With --safe, the working tree is snapshotted before the run (a commit under
+refs/juggle/snapshots for git, the current change for jj). If the run errors,
+is cancelled with Ctrl+C, times out, browns out, or ends with unvalidated
+signals, the working tree can be reverted to the snapshot. Set
+safe_mode_revert in the global config to "ask" (default) or "auto"; without
+a terminal, "ask" keeps the changes. The .juggle directory is never reverted,
+and the decision is logged in the agent history.
+
+Budget Preview:
+Before an unattended loop started from a terminal, juggle shows the run's
+actionable balls, iterations, timeout and delay, approximate prompt tokens,
+and the session's past time and balls per iteration, then asks whether to
+start. --dry-run shows the same preview; --yes skips it.
+
 Examples:
   # Show session selector (interactive)
   juggle agent run
@@ -113,6 +145,9 @@ Examples:
   # Run with full permissions (dangerous)
   juggle agent run my-feature --trust
 
+  # Snapshot first and offer to revert if the run fails
+  juggle agent run my-feature --safe
+
   # Run with 5 minute timeout per iteration
   juggle agent run my-feature --timeout 5m
 
@@ -146,6 +181,9 @@ Examples:
   # Disable delay entirely (overrides config even if set)
   juggle agent run my-feature --delay 0
 
+  # Label the run so it can be told apart in agent history
+  juggle agent run my-feature --label attempt-2-after-prompt-fix
+
   # Append a message to the agent prompt
   juggle agent run my-feature -M "Focus on the authentication flow first"
 
@@ -191,21 +229,27 @@ func init() {
 	agentRunCmd.Flags().BoolVar(&agentTrust, "trust", false, "Run with --dangerously-skip-permissions (dangerous!)")
 	agentRunCmd.Flags().DurationVarP(&agentTimeout, "timeout", "T", 0, "Timeout per iteration (e.g., 5m, 1h). 0 = no timeout")
 	agentRunCmd.Flags().BoolVarP(&agentDebug, "debug", "d", false, "Show prompt info before running the agent")
-	agentRunCmd.Flags().BoolVar(&agentDryRun, "dry-run", false, "Show prompt info without running the agent")
+	agentRunCmd.Flags().BoolVar(&agentDryRun, "dry-run", false, "Show the prompt, pre-loop checks and runner command without running the agent")
 	agentRunCmd.Flags().DurationVar(&agentMaxWait, "max-wait", 0, "Maximum wait time for rate limits before giving up (e.g., 30m). 0 = wait indefinitely")
 	agentRunCmd.Flags().StringVarP(&agentBallID, "ball", "b", "", "Work on a specific ball only (defaults to 1 iteration, interactive)")
 	agentRunCmd.Flags().BoolVarP(&agentInteractive, "interactive", "i", false, "Run in interactive mode (full Claude TUI, defaults to 1 iteration)")
 	agentRunCmd.Flags().StringVarP(&agentModel, "model", "m", "", "Model to use (opus, sonnet, haiku). Default: opus for large balls, sonnet for others")
 	agentRunCmd.Flags().IntVar(&agentDelay, "delay", 0, "Delay between iterations in minutes (overrides config, 0 = no delay)")
 	agentRunCmd.Flags().IntVar(&agentFuzz, "fuzz", 0, "Random +/- variance in delay minutes (overrides config)")
-	agentRunCmd.Flags().StringVar(&agentProvider, "provider", "", "Agent provider to use (claude, opencode). Default: from config or claude")
+	agentRunCmd.Flags().StringVar(&agentProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
 	agentRunCmd.Flags().BoolVar(&agentIgnoreLock, "ignore-lock", false, "Skip lock acquisition (use with caution)")
+	agentRunCmd.Flags().BoolVar(&agentIgnoreQuiet, "ignore-quiet-hours", false, "Run even during configured quiet hours")
+	agentRunCmd.Flags().BoolVar(&agentSkipBrownout, "ignore-brownout", false, "Run even if the session is paused after repeated agent errors")
+	agentRunCmd.Flags().BoolVar(&agentSafeMode, "safe", false, "Snapshot the working tree and offer to revert it if the run fails")
+	agentRunCmd.Flags().BoolVar(&agentWorktree, "worktree", false, "Work in a git worktree on branch juggle/agent-<session> (see agent worktree)")
+	agentRunCmd.Flags().StringVar(&agentLabel, "label", "", "Label to record with the run in agent history (e.g., attempt-2-after-prompt-fix)")
 	agentRunCmd.Flags().BoolVar(&agentClearProgress, "clear-progress", false, "Clear session progress before running")
 	agentRunCmd.Flags().BoolVar(&agentPickBall, "pick", false, "Interactively select a ball to work on")
+	agentRunCmd.Flags().BoolVarP(&agentYes, "yes", "y", false, "Start without the budget preview and confirmation")
 	agentRunCmd.Flags().StringVarP(&agentMessage, "message", "M", "", "Message to append to the agent prompt. If flag is provided without value, opens interactive input")
 
 	// Refine command flags
-	agentRefineCmd.Flags().StringVar(&refineProvider, "provider", "", "Agent provider to use (claude, opencode). Default: from config or claude")
+	agentRefineCmd.Flags().StringVar(&refineProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
 	agentRefineCmd.Flags().StringVarP(&refineModel, "model", "m", "", "Model to use (opus, sonnet, haiku). Default: sonnet")
 	agentRefineCmd.Flags().StringVarP(&refineMessage, "message", "M", "", "Message to append to the refine prompt. If flag is provided without value, opens interactive input")
 
@@ -270,11 +314,24 @@ type AgentResult struct {
 	TotalWaitTime      time.Duration `json:"total_wait_time,omitempty"`
 	OverloadRetries    int           `json:"overload_retries,omitempty"`    // Number of 529 overload retry waits
 	OverloadWaitTime   time.Duration `json:"overload_wait_time,omitempty"` // Total time spent waiting for overload recovery
+	QuietWaitTime      time.Duration `json:"quiet_wait_time,omitempty"`    // Total time spent waiting out quiet hours
+	PausedTime         time.Duration `json:"paused_time,omitempty"`        // Total time held by agent pause
 	BallsComplete      int           `json:"balls_complete"`
 	BallsBlocked       int           `json:"balls_blocked"`
 	BallsTotal         int           `json:"balls_total"`
 	StartedAt          time.Time     `json:"started_at"`
 	EndedAt            time.Time     `json:"ended_at"`
+	CreatedBalls       []string        `json:"created_balls,omitempty"` // IDs of balls created during the run
+	Duplicates         []DuplicateBall `json:"duplicates,omitempty"`    // Created balls that look like duplicates of another ball
+	Brownout           *session.Brownout `json:"brownout,omitempty"`    // Set when the session is paused after repeated agent errors
+	Cancelled          bool                     `json:"cancelled,omitempty"`           // Run was stopped with Ctrl+C (safe mode only)
+	ValidationFailures int                      `json:"validation_failures,omitempty"` // Signals rejected because progress wasn't updated
+	HumanSignal        session.LoopSignal       `json:"human_signal,omitempty"`        // Last signal a person sent mid-run (see agent signal)
+	SafeMode           *session.SafeModeOutcome `json:"safe_mode,omitempty"`           // What safe mode did with the run's changes
+	Redactions         session.ScrubReport      `json:"redactions,omitempty"`          // Secrets scrubbed from prompts and saved output
+	RunID              string                   `json:"run_id"`                        // ID of the run in agent history
+	LogFile            string                   `json:"log_file,omitempty"`            // Log the agent's output was streamed to
+	IterationOutcomes  []session.IterationOutcome `json:"iteration_outcomes,omitempty"` // How each iteration ended
 }
 
 // AgentLoopConfig configures the agent loop behavior
@@ -291,9 +348,19 @@ type AgentLoopConfig struct {
 	Interactive          bool          // Run in interactive mode (full Claude TUI)
 	Model                string        // Model to use (opus, sonnet, haiku). Empty = auto-select based on ball model_size
 	OverloadRetryMinutes int           // Minutes to wait before retrying after 529 overload exhaustion (-1 = use config default, 0 = no wait)
-	Provider             string        // Agent provider to use (claude, opencode). Empty = from config or claude
+	Provider             string        // Agent provider to use (claude, opencode, http, ollama, shell). Empty = from config or claude
 	IgnoreLock           bool          // Skip lock acquisition (use with caution)
 	Message              string        // User message to append to the agent prompt
+	IgnoreQuietHours     bool          // Run even during configured quiet hours
+	IgnoreBrownout       bool          // Run even if the session is cooling down after a brownout
+	SafeMode             bool          // Snapshot the working tree first and offer a revert if the run fails
+	Label                string        // Label recorded with the run in agent history
+	WorkDir              string        // Where the agent works: its worktree (see --worktree), or ProjectDir if empty
+	Phase                string        // Plan-then-run phase recorded in agent history (empty for plain runs)
+	LinkedRunID          string        // Run of the other plan-then-run phase
+
+	// Resume is the checkpoint of an interrupted run to carry on from (agent resume)
+	Resume *session.AgentCheckpoint
 }
 
 // sessionStorageID returns the session ID used for storage (progress, output, lock)
@@ -307,7 +374,7 @@ func sessionStorageID(sessionID string) string {
 
 // RunAgentLoop executes the agent loop with the given configuration.
 // This is the testable core of the agent run command.
-func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
+func RunAgentLoop(config AgentLoopConfig) (_ *AgentResult, runErr error) {
 	startTime := time.Now()
 
 	sessionStore, err := session.NewSessionStore(config.ProjectDir)
@@ -324,7 +391,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		var err error
 		juggleSession, err = sessionStore.LoadSession(config.SessionID)
 		if err != nil {
-			return nil, fmt.Errorf("session not found: %s", config.SessionID)
+			return nil, session.NewSessionNotFoundError(config.SessionID)
 		}
 	}
 
@@ -356,6 +423,28 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	}
 	defer lockRelease()
 
+	// The loop is checkpointed before each iteration so a run that's killed
+	// can be resumed (agent resume); a run that ends clears its checkpoint.
+	// Single-ball and lockless runs aren't checkpointed, since a stale
+	// checkpoint is told from a live one by the session lock.
+	checkpointing := config.BallID == "" && !config.IgnoreLock
+	if checkpointing {
+		defer func() {
+			if err := sessionStore.ClearAgentCheckpoint(storageID); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}()
+	}
+
+	// A human signal or pause left over from an earlier run doesn't apply
+	// to this one
+	if err := sessionStore.ClearHumanSignal(storageID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if err := sessionStore.ClearAgentPause(storageID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Create output file path using storage ID
 	// For "all" meta-session, ensure the _all session directory exists
 	if isAllSession {
@@ -366,8 +455,18 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	}
 	outputPath := filepath.Join(config.ProjectDir, ".juggle", "sessions", storageID, "last_output.txt")
 
+	// Each iteration's output is also kept per run so it can be attached to
+	// a ball later (see attach-transcript)
+	runID := session.AgentRunID(startTime)
+	historyStore, err := session.NewAgentHistoryStore(config.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &AgentResult{
-		StartedAt: startTime,
+		StartedAt:  startTime,
+		Redactions: session.ScrubReport{},
+		RunID:      runID,
 	}
 
 	// Track rate limit state
@@ -375,15 +474,40 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	rateLimitRetries := 0
 	rateLimitRetrying := false // Skip header when retrying after rate limit
 
+	// A resumed run carries on with the iterations and wait time it had left
+	checkpoint := newAgentCheckpoint(config, startTime)
+	firstIteration := 1
+	if config.Resume != nil {
+		firstIteration = config.Resume.Iteration + 1
+		totalWaitTime = config.Resume.WaitTime
+	}
+
 	// Track 529 overload exhaustion state
 	var overloadWaitTime time.Duration
 	overloadRetries := 0
 	overloadRetrying := false // Skip header when retrying after overload
 
-	// Track crash retry state
+	// Track crash retry state. Consecutive crashes trip the brownout breaker,
+	// which pauses the session instead of burning more iterations.
 	crashRetries := 0
 	crashRetrying := false // Skip header when retrying after crash
-	const maxCrashRetries = 3
+	brownoutSettings, err := session.GetGlobalBrownoutSettingsWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load brownout config: %v\n", err)
+	}
+
+	// Don't start while the session is cooling down after a brownout
+	previousBrownout, err := sessionStore.LoadBrownout(storageID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if previousBrownout.Active(time.Now()) && !config.IgnoreBrownout {
+		fmt.Fprintf(os.Stderr, "⏸ Session %s is paused until %s after %d consecutive agent errors\n",
+			config.SessionID, previousBrownout.Until.Format("15:04"), previousBrownout.Errors)
+		result.Brownout = previousBrownout
+		result.EndedAt = time.Now()
+		return result, nil
+	}
 
 	// Load overload retry interval from config (or use provided override)
 	// -1 means "use config default", 0 means "no wait" (for testing), >0 is explicit minutes
@@ -392,16 +516,17 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		overloadRetryMinutes, _ = session.GetGlobalOverloadRetryMinutesWithOptions(GetConfigOptions())
 	}
 
-	// Configure agent provider based on CLI flag, project config, and global config
-	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	// Load quota reset times and quiet hours from global config
+	quotaResets, quietHours, err := session.GetGlobalScheduleWithOptions(GetConfigOptions())
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to load agent schedule config: %v\n", err)
 	}
-	projectProvider, err := session.GetProjectAgentProvider(config.ProjectDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	if config.IgnoreQuietHours {
+		quietHours = nil
 	}
-	providerType := provider.Detect(config.Provider, projectProvider, globalProvider)
+
+	// Configure agent provider based on CLI flag, project config, and global config
+	providerType := detectRunProvider(config, juggleSession)
 
 	// Verify provider binary is available
 	if !provider.IsAvailable(providerType) {
@@ -409,20 +534,20 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			providerType, provider.BinaryName(providerType))
 	}
 
-	agentProv := provider.Get(providerType)
+	agentProv := newAgentProvider(providerType)
 	agent.SetProvider(agentProv)
 
-	// Configure model overrides
-	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
-	}
-	projectOverrides, err := session.GetProjectModelOverrides(config.ProjectDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	// Providers routed by model size apply unless the run's provider was
+	// picked explicitly (flag or session)
+	modelProviders := loadModelProviders(config.ProjectDir)
+	if config.Provider != "" || (juggleSession != nil && juggleSession.AgentProvider != "") {
+		modelProviders = nil
 	}
-	modelOverrides := session.MergeModelOverrides(globalOverrides, projectOverrides)
-	agent.SetModelOverrides(modelOverrides)
+
+	// Configure model overrides
+	agent.SetModelOverrides(loadRunModelOverrides(config.ProjectDir))
+	signalOptions := loadSignalOptions()
+	scrubber := loadScrubber()
 
 	// Pre-loop check: is there any work the agent can do?
 	// Exit early if all balls are blocked (need human intervention) or no actionable balls exist
@@ -443,14 +568,74 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			result.Blocked = true
 			return result, nil
 		}
+		// No balls left, but the goal isn't reached: a human has to add work
+		// or settle the criteria
+		if unmet := unmetSessionExitCriteria(sessionStore, config); len(unmet) > 0 {
+			fmt.Fprintf(os.Stderr, "⏸ No actionable balls, but %d exit criteria are unmet\n", len(unmet))
+			result.Blocked = true
+			result.BlockedReason = fmt.Sprintf("%d exit criteria unmet", len(unmet))
+			return result, nil
+		}
 		// No balls at all (all complete/researched or truly empty)
 		fmt.Fprintf(os.Stderr, "✓ No actionable balls in session\n")
 		result.Complete = true
 		return result, nil
 	}
 
-	for iteration := 1; iteration <= config.MaxIterations; iteration++ {
+	// Safe mode snapshots the working tree so a failed run can be reverted
+	var safe *safeModeRun
+	if config.SafeMode {
+		safe, err = startSafeMode(config.ProjectDir, agentWorkDir(config), runID)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if safe != nil && runErr != nil {
+				outcome := safe.finish(safeModeFailure(nil, runErr))
+				printSafeModeOutcome(outcome)
+				saveAgentErrorHistory(config, result, outputPath, runErr, outcome)
+			}
+		}()
+	}
+
+	// Stream the agent's output to a log as it runs, so every run's output
+	// is kept (last_output.txt only has the latest iteration)
+	agentLog := openAgentLog(config.ProjectDir, storageID, startTime, scrubber)
+	if agentLog != nil {
+		defer agentLog.Close()
+		result.LogFile = agentLog.Path()
+	}
+
+	// Remember which balls exist so balls the agent creates can be checked for duplicates
+	knownBallIDs := loadBallIDs(config.ProjectDir)
+	var reportedBallIDs []string
+
+	for iteration := firstIteration; iteration <= config.MaxIterations; iteration++ {
+		if safe.Interrupted() {
+			result.Cancelled = true
+			break
+		}
 		result.Iterations = iteration
+		if checkpointing {
+			saveAgentCheckpoint(sessionStore, storageID, checkpoint, iteration-1, totalWaitTime+overloadWaitTime, result.IterationOutcomes)
+		}
+
+		// Hold between iterations while a person has paused the run
+		result.PausedTime += holdWhilePaused(sessionStore, config.SessionID, storageID, iteration-1, safe.Interrupted)
+		if safe.Interrupted() {
+			result.Cancelled = true
+			break
+		}
+
+		// Don't start an agent during configured quiet hours
+		if end, quiet := session.QuietHoursEnd(time.Now(), quietHours); quiet {
+			waitTime := time.Until(end)
+			logQuietHoursToProgress(config.ProjectDir, storageID,
+				fmt.Sprintf("Quiet hours, waiting %v until %s", waitTime.Round(time.Second), end.Format("15:04 MST")))
+			fmt.Printf("🌙 Quiet hours. Waiting %v until %s...\n", waitTime.Round(time.Second), end.Format("15:04 MST"))
+			waitWithCountdown(waitTime)
+			result.QuietWaitTime += waitTime
+		}
 
 		// Print iteration separator and header (skip when retrying after rate limit, overload, or crash)
 		if !rateLimitRetrying && !overloadRetrying && !crashRetrying {
@@ -477,20 +662,6 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			return nil, fmt.Errorf("failed to load balls for model selection: %w", err)
 		}
 
-		// Check for ball-level AgentProvider override when working on a single ball
-		activeBalls := filterActiveBalls(balls)
-		if len(activeBalls) == 1 && activeBalls[0].AgentProvider != "" && config.Provider == "" {
-			// Ball has an AgentProvider override and CLI didn't explicitly set one
-			ballProvider := activeBalls[0].AgentProvider
-			if provider.IsAvailable(provider.Type(ballProvider)) {
-				agentProv := provider.Get(provider.Type(ballProvider))
-				agent.SetProvider(agentProv)
-				fmt.Printf("🔧 Provider: %s (ball %s has agent_provider override)\n", ballProvider, activeBalls[0].ShortID())
-			} else {
-				fmt.Fprintf(os.Stderr, "⚠️  Ball %s has agent_provider=%q but it's not available, using default\n", activeBalls[0].ShortID(), ballProvider)
-			}
-		}
-
 		// Get session default model
 		var sessionDefaultModel session.ModelSize
 		if juggleSession != nil {
@@ -500,42 +671,61 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		// Select optimal model for this iteration
 		modelSelection := selectModelForIteration(config, balls, sessionDefaultModel)
 
+		// Pick the provider for this iteration: a single ball's override or
+		// the provider routed for the model's size, else the run's provider
+		iterationProvider, reason := selectProviderForIteration(config, filterActiveBalls(balls), modelSelection.Model, modelProviders)
+		usedProvider := providerType
+		if iterationProvider == "" || iterationProvider == providerType {
+			agent.SetProvider(agentProv)
+		} else if provider.IsAvailable(iterationProvider) {
+			usedProvider = iterationProvider
+			agent.SetProvider(newAgentProvider(iterationProvider))
+			fmt.Printf("🔧 Provider: %s (%s)\n", iterationProvider, reason)
+		} else {
+			agent.SetProvider(agentProv)
+			fmt.Fprintf(os.Stderr, "⚠️  %s wants provider %q but it's not available, using %s\n", reason, iterationProvider, providerType)
+		}
+
 		// Log model selection (only if not explicitly set)
 		if config.Model == "" {
 			fmt.Printf("🤖 Model: %s (%s)\n\n", modelSelection.Model, modelSelection.Reason)
 		}
 
 		// Generate prompt using export command
-		prompt, err := generateAgentPrompt(config.ProjectDir, config.SessionID, config.Debug, config.BallID, config.Message)
+		prompt, err := generateAgentPrompt(config.ProjectDir, config.SessionID, config.Debug, config.BallID, config.Message, result.Redactions)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate prompt: %w", err)
 		}
+		_ = historyStore.SaveIterationPrompt(runID, iteration, prompt)
 
 		// Build run options
-		opts := agent.RunOptions{
-			Prompt:     prompt,
-			Mode:       agent.ModeHeadless,
-			Permission: agent.PermissionAcceptEdits,
-			Timeout:    config.Timeout,
-			Model:      modelSelection.Model,
-		}
-		if config.Interactive {
-			opts.Mode = agent.ModeInteractive
+		opts := agentRunOptions(config, prompt, modelSelection.Model, signalOptions)
+		if agentLog != nil {
+			opts.Stdout = io.MultiWriter(os.Stdout, agentLog)
+			opts.Stderr = io.MultiWriter(os.Stderr, agentLog)
 		}
-		if config.Trust {
-			opts.Permission = agent.PermissionBypass
-		}
-		// Add autonomous system prompt for headless mode
-		if !config.Interactive {
-			opts.SystemPrompt = agent.AutonomousSystemPrompt
+
+		// Remember the session's open balls, to claim the ones this run completes
+		var openBallIDs map[string]bool
+		if !isAllSession {
+			openBallIDs = loadOpenSessionBallIDs(config.ProjectDir, config.SessionID)
 		}
 
-		// Run agent with options using the Runner interface
+		// Run agent with options using the Runner interface, recording how
+		// the call went toward the provider's API health
+		callStart := time.Now()
 		runResult, err := agent.DefaultRunner.Run(opts)
+		recordAPIHealth(usedProvider, callStart, runResult, err)
 		if err != nil {
 			return nil, fmt.Errorf("failed to run agent: %w", err)
 		}
 
+		// Ctrl+C reaches the agent too, so whatever it returned is incomplete
+		if safe.Interrupted() {
+			result.Cancelled = true
+			break
+		}
+
 		// Check for subprocess crash (non-zero exit, not rate limit/overload)
 		if runResult.Error != nil && runResult.ExitCode != 0 && !runResult.RateLimited && !runResult.OverloadExhausted {
 			waitTime := time.Duration(math.Pow(2, float64(crashRetries))) * time.Second
@@ -544,16 +734,17 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			}
 
 			crashRetries++
-			if crashRetries > maxCrashRetries {
-				return nil, fmt.Errorf("agent crashed %d times, giving up (last error: %v)", crashRetries, runResult.Error)
+			if crashRetries >= brownoutSettings.Threshold {
+				result.Brownout = tripBrownout(sessionStore, config, storageID, brownoutSettings, crashRetries, runResult.Error)
+				break
 			}
 
 			logCrashToProgress(config.ProjectDir, storageID,
 				fmt.Sprintf("Agent crashed (exit code %d), waiting %v before retry (attempt %d/%d)",
-					runResult.ExitCode, waitTime, crashRetries, maxCrashRetries))
+					runResult.ExitCode, waitTime, crashRetries, brownoutSettings.Threshold-1))
 
 			fmt.Printf("💥 Agent crashed (exit code %d). Waiting %v before retry (attempt %d/%d)...\n",
-				runResult.ExitCode, waitTime, crashRetries, maxCrashRetries)
+				runResult.ExitCode, waitTime, crashRetries, brownoutSettings.Threshold-1)
 
 			waitWithCountdown(waitTime)
 			crashRetrying = true
@@ -564,7 +755,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 
 		// Check for rate limit
 		if runResult.RateLimited {
-			waitTime := calculateWaitTime(runResult.RetryAfter, rateLimitRetries)
+			waitTime := calculateScheduledWaitTime(time.Now(), runResult.RetryAfter, rateLimitRetries, quotaResets)
 
 			// Check if we've exceeded max wait
 			if config.MaxWait > 0 && totalWaitTime+waitTime > config.MaxWait {
@@ -596,6 +787,11 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		// Reset retry counters on successful run
 		rateLimitRetries = 0
 		crashRetries = 0
+		if previousBrownout != nil {
+			// The session is healthy again; forget the last brownout
+			_ = sessionStore.ClearBrownout(storageID)
+			previousBrownout = nil
+		}
 
 		// Check for 529 overload exhaustion (Claude's built-in retries exhausted)
 		if runResult.OverloadExhausted {
@@ -631,17 +827,67 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			continue
 		}
 
-		// Check for timeout
+		// Save output to file (ignore errors for test compatibility). A
+		// timed out iteration's partial output is kept too.
+		savedOutput := scrubber.Scrub(runResult.Output, result.Redactions)
+		_ = os.WriteFile(outputPath, []byte(savedOutput), 0644)
+		_ = historyStore.SaveIterationOutput(runID, iteration, savedOutput)
+		reportedBallIDs = append(reportedBallIDs, session.ExtractCreatedBallIDs(runResult.Output)...)
+		claimCompletedBalls(config.ProjectDir, config.SessionID, openBallIDs)
+
+		// Record how the iteration ended, for agent export-dataset
+		signalRejected := false
+		// Exit criteria are evaluated at most once per iteration, when every
+		// ball is finished
+		var unmetCriteria []session.ExitCriterion
+		criteriaEvaluated := false
+		recordOutcome := func(outcome string) {
+			result.IterationOutcomes = append(result.IterationOutcomes, session.IterationOutcome{
+				Iteration: iteration, Outcome: outcome, Model: modelSelection.Model,
+			})
+		}
+
+		// Check for timeout, keeping what the iteration got done for the
+		// next one's prompt
 		if runResult.TimedOut {
+			salvage := salvageTimedOutIteration(sessionStore, config.ProjectDir, storageID, iteration, config.Timeout,
+				savedOutput, filterActiveBalls(balls), progressBefore, signalOptions)
 			result.TimedOut = true
-			result.TimeoutMessage = fmt.Sprintf("Iteration %d timed out after %v", iteration, config.Timeout)
+			result.TimeoutMessage = salvage.Summary()
+			recordOutcome(session.IterationTimeout)
 			// Log timeout to progress
 			logTimeoutToProgress(config.ProjectDir, storageID, result.TimeoutMessage)
 			break
 		}
+		_ = sessionStore.ClearTimeoutSalvage(storageID)
 
-		// Save output to file (ignore errors for test compatibility)
-		_ = os.WriteFile(outputPath, []byte(runResult.Output), 0644)
+		// A human's signal overrides the agent's own at this checkpoint
+		if human := takeHumanSignal(sessionStore, config.ProjectDir, storageID); human != nil {
+			recordOutcome(session.IterationHumanOverride)
+			_, complete, blocked, total := checkBallsTerminal(config.ProjectDir, config.SessionID, config.BallID)
+			result.BallsComplete = complete
+			result.BallsBlocked = blocked
+			result.BallsTotal = total
+			result.HumanSignal = human.Signal
+
+			fmt.Println()
+			if human.Signal == session.LoopSignalComplete {
+				fmt.Printf("👤 Human signaled COMPLETE. Ending the run...\n")
+				result.Complete = true
+				break
+			}
+			if human.Signal == session.LoopSignalBlocked {
+				fmt.Printf("👤 Human signaled BLOCKED. Ending the run...\n")
+				result.Blocked = true
+				result.BlockedReason = human.Reason
+				break
+			}
+			fmt.Printf("👤 Human signaled CONTINUE. Continuing to next iteration...\n")
+			if iteration < config.MaxIterations && config.IterDelay > 0 {
+				time.Sleep(config.IterDelay)
+			}
+			continue
+		}
 
 		// Check for completion signals (already parsed by Runner)
 		if runResult.Complete {
@@ -650,14 +896,20 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			if progressAfter <= progressBefore {
 				fmt.Println()
 				fmt.Printf("⚠️  Agent signaled COMPLETE but did not update progress. Continuing iteration...\n")
+				result.ValidationFailures++
+				signalRejected = true
 				// Don't accept the signal - continue to check terminal state
 			} else {
 				// VALIDATE: Check if all balls are actually in terminal state (complete or blocked)
 				terminal, complete, blocked, total := checkBallsTerminal(config.ProjectDir, config.SessionID, config.BallID)
 				if total > 0 && terminal == total {
+					// VALIDATE: Check the session's exit criteria before accepting the signal
+					unmetCriteria, criteriaEvaluated = unmetSessionExitCriteria(sessionStore, config), true
+				}
+				if total > 0 && terminal == total && len(unmetCriteria) == 0 {
 					// Commit changes if agent provided a commit message
 					if runResult.CommitMessage != "" {
-						commitResult, err := performJJCommit(config.ProjectDir, runResult.CommitMessage)
+						commitResult, err := performVCSCommitIn(config.ProjectDir, agentWorkDir(config), runResult.CommitMessage)
 						if err == nil && commitResult != nil {
 							if commitResult.Success {
 								if commitResult.CommitHash != "" {
@@ -675,12 +927,18 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 					result.BallsComplete = complete
 					result.BallsBlocked = blocked
 					result.BallsTotal = total
+					recordOutcome(session.IterationCompleted)
 					break
 				}
 				// Signal was premature - log warning and continue
+				signalRejected = true
 				fmt.Println()
-				fmt.Printf("⚠️  Agent signaled COMPLETE but only %d/%d balls are in terminal state (%d complete, %d blocked). Continuing...\n",
-					terminal, total, complete, blocked)
+				if len(unmetCriteria) > 0 {
+					printUnmetExitCriteria("Agent signaled COMPLETE", unmetCriteria)
+				} else {
+					fmt.Printf("⚠️  Agent signaled COMPLETE but only %d/%d balls are in terminal state (%d complete, %d blocked). Continuing...\n",
+						terminal, total, complete, blocked)
+				}
 			}
 		}
 
@@ -690,6 +948,8 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			if progressAfter <= progressBefore {
 				fmt.Println()
 				fmt.Printf("⚠️  Agent signaled CONTINUE but did not update progress. Continuing iteration...\n")
+				result.ValidationFailures++
+				signalRejected = true
 				// Don't accept the signal - fall through to terminal state check
 			} else {
 				// Agent completed one ball, more remain - continue to next iteration
@@ -698,7 +958,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 
 				// Commit changes if agent provided a commit message
 				if runResult.CommitMessage != "" {
-					commitResult, err := performJJCommit(config.ProjectDir, runResult.CommitMessage)
+					commitResult, err := performVCSCommitIn(config.ProjectDir, agentWorkDir(config), runResult.CommitMessage)
 					if err == nil && commitResult != nil {
 						if commitResult.Success {
 							if commitResult.CommitHash != "" {
@@ -718,6 +978,7 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 				result.BallsComplete = complete
 				result.BallsBlocked = blocked
 				result.BallsTotal = total
+				recordOutcome(session.IterationContinued)
 
 				continue
 			}
@@ -729,13 +990,21 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 			if progressAfter <= progressBefore {
 				fmt.Println()
 				fmt.Printf("⚠️  Agent signaled BLOCKED but did not update progress. Continuing iteration...\n")
+				result.ValidationFailures++
+				signalRejected = true
 				// Don't accept the signal - fall through to terminal state check
 			} else {
 				result.Blocked = true
 				result.BlockedReason = runResult.BlockedReason
+				recordOutcome(session.IterationBlocked)
 				break
 			}
 		}
+		if signalRejected {
+			recordOutcome(session.IterationRejectedSignal)
+		} else {
+			recordOutcome(session.IterationNoSignal)
+		}
 
 		// Check if all balls are in terminal state (complete or blocked)
 		terminal, complete, blocked, total := checkBallsTerminal(config.ProjectDir, config.SessionID, config.BallID)
@@ -744,8 +1013,16 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 		result.BallsTotal = total
 
 		if total > 0 && terminal == total {
-			result.Complete = true
-			break
+			if !criteriaEvaluated {
+				if unmetCriteria = unmetSessionExitCriteria(sessionStore, config); len(unmetCriteria) > 0 {
+					fmt.Println()
+					printUnmetExitCriteria("All balls are finished", unmetCriteria)
+				}
+			}
+			if len(unmetCriteria) == 0 {
+				result.Complete = true
+				break
+			}
 		}
 
 		// Delay before next iteration (unless this was the last one)
@@ -758,9 +1035,17 @@ func RunAgentLoop(config AgentLoopConfig) (*AgentResult, error) {
 	result.OverloadRetries = overloadRetries
 	result.OverloadWaitTime = overloadWaitTime
 	result.EndedAt = time.Now()
+	result.CreatedBalls, result.Duplicates = detectDuplicateBalls(config.ProjectDir, knownBallIDs, reportedBallIDs)
+
+	if safe != nil {
+		result.SafeMode = safe.finish(safeModeFailure(result, nil))
+		safe = nil
+	}
 
 	// Save run history (best-effort, don't fail the run if this errors)
-	saveAgentHistory(config, result, outputPath)
+	record := newAgentRunRecord(config, result, outputPath)
+	saveAgentHistory(config.ProjectDir, record)
+	runAgentHooks(record)
 
 	return result, nil
 }
@@ -785,6 +1070,25 @@ func calculateWaitTime(retryAfter time.Duration, retryCount int) time.Duration {
 	return wait
 }
 
+// calculateScheduledWaitTime determines how long to wait after a rate limit when
+// quota reset times are configured. An explicit retry-after still wins; otherwise
+// the wait runs until the next known reset instead of backing off exponentially.
+func calculateScheduledWaitTime(now time.Time, retryAfter time.Duration, retryCount int, quotaResets []string) time.Duration {
+	if retryAfter > 0 {
+		return calculateWaitTime(retryAfter, retryCount)
+	}
+	if reset, ok := session.NextQuotaReset(now, quotaResets); ok {
+		// Small buffer so we don't race the reset
+		return reset.Sub(now) + 5*time.Second
+	}
+	return calculateWaitTime(retryAfter, retryCount)
+}
+
+// CalculateScheduledWaitTimeForTest is an exported wrapper for testing
+func CalculateScheduledWaitTimeForTest(now time.Time, retryAfter time.Duration, retryCount int, quotaResets []string) time.Duration {
+	return calculateScheduledWaitTime(now, retryAfter, retryCount, quotaResets)
+}
+
 // calculateFuzzyDelay calculates the actual delay to use with random variance.
 // baseMinutes is the base delay in minutes, fuzz is the +/- variance in minutes.
 // The actual delay will be: base + random(-fuzz, fuzz) minutes.
@@ -834,8 +1138,11 @@ func logRateLimitToProgress(projectDir, sessionID, message string) {
 		return // Ignore errors - logging is best-effort
 	}
 
-	entry := fmt.Sprintf("[RATE_LIMIT] %s", message)
-	_ = sessionStore.AppendProgress(sessionID, entry)
+	_ = sessionStore.AppendProgressEntry(sessionID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryBlocker,
+		Text:     "[RATE_LIMIT] " + message,
+	})
 }
 
 // logOverloadToProgress logs a 529 overload event to the session's progress file
@@ -845,19 +1152,214 @@ func logOverloadToProgress(projectDir, sessionID, message string) {
 		return // Ignore errors - logging is best-effort
 	}
 
-	entry := fmt.Sprintf("[OVERLOAD_529] %s", message)
-	_ = sessionStore.AppendProgress(sessionID, entry)
+	_ = sessionStore.AppendProgressEntry(sessionID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryBlocker,
+		Text:     "[OVERLOAD_529] " + message,
+	})
+}
+
+// logQuietHoursToProgress logs a quiet hours wait to the session's progress file
+func logQuietHoursToProgress(projectDir, sessionID, message string) {
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return // Ignore errors - logging is best-effort
+	}
+
+	_ = sessionStore.AppendProgressEntry(sessionID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryWork,
+		Text:     "[QUIET_HOURS] " + message,
+	})
+}
+
+// newAgentProvider returns the provider implementation for t. The http
+// provider is configured from the http_agent settings in global config.
+func newAgentProvider(t provider.Type) provider.Provider {
+	switch t {
+	case provider.TypeHTTP:
+		httpAgent, err := session.GetGlobalHTTPAgentWithOptions(GetConfigOptions())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load http provider config: %v\n", err)
+		}
+		return provider.NewHTTPProvider(provider.HTTPConfig{
+			Endpoint:  httpAgent.Endpoint,
+			Format:    httpAgent.Format,
+			Model:     httpAgent.Model,
+			Headers:   httpAgent.Headers,
+			MaxTokens: httpAgent.MaxTokens,
+		})
+	case provider.TypeOllama:
+		ollamaAgent, err := session.GetGlobalOllamaAgentWithOptions(GetConfigOptions())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load ollama provider config: %v\n", err)
+		}
+		return provider.NewOllamaProvider(provider.OllamaConfig{
+			Endpoint: ollamaAgent.Endpoint,
+			Model:    ollamaAgent.Model,
+			Models:   ollamaAgent.Models,
+		})
+	case provider.TypeShell:
+		shellAgent, err := session.GetGlobalShellAgentWithOptions(GetConfigOptions())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load shell provider config: %v\n", err)
+		}
+		return provider.NewShellProvider(provider.ShellConfig{
+			Command: shellAgent.Command,
+			Signals: shellAgent.Signals,
+		})
+	default:
+		return provider.Get(t)
+	}
+}
+
+// detectRunProvider picks the provider for an agent run from the CLI flag,
+// the session's or project's provider and the global config
+func detectRunProvider(config AgentLoopConfig, juggleSession *session.JuggleSession) provider.Type {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(config.ProjectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	// A session's provider takes the project's place
+	if juggleSession != nil && juggleSession.AgentProvider != "" {
+		projectProvider = juggleSession.AgentProvider
+	}
+	return provider.Detect(config.Provider, projectProvider, globalProvider)
+}
+
+// loadRunModelOverrides merges the global and project model overrides
+func loadRunModelOverrides(projectDir string) map[string]string {
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	return session.MergeModelOverrides(globalOverrides, projectOverrides)
+}
+
+// agentRunOptions builds the options an iteration runs the agent with
+func agentRunOptions(config AgentLoopConfig, prompt, model string, signals provider.SignalOptions) agent.RunOptions {
+	opts := agent.RunOptions{
+		Prompt:     prompt,
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionAcceptEdits,
+		Timeout:    config.Timeout,
+		Model:      model,
+		Signals:    signals,
+		WorkingDir: config.WorkDir,
+	}
+	if config.Interactive {
+		opts.Mode = agent.ModeInteractive
+	}
+	if config.Trust {
+		opts.Permission = agent.PermissionBypass
+	}
+	// Add autonomous system prompt for headless mode
+	if !config.Interactive {
+		opts.SystemPrompt = agent.AutonomousSystemPrompt
+	}
+	return opts
+}
+
+// loadModelProviders returns the global provider routes by model size with
+// the project's on top
+func loadModelProviders(projectDir string) map[string]string {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model providers: %v\n", err)
+		config = &session.Config{}
+	}
+	projectConfig, err := session.LoadProjectConfig(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model providers: %v\n", err)
+		projectConfig = &session.ProjectConfig{}
+	}
+	return session.MergeModelOverrides(config.ModelProviders, projectConfig.ModelProviders)
+}
+
+// selectProviderForIteration returns the provider an iteration should use
+// instead of the run's and why, or "" to keep the run's provider.
+// Priority order:
+// 1. --provider flag (keeps the run's provider)
+// 2. A single active ball's AgentProvider override
+// 3. The provider routed for the selected model's size
+func selectProviderForIteration(config AgentLoopConfig, activeBalls []*session.Ball, model string, modelProviders map[string]string) (provider.Type, string) {
+	if config.Provider != "" {
+		return "", ""
+	}
+	if len(activeBalls) == 1 && activeBalls[0].AgentProvider != "" {
+		return provider.Type(activeBalls[0].AgentProvider), fmt.Sprintf("ball %s has agent_provider override", activeBalls[0].ShortID())
+	}
+	if routed := session.ResolveModelProvider(model, modelProviders, nil); routed != "" {
+		return provider.Type(routed), fmt.Sprintf("%s models are routed to %s", session.ModelProviderSize(model), routed)
+	}
+	return "", ""
 }
 
 // logCrashToProgress logs a crash event to the session's progress file
+// tripBrownout pauses a session after repeated agent errors: it records the
+// brownout, logs it to progress, and runs the notify command if configured.
+func tripBrownout(sessionStore *session.SessionStore, config AgentLoopConfig, storageID string, settings session.BrownoutSettings, errCount int, lastErr error) *session.Brownout {
+	now := time.Now()
+	b := &session.Brownout{
+		SessionID: storageID,
+		StartedAt: now,
+		Until:     now.Add(settings.Cooldown),
+		Errors:    errCount,
+	}
+	if lastErr != nil {
+		b.LastError = lastErr.Error()
+	}
+	if err := sessionStore.RecordBrownout(b); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record brownout: %v\n", err)
+	}
+
+	message := fmt.Sprintf("Agent failed %d times in a row, pausing session until %s", errCount, b.Until.Format("15:04"))
+	if b.LastError != "" {
+		message += " (last error: " + b.LastError + ")"
+	}
+	_ = sessionStore.AppendProgressEntry(storageID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryBlocker,
+		Text:     "[BROWNOUT] " + message,
+	})
+	fmt.Printf("🧯 %s\n", message)
+
+	if settings.NotifyCommand != "" {
+		notify := exec.Command("sh", "-c", settings.NotifyCommand)
+		notify.Dir = config.ProjectDir
+		notify.Env = append(os.Environ(),
+			"JUGGLE_SESSION="+config.SessionID,
+			"JUGGLE_PROJECT="+config.ProjectDir,
+			"JUGGLE_BROWNOUT_UNTIL="+b.Until.Format(time.RFC3339),
+			fmt.Sprintf("JUGGLE_BROWNOUT_ERRORS=%d", errCount),
+			"JUGGLE_BROWNOUT_ERROR="+b.LastError,
+		)
+		if out, err := notify.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: brownout notify command failed: %v\n%s", err, out)
+		}
+	}
+	return b
+}
+
 func logCrashToProgress(projectDir, sessionID, message string) {
 	sessionStore, err := session.NewSessionStore(projectDir)
 	if err != nil {
 		return // Ignore errors - logging is best-effort
 	}
 
-	entry := fmt.Sprintf("[CRASH] %s", message)
-	_ = sessionStore.AppendProgress(sessionID, entry)
+	_ = sessionStore.AppendProgressEntry(sessionID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryBlocker,
+		Text:     "[CRASH] " + message,
+	})
 }
 
 // SessionSelection holds the result of selecting a session for agent run
@@ -991,7 +1493,7 @@ func selectSessionForAgent(cwd string) (*SessionSelection, error) {
 	var idx int
 	_, err = fmt.Sscanf(input, "%d", &idx)
 	if err != nil || idx < 1 || idx > len(sessions) {
-		return nil, fmt.Errorf("invalid selection: %s", input)
+		return nil, validationErrorf("invalid selection: %s", input)
 	}
 
 	selected := sessions[idx-1]
@@ -1254,7 +1756,7 @@ func selectBallForAgent(cwd string, sessionFilter string) (*BallSelection, error
 	var idx int
 	_, err = fmt.Sscanf(input, "%d", &idx)
 	if err != nil || idx < 1 || idx > len(actionable) {
-		return nil, fmt.Errorf("invalid selection: %s", input)
+		return nil, validationErrorf("invalid selection: %s", input)
 	}
 
 	selected := actionable[idx-1]
@@ -1299,7 +1801,7 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 	if agentPickBall {
 		// --pick and --ball are mutually exclusive
 		if agentBallID != "" {
-			return fmt.Errorf("cannot use --pick with --ball (they are mutually exclusive)")
+			return usageErrorf("cannot use --pick with --ball (they are mutually exclusive)")
 		}
 
 		// Check terminal for interactive mode
@@ -1347,18 +1849,19 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 
 		// Run agent loop for the selected ball
 		_, err = RunAgentLoop(AgentLoopConfig{
-			SessionID:     selected.SessionID,
-			ProjectDir:    projectDir,
-			MaxIterations: 1,
-			BallID:        agentBallID,
-			Interactive:   true,
-			Model:         agentModel,
-			IterDelay:     0,
-			Timeout:       agentTimeout,
-			Trust:         agentTrust,
-			MaxWait:       agentMaxWait,
-			Provider:      agentProvider,
-			IgnoreLock:    agentIgnoreLock,
+			SessionID:        selected.SessionID,
+			ProjectDir:       projectDir,
+			MaxIterations:    1,
+			BallID:           agentBallID,
+			Interactive:      true,
+			Model:            agentModel,
+			IterDelay:        0,
+			Timeout:          agentTimeout,
+			Trust:            agentTrust,
+			MaxWait:          agentMaxWait,
+			Provider:         agentProvider,
+			IgnoreLock:       agentIgnoreLock,
+			IgnoreQuietHours: agentIgnoreQuiet,
 		})
 		return err
 	}
@@ -1416,9 +1919,45 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Load iteration delay settings (flags override config)
+	var iterDelay time.Duration
+	var delayMinutes, fuzz int
+
+	// Check if --delay flag was explicitly provided
+	if cmd.Flags().Changed("delay") {
+		delayMinutes = agentDelay
+		// Check if --fuzz was also provided, otherwise default to 0
+		if cmd.Flags().Changed("fuzz") {
+			fuzz = agentFuzz
+		}
+	} else {
+		// Load from config
+		var err error
+		delayMinutes, fuzz, err = session.GetGlobalIterationDelayWithOptions(GetConfigOptions())
+		if err != nil {
+			delayMinutes = 0
+			fuzz = 0
+		}
+		// Override fuzz from flag if set
+		if cmd.Flags().Changed("fuzz") {
+			fuzz = agentFuzz
+		}
+	}
+
 	// Handle --dry-run and --debug: show prompt info
 	if agentDryRun || agentDebug {
-		prompt, err := generateAgentPrompt(projectDir, sessionID, true, agentBallID, message) // debug=true for reasoning instructions
+		// A dry run reports on the session, so it has to exist
+		if agentDryRun && sessionID != "all" {
+			sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+			if err != nil {
+				return fmt.Errorf("failed to initialize session store: %w", err)
+			}
+			if _, err := sessionStore.LoadSession(sessionID); err != nil {
+				return session.NewSessionNotFoundError(sessionID)
+			}
+		}
+		redactions := session.ScrubReport{}
+		prompt, err := generateAgentPrompt(projectDir, sessionID, true, agentBallID, message, redactions) // debug=true for reasoning instructions
 		if err != nil {
 			return fmt.Errorf("failed to generate prompt: %w", err)
 		}
@@ -1447,6 +1986,9 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		if agentMaxWait > 0 {
 			fmt.Printf("Max rate limit wait: %v\n", agentMaxWait)
 		}
+		if redactions.Total() > 0 {
+			fmt.Printf("Redacted: %s\n", redactions)
+		}
 		fmt.Println()
 		fmt.Println("=== Generated Prompt ===")
 		fmt.Println()
@@ -1457,6 +1999,28 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		// If dry-run, exit without running
 		if agentDryRun {
 			fmt.Println()
+			budget, err := buildRunBudget(projectDir, sessionID, agentBallID, interactive, iterations, time.Duration(delayMinutes)*time.Minute, len(prompt))
+			if err != nil {
+				return err
+			}
+			printRunBudget(budget)
+			report, err := buildAgentDryRun(AgentLoopConfig{
+				SessionID:        sessionID,
+				ProjectDir:       projectDir,
+				Trust:            agentTrust,
+				Timeout:          agentTimeout,
+				BallID:           agentBallID,
+				Interactive:      interactive,
+				Model:            agentModel,
+				Provider:         agentProvider,
+				IgnoreLock:       agentIgnoreLock,
+				IgnoreQuietHours: agentIgnoreQuiet,
+				IgnoreBrownout:   agentSkipBrownout,
+			}, prompt)
+			if err != nil {
+				return err
+			}
+			printAgentDryRun(report)
 			fmt.Println("(Dry run - agent not started)")
 			return nil
 		}
@@ -1480,6 +2044,9 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Starting agent for session: %s\n", sessionID)
 	}
 	fmt.Printf("Max iterations: %d\n", iterations)
+	if getVCSBackendForProject(projectDir).Type() == vcs.VCSTypeNone {
+		fmt.Println("Not under version control: changes are left uncommitted, and revisions are snapshots in .juggle/snapshots")
+	}
 	fmt.Println()
 
 	// Print timeout if specified
@@ -1487,31 +2054,6 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Timeout per iteration: %v\n", agentTimeout)
 	}
 
-	// Load iteration delay settings (flags override config)
-	var iterDelay time.Duration
-	var delayMinutes, fuzz int
-
-	// Check if --delay flag was explicitly provided
-	if cmd.Flags().Changed("delay") {
-		delayMinutes = agentDelay
-		// Check if --fuzz was also provided, otherwise default to 0
-		if cmd.Flags().Changed("fuzz") {
-			fuzz = agentFuzz
-		}
-	} else {
-		// Load from config
-		var err error
-		delayMinutes, fuzz, err = session.GetGlobalIterationDelayWithOptions(GetConfigOptions())
-		if err != nil {
-			delayMinutes = 0
-			fuzz = 0
-		}
-		// Override fuzz from flag if set
-		if cmd.Flags().Changed("fuzz") {
-			fuzz = agentFuzz
-		}
-	}
-
 	// If delay is 0, skip the delay feature entirely (regardless of fuzz)
 	if delayMinutes > 0 {
 		iterDelay = calculateFuzzyDelay(delayMinutes, fuzz)
@@ -1522,6 +2064,18 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Before an unattended loop, show its budget and let the user back out
+	if !interactive && !agentYes && isTerminal(os.Stdin.Fd()) {
+		started, err := confirmRunBudget(projectDir, sessionID, agentBallID, iterations, time.Duration(delayMinutes)*time.Minute, message)
+		if err != nil {
+			return err
+		}
+		if !started {
+			fmt.Println("Run not started. Adjust --iterations, --timeout or --delay and run again.")
+			return nil
+		}
+	}
+
 	// Clear session progress if requested
 	if agentClearProgress {
 		sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
@@ -1542,6 +2096,17 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// Keep the agent's changes off the main working tree
+	var workDir string
+	if agentWorktree || useAgentWorktree(projectDir) {
+		worktree, err := ensureAgentWorktree(projectDir, sessionID)
+		if err != nil {
+			return err
+		}
+		workDir = worktree.Path
+		fmt.Println()
+	}
+
 	// Run the agent loop
 	loopConfig := AgentLoopConfig{
 		SessionID:            sessionID,
@@ -1559,18 +2124,31 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 		Provider:             agentProvider,   // Use CLI flag (empty = auto-detect from config)
 		IgnoreLock:           agentIgnoreLock, // Skip lock acquisition if set
 		Message:              message,         // User message to append to prompt
+		IgnoreQuietHours:     agentIgnoreQuiet,
+		IgnoreBrownout:       agentSkipBrownout,
+		SafeMode:             agentSafeMode,
+		Label:                strings.TrimSpace(agentLabel),
+		WorkDir:              workDir,
 	}
 
 	result, err := RunAgentLoop(loopConfig)
 	if err != nil {
 		return err
 	}
+	return reportAgentRun(loopConfig, result)
+}
 
+// reportAgentRun prints the summary of a finished agent run, returning an
+// error for runs that gave up so scripts can tell them from finished ones
+func reportAgentRun(loopConfig AgentLoopConfig, result *AgentResult) error {
 	elapsed := result.EndedAt.Sub(result.StartedAt)
 
 	// Print summary
 	fmt.Println()
 	fmt.Println("=== Summary ===")
+	if loopConfig.Label != "" {
+		fmt.Printf("Label: %s\n", loopConfig.Label)
+	}
 	fmt.Printf("Iterations: %d\n", result.Iterations)
 	fmt.Printf("Balls: %d complete, %d blocked, %d total\n", result.BallsComplete, result.BallsBlocked, result.BallsTotal)
 	fmt.Printf("Time elapsed: %s\n", elapsed.Round(time.Second))
@@ -1581,30 +2159,90 @@ func runAgentRun(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  - Overload (529) retries: %d (waited %v)\n", result.OverloadRetries, result.OverloadWaitTime.Round(time.Second))
 		}
 	}
+	if result.PausedTime > 0 {
+		fmt.Printf("Time paused: %v\n", result.PausedTime.Round(time.Second))
+	}
 
-	if result.Complete {
-		fmt.Println("Status: COMPLETE")
+	if result.Cancelled {
+		fmt.Println("Status: CANCELLED")
+	} else if result.Complete {
+		fmt.Println("Status: COMPLETE" + humanSignalSuffix(result))
 	} else if result.Blocked {
-		fmt.Printf("Status: BLOCKED (%s)\n", result.BlockedReason)
+		fmt.Printf("Status: BLOCKED (%s)%s\n", result.BlockedReason, humanSignalSuffix(result))
 	} else if result.TimedOut {
 		fmt.Printf("Status: TIMEOUT (%s)\n", result.TimeoutMessage)
 	} else if result.RateLimitExceded {
-		fmt.Printf("Status: RATE_LIMIT_EXCEEDED (max-wait: %v)\n", agentMaxWait)
+		fmt.Printf("Status: RATE_LIMIT_EXCEEDED (max-wait: %v)\n", loopConfig.MaxWait)
+	} else if result.Brownout != nil {
+		fmt.Printf("Status: BROWNOUT (paused until %s)\n", result.Brownout.Until.Format("15:04"))
 	} else {
 		fmt.Println("Status: Max iterations reached")
 	}
 
+	if result.Redactions.Total() > 0 {
+		fmt.Printf("Redacted: %s\n", result.Redactions)
+	}
+
+	printDuplicateBalls(result.Duplicates)
+	if result.SafeMode != nil {
+		printSafeModeOutcome(result.SafeMode)
+	}
+
 	// Map "all" meta-session to "_all" for output path
-	outputStorageID := sessionStorageID(sessionID)
-	outputPath := filepath.Join(projectDir, ".juggle", "sessions", outputStorageID, "last_output.txt")
+	outputStorageID := sessionStorageID(loopConfig.SessionID)
+	outputPath := filepath.Join(loopConfig.ProjectDir, ".juggle", "sessions", outputStorageID, "last_output.txt")
 	fmt.Printf("\nOutput saved to: %s\n", outputPath)
 
+	promptRunNote(loopConfig.ProjectDir, result.RunID)
+
+	// Exit nonzero so scripts can tell the agent gave up rather than finished
+	if result.RateLimitExceded {
+		return &CLIError{
+			Code:    CodeRateLimited,
+			Message: fmt.Sprintf("rate limit exceeded (max-wait: %v)", loopConfig.MaxWait),
+			Hint:    "retry later or raise --max-wait",
+		}
+	}
+	if result.Brownout != nil {
+		return &CLIError{
+			Code:    CodeBrownout,
+			Message: fmt.Sprintf("session %s paused until %s after %d consecutive agent errors", loopConfig.SessionID, result.Brownout.Until.Format("15:04"), result.Brownout.Errors),
+			Hint:    "check " + outputPath + ", then rerun with --ignore-brownout to resume early",
+		}
+	}
+
 	return nil
 }
 
+// promptRunNote offers to note down how the run went. Skipped without a
+// terminal; the note can be added later with 'agent history annotate'.
+func promptRunNote(projectDir, runID string) {
+	if !isTerminal(os.Stdin.Fd()) {
+		return
+	}
+	fmt.Print("Note for this run (Enter to skip): ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	note := strings.TrimSpace(line)
+	if note == "" {
+		return
+	}
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
+	if err != nil {
+		return
+	}
+	if _, err := historyStore.UpdateRecord(runID, func(record *session.AgentRunRecord) {
+		record.Note = note
+	}); err != nil {
+		fmt.Printf("Warning: failed to save note: %v\n", err)
+	}
+}
+
 // generateAgentPrompt generates the agent prompt using export command.
 // The message parameter, if non-empty, is appended to the end of the generated prompt.
-func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string, message string) (string, error) {
+// Secrets matched by the configured scrub rules are redacted and counted in report (may be nil).
+func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string, message string, report session.ScrubReport) (string, error) {
+	defer profile.Start(profile.KindPrompt, "agent")()
+
 	// Use the export functionality directly instead of shelling out
 	// This is more efficient and avoids subprocess overhead
 
@@ -1670,14 +2308,17 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 	if ballID != "" {
 		matches := session.ResolveBallByPrefix(balls, ballID)
 		if len(matches) == 0 {
-			return "", fmt.Errorf("ball %s not found in session %s", ballID, sessionID)
+			return "", notFoundErrorf("ball %s not found in session %s", ballID, sessionID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return "", fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+			return "", validationErrorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+		}
+		if matches[0].IsEscalated() {
+			return "", validationErrorf("ball %s is escalated to a human owner; resolve it first: juggle escalate %s --resolve", matches[0].ShortID(), matches[0].ShortID())
 		}
 		balls = []*session.Ball{matches[0]}
 		singleBall = true
@@ -1689,7 +2330,12 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 		return "", err
 	}
 
-	prompt := string(output)
+	// Redact secrets from ball text, session context, and progress
+	scrubber, err := session.NewScrubber(config.Scrub)
+	if err != nil {
+		return "", validationErrorf("invalid scrub settings: %w", err)
+	}
+	prompt := scrubber.Scrub(string(output), report)
 
 	// Append user message if provided
 	if message != "" {
@@ -1699,6 +2345,21 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 	return prompt, nil
 }
 
+// loadScrubber returns the secret scrubber from the global config, or nil
+// (no scrubbing) if it isn't configured or can't be read
+func loadScrubber() *session.Scrubber {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load scrub settings: %v\n", err)
+		return nil
+	}
+	scrubber, err := session.NewScrubber(config.Scrub)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid scrub settings: %v\n", err)
+	}
+	return scrubber
+}
+
 // countWorkableBalls returns counts of balls the agent can work on (pending/in_progress) vs blocked
 // This is used for pre-loop validation to exit early when there's no actionable work
 // Balls in complete/researched states are excluded (same as agent export)
@@ -1706,28 +2367,35 @@ func generateAgentPrompt(projectDir, sessionID string, debug bool, ballID string
 // If interactive is true, blocked balls are treated as workable (human is present to intervene)
 // "all" is a special meta-session that includes all balls in the repo without filtering by tag
 func countWorkableBalls(projectDir, sessionID, ballID string, interactive bool) (workable, blocked, total int, err error) {
+	balls, blocked, total, err := listWorkableBalls(projectDir, sessionID, ballID, interactive)
+	return len(balls), blocked, total, err
+}
+
+// listWorkableBalls returns the balls the agent can work on, and counts of
+// blocked and total balls, as countWorkableBalls counts them
+func listWorkableBalls(projectDir, sessionID, ballID string, interactive bool) (workable []*session.Ball, blocked, total int, err error) {
 	// Load config
 	config, err := LoadConfigForCommand()
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to load config: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Create store
 	store, err := NewStoreForCommand(projectDir)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to create store: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to create store: %w", err)
 	}
 
 	// Discover projects
 	projects, err := DiscoverProjectsForCommand(config, store)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to discover projects: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to discover projects: %w", err)
 	}
 
 	// Load all balls
 	allBalls, err := session.LoadAllBalls(projects)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("failed to load balls: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to load balls: %w", err)
 	}
 
 	// "all" is a meta-session that means "all balls in repo"
@@ -1759,13 +2427,16 @@ func countWorkableBalls(projectDir, sessionID, ballID string, interactive bool)
 			case session.StateComplete, session.StateResearched:
 				continue
 			case session.StatePending, session.StateInProgress:
-				workable++
+				workable = append(workable, ball)
 				total++
 			case session.StateBlocked:
 				// If user is running interactively or explicitly targeted this ball,
-				// treat it as workable (they ARE the human intervention)
-				if interactive || (ballID != "" && (ball.ID == ballID || ball.ShortID() == ballID)) {
-					workable++
+				// treat it as workable (they ARE the human intervention), unless
+				// it has been escalated to someone else
+				if ball.IsEscalated() {
+					blocked++
+				} else if interactive || (ballID != "" && (ball.ID == ballID || ball.ShortID() == ballID)) {
+					workable = append(workable, ball)
 				} else {
 					blocked++
 				}
@@ -1777,6 +2448,30 @@ func countWorkableBalls(projectDir, sessionID, ballID string, interactive bool)
 	return workable, blocked, total, nil
 }
 
+// unmetSessionExitCriteria evaluates the exit criteria of the session a
+// run works on, returning the unmet ones. Single-ball runs and the "all"
+// meta-session have no exit criteria. The session is reloaded, since the
+// agent may have marked criteria met during the run.
+func unmetSessionExitCriteria(sessionStore *session.SessionStore, config AgentLoopConfig) []session.ExitCriterion {
+	if config.SessionID == "all" || config.BallID != "" {
+		return nil
+	}
+	sess, err := sessionStore.LoadSession(config.SessionID)
+	if err != nil || len(sess.ExitCriteria) == 0 {
+		return nil
+	}
+	return evaluateSessionExitCriteria(sessionStore, sess, config.ProjectDir)
+}
+
+// printUnmetExitCriteria warns that the run goes on because exit criteria
+// are unmet
+func printUnmetExitCriteria(reason string, unmet []session.ExitCriterion) {
+	fmt.Printf("⚠️  %s but %d exit criteria are unmet. Continuing...\n", reason, len(unmet))
+	for _, criterion := range unmet {
+		fmt.Printf("   - %s\n", criterion.Text)
+	}
+}
+
 // checkBallsTerminal returns counts of balls in terminal states (complete or blocked) and total balls for session
 // If ballID is specified, only counts that specific ball
 // "all" is a special meta-session that includes all balls in the repo without filtering by tag
@@ -1848,8 +2543,48 @@ func logTimeoutToProgress(projectDir, sessionID, message string) {
 		return // Ignore errors - logging is best-effort
 	}
 
-	entry := fmt.Sprintf("[TIMEOUT] %s", message)
-	_ = sessionStore.AppendProgress(sessionID, entry)
+	_ = sessionStore.AppendProgressEntry(sessionID, session.ProgressEntry{
+		Author:   session.ProgressAuthorAgent,
+		Category: session.ProgressCategoryBlocker,
+		Text:     "[TIMEOUT] " + message,
+	})
+}
+
+// takeHumanSignal takes the signal a person sent to the session's loop, if
+// any, and logs it to progress
+func takeHumanSignal(sessionStore *session.SessionStore, projectDir, storageID string) *session.HumanSignal {
+	human, err := sessionStore.TakeHumanSignal(storageID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	if human == nil {
+		return nil
+	}
+	if human.Signal == session.LoopSignalBlocked && human.Reason == "" {
+		human.Reason = "blocked by a human"
+	}
+	entry := session.ProgressEntry{
+		Author:   session.ProgressAuthorHuman,
+		Category: session.ProgressCategoryDecision,
+		Text:     fmt.Sprintf("[HUMAN] Signaled %s", strings.ToUpper(string(human.Signal))),
+	}
+	if human.Signal == session.LoopSignalBlocked {
+		entry.Category = session.ProgressCategoryBlocker
+	}
+	if human.Reason != "" {
+		entry.Text += ": " + human.Reason
+	}
+	_ = sessionStore.AppendProgressEntry(storageID, entry)
+	return human
+}
+
+// humanSignalSuffix notes a run status that a person's signal decided
+func humanSignalSuffix(result *AgentResult) string {
+	if result.HumanSignal == "" || result.HumanSignal == session.LoopSignalContinue {
+		return ""
+	}
+	return " [human signal]"
 }
 
 // getProgressLineCount returns the number of lines in the session's progress file.
@@ -1876,19 +2611,58 @@ func GetProgressLineCountForTest(store *session.SessionStore, sessionID string)
 	return getProgressLineCount(store, sessionID)
 }
 
-// saveAgentHistory saves the agent run history to the history file
-func saveAgentHistory(config AgentLoopConfig, result *AgentResult, outputPath string) {
-	historyStore, err := session.NewAgentHistoryStore(config.ProjectDir)
+// openAgentLog starts the log a run's agent output is streamed to, with
+// secrets scrubbed. Logging is best-effort: on failure it warns and
+// returns nil.
+func openAgentLog(projectDir, storageID string, start time.Time, scrubber *session.Scrubber) *agentlog.Writer {
+	settings, err := session.GetGlobalAgentLogsWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load agent log config: %v\n", err)
+	}
+	opts := agentlog.Options{
+		MaxSize:   int64(settings.MaxSizeMB) * 1024 * 1024,
+		MaxFiles:  settings.MaxFiles,
+		Retention: time.Duration(settings.RetentionDays) * 24 * time.Hour,
+		Filter: func(line string) string {
+			return scrubber.Scrub(line, nil)
+		},
+	}
+	dir := filepath.Join(projectDir, ".juggle", agentlog.DirName)
+	logWriter, err := agentlog.Create(dir, storageID, start, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return logWriter
+}
+
+// saveAgentHistory saves the agent run record to the history file
+func saveAgentHistory(projectDir string, record *session.AgentRunRecord) {
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
 	if err != nil {
 		return // Best-effort, ignore errors
 	}
 
+	_ = historyStore.AppendRecord(record)
+	_ = historyStore.PruneIterationOutputs(session.KeptRunOutputs)
+}
+
+// newAgentRunRecord builds the history record of a finished run
+func newAgentRunRecord(config AgentLoopConfig, result *AgentResult, outputPath string) *session.AgentRunRecord {
 	record := session.NewAgentRunRecord(config.SessionID, config.ProjectDir, result.StartedAt)
 	record.MaxIterations = config.MaxIterations
 	record.OutputFile = outputPath
+	record.LogFile = result.LogFile
+	record.Label = config.Label
+	record.Phase = config.Phase
+	record.LinkedRunID = config.LinkedRunID
+	record.HumanSignal = result.HumanSignal
+	record.IterationOutcomes = result.IterationOutcomes
 
 	// Set the appropriate result type
-	if result.Complete {
+	if result.Cancelled {
+		record.SetCancelled(result.Iterations, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
+	} else if result.Complete {
 		record.SetComplete(result.Iterations, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
 	} else if result.Blocked {
 		record.SetBlocked(result.Iterations, result.BlockedReason, result.BallsComplete, result.BallsBlocked, result.BallsTotal)
@@ -1904,6 +2678,31 @@ func saveAgentHistory(config AgentLoopConfig, result *AgentResult, outputPath st
 	// Preserve total wait time and ended time from result
 	record.TotalWaitTime = result.TotalWaitTime
 	record.EndedAt = result.EndedAt
+	record.SafeMode = result.SafeMode
+	if result.Redactions.Total() > 0 {
+		record.Redactions = result.Redactions
+	}
+	return record
+}
+
+// saveAgentErrorHistory records a run that ended with an error. Only safe
+// mode does this, so the revert decision isn't lost with the run.
+func saveAgentErrorHistory(config AgentLoopConfig, result *AgentResult, outputPath string, runErr error, outcome *session.SafeModeOutcome) {
+	historyStore, err := session.NewAgentHistoryStore(config.ProjectDir)
+	if err != nil {
+		return // Best-effort, ignore errors
+	}
+
+	record := session.NewAgentRunRecord(config.SessionID, config.ProjectDir, result.StartedAt)
+	record.MaxIterations = config.MaxIterations
+	record.OutputFile = outputPath
+	record.LogFile = result.LogFile
+	record.SetError(result.Iterations, runErr.Error(), result.BallsComplete, result.BallsBlocked, result.BallsTotal)
+	record.Label = config.Label
+	record.Phase = config.Phase
+	record.LinkedRunID = config.LinkedRunID
+	record.SafeMode = outcome
+	record.IterationOutcomes = result.IterationOutcomes
 
 	_ = historyStore.AppendRecord(record)
 }
@@ -1977,7 +2776,7 @@ func runAgentRefine(cmd *cobra.Command, args []string) error {
 			providerType, provider.BinaryName(providerType))
 	}
 
-	agentProv := provider.Get(providerType)
+	agentProv := newAgentProvider(providerType)
 	agent.SetProvider(agentProv)
 
 	// Configure model overrides
@@ -2071,6 +2870,8 @@ func loadBallsForRefine(projectDir, sessionID string) ([]*session.Ball, error) {
 // generateRefinePrompt creates the prompt for the refinement session.
 // The message parameter, if non-empty, is appended to the end of the generated prompt.
 func generateRefinePrompt(projectDir, sessionID string, balls []*session.Ball, message string) (string, error) {
+	defer profile.Start(profile.KindPrompt, "refine")()
+
 	var buf strings.Builder
 
 	// Write context section with session info if available
@@ -2174,12 +2975,12 @@ func RunAgentRefineForTest(projectDir, sessionID string) error {
 
 // GenerateAgentPromptForTest is an exported wrapper for testing prompt generation
 func GenerateAgentPromptForTest(projectDir, sessionID string, debug bool, ballID string) (string, error) {
-	return generateAgentPrompt(projectDir, sessionID, debug, ballID, "")
+	return generateAgentPrompt(projectDir, sessionID, debug, ballID, "", nil)
 }
 
 // GenerateAgentPromptWithMessageForTest is an exported wrapper for testing prompt generation with a message
 func GenerateAgentPromptWithMessageForTest(projectDir, sessionID string, debug bool, ballID string, message string) (string, error) {
-	return generateAgentPrompt(projectDir, sessionID, debug, ballID, message)
+	return generateAgentPrompt(projectDir, sessionID, debug, ballID, message, nil)
 }
 
 // writeBallForRefine writes a single ball with all details for refinement
@@ -2460,14 +3261,14 @@ func loadBallsForModelSelection(projectDir, sessionID, ballID string) ([]*sessio
 	if ballID != "" {
 		matches := session.ResolveBallByPrefix(balls, ballID)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball %s not found in session %s", ballID, sessionID)
+			return nil, notFoundErrorf("ball %s not found in session %s", ballID, sessionID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+			return nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
 		}
 		return []*session.Ball{matches[0]}, nil
 	}
@@ -2492,15 +3293,16 @@ type CommitResult struct {
 // This is called by juggle after the agent signals completion.
 // Returns nil if there are no changes to commit.
 func performVCSCommit(projectDir, commitMessage string) (*CommitResult, error) {
-	// Load VCS settings
-	globalVCS, _ := session.GetGlobalVCSWithOptions(GetConfigOptions())
-	projectVCS, _ := session.GetProjectVCS(projectDir)
+	return performVCSCommitIn(projectDir, projectDir, commitMessage)
+}
 
-	// Get the appropriate backend
-	backend := vcs.GetBackendForProject(projectDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS))
+// performVCSCommitIn commits the changes in workDir, such as an agent
+// worktree, with the VCS configured for projectDir.
+func performVCSCommitIn(projectDir, workDir, commitMessage string) (*CommitResult, error) {
+	backend := getVCSBackendForProject(projectDir)
 
 	// Perform commit
-	vcsResult, err := backend.Commit(projectDir, commitMessage)
+	vcsResult, err := backend.Commit(workDir, commitMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	datasetSessions []string
+	datasetOutcomes []string
+	datasetLabel    string
+	datasetFormat   string
+	datasetOutput   string
+)
+
+// datasetOutcomeNames lists the iteration outcomes --outcome accepts
+var datasetOutcomeNames = []string{
+	session.IterationCompleted,
+	session.IterationContinued,
+	session.IterationBlocked,
+	session.IterationRejectedSignal,
+	session.IterationNoSignal,
+	session.IterationTimeout,
+	session.IterationHumanOverride,
+}
+
+var agentExportDatasetCmd = &cobra.Command{
+	Use:   "export-dataset",
+	Short: "Export agent prompts and responses as a dataset",
+	Long: `Export each agent iteration's prompt paired with the agent's response,
+labeled with how the iteration ended, for prompt experiments or fine-tuning
+a local model on this project's loop history.
+
+Outcomes:
+  completed        COMPLETE signal, accepted
+  continued        CONTINUE signal, accepted
+  blocked          BLOCKED signal, accepted
+  rejected-signal  A signal was given but rejected (progress not updated,
+                   or COMPLETE while balls were still open)
+  no-signal        No signal was given
+  timeout          The iteration timed out
+  human-override   A person's 'agent signal' replaced the agent's own
+
+Formats:
+  jsonl  One JSON object per iteration: run, session, model, prompt,
+         response, outcome and the run's result
+  chat   One {"messages": [user, assistant]} object per iteration, the
+         layout fine-tuning tools take; filter it with --outcome
+
+Prompts and responses are kept for the most recent agent runs only, and
+prompts are saved from this version on, so older iterations are skipped.
+Secrets are scrubbed from both if scrubbing is configured.
+
+Examples:
+  juggle agent export-dataset -o dataset.jsonl
+  juggle agent export-dataset --sessions auth,billing --outcome completed --format chat`,
+	Args: cobra.NoArgs,
+	RunE: runAgentExportDataset,
+}
+
+func init() {
+	agentExportDatasetCmd.Flags().StringSliceVar(&datasetSessions, "sessions", nil, "Only export runs of these sessions (comma-separated)")
+	agentExportDatasetCmd.Flags().StringSliceVar(&datasetOutcomes, "outcome", nil, "Only export iterations with these outcomes (comma-separated)")
+	agentExportDatasetCmd.Flags().StringVar(&datasetLabel, "label", "", "Only export runs with this label (see agent run --label)")
+	agentExportDatasetCmd.Flags().StringVar(&datasetFormat, "format", "jsonl", "Output format: jsonl or chat")
+	agentExportDatasetCmd.Flags().StringVarP(&datasetOutput, "output", "o", "", "File to write (default: stdout)")
+
+	agentCmd.AddCommand(agentExportDatasetCmd)
+}
+
+func runAgentExportDataset(cmd *cobra.Command, args []string) error {
+	if datasetFormat != "jsonl" && datasetFormat != "chat" {
+		return validationErrorf("unknown format %q (must be jsonl or chat)", datasetFormat)
+	}
+	for _, outcome := range datasetOutcomes {
+		if !slices.Contains(datasetOutcomeNames, outcome) {
+			return validationErrorf("unknown outcome %q (must be one of: %s)", outcome, strings.Join(datasetOutcomeNames, ", "))
+		}
+	}
+
+	historyStore, err := newAgentHistoryStoreForCommand()
+	if err != nil {
+		return err
+	}
+	sessions := make([]string, len(datasetSessions))
+	for i, id := range datasetSessions {
+		sessions[i] = sessionStorageID(id)
+	}
+	examples, err := historyStore.BuildDataset(session.DatasetFilter{
+		Sessions: sessions,
+		Outcomes: datasetOutcomes,
+		Label:    datasetLabel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build dataset: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if datasetOutput != "" {
+		f, err := os.Create(datasetOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", datasetOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	if err := writeDataset(w, examples, datasetFormat); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+
+	if datasetOutput != "" {
+		fmt.Printf("✓ Exported %d iteration(s) to %s\n", len(examples), datasetOutput)
+	}
+	return nil
+}
+
+// datasetMessage is one turn of a chat-format example
+type datasetMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// writeDataset writes one JSON line per example
+func writeDataset(w io.Writer, examples []session.DatasetExample, format string) error {
+	enc := json.NewEncoder(w)
+	for _, example := range examples {
+		var line any = example
+		if format == "chat" {
+			line = struct {
+				Messages []datasetMessage `json:"messages"`
+			}{[]datasetMessage{
+				{Role: "user", Content: example.Prompt},
+				{Role: "assistant", Content: example.Response},
+			}}
+		}
+		if err := enc.Encode(line); err != nil {
+			return fmt.Errorf("failed to write dataset: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// dryRunCheck is one of the checks an agent run makes before its first
+// iteration, as agent run --dry-run reports it
+type dryRunCheck struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// agentDryRunReport is what agent run --dry-run found an agent run would do
+type agentDryRunReport struct {
+	Checks   []dryRunCheck
+	Balls    []*session.Ball // Balls the run would work on
+	Provider provider.Type   // Provider of the first iteration
+	Model    *ModelSelection // Model of the first iteration
+	Command  string          // What the runner executes for the first iteration
+}
+
+// buildAgentDryRun makes the checks RunAgentLoop makes before its first
+// iteration without acting on them: locks are probed rather than taken,
+// and exit criteria checks run without their results being recorded. It
+// also works out how the first iteration would invoke the runner.
+func buildAgentDryRun(config AgentLoopConfig, prompt string) (*agentDryRunReport, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(config.ProjectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	var juggleSession *session.JuggleSession
+	if config.SessionID != "all" {
+		juggleSession, err = sessionStore.LoadSession(config.SessionID)
+		if err != nil {
+			return nil, session.NewSessionNotFoundError(config.SessionID)
+		}
+	}
+	storageID := sessionStorageID(config.SessionID)
+	now := time.Now()
+	report := &agentDryRunReport{}
+
+	lock := dryRunCheck{Name: "Lock", OK: true, Detail: "free"}
+	var locked bool
+	var info *session.LockInfo
+	switch {
+	case config.IgnoreLock:
+		lock.Detail = "not taken (--ignore-lock)"
+	case config.BallID != "":
+		locked, info = session.IsBallLocked(config.ProjectDir, config.BallID)
+	default:
+		locked, info = sessionStore.IsLocked(storageID)
+	}
+	if locked {
+		lock.OK = false
+		lock.Detail = "held by another agent run"
+		if info != nil {
+			lock.Detail = fmt.Sprintf("held by PID %d on %s since %s", info.PID, info.Hostname, info.StartedAt.Format("15:04"))
+		}
+	}
+	report.Checks = append(report.Checks, lock)
+
+	brownout := dryRunCheck{Name: "Brownout", OK: true, Detail: "none"}
+	if previous, err := sessionStore.LoadBrownout(storageID); err == nil && previous.Active(now) {
+		brownout.Detail = fmt.Sprintf("paused until %s after %d consecutive agent errors", previous.Until.Format("15:04"), previous.Errors)
+		if config.IgnoreBrownout {
+			brownout.Detail += " (ignored)"
+		} else {
+			brownout.OK = false
+		}
+	}
+	report.Checks = append(report.Checks, brownout)
+
+	quiet := dryRunCheck{Name: "Quiet hours", OK: true, Detail: "not in quiet hours"}
+	if config.IgnoreQuietHours {
+		quiet.Detail = "ignored"
+	} else if _, quietHours, err := session.GetGlobalScheduleWithOptions(GetConfigOptions()); err == nil {
+		if end, ok := session.QuietHoursEnd(now, quietHours); ok {
+			quiet.OK = false
+			quiet.Detail = fmt.Sprintf("the run would wait until %s", end.Format("15:04 MST"))
+		}
+	}
+	report.Checks = append(report.Checks, quiet)
+
+	providerType := detectRunProvider(config, juggleSession)
+	available := dryRunCheck{Name: "Provider", OK: provider.IsAvailable(providerType), Detail: providerType.String()}
+	if !available.OK {
+		available.Detail = fmt.Sprintf("%s (binary %q not found in PATH)", providerType, provider.BinaryName(providerType))
+	}
+	report.Checks = append(report.Checks, available)
+
+	workable, blockedCount, _, err := listWorkableBalls(config.ProjectDir, config.SessionID, config.BallID, config.Interactive)
+	if err != nil {
+		return nil, fmt.Errorf("checking workable balls: %w", err)
+	}
+	report.Balls = workable
+	balls := dryRunCheck{Name: "Workable balls", OK: len(workable) > 0, Detail: fmt.Sprintf("%d workable, %d blocked", len(workable), blockedCount)}
+	if len(workable) == 0 {
+		balls.Detail += ", the run would end without an iteration"
+	}
+	report.Checks = append(report.Checks, balls)
+
+	if juggleSession != nil && config.BallID == "" && len(juggleSession.ExitCriteria) > 0 {
+		criteria := session.EvaluateExitCriteria(juggleSession.ExitCriteria, exitCheckRunner(config.ProjectDir))
+		unmet := session.UnmetExitCriteria(criteria)
+		exit := dryRunCheck{Name: "Exit criteria", OK: len(unmet) == 0,
+			Detail: fmt.Sprintf("%d/%d met", len(criteria)-len(unmet), len(criteria))}
+		if len(unmet) > 0 {
+			exit.Detail += ", COMPLETE is rejected until all are"
+		}
+		report.Checks = append(report.Checks, exit)
+	}
+
+	// The first iteration's model and provider, picked as the loop picks them
+	modelBalls, err := loadBallsForModelSelection(config.ProjectDir, config.SessionID, config.BallID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls for model selection: %w", err)
+	}
+	var sessionDefaultModel session.ModelSize
+	if juggleSession != nil {
+		sessionDefaultModel = juggleSession.DefaultModel
+	}
+	report.Model = selectModelForIteration(config, modelBalls, sessionDefaultModel)
+
+	modelProviders := loadModelProviders(config.ProjectDir)
+	if config.Provider != "" || (juggleSession != nil && juggleSession.AgentProvider != "") {
+		modelProviders = nil
+	}
+	report.Provider = providerType
+	if iterationProvider, _ := selectProviderForIteration(config, filterActiveBalls(modelBalls), report.Model.Model, modelProviders); iterationProvider != "" && provider.IsAvailable(iterationProvider) {
+		report.Provider = iterationProvider
+	}
+
+	runner := &agent.ProviderRunner{
+		Provider:       newAgentProvider(report.Provider),
+		ModelOverrides: loadRunModelOverrides(config.ProjectDir),
+	}
+	opts := agentRunOptions(config, prompt, report.Model.Model, loadSignalOptions())
+	report.Command = runner.Describe(opts)
+	return report, nil
+}
+
+// printAgentDryRun shows what an agent run would do
+func printAgentDryRun(report *agentDryRunReport) {
+	fmt.Println("=== Pre-loop Checks ===")
+	for _, check := range report.Checks {
+		mark := "✓"
+		if !check.OK {
+			mark = "✗"
+		}
+		fmt.Printf("%s %s: %s\n", mark, check.Name, check.Detail)
+	}
+	fmt.Println()
+
+	fmt.Println("=== Balls To Work ===")
+	if len(report.Balls) == 0 {
+		fmt.Println("(none)")
+	}
+	for _, ball := range report.Balls {
+		fmt.Printf("%s [%s] %s\n", ball.ShortID(), ball.State, ball.Title)
+	}
+	fmt.Println()
+
+	fmt.Println("=== Runner ===")
+	fmt.Printf("Provider: %s\n", report.Provider)
+	fmt.Printf("Model: %s (%s)\n", report.Model.Model, report.Model.Reason)
+	fmt.Printf("Command: %s\n", report.Command)
+	fmt.Println()
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentHistorySession string
+	agentHistoryLimit   int
+	annotateLabel       string
+)
+
+var agentHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List past agent runs with their labels and notes",
+	Long: `List past agent runs in this project, most recent first.
+
+Runs are identified by run ID (the start timestamp). Label runs at launch
+with 'agent run --label' and note how they went with 'agent history
+annotate', so experiments can be compared later.
+
+Examples:
+  juggle agent history
+  juggle agent history --session my-feature --limit 5
+  juggle agent history annotate latest "Prompt fix helped; blocked on auth"
+  juggle agent history annotate 1760000000000000000 --label baseline`,
+	Args: cobra.NoArgs,
+	RunE: runAgentHistory,
+}
+
+var agentHistoryAnnotateCmd = &cobra.Command{
+	Use:   "annotate <run-id> [note]",
+	Short: "Add a note (or change the label) of a past agent run",
+	Long: `Add a note to a past agent run, replacing any earlier note.
+
+Use "latest" for the most recent run. An empty note ("") clears it.
+--label changes the run's label.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAgentHistoryAnnotate,
+}
+
+func init() {
+	agentHistoryCmd.Flags().StringVarP(&agentHistorySession, "session", "s", "", "Only show runs for this session")
+	agentHistoryCmd.Flags().IntVar(&agentHistoryLimit, "limit", 20, "Maximum number of runs to show (0 = no limit)")
+	agentHistoryAnnotateCmd.Flags().StringVar(&annotateLabel, "label", "", "Set the run's label")
+
+	agentHistoryCmd.AddCommand(agentHistoryAnnotateCmd)
+	agentCmd.AddCommand(agentHistoryCmd)
+}
+
+func runAgentHistory(cmd *cobra.Command, args []string) error {
+	historyStore, err := newAgentHistoryStoreForCommand()
+	if err != nil {
+		return err
+	}
+
+	var records []*session.AgentRunRecord
+	if agentHistorySession != "" {
+		records, err = historyStore.LoadHistoryBySession(agentHistorySession)
+	} else {
+		records, err = historyStore.LoadHistory()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load agent history: %w", err)
+	}
+	if agentHistoryLimit > 0 && len(records) > agentHistoryLimit {
+		records = records[:agentHistoryLimit]
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No agent runs recorded yet.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN ID\tSTARTED\tSESSION\tRESULT\tITER\tBALLS\tLABEL\tNOTE")
+	for _, record := range records {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d/%d\t%d/%d\t%s\t%s\n",
+			record.ID,
+			record.StartedAt.Format("2006-01-02 15:04"),
+			record.SessionID,
+			historyRunResult(record),
+			record.Iterations, record.MaxIterations,
+			record.BallsComplete, record.BallsTotal,
+			historyRunLabel(record),
+			truncate(record.Note, 50),
+		)
+	}
+	return tw.Flush()
+}
+
+// historyRunResult returns the run's result, marked when a person's signal
+// decided it
+func historyRunResult(record *session.AgentRunRecord) string {
+	if record.HumanSignal == session.LoopSignalComplete || record.HumanSignal == session.LoopSignalBlocked {
+		return record.Result + " (human)"
+	}
+	return record.Result
+}
+
+// historyRunLabel returns the run's label, followed by its phase if it was
+// part of a plan-then-run workflow
+func historyRunLabel(record *session.AgentRunRecord) string {
+	if phase := record.PhaseSummary(); phase != "" {
+		return strings.TrimSpace(record.Label + " (" + phase + ")")
+	}
+	return record.Label
+}
+
+func runAgentHistoryAnnotate(cmd *cobra.Command, args []string) error {
+	labelChanged := cmd.Flags().Changed("label")
+	if len(args) < 2 && !labelChanged {
+		return usageErrorf("provide a note or --label")
+	}
+
+	historyStore, err := newAgentHistoryStoreForCommand()
+	if err != nil {
+		return err
+	}
+
+	record, err := historyStore.FindRecord(args[0])
+	if err != nil {
+		return notFoundErrorf("%v", err)
+	}
+
+	record, err = historyStore.UpdateRecord(record.ID, func(record *session.AgentRunRecord) {
+		if len(args) == 2 {
+			record.Note = strings.TrimSpace(args[1])
+		}
+		if labelChanged {
+			record.Label = strings.TrimSpace(annotateLabel)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Updated run %s\n", record.ID)
+	if record.Label != "" {
+		fmt.Printf("  Label: %s\n", record.Label)
+	}
+	if record.Note != "" {
+		fmt.Printf("  Note: %s\n", record.Note)
+	}
+	return nil
+}
+
+// newAgentHistoryStoreForCommand creates the agent history store for the
+// current project
+func newAgentHistoryStoreForCommand() (*session.AgentHistoryStore, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return session.NewAgentHistoryStoreWithConfig(cwd, GetStoreConfig())
+}
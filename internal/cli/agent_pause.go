@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// agentPausePollInterval is how often a paused loop checks if it was resumed
+const agentPausePollInterval = time.Second
+
+var agentPauseCmd = &cobra.Command{
+	Use:   "pause <session>",
+	Short: "Pause an agent run after its current iteration",
+	Long: `Pause the agent run on a session without killing it. The agent finishes
+the iteration it's on, then the loop waits, still holding the session lock,
+until it's resumed with juggle agent resume. Unlike cancelling the run, this
+never stops the agent partway through its edits.
+
+The run's checkpoint is saved before it waits, so a paused run that is
+killed can still be resumed later.
+
+Examples:
+  juggle agent pause my-feature
+  juggle agent resume my-feature   # Carry on`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentPause,
+}
+
+func init() {
+	agentCmd.AddCommand(agentPauseCmd)
+}
+
+func runAgentPause(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if sessionID != "all" {
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return session.NewSessionNotFoundError(sessionID)
+		}
+	}
+	storageID := sessionStorageID(sessionID)
+
+	if locked, _ := sessionStore.IsLocked(storageID); !locked {
+		return validationErrorf("no agent is running on session %s", sessionID)
+	}
+	existing, err := sessionStore.LoadAgentPause(storageID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.Holding() {
+			fmt.Printf("The agent run on session %s is already paused (since %s)\n", sessionID, existing.PausedAt.Format("15:04"))
+		} else {
+			fmt.Printf("The agent run on session %s is already pausing after its current iteration\n", sessionID)
+		}
+		return nil
+	}
+
+	if err := sessionStore.SaveAgentPause(&session.AgentPause{SessionID: storageID, Source: "cli"}); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Pausing the agent run on session %s\n", sessionID)
+	fmt.Printf("  It finishes its current iteration first. Resume with: juggle agent resume %s\n", sessionID)
+	return nil
+}
+
+// holdWhilePaused holds the loop while a person has paused it (see agent
+// pause), returning how long it waited. The session lock stays held, and
+// the checkpoint is saved first, so a paused run that is killed can still
+// be resumed.
+func holdWhilePaused(sessionStore *session.SessionStore, sessionID, storageID string, finished int, interrupted func() bool) time.Duration {
+	pause, err := sessionStore.LoadAgentPause(storageID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return 0
+	}
+	if pause == nil {
+		return 0
+	}
+
+	pausedAt := time.Now()
+	pause.PausedAt = pausedAt
+	if err := sessionStore.SaveAgentPause(pause); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	_ = sessionStore.AppendProgressEntry(storageID, session.ProgressEntry{
+		Author:   session.ProgressAuthorHuman,
+		Category: session.ProgressCategoryDecision,
+		Text:     fmt.Sprintf("[HUMAN] Paused the agent run after iteration %d", finished),
+	})
+	fmt.Println()
+	fmt.Printf("⏸ Paused after iteration %d. Resume with: juggle agent resume %s\n", finished, sessionID)
+
+	for !interrupted() {
+		time.Sleep(agentPausePollInterval)
+		pause, err := sessionStore.LoadAgentPause(storageID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			break
+		}
+		if pause == nil {
+			break
+		}
+	}
+	waited := time.Since(pausedAt)
+	if interrupted() {
+		_ = sessionStore.ClearAgentPause(storageID)
+		return waited
+	}
+
+	_ = sessionStore.AppendProgressEntry(storageID, session.ProgressEntry{
+		Author:   session.ProgressAuthorHuman,
+		Category: session.ProgressCategoryDecision,
+		Text:     fmt.Sprintf("[HUMAN] Resumed the agent run after %v paused", waited.Round(time.Second)),
+	})
+	fmt.Printf("▶ Resumed after %v paused\n\n", waited.Round(time.Second))
+	return waited
+}
@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	planRunSession    string
+	planRunIterations int
+	planRunModel      string
+	planRunPlanModel  string
+	planRunProvider   string
+	planRunTrust      bool
+	planRunTimeout    time.Duration
+	planRunLabel      string
+	planRunMessage    string
+	planRunYes        bool
+	planRunNoDeps     bool
+)
+
+// planOutputFile is where the planning phase's output is kept, next to the
+// session's last_output.txt
+const planOutputFile = "plan_output.txt"
+
+// planBlockPattern matches the plan the planning agent proposes
+var planBlockPattern = regexp.MustCompile(`(?s)<plan>(.*?)</plan>`)
+
+var agentPlanThenRunCmd = &cobra.Command{
+	Use:   "plan-then-run",
+	Short: "Plan a session's balls with one agent run, then implement them with another",
+	Long: `Run a session in two phases, with a human approving the plan in between.
+
+1. Plan: the agent researches the codebase in read-only plan mode and
+   proposes the balls still needed to finish the session, with acceptance
+   criteria. Its output is saved to .juggle/sessions/<id>/plan_output.txt.
+2. Approve: each proposed ball is shown for approval (--yes approves them
+   all). Approved balls are created in the session, pending, each depending
+   on the one before it (--no-deps to skip).
+3. Implement: the agent loop runs on the session, as with 'agent run'.
+
+Both runs are recorded in agent history with the same label, each linking
+to the other (see 'juggle agent history').
+
+Examples:
+  juggle agent plan-then-run --session my-feature
+  juggle agent plan-then-run -s my-feature --plan-model opus --model sonnet
+  juggle agent plan-then-run -s my-feature -M "Keep the public API unchanged"
+  juggle agent plan-then-run -s my-feature --yes -n 20`,
+	Args: cobra.NoArgs,
+	RunE: runAgentPlanThenRun,
+}
+
+func init() {
+	agentPlanThenRunCmd.Flags().StringVarP(&planRunSession, "session", "s", "", "Session to plan and implement (required)")
+	agentPlanThenRunCmd.Flags().IntVarP(&planRunIterations, "iterations", "n", 10, "Maximum number of implementation iterations")
+	agentPlanThenRunCmd.Flags().StringVarP(&planRunModel, "model", "m", "", "Model for the implementation phase (default: chosen per ball, as in 'agent run')")
+	agentPlanThenRunCmd.Flags().StringVar(&planRunPlanModel, "plan-model", "", "Model for the planning phase (opus, sonnet, haiku)")
+	agentPlanThenRunCmd.Flags().StringVar(&planRunProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
+	agentPlanThenRunCmd.Flags().BoolVar(&planRunTrust, "trust", false, "Implement with --dangerously-skip-permissions (dangerous!)")
+	agentPlanThenRunCmd.Flags().DurationVarP(&planRunTimeout, "timeout", "T", 0, "Timeout per implementation iteration (e.g., 5m). 0 = no timeout")
+	agentPlanThenRunCmd.Flags().StringVar(&planRunLabel, "label", "", "Label to record with both runs in agent history (default: plan-then-run)")
+	agentPlanThenRunCmd.Flags().StringVarP(&planRunMessage, "message", "M", "", "Message to append to the planning prompt")
+	agentPlanThenRunCmd.Flags().BoolVarP(&planRunYes, "yes", "y", false, "Approve every proposed ball without asking")
+	agentPlanThenRunCmd.Flags().BoolVar(&planRunNoDeps, "no-deps", false, "Don't make each approved ball depend on the one before it")
+	_ = agentPlanThenRunCmd.MarkFlagRequired("session")
+
+	agentCmd.AddCommand(agentPlanThenRunCmd)
+}
+
+// PlanThenRunConfig configures a plan-then-run workflow
+type PlanThenRunConfig struct {
+	Loop      AgentLoopConfig // Implementation run; its SessionID, ProjectDir, Provider and Label apply to both phases
+	PlanModel string          // Model for the planning run (empty = provider default)
+	Message   string          // Message appended to the planning prompt
+	NoDeps    bool            // Don't chain the approved balls
+
+	// Approve returns the proposed steps a human approved (none stops the
+	// workflow before implementation)
+	Approve func(steps []PlanStep) []PlanStep
+}
+
+// PlanThenRunResult is the outcome of a plan-then-run workflow
+type PlanThenRunResult struct {
+	PlanRunID string
+	Proposed  []PlanStep
+	Approved  []PlanStep
+	Run       *AgentResult // Implementation run; nil if nothing was approved
+}
+
+// RunPlanThenRun plans the session's balls in a read-only agent run, creates
+// the approved ones, and runs the agent loop on them. This is the testable
+// core of the agent plan-then-run command.
+func RunPlanThenRun(config PlanThenRunConfig) (*PlanThenRunResult, error) {
+	sessionID, projectDir := config.Loop.SessionID, config.Loop.ProjectDir
+	if sessionID == "all" {
+		return nil, validationErrorf("plan-then-run needs a session to add the planned balls to, not \"all\"")
+	}
+	sessionStore, err := session.NewSessionStore(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	juggleSession, err := sessionStore.LoadSession(sessionID)
+	if err != nil {
+		return nil, session.NewSessionNotFoundError(sessionID)
+	}
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if config.Loop.Label == "" {
+		config.Loop.Label = "plan-then-run"
+	}
+
+	prompt, err := generatePlanPrompt(projectDir, juggleSession, config.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("=== Phase 1: planning session %s (read-only) ===\n", sessionID)
+	record := session.NewAgentRunRecord(sessionID, projectDir, time.Now())
+	record.Phase = session.RunPhasePlan
+	record.Label = config.Loop.Label
+	record.MaxIterations = 1
+	record.OutputFile = filepath.Join(projectDir, ".juggle", "sessions", sessionID, planOutputFile)
+
+	runResult, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     prompt,
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      config.PlanModel,
+		WorkingDir: projectDir,
+	})
+	if err == nil && runResult.RateLimited {
+		err = fmt.Errorf("agent was rate limited, try again later")
+	}
+	if err != nil {
+		record.SetError(0, err.Error(), 0, 0, 0)
+		_ = historyStore.AppendRecord(record)
+		return nil, fmt.Errorf("planning failed: %w", err)
+	}
+	if err := os.WriteFile(record.OutputFile, []byte(runResult.Output), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save plan output: %v\n", err)
+	}
+
+	// Balls of a plan run are the balls it proposed
+	result := &PlanThenRunResult{PlanRunID: record.ID, Proposed: parsePlanOutput(runResult.Output)}
+	record.SetComplete(1, 0, 0, len(result.Proposed))
+	if err := historyStore.AppendRecord(record); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record plan run: %v\n", err)
+	}
+
+	if len(result.Proposed) == 0 {
+		fmt.Println("The agent proposed no balls; see " + record.OutputFile)
+		return result, nil
+	}
+
+	fmt.Printf("\n=== Phase 2: approving %d proposed ball(s) ===\n", len(result.Proposed))
+	result.Approved = config.Approve(result.Proposed)
+	if len(result.Approved) == 0 {
+		fmt.Println("No balls approved; not implementing.")
+		return result, nil
+	}
+	if err := ImportPlanSteps(result.Approved, projectDir, sessionID, !config.NoDeps); err != nil {
+		return result, err
+	}
+
+	fmt.Printf("\n=== Phase 3: implementing session %s ===\n", sessionID)
+	config.Loop.Phase = session.RunPhaseImplement
+	config.Loop.LinkedRunID = record.ID
+	result.Run, err = RunAgentLoop(config.Loop)
+	if err != nil {
+		return result, err
+	}
+	if _, err := historyStore.UpdateRecord(record.ID, func(record *session.AgentRunRecord) {
+		record.LinkedRunID = result.Run.RunID
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to link plan run: %v\n", err)
+	}
+	return result, nil
+}
+
+// generatePlanPrompt builds the planning prompt from the session and the
+// balls already in it
+func generatePlanPrompt(projectDir string, juggleSession *session.JuggleSession, message string) (string, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return "", fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var buf strings.Builder
+	buf.WriteString("<session>\n")
+	buf.WriteString(fmt.Sprintf("Session: %s\n", juggleSession.ID))
+	if juggleSession.Description != "" {
+		buf.WriteString(fmt.Sprintf("Description: %s\n", juggleSession.Description))
+	}
+	if len(juggleSession.AcceptanceCriteria) > 0 {
+		buf.WriteString("Acceptance Criteria:\n")
+		for i, ac := range juggleSession.AcceptanceCriteria {
+			buf.WriteString(fmt.Sprintf("  %d. %s\n", i+1, ac))
+		}
+	}
+	if juggleSession.Context != "" {
+		buf.WriteString("\n")
+		buf.WriteString(juggleSession.Context)
+		if !strings.HasSuffix(juggleSession.Context, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString("</session>\n\n")
+
+	buf.WriteString("<balls>\n")
+	written := 0
+	for _, ball := range balls {
+		if !ballHasTag(ball, juggleSession.ID) {
+			continue
+		}
+		if written > 0 {
+			buf.WriteString("\n")
+		}
+		writeBallForRefine(&buf, ball)
+		written++
+	}
+	buf.WriteString("</balls>\n\n")
+
+	buf.WriteString("<instructions>\n")
+	buf.WriteString(agent.GetPlanPromptTemplate())
+	if !strings.HasSuffix(agent.GetPlanPromptTemplate(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</instructions>\n")
+
+	if message != "" {
+		buf.WriteString("\n<user-message>\n")
+		buf.WriteString(message)
+		buf.WriteString("\n</user-message>\n")
+	}
+
+	return buf.String(), nil
+}
+
+// parsePlanOutput extracts the proposed steps from the last <plan> block of
+// the planning output. Every step is proposed as pending work.
+func parsePlanOutput(output string) []PlanStep {
+	matches := planBlockPattern.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	steps := ParsePlan(matches[len(matches)-1][1])
+	for i := range steps {
+		steps[i].Status = PlanStepPending
+	}
+	return steps
+}
+
+// approveAllPlanSteps approves every proposed step, for --yes
+func approveAllPlanSteps(steps []PlanStep) []PlanStep {
+	printPlanSteps(steps)
+	return steps
+}
+
+// reviewPlanSteps asks for approval of each proposed step in turn
+func reviewPlanSteps(steps []PlanStep) []PlanStep {
+	reader := bufio.NewReader(os.Stdin)
+	approved := make([]PlanStep, 0, len(steps))
+	for i, step := range steps {
+		fmt.Printf("\n[%d/%d] ", i+1, len(steps))
+		printPlanStep(step)
+		fmt.Print("  (y)es, (n)o, (a)ll remaining, (q)uit without creating any: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			approved = append(approved, step)
+		case "a":
+			return append(approved, steps[i:]...)
+		case "q":
+			return nil
+		default:
+			fmt.Println("  Skipped")
+		}
+	}
+	return approved
+}
+
+// printPlanSteps prints proposed steps with their acceptance criteria
+func printPlanSteps(steps []PlanStep) {
+	for i, step := range steps {
+		fmt.Printf("%d. ", i+1)
+		printPlanStep(step)
+	}
+}
+
+// printPlanStep prints a proposed step's title and acceptance criteria
+func printPlanStep(step PlanStep) {
+	fmt.Println(step.Title)
+	for _, criterion := range step.Criteria {
+		fmt.Printf("     - %s\n", criterion)
+	}
+}
+
+func runAgentPlanThenRun(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	approve := reviewPlanSteps
+	if planRunYes {
+		approve = approveAllPlanSteps
+	} else if !isTerminal(os.Stdin.Fd()) {
+		return usageErrorf("approving the plan needs a terminal; pass --yes to approve every proposed ball")
+	}
+
+	// Configure agent provider for the planning run (the implementation
+	// run configures its own)
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	providerType := provider.Detect(planRunProvider, projectProvider, globalProvider)
+	if !provider.IsAvailable(providerType) {
+		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
+			providerType, provider.BinaryName(providerType))
+	}
+	agent.SetProvider(newAgentProvider(providerType))
+
+	// Configure model overrides
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+
+	result, err := RunPlanThenRun(PlanThenRunConfig{
+		Loop: AgentLoopConfig{
+			SessionID:            planRunSession,
+			ProjectDir:           cwd,
+			MaxIterations:        planRunIterations,
+			Trust:                planRunTrust,
+			Timeout:              planRunTimeout,
+			Model:                planRunModel,
+			OverloadRetryMinutes: -1, // Use config default
+			Provider:             planRunProvider,
+			Label:                strings.TrimSpace(planRunLabel),
+		},
+		PlanModel: planRunPlanModel,
+		Message:   planRunMessage,
+		NoDeps:    planRunNoDeps,
+		Approve:   approve,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("=== Summary ===")
+	fmt.Printf("Plan run: %s (%d proposed, %d approved)\n", result.PlanRunID, len(result.Proposed), len(result.Approved))
+	if run := result.Run; run != nil {
+		fmt.Printf("Implementation run: %s\n", run.RunID)
+		fmt.Printf("Iterations: %d\n", run.Iterations)
+		fmt.Printf("Balls: %d complete, %d blocked, %d total\n", run.BallsComplete, run.BallsBlocked, run.BallsTotal)
+		switch {
+		case run.Complete:
+			fmt.Println("Status: COMPLETE")
+		case run.Blocked:
+			fmt.Printf("Status: BLOCKED (%s)\n", run.BlockedReason)
+		default:
+			fmt.Println("Status: not finished (see 'juggle agent history')")
+		}
+		promptRunNote(cwd, run.RunID)
+	}
+	return nil
+}
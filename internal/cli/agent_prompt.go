@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// AgentPromptData is the data passed to agent prompt templates. The section
+// fields hold the prompt's sections without their tags, and are empty when
+// the session has nothing for them.
+type AgentPromptData struct {
+	SessionID  string
+	Session    *session.JuggleSession
+	BallList   []*session.Ball // Balls shown to the agent, in the order given
+	SingleBall bool            // The run works on one ball, shown as a <task>
+	Debug      bool
+
+	Context            string // Session description and context
+	Progress           string // Last 50 lines of the progress log
+	AcceptanceCriteria string // Repository and session level criteria
+	Goal               string // Session goal and exit criteria
+	PreviousIteration  string // What a timed-out iteration got done
+	Balls              string // Balls with state and acceptance criteria
+	Instructions       string // Agent instructions and signals
+}
+
+// promptTemplateFile is the project's prompt template, in the prompts
+// directory; per-session overrides live in its sessions directory
+const promptTemplateFile = "agent.tmpl"
+
+// agentPromptTemplatePath returns where the prompt template for a session is
+// kept: its override when sessionID is set, else the project's
+func agentPromptTemplatePath(projectDir, sessionID string) string {
+	juggleDir := GetStoreConfig().JuggleDirName
+	if storageDir, err := session.ResolveStorageDir(projectDir, juggleDir); err == nil {
+		projectDir = storageDir
+	}
+	dir := filepath.Join(projectDir, juggleDir, "prompts")
+	if sessionID != "" {
+		return filepath.Join(dir, "sessions", sessionID+".tmpl")
+	}
+	return filepath.Join(dir, promptTemplateFile)
+}
+
+// findAgentPromptTemplate returns the path of the prompt template a
+// session's runs use, the session's override or the project's, or "" when
+// neither exists and the built-in skeleton is used
+func findAgentPromptTemplate(projectDir, sessionID string) string {
+	for _, path := range []string{agentPromptTemplatePath(projectDir, sessionID), agentPromptTemplatePath(projectDir, "")} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadAgentPromptTemplate parses the prompt template for a session's runs
+func loadAgentPromptTemplate(projectDir, sessionID string) (*template.Template, error) {
+	path := findAgentPromptTemplate(projectDir, sessionID)
+	if path == "" {
+		return parseExportTemplate("built-in prompt skeleton", agent.GetPromptSkeleton())
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+	return parseExportTemplate(path, string(content))
+}
+
+// renderAgentPrompt renders a prompt template, ending the prompt with a
+// single newline however the template file ends
+func renderAgentPrompt(tmpl *template.Template, data AgentPromptData) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", validationErrorf("failed to render prompt template: %v", err)
+	}
+	return strings.TrimRight(buf.String(), "\n") + "\n", nil
+}
+
+var (
+	agentPromptBallFlag    string
+	agentPromptDebugFlag   bool
+	agentPromptMessageFlag string
+	agentPromptForceFlag   bool
+)
+
+var agentPromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Preview or customize the agent prompt",
+	Long: `Preview or customize the prompt the agent loop sends each iteration.
+
+The prompt's sections (context, progress, acceptance criteria, goal, balls
+and instructions) are arranged by a Go text/template. Without a template of
+your own, the built-in skeleton is used. A project template lives in
+.juggle/prompts/agent.tmpl; a session's own template in
+.juggle/prompts/sessions/<session>.tmpl takes precedence over it.
+
+Templates get the same helpers as juggle export --template. See the comment
+at the top of the skeleton written by 'agent prompt init' for the fields.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var agentPromptPreviewCmd = &cobra.Command{
+	Use:   "preview <session>",
+	Short: "Print the exact prompt an agent run would send",
+	Long: `Print the exact prompt the next agent run on a session would send, after
+templating and secret scrubbing. The template used is reported on stderr.
+
+Examples:
+  juggle agent prompt preview my-feature
+  juggle agent prompt preview my-feature --ball my-app-3
+  juggle agent prompt preview my-feature --debug -M "Focus on the tests"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentPromptPreview,
+}
+
+var agentPromptInitCmd = &cobra.Command{
+	Use:   "init [session]",
+	Short: "Write the built-in prompt skeleton out for editing",
+	Long: `Write the built-in prompt skeleton to .juggle/prompts/agent.tmpl, or with
+a session, to that session's override in .juggle/prompts/sessions/.
+
+Existing templates are kept unless --force is given.
+
+Examples:
+  juggle agent prompt init
+  juggle agent prompt init my-feature`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentPromptInit,
+}
+
+func init() {
+	agentPromptPreviewCmd.Flags().StringVarP(&agentPromptBallFlag, "ball", "b", "", "Preview the prompt for a run on one ball")
+	agentPromptPreviewCmd.Flags().BoolVarP(&agentPromptDebugFlag, "debug", "d", false, "Include the debug instructions agent run --debug adds")
+	agentPromptPreviewCmd.Flags().StringVarP(&agentPromptMessageFlag, "message", "M", "", "Message to append to the prompt, as agent run --message does")
+	agentPromptInitCmd.Flags().BoolVar(&agentPromptForceFlag, "force", false, "Overwrite an existing template")
+	agentPromptCmd.AddCommand(agentPromptPreviewCmd)
+	agentPromptCmd.AddCommand(agentPromptInitCmd)
+	agentCmd.AddCommand(agentPromptCmd)
+}
+
+func runAgentPromptPreview(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if sessionID != "all" {
+		sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+		if err != nil {
+			return fmt.Errorf("failed to initialize session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return session.NewSessionNotFoundError(sessionID)
+		}
+	}
+
+	prompt, err := generateAgentPrompt(cwd, sessionID, agentPromptDebugFlag, agentPromptBallFlag, agentPromptMessageFlag, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Template: %s\n", cmp.Or(findAgentPromptTemplate(cwd, sessionID), "built-in skeleton"))
+	fmt.Print(prompt)
+	return nil
+}
+
+func runAgentPromptInit(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	var sessionID string
+	if len(args) == 1 {
+		sessionID = args[0]
+		sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+		if err != nil {
+			return fmt.Errorf("failed to initialize session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return session.NewSessionNotFoundError(sessionID)
+		}
+	}
+
+	path := agentPromptTemplatePath(cwd, sessionID)
+	if _, err := os.Stat(path); err == nil && !agentPromptForceFlag {
+		return validationErrorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create prompts directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(agent.GetPromptSkeleton()), 0644); err != nil {
+		return fmt.Errorf("failed to write prompt template: %w", err)
+	}
+	fmt.Printf("✓ Wrote %s\n", path)
+	fmt.Println("  Edit it, then check the result with: juggle agent prompt preview", cmp.Or(sessionID, "<session>"))
+	return nil
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var agentResumeDiscardFlag bool
+
+var agentResumeCmd = &cobra.Command{
+	Use:   "resume <session>",
+	Short: "Resume an agent run that was paused or interrupted",
+	Long: `Resume an agent run on a session that was paused with juggle agent pause,
+or that was killed partway, e.g. by the laptop going to sleep or a crash.
+
+A paused run is still going and just carries on. If it hasn't finished the
+iteration it paused after yet, the pause is called off.
+
+Before each iteration, agent run saves how far it got to
+.juggle/agent-state/<session>.json: the iterations done, the time spent
+waiting on rate limits and how the last iteration ended. A run that ends
+removes it. Resuming carries on from the iteration that was interrupted,
+with the settings the run was started with and the iteration budget it had
+left.
+
+Single-ball runs and runs with --ignore-lock aren't checkpointed.
+
+Examples:
+  juggle agent resume my-feature
+  juggle agent resume my-feature --discard   # Drop the checkpoint instead`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentResume,
+}
+
+func init() {
+	agentResumeCmd.Flags().BoolVar(&agentResumeDiscardFlag, "discard", false, "Discard the checkpoint instead of resuming")
+	agentCmd.AddCommand(agentResumeCmd)
+}
+
+func runAgentResume(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if sessionID != "all" {
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return session.NewSessionNotFoundError(sessionID)
+		}
+	}
+
+	storageID := sessionStorageID(sessionID)
+
+	if !agentResumeDiscardFlag {
+		resumed, err := resumePausedRun(sessionStore, sessionID, storageID)
+		if err != nil || resumed {
+			return err
+		}
+	}
+
+	checkpoint, err := sessionStore.LoadAgentCheckpoint(storageID)
+	if err != nil {
+		return err
+	}
+	if checkpoint == nil {
+		return notFoundErrorf("no interrupted agent run to resume on session %s", sessionID)
+	}
+	if locked, _ := sessionStore.IsLocked(storageID); locked {
+		return validationErrorf("the agent run on session %s is still going (see juggle agent status)", sessionID)
+	}
+
+	if agentResumeDiscardFlag {
+		if err := sessionStore.ClearAgentCheckpoint(storageID); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Discarded the checkpoint of the run on session %s\n", sessionID)
+		return nil
+	}
+	if checkpoint.Remaining() == 0 {
+		if err := sessionStore.ClearAgentCheckpoint(storageID); err != nil {
+			return err
+		}
+		fmt.Printf("The run on session %s had used all %d iterations; nothing to resume.\n", sessionID, checkpoint.MaxIterations)
+		return nil
+	}
+
+	if settings := checkpoint.Settings; settings.WorkDir != "" {
+		if _, err := os.Stat(settings.WorkDir); err != nil {
+			return validationErrorf("the run's worktree %s is gone; drop the checkpoint with --discard", settings.WorkDir)
+		}
+	}
+
+	fmt.Printf("Resuming agent run on session %s from iteration %d/%d\n", sessionID, checkpoint.Iteration+1, checkpoint.MaxIterations)
+	fmt.Printf("Started: %s, last checkpoint: %s\n", checkpoint.StartedAt.Format("2006-01-02 15:04"), checkpoint.UpdatedAt.Format("2006-01-02 15:04"))
+	if checkpoint.LastSignal != "" {
+		fmt.Printf("Last iteration: %s\n", checkpoint.LastSignal)
+	}
+	if checkpoint.WaitTime > 0 {
+		fmt.Printf("Waited so far: %v\n", checkpoint.WaitTime.Round(time.Second))
+	}
+	fmt.Println()
+
+	loopConfig := resumeLoopConfig(cwd, checkpoint)
+	result, err := RunAgentLoop(loopConfig)
+	if err != nil {
+		return err
+	}
+	return reportAgentRun(loopConfig, result)
+}
+
+// resumePausedRun lets a paused run on the session carry on, reporting
+// whether there was one
+func resumePausedRun(sessionStore *session.SessionStore, sessionID, storageID string) (bool, error) {
+	pause, err := sessionStore.LoadAgentPause(storageID)
+	if err != nil || pause == nil {
+		return false, err
+	}
+	if err := sessionStore.ClearAgentPause(storageID); err != nil {
+		return false, err
+	}
+	// The pause of a run that was killed since is left over; its checkpoint
+	// is what to resume
+	if locked, _ := sessionStore.IsLocked(storageID); !locked {
+		return false, nil
+	}
+	if pause.Holding() {
+		fmt.Printf("✓ Resumed the paused agent run on session %s\n", sessionID)
+	} else {
+		fmt.Printf("✓ Called off the pause; the agent run on session %s keeps going\n", sessionID)
+	}
+	return true, nil
+}
+
+// newAgentCheckpoint starts the checkpoint of a run, carrying on from the
+// run it resumes if any
+func newAgentCheckpoint(config AgentLoopConfig, startTime time.Time) *session.AgentCheckpoint {
+	hostname, _ := os.Hostname()
+	checkpoint := &session.AgentCheckpoint{
+		SessionID:     config.SessionID,
+		MaxIterations: config.MaxIterations,
+		PID:           os.Getpid(),
+		Hostname:      hostname,
+		StartedAt:     startTime,
+		Settings: session.AgentRunSettings{
+			Model:            config.Model,
+			Provider:         config.Provider,
+			Trust:            config.Trust,
+			Interactive:      config.Interactive,
+			Timeout:          config.Timeout,
+			IterDelay:        config.IterDelay,
+			MaxWait:          config.MaxWait,
+			Message:          config.Message,
+			IgnoreQuietHours: config.IgnoreQuietHours,
+			IgnoreBrownout:   config.IgnoreBrownout,
+			SafeMode:         config.SafeMode,
+			Label:            config.Label,
+			WorkDir:          config.WorkDir,
+		},
+	}
+	if config.Resume != nil {
+		checkpoint.StartedAt = config.Resume.StartedAt
+		checkpoint.Iteration = config.Resume.Iteration
+		checkpoint.WaitTime = config.Resume.WaitTime
+		checkpoint.LastSignal = config.Resume.LastSignal
+	}
+	return checkpoint
+}
+
+// saveAgentCheckpoint records that a run has finished iterations and
+// waited waitTime so far. Failing to save only costs the ability to
+// resume, so it's a warning.
+func saveAgentCheckpoint(sessionStore *session.SessionStore, storageID string, checkpoint *session.AgentCheckpoint,
+	finished int, waitTime time.Duration, outcomes []session.IterationOutcome) {
+	checkpoint.Iteration = finished
+	checkpoint.WaitTime = waitTime
+	if len(outcomes) > 0 {
+		checkpoint.LastSignal = outcomes[len(outcomes)-1].Outcome
+	}
+	checkpoint.UpdatedAt = time.Now()
+	if err := sessionStore.SaveAgentCheckpoint(storageID, checkpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// resumeLoopConfig returns the loop config that resumes a checkpointed run
+func resumeLoopConfig(projectDir string, checkpoint *session.AgentCheckpoint) AgentLoopConfig {
+	settings := checkpoint.Settings
+	return AgentLoopConfig{
+		SessionID:            checkpoint.SessionID,
+		ProjectDir:           projectDir,
+		MaxIterations:        checkpoint.MaxIterations,
+		Trust:                settings.Trust,
+		IterDelay:            settings.IterDelay,
+		Timeout:              settings.Timeout,
+		MaxWait:              settings.MaxWait,
+		Interactive:          settings.Interactive,
+		Model:                settings.Model,
+		OverloadRetryMinutes: -1, // Use config default
+		Provider:             settings.Provider,
+		Message:              settings.Message,
+		IgnoreQuietHours:     settings.IgnoreQuietHours,
+		IgnoreBrownout:       settings.IgnoreBrownout,
+		SafeMode:             settings.SafeMode,
+		Label:                settings.Label,
+		WorkDir:              settings.WorkDir,
+		Resume:               checkpoint,
+	}
+}
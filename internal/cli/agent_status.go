@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var agentStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show recent API health of the agent providers",
+	Long: `Show how the agent providers have been behaving, so you know before
+launching a run whether the provider is currently flaky.
+
+Every agent call in every project is recorded in ~/.juggle/api-health.json:
+how long it took and whether it failed (crash, timeout, 429 rate limit or 529
+overload). Calls from the last 6 hours decide each provider's health:
+
+  healthy    Under 20% of calls failed and no 429/529 in the window
+  degraded   20% or more failed, or a 429/529 earlier in the window
+  unhealthy  Half or more failed, or a 429/529 in the last 15 minutes
+
+The provider runs in this project would use is marked with *.
+
+Examples:
+  juggle agent status
+  juggle agent status --json`,
+	Args: cobra.NoArgs,
+	RunE: runAgentStatus,
+}
+
+func init() {
+	agentCmd.AddCommand(agentStatusCmd)
+}
+
+func runAgentStatus(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	now := time.Now()
+	summaries, err := session.LoadAPIHealthWithOptions(GetConfigOptions(), now)
+	if err != nil {
+		return err
+	}
+	configured := string(configuredProvider(cwd))
+
+	// Always show the configured provider, even before its first call
+	found := false
+	for _, summary := range summaries {
+		if summary.Provider == configured {
+			found = true
+		}
+	}
+	if !found {
+		summary, err := session.GetAPIHealthWithOptions(GetConfigOptions(), configured, now)
+		if err != nil {
+			return err
+		}
+		summaries = append([]session.APIHealthSummary{summary}, summaries...)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tHEALTH\tCALLS\tERRORS\tAVG LATENCY\tLAST 429\tLAST 529")
+	for _, summary := range summaries {
+		name := summary.Provider
+		if name == configured {
+			name += " *"
+		}
+		latency := "-"
+		if summary.Calls > 0 {
+			latency = summary.AvgLatency.Round(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			name,
+			summary.Status,
+			summary.Calls,
+			formatErrorRate(summary),
+			latency,
+			formatLastLimit(summary.LastRateLimited, now),
+			formatLastLimit(summary.LastOverloaded, now),
+		)
+	}
+	return tw.Flush()
+}
+
+// configuredProvider returns the provider a run in projectDir would use
+// without --provider
+func configuredProvider(projectDir string) provider.Type {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	return provider.Detect("", projectProvider, globalProvider)
+}
+
+// recordAPIHealth records the outcome of an agent call that started at
+// start and prints the provider's health so far
+func recordAPIHealth(providerType provider.Type, start time.Time, result *agent.RunResult, runErr error) {
+	now := time.Now()
+	sample := session.APIHealthSample{
+		At:        now,
+		LatencyMS: now.Sub(start).Milliseconds(),
+		Error:     runErr != nil,
+	}
+	if result != nil {
+		sample.RateLimited = result.RateLimited
+		sample.Overloaded = result.OverloadExhausted
+		sample.Error = sample.Error || result.RateLimited || result.OverloadExhausted || result.TimedOut ||
+			(result.Error != nil && result.ExitCode != 0)
+	}
+
+	opts := GetConfigOptions()
+	if err := session.RecordAPIHealthWithOptions(opts, string(providerType), sample); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record API health: %v\n", err)
+		return
+	}
+	summary, err := session.GetAPIHealthWithOptions(opts, string(providerType), now)
+	if err != nil {
+		return
+	}
+	fmt.Printf("📶 %s took %v · API %s (%d calls, %s errors, avg %v)\n",
+		providerType, now.Sub(start).Round(time.Second), summary.Status,
+		summary.Calls, formatErrorRate(summary), summary.AvgLatency.Round(time.Second))
+}
+
+// formatErrorRate formats a provider's failed calls as a percentage
+func formatErrorRate(summary session.APIHealthSummary) string {
+	if summary.Calls == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", summary.ErrorRate*100)
+}
+
+// formatLastLimit formats when a 429 or 529 last happened
+func formatLastLimit(at time.Time, now time.Time) string {
+	if at.IsZero() {
+		return "never"
+	}
+	return formatDuration(now.Sub(at)) + " ago"
+}
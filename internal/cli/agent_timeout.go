@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// timeoutOutputTailLines is how much of a timed out iteration's partial
+// output the next iteration's prompt quotes
+const timeoutOutputTailLines = 20
+
+// salvageTimedOutIteration records what an iteration got done before it
+// timed out: the balls it completed, the progress it logged, any signal in
+// its partial output and the output's last lines. The next iteration's
+// prompt is told about it (see writeTimeoutSalvage).
+func salvageTimedOutIteration(sessionStore *session.SessionStore, projectDir, storageID string, iteration int, timeout time.Duration,
+	output string, openBalls []*session.Ball, progressBefore int, signals provider.SignalOptions) *session.TimeoutSalvage {
+	salvage := &session.TimeoutSalvage{
+		SessionID:  storageID,
+		Iteration:  iteration,
+		Timeout:    timeout,
+		TimedOutAt: time.Now(),
+		OutputTail: strings.TrimSpace(limitToLastLines(output, timeoutOutputTailLines)),
+	}
+
+	// Providers don't read signals from output cut short by the timeout
+	partial := &provider.RunResult{Output: output}
+	provider.ApplySignals(partial, signals)
+	switch {
+	case partial.Blocked:
+		salvage.Signal = strings.TrimSuffix("BLOCKED: "+partial.BlockedReason, ": ")
+	case partial.Complete:
+		salvage.Signal = strings.TrimSuffix("COMPLETE: "+partial.CommitMessage, ": ")
+	case partial.Continue:
+		salvage.Signal = strings.TrimSuffix("CONTINUE: "+partial.CommitMessage, ": ")
+	}
+
+	if store, err := NewStoreForCommand(projectDir); err == nil {
+		for _, before := range openBalls {
+			ball, _, err := store.GetBallAnywhere(before.ID)
+			if err == nil && ball != nil && (ball.State == session.StateComplete || ball.State == session.StateResearched) {
+				salvage.CompletedBalls = append(salvage.CompletedBalls, ball.ShortID()+" "+ball.Title)
+			}
+		}
+	}
+
+	if progress, err := sessionStore.LoadProgress(storageID); err == nil {
+		lines := strings.Split(strings.TrimSuffix(progress, "\n"), "\n")
+		if progressBefore < len(lines) {
+			for _, line := range lines[progressBefore:] {
+				if strings.TrimSpace(line) != "" {
+					salvage.ProgressEntries = append(salvage.ProgressEntries, line)
+				}
+			}
+		}
+	}
+
+	if err := sessionStore.SaveTimeoutSalvage(salvage); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return salvage
+}
+
+// writeTimeoutSalvage tells the agent what the session's last iteration got
+// done before it timed out, if it did. It writes the prompt's
+// <previous-iteration> section, without its tags.
+func writeTimeoutSalvage(buf *strings.Builder, sessionStore *session.SessionStore, sessionID string) {
+	salvage, err := sessionStore.LoadTimeoutSalvage(sessionStorageID(sessionID))
+	if err != nil || salvage == nil {
+		return
+	}
+	buf.WriteString("The previous iteration was cut off by its timeout: " + salvage.Summary() + ".\n")
+	buf.WriteString("Its work may be half done. Check the state of the code and balls it touched, finish or fix that first, ")
+	buf.WriteString("and keep each step small enough to finish in time.\n")
+	if salvage.OutputTail != "" {
+		buf.WriteString("\nIts output ended with:\n")
+		buf.WriteString(salvage.OutputTail + "\n")
+	}
+}
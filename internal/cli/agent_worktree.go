@@ -0,0 +1,327 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/git"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentWorktreeDiffStat bool // Show the diffstat instead of the diff
+	agentWorktreeKeep     bool // Keep the worktree and branch after merging
+	agentWorktreeYes      bool // Discard without confirming
+)
+
+var agentWorktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Review, merge or discard the work of agent runs made with --worktree",
+	Long: `Review, merge or discard the work of agent runs made in a git worktree.
+
+With agent run --worktree, or agent_worktree in the project config, the
+agent works in a git worktree under .juggle/worktrees/<session> on the
+branch juggle/agent-<session>, branched from the checked out branch. Its
+changes never touch the main working tree: balls and progress still go to
+the main repo's .juggle, and later runs on the session carry on in the same
+worktree until it's merged or discarded.
+
+Examples:
+  juggle agent worktree diff my-feature --stat
+  juggle agent worktree merge my-feature
+  juggle agent worktree discard my-feature`,
+}
+
+var agentWorktreeDiffCmd = &cobra.Command{
+	Use:   "diff <session>",
+	Short: "Show the changes an agent made in the session's worktree",
+	Long: `Show the changes in the session's agent worktree since its branch forked,
+committed or not. Untracked files are listed after the diff.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentWorktreeDiff,
+}
+
+var agentWorktreeMergeCmd = &cobra.Command{
+	Use:   "merge <session>",
+	Short: "Merge the session's agent branch into the branch it came from",
+	Long: `Merge the session's agent branch into the branch it was created from,
+which must be checked out in the main working tree. Changes the agent left
+uncommitted are committed to the agent branch first. The merge is aborted
+on a conflict, leaving both branches as they were.
+
+The worktree and branch are removed after merging, unless --keep is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentWorktreeMerge,
+}
+
+var agentWorktreeDiscardCmd = &cobra.Command{
+	Use:   "discard <session>",
+	Short: "Remove the session's agent worktree and branch, dropping its changes",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAgentWorktreeDiscard,
+}
+
+func init() {
+	agentWorktreeDiffCmd.Flags().BoolVar(&agentWorktreeDiffStat, "stat", false, "Show the diffstat instead of the diff")
+	agentWorktreeMergeCmd.Flags().BoolVar(&agentWorktreeKeep, "keep", false, "Keep the worktree and branch after merging")
+	agentWorktreeDiscardCmd.Flags().BoolVarP(&agentWorktreeYes, "yes", "y", false, "Skip confirmation prompt (for headless mode)")
+
+	agentWorktreeCmd.AddCommand(agentWorktreeDiffCmd, agentWorktreeMergeCmd, agentWorktreeDiscardCmd)
+	agentCmd.AddCommand(agentWorktreeCmd)
+}
+
+// agentWorkDir returns the directory the agent of a run works in
+func agentWorkDir(config AgentLoopConfig) string {
+	if config.WorkDir != "" {
+		return config.WorkDir
+	}
+	return config.ProjectDir
+}
+
+// useAgentWorktree reports whether runs in the project work in a worktree
+// without being asked to with --worktree
+func useAgentWorktree(projectDir string) bool {
+	config, err := session.LoadProjectConfig(projectDir)
+	return err == nil && config.AgentWorktree
+}
+
+// ensureAgentWorktree returns the worktree the session's agent runs work
+// in, creating it on the session's branch from the checked out commit the
+// first time
+func ensureAgentWorktree(projectDir, sessionID string) (*session.AgentWorktree, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	storageID := sessionStorageID(sessionID)
+	existing, err := sessionStore.LoadAgentWorktree(storageID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if _, err := os.Stat(existing.Path); err == nil {
+			fmt.Printf("🌳 Working in %s on branch %s\n", existing.Path, existing.Branch)
+			return existing, nil
+		}
+		// Removed outside juggle, so it's made again below, once git and
+		// the worktree registry have forgotten it too
+		_ = session.ForgetWorktree(sessionStore.ProjectDir(), existing.Path, GlobalOpts.JuggleDir)
+		if err := git.PruneWorktrees(sessionStore.ProjectDir()); err != nil {
+			return nil, err
+		}
+	}
+
+	mainDir := sessionStore.ProjectDir()
+	if !git.IsRepo(mainDir) {
+		return nil, validationErrorf("agent worktrees need a git repository")
+	}
+	base, err := git.CurrentBranch(mainDir)
+	if err != nil {
+		return nil, validationErrorf("agent worktrees branch off the checked out branch: %v", err)
+	}
+	baseCommit, err := git.Head(mainDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Neither the worktrees nor the link to the main repo's .juggle in
+	// them are part of the project
+	juggleDir := GlobalOpts.JuggleDir
+	for _, pattern := range []string{"/" + juggleDir + "/worktrees/", "/" + juggleDir + "/link"} {
+		if err := git.Exclude(mainDir, pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	worktree := &session.AgentWorktree{
+		SessionID:  storageID,
+		Path:       sessionStore.AgentWorktreePath(storageID),
+		Branch:     session.AgentWorktreeBranch(sessionID),
+		Base:       base,
+		BaseCommit: baseCommit,
+	}
+	if err := git.AddWorktree(mainDir, worktree.Path, worktree.Branch, baseCommit); err != nil {
+		return nil, err
+	}
+	if err := session.RegisterWorktree(mainDir, worktree.Path, juggleDir); err != nil {
+		return nil, err
+	}
+	if err := sessionStore.SaveAgentWorktree(worktree); err != nil {
+		return nil, err
+	}
+	fmt.Printf("🌳 Created worktree %s on branch %s (from %s)\n", worktree.Path, worktree.Branch, base)
+	return worktree, nil
+}
+
+// loadAgentWorktree returns the session store and the session's agent
+// worktree, for the worktree commands
+func loadAgentWorktree(sessionID string) (*session.SessionStore, *session.AgentWorktree, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	worktree, err := sessionStore.LoadAgentWorktree(sessionStorageID(sessionID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if worktree == nil {
+		return nil, nil, notFoundErrorf("session %s has no agent worktree", sessionID)
+	}
+	return sessionStore, worktree, nil
+}
+
+// checkAgentWorktreeIdle refuses to change a worktree an agent is running in
+func checkAgentWorktreeIdle(sessionStore *session.SessionStore, sessionID string) error {
+	if locked, _ := sessionStore.IsLocked(sessionStorageID(sessionID)); locked {
+		return validationErrorf("an agent is running on session %s; wait for the run to end first", sessionID)
+	}
+	return nil
+}
+
+// removeAgentWorktree removes the session's worktree and branch, and the
+// record of them
+func removeAgentWorktree(sessionStore *session.SessionStore, worktree *session.AgentWorktree) error {
+	mainDir := sessionStore.ProjectDir()
+	_ = session.ForgetWorktree(mainDir, worktree.Path, GlobalOpts.JuggleDir)
+	if _, err := os.Stat(worktree.Path); err == nil {
+		if err := git.RemoveWorktree(mainDir, worktree.Path); err != nil {
+			return err
+		}
+	}
+	if git.BranchExists(mainDir, worktree.Branch) {
+		if err := git.DeleteBranch(mainDir, worktree.Branch); err != nil {
+			return err
+		}
+	}
+	return sessionStore.ClearAgentWorktree(worktree.SessionID)
+}
+
+func runAgentWorktreeDiff(cmd *cobra.Command, args []string) error {
+	sessionStore, worktree, err := loadAgentWorktree(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(worktree.Path); err != nil {
+		return notFoundErrorf("the worktree %s is gone; remove its record with juggle agent worktree discard %s", worktree.Path, args[0])
+	}
+
+	// Compare with where the branch forked, so work since committed to the
+	// base branch isn't shown as undone
+	mainDir := sessionStore.ProjectDir()
+	base := worktree.BaseCommit
+	if forked, err := git.MergeBase(mainDir, worktree.Base, worktree.Branch); err == nil {
+		base = forked
+	}
+	commits, _ := git.CountCommits(mainDir, base, worktree.Branch)
+
+	diff, err := git.Diff(worktree.Path, base, agentWorktreeDiffStat, GlobalOpts.JuggleDir)
+	if err != nil {
+		return err
+	}
+	untracked, err := git.UntrackedFiles(worktree.Path, GlobalOpts.JuggleDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Branch %s from %s (%d commits)\n", worktree.Branch, worktree.Base, commits)
+	fmt.Printf("Worktree: %s\n\n", worktree.Path)
+	if diff == "" && len(untracked) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+	if diff != "" {
+		fmt.Println(diff)
+	}
+	if len(untracked) > 0 {
+		if diff != "" {
+			fmt.Println()
+		}
+		fmt.Println("Untracked files (merging commits them):")
+		for _, file := range untracked {
+			fmt.Printf("  %s\n", file)
+		}
+	}
+	return nil
+}
+
+func runAgentWorktreeMerge(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	sessionStore, worktree, err := loadAgentWorktree(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := checkAgentWorktreeIdle(sessionStore, sessionID); err != nil {
+		return err
+	}
+	mainDir := sessionStore.ProjectDir()
+	if branch, err := git.CurrentBranch(mainDir); err != nil || branch != worktree.Base {
+		return validationErrorf("check out %s to merge the agent's work into it", worktree.Base)
+	}
+
+	if _, err := os.Stat(worktree.Path); err == nil {
+		committed, err := git.CommitAll(worktree.Path, fmt.Sprintf("Uncommitted agent work on session %s", sessionID), GlobalOpts.JuggleDir)
+		if err != nil {
+			return err
+		}
+		if committed {
+			fmt.Printf("📝 Committed the changes the agent left uncommitted to %s\n", worktree.Branch)
+		}
+	}
+
+	commits, err := git.CountCommits(mainDir, worktree.Base, worktree.Branch)
+	if err != nil {
+		return err
+	}
+	if commits == 0 {
+		fmt.Printf("Nothing to merge: %s has no commits %s doesn't\n", worktree.Branch, worktree.Base)
+	} else {
+		message := fmt.Sprintf("Merge agent work on session %s", sessionID)
+		if err := git.Merge(mainDir, worktree.Branch, message); err != nil {
+			return fmt.Errorf("failed to merge %s into %s, so the merge was aborted; merge it by hand or discard it: %w",
+				worktree.Branch, worktree.Base, err)
+		}
+		fmt.Printf("✓ Merged %d commits from %s into %s\n", commits, worktree.Branch, worktree.Base)
+	}
+
+	if agentWorktreeKeep {
+		return nil
+	}
+	if err := removeAgentWorktree(sessionStore, worktree); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Removed the worktree and branch %s\n", worktree.Branch)
+	return nil
+}
+
+func runAgentWorktreeDiscard(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	sessionStore, worktree, err := loadAgentWorktree(sessionID)
+	if err != nil {
+		return err
+	}
+	if err := checkAgentWorktreeIdle(sessionStore, sessionID); err != nil {
+		return err
+	}
+
+	if !agentWorktreeYes {
+		confirmed, err := ConfirmSingleKey(fmt.Sprintf("Discard the worktree and branch %s with all the agent's changes?", worktree.Branch))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := removeAgentWorktree(sessionStore, worktree); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Discarded the worktree and branch %s\n", worktree.Branch)
+	return nil
+}
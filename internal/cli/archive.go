@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveListOpts     ballListOptions
+	archiveShowJSONFlag bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Work with archived (completed) balls",
+	Long: `Work with archived (completed) balls.
+
+Commands:
+  list    List archived balls, one per line
+  show    Show an archived ball's details
+
+See also "juggle history" to search the archive and "juggle unarchive" to
+restore a ball.`,
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archived balls, one per line, for scripting",
+	Long: `List archived balls in archive order (oldest first), one per line.
+
+The archive is streamed: balls before --offset are skipped as they are read
+and reading stops once the page is full, so large archives are never loaded
+at once. Prints 50 balls by default (--limit 0 prints all of them).
+
+Examples:
+  juggle archive list                          # First 50 archived balls
+  juggle archive list --page 3                 # Balls 101-150
+  juggle archive list --limit 0 --jsonl > archive.jsonl
+  juggle archive list --columns id,completed,title --all`,
+	Args: cobra.NoArgs,
+	RunE: runArchiveList,
+}
+
+var archiveShowCmd = &cobra.Command{
+	Use:   "show <ball-id>",
+	Short: "Show an archived ball's details",
+	Long: `Show the details of an archived ball, the same way "juggle show" does for
+active balls. IDs can be shortened to any unique prefix.
+
+Examples:
+  juggle archive show juggle-5
+  juggle archive show juggle-5 --json
+  juggle unarchive juggle-5        # Restore it`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteArchivedBallIDs,
+	RunE:              runArchiveShow,
+}
+
+func init() {
+	archiveListOpts.addFlags(archiveListCmd, defaultListPageSize, "id,priority,completed,title")
+	archiveShowCmd.Flags().BoolVar(&archiveShowJSONFlag, "json", false, "Output as JSON")
+
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveShowCmd)
+	rootCmd.AddCommand(archiveCmd)
+}
+
+func runArchiveList(cmd *cobra.Command, args []string) error {
+	offset, limit, err := archiveListOpts.window()
+	if err != nil {
+		return err
+	}
+	columns, err := parseBallListColumns(archiveListOpts.columns)
+	if err != nil {
+		return err
+	}
+
+	projects, err := listProjects()
+	if err != nil {
+		return err
+	}
+
+	w := newBallListWriter(os.Stdout, columns, archiveListOpts.jsonl, cmd.Flags().Changed("columns"))
+	seen := 0
+	more := false
+	for _, projectPath := range projects {
+		store, err := session.NewStoreWithConfig(projectPath, GetStoreConfig())
+		if err != nil {
+			continue // Skip projects we can't access
+		}
+		var writeErr error
+		err = store.EachArchivedBall(func(ball *session.Ball) bool {
+			if limit > 0 && seen >= offset+limit {
+				more = true // One ball past the page is enough to know
+				return false
+			}
+			if seen >= offset {
+				if writeErr = w.write(ball); writeErr != nil {
+					return false
+				}
+			}
+			seen++
+			return true
+		})
+		if writeErr != nil {
+			return writeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive for %s: %w", projectPath, err)
+		}
+		if more {
+			break
+		}
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	w.footer(offset, more, "")
+	return nil
+}
+
+func runArchiveShow(cmd *cobra.Command, args []string) error {
+	ball, _, err := findArchivedBallByID(args[0])
+	if err != nil {
+		if archiveShowJSONFlag {
+			return printJSONError(err)
+		}
+		return err
+	}
+
+	if archiveShowJSONFlag {
+		return printBallJSON(ball)
+	}
+	renderBallDetails(ball)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	boardPlain        bool
+	boardSession      string
+	boardSort         string
+	boardShowComplete bool
+)
+
+var boardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show the sessions/balls board",
+	Long: `Show the same sessions and balls layout as the TUI split view.
+
+With --plain, the board is printed as aligned plain text instead of launching
+the interactive UI. The output uses the same filtering and sorting as the TUI,
+so it is suitable for piping to less/grep or reading with a screen reader.
+
+Without --plain, this launches the TUI in the board layout (same as
+'juggle tui --view board').
+
+Sort orders: id (default), id-desc, priority, priority-asc, activity,
+activity-asc, created, created-asc, any custom sort order defined with
+'juggle config sort add', or an inline sort expression such as
+"priority desc, age desc".
+
+Examples:
+  juggle board --plain                       # Print every session's balls
+  juggle board --plain --session my-feature  # Print a single session
+  juggle board --plain --sort priority       # Urgent balls first
+  juggle board --plain --sort "state, priority desc"
+  juggle --all board --plain | less          # All discovered projects`,
+	RunE: runBoard,
+}
+
+func runBoard(cmd *cobra.Command, args []string) error {
+	if !boardPlain {
+		tuiSessionFilter = boardSession
+		if tuiView == "" {
+			tuiView = session.ViewBoard
+		}
+		return runTUI(cmd, args)
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sortOrder, customSort, err := tui.ResolveSort(boardSort, config)
+	if err != nil {
+		return err
+	}
+
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	output, err := tui.RenderPlainBoard(store, sessionStore, config, !GlobalOpts.AllProjects, tui.PlainBoardOptions{
+		SessionID:    boardSession,
+		ShowComplete: boardShowComplete,
+		SortOrder:    sortOrder,
+		CustomSort:   customSort,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(output)
+	return nil
+}
+
+func init() {
+	boardCmd.Flags().BoolVar(&boardPlain, "plain", false, "Print the board as aligned plain text instead of launching the TUI")
+	boardCmd.Flags().StringVar(&boardSession, "session", "", "Only show this session")
+	boardCmd.Flags().StringVar(&boardSort, "sort", "id", "Sort order for balls")
+	boardCmd.Flags().BoolVar(&boardShowComplete, "include-done", false, "Include complete balls")
+	rootCmd.AddCommand(boardCmd)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var briefCmd = &cobra.Command{
+	Use:   "brief <session>",
+	Short: "Summarise a session before starting a work block",
+	Long: `Print a short briefing for picking a session back up: its goal, the
+next balls to work on, current blockers, and how the last agent run ended.
+
+The next balls are the first few ready to work on (pending or in progress,
+with their dependencies done), in the order the agent would take them:
+focused balls first, then in-progress, then by priority.
+
+The TUI shows the same briefing when you select a session that has been
+idle for a few days, or on demand with b in the sessions panel.
+
+Examples:
+  juggle brief auth
+  juggle brief auth --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBrief,
+}
+
+func init() {
+	rootCmd.AddCommand(briefCmd)
+}
+
+func runBrief(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sess, err := sessionStore.LoadSession(sessionID)
+	if err != nil {
+		return notFoundErrorf("session not found: %s", sessionID)
+	}
+
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	historyStore, err := session.NewAgentHistoryStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to open agent history: %w", err)
+	}
+	history, err := historyStore.LoadHistoryBySession(sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to load agent history: %w", err)
+	}
+
+	briefing := session.BuildBriefing(sess, balls, history)
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(briefing, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printBriefing(briefing, time.Now())
+	return nil
+}
+
+// printBriefing prints a briefing for the terminal
+func printBriefing(briefing *session.Briefing, now time.Time) {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	header := "Briefing: " + briefing.SessionID
+	if idle := briefing.IdleFor(now); idle >= time.Minute {
+		header += dimStyle.Render(" (last touched " + formatDuration(idle) + " ago)")
+	}
+	fmt.Println(labelStyle.Render(header))
+	if briefing.Goal != "" {
+		fmt.Printf("Goal: %s\n", briefing.Goal)
+	}
+
+	fmt.Println()
+	fmt.Println(labelStyle.Render("Next up:"))
+	if len(briefing.Next) == 0 {
+		fmt.Println(dimStyle.Render("  (nothing ready to work on)"))
+	}
+	for _, ball := range briefing.Next {
+		line := fmt.Sprintf("  %s %s %-11s %s",
+			padRight(ball.ShortID(), 8), padRight(string(ball.Priority), 7), ball.State, ball.Title)
+		if ball.Focused {
+			line += " (focus)"
+		}
+		fmt.Println(line)
+		if ball.NextAction != "" {
+			fmt.Println(dimStyle.Render("    → " + ball.NextAction))
+		}
+	}
+	if more := briefing.Remaining - len(briefing.Next); more > 0 {
+		fmt.Println(dimStyle.Render(fmt.Sprintf("  +%d more pending or in progress", more)))
+	}
+
+	if len(briefing.Blocked) > 0 {
+		fmt.Println()
+		fmt.Println(labelStyle.Render("Blocked:"))
+		for _, ball := range briefing.Blocked {
+			fmt.Printf("  %s %s\n", padRight(ball.ShortID(), 8), ball.Title)
+			if ball.BlockedReason != "" {
+				fmt.Println(dimStyle.Render("    " + ball.BlockedReason))
+			}
+		}
+	}
+
+	fmt.Println()
+	if run := briefing.LastRun; run != nil {
+		fmt.Printf("Last agent run: %s, %s\n", run.StartedAt.Format("2006-01-02 15:04"), run.Outcome())
+	} else {
+		fmt.Println("Last agent run: " + dimStyle.Render("none"))
+	}
+}
@@ -198,7 +198,7 @@ func handleMultipleInProgressBalls(inProgressBalls []*session.Ball, pendingBalls
 	choice := strings.TrimSpace(input)
 	selected, err := strconv.Atoi(choice)
 	if err != nil || selected < 1 || selected > len(inProgressBalls) {
-		return fmt.Errorf("invalid choice: %s (must be 1-%d)", choice, len(inProgressBalls))
+		return validationErrorf("invalid choice: %s (must be 1-%d)", choice, len(inProgressBalls))
 	}
 
 	selectedBall := inProgressBalls[selected-1]
@@ -273,7 +273,7 @@ func promptForPendingBalls(pendingBalls []*session.Ball) error {
 		fmt.Println(dimStyle.Render("Remember: working on too many things reduces focus and effectiveness."))
 		return nil
 	default:
-		return fmt.Errorf("invalid choice: %s (must be 1-4)", choice)
+		return validationErrorf("invalid choice: %s (must be 1-4)", choice)
 	}
 }
 
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/agentlog"
+	"github.com/ohare93/juggle/internal/editor"
 	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/tui"
 	"github.com/ohare93/juggle/internal/vcs"
 	"github.com/spf13/cobra"
 )
@@ -356,7 +360,9 @@ func runConfigPathsPrune(cmd *cobra.Command, args []string) error {
 	}
 
 	// Update config
-	config.SearchPaths = toKeep
+	for _, path := range toRemove {
+		config.UnregisterProject(path)
+	}
 	if err := config.SaveWithOptions(GetConfigOptions()); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
@@ -440,11 +446,6 @@ func runConfigACSet(cmd *cobra.Command, args []string) error {
 
 	if configACEditFlag {
 		// Edit in $EDITOR
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi"
-		}
-
 		// Create temp file with current criteria
 		tmpFile, err := os.CreateTemp("", "juggle-ac-*.txt")
 		if err != nil {
@@ -463,7 +464,7 @@ func runConfigACSet(cmd *cobra.Command, args []string) error {
 		tmpFile.Close()
 
 		// Open editor
-		editorCmd := exec.Command(editor, tmpPath)
+		editorCmd := editor.Command(tmpPath)
 		editorCmd.Stdin = os.Stdin
 		editorCmd.Stdout = os.Stdout
 		editorCmd.Stderr = os.Stderr
@@ -631,11 +632,6 @@ func runConfigTemplatesSet(cmd *cobra.Command, args []string) error {
 
 	if configTemplatesEditFlag {
 		// Edit in $EDITOR
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi"
-		}
-
 		// Create temp file with current templates
 		tmpFile, err := os.CreateTemp("", "juggle-templates-*.txt")
 		if err != nil {
@@ -654,7 +650,7 @@ func runConfigTemplatesSet(cmd *cobra.Command, args []string) error {
 		tmpFile.Close()
 
 		// Open editor
-		editorCmd := exec.Command(editor, tmpPath)
+		editorCmd := editor.Command(tmpPath)
 		editorCmd.Stdin = os.Stdin
 		editorCmd.Stdout = os.Stdout
 		editorCmd.Stderr = os.Stderr
@@ -848,11 +844,11 @@ func runConfigDelaySet(cmd *cobra.Command, args []string) error {
 	var delayMinutes int
 	_, err := fmt.Sscanf(args[0], "%d", &delayMinutes)
 	if err != nil || delayMinutes < 0 {
-		return fmt.Errorf("invalid delay: %s (must be a non-negative integer)", args[0])
+		return validationErrorf("invalid delay: %s (must be a non-negative integer)", args[0])
 	}
 
 	if configDelayFuzz < 0 {
-		return fmt.Errorf("invalid fuzz: %d (must be a non-negative integer)", configDelayFuzz)
+		return validationErrorf("invalid fuzz: %d (must be a non-negative integer)", configDelayFuzz)
 	}
 
 	if err := session.UpdateGlobalIterationDelayWithOptions(GetConfigOptions(), delayMinutes, configDelayFuzz); err != nil {
@@ -882,366 +878,2247 @@ func runConfigDelayClear(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// VCS command variables
-var configVCSProjectFlag bool
+// configScheduleCmd is the parent command for agent schedule settings
+var configScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage quota reset times and quiet hours (global)",
+	Long: `Manage when agents are allowed to run and when usage quotas reset.
 
-// configVCSCmd is the parent command for VCS settings
-var configVCSCmd = &cobra.Command{
-	Use:   "vcs",
-	Short: "Manage version control system settings",
-	Long: `Manage the version control system used for agent commits.
+This is a global setting stored in ~/.juggle/config.json.
 
-By default, juggle auto-detects VCS by checking for .jj (preferred) then .git.
-You can override this globally or per-project.
+Quota reset times tell the agent loop when a usage window resets. When an
+agent is rate limited without an explicit retry-after, the loop sleeps until
+the next reset instead of backing off exponentially.
 
-Resolution order (highest to lowest priority):
-  1. Project config (.juggle/config.json vcs field)
-  2. Global config (~/.juggle/config.json vcs field)
-  3. Auto-detect: .jj directory > .git directory > git (default)
+Quiet hours are daily windows when agents must not start. The agent loop
+waits for the window to end before starting the next iteration. Windows that
+end before they start wrap past midnight (e.g., 22:00-06:00).
+
+Times are HH:MM in local time, optionally followed by a time zone name.
 
 Commands:
-  config vcs show              Show current VCS settings and detection
-  config vcs set <type>        Set VCS type (git or jj)
-  config vcs clear             Clear VCS setting (use auto-detection)
+  config schedule show                Show current schedule settings
+  config schedule reset <times...>    Set quota reset times
+  config schedule quiet <windows...>  Set quiet hours windows
+  config schedule clear               Remove all schedule settings
 
 Examples:
-  juggle config vcs show
-  juggle config vcs set git           # Use git globally
-  juggle config vcs set jj            # Use jj globally
-  juggle config vcs set git --project # Use git for this project only
-  juggle config vcs clear             # Clear global setting
-  juggle config vcs clear --project   # Clear project setting`,
-	RunE: runConfigVCSShow,
+  juggle config schedule reset "00:00 UTC"
+  juggle config schedule quiet 09:00-17:00
+  juggle config schedule quiet "22:00-06:00 Europe/Oslo"
+  juggle config schedule clear`,
+	RunE: runConfigScheduleShow,
 }
 
-var configVCSShowCmd = &cobra.Command{
+var configScheduleShowCmd = &cobra.Command{
 	Use:   "show",
-	Short: "Show current VCS settings and detection",
-	RunE:  runConfigVCSShow,
+	Short: "Show current schedule settings",
+	RunE:  runConfigScheduleShow,
 }
 
-var configVCSSetCmd = &cobra.Command{
-	Use:   "set <type>",
-	Short: "Set VCS type (git or jj)",
-	Long: `Set the version control system type.
-
-Valid types: git, jj
+var configScheduleResetCmd = &cobra.Command{
+	Use:   "reset <time> [time...]",
+	Short: "Set the times of day when the usage quota resets",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runConfigScheduleReset,
+}
 
-Use --project to set for the current project only (stored in .juggle/config.json).
-Without --project, sets the global default (stored in ~/.juggle/config.json).`,
-	Args: cobra.ExactArgs(1),
-	RunE: runConfigVCSSet,
+var configScheduleQuietCmd = &cobra.Command{
+	Use:   "quiet <HH:MM-HH:MM> [window...]",
+	Short: "Set daily windows when agents must not run",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runConfigScheduleQuiet,
 }
 
-var configVCSClearCmd = &cobra.Command{
+var configScheduleClearCmd = &cobra.Command{
 	Use:   "clear",
-	Short: "Clear VCS setting (use auto-detection)",
-	Long: `Clear the VCS setting to use auto-detection.
-
-Use --project to clear the project setting only.
-Without --project, clears the global setting.`,
-	RunE: runConfigVCSClear,
+	Short: "Remove quota reset times and quiet hours",
+	RunE:  runConfigScheduleClear,
 }
 
 func init() {
-	configVCSSetCmd.Flags().BoolVar(&configVCSProjectFlag, "project", false, "Set for this project only (vs global)")
-	configVCSClearCmd.Flags().BoolVar(&configVCSProjectFlag, "project", false, "Clear for this project only (vs global)")
-
-	configVCSCmd.AddCommand(configVCSShowCmd)
-	configVCSCmd.AddCommand(configVCSSetCmd)
-	configVCSCmd.AddCommand(configVCSClearCmd)
+	configScheduleCmd.AddCommand(configScheduleShowCmd)
+	configScheduleCmd.AddCommand(configScheduleResetCmd)
+	configScheduleCmd.AddCommand(configScheduleQuietCmd)
+	configScheduleCmd.AddCommand(configScheduleClearCmd)
 
-	configCmd.AddCommand(configVCSCmd)
+	configCmd.AddCommand(configScheduleCmd)
 }
 
-func runConfigVCSShow(cmd *cobra.Command, args []string) error {
-	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-
-	// Get global config
-	globalVCS, err := session.GetGlobalVCSWithOptions(GetConfigOptions())
+func runConfigScheduleShow(cmd *cobra.Command, args []string) error {
+	resets, quietHours, err := session.GetGlobalScheduleWithOptions(GetConfigOptions())
 	if err != nil {
-		return fmt.Errorf("failed to load global config: %w", err)
+		return fmt.Errorf("failed to load schedule settings: %w", err)
 	}
 
-	fmt.Println(labelStyle.Render("VCS Settings:"))
-	fmt.Println()
+	if len(resets) == 0 && len(quietHours) == 0 {
+		fmt.Println("No agent schedule configured.")
+		fmt.Println("\nSet quota reset times with: juggle config schedule reset \"00:00 UTC\"")
+		fmt.Println("Set quiet hours with: juggle config schedule quiet 09:00-17:00")
+		return nil
+	}
 
-	// Global setting
-	fmt.Printf("  %s: ", keyStyle.Render("global"))
-	if globalVCS == "" {
-		fmt.Println(dimStyle.Render("(not set)"))
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	fmt.Println(labelStyle.Render("Agent Schedule Settings:"))
+	fmt.Println()
+	now := time.Now()
+	if len(resets) > 0 {
+		fmt.Printf("  Quota resets: %s\n", strings.Join(resets, ", "))
+		if next, ok := session.NextQuotaReset(now, resets); ok {
+			fmt.Printf("  Next reset: %s (in %v)\n", next.Local().Format("2006-01-02 15:04 MST"), next.Sub(now).Round(time.Minute))
+		}
 	} else {
-		fmt.Println(valueStyle.Render(globalVCS))
+		fmt.Println("  Quota resets: none (exponential backoff on rate limits)")
 	}
-
-	// Try to load project config
-	cwd, err := GetWorkingDir()
-	if err == nil {
-		projectVCS, err := session.GetProjectVCS(cwd)
-		if err == nil {
-			fmt.Printf("  %s: ", keyStyle.Render("project"))
-			if projectVCS == "" {
-				fmt.Println(dimStyle.Render("(not set)"))
-			} else {
-				fmt.Println(valueStyle.Render(projectVCS))
-			}
-
-			// Show auto-detection result
-			detected := autoDetectVCS(cwd)
-			fmt.Printf("  %s: ", keyStyle.Render("auto-detected"))
-			fmt.Println(valueStyle.Render(detected))
-
-			// Show effective VCS
-			effective := resolveVCS(cwd, projectVCS, globalVCS)
-			fmt.Println()
-			fmt.Printf("  %s: ", keyStyle.Render("effective"))
-			fmt.Println(valueStyle.Render(effective))
+	if len(quietHours) > 0 {
+		fmt.Printf("  Quiet hours: %s\n", strings.Join(quietHours, ", "))
+		if end, quiet := session.QuietHoursEnd(now, quietHours); quiet {
+			fmt.Printf("  Currently quiet until %s\n", end.Local().Format("15:04 MST"))
 		}
+	} else {
+		fmt.Println("  Quiet hours: none")
 	}
 
 	return nil
 }
 
-func runConfigVCSSet(cmd *cobra.Command, args []string) error {
-	vcsType := vcs.VCSType(strings.ToLower(strings.TrimSpace(args[0])))
-	if !vcsType.IsValid() {
-		return fmt.Errorf("invalid VCS type: %s (must be 'git' or 'jj')", args[0])
-	}
-
-	if configVCSProjectFlag {
-		cwd, err := GetWorkingDir()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
-		if err := session.UpdateProjectVCS(cwd, string(vcsType)); err != nil {
-			return fmt.Errorf("failed to set project VCS: %w", err)
-		}
-		fmt.Printf("Set project VCS to: %s\n", vcsType)
-	} else {
-		if err := session.UpdateGlobalVCSWithOptions(GetConfigOptions(), string(vcsType)); err != nil {
-			return fmt.Errorf("failed to set global VCS: %w", err)
-		}
-		fmt.Printf("Set global VCS to: %s\n", vcsType)
+func runConfigScheduleReset(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalQuotaResetTimesWithOptions(GetConfigOptions(), args); err != nil {
+		return fmt.Errorf("failed to save quota reset times: %w", err)
 	}
 
+	fmt.Printf("Set quota reset times: %s\n", strings.Join(args, ", "))
 	return nil
 }
 
-func runConfigVCSClear(cmd *cobra.Command, args []string) error {
-	if configVCSProjectFlag {
-		cwd, err := GetWorkingDir()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
-		if err := session.ClearProjectVCS(cwd); err != nil {
-			return fmt.Errorf("failed to clear project VCS: %w", err)
-		}
-		fmt.Println("Cleared project VCS setting.")
-	} else {
-		if err := session.ClearGlobalVCSWithOptions(GetConfigOptions()); err != nil {
-			return fmt.Errorf("failed to clear global VCS: %w", err)
-		}
-		fmt.Println("Cleared global VCS setting.")
+func runConfigScheduleQuiet(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalQuietHoursWithOptions(GetConfigOptions(), args); err != nil {
+		return fmt.Errorf("failed to save quiet hours: %w", err)
 	}
 
+	fmt.Printf("Set quiet hours: %s\n", strings.Join(args, ", "))
 	return nil
 }
 
-// autoDetectVCS checks for .jj or .git directories
-func autoDetectVCS(projectDir string) string {
-	if _, err := os.Stat(filepath.Join(projectDir, ".jj")); err == nil {
-		return "jj"
+func runConfigScheduleClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalScheduleWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear schedule settings: %w", err)
 	}
-	if _, err := os.Stat(filepath.Join(projectDir, ".git")); err == nil {
-		return "git"
-	}
-	return "git" // default
-}
 
-// resolveVCS determines the effective VCS using resolution priority
-func resolveVCS(projectDir, projectVCS, globalVCS string) string {
-	if projectVCS != "" {
-		return projectVCS
-	}
-	if globalVCS != "" {
-		return globalVCS
-	}
-	return autoDetectVCS(projectDir)
+	fmt.Println("Cleared agent schedule settings.")
+	return nil
 }
 
-// Provider command variables
-var configProviderProjectFlag bool
-
-// configProviderCmd is the parent command for provider settings
-var configProviderCmd = &cobra.Command{
-	Use:   "provider",
-	Short: "Manage agent provider settings",
-	Long: `Manage the agent provider (CLI tool) used for running agents.
+// configIconsCmd is the parent command for icon settings
+var configIconsCmd = &cobra.Command{
+	Use:   "icons",
+	Short: "Manage the glyphs used for states, priorities, and agent status (global)",
+	Long: `Manage the icons used in the TUI for ball states, priorities, and agent status.
 
-By default, juggle uses "claude" (Claude Code CLI).
-You can override this globally or per-project.
+This is a global setting stored in ~/.juggle/config.json.
 
-Available providers:
-  claude    - Claude Code CLI (default)
-  opencode  - OpenCode CLI
+Two icon sets are available:
+  unicode   Default glyphs (○ ● ✓ ✗ ▶ 📋 →)
+  ascii     ASCII-only fallback for terminals/fonts that render Unicode as tofu
 
-Resolution order (highest to lowest priority):
-  1. CLI flag (--provider on agent commands)
-  2. Project config (.juggle/config.json agent_provider field)
-  3. Global config (~/.juggle/config.json agent_provider field)
-  4. Default: claude
+Individual icons can be overridden on top of either set.
 
 Commands:
-  config provider show              Show current provider settings
-  config provider set <provider>    Set provider (claude or opencode)
-  config provider clear             Clear provider setting
+  config icons show                  Show the current icons
+  config icons set <unicode|ascii>   Choose the icon set
+  config icons override <key> <glyph> Override a single icon
+  config icons clear                 Restore the default Unicode set
 
 Examples:
-  juggle config provider show
-  juggle config provider set claude           # Use claude globally
-  juggle config provider set opencode         # Use opencode globally
-  juggle config provider set claude --project # Use claude for this project only
-  juggle config provider clear                # Clear global setting
-  juggle config provider clear --project      # Clear project setting`,
-	RunE: runConfigProviderShow,
+  juggle config icons set ascii
+  juggle config icons override state.pending -
+  juggle config icons override agent.running "*"
+  juggle config icons clear`,
+	RunE: runConfigIconsShow,
 }
 
-var configProviderShowCmd = &cobra.Command{
+var configIconsShowCmd = &cobra.Command{
 	Use:   "show",
-	Short: "Show current provider settings",
-	RunE:  runConfigProviderShow,
+	Short: "Show the current icons",
+	RunE:  runConfigIconsShow,
 }
 
-var configProviderSetCmd = &cobra.Command{
-	Use:   "set <provider>",
-	Short: "Set agent provider (claude or opencode)",
-	Long: `Set the agent provider.
-
-Valid providers: claude, opencode
+var configIconsSetCmd = &cobra.Command{
+	Use:   "set <unicode|ascii>",
+	Short: "Choose the icon set",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigIconsSet,
+}
 
-Use --project to set for the current project only (stored in .juggle/config.json).
-Without --project, sets the global default (stored in ~/.juggle/config.json).`,
-	Args: cobra.ExactArgs(1),
-	RunE: runConfigProviderSet,
+var configIconsOverrideCmd = &cobra.Command{
+	Use:   "override <key> <glyph>",
+	Short: "Override a single icon (see 'config icons show' for keys)",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigIconsOverride,
 }
 
-var configProviderClearCmd = &cobra.Command{
+var configIconsClearCmd = &cobra.Command{
 	Use:   "clear",
-	Short: "Clear provider setting (use default)",
-	Long: `Clear the provider setting to use the default (claude).
-
-Use --project to clear the project setting only.
-Without --project, clears the global setting.`,
-	RunE: runConfigProviderClear,
+	Short: "Restore the default Unicode icons",
+	RunE:  runConfigIconsClear,
 }
 
 func init() {
-	configProviderSetCmd.Flags().BoolVar(&configProviderProjectFlag, "project", false, "Set for this project only (vs global)")
-	configProviderClearCmd.Flags().BoolVar(&configProviderProjectFlag, "project", false, "Clear for this project only (vs global)")
-
-	configProviderCmd.AddCommand(configProviderShowCmd)
-	configProviderCmd.AddCommand(configProviderSetCmd)
-	configProviderCmd.AddCommand(configProviderClearCmd)
+	configIconsCmd.AddCommand(configIconsShowCmd)
+	configIconsCmd.AddCommand(configIconsSetCmd)
+	configIconsCmd.AddCommand(configIconsOverrideCmd)
+	configIconsCmd.AddCommand(configIconsClearCmd)
 
-	configCmd.AddCommand(configProviderCmd)
+	configCmd.AddCommand(configIconsCmd)
 }
 
-func runConfigProviderShow(cmd *cobra.Command, args []string) error {
-	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
-	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-
-	// Get global config
-	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+func runConfigIconsShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
 	if err != nil {
-		return fmt.Errorf("failed to load global config: %w", err)
+		return fmt.Errorf("failed to load icon settings: %w", err)
 	}
 
-	fmt.Println(labelStyle.Render("Provider Settings:"))
-	fmt.Println()
-
-	// Global setting
-	fmt.Printf("  %s: ", keyStyle.Render("global"))
-	if globalProvider == "" {
-		fmt.Println(dimStyle.Render("(not set)"))
-	} else {
-		fmt.Println(valueStyle.Render(globalProvider))
+	iconSet := config.IconSet
+	if iconSet == "" {
+		iconSet = session.IconSetUnicode
 	}
 
-	// Try to load project config
-	cwd, err := GetWorkingDir()
-	if err == nil {
-		projectProvider, err := session.GetProjectAgentProvider(cwd)
-		if err == nil {
-			fmt.Printf("  %s: ", keyStyle.Render("project"))
-			if projectProvider == "" {
-				fmt.Println(dimStyle.Render("(not set)"))
-			} else {
-				fmt.Println(valueStyle.Render(projectProvider))
-			}
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	fmt.Println(labelStyle.Render("Icon Settings:"))
+	fmt.Println()
+	fmt.Printf("  Icon set: %s\n", iconSet)
+	fmt.Println()
 
-			// Show effective provider
-			effective := resolveProvider(projectProvider, globalProvider)
-			fmt.Println()
-			fmt.Printf("  %s: ", keyStyle.Render("effective"))
-			fmt.Println(valueStyle.Render(effective))
+	icons := session.ResolveIcons(config)
+	for _, key := range session.IconKeys() {
+		marker := ""
+		if _, ok := config.IconOverrides[key]; ok {
+			marker = " (override)"
 		}
+		fmt.Printf("  %-18s %s%s\n", key, icons.Get(key), marker)
 	}
 
 	return nil
 }
 
-func runConfigProviderSet(cmd *cobra.Command, args []string) error {
-	provider := strings.ToLower(strings.TrimSpace(args[0]))
-	if provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid provider: %s (must be 'claude' or 'opencode')", args[0])
+func runConfigIconsSet(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalIconSetWithOptions(GetConfigOptions(), args[0]); err != nil {
+		return fmt.Errorf("failed to save icon set: %w", err)
 	}
 
-	// Check if CLI is available in PATH
-	if _, err := exec.LookPath(provider); err != nil {
-		fmt.Printf("Warning: %s not found in PATH. Install it before running agents.\n", provider)
+	fmt.Printf("Set icon set: %s\n", args[0])
+	return nil
+}
+
+func runConfigIconsOverride(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalIconOverrideWithOptions(GetConfigOptions(), args[0], args[1]); err != nil {
+		return fmt.Errorf("failed to save icon override: %w", err)
 	}
 
-	if configProviderProjectFlag {
-		cwd, err := GetWorkingDir()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %w", err)
-		}
-		if err := session.UpdateProjectAgentProvider(cwd, provider); err != nil {
-			return fmt.Errorf("failed to set project provider: %w", err)
-		}
-		fmt.Printf("Set project provider to: %s\n", provider)
-	} else {
-		if err := session.UpdateGlobalAgentProviderWithOptions(GetConfigOptions(), provider); err != nil {
-			return fmt.Errorf("failed to set global provider: %w", err)
-		}
-		fmt.Printf("Set global provider to: %s\n", provider)
+	fmt.Printf("Set icon %s: %s\n", args[0], args[1])
+	return nil
+}
+
+func runConfigIconsClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalIconsWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear icon settings: %w", err)
 	}
 
+	fmt.Println("Cleared icon settings (using default Unicode icons).")
 	return nil
 }
 
-func runConfigProviderClear(cmd *cobra.Command, args []string) error {
-	if configProviderProjectFlag {
-		cwd, err := GetWorkingDir()
-		if err != nil {
+// configViewCmd is the parent command for the TUI startup view
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Manage the layout the TUI starts in (global)",
+	Long: `Manage the layout the TUI starts in.
+
+This is a global setting stored in ~/.juggle/config.json. The --view flag
+of 'juggle tui' overrides it for a single launch.
+
+Layouts:
+  split   Sessions panel beside the balls panel (default)
+  list    Balls panel at full width, sessions panel hidden
+  board   Balls grouped into one column per state
+
+Commands:
+  config view show                  Show the startup view
+  config view set <split|list|board> Set the startup view
+  config view clear                 Restore the default (split)`,
+	RunE: runConfigViewShow,
+}
+
+var configViewShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the startup view",
+	RunE:  runConfigViewShow,
+}
+
+var configViewSetCmd = &cobra.Command{
+	Use:   "set <split|list|board>",
+	Short: "Set the startup view",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigViewSet,
+}
+
+var configViewClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Restore the default startup view (split)",
+	RunE:  runConfigViewClear,
+}
+
+func init() {
+	configViewCmd.AddCommand(configViewShowCmd)
+	configViewCmd.AddCommand(configViewSetCmd)
+	configViewCmd.AddCommand(configViewClearCmd)
+
+	configCmd.AddCommand(configViewCmd)
+}
+
+func runConfigViewShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load view settings: %w", err)
+	}
+
+	fmt.Printf("Startup view: %s\n", config.GetDefaultView())
+	return nil
+}
+
+func runConfigViewSet(cmd *cobra.Command, args []string) error {
+	if err := session.UpdateGlobalDefaultViewWithOptions(GetConfigOptions(), args[0]); err != nil {
+		return fmt.Errorf("failed to save startup view: %w", err)
+	}
+
+	fmt.Printf("Set startup view: %s\n", args[0])
+	return nil
+}
+
+func runConfigViewClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalDefaultViewWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear startup view: %w", err)
+	}
+
+	fmt.Println("Cleared startup view (using split).")
+	return nil
+}
+
+// configSafeModeCmd is the parent command for the safe-mode revert policy
+var configSafeModeCmd = &cobra.Command{
+	Use:   "safe-mode",
+	Short: "Manage what 'agent run --safe' does when a run fails (global)",
+	Long: `Manage what 'juggle agent run --safe' does when a run fails.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Policies:
+  ask    Ask before reverting to the pre-run snapshot (default).
+         Without a terminal, the changes are kept.
+  auto   Revert to the pre-run snapshot without asking
+
+Commands:
+  config safe-mode show            Show the revert policy
+  config safe-mode set <ask|auto>  Set the revert policy
+  config safe-mode clear           Restore the default (ask)`,
+	RunE: runConfigSafeModeShow,
+}
+
+var configSafeModeShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the safe-mode revert policy",
+	RunE:  runConfigSafeModeShow,
+}
+
+var configSafeModeSetCmd = &cobra.Command{
+	Use:   "set <ask|auto>",
+	Short: "Set the safe-mode revert policy",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigSafeModeSet,
+}
+
+var configSafeModeClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Restore the default safe-mode revert policy (ask)",
+	RunE:  runConfigSafeModeClear,
+}
+
+func init() {
+	configSafeModeCmd.AddCommand(configSafeModeShowCmd)
+	configSafeModeCmd.AddCommand(configSafeModeSetCmd)
+	configSafeModeCmd.AddCommand(configSafeModeClearCmd)
+
+	configCmd.AddCommand(configSafeModeCmd)
+}
+
+func runConfigSafeModeShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load safe mode settings: %w", err)
+	}
+
+	fmt.Printf("Safe mode revert: %s\n", config.GetSafeModeRevert())
+	return nil
+}
+
+func runConfigSafeModeSet(cmd *cobra.Command, args []string) error {
+	if !session.ValidateSafeModeRevert(args[0]) {
+		return validationErrorf("invalid safe mode revert policy %q (must be ask or auto)", args[0])
+	}
+	if err := session.UpdateGlobalSafeModeRevertWithOptions(GetConfigOptions(), args[0]); err != nil {
+		return fmt.Errorf("failed to save safe mode revert policy: %w", err)
+	}
+
+	fmt.Printf("Set safe mode revert: %s\n", args[0])
+	return nil
+}
+
+func runConfigSafeModeClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalSafeModeRevertWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear safe mode revert policy: %w", err)
+	}
+
+	fmt.Println("Cleared safe mode revert (using ask).")
+	return nil
+}
+
+// configOwnershipCmd is the parent command for the completion ownership policy
+var configOwnershipCmd = &cobra.Command{
+	Use:   "ownership",
+	Short: "Manage what happens to a ball's other sessions when an agent completes it (global)",
+	Long: `Manage what happens when an agent run completes a ball that is tagged in
+more than one session.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Either way, the ball records which session's run completed it, and only that
+session counts it as progress (throughput, reports).
+
+Policies:
+  keep      Other sessions keep their tags on the ball (default)
+  transfer  Remove the other sessions' tags from the ball
+
+Commands:
+  config ownership show                 Show the policy
+  config ownership set <keep|transfer>  Set the policy
+  config ownership clear                Restore the default (keep)`,
+	RunE: runConfigOwnershipShow,
+}
+
+var configOwnershipShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the completion ownership policy",
+	RunE:  runConfigOwnershipShow,
+}
+
+var configOwnershipSetCmd = &cobra.Command{
+	Use:   "set <keep|transfer>",
+	Short: "Set the completion ownership policy",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigOwnershipSet,
+}
+
+var configOwnershipClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Restore the default completion ownership policy (keep)",
+	RunE:  runConfigOwnershipClear,
+}
+
+func init() {
+	configOwnershipCmd.AddCommand(configOwnershipShowCmd)
+	configOwnershipCmd.AddCommand(configOwnershipSetCmd)
+	configOwnershipCmd.AddCommand(configOwnershipClearCmd)
+
+	configCmd.AddCommand(configOwnershipCmd)
+}
+
+func runConfigOwnershipShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load completion ownership settings: %w", err)
+	}
+
+	fmt.Printf("Completion ownership: %s\n", config.GetCompletionOwnership())
+	return nil
+}
+
+func runConfigOwnershipSet(cmd *cobra.Command, args []string) error {
+	if !session.ValidateCompletionOwnership(args[0]) {
+		return validationErrorf("invalid completion ownership policy %q (must be keep or transfer)", args[0])
+	}
+	if err := session.UpdateGlobalCompletionOwnershipWithOptions(GetConfigOptions(), args[0]); err != nil {
+		return fmt.Errorf("failed to save completion ownership policy: %w", err)
+	}
+
+	fmt.Printf("Set completion ownership: %s\n", args[0])
+	return nil
+}
+
+func runConfigOwnershipClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalCompletionOwnershipWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear completion ownership policy: %w", err)
+	}
+
+	fmt.Println("Cleared completion ownership (using keep).")
+	return nil
+}
+
+// Title rules command variables
+var (
+	configTitlesMaxLength  int
+	configTitlesImperative bool
+	configTitlesCapitalize bool
+	configTitlesForbid     []string
+)
+
+// configTitlesCmd is the parent command for the project's title rules
+var configTitlesCmd = &cobra.Command{
+	Use:   "titles",
+	Short: "Manage the conventions ball titles are checked against (project)",
+	Long: `Manage the project's title rules, stored in .juggle/config.json.
+
+Titles that break a rule are warned about when balls are planned (CLI or
+TUI form), and listed by 'juggle lint titles', which can --fix them.
+
+Rules:
+  --max-length N        Longest title, in characters (0 = no limit)
+  --imperative          Start with a verb in the imperative ("Add", not "Added")
+  --capitalize          Start with a capital letter, no trailing period
+  --forbid-prefix TEXT  Prefix titles may not start with (repeatable, e.g. WIP)
+
+Commands:
+  config titles show          Show the title rules
+  config titles set [flags]   Change the given rules, keeping the others
+  config titles clear         Remove all title rules`,
+	RunE: runConfigTitlesShow,
+}
+
+var configTitlesShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the title rules",
+	RunE:  runConfigTitlesShow,
+}
+
+var configTitlesSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Change the given title rules, keeping the others",
+	Long: `Change the given title rules, keeping the others.
+
+Examples:
+  juggle config titles set --max-length 72 --imperative --capitalize
+  juggle config titles set --forbid-prefix WIP --forbid-prefix "TODO:"
+  juggle config titles set --imperative=false`,
+	Args: cobra.NoArgs,
+	RunE: runConfigTitlesSet,
+}
+
+var configTitlesClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all title rules",
+	RunE:  runConfigTitlesClear,
+}
+
+func init() {
+	configTitlesSetCmd.Flags().IntVar(&configTitlesMaxLength, "max-length", 0, "Longest title, in characters (0 = no limit)")
+	configTitlesSetCmd.Flags().BoolVar(&configTitlesImperative, "imperative", false, "Titles start with a verb in the imperative")
+	configTitlesSetCmd.Flags().BoolVar(&configTitlesCapitalize, "capitalize", false, "Titles start with a capital letter and have no trailing period")
+	configTitlesSetCmd.Flags().StringArrayVar(&configTitlesForbid, "forbid-prefix", nil, "Prefix titles may not start with (repeatable; replaces the list)")
+
+	configTitlesCmd.AddCommand(configTitlesShowCmd)
+	configTitlesCmd.AddCommand(configTitlesSetCmd)
+	configTitlesCmd.AddCommand(configTitlesClearCmd)
+
+	configCmd.AddCommand(configTitlesCmd)
+}
+
+func runConfigTitlesShow(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	rules, err := session.GetProjectTitleRules(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load title rules: %w", err)
+	}
+	if rules == nil {
+		fmt.Println("No title rules set.")
+		return nil
+	}
+
+	fmt.Println("Title rules:")
+	if rules.MaxLength > 0 {
+		fmt.Printf("  Max length: %d\n", rules.MaxLength)
+	}
+	if rules.Imperative {
+		fmt.Println("  Imperative mood")
+	}
+	if rules.Capitalize {
+		fmt.Println("  Capitalized, no trailing period")
+	}
+	if len(rules.ForbiddenPrefixes) > 0 {
+		fmt.Printf("  Forbidden prefixes: %s\n", strings.Join(rules.ForbiddenPrefixes, ", "))
+	}
+	return nil
+}
+
+func runConfigTitlesSet(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	rules, err := session.GetProjectTitleRules(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load title rules: %w", err)
+	}
+	if rules == nil {
+		rules = &session.TitleRules{}
+	}
+
+	flags := cmd.Flags()
+	if flags.NFlag() == 0 {
+		return usageErrorf("set at least one rule (see 'juggle config titles --help')")
+	}
+	if flags.Changed("max-length") {
+		rules.MaxLength = configTitlesMaxLength
+	}
+	if flags.Changed("imperative") {
+		rules.Imperative = configTitlesImperative
+	}
+	if flags.Changed("capitalize") {
+		rules.Capitalize = configTitlesCapitalize
+	}
+	if flags.Changed("forbid-prefix") {
+		rules.ForbiddenPrefixes = nil
+		for _, prefix := range configTitlesForbid {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				rules.ForbiddenPrefixes = append(rules.ForbiddenPrefixes, prefix)
+			}
+		}
+	}
+
+	if err := session.UpdateProjectTitleRules(cwd, rules); err != nil {
+		return validationErrorf("failed to save title rules: %w", err)
+	}
+	fmt.Println("Updated title rules.")
+	return runConfigTitlesShow(cmd, args)
+}
+
+func runConfigTitlesClear(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if err := session.UpdateProjectTitleRules(cwd, nil); err != nil {
+		return fmt.Errorf("failed to clear title rules: %w", err)
+	}
+
+	fmt.Println("Cleared title rules.")
+	return nil
+}
+
+// VCS command variables
+var configVCSProjectFlag bool
+
+// configVCSCmd is the parent command for VCS settings
+var configVCSCmd = &cobra.Command{
+	Use:   "vcs",
+	Short: "Manage version control system settings",
+	Long: `Manage the version control system used for agent commits.
+
+By default, juggle auto-detects VCS by checking the project and its parents
+for .jj (preferred) then .git. You can override this globally or per-project.
+
+Resolution order (highest to lowest priority):
+  1. Project config (.juggle/config.json vcs field)
+  2. Global config (~/.juggle/config.json vcs field)
+  3. Auto-detect: .jj directory > .git directory > none
+
+Without version control ("none"), agent changes are left uncommitted, and
+safe mode, ball revisions and diffs use snapshots of the project's files kept
+in .juggle/snapshots. A project outside any repository always uses "none",
+whatever the settings.
+
+Commands:
+  config vcs show              Show current VCS settings and detection
+  config vcs set <type>        Set VCS type (git, jj or none)
+  config vcs clear             Clear VCS setting (use auto-detection)
+
+Examples:
+  juggle config vcs show
+  juggle config vcs set git           # Use git globally
+  juggle config vcs set jj            # Use jj globally
+  juggle config vcs set git --project # Use git for this project only
+  juggle config vcs clear             # Clear global setting
+  juggle config vcs clear --project   # Clear project setting`,
+	RunE: runConfigVCSShow,
+}
+
+var configVCSShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show current VCS settings and detection",
+	RunE:  runConfigVCSShow,
+}
+
+var configVCSSetCmd = &cobra.Command{
+	Use:   "set <type>",
+	Short: "Set VCS type (git, jj or none)",
+	Long: `Set the version control system type.
+
+Valid types: git, jj, none
+
+Use --project to set for the current project only (stored in .juggle/config.json).
+Without --project, sets the global default (stored in ~/.juggle/config.json).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigVCSSet,
+}
+
+var configVCSClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear VCS setting (use auto-detection)",
+	Long: `Clear the VCS setting to use auto-detection.
+
+Use --project to clear the project setting only.
+Without --project, clears the global setting.`,
+	RunE: runConfigVCSClear,
+}
+
+func init() {
+	configVCSSetCmd.Flags().BoolVar(&configVCSProjectFlag, "project", false, "Set for this project only (vs global)")
+	configVCSClearCmd.Flags().BoolVar(&configVCSProjectFlag, "project", false, "Clear for this project only (vs global)")
+
+	configVCSCmd.AddCommand(configVCSShowCmd)
+	configVCSCmd.AddCommand(configVCSSetCmd)
+	configVCSCmd.AddCommand(configVCSClearCmd)
+
+	configCmd.AddCommand(configVCSCmd)
+}
+
+func runConfigVCSShow(cmd *cobra.Command, args []string) error {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	// Get global config
+	globalVCS, err := session.GetGlobalVCSWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	fmt.Println(labelStyle.Render("VCS Settings:"))
+	fmt.Println()
+
+	// Global setting
+	fmt.Printf("  %s: ", keyStyle.Render("global"))
+	if globalVCS == "" {
+		fmt.Println(dimStyle.Render("(not set)"))
+	} else {
+		fmt.Println(valueStyle.Render(globalVCS))
+	}
+
+	// Try to load project config
+	cwd, err := GetWorkingDir()
+	if err == nil {
+		projectVCS, err := session.GetProjectVCS(cwd)
+		if err == nil {
+			fmt.Printf("  %s: ", keyStyle.Render("project"))
+			if projectVCS == "" {
+				fmt.Println(dimStyle.Render("(not set)"))
+			} else {
+				fmt.Println(valueStyle.Render(projectVCS))
+			}
+
+			// Show auto-detection result
+			detected := autoDetectVCS(cwd)
+			fmt.Printf("  %s: ", keyStyle.Render("auto-detected"))
+			fmt.Println(valueStyle.Render(detected))
+
+			// Show effective VCS
+			effective := resolveVCS(cwd, projectVCS, globalVCS)
+			fmt.Println()
+			fmt.Printf("  %s: ", keyStyle.Render("effective"))
+			fmt.Println(valueStyle.Render(effective))
+		}
+	}
+
+	return nil
+}
+
+func runConfigVCSSet(cmd *cobra.Command, args []string) error {
+	vcsType := vcs.VCSType(strings.ToLower(strings.TrimSpace(args[0])))
+	if !vcsType.IsValid() {
+		return validationErrorf("invalid VCS type: %s (must be 'git', 'jj' or 'none')", args[0])
+	}
+
+	if configVCSProjectFlag {
+		cwd, err := GetWorkingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if err := session.UpdateProjectVCS(cwd, string(vcsType)); err != nil {
+			return fmt.Errorf("failed to set project VCS: %w", err)
+		}
+		fmt.Printf("Set project VCS to: %s\n", vcsType)
+	} else {
+		if err := session.UpdateGlobalVCSWithOptions(GetConfigOptions(), string(vcsType)); err != nil {
+			return fmt.Errorf("failed to set global VCS: %w", err)
+		}
+		fmt.Printf("Set global VCS to: %s\n", vcsType)
+	}
+
+	return nil
+}
+
+func runConfigVCSClear(cmd *cobra.Command, args []string) error {
+	if configVCSProjectFlag {
+		cwd, err := GetWorkingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if err := session.ClearProjectVCS(cwd); err != nil {
+			return fmt.Errorf("failed to clear project VCS: %w", err)
+		}
+		fmt.Println("Cleared project VCS setting.")
+	} else {
+		if err := session.ClearGlobalVCSWithOptions(GetConfigOptions()); err != nil {
+			return fmt.Errorf("failed to clear global VCS: %w", err)
+		}
+		fmt.Println("Cleared global VCS setting.")
+	}
+
+	return nil
+}
+
+// autoDetectVCS checks the project and its parents for .jj or .git
+func autoDetectVCS(projectDir string) string {
+	return string(vcs.AutoDetect(projectDir))
+}
+
+// resolveVCS determines the effective VCS using resolution priority
+func resolveVCS(projectDir, projectVCS, globalVCS string) string {
+	return string(vcs.GetBackendForProject(projectDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS)).Type())
+}
+
+// Provider command variables
+var (
+	configProviderProjectFlag bool
+	configProviderForFlag     string
+)
+
+// configProviderCmd is the parent command for provider settings
+var configProviderCmd = &cobra.Command{
+	Use:   "provider",
+	Short: "Manage agent provider settings",
+	Long: `Manage the agent provider (CLI tool) used for running agents.
+
+By default, juggle uses "claude" (Claude Code CLI).
+You can override this globally or per-project.
+
+Available providers:
+  claude    - Claude Code CLI (default)
+  opencode  - OpenCode CLI
+  http      - Call an LLM HTTP API directly, Anthropic or OpenAI (see 'juggle config http')
+  ollama    - Local models served by Ollama (see 'juggle config ollama')
+  shell     - Any command, given the prompt on stdin (see 'juggle config shell')
+
+Resolution order (highest to lowest priority):
+  1. CLI flag (--provider on agent commands)
+  2. Ball override (agent_provider, when the run works on a single ball)
+  3. Session (juggle sessions edit <id> --provider)
+  4. Model size route (set with --for), for the model the iteration picked
+  5. Project config (.juggle/config.json agent_provider field)
+  6. Global config (~/.juggle/config.json agent_provider field)
+  7. Default: claude
+
+Commands:
+  config provider show              Show current provider settings
+  config provider set <provider>    Set provider (claude, opencode, http, ollama, or shell)
+  config provider clear             Clear provider setting
+
+Examples:
+  juggle config provider show
+  juggle config provider set claude           # Use claude globally
+  juggle config provider set opencode         # Use opencode globally
+  juggle config provider set claude --project # Use claude for this project only
+  juggle config provider set ollama --for small  # Run small-model iterations locally
+  juggle config provider clear --for small    # Remove the route
+  juggle config provider clear                # Clear global setting
+  juggle config provider clear --project      # Clear project setting`,
+	RunE: runConfigProviderShow,
+}
+
+var configProviderShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show current provider settings",
+	RunE:  runConfigProviderShow,
+}
+
+var configProviderSetCmd = &cobra.Command{
+	Use:   "set <provider>",
+	Short: "Set agent provider (claude, opencode, http, ollama, or shell)",
+	Long: `Set the agent provider.
+
+Valid providers: claude, opencode, http, ollama, shell
+
+Use --for small|medium|large to route iterations that run that model size
+to the provider instead of setting the default.
+
+Use --project to set for the current project only (stored in .juggle/config.json).
+Without --project, sets the global default (stored in ~/.juggle/config.json).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigProviderSet,
+}
+
+var configProviderClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear provider setting (use default)",
+	Long: `Clear the provider setting to use the default (claude).
+
+Use --for small|medium|large to remove a model size route instead.
+Use --project to clear the project setting only.
+Without --project, clears the global setting.`,
+	RunE: runConfigProviderClear,
+}
+
+func init() {
+	configProviderSetCmd.Flags().BoolVar(&configProviderProjectFlag, "project", false, "Set for this project only (vs global)")
+	configProviderClearCmd.Flags().BoolVar(&configProviderProjectFlag, "project", false, "Clear for this project only (vs global)")
+	configProviderSetCmd.Flags().StringVar(&configProviderForFlag, "for", "", "Route a model size to the provider: small, medium, or large")
+	configProviderClearCmd.Flags().StringVar(&configProviderForFlag, "for", "", "Remove the route for a model size: small, medium, or large")
+
+	configProviderCmd.AddCommand(configProviderShowCmd)
+	configProviderCmd.AddCommand(configProviderSetCmd)
+	configProviderCmd.AddCommand(configProviderClearCmd)
+
+	configCmd.AddCommand(configProviderCmd)
+}
+
+func runConfigProviderShow(cmd *cobra.Command, args []string) error {
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	// Get global config
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	fmt.Println(labelStyle.Render("Provider Settings:"))
+	fmt.Println()
+
+	// Global setting
+	fmt.Printf("  %s: ", keyStyle.Render("global"))
+	if globalProvider == "" {
+		fmt.Println(dimStyle.Render("(not set)"))
+	} else {
+		fmt.Println(valueStyle.Render(globalProvider))
+	}
+
+	// Try to load project config
+	cwd, err := GetWorkingDir()
+	if err == nil {
+		projectProvider, err := session.GetProjectAgentProvider(cwd)
+		if err == nil {
+			fmt.Printf("  %s: ", keyStyle.Render("project"))
+			if projectProvider == "" {
+				fmt.Println(dimStyle.Render("(not set)"))
+			} else {
+				fmt.Println(valueStyle.Render(projectProvider))
+			}
+
+			// Show effective provider
+			effective := resolveProvider(projectProvider, globalProvider)
+			fmt.Println()
+			fmt.Printf("  %s: ", keyStyle.Render("effective"))
+			fmt.Println(valueStyle.Render(effective))
+		}
+	}
+
+	// Model size routes, project over global
+	routes := loadModelProviders(cwd)
+	if len(routes) > 0 {
+		fmt.Println()
+		fmt.Println(labelStyle.Render("Model Size Routes:"))
+		fmt.Println()
+		for _, size := range []string{"small", "medium", "large"} {
+			if routed := routes[size]; routed != "" {
+				fmt.Printf("  %s: %s\n", keyStyle.Render(size), valueStyle.Render(routed))
+			}
+		}
+	}
+
+	return nil
+}
+
+func runConfigProviderSet(cmd *cobra.Command, args []string) error {
+	provider := strings.ToLower(strings.TrimSpace(args[0]))
+	if provider == "" || !session.ValidateAgentProvider(provider) {
+		return validationErrorf("invalid provider: %s (must be 'claude', 'opencode', 'http', 'ollama', or 'shell')", args[0])
+	}
+
+	// Check if CLI is available in PATH (http, ollama and shell have no CLI)
+	if provider == "claude" || provider == "opencode" {
+		if _, err := exec.LookPath(provider); err != nil {
+			fmt.Printf("Warning: %s not found in PATH. Install it before running agents.\n", provider)
+		}
+	}
+
+	if configProviderForFlag != "" {
+		return setConfigModelProvider(provider)
+	}
+
+	if configProviderProjectFlag {
+		cwd, err := GetWorkingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if err := session.UpdateProjectAgentProvider(cwd, provider); err != nil {
+			return fmt.Errorf("failed to set project provider: %w", err)
+		}
+		fmt.Printf("Set project provider to: %s\n", provider)
+	} else {
+		if err := session.UpdateGlobalAgentProviderWithOptions(GetConfigOptions(), provider); err != nil {
+			return fmt.Errorf("failed to set global provider: %w", err)
+		}
+		fmt.Printf("Set global provider to: %s\n", provider)
+	}
+
+	return nil
+}
+
+func runConfigProviderClear(cmd *cobra.Command, args []string) error {
+	if configProviderForFlag != "" {
+		return setConfigModelProvider("")
+	}
+
+	if configProviderProjectFlag {
+		cwd, err := GetWorkingDir()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		if err := session.ClearProjectAgentProvider(cwd); err != nil {
+			return fmt.Errorf("failed to clear project provider: %w", err)
+		}
+		fmt.Println("Cleared project provider setting.")
+	} else {
+		if err := session.ClearGlobalAgentProviderWithOptions(GetConfigOptions()); err != nil {
+			return fmt.Errorf("failed to clear global provider: %w", err)
+		}
+		fmt.Println("Cleared global provider setting.")
+	}
+
+	return nil
+}
+
+// setConfigModelProvider routes the --for model size to provider, or removes
+// the route when provider is empty
+func setConfigModelProvider(provider string) error {
+	size := session.ModelProviderSize(strings.ToLower(strings.TrimSpace(configProviderForFlag)))
+	if size == "" {
+		return validationErrorf("invalid model size: %s (must be small, medium, or large)", configProviderForFlag)
+	}
+
+	scope := "global"
+	if configProviderProjectFlag {
+		scope = "project"
+		cwd, err := GetWorkingDir()
+		if err != nil {
 			return fmt.Errorf("failed to get current directory: %w", err)
 		}
-		if err := session.ClearProjectAgentProvider(cwd); err != nil {
-			return fmt.Errorf("failed to clear project provider: %w", err)
+		if err := session.UpdateProjectModelProvider(cwd, size, provider); err != nil {
+			return fmt.Errorf("failed to set project model provider: %w", err)
+		}
+	} else if err := session.UpdateGlobalModelProviderWithOptions(GetConfigOptions(), size, provider); err != nil {
+		return fmt.Errorf("failed to set global model provider: %w", err)
+	}
+
+	if provider == "" {
+		fmt.Printf("Cleared %s provider route for %s models.\n", scope, size)
+	} else {
+		fmt.Printf("Routed %s models to %s (%s).\n", size, provider, scope)
+	}
+	return nil
+}
+
+// HTTP agent command variables
+var (
+	configHTTPEndpoint  string
+	configHTTPFormat    string
+	configHTTPModel     string
+	configHTTPMaxTokens int
+	configHTTPHeaders   []string
+)
+
+// configHTTPCmd is the parent command for the http provider's API settings
+var configHTTPCmd = &cobra.Command{
+	Use:   "http",
+	Short: "Manage the API used by the http agent provider (global)",
+	Long: `Manage the API settings for the "http" agent provider.
+
+The http provider sends the agent prompt straight to an LLM HTTP API and
+streams the reply, instead of running an agent CLI. It has no tools, so the
+model can only answer with text. Rate limits (HTTP 429) and server errors
+(5xx, including 529 overloaded) are retried like CLI rate limits and overloads.
+
+This is a global setting stored in ~/.juggle/config.json. Select the provider
+with 'juggle config provider set http' or 'juggle agent run --provider http'.
+
+Formats:
+  anthropic   Anthropic Messages API (default, key from $ANTHROPIC_API_KEY)
+  openai      OpenAI-compatible chat completions (key from $OPENAI_API_KEY)
+
+Header values may reference environment variables, which are expanded when
+the request is sent, so secrets don't need to be stored in the config.
+
+Commands:
+  config http show                    Show the API settings
+  config http set [flags]             Update the API settings
+  config http clear                   Remove the API settings
+
+Examples:
+  juggle config http set --model claude-sonnet-4-5
+  juggle config http set --format openai --endpoint http://localhost:11434/v1/chat/completions --model llama3
+  juggle config http set --header 'Authorization=Bearer $MY_GATEWAY_TOKEN'
+  juggle config http set --header Authorization=   # Remove a header`,
+	RunE: runConfigHTTPShow,
+}
+
+var configHTTPShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the http provider's API settings",
+	RunE:  runConfigHTTPShow,
+}
+
+var configHTTPSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the http provider's API settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigHTTPSet,
+}
+
+var configHTTPClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the http provider's API settings",
+	RunE:  runConfigHTTPClear,
+}
+
+func init() {
+	configHTTPSetCmd.Flags().StringVar(&configHTTPEndpoint, "endpoint", "", "API URL")
+	configHTTPSetCmd.Flags().StringVar(&configHTTPFormat, "format", "", "API format: anthropic or openai")
+	configHTTPSetCmd.Flags().StringVar(&configHTTPModel, "model", "", "Model used when the run doesn't pick one")
+	configHTTPSetCmd.Flags().IntVar(&configHTTPMaxTokens, "max-tokens", 0, "Maximum tokens per response")
+	configHTTPSetCmd.Flags().StringArrayVar(&configHTTPHeaders, "header", nil, "Request header as Name=Value (repeatable, empty value removes it)")
+
+	configHTTPCmd.AddCommand(configHTTPShowCmd)
+	configHTTPCmd.AddCommand(configHTTPSetCmd)
+	configHTTPCmd.AddCommand(configHTTPClearCmd)
+
+	configCmd.AddCommand(configHTTPCmd)
+}
+
+func runConfigHTTPShow(cmd *cobra.Command, args []string) error {
+	httpAgent, err := session.GetGlobalHTTPAgentWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load http provider settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	orDefault := func(value string) string {
+		if value == "" {
+			return dimStyle.Render("(default)")
+		}
+		return value
+	}
+
+	fmt.Println(labelStyle.Render("HTTP Provider Settings:"))
+	fmt.Println()
+	fmt.Printf("  endpoint:   %s\n", orDefault(httpAgent.Endpoint))
+	fmt.Printf("  format:     %s\n", orDefault(httpAgent.Format))
+	fmt.Printf("  model:      %s\n", orDefault(httpAgent.Model))
+	maxTokens := ""
+	if httpAgent.MaxTokens > 0 {
+		maxTokens = fmt.Sprintf("%d", httpAgent.MaxTokens)
+	}
+	fmt.Printf("  max_tokens: %s\n", orDefault(maxTokens))
+	if len(httpAgent.Headers) > 0 {
+		fmt.Println("  headers:")
+		for name, value := range httpAgent.Headers {
+			fmt.Printf("    %s: %s\n", name, value)
+		}
+	}
+	return nil
+}
+
+func runConfigHTTPSet(cmd *cobra.Command, args []string) error {
+	headers := make(map[string]string, len(configHTTPHeaders))
+	for _, header := range configHTTPHeaders {
+		name, value, ok := strings.Cut(header, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return validationErrorf("invalid header %q (expected Name=Value)", header)
+		}
+		headers[strings.TrimSpace(name)] = value
+	}
+
+	err := session.UpdateGlobalHTTPAgentWithOptions(GetConfigOptions(), func(httpAgent *session.HTTPAgentConfig) {
+		if cmd.Flags().Changed("endpoint") {
+			httpAgent.Endpoint = configHTTPEndpoint
+		}
+		if cmd.Flags().Changed("format") {
+			httpAgent.Format = configHTTPFormat
+		}
+		if cmd.Flags().Changed("model") {
+			httpAgent.Model = configHTTPModel
+		}
+		if cmd.Flags().Changed("max-tokens") {
+			httpAgent.MaxTokens = configHTTPMaxTokens
+		}
+		for name, value := range headers {
+			if value == "" {
+				delete(httpAgent.Headers, name)
+				continue
+			}
+			if httpAgent.Headers == nil {
+				httpAgent.Headers = make(map[string]string)
+			}
+			httpAgent.Headers[name] = value
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save http provider settings: %w", err)
+	}
+
+	fmt.Println("Updated http provider settings.")
+	return nil
+}
+
+func runConfigHTTPClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalHTTPAgentWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear http provider settings: %w", err)
+	}
+
+	fmt.Println("Cleared http provider settings.")
+	return nil
+}
+
+// Shell agent command variables
+var (
+	configShellCommand string
+	configShellSignals string
+)
+
+// configShellCmd is the parent command for the shell provider's settings
+var configShellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Manage the command run by the shell agent provider (global)",
+	Long: `Manage the command run by the "shell" agent provider.
+
+The shell provider runs any command as the agent, for tools juggle has no
+dedicated provider for. The command runs with sh -c in the project directory
+and gets the prompt on stdin. Run options are passed as environment variables:
+
+  JUGGLE_MODEL          Model the iteration picked (opus, sonnet, haiku, or --model)
+  JUGGLE_PERMISSION     acceptEdits, plan, or bypassPermissions
+  JUGGLE_SYSTEM_PROMPT  Extra system prompt (set in headless mode)
+  JUGGLE_MODE           headless or interactive
+
+Signals:
+  promise     Read <promise> signals from the output, like other providers (default)
+  exit_code   Read the outcome from the exit code: 0 COMPLETE, 3 CONTINUE,
+              2 BLOCKED (the last output line is the reason), anything else fails
+
+This is a global setting stored in ~/.juggle/config.json. Select the provider
+with 'juggle config provider set shell' or 'juggle agent run --provider shell'.
+
+Examples:
+  juggle config shell set --command 'aider --yes --message "$(cat)"'
+  juggle config shell set --command ./scripts/agent.sh --signals exit_code`,
+	RunE: runConfigShellShow,
+}
+
+var configShellShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the shell provider's settings",
+	RunE:  runConfigShellShow,
+}
+
+var configShellSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the shell provider's settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigShellSet,
+}
+
+var configShellClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the shell provider's settings",
+	RunE:  runConfigShellClear,
+}
+
+func init() {
+	configShellSetCmd.Flags().StringVar(&configShellCommand, "command", "", "Command to run; it gets the prompt on stdin")
+	configShellSetCmd.Flags().StringVar(&configShellSignals, "signals", "", "How the outcome is read: promise or exit_code")
+
+	configShellCmd.AddCommand(configShellShowCmd)
+	configShellCmd.AddCommand(configShellSetCmd)
+	configShellCmd.AddCommand(configShellClearCmd)
+
+	configCmd.AddCommand(configShellCmd)
+}
+
+func runConfigShellShow(cmd *cobra.Command, args []string) error {
+	shellAgent, err := session.GetGlobalShellAgentWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load shell provider settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	command := shellAgent.Command
+	if command == "" {
+		command = dimStyle.Render("(not set)")
+	}
+	signals := shellAgent.Signals
+	if signals == "" {
+		signals = dimStyle.Render("(default)")
+	}
+
+	fmt.Println(labelStyle.Render("Shell Provider Settings:"))
+	fmt.Println()
+	fmt.Printf("  command: %s\n", command)
+	fmt.Printf("  signals: %s\n", signals)
+	return nil
+}
+
+func runConfigShellSet(cmd *cobra.Command, args []string) error {
+	err := session.UpdateGlobalShellAgentWithOptions(GetConfigOptions(), func(shellAgent *session.ShellAgentConfig) {
+		if cmd.Flags().Changed("command") {
+			shellAgent.Command = configShellCommand
+		}
+		if cmd.Flags().Changed("signals") {
+			shellAgent.Signals = configShellSignals
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save shell provider settings: %w", err)
+	}
+
+	fmt.Println("Updated shell provider settings.")
+	return nil
+}
+
+func runConfigShellClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalShellAgentWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear shell provider settings: %w", err)
+	}
+
+	fmt.Println("Cleared shell provider settings.")
+	return nil
+}
+
+// Ollama agent command variables
+var (
+	configOllamaEndpoint string
+	configOllamaModel    string
+	configOllamaModels   []string
+)
+
+// configOllamaCmd is the parent command for the ollama provider's settings
+var configOllamaCmd = &cobra.Command{
+	Use:   "ollama",
+	Short: "Manage the server and models used by the ollama agent provider (global)",
+	Long: `Manage the settings for the "ollama" agent provider.
+
+The ollama provider runs local models through an Ollama server's
+OpenAI-compatible API. Like the http provider it has no tools, so the model
+can only answer with text.
+
+Model sizes the agent loop picks (small, medium, large, or haiku, sonnet,
+opus) map to the model set for that size, else to the default model. Any
+other --model value is passed to Ollama as is.
+
+This is a global setting stored in ~/.juggle/config.json. Select the provider
+with 'juggle config provider set ollama', or route one model size to it with
+'juggle config provider set ollama --for small'.
+
+Examples:
+  juggle config ollama set --model llama3.1
+  juggle config ollama set --size large=qwen2.5-coder:32b
+  juggle config ollama set --size large=        # Remove a size's model
+  juggle config ollama set --endpoint http://gpu-box:11434/v1/chat/completions`,
+	RunE: runConfigOllamaShow,
+}
+
+var configOllamaShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the ollama provider's settings",
+	RunE:  runConfigOllamaShow,
+}
+
+var configOllamaSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the ollama provider's settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigOllamaSet,
+}
+
+var configOllamaClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the ollama provider's settings",
+	RunE:  runConfigOllamaClear,
+}
+
+func init() {
+	configOllamaSetCmd.Flags().StringVar(&configOllamaEndpoint, "endpoint", "", "Chat completions URL")
+	configOllamaSetCmd.Flags().StringVar(&configOllamaModel, "model", "", "Model used for sizes without their own")
+	configOllamaSetCmd.Flags().StringArrayVar(&configOllamaModels, "size", nil, "Model for a size as size=model (repeatable, empty model removes it)")
+
+	configOllamaCmd.AddCommand(configOllamaShowCmd)
+	configOllamaCmd.AddCommand(configOllamaSetCmd)
+	configOllamaCmd.AddCommand(configOllamaClearCmd)
+
+	configCmd.AddCommand(configOllamaCmd)
+}
+
+func runConfigOllamaShow(cmd *cobra.Command, args []string) error {
+	ollamaAgent, err := session.GetGlobalOllamaAgentWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load ollama provider settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	orDefault := func(value string) string {
+		if value == "" {
+			return dimStyle.Render("(default)")
+		}
+		return value
+	}
+
+	fmt.Println(labelStyle.Render("Ollama Provider Settings:"))
+	fmt.Println()
+	fmt.Printf("  endpoint: %s\n", orDefault(ollamaAgent.Endpoint))
+	fmt.Printf("  model:    %s\n", orDefault(ollamaAgent.Model))
+	for _, size := range []string{"small", "medium", "large"} {
+		if model := ollamaAgent.Models[size]; model != "" {
+			fmt.Printf("  %-8s  %s\n", size+":", model)
+		}
+	}
+	return nil
+}
+
+func runConfigOllamaSet(cmd *cobra.Command, args []string) error {
+	models := make(map[string]string, len(configOllamaModels))
+	for _, entry := range configOllamaModels {
+		size, model, ok := strings.Cut(entry, "=")
+		size = strings.ToLower(strings.TrimSpace(size))
+		if !ok || session.ModelProviderSize(size) == "" {
+			return validationErrorf("invalid size %q (expected small=model, medium=model, or large=model)", entry)
+		}
+		models[session.ModelProviderSize(size)] = strings.TrimSpace(model)
+	}
+
+	err := session.UpdateGlobalOllamaAgentWithOptions(GetConfigOptions(), func(ollamaAgent *session.OllamaAgentConfig) {
+		if cmd.Flags().Changed("endpoint") {
+			ollamaAgent.Endpoint = configOllamaEndpoint
+		}
+		if cmd.Flags().Changed("model") {
+			ollamaAgent.Model = configOllamaModel
+		}
+		for size, model := range models {
+			if model == "" {
+				delete(ollamaAgent.Models, size)
+				continue
+			}
+			if ollamaAgent.Models == nil {
+				ollamaAgent.Models = make(map[string]string)
+			}
+			ollamaAgent.Models[size] = model
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save ollama provider settings: %w", err)
+	}
+
+	fmt.Println("Updated ollama provider settings.")
+	return nil
+}
+
+func runConfigOllamaClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalOllamaAgentWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear ollama provider settings: %w", err)
+	}
+
+	fmt.Println("Cleared ollama provider settings.")
+	return nil
+}
+
+// Signal detection command variables
+var (
+	configSignalsStrict bool
+	configSignalsTags   []string
+)
+
+// configSignalsCmd is the parent command for promise signal detection settings
+var configSignalsCmd = &cobra.Command{
+	Use:   "signals",
+	Short: "Manage how agent promise signals are detected (global)",
+	Long: `Manage how COMPLETE/CONTINUE/BLOCKED promise signals are detected in agent output.
+
+By default detection is tolerant: <promise> tags are matched case-insensitively,
+escaped tags and markdown around them (code fences, bold, inline code) are
+ignored, BLOCKED may omit its reason, and a JSON form is accepted:
+
+  {"promise": "COMPLETE", "message": "feat: add login"}
+  {"promise": "BLOCKED", "reason": "needs an API key"}
+
+Strict mode only accepts the exact <promise>KEYWORD</promise> form. Extra tags
+are accepted alongside <promise>, for prompts that use a different name.
+
+This is a global setting stored in ~/.juggle/config.json. Use
+'juggle agent parse-check <file>' to see what a saved output parses to.
+
+Commands:
+  config signals show                 Show the signal detection settings
+  config signals set [flags]          Update the signal detection settings
+  config signals clear                Restore tolerant detection of <promise> only
+
+Examples:
+  juggle config signals set --strict
+  juggle config signals set --strict=false --tag signal`,
+	RunE: runConfigSignalsShow,
+}
+
+var configSignalsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the signal detection settings",
+	RunE:  runConfigSignalsShow,
+}
+
+var configSignalsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the signal detection settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigSignalsSet,
+}
+
+var configSignalsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Restore the default signal detection settings",
+	RunE:  runConfigSignalsClear,
+}
+
+func init() {
+	configSignalsSetCmd.Flags().BoolVar(&configSignalsStrict, "strict", false, "Only accept the exact <promise>KEYWORD</promise> form")
+	configSignalsSetCmd.Flags().StringSliceVar(&configSignalsTags, "tag", nil, "Extra tag names accepted alongside promise (replaces the list, empty clears it)")
+
+	configSignalsCmd.AddCommand(configSignalsShowCmd)
+	configSignalsCmd.AddCommand(configSignalsSetCmd)
+	configSignalsCmd.AddCommand(configSignalsClearCmd)
+
+	configCmd.AddCommand(configSignalsCmd)
+}
+
+func runConfigSignalsShow(cmd *cobra.Command, args []string) error {
+	signals, err := session.GetGlobalAgentSignalsWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load signal settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	mode := "tolerant " + dimStyle.Render("(default)")
+	if signals.Strict {
+		mode = "strict"
+	}
+	tags := provider.DefaultSignalTag
+	if len(signals.Tags) > 0 {
+		tags += ", " + strings.Join(signals.Tags, ", ")
+	}
+
+	fmt.Println(labelStyle.Render("Agent Signal Detection:"))
+	fmt.Println()
+	fmt.Printf("  mode: %s\n", mode)
+	fmt.Printf("  tags: %s\n", tags)
+	return nil
+}
+
+func runConfigSignalsSet(cmd *cobra.Command, args []string) error {
+	if !cmd.Flags().Changed("strict") && !cmd.Flags().Changed("tag") {
+		return usageErrorf("nothing to set (use --strict or --tag)")
+	}
+
+	err := session.UpdateGlobalAgentSignalsWithOptions(GetConfigOptions(), func(signals *session.AgentSignalsConfig) {
+		if cmd.Flags().Changed("strict") {
+			signals.Strict = configSignalsStrict
+		}
+		if cmd.Flags().Changed("tag") {
+			signals.Tags = nil
+			for _, tag := range configSignalsTags {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					signals.Tags = append(signals.Tags, tag)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save signal settings: %w", err)
+	}
+
+	fmt.Println("Updated signal detection settings.")
+	return nil
+}
+
+func runConfigSignalsClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalAgentSignalsWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear signal settings: %w", err)
+	}
+
+	fmt.Println("Cleared signal detection settings (tolerant, <promise> only).")
+	return nil
+}
+
+// Attention command variables
+var (
+	configAttentionBell   bool
+	configAttentionTmux   bool
+	configAttentionTitle  bool
+	configAttentionAlways bool
+	configAttentionEvents []string
+)
+
+// configAttentionCmd is the parent command for TUI attention signal settings
+var configAttentionCmd = &cobra.Command{
+	Use:   "attention",
+	Short: "Manage how the TUI gets your attention (global)",
+	Long: `Manage the signals the TUI sends when it needs you while its terminal is
+unfocused: an agent run finished, an agent run blocked or failed, or a prompt
+(such as the duplicate merge review) is waiting for an answer.
+
+Signals:
+  bell   Ring the terminal bell
+  tmux   Ring the bell so tmux flags the window, and set the window's
+         @juggle_attention option (show it with #{@juggle_attention})
+  title  Show the event in the terminal title
+
+Focus tracking needs a terminal that reports focus changes. Use --always to
+signal even while the TUI is focused. Signals are cleared when you return.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Commands:
+  config attention show               Show the attention settings
+  config attention set [flags]        Update the attention settings
+  config attention clear              Turn attention signals off
+
+Examples:
+  juggle config attention set --bell --title
+  juggle config attention set --tmux --events blocked,confirm`,
+	RunE: runConfigAttentionShow,
+}
+
+var configAttentionShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the attention settings",
+	RunE:  runConfigAttentionShow,
+}
+
+var configAttentionSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the attention settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigAttentionSet,
+}
+
+var configAttentionClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Turn attention signals off",
+	RunE:  runConfigAttentionClear,
+}
+
+func init() {
+	configAttentionSetCmd.Flags().BoolVar(&configAttentionBell, "bell", false, "Ring the terminal bell")
+	configAttentionSetCmd.Flags().BoolVar(&configAttentionTmux, "tmux", false, "Flag the tmux window and set @juggle_attention")
+	configAttentionSetCmd.Flags().BoolVar(&configAttentionTitle, "title", false, "Show the event in the terminal title")
+	configAttentionSetCmd.Flags().BoolVar(&configAttentionAlways, "always", false, "Signal even while the TUI is focused")
+	configAttentionSetCmd.Flags().StringSliceVar(&configAttentionEvents, "events", nil, "Events to signal: finished, blocked, confirm (empty for all)")
+
+	configAttentionCmd.AddCommand(configAttentionShowCmd)
+	configAttentionCmd.AddCommand(configAttentionSetCmd)
+	configAttentionCmd.AddCommand(configAttentionClearCmd)
+
+	configCmd.AddCommand(configAttentionCmd)
+}
+
+func runConfigAttentionShow(cmd *cobra.Command, args []string) error {
+	attention, err := session.GetGlobalAttentionWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load attention settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	fmt.Println(labelStyle.Render("Attention Signals:"))
+	fmt.Println()
+	if !attention.Enabled() {
+		fmt.Println(dimStyle.Render("  (off)"))
+		return nil
+	}
+
+	var signals []string
+	if attention.Bell {
+		signals = append(signals, "bell")
+	}
+	if attention.Tmux {
+		signals = append(signals, "tmux")
+	}
+	if attention.Title {
+		signals = append(signals, "title")
+	}
+	events := strings.Join(session.AttentionEvents, ", ") + " " + dimStyle.Render("(default)")
+	if len(attention.Events) > 0 {
+		events = strings.Join(attention.Events, ", ")
+	}
+	when := "while unfocused"
+	if attention.Always {
+		when = "always"
+	}
+
+	fmt.Printf("  signals: %s\n", strings.Join(signals, ", "))
+	fmt.Printf("  events:  %s\n", events)
+	fmt.Printf("  when:    %s\n", when)
+	return nil
+}
+
+func runConfigAttentionSet(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("bell") && !flags.Changed("tmux") && !flags.Changed("title") &&
+		!flags.Changed("always") && !flags.Changed("events") {
+		return usageErrorf("nothing to set (use --bell, --tmux, --title, --always, or --events)")
+	}
+
+	err := session.UpdateGlobalAttentionWithOptions(GetConfigOptions(), func(attention *session.AttentionConfig) {
+		if flags.Changed("bell") {
+			attention.Bell = configAttentionBell
+		}
+		if flags.Changed("tmux") {
+			attention.Tmux = configAttentionTmux
 		}
-		fmt.Println("Cleared project provider setting.")
-	} else {
-		if err := session.ClearGlobalAgentProviderWithOptions(GetConfigOptions()); err != nil {
-			return fmt.Errorf("failed to clear global provider: %w", err)
+		if flags.Changed("title") {
+			attention.Title = configAttentionTitle
 		}
-		fmt.Println("Cleared global provider setting.")
+		if flags.Changed("always") {
+			attention.Always = configAttentionAlways
+		}
+		if flags.Changed("events") {
+			attention.Events = nil
+			for _, event := range configAttentionEvents {
+				if event = strings.ToLower(strings.TrimSpace(event)); event != "" {
+					attention.Events = append(attention.Events, event)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save attention settings: %w", err)
+	}
+
+	fmt.Println("Updated attention settings.")
+	return nil
+}
+
+func runConfigAttentionClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalAttentionWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear attention settings: %w", err)
+	}
+
+	fmt.Println("Cleared attention settings (signals off).")
+	return nil
+}
+
+// Key sequence command variables
+var (
+	configKeysTimeout  time.Duration
+	configKeysWhichKey bool
+)
+
+// configKeysCmd is the parent command for TUI two-key sequence settings
+var configKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the TUI's two-key sequences (global)",
+	Long: `Manage how the TUI handles two-key sequences such as s+c (complete) and
+t+b (toggle blocked balls).
+
+After the first key, the TUI waits for the second; if it doesn't come within
+the timeout (1.5s by default), the sequence is cancelled so a stray key
+doesn't leave the TUI waiting. With --which-key, the bottom pane lists the
+second keys and what they do while a sequence is pending.
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Commands:
+  config keys show                   Show the key sequence settings
+  config keys set [flags]            Update the key sequence settings
+  config keys clear                  Restore the defaults
+
+Examples:
+  juggle config keys set --timeout 3s
+  juggle config keys set --which-key`,
+	RunE: runConfigKeysShow,
+}
+
+var configKeysShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the key sequence settings",
+	RunE:  runConfigKeysShow,
+}
+
+var configKeysSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the key sequence settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigKeysSet,
+}
+
+var configKeysClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Restore the default key sequence settings",
+	RunE:  runConfigKeysClear,
+}
+
+func init() {
+	configKeysSetCmd.Flags().DurationVar(&configKeysTimeout, "timeout", 0, "How long to wait for the second key (e.g. 800ms, 3s; 0 for the default)")
+	configKeysSetCmd.Flags().BoolVar(&configKeysWhichKey, "which-key", false, "List the second keys in the bottom pane while a sequence is pending")
+
+	configKeysCmd.AddCommand(configKeysShowCmd)
+	configKeysCmd.AddCommand(configKeysSetCmd)
+	configKeysCmd.AddCommand(configKeysClearCmd)
+
+	configCmd.AddCommand(configKeysCmd)
+}
+
+func runConfigKeysShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load key sequence settings: %w", err)
+	}
+	keys := config.GetKeySequences()
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	timeout := keys.Timeout().String()
+	if keys.TimeoutMs == 0 {
+		timeout += " " + dimStyle.Render("(default)")
+	}
+	whichKey := "off"
+	if keys.WhichKey {
+		whichKey = "on"
+	}
+
+	fmt.Println(labelStyle.Render("Key Sequences:"))
+	fmt.Println()
+	fmt.Printf("  timeout:   %s\n", timeout)
+	fmt.Printf("  which-key: %s\n", whichKey)
+	return nil
+}
+
+func runConfigKeysSet(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("timeout") && !flags.Changed("which-key") {
+		return usageErrorf("nothing to set (use --timeout or --which-key)")
+	}
+	if flags.Changed("timeout") && configKeysTimeout != 0 && configKeysTimeout < time.Millisecond {
+		return validationErrorf("invalid timeout %s (must be at least 1ms)", configKeysTimeout)
+	}
+
+	err := session.UpdateGlobalKeySequencesWithOptions(GetConfigOptions(), func(keys *session.KeySequenceConfig) {
+		if flags.Changed("timeout") {
+			keys.TimeoutMs = int(configKeysTimeout / time.Millisecond)
+		}
+		if flags.Changed("which-key") {
+			keys.WhichKey = configKeysWhichKey
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save key sequence settings: %w", err)
+	}
+
+	fmt.Println("Updated key sequence settings.")
+	return nil
+}
+
+func runConfigKeysClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalKeySequencesWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear key sequence settings: %w", err)
+	}
+
+	fmt.Println("Cleared key sequence settings (using defaults).")
+	return nil
+}
+
+// Agent log command variables
+var (
+	configLogsMaxSize   int
+	configLogsMaxFiles  int
+	configLogsRetention int
+)
+
+// configLogsCmd is the parent command for agent output log settings
+var configLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Manage agent output log rotation and retention (global)",
+	Long: `Manage the logs agent output is streamed to. Every agent run writes its
+stdout and stderr to a timestamped file under .juggle/agent-logs/ as it runs;
+the history output view (H, then Enter) reads from these files.
+
+Settings:
+  max-size   Size in MB a log reaches before it's rotated (default 10)
+  max-files  Rotated parts kept per run; older parts are dropped (default 5)
+  retention  Days logs are kept before being removed (default 30)
+
+This is a global setting stored in ~/.juggle/config.json.
+
+Commands:
+  config logs show               Show the log settings
+  config logs set [flags]        Update the log settings
+  config logs clear              Reset the log settings to defaults
+
+Examples:
+  juggle config logs set --max-size 50 --max-files 2
+  juggle config logs set --retention 7`,
+	RunE: runConfigLogsShow,
+}
+
+var configLogsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the log settings",
+	RunE:  runConfigLogsShow,
+}
+
+var configLogsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update the log settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigLogsSet,
+}
+
+var configLogsClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Reset the log settings to defaults",
+	RunE:  runConfigLogsClear,
+}
+
+func init() {
+	configLogsSetCmd.Flags().IntVar(&configLogsMaxSize, "max-size", 0, "Size in MB before a log is rotated (0 for default)")
+	configLogsSetCmd.Flags().IntVar(&configLogsMaxFiles, "max-files", 0, "Rotated parts kept per run (0 for default)")
+	configLogsSetCmd.Flags().IntVar(&configLogsRetention, "retention", 0, "Days logs are kept (0 for default)")
+
+	configLogsCmd.AddCommand(configLogsShowCmd)
+	configLogsCmd.AddCommand(configLogsSetCmd)
+	configLogsCmd.AddCommand(configLogsClearCmd)
+
+	configCmd.AddCommand(configLogsCmd)
+}
+
+func runConfigLogsShow(cmd *cobra.Command, args []string) error {
+	logs, err := session.GetGlobalAgentLogsWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load log settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	setting := func(value, def int, unit string) string {
+		if value == 0 {
+			return fmt.Sprintf("%d%s %s", def, unit, dimStyle.Render("(default)"))
+		}
+		return fmt.Sprintf("%d%s", value, unit)
+	}
+
+	fmt.Println(labelStyle.Render("Agent Logs:"))
+	fmt.Println()
+	fmt.Printf("  max size:  %s\n", setting(logs.MaxSizeMB, agentlog.DefaultMaxSize/(1024*1024), "MB"))
+	fmt.Printf("  max files: %s\n", setting(logs.MaxFiles, agentlog.DefaultMaxFiles, ""))
+	fmt.Printf("  retention: %s\n", setting(logs.RetentionDays, int(agentlog.DefaultRetention/(24*time.Hour)), " days"))
+	return nil
+}
+
+func runConfigLogsSet(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if !flags.Changed("max-size") && !flags.Changed("max-files") && !flags.Changed("retention") {
+		return usageErrorf("nothing to set (use --max-size, --max-files, or --retention)")
+	}
+
+	err := session.UpdateGlobalAgentLogsWithOptions(GetConfigOptions(), func(logs *session.AgentLogsConfig) {
+		if flags.Changed("max-size") {
+			logs.MaxSizeMB = configLogsMaxSize
+		}
+		if flags.Changed("max-files") {
+			logs.MaxFiles = configLogsMaxFiles
+		}
+		if flags.Changed("retention") {
+			logs.RetentionDays = configLogsRetention
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save log settings: %w", err)
+	}
+
+	fmt.Println("Updated agent log settings.")
+	return nil
+}
+
+func runConfigLogsClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalAgentLogsWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear log settings: %w", err)
+	}
+
+	fmt.Println("Cleared agent log settings (using defaults).")
+	return nil
+}
+
+var (
+	configScrubRules    []string
+	configScrubPatterns []string
+)
+
+// configScrubCmd is the parent command for secret scrubbing settings
+var configScrubCmd = &cobra.Command{
+	Use:   "scrub",
+	Short: "Manage redaction of secrets from agent prompts (global)",
+	Long: `Manage redaction of secrets from agent prompts and saved agent output.
+
+When enabled, matches are replaced with [REDACTED:<rule>] in ball text,
+session context, and progress before they reach the agent, and in the agent
+output saved to last_output.txt and the run history. Each run reports how
+many redactions each rule made (never the redacted text).
+
+Built-in rules:
+  api_keys   Anthropic/OpenAI, GitHub, AWS, Slack, and Google keys, private
+             keys, and values of api_key/token/secret/password assignments
+  emails     Email addresses
+
+Scrubbing is off by default. This is a global setting stored in
+~/.juggle/config.json.
+
+Commands:
+  config scrub show                   Show the scrub settings
+  config scrub set [flags]            Enable scrubbing and update its settings
+  config scrub clear                  Turn scrubbing off
+
+Examples:
+  juggle config scrub set                        # All built-in rules
+  juggle config scrub set --rule api_keys        # Keys only, keep emails
+  juggle config scrub set --pattern 'ACME-[0-9]{6}'`,
+	RunE: runConfigScrubShow,
+}
+
+var configScrubShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the scrub settings",
+	RunE:  runConfigScrubShow,
+}
+
+var configScrubSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Enable scrubbing and update its settings",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigScrubSet,
+}
+
+var configScrubClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Turn scrubbing off",
+	RunE:  runConfigScrubClear,
+}
+
+func init() {
+	configScrubSetCmd.Flags().StringSliceVar(&configScrubRules, "rule", nil, "Built-in rules to apply: api_keys, emails (replaces the list, empty means all)")
+	configScrubSetCmd.Flags().StringArrayVar(&configScrubPatterns, "pattern", nil, "Extra regex to redact (repeatable, replaces the list)")
+
+	configScrubCmd.AddCommand(configScrubShowCmd)
+	configScrubCmd.AddCommand(configScrubSetCmd)
+	configScrubCmd.AddCommand(configScrubClearCmd)
+
+	configCmd.AddCommand(configScrubCmd)
+}
+
+func runConfigScrubShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load scrub settings: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	fmt.Println(labelStyle.Render("Secret Scrubbing:"))
+	fmt.Println()
+	if config.Scrub == nil {
+		fmt.Printf("  status: off %s\n", dimStyle.Render("(default)"))
+		return nil
+	}
+	rules := strings.Join(config.Scrub.Rules, ", ")
+	if rules == "" {
+		rules = strings.Join(session.ScrubRules(), ", ") + " " + dimStyle.Render("(all)")
+	}
+	fmt.Println("  status: on")
+	fmt.Printf("  rules: %s\n", rules)
+	for _, pattern := range config.Scrub.Patterns {
+		fmt.Printf("  pattern: %s\n", pattern)
+	}
+	return nil
+}
+
+func runConfigScrubSet(cmd *cobra.Command, args []string) error {
+	err := session.UpdateGlobalScrubWithOptions(GetConfigOptions(), func(scrub *session.ScrubConfig) {
+		if cmd.Flags().Changed("rule") {
+			scrub.Rules = nil
+			for _, rule := range configScrubRules {
+				if rule = strings.TrimSpace(rule); rule != "" {
+					scrub.Rules = append(scrub.Rules, rule)
+				}
+			}
+		}
+		if cmd.Flags().Changed("pattern") {
+			scrub.Patterns = nil
+			for _, pattern := range configScrubPatterns {
+				if pattern != "" {
+					scrub.Patterns = append(scrub.Patterns, pattern)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return validationErrorf("failed to save scrub settings: %w", err)
+	}
+
+	fmt.Println("Updated scrub settings (scrubbing is on).")
+	return nil
+}
+
+func runConfigScrubClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalScrubWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear scrub settings: %w", err)
+	}
+
+	fmt.Println("Cleared scrub settings (scrubbing is off).")
+	return nil
+}
+
+// configSortCmd is the parent command for custom sort orders
+var configSortCmd = &cobra.Command{
+	Use:   "sort",
+	Short: "Manage custom ball sort orders (global)",
+	Long: `Manage custom sort orders for balls.
+
+A sort order is a name plus a comma-separated list of "field [asc|desc]"
+terms. Later terms break ties between earlier ones, and fields sort
+ascending unless "desc" is given. Custom sort orders follow the built-in
+ones in the TUI sort cycle ('o') and can be passed by name to
+'juggle board --sort'.
+
+Fields: id, title, state, priority, activity, created, age, updates, size, deps
+
+Commands:
+  config sort show                  Show the custom sort orders
+  config sort add <name> <expr>     Add or replace a custom sort order
+  config sort remove <name>         Remove a custom sort order
+  config sort clear                 Remove all custom sort orders
+
+Examples:
+  juggle config sort add triage "priority desc, age desc"
+  juggle config sort add stale "activity, priority desc"
+  juggle config sort remove stale`,
+	RunE: runConfigSortShow,
+}
+
+var configSortShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the custom sort orders",
+	RunE:  runConfigSortShow,
+}
+
+var configSortAddCmd = &cobra.Command{
+	Use:   "add <name> <expr>",
+	Short: "Add or replace a custom sort order",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSortAdd,
+}
+
+var configSortRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a custom sort order",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigSortRemove,
+}
+
+var configSortClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all custom sort orders",
+	RunE:  runConfigSortClear,
+}
+
+func init() {
+	configSortCmd.AddCommand(configSortShowCmd)
+	configSortCmd.AddCommand(configSortAddCmd)
+	configSortCmd.AddCommand(configSortRemoveCmd)
+	configSortCmd.AddCommand(configSortClearCmd)
+
+	configCmd.AddCommand(configSortCmd)
+}
+
+func runConfigSortShow(cmd *cobra.Command, args []string) error {
+	config, err := session.LoadConfigWithOptions(GetConfigOptions())
+	if err != nil {
+		return fmt.Errorf("failed to load sort orders: %w", err)
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	fmt.Println(labelStyle.Render("Custom Sort Orders:"))
+	fmt.Println()
+	if len(config.SortOrders) == 0 {
+		fmt.Println("  (none - only the built-in sort orders are used)")
+		return nil
+	}
+	for _, order := range config.SortOrders {
+		fmt.Printf("  %-16s %s\n", order.Name, order.Expr)
+	}
+	return nil
+}
+
+func runConfigSortAdd(cmd *cobra.Command, args []string) error {
+	if _, err := tui.ParseSortOrder(args[0]); err == nil {
+		return validationErrorf("%q is a built-in sort order; choose another name", args[0])
+	}
+	if err := session.UpdateGlobalSortOrderWithOptions(GetConfigOptions(), args[0], args[1]); err != nil {
+		return validationErrorf("failed to save sort order: %w", err)
+	}
+
+	fmt.Printf("Set sort order %s: %s\n", args[0], args[1])
+	return nil
+}
+
+func runConfigSortRemove(cmd *cobra.Command, args []string) error {
+	if err := session.RemoveGlobalSortOrderWithOptions(GetConfigOptions(), args[0]); err != nil {
+		return notFoundErrorf("failed to remove sort order: %w", err)
+	}
+
+	fmt.Printf("Removed sort order %s\n", args[0])
+	return nil
+}
+
+func runConfigSortClear(cmd *cobra.Command, args []string) error {
+	if err := session.ClearGlobalSortOrdersWithOptions(GetConfigOptions()); err != nil {
+		return fmt.Errorf("failed to clear sort orders: %w", err)
 	}
 
+	fmt.Println("Cleared custom sort orders.")
 	return nil
 }
 
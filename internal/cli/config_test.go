@@ -859,3 +859,64 @@ func TestConfigProviderClear_Project(t *testing.T) {
 		t.Errorf("expected empty provider after clear, got '%s'", provider)
 	}
 }
+
+// TestCalculateScheduledWaitTime_PrefersQuotaReset tests that a configured reset replaces backoff
+func TestCalculateScheduledWaitTime_PrefersQuotaReset(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 50, 0, 0, time.UTC)
+
+	wait := CalculateScheduledWaitTimeForTest(now, 0, 0, []string{"00:00 UTC"})
+	if expected := 10*time.Minute + 5*time.Second; wait != expected {
+		t.Errorf("expected %v, got %v", expected, wait)
+	}
+
+	// Explicit retry-after still wins over the reset time
+	wait = CalculateScheduledWaitTimeForTest(now, time.Minute, 0, []string{"00:00 UTC"})
+	if expected := time.Minute + 5*time.Second; wait != expected {
+		t.Errorf("expected %v, got %v", expected, wait)
+	}
+
+	// Without a reset configured, falls back to exponential backoff
+	wait = CalculateScheduledWaitTimeForTest(now, 0, 1, nil)
+	if expected := time.Minute; wait != expected {
+		t.Errorf("expected %v, got %v", expected, wait)
+	}
+}
+
+// TestConfigSchedule_SetAndClear tests setting and clearing the agent schedule
+func TestConfigSchedule_SetAndClear(t *testing.T) {
+	tmpDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	if err := runConfigScheduleReset(configScheduleResetCmd, []string{"00:00 UTC"}); err != nil {
+		t.Fatalf("failed to set reset times: %v", err)
+	}
+	if err := runConfigScheduleQuiet(configScheduleQuietCmd, []string{"22:00-06:00"}); err != nil {
+		t.Fatalf("failed to set quiet hours: %v", err)
+	}
+	if err := runConfigScheduleQuiet(configScheduleQuietCmd, []string{"nine-to-five"}); err == nil {
+		t.Error("expected error for invalid quiet hours")
+	}
+	if err := runConfigScheduleShow(configScheduleShowCmd, []string{}); err != nil {
+		t.Errorf("expected no error showing schedule, got: %v", err)
+	}
+
+	opts := session.ConfigOptions{
+		ConfigHome:    tmpDir,
+		JuggleDirName: ".juggle",
+	}
+	resets, quiet, err := session.GetGlobalScheduleWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to get schedule: %v", err)
+	}
+	if len(resets) != 1 || len(quiet) != 1 || quiet[0] != "22:00-06:00" {
+		t.Errorf("unexpected schedule: resets=%v quiet=%v", resets, quiet)
+	}
+
+	if err := runConfigScheduleClear(configScheduleClearCmd, []string{}); err != nil {
+		t.Fatalf("failed to clear schedule: %v", err)
+	}
+	resets, quiet, _ = session.GetGlobalScheduleWithOptions(opts)
+	if len(resets) != 0 || len(quiet) != 0 {
+		t.Errorf("expected schedule cleared, got resets=%v quiet=%v", resets, quiet)
+	}
+}
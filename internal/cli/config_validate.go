@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check every layer of configuration for mistakes",
+	Long: `Check the global config, the project config and each session's settings
+for unknown keys, values of the wrong type or out of range, and referenced
+directories and commands that don't exist.
+
+Each problem is reported as file:line: field: message, with a hint on how to
+fix it where there is one. Errors are settings juggle rejects or misreads;
+warnings are settings it ignores (such as a misspelled key) or that point at
+something missing. Settings that only break together are checked across
+layers, such as a session picking the shell provider when no shell command
+is configured.
+
+Exits with code 4 if there are errors, so it can gate CI. With --json, the
+issues are printed as a JSON array.
+
+Examples:
+  juggle config validate
+  juggle config validate --json | jq '.[] | select(.warning | not)'`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	files, err := configValidateFiles()
+	if err != nil {
+		return err
+	}
+	issues := session.ValidateConfig(files)
+
+	errorCount := 0
+	for _, issue := range issues {
+		if !issue.Warning {
+			errorCount++
+		}
+	}
+
+	if GlobalOpts.JSONOutput {
+		if issues == nil {
+			issues = []session.ConfigIssue{}
+		}
+		data, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range issues {
+			label := StyleBlocked.Render("error:  ")
+			if issue.Warning {
+				label = StyleMedium.Render("warning:")
+			}
+			fmt.Printf("%s %s\n", label, issue)
+			if issue.Hint != "" {
+				fmt.Printf("          %s\n", StyleDim.Render(issue.Hint))
+			}
+		}
+		if len(issues) == 0 {
+			fmt.Println("✓ Configuration is valid")
+		} else {
+			fmt.Printf("\n%d error(s), %d warning(s)\n", errorCount, len(issues)-errorCount)
+		}
+	}
+
+	if errorCount > 0 {
+		err := validationErrorf("configuration has %d error(s)", errorCount)
+		if GlobalOpts.JSONOutput {
+			// The issues are the output; only the exit code is left to report
+			cliErr := ClassifyError(err)
+			cliErr.reported = true
+			return cliErr
+		}
+		return err
+	}
+	return nil
+}
+
+// configValidateFiles lists the config files that apply in the current
+// directory. Outside a project only the global config is checked.
+func configValidateFiles() (session.ConfigFiles, error) {
+	opts := GetConfigOptions()
+	files := session.ConfigFiles{
+		Global: filepath.Join(opts.ConfigHome, opts.JuggleDirName, "config.json"),
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return files, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	juggleDir := GetStoreConfig().JuggleDirName
+	projectDir, err := session.ResolveStorageDir(cwd, juggleDir)
+	if err != nil {
+		projectDir = cwd
+	}
+	files.ProjectDir = projectDir
+	files.Project = filepath.Join(projectDir, juggleDir, "config.json")
+	files.Sessions, _ = filepath.Glob(filepath.Join(projectDir, juggleDir, "sessions", "*", "session.json"))
+	return files, nil
+}
@@ -0,0 +1,628 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonInterval      time.Duration
+	daemonSessionIDs    []string
+	daemonSchedules     []string
+	daemonLimits        []string
+	daemonMaxConcurrent int
+	daemonIterations    int
+	daemonProvider      string
+	daemonModel         string
+	daemonListen        string
+	daemonOnce          bool
+)
+
+var agentDaemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Stay resident and launch agent runs when sessions have work",
+	Long: `Stay resident, watch the project's sessions, and launch agent runs for them.
+
+Every --interval the daemon checks each session for workable (pending or
+in-progress) balls. A session without a schedule gets a run as soon as a
+ball becomes workable that no run has seen yet; balls a run leaves behind
+wait until they're changed (e.g. blocked and unblocked) or new work arrives.
+A session with a schedule gets a run each time the schedule fires, if it has
+workable balls. Schedules are cron expressions or intervals, as in 'juggle
+recur'.
+
+Each session gets at most --max-concurrent runs at once (override with
+--limit). With a limit of 1 a run works on the whole session; above 1, each
+run works on one ball. Runs are 'juggle agent run' processes; their output is
+written to .juggle/daemon/. Hooks (see 'juggle config hooks') fire as usual.
+
+Paused and archived sessions (see 'juggle sessions pause' and 'juggle
+sessions archive') are skipped, even when named with --session.
+
+What the daemon is doing is saved to .juggle/daemon.json: see it with
+'juggle agent daemon status', or serve it over HTTP with --listen.
+
+Examples:
+  juggle agent daemon                                  # Run every session as work appears
+  juggle agent daemon --session auth --session docs    # Only watch these sessions
+  juggle agent daemon --schedule "nightly=0 2 * * *"   # Run nightly at 02:00 only
+  juggle agent daemon --schedule reports=6h --limit backlog=3
+  juggle agent daemon --listen 127.0.0.1:7070          # Serve status at /status
+  juggle agent daemon --once                           # Launch what's due, wait, and exit`,
+	Args: cobra.NoArgs,
+	RunE: runAgentDaemon,
+}
+
+var agentDaemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what the agent daemon is doing",
+	Args:  cobra.NoArgs,
+	RunE:  runAgentDaemonStatus,
+}
+
+func init() {
+	agentDaemonCmd.Flags().DurationVar(&daemonInterval, "interval", time.Minute, "How often to check sessions for work")
+	agentDaemonCmd.Flags().StringArrayVar(&daemonSessionIDs, "session", nil, "Session to watch (repeatable; default: every session)")
+	agentDaemonCmd.Flags().StringArrayVar(&daemonSchedules, "schedule", nil, "Run a session on a schedule: session=cron or session=interval (repeatable)")
+	agentDaemonCmd.Flags().StringArrayVar(&daemonLimits, "limit", nil, "Most runs at once for a session: session=N (repeatable)")
+	agentDaemonCmd.Flags().IntVar(&daemonMaxConcurrent, "max-concurrent", 1, "Most runs at once per session")
+	agentDaemonCmd.Flags().IntVarP(&daemonIterations, "iterations", "n", 10, "Maximum iterations per run")
+	agentDaemonCmd.Flags().StringVar(&daemonProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
+	agentDaemonCmd.Flags().StringVarP(&daemonModel, "model", "m", "", "Model to use (opus, sonnet, haiku). Default: per ball")
+	agentDaemonCmd.Flags().StringVar(&daemonListen, "listen", "", "Serve the daemon's status as JSON on this address (e.g. 127.0.0.1:7070)")
+	agentDaemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "Launch the runs that are due, wait for them, and exit")
+
+	agentDaemonCmd.AddCommand(agentDaemonStatusCmd)
+	agentCmd.AddCommand(agentDaemonCmd)
+}
+
+// daemonCommand builds the process for one agent run; tests replace it
+var daemonCommand = func(projectDir string, args []string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the juggle executable: %w", err)
+	}
+	command := exec.Command(self, args...)
+	command.Dir = projectDir
+	return command, nil
+}
+
+// SetDaemonCommandForTest replaces how the daemon starts agent runs and
+// returns a function that restores it
+func SetDaemonCommandForTest(fn func(projectDir string, args []string) (*exec.Cmd, error)) func() {
+	previous := daemonCommand
+	daemonCommand = fn
+	return func() { daemonCommand = previous }
+}
+
+// daemonOptions configures an agent daemon
+type daemonOptions struct {
+	Interval      time.Duration
+	SessionIDs    []string          // Sessions to watch; empty watches every session
+	Schedules     map[string]string // Session ID → cron expression or interval
+	Limits        map[string]int    // Session ID → most runs at once
+	MaxConcurrent int               // Limit for sessions not in Limits
+	Iterations    int
+	Provider      string
+	Model         string
+	Listen        string
+	Once          bool // Launch what's due, wait for it, and exit
+}
+
+// daemonExit reports that one of the daemon's runs ended
+type daemonExit struct {
+	session  string
+	pid      int
+	exitCode int
+	err      error
+}
+
+// agentDaemon launches and tracks agent runs for a project's sessions
+type agentDaemon struct {
+	projectDir string
+	store      *session.Store
+	opts       daemonOptions
+
+	mu     sync.Mutex // Guards status, which the HTTP endpoint reads
+	status *session.DaemonStatus
+
+	procs map[int]*exec.Cmd
+	exits chan daemonExit
+}
+
+func runAgentDaemon(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	schedules, err := parseDaemonAssignments("--schedule", daemonSchedules)
+	if err != nil {
+		return err
+	}
+	limitValues, err := parseDaemonAssignments("--limit", daemonLimits)
+	if err != nil {
+		return err
+	}
+	limits := make(map[string]int)
+	for sessionID, value := range limitValues {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return validationErrorf("invalid --limit for %s: %q (must be 1 or more)", sessionID, value)
+		}
+		limits[sessionID] = limit
+	}
+
+	return runDaemon(cwd, daemonOptions{
+		Interval:      daemonInterval,
+		SessionIDs:    daemonSessionIDs,
+		Schedules:     schedules,
+		Limits:        limits,
+		MaxConcurrent: daemonMaxConcurrent,
+		Iterations:    daemonIterations,
+		Provider:      daemonProvider,
+		Model:         daemonModel,
+		Listen:        daemonListen,
+		Once:          daemonOnce,
+	})
+}
+
+// RunAgentDaemonOnceForTest runs the daemon with --once and returns the
+// status it saved
+func RunAgentDaemonOnceForTest(projectDir string, limits map[string]int, maxConcurrent int) (*session.DaemonStatus, error) {
+	opts := daemonOptions{Interval: time.Minute, Limits: limits, MaxConcurrent: maxConcurrent, Iterations: 1, Once: true}
+	if err := runDaemon(projectDir, opts); err != nil {
+		return nil, err
+	}
+	store, err := session.NewStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadDaemonStatus()
+}
+
+// runDaemon runs the agent daemon for the project until it's stopped (or,
+// with Once, until the runs it launched first have ended)
+func runDaemon(projectDir string, opts daemonOptions) error {
+	if opts.Interval < time.Second {
+		return validationErrorf("--interval must be at least 1s")
+	}
+	if opts.MaxConcurrent < 1 {
+		return validationErrorf("--max-concurrent must be 1 or more")
+	}
+	if opts.Iterations < 1 {
+		return validationErrorf("--iterations must be 1 or more")
+	}
+	for sessionID, schedule := range opts.Schedules {
+		if _, err := session.NewDaemonSessionStatus(sessionID, schedule, 1, time.Now()); err != nil {
+			return validationErrorf("%v", err)
+		}
+	}
+
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	if previous, err := store.LoadDaemonStatus(); err == nil && previous != nil && previous.Running() && previous.PID != os.Getpid() {
+		return &CLIError{
+			Code:    CodeLockHeld,
+			Message: fmt.Sprintf("an agent daemon is already running in this project (pid %d on %s)", previous.PID, previous.Hostname),
+			Hint:    "see what it's doing with: juggle agent daemon status",
+		}
+	}
+	if len(opts.SessionIDs) > 0 {
+		sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+		if err != nil {
+			return fmt.Errorf("failed to initialize session store: %w", err)
+		}
+		for _, sessionID := range opts.SessionIDs {
+			if sessionID == "all" {
+				continue
+			}
+			if _, err := sessionStore.LoadSession(sessionID); err != nil {
+				return session.NewSessionNotFoundError(sessionID)
+			}
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	d := &agentDaemon{
+		projectDir: store.ProjectDir(),
+		store:      store,
+		opts:       opts,
+		status: &session.DaemonStatus{
+			PID:       os.Getpid(),
+			Hostname:  hostname,
+			StartedAt: time.Now(),
+			Interval:  opts.Interval.String(),
+			Sessions:  []*session.DaemonSessionStatus{},
+		},
+		procs: make(map[int]*exec.Cmd),
+		exits: make(chan daemonExit),
+	}
+
+	if opts.Listen != "" {
+		listener, err := net.Listen("tcp", opts.Listen)
+		if err != nil {
+			return validationErrorf("failed to listen on %s: %v", opts.Listen, err)
+		}
+		d.status.Listen = listener.Addr().String()
+		server := &http.Server{Handler: d.statusHandler()}
+		go server.Serve(listener)
+		defer server.Close()
+		fmt.Printf("Serving status at http://%s/status\n", d.status.Listen)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	fmt.Printf("Agent daemon watching %s (every %s)\n", d.projectDir, opts.Interval)
+	if err := d.pass(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for !(opts.Once && len(d.procs) == 0) {
+		select {
+		case exit := <-d.exits:
+			d.finished(exit)
+			if !opts.Once {
+				d.passOrWarn()
+			}
+		case <-ticker.C:
+			if !opts.Once {
+				d.passOrWarn()
+			}
+		case sig := <-signals:
+			fmt.Printf("Stopping: waiting for %d run(s) to finish\n", len(d.procs))
+			for _, proc := range d.procs {
+				_ = proc.Process.Signal(sig)
+			}
+			for len(d.procs) > 0 {
+				d.finished(<-d.exits)
+			}
+			d.stop()
+			return nil
+		}
+	}
+	d.stop()
+	return nil
+}
+
+// parseDaemonAssignments parses repeated session=value flags
+func parseDaemonAssignments(flag string, values []string) (map[string]string, error) {
+	assignments := make(map[string]string)
+	for _, value := range values {
+		sessionID, setting, ok := strings.Cut(value, "=")
+		sessionID, setting = strings.TrimSpace(sessionID), strings.TrimSpace(setting)
+		if !ok || sessionID == "" || setting == "" {
+			return nil, validationErrorf("invalid %s %q (use session=value)", flag, value)
+		}
+		assignments[sessionID] = setting
+	}
+	return assignments, nil
+}
+
+// pass checks every watched session for work and launches the runs that are due
+func (d *agentDaemon) pass() error {
+	sessionIDs, err := d.watchedSessions()
+	if err != nil {
+		return err
+	}
+	balls, err := d.store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+
+	// Forget sessions that were deleted, once their runs are over
+	kept := make([]*session.DaemonSessionStatus, 0, len(d.status.Sessions))
+	for _, status := range d.status.Sessions {
+		if containsID(sessionIDs, status.Session) || len(status.Running) > 0 {
+			kept = append(kept, status)
+		}
+	}
+	d.status.Sessions = kept
+
+	for _, sessionID := range sessionIDs {
+		status := d.status.Session(sessionID)
+		if status == nil {
+			limit := d.opts.MaxConcurrent
+			if l, ok := d.opts.Limits[sessionID]; ok {
+				limit = l
+			}
+			status, err = session.NewDaemonSessionStatus(sessionID, d.opts.Schedules[sessionID], limit, now)
+			if err != nil {
+				return validationErrorf("%v", err)
+			}
+			d.status.Sessions = append(d.status.Sessions, status)
+		}
+		status.SetWorkable(session.DaemonWorkableBalls(balls, sessionID))
+		for _, ballID := range status.Plan(now) {
+			if err := d.launch(status, ballID, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to start agent run for %s: %v\n", sessionID, err)
+			}
+		}
+	}
+	sort.Slice(d.status.Sessions, func(i, j int) bool {
+		return d.status.Sessions[i].Session < d.status.Sessions[j].Session
+	})
+	d.saveLocked()
+	return nil
+}
+
+// passOrWarn runs a pass, warning instead of stopping the daemon on errors
+func (d *agentDaemon) passOrWarn() {
+	if err := d.pass(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// watchedSessions returns the sessions to watch: those given with
+// --session, or every session in the project
+func (d *agentDaemon) watchedSessions() ([]string, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(d.projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if len(d.opts.SessionIDs) > 0 {
+		// Paused and archived sessions aren't scheduled, even when named
+		ids := make([]string, 0, len(d.opts.SessionIDs))
+		for _, id := range d.opts.SessionIDs {
+			if sess, err := sessionStore.LoadSession(id); err == nil && !sess.Schedulable() {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	ids := make([]string, 0, len(sessions))
+	for _, s := range sessions {
+		if s.Schedulable() {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids, nil
+}
+
+// launch starts an agent run for the session, or for one of its balls
+func (d *agentDaemon) launch(status *session.DaemonSessionStatus, ballID string, now time.Time) error {
+	args := agentRunArgs(status.Session, ballID, d.opts.Iterations, d.opts.Provider, d.opts.Model)
+
+	if err := os.MkdirAll(d.store.DaemonLogDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	name := sessionStorageID(status.Session) + "-" + now.Format("20060102-150405")
+	if ballID != "" {
+		name += "-" + ballID
+	}
+	logPath := filepath.Join(d.store.DaemonLogDir(), name+".log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+
+	proc, err := daemonCommand(d.projectDir, args)
+	if err != nil {
+		logFile.Close()
+		return err
+	}
+	proc.Stdout = logFile
+	proc.Stderr = logFile
+	if err := proc.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+
+	pid := proc.Process.Pid
+	d.procs[pid] = proc
+	status.Started(&session.DaemonRun{PID: pid, BallID: ballID, StartedAt: now, LogFile: logPath})
+	target := status.Session
+	if ballID != "" {
+		target += " (" + ballID + ")"
+	}
+	fmt.Printf("%s ▶ started agent run for %s (pid %d), log: %s\n", now.Format("15:04:05"), target, pid, logPath)
+
+	sessionID := status.Session
+	go func() {
+		err := proc.Wait()
+		logFile.Close()
+		exit := daemonExit{session: sessionID, pid: pid}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exit.exitCode = exitErr.ExitCode()
+		} else if err != nil {
+			exit.exitCode = -1
+			exit.err = err
+		}
+		d.exits <- exit
+	}()
+	return nil
+}
+
+// agentRunArgs returns the arguments of a 'juggle agent run' process for
+// the session, or for one of its balls
+func agentRunArgs(sessionID, ballID string, iterations int, provider, model string) []string {
+	args := []string{"agent", "run", sessionID, "--iterations", strconv.Itoa(iterations)}
+	if ballID != "" {
+		args = append(args, "--ball", ballID)
+	}
+	if provider != "" {
+		args = append(args, "--provider", provider)
+	}
+	if model != "" {
+		args = append(args, "--model", model)
+	}
+	if GlobalOpts.ConfigHome != "" {
+		args = append(args, "--config-home", GlobalOpts.ConfigHome)
+	}
+	if GlobalOpts.JuggleDir != "" {
+		args = append(args, "--juggle-dir", GlobalOpts.JuggleDir)
+	}
+	return args
+}
+
+// finished records that a run ended
+func (d *agentDaemon) finished(exit daemonExit) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.procs, exit.pid)
+	if status := d.status.Session(exit.session); status != nil {
+		status.Finished(exit.pid, time.Now(), exit.exitCode, exit.err)
+	}
+	fmt.Printf("%s ■ agent run for %s (pid %d) exited with %d\n", time.Now().Format("15:04:05"), exit.session, exit.pid, exit.exitCode)
+	d.saveLocked()
+}
+
+// stop records that the daemon exited cleanly
+func (d *agentDaemon) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status.Stopped = true
+	d.saveLocked()
+}
+
+// saveLocked saves the status file; d.mu must be held
+func (d *agentDaemon) saveLocked() {
+	d.status.UpdatedAt = time.Now()
+	if err := d.store.SaveDaemonStatus(d.status); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// statusHandler serves the daemon's status as JSON
+func (d *agentDaemon) statusHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		data, err := json.MarshalIndent(d.status, "", "  ")
+		d.mu.Unlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+	return mux
+}
+
+// containsID returns true if ids contains id
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func runAgentDaemonStatus(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	status, err := store.LoadDaemonStatus()
+	if err != nil {
+		return err
+	}
+
+	if GlobalOpts.JSONOutput {
+		var output any = struct {
+			Running bool `json:"running"`
+		}{false}
+		if status != nil {
+			output = struct {
+				Running bool `json:"running"`
+				*session.DaemonStatus
+			}{status.Running(), status}
+		}
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if status == nil {
+		fmt.Println("No agent daemon has run in this project.")
+		fmt.Println("\nStart one with: juggle agent daemon")
+		return nil
+	}
+
+	switch {
+	case status.Running():
+		fmt.Printf("Agent daemon: running (pid %d on %s) since %s, checking every %s\n",
+			status.PID, status.Hostname, status.StartedAt.Format("2006-01-02 15:04"), status.Interval)
+		if status.Listen != "" {
+			fmt.Printf("Status endpoint: http://%s/status\n", status.Listen)
+		}
+	case status.Stopped:
+		fmt.Printf("Agent daemon: stopped at %s\n", status.UpdatedAt.Format("2006-01-02 15:04"))
+	default:
+		fmt.Printf("Agent daemon: not running (pid %d exited without stopping, last seen %s)\n",
+			status.PID, status.UpdatedAt.Format("2006-01-02 15:04"))
+	}
+	if len(status.Sessions) == 0 {
+		fmt.Println("\nNo sessions watched.")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(StyleHeader.Render(padRight("SESSION", 20) + padRight("SCHEDULE", 18) + padRight("WORKABLE", 10) +
+		padRight("RUNNING", 10) + padRight("NEXT RUN", 18) + "LAST RUN"))
+	for _, s := range status.Sessions {
+		schedule := "on new work"
+		if s.Schedule != "" {
+			schedule = s.Schedule
+		}
+		nextRun := "-"
+		if s.NextRun != nil {
+			nextRun = s.NextRun.Local().Format("Mon Jan 2 15:04")
+		}
+		lastRun := "-"
+		if s.LastRun != nil && s.LastRun.EndedAt != nil {
+			lastRun = fmt.Sprintf("exit %d at %s", s.LastRun.ExitCode, s.LastRun.EndedAt.Local().Format("Jan 2 15:04"))
+		}
+		fmt.Printf("%s%s%s%s%s%s\n", padRight(s.Session, 20), padRight(schedule, 18), padRight(strconv.Itoa(len(s.Workable)), 10),
+			padRight(fmt.Sprintf("%d/%d", len(s.Running), s.Limit), 10), padRight(nextRun, 18), lastRun)
+		for _, run := range s.Running {
+			target := "whole session"
+			if run.BallID != "" {
+				target = run.BallID
+			}
+			fmt.Printf("  pid %d: %s since %s, log: %s\n", run.PID, target, run.StartedAt.Local().Format("15:04"), run.LogFile)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// DuplicateBall reports a ball created during an agent run that looks like
+// a near-duplicate of another ball.
+type DuplicateBall struct {
+	BallID        string  `json:"ball_id"`
+	Title         string  `json:"title"`
+	DuplicateOf   string  `json:"duplicate_of"`
+	OriginalTitle string  `json:"original_title"`
+	Similarity    float64 `json:"similarity"`
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <keep-id> <duplicate-id>",
+	Short: "Merge a duplicate ball into another ball",
+	Long: `Fold a duplicate ball into the ball you want to keep, then delete the duplicate.
+
+Acceptance criteria, tags, dependencies, and watch globs missing from the kept
+ball are copied over, and balls that depended on the duplicate are updated to
+depend on the kept ball instead. Both balls must be in the same project.
+
+Agent runs report balls they created that look like duplicates; this is how
+to act on that report from the command line.
+
+Examples:
+  juggle merge my-app-3 my-app-9`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMerge,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	keep, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	dup, _, err := findBallByID(args[1])
+	if err != nil {
+		return err
+	}
+	if dup.ID == keep.ID {
+		return validationErrorf("cannot merge ball %s into itself", keep.ShortID())
+	}
+	if dup.WorkingDir != keep.WorkingDir {
+		return validationErrorf("balls %s and %s are in different projects", keep.ShortID(), dup.ShortID())
+	}
+
+	if err := store.MergeDuplicateBall(keep, dup); err != nil {
+		return fmt.Errorf("failed to merge balls: %w", err)
+	}
+
+	fmt.Printf("✓ Merged %s into %s\n", dup.ShortID(), keep.ShortID())
+	return nil
+}
+
+// loadBallIDs returns the IDs of the balls currently in a project.
+// Errors are ignored: duplicate detection is best-effort.
+func loadBallIDs(projectDir string) map[string]bool {
+	ids := make(map[string]bool)
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return ids
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return ids
+	}
+	for _, ball := range balls {
+		ids[ball.ID] = true
+	}
+	return ids
+}
+
+// detectDuplicateBalls finds the balls created during an agent run, from the
+// IDs the agent reported and from balls missing from the pre-run snapshot,
+// and cross-checks their titles against every ball in the project.
+func detectDuplicateBalls(projectDir string, knownIDs map[string]bool, reportedIDs []string) ([]string, []DuplicateBall) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, nil
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check for duplicate balls: %v\n", err)
+		return nil, nil
+	}
+
+	isCreated := make(map[string]bool)
+	for _, ball := range balls {
+		if !knownIDs[ball.ID] {
+			isCreated[ball.ID] = true
+		}
+	}
+	// Reported IDs may be short IDs or prefixes
+	for _, id := range reportedIDs {
+		if matches := session.ResolveBallByPrefix(balls, id); len(matches) == 1 {
+			isCreated[matches[0].ID] = true
+		}
+	}
+
+	var created []*session.Ball
+	var createdIDs []string
+	for _, ball := range balls {
+		if isCreated[ball.ID] {
+			created = append(created, ball)
+			createdIDs = append(createdIDs, ball.ID)
+		}
+	}
+
+	var duplicates []DuplicateBall
+	for _, c := range session.FindNearDuplicates(created, balls, session.DefaultDuplicateThreshold) {
+		duplicates = append(duplicates, DuplicateBall{
+			BallID:        c.Ball.ID,
+			Title:         c.Ball.Title,
+			DuplicateOf:   c.Original.ID,
+			OriginalTitle: c.Original.Title,
+			Similarity:    c.Similarity,
+		})
+	}
+	return createdIDs, duplicates
+}
+
+// printDuplicateBalls flags likely duplicates in the run summary
+func printDuplicateBalls(duplicates []DuplicateBall) {
+	if len(duplicates) == 0 {
+		return
+	}
+	fmt.Printf("\n⚠️  Possible duplicate balls created this run:\n")
+	for _, d := range duplicates {
+		fmt.Printf("  %s %q ≈ %s %q (%.0f%% similar)\n", d.BallID, d.Title, d.DuplicateOf, d.OriginalTitle, d.Similarity*100)
+	}
+	fmt.Printf("Merge with: juggle merge <keep-id> <duplicate-id>\n")
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestDetectDuplicateBalls(t *testing.T) {
+	tmpDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	store, err := NewStoreForCommand(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	existing := &session.Ball{ID: "proj-1", Title: "Add login page", StartedAt: time.Now().Add(-time.Hour)}
+	if err := store.AppendBall(existing); err != nil {
+		t.Fatalf("failed to append ball: %v", err)
+	}
+	known := loadBallIDs(tmpDir)
+
+	// Balls created during the run
+	for _, b := range []*session.Ball{
+		{ID: "proj-2", Title: "Add the login page", StartedAt: time.Now()},
+		{ID: "proj-3", Title: "Fix billing export", StartedAt: time.Now()},
+	} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("failed to append ball: %v", err)
+		}
+	}
+
+	created, duplicates := detectDuplicateBalls(tmpDir, known, []string{"proj-2"})
+	if len(created) != 2 {
+		t.Errorf("expected 2 created balls, got %v", created)
+	}
+	if len(duplicates) != 1 || duplicates[0].BallID != "proj-2" || duplicates[0].DuplicateOf != "proj-1" {
+		t.Errorf("expected proj-2 to be flagged as a duplicate of proj-1, got %+v", duplicates)
+	}
+}
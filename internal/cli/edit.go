@@ -78,7 +78,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	if editPriority != "" {
 		if !session.ValidatePriority(editPriority) {
-			return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", editPriority)
+			return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", editPriority)
 		}
 		foundBall.Priority = session.Priority(editPriority)
 		modified = true
@@ -87,7 +87,7 @@ func runEdit(cmd *cobra.Command, args []string) error {
 
 	if editState != "" {
 		if !session.ValidateBallState(editState) {
-			return fmt.Errorf("invalid state: %s (must be pending|in_progress|blocked|complete)", editState)
+			return validationErrorf("invalid state: %s (must be pending|in_progress|blocked|complete)", editState)
 		}
 		if err := foundBall.SetState(session.BallState(editState)); err != nil {
 			return err
@@ -139,7 +139,7 @@ func runInteractiveEdit(ball *session.Ball, store *session.Store) error {
 	input = strings.TrimSpace(input)
 	if input != "" {
 		if !session.ValidatePriority(input) {
-			return fmt.Errorf("invalid priority: %s", input)
+			return validationErrorf("invalid priority: %s", input)
 		}
 		ball.Priority = session.Priority(input)
 	}
@@ -150,7 +150,7 @@ func runInteractiveEdit(ball *session.Ball, store *session.Store) error {
 	input = strings.TrimSpace(input)
 	if input != "" {
 		if !session.ValidateBallState(input) {
-			return fmt.Errorf("invalid state: %s", input)
+			return validationErrorf("invalid state: %s", input)
 		}
 		if err := ball.SetState(session.BallState(input)); err != nil {
 			return err
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes returned by juggle. These are part of the CLI's interface:
+// wrapper scripts and CI can branch on them, so never renumber existing codes.
+const (
+	ExitOK          = 0
+	ExitError       = 1 // Unclassified failure
+	ExitUsage       = 2 // Unknown command or flag, bad arguments
+	ExitNotFound    = 3 // Ball, session, or project not found
+	ExitValidation  = 4 // Invalid value, ambiguous ID, or refused state change
+	ExitLockHeld    = 5 // Session or ball is locked by another process
+	ExitRateLimited = 6 // Agent gave up after rate limits exceeded --max-wait
+	ExitBrownout    = 7 // Session paused after repeated agent errors
+)
+
+// Error codes reported in --json error output, one per exit code
+const (
+	CodeError       = "error"
+	CodeUsage       = "usage"
+	CodeNotFound    = "not_found"
+	CodeValidation  = "validation"
+	CodeLockHeld    = "lock_held"
+	CodeRateLimited = "rate_limited"
+	CodeBrownout    = "brownout"
+)
+
+var exitCodes = map[string]int{
+	CodeError:       ExitError,
+	CodeUsage:       ExitUsage,
+	CodeNotFound:    ExitNotFound,
+	CodeValidation:  ExitValidation,
+	CodeLockHeld:    ExitLockHeld,
+	CodeRateLimited: ExitRateLimited,
+	CodeBrownout:    ExitBrownout,
+}
+
+// CLIError is an error with a stable machine-readable code and an optional
+// hint telling the user how to fix it.
+type CLIError struct {
+	Code    string
+	Message string
+	Hint    string
+	Err     error // Underlying error, if any
+
+	json     bool // Report as JSON (the command was run with --json)
+	reported bool // Already printed; only the exit code is still needed
+}
+
+func (e *CLIError) Error() string {
+	return e.Message
+}
+
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for this error
+func (e *CLIError) ExitCode() int {
+	if code, ok := exitCodes[e.Code]; ok {
+		return code
+	}
+	return ExitError
+}
+
+// newCLIError wraps err with a code and hint
+func newCLIError(code string, err error, hint string) *CLIError {
+	return &CLIError{Code: code, Message: err.Error(), Hint: hint, Err: err}
+}
+
+// notFoundErrorf returns a not-found error; %w is supported as in fmt.Errorf
+func notFoundErrorf(format string, args ...any) error {
+	return newCLIError(CodeNotFound, fmt.Errorf(format, args...), "")
+}
+
+// validationErrorf returns a validation error; %w is supported as in fmt.Errorf
+func validationErrorf(format string, args ...any) error {
+	return newCLIError(CodeValidation, fmt.Errorf(format, args...), "")
+}
+
+// usageErrorf returns a usage error; %w is supported as in fmt.Errorf
+func usageErrorf(format string, args ...any) error {
+	return newCLIError(CodeUsage, fmt.Errorf(format, args...), "")
+}
+
+// ClassifyError returns err as a CLIError, deriving the code from the
+// session package's typed errors when err isn't already a CLIError.
+func ClassifyError(err error) *CLIError {
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return cliErr
+	}
+
+	var ambiguous *session.AmbiguousIDError
+	switch {
+	case errors.Is(err, session.ErrBallNotFound), errors.Is(err, session.ErrSessionNotFound):
+		return newCLIError(CodeNotFound, err, "")
+	case errors.As(err, &ambiguous):
+		return newCLIError(CodeValidation, err, "use a longer ID prefix")
+	case errors.Is(err, session.ErrInvalidState), errors.Is(err, session.ErrTestsFailing):
+		return newCLIError(CodeValidation, err, "")
+	case errors.Is(err, session.ErrSessionLocked), errors.Is(err, session.ErrBallLocked):
+		// Lock errors carry their hint on the following line
+		message, hint, _ := strings.Cut(err.Error(), "\n")
+		return &CLIError{Code: CodeLockHeld, Message: message, Hint: hint, Err: err}
+	}
+	return newCLIError(CodeError, err, "")
+}
+
+// commandWantsJSON reports whether cmd was run with --json
+func commandWantsJSON(cmd *cobra.Command) bool {
+	if GlobalOpts.JSONOutput {
+		return true
+	}
+	if cmd == nil {
+		return false
+	}
+	flag := cmd.Flags().Lookup("json")
+	return flag != nil && flag.Changed
+}
+
+var classifyCobraErrorsOnce sync.Once
+
+// classifyCobraErrors makes cobra's own flag and argument errors usage errors.
+// Run lazily since commands are registered by init functions across the package.
+func classifyCobraErrors() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return newCLIError(CodeUsage, err, fmt.Sprintf("run '%s --help' for usage", cmd.CommandPath()))
+	})
+
+	var wrap func(cmd *cobra.Command)
+	wrap = func(cmd *cobra.Command) {
+		if args := cmd.Args; args != nil {
+			cmd.Args = func(c *cobra.Command, a []string) error {
+				if err := args(c, a); err != nil {
+					return newCLIError(CodeUsage, err, fmt.Sprintf("run '%s --help' for usage", c.CommandPath()))
+				}
+				return nil
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			wrap(sub)
+		}
+	}
+	wrap(rootCmd)
+}
+
+// executeRoot runs the root command and records on the returned error
+// whether it should be reported as JSON.
+func executeRoot() error {
+	classifyCobraErrorsOnce.Do(classifyCobraErrors)
+
+	cmd, err := rootCmd.ExecuteC()
+	waitForHooks()
+	stopProfiling()
+	if err == nil {
+		return nil
+	}
+	cliErr := ClassifyError(err)
+	cliErr.json = commandWantsJSON(cmd)
+	return cliErr
+}
+
+// jsonError is the machine-readable error format
+type jsonError struct {
+	Error string `json:"error"` // Human-readable message
+	Code  string `json:"code"`
+	Exit  int    `json:"exit_code"`
+	Hint  string `json:"hint,omitempty"`
+}
+
+// writeJSONError writes err as a single JSON object
+func writeJSONError(w io.Writer, err *CLIError) {
+	data, _ := json.Marshal(jsonError{Error: err.Message, Code: err.Code, Exit: err.ExitCode(), Hint: err.Hint})
+	fmt.Fprintln(w, string(data))
+}
+
+// writeError writes err for humans, with its hint on a second line
+func writeError(w io.Writer, err *CLIError) {
+	fmt.Fprintf(w, "Error: %s\n", err.Message)
+	if err.Hint != "" {
+		fmt.Fprintf(w, "Hint: %s\n", err.Hint)
+	}
+}
+
+// ReportError prints an error returned by Execute and returns the exit code
+// to use. With --json the error is printed to stdout as JSON; otherwise it
+// goes to stderr.
+func ReportError(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	cliErr := ClassifyError(err)
+	switch {
+	case cliErr.reported:
+	case cliErr.json:
+		writeJSONError(os.Stdout, cliErr)
+	default:
+		writeError(os.Stderr, cliErr)
+	}
+	return cliErr.ExitCode()
+}
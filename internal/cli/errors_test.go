@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantExit int
+		wantMsg  string
+		wantHint string
+	}{
+		{
+			name:     "plain error",
+			err:      errors.New("boom"),
+			wantCode: CodeError,
+			wantExit: ExitError,
+			wantMsg:  "boom",
+		},
+		{
+			name:     "wrapped ball not found",
+			err:      fmt.Errorf("failed to load ball: %w", session.NewBallNotFoundError("abc")),
+			wantCode: CodeNotFound,
+			wantExit: ExitNotFound,
+			wantMsg:  "failed to load ball: ball abc not found",
+		},
+		{
+			name:     "session not found",
+			err:      session.NewSessionNotFoundError("feature-x"),
+			wantCode: CodeNotFound,
+			wantExit: ExitNotFound,
+			wantMsg:  "session not found: feature-x",
+		},
+		{
+			name:     "ambiguous ID",
+			err:      session.NewAmbiguousIDError("ab", []string{"ab1", "ab2"}),
+			wantCode: CodeValidation,
+			wantExit: ExitValidation,
+			wantHint: "use a longer ID prefix",
+		},
+		{
+			name:     "invalid state transition",
+			err:      session.NewInvalidStateTransitionError("complete", "blocked"),
+			wantCode: CodeValidation,
+			wantExit: ExitValidation,
+		},
+		{
+			name:     "session locked",
+			err:      session.NewSessionLockedError("feature-x", nil),
+			wantCode: CodeLockHeld,
+			wantExit: ExitLockHeld,
+			wantMsg:  "session feature-x is already locked by another agent",
+			wantHint: "Use --ignore-lock to bypass (use with caution)",
+		},
+		{
+			name:     "ball locked",
+			err:      session.NewBallLockedError("app-1", nil),
+			wantCode: CodeLockHeld,
+			wantExit: ExitLockHeld,
+		},
+		{
+			name:     "validation helper keeps wrapped error",
+			err:      validationErrorf("invalid priority: %s", "x"),
+			wantCode: CodeValidation,
+			wantExit: ExitValidation,
+			wantMsg:  "invalid priority: x",
+		},
+		{
+			name:     "usage helper",
+			err:      usageErrorf("unknown operation: %s", "frob"),
+			wantCode: CodeUsage,
+			wantExit: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			if got.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", got.Code, tt.wantCode)
+			}
+			if got.ExitCode() != tt.wantExit {
+				t.Errorf("ExitCode() = %d, want %d", got.ExitCode(), tt.wantExit)
+			}
+			if tt.wantMsg != "" && got.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMsg)
+			}
+			if tt.wantHint != "" && got.Hint != tt.wantHint {
+				t.Errorf("Hint = %q, want %q", got.Hint, tt.wantHint)
+			}
+			if !errors.Is(got, tt.err) && got != tt.err {
+				t.Error("classified error should wrap the original")
+			}
+		})
+	}
+}
+
+func TestEnhanceBallNotFoundErrorKeepsCode(t *testing.T) {
+	err := notFoundErrorf("ball not found in current project: %s", "myball")
+	got := ClassifyError(enhanceBallNotFoundError(err, "myball", []string{"myball", "extra"}))
+	if got.Code != CodeNotFound {
+		t.Errorf("Code = %q, want %q", got.Code, CodeNotFound)
+	}
+
+	got = ClassifyError(enhanceBallNotFoundError(err, "agent", []string{"agent"}))
+	if got.Code != CodeUsage {
+		t.Errorf("command name used as ball ID: Code = %q, want %q", got.Code, CodeUsage)
+	}
+}
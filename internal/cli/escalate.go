@@ -0,0 +1,408 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// Limits on what an escalation package includes, so it stays readable and
+// fits in a GitHub issue body
+const (
+	escalationMaxAttempts  = 10
+	escalationMaxDiffLines = 300
+)
+
+var (
+	escalateOwner   string
+	escalateGitHub  string
+	escalateNotify  bool
+	escalateOutput  string
+	escalateResolve bool
+	escalateNote    string
+)
+
+var escalateCmd = &cobra.Command{
+	Use:   "escalate <ball-id>",
+	Short: "Hand a blocked ball to a human owner",
+	Long: `Package a blocked ball for a human: its context, acceptance criteria and
+blocked reason, the agent runs that tried it, progress notes that mention it,
+and the changes made before it was blocked. The package is saved as markdown
+under .juggle/escalations and the ball is marked escalated.
+
+Agents skip escalated balls, even when targeted with --ball or run
+interactively, until the escalation is resolved. Resolve it with --resolve,
+which returns the ball to pending (a --note is appended to its context), or
+by moving the ball out of the blocked state.
+
+Sharing:
+  --github owner/repo   Create a GitHub issue from the package (uses gh)
+  --notify              Run escalation_notify_command from the global config,
+                        with the package on stdin and JUGGLE_BALL,
+                        JUGGLE_BALL_TITLE, JUGGLE_BLOCKED_REASON, JUGGLE_OWNER,
+                        JUGGLE_PROJECT, JUGGLE_ESCALATION_FILE and
+                        JUGGLE_ISSUE_URL set
+  --output <file>       Also write the package to a file ("-" for stdout)
+
+Examples:
+  juggle escalate my-app-1 --owner alice
+  juggle escalate my-app-1 --github acme/my-app --notify
+  juggle escalate my-app-1 --output - | pbcopy
+  juggle escalate my-app-1 --resolve --note "Use the v2 API key from the vault"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEscalate,
+}
+
+func init() {
+	escalateCmd.Flags().StringVar(&escalateOwner, "owner", "", "Person expected to unblock the ball")
+	escalateCmd.Flags().StringVar(&escalateGitHub, "github", "", "Create a GitHub issue in this repository (owner/repo)")
+	escalateCmd.Flags().BoolVar(&escalateNotify, "notify", false, "Run escalation_notify_command from the global config")
+	escalateCmd.Flags().StringVarP(&escalateOutput, "output", "o", "", "Also write the package to a file (\"-\" for stdout)")
+	escalateCmd.Flags().BoolVar(&escalateResolve, "resolve", false, "Resolve the escalation and return the ball to pending")
+	escalateCmd.Flags().StringVar(&escalateNote, "note", "", "Resolution to append to the ball's context (with --resolve)")
+	rootCmd.AddCommand(escalateCmd)
+}
+
+func runEscalate(cmd *cobra.Command, args []string) error {
+	if escalateResolve && (escalateOwner != "" || escalateGitHub != "" || escalateNotify || escalateOutput != "") {
+		return validationErrorf("--resolve can't be combined with --owner, --github, --notify or --output")
+	}
+	if escalateNote != "" && !escalateResolve {
+		return validationErrorf("--note can only be used with --resolve")
+	}
+	if escalateGitHub != "" {
+		if parts := strings.Split(escalateGitHub, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return validationErrorf("invalid repository format: %s (expected: owner/repo)", escalateGitHub)
+		}
+	}
+
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if escalateResolve {
+		return resolveEscalation(ball, store)
+	}
+
+	if ball.State != session.StateBlocked {
+		return validationErrorf("ball %s is %s; only blocked balls can be escalated", ball.ShortID(), ball.State)
+	}
+
+	var notifyCommand string
+	if escalateNotify {
+		config, err := session.LoadConfigWithOptions(GetConfigOptions())
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		notifyCommand = config.EscalationNotifyCommand
+		if notifyCommand == "" {
+			return validationErrorf("--notify needs escalation_notify_command set in the global config")
+		}
+	}
+
+	escalation := session.Escalation{EscalatedAt: time.Now(), Owner: escalateOwner}
+	if ball.IsEscalated() {
+		// Re-escalating refreshes the package but keeps the owner and issue
+		if escalation.Owner == "" {
+			escalation.Owner = ball.Escalation.Owner
+		}
+		escalation.IssueURL = ball.Escalation.IssueURL
+	}
+
+	markdown := BuildEscalationPackage(ball, escalation)
+
+	if escalateGitHub != "" {
+		url, err := CreateEscalationIssue(escalateGitHub, ball, markdown)
+		if err != nil {
+			return err
+		}
+		escalation.IssueURL = url
+	}
+
+	escalation.File, err = store.SaveEscalation(ball.ID, markdown)
+	if err != nil {
+		return err
+	}
+	path := store.EscalationPath(&escalation)
+
+	if notifyCommand != "" {
+		if err := notifyEscalation(notifyCommand, ball, &escalation, path, markdown); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: escalation notify command failed: %v\n", err)
+		} else {
+			escalation.Notified = true
+		}
+	}
+
+	if err := ball.Escalate(escalation); err != nil {
+		return err
+	}
+	if err := store.Save(ball); err != nil {
+		return fmt.Errorf("failed to save ball: %w", err)
+	}
+
+	if escalateOutput == "-" {
+		fmt.Print(markdown)
+		return nil
+	}
+	if escalateOutput != "" {
+		if err := os.WriteFile(escalateOutput, []byte(markdown), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", escalateOutput, err)
+		}
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(struct {
+			BallID string `json:"ball_id"`
+			Path   string `json:"path"`
+			*session.Escalation
+		}{ball.ID, path, ball.Escalation}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("✓ Escalated ball %s\n", ball.ShortID())
+	fmt.Printf("  Package: %s\n", path)
+	if escalation.Owner != "" {
+		fmt.Printf("  Owner: %s\n", escalation.Owner)
+	}
+	if escalation.IssueURL != "" {
+		fmt.Printf("  Issue: %s\n", escalation.IssueURL)
+	}
+	if escalation.Notified {
+		fmt.Println("  🔔 Notified")
+	}
+	fmt.Printf("\nAgents will skip this ball until resolved: juggle escalate %s --resolve\n", ball.ShortID())
+	return nil
+}
+
+// resolveEscalation clears a ball's escalation and returns it to pending
+func resolveEscalation(ball *session.Ball, store *session.Store) error {
+	if err := ball.ResolveEscalation(escalateNote); err != nil {
+		return validationErrorf("%v", err)
+	}
+	if err := store.Save(ball); err != nil {
+		return fmt.Errorf("failed to save ball: %w", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		return printBallJSON(ball)
+	}
+	fmt.Printf("✓ Resolved escalation: %s → pending\n", ball.ShortID())
+	return nil
+}
+
+// BuildEscalationPackage renders the markdown handed to a blocked ball's
+// owner: what the ball is, why it's blocked, what was tried, and the
+// changes made so far
+func BuildEscalationPackage(ball *session.Ball, escalation session.Escalation) string {
+	var buf strings.Builder
+
+	fmt.Fprintf(&buf, "# Blocked: %s\n\n", ball.Title)
+	fmt.Fprintf(&buf, "- **Ball:** `%s`\n", ball.ID)
+	fmt.Fprintf(&buf, "- **Project:** %s\n", ball.WorkingDir)
+	fmt.Fprintf(&buf, "- **Priority:** %s\n", ball.Priority)
+	if len(ball.Tags) > 0 {
+		fmt.Fprintf(&buf, "- **Tags:** %s\n", strings.Join(ball.Tags, ", "))
+	}
+	if escalation.Owner != "" {
+		fmt.Fprintf(&buf, "- **Owner:** %s\n", escalation.Owner)
+	}
+	fmt.Fprintf(&buf, "- **Escalated:** %s\n", escalation.EscalatedAt.Format("2006-01-02 15:04"))
+
+	buf.WriteString("\n## Why it's blocked\n\n")
+	reason := ball.BlockedReason
+	if reason == "" {
+		reason = "(no reason recorded)"
+	}
+	fmt.Fprintf(&buf, "> %s\n", strings.ReplaceAll(reason, "\n", "\n> "))
+
+	if ball.Context != "" {
+		fmt.Fprintf(&buf, "\n## Context\n\n%s\n", ball.Context)
+	}
+
+	if len(ball.AcceptanceCriteria) > 0 {
+		buf.WriteString("\n## Acceptance criteria\n\n")
+		for i, ac := range ball.AcceptanceCriteria {
+			fmt.Fprintf(&buf, "%d. %s\n", i+1, ac)
+		}
+	}
+
+	buf.WriteString("\n## Attempts\n\n")
+	runs := escalationAttempts(ball)
+	notes := escalationProgressNotes(ball)
+	if len(runs) == 0 && len(notes) == 0 {
+		buf.WriteString("No agent runs or progress notes recorded for this ball.\n")
+	}
+	for _, record := range runs {
+		line := fmt.Sprintf("- %s, session `%s`: %s after %d iteration(s)",
+			record.StartedAt.Format("2006-01-02 15:04"), record.SessionID, record.Result, record.Iterations)
+		switch {
+		case record.BlockedReason != "":
+			line += " (" + record.BlockedReason + ")"
+		case record.ErrorMessage != "":
+			line += " (" + record.ErrorMessage + ")"
+		}
+		buf.WriteString(line + "\n")
+	}
+	if len(notes) > 0 {
+		if len(runs) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Progress notes mentioning this ball:\n\n")
+		for _, note := range notes {
+			fmt.Fprintf(&buf, "- %s\n", note)
+		}
+	}
+	if len(ball.Transcripts) > 0 {
+		fmt.Fprintf(&buf, "\n%d transcript(s) attached; read them with `juggle tui` (T on the ball).\n", len(ball.Transcripts))
+	}
+
+	if diff, revisions := escalationDiff(ball); diff != "" {
+		fmt.Fprintf(&buf, "\n## Changes\n\n%s\n\n```diff\n%s\n```\n", revisions, diff)
+	}
+
+	fmt.Fprintf(&buf, "\n---\n\nWhen unblocked: `juggle escalate %s --resolve --note \"<answer>\"`\n", ball.ShortID())
+	return buf.String()
+}
+
+// escalationAttempts returns the most recent agent runs on the ball's
+// sessions, newest first
+func escalationAttempts(ball *session.Ball) []*session.AgentRunRecord {
+	historyStore, err := session.NewAgentHistoryStoreWithConfig(ball.WorkingDir, GetStoreConfig())
+	if err != nil {
+		return nil
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil {
+		return nil
+	}
+
+	attempts := make([]*session.AgentRunRecord, 0)
+	for _, record := range records {
+		for _, tag := range ball.Tags {
+			if record.SessionID == tag {
+				attempts = append(attempts, record)
+				break
+			}
+		}
+		if len(attempts) == escalationMaxAttempts {
+			break
+		}
+	}
+	return attempts
+}
+
+// escalationProgressNotes returns lines from the ball's session progress
+// logs that mention it
+func escalationProgressNotes(ball *session.Ball) []string {
+	sessionStore, err := session.NewSessionStoreWithConfig(ball.WorkingDir, GetStoreConfig())
+	if err != nil {
+		return nil
+	}
+
+	ids := []string{ball.ID}
+	// Legacy numeric short IDs are too short to search for
+	if short := ball.ShortID(); len(short) >= 6 {
+		ids = append(ids, short)
+	}
+
+	var notes []string
+	for _, tag := range ball.Tags {
+		progress, err := sessionStore.LoadProgress(tag)
+		if err != nil {
+			continue // Not a session
+		}
+		for _, line := range strings.Split(progress, "\n") {
+			line = strings.TrimSpace(line)
+			for _, id := range ids {
+				if strings.Contains(line, id) {
+					notes = append(notes, line)
+					break
+				}
+			}
+		}
+	}
+	return notes
+}
+
+// escalationDiff returns the changes between where the ball started and
+// where its work was set aside when blocked, with a line describing the
+// revisions. Returns "" if there are none or the VCS can't produce them.
+func escalationDiff(ball *session.Ball) (string, string) {
+	if ball.RevisionID == "" {
+		return "", ""
+	}
+	diff, err := getVCSBackendForBall(ball).Diff(ball.WorkingDir, ball.StartingRevision, ball.RevisionID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to diff blocked work: %v\n", err)
+		return "", ""
+	}
+	diff = strings.TrimRight(diff, "\n")
+	if diff == "" {
+		return "", ""
+	}
+
+	lines := strings.Split(diff, "\n")
+	if len(lines) > escalationMaxDiffLines {
+		diff = strings.Join(lines[:escalationMaxDiffLines], "\n") +
+			fmt.Sprintf("\n... (%d more lines)", len(lines)-escalationMaxDiffLines)
+	}
+
+	revisions := fmt.Sprintf("Blocked work is in `%s`", ball.RevisionID)
+	if ball.StartingRevision != "" {
+		revisions += fmt.Sprintf(" (started from `%s`)", ball.StartingRevision)
+	}
+	return diff, revisions + "."
+}
+
+// CreateEscalationIssue opens a GitHub issue for an escalated ball and
+// returns its URL
+func CreateEscalationIssue(repo string, ball *session.Ball, markdown string) (string, error) {
+	output, err := GhRunnerInstance.Run(
+		"issue", "create",
+		"--repo", repo,
+		"--title", "Blocked: "+ball.Title,
+		"--body", markdown,
+	)
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("gh command failed: %s", string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("gh command failed: %w (is gh CLI installed and authenticated?)", err)
+	}
+
+	// gh prints the new issue's URL last
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// notifyEscalation runs the configured command for an escalated ball, with
+// the package on stdin
+func notifyEscalation(command string, ball *session.Ball, escalation *session.Escalation, path, markdown string) error {
+	notify := exec.Command("sh", "-c", command)
+	notify.Dir = ball.WorkingDir
+	notify.Stdin = strings.NewReader(markdown)
+	notify.Env = append(os.Environ(),
+		"JUGGLE_BALL="+ball.ID,
+		"JUGGLE_BALL_TITLE="+ball.Title,
+		"JUGGLE_BLOCKED_REASON="+ball.BlockedReason,
+		"JUGGLE_OWNER="+escalation.Owner,
+		"JUGGLE_PROJECT="+ball.WorkingDir,
+		"JUGGLE_ESCALATION_FILE="+path,
+		"JUGGLE_ISSUE_URL="+escalation.IssueURL,
+	)
+	if out, err := notify.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, out)
+	}
+	return nil
+}
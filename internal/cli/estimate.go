@@ -0,0 +1,545 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateProvider   string
+	estimateModel      string
+	estimateAll        bool
+	estimateModelSize  string
+	estimateComplexity string
+	estimateIterations int
+)
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate <session|ball-id>",
+	Short: "Ask the agent to estimate balls and queue the suggestions for review",
+	Long: `Ask the configured agent (in read-only plan mode) to estimate complexity,
+model size, and iteration count for each ball in a session, or for a single ball.
+
+Suggestions are written to a review queue (.juggle/estimates.jsonl) and are not
+applied until you accept them. Accepting an estimate sets the ball's model size
+and keeps the complexity and iteration count on the ball.
+
+Use "all" to estimate every non-complete ball in the current project.
+
+Examples:
+  juggle estimate my-feature            # Estimate all balls in a session
+  juggle estimate a1b2                  # Estimate a single ball
+  juggle estimate list                  # Show the review queue
+  juggle estimate review                # Accept/adjust/reject interactively
+  juggle estimate accept a1b2           # Apply a suggestion
+  juggle estimate accept a1b2 --model-size large  # Adjust, then apply
+  juggle estimate accept a1b2 --iterations 3      # Adjust the iteration count
+  juggle estimate reject --all          # Discard every suggestion`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEstimate,
+}
+
+var estimateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List estimates waiting for review",
+	Args:  cobra.NoArgs,
+	RunE:  runEstimateList,
+}
+
+var estimateAcceptCmd = &cobra.Command{
+	Use:   "accept [ball-id...]",
+	Short: "Apply estimates to their balls and remove them from the queue",
+	RunE:  runEstimateAccept,
+}
+
+var estimateRejectCmd = &cobra.Command{
+	Use:   "reject [ball-id...]",
+	Short: "Discard estimates without applying them",
+	RunE:  runEstimateReject,
+}
+
+var estimateReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Interactively accept, adjust, or reject each estimate",
+	Args:  cobra.NoArgs,
+	RunE:  runEstimateReview,
+}
+
+// estimatePattern matches a single estimate line emitted by the agent
+var estimatePattern = regexp.MustCompile(`<estimate\s+([^>]*)>([^<]*)</estimate>`)
+
+// estimateAttrPattern matches key="value" attributes inside an estimate tag
+var estimateAttrPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+func runEstimate(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	// Configure agent provider
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	providerType := provider.Detect(estimateProvider, projectProvider, globalProvider)
+	if !provider.IsAvailable(providerType) {
+		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
+			providerType, provider.BinaryName(providerType))
+	}
+	agent.SetProvider(newAgentProvider(providerType))
+
+	// Configure model overrides
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+
+	estimates, err := estimateBalls(cwd, args[0], estimateModel)
+	if err != nil {
+		return err
+	}
+
+	if len(estimates) == 0 {
+		fmt.Println("Agent returned no estimates.")
+		return nil
+	}
+
+	printEstimates(estimates)
+	fmt.Printf("\n%d estimate(s) queued. Review with: juggle estimate review\n", len(estimates))
+	return nil
+}
+
+// estimateBalls runs the agent in read-only mode on the target balls and queues the parsed estimates
+func estimateBalls(projectDir, target, model string) ([]*session.Estimate, error) {
+	balls, err := loadBallsForEstimate(projectDir, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(balls) == 0 {
+		return nil, fmt.Errorf("no balls to estimate for %s", target)
+	}
+
+	fmt.Printf("Estimating %d ball(s)...\n", len(balls))
+
+	result, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     generateEstimatePrompt(balls),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: projectDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("agent failed: %w", err)
+	}
+	if result.RateLimited {
+		return nil, fmt.Errorf("agent was rate limited, try again later")
+	}
+
+	validIDs := make(map[string]bool, len(balls))
+	for _, ball := range balls {
+		validIDs[ball.ID] = true
+	}
+
+	estimates := parseEstimateOutput(result.Output)
+	accepted := make([]*session.Estimate, 0, len(estimates))
+	for _, estimate := range estimates {
+		if !validIDs[estimate.BallID] {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring estimate for unknown ball %s\n", estimate.BallID)
+			continue
+		}
+		accepted = append(accepted, estimate)
+	}
+
+	if len(accepted) == 0 {
+		return accepted, nil
+	}
+
+	estimateStore, err := session.NewEstimateStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create estimate store: %w", err)
+	}
+	if err := estimateStore.AddEstimates(accepted); err != nil {
+		return nil, fmt.Errorf("failed to save estimates: %w", err)
+	}
+
+	return accepted, nil
+}
+
+// loadBallsForEstimate resolves the target as the "all" meta-session, a session ID, or a ball ID
+func loadBallsForEstimate(projectDir, target string) ([]*session.Ball, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	isSession := target == "all"
+	if !isSession {
+		sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(target); err == nil {
+			isSession = true
+		}
+	}
+
+	if !isSession {
+		ball, err := store.ResolveBallID(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a session or ball: %w", target, err)
+		}
+		return []*session.Ball{ball}, nil
+	}
+
+	result := make([]*session.Ball, 0)
+	for _, ball := range balls {
+		if ball.State == session.StateComplete || ball.State == session.StateResearched {
+			continue
+		}
+		if target != "all" && !ballHasTag(ball, target) {
+			continue
+		}
+		result = append(result, ball)
+	}
+	return result, nil
+}
+
+// ballHasTag reports whether a ball carries the given tag
+func ballHasTag(ball *session.Ball, tag string) bool {
+	for _, t := range ball.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// generateEstimatePrompt builds the estimation prompt for a set of balls
+func generateEstimatePrompt(balls []*session.Ball) string {
+	var buf strings.Builder
+
+	buf.WriteString("<balls>\n")
+	for i, ball := range balls {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		writeBallForRefine(&buf, ball)
+	}
+	buf.WriteString("</balls>\n\n")
+
+	buf.WriteString("<instructions>\n")
+	buf.WriteString(agent.GetEstimatePromptTemplate())
+	if !strings.HasSuffix(agent.GetEstimatePromptTemplate(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</instructions>\n")
+
+	return buf.String()
+}
+
+// parseEstimateOutput extracts estimates from agent output.
+// Entries with an invalid complexity are skipped; invalid model sizes and iteration counts are dropped.
+func parseEstimateOutput(output string) []*session.Estimate {
+	estimates := make([]*session.Estimate, 0)
+	now := time.Now()
+
+	for _, match := range estimatePattern.FindAllStringSubmatch(output, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range estimateAttrPattern.FindAllStringSubmatch(match[1], -1) {
+			attrs[strings.ToLower(attr[1])] = strings.TrimSpace(attr[2])
+		}
+
+		ballID := attrs["ball"]
+		complexity := strings.ToLower(attrs["complexity"])
+		if ballID == "" || !session.ValidateComplexity(complexity) {
+			continue
+		}
+
+		estimate := &session.Estimate{
+			BallID:     ballID,
+			Complexity: complexity,
+			Rationale:  strings.TrimSpace(match[2]),
+			CreatedAt:  now,
+		}
+		if size := strings.ToLower(attrs["model_size"]); size != "" && session.ValidateModelSize(size) {
+			estimate.ModelSize = session.ModelSize(size)
+		}
+		if n, err := strconv.Atoi(attrs["iterations"]); err == nil && n > 0 {
+			estimate.Iterations = n
+		}
+		estimates = append(estimates, estimate)
+	}
+
+	return estimates
+}
+
+// printEstimates prints estimates in a compact table
+func printEstimates(estimates []*session.Estimate) {
+	fmt.Printf("%-20s %-10s %-8s %-5s %s\n", "BALL", "COMPLEXITY", "MODEL", "ITERS", "RATIONALE")
+	for _, e := range estimates {
+		model := string(e.ModelSize)
+		if model == "" {
+			model = "-"
+		}
+		iters := "-"
+		if e.Iterations > 0 {
+			iters = strconv.Itoa(e.Iterations)
+		}
+		fmt.Printf("%-20s %-10s %-8s %-5s %s\n", e.BallID, e.Complexity, model, iters, e.Rationale)
+	}
+}
+
+// newEstimateStoreForCommand creates the estimate store and ball store for the current project
+func newEstimateStoreForCommand() (*session.EstimateStore, *session.Store, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	estimateStore, err := session.NewEstimateStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create estimate store: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return estimateStore, store, nil
+}
+
+func runEstimateList(cmd *cobra.Command, args []string) error {
+	estimateStore, _, err := newEstimateStoreForCommand()
+	if err != nil {
+		return err
+	}
+	estimates, err := estimateStore.LoadEstimates()
+	if err != nil {
+		return err
+	}
+	if len(estimates) == 0 {
+		fmt.Println("No estimates waiting for review.")
+		return nil
+	}
+	printEstimates(estimates)
+	return nil
+}
+
+// selectEstimates returns the queued estimates matching the given ball IDs (or all with --all)
+func selectEstimates(estimateStore *session.EstimateStore, store *session.Store, ids []string, all bool) ([]*session.Estimate, error) {
+	estimates, err := estimateStore.LoadEstimates()
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return estimates, nil
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("specify ball IDs or --all")
+	}
+
+	selected := make([]*session.Estimate, 0, len(ids))
+	for _, id := range ids {
+		ballID := id
+		if ball, err := store.ResolveBallID(id); err == nil {
+			ballID = ball.ID
+		}
+		var found *session.Estimate
+		for _, e := range estimates {
+			if e.BallID == ballID {
+				found = e
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no pending estimate for ball %s", id)
+		}
+		selected = append(selected, found)
+	}
+	return selected, nil
+}
+
+// acceptEstimate applies an estimate to its ball and removes it from the queue
+func acceptEstimate(estimateStore *session.EstimateStore, store *session.Store, estimate *session.Estimate) error {
+	ball, err := store.GetBallByID(estimate.BallID)
+	if err != nil {
+		return fmt.Errorf("failed to load ball %s: %w", estimate.BallID, err)
+	}
+	session.ApplyEstimate(ball, estimate)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+	}
+	return estimateStore.RemoveEstimate(estimate.BallID)
+}
+
+func runEstimateAccept(cmd *cobra.Command, args []string) error {
+	estimateStore, store, err := newEstimateStoreForCommand()
+	if err != nil {
+		return err
+	}
+	estimates, err := selectEstimates(estimateStore, store, args, estimateAll)
+	if err != nil {
+		return err
+	}
+
+	adjusting := cmd.Flags().Changed("model-size") || cmd.Flags().Changed("complexity") || cmd.Flags().Changed("iterations")
+	if adjusting && len(estimates) != 1 {
+		return fmt.Errorf("--model-size, --complexity and --iterations can only adjust a single estimate")
+	}
+	if estimateModelSize != "" {
+		if !session.ValidateModelSize(estimateModelSize) {
+			return validationErrorf("invalid model size %q (valid: small, medium, large)", estimateModelSize)
+		}
+		estimates[0].ModelSize = session.ModelSize(estimateModelSize)
+	}
+	if estimateComplexity != "" {
+		if !session.ValidateComplexity(estimateComplexity) {
+			return validationErrorf("invalid complexity %q (valid: low, medium, high)", estimateComplexity)
+		}
+		estimates[0].Complexity = estimateComplexity
+	}
+	if cmd.Flags().Changed("iterations") {
+		if estimateIterations < 1 {
+			return validationErrorf("--iterations must be at least 1")
+		}
+		estimates[0].Iterations = estimateIterations
+	}
+
+	for _, estimate := range estimates {
+		if err := acceptEstimate(estimateStore, store, estimate); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Accepted estimate for %s (complexity: %s, model: %s, iterations: %d)\n",
+			estimate.BallID, estimate.Complexity, estimate.ModelSize, estimate.Iterations)
+	}
+	return nil
+}
+
+func runEstimateReject(cmd *cobra.Command, args []string) error {
+	estimateStore, store, err := newEstimateStoreForCommand()
+	if err != nil {
+		return err
+	}
+	estimates, err := selectEstimates(estimateStore, store, args, estimateAll)
+	if err != nil {
+		return err
+	}
+	for _, estimate := range estimates {
+		if err := estimateStore.RemoveEstimate(estimate.BallID); err != nil {
+			return err
+		}
+		fmt.Printf("Rejected estimate for %s\n", estimate.BallID)
+	}
+	return nil
+}
+
+func runEstimateReview(cmd *cobra.Command, args []string) error {
+	estimateStore, store, err := newEstimateStoreForCommand()
+	if err != nil {
+		return err
+	}
+	estimates, err := estimateStore.LoadEstimates()
+	if err != nil {
+		return err
+	}
+	if len(estimates) == 0 {
+		fmt.Println("No estimates waiting for review.")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for i, estimate := range estimates {
+		title := ""
+		if ball, err := store.GetBallByID(estimate.BallID); err == nil {
+			title = ball.Title
+		}
+		fmt.Printf("\n[%d/%d] %s: %s\n", i+1, len(estimates), estimate.BallID, title)
+		fmt.Printf("  Complexity: %s  Model: %s  Iterations: %d\n", estimate.Complexity, estimate.ModelSize, estimate.Iterations)
+		if estimate.Rationale != "" {
+			fmt.Printf("  Rationale: %s\n", estimate.Rationale)
+		}
+		fmt.Print("  (a)ccept, set model (s)mall/(m)edium/(l)arge, (r)eject, (k)eep for later, (q)uit: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "a":
+		case "s":
+			estimate.ModelSize = session.ModelSizeSmall
+		case "m":
+			estimate.ModelSize = session.ModelSizeMedium
+		case "l":
+			estimate.ModelSize = session.ModelSizeLarge
+		case "r":
+			if err := estimateStore.RemoveEstimate(estimate.BallID); err != nil {
+				return err
+			}
+			fmt.Println("  Rejected")
+			continue
+		case "q":
+			return nil
+		default:
+			fmt.Println("  Kept in queue")
+			continue
+		}
+
+		if err := acceptEstimate(estimateStore, store, estimate); err != nil {
+			return err
+		}
+		fmt.Printf("  ✓ Accepted (model: %s)\n", estimate.ModelSize)
+	}
+	return nil
+}
+
+// EstimateBallsForTest is an exported wrapper for testing
+func EstimateBallsForTest(projectDir, target string) ([]*session.Estimate, error) {
+	return estimateBalls(projectDir, target, "")
+}
+
+// ParseEstimateOutputForTest is an exported wrapper for testing
+func ParseEstimateOutputForTest(output string) []*session.Estimate {
+	return parseEstimateOutput(output)
+}
+
+func init() {
+	estimateCmd.Flags().StringVar(&estimateProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
+	estimateCmd.Flags().StringVarP(&estimateModel, "model", "m", "", "Model to use (opus, sonnet, haiku)")
+
+	estimateAcceptCmd.Flags().BoolVar(&estimateAll, "all", false, "Accept every queued estimate")
+	estimateAcceptCmd.Flags().StringVar(&estimateModelSize, "model-size", "", "Adjust the suggested model size before accepting (small, medium, large)")
+	estimateAcceptCmd.Flags().StringVar(&estimateComplexity, "complexity", "", "Adjust the suggested complexity before accepting (low, medium, high)")
+	estimateAcceptCmd.Flags().IntVar(&estimateIterations, "iterations", 0, "Adjust the suggested number of agent iterations before accepting")
+	estimateRejectCmd.Flags().BoolVar(&estimateAll, "all", false, "Reject every queued estimate")
+
+	estimateCmd.AddCommand(estimateListCmd)
+	estimateCmd.AddCommand(estimateAcceptCmd)
+	estimateCmd.AddCommand(estimateRejectCmd)
+	estimateCmd.AddCommand(estimateReviewCmd)
+	rootCmd.AddCommand(estimateCmd)
+}
@@ -0,0 +1,401 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/watcher"
+	"github.com/spf13/cobra"
+)
+
+// eventsClientBuffer is how many events a socket client may fall behind
+// before it's disconnected
+const eventsClientBuffer = 64
+
+var (
+	eventsSocket     bool
+	eventsSocketPath string
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Stream ball, session and agent changes as JSON",
+	Long: `Stream the current project's ball, session and agent changes as JSON, one
+event per line, so status bars, editor statuslines and other overlays can
+show what's in progress without polling juggle's files.
+
+Without --socket, events are written to stdout. With --socket, they're
+broadcast to every client of a unix socket, .juggle/events.sock unless
+--socket-path says otherwise; the socket is removed when the stream stops.
+
+Each event has a type, the time and the project, the ball, session or agent
+it's about, and a status summary: the in-progress balls, ball counts by
+state and the running agents. A client first receives a "snapshot" event.
+
+Event types:
+  snapshot          The status when the stream starts or a client connects
+  ball_changed      A ball was added or changed ("ball" holds it)
+  ball_removed      A ball was deleted or archived
+  balls_changed     The balls changed but couldn't be diffed
+  session_changed   A session's description, context or settings changed
+  progress_changed  A session's progress log was appended to
+  agent_started     An agent run took a session's lock ("agent" holds it)
+  agent_stopped     An agent run released a session's lock
+
+Examples:
+  juggle events
+  juggle events --socket
+  juggle events --socket-path /tmp/juggle.sock
+  socat - UNIX-CONNECT:.juggle/events.sock | jq -r '.status.in_progress[0].title'`,
+	Args: cobra.NoArgs,
+	RunE: runEvents,
+}
+
+func init() {
+	eventsCmd.Flags().BoolVar(&eventsSocket, "socket", false, "Broadcast events on a unix socket instead of stdout")
+	eventsCmd.Flags().StringVar(&eventsSocketPath, "socket-path", "", "Socket to broadcast on (implies --socket; default .juggle/events.sock)")
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// overlayEvent is one event of `juggle events`
+type overlayEvent struct {
+	Type      string        `json:"type"`
+	Time      time.Time     `json:"time"`
+	Project   string        `json:"project"`
+	BallID    string        `json:"ball_id,omitempty"`
+	Ball      *session.Ball `json:"ball,omitempty"`
+	SessionID string        `json:"session_id,omitempty"`
+	Agent     *overlayAgent `json:"agent,omitempty"`
+	Status    overlayStatus `json:"status"`
+}
+
+// overlayStatus summarises the project for display
+type overlayStatus struct {
+	InProgress []overlayBall  `json:"in_progress"`
+	Counts     map[string]int `json:"counts"`
+	Agents     []overlayAgent `json:"agents"`
+}
+
+// overlayBall is an in-progress ball in a status summary
+type overlayBall struct {
+	ID         string   `json:"id"`
+	ShortID    string   `json:"short_id"`
+	Title      string   `json:"title"`
+	Priority   string   `json:"priority"`
+	Checkpoint string   `json:"checkpoint,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// overlayAgent is a running agent, known by the session lock it holds
+type overlayAgent struct {
+	SessionID string    `json:"session_id"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// eventStream turns watcher events into overlay events and fans them out
+type eventStream struct {
+	projectDir   string
+	store        *session.Store
+	sessionStore *session.SessionStore
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	agents  map[string]bool // Sessions with a running agent, to tell starts from stops
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	stream, err := newEventStream(cwd)
+	if err != nil {
+		return err
+	}
+
+	w, err := watcher.New()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer w.Close()
+	if err := w.WatchProject(stream.projectDir); err != nil {
+		return fmt.Errorf("failed to watch project: %w", err)
+	}
+	w.Start()
+
+	var emit func(overlayEvent)
+	errs := make(chan error, 1)
+	if !eventsSocket && eventsSocketPath == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		emit = func(event overlayEvent) { encoder.Encode(event) }
+		emit(stream.snapshot())
+	} else {
+		path := eventsSocketPath
+		if path == "" {
+			path = filepath.Join(stream.projectDir, GetStoreConfig().JuggleDirName, "events.sock")
+		}
+		listener, err := listenEventsSocket(path)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(path)
+		defer listener.Close()
+		go func() { errs <- stream.serve(listener) }()
+		emit = stream.broadcast
+		fmt.Fprintf(os.Stderr, "Streaming %s events on %s\n", stream.projectDir, path)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			for _, out := range stream.translate(event) {
+				emit(out)
+			}
+		case <-w.Errors:
+			// Watcher errors are transient; the next change still arrives
+		case err := <-errs:
+			return err
+		case <-signals:
+			return nil
+		}
+	}
+}
+
+// listenEventsSocket listens on a unix socket at path, replacing a stale
+// socket file but refusing to take over one that's still being served
+func listenEventsSocket(path string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, validationErrorf("another process is already serving events on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, validationErrorf("failed to listen on %s: %v", path, err)
+	}
+	return listener, nil
+}
+
+func newEventStream(projectDir string) (*eventStream, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(store.ProjectDir(), GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	stream := &eventStream{
+		projectDir:   store.ProjectDir(),
+		store:        store,
+		sessionStore: sessionStore,
+		clients:      make(map[chan []byte]struct{}),
+		agents:       make(map[string]bool),
+	}
+	for _, agent := range stream.runningAgents() {
+		stream.agents[sessionStorageID(agent.SessionID)] = true
+	}
+	return stream, nil
+}
+
+// serve accepts socket clients until the listener is closed, sending each
+// a snapshot and then every broadcast event
+func (s *eventStream) serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return nil // Closed on shutdown
+		}
+		client := make(chan []byte, eventsClientBuffer)
+		if data, err := json.Marshal(s.snapshot()); err == nil {
+			client <- append(data, '\n')
+		}
+		s.mu.Lock()
+		s.clients[client] = struct{}{}
+		s.mu.Unlock()
+		go s.writeClient(conn, client)
+	}
+}
+
+// writeClient writes a client's events until it disconnects or is dropped
+func (s *eventStream) writeClient(conn net.Conn, client chan []byte) {
+	defer conn.Close()
+	for data := range client {
+		if _, err := conn.Write(data); err != nil {
+			s.drop(client)
+			return
+		}
+	}
+}
+
+// drop disconnects a client, once
+func (s *eventStream) drop(client chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[client]; ok {
+		delete(s.clients, client)
+		close(client)
+	}
+}
+
+// broadcast sends an event to every client, dropping any that have fallen
+// too far behind rather than stalling the others
+func (s *eventStream) broadcast(event overlayEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- data:
+		default:
+			delete(s.clients, client)
+			close(client)
+		}
+	}
+}
+
+// translate turns a watcher event into the overlay events it stands for
+func (s *eventStream) translate(event watcher.Event) []overlayEvent {
+	switch event.Type {
+	case watcher.BallsChanged:
+		if !event.Diffed {
+			return []overlayEvent{s.newEvent("balls_changed")}
+		}
+		var events []overlayEvent
+		for _, id := range event.BallIDs {
+			out := s.newEvent("ball_changed")
+			out.BallID = id
+			out.Ball, _ = s.store.GetBallByID(id)
+			events = append(events, out)
+		}
+		for _, id := range event.RemovedBallIDs {
+			out := s.newEvent("ball_removed")
+			out.BallID = id
+			events = append(events, out)
+		}
+		return events
+	case watcher.SessionChanged, watcher.ProgressChanged:
+		out := s.newEvent(event.Type.String())
+		out.SessionID = event.SessionID
+		return []overlayEvent{out}
+	case watcher.AgentChanged:
+		locked, info := s.sessionStore.IsLocked(event.SessionID)
+		s.mu.Lock()
+		changed := s.agents[event.SessionID] != locked
+		s.agents[event.SessionID] = locked
+		s.mu.Unlock()
+		if !changed {
+			return nil
+		}
+		out := s.newEvent("agent_stopped")
+		out.SessionID = overlaySessionID(event.SessionID)
+		if locked {
+			out.Type = "agent_started"
+			out.Agent = newOverlayAgent(event.SessionID, info)
+		}
+		return []overlayEvent{out}
+	}
+	return nil
+}
+
+// snapshot is the event a stream or client starts with
+func (s *eventStream) snapshot() overlayEvent {
+	return s.newEvent("snapshot")
+}
+
+// newEvent stamps an event of the given type with the current status
+func (s *eventStream) newEvent(eventType string) overlayEvent {
+	return overlayEvent{
+		Type:    eventType,
+		Time:    time.Now(),
+		Project: s.projectDir,
+		Status:  s.status(),
+	}
+}
+
+// status summarises the project's balls and running agents
+func (s *eventStream) status() overlayStatus {
+	status := overlayStatus{
+		InProgress: []overlayBall{},
+		Counts:     make(map[string]int),
+		Agents:     s.runningAgents(),
+	}
+	balls, _ := s.store.LoadBalls()
+	for _, ball := range balls {
+		status.Counts[string(ball.State)]++
+		if ball.State != session.StateInProgress {
+			continue
+		}
+		status.InProgress = append(status.InProgress, overlayBall{
+			ID:         ball.ID,
+			ShortID:    ball.ShortID(),
+			Title:      ball.Title,
+			Priority:   string(ball.Priority),
+			Checkpoint: ball.Checkpoint,
+			Tags:       ball.Tags,
+		})
+	}
+	return status
+}
+
+// runningAgents lists the agents holding a session lock, including runs
+// against the "all" meta-session
+func (s *eventStream) runningAgents() []overlayAgent {
+	ids := []string{sessionStorageID("all")}
+	if sessions, err := s.sessionStore.ListSessions(); err == nil {
+		for _, sess := range sessions {
+			ids = append(ids, sess.ID)
+		}
+	}
+	agents := []overlayAgent{}
+	for _, id := range ids {
+		if locked, info := s.sessionStore.IsLocked(id); locked {
+			agents = append(agents, *newOverlayAgent(id, info))
+		}
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].SessionID < agents[j].SessionID })
+	return agents
+}
+
+// newOverlayAgent describes the agent holding a session's lock
+func newOverlayAgent(storageID string, info *session.LockInfo) *overlayAgent {
+	agent := &overlayAgent{SessionID: overlaySessionID(storageID)}
+	if info != nil {
+		agent.PID = info.PID
+		agent.StartedAt = info.StartedAt
+	}
+	return agent
+}
+
+// overlaySessionID maps a session's storage ID back to the ID users pass,
+// so "all" runs aren't reported as "_all"
+func overlaySessionID(storageID string) string {
+	if storageID == sessionStorageID("all") {
+		return "all"
+	}
+	return storageID
+}
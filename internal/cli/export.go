@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -14,19 +13,20 @@ import (
 )
 
 var (
-	exportFormat      string
-	exportOutput      string
-	exportIncludeDone bool
-	exportBallIDs     string
-	exportFilterState string
-	exportSession     string
-	exportBallID      string // Single ball filter for focused agent prompts
+	exportFormat       string
+	exportOutput       string
+	exportIncludeDone  bool
+	exportBallIDs      string
+	exportFilterState  string
+	exportSession      string
+	exportBallID       string // Single ball filter for focused agent prompts
+	exportTemplatePath string // Go template file for user-defined formats
 )
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Export balls to JSON, CSV, Ralph, or agent format",
-	Long: `Export session data to JSON, CSV, Ralph, or agent format for analysis or agent use.
+	Short: "Export balls to JSON, CSV, TSV, Ralph, or agent format",
+	Long: `Export session data to JSON, CSV, TSV, Ralph, or agent format for analysis or agent use.
 
 By default exports active balls (excluding complete) from the current project only.
 Use --all to export from all discovered projects.
@@ -42,6 +42,12 @@ Filters are applied in order:
 3. --filter-state (if specified, only balls in these states)
 4. --include-done (if false, excludes completed balls)
 
+The CSV and TSV formats (--format csv|tsv) have one row per ball, with columns
+id, title, state, priority, tags, due, session. Tags and sessions are
+";"-separated; tags lists the tags that aren't sessions. due is when a
+recurring ball is next due (YYYY-MM-DD HH:MM). Edit the file in a spreadsheet
+and load it back with 'juggle import --format csv'.
+
 The Ralph format (--format ralph) is designed for agent loops and includes:
 - <context> section from the session's context
 - <progress> section from the session's progress.txt
@@ -50,9 +56,22 @@ The Ralph format (--format ralph) is designed for agent loops and includes:
 The Agent format (--format agent) is a self-contained prompt for AI agents:
 - <context> section from the session's context
 - <progress> section with last 50 lines of progress.txt
+- <goal> section with the session goal and exit criteria, if set
+- <previous-iteration> section if the last agent iteration timed out
 - <balls> section with all session balls (state, acceptance criteria)
 - <instructions> section with the agent prompt template
-Can be piped directly to 'claude -p'.
+Can be piped directly to 'claude -p'. The sections are arranged by the
+prompt template, which can be customized (see 'juggle agent prompt').
+
+Custom formats (--template FILE) render balls through a Go text/template.
+The template receives .Balls, .Sessions (this project's sessions), .Session
+(from --session) and .GeneratedAt, plus these helpers:
+  md         escape Markdown:            {{md .Title}}
+  date       format a time:              {{date "2006-01-02" .CompletedAt}}
+  checklist  ACs as a Markdown checklist: {{checklist .}}
+  inSession  balls tagged with a session: {{range inSession "auth" .Balls}}
+  inState    balls in a state:           {{range inState "blocked" .Balls}}
+  join, lower, upper, trim, indent N, default DEF
 
 Examples:
   # Export current project balls
@@ -77,24 +96,33 @@ Examples:
   juggle export --filter-state in_progress --format json
 
   # Combine filters: export pending and in_progress balls from all projects
-  juggle export --all --filter-state "pending,in_progress" --format csv`,
+  juggle export --all --filter-state "pending,in_progress" --format csv
+
+  # Render a custom report through a Go template
+  juggle export --template weekly-report.tmpl --include-done`,
 	RunE: runExport,
 }
 
 func init() {
-	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "Export format: json, csv, ralph, or agent")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "Export format: json, csv, tsv, ralph, or agent")
 	exportCmd.Flags().StringVar(&exportOutput, "output", "", "Output file path (default: stdout)")
 	exportCmd.Flags().BoolVar(&exportIncludeDone, "include-done", false, "Include complete balls in export (by default excluded from all formats)")
 	exportCmd.Flags().StringVar(&exportBallIDs, "ball-ids", "", "Filter by specific ball IDs (comma-separated, supports full or short IDs)")
 	exportCmd.Flags().StringVar(&exportFilterState, "filter-state", "", "Filter by states (comma-separated: pending, in_progress, blocked, complete)")
 	exportCmd.Flags().StringVar(&exportSession, "session", "", "Export balls from a specific session (for ralph format, includes context and progress)")
 	exportCmd.Flags().StringVar(&exportBallID, "ball", "", "Export a single ball by ID (for focused agent prompts)")
+	exportCmd.Flags().StringVar(&exportTemplatePath, "template", "", "Render balls through a Go template file instead of a built-in format")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
 	// Validate format
-	if exportFormat != "json" && exportFormat != "csv" && exportFormat != "ralph" && exportFormat != "agent" {
-		return fmt.Errorf("invalid format: %s (must be json, csv, ralph, or agent)", exportFormat)
+	if exportTemplatePath != "" {
+		if cmd.Flags().Changed("format") {
+			return usageErrorf("--template and --format cannot be used together")
+		}
+		exportFormat = "template"
+	} else if exportFormat != "json" && exportFormat != "csv" && exportFormat != "tsv" && exportFormat != "ralph" && exportFormat != "agent" {
+		return validationErrorf("invalid format: %s (must be json, csv, tsv, ralph, or agent)", exportFormat)
 	}
 
 	// Ralph and agent formats require --session (but "all" is a special meta-session)
@@ -158,14 +186,14 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if exportBallID != "" {
 		matches := session.ResolveBallByPrefix(balls, exportBallID)
 		if len(matches) == 0 {
-			return fmt.Errorf("ball not found: %s", exportBallID)
+			return notFoundErrorf("ball not found: %s", exportBallID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", exportBallID, len(matches), strings.Join(matchingIDs, ", "))
+			return validationErrorf("ambiguous ID '%s' matches %d balls: %s", exportBallID, len(matches), strings.Join(matchingIDs, ", "))
 		}
 		balls = []*session.Ball{matches[0]}
 	}
@@ -230,12 +258,14 @@ func runExport(cmd *cobra.Command, args []string) error {
 	switch exportFormat {
 	case "json":
 		output, err = exportJSON(balls)
-	case "csv":
-		output, err = exportCSV(balls)
+	case "csv", "tsv":
+		output, err = exportTable(balls, exportFormat)
 	case "ralph":
 		output, err = exportRalph(cwd, exportSession, balls)
 	case "agent":
 		output, err = exportAgent(cwd, exportSession, balls, false, exportBallID != "") // debug only via agent run --debug
+	case "template":
+		output, err = exportTemplate(cwd, exportTemplatePath, exportSession, balls)
 	}
 
 	if err != nil {
@@ -278,14 +308,14 @@ func filterByBallIDs(balls []*session.Ball, ballIDsStr string, projects []string
 	for _, requestedID := range requestedIDs {
 		matches := session.ResolveBallByPrefix(balls, requestedID)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball ID not found: %s", requestedID)
+			return nil, notFoundErrorf("ball ID not found: %s", requestedID)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", requestedID, len(matches), strings.Join(matchingIDs, ", "))
+			return nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", requestedID, len(matches), strings.Join(matchingIDs, ", "))
 		}
 		ball := matches[0]
 		if !seenBalls[ball.ID] {
@@ -311,7 +341,7 @@ func filterByState(balls []*session.Ball, stateStr string) ([]*session.Ball, err
 		}
 
 		if !session.ValidateBallState(s) {
-			return nil, fmt.Errorf("invalid state: %s (must be pending, in_progress, blocked, complete, or researched)", s)
+			return nil, validationErrorf("invalid state: %s (must be pending, in_progress, blocked, complete, or researched)", s)
 		}
 		stateFilters = append(stateFilters, session.BallState(s))
 	}
@@ -354,64 +384,6 @@ func exportJSON(balls []*session.Ball) ([]byte, error) {
 	return data, nil
 }
 
-func exportCSV(balls []*session.Ball) ([]byte, error) {
-	var buf strings.Builder
-	writer := csv.NewWriter(&buf)
-
-	// Write header
-	header := []string{
-		"ID",
-		"Project",
-		"Intent",
-		"Priority",
-		"State",
-		"BlockedReason",
-		"StartedAt",
-		"CompletedAt",
-		"LastActivity",
-		"Tags",
-		"CompletionNote",
-	}
-	if err := writer.Write(header); err != nil {
-		return nil, err
-	}
-
-	// Write rows
-	for _, ball := range balls {
-		completedAt := ""
-		if ball.CompletedAt != nil {
-			completedAt = ball.CompletedAt.Format("2006-01-02 15:04:05")
-		}
-
-		tags := strings.Join(ball.Tags, ";")
-
-		row := []string{
-			ball.ID,
-			ball.WorkingDir,
-			ball.Title,
-			string(ball.Priority),
-			string(ball.State),
-			ball.BlockedReason,
-			ball.StartedAt.Format("2006-01-02 15:04:05"),
-			completedAt,
-			ball.LastActivity.Format("2006-01-02 15:04:05"),
-			tags,
-			ball.CompletionNote,
-		}
-
-		if err := writer.Write(row); err != nil {
-			return nil, err
-		}
-	}
-
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		return nil, err
-	}
-
-	return []byte(buf.String()), nil
-}
-
 // exportRalph exports session data in Ralph agent format
 // Format:
 // <context>
@@ -506,8 +478,11 @@ func exportRalph(projectDir, sessionID string, balls []*session.Ball) ([]byte, e
 		buf.WriteString("</global-acceptance-criteria>\n\n")
 	}
 
+	// Resolve dependencies against active and archived balls
+	deps := loadDependencyIndex(projectDir, balls)
+
 	// Sort balls: in_progress first (implies unfinished work), then by priority
-	sortBallsForAgent(balls)
+	sortBallsForAgent(balls, deps)
 
 	// Write <tasks> section
 	buf.WriteString("<tasks>\n")
@@ -515,22 +490,53 @@ func exportRalph(projectDir, sessionID string, balls []*session.Ball) ([]byte, e
 		if i > 0 {
 			buf.WriteString("\n")
 		}
-		writeBallForRalph(&buf, ball)
+		writeBallForRalph(&buf, ball, deps)
 	}
 	buf.WriteString("</tasks>\n")
 
 	return []byte(buf.String()), nil
 }
 
+// writeSessionGoal writes the session's goal and exit criteria, which must
+// all be met before the session is COMPLETE. It writes the prompt's <goal>
+// section, without its tags.
+func writeSessionGoal(buf *strings.Builder, sess *session.JuggleSession) {
+	if sess.Goal != "" {
+		buf.WriteString(sess.Goal + "\n")
+	}
+	if len(sess.ExitCriteria) > 0 {
+		if sess.Goal != "" {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Exit criteria. COMPLETE is only accepted once all of them are met:\n")
+		for i, criterion := range sess.ExitCriteria {
+			buf.WriteString(fmt.Sprintf("  %d. %s\n", i+1, criterion))
+		}
+		buf.WriteString("\nCriteria with a check are re-run before COMPLETE is accepted. When you have met\n")
+		buf.WriteString("one without a check, mark it with: juggle sessions goal " + sess.ID + " --met <number>\n")
+	}
+}
+
 // writeBallForRalph writes a single ball in Ralph format
-func writeBallForRalph(buf *strings.Builder, ball *session.Ball) {
+func writeBallForRalph(buf *strings.Builder, ball *session.Ball, deps *session.DependencyIndex) {
 	// Task header with ID, state, and priority
 	header := fmt.Sprintf("## %s [%s] (priority: %s)", ball.ID, ball.State, ball.Priority)
 	if ball.ModelSize != "" {
 		header += fmt.Sprintf(" (model: %s)", ball.ModelSize)
 	}
+	if ball.Focused {
+		header += " (FOCUS)"
+	}
 	buf.WriteString(header + "\n")
 
+	// Next action first, so it's the first thing read
+	if ball.NextAction != "" {
+		buf.WriteString(fmt.Sprintf("Next Action: %s\n", ball.NextAction))
+	}
+	if ball.Checkpoint != "" {
+		buf.WriteString(fmt.Sprintf("Checkpoint: %s (resume from here)\n", ball.Checkpoint))
+	}
+
 	// Title
 	buf.WriteString(fmt.Sprintf("Title: %s\n", ball.Title))
 
@@ -542,9 +548,9 @@ func writeBallForRalph(buf *strings.Builder, ball *session.Ball) {
 		}
 	}
 
-	// Dependencies
+	// Dependencies, with their resolved state
 	if len(ball.DependsOn) > 0 {
-		buf.WriteString(fmt.Sprintf("Depends On: %s\n", strings.Join(ball.DependsOn, ", ")))
+		buf.WriteString(fmt.Sprintf("Depends On: %s\n", deps.FormatDependencies(ball)))
 	}
 
 	// Blocked reason if blocked
@@ -572,6 +578,14 @@ func writeBallForRalph(buf *strings.Builder, ball *session.Ball) {
 // [repo and session level ACs]
 // </global-acceptance-criteria>
 //
+// <goal> (if the session has a goal or exit criteria)
+// [goal and exit criteria]
+// </goal>
+//
+// <previous-iteration> (if the last iteration timed out)
+// [what it got done and the end of its output]
+// </previous-iteration>
+//
 // <balls> or <task> (if singleBall)
 // [balls with state and acceptance criteria]
 // </balls> or </task>
@@ -580,9 +594,10 @@ func writeBallForRalph(buf *strings.Builder, ball *session.Ball) {
 // [agent prompt template]
 // [optional debug instructions]
 // </instructions>
+//
+// The sections are arranged by the prompt skeleton, which a project or
+// session can override (see loadAgentPromptTemplate).
 func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool, singleBall bool) ([]byte, error) {
-	var buf strings.Builder
-
 	// Load session store to get context and progress
 	sessionStore, err := session.NewSessionStore(projectDir)
 	if err != nil {
@@ -607,8 +622,20 @@ func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool
 	// Load repo-level acceptance criteria
 	repoACs, _ := session.GetProjectAcceptanceCriteria(projectDir) // Ignore error
 
-	// Write <context> section
-	buf.WriteString("<context>\n")
+	data := AgentPromptData{
+		SessionID:  sessionID,
+		Session:    juggleSession,
+		BallList:   balls,
+		SingleBall: singleBall && len(balls) == 1,
+		Debug:      debug,
+	}
+	var buf strings.Builder
+	section := func(field *string) {
+		*field = buf.String()
+		buf.Reset()
+	}
+
+	// <context> section
 	if juggleSession.Description != "" {
 		buf.WriteString("# " + juggleSession.Description + "\n\n")
 	}
@@ -618,77 +645,73 @@ func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool
 			buf.WriteString("\n")
 		}
 	}
-	buf.WriteString("</context>\n\n")
+	section(&data.Context)
 
-	// Write <session> section with the session ID
-	// This tells the agent which session it's working on for progress commands
-	buf.WriteString("<session>\n")
-	buf.WriteString(sessionID)
-	buf.WriteString("\n</session>\n\n")
-
-	// Write <progress> section
-	buf.WriteString("<progress>\n")
+	// <progress> section
 	if progress != "" {
 		buf.WriteString(progress)
 		if !strings.HasSuffix(progress, "\n") {
 			buf.WriteString("\n")
 		}
 	}
-	buf.WriteString("</progress>\n\n")
-
-	// Write <global-acceptance-criteria> section if any exist
-	if len(repoACs) > 0 || len(juggleSession.AcceptanceCriteria) > 0 {
-		buf.WriteString("<global-acceptance-criteria>\n")
-		buf.WriteString("These criteria apply to ALL tasks in this session:\n\n")
+	section(&data.Progress)
 
-		acIndex := 1
+	// <global-acceptance-criteria> section, if any exist
+	acIndex := 1
+	if len(repoACs) > 0 {
+		buf.WriteString("## Repository-Level Requirements\n")
+		for _, ac := range repoACs {
+			buf.WriteString(fmt.Sprintf("  %d. %s\n", acIndex, ac))
+			acIndex++
+		}
+	}
+	if len(juggleSession.AcceptanceCriteria) > 0 {
 		if len(repoACs) > 0 {
-			buf.WriteString("## Repository-Level Requirements\n")
-			for _, ac := range repoACs {
-				buf.WriteString(fmt.Sprintf("  %d. %s\n", acIndex, ac))
-				acIndex++
-			}
+			buf.WriteString("\n## Session-Level Requirements\n")
+		} else {
+			buf.WriteString("## Session-Level Requirements\n")
 		}
-		if len(juggleSession.AcceptanceCriteria) > 0 {
-			if len(repoACs) > 0 {
-				buf.WriteString("\n## Session-Level Requirements\n")
-			} else {
-				buf.WriteString("## Session-Level Requirements\n")
-			}
-			for _, ac := range juggleSession.AcceptanceCriteria {
-				buf.WriteString(fmt.Sprintf("  %d. %s\n", acIndex, ac))
-				acIndex++
-			}
+		for _, ac := range juggleSession.AcceptanceCriteria {
+			buf.WriteString(fmt.Sprintf("  %d. %s\n", acIndex, ac))
+			acIndex++
 		}
-		buf.WriteString("</global-acceptance-criteria>\n\n")
 	}
+	section(&data.AcceptanceCriteria)
+
+	// <goal> section, if the session has a goal or exit criteria
+	writeSessionGoal(&buf, juggleSession)
+	section(&data.Goal)
+
+	// <previous-iteration> section, if the last iteration timed out
+	writeTimeoutSalvage(&buf, sessionStore, sessionID)
+	section(&data.PreviousIteration)
+
+	// Resolve dependencies against active and archived balls
+	deps := loadDependencyIndex(projectDir, balls)
 
 	// Sort balls: in_progress first (implies unfinished work), then by priority
-	sortBallsForAgent(balls)
+	sortBallsForAgent(balls, deps)
 
-	// Write <balls> or <task> section
-	if singleBall && len(balls) == 1 {
+	// <balls> or <task> section
+	if data.SingleBall {
 		// Single ball mode: focused task format
-		buf.WriteString("<task>\n")
-		buf.WriteString("This is your task:\n\n")
-		writeBallForAgent(&buf, balls[0])
-		buf.WriteString("</task>\n\n")
+		writeBallForAgent(&buf, balls[0], deps)
 	} else {
 		// Multi-ball session mode
-		buf.WriteString("<balls>\n")
+		if hasFocusedBall(balls) {
+			buf.WriteString("Balls marked FOCUS were flagged by a human. Work on them before any other ball, whatever their priority.\n\n")
+		}
 		for i, ball := range balls {
 			if i > 0 {
 				buf.WriteString("\n")
 			}
-			writeBallForAgent(&buf, ball)
+			writeBallForAgent(&buf, ball, deps)
 		}
-		buf.WriteString("</balls>\n\n")
 	}
+	section(&data.Balls)
 
-	// Write <instructions> section with agent prompt template
-	buf.WriteString("<instructions>\n")
-
-	if singleBall && len(balls) == 1 {
+	// <instructions> section with agent prompt template
+	if data.SingleBall {
 		// Single ball mode: task-focused instructions
 		buf.WriteString("You are working on a single task. Complete the acceptance criteria above.\n\n")
 		buf.WriteString("When done, output one of these signals:\n")
@@ -707,10 +730,17 @@ func exportAgent(projectDir, sessionID string, balls []*session.Ball, debug bool
 		buf.WriteString("\n## DEBUG MODE\n\n")
 		buf.WriteString("Before outputting your completion signal, explain WHY you chose that signal.\n")
 	}
+	section(&data.Instructions)
 
-	buf.WriteString("</instructions>\n")
-
-	return []byte(buf.String()), nil
+	tmpl, err := loadAgentPromptTemplate(projectDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := renderAgentPrompt(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(prompt), nil
 }
 
 // limitToLastLines returns the last n lines of a string
@@ -734,14 +764,25 @@ func limitToLastLines(s string, n int) string {
 }
 
 // writeBallForAgent writes a single ball in agent format
-func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
+func writeBallForAgent(buf *strings.Builder, ball *session.Ball, deps *session.DependencyIndex) {
 	// Ball header with ID, state, and priority
 	header := fmt.Sprintf("## %s [%s] (priority: %s)", ball.ID, ball.State, ball.Priority)
 	if ball.ModelSize != "" {
 		header += fmt.Sprintf(" (model: %s)", ball.ModelSize)
 	}
+	if ball.Focused {
+		header += " (FOCUS)"
+	}
 	buf.WriteString(header + "\n")
 
+	// Next action first, so it's the first thing read
+	if ball.NextAction != "" {
+		buf.WriteString(fmt.Sprintf("Next Action: %s\n", ball.NextAction))
+	}
+	if ball.Checkpoint != "" {
+		buf.WriteString(fmt.Sprintf("Checkpoint: %s (resume from here)\n", ball.Checkpoint))
+	}
+
 	// Title
 	buf.WriteString(fmt.Sprintf("Title: %s\n", ball.Title))
 
@@ -753,9 +794,9 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 		}
 	}
 
-	// Dependencies
+	// Dependencies, with their resolved state
 	if len(ball.DependsOn) > 0 {
-		buf.WriteString(fmt.Sprintf("Depends On: %s\n", strings.Join(ball.DependsOn, ", ")))
+		buf.WriteString(fmt.Sprintf("Depends On: %s\n", deps.FormatDependencies(ball)))
 	}
 
 	// Blocked reason if blocked
@@ -767,6 +808,29 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 	if len(ball.Tags) > 0 {
 		buf.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(ball.Tags, ", ")))
 	}
+
+	// Latest notes from the ball's work log
+	if len(ball.Notes) > 0 {
+		notes := ball.RecentNotes(agentPromptNotes)
+		if len(notes) < len(ball.Notes) {
+			buf.WriteString(fmt.Sprintf("Notes (latest %d of %d):\n", len(notes), len(ball.Notes)))
+		} else {
+			buf.WriteString("Notes:\n")
+		}
+		for _, note := range notes {
+			buf.WriteString(fmt.Sprintf("  - [%s] %s\n", note.CreatedAt.Format(noteTimeFormat), note.Text))
+		}
+	}
+}
+
+// hasFocusedBall returns true if any of the balls is flagged for focus
+func hasFocusedBall(balls []*session.Ball) bool {
+	for _, ball := range balls {
+		if ball.Focused {
+			return true
+		}
+	}
+	return false
 }
 
 // SortBallsForAgentExport sorts balls so in_progress balls come first,
@@ -775,41 +839,34 @@ func writeBallForAgent(buf *strings.Builder, ball *session.Ball) {
 // Within each state, balls are sorted by priority (urgent > high > medium > low).
 // This is exported for testing.
 func SortBallsForAgentExport(balls []*session.Ball) {
-	sortBallsForAgent(balls)
+	sortBallsForAgent(balls, nil)
 }
 
-// sortBallsForAgent sorts balls so in_progress balls come first,
-// followed by pending balls, then blocked balls.
+// loadDependencyIndex builds a dependency index from the project's active and
+// archived balls. Falls back to indexing only the given balls if the store
+// can't be read.
+func loadDependencyIndex(projectDir string, balls []*session.Ball) *session.DependencyIndex {
+	store, err := NewStoreForCommand(projectDir)
+	if err == nil {
+		if deps, err := store.LoadDependencyIndex(); err == nil {
+			return deps
+		}
+	}
+	return session.NewDependencyIndex(balls, nil)
+}
+
+// sortBallsForAgent sorts balls flagged for focus first, then so
+// in_progress balls come first, followed by pending balls, then blocked balls.
 // Complete balls should be filtered out before calling this.
 // Within each state, balls are sorted by:
-// 1. Dependencies satisfied (balls with all deps complete come first)
+// 1. Dependencies satisfied (balls with all deps complete or archived come first)
 // 2. Priority (urgent > high > medium > low)
-func sortBallsForAgent(balls []*session.Ball) {
-	// Build a map of ball states for dependency checking
-	ballStates := make(map[string]session.BallState)
-	for _, ball := range balls {
-		ballStates[ball.ID] = ball.State
-		// Also map by short ID for dependency resolution
-		ballStates[ball.ShortID()] = ball.State
-	}
-
-	// Helper to check if all dependencies are satisfied (complete or researched)
-	allDepsSatisfied := func(ball *session.Ball) bool {
-		if len(ball.DependsOn) == 0 {
-			return true
-		}
-		for _, depID := range ball.DependsOn {
-			state, exists := ballStates[depID]
-			if !exists {
-				// Dependency not in current set - assume satisfied
-				continue
-			}
-			if state != session.StateComplete && state != session.StateResearched {
-				return false
-			}
-		}
-		return true
+// If deps is nil, dependencies are resolved against the given balls only.
+func sortBallsForAgent(balls []*session.Ball, deps *session.DependencyIndex) {
+	if deps == nil {
+		deps = session.NewDependencyIndex(balls, nil)
 	}
+	allDepsSatisfied := deps.DependenciesSatisfied
 
 	// State priority: in_progress first, then pending, then blocked, then complete
 	// blocked balls are filtered out before reaching this sort for agent exports
@@ -830,7 +887,12 @@ func sortBallsForAgent(balls []*session.Ball) {
 	}
 
 	sort.SliceStable(balls, func(i, j int) bool {
-		// First sort by state
+		// Focused balls come first, whatever their state or priority
+		if balls[i].Focused != balls[j].Focused {
+			return balls[i].Focused
+		}
+
+		// Then sort by state
 		stateI := stateOrder[balls[i].State]
 		stateJ := stateOrder[balls[j].State]
 		if stateI != stateJ {
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TemplateExportData is the data passed to export templates
+type TemplateExportData struct {
+	Balls       []*session.Ball          // Balls selected by the export filters
+	Sessions    []*session.JuggleSession // Sessions in the current project
+	Session     *session.JuggleSession   // Session from --session (nil if not set or "all")
+	GeneratedAt time.Time
+}
+
+// markdownEscaper escapes characters that have meaning in Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `{`, `\{`, `}`, `\}`,
+	`[`, `\[`, `]`, `\]`, `<`, `\<`, `>`, `\>`, `(`, `\(`, `)`, `\)`,
+	`#`, `\#`, `+`, `\+`, `-`, `\-`, `!`, `\!`, `|`, `\|`,
+)
+
+// exportTemplateFuncs are the helper functions available to export templates
+var exportTemplateFuncs = template.FuncMap{
+	// md escapes Markdown special characters
+	"md": markdownEscaper.Replace,
+	// date formats a time.Time or *time.Time with a Go layout; zero/nil gives ""
+	"date": func(layout string, t any) string {
+		switch v := t.(type) {
+		case time.Time:
+			if !v.IsZero() {
+				return v.Format(layout)
+			}
+		case *time.Time:
+			if v != nil && !v.IsZero() {
+				return v.Format(layout)
+			}
+		}
+		return ""
+	},
+	// checklist renders a ball's acceptance criteria as a Markdown checklist,
+	// checked when the ball is complete
+	"checklist": func(ball *session.Ball) string {
+		mark := " "
+		if ball.State == session.StateComplete {
+			mark = "x"
+		}
+		var b strings.Builder
+		for _, ac := range ball.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- [%s] %s\n", mark, ac)
+		}
+		return b.String()
+	},
+	// inSession returns the balls tagged with a session ID
+	"inSession": func(sessionID string, balls []*session.Ball) []*session.Ball {
+		matched := make([]*session.Ball, 0)
+		for _, ball := range balls {
+			if ballHasTag(ball, sessionID) {
+				matched = append(matched, ball)
+			}
+		}
+		return matched
+	},
+	// inState returns the balls in a state
+	"inState": func(state string, balls []*session.Ball) []*session.Ball {
+		matched := make([]*session.Ball, 0)
+		for _, ball := range balls {
+			if string(ball.State) == state {
+				matched = append(matched, ball)
+			}
+		}
+		return matched
+	},
+	"join":  func(sep string, values []string) string { return strings.Join(values, sep) },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	// indent prefixes every line of s with n spaces
+	"indent": func(n int, s string) string {
+		pad := strings.Repeat(" ", n)
+		return pad + strings.ReplaceAll(strings.TrimRight(s, "\n"), "\n", "\n"+pad)
+	},
+	// default returns def when value is empty
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+}
+
+// exportTemplate renders balls through a user-supplied Go text/template file
+func exportTemplate(projectDir, templatePath, sessionID string, balls []*session.Ball) ([]byte, error) {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := parseExportTemplate(templatePath, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	data := TemplateExportData{
+		Balls:       balls,
+		Sessions:    make([]*session.JuggleSession, 0),
+		GeneratedAt: time.Now(),
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+	if sessions, err := sessionStore.ListSessions(); err == nil {
+		data.Sessions = sessions
+	}
+	if sessionID != "" && sessionID != "all" {
+		if sess, err := sessionStore.LoadSession(sessionID); err == nil {
+			data.Session = sess
+		} else {
+			data.Session = &session.JuggleSession{ID: sessionID}
+		}
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return []byte(buf.String()), nil
+}
+
+// parseExportTemplate parses an export template with the helper functions
+func parseExportTemplate(name, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(exportTemplateFuncs).Parse(content)
+	if err != nil {
+		return nil, validationErrorf("invalid template %s: %v", name, err)
+	}
+	return tmpl, nil
+}
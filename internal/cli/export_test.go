@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ohare93/juggle/internal/session"
 )
@@ -312,3 +313,163 @@ func TestExportRalph_OnlySessionACs(t *testing.T) {
 		t.Error("expected output to contain 'Session-Level Requirements' header")
 	}
 }
+
+// TestExportAgent_ResolvesArchivedDependencies tests that dependencies on archived
+// balls show as complete and don't hold up dependent balls
+func TestExportAgent_ResolvesArchivedDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".juggle"), 0755); err != nil {
+		t.Fatalf("failed to create .juggle dir: %v", err)
+	}
+
+	ballStore, err := session.NewStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create ball store: %v", err)
+	}
+
+	archivedDep, _ := session.NewBall(tmpDir, "Finished groundwork", session.PriorityMedium)
+	archivedDep.SetState(session.StateComplete)
+	activeDep, _ := session.NewBall(tmpDir, "Still pending elsewhere", session.PriorityMedium)
+	waitingBall, _ := session.NewBall(tmpDir, "Waits on pending work", session.PriorityMedium)
+	waitingBall.SetDependencies([]string{activeDep.ID})
+	readyBall, _ := session.NewBall(tmpDir, "Builds on archived work", session.PriorityMedium)
+	readyBall.SetDependencies([]string{archivedDep.ID})
+
+	for _, ball := range []*session.Ball{archivedDep, activeDep, waitingBall, readyBall} {
+		if err := ballStore.AppendBall(ball); err != nil {
+			t.Fatalf("failed to save ball: %v", err)
+		}
+	}
+	if err := ballStore.ArchiveBall(archivedDep); err != nil {
+		t.Fatalf("failed to archive ball: %v", err)
+	}
+
+	output, err := exportAgent(tmpDir, "deps-session", []*session.Ball{waitingBall, readyBall}, false, false)
+	if err != nil {
+		t.Fatalf("failed to export Agent: %v", err)
+	}
+	outputStr := string(output)
+
+	if !strings.Contains(outputStr, archivedDep.ID+" (complete (archived))") {
+		t.Errorf("expected archived dependency to be shown as complete (archived), got:\n%s", outputStr)
+	}
+	if !strings.Contains(outputStr, activeDep.ID+" (pending)") {
+		t.Errorf("expected active dependency to show its state, got:\n%s", outputStr)
+	}
+
+	// The ball whose dependency was archived is ready, so it sorts first
+	readyPos := strings.Index(outputStr, "Builds on archived work")
+	waitingPos := strings.Index(outputStr, "Waits on pending work")
+	if readyPos == -1 || waitingPos == -1 || readyPos > waitingPos {
+		t.Errorf("expected ball with archived dependency before ball with pending dependency (ready=%d, waiting=%d)", readyPos, waitingPos)
+	}
+}
+
+// TestExportTemplate tests rendering balls through a user-defined template
+func TestExportTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionStore, err := session.NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("auth", "Login work"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	done, _ := session.NewBall(tmpDir, "Fix *login* bug", session.PriorityHigh)
+	done.AddTag("auth")
+	done.AcceptanceCriteria = []string{"Errors shown"}
+	completedAt := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	done.State = session.StateComplete
+	done.CompletedAt = &completedAt
+	open, _ := session.NewBall(tmpDir, "Write docs", session.PriorityLow)
+
+	templatePath := filepath.Join(tmpDir, "report.tmpl")
+	content := `{{.Session.Description}}
+{{range inSession "auth" .Balls}}## {{md .Title}} ({{date "2006" .CompletedAt}})
+{{checklist .}}{{end}}{{range inState "pending" .Balls}}TODO {{upper .Title}}
+{{end}}{{len .Sessions}} session(s)`
+	if err := os.WriteFile(templatePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	output, err := exportTemplate(tmpDir, templatePath, "auth", []*session.Ball{done, open})
+	if err != nil {
+		t.Fatalf("failed to export template: %v", err)
+	}
+	got := string(output)
+	for _, want := range []string{
+		"Login work\n",
+		`## Fix \*login\* bug (2026)`,
+		"- [x] Errors shown",
+		"TODO WRITE DOCS",
+		"1 session(s)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	if err := os.WriteFile(templatePath, []byte("{{range .Balls}"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	_, err = exportTemplate(tmpDir, templatePath, "", nil)
+	if err == nil || ClassifyError(err).Code != CodeValidation {
+		t.Errorf("expected validation error for invalid template, got %v", err)
+	}
+}
+
+// TestExportAgent_PromptTemplates tests that the agent prompt is arranged by
+// the project's template, and by a session's own template over it
+func TestExportAgent_PromptTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := session.NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	if _, err := store.CreateSession("auth", "Authentication"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	ball, _ := session.NewBall(tmpDir, "Login page", session.PriorityMedium)
+	ball.AddTag("auth")
+	balls := []*session.Ball{ball}
+
+	builtIn, err := exportAgent(tmpDir, "auth", balls, false, false)
+	if err != nil {
+		t.Fatalf("failed to export Agent: %v", err)
+	}
+	if !strings.HasPrefix(string(builtIn), "<context>\n# Authentication\n\n</context>\n\n<session>\nauth\n</session>\n") ||
+		!strings.HasSuffix(string(builtIn), "</instructions>\n") {
+		t.Errorf("expected the built-in skeleton's layout, got:\n%s", builtIn)
+	}
+
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create prompts dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+	}
+	write(agentPromptTemplatePath(tmpDir, ""), "<balls>\n{{.Balls}}</balls>\n{{range .BallList}}{{.Title}}{{end}}\n\n\n")
+	output, err := exportAgent(tmpDir, "auth", balls, false, false)
+	if err != nil {
+		t.Fatalf("failed to export Agent: %v", err)
+	}
+	if !strings.HasPrefix(string(output), "<balls>\n## ") || !strings.HasSuffix(string(output), "</balls>\nLogin page\n") {
+		t.Errorf("expected the project template with one trailing newline, got:\n%q", output)
+	}
+
+	write(agentPromptTemplatePath(tmpDir, "auth"), "Session {{.SessionID}}: {{.Session.Description}}")
+	if output, err = exportAgent(tmpDir, "auth", balls, false, false); err != nil || string(output) != "Session auth: Authentication\n" {
+		t.Errorf("expected the session's template, got %q, %v", output, err)
+	}
+	if output, err = exportAgent(tmpDir, "other", balls, false, false); err != nil || !strings.HasPrefix(string(output), "<balls>") {
+		t.Errorf("expected other sessions to keep the project template, got %q, %v", output, err)
+	}
+
+	write(agentPromptTemplatePath(tmpDir, "auth"), "{{.Missing}}")
+	if _, err := exportAgent(tmpDir, "auth", balls, false, false); err == nil {
+		t.Error("expected an error for a template using an unknown field")
+	}
+}
@@ -102,7 +102,7 @@ func runHistory(cmd *cobra.Command, args []string) error {
 	// Parse priority
 	if historyPriority != "" {
 		if !session.ValidatePriority(historyPriority) {
-			return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", historyPriority)
+			return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", historyPriority)
 		}
 		query.Priority = session.Priority(historyPriority)
 	}
@@ -111,7 +111,7 @@ func runHistory(cmd *cobra.Command, args []string) error {
 	if historyAfter != "" {
 		t, err := time.Parse("2006-01-02", historyAfter)
 		if err != nil {
-			return fmt.Errorf("invalid date format for --after (use YYYY-MM-DD): %w", err)
+			return validationErrorf("invalid date format for --after (use YYYY-MM-DD): %w", err)
 		}
 		query.CompletedAfter = &t
 	}
@@ -119,7 +119,7 @@ func runHistory(cmd *cobra.Command, args []string) error {
 	if historyBefore != "" {
 		t, err := time.Parse("2006-01-02", historyBefore)
 		if err != nil {
-			return fmt.Errorf("invalid date format for --before (use YYYY-MM-DD): %w", err)
+			return validationErrorf("invalid date format for --before (use YYYY-MM-DD): %w", err)
 		}
 		// Set to end of day
 		endOfDay := t.Add(24*time.Hour - time.Second)
@@ -0,0 +1,391 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// hookTimeout bounds how long a hook's command or POST may take
+var hookTimeout = 10 * time.Second
+
+// hookQueue runs hooks one at a time on a background worker, so a slow hook
+// never holds up the store write or agent run that fired it
+var hookQueue struct {
+	mu      sync.Mutex
+	runs    []tui.HookRun
+	working bool
+	pending sync.WaitGroup
+	tuiRuns chan tui.HookRun // Set while the TUI is open; its update loop runs hooks instead
+}
+
+var (
+	configHooksCommand string
+	configHooksURL     string
+)
+
+// configHooksCmd is the parent command for the project's event hooks
+var configHooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage commands and URLs run on ball and agent events (project)",
+	Long: `Manage the project's hooks, stored under "hooks" in .juggle/config.json.
+
+A hook is a shell command or a URL. Commands run in the project directory
+with the event payload as JSON on stdin; URLs get the payload POSTed as JSON.
+The payload's "text" field is a one-line summary, so a Slack incoming webhook
+URL posts it as-is. A failing hook is a warning and never fails the command. Hooks run in the
+background, each for at most 10 seconds; a command waits for its hooks before
+exiting.
+
+Events:
+  on_ball_complete    A ball was marked complete
+  on_ball_blocked     A ball was marked blocked
+  on_agent_blocked    An agent run ended blocked
+  on_agent_finished   An agent run ended, however it ended
+
+Commands run with JUGGLE_EVENT, JUGGLE_PROJECT and JUGGLE_HOOK_TEXT set, plus
+JUGGLE_BALL and JUGGLE_BALL_TITLE for ball events and JUGGLE_SESSION and
+JUGGLE_RUN_RESULT for agent events.
+
+Commands:
+  config hooks list                          List hooks by event
+  config hooks add <event> --url URL         POST the payload to a URL
+  config hooks add <event> --command CMD     Run a shell command
+  config hooks test <event>                  Send a test payload to the event's hooks
+  config hooks clear [event]                 Remove the event's hooks (or all hooks)
+
+Examples:
+  juggle config hooks add on_agent_blocked --url https://hooks.slack.com/services/T000/B000/XXXX
+  juggle config hooks add on_ball_complete --command 'jq -r .text >> ~/juggle.log'`,
+	RunE: runConfigHooksList,
+}
+
+var configHooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hooks by event",
+	RunE:  runConfigHooksList,
+}
+
+var configHooksAddCmd = &cobra.Command{
+	Use:   "add <event>",
+	Short: "Add a command or URL hook for an event",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigHooksAdd,
+}
+
+var configHooksTestCmd = &cobra.Command{
+	Use:   "test <event>",
+	Short: "Send a test payload to an event's hooks",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigHooksTest,
+}
+
+var configHooksClearCmd = &cobra.Command{
+	Use:   "clear [event]",
+	Short: "Remove an event's hooks, or every hook",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runConfigHooksClear,
+}
+
+func init() {
+	configHooksAddCmd.Flags().StringVar(&configHooksCommand, "command", "", "Shell command to run with the payload on stdin")
+	configHooksAddCmd.Flags().StringVar(&configHooksURL, "url", "", "URL to POST the payload to")
+
+	configHooksCmd.AddCommand(configHooksListCmd)
+	configHooksCmd.AddCommand(configHooksAddCmd)
+	configHooksCmd.AddCommand(configHooksTestCmd)
+	configHooksCmd.AddCommand(configHooksClearCmd)
+
+	configCmd.AddCommand(configHooksCmd)
+
+	session.SetStateChangeHandler(runBallHooks)
+}
+
+func runConfigHooksList(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	hooks, err := session.GetAllProjectHooks(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load hooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		fmt.Println("No hooks configured.")
+		fmt.Println("\nAdd one with: juggle config hooks add <event> --url URL")
+		return nil
+	}
+
+	for _, event := range session.HookEvents {
+		if len(hooks[event]) == 0 {
+			continue
+		}
+		fmt.Printf("%s:\n", event)
+		for _, hook := range hooks[event] {
+			fmt.Printf("  %s\n", hook)
+		}
+	}
+	return nil
+}
+
+func runConfigHooksAdd(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	event := session.HookEvent(args[0])
+	hook := session.Hook{Command: configHooksCommand, URL: configHooksURL}
+	if err := session.AddProjectHook(cwd, event, hook); err != nil {
+		return validationErrorf("%v", err)
+	}
+	fmt.Printf("Added %s hook: %s\n", event, hook)
+	return nil
+}
+
+func runConfigHooksTest(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	event := session.HookEvent(args[0])
+	if err := session.ValidateHookEvent(event); err != nil {
+		return validationErrorf("%v", err)
+	}
+	hooks, err := session.GetProjectHooks(cwd, event)
+	if err != nil {
+		return fmt.Errorf("failed to load hooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		return validationErrorf("no hooks configured for %s", event)
+	}
+
+	payload := &session.HookPayload{
+		Event:   event,
+		Text:    fmt.Sprintf("juggle: test of the %s hook", event),
+		Project: cwd,
+		Time:    time.Now(),
+	}
+	failed := 0
+	for _, hook := range hooks {
+		if err := runHook(cwd, hook, payload); err != nil {
+			fmt.Printf("✗ %s: %v\n", hook, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✓ %s\n", hook)
+	}
+	if failed > 0 {
+		return validationErrorf("%d of %d hook(s) failed", failed, len(hooks))
+	}
+	return nil
+}
+
+func runConfigHooksClear(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	var event session.HookEvent
+	if len(args) > 0 {
+		event = session.HookEvent(args[0])
+	}
+	if err := session.ClearProjectHooks(cwd, event); err != nil {
+		return validationErrorf("%v", err)
+	}
+	if event == "" {
+		fmt.Println("Cleared all hooks.")
+	} else {
+		fmt.Printf("Cleared %s hooks.\n", event)
+	}
+	return nil
+}
+
+// runBallHooks queues the project's hooks for a ball's state change
+func runBallHooks(projectDir string, before, after *session.Ball) {
+	event, ok := session.BallHookEvent(before, after)
+	if !ok {
+		return
+	}
+	queueHooks(projectDir, session.NewBallHookPayload(event, projectDir, after))
+}
+
+// runAgentHooks queues the project's hooks for a finished agent run
+func runAgentHooks(run *session.AgentRunRecord) {
+	if run.Result == "blocked" {
+		queueHooks(run.ProjectDir, session.NewAgentHookPayload(session.HookAgentBlocked, run))
+	}
+	queueHooks(run.ProjectDir, session.NewAgentHookPayload(session.HookAgentFinished, run))
+}
+
+// queueHooks hands the hooks for the payload's event to the TUI while it's
+// open, and to the background worker otherwise
+func queueHooks(projectDir string, payload *session.HookPayload) {
+	run := func() []error { return runHooks(projectDir, payload) }
+
+	hookQueue.mu.Lock()
+	defer hookQueue.mu.Unlock()
+	if hookQueue.tuiRuns != nil {
+		select {
+		case hookQueue.tuiRuns <- run:
+			return
+		default:
+			// The TUI is behind, so the worker takes this one
+		}
+	}
+	addHookRun(run)
+}
+
+// addHookRun queues hooks for the background worker, starting it if it's
+// idle. The caller holds hookQueue.mu.
+func addHookRun(run tui.HookRun) {
+	hookQueue.pending.Add(1)
+	hookQueue.runs = append(hookQueue.runs, run)
+	if !hookQueue.working {
+		hookQueue.working = true
+		go workHookQueue()
+	}
+}
+
+// workHookQueue runs queued hooks until there are none left. Failures are
+// warnings: hooks never fail the command that fired them.
+func workHookQueue() {
+	for {
+		hookQueue.mu.Lock()
+		if len(hookQueue.runs) == 0 {
+			hookQueue.working = false
+			hookQueue.mu.Unlock()
+			return
+		}
+		run := hookQueue.runs[0]
+		hookQueue.runs = hookQueue.runs[1:]
+		hookQueue.mu.Unlock()
+
+		for _, err := range run() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		hookQueue.pending.Done()
+	}
+}
+
+// waitForHooks waits for the background worker to run the queued hooks
+func waitForHooks() {
+	hookQueue.pending.Wait()
+}
+
+// WaitForHooksForTest waits for the hooks queued so far to run
+func WaitForHooksForTest() {
+	waitForHooks()
+}
+
+// SetHookTimeoutForTest replaces how long a hook may take and returns a
+// function that restores it
+func SetHookTimeoutForTest(timeout time.Duration) func() {
+	previous := hookTimeout
+	hookTimeout = timeout
+	return func() { hookTimeout = previous }
+}
+
+// sendHooksToTUI has the TUI run the hooks fired while it's open, so they
+// don't block its update loop and failures show in it rather than on the
+// terminal under it. The returned function hands hooks back to the
+// background worker, with any the TUI didn't get to.
+func sendHooksToTUI(model *tui.Model) func() {
+	runs := make(chan tui.HookRun, 64)
+	model.SetHookRuns(runs)
+	hookQueue.mu.Lock()
+	hookQueue.tuiRuns = runs
+	hookQueue.mu.Unlock()
+
+	return func() {
+		hookQueue.mu.Lock()
+		defer hookQueue.mu.Unlock()
+		hookQueue.tuiRuns = nil
+		for {
+			select {
+			case run := <-runs:
+				addHookRun(run)
+			default:
+				return
+			}
+		}
+	}
+}
+
+// runHooks runs every hook configured for the payload's event, returning
+// the failures
+func runHooks(projectDir string, payload *session.HookPayload) []error {
+	hooks, err := session.GetProjectHooks(projectDir, payload.Event)
+	if err != nil {
+		return []error{fmt.Errorf("failed to load hooks: %w", err)}
+	}
+	var failures []error
+	for _, hook := range hooks {
+		if err := runHook(projectDir, hook, payload); err != nil {
+			failures = append(failures, fmt.Errorf("%s hook failed (%s): %w", payload.Event, hook, err))
+		}
+	}
+	return failures
+}
+
+// runHook sends the payload to one hook
+func runHook(projectDir string, hook session.Hook, payload *session.HookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if hook.URL != "" {
+		return postHook(hook.URL, data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	command := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	// Commands the shell started may keep its output open after it's killed
+	command.WaitDelay = time.Second
+	command.Dir = projectDir
+	command.Stdin = bytes.NewReader(data)
+	command.Env = append(os.Environ(),
+		"JUGGLE_EVENT="+string(payload.Event),
+		"JUGGLE_PROJECT="+payload.Project,
+		"JUGGLE_HOOK_TEXT="+payload.Text,
+	)
+	if payload.Ball != nil {
+		command.Env = append(command.Env, "JUGGLE_BALL="+payload.Ball.ID, "JUGGLE_BALL_TITLE="+payload.Ball.Title)
+	}
+	if payload.Run != nil {
+		command.Env = append(command.Env, "JUGGLE_SESSION="+payload.Run.SessionID, "JUGGLE_RUN_RESULT="+payload.Run.Result)
+	}
+	if out, err := command.CombinedOutput(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %v", hookTimeout)
+		}
+		return fmt.Errorf("%w\n%s", err, out)
+	}
+	return nil
+}
+
+// postHook POSTs the payload to a hook URL; any non-2xx response is an error
+func postHook(url string, data []byte) error {
+	client := &http.Client{Timeout: hookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -28,13 +28,60 @@ var (
 	importGitHubLabel      string
 	importGitHubState      string
 	importGitHubLimit      int
+	importFormat           string
 )
 
-// importCmd is the parent command for import operations
+// importCmd is the parent command for import operations. With --format csv
+// or tsv it loads a file written by 'juggle export' back into balls, and with
+// --source a Todoist, Trello or Jira export.
 var importCmd = &cobra.Command{
-	Use:   "import",
+	Use:   "import [file]",
 	Short: "Import external data into juggle",
-	Long:  `Import external data (like prd.json from ralph) into juggle balls.`,
+	Long: `Import external data (like prd.json from ralph) into juggle balls.
+
+With --format csv or tsv, load a spreadsheet of balls, such as one written by
+'juggle export --format csv' and edited. Reads the file, or stdin for "-".
+The format is taken from a .csv or .tsv extension when --format is omitted.
+
+Columns are matched by header name, in any order:
+  id        Ball to update; leave empty to create a ball in this project
+  title     Ball title (required for new balls)
+  state     pending, in_progress, blocked, complete, or researched
+  priority  low, medium, high, or urgent
+  tags      ";"-separated tags that aren't sessions
+  due       When a recurring ball is next due (YYYY-MM-DD or YYYY-MM-DD HH:MM)
+  session   ";"-separated sessions the ball belongs to
+
+A column left out of the header leaves that field unchanged; an empty tags or
+session cell removes them. Every row is checked before any ball is written.
+
+With --source todoist, trello, or jira, import a JSON export from that
+tracker into the current project:
+  todoist   Sync API dump or REST task list: projects → sessions, sections
+            and labels → tags, p1/p2 → urgent/high, subtasks → criteria
+  trello    Board "Export as JSON": board → session, labels → tags (or a
+            priority when named like one), Doing/Done/Blocked lists → state,
+            other lists → tags, checklist items → criteria
+  jira      Issue search response: project → session, labels and components
+            → tags, priority and status mapped, sub-tasks → criteria
+
+Sessions that don't exist yet are created, the task's reference (todoist#123,
+trello#abc, PROJ-7) is kept as a tag, and tasks whose title matches an
+existing or archived ball are skipped. Use --dry-run to see the mapping first.
+
+Examples:
+  # Bulk-edit balls in a spreadsheet
+  juggle export --format csv --output balls.csv
+  juggle import balls.csv
+
+  # Create balls from a TSV on stdin
+  juggle import --format tsv - < new-balls.tsv
+
+  # Preview, then import, a Trello board
+  juggle import board.json --source trello --dry-run
+  juggle import board.json --source trello`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportTable,
 }
 
 // importRalphCmd imports prd.json user stories as balls
@@ -113,11 +160,81 @@ func init() {
 	importGitHubCmd.Flags().StringVar(&importGitHubState, "state", "open", "Filter by state (open, closed, all)")
 	importGitHubCmd.Flags().IntVar(&importGitHubLimit, "limit", 100, "Maximum number of issues to import")
 
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Import a spreadsheet of balls: csv or tsv")
+	importCmd.Flags().StringVar(&importSource, "source", "", "Import a JSON export from another tracker: todoist, trello, or jira")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "With --source, print how tasks map onto balls without writing them")
+
 	importCmd.AddCommand(importRalphCmd)
 	importCmd.AddCommand(importGitHubCmd)
 	rootCmd.AddCommand(importCmd)
 }
 
+func runImportTable(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && importFormat == "" && importSource == "" {
+		return cmd.Help()
+	}
+	path := "-"
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if importSource != "" {
+		if importFormat != "" {
+			return usageErrorf("--source and --format can't be combined")
+		}
+		return runImportSource(path)
+	}
+	if importDryRun {
+		return usageErrorf("--dry-run only applies to --source imports")
+	}
+	format := importFormat
+	if format == "" {
+		format = tableFormatFor(path)
+	}
+	if format == "" {
+		return usageErrorf("unknown import source %q (use a subcommand, or --format csv|tsv for a spreadsheet)", path)
+	}
+	if format != "csv" && format != "tsv" {
+		return validationErrorf("invalid format: %s (must be csv or tsv)", format)
+	}
+
+	data, err := readTableSource(path)
+	if err != nil {
+		return validationErrorf("failed to read %s: %v", format, err)
+	}
+	rows, err := ParseTable(data, format)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return validationErrorf("no balls found in %s (expected a header row and one row per ball)", format)
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	// Like export, --all reaches the balls of every discovered project
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+	balls, err := session.LoadAllBalls(projects)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	return importTable(rows, cwd, balls)
+}
+
 func runImportRalph(cmd *cobra.Command, args []string) error {
 	prdPath := args[0]
 
@@ -144,7 +261,7 @@ func runImportRalph(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create session store: %w", err)
 		}
 		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
-			return fmt.Errorf("session not found: %s", importSessionID)
+			return session.NewSessionNotFoundError(importSessionID)
 		}
 	}
 
@@ -275,16 +392,16 @@ func runImportGitHub(cmd *cobra.Command, args []string) error {
 
 	// Validate repo format (owner/repo)
 	if !strings.Contains(repo, "/") || strings.Count(repo, "/") != 1 {
-		return fmt.Errorf("invalid repository format: %s (expected: owner/repo)", repo)
+		return validationErrorf("invalid repository format: %s (expected: owner/repo)", repo)
 	}
 	parts := strings.Split(repo, "/")
 	if parts[0] == "" || parts[1] == "" {
-		return fmt.Errorf("invalid repository format: %s (owner and repo cannot be empty)", repo)
+		return validationErrorf("invalid repository format: %s (owner and repo cannot be empty)", repo)
 	}
 
 	// Validate state filter
 	if importGitHubState != "open" && importGitHubState != "closed" && importGitHubState != "all" {
-		return fmt.Errorf("invalid state: %s (must be open, closed, or all)", importGitHubState)
+		return validationErrorf("invalid state: %s (must be open, closed, or all)", importGitHubState)
 	}
 
 	// Get current directory
@@ -300,7 +417,7 @@ func runImportGitHub(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to create session store: %w", err)
 		}
 		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
-			return fmt.Errorf("session not found: %s", importSessionID)
+			return session.NewSessionNotFoundError(importSessionID)
 		}
 	}
 
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/importers"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+var (
+	importSource string
+	importDryRun bool
+)
+
+// runImportSource imports a Todoist, Trello or Jira JSON export from path
+// into the current project
+func runImportSource(path string) error {
+	source, err := importers.ParseSource(importSource)
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+	data, err := readTableSource(path)
+	if err != nil {
+		return validationErrorf("failed to read %s export: %v", source, err)
+	}
+	items, err := importers.Parse(source, data)
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+	if len(items) == 0 {
+		return validationErrorf("no tasks found in %s export", source)
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return importExternalItems(source, items, cwd, importDryRun)
+}
+
+// importExternalItems creates a ball for each item whose title doesn't
+// match an existing or archived ball, creating the sessions they map onto.
+// With dryRun it only prints the mapping.
+func importExternalItems(source importers.Source, items []importers.Item, projectDir string, dryRun bool) error {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := store.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	existingTitles := make(map[string]bool, len(balls))
+	for _, ball := range balls {
+		existingTitles[ball.Title] = true
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sessionIDs := loadSessionIDSet(projectDir)
+
+	if dryRun {
+		fmt.Println("Dry run: nothing will be written.")
+		fmt.Println()
+	}
+
+	var imported, skipped, createdSessions int
+	for _, item := range items {
+		if item.Title == "" {
+			fmt.Printf("Skipped: %s (no title)\n", item.Ref)
+			skipped++
+			continue
+		}
+		if existingTitles[item.Title] {
+			fmt.Printf("Skipped: %s - \"%s\" (already exists)\n", item.Ref, item.Title)
+			skipped++
+			continue
+		}
+		existingTitles[item.Title] = true
+
+		if item.Session != "" && !sessionIDs[item.Session] {
+			sessionIDs[item.Session] = true
+			createdSessions++
+			if dryRun {
+				fmt.Printf("Would create session: %s (from \"%s\")\n", item.Session, item.SessionName)
+			} else {
+				if _, err := sessionStore.CreateSession(item.Session, item.SessionName); err != nil {
+					return fmt.Errorf("failed to create session %s: %w", item.Session, err)
+				}
+				fmt.Printf("Created session: %s (from \"%s\")\n", item.Session, item.SessionName)
+			}
+		}
+
+		if dryRun {
+			fmt.Printf("%s - \"%s\"\n", item.Ref, item.Title)
+			fmt.Printf("  → state %s, priority %s", item.State, item.Priority)
+			if item.Session != "" {
+				fmt.Printf(", session %s", item.Session)
+			}
+			if len(item.Tags) > 0 {
+				fmt.Printf(", tags %s", strings.Join(item.Tags, ", "))
+			}
+			if len(item.AcceptanceCriteria) > 0 {
+				fmt.Printf(", %d acceptance criteria", len(item.AcceptanceCriteria))
+			}
+			fmt.Println()
+			imported++
+			continue
+		}
+
+		ball, err := session.NewBall(projectDir, item.Title, item.Priority)
+		if err != nil {
+			fmt.Printf("Warning: failed to create ball for %s: %v\n", item.Ref, err)
+			continue
+		}
+		ball.Context = item.Context
+		if len(item.AcceptanceCriteria) > 0 {
+			ball.SetAcceptanceCriteria(item.AcceptanceCriteria)
+		}
+		applyTableState(ball, item.State)
+		if item.State == session.StateBlocked {
+			ball.BlockedReason = fmt.Sprintf("Blocked in %s", source)
+		}
+		ball.AddTag(item.Ref)
+		for _, tag := range item.Tags {
+			ball.AddTag(tag)
+		}
+		if item.Session != "" {
+			ball.AddTag(item.Session)
+		}
+
+		if err := store.AppendBall(ball); err != nil {
+			fmt.Printf("Warning: failed to create ball for %s: %v\n", item.Ref, err)
+			continue
+		}
+		imported++
+		fmt.Printf("Imported: %s → %s (%s)\n", item.Ref, ball.ID, ball.State)
+	}
+
+	if dryRun {
+		fmt.Printf("\nDry run: %d would be imported, %d skipped, %d new session(s)\n", imported, skipped, createdSessions)
+		return nil
+	}
+	fmt.Printf("\nImport complete: %d imported, %d skipped, %d new session(s)\n", imported, skipped, createdSessions)
+	return nil
+}
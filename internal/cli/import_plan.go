@@ -0,0 +1,366 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// Patterns for plan lines. Claude CLI renders its todo list with box glyphs
+// (☐ pending, ◼ in progress, ☒/✔ completed), optionally behind a "⎿" gutter.
+var (
+	planTodoGlyphRegex = regexp.MustCompile(`^(?:⎿\s*)?([☐☒☑◻◼□■✔✓])\s+(.+)$`)
+	planCheckboxRegex  = regexp.MustCompile(`^[-*+]\s*\[([xX ~-])\]\s+(.+)$`)
+	planNumberedRegex  = regexp.MustCompile(`^\d+[.)]\s+(.+)$`)
+	planBulletRegex    = regexp.MustCompile(`^[-*+]\s+(.+)$`)
+	planANSIRegex      = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	planEmphasisRegex  = regexp.MustCompile(`^(\*\*|__|~~)(.+)(\*\*|__|~~)$`)
+)
+
+var importPlanNoDeps bool
+
+// Plan step statuses, matching Claude's TodoWrite statuses
+const (
+	PlanStepPending    = "pending"
+	PlanStepInProgress = "in_progress"
+	PlanStepCompleted  = "completed"
+)
+
+// PlanStep is one step of an imported plan or todo list
+type PlanStep struct {
+	Title    string
+	Status   string   // pending, in_progress, or completed
+	Priority string   // Optional: low, medium, high (TodoWrite JSON only)
+	Criteria []string // Nested list items under the step
+}
+
+// planTodo is an item of Claude's TodoWrite JSON
+type planTodo struct {
+	Content  string `json:"content"`
+	Status   string `json:"status"`
+	Priority string `json:"priority"`
+}
+
+// importPlanCmd imports a Claude todo list or markdown plan as balls
+var importPlanCmd = &cobra.Command{
+	Use:   "plan [file]",
+	Short: "Import a Claude todo list or markdown plan as balls",
+	Long: `Import the steps of a plan as juggle balls.
+
+Reads the file, or stdin when the file is omitted or "-", so a plan can be
+pasted straight into the terminal. Accepted formats:
+  - Claude's TodoWrite JSON ({"todos": [...]} or a bare array)
+  - Claude CLI's rendered todo list (☐ pending, ◼ in progress, ☒ done)
+  - Markdown numbered, checkbox, or bullet lists
+
+Mappings:
+  - each top-level item  → a ball (intent)
+  - nested list items    → acceptance criteria of the step above them
+  - ☒ / [x] / completed  → state: complete
+  - ◼ / in_progress      → state: in_progress
+  - everything else      → state: pending
+
+Each step depends on the step before it, so agents work through the plan
+in order (use --no-deps to import independent balls). Steps whose title
+matches an existing or archived ball are skipped, and the next step depends
+on the existing ball instead.
+
+Examples:
+  # Import a saved plan into a session
+  juggle import plan plan.md --session my-feature
+
+  # Paste a todo list from Claude (end with Ctrl-D)
+  juggle import plan -s my-feature
+
+  # Import unrelated tasks without chaining them
+  juggle import plan todos.json --no-deps`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImportPlan,
+}
+
+func init() {
+	importPlanCmd.Flags().StringVarP(&importSessionID, "session", "s", "", "Session ID to tag imported balls with")
+	importPlanCmd.Flags().BoolVar(&importPlanNoDeps, "no-deps", false, "Don't make each step depend on the previous one")
+
+	importCmd.AddCommand(importPlanCmd)
+}
+
+func runImportPlan(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return validationErrorf("failed to read plan: %w", err)
+	}
+
+	steps := ParsePlan(string(data))
+	if len(steps) == 0 {
+		return validationErrorf("no plan steps found (expected a todo list, TodoWrite JSON, or markdown list)")
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	// Validate session exists if specified
+	if importSessionID != "" {
+		sessionStore, err := session.NewSessionStore(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to create session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(importSessionID); err != nil {
+			return session.NewSessionNotFoundError(importSessionID)
+		}
+	}
+
+	return ImportPlanSteps(steps, cwd, importSessionID, !importPlanNoDeps)
+}
+
+// ParsePlan extracts plan steps from TodoWrite JSON, a rendered Claude todo
+// list, or a markdown list (exported for testing). Headings and prose are
+// ignored; list items nested under a step become its acceptance criteria.
+func ParsePlan(text string) []PlanStep {
+	text = planANSIRegex.ReplaceAllString(text, "")
+	if steps, ok := parsePlanJSON(text); ok {
+		return steps
+	}
+
+	var steps []PlanStep
+	topIndent := -1
+	for _, raw := range strings.Split(text, "\n") {
+		indent := planIndent(raw)
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Claude's todo list is flat, whatever the indentation
+		if m := planTodoGlyphRegex.FindStringSubmatch(line); m != nil {
+			steps = append(steps, PlanStep{Title: cleanPlanTitle(m[2]), Status: planGlyphStatus(m[1])})
+			continue
+		}
+
+		title, status, ok := parsePlanListItem(line)
+		if !ok {
+			continue
+		}
+		if topIndent == -1 || indent < topIndent {
+			topIndent = indent
+		}
+		if indent > topIndent && len(steps) > 0 {
+			last := &steps[len(steps)-1]
+			last.Criteria = append(last.Criteria, title)
+			continue
+		}
+		steps = append(steps, PlanStep{Title: title, Status: status})
+	}
+	return steps
+}
+
+// parsePlanJSON parses TodoWrite JSON, returning false if the text isn't JSON
+func parsePlanJSON(text string) ([]PlanStep, bool) {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+
+	var todos []planTodo
+	if err := json.Unmarshal([]byte(trimmed), &todos); err != nil {
+		var wrapper struct {
+			Todos []planTodo `json:"todos"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &wrapper); err != nil {
+			return nil, false
+		}
+		todos = wrapper.Todos
+	}
+
+	steps := make([]PlanStep, 0, len(todos))
+	for _, todo := range todos {
+		title := cleanPlanTitle(todo.Content)
+		if title == "" {
+			continue
+		}
+		status := strings.ToLower(todo.Status)
+		if status != PlanStepInProgress && status != PlanStepCompleted {
+			status = PlanStepPending
+		}
+		steps = append(steps, PlanStep{Title: title, Status: status, Priority: strings.ToLower(todo.Priority)})
+	}
+	return steps, true
+}
+
+// parsePlanListItem parses a markdown list line into a step title and status
+func parsePlanListItem(line string) (string, string, bool) {
+	if m := planCheckboxRegex.FindStringSubmatch(line); m != nil {
+		status := PlanStepPending
+		switch m[1] {
+		case "x", "X":
+			status = PlanStepCompleted
+		case "~", "-":
+			status = PlanStepInProgress
+		}
+		return cleanPlanTitle(m[2]), status, true
+	}
+	if m := planNumberedRegex.FindStringSubmatch(line); m != nil {
+		return cleanPlanTitle(m[1]), PlanStepPending, true
+	}
+	if m := planBulletRegex.FindStringSubmatch(line); m != nil {
+		return cleanPlanTitle(m[1]), PlanStepPending, true
+	}
+	return "", "", false
+}
+
+// planGlyphStatus maps a Claude todo glyph to a step status
+func planGlyphStatus(glyph string) string {
+	switch glyph {
+	case "☒", "☑", "✔", "✓":
+		return PlanStepCompleted
+	case "◼", "■":
+		return PlanStepInProgress
+	}
+	return PlanStepPending
+}
+
+// planIndent returns the width of a line's leading whitespace (tabs count as 4)
+func planIndent(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// cleanPlanTitle trims whitespace and surrounding markdown emphasis
+func cleanPlanTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if m := planEmphasisRegex.FindStringSubmatch(title); m != nil {
+		title = strings.TrimSpace(m[2])
+	}
+	return title
+}
+
+// normalizePlanTitle returns the form of a title used for duplicate detection
+func normalizePlanTitle(title string) string {
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(title), " ")), ".:")
+}
+
+// ImportPlanSteps creates balls from plan steps (exported for testing).
+// With chain set, each ball depends on the ball for the previous step.
+func ImportPlanSteps(steps []PlanStep, projectDir, sessionID string, chain bool) error {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	archived, err := store.LoadArchivedBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	// Existing work by normalized title. Archived balls are matched for
+	// deduplication but never depended on.
+	existing := make(map[string]*session.Ball)
+	for _, ball := range archived {
+		existing[normalizePlanTitle(ball.Title)] = nil
+	}
+	for _, ball := range balls {
+		existing[normalizePlanTitle(ball.Title)] = ball
+	}
+
+	var imported, skipped int
+	previousID := ""
+
+	for i, step := range steps {
+		key := normalizePlanTitle(step.Title)
+		if match, ok := existing[key]; ok {
+			if match != nil {
+				fmt.Printf("Skipped: step %d - \"%s\" (matches %s)\n", i+1, step.Title, match.ShortID())
+				previousID = match.ID
+			} else {
+				fmt.Printf("Skipped: step %d - \"%s\" (matches an archived ball)\n", i+1, step.Title)
+			}
+			skipped++
+			continue
+		}
+
+		ball, err := session.NewBall(projectDir, step.Title, planStepPriority(step.Priority))
+		if err != nil {
+			fmt.Printf("Warning: failed to create ball for step %d: %v\n", i+1, err)
+			continue
+		}
+
+		if len(step.Criteria) > 0 {
+			ball.SetAcceptanceCriteria(step.Criteria)
+		}
+
+		switch step.Status {
+		case PlanStepCompleted:
+			ball.State = session.StateComplete
+			now := time.Now()
+			ball.CompletedAt = &now
+		case PlanStepInProgress:
+			ball.State = session.StateInProgress
+		default:
+			ball.State = session.StatePending
+		}
+
+		if chain && previousID != "" {
+			ball.AddDependency(previousID)
+		}
+
+		if sessionID != "" {
+			ball.AddTag(sessionID)
+		}
+
+		if err := store.AppendBall(ball); err != nil {
+			fmt.Printf("Warning: failed to create ball for step %d: %v\n", i+1, err)
+			continue
+		}
+		imported++
+		fmt.Printf("Imported: step %d → %s (%s)\n", i+1, ball.ID, ball.State)
+
+		// Later duplicates in the same plan match this ball
+		existing[key] = ball
+		previousID = ball.ID
+	}
+
+	fmt.Printf("\nImport complete: %d imported, %d skipped\n", imported, skipped)
+	return nil
+}
+
+// planStepPriority maps a TodoWrite priority to a ball priority (default medium)
+func planStepPriority(priority string) session.Priority {
+	switch priority {
+	case "low":
+		return session.PriorityLow
+	case "high":
+		return session.PriorityHigh
+	case "urgent":
+		return session.PriorityUrgent
+	}
+	return session.PriorityMedium
+}
@@ -0,0 +1,464 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Columns of the CSV and TSV formats, in export order. Import matches
+// columns by header name, so columns can be reordered or left out.
+const (
+	TableColumnID       = "id"
+	TableColumnTitle    = "title"
+	TableColumnState    = "state"
+	TableColumnPriority = "priority"
+	TableColumnTags     = "tags"    // Tags that aren't sessions, ";"-separated
+	TableColumnDue      = "due"     // When a recurring ball is next due
+	TableColumnSession  = "session" // Sessions the ball is tagged with, ";"-separated
+)
+
+var tableColumns = []string{
+	TableColumnID, TableColumnTitle, TableColumnState, TableColumnPriority,
+	TableColumnTags, TableColumnDue, TableColumnSession,
+}
+
+// tableDueLayout is how due dates are written; import also accepts a bare date
+const tableDueLayout = "2006-01-02 15:04"
+
+// TableRow is one data row of an imported CSV or TSV file. Columns missing
+// from the header leave the ball's field as it is.
+type TableRow struct {
+	Line     int // Line in the file, for error messages
+	ID       string
+	Title    string
+	State    string
+	Priority string
+	Tags     []string
+	Due      string
+	Sessions []string
+	Columns  map[string]bool // Columns present in the header
+}
+
+// tableDelimiter returns the field separator of a table format
+func tableDelimiter(format string) rune {
+	if format == "tsv" {
+		return '\t'
+	}
+	return ','
+}
+
+// tableFormatFor returns the table format of a file from its extension
+func tableFormatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".tsv", ".tab":
+		return "tsv"
+	}
+	return ""
+}
+
+// exportTable writes balls as CSV or TSV with the documented columns.
+// Session tags go in the session column, the rest in tags.
+func exportTable(balls []*session.Ball, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = tableDelimiter(format)
+
+	if err := writer.Write(tableColumns); err != nil {
+		return nil, err
+	}
+
+	sessionsByProject := make(map[string]map[string]bool)
+	for _, ball := range balls {
+		sessionIDs, ok := sessionsByProject[ball.WorkingDir]
+		if !ok {
+			sessionIDs = loadSessionIDSet(ball.WorkingDir)
+			sessionsByProject[ball.WorkingDir] = sessionIDs
+		}
+
+		var tags, sessions []string
+		for _, tag := range ball.Tags {
+			if sessionIDs[tag] {
+				sessions = append(sessions, tag)
+			} else {
+				tags = append(tags, tag)
+			}
+		}
+		due := ""
+		if ball.IsRecurring() {
+			due = ball.Recurrence.DueAt.Local().Format(tableDueLayout)
+		}
+
+		row := []string{
+			ball.ID,
+			ball.Title,
+			string(ball.State),
+			string(ball.Priority),
+			strings.Join(tags, ";"),
+			due,
+			strings.Join(sessions, ";"),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// loadSessionIDSet returns the IDs of a project's sessions.
+// Errors are ignored: without sessions, every tag is a plain tag.
+func loadSessionIDSet(projectDir string) map[string]bool {
+	ids := make(map[string]bool)
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return ids
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return ids
+	}
+	for _, sess := range sessions {
+		ids[sess.ID] = true
+	}
+	return ids
+}
+
+// ParseTable reads the rows of a CSV or TSV file with a header row
+// (exported for testing). Header names are matched case-insensitively;
+// "due date" and "sessions" are accepted too, other columns are ignored.
+func ParseTable(data []byte, format string) ([]TableRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.Comma = tableDelimiter(format)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = format == "tsv" // Spreadsheets don't quote TSV cells
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, validationErrorf("empty %s file (expected a header row: %s)", format, strings.Join(tableColumns, ","))
+	}
+	if err != nil {
+		return nil, validationErrorf("failed to parse %s: %v", format, err)
+	}
+
+	columns := make(map[string]bool)
+	index := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "due date", "due_date":
+			name = TableColumnDue
+		case "sessions":
+			name = TableColumnSession
+		}
+		for _, column := range tableColumns {
+			if name == column {
+				columns[name] = true
+				index[name] = i
+			}
+		}
+	}
+	if !columns[TableColumnID] && !columns[TableColumnTitle] {
+		return nil, validationErrorf("%s header needs an id or title column (columns: %s)", format, strings.Join(tableColumns, ","))
+	}
+
+	var rows []TableRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, validationErrorf("failed to parse %s: %v", format, err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := TableRow{
+			Line:     line,
+			ID:       field(TableColumnID),
+			Title:    field(TableColumnTitle),
+			State:    field(TableColumnState),
+			Priority: field(TableColumnPriority),
+			Tags:     splitTableList(field(TableColumnTags)),
+			Due:      field(TableColumnDue),
+			Sessions: splitTableList(field(TableColumnSession)),
+			Columns:  columns,
+		}
+		if row.ID == "" && row.Title == "" && row.State == "" && row.Priority == "" &&
+			len(row.Tags) == 0 && row.Due == "" && len(row.Sessions) == 0 {
+			continue // Blank spreadsheet row
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// splitTableList splits a ";"-separated cell, dropping empty items
+func splitTableList(cell string) []string {
+	var items []string
+	for _, item := range strings.Split(cell, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTableDue parses a due date cell as local time
+func parseTableDue(cell string) (time.Time, error) {
+	if due, err := time.ParseInLocation(tableDueLayout, cell, time.Local); err == nil {
+		return due, nil
+	}
+	return time.ParseInLocation("2006-01-02", cell, time.Local)
+}
+
+// tableChange is a validated row, ready to be written
+type tableChange struct {
+	row   TableRow
+	ball  *session.Ball // Nil for a new ball
+	state session.BallState
+	due   time.Time
+}
+
+// importTable creates balls from rows without an id and updates the balls
+// whose id is given. Every row is checked before anything is written, so a
+// bad row leaves all balls as they were.
+func importTable(rows []TableRow, projectDir string, balls []*session.Ball) error {
+	sessionsByProject := make(map[string]map[string]bool)
+	sessionIDsFor := func(dir string) map[string]bool {
+		ids, ok := sessionsByProject[dir]
+		if !ok {
+			ids = loadSessionIDSet(dir)
+			sessionsByProject[dir] = ids
+		}
+		return ids
+	}
+
+	changes := make([]*tableChange, 0, len(rows))
+	seen := make(map[string]int)
+	for _, row := range rows {
+		change := &tableChange{row: row}
+		dir := projectDir
+		if row.ID != "" {
+			matches := session.ResolveBallByPrefix(balls, row.ID)
+			if len(matches) != 1 {
+				if len(matches) > 1 {
+					return validationErrorf("line %d: ambiguous ID '%s' matches %d balls", row.Line, row.ID, len(matches))
+				}
+				return notFoundErrorf("line %d: ball not found: %s", row.Line, row.ID)
+			}
+			change.ball = matches[0]
+			dir = change.ball.WorkingDir
+			if line, ok := seen[change.ball.ID]; ok {
+				return validationErrorf("line %d: ball %s is already on line %d", row.Line, change.ball.ShortID(), line)
+			}
+			seen[change.ball.ID] = row.Line
+		}
+
+		if row.Columns[TableColumnTitle] && row.Title == "" {
+			return validationErrorf("line %d: title cannot be empty", row.Line)
+		}
+		if change.ball == nil && row.Title == "" {
+			return validationErrorf("line %d: new balls need a title", row.Line)
+		}
+		if row.State != "" && !session.ValidateBallState(row.State) {
+			return validationErrorf("line %d: invalid state: %s (must be pending, in_progress, blocked, complete, or researched)", row.Line, row.State)
+		}
+		change.state = session.BallState(row.State)
+		if row.Priority != "" && !session.ValidatePriority(row.Priority) {
+			return validationErrorf("line %d: invalid priority: %s (must be low, medium, high, or urgent)", row.Line, row.Priority)
+		}
+		if row.Due != "" {
+			due, err := parseTableDue(row.Due)
+			if err != nil {
+				return validationErrorf("line %d: invalid due date %q (use YYYY-MM-DD or YYYY-MM-DD HH:MM)", row.Line, row.Due)
+			}
+			if change.ball == nil || !change.ball.IsRecurring() {
+				return validationErrorf("line %d: due dates only apply to recurring balls (set one with 'juggle recur set')", row.Line)
+			}
+			change.due = due
+		}
+		sessionIDs := sessionIDsFor(dir)
+		for _, sessionID := range row.Sessions {
+			if !sessionIDs[sessionID] {
+				return validationErrorf("line %d: session not found: %s", row.Line, sessionID)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	stores := make(map[string]*session.Store)
+	storeFor := func(dir string) (*session.Store, error) {
+		if store, ok := stores[dir]; ok {
+			return store, nil
+		}
+		store, err := NewStoreForCommand(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store: %w", err)
+		}
+		stores[dir] = store
+		return store, nil
+	}
+
+	var created, updated, unchanged int
+	for _, change := range changes {
+		row := change.row
+		if change.ball == nil {
+			priority := session.PriorityMedium
+			if row.Priority != "" {
+				priority = session.Priority(row.Priority)
+			}
+			ball, err := session.NewBall(projectDir, row.Title, priority)
+			if err != nil {
+				return fmt.Errorf("line %d: failed to create ball: %w", row.Line, err)
+			}
+			if change.state != "" {
+				applyTableState(ball, change.state)
+			}
+			for _, tag := range append(row.Tags, row.Sessions...) {
+				ball.AddTag(tag)
+			}
+			store, err := storeFor(projectDir)
+			if err != nil {
+				return err
+			}
+			if err := store.AppendBall(ball); err != nil {
+				return fmt.Errorf("line %d: failed to create ball: %w", row.Line, err)
+			}
+			created++
+			fmt.Printf("Created: %s - \"%s\" (%s)\n", ball.ID, ball.Title, ball.State)
+			continue
+		}
+
+		ball := change.ball
+		before := *ball
+		if row.Columns[TableColumnTitle] && row.Title != ball.Title {
+			ball.SetTitle(row.Title)
+		}
+		if row.Priority != "" && session.Priority(row.Priority) != ball.Priority {
+			ball.Priority = session.Priority(row.Priority)
+		}
+		if change.state != "" && change.state != ball.State {
+			applyTableState(ball, change.state)
+		}
+		if !change.due.IsZero() && !change.due.Equal(ball.Recurrence.DueAt.Truncate(time.Minute)) {
+			recurrence := *ball.Recurrence
+			recurrence.DueAt = change.due
+			ball.Recurrence = &recurrence
+		}
+		if row.Columns[TableColumnTags] || row.Columns[TableColumnSession] {
+			sessionIDs := sessionIDsFor(ball.WorkingDir)
+			var tags, sessions []string
+			for _, tag := range ball.Tags {
+				if sessionIDs[tag] {
+					sessions = append(sessions, tag)
+				} else {
+					tags = append(tags, tag)
+				}
+			}
+			if row.Columns[TableColumnTags] {
+				tags = row.Tags
+			}
+			if row.Columns[TableColumnSession] {
+				sessions = row.Sessions
+			}
+			// Keep the ball's tag order unless the tags really changed
+			if newTags := append(tags, sessions...); !sameTags(newTags, ball.Tags) {
+				ball.Tags = []string{}
+				for _, tag := range newTags {
+					ball.AddTag(tag)
+				}
+			}
+		}
+
+		if ball.Title == before.Title && ball.Priority == before.Priority && ball.State == before.State &&
+			sameTags(ball.Tags, before.Tags) && ball.Recurrence == before.Recurrence {
+			unchanged++
+			continue
+		}
+
+		store, err := storeFor(ball.WorkingDir)
+		if err != nil {
+			return err
+		}
+		if ball.State == session.StateComplete && before.State != session.StateComplete {
+			next, err := store.Recur(ball, time.Now())
+			if err != nil {
+				return fmt.Errorf("line %d: failed to schedule the next instance: %w", row.Line, err)
+			}
+			if next != nil {
+				fmt.Printf("Created: %s - \"%s\" (next instance)\n", next.ID, next.Title)
+			}
+		}
+		if err := store.UpdateBall(ball); err != nil {
+			return fmt.Errorf("line %d: failed to update ball: %w", row.Line, err)
+		}
+		updated++
+		fmt.Printf("Updated: %s - \"%s\" (%s)\n", ball.ID, ball.Title, ball.State)
+	}
+
+	fmt.Printf("\nImport complete: %d created, %d updated, %d unchanged\n", created, updated, unchanged)
+	return nil
+}
+
+// sameTags reports whether two tag lists hold the same tags, in any order
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, tag := range a {
+		set[tag] = true
+	}
+	for _, tag := range b {
+		if !set[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyTableState moves a ball to an imported state. Spreadsheet edits are
+// administrative, so any state can follow any other.
+func applyTableState(ball *session.Ball, state session.BallState) {
+	switch state {
+	case session.StateComplete:
+		ball.MarkComplete(ball.CompletionNote)
+	case session.StateResearched:
+		ball.MarkResearched(ball.Output)
+	case session.StateBlocked:
+		ball.ForceSetState(state)
+		ball.BlockedReason = "Blocked in spreadsheet import"
+	default:
+		ball.ForceSetState(state)
+		ball.CompletedAt = nil
+	}
+}
+
+// readTableSource reads an import file, or stdin for "-"
+func readTableSource(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
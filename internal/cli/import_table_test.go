@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	data := "\ufeffID,Title,Notes,Due Date,Sessions\n" +
+		"proj-1,\"Fix login, again\",ignored,2026-11-01,auth; billing\n" +
+		",,,,\n" +
+		",New ball,,,\n"
+	rows, err := ParseTable([]byte(data), "csv")
+	if err != nil {
+		t.Fatalf("ParseTable failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected blank rows skipped, got %d rows", len(rows))
+	}
+	first := rows[0]
+	if first.ID != "proj-1" || first.Title != "Fix login, again" || first.Due != "2026-11-01" ||
+		strings.Join(first.Sessions, ",") != "auth,billing" || first.Line != 2 {
+		t.Errorf("Unexpected first row %+v", first)
+	}
+	if !first.Columns[TableColumnDue] || !first.Columns[TableColumnSession] || first.Columns[TableColumnState] || first.Columns[TableColumnTags] {
+		t.Errorf("Expected only the header's known columns, got %v", first.Columns)
+	}
+	if rows[1].ID != "" || rows[1].Title != "New ball" || rows[1].Line != 4 {
+		t.Errorf("Unexpected second row %+v", rows[1])
+	}
+}
+
+func TestParseTable_TSV(t *testing.T) {
+	rows, err := ParseTable([]byte("title\tstate\ttags\nSay \"hi\"\tblocked\tx;;y\n"), "tsv")
+	if err != nil {
+		t.Fatalf("ParseTable failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Title != `Say "hi"` || rows[0].State != "blocked" || strings.Join(rows[0].Tags, ",") != "x,y" {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+}
+
+func TestParseTable_Errors(t *testing.T) {
+	if _, err := ParseTable(nil, "csv"); err == nil {
+		t.Error("Expected an empty file to be rejected")
+	}
+	if _, err := ParseTable([]byte("state,priority\npending,low\n"), "csv"); err == nil {
+		t.Error("Expected a header without id or title to be rejected")
+	}
+}
+
+func TestTableFormatFor(t *testing.T) {
+	for path, want := range map[string]string{"balls.csv": "csv", "BALLS.TSV": "tsv", "balls.tab": "tsv", "balls.json": "", "-": ""} {
+		if got := tableFormatFor(path); got != want {
+			t.Errorf("tableFormatFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
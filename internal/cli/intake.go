@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/intake"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	intakeAll         bool
+	intakeSession     string
+	intakeServer      string
+	intakeUser        string
+	intakePasswordEnv string
+	intakeMailbox     string
+	intakeInterval    time.Duration
+	intakeOnce        bool
+	intakePlainText   bool
+)
+
+var intakeCmd = &cobra.Command{
+	Use:   "intake",
+	Short: "Triage balls proposed by email and webhooks",
+	Long: `Triage the project's inbox of proposed balls (.juggle/inbox.jsonl).
+
+Emails and webhook payloads don't become balls straight away: they wait in
+the inbox, with where they came from, until you accept or reject them.
+Accepting an item adds it as a pending ball, with its source noted at the
+end of the ball's context.
+
+Items reach the inbox from:
+  juggle serve --intake          POST /api/intake (JSON) and /api/intake/email
+  juggle intake email            A raw email on stdin, e.g. from a mail filter
+  juggle intake poll             Unseen mail in an IMAP mailbox
+
+An email's subject becomes the title, its plain text body the context, and
+a leading "[urgent]" (or [high], [medium], [low]) in the subject sets the
+priority. Repeats (the same Message-ID, or webhook id) are dropped.
+
+Examples:
+  juggle intake list
+  juggle intake accept 3f2a --session backend
+  juggle intake reject --all
+  juggle intake email < bug-report.eml
+  juggle intake poll --server imap.example.com:993 --user bugs@example.com`,
+	Args: cobra.NoArgs,
+}
+
+var intakeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the proposed balls waiting in the inbox",
+	Args:  cobra.NoArgs,
+	RunE:  runIntakeList,
+}
+
+var intakeAcceptCmd = &cobra.Command{
+	Use:   "accept [item-id...]",
+	Short: "Add inbox items as pending balls",
+	RunE:  runIntakeAccept,
+}
+
+var intakeRejectCmd = &cobra.Command{
+	Use:   "reject [item-id...]",
+	Short: "Discard inbox items",
+	RunE:  runIntakeReject,
+}
+
+var intakeEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Add an email read from stdin to the inbox",
+	Long: `Add an email (the raw RFC 5322 message) read from stdin to the inbox.
+
+This lets a mail filter hand bug reports to juggle, e.g. in ~/.forward or
+a procmail recipe:
+  "|cd /path/to/project && juggle intake email"`,
+	Args: cobra.NoArgs,
+	RunE: runIntakeEmail,
+}
+
+var intakePollCmd = &cobra.Command{
+	Use:   "poll",
+	Short: "Add unseen mail from an IMAP mailbox to the inbox",
+	Long: `Poll an IMAP mailbox over TLS and add its unseen messages to the inbox,
+marking them seen once they're in. Repeats are dropped by Message-ID, so a
+poll cut short can simply be run again.
+
+The password is read from the environment variable named by --password-env
+(JUGGLE_IMAP_PASSWORD by default), so it doesn't end up in shell history.
+
+Examples:
+  juggle intake poll --server imap.example.com:993 --user bugs@example.com --once
+  juggle intake poll --server imap.example.com:993 --user bugs@example.com --interval 2m`,
+	Args: cobra.NoArgs,
+	RunE: runIntakePoll,
+}
+
+func init() {
+	intakeAcceptCmd.Flags().BoolVar(&intakeAll, "all", false, "Accept every item in the inbox")
+	intakeAcceptCmd.Flags().StringVarP(&intakeSession, "session", "s", "", "Tag the new balls with this session")
+	intakeRejectCmd.Flags().BoolVar(&intakeAll, "all", false, "Reject every item in the inbox")
+
+	intakePollCmd.Flags().StringVar(&intakeServer, "server", "", "IMAP server as host:port (e.g. imap.example.com:993)")
+	intakePollCmd.Flags().StringVar(&intakeUser, "user", "", "IMAP user name")
+	intakePollCmd.Flags().StringVar(&intakePasswordEnv, "password-env", "JUGGLE_IMAP_PASSWORD", "Environment variable holding the IMAP password")
+	intakePollCmd.Flags().StringVar(&intakeMailbox, "mailbox", "INBOX", "Mailbox to poll")
+	intakePollCmd.Flags().DurationVar(&intakeInterval, "interval", 5*time.Minute, "How often to poll")
+	intakePollCmd.Flags().BoolVar(&intakeOnce, "once", false, "Poll once and exit")
+	intakePollCmd.Flags().BoolVar(&intakePlainText, "plaintext", false, "Connect without TLS (only for local servers)")
+	intakePollCmd.Flags().MarkHidden("plaintext")
+	intakePollCmd.MarkFlagRequired("server")
+	intakePollCmd.MarkFlagRequired("user")
+
+	intakeCmd.AddCommand(intakeListCmd, intakeAcceptCmd, intakeRejectCmd, intakeEmailCmd, intakePollCmd)
+	rootCmd.AddCommand(intakeCmd)
+}
+
+func newIntakeStoreForCommand() (*session.IntakeStore, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	inbox, err := session.NewIntakeStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create intake store: %w", err)
+	}
+	return inbox, nil
+}
+
+func runIntakeList(cmd *cobra.Command, args []string) error {
+	inbox, err := newIntakeStoreForCommand()
+	if err != nil {
+		return err
+	}
+	items, err := inbox.LoadIntake()
+	if err != nil {
+		return err
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(items) == 0 {
+		fmt.Println("The inbox is empty.")
+		return nil
+	}
+	for _, item := range items {
+		fmt.Printf("%s  %-7s %s\n", item.ID, item.Priority, item.Title)
+		fmt.Printf("          via %s, %s\n", item.Source, item.ReceivedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n%d item(s). Accept with 'juggle intake accept <id>', or reject with 'juggle intake reject <id>'.\n", len(items))
+	return nil
+}
+
+// selectIntake returns the inbox items with the given IDs (or all with --all)
+func selectIntake(inbox *session.IntakeStore, ids []string, all bool) ([]*session.IntakeItem, error) {
+	if all {
+		return inbox.LoadIntake()
+	}
+	if len(ids) == 0 {
+		return nil, usageErrorf("specify inbox item IDs or --all")
+	}
+	selected := make([]*session.IntakeItem, 0, len(ids))
+	for _, id := range ids {
+		item, err := inbox.GetIntake(id)
+		if err != nil {
+			return nil, notFoundErrorf("%v", err)
+		}
+		selected = append(selected, item)
+	}
+	return selected, nil
+}
+
+func runIntakeAccept(cmd *cobra.Command, args []string) error {
+	inbox, err := newIntakeStoreForCommand()
+	if err != nil {
+		return err
+	}
+	items, err := selectIntake(inbox, args, intakeAll)
+	if err != nil {
+		return err
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	if intakeSession != "" {
+		sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+		if err != nil {
+			return fmt.Errorf("failed to initialize session store: %w", err)
+		}
+		if _, err := sessionStore.LoadSession(intakeSession); err != nil {
+			return session.NewSessionNotFoundError(intakeSession)
+		}
+	}
+
+	for _, item := range items {
+		ball, err := item.NewBall(store.ProjectDir())
+		if err != nil {
+			return fmt.Errorf("failed to create ball: %w", err)
+		}
+		if intakeSession != "" {
+			ball.AddTag(intakeSession)
+		}
+		if err := store.AppendBall(ball); err != nil {
+			return fmt.Errorf("failed to save ball: %w", err)
+		}
+		if err := inbox.RemoveIntake(item.ID); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Accepted %s as %s: %s\n", item.ID, ball.ShortID(), ball.Title)
+	}
+	return nil
+}
+
+func runIntakeReject(cmd *cobra.Command, args []string) error {
+	inbox, err := newIntakeStoreForCommand()
+	if err != nil {
+		return err
+	}
+	items, err := selectIntake(inbox, args, intakeAll)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := inbox.RemoveIntake(item.ID); err != nil {
+			return err
+		}
+		fmt.Printf("Rejected %s: %s\n", item.ID, item.Title)
+	}
+	return nil
+}
+
+func runIntakeEmail(cmd *cobra.Command, args []string) error {
+	inbox, err := newIntakeStoreForCommand()
+	if err != nil {
+		return err
+	}
+	email, err := intake.ParseEmail(cmd.InOrStdin())
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+	item, err := email.IntakeItem()
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+	added, err := inbox.AddIntake(item)
+	if err != nil {
+		return err
+	}
+	if !added {
+		fmt.Printf("Already in the inbox: %s\n", item.Title)
+		return nil
+	}
+	fmt.Printf("✓ Added %s to the inbox: %s\n", item.ID, item.Title)
+	return nil
+}
+
+func runIntakePoll(cmd *cobra.Command, args []string) error {
+	if !intakeOnce && intakeInterval < time.Second {
+		return validationErrorf("--interval must be at least 1s")
+	}
+	password := os.Getenv(intakePasswordEnv)
+	if password == "" {
+		return validationErrorf("no IMAP password: set %s", intakePasswordEnv)
+	}
+	inbox, err := newIntakeStoreForCommand()
+	if err != nil {
+		return err
+	}
+	config := intake.IMAPConfig{
+		Addr:      intakeServer,
+		User:      intakeUser,
+		Password:  password,
+		Mailbox:   intakeMailbox,
+		PlainText: intakePlainText,
+	}
+
+	poll := func() error {
+		result, err := intake.Poll(config, inbox)
+		if result != nil {
+			for _, item := range result.Added {
+				fmt.Printf("✓ Added %s to the inbox: %s (from %s)\n", item.ID, item.Title, item.Source.From)
+			}
+			for _, skipped := range result.Skipped {
+				fmt.Fprintf(os.Stderr, "Warning: skipped %s\n", skipped)
+			}
+		}
+		return err
+	}
+	if intakeOnce {
+		return poll()
+	}
+
+	fmt.Printf("Polling %s every %s (Ctrl+C to stop)\n", strings.TrimSpace(intakeServer), intakeInterval)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	ticker := time.NewTicker(intakeInterval)
+	defer ticker.Stop()
+	for {
+		// A failed poll is retried next time rather than ending the loop
+		if err := poll(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		select {
+		case <-signals:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,32 +19,62 @@ import (
 // knownCommands maps top-level subcommand names to their subcommands (if any).
 // Used to provide helpful error messages when a ball ID looks like a command.
 var knownCommands = map[string][]string{
-	"agent":    {"run", "refine"},
+	"admin":    {"compact", "fsck"},
+	"agent":    {"run", "refine", "history", "plan-then-run", "parse-check", "signal", "daemon", "status", "export-dataset", "pause", "prompt", "resume", "worktree"},
+	"archive":  {"list", "show"},
+	"attach-transcript": {},
 	"audit":    {},
 	"balls":    {},
+	"board":    {},
+	"brief":    {},
 	"check":    {},
-	"config":   {"ac", "delay", "vcs"},
+	"config":   {"ac", "attention", "delay", "hooks", "http", "icons", "keys", "logs", "ollama", "ownership", "provider", "safe-mode", "schedule", "scrub", "shell", "signals", "sort", "titles", "validate", "vcs", "view"},
 	"delete":   {},
 	"edit":     {},
+	"escalate": {},
+	"estimate": {"list", "accept", "reject", "review"},
 	"export":   {},
 	"history":  {},
-	"import":   {"ralph", "github"},
+	"import":   {"ralph", "github", "plan"},
+	"intake":   {"list", "accept", "reject", "email", "poll"},
+	"link":     {},
+	"lint":     {"titles"},
 	"list":     {},
+	"locks":    {"list", "break"},
+	"merge":    {},
+	"merge-driver": {"install", "conflicts"},
+	"migrate":  {},
+	"milestone": {"create", "list", "assign", "unassign", "report"},
 	"move":     {},
 	"next":     {},
+	"note":     {},
 	"plan":     {},
-	"progress": {"append"},
-	"projects": {"add", "remove"},
+	"redo":     {},
+	"progress": {"append", "show"},
+	"recur":    {"set", "clear", "run"},
+	"reopen":   {},
+	"report":   {"sprint"},
+	"projects": {"list", "add", "remove", "enable", "disable", "move"},
 	"search":   {},
-	"sessions": {"create", "list", "show", "context", "delete", "progress", "edit"},
+	"serve":    {},
+	"mcp":      {},
+	"events":   {},
+	"sessions": {"create", "list", "show", "context", "delete", "progress", "edit", "goal", "archive", "pause", "resume", "targets"},
+	"shell":    {},
 	"show":     {},
 	"start":    {},
 	"status":   {},
+	"store":    {"compact", "fsck"},
 	"sync":     {"ralph"},
-	"tag":      {"add", "rm", "list"},
+	"tag":      {"add", "rm", "list", "apply"},
+	"tests":    {"record", "policy"},
 	"tui":      {},
 	"unarchive": {},
+	"undo":     {},
 	"update":   {},
+	"verify":   {},
+	"watch":    {"add", "rm", "list"},
+	"week":     {"candidates", "add", "rm", "capacity", "session", "review"},
 	"worktree": {"add", "forget", "list", "status"},
 }
 
@@ -97,17 +128,23 @@ func enhanceBallNotFoundError(err error, ballID string, args []string) error {
 		subs := knownCommands[ballID]
 		if len(subs) > 0 {
 			// Command has subcommands - suggest the proper syntax
-			return fmt.Errorf("'%s' is a command, not a ball ID. Did you mean 'juggle %s <subcommand>'? (subcommands: %s)",
+			return usageErrorf("'%s' is a command, not a ball ID. Did you mean 'juggle %s <subcommand>'? (subcommands: %s)",
 				ballID, ballID, strings.Join(subs, ", "))
 		}
 		// Command without subcommands
-		return fmt.Errorf("'%s' is a command, not a ball ID. Did you mean 'juggle %s'?", ballID, ballID)
+		return usageErrorf("'%s' is a command, not a ball ID. Did you mean 'juggle %s'?", ballID, ballID)
 	}
 
 	// Warn about unused arguments if we got extra args after ball ID
 	if len(args) > 1 {
 		unusedArgs := args[1:]
-		return fmt.Errorf("%s\nNote: unused arguments after ball ID: %s", errStr, strings.Join(unusedArgs, " "))
+		cliErr := ClassifyError(err)
+		return &CLIError{
+			Code:    cliErr.Code,
+			Message: fmt.Sprintf("%s\nNote: unused arguments after ball ID: %s", errStr, strings.Join(unusedArgs, " ")),
+			Hint:    cliErr.Hint,
+			Err:     err,
+		}
 	}
 
 	return err
@@ -235,7 +272,7 @@ func listJugglingBalls(cmd *cobra.Command) error {
 		priorityPadded := padRight(priorityStr, 7)
 
 		// Apply styling after padding
-		if ball.WorkingDir == cwd {
+		if session.SamePath(ball.WorkingDir, cwd) {
 			idPadded = cwdHighlight.Render(idPadded)
 		}
 		projectPadded = projectStyle.Render(projectPadded)
@@ -436,7 +473,7 @@ func listAllBalls(cmd *cobra.Command) error {
 				priorityPadded := padRight(priorityStr, 7)
 
 				// Apply styling after padding
-				if ball.WorkingDir == cwd {
+				if session.SamePath(ball.WorkingDir, cwd) {
 					idPadded = cwdHighlight.Render(idPadded)
 				}
 				statePadded = stateStyle.Render(statePadded)
@@ -517,16 +554,16 @@ func findBallByID(ballID string) (*session.Ball, *session.Store, error) {
 	if len(matches) == 0 {
 		// If not found and we're in local mode, suggest using --all
 		if !GlobalOpts.AllProjects {
-			return nil, nil, fmt.Errorf("ball not found in current project: %s (use --all to search all projects)", ballID)
+			return nil, nil, newCLIError(CodeNotFound, fmt.Errorf("ball not found in current project: %s", ballID), "use --all to search all projects")
 		}
-		return nil, nil, fmt.Errorf("ball not found: %s", ballID)
+		return nil, nil, notFoundErrorf("ball not found: %s", ballID)
 	}
 	if len(matches) > 1 {
 		matchingIDs := make([]string, len(matches))
 		for i, m := range matches {
 			matchingIDs[i] = m.ID
 		}
-		return nil, nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+		return nil, nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
 	}
 
 	ball := matches[0]
@@ -540,14 +577,14 @@ func findBallByID(ballID string) (*session.Ball, *session.Store, error) {
 
 func handleBallCommand(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("ball ID required")
+		return usageErrorf("ball ID required")
 	}
 
 	ballID := args[0]
 
 	// Check if this looks like a reversed command (e.g., "run agent" instead of "agent run")
 	if suggestion := suggestCommandSwap(args); suggestion != "" {
-		return fmt.Errorf("%s '%s' is not a valid ball ID", suggestion, ballID)
+		return usageErrorf("%s '%s' is not a valid ball ID", suggestion, ballID)
 	}
 
 	// Special case: unarchive needs to look in archives, not active balls
@@ -559,6 +596,15 @@ func handleBallCommand(cmd *cobra.Command, args []string) error {
 		return handleBallUnarchive(ball, store)
 	}
 
+	// Reopen looks in active balls and then archives
+	if len(args) > 1 && args[1] == "reopen" {
+		ball, store, err := findCompletedBallByID(ballID)
+		if err != nil {
+			return enhanceBallNotFoundError(err, ballID, args)
+		}
+		return handleBallReopen(ball, store, args[2:])
+	}
+
 	// Find ball across all projects
 	ball, store, err := findBallByID(ballID)
 	if err != nil {
@@ -598,7 +644,7 @@ func handleBallCommand(cmd *cobra.Command, args []string) error {
 	case "delete":
 		return handleBallDelete(ball, operationArgs, store)
 	default:
-		return fmt.Errorf("unknown operation: %s", operation)
+		return usageErrorf("unknown operation: %s", operation)
 	}
 }
 
@@ -679,8 +725,18 @@ func setBallComplete(ball *session.Ball, args []string, store *session.Store) er
 		ball.RevisionID = revisionID
 	}
 
+	if err := checkTestsBeforeComplete(ball); err != nil {
+		return err
+	}
+
 	ball.MarkComplete(note)
 
+	// A recurring ball hands its schedule to a fresh instance
+	next, err := store.Recur(ball, time.Now())
+	if err != nil {
+		return err
+	}
+
 	if err := store.Save(ball); err != nil {
 		return fmt.Errorf("failed to save ball: %w", err)
 	}
@@ -692,6 +748,9 @@ func setBallComplete(ball *session.Ball, args []string, store *session.Store) er
 	if ball.RevisionID != "" {
 		fmt.Printf("  Revision: %s\n", ball.RevisionID)
 	}
+	if next != nil {
+		printNextInstance(next, time.Now())
+	}
 
 	// Archive completed ball
 	if err := store.ArchiveBall(ball); err != nil {
@@ -760,9 +819,16 @@ func setBallBlocked(ball *session.Ball, args []string, store *session.Store) err
 
 // getVCSBackendForBall returns the VCS backend for a ball's working directory
 func getVCSBackendForBall(ball *session.Ball) vcs.VCS {
+	return getVCSBackendForProject(ball.WorkingDir)
+}
+
+// getVCSBackendForProject returns the VCS backend for a project, from its
+// settings and what's on disk. Directories outside any repository get the
+// filesystem backend, which snapshots files instead of committing.
+func getVCSBackendForProject(projectDir string) vcs.VCS {
 	globalVCS, _ := session.GetGlobalVCSWithOptions(GetConfigOptions())
-	projectVCS, _ := session.GetProjectVCS(ball.WorkingDir)
-	return vcs.GetBackendForProject(ball.WorkingDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS))
+	projectVCS, _ := session.GetProjectVCS(projectDir)
+	return vcs.GetBackendForProject(projectDir, vcs.VCSType(projectVCS), vcs.VCSType(globalVCS))
 }
 
 // handleBallTag handles tag operations for a ball
@@ -781,7 +847,7 @@ func handleBallTag(ball *session.Ball, args []string, store *session.Store) erro
 	case "rm", "remove":
 		return removeBallTag(ball, subArgs, store)
 	default:
-		return fmt.Errorf("unknown tag command: %s", subCmd)
+		return usageErrorf("unknown tag command: %s", subCmd)
 	}
 }
 
@@ -821,13 +887,13 @@ func addBallTags(ball *session.Ball, tags []string, store *session.Store) error
 // removeBallTag removes a tag
 func removeBallTag(ball *session.Ball, args []string, store *session.Store) error {
 	if len(args) == 0 {
-		return fmt.Errorf("tag name required")
+		return usageErrorf("tag name required")
 	}
 
 	tag := args[0]
 	
 	if !ball.RemoveTag(tag) {
-		return fmt.Errorf("tag not found: %s", tag)
+		return notFoundErrorf("tag not found: %s", tag)
 	}
 
 	if err := store.Save(ball); err != nil {
@@ -849,7 +915,7 @@ func handleBallEdit(ball *session.Ball, args []string, store *session.Store) err
 	switch property {
 	case "intent":
 		if len(args) < 2 {
-			return fmt.Errorf("new intent text required")
+			return usageErrorf("new intent text required")
 		}
 		newIntent := strings.Join(args[1:], " ")
 		ball.SetTitle(newIntent)
@@ -859,12 +925,12 @@ func handleBallEdit(ball *session.Ball, args []string, store *session.Store) err
 			return fmt.Errorf("priority value required (low, medium, high, urgent)")
 		}
 		if !session.ValidatePriority(args[1]) {
-			return fmt.Errorf("invalid priority: %s (valid: low, medium, high, urgent)", args[1])
+			return validationErrorf("invalid priority: %s (valid: low, medium, high, urgent)", args[1])
 		}
 		ball.Priority = session.Priority(args[1])
 
 	default:
-		return fmt.Errorf("unknown property: %s (valid: intent, priority)", property)
+		return usageErrorf("unknown property: %s (valid: intent, priority)", property)
 	}
 	
 	if err := store.Save(ball); err != nil {
@@ -897,7 +963,7 @@ func handleBallUpdate(ball *session.Ball, args []string, store *session.Store) e
 				return fmt.Errorf("--priority requires a value")
 			}
 			if !session.ValidatePriority(args[i+1]) {
-				return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", args[i+1])
+				return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", args[i+1])
 			}
 			ball.Priority = session.Priority(args[i+1])
 			modified = true
@@ -915,7 +981,7 @@ func handleBallUpdate(ball *session.Ball, args []string, store *session.Store) e
 			}
 			newState, ok := stateMap[args[i+1]]
 			if !ok {
-				return fmt.Errorf("invalid state: %s (must be pending|in_progress|blocked|complete)", args[i+1])
+				return validationErrorf("invalid state: %s (must be pending|in_progress|blocked|complete)", args[i+1])
 			}
 			// Check for --reason if setting to blocked
 			if newState == session.StateBlocked {
@@ -935,6 +1001,11 @@ func handleBallUpdate(ball *session.Ball, args []string, store *session.Store) e
 				}
 				fmt.Printf("✓ Updated state: blocked (reason: %s)\n", reason)
 			} else {
+				if newState == session.StateComplete {
+					if err := checkTestsBeforeComplete(ball); err != nil {
+						return err
+					}
+				}
 				if err := ball.SetState(newState); err != nil {
 					return err
 				}
@@ -979,7 +1050,7 @@ func handleBallUpdate(ball *session.Ball, args []string, store *session.Store) e
 			fmt.Printf("✓ Updated tags: %s\n", strings.Join(tags, ", "))
 			i += 2
 		default:
-			return fmt.Errorf("unknown flag: %s", arg)
+			return usageErrorf("unknown flag: %s", arg)
 		}
 	}
 
@@ -1064,6 +1135,7 @@ func editBallTUI(ball *session.Ball, store *session.Store) error {
 	model := tui.NewStandaloneEditModel(store, sessionStore, ball)
 
 	// Run the TUI
+	applyIconConfig()
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/git"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// linkMaxCommits is how many linked commits juggle link lists
+const linkMaxCommits = 10
+
+var (
+	linkBranch string
+	linkClear  bool
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <ball-id>",
+	Short: "Link a ball to a git branch and the commits that reference it",
+	Long: `Bind a ball to the current git branch (or --branch), and collect the commits
+whose messages reference the ball's full ID, on any branch. Reference a ball
+in a commit message like this:
+
+  Add the login page
+
+  juggle: my-app-a1b2c3d4
+
+Commits are collected again whenever the ball is shown with 'juggle show'
+and when the TUI starts, so new ones are picked up without linking again.
+The branch and commit count appear in the ball's details and in
+'juggle show --json' (under "git").
+
+Examples:
+  juggle link a1b2                        # Link to the current branch
+  juggle link a1b2 --branch feature/auth  # Link to another branch
+  juggle link a1b2 --clear                # Remove the branch link`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLink,
+}
+
+func init() {
+	linkCmd.Flags().StringVar(&linkBranch, "branch", "", "Branch to link (default: the current branch)")
+	linkCmd.Flags().BoolVar(&linkClear, "clear", false, "Remove the ball's branch link")
+	rootCmd.AddCommand(linkCmd)
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	if linkClear && linkBranch != "" {
+		return validationErrorf("--clear can't be combined with --branch")
+	}
+
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if linkClear {
+		if ball.Git == nil || ball.Git.Branch == "" {
+			fmt.Printf("Ball %s isn't linked to a branch\n", ball.ShortID())
+			return nil
+		}
+		branch := ball.Git.Branch
+		ball.UnlinkBranch()
+		if err := store.UpdateBall(ball); err != nil {
+			return fmt.Errorf("failed to update ball: %w", err)
+		}
+		fmt.Printf("✓ Ball %s unlinked from branch %s\n", ball.ShortID(), branch)
+		return nil
+	}
+
+	if !git.IsRepo(ball.WorkingDir) {
+		return validationErrorf("%s is not a git repository", ball.WorkingDir)
+	}
+	branch := linkBranch
+	if branch == "" {
+		if branch, err = git.CurrentBranch(ball.WorkingDir); err != nil {
+			return validationErrorf("%v; pass --branch", err)
+		}
+	} else if !git.BranchExists(ball.WorkingDir, branch) {
+		return notFoundErrorf("branch not found: %s", branch)
+	}
+
+	ball.LinkBranch(branch)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+	ball = syncBallCommits(store, ball)
+
+	fmt.Printf("✓ Ball %s linked to branch %s\n", ball.ShortID(), branch)
+	commits := ball.Git.Commits
+	if len(commits) == 0 {
+		fmt.Printf("  No commits reference it yet. Add \"juggle: %s\" to commit messages to link them.\n", ball.ID)
+		return nil
+	}
+	fmt.Printf("  %d commit(s) reference it:\n", len(commits))
+	for i, c := range commits {
+		if i == linkMaxCommits {
+			fmt.Printf("    ... and %d more\n", len(commits)-linkMaxCommits)
+			break
+		}
+		fmt.Printf("    %s %s\n", c.Hash, c.Subject)
+	}
+	return nil
+}
+
+// syncBallCommits collects the commits that reference the project's balls
+// and returns ball with its commits up to date
+func syncBallCommits(store *session.Store, ball *session.Ball) *session.Ball {
+	changed, err := store.SyncGitCommits()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read git commits: %v\n", err)
+		return ball
+	}
+	for _, updated := range changed {
+		if updated.ID == ball.ID {
+			return updated
+		}
+	}
+	return ball
+}
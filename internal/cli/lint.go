@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var lintTitlesFix bool
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check balls against the project's conventions",
+}
+
+var lintTitlesCmd = &cobra.Command{
+	Use:   "titles",
+	Short: "Check ball titles against the project's title rules",
+	Long: `Check active ball titles against the project's title rules (see
+'juggle config titles'), such as a maximum length, the imperative mood, and
+forbidden prefixes.
+
+With --fix, titles are normalized: forbidden prefixes are stripped, the
+leading verb is put in the imperative ("Added" → "Add"), and the first
+letter is capitalized without a trailing period. Titles that are too long
+are reported for a person to shorten.
+
+Exits with code 4 if any title still breaks a rule, so it can gate CI.
+
+Examples:
+  juggle lint titles             # Report titles that break the rules
+  juggle lint titles --fix       # Normalize them
+  juggle lint titles --all       # Every discovered project with rules`,
+	Args: cobra.NoArgs,
+	RunE: runLintTitles,
+}
+
+func init() {
+	lintTitlesCmd.Flags().BoolVar(&lintTitlesFix, "fix", false, "Normalize titles that break the rules")
+
+	lintCmd.AddCommand(lintTitlesCmd)
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLintTitles(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	// Discover projects (respects --all flag)
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	var checked, fixed, failing int
+	for _, projectDir := range projects {
+		rules, err := session.GetProjectTitleRules(projectDir)
+		if err != nil || rules == nil {
+			continue
+		}
+		projectStore, err := NewStoreForCommand(projectDir)
+		if err != nil {
+			continue
+		}
+		balls, err := projectStore.LoadBalls()
+		if err != nil {
+			return fmt.Errorf("failed to load balls from %s: %w", projectDir, err)
+		}
+		checked++
+
+		for _, ball := range balls {
+			if lintTitlesFix {
+				if title := rules.FixTitle(ball.Title); title != ball.Title {
+					fmt.Printf("Fixed %s: %q → %q\n", ball.ShortID(), ball.Title, title)
+					ball.Title = title
+					if err := projectStore.UpdateBall(ball); err != nil {
+						return fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+					}
+					fixed++
+				}
+			}
+
+			warnings := rules.CheckTitle(ball.Title)
+			if len(warnings) == 0 {
+				continue
+			}
+			failing++
+			label := ball.ShortID()
+			if len(projects) > 1 {
+				label += " [" + filepath.Base(projectDir) + "]"
+			}
+			fmt.Printf("%s %s\n", label, ball.Title)
+			for _, warning := range warnings {
+				fmt.Printf("  - %s\n", warning)
+			}
+		}
+	}
+
+	if checked == 0 {
+		fmt.Println("No title rules set. Set them with: juggle config titles set --max-length 72 --imperative")
+		return nil
+	}
+	if lintTitlesFix {
+		fmt.Printf("\n%d title(s) fixed\n", fixed)
+	}
+	if failing > 0 {
+		return validationErrorf("%d title(s) break the title rules", failing)
+	}
+	if !lintTitlesFix {
+		fmt.Println("All titles follow the title rules.")
+	}
+	return nil
+}
+
+// warnTitleRules prints a warning for each title rule of the project the
+// title breaks
+func warnTitleRules(projectDir, title string) {
+	rules, err := session.GetProjectTitleRules(projectDir)
+	if err != nil {
+		return
+	}
+	for _, warning := range rules.CheckTitle(title) {
+		fmt.Fprintf(os.Stderr, "Warning: title %s (see 'juggle lint titles')\n", warning)
+	}
+}
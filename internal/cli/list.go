@@ -1,12 +1,324 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
 
+// defaultListPageSize is the page size used by --page when --limit isn't set
+const defaultListPageSize = 50
+
+var (
+	listTags      string
+	listPriority  string
+	listMilestone string
+	listOpts      ballListOptions
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all sessions (alias for status)",
-	Long:  `List all active sessions. This is an alias for the status command.`,
-	RunE:  runStatus, // Reuse status command
+	Short: "List balls, one per line, for scripting",
+	Long: `List active (non-complete) balls, one per line.
+
+Unlike status, the output is flat and pageable, so it suits scripts. Use
+"juggle archive list" for completed balls.
+
+Columns: ` + strings.Join(ballListColumnNames(), ", ") + `
+
+Examples:
+  juggle list                              # All active balls in this project
+  juggle list --limit 20 --page 2          # Balls 21-40
+  juggle list --columns id,state,title     # Choose columns
+  juggle list --jsonl | jq .title          # One JSON object per line
+  juggle list --all --tags feature
+  juggle list --milestone v1.2`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().StringVar(&listTags, "tags", "", "Filter by tags (comma-separated, OR logic)")
+	listCmd.Flags().StringVar(&listPriority, "priority", "", "Filter by priority (low|medium|high|urgent)")
+	listCmd.Flags().StringVar(&listMilestone, "milestone", "", "Filter by milestone")
+	listOpts.addFlags(listCmd, 0, "id,state,priority,title")
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	offset, limit, err := listOpts.window()
+	if err != nil {
+		return err
+	}
+	columns, err := parseBallListColumns(listOpts.columns)
+	if err != nil {
+		return err
+	}
+	if listPriority != "" && !session.ValidatePriority(listPriority) {
+		return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", listPriority)
+	}
+	var tags []string
+	if listTags != "" {
+		for _, tag := range strings.Split(listTags, ",") {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+	}
+
+	projects, err := listProjects()
+	if err != nil {
+		return err
+	}
+	balls, err := session.LoadAllBalls(projects)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	w := newBallListWriter(os.Stdout, columns, listOpts.jsonl, cmd.Flags().Changed("columns"))
+	matched := 0
+	for _, ball := range balls {
+		if ball.State == session.StateComplete {
+			continue
+		}
+		if listPriority != "" && string(ball.Priority) != listPriority {
+			continue
+		}
+		if len(tags) > 0 && !ballHasAnyTag(ball, tags) {
+			continue
+		}
+		if listMilestone != "" && !strings.EqualFold(ball.Milestone, listMilestone) {
+			continue
+		}
+		if matched >= offset && (limit == 0 || matched < offset+limit) {
+			if err := w.write(ball); err != nil {
+				return err
+			}
+		}
+		matched++
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	more := limit > 0 && matched > offset+limit
+	w.footer(offset, more, fmt.Sprintf(" of %d", matched))
+	return nil
+}
+
+// listProjects returns the projects to list balls from (respects --all)
+func listProjects() ([]string, error) {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover projects: %w", err)
+	}
+	return projects, nil
+}
+
+// ballHasAnyTag returns true if the ball has at least one of the tags
+func ballHasAnyTag(ball *session.Ball, tags []string) bool {
+	for _, tag := range tags {
+		for _, ballTag := range ball.Tags {
+			if ballTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ballListOptions holds the paging and output flags shared by the list commands
+type ballListOptions struct {
+	limit   int
+	offset  int
+	page    int
+	jsonl   bool
+	columns string
+}
+
+// addFlags registers the paging and output flags on cmd
+func (o *ballListOptions) addFlags(cmd *cobra.Command, defaultLimit int, defaultColumns string) {
+	cmd.Flags().IntVar(&o.limit, "limit", defaultLimit, "Maximum number of balls to print (0 = no limit)")
+	cmd.Flags().IntVar(&o.offset, "offset", 0, "Number of balls to skip")
+	cmd.Flags().IntVar(&o.page, "page", 0, fmt.Sprintf("Page to print, 1-based (page size is --limit, or %d)", defaultListPageSize))
+	cmd.Flags().BoolVar(&o.jsonl, "jsonl", false, "Stream one JSON object per line")
+	cmd.Flags().StringVar(&o.columns, "columns", defaultColumns, "Comma-separated columns to print")
+}
+
+// window returns the offset and limit to print, resolving --page
+func (o *ballListOptions) window() (int, int, error) {
+	if o.limit < 0 || o.offset < 0 || o.page < 0 {
+		return 0, 0, usageErrorf("--limit, --offset, and --page must not be negative")
+	}
+	if o.page == 0 {
+		return o.offset, o.limit, nil
+	}
+	if o.offset != 0 {
+		return 0, 0, usageErrorf("--page and --offset can't be combined")
+	}
+	limit := o.limit
+	if limit == 0 {
+		limit = defaultListPageSize
+	}
+	return (o.page - 1) * limit, limit, nil
+}
+
+// ballListColumn is a column that list commands can print
+type ballListColumn struct {
+	name  string
+	value func(ball *session.Ball) string
+}
+
+var ballListColumns = []ballListColumn{
+	{"id", func(b *session.Ball) string { return b.ID }},
+	{"state", func(b *session.Ball) string { return string(b.State) }},
+	{"priority", func(b *session.Ball) string { return string(b.Priority) }},
+	{"title", func(b *session.Ball) string { return b.Title }},
+	{"next", func(b *session.Ball) string { return b.NextAction }},
+	{"checkpoint", func(b *session.Ball) string { return b.Checkpoint }},
+	{"tags", func(b *session.Ball) string { return strings.Join(b.Tags, ",") }},
+	{"deps", func(b *session.Ball) string { return strings.Join(b.DependsOn, ",") }},
+	{"milestone", func(b *session.Ball) string { return b.Milestone }},
+	{"project", func(b *session.Ball) string { return filepath.Base(b.WorkingDir) }},
+	{"created", func(b *session.Ball) string { return b.StartedAt.Format("2006-01-02") }},
+	{"activity", func(b *session.Ball) string { return b.LastActivity.Format("2006-01-02") }},
+	{"completed", func(b *session.Ball) string {
+		if b.CompletedAt == nil {
+			return ""
+		}
+		return b.CompletedAt.Format("2006-01-02")
+	}},
+}
+
+// ballListColumnNames returns the names of the available columns
+func ballListColumnNames() []string {
+	names := make([]string, len(ballListColumns))
+	for i, col := range ballListColumns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// parseBallListColumns parses a comma-separated column list
+func parseBallListColumns(spec string) ([]ballListColumn, error) {
+	var columns []ballListColumn
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, col := range ballListColumns {
+			if col.name == name {
+				columns = append(columns, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, validationErrorf("unknown column %q (valid: %s)", name, strings.Join(ballListColumnNames(), ", "))
+		}
+	}
+	if len(columns) == 0 {
+		return nil, validationErrorf("no columns selected")
+	}
+	return columns, nil
+}
+
+// ballListWriter prints balls as they are produced, either as an aligned
+// table or as JSON lines
+type ballListWriter struct {
+	out     io.Writer
+	columns []ballListColumn
+	jsonl   bool
+	picked  bool // Columns were chosen explicitly, so JSON lines include only them
+	tw      *tabwriter.Writer
+	written int
+}
+
+func newBallListWriter(out io.Writer, columns []ballListColumn, jsonl, picked bool) *ballListWriter {
+	w := &ballListWriter{out: out, columns: columns, jsonl: jsonl, picked: picked}
+	if !jsonl {
+		w.tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	}
+	return w
+}
+
+// write prints one ball. JSON lines are written straight through; table rows
+// are aligned when the writer is flushed.
+func (w *ballListWriter) write(ball *session.Ball) error {
+	if w.jsonl {
+		var data []byte
+		var err error
+		if w.picked {
+			fields := make(map[string]string, len(w.columns))
+			for _, col := range w.columns {
+				fields[col.name] = col.value(ball)
+			}
+			data, err = json.Marshal(fields)
+		} else {
+			data, err = json.Marshal(ball)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode ball %s: %w", ball.ID, err)
+		}
+		w.written++
+		_, err = fmt.Fprintf(w.out, "%s\n", data)
+		return err
+	}
+
+	if w.written == 0 {
+		headers := make([]string, len(w.columns))
+		for i, col := range w.columns {
+			headers[i] = strings.ToUpper(col.name)
+		}
+		fmt.Fprintln(w.tw, strings.Join(headers, "\t"))
+	}
+	values := make([]string, len(w.columns))
+	for i, col := range w.columns {
+		values[i] = col.value(ball)
+	}
+	w.written++
+	_, err := fmt.Fprintln(w.tw, strings.Join(values, "\t"))
+	return err
+}
+
+func (w *ballListWriter) flush() error {
+	if w.tw == nil {
+		return nil
+	}
+	return w.tw.Flush()
+}
+
+// footer reports which balls were shown and how to get the next page.
+// Nothing is printed for JSON lines, which stay machine-readable.
+func (w *ballListWriter) footer(offset int, more bool, total string) {
+	if w.jsonl {
+		return
+	}
+	if w.written == 0 {
+		fmt.Fprintln(w.out, "No balls found.")
+		return
+	}
+	fmt.Fprintf(w.out, "\nShowing %d-%d%s", offset+1, offset+w.written, total)
+	if more {
+		fmt.Fprintf(w.out, " (next: --offset %d)", offset+w.written)
+	}
+	fmt.Fprintln(w.out)
 }
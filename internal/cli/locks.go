@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	locksBreakForce bool
+	locksBreakYes   bool
+)
+
+var locksCmd = &cobra.Command{
+	Use:   "locks",
+	Short: "Show and break agent session locks",
+	Long: `Show and break the locks agent runs take on sessions.
+
+An agent run locks its session (or "all") so no second run starts on it. The
+lock is released when the run ends, and by the OS if it crashes, but a lock
+can still be left behind: held by a process the run started, or claimed
+from another host. Each lock is shown with its state:
+
+  active   Held by a process still running on this host
+  stale    Not held any more, or its process is gone
+  unknown  Held from another host, whose process can't be checked
+
+Examples:
+  juggle locks list                 # Show every session lock
+  juggle locks break my-feature     # Break a stale lock, after confirming
+  juggle locks break all --yes      # Break the lock of 'agent run all'`,
+}
+
+var locksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show session locks with their holder, age and liveness",
+	Args:  cobra.NoArgs,
+	RunE:  runLocksList,
+}
+
+var locksBreakCmd = &cobra.Command{
+	Use:   "break <session>",
+	Short: "Remove a session's lock so a new run can start",
+	Long: `Remove a session's lock files so a new agent run can start on it.
+
+Stale locks are broken after confirming. A lock held by a running agent,
+or from another host, is only broken with --force: the agent keeps
+running, and a new run on the session would work alongside it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLocksBreak,
+}
+
+func init() {
+	locksBreakCmd.Flags().BoolVar(&locksBreakForce, "force", false, "Break the lock even if its agent may still be running")
+	locksBreakCmd.Flags().BoolVarP(&locksBreakYes, "yes", "y", false, "Skip confirmation prompt (for headless mode)")
+
+	locksCmd.AddCommand(locksListCmd, locksBreakCmd)
+	rootCmd.AddCommand(locksCmd)
+}
+
+// lockSessionName returns the name a session's lock is shown and broken by
+func lockSessionName(storageID string) string {
+	if storageID == "_all" {
+		return "all"
+	}
+	return storageID
+}
+
+func runLocksList(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStore(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+	locks, err := sessionStore.ListSessionLocks()
+	if err != nil {
+		return err
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(locks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(locks) == 0 {
+		fmt.Println("No session locks")
+		return nil
+	}
+	now := time.Now()
+	stale := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SESSION\tSTATE\tPID\tHOST\tAGE\tPROCESS")
+	for _, lock := range locks {
+		pid, host, age := "-", "-", "-"
+		if lock.PID > 0 {
+			pid = fmt.Sprint(lock.PID)
+		}
+		if lock.Hostname != "" {
+			host = lock.Hostname
+		}
+		if !lock.StartedAt.IsZero() {
+			age = formatDuration(now.Sub(lock.StartedAt))
+		}
+		process := "unknown"
+		if lock.ProcessRunning != nil {
+			process = "not running"
+			if *lock.ProcessRunning {
+				process = "running"
+			}
+		}
+		if lock.State == session.LockStale {
+			stale++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", lockSessionName(lock.SessionID), lock.State, pid, host, age, process)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if stale > 0 {
+		fmt.Printf("\nBreak a stale lock with: juggle locks break <session>\n")
+	}
+	return nil
+}
+
+func runLocksBreak(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStore(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+	// Locks of deleted sessions can still be broken, so the session itself
+	// isn't looked up
+	lock, err := sessionStore.SessionLockState(sessionStorageID(name))
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		return notFoundErrorf("session %s has no lock", name)
+	}
+
+	holder := ""
+	if lock.PID > 0 {
+		holder = fmt.Sprintf(" (PID %d", lock.PID)
+		if lock.Hostname != "" {
+			holder += " on " + lock.Hostname
+		}
+		holder += ")"
+	}
+	if !locksBreakForce {
+		switch lock.State {
+		case session.LockActive:
+			return validationErrorf("session %s is locked by a running agent%s; stop it, or break the lock anyway with --force", name, holder)
+		case session.LockUnknown:
+			return validationErrorf("session %s is locked from another host%s; make sure its agent has stopped, then break the lock with --force", name, holder)
+		}
+	}
+
+	if !locksBreakYes {
+		confirmed, err := ConfirmSingleKey(fmt.Sprintf("Break the %s lock on session %s%s?", lock.State, name, holder))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := sessionStore.BreakSessionLock(lock.SessionID); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Broke the lock on session %s\n", name)
+	return nil
+}
@@ -0,0 +1,471 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve balls and sessions to MCP clients over stdio",
+	Long: `Serve the current project as a Model Context Protocol (MCP) server over
+stdio, so Claude Desktop and other MCP clients can use juggle as tools.
+
+Messages are JSON-RPC 2.0, one per line on stdin and stdout; anything else
+the server prints goes to stderr. The tools are:
+
+  list_balls        List balls, filtered by session and state
+  show_ball         Show a ball (full ID, short ID, or prefix)
+  create_ball       Create a pending ball, optionally in a session
+  update_ball       Update a ball's title, priority, state, tags, ...
+  list_sessions     List sessions
+  show_session      Show a session with its progress and balls
+  append_progress   Append to a session's progress log
+  create_session    Create a session
+
+Tools work on the project in the directory the server is started in. They
+take and return the same JSON as 'juggle serve', and failures are reported
+as tool errors with the message and hint --json would give.
+
+Example Claude Desktop configuration (claude_desktop_config.json):
+
+  {
+    "mcpServers": {
+      "juggle": {
+        "command": "juggle",
+        "args": ["mcp"],
+        "cwd": "/path/to/project"
+      }
+    }
+  }`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+// mcpProtocolVersions are the MCP revisions the server speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	mcpParseError     = -32700
+	mcpInvalidRequest = -32600
+	mcpMethodNotFound = -32601
+	mcpInvalidParams  = -32602
+)
+
+// mcpMessage is a JSON-RPC request or notification (no ID)
+type mcpMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// mcpResponse is a JSON-RPC response
+type mcpResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+// mcpError is a JSON-RPC protocol error. Tool failures are not protocol
+// errors: they're tool results with isError set.
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpContent is a block of a tool result
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult is the result of tools/call
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpTool is a tool the server offers
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	call func(s *apiServer, args json.RawMessage) (any, error)
+}
+
+// mcpSchema is the input schema of a tool taking an object with properties
+func mcpSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func mcpString(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func mcpEnum(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
+
+func mcpStrings(description string) map[string]any {
+	return map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": description}
+}
+
+// mcpBallProperties are the ball fields create_ball and update_ball accept
+func mcpBallProperties() map[string]any {
+	return map[string]any{
+		"title":               mcpString("Short title of the work"),
+		"context":             mcpString("Background and details for whoever picks the ball up"),
+		"priority":            mcpEnum("Priority", "low", "medium", "high", "urgent"),
+		"state":               mcpEnum("State", "pending", "in_progress", "blocked", "complete", "researched"),
+		"blocked_reason":      mcpString("Why the ball is blocked (required with state blocked)"),
+		"next_action":         mcpString("The next concrete step"),
+		"checkpoint":          mcpString("How far in-progress work has got, to resume from (e.g. tests written)"),
+		"tags":                mcpStrings("Tags, replacing the existing ones on update (session IDs are tags)"),
+		"acceptance_criteria": mcpStrings("Acceptance criteria, replacing the existing ones"),
+		"depends_on":          mcpStrings("IDs of balls this one depends on, replacing the existing ones"),
+	}
+}
+
+// mcpTools are the tools the server offers, in the order they're listed
+var mcpTools = []mcpTool{
+	{
+		Name:        "list_balls",
+		Description: "List the project's balls (units of work), optionally only those in a session or in some states.",
+		InputSchema: mcpSchema(nil, map[string]any{
+			"session":          mcpString("Only balls in this session"),
+			"states":           mcpStrings("Only balls in one of these states (pending, in_progress, blocked, complete, researched)"),
+			"include_archived": map[string]any{"type": "boolean", "description": "Include archived balls"},
+		}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				Session         string   `json:"session"`
+				States          []string `json:"states"`
+				IncludeArchived bool     `json:"include_archived"`
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return s.filterBalls(req.Session, req.States, req.IncludeArchived)
+		},
+	},
+	{
+		Name:        "show_ball",
+		Description: "Show a ball by full ID, short ID or ID prefix, including archived balls.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id": mcpString("Ball ID"),
+		}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				ID string `json:"id"`
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			ball, _, err := s.findBall(req.ID)
+			return ball, err
+		},
+	},
+	{
+		Name:        "create_ball",
+		Description: "Create a pending ball, optionally in a session.",
+		InputSchema: func() map[string]any {
+			properties := mcpBallProperties()
+			properties["session"] = mcpString("Session to add the ball to")
+			return mcpSchema([]string{"title"}, properties)
+		}(),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req apiBallRequest
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return s.addBall(req)
+		},
+	},
+	{
+		Name:        "update_ball",
+		Description: "Update an active ball. Only the fields given are changed; set state to move it through pending, in_progress, blocked and complete.",
+		InputSchema: func() map[string]any {
+			properties := mcpBallProperties()
+			properties["id"] = mcpString("Ball ID")
+			return mcpSchema([]string{"id"}, properties)
+		}(),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				ID string `json:"id"`
+				apiBallRequest
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return s.editBall(req.ID, req.apiBallRequest)
+		},
+	},
+	{
+		Name:        "list_sessions",
+		Description: "List the project's sessions (groups of balls worked on together).",
+		InputSchema: mcpSchema(nil, map[string]any{}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			if err := decodeMCPArgs(args, &struct{}{}); err != nil {
+				return nil, err
+			}
+			sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize session store: %w", err)
+			}
+			return sessionStore.ListSessions()
+		},
+	},
+	{
+		Name:        "show_session",
+		Description: "Show a session with its progress log and active balls.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id": mcpString("Session ID"),
+		}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				ID string `json:"id"`
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return s.sessionDetail(req.ID)
+		},
+	},
+	{
+		Name:        "append_progress",
+		Description: "Append a note to a session's progress log, which agents read at the start of every iteration.",
+		InputSchema: mcpSchema([]string{"session", "text"}, map[string]any{
+			"session": mcpString("Session ID"),
+			"text":    mcpString("Text to append"),
+		}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				Session string `json:"session"`
+				Text    string `json:"text"`
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			progress, err := s.addProgress(req.Session, req.Text)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]string{"session": req.Session, "progress": progress}, nil
+		},
+	},
+	{
+		Name:        "create_session",
+		Description: "Create a session. Balls join it by being tagged with its ID.",
+		InputSchema: mcpSchema([]string{"id"}, map[string]any{
+			"id":          mcpString("Session ID, e.g. auth or frontend"),
+			"description": mcpString("What the session is for"),
+			"context":     mcpString("Background shared with agents working on the session"),
+		}),
+		call: func(s *apiServer, args json.RawMessage) (any, error) {
+			var req struct {
+				ID          string `json:"id"`
+				Description string `json:"description"`
+				Context     string `json:"context"`
+			}
+			if err := decodeMCPArgs(args, &req); err != nil {
+				return nil, err
+			}
+			return s.addSession(req.ID, req.Description, req.Context)
+		},
+	},
+}
+
+// decodeMCPArgs reads a tool's arguments into v
+func decodeMCPArgs(args json.RawMessage, v any) error {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return usageErrorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+// addSession creates a session with an optional description and context
+func (s *apiServer) addSession(id, description, context string) (*session.JuggleSession, error) {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return nil, validationErrorf("invalid session ID: %q", id)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if _, err := sessionStore.LoadSession(id); err == nil {
+		return nil, validationErrorf("session %s already exists", id)
+	}
+	sess, err := sessionStore.CreateSession(id, description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if context != "" {
+		if err := sessionStore.UpdateSessionContext(id, context); err != nil {
+			return nil, fmt.Errorf("failed to set context: %w", err)
+		}
+		sess.Context = context
+	}
+	return sess, nil
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	server, err := newAPIServer(cwd, "")
+	if err != nil {
+		return err
+	}
+
+	// Stdout carries the protocol, so anything else printed goes to stderr
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = out }()
+
+	fmt.Fprintf(os.Stderr, "Serving %s over MCP on stdio\n", server.projectDir)
+	return serveMCP(server, os.Stdin, out)
+}
+
+// ServeMCPForTest serves MCP messages from in to out for projectDir, as
+// `juggle mcp` would over stdio
+func ServeMCPForTest(projectDir string, in io.Reader, out io.Writer) error {
+	server, err := newAPIServer(projectDir, "")
+	if err != nil {
+		return err
+	}
+	return serveMCP(server, in, out)
+}
+
+// serveMCP answers newline-delimited JSON-RPC messages from in until it
+// closes
+func serveMCP(s *apiServer, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), apiMaxBody)
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		response := s.handleMCP(line)
+		if response == nil {
+			continue
+		}
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// handleMCP answers one message, returning nil for notifications
+func (s *apiServer) handleMCP(line []byte) *mcpResponse {
+	var msg mcpMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return mcpFailure(json.RawMessage("null"), mcpParseError, "parse error: "+err.Error())
+	}
+	if len(msg.ID) == 0 {
+		// Notifications (initialized, cancelled, ...) need no reply
+		return nil
+	}
+	if msg.JSONRPC != "2.0" || msg.Method == "" {
+		return mcpFailure(msg.ID, mcpInvalidRequest, "invalid request")
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		version := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return mcpSuccess(msg.ID, map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "juggle", "version": rootCmd.Version},
+			"instructions": "juggle tracks units of work (balls) grouped into sessions for " + s.projectDir +
+				". Balls move pending → in_progress → complete (or blocked with a reason).",
+		})
+	case "ping":
+		return mcpSuccess(msg.ID, map[string]any{})
+	case "tools/list":
+		return mcpSuccess(msg.ID, map[string]any{"tools": mcpTools})
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return mcpFailure(msg.ID, mcpInvalidParams, "invalid params: "+err.Error())
+		}
+		for _, tool := range mcpTools {
+			if tool.Name == params.Name {
+				return mcpSuccess(msg.ID, s.callMCPTool(tool, params.Arguments))
+			}
+		}
+		return mcpFailure(msg.ID, mcpInvalidParams, "unknown tool: "+params.Name)
+	default:
+		return mcpFailure(msg.ID, mcpMethodNotFound, "method not found: "+msg.Method)
+	}
+}
+
+// callMCPTool runs a tool, returning its value as indented JSON or its
+// error as a tool error
+func (s *apiServer) callMCPTool(tool mcpTool, args json.RawMessage) mcpToolResult {
+	value, err := tool.call(s, args)
+	if err != nil {
+		cliErr := ClassifyError(err)
+		text := cliErr.Message
+		if cliErr.Hint != "" {
+			text += "\nHint: " + cliErr.Hint
+		}
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: text}}, IsError: true}
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(data)}}}
+}
+
+func mcpSuccess(id json.RawMessage, result any) *mcpResponse {
+	return &mcpResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func mcpFailure(id json.RawMessage, code int, message string) *mcpResponse {
+	return &mcpResponse{JSONRPC: "2.0", ID: id, Error: &mcpError{Code: code, Message: message}}
+}
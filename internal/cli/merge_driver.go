@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var mergeConflictsClear bool
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs> [<path>]",
+	Short: "Git merge driver for juggle's ball and session files",
+	Long: `Semantically merge juggle files when a .juggle directory is shared through git.
+
+Git runs this as a custom merge driver (see "juggle merge-driver install") with
+the common ancestor, the current version and the other branch's version. The
+merged result is written back over <ours>, as git expects.
+
+Balls (balls.jsonl and the archive) and sessions (session.json) are merged by
+ID, field by field:
+  - A field changed on only one side takes that change
+  - Tags, dependencies and watch globs are merged as sets
+  - last_activity / updated_at take the latest time
+  - A field changed differently on both sides is a conflict: the side with
+    the latest activity wins and the conflict is recorded in
+    .juggle/conflicts.jsonl for review
+  - A ball deleted on one side stays deleted unless the other side changed it
+
+Session progress logs (progress.txt) keep both sides' appended lines.
+
+Recorded conflicts are shown by "juggle status" until cleared with
+"juggle merge-driver conflicts --clear".
+
+Examples:
+  juggle merge-driver install               # Register the driver in this repo
+  juggle merge-driver conflicts             # Review recorded conflicts
+  juggle merge-driver conflicts --clear     # Mark them as reviewed`,
+	Args: cobra.RangeArgs(3, 4),
+	RunE: runMergeDriver,
+}
+
+var mergeDriverInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register the merge driver in .gitattributes and git config",
+	Args:  cobra.NoArgs,
+	RunE:  runMergeDriverInstall,
+}
+
+var mergeDriverConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List conflicts recorded by the merge driver",
+	Args:  cobra.NoArgs,
+	RunE:  runMergeDriverConflicts,
+}
+
+func init() {
+	mergeDriverConflictsCmd.Flags().BoolVar(&mergeConflictsClear, "clear", false, "Clear recorded conflicts after reviewing them")
+
+	mergeDriverCmd.AddCommand(mergeDriverInstallCmd)
+	mergeDriverCmd.AddCommand(mergeDriverConflictsCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) error {
+	basePath, oursPath, theirsPath := args[0], args[1], args[2]
+	path := oursPath
+	if len(args) == 4 {
+		path = args[3]
+	}
+
+	base, err := os.ReadFile(basePath)
+	if err != nil {
+		return fmt.Errorf("failed to read base: %w", err)
+	}
+	ours, err := os.ReadFile(oursPath)
+	if err != nil {
+		return fmt.Errorf("failed to read ours: %w", err)
+	}
+	theirs, err := os.ReadFile(theirsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read theirs: %w", err)
+	}
+
+	var merged []byte
+	var conflicts []session.MergeConflict
+	switch filepath.Base(path) {
+	case "session.json":
+		merged, conflicts, err = session.MergeSessionJSON(base, ours, theirs)
+	case "progress.txt":
+		merged = session.MergeProgress(base, ours, theirs)
+	default:
+		merged, conflicts, err = session.MergeBallsJSONL(base, ours, theirs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(oursPath, merged, 0644); err != nil {
+		return fmt.Errorf("failed to write merge result: %w", err)
+	}
+
+	if len(conflicts) > 0 {
+		for i := range conflicts {
+			conflicts[i].File = path
+		}
+		juggleDir := mergeJuggleDir(path)
+		if err := session.AppendMergeConflicts(juggleDir, conflicts); err != nil {
+			return fmt.Errorf("failed to record conflicts: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "juggle: merged %s with %d conflict(s), recorded in %s\n",
+			path, len(conflicts), filepath.Join(juggleDir, "conflicts.jsonl"))
+	}
+	return nil
+}
+
+// mergeJuggleDir finds the juggle directory that contains path, falling back
+// to the one in the current directory
+func mergeJuggleDir(path string) string {
+	name := GetStoreConfig().JuggleDirName
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		if filepath.Base(dir) == name {
+			return dir
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	return name
+}
+
+// mergeDriverPatterns returns the .gitattributes patterns handled by the driver
+func mergeDriverPatterns() []string {
+	dir := GetStoreConfig().JuggleDirName
+	return []string{
+		dir + "/balls.jsonl",
+		dir + "/archive/balls.jsonl",
+		dir + "/sessions/*/session.json",
+		dir + "/sessions/*/progress.txt",
+	}
+}
+
+func runMergeDriverInstall(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	gitCmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	gitCmd.Dir = cwd
+	out, err := gitCmd.Output()
+	if err != nil {
+		return validationErrorf("not a git repository: %s", cwd)
+	}
+	repoDir := strings.TrimSpace(string(out))
+
+	// Add missing patterns to .gitattributes
+	attrsPath := filepath.Join(repoDir, ".gitattributes")
+	existing, err := os.ReadFile(attrsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		lines[strings.TrimSpace(line)] = true
+	}
+
+	var added []string
+	for _, pattern := range mergeDriverPatterns() {
+		line := pattern + " merge=juggle"
+		if !lines[line] {
+			added = append(added, line)
+		}
+	}
+	if len(added) > 0 {
+		content := string(existing)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += strings.Join(added, "\n") + "\n"
+		if err := os.WriteFile(attrsPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write .gitattributes: %w", err)
+		}
+	}
+
+	settings := [][2]string{
+		{"merge.juggle.name", "juggle semantic merge for balls and sessions"},
+		{"merge.juggle.driver", "juggle merge-driver %O %A %B %P"},
+	}
+	for _, kv := range settings {
+		gitCmd := exec.Command("git", "config", kv[0], kv[1])
+		gitCmd.Dir = repoDir
+		if out, err := gitCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to set %s: %s: %w", kv[0], strings.TrimSpace(string(out)), err)
+		}
+	}
+
+	if len(added) > 0 {
+		fmt.Printf("Added %d pattern(s) to %s\n", len(added), attrsPath)
+	} else {
+		fmt.Printf("%s already up to date\n", attrsPath)
+	}
+	fmt.Println("Registered merge driver \"juggle\" in git config")
+	fmt.Println("\nCommit .gitattributes so the patterns apply for everyone; each clone must run")
+	fmt.Println("\"juggle merge-driver install\" to register the driver locally.")
+	return nil
+}
+
+func runMergeDriverConflicts(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	conflicts, err := store.LoadMergeConflicts()
+	if err != nil {
+		return err
+	}
+
+	if mergeConflictsClear {
+		if err := store.ClearMergeConflicts(); err != nil {
+			return err
+		}
+		if GlobalOpts.JSONOutput {
+			fmt.Printf("{\"cleared\": %d}\n", len(conflicts))
+			return nil
+		}
+		fmt.Printf("Cleared %d merge conflict(s)\n", len(conflicts))
+		return nil
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(conflicts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Println("No merge conflicts recorded.")
+		return nil
+	}
+
+	fmt.Printf("%d merge conflict(s) resolved automatically:\n\n", len(conflicts))
+	for _, c := range conflicts {
+		if c.Field == "" {
+			fmt.Printf("  %s %s: deleted on one side, changed on the other (kept %s)\n", c.File, c.ID, c.Resolution)
+			continue
+		}
+		fmt.Printf("  %s %s.%s (kept %s)\n", c.File, c.ID, c.Field, c.Resolution)
+		fmt.Printf("    base:   %s\n", conflictValue(c.Base))
+		fmt.Printf("    ours:   %s\n", conflictValue(c.Ours))
+		fmt.Printf("    theirs: %s\n", conflictValue(c.Theirs))
+	}
+	fmt.Println("\nReview the kept values, then run: juggle merge-driver conflicts --clear")
+	return nil
+}
+
+// conflictValue formats a raw JSON value for display
+func conflictValue(v json.RawMessage) string {
+	if len(v) == 0 {
+		return "(none)"
+	}
+	return truncate(string(v), 80)
+}
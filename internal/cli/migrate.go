@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var migrateTo string
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Convert the project's active balls to another store backend",
+	Long: `Convert the project's active balls to another store backend, and select it
+in the project config (store_backend in .juggle/config.json).
+
+Backends:
+  jsonl  .juggle/balls.jsonl, rewritten whole on every change (default).
+         Plain text that diffs and merges in git.
+  bolt   .juggle/balls.db, a bbolt database indexed by state, tag and last
+         activity. A change only writes the ball it touches, which keeps big
+         projects fast, but the file is binary and shouldn't be committed.
+
+The old file is moved aside with a .bak suffix rather than deleted. Archived
+balls stay in .juggle/archive/balls.jsonl with either backend.
+
+Examples:
+  juggle migrate --to bolt     # Convert balls.jsonl to balls.db
+  juggle migrate --to jsonl    # Convert back`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "", "Backend to convert to: jsonl or bolt")
+	migrateCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	to := session.StoreBackend(migrateTo)
+	if to == "" {
+		return usageErrorf("--to is required (jsonl or bolt)")
+	}
+	if err := session.ValidateStoreBackend(to); err != nil {
+		return validationErrorf("%v", err)
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	migration, err := session.MigrateStore(cwd, GetStoreConfig(), to)
+	if err != nil {
+		return validationErrorf("balls were not migrated: %v", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(migration, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("✓ Migrated %d ball(s) from %s to %s\n", migration.Balls, migration.From, migration.To)
+	if migration.Backup != "" {
+		fmt.Printf("  Old file kept at %s\n", migration.Backup)
+	}
+	return nil
+}
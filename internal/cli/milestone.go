@@ -0,0 +1,388 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	milestoneDue         string
+	milestoneDescription string
+)
+
+var milestoneCmd = &cobra.Command{
+	Use:   "milestone",
+	Short: "Group balls under target releases or milestones",
+	Long: `Create milestones (target releases like "v1.2"), assign balls to them from
+any session, and report how many of each milestone's balls are done.
+
+A ball belongs to at most one milestone. Completed balls still count toward
+their milestone after they're archived.
+
+With no subcommand, lists the milestones.
+
+Examples:
+  juggle milestone create v1.2 --due 2026-11-30   # Create a milestone
+  juggle milestone assign v1.2 a1b2 c3d4          # Assign balls to it
+  juggle milestone unassign c3d4                  # Take a ball out of its milestone
+  juggle milestone report v1.2                    # Completed vs. remaining
+  juggle list --milestone v1.2                    # Filter lists by milestone`,
+	Args: cobra.NoArgs,
+	RunE: runMilestoneList,
+}
+
+var milestoneCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a milestone",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMilestoneCreate,
+}
+
+var milestoneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List milestones with their progress",
+	Args:  cobra.NoArgs,
+	RunE:  runMilestoneList,
+}
+
+var milestoneAssignCmd = &cobra.Command{
+	Use:   "assign <milestone> <ball-id> [<ball-id>...]",
+	Short: "Assign balls to a milestone",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runMilestoneAssign,
+}
+
+var milestoneUnassignCmd = &cobra.Command{
+	Use:   "unassign <ball-id> [<ball-id>...]",
+	Short: "Remove balls from their milestone",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMilestoneUnassign,
+}
+
+var milestoneReportCmd = &cobra.Command{
+	Use:   "report [milestone]",
+	Short: "Report completed vs. remaining balls per milestone, by session",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runMilestoneReport,
+}
+
+func init() {
+	milestoneCreateCmd.Flags().StringVar(&milestoneDue, "due", "", "Due date (YYYY-MM-DD)")
+	milestoneCreateCmd.Flags().StringVarP(&milestoneDescription, "description", "d", "", "What the milestone delivers")
+
+	milestoneCmd.AddCommand(milestoneCreateCmd)
+	milestoneCmd.AddCommand(milestoneListCmd)
+	milestoneCmd.AddCommand(milestoneAssignCmd)
+	milestoneCmd.AddCommand(milestoneUnassignCmd)
+	milestoneCmd.AddCommand(milestoneReportCmd)
+	rootCmd.AddCommand(milestoneCmd)
+}
+
+// newMilestoneStoreForCommand creates the milestone store for the current project
+func newMilestoneStoreForCommand() (*session.MilestoneStore, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	milestoneStore, err := session.NewMilestoneStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create milestone store: %w", err)
+	}
+	return milestoneStore, nil
+}
+
+// findMilestone returns the named milestone, or a not-found error
+func findMilestone(milestoneStore *session.MilestoneStore, name string) (*session.Milestone, error) {
+	milestone, err := milestoneStore.GetMilestone(name)
+	if err != nil {
+		return nil, err
+	}
+	if milestone == nil {
+		return nil, notFoundErrorf("milestone not found: %s (create it with: juggle milestone create %s)", name, name)
+	}
+	return milestone, nil
+}
+
+// loadMilestoneBalls returns the current project's active and archived
+// balls, and its session IDs
+func loadMilestoneBalls() ([]*session.Ball, []string, error) {
+	_, store, _, err := loadWeekProject()
+	if err != nil {
+		return nil, nil, err
+	}
+	balls, err := store.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(store.ProjectDir(), GetStoreConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	sessionIDs := make([]string, len(sessions))
+	for i, sess := range sessions {
+		sessionIDs[i] = sess.ID
+	}
+	return balls, sessionIDs, nil
+}
+
+// milestoneDueLabel returns "due Nov 30" (or "overdue since Nov 30"), or ""
+func milestoneDueLabel(milestone *session.Milestone) string {
+	if milestone.Due == nil {
+		return ""
+	}
+	if milestone.Due.Before(time.Now().Truncate(24 * time.Hour)) {
+		return "overdue since " + milestone.Due.Format("Jan 2, 2006")
+	}
+	return "due " + milestone.Due.Format("Jan 2, 2006")
+}
+
+func runMilestoneCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := session.ValidateMilestoneName(name); err != nil {
+		return validationErrorf("%v", err)
+	}
+	milestone := &session.Milestone{
+		Name:        name,
+		Description: milestoneDescription,
+		CreatedAt:   time.Now(),
+	}
+	if milestoneDue != "" {
+		due, err := time.ParseInLocation("2006-01-02", milestoneDue, time.Local)
+		if err != nil {
+			return validationErrorf("invalid due date %q (expected YYYY-MM-DD)", milestoneDue)
+		}
+		milestone.Due = &due
+	}
+
+	milestoneStore, err := newMilestoneStoreForCommand()
+	if err != nil {
+		return err
+	}
+	if existing, err := milestoneStore.GetMilestone(name); err != nil {
+		return err
+	} else if existing != nil {
+		return validationErrorf("milestone %s already exists", existing.Name)
+	}
+	if err := milestoneStore.AddMilestone(milestone); err != nil {
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	fmt.Printf("✓ Created milestone %s", milestone.Name)
+	if due := milestoneDueLabel(milestone); due != "" {
+		fmt.Printf(" (%s)", due)
+	}
+	fmt.Printf("\nAssign balls with: juggle milestone assign %s <ball-id>...\n", milestone.Name)
+	return nil
+}
+
+func runMilestoneList(cmd *cobra.Command, args []string) error {
+	milestoneStore, err := newMilestoneStoreForCommand()
+	if err != nil {
+		return err
+	}
+	milestones, err := milestoneStore.LoadMilestones()
+	if err != nil {
+		return err
+	}
+	balls, sessionIDs, err := loadMilestoneBalls()
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*session.MilestoneProgress, len(milestones))
+	for i, milestone := range milestones {
+		reports[i] = session.ReportMilestone(milestone, balls, sessionIDs)
+	}
+
+	if GlobalOpts.JSONOutput {
+		entries := make([]map[string]any, len(reports))
+		for i, report := range reports {
+			entries[i] = map[string]any{
+				"milestone": report.Milestone,
+				"done":      len(report.Done),
+				"total":     report.Total(),
+			}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(milestones) == 0 {
+		fmt.Println("No milestones. Create one with: juggle milestone create <name>")
+		return nil
+	}
+	for _, report := range reports {
+		line := fmt.Sprintf("%s %3d/%-3d done (%d%%)", padRight(report.Milestone.Name, 16), len(report.Done), report.Total(), report.Percent())
+		if due := milestoneDueLabel(report.Milestone); due != "" {
+			line += "  " + due
+		}
+		if report.Milestone.Description != "" {
+			line += "  " + report.Milestone.Description
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runMilestoneAssign(cmd *cobra.Command, args []string) error {
+	milestoneStore, err := newMilestoneStoreForCommand()
+	if err != nil {
+		return err
+	}
+	milestone, err := findMilestone(milestoneStore, args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args[1:] {
+		ball, store, err := findBallByID(id)
+		if err != nil {
+			return err
+		}
+		if ball.Milestone == milestone.Name {
+			fmt.Printf("Ball %s is already in %s\n", ball.ShortID(), milestone.Name)
+			continue
+		}
+		previous := ball.Milestone
+		ball.Milestone = milestone.Name
+		ball.UpdateActivity()
+		if err := store.UpdateBall(ball); err != nil {
+			return fmt.Errorf("failed to update ball: %w", err)
+		}
+		if previous != "" {
+			fmt.Printf("✓ Moved %s from %s to %s\n", ball.ShortID(), previous, milestone.Name)
+		} else {
+			fmt.Printf("✓ Assigned %s to %s\n", ball.ShortID(), milestone.Name)
+		}
+	}
+	return nil
+}
+
+func runMilestoneUnassign(cmd *cobra.Command, args []string) error {
+	for _, id := range args {
+		ball, store, err := findBallByID(id)
+		if err != nil {
+			return err
+		}
+		if ball.Milestone == "" {
+			fmt.Printf("Ball %s isn't in a milestone\n", ball.ShortID())
+			continue
+		}
+		previous := ball.Milestone
+		ball.Milestone = ""
+		ball.UpdateActivity()
+		if err := store.UpdateBall(ball); err != nil {
+			return fmt.Errorf("failed to update ball: %w", err)
+		}
+		fmt.Printf("✓ Removed %s from %s\n", ball.ShortID(), previous)
+	}
+	return nil
+}
+
+func runMilestoneReport(cmd *cobra.Command, args []string) error {
+	milestoneStore, err := newMilestoneStoreForCommand()
+	if err != nil {
+		return err
+	}
+	var milestones []*session.Milestone
+	if len(args) == 1 {
+		milestone, err := findMilestone(milestoneStore, args[0])
+		if err != nil {
+			return err
+		}
+		milestones = []*session.Milestone{milestone}
+	} else if milestones, err = milestoneStore.LoadMilestones(); err != nil {
+		return err
+	}
+	balls, sessionIDs, err := loadMilestoneBalls()
+	if err != nil {
+		return err
+	}
+
+	reports := make([]*session.MilestoneProgress, len(milestones))
+	for i, milestone := range milestones {
+		reports[i] = session.ReportMilestone(milestone, balls, sessionIDs)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("No milestones. Create one with: juggle milestone create <name>")
+		return nil
+	}
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Println()
+		}
+		printMilestoneReport(report)
+	}
+	return nil
+}
+
+// printMilestoneReport prints one milestone's progress, by session, and its balls
+func printMilestoneReport(report *session.MilestoneProgress) {
+	header := "Milestone " + report.Milestone.Name
+	if due := milestoneDueLabel(report.Milestone); due != "" {
+		header += " (" + due + ")"
+	}
+	fmt.Println(header)
+	if report.Milestone.Description != "" {
+		fmt.Printf("  %s\n", report.Milestone.Description)
+	}
+	fmt.Printf("  %d of %d ball(s) done (%d%%), %d remaining\n", len(report.Done), report.Total(), report.Percent(), len(report.Remaining))
+	if report.Total() == 0 {
+		fmt.Printf("  Assign balls with: juggle milestone assign %s <ball-id>...\n", report.Milestone.Name)
+		return
+	}
+
+	fmt.Println("\n  By session:")
+	for _, sp := range report.Sessions {
+		name := sp.Session
+		if name == "" {
+			name = "(no session)"
+		}
+		fmt.Printf("    %s %d/%d\n", padRight(name, 20), sp.Done, sp.Total)
+	}
+
+	sections := []struct {
+		title string
+		balls []*session.Ball
+	}{
+		{"Remaining", report.Remaining},
+		{"Done", report.Done},
+	}
+	for _, section := range sections {
+		if len(section.balls) == 0 {
+			continue
+		}
+		fmt.Printf("\n  %s:\n", section.title)
+		for _, ball := range section.balls {
+			line := fmt.Sprintf("    %s %s %-11s %s", padRight(ball.ShortID(), 8), padRight(string(ball.Priority), 7), ball.State, ball.Title)
+			if len(ball.Tags) > 0 {
+				line += "  [" + strings.Join(ball.Tags, ",") + "]"
+			}
+			fmt.Println(line)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
 
@@ -39,7 +40,7 @@ func runMove(cmd *cobra.Command, args []string) error {
 	// Resolve target path to absolute
 	targetPath, err := filepath.Abs(targetPath)
 	if err != nil {
-		return fmt.Errorf("invalid target path: %w", err)
+		return validationErrorf("invalid target path: %w", err)
 	}
 
 	// Verify target has .juggle directory
@@ -59,7 +60,7 @@ func runMove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if trying to move to same project
-	if ball.WorkingDir == targetPath {
+	if session.SamePath(ball.WorkingDir, targetPath) {
 		return fmt.Errorf("ball is already in the target project")
 	}
 
@@ -121,9 +122,13 @@ func runMove(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Append to target
+	shownID := ball.ID
 	if err := targetStore.AppendBall(ball); err != nil {
 		return fmt.Errorf("failed to add ball to target: %w", err)
 	}
+	if ball.ID != shownID {
+		fmt.Printf("ID %s was used before in the target project, so the ball is now %s\n", shownID, ball.ID)
+	}
 
 	// Delete from source
 	if err := sourceStore.DeleteBall(originalID); err != nil {
@@ -64,6 +64,9 @@ func runNext(cmd *cobra.Command, args []string) error {
 	fmt.Printf("→ Next ball: %s\n", nextBall.ID)
 	fmt.Printf("  Project: %s\n", nextBall.WorkingDir)
 	fmt.Printf("  Title: %s\n", nextBall.Title)
+	if nextBall.NextAction != "" {
+		fmt.Printf("  Next action: %s\n", nextBall.NextAction)
+	}
 	fmt.Printf("  State: %s\n", nextBall.State)
 	if nextBall.BlockedReason != "" {
 		fmt.Printf("  Blocked: %s\n", nextBall.BlockedReason)
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// noteTimeFormat is how note timestamps are shown
+	noteTimeFormat = "2006-01-02 15:04"
+	// agentPromptNotes is how many of a ball's latest notes agent prompts include
+	agentPromptNotes = 10
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note <ball-id> [text...]",
+	Short: "Add a note to a ball's work log, or list its notes",
+	Long: `Add a timestamped note to a ball's work log. Without text, list the
+ball's notes, oldest first.
+
+Notes are append-only and belong to one ball, unlike session progress, which
+is shared by every ball in the session. They are shown in the TUI's detail
+pane and the latest ones are included in agent prompts for the ball.
+
+Examples:
+  juggle note my-app-1 "Tried the retry approach, flaky under load"
+  juggle note my-app-1                  # List notes
+  juggle note my-app-1 --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNote,
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+}
+
+func runNote(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 {
+		return printBallNotes(ball)
+	}
+
+	text := strings.TrimSpace(strings.Join(args[1:], " "))
+	if text == "" {
+		return validationErrorf("note text cannot be empty")
+	}
+	note := ball.AddNote(text)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		return printNotesJSON([]session.BallNote{note})
+	}
+	fmt.Printf("✓ Added note to %s (%d note(s))\n", ball.ShortID(), len(ball.Notes))
+	return nil
+}
+
+// printBallNotes lists a ball's notes, oldest first
+func printBallNotes(ball *session.Ball) error {
+	if GlobalOpts.JSONOutput {
+		notes := ball.Notes
+		if notes == nil {
+			notes = []session.BallNote{}
+		}
+		return printNotesJSON(notes)
+	}
+
+	if len(ball.Notes) == 0 {
+		fmt.Printf("No notes for %s. Add one with: juggle note %s \"text\"\n", ball.ShortID(), ball.ShortID())
+		return nil
+	}
+	for _, note := range ball.Notes {
+		fmt.Printf("%s  %s\n", StyleHighlight.Render(note.CreatedAt.Format(noteTimeFormat)),
+			strings.ReplaceAll(note.Text, "\n", "\n"+strings.Repeat(" ", len(noteTimeFormat)+2)))
+	}
+	return nil
+}
+
+func printNotesJSON(notes []session.BallNote) error {
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// loadOpenSessionBallIDs returns the IDs of a session's balls that aren't
+// complete or researched yet, so balls an agent run completes can be found
+// afterwards. Errors are ignored: ownership tracking is best-effort.
+func loadOpenSessionBallIDs(projectDir, sessionID string) map[string]bool {
+	ids := make(map[string]bool)
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return ids
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return ids
+	}
+	for _, ball := range balls {
+		if ball.State != session.StateComplete && ball.State != session.StateResearched && ball.HasTag(sessionID) {
+			ids[ball.ID] = true
+		}
+	}
+	return ids
+}
+
+// claimCompletedBalls records sessionID's run as the completer of the
+// previously open balls it completed that are also tagged in other
+// sessions, so only this session reports them as progress. With the
+// transfer policy, the other sessions' tags are removed as well.
+func claimCompletedBalls(projectDir, sessionID string, openIDs map[string]bool) {
+	if len(openIDs) == 0 {
+		return
+	}
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return
+	}
+	sessionIDs := make([]string, 0, len(sessions))
+	for _, sess := range sessions {
+		sessionIDs = append(sessionIDs, sess.ID)
+	}
+
+	policy := session.CompletionOwnershipKeep
+	if config, err := LoadConfigForCommand(); err == nil {
+		policy = config.GetCompletionOwnership()
+	}
+	transfer := policy == session.CompletionOwnershipTransfer
+
+	claim := func(ball *session.Ball, save func(*session.Ball) error) {
+		if !openIDs[ball.ID] || (ball.State != session.StateComplete && ball.State != session.StateResearched) {
+			return
+		}
+		if ball.CompletedBySession != "" || len(otherSessionTags(ball, sessionID, sessionIDs)) == 0 {
+			return
+		}
+		removed := ball.ClaimCompletion(sessionID, sessionIDs, transfer)
+		if err := save(ball); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record %s as completed by %s: %v\n", ball.ShortID(), sessionID, err)
+			return
+		}
+		if len(removed) > 0 {
+			fmt.Printf("🏷️  %s completed by %s, removed from %s\n", ball.ShortID(), sessionID, strings.Join(removed, ", "))
+		} else {
+			fmt.Printf("🏷️  %s completed by %s, not counted as progress in %s\n",
+				ball.ShortID(), sessionID, strings.Join(otherSessionTags(ball, sessionID, sessionIDs), ", "))
+		}
+	}
+
+	if balls, err := store.LoadBalls(); err == nil {
+		for _, ball := range balls {
+			claim(ball, store.UpdateBall)
+		}
+	}
+	_ = store.EachArchivedBall(func(ball *session.Ball) bool {
+		claim(ball, store.UpdateArchivedBall)
+		return true
+	})
+}
+
+// otherSessionTags returns the ball's tags naming sessions other than sessionID
+func otherSessionTags(ball *session.Ball, sessionID string, sessionIDs []string) []string {
+	var others []string
+	for _, id := range sessionIDs {
+		if id != sessionID && ball.HasTag(id) {
+			others = append(others, id)
+		}
+	}
+	return others
+}
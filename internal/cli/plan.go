@@ -4,10 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/editor"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/tui"
 	"github.com/spf13/cobra"
@@ -27,11 +27,25 @@ TUI mode (default):
   juggle plan                         # Opens TUI form
   juggle plan --intent "Fix bug"      # Pre-populates title in TUI
   juggle plan -c "AC1" -c "AC2"       # Pre-populates acceptance criteria
+  juggle plan -s auth --depends-on a1 # Pre-selects session and dependencies
+
+  The form is the one the TUI uses to add balls: @ completes file paths in
+  the context, title and criteria, the session field can create a new
+  session, Depends On opens a picker, and the project's AC templates are
+  offered under the acceptance criteria.
 
 Editor mode:
   juggle plan --edit                  # Opens $EDITOR with YAML template
   juggle plan --edit --intent "Task"  # Pre-populates fields in template
 
+Assisted mode:
+  juggle plan "Add login lockout" --assist  # Agent proposes context and criteria
+
+  The agent reads the project (read-only) and proposes context and
+  acceptance criteria for the intent. Accept, edit or reject each one,
+  add your own, and the ball is created. With --non-interactive, every
+  suggestion is accepted as is.
+
 Non-interactive mode (for headless agents):
   juggle plan "Task intent" --non-interactive              # Uses defaults
   juggle plan "Task" -p high -c "AC1" --non-interactive    # With options
@@ -54,6 +68,7 @@ var dependsOnFlag []string
 var contextFlag string
 var nonInteractiveFlag bool
 var editFlag bool
+var assistFlag bool
 
 func init() {
 	planCmd.Flags().StringVarP(&intentFlag, "intent", "i", "", "What are you planning to work on?")
@@ -67,6 +82,9 @@ func init() {
 	planCmd.Flags().StringSliceVar(&dependsOnFlag, "depends-on", []string{}, "Ball IDs this ball depends on (can be specified multiple times)")
 	planCmd.Flags().BoolVar(&nonInteractiveFlag, "non-interactive", false, "Skip interactive prompts, use defaults for unspecified fields (headless mode)")
 	planCmd.Flags().BoolVar(&editFlag, "edit", false, "Open $EDITOR with YAML template instead of TUI form")
+	planCmd.Flags().BoolVar(&assistFlag, "assist", false, "Have the agent propose context and acceptance criteria to review")
+	planCmd.Flags().StringVar(&assistProvider, "provider", "", "Agent provider for --assist (claude, opencode, http, ollama, shell). Default: from config or claude")
+	planCmd.Flags().StringVar(&assistModel, "model", "", "Model for --assist (opus, sonnet, haiku)")
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
@@ -94,9 +112,17 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	// Determine which mode to use
 	isTTY := term.IsTerminal(int(os.Stdin.Fd()))
 
+	if assistFlag {
+		// Assisted mode: the agent proposes context and criteria to review
+		if editFlag {
+			return validationErrorf("--assist can't be combined with --edit")
+		}
+		return runPlanAssist(store, cwd, intent, acceptanceCriteria)
+	}
+
 	if nonInteractiveFlag {
 		// Non-interactive mode: require intent, use defaults
-		return runPlanNonInteractive(store, cwd, intent, acceptanceCriteria)
+		return runPlanNonInteractive(store, cwd, intent, contextFlag, acceptanceCriteria)
 	}
 
 	if editFlag {
@@ -109,7 +135,7 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		if intent == "" {
 			return fmt.Errorf("intent is required when not running in a terminal (use --intent or positional args)")
 		}
-		return runPlanNonInteractive(store, cwd, intent, acceptanceCriteria)
+		return runPlanNonInteractive(store, cwd, intent, contextFlag, acceptanceCriteria)
 	}
 
 	// Default: TUI mode
@@ -125,13 +151,23 @@ func runPlanTUI(store *session.Store, cwd, intent string, acceptanceCriteria []s
 		sessionStore = nil
 	}
 
+	// Short IDs from --depends-on are resolved up front, as in non-interactive mode
+	dependsOn := dependsOnFlag
+	if len(dependsOn) > 0 {
+		dependsOn, err = resolveDependencyIDs(store, dependsOn)
+		if err != nil {
+			return fmt.Errorf("failed to resolve dependencies: %w", err)
+		}
+	}
+
 	// Create standalone ball model
 	model := tui.NewStandaloneBallModel(store, sessionStore)
 
 	// Pre-populate from flags
-	model.PrePopulate(intent, contextFlag, tagsFlag, sessionFlag, priorityFlag, modelSizeFlag, acceptanceCriteria, dependsOnFlag)
+	model.PrePopulate(intent, contextFlag, tagsFlag, sessionFlag, priorityFlag, modelSizeFlag, acceptanceCriteria, dependsOn)
 
 	// Run the TUI
+	applyIconConfig()
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -155,6 +191,7 @@ func runPlanTUI(store *session.Store, cwd, intent string, acceptanceCriteria []s
 
 	fmt.Printf("✓ Planned ball added: %s\n", result.Ball.ID)
 	fmt.Printf("  Title: %s\n", result.Ball.Title)
+	warnTitleRules(cwd, result.Ball.Title)
 	fmt.Printf("  Priority: %s\n", result.Ball.Priority)
 	fmt.Printf("  State: %s\n", result.Ball.State)
 	if len(result.Ball.Tags) > 0 {
@@ -197,7 +234,7 @@ func runPlanEditor(store *session.Store, cwd, intent string, acceptanceCriteria
 		priority = "medium"
 	}
 	if !session.ValidatePriority(priority) {
-		return fmt.Errorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
+		return validationErrorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
 	}
 
 	// Create YAML template
@@ -249,6 +286,7 @@ func runPlanEditor(store *session.Store, cwd, intent string, acceptanceCriteria
 
 	fmt.Printf("✓ Planned ball added: %s\n", ball.ID)
 	fmt.Printf("  Title: %s\n", ball.Title)
+	warnTitleRules(cwd, ball.Title)
 	fmt.Printf("  Priority: %s\n", ball.Priority)
 	fmt.Printf("  State: %s\n", ball.State)
 	if len(ball.Tags) > 0 {
@@ -263,7 +301,7 @@ func runPlanEditor(store *session.Store, cwd, intent string, acceptanceCriteria
 }
 
 // runPlanNonInteractive creates a ball without any interactive prompts
-func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceCriteria []string) error {
+func runPlanNonInteractive(store *session.Store, cwd, intent, context string, acceptanceCriteria []string) error {
 	if intent == "" {
 		return fmt.Errorf("intent is required in non-interactive mode (use positional args or --intent)")
 	}
@@ -274,7 +312,7 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 		priority = "medium"
 	}
 	if !session.ValidatePriority(priority) {
-		return fmt.Errorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
+		return validationErrorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
 	}
 
 	// Create the planned ball
@@ -286,8 +324,8 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 	ball.State = session.StatePending
 
 	// Set context if provided
-	if contextFlag != "" {
-		ball.Context = contextFlag
+	if context != "" {
+		ball.Context = context
 	}
 
 	// Set acceptance criteria if provided
@@ -309,7 +347,7 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 	if modelSizeFlag != "" {
 		ms := session.ModelSize(modelSizeFlag)
 		if ms != session.ModelSizeSmall && ms != session.ModelSizeMedium && ms != session.ModelSizeLarge {
-			return fmt.Errorf("invalid model size %q, must be one of: small, medium, large", modelSizeFlag)
+			return validationErrorf("invalid model size %q, must be one of: small, medium, large", modelSizeFlag)
 		}
 		ball.ModelSize = ms
 	}
@@ -342,6 +380,7 @@ func runPlanNonInteractive(store *session.Store, cwd, intent string, acceptanceC
 
 	fmt.Printf("✓ Planned ball added: %s\n", ball.ID)
 	fmt.Printf("  Title: %s\n", ball.Title)
+	warnTitleRules(cwd, ball.Title)
 	fmt.Printf("  Priority: %s\n", ball.Priority)
 	fmt.Printf("  State: %s\n", ball.State)
 	if len(ball.Tags) > 0 {
@@ -420,11 +459,6 @@ type editorResult struct {
 
 // runEditorForNewBall opens $EDITOR for creating a new ball
 func runEditorForNewBall(yamlContent string) (editorResult, error) {
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi"
-	}
-
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "juggle-new-ball-*.yaml")
 	if err != nil {
@@ -444,13 +478,7 @@ func runEditorForNewBall(yamlContent string) (editorResult, error) {
 	originalContent := yamlContent
 
 	// Run editor
-	editorParts := strings.Fields(editor)
-	var cmd *exec.Cmd
-	if len(editorParts) > 1 {
-		cmd = exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...)
-	} else {
-		cmd = exec.Command(editor, tmpPath)
-	}
+	cmd := editor.Command(tmpPath)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -465,12 +493,14 @@ func runEditorForNewBall(yamlContent string) (editorResult, error) {
 		return editorResult{}, fmt.Errorf("failed to read edited file: %w", err)
 	}
 
-	// Check if content was modified
-	if string(editedContent) == originalContent {
+	// Check if content was modified, ignoring line endings a Windows
+	// editor may have changed
+	edited := session.NormalizeNewlines(string(editedContent))
+	if edited == originalContent {
 		return editorResult{cancelled: true}, nil
 	}
 
-	return editorResult{content: string(editedContent)}, nil
+	return editorResult{content: edited}, nil
 }
 
 // NewBallYAML is the YAML representation for new ball creation
@@ -488,13 +518,13 @@ type NewBallYAML struct {
 func parseNewBallYAML(yamlContent, cwd string, store *session.Store) (*session.Ball, error) {
 	var yamlBall NewBallYAML
 	if err := yaml.Unmarshal([]byte(yamlContent), &yamlBall); err != nil {
-		return nil, fmt.Errorf("invalid YAML: %w", err)
+		return nil, validationErrorf("invalid YAML: %w", err)
 	}
 
 	// Validate required fields
 	title := strings.TrimSpace(yamlBall.Title)
 	if title == "" {
-		return nil, fmt.Errorf("title is required")
+		return nil, validationErrorf("title is required")
 	}
 
 	// Validate priority
@@ -503,7 +533,7 @@ func parseNewBallYAML(yamlContent, cwd string, store *session.Store) (*session.B
 		priority = "medium"
 	}
 	if !session.ValidatePriority(priority) {
-		return nil, fmt.Errorf("invalid priority: %s (must be low, medium, high, or urgent)", priority)
+		return nil, validationErrorf("invalid priority: %s (must be low, medium, high, or urgent)", priority)
 	}
 
 	// Create the ball
@@ -545,7 +575,7 @@ func parseNewBallYAML(yamlContent, cwd string, store *session.Store) (*session.B
 		case session.ModelSizeSmall, session.ModelSizeMedium, session.ModelSizeLarge:
 			ball.ModelSize = ms
 		default:
-			return nil, fmt.Errorf("invalid model_size: %s (must be small, medium, large, or empty)", modelSize)
+			return nil, validationErrorf("invalid model_size: %s (must be small, medium, large, or empty)", modelSize)
 		}
 	}
 
@@ -593,14 +623,14 @@ func resolveDependencyIDs(store *session.Store, ids []string) ([]string, error)
 		// Use prefix matching
 		matches := session.ResolveBallByPrefix(balls, id)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball not found: %s", id)
+			return nil, notFoundErrorf("ball not found: %s", id)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", id, len(matches), strings.Join(matchingIDs, ", "))
+			return nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", id, len(matches), strings.Join(matchingIDs, ", "))
 		}
 		resolved = append(resolved, matches[0].ID)
 	}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+var (
+	assistProvider string
+	assistModel    string
+)
+
+// assistContextPattern and assistCriterionPattern match the agent's proposals
+var (
+	assistContextPattern   = regexp.MustCompile(`(?s)<context>(.*?)</context>`)
+	assistCriterionPattern = regexp.MustCompile(`(?s)<criterion>(.*?)</criterion>`)
+)
+
+// PlanSuggestion is the context and acceptance criteria the agent proposes
+// for a new ball
+type PlanSuggestion struct {
+	Context  string
+	Criteria []string
+}
+
+// runPlanAssist asks the agent to propose context and criteria for the
+// intent, lets the user review them, and creates the ball
+func runPlanAssist(store *session.Store, cwd, intent string, acceptanceCriteria []string) error {
+	if intent == "" {
+		return validationErrorf("intent is required with --assist (use positional args or --intent)")
+	}
+
+	// Configure agent provider
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	providerType := provider.Detect(assistProvider, projectProvider, globalProvider)
+	if !provider.IsAvailable(providerType) {
+		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
+			providerType, provider.BinaryName(providerType))
+	}
+	agent.SetProvider(newAgentProvider(providerType))
+
+	// Configure model overrides
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+
+	suggestion, err := suggestPlan(cwd, intent, contextFlag, acceptanceCriteria, assistModel)
+	if err != nil {
+		return err
+	}
+
+	context := suggestion.Context
+	criteria := append(acceptanceCriteria, suggestion.Criteria...)
+	if !nonInteractiveFlag {
+		var ok bool
+		context, criteria, ok = reviewPlanSuggestion(os.Stdin, contextFlag, acceptanceCriteria, suggestion)
+		if !ok {
+			fmt.Println("Cancelled - no ball created")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	return runPlanNonInteractive(store, cwd, intent, context, criteria)
+}
+
+// suggestPlan runs the agent in read-only mode to propose context and
+// criteria for the intent. The suggested context already includes the
+// given context; the suggested criteria don't include the given ones.
+func suggestPlan(cwd, intent, context string, criteria []string, model string) (*PlanSuggestion, error) {
+	projectCriteria, err := session.GetProjectAcceptanceCriteria(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project acceptance criteria: %v\n", err)
+	}
+
+	fmt.Fprintln(os.Stderr, "Asking the agent for context and acceptance criteria...")
+	result, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     generateAssistPrompt(intent, context, criteria, projectCriteria),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: cwd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("agent failed: %w", err)
+	}
+	if result.RateLimited {
+		return nil, fmt.Errorf("agent was rate limited, try again later")
+	}
+
+	suggestion := parseAssistOutput(result.Output, criteria)
+	if suggestion.Context == "" {
+		suggestion.Context = context
+	}
+	if suggestion.Context == "" && len(suggestion.Criteria) == 0 {
+		return nil, fmt.Errorf("agent made no suggestions")
+	}
+	return suggestion, nil
+}
+
+// generateAssistPrompt builds the planning assistant prompt for an intent
+func generateAssistPrompt(intent, context string, criteria, projectCriteria []string) string {
+	var buf strings.Builder
+
+	buf.WriteString(fmt.Sprintf("<intent>\n%s\n</intent>\n\n", intent))
+	if context != "" {
+		buf.WriteString(fmt.Sprintf("<context>\n%s\n</context>\n\n", context))
+	}
+	if len(criteria) > 0 {
+		buf.WriteString("<criteria>\n")
+		for i, ac := range criteria {
+			buf.WriteString(fmt.Sprintf("%d. %s\n", i+1, ac))
+		}
+		buf.WriteString("</criteria>\n\n")
+	}
+	if len(projectCriteria) > 0 {
+		buf.WriteString("<project_criteria>\n")
+		for _, ac := range projectCriteria {
+			buf.WriteString(fmt.Sprintf("- %s\n", ac))
+		}
+		buf.WriteString("</project_criteria>\n\n")
+	}
+
+	buf.WriteString("<instructions>\n")
+	buf.WriteString(agent.GetAssistPromptTemplate())
+	if !strings.HasSuffix(agent.GetAssistPromptTemplate(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</instructions>\n")
+
+	return buf.String()
+}
+
+// parseAssistOutput extracts the proposed context and criteria from agent
+// output, dropping blank criteria and ones already given
+func parseAssistOutput(output string, existing []string) *PlanSuggestion {
+	suggestion := &PlanSuggestion{}
+	if match := assistContextPattern.FindStringSubmatch(output); match != nil {
+		suggestion.Context = strings.TrimSpace(match[1])
+	}
+
+	seen := make(map[string]bool)
+	for _, ac := range existing {
+		seen[strings.ToLower(ac)] = true
+	}
+	for _, match := range assistCriterionPattern.FindAllStringSubmatch(output, -1) {
+		ac := strings.Join(strings.Fields(match[1]), " ")
+		if ac == "" || seen[strings.ToLower(ac)] {
+			continue
+		}
+		seen[strings.ToLower(ac)] = true
+		suggestion.Criteria = append(suggestion.Criteria, ac)
+	}
+	return suggestion
+}
+
+// reviewPlanSuggestion walks the user through the suggested context and each
+// suggested criterion, then lets them add their own. It returns the context
+// and criteria to create the ball with, or false if the user quit.
+func reviewPlanSuggestion(in io.Reader, context string, criteria []string, suggestion *PlanSuggestion) (string, []string, bool) {
+	reader := bufio.NewReader(in)
+	criteria = append([]string{}, criteria...)
+
+	if suggestion.Context != "" && suggestion.Context != context {
+		fmt.Printf("\nSuggested context:\n  %s\n", suggestion.Context)
+		switch reviewChoice(reader, "  (a)ccept, (e)dit, (r)eject, (q)uit [a]: ") {
+		case "e":
+			if edited := promptLine(reader, "  Context: "); edited != "" {
+				context = edited
+			} else {
+				context = suggestion.Context
+			}
+		case "r":
+		case "q":
+			return "", nil, false
+		default:
+			context = suggestion.Context
+		}
+	}
+
+	for i, ac := range suggestion.Criteria {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(suggestion.Criteria), ac)
+		switch reviewChoice(reader, "  (a)ccept, (e)dit, (r)eject, (q)uit [a]: ") {
+		case "e":
+			if edited := promptLine(reader, "  Criterion: "); edited != "" {
+				ac = edited
+			}
+			criteria = append(criteria, ac)
+		case "r":
+			fmt.Println("  Rejected")
+		case "q":
+			return "", nil, false
+		default:
+			criteria = append(criteria, ac)
+		}
+	}
+
+	fmt.Println()
+	for {
+		ac := promptLine(reader, "Add a criterion (blank to finish): ")
+		if ac == "" {
+			break
+		}
+		criteria = append(criteria, ac)
+	}
+	return context, criteria, true
+}
+
+// reviewChoice prompts for a one-letter choice; blank input or EOF is ""
+func reviewChoice(reader *bufio.Reader, prompt string) string {
+	return strings.ToLower(promptLine(reader, prompt))
+}
+
+// promptLine prints prompt and reads one trimmed line; EOF is ""
+func promptLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// SuggestPlanForTest is an exported wrapper for testing
+func SuggestPlanForTest(cwd, intent, context string, criteria []string) (*PlanSuggestion, error) {
+	return suggestPlan(cwd, intent, context, criteria, "")
+}
+
+// ReviewPlanSuggestionForTest is an exported wrapper for testing
+func ReviewPlanSuggestionForTest(in io.Reader, context string, criteria []string, suggestion *PlanSuggestion) (string, []string, bool) {
+	return reviewPlanSuggestion(in, context, criteria, suggestion)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ohare93/juggle/internal/profile"
+	"github.com/spf13/cobra"
+)
+
+// profileLogName is the profile log's file name, under the config home's
+// juggle directory
+const profileLogName = "profile.jsonl"
+
+// profileLogPath returns where --profile writes its timings
+func profileLogPath() (string, error) {
+	opts := GetConfigOptions()
+	if opts.ConfigHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.ConfigHome = home
+	}
+	return filepath.Join(opts.ConfigHome, opts.JuggleDirName, profileLogName), nil
+}
+
+// startProfiling enables profiling for the command when --profile is set,
+// serving pprof endpoints when --profile-addr is also set
+func startProfiling(cmd *cobra.Command, args []string) error {
+	if !GlobalOpts.Profile {
+		if GlobalOpts.ProfileAddr != "" {
+			return validationErrorf("--profile-addr requires --profile")
+		}
+		return nil
+	}
+
+	path, err := profileLogPath()
+	if err != nil {
+		return err
+	}
+	if err := profile.Enable(path); err != nil {
+		return err
+	}
+	if GlobalOpts.ProfileAddr != "" {
+		addr, err := profile.Serve(GlobalOpts.ProfileAddr)
+		if err != nil {
+			profile.Close()
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Profiling: pprof at http://%s/debug/pprof/, timings at http://%s/debug/juggle/profile\n", addr, addr)
+	}
+	return nil
+}
+
+// stopProfiling closes the profile log and prints a summary of the
+// recorded timings to stderr
+func stopProfiling() {
+	if !profile.Enabled() {
+		return
+	}
+	profile.Close()
+
+	path, _ := profileLogPath()
+	stats := profile.Stats()
+	fmt.Fprintf(os.Stderr, "\nProfile written to %s\n", path)
+	if len(stats) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%-8s %-20s %7s %10s %10s\n", "KIND", "NAME", "COUNT", "AVG", "MAX")
+	for _, stat := range stats {
+		fmt.Fprintf(os.Stderr, "%-8s %-20s %7d %10s %10s\n",
+			stat.Kind, stat.Name, stat.Count,
+			stat.Avg().Round(time.Microsecond), stat.Max.Round(time.Microsecond))
+	}
+}
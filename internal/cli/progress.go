@@ -4,18 +4,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
 
-var progressAppendJSONFlag bool
+var (
+	progressAppendJSONFlag bool
+	progressCategoryFlag   string
+	progressBallFlag       string
+	progressAuthorFlag     string
+
+	progressShowCategoryFlag string
+	progressShowBallFlag     string
+	progressShowAuthorFlag   string
+)
 
 var progressCmd = &cobra.Command{
 	Use:   "progress",
 	Short: "Manage session progress logs",
-	Long:  `Commands for managing session progress logs (progress.txt files).`,
+	Long: `Commands for managing session progress logs (progress.txt files).
+
+Progress entries are records with a timestamp, an author (human or agent),
+a category (work, decision or blocker) and optionally the ball they are
+about. Free-form progress written before entries were structured is still
+read, as work entries (blockers when they start with "Blocked").`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
@@ -29,19 +44,53 @@ var progressAppendCmd = &cobra.Command{
 The session-id can be provided as the first argument, or via the
 JUGGLE_SESSION_ID environment variable.
 
+Entries are work by default. Use --category decision to record a choice and
+why it was made, and --category blocker for what is stopping the work. The
+author defaults to agent, as agents log their progress with this command;
+pass --author human for your own notes.
+
 Creates progress.txt if it doesn't exist.
 
 Examples:
   juggle progress append my-session "Completed user story US-001"
+  juggle progress append my-session "Use PKCE, the app has no secret" --category decision
+  juggle progress append my-session "Cannot reach the DB" -c blocker --ball my-app-3
   JUGGLE_SESSION_ID=my-session juggle progress append "Fixed auth bug"
   juggle progress append my-session "Message" --json`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runProgressAppend,
 }
 
+var progressShowCmd = &cobra.Command{
+	Use:   "show [session-id]",
+	Short: "Show session progress, optionally filtered",
+	Long: `Show the entries of a session's progress log, oldest first.
+
+The session-id can be provided as an argument, or via the
+JUGGLE_SESSION_ID environment variable.
+
+Filter by category, author or ball. The ball filter also matches free-form
+entries that mention the ball.
+
+Examples:
+  juggle progress show my-session
+  juggle progress show my-session --category blocker
+  juggle progress show my-session --author human --ball my-app-3
+  juggle progress show my-session --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProgressShow,
+}
+
 func init() {
 	progressAppendCmd.Flags().BoolVar(&progressAppendJSONFlag, "json", false, "Output as JSON")
+	progressAppendCmd.Flags().StringVarP(&progressCategoryFlag, "category", "c", string(session.ProgressCategoryWork), "Entry category: work, decision or blocker")
+	progressAppendCmd.Flags().StringVarP(&progressBallFlag, "ball", "b", "", "Ball the entry is about")
+	progressAppendCmd.Flags().StringVar(&progressAuthorFlag, "author", string(session.ProgressAuthorAgent), "Who wrote the entry: human or agent")
+	progressShowCmd.Flags().StringVarP(&progressShowCategoryFlag, "category", "c", "", "Only show entries in this category: work, decision or blocker")
+	progressShowCmd.Flags().StringVarP(&progressShowBallFlag, "ball", "b", "", "Only show entries about this ball")
+	progressShowCmd.Flags().StringVar(&progressShowAuthorFlag, "author", "", "Only show entries by human or agent")
 	progressCmd.AddCommand(progressAppendCmd)
+	progressCmd.AddCommand(progressShowCmd)
 	rootCmd.AddCommand(progressCmd)
 }
 
@@ -65,6 +114,23 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 		text = args[0]
 	}
 
+	entry := session.ProgressEntry{BallID: strings.TrimSpace(progressBallFlag), Text: text}
+	category, err := session.ParseProgressCategory(progressCategoryFlag)
+	if err == nil {
+		entry.Category = category
+		entry.Author, err = session.ParseProgressAuthor(progressAuthorFlag)
+	}
+	if err == nil && strings.TrimSpace(text) == "" {
+		err = fmt.Errorf("progress text cannot be empty")
+	}
+	if err != nil {
+		err = validationErrorf("%v", err)
+		if progressAppendJSONFlag {
+			return printProgressAppendJSONError(err)
+		}
+		return err
+	}
+
 	cwd, err := GetWorkingDir()
 	if err != nil {
 		err = fmt.Errorf("failed to get current directory: %w", err)
@@ -83,9 +149,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Format timestamped entry
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	entry := fmt.Sprintf("[%s] %s\n", timestamp, text)
+	entry.Timestamp = time.Now()
 
 	// Map "all" meta-session to "_all" for storage
 	storageID := sessionID
@@ -94,7 +158,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 	}
 
 	// Append to progress file
-	if err := store.AppendProgress(storageID, entry); err != nil {
+	if err := store.AppendProgressEntry(storageID, entry); err != nil {
 		err = fmt.Errorf("failed to append progress: %w", err)
 		if progressAppendJSONFlag {
 			return printProgressAppendJSONError(err)
@@ -103,7 +167,7 @@ func runProgressAppend(cmd *cobra.Command, args []string) error {
 	}
 
 	if progressAppendJSONFlag {
-		return printProgressAppendJSONSuccess(sessionID, text, timestamp)
+		return printProgressAppendJSONSuccess(sessionID, entry)
 	}
 
 	// Success message for agent confirmation
@@ -117,14 +181,20 @@ type ProgressAppendResponse struct {
 	SessionID string `json:"session_id"`
 	Text      string `json:"text"`
 	Timestamp string `json:"timestamp"`
+	Author    string `json:"author"`
+	Category  string `json:"category"`
+	BallID    string `json:"ball_id,omitempty"`
 }
 
-func printProgressAppendJSONSuccess(sessionID, text, timestamp string) error {
+func printProgressAppendJSONSuccess(sessionID string, entry session.ProgressEntry) error {
 	resp := ProgressAppendResponse{
 		Success:   true,
 		SessionID: sessionID,
-		Text:      text,
-		Timestamp: timestamp,
+		Text:      strings.TrimSpace(entry.Text),
+		Timestamp: entry.Timestamp.Format("2006-01-02 15:04:05"),
+		Author:    string(entry.Author),
+		Category:  string(entry.Category),
+		BallID:    entry.BallID,
 	}
 	data, _ := json.Marshal(resp)
 	fmt.Println(string(data))
@@ -137,3 +207,67 @@ func printProgressAppendJSONError(err error) error {
 	fmt.Println(string(data))
 	return nil // Return nil so the error is in JSON, not stderr
 }
+
+func runProgressShow(cmd *cobra.Command, args []string) error {
+	sessionID := os.Getenv("JUGGLE_SESSION_ID")
+	if len(args) == 1 {
+		sessionID = args[0]
+	}
+	if sessionID == "" {
+		return usageErrorf("session ID required: provide as an argument or set JUGGLE_SESSION_ID")
+	}
+
+	filter := session.ProgressFilter{BallID: strings.TrimSpace(progressShowBallFlag)}
+	var err error
+	if progressShowCategoryFlag != "" {
+		if filter.Category, err = session.ParseProgressCategory(progressShowCategoryFlag); err != nil {
+			return validationErrorf("%v", err)
+		}
+	}
+	if progressShowAuthorFlag != "" {
+		if filter.Author, err = session.ParseProgressAuthor(progressShowAuthorFlag); err != nil {
+			return validationErrorf("%v", err)
+		}
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+
+	storageID := sessionID
+	if sessionID == "all" {
+		storageID = "_all"
+	} else if _, err := store.LoadSession(sessionID); err != nil {
+		return session.NewSessionNotFoundError(sessionID)
+	}
+
+	entries, err := store.LoadProgressEntries(storageID)
+	if err != nil {
+		return fmt.Errorf("failed to load progress: %w", err)
+	}
+	entries = session.FilterProgress(entries, filter)
+
+	if GlobalOpts.JSONOutput {
+		if entries == nil {
+			entries = []session.ProgressEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No matching progress for session:", sessionID)
+		return nil
+	}
+	fmt.Print(session.FormatProgress(entries))
+	return nil
+}
@@ -1,38 +1,103 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 )
 
+var projectsAddName string
+
 var projectsCmd = &cobra.Command{
 	Use:   "projects",
-	Short: "List and manage tracked projects",
-	Long:  `List all projects with .juggle directories and manage search paths.`,
+	Short: "List and manage registered projects",
+	Long: `List and manage the project registry.
+
+Cross-project views (--all, and the TUI's all-projects mode) load only the
+projects registered here that are enabled. A project is registered the first
+time you create a ball in it, or with 'juggle projects add'.
+
+Projects are named after their directory unless given a name. A project
+whose directory moved or was renamed shows as missing, and is skipped until
+it's pointed at its new directory with 'juggle projects move'.
+
+Examples:
+  juggle projects                           # List registered projects
+  juggle projects add ~/code/api --name api # Register a project under a name
+  juggle projects disable ~/code/old        # Leave a project out of --all views
+  juggle projects move ~/code/api ~/src/api # Follow a moved project`,
+	RunE: runProjects,
+}
+
+var projectsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered projects with their status and balls",
+	Args:  cobra.NoArgs,
 	RunE:  runProjects,
 }
 
 var projectsAddCmd = &cobra.Command{
 	Use:   "add <path>",
-	Short: "Add a search path for project discovery",
+	Short: "Register a project, or rename a registered one with --name",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runProjectsAdd,
 }
 
 var projectsRemoveCmd = &cobra.Command{
-	Use:   "remove <path>",
-	Short: "Remove a search path",
+	Use:   "remove <project>",
+	Short: "Remove a project from the registry (its .juggle is left alone)",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runProjectsRemove,
 }
 
+var projectsEnableCmd = &cobra.Command{
+	Use:   "enable <project>",
+	Short: "Include a disabled project in cross-project views again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setProjectDisabled(args[0], false)
+	},
+}
+
+var projectsDisableCmd = &cobra.Command{
+	Use:   "disable <project>",
+	Short: "Leave a project out of cross-project views, keeping it registered",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setProjectDisabled(args[0], true)
+	},
+}
+
+var projectsMoveCmd = &cobra.Command{
+	Use:   "move <project> <new-path>",
+	Short: "Point a registered project at its moved or renamed directory",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runProjectsMove,
+}
+
 func init() {
+	projectsAddCmd.Flags().StringVar(&projectsAddName, "name", "", "Display name for the project (defaults to its directory name)")
+
+	projectsCmd.AddCommand(projectsListCmd)
 	projectsCmd.AddCommand(projectsAddCmd)
 	projectsCmd.AddCommand(projectsRemoveCmd)
+	projectsCmd.AddCommand(projectsEnableCmd)
+	projectsCmd.AddCommand(projectsDisableCmd)
+	projectsCmd.AddCommand(projectsMoveCmd)
+}
+
+// projectListEntry is a registered project as shown by 'juggle projects'
+type projectListEntry struct {
+	session.RegisteredProject
+	DisplayName string               `json:"display_name"`
+	Status      string               `json:"status"`
+	Balls       *session.ProjectInfo `json:"balls,omitempty"`
 }
 
 func runProjects(cmd *cobra.Command, args []string) error {
@@ -41,23 +106,39 @@ func runProjects(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Get project info
+	// Get ball counts of the projects that load
 	projectInfos, err := session.GetProjectsInfo(config)
 	if err != nil {
 		return fmt.Errorf("failed to get project info: %w", err)
 	}
+	infoByPath := make(map[string]*session.ProjectInfo, len(projectInfos))
+	for _, info := range projectInfos {
+		infoByPath[info.Path] = info
+	}
+
+	registered := config.RegisteredProjects()
+	entries := make([]projectListEntry, 0, len(registered))
+	for _, project := range registered {
+		entries = append(entries, projectListEntry{
+			RegisteredProject: project,
+			DisplayName:       project.DisplayName(),
+			Status:            project.Status(),
+			Balls:             infoByPath[project.Path],
+		})
+	}
 
-	if len(projectInfos) == 0 {
-		fmt.Println("No projects with .juggle directories found.")
-		fmt.Println("\nSearch paths:")
-		for _, path := range config.SearchPaths {
-			if _, err := os.Stat(path); os.IsNotExist(err) {
-				fmt.Printf("  - %s (does not exist)\n", path)
-			} else {
-				fmt.Printf("  - %s\n", path)
-			}
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		fmt.Println("\nAdd a search path with: juggle projects add <path>")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No projects registered.")
+		fmt.Println("\nRegister one with: juggle projects add <path>")
 		return nil
 	}
 
@@ -71,49 +152,89 @@ func runProjects(cmd *cobra.Command, args []string) error {
 	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
 	plannedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
 	blockedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	faintStyle := lipgloss.NewStyle().Faint(true)
+	missingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
 
 	// Print header
 	fmt.Println(
-		headerStyle.Render(padRight("PROJECT", 50)) +
-		headerStyle.Render(padRight("JUGGLING", 10)) +
-		headerStyle.Render(padRight("READY", 8)) +
-		headerStyle.Render(padRight("DROPPED", 9)) +
-		headerStyle.Render(padRight("COMPLETE", 10)),
+		headerStyle.Render(padRight("NAME", 20)) +
+			headerStyle.Render(padRight("PATH", 40)) +
+			headerStyle.Render(padRight("STATUS", 11)) +
+			headerStyle.Render(padRight("JUGGLING", 10)) +
+			headerStyle.Render(padRight("READY", 8)) +
+			headerStyle.Render(padRight("DROPPED", 9)) +
+			headerStyle.Render(padRight("COMPLETE", 10)),
 	)
 
 	// Print projects
-	for _, info := range projectInfos {
-		projectCell := info.Path
-		if len(projectCell) > 48 {
-			projectCell = "..." + projectCell[len(projectCell)-45:]
+	missing := 0
+	for _, entry := range entries {
+		pathCell := entry.Path
+		if len(pathCell) > 38 {
+			pathCell = "..." + pathCell[len(pathCell)-35:]
+		}
+
+		status := padRight(entry.Status, 11)
+		switch entry.Status {
+		case session.ProjectMissing:
+			missing++
+			status = missingStyle.Render(status)
+		case session.ProjectDisabled, session.ProjectNoJuggle:
+			status = faintStyle.Render(status)
+		}
+
+		counts := faintStyle.Render(padRight("-", 10) + padRight("-", 8) + padRight("-", 9) + padRight("-", 10))
+		if info := entry.Balls; info != nil {
+			counts = activeStyle.Render(padRight(fmt.Sprintf("%d", info.InProgressBalls), 10)) +
+				plannedStyle.Render(padRight(fmt.Sprintf("%d", info.PendingBalls), 8)) +
+				blockedStyle.Render(padRight(fmt.Sprintf("%d", info.BlockedBalls), 9)) +
+				padRight(fmt.Sprintf("%d", info.CompleteBalls), 10)
 		}
 
-		fmt.Println(
-			padRight(projectCell, 50) +
-			activeStyle.Render(padRight(fmt.Sprintf("%d", info.InProgressBalls), 10)) +
-			plannedStyle.Render(padRight(fmt.Sprintf("%d", info.PendingBalls), 8)) +
-			blockedStyle.Render(padRight(fmt.Sprintf("%d", info.BlockedBalls), 9)) +
-			padRight(fmt.Sprintf("%d", info.CompleteBalls), 10),
-		)
+		fmt.Println(padRight(truncate(entry.DisplayName, 19), 20) + padRight(pathCell, 40) + status + counts)
+	}
+
+	fmt.Printf("\n%d project(s) registered\n", len(entries))
+	if missing > 0 {
+		fmt.Println("Follow a moved project with: juggle projects move <project> <new-path>")
 	}
 
-	fmt.Printf("\n%d project(s) found\n", len(projectInfos))
+	return nil
+}
 
-	// Show search paths
-	fmt.Println("\nSearch paths:")
-	for _, path := range config.SearchPaths {
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			fmt.Printf("  - %s (does not exist)\n", path)
-		} else {
-			fmt.Printf("  - %s\n", path)
+// findRegisteredProject returns the registered project a command argument
+// names, by path or by display name
+func findRegisteredProject(config *session.Config, arg string) (*session.RegisteredProject, error) {
+	if absPath, err := filepath.Abs(arg); err == nil {
+		if project := config.FindProject(absPath); project != nil {
+			return project, nil
 		}
 	}
+	if project := config.FindProject(arg); project != nil {
+		return project, nil
+	}
 
-	return nil
+	var matches []string
+	for _, project := range config.RegisteredProjects() {
+		if strings.EqualFold(project.DisplayName(), arg) {
+			matches = append(matches, project.Path)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, notFoundErrorf("project not registered: %s", arg)
+	case 1:
+		return config.FindProject(matches[0]), nil
+	default:
+		return nil, validationErrorf("%d projects are named %s, give its path instead: %s", len(matches), arg, strings.Join(matches, ", "))
+	}
 }
 
 func runProjectsAdd(cmd *cobra.Command, args []string) error {
-	path := args[0]
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
 
 	// Check if path exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -125,8 +246,16 @@ func runProjectsAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if !config.AddSearchPath(path) {
-		fmt.Printf("Path already in search paths: %s\n", path)
+	if !config.RegisterProject(path, projectsAddName) {
+		if projectsAddName == "" {
+			fmt.Printf("Project already registered: %s\n", path)
+			return nil
+		}
+		config.FindProject(path).Name = projectsAddName
+		if err := config.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ Renamed project %s to %s\n", path, projectsAddName)
 		return nil
 	}
 
@@ -134,26 +263,90 @@ func runProjectsAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("✓ Added search path: %s\n", path)
+	fmt.Printf("✓ Registered project: %s\n", path)
+	if _, err := os.Stat(filepath.Join(path, ".juggle")); os.IsNotExist(err) {
+		fmt.Println("  It has no .juggle yet, so it's listed once its first ball is created")
+	}
 	return nil
 }
 
 func runProjectsRemove(cmd *cobra.Command, args []string) error {
-	path := args[0]
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	project, err := findRegisteredProject(config, args[0])
+	if err != nil {
+		return err
+	}
+	path := project.Path
+	config.UnregisterProject(path)
+
+	if err := config.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Removed project: %s\n", path)
+	return nil
+}
+
+// setProjectDisabled enables or disables a registered project
+func setProjectDisabled(arg string, disabled bool) error {
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	project, err := findRegisteredProject(config, arg)
+	if err != nil {
+		return err
+	}
+	verb := "Enabled"
+	if disabled {
+		verb = "Disabled"
+	}
+	if project.Disabled == disabled {
+		fmt.Printf("Project already %s: %s\n", strings.ToLower(verb), project.DisplayName())
+		return nil
+	}
+	project.Disabled = disabled
+
+	if err := config.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ %s project: %s\n", verb, project.DisplayName())
+	return nil
+}
+
+func runProjectsMove(cmd *cobra.Command, args []string) error {
+	newPath, err := filepath.Abs(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if info, err := os.Stat(newPath); err != nil || !info.IsDir() {
+		return validationErrorf("new path is not a directory: %s", newPath)
+	}
 
 	config, err := LoadConfigForCommand()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if !config.RemoveSearchPath(path) {
-		return fmt.Errorf("path not found in search paths: %s", path)
+	project, err := findRegisteredProject(config, args[0])
+	if err != nil {
+		return err
+	}
+	oldPath := project.Path
+	if err := config.MoveProject(oldPath, newPath); err != nil {
+		return validationErrorf("%v", err)
 	}
 
 	if err := config.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("✓ Removed search path: %s\n", path)
+	fmt.Printf("✓ Moved project %s: %s → %s\n", config.ProjectName(newPath), oldPath, newPath)
 	return nil
 }
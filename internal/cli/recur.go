@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recurCron  string
+	recurEvery string
+)
+
+var recurCmd = &cobra.Command{
+	Use:   "recur",
+	Short: "Manage recurring balls",
+	Long: `Give a ball a schedule so it comes back after it's done. A schedule is a
+five-field cron expression (minute hour day month weekday) or a fixed
+interval such as 7d, 2w or 12h.
+
+A recurring ball is one instance of the task, due at a given time. When it's
+completed, a fresh pending instance is created, due at the next occurrence.
+If it's still open when the schedule fires again, a fresh instance is
+created anyway and the open one stops recurring. The new instance keeps the
+title, context, acceptance criteria, tags and agent hints.
+
+Completing a ball creates its next instance straight away. Instances for
+schedules that fired are created by 'juggle recur run' (e.g. from cron) and
+when the TUI starts.
+
+With no subcommand, lists recurring balls and when they're due.
+
+Examples:
+  juggle recur                                 # List recurring balls
+  juggle recur set a1b2 --cron "0 9 * * MON"   # Every Monday at 09:00
+  juggle recur set a1b2 --every 7d             # A week after each instance
+  juggle recur clear a1b2                      # Stop recurring
+  juggle recur run                             # Create instances that are due`,
+	Args: cobra.NoArgs,
+	RunE: runRecurList,
+}
+
+var recurSetCmd = &cobra.Command{
+	Use:   "set <ball-id>",
+	Short: "Give a ball a schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRecurSet,
+}
+
+var recurClearCmd = &cobra.Command{
+	Use:   "clear <ball-id>",
+	Short: "Stop a ball from recurring",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRecurClear,
+}
+
+var recurRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Create the next instance of recurring balls that are due",
+	Args:  cobra.NoArgs,
+	RunE:  runRecurRun,
+}
+
+func init() {
+	recurSetCmd.Flags().StringVar(&recurCron, "cron", "", "Cron expression, e.g. \"0 9 * * MON\"")
+	recurSetCmd.Flags().StringVar(&recurEvery, "every", "", "Interval, e.g. 7d, 2w or 12h")
+
+	recurCmd.AddCommand(recurSetCmd)
+	recurCmd.AddCommand(recurClearCmd)
+	recurCmd.AddCommand(recurRunCmd)
+	rootCmd.AddCommand(recurCmd)
+}
+
+func runRecurSet(cmd *cobra.Command, args []string) error {
+	recurrence, err := session.NewRecurrence(recurCron, recurEvery, time.Now())
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	if ball.State == session.StateComplete || ball.State == session.StateResearched {
+		return validationErrorf("ball %s is %s; only open balls can recur", ball.ShortID(), ball.State)
+	}
+
+	ball.Recurrence = recurrence
+	ball.UpdateActivity()
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Ball %s recurs %s\n", ball.ShortID(), recurrence.Label())
+	fmt.Printf("  Due: %s\n", recurrence.DueLabel(time.Now()))
+	return nil
+}
+
+func runRecurClear(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	if !ball.IsRecurring() {
+		fmt.Printf("Ball %s doesn't recur\n", ball.ShortID())
+		return nil
+	}
+
+	ball.Recurrence = nil
+	ball.UpdateActivity()
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Ball %s no longer recurs\n", ball.ShortID())
+	return nil
+}
+
+// recurProjects returns the projects recur commands work on (respects --all)
+func recurProjects() ([]string, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	projects, err := DiscoverProjectsForCommand(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover projects: %w", err)
+	}
+	return projects, nil
+}
+
+func runRecurList(cmd *cobra.Command, args []string) error {
+	projects, err := recurProjects()
+	if err != nil {
+		return err
+	}
+	balls, err := session.LoadAllBalls(projects)
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var recurring []*session.Ball
+	for _, ball := range balls {
+		if ball.IsRecurring() {
+			recurring = append(recurring, ball)
+		}
+	}
+	if len(recurring) == 0 {
+		fmt.Println("No recurring balls. Add a schedule with: juggle recur set <ball-id> --every 7d")
+		return nil
+	}
+	sort.SliceStable(recurring, func(i, j int) bool {
+		return recurring[i].Recurrence.DueAt.Before(recurring[j].Recurrence.DueAt)
+	})
+
+	now := time.Now()
+	for _, ball := range recurring {
+		label := ball.ShortID()
+		if len(projects) > 1 {
+			label += " [" + filepath.Base(ball.WorkingDir) + "]"
+		}
+		fmt.Printf("%s %s\n", label, ball.Title)
+		fmt.Printf("  %s, due %s\n", ball.Recurrence.Label(), ball.Recurrence.DueLabel(now))
+	}
+	return nil
+}
+
+func runRecurRun(cmd *cobra.Command, args []string) error {
+	projects, err := recurProjects()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	total := 0
+	for _, projectDir := range projects {
+		store, err := NewStoreForCommand(projectDir)
+		if err != nil {
+			return fmt.Errorf("failed to create store for %s: %w", projectDir, err)
+		}
+		created, err := store.MaterializeRecurrences(now)
+		for _, ball := range created {
+			printNextInstance(ball, now)
+		}
+		total += len(created)
+		if err != nil {
+			return err
+		}
+	}
+
+	if total == 0 {
+		fmt.Println("No recurring balls are due.")
+	}
+	return nil
+}
+
+// printNextInstance reports a newly created instance of a recurring ball
+func printNextInstance(ball *session.Ball, now time.Time) {
+	fmt.Printf("↻ Next instance: %s (due %s)\n", ball.ShortID(), ball.Recurrence.DueLabel(now))
+}
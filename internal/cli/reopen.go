@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var reopenNote string
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen <ball-id>",
+	Short: "Reopen a completed or archived ball as pending",
+	Long: `Reopen a complete or researched ball, for work that has regressed.
+
+The ball goes back to pending, restored from the archive if it was archived.
+Its completion is cleared, and a line recording the reopen (with --note, and
+the old completion note) is appended to its context. Session tags removed when
+another session's agent run completed the ball (completion_ownership transfer)
+are added back, so it shows up in those sessions again.
+
+Examples:
+  juggle reopen my-app-5
+  juggle reopen my-app-5 --note "Login fails on Safari again"
+  juggle my-app-5 reopen Login fails on Safari again    # Alternative syntax`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: CompleteArchivedBallIDs,
+	RunE:              runReopen,
+}
+
+func init() {
+	reopenCmd.Flags().StringVarP(&reopenNote, "note", "n", "", "Why the ball is being reopened, appended to its context")
+	rootCmd.AddCommand(reopenCmd)
+}
+
+func runReopen(cmd *cobra.Command, args []string) error {
+	ball, store, err := findCompletedBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	return reopenBall(ball, store, reopenNote)
+}
+
+// handleBallReopen handles the ball-specific reopen command (juggle <ball-id> reopen [note])
+func handleBallReopen(ball *session.Ball, store *session.Store, args []string) error {
+	return reopenBall(ball, store, strings.Join(args, " "))
+}
+
+// findCompletedBallByID looks a ball up in the active balls and then the
+// archives (both respect --all)
+func findCompletedBallByID(ballID string) (*session.Ball, *session.Store, error) {
+	ball, store, err := findBallByID(ballID)
+	if err == nil {
+		return ball, store, nil
+	}
+	if ClassifyError(err).Code != CodeNotFound {
+		return nil, nil, err
+	}
+	if ball, store, archiveErr := findArchivedBallByID(ballID); archiveErr == nil {
+		return ball, store, nil
+	} else if ClassifyError(archiveErr).Code != CodeNotFound {
+		return nil, nil, archiveErr
+	}
+	return nil, nil, err
+}
+
+// reopenBall reopens a complete or researched ball and reports the result
+func reopenBall(ball *session.Ball, store *session.Store, note string) error {
+	if ball.State != session.StateComplete && ball.State != session.StateResearched {
+		return validationErrorf("ball %s is not complete (current state: %s)", ball.ID, ball.State)
+	}
+
+	reopened, restored, archived, err := store.ReopenBall(ball.ID, note)
+	if err != nil {
+		return fmt.Errorf("failed to reopen ball: %w", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		return printBallJSON(reopened)
+	}
+
+	fmt.Printf("✓ Reopened ball: %s\n", StyleHighlight.Render(reopened.ID))
+	fmt.Printf("  State: %s\n", StylePending.Render(string(reopened.State)))
+	fmt.Printf("  Title: %s\n", reopened.Title)
+	if archived {
+		fmt.Println("  Restored from the archive")
+	}
+	if len(restored) > 0 {
+		fmt.Printf("  Back in sessions: %s\n", strings.Join(restored, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// sprintDefaultDays is how far back a sprint report goes without --from
+const sprintDefaultDays = 14
+
+var (
+	sprintFrom string
+	sprintTo   string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report on the work done in a project",
+	Long: `Report on the work done in the current project, for sharing with people
+who don't use juggle.
+
+Examples:
+  juggle report sprint --from 2026-10-01 --to 2026-10-14`,
+	Args: cobra.NoArgs,
+}
+
+var reportSprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Summarize the balls completed in a sprint as markdown",
+	Long: `Summarize the balls completed between two dates for a sprint review.
+
+Completed balls (archived ones included) are grouped by session and by tag,
+with how long they took from creation to completion and whether an agent or
+a human completed them. A ball counts as completed by an agent if an agent
+run on one of its sessions was going when it was completed. Notable
+blockers are listed too: balls still blocked that were touched during the
+sprint, and agent runs that ended blocked.
+
+The report is markdown split into sections by "---" lines, so it can be
+shown as slides (e.g. with Marp or reveal.js) or pasted into a document.
+With --json, the report is printed as JSON.
+
+Both dates are inclusive. --to defaults to today and --from to two weeks
+before it.
+
+Examples:
+  juggle report sprint
+  juggle report sprint --from 2026-10-01 --to 2026-10-14 > sprint-review.md
+  juggle report sprint --from 2026-10-01 --json`,
+	Args: cobra.NoArgs,
+	RunE: runReportSprint,
+}
+
+func init() {
+	reportSprintCmd.Flags().StringVar(&sprintFrom, "from", "", "First day of the sprint (YYYY-MM-DD, default: two weeks before --to)")
+	reportSprintCmd.Flags().StringVar(&sprintTo, "to", "", "Last day of the sprint (YYYY-MM-DD, default: today)")
+
+	reportCmd.AddCommand(reportSprintCmd)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// parseSprintDates returns the sprint's first day and the day after its last
+func parseSprintDates(from, to string) (time.Time, time.Time, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, validationErrorf("invalid --to date %q (expected YYYY-MM-DD)", to)
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, 1-sprintDefaultDays)
+	if from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, validationErrorf("invalid --from date %q (expected YYYY-MM-DD)", from)
+		}
+		start = t
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, validationErrorf("--from (%s) is after --to (%s)", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+func runReportSprint(cmd *cobra.Command, args []string) error {
+	from, to, err := parseSprintDates(sprintFrom, sprintTo)
+	if err != nil {
+		return err
+	}
+	balls, sessionIDs, err := loadMilestoneBalls()
+	if err != nil {
+		return err
+	}
+	historyStore, err := newAgentHistoryStoreForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to create history store: %w", err)
+	}
+	runs, err := historyStore.LoadHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load agent history: %w", err)
+	}
+
+	report := session.ReportSprint(from, to, balls, sessionIDs, runs)
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(renderSprintReport(report))
+	return nil
+}
+
+// renderSprintReport renders a sprint report as markdown, one slide per
+// section
+func renderSprintReport(report *session.SprintReport) string {
+	var buf strings.Builder
+	last := report.To.AddDate(0, 0, -1)
+	fmt.Fprintf(&buf, "# Sprint review: %s – %s\n\n", report.From.Format("Jan 2"), last.Format("Jan 2, 2006"))
+
+	if len(report.Completed) == 0 {
+		buf.WriteString("No balls were completed in this sprint.\n")
+	} else {
+		n := len(report.Completed)
+		fmt.Fprintf(&buf, "- **%d** ball(s) completed: %d by agents, %d by hand\n", n, report.ByAgent, n-report.ByAgent)
+		fmt.Fprintf(&buf, "- Average time from creation to completion: %s\n", formatDuration(report.Duration/time.Duration(n)))
+		fmt.Fprintf(&buf, "- Across %d session(s) and %d tag(s)\n", len(report.Sessions), len(report.Tags))
+	}
+	if len(report.Blockers) > 0 {
+		fmt.Fprintf(&buf, "- **%d** blocker(s)\n", len(report.Blockers))
+	}
+
+	if len(report.Completed) > 0 {
+		writeSprintTable(&buf, "By session", "Session", report.Sessions)
+		for _, group := range report.Sessions {
+			fmt.Fprintf(&buf, "\n---\n\n## %s\n\n", sprintGroupName(group))
+			for _, ball := range group.Balls {
+				fmt.Fprintf(&buf, "- `%s` %s _(%s, by %s, %s)_\n", ball.ShortID(), ball.Title,
+					ball.Priority, report.CompletedBy[ball.ID], formatDuration(ball.CompletedAt.Sub(ball.StartedAt)))
+			}
+		}
+		if len(report.Tags) > 0 {
+			writeSprintTable(&buf, "By tag", "Tag", report.Tags)
+		}
+	}
+
+	if len(report.Blockers) > 0 {
+		buf.WriteString("\n---\n\n## Blockers\n\n")
+		for _, blocker := range report.Blockers {
+			reason := blocker.Reason
+			if reason == "" {
+				reason = "no reason given"
+			}
+			if blocker.Ball != nil {
+				fmt.Fprintf(&buf, "- `%s` %s: %s _(still blocked, %s)_\n", blocker.Ball.ShortID(), blocker.Ball.Title, reason, blocker.At.Format("Jan 2"))
+			} else {
+				fmt.Fprintf(&buf, "- Agent run on %s: %s _(%s)_\n", blocker.Session, reason, blocker.At.Format("Jan 2"))
+			}
+		}
+	}
+	return buf.String()
+}
+
+// writeSprintTable writes a slide tabulating a sprint report's groups
+func writeSprintTable(buf *strings.Builder, title, column string, groups []*session.SprintGroup) {
+	fmt.Fprintf(buf, "\n---\n\n## %s\n\n", title)
+	fmt.Fprintf(buf, "| %s | Done | By agent | By hand | Avg. time |\n", column)
+	buf.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	for _, group := range groups {
+		fmt.Fprintf(buf, "| %s | %d | %d | %d | %s |\n", sprintGroupName(group), len(group.Balls),
+			group.ByAgent, len(group.Balls)-group.ByAgent, formatDuration(group.AverageDuration()))
+	}
+}
+
+// sprintGroupName returns a group's session or tag, or "(no session)"
+func sprintGroupName(group *session.SprintGroup) string {
+	if group.Name == "" {
+		return "(no session)"
+	}
+	return group.Name
+}
@@ -30,6 +30,7 @@ Task operations:
 
 Task states: pending → in_progress → complete (or blocked)`,
 	RunE:                       runRootCommand,
+	PersistentPreRunE:          startProfiling,
 	Args:                       cobra.ArbitraryArgs,
 	DisableFlagParsing:         false,
 	FParseErrWhitelist:         cobra.FParseErrWhitelist{UnknownFlags: true},
@@ -43,6 +44,8 @@ type GlobalOptions struct {
 	AllProjects bool   // Enable cross-project discovery (default is local only)
 	JSONOutput  bool   // Output as JSON
 	EditTUI     bool   // Open TUI editor for ball
+	Profile     bool   // Record timings to the local profile log
+	ProfileAddr string // Serve pprof endpoints on this address while profiling
 }
 
 // GlobalOpts holds the parsed global flags (exported for testing)
@@ -75,9 +78,15 @@ func GetConfigOptions() session.ConfigOptions {
 	return opts
 }
 
-// NewStoreForCommand creates a Store with configuration from global flags
+// NewStoreForCommand creates a Store with configuration from global flags,
+// compacting its balls file first if it has grown past the project's threshold
 func NewStoreForCommand(projectDir string) (*session.Store, error) {
-	return session.NewStoreWithConfig(projectDir, GetStoreConfig())
+	store, err := session.NewStoreWithConfig(projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, err
+	}
+	autoCompact(store)
+	return store, nil
 }
 
 // LoadConfigForCommand loads Config with options from global flags
@@ -106,9 +115,9 @@ func SetVersion(v string) {
 	rootCmd.Version = v
 }
 
-// Execute runs the root command
+// Execute runs the root command. Errors are returned for ReportError to print.
 func Execute() error {
-	return rootCmd.Execute()
+	return executeRoot()
 }
 
 // BallsListOptions holds options for the balls list command
@@ -193,6 +202,8 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&GlobalOpts.AllProjects, "all", "a", false, "Search across all discovered projects")
 	rootCmd.PersistentFlags().BoolVar(&GlobalOpts.JSONOutput, "json", false, "Output as JSON")
 	rootCmd.PersistentFlags().BoolVarP(&GlobalOpts.EditTUI, "edit", "e", false, "Open TUI editor for ball")
+	rootCmd.PersistentFlags().BoolVar(&GlobalOpts.Profile, "profile", false, "Record render, load, watcher and prompt timings to ~/.juggle/profile.jsonl")
+	rootCmd.PersistentFlags().StringVar(&GlobalOpts.ProfileAddr, "profile-addr", "", "Serve pprof endpoints on this address while profiling (e.g. localhost:6060)")
 
 	// Set custom help function
 	defaultHelpFunc = rootCmd.HelpFunc()
@@ -211,6 +222,7 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(projectsCmd)
 	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(tagCmd)
 	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(planCmd)
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// buildRunBudget gathers the envelope of an agent run from its settings,
+// the session's workable balls, its prompt and its past runs. The history
+// is best-effort: without it the preview has no time estimate.
+func buildRunBudget(projectDir, sessionID, ballID string, interactive bool, iterations int, delay time.Duration, promptChars int) (session.RunBudget, error) {
+	workable, _, _, err := countWorkableBalls(projectDir, sessionID, ballID, interactive)
+	if err != nil {
+		return session.RunBudget{}, err
+	}
+	budget := session.RunBudget{
+		ActionableBalls: workable,
+		MaxIterations:   iterations,
+		Timeout:         agentTimeout,
+		IterDelay:       delay,
+		PromptChars:     promptChars,
+	}
+	if historyStore, err := session.NewAgentHistoryStoreWithConfig(projectDir, GetStoreConfig()); err == nil {
+		if records, err := historyStore.LoadHistoryBySession(sessionID); err == nil {
+			budget.UseHistory(records)
+		}
+	}
+	return budget, nil
+}
+
+// printRunBudget shows a run's budget preview
+func printRunBudget(budget session.RunBudget) {
+	fmt.Println("=== Budget Preview ===")
+	fmt.Printf("Actionable balls: %d\n", budget.ActionableBalls)
+	fmt.Printf("Max iterations: %d", budget.MaxIterations)
+	if budget.HistoryRuns > 0 {
+		fmt.Printf(" (expect about %d)", budget.ExpectedIterations())
+	}
+	fmt.Println()
+	if budget.Timeout > 0 {
+		fmt.Printf("Timeout per iteration: %v\n", budget.Timeout)
+	} else {
+		fmt.Println("Timeout per iteration: none")
+	}
+	if budget.IterDelay > 0 {
+		fmt.Printf("Delay between iterations: %v\n", budget.IterDelay)
+	}
+	fmt.Printf("Prompt size: ~%d tokens (%d characters), up to ~%d tokens over the run\n",
+		budget.PromptTokens(), budget.PromptChars, budget.MaxPromptTokens())
+
+	if budget.HistoryRuns > 0 {
+		fmt.Printf("History: %s and %.1f balls completed per iteration (%d past run(s))\n",
+			formatDuration(budget.AvgIteration), budget.AvgBallsPerIteration, budget.HistoryRuns)
+		fmt.Printf("Expected time: about %s\n", formatDuration(budget.ExpectedDuration()))
+	} else {
+		fmt.Println("History: no past runs for this session, so no time estimate")
+	}
+	if longest := budget.MaxDuration(); longest > 0 {
+		fmt.Printf("Longest possible: %s, plus rate limit waits\n", formatDuration(longest))
+	} else {
+		fmt.Println("Longest possible: unbounded (set --timeout to cap each iteration)")
+	}
+	fmt.Println()
+}
+
+// confirmRunBudget shows the budget preview for an agent run and asks
+// whether to start it
+func confirmRunBudget(projectDir, sessionID, ballID string, iterations int, delay time.Duration, message string) (bool, error) {
+	prompt, err := generateAgentPrompt(projectDir, sessionID, false, ballID, message, session.ScrubReport{})
+	if err != nil {
+		return false, fmt.Errorf("failed to generate prompt: %w", err)
+	}
+	budget, err := buildRunBudget(projectDir, sessionID, ballID, false, iterations, delay, len(prompt))
+	if err != nil {
+		return false, err
+	}
+	fmt.Println()
+	printRunBudget(budget)
+	return ConfirmSingleKey("Start the run?")
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/vcs"
+)
+
+// safeModeRun tracks the snapshot taken before a safe-mode agent run so the
+// working tree can be put back if the run fails
+type safeModeRun struct {
+	backend     vcs.VCS
+	projectDir  string
+	runID       string
+	snapshot    string
+	exclude     []string
+	interrupted atomic.Bool
+	stopSignals func()
+}
+
+// startSafeMode snapshots the working tree and starts watching for Ctrl+C,
+// so a cancelled run can still be offered a revert
+func startSafeMode(projectDir, workDir, runID string) (*safeModeRun, error) {
+	backend := getVCSBackendForProject(projectDir)
+
+	snapshot, err := backend.Snapshot(workDir, runID)
+	if err != nil {
+		return nil, fmt.Errorf("safe mode: %w", err)
+	}
+	fmt.Printf("🛟 Safe mode: snapshot %s taken (%s)\n\n", shortSnapshot(snapshot), backend.Type())
+
+	s := &safeModeRun{
+		backend:    backend,
+		projectDir: workDir,
+		runID:      runID,
+		snapshot:   snapshot,
+		// Balls, progress and history belong to juggle, not the agent's work
+		exclude: []string{".juggle"},
+	}
+
+	// The agent subprocess receives Ctrl+C too, so the first one ends the
+	// current iteration and the loop stops. A second one exits immediately.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if s.interrupted.Swap(true) {
+					os.Exit(130) // Conventional exit code for SIGINT
+				}
+				fmt.Fprintln(os.Stderr, "\n⏹ Cancelling agent run (Ctrl+C again to exit without reverting)...")
+			case <-done:
+				return
+			}
+		}
+	}()
+	s.stopSignals = func() {
+		signal.Stop(signals)
+		close(done)
+	}
+	return s, nil
+}
+
+// Interrupted reports whether the run was cancelled with Ctrl+C
+func (s *safeModeRun) Interrupted() bool {
+	return s != nil && s.interrupted.Load()
+}
+
+// safeModeFailure returns why a run counts as failed for safe mode, or ""
+// if its changes should be kept
+func safeModeFailure(result *AgentResult, runErr error) string {
+	switch {
+	case runErr != nil:
+		return runErr.Error()
+	case result == nil:
+		return ""
+	case result.Cancelled:
+		return "cancelled"
+	case result.Brownout != nil:
+		return fmt.Sprintf("agent failed %d times in a row", result.Brownout.Errors)
+	case result.TimedOut:
+		return "timed out"
+	case result.ValidationFailures > 0 && !result.Complete:
+		return fmt.Sprintf("%d validation failure(s)", result.ValidationFailures)
+	}
+	return ""
+}
+
+// finish decides what happens to the run's changes. Successful runs keep
+// them. Failed runs are reverted to the snapshot when safe_mode_revert is
+// "auto", or after confirmation when it is "ask".
+func (s *safeModeRun) finish(failure string) *session.SafeModeOutcome {
+	s.stopSignals()
+
+	outcome := &session.SafeModeOutcome{Snapshot: s.snapshot, Failure: failure}
+	if failure == "" {
+		outcome.Decision = session.SafeModeKept
+		_ = s.backend.DropSnapshot(s.projectDir, s.runID)
+		return outcome
+	}
+
+	policy := session.SafeModeRevertAsk
+	if config, err := session.LoadConfigWithOptions(GetConfigOptions()); err == nil {
+		policy = config.GetSafeModeRevert()
+	}
+
+	revert := policy == session.SafeModeRevertAuto
+	if !revert && isTerminal(os.Stdin.Fd()) {
+		fmt.Println()
+		confirmed, err := ConfirmSingleKey(fmt.Sprintf("🛟 Run failed (%s). Revert to snapshot %s?", failure, shortSnapshot(s.snapshot)))
+		revert = err == nil && confirmed
+	}
+	if !revert {
+		outcome.Decision = session.SafeModeDeclined
+		return outcome
+	}
+
+	// Keep the failed state around in case something in it is worth saving
+	failed, err := s.backend.Snapshot(s.projectDir, s.runID+"-failed")
+	if err == nil {
+		outcome.FailedSnapshot = failed
+	}
+	if err := s.backend.RestoreSnapshot(s.projectDir, s.snapshot, s.exclude); err != nil {
+		outcome.Decision = session.SafeModeRevertFailed
+		outcome.Error = err.Error()
+		return outcome
+	}
+	outcome.Decision = session.SafeModeReverted
+	_ = s.backend.DropSnapshot(s.projectDir, s.runID)
+	return outcome
+}
+
+// printSafeModeOutcome reports what safe mode did with the run's changes
+func printSafeModeOutcome(outcome *session.SafeModeOutcome) {
+	switch outcome.Decision {
+	case session.SafeModeKept:
+		fmt.Println("🛟 Safe mode: run succeeded, changes kept")
+	case session.SafeModeReverted:
+		fmt.Printf("🛟 Safe mode: run failed (%s), reverted to snapshot %s\n", outcome.Failure, shortSnapshot(outcome.Snapshot))
+		if outcome.FailedSnapshot != "" {
+			fmt.Printf("   Failed state saved as %s\n", outcome.FailedSnapshot)
+		}
+	case session.SafeModeDeclined:
+		fmt.Printf("🛟 Safe mode: run failed (%s), changes kept; snapshot is %s\n", outcome.Failure, outcome.Snapshot)
+	case session.SafeModeRevertFailed:
+		fmt.Printf("⚠️  Safe mode: run failed (%s) but revert failed: %s\n", outcome.Failure, outcome.Error)
+		fmt.Printf("   Snapshot is %s\n", outcome.Snapshot)
+	}
+}
+
+// shortSnapshot abbreviates a commit ID snapshot for display
+func shortSnapshot(snapshot string) string {
+	if len(snapshot) > 12 {
+		return snapshot[:12]
+	}
+	return snapshot
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -13,38 +15,73 @@ var (
 	searchTags     string
 	searchState    string
 	searchPriority string
+	searchSession  string
+	searchProject  string
 )
 
 var searchCmd = &cobra.Command{
-	Use:   "search <query>",
-	Short: "Search for active balls by intent, tags, or other criteria",
-	Long: `Search through active balls (excluding complete) by matching against:
-  - Intent text (case-insensitive)
-  - Tags
-  - State
-  - Priority
+	Use:   "search [query]",
+	Short: "Full-text search across balls, sessions, progress and archives",
+	Long: `Search balls, sessions and progress logs for text (case-insensitive).
 
-The query string will be matched against ball intents. Use flags for more specific filtering.
+A query matches ball IDs, titles, next actions, contexts, acceptance
+criteria, tags, blocked reasons and completion notes, in both active and
+archived balls, as well as session descriptions, contexts, acceptance
+criteria and progress logs. Each result lists the lines that matched, with
+the match highlighted.
+
+Without a query, lists the active balls that pass the filters (add
+--state complete for archived balls).
 
 By default, searches the current project only. Use --all to search across all discovered projects.
 
 Examples:
-  juggle search bug                    # Search current project for "bug"
-  juggle search --all feature          # Search all projects for "feature"
-  juggle search --tags backend         # Search by tags
-  juggle search --state blocked        # Search by state
-  juggle search --priority high        # Search by priority`,
+  juggle search bug                         # Search current project for "bug"
+  juggle search --all "rate limit"          # Search all projects
+  juggle search --all oauth --project api   # Only the project named api
+  juggle search timeout --session auth      # Balls in a session, and its progress
+  juggle search flaky --state pending,blocked
+  juggle search --tags backend              # List by tags
+  juggle search --priority high             # List by priority`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSearch,
 }
 
 func init() {
-	searchCmd.Flags().StringVar(&searchTags, "tags", "", "Filter by tags (comma-separated, OR logic)")
-	searchCmd.Flags().StringVar(&searchState, "state", "", "Filter by state (pending|in_progress|blocked|complete)")
-	searchCmd.Flags().StringVar(&searchPriority, "priority", "", "Filter by priority (low|medium|high|urgent)")
+	searchCmd.Flags().StringVar(&searchTags, "tags", "", "Filter balls by tags (comma-separated, OR logic)")
+	searchCmd.Flags().StringVar(&searchState, "state", "", "Filter balls by state (comma-separated: pending|in_progress|blocked|complete)")
+	searchCmd.Flags().StringVar(&searchPriority, "priority", "", "Filter balls by priority (low|medium|high|urgent)")
+	searchCmd.Flags().StringVar(&searchSession, "session", "", "Only balls in this session, and the session's own context and progress")
+	searchCmd.Flags().StringVar(&searchProject, "project", "", "Only the project with this directory name or path")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
+	query := session.SearchQuery{
+		Session: searchSession,
+		Project: searchProject,
+	}
+	if len(args) > 0 {
+		query.Text = strings.TrimSpace(args[0])
+	}
+	if searchState != "" {
+		for _, state := range strings.Split(searchState, ",") {
+			state = strings.TrimSpace(state)
+			if !session.ValidateBallState(state) {
+				return validationErrorf("invalid state: %s (must be pending|in_progress|blocked|complete)", state)
+			}
+			query.States = append(query.States, session.BallState(state))
+		}
+	}
+	if searchPriority != "" && !session.ValidatePriority(searchPriority) {
+		return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", searchPriority)
+	}
+	var tags []string
+	if searchTags != "" {
+		for _, tag := range strings.Split(searchTags, ",") {
+			tags = append(tags, strings.TrimSpace(tag))
+		}
+	}
+
 	// Get current directory
 	cwd, err := GetWorkingDir()
 	if err != nil {
@@ -73,131 +110,113 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Load all balls from discovered projects
-	allBalls, err := session.LoadAllBalls(projects)
+	results, err := session.Search(projects, query)
 	if err != nil {
-		return fmt.Errorf("failed to load balls: %w", err)
-	}
-
-	// Filter to non-complete balls
-	activeBalls := make([]*session.Ball, 0)
-	for _, ball := range allBalls {
-		if ball.State != session.StateComplete {
-			activeBalls = append(activeBalls, ball)
-		}
+		return fmt.Errorf("failed to search: %w", err)
 	}
 
-	// Apply query filter if provided
-	var query string
-	if len(args) > 0 {
-		query = strings.ToLower(args[0])
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			if strings.Contains(strings.ToLower(ball.Title), query) {
-				filtered = append(filtered, ball)
+	// Tag and priority filters only apply to balls, so they exclude sessions
+	if len(tags) > 0 || searchPriority != "" {
+		filtered := make([]*session.SearchResult, 0, len(results))
+		for _, result := range results {
+			ball := result.Ball
+			if ball == nil || len(tags) > 0 && !ballHasAnyTag(ball, tags) || searchPriority != "" && string(ball.Priority) != searchPriority {
+				continue
 			}
+			filtered = append(filtered, result)
 		}
-		activeBalls = filtered
+		results = filtered
 	}
 
-	// Apply tag filter if specified
-	if searchTags != "" {
-		tagList := strings.Split(searchTags, ",")
-		for i := range tagList {
-			tagList[i] = strings.TrimSpace(tagList[i])
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
+		fmt.Println(string(data))
+		return nil
+	}
 
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			hasTag := false
-			for _, filterTag := range tagList {
-				for _, ballTag := range ball.Tags {
-					if ballTag == filterTag {
-						hasTag = true
-						break
-					}
-				}
-				if hasTag {
-					break
-				}
-			}
-			if hasTag {
-				filtered = append(filtered, ball)
-			}
-		}
-		activeBalls = filtered
+	if len(results) == 0 {
+		fmt.Println("No results found matching search criteria.")
+		printSearchCriteria(query.Text)
+		return nil
 	}
 
-	// Apply state filter if specified
-	if searchState != "" {
-		if !session.ValidateBallState(searchState) {
-			return fmt.Errorf("invalid state: %s (must be pending|in_progress|blocked|complete)", searchState)
-		}
+	fmt.Printf("Found %d result(s)\n", len(results))
+	printSearchCriteria(query.Text)
+	fmt.Println()
 
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			if string(ball.State) == searchState {
-				filtered = append(filtered, ball)
-			}
+	// Without a query there are no matches to show, just the balls
+	if query.Text == "" {
+		balls := make([]*session.Ball, len(results))
+		for i, result := range results {
+			balls[i] = result.Ball
 		}
-		activeBalls = filtered
+		renderSearchResults(balls)
+		return nil
 	}
+	renderSearchMatches(results)
+	return nil
+}
 
-	// Apply priority filter if specified
+// printSearchCriteria lists the query and filters that were applied
+func printSearchCriteria(query string) {
+	if query == "" && searchTags == "" && searchState == "" && searchPriority == "" && searchSession == "" && searchProject == "" {
+		return
+	}
+	fmt.Println("Search criteria:")
+	if query != "" {
+		fmt.Printf("  Query: \"%s\"\n", query)
+	}
+	if searchTags != "" {
+		fmt.Printf("  Tags: %s\n", searchTags)
+	}
+	if searchState != "" {
+		fmt.Printf("  State: %s\n", searchState)
+	}
 	if searchPriority != "" {
-		if !session.ValidatePriority(searchPriority) {
-			return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", searchPriority)
-		}
-
-		filtered := make([]*session.Ball, 0)
-		for _, ball := range activeBalls {
-			if string(ball.Priority) == searchPriority {
-				filtered = append(filtered, ball)
-			}
-		}
-		activeBalls = filtered
+		fmt.Printf("  Priority: %s\n", searchPriority)
 	}
-
-	if len(activeBalls) == 0 {
-		fmt.Println("No balls found matching search criteria.")
-		if query != "" {
-			fmt.Printf("  Query: \"%s\"\n", query)
-		}
-		if searchTags != "" {
-			fmt.Printf("  Tags: %s\n", searchTags)
-		}
-		if searchState != "" {
-			fmt.Printf("  State: %s\n", searchState)
-		}
-		if searchPriority != "" {
-			fmt.Printf("  Priority: %s\n", searchPriority)
-		}
-		return nil
+	if searchSession != "" {
+		fmt.Printf("  Session: %s\n", searchSession)
 	}
+	if searchProject != "" {
+		fmt.Printf("  Project: %s\n", searchProject)
+	}
+}
 
-	// Show search criteria
-	fmt.Printf("Found %d ball(s)\n", len(activeBalls))
-	if query != "" || searchTags != "" || searchState != "" || searchPriority != "" {
-		fmt.Println("Search criteria:")
-		if query != "" {
-			fmt.Printf("  Query: \"%s\"\n", query)
-		}
-		if searchTags != "" {
-			fmt.Printf("  Tags: %s\n", searchTags)
-		}
-		if searchState != "" {
-			fmt.Printf("  State: %s\n", searchState)
+// searchMatchesShown is how many matches are listed under each result
+const searchMatchesShown = 3
+
+// renderSearchMatches prints each result with the lines that matched
+func renderSearchMatches(results []*session.SearchResult) {
+	for _, result := range results {
+		project := StyleProject.Render("[" + filepath.Base(result.ProjectDir) + "]")
+		if ball := result.Ball; ball != nil {
+			state := string(ball.State)
+			if result.Archived {
+				state += ", archived"
+			}
+			fmt.Printf("%s  %s  %s  %s\n", StyleHighlight.Render(ball.ID), StyleDim.Render("("+state+")"), ball.Title, project)
+		} else {
+			fmt.Printf("%s %s  %s  %s\n", StyleDim.Render("session"), StyleHighlight.Render(result.Session.ID), result.Session.Description, project)
 		}
-		if searchPriority != "" {
-			fmt.Printf("  Priority: %s\n", searchPriority)
+
+		for i, match := range result.Matches {
+			if i == searchMatchesShown {
+				fmt.Println(StyleDim.Render(fmt.Sprintf("    … %d more", len(result.Matches)-i)))
+				break
+			}
+			fmt.Printf("    %s %s\n", StyleDim.Render(match.Field+":"), highlightMatch(match))
 		}
 		fmt.Println()
 	}
+}
 
-	// Display results
-	renderSearchResults(activeBalls)
-
-	return nil
+// highlightMatch renders a match's snippet with the matched text highlighted
+func highlightMatch(match session.SearchMatch) string {
+	return match.Snippet[:match.Start] + StyleMatch.Render(match.Snippet[match.Start:match.End]) + match.Snippet[match.End:]
 }
 
 func renderSearchResults(balls []*session.Ball) {
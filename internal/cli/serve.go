@@ -0,0 +1,1159 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ohare93/juggle/internal/intake"
+	"github.com/ohare93/juggle/internal/profile"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen      string
+	serveToken       string
+	serveIntake      bool
+	serveIntakeToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve balls, sessions and agent runs over a local HTTP API",
+	Long: `Serve the current project over a local HTTP API, so editor plugins and
+scripts can work with juggle without running a command for every operation.
+
+Requests and responses are JSON. Errors are {"error", "code", "exit_code", "hint"},
+as with --json, and use 404 for not_found, 400 for usage and validation,
+409 for lock_held and 500 otherwise.
+
+  GET    /api/balls                     List balls (?session=, ?state=, ?archived=true)
+  POST   /api/balls                     Create a ball
+  GET    /api/balls/{id}                Show a ball (full ID, short ID, or prefix)
+  PATCH  /api/balls/{id}                Update title, priority, state, tags, ...
+  GET    /api/sessions                  List sessions
+  GET    /api/sessions/{id}             Show a session with its progress and balls
+  GET    /api/sessions/{id}/progress    Show a session's progress log
+  POST   /api/sessions/{id}/progress    Append {"text": "..."} to the progress log
+  GET    /api/agents                    List agent runs started by this server
+  POST   /api/agents                    Start 'juggle agent run' ({"session", "ball", ...})
+  GET    /api/agents/{id}               Show an agent run
+  DELETE /api/agents/{id}               Cancel an agent run
+  GET    /api/agents/{id}/events        Stream a run's output as server-sent events
+
+The events stream replays the run's output so far as "output" events, one
+per line, then follows it live and ends with an "exit" event.
+
+With --intake, other systems can propose balls for the triage inbox (see
+'juggle intake'):
+
+  POST   /api/intake                    Propose a ball ({"title", "body", "priority",
+                                        "tags", "source", "from", "id"})
+  POST   /api/intake/email              Propose a ball from a raw email (message/rfc822)
+  GET    /api/intake                    List the inbox
+
+A repeated "id" (or email Message-ID) is dropped, so senders can retry.
+--intake-token is a second token that's only accepted by the two POST
+endpoints, as a bearer token or ?token=, for senders that shouldn't get the
+rest of the API.
+
+The server listens on 127.0.0.1 by default. With --token, every request must
+send "Authorization: Bearer <token>"; a token is required to listen on an
+address other than loopback.
+
+With --profile, the pprof endpoints are served at /debug/pprof/ and the
+running timings summary at /debug/juggle/profile, behind the same token.
+
+So web pages open in a browser can't use the API, requests from another
+origin are refused, POST and PATCH bodies must be sent as application/json
+(message/rfc822 for /api/intake/email), and without --token the Host header
+must name a loopback address.
+
+Examples:
+  juggle serve                                   # http://127.0.0.1:7171/api
+  juggle serve --listen 127.0.0.1:0              # Any free port
+  juggle serve --token "$(openssl rand -hex 16)"
+  juggle serve --intake --intake-token "$(openssl rand -hex 16)"
+  juggle --profile serve                         # Also serve /debug/pprof/
+  curl -s localhost:7171/api/balls?session=auth`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:7171", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Require this bearer token on every request")
+	serveCmd.Flags().BoolVar(&serveIntake, "intake", false, "Accept proposed balls for the triage inbox at /api/intake")
+	serveCmd.Flags().StringVar(&serveIntakeToken, "intake-token", "", "Also accept this token, but only for proposing balls")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// apiRunMaxLines caps the output lines kept per agent run; older lines are
+// dropped from replays
+const apiRunMaxLines = 5000
+
+// apiMaxBody caps the size of request bodies
+const apiMaxBody = 1 << 20
+
+// apiServer serves one project's balls, sessions and agent runs
+type apiServer struct {
+	projectDir string
+	store      *session.Store
+	token      string
+
+	intake      *session.IntakeStore // Nil unless --intake
+	intakeToken string
+
+	mu      sync.Mutex // Guards runs and nextRun
+	runs    map[string]*apiRun
+	nextRun int
+}
+
+// apiRunStatus is what the API reports about an agent run
+type apiRunStatus struct {
+	ID        string     `json:"id"`
+	Session   string     `json:"session"`
+	BallID    string     `json:"ball_id,omitempty"`
+	PID       int        `json:"pid"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	ExitCode  int        `json:"exit_code"`
+	Error     string     `json:"error,omitempty"`
+	Cancelled bool       `json:"cancelled,omitempty"`
+}
+
+// apiRun is an agent run started through the API
+type apiRun struct {
+	mu      sync.Mutex // Guards status and the output
+	status  apiRunStatus
+	lines   []string      // Output lines, oldest dropped past apiRunMaxLines
+	dropped int           // Lines dropped from the front of lines
+	changed chan struct{} // Closed and replaced whenever output arrives or the run ends
+	proc    *exec.Cmd
+}
+
+// apiBallRequest is the body of POST /api/balls and PATCH /api/balls/{id}.
+// For PATCH, only the fields present are changed.
+type apiBallRequest struct {
+	Title              *string   `json:"title"`
+	Context            *string   `json:"context"`
+	Priority           *string   `json:"priority"`
+	State              *string   `json:"state"`
+	BlockedReason      string    `json:"blocked_reason"`
+	NextAction         *string   `json:"next_action"`
+	Checkpoint         *string   `json:"checkpoint"`
+	Tags               *[]string `json:"tags"`
+	Session            string    `json:"session"` // Tag added on create
+	AcceptanceCriteria *[]string `json:"acceptance_criteria"`
+	DependsOn          *[]string `json:"depends_on"`
+}
+
+// apiIntakeRequest is the body of POST /api/intake
+type apiIntakeRequest struct {
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	Priority string   `json:"priority"`
+	Tags     []string `json:"tags"`
+	Source   string   `json:"source"` // Sending system, e.g. "grafana"
+	From     string   `json:"from"`   // Reporter
+	ID       string   `json:"id"`     // Sender's own ID; repeats are dropped
+}
+
+// apiIntakeResponse is the response to proposing a ball
+type apiIntakeResponse struct {
+	Item  *session.IntakeItem `json:"item"`
+	Added bool                `json:"added"` // False if the item was already in the inbox
+}
+
+// apiAgentRequest is the body of POST /api/agents
+type apiAgentRequest struct {
+	Session    string `json:"session"`
+	Ball       string `json:"ball"`
+	Iterations int    `json:"iterations"`
+	Provider   string `json:"provider"`
+	Model      string `json:"model"`
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	host, _, err := net.SplitHostPort(serveListen)
+	if err != nil {
+		return validationErrorf("invalid --listen address %q: %v", serveListen, err)
+	}
+	if serveToken == "" && !isLoopbackHost(host) {
+		return validationErrorf("--token is required to listen on %s (the API can change balls and start agents)", serveListen)
+	}
+
+	if serveIntakeToken != "" && !serveIntake {
+		return usageErrorf("--intake-token needs --intake")
+	}
+
+	server, err := newAPIServer(cwd, serveToken)
+	if err != nil {
+		return err
+	}
+	if serveIntake {
+		if err := server.enableIntake(serveIntakeToken); err != nil {
+			return err
+		}
+	}
+	listener, err := net.Listen("tcp", serveListen)
+	if err != nil {
+		return validationErrorf("failed to listen on %s: %v", serveListen, err)
+	}
+	httpServer := &http.Server{Handler: server.handler()}
+	errs := make(chan error, 1)
+	go func() { errs <- httpServer.Serve(listener) }()
+	fmt.Printf("Serving %s at http://%s/api\n", server.projectDir, listener.Addr())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-signals:
+	}
+	running := server.cancelAll()
+	fmt.Printf("Stopping: waiting for %d agent run(s) to finish\n", len(running))
+	for _, run := range running {
+		run.wait()
+	}
+	return httpServer.Close()
+}
+
+// NewServeHandlerForTest returns the API handler `juggle serve` would use
+// for projectDir
+func NewServeHandlerForTest(projectDir, token string) (http.Handler, error) {
+	server, err := newAPIServer(projectDir, token)
+	if err != nil {
+		return nil, err
+	}
+	return server.handler(), nil
+}
+
+// NewServeIntakeHandlerForTest returns the API handler `juggle serve --intake`
+// would use for projectDir
+func NewServeIntakeHandlerForTest(projectDir, token, intakeToken string) (http.Handler, error) {
+	server, err := newAPIServer(projectDir, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := server.enableIntake(intakeToken); err != nil {
+		return nil, err
+	}
+	return server.handler(), nil
+}
+
+func newAPIServer(projectDir, token string) (*apiServer, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return &apiServer{
+		projectDir: store.ProjectDir(),
+		store:      store,
+		token:      token,
+		runs:       make(map[string]*apiRun),
+	}, nil
+}
+
+// enableIntake serves the intake endpoints, also accepting intakeToken (if
+// set) on the ones that propose balls
+func (s *apiServer) enableIntake(intakeToken string) error {
+	inbox, err := session.NewIntakeStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create intake store: %w", err)
+	}
+	s.intake = inbox
+	s.intakeToken = intakeToken
+	return nil
+}
+
+// handler routes the API's endpoints
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/balls", s.listBalls)
+	mux.HandleFunc("POST /api/balls", s.createBall)
+	mux.HandleFunc("GET /api/balls/{id}", s.getBall)
+	mux.HandleFunc("PATCH /api/balls/{id}", s.updateBall)
+	mux.HandleFunc("GET /api/sessions", s.listSessions)
+	mux.HandleFunc("GET /api/sessions/{id}", s.getSession)
+	mux.HandleFunc("GET /api/sessions/{id}/progress", s.getProgress)
+	mux.HandleFunc("POST /api/sessions/{id}/progress", s.appendProgress)
+	mux.HandleFunc("GET /api/agents", s.listRuns)
+	mux.HandleFunc("POST /api/agents", s.startRun)
+	mux.HandleFunc("GET /api/agents/{id}", s.getRun)
+	mux.HandleFunc("DELETE /api/agents/{id}", s.cancelRun)
+	mux.HandleFunc("GET /api/agents/{id}/events", s.streamRun)
+	if s.intake != nil {
+		mux.HandleFunc("GET /api/intake", s.listIntake)
+		mux.HandleFunc("POST /api/intake", s.proposeBall)
+		mux.HandleFunc("POST /api/intake/email", s.proposeEmail)
+	}
+	if profile.Enabled() {
+		profiling := profile.Handler()
+		mux.Handle("/debug/pprof/", profiling)
+		mux.Handle("/debug/juggle/profile", profiling)
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, notFoundErrorf("no such endpoint: %s %s", r.Method, r.URL.Path))
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := s.checkOrigin(r); err != nil {
+			writeAPIJSON(w, http.StatusForbidden, jsonError{Error: err.Error(), Code: CodeUsage, Exit: ExitUsage})
+			return
+		}
+		if !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAPIJSON(w, http.StatusUnauthorized, jsonError{Error: "missing or invalid bearer token", Code: CodeUsage, Exit: ExitUsage})
+			return
+		}
+		if err := checkContentType(r); err != nil {
+			writeAPIJSON(w, http.StatusUnsupportedMediaType, jsonError{Error: err.Error(), Code: CodeUsage, Exit: ExitUsage})
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, apiMaxBody)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// checkOrigin refuses requests a browser sent from another origin, and,
+// without a token, requests whose Host isn't loopback, which is how a DNS
+// rebinding page reaches the API under its own name
+func (s *apiServer) checkOrigin(r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("cross-origin requests are not allowed (origin %s)", origin)
+		}
+	}
+	if s.token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if !isLoopbackHost(strings.Trim(host, "[]")) {
+		return fmt.Errorf("host %s is not loopback; use --token to serve other hosts", r.Host)
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback address
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkContentType refuses API request bodies that aren't JSON (or an
+// email, for /api/intake/email). Browsers send other types, like
+// text/plain, cross-origin without asking first.
+func checkContentType(r *http.Request) error {
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		return nil
+	}
+	switch r.Method {
+	case http.MethodPost, http.MethodPatch:
+	case http.MethodDelete:
+		if r.ContentLength == 0 {
+			return nil
+		}
+	default:
+		return nil
+	}
+	want := "application/json"
+	if r.URL.Path == "/api/intake/email" {
+		want = "message/rfc822"
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != want {
+		return fmt.Errorf("request body must be sent as %s", want)
+	}
+	return nil
+}
+
+// authorized reports whether a request has the token, or the intake token
+// for an intake endpoint that proposes balls
+func (s *apiServer) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if s.token == "" || auth == "Bearer "+s.token {
+		return true
+	}
+	if s.intake == nil || s.intakeToken == "" || r.Method != http.MethodPost {
+		return false
+	}
+	if r.URL.Path != "/api/intake" && r.URL.Path != "/api/intake/email" {
+		return false
+	}
+	return auth == "Bearer "+s.intakeToken || r.URL.Query().Get("token") == s.intakeToken
+}
+
+// writeAPIJSON writes v as a JSON response
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// writeAPIError writes err in the same shape as --json errors, with the
+// HTTP status matching its code
+func writeAPIError(w http.ResponseWriter, err error) {
+	cliErr := ClassifyError(err)
+	status := http.StatusInternalServerError
+	switch cliErr.Code {
+	case CodeNotFound:
+		status = http.StatusNotFound
+	case CodeUsage, CodeValidation:
+		status = http.StatusBadRequest
+	case CodeLockHeld:
+		status = http.StatusConflict
+	}
+	writeAPIJSON(w, status, jsonError{Error: cliErr.Message, Code: cliErr.Code, Exit: cliErr.ExitCode(), Hint: cliErr.Hint})
+}
+
+// decodeAPIRequest reads a JSON request body into v
+func decodeAPIRequest(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return usageErrorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func (s *apiServer) listBalls(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	archived, _ := strconv.ParseBool(query.Get("archived"))
+	balls, err := s.filterBalls(query.Get("session"), strings.Split(query.Get("state"), ","), archived)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, balls)
+}
+
+// filterBalls lists the balls tagged with sessionID (any, if empty) in one
+// of states (any, if none), including archived balls if asked
+func (s *apiServer) filterBalls(sessionID string, states []string, archived bool) ([]*session.Ball, error) {
+	balls, err := s.store.ListBalls(session.ListOptions{IncludeArchived: archived})
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, state := range states {
+		if state = strings.TrimSpace(state); state != "" {
+			if !session.ValidateBallState(state) {
+				return nil, validationErrorf("invalid state: %s (must be pending, in_progress, blocked, complete, or researched)", state)
+			}
+			wanted[state] = true
+		}
+	}
+
+	filtered := make([]*session.Ball, 0, len(balls))
+	for _, ball := range balls {
+		if sessionID != "" && !ball.HasTag(sessionID) {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[string(ball.State)] {
+			continue
+		}
+		filtered = append(filtered, ball)
+	}
+	return filtered, nil
+}
+
+// findBall resolves id against active balls (full ID, short ID or prefix)
+// and then the archive, reporting whether the ball is archived
+func (s *apiServer) findBall(id string) (*session.Ball, bool, error) {
+	ball, err := s.store.ResolveBallID(id)
+	if err == nil {
+		return ball, false, nil
+	}
+	if !errors.Is(err, session.ErrBallNotFound) {
+		return nil, false, err
+	}
+	return s.store.GetBallAnywhere(id)
+}
+
+func (s *apiServer) getBall(w http.ResponseWriter, r *http.Request) {
+	ball, _, err := s.findBall(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, ball)
+}
+
+func (s *apiServer) createBall(w http.ResponseWriter, r *http.Request) {
+	var req apiBallRequest
+	if err := decodeAPIRequest(r, &req); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	ball, err := s.addBall(req)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, ball)
+}
+
+// addBall creates and saves a pending ball from req
+func (s *apiServer) addBall(req apiBallRequest) (*session.Ball, error) {
+	if req.Title == nil || strings.TrimSpace(*req.Title) == "" {
+		return nil, validationErrorf("title is required")
+	}
+	priority := session.PriorityMedium
+	if req.Priority != nil {
+		if !session.ValidatePriority(*req.Priority) {
+			return nil, validationErrorf("invalid priority: %s (must be low, medium, high, or urgent)", *req.Priority)
+		}
+		priority = session.Priority(*req.Priority)
+	}
+	if req.Session != "" {
+		if err := s.checkSession(req.Session); err != nil {
+			return nil, err
+		}
+	}
+
+	ball, err := session.NewBall(s.projectDir, strings.TrimSpace(*req.Title), priority)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ball: %w", err)
+	}
+	ball.State = session.StatePending
+	if req.Tags != nil {
+		for _, tag := range *req.Tags {
+			ball.AddTag(tag)
+		}
+	}
+	if req.Session != "" {
+		ball.AddTag(req.Session)
+	}
+	if _, err := s.applyBallRequest(ball, req); err != nil {
+		return nil, err
+	}
+	if err := s.store.AppendBall(ball); err != nil {
+		return nil, fmt.Errorf("failed to save ball: %w", err)
+	}
+	return ball, nil
+}
+
+func (s *apiServer) updateBall(w http.ResponseWriter, r *http.Request) {
+	var req apiBallRequest
+	if err := decodeAPIRequest(r, &req); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	ball, err := s.editBall(r.PathValue("id"), req)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, ball)
+}
+
+// editBall applies req to an active ball and saves it
+func (s *apiServer) editBall(id string, req apiBallRequest) (*session.Ball, error) {
+	ball, archived, err := s.findBall(id)
+	if err != nil {
+		return nil, err
+	}
+	if archived {
+		return nil, validationErrorf("ball %s is archived (unarchive it first)", ball.ID)
+	}
+	if req.Session != "" {
+		return nil, usageErrorf("session only applies when creating a ball (set tags instead)")
+	}
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return nil, validationErrorf("title cannot be empty")
+		}
+		ball.SetTitle(strings.TrimSpace(*req.Title))
+	}
+	if req.Priority != nil {
+		if !session.ValidatePriority(*req.Priority) {
+			return nil, validationErrorf("invalid priority: %s (must be low, medium, high, or urgent)", *req.Priority)
+		}
+		ball.Priority = session.Priority(*req.Priority)
+	}
+	if req.Tags != nil {
+		ball.Tags = []string{}
+		for _, tag := range *req.Tags {
+			ball.AddTag(tag)
+		}
+	}
+	stateChanged, err := s.applyBallRequest(ball, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A completed recurring ball hands its schedule to a fresh instance
+	if stateChanged && (ball.State == session.StateComplete || ball.State == session.StateResearched) {
+		if _, err := s.store.Recur(ball, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	ball.UpdateActivity()
+	if err := s.store.UpdateBall(ball); err != nil {
+		return nil, fmt.Errorf("failed to update ball: %w", err)
+	}
+	return ball, nil
+}
+
+// applyBallRequest applies the fields shared by create and update, returning
+// whether the state changed
+func (s *apiServer) applyBallRequest(ball *session.Ball, req apiBallRequest) (bool, error) {
+	if req.Context != nil {
+		ball.Context = *req.Context
+	}
+	if req.NextAction != nil {
+		ball.SetNextAction(*req.NextAction)
+	}
+	if req.Checkpoint != nil {
+		ball.SetCheckpoint(*req.Checkpoint)
+	}
+	if req.AcceptanceCriteria != nil {
+		ball.SetAcceptanceCriteria(*req.AcceptanceCriteria)
+	}
+	if req.DependsOn != nil {
+		deps, err := resolveDependencyIDsForUpdate(s.store, *req.DependsOn, ball.ID)
+		if err != nil {
+			return false, err
+		}
+		ball.SetDependencies(deps)
+		balls, err := s.store.LoadBalls()
+		if err != nil {
+			return false, fmt.Errorf("failed to load balls for dependency check: %w", err)
+		}
+		replaced := false
+		for i, b := range balls {
+			if b.ID == ball.ID {
+				balls[i] = ball
+				replaced = true
+			}
+		}
+		if !replaced {
+			balls = append(balls, ball)
+		}
+		if err := session.DetectCircularDependencies(balls); err != nil {
+			return false, validationErrorf("dependency error: %v", err)
+		}
+	}
+
+	if req.State == nil {
+		if req.BlockedReason != "" {
+			return false, usageErrorf("blocked_reason only applies when setting state to blocked")
+		}
+		return false, nil
+	}
+	state := session.BallState(*req.State)
+	if state == ball.State {
+		if state == session.StateBlocked && req.BlockedReason != "" {
+			ball.BlockedReason = req.BlockedReason
+		}
+		return false, nil
+	}
+	switch state {
+	case session.StateBlocked:
+		if req.BlockedReason == "" {
+			return false, validationErrorf("blocked_reason is required when setting state to blocked")
+		}
+		if err := ball.SetBlocked(req.BlockedReason); err != nil {
+			return false, err
+		}
+	case session.StateResearched:
+		ball.MarkResearched(ball.Output)
+	case session.StateComplete:
+		if err := checkTestsBeforeComplete(ball); err != nil {
+			return false, err
+		}
+		ball.MarkComplete(ball.CompletionNote)
+	case session.StatePending, session.StateInProgress:
+		if err := ball.SetState(state); err != nil {
+			return false, err
+		}
+	default:
+		return false, validationErrorf("invalid state: %s (must be pending, in_progress, blocked, complete, or researched)", state)
+	}
+	return true, nil
+}
+
+// checkSession returns a not-found error unless the session exists
+func (s *apiServer) checkSession(id string) error {
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if _, err := sessionStore.LoadSession(id); err != nil {
+		return session.NewSessionNotFoundError(id)
+	}
+	return nil
+}
+
+func (s *apiServer) listSessions(w http.ResponseWriter, r *http.Request) {
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		writeAPIError(w, fmt.Errorf("failed to initialize session store: %w", err))
+		return
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		writeAPIError(w, fmt.Errorf("failed to list sessions: %w", err))
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, sessions)
+}
+
+func (s *apiServer) getSession(w http.ResponseWriter, r *http.Request) {
+	detail, err := s.sessionDetail(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, detail)
+}
+
+// apiSessionDetail is a session with its progress log and balls
+type apiSessionDetail struct {
+	*session.JuggleSession
+	Progress string          `json:"progress"`
+	Balls    []*session.Ball `json:"balls"`
+}
+
+// sessionDetail loads a session with its progress log and active balls
+func (s *apiServer) sessionDetail(id string) (*apiSessionDetail, error) {
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sess, err := sessionStore.LoadSession(id)
+	if err != nil {
+		return nil, session.NewSessionNotFoundError(id)
+	}
+	progress, _ := sessionStore.LoadProgress(id)
+	balls, err := s.store.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+	sessionBalls := make([]*session.Ball, 0)
+	for _, ball := range balls {
+		if ball.HasTag(id) {
+			sessionBalls = append(sessionBalls, ball)
+		}
+	}
+	return &apiSessionDetail{sess, progress, sessionBalls}, nil
+}
+
+func (s *apiServer) getProgress(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := s.checkSession(id); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		writeAPIError(w, fmt.Errorf("failed to initialize session store: %w", err))
+		return
+	}
+	progress, err := sessionStore.LoadProgress(id)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]string{"session": id, "progress": progress})
+}
+
+func (s *apiServer) appendProgress(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := decodeAPIRequest(r, &req); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	progress, err := s.addProgress(id, req.Text)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]string{"session": id, "progress": progress})
+}
+
+// addProgress appends text to a session's progress log and returns the log
+func (s *apiServer) addProgress(id, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", validationErrorf("text is required")
+	}
+	if err := s.checkSession(id); err != nil {
+		return "", err
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(s.projectDir, GetStoreConfig())
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	entry := session.ProgressEntry{Author: session.ProgressAuthorHuman, Text: text}
+	if err := sessionStore.AppendProgressEntry(id, entry); err != nil {
+		return "", fmt.Errorf("failed to append progress: %w", err)
+	}
+	progress, _ := sessionStore.LoadProgress(id)
+	return progress, nil
+}
+
+func (s *apiServer) startRun(w http.ResponseWriter, r *http.Request) {
+	var req apiAgentRequest
+	if err := decodeAPIRequest(r, &req); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if req.Session == "" {
+		writeAPIError(w, validationErrorf("session is required"))
+		return
+	}
+	if req.Session != "all" {
+		if err := s.checkSession(req.Session); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+	}
+	if req.Iterations == 0 {
+		req.Iterations = 10
+	}
+	if req.Iterations < 1 {
+		writeAPIError(w, validationErrorf("iterations must be 1 or more"))
+		return
+	}
+	if req.Provider != "" && !session.ValidateAgentProvider(req.Provider) {
+		writeAPIError(w, validationErrorf("invalid agent provider: %s (must be claude|opencode|http|ollama|shell)", req.Provider))
+		return
+	}
+	ballID := ""
+	if req.Ball != "" {
+		ball, archived, err := s.findBall(req.Ball)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		if archived {
+			writeAPIError(w, validationErrorf("ball %s is archived", ball.ID))
+			return
+		}
+		ballID = ball.ID
+	}
+
+	run, err := s.start(req.Session, ballID, agentRunArgs(req.Session, ballID, req.Iterations, req.Provider, req.Model))
+	if err != nil {
+		writeAPIError(w, fmt.Errorf("failed to start agent run: %w", err))
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// start launches an agent run process and follows its output
+func (s *apiServer) start(sessionID, ballID string, args []string) (*apiRun, error) {
+	proc, err := daemonCommand(s.projectDir, args)
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	proc.Stdout = writer
+	proc.Stderr = writer
+	if err := proc.Start(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.nextRun++
+	run := &apiRun{
+		status: apiRunStatus{
+			ID:        strconv.Itoa(s.nextRun),
+			Session:   sessionID,
+			BallID:    ballID,
+			PID:       proc.Process.Pid,
+			StartedAt: time.Now(),
+		},
+		changed: make(chan struct{}),
+		proc:    proc,
+	}
+	s.runs[run.status.ID] = run
+	s.mu.Unlock()
+
+	scanned := make(chan struct{})
+	go func() {
+		defer close(scanned)
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			run.append(scanner.Text())
+		}
+		// Keep draining so the process never blocks on a full pipe
+		io.Copy(io.Discard, reader)
+	}()
+	go func() {
+		err := proc.Wait()
+		writer.Close()
+		<-scanned
+		exitCode := 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+			err = nil
+		} else if err != nil {
+			exitCode = -1
+		}
+		run.finish(exitCode, err)
+	}()
+	return run, nil
+}
+
+// append adds a line of output and wakes the run's followers
+func (r *apiRun) append(line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, line)
+	if len(r.lines) > apiRunMaxLines {
+		drop := len(r.lines) - apiRunMaxLines
+		r.lines = append([]string(nil), r.lines[drop:]...)
+		r.dropped += drop
+	}
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// finish records how the run ended and wakes its followers
+func (r *apiRun) finish(exitCode int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.status.EndedAt = &now
+	r.status.ExitCode = exitCode
+	if err != nil {
+		r.status.Error = err.Error()
+	}
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// since returns the output after line next (counting dropped lines), the
+// next line to ask for, a channel closed when there's more, and whether the
+// run has ended
+func (r *apiRun) since(next int) ([]string, int, <-chan struct{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if next < r.dropped {
+		next = r.dropped
+	}
+	lines := append([]string(nil), r.lines[next-r.dropped:]...)
+	return lines, r.dropped + len(r.lines), r.changed, r.status.EndedAt != nil
+}
+
+// snapshot returns a copy of the run's status for a response
+func (r *apiRun) snapshot() apiRunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// cancel interrupts the run, as Ctrl-C would. Returns false if it had already ended.
+func (r *apiRun) cancel() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status.EndedAt != nil {
+		return false
+	}
+	r.status.Cancelled = true
+	_ = r.proc.Process.Signal(os.Interrupt)
+	return true
+}
+
+// wait blocks until the run has ended
+func (r *apiRun) wait() {
+	for {
+		_, _, changed, done := r.since(0)
+		if done {
+			return
+		}
+		<-changed
+	}
+}
+
+// cancelAll interrupts every run still going and returns them
+func (s *apiServer) cancelAll() []*apiRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var running []*apiRun
+	for _, run := range s.runs {
+		if run.cancel() {
+			running = append(running, run)
+		}
+	}
+	return running
+}
+
+// getRunByID returns the run with the given ID, or a not-found error
+func (s *apiServer) getRunByID(id string) (*apiRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run, ok := s.runs[id]
+	if !ok {
+		return nil, notFoundErrorf("agent run not found: %s", id)
+	}
+	return run, nil
+}
+
+func (s *apiServer) listRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	runs := make([]apiRunStatus, 0, len(s.runs))
+	for _, run := range s.runs {
+		runs = append(runs, run.snapshot())
+	}
+	s.mu.Unlock()
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	writeAPIJSON(w, http.StatusOK, runs)
+}
+
+func (s *apiServer) getRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.getRunByID(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, run.snapshot())
+}
+
+func (s *apiServer) cancelRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.getRunByID(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if !run.cancel() {
+		writeAPIError(w, validationErrorf("agent run %s has already ended", r.PathValue("id")))
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// streamRun sends a run's output as server-sent events: the output so far,
+// then new lines as they arrive, then an "exit" event with the run's state
+func (s *apiServer) streamRun(w http.ResponseWriter, r *http.Request) {
+	run, err := s.getRunByID(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, fmt.Errorf("streaming is not supported by this connection"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	next := 0
+	for {
+		lines, after, changed, done := run.since(next)
+		for _, line := range lines {
+			fmt.Fprintf(w, "event: output\ndata: %s\n\n", line)
+		}
+		next = after
+		if done {
+			data, _ := json.Marshal(run.snapshot())
+			fmt.Fprintf(w, "event: exit\ndata: %s\n\n", data)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *apiServer) listIntake(w http.ResponseWriter, r *http.Request) {
+	items, err := s.intake.LoadIntake()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, items)
+}
+
+func (s *apiServer) proposeBall(w http.ResponseWriter, r *http.Request) {
+	var req apiIntakeRequest
+	if err := decodeAPIRequest(r, &req); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	item, err := session.NewIntakeItem(req.Title, req.Body, session.Priority(req.Priority), session.IntakeSource{
+		Kind: session.IntakeWebhook,
+		Name: req.Source,
+		From: req.From,
+		Ref:  req.ID,
+	})
+	if err != nil {
+		writeAPIError(w, validationErrorf("%v", err))
+		return
+	}
+	item.Tags = req.Tags
+	s.addIntake(w, item)
+}
+
+func (s *apiServer) proposeEmail(w http.ResponseWriter, r *http.Request) {
+	email, err := intake.ParseEmail(r.Body)
+	if err != nil {
+		writeAPIError(w, validationErrorf("%v", err))
+		return
+	}
+	item, err := email.IntakeItem()
+	if err != nil {
+		writeAPIError(w, validationErrorf("%v", err))
+		return
+	}
+	s.addIntake(w, item)
+}
+
+// addIntake adds a proposed ball to the inbox. It's accepted (202) either
+// way, since a repeat means the sender's retry can stop.
+func (s *apiServer) addIntake(w http.ResponseWriter, item *session.IntakeItem) {
+	added, err := s.intake.AddIntake(item)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, apiIntakeResponse{Item: item, Added: added})
+}
@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+const (
+	// sessionContextWarnSize is the context size (in bytes) past which
+	// imports warn that every agent iteration pays for reading it
+	sessionContextWarnSize = 16 * 1024
+
+	// defaultContextSummarizeKB is the imported document size (in KB) past
+	// which the agent condenses it before it becomes session context
+	defaultContextSummarizeKB = 32
+
+	// maxContextFetchSize caps how much of a URL is downloaded
+	maxContextFetchSize = 5 * 1024 * 1024
+
+	contextFetchTimeout = 30 * time.Second
+)
+
+// Flags for importing session context
+var (
+	sessionContextFromFileFlag      string
+	sessionContextFromURLFlag       string
+	sessionContextAppendFlag        bool
+	sessionContextSummarizeOver     int
+	sessionContextSummarizeProvider string
+	sessionContextSummarizeModel    string
+)
+
+// summaryPattern matches the summary emitted by the agent
+var summaryPattern = regexp.MustCompile(`(?s)<summary>(.*?)</summary>`)
+
+// Patterns for extracting readable text from HTML
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlNoisePattern   = regexp.MustCompile(`(?is)<(script|style|noscript|svg|nav|header|footer|aside|form|iframe|template)\b[^>]*>.*?</(script|style|noscript|svg|nav|header|footer|aside|form|iframe|template)\s*>`)
+	htmlMainPattern    = regexp.MustCompile(`(?is)<(article|main)\b[^>]*>(.*)</(article|main)\s*>`)
+	htmlBodyPattern    = regexp.MustCompile(`(?is)<body\b[^>]*>(.*)</body\s*>`)
+	htmlTitlePattern   = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title\s*>`)
+	htmlHeadingPattern = regexp.MustCompile(`(?i)<h([1-6])\b[^>]*>`)
+	htmlItemPattern    = regexp.MustCompile(`(?i)<li\b[^>]*>`)
+	htmlPrePattern     = regexp.MustCompile(`(?is)<pre\b[^>]*>(.*?)</pre\s*>`)
+	htmlBreakPattern   = regexp.MustCompile(`(?i)<br\s*/?>|</?(p|div|section|table|tr|ul|ol|dl|dt|dd|blockquote|h[1-6])\b[^>]*>`)
+	htmlTagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// importSessionContext reads context from a file or URL, condenses it with
+// the agent when it's over the summarize threshold, and sets or appends it
+// to the session's context
+func importSessionContext(store *session.SessionStore, cwd, id, existing string) error {
+	text, source, err := readContextSource(sessionContextFromFileFlag, sessionContextFromURLFlag)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return validationErrorf("%s has no text to import", source)
+	}
+
+	if sessionContextSummarizeOver > 0 && len(text) > sessionContextSummarizeOver*1024 {
+		fmt.Fprintf(os.Stderr, "%s is %s, over the %d KB summarize threshold\n",
+			source, formatContextSize(len(text)), sessionContextSummarizeOver)
+		if err := configureSummarizeAgent(cwd); err != nil {
+			return err
+		}
+		summary, err := summarizeContext(cwd, text, sessionContextSummarizeModel)
+		if err != nil {
+			return fmt.Errorf("failed to summarize %s (use --summarize-over 0 to import it as is): %w", source, err)
+		}
+		fmt.Fprintf(os.Stderr, "Summarized to %s\n", formatContextSize(len(summary)))
+		text = summary
+	}
+
+	context := appendContext(existing, text, sessionContextAppendFlag)
+	if err := store.UpdateSessionContext(id, context); err != nil {
+		return fmt.Errorf("failed to update context: %w", err)
+	}
+
+	verb := "Set"
+	if sessionContextAppendFlag && existing != "" {
+		verb = "Appended"
+	}
+	fmt.Printf("%s context for session %s from %s (%s)\n", verb, id, source, formatContextSize(len(text)))
+	warnContextSize(context)
+	return nil
+}
+
+// readContextSource returns the text of the file ("-" for stdin) or URL to
+// import, and a name for it in messages
+func readContextSource(file, url string) (string, string, error) {
+	if file != "" {
+		if file == "-" {
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read stdin: %w", err)
+			}
+			return string(data), "stdin", nil
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "", "", notFoundErrorf("file not found: %s", file)
+			}
+			return "", "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		return string(data), file, nil
+	}
+
+	text, err := fetchContextURL(url)
+	return text, url, err
+}
+
+// fetchContextURL downloads the URL, extracting the readable text of HTML
+// pages and returning other text as is
+func fetchContextURL(url string) (string, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", validationErrorf("--from-url must be an http or https URL, got %q", url)
+	}
+
+	client := &http.Client{Timeout: contextFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxContextFetchSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	if len(data) > maxContextFetchSize {
+		return "", fmt.Errorf("%s is larger than %s", url, formatContextSize(maxContextFetchSize))
+	}
+
+	body := string(data)
+	contentType := strings.ToLower(resp.Header.Get("Content-Type"))
+	if strings.Contains(contentType, "html") || (contentType == "" && looksLikeHTML(body)) {
+		return extractReadableText(body), nil
+	}
+	return body, nil
+}
+
+// looksLikeHTML reports whether an untyped response body is an HTML page
+func looksLikeHTML(body string) bool {
+	start := strings.ToLower(strings.TrimSpace(body))
+	if len(start) > 512 {
+		start = start[:512]
+	}
+	return strings.HasPrefix(start, "<!doctype html") || strings.Contains(start, "<html")
+}
+
+// extractReadableText reduces an HTML page to its main text: navigation,
+// scripts and other page furniture are dropped, the article or main element
+// is preferred over the whole body, and headings and list items keep a
+// markdown marker so the structure survives
+func extractReadableText(page string) string {
+	page = htmlCommentPattern.ReplaceAllString(page, "")
+	title := ""
+	if match := htmlTitlePattern.FindStringSubmatch(page); match != nil {
+		title = strings.Join(strings.Fields(html.UnescapeString(htmlTagPattern.ReplaceAllString(match[1], ""))), " ")
+	}
+	page = htmlNoisePattern.ReplaceAllString(page, "")
+
+	if match := htmlMainPattern.FindStringSubmatch(page); match != nil {
+		page = match[2]
+	} else if match := htmlBodyPattern.FindStringSubmatch(page); match != nil {
+		page = match[1]
+	}
+
+	// Keep preformatted blocks verbatim by fencing them before whitespace
+	// is collapsed, then restoring them at the end
+	var blocks []string
+	page = htmlPrePattern.ReplaceAllStringFunc(page, func(pre string) string {
+		inner := htmlPrePattern.FindStringSubmatch(pre)[1]
+		code := strings.Trim(html.UnescapeString(htmlTagPattern.ReplaceAllString(inner, "")), "\n")
+		blocks = append(blocks, "```\n"+code+"\n```")
+		return fmt.Sprintf("\n\x00%d\x00\n", len(blocks)-1)
+	})
+
+	page = htmlHeadingPattern.ReplaceAllStringFunc(page, func(tag string) string {
+		level := htmlHeadingPattern.FindStringSubmatch(tag)[1][0] - '0'
+		return "\n\n" + strings.Repeat("#", int(level)) + " "
+	})
+	page = htmlItemPattern.ReplaceAllString(page, "\n- ")
+	page = htmlBreakPattern.ReplaceAllString(page, "\n")
+	page = html.UnescapeString(htmlTagPattern.ReplaceAllString(page, ""))
+
+	var lines []string
+	for _, line := range strings.Split(page, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "-" || line == "#" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, line)
+	}
+	text := strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+
+	for i, block := range blocks {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), block, 1)
+	}
+	if title != "" && !strings.HasPrefix(text, "# ") {
+		text = "# " + title + "\n\n" + text
+	}
+	return text
+}
+
+// configureSummarizeAgent sets up the agent provider and model overrides
+// for summarizing imported context
+func configureSummarizeAgent(cwd string) error {
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	providerType := provider.Detect(sessionContextSummarizeProvider, projectProvider, globalProvider)
+	if !provider.IsAvailable(providerType) {
+		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH); use --summarize-over 0 to import without summarizing",
+			providerType, provider.BinaryName(providerType))
+	}
+	agent.SetProvider(newAgentProvider(providerType))
+
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(cwd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+	return nil
+}
+
+// summarizeContext runs the agent in read-only mode to condense text into
+// session context
+func summarizeContext(cwd, text, model string) (string, error) {
+	fmt.Fprintln(os.Stderr, "Asking the agent to summarize...")
+	result, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     generateSummarizePrompt(text),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: cwd,
+	})
+	if err != nil {
+		return "", fmt.Errorf("agent failed: %w", err)
+	}
+	if result.RateLimited {
+		return "", fmt.Errorf("agent was rate limited, try again later")
+	}
+
+	match := summaryPattern.FindStringSubmatch(result.Output)
+	if match == nil || strings.TrimSpace(match[1]) == "" {
+		return "", fmt.Errorf("agent returned no summary")
+	}
+	return strings.TrimSpace(match[1]), nil
+}
+
+// generateSummarizePrompt builds the summarization prompt for a document
+func generateSummarizePrompt(text string) string {
+	var buf strings.Builder
+
+	buf.WriteString(fmt.Sprintf("<document>\n%s\n</document>\n\n", strings.TrimSpace(text)))
+	buf.WriteString("<instructions>\n")
+	buf.WriteString(agent.GetSummarizePromptTemplate())
+	if !strings.HasSuffix(agent.GetSummarizePromptTemplate(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</instructions>\n")
+
+	return buf.String()
+}
+
+// appendContext returns the context with text added after it, or text alone
+// when not appending
+func appendContext(existing, text string, appendText bool) string {
+	text = strings.TrimSpace(text)
+	existing = strings.TrimRight(existing, "\n")
+	if !appendText || strings.TrimSpace(existing) == "" {
+		return text
+	}
+	return existing + "\n\n" + text
+}
+
+// warnContextSize warns when a session's context is large enough that
+// reading it every iteration costs noticeably
+func warnContextSize(context string) {
+	if len(context) > sessionContextWarnSize {
+		fmt.Fprintf(os.Stderr, "Warning: session context is %s; agents read all of it every iteration\n",
+			formatContextSize(len(context)))
+	}
+}
+
+// formatContextSize formats a byte count as B or KB
+func formatContextSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
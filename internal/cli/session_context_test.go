@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+)
+
+func TestExtractReadableText(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html><head><title>Auth &amp; Sessions RFC</title><style>body { color: red }</style></head>
+<body>
+<nav><a href="/">Home</a> | <a href="/docs">Docs</a></nav>
+<article>
+  <h1>Auth   RFC</h1>
+  <!-- draft note -->
+  <p>Tokens expire after <b>15 minutes</b>.</p>
+  <h2>Requirements</h2>
+  <ul><li>Refresh tokens rotate</li><li>Logout revokes   all tokens</li></ul>
+  <pre><code>POST /auth/refresh
+  {"token": "..."}</code></pre>
+  <script>track()</script>
+</article>
+<footer>Copyright</footer>
+</body></html>`
+
+	got := extractReadableText(page)
+	want := "# Auth RFC\n\nTokens expire after 15 minutes.\n\n## Requirements\n\n- Refresh tokens rotate\n- Logout revokes all tokens\n\n" +
+		"```\nPOST /auth/refresh\n  {\"token\": \"...\"}\n```"
+	if got != want {
+		t.Errorf("Unexpected readable text:\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestExtractReadableText_UsesTitleWithoutHeading(t *testing.T) {
+	got := extractReadableText(`<html><head><title>Notes</title></head><body><p>First</p><p>Second</p></body></html>`)
+	if got != "# Notes\n\nFirst\n\nSecond" {
+		t.Errorf("Unexpected readable text %q", got)
+	}
+}
+
+func TestFetchContextURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/spec.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<html><body><main><p>Build it</p></main></body></html>`))
+		case "/spec.md":
+			w.Header().Set("Content-Type", "text/markdown")
+			w.Write([]byte("# Spec\n\n<b>kept</b>\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	if text, err := fetchContextURL(server.URL + "/spec.html"); err != nil || text != "Build it" {
+		t.Errorf("Expected readable text of the page, got %q (%v)", text, err)
+	}
+	if text, err := fetchContextURL(server.URL + "/spec.md"); err != nil || text != "# Spec\n\n<b>kept</b>\n" {
+		t.Errorf("Expected non-HTML content as is, got %q (%v)", text, err)
+	}
+	if _, err := fetchContextURL(server.URL + "/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected a 404 error, got %v", err)
+	}
+	if _, err := fetchContextURL("file:///etc/passwd"); err == nil {
+		t.Error("Expected non-http URLs to be rejected")
+	}
+}
+
+func TestSummarizeContext(t *testing.T) {
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "Reading the spec...\n<summary>\n- Tokens expire after 15 minutes\n</summary>\n"})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	summary, err := summarizeContext(t.TempDir(), "A very long spec", "")
+	if err != nil {
+		t.Fatalf("Summarize failed: %v", err)
+	}
+	if summary != "- Tokens expire after 15 minutes" {
+		t.Errorf("Unexpected summary %q", summary)
+	}
+	if len(mock.Calls) != 1 || mock.Calls[0].Permission != agent.PermissionPlan ||
+		!strings.Contains(mock.Calls[0].Prompt, "<document>\nA very long spec\n</document>") {
+		t.Errorf("Expected one read-only call with the document in the prompt, got %+v", mock.Calls)
+	}
+
+	agent.SetRunner(agent.NewMockRunner(&agent.RunResult{Output: "I couldn't read it"}))
+	if _, err := summarizeContext(t.TempDir(), "spec", ""); err == nil {
+		t.Error("Expected an error when the agent returns no summary")
+	}
+}
+
+func TestAppendContext(t *testing.T) {
+	if got := appendContext("Existing\n", "  New  \n", true); got != "Existing\n\nNew" {
+		t.Errorf("Unexpected appended context %q", got)
+	}
+	if got := appendContext("Existing", "New", false); got != "New" {
+		t.Errorf("Expected replaced context, got %q", got)
+	}
+	if got := appendContext("", "New", true); got != "New" {
+		t.Errorf("Expected appending to empty context to set it, got %q", got)
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionsGoalSetFlag      string
+	sessionsGoalAddFlag      []string
+	sessionsGoalCheckFlag    string
+	sessionsGoalMetFlag      []int
+	sessionsGoalUnmetFlag    []int
+	sessionsGoalRemoveFlag   []int
+	sessionsGoalEvaluateFlag bool
+)
+
+var sessionsGoalCmd = &cobra.Command{
+	Use:   "goal <id>",
+	Short: "View or edit a session's goal and exit criteria",
+	Long: `View or edit a session's goal and exit criteria.
+
+The goal says what the session is meant to achieve. Exit criteria are the
+conditions that must hold before it is: a criterion with --check is met
+when its command exits 0 in the project directory, the rest are marked
+met by hand. The agent loop evaluates the exit criteria before accepting
+a COMPLETE signal, and keeps working while any are unmet.
+
+Criteria are numbered as sessions goal lists them. Without flags, shows
+the goal and criteria as of their last evaluation; --evaluate runs the
+checks first.
+
+Examples:
+  juggle sessions goal auth-feature --set "Users can log in with OAuth"
+  juggle sessions goal auth-feature --add "Tests pass" --check "go test ./..."
+  juggle sessions goal auth-feature --add "Docs updated" --add "Demoed to the team"
+  juggle sessions goal auth-feature --met 2
+  juggle sessions goal auth-feature --remove 3
+  juggle sessions goal auth-feature --evaluate`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionsGoal,
+}
+
+func init() {
+	sessionsGoalCmd.Flags().StringVar(&sessionsGoalSetFlag, "set", "", "Set the goal (empty to clear)")
+	sessionsGoalCmd.Flags().StringArrayVar(&sessionsGoalAddFlag, "add", nil, "Add an exit criterion (can be specified multiple times)")
+	sessionsGoalCmd.Flags().StringVar(&sessionsGoalCheckFlag, "check", "", "Shell command that checks the criterion being added")
+	sessionsGoalCmd.Flags().IntSliceVar(&sessionsGoalMetFlag, "met", nil, "Mark criteria met by number")
+	sessionsGoalCmd.Flags().IntSliceVar(&sessionsGoalUnmetFlag, "unmet", nil, "Mark criteria unmet by number")
+	sessionsGoalCmd.Flags().IntSliceVar(&sessionsGoalRemoveFlag, "remove", nil, "Remove criteria by number")
+	sessionsGoalCmd.Flags().BoolVar(&sessionsGoalEvaluateFlag, "evaluate", false, "Run the criteria checks and record the results")
+	sessionsCmd.AddCommand(sessionsGoalCmd)
+}
+
+// SessionGoalResponse is the JSON response for sessions goal
+type SessionGoalResponse struct {
+	Session      string                  `json:"session"`
+	Goal         string                  `json:"goal"`
+	ExitCriteria []session.ExitCriterion `json:"exit_criteria"`
+	Met          int                     `json:"met"`
+	Total        int                     `json:"total"`
+}
+
+func runSessionsGoal(cmd *cobra.Command, args []string) error {
+	id := args[0]
+
+	if sessionsGoalCheckFlag != "" && len(sessionsGoalAddFlag) != 1 {
+		return validationErrorf("--check needs exactly one --add for the criterion it checks")
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sess, err := store.LoadSession(id)
+	if err != nil {
+		return session.NewSessionNotFoundError(id)
+	}
+
+	criteria, err := editExitCriteria(sess.ExitCriteria)
+	if err != nil {
+		return err
+	}
+	if sessionsGoalEvaluateFlag {
+		criteria = session.EvaluateExitCriteria(criteria, exitCheckRunner(cwd))
+	}
+
+	if cmd.Flags().Changed("set") {
+		if err := store.UpdateSessionGoal(id, sessionsGoalSetFlag); err != nil {
+			return fmt.Errorf("failed to update goal: %w", err)
+		}
+	}
+	if !slices.Equal(criteria, sess.ExitCriteria) {
+		if err := store.UpdateSessionExitCriteria(id, criteria); err != nil {
+			return fmt.Errorf("failed to update exit criteria: %w", err)
+		}
+	}
+
+	if sess, err = store.LoadSession(id); err != nil {
+		return fmt.Errorf("failed to reload session: %w", err)
+	}
+	met, total := sess.GoalProgress()
+
+	if GlobalOpts.JSONOutput {
+		resp := SessionGoalResponse{Session: id, Goal: sess.Goal, ExitCriteria: sess.ExitCriteria, Met: met, Total: total}
+		if resp.ExitCriteria == nil {
+			resp.ExitCriteria = []session.ExitCriterion{}
+		}
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if sess.Goal != "" {
+		fmt.Printf("Goal: %s\n", sess.Goal)
+	} else {
+		fmt.Println("Goal: (not set)")
+	}
+	if total == 0 {
+		fmt.Println("\nNo exit criteria. Add one with: juggle sessions goal", id, "--add \"...\"")
+		return nil
+	}
+	fmt.Printf("\nExit criteria (%d/%d met):\n", met, total)
+	for i, criterion := range sess.ExitCriteria {
+		fmt.Printf("  %d. %s\n", i+1, criterion)
+	}
+	return nil
+}
+
+// editExitCriteria applies the --met, --unmet, --remove and --add flags to
+// a copy of criteria. Numbers refer to the criteria before any are removed.
+func editExitCriteria(criteria []session.ExitCriterion) ([]session.ExitCriterion, error) {
+	edited := slices.Clone(criteria)
+	valid := func(flag string, numbers []int) error {
+		for _, n := range numbers {
+			if n < 1 || n > len(edited) {
+				return validationErrorf("%s %d: the session has %d exit criteria", flag, n, len(edited))
+			}
+		}
+		return nil
+	}
+	for _, check := range []struct {
+		flag    string
+		numbers []int
+	}{{"--met", sessionsGoalMetFlag}, {"--unmet", sessionsGoalUnmetFlag}, {"--remove", sessionsGoalRemoveFlag}} {
+		if err := valid(check.flag, check.numbers); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, n := range sessionsGoalMetFlag {
+		if edited[n-1].Check != "" {
+			return nil, validationErrorf("criterion %d is met by its check; run --evaluate instead", n)
+		}
+		edited[n-1].Met = true
+	}
+	for _, n := range sessionsGoalUnmetFlag {
+		edited[n-1].Met = false
+	}
+	if len(sessionsGoalRemoveFlag) > 0 {
+		kept := make([]session.ExitCriterion, 0, len(edited))
+		for i, criterion := range edited {
+			if !slices.Contains(sessionsGoalRemoveFlag, i+1) {
+				kept = append(kept, criterion)
+			}
+		}
+		edited = kept
+	}
+	for _, text := range sessionsGoalAddFlag {
+		if text == "" {
+			return nil, validationErrorf("exit criterion text cannot be empty")
+		}
+		edited = append(edited, session.ExitCriterion{Text: text, Check: sessionsGoalCheckFlag})
+	}
+	return edited, nil
+}
+
+// exitCheckRunner returns a runner for EvaluateExitCriteria that runs checks
+// with sh in projectDir
+func exitCheckRunner(projectDir string) func(check string) bool {
+	return func(check string) bool {
+		cmd := exec.Command("sh", "-c", check)
+		cmd.Dir = projectDir
+		return cmd.Run() == nil
+	}
+}
+
+// evaluateSessionExitCriteria runs a session's exit criteria checks and
+// records the results, returning the criteria that are unmet
+func evaluateSessionExitCriteria(store *session.SessionStore, sess *session.JuggleSession, projectDir string) []session.ExitCriterion {
+	criteria := session.EvaluateExitCriteria(sess.ExitCriteria, exitCheckRunner(projectDir))
+	if !slices.Equal(criteria, sess.ExitCriteria) {
+		if err := store.UpdateSessionExitCriteria(sess.ID, criteria); err != nil {
+			fmt.Printf("⚠️  Failed to record exit criteria results: %v\n", err)
+		}
+	}
+	return session.UnmetExitCriteria(criteria)
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var sessionsArchiveBallsFlag bool
+
+var sessionsArchiveCmd = &cobra.Command{
+	Use:   "archive <id>",
+	Short: "Archive a session you are done with",
+	Long: `Archive a session. Archived sessions are hidden from sessions list and
+the TUI's sessions panel by default, and the agent daemon doesn't start runs
+for them. The session, its balls and its progress are kept.
+
+With --balls, the session's complete and researched balls are archived too.
+
+Bring a session back with: juggle sessions resume <id>
+
+Examples:
+  juggle sessions archive auth-feature
+  juggle sessions archive auth-feature --balls`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSessionState(args[0], session.SessionStateArchived)
+	},
+}
+
+var sessionsPauseCmd = &cobra.Command{
+	Use:   "pause <id>",
+	Short: "Pause a session so the agent daemon leaves it alone",
+	Long: `Pause a session. Paused sessions stay in view, but the agent daemon
+doesn't start runs for them, even when named with --session.
+
+Examples:
+  juggle sessions pause auth-feature
+  juggle sessions resume auth-feature`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSessionState(args[0], session.SessionStatePaused)
+	},
+}
+
+var sessionsResumeCmd = &cobra.Command{
+	Use:   "resume <id>",
+	Short: "Make a paused or archived session active again",
+	Long: `Make a paused or archived session active again, so it is shown and
+scheduled as usual. Balls archived with the session stay archived; bring
+them back with juggle unarchive.
+
+Examples:
+  juggle sessions resume auth-feature`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSessionState(args[0], session.SessionStateActive)
+	},
+}
+
+func init() {
+	sessionsArchiveCmd.Flags().BoolVar(&sessionsArchiveBallsFlag, "balls", false, "Also archive the session's complete and researched balls")
+	sessionsCmd.AddCommand(sessionsArchiveCmd)
+	sessionsCmd.AddCommand(sessionsPauseCmd)
+	sessionsCmd.AddCommand(sessionsResumeCmd)
+}
+
+// SessionStateResponse is the JSON response for sessions archive, pause and resume
+type SessionStateResponse struct {
+	Session       string   `json:"session"`
+	State         string   `json:"state"`
+	ArchivedBalls []string `json:"archived_balls,omitempty"`
+}
+
+// setSessionState moves a session to state, archiving its finished balls
+// when archiving with --balls
+func setSessionState(id string, state session.SessionState) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	if _, err := sessionStore.LoadSession(id); err != nil {
+		return session.NewSessionNotFoundError(id)
+	}
+	if err := sessionStore.UpdateSessionState(id, state); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	var archived []string
+	if state == session.SessionStateArchived && sessionsArchiveBallsFlag {
+		if archived, err = archiveFinishedSessionBalls(cwd, id); err != nil {
+			return err
+		}
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(SessionStateResponse{Session: id, State: string(state), ArchivedBalls: archived}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	switch state {
+	case session.SessionStateArchived:
+		fmt.Printf("✓ Archived session %s\n", id)
+		if len(archived) > 0 {
+			fmt.Printf("  Archived %d finished ball(s)\n", len(archived))
+		}
+	case session.SessionStatePaused:
+		fmt.Printf("✓ Paused session %s (the agent daemon won't start runs for it)\n", id)
+	default:
+		fmt.Printf("✓ Session %s is active\n", id)
+	}
+	return nil
+}
+
+// archiveFinishedSessionBalls archives the session's complete and
+// researched balls, returning their IDs
+func archiveFinishedSessionBalls(projectDir, sessionID string) ([]string, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize ball store: %w", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var archived []string
+	for _, ball := range balls {
+		if !ball.HasTag(sessionID) || (ball.State != session.StateComplete && ball.State != session.StateResearched) {
+			continue
+		}
+		if err := store.ArchiveBall(ball); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to archive %s: %v\n", ball.ShortID(), err)
+			continue
+		}
+		archived = append(archived, ball.ID)
+	}
+	return archived, nil
+}
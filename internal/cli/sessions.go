@@ -4,11 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/editor"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -29,8 +29,13 @@ Commands:
   sessions show <id>                     Show session details
   sessions edit <id>                     Edit session properties (opens in editor)
   sessions context <id> [--edit]         View or edit session context
+  sessions context <id> --from-file f    Import session context from a file or --from-url
   sessions progress <id>                 View session progress log
   sessions progress clear <id>           Clear session progress log
+  sessions goal <id> [--add text]        View or edit the session goal and exit criteria
+  sessions targets [--notify]            Report progress toward throughput targets
+  sessions archive <id> [--balls]        Archive a session (hidden, not scheduled)
+  sessions pause|resume <id>             Pause a session's scheduling, or make it active again
   sessions delete <id>                   Delete a session
 
 Alias: 'session' can be used instead of 'sessions'`,
@@ -47,6 +52,7 @@ var (
 	sessionACFlag               []string // Acceptance criteria for session
 	sessionYesFlag              bool     // Skip confirmation for delete
 	sessionNonInteractiveFlag   bool     // Skip interactive prompts
+	sessionTargetFlag           string   // Throughput target, e.g. 5/week
 )
 
 var sessionsCreateCmd = &cobra.Command{
@@ -64,9 +70,13 @@ corresponding progress.txt file for agent memory.`,
 var sessionsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all sessions",
-	RunE:  runSessionsList,
+	Long: `List the project's sessions with their ball counts. Archived sessions
+are left out unless --archived is given.`,
+	RunE: runSessionsList,
 }
 
+var sessionsListArchivedFlag bool
+
 var sessionsShowCmd = &cobra.Command{
 	Use:   "show <id>",
 	Short: "Show session details",
@@ -81,7 +91,18 @@ var sessionsContextCmd = &cobra.Command{
 
 Without flags, displays the current context.
 With --edit, opens the context in $EDITOR for editing.
-With --set "text", sets the context directly (agent-friendly).`,
+With --set "text", sets the context directly (agent-friendly).
+With --from-file or --from-url, imports the context from a spec instead of
+pasting it through shell quoting. Web pages are reduced to their readable
+text. Imports over --summarize-over KB are condensed by the agent first.
+
+Use --append to add to the existing context instead of replacing it.
+
+Examples:
+  juggle sessions context my-feature --from-file spec.md
+  juggle sessions context my-feature --from-file - < notes.txt
+  juggle sessions context my-feature --from-url https://example.com/rfc --append
+  juggle sessions context my-feature --from-file big.md --summarize-over 0   # Never summarize`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionsContext,
 }
@@ -134,7 +155,11 @@ Examples:
   juggle sessions edit my-session                    # Open in editor
   juggle sessions edit my-session -m "New description"
   juggle sessions edit my-session --ac "AC1" --ac "AC2"
-  juggle sessions edit my-session --default-model medium`,
+  juggle sessions edit my-session --default-model medium
+  juggle sessions edit my-session --provider ollama   # Run this session's balls on a local model
+  juggle sessions edit my-session --provider none     # Back to the configured provider
+  juggle sessions edit my-session --target 5/week
+  juggle sessions edit my-session --target none      # Clear the target`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionsEdit,
 }
@@ -147,6 +172,8 @@ var (
 	sessionEditDefaultModelFlag  string
 	sessionEditACAppendFlag      []string
 	sessionEditACRemoveFlag      []string
+	sessionEditTargetFlag        string
+	sessionEditProviderFlag      string
 )
 
 func init() {
@@ -155,8 +182,16 @@ func init() {
 	sessionsCreateCmd.Flags().StringVar(&sessionContextFlag, "context", "", "Initial session context (agent-friendly)")
 	sessionsCreateCmd.Flags().StringSliceVar(&sessionACFlag, "ac", []string{}, "Session-level acceptance criteria (can be specified multiple times)")
 	sessionsCreateCmd.Flags().BoolVar(&sessionNonInteractiveFlag, "non-interactive", false, "Skip interactive prompts (for headless mode)")
+	sessionsCreateCmd.Flags().StringVar(&sessionTargetFlag, "target", "", "Throughput target, e.g. 5/week (periods: day, week, month)")
 	sessionsContextCmd.Flags().BoolVar(&sessionEditFlag, "edit", false, "Open context in $EDITOR")
 	sessionsContextCmd.Flags().StringVar(&sessionSetFlag, "set", "", "Set context directly (agent-friendly)")
+	sessionsContextCmd.Flags().StringVar(&sessionContextFromFileFlag, "from-file", "", "Import context from a file (\"-\" for stdin)")
+	sessionsContextCmd.Flags().StringVar(&sessionContextFromURLFlag, "from-url", "", "Import context from a URL (web pages are reduced to their readable text)")
+	sessionsContextCmd.Flags().BoolVar(&sessionContextAppendFlag, "append", false, "Append to the existing context instead of replacing it")
+	sessionsContextCmd.Flags().IntVar(&sessionContextSummarizeOver, "summarize-over", defaultContextSummarizeKB, "Summarize imports larger than this many KB with the agent (0 to never summarize)")
+	sessionsContextCmd.Flags().StringVar(&sessionContextSummarizeProvider, "provider", "", "Agent provider for summarizing: claude|opencode|http|ollama|shell")
+	sessionsContextCmd.Flags().StringVar(&sessionContextSummarizeModel, "model", "", "Model for summarizing")
+	sessionsListCmd.Flags().BoolVar(&sessionsListArchivedFlag, "archived", false, "Include archived sessions")
 	sessionsDeleteCmd.Flags().BoolVarP(&sessionYesFlag, "yes", "y", false, "Skip confirmation prompt (for headless mode)")
 	sessionsProgressClearCmd.Flags().BoolVarP(&sessionProgressClearYesFlag, "yes", "y", false, "Skip confirmation prompt (for headless mode)")
 
@@ -167,6 +202,8 @@ func init() {
 	sessionsEditCmd.Flags().StringSliceVar(&sessionEditACAppendFlag, "ac-append", []string{}, "Append acceptance criteria (can be specified multiple times)")
 	sessionsEditCmd.Flags().StringSliceVar(&sessionEditACRemoveFlag, "ac-remove", []string{}, "Remove acceptance criteria by text (can be specified multiple times)")
 	sessionsEditCmd.Flags().StringVar(&sessionEditDefaultModelFlag, "default-model", "", "Set default model size (small|medium|large)")
+	sessionsEditCmd.Flags().StringVar(&sessionEditTargetFlag, "target", "", "Set throughput target, e.g. 5/week (\"none\" to clear)")
+	sessionsEditCmd.Flags().StringVar(&sessionEditProviderFlag, "provider", "", "Set agent provider for the session's runs: claude|opencode|http|ollama|shell (\"none\" to clear)")
 
 	// Add subcommands
 	sessionsCmd.AddCommand(sessionsCreateCmd)
@@ -195,11 +232,25 @@ func runSessionsCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize session store: %w", err)
 	}
 
+	var target *session.ThroughputTarget
+	if sessionTargetFlag != "" {
+		target, err = session.ParseThroughputTarget(sessionTargetFlag)
+		if err != nil {
+			return validationErrorf("%v", err)
+		}
+	}
+
 	sess, err := store.CreateSession(id, description)
 	if err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
+	if target != nil {
+		if err := store.UpdateSessionTarget(id, target); err != nil {
+			return fmt.Errorf("failed to set target: %w", err)
+		}
+	}
+
 	// Set context if provided
 	if sessionContextFlag != "" {
 		if err := store.UpdateSessionContext(id, sessionContextFlag); err != nil {
@@ -267,6 +318,9 @@ func runSessionsCreate(cmd *cobra.Command, args []string) error {
 	} else if inheritedCount > 0 {
 		fmt.Printf("  Acceptance criteria: (inherited %d from repo defaults)\n", inheritedCount)
 	}
+	if target != nil {
+		fmt.Printf("  Target: %s\n", target)
+	}
 	fmt.Printf("  Path: .juggle/sessions/%s/\n", id)
 
 	return nil
@@ -288,7 +342,24 @@ func runSessionsList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list sessions: %w", err)
 	}
 
+	hiddenArchived := 0
+	if !sessionsListArchivedFlag {
+		shown := sessions[:0]
+		for _, sess := range sessions {
+			if sess.IsArchived() {
+				hiddenArchived++
+				continue
+			}
+			shown = append(shown, sess)
+		}
+		sessions = shown
+	}
+
 	if len(sessions) == 0 {
+		if hiddenArchived > 0 {
+			fmt.Printf("No active sessions (%d archived, show them with --archived).\n", hiddenArchived)
+			return nil
+		}
 		fmt.Println("No sessions found.")
 		fmt.Println("\nCreate a session with: juggle sessions create <id> -m \"description\"")
 		return nil
@@ -305,6 +376,13 @@ func runSessionsList(cmd *cobra.Command, args []string) error {
 		balls = []*session.Ball{} // Continue even if no balls
 	}
 
+	// Completed balls are often archived, but still count toward targets
+	withArchived, err := ballStore.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		withArchived = balls
+	}
+	now := time.Now()
+
 	// Count balls per session (by tag)
 	ballCounts := make(map[string]int)
 	for _, ball := range balls {
@@ -322,9 +400,19 @@ func runSessionsList(cmd *cobra.Command, args []string) error {
 		ballCountStr := fmt.Sprintf("%d ball(s)", ballCount)
 
 		fmt.Printf("%s %s\n", labelStyle.Render(sess.ID+":"), valueStyle.Render(sess.Description))
-		fmt.Printf("  Balls: %s | Created: %s\n", ballCountStr, sess.CreatedAt.Format("2006-01-02"))
+		fmt.Printf("  Balls: %s | Created: %s", ballCountStr, sess.CreatedAt.Format("2006-01-02"))
+		if state := sess.CurrentState(); state != session.SessionStateActive {
+			fmt.Printf(" | State: %s", state)
+		}
+		fmt.Println()
+		if progress := session.SessionThroughput(sess, withArchived, now); progress != nil {
+			fmt.Printf("  Target: %s\n", progress.Summary())
+		}
 		fmt.Println()
 	}
+	if hiddenArchived > 0 {
+		fmt.Printf("%d archived session(s) hidden, show them with --archived\n", hiddenArchived)
+	}
 
 	return nil
 }
@@ -364,6 +452,12 @@ func runSessionsShow(cmd *cobra.Command, args []string) error {
 		allBalls = []*session.Ball{}
 	}
 
+	withArchived, err := ballStore.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		withArchived = allBalls
+	}
+	throughput := session.SessionThroughput(sess, withArchived, time.Now())
+
 	// Filter balls by tag matching session ID
 	var sessionBalls []*session.Ball
 	for _, ball := range allBalls {
@@ -388,6 +482,18 @@ func runSessionsShow(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println(labelStyle.Render("Created:"), valueStyle.Render(sess.CreatedAt.Format(time.RFC3339)))
 	fmt.Println(labelStyle.Render("Updated:"), valueStyle.Render(sess.UpdatedAt.Format(time.RFC3339)))
+	if state := sess.CurrentState(); state != session.SessionStateActive {
+		fmt.Println(labelStyle.Render("State:"), valueStyle.Render(string(state)))
+	}
+	if sess.Goal != "" {
+		fmt.Println(labelStyle.Render("Goal:"), valueStyle.Render(sess.Goal))
+	}
+	if throughput != nil {
+		fmt.Println(labelStyle.Render("Target:"), valueStyle.Render(sess.Target.String()+" — "+throughput.Summary()))
+		for _, period := range throughput.Previous {
+			fmt.Printf("  %s\n", formatTargetPeriod(sess.Target, period))
+		}
+	}
 
 	// Acceptance criteria section
 	fmt.Println()
@@ -400,6 +506,15 @@ func runSessionsShow(cmd *cobra.Command, args []string) error {
 		fmt.Println("  (no session-level acceptance criteria)")
 	}
 
+	// Exit criteria section
+	if met, total := sess.GoalProgress(); total > 0 {
+		fmt.Println()
+		fmt.Printf("%s (%d/%d met)\n", labelStyle.Render("Exit Criteria:"), met, total)
+		for i, criterion := range sess.ExitCriteria {
+			fmt.Printf("  %d. %s\n", i+1, criterion)
+		}
+	}
+
 	// Context section
 	fmt.Println()
 	fmt.Println(labelStyle.Render("Context:"))
@@ -464,32 +579,44 @@ func runSessionsContext(cmd *cobra.Command, args []string) error {
 	}
 
 	// Verify session exists
-	_, err = store.LoadSession(id)
+	sess, err := store.LoadSession(id)
 	if err != nil {
 		return fmt.Errorf("failed to load session: %w", err)
 	}
 
+	sources := 0
+	for _, set := range []bool{sessionSetFlag != "", sessionEditFlag, sessionContextFromFileFlag != "", sessionContextFromURLFlag != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return validationErrorf("use only one of --set, --edit, --from-file and --from-url")
+	}
+	if sessionContextAppendFlag && sessionEditFlag {
+		return validationErrorf("--append can't be used with --edit")
+	}
+	if sessionContextSummarizeOver < 0 {
+		return validationErrorf("--summarize-over must not be negative, got %d", sessionContextSummarizeOver)
+	}
+
+	if sessionContextFromFileFlag != "" || sessionContextFromURLFlag != "" {
+		return importSessionContext(store, cwd, id, sess.Context)
+	}
+
 	// Handle --set flag (agent-friendly)
 	if sessionSetFlag != "" {
-		if err := store.UpdateSessionContext(id, sessionSetFlag); err != nil {
+		context := appendContext(sess.Context, sessionSetFlag, sessionContextAppendFlag)
+		if err := store.UpdateSessionContext(id, context); err != nil {
 			return fmt.Errorf("failed to update context: %w", err)
 		}
 		fmt.Printf("Updated context for session: %s\n", id)
+		warnContextSize(context)
 		return nil
 	}
 
 	if sessionEditFlag {
 		// Open in editor
-		editor := os.Getenv("EDITOR")
-		if editor == "" {
-			editor = "vi" // Default fallback
-		}
-
-		sess, err := store.LoadSession(id)
-		if err != nil {
-			return fmt.Errorf("failed to load session: %w", err)
-		}
-
 		// Create temp file with current context
 		tmpFile, err := os.CreateTemp("", "juggle-context-*.md")
 		if err != nil {
@@ -505,7 +632,7 @@ func runSessionsContext(cmd *cobra.Command, args []string) error {
 		tmpFile.Close()
 
 		// Open editor
-		editorCmd := exec.Command(editor, tmpPath)
+		editorCmd := editor.Command(tmpPath)
 		editorCmd.Stdin = os.Stdin
 		editorCmd.Stdout = os.Stdout
 		editorCmd.Stderr = os.Stderr
@@ -521,7 +648,7 @@ func runSessionsContext(cmd *cobra.Command, args []string) error {
 		}
 
 		// Update session context
-		if err := store.UpdateSessionContext(id, string(newContext)); err != nil {
+		if err := store.UpdateSessionContext(id, session.NormalizeNewlines(string(newContext))); err != nil {
 			return fmt.Errorf("failed to update context: %w", err)
 		}
 
@@ -530,11 +657,6 @@ func runSessionsContext(cmd *cobra.Command, args []string) error {
 	}
 
 	// Just display context
-	sess, err := store.LoadSession(id)
-	if err != nil {
-		return fmt.Errorf("failed to load session: %w", err)
-	}
-
 	if sess.Context == "" {
 		fmt.Println("No context set for session:", id)
 		fmt.Println("\nSet context with: juggle sessions context", id, "--set \"text\"")
@@ -560,7 +682,7 @@ func runSessionsDelete(cmd *cobra.Command, args []string) error {
 
 	// Verify session exists
 	if _, err := store.LoadSession(id); err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return session.NewSessionNotFoundError(id)
 	}
 
 	// Confirm deletion (skip with --yes flag)
@@ -598,7 +720,7 @@ func runSessionsProgress(cmd *cobra.Command, args []string) error {
 
 	// Verify session exists
 	if _, err := store.LoadSession(id); err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return session.NewSessionNotFoundError(id)
 	}
 
 	// Load progress
@@ -633,7 +755,7 @@ func runSessionsProgressClear(cmd *cobra.Command, args []string) error {
 	// Verify session exists (skip for _all virtual session)
 	if id != "_all" && id != "all" {
 		if _, err := store.LoadSession(id); err != nil {
-			return fmt.Errorf("session not found: %s", id)
+			return session.NewSessionNotFoundError(id)
 		}
 	}
 
@@ -679,7 +801,7 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 	// Load session to verify it exists
 	sess, err := store.LoadSession(id)
 	if err != nil {
-		return fmt.Errorf("session not found: %s", id)
+		return session.NewSessionNotFoundError(id)
 	}
 
 	// Check if any flags are provided
@@ -688,7 +810,9 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 		len(sessionEditACFlag) > 0 ||
 		len(sessionEditACAppendFlag) > 0 ||
 		len(sessionEditACRemoveFlag) > 0 ||
-		sessionEditDefaultModelFlag != ""
+		sessionEditDefaultModelFlag != "" ||
+		cmd.Flags().Changed("target") ||
+		cmd.Flags().Changed("provider")
 
 	// If no flags provided, open in editor
 	if !hasFlags {
@@ -765,7 +889,7 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 	if sessionEditDefaultModelFlag != "" {
 		ms := session.ModelSize(sessionEditDefaultModelFlag)
 		if ms != session.ModelSizeSmall && ms != session.ModelSizeMedium && ms != session.ModelSizeLarge && ms != session.ModelSizeBlank {
-			return fmt.Errorf("invalid model size %q, must be one of: small, medium, large (or empty to clear)", sessionEditDefaultModelFlag)
+			return validationErrorf("invalid model size %q, must be one of: small, medium, large (or empty to clear)", sessionEditDefaultModelFlag)
 		}
 		if err := store.UpdateSessionDefaultModel(id, ms); err != nil {
 			return fmt.Errorf("failed to update default model: %w", err)
@@ -778,6 +902,44 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 		modified = true
 	}
 
+	if cmd.Flags().Changed("provider") {
+		provider := strings.ToLower(strings.TrimSpace(sessionEditProviderFlag))
+		if provider == "none" {
+			provider = ""
+		}
+		if !session.ValidateAgentProvider(provider) {
+			return validationErrorf("invalid agent provider %q, must be one of: claude, opencode, http, ollama, shell (or none to clear)", sessionEditProviderFlag)
+		}
+		if err := store.UpdateSessionAgentProvider(id, provider); err != nil {
+			return fmt.Errorf("failed to update agent provider: %w", err)
+		}
+		if provider == "" {
+			fmt.Printf("✓ Cleared agent provider\n")
+		} else {
+			fmt.Printf("✓ Updated agent provider: %s\n", provider)
+		}
+		modified = true
+	}
+
+	if cmd.Flags().Changed("target") {
+		var target *session.ThroughputTarget
+		if value := strings.TrimSpace(sessionEditTargetFlag); value != "" && value != "none" {
+			target, err = session.ParseThroughputTarget(value)
+			if err != nil {
+				return validationErrorf("%v", err)
+			}
+		}
+		if err := store.UpdateSessionTarget(id, target); err != nil {
+			return fmt.Errorf("failed to update target: %w", err)
+		}
+		if target == nil {
+			fmt.Printf("✓ Cleared target\n")
+		} else {
+			fmt.Printf("✓ Updated target: %s\n", target)
+		}
+		modified = true
+	}
+
 	if modified {
 		fmt.Printf("\n✓ Session %s updated successfully\n", id)
 	}
@@ -786,11 +948,6 @@ func runSessionsEdit(cmd *cobra.Command, args []string) error {
 }
 
 func runSessionsEditInEditor(store *session.SessionStore, sess *session.JuggleSession) error {
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi" // Default fallback
-	}
-
 	// Create a temporary file with session data in editable format
 	tmpFile, err := os.CreateTemp("", "juggle-session-*.yaml")
 	if err != nil {
@@ -837,7 +994,7 @@ func runSessionsEditInEditor(store *session.SessionStore, sess *session.JuggleSe
 	tmpFile.Close()
 
 	// Open editor
-	editorCmd := exec.Command(editor, tmpPath)
+	editorCmd := editor.Command(tmpPath)
 	editorCmd.Stdin = os.Stdin
 	editorCmd.Stdout = os.Stdout
 	editorCmd.Stderr = os.Stderr
@@ -853,7 +1010,7 @@ func runSessionsEditInEditor(store *session.SessionStore, sess *session.JuggleSe
 	}
 
 	// Parse the edited content
-	newDesc, newModel, newACs, newContext, err := parseEditedSession(string(editedContent))
+	newDesc, newModel, newACs, newContext, err := parseEditedSession(session.NormalizeNewlines(string(editedContent)))
 	if err != nil {
 		return fmt.Errorf("failed to parse edited content: %w", err)
 	}
@@ -0,0 +1,528 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// shellHistoryLimit caps the number of lines kept in the shell history file
+const shellHistoryLimit = 500
+
+// shellLongHelp is the shell command's help text, also printed by the "help" shorthand
+const shellLongHelp = `Start an interactive prompt for running many juggle operations without
+paying process startup and store setup costs for each one.
+
+Shorthand commands:
+  ls [session]            List non-complete balls (optionally for one session)
+  show <id>               Show ball details
+  start <id>              Start a pending ball
+  block <id> <reason>     Mark a ball blocked
+  done <id> [note]        Mark a ball complete and archive it
+  pending <id>            Move a ball back to pending
+  sessions                List sessions
+  help                    Show this help
+  exit, quit              Leave the shell (or press Ctrl+D)
+
+Anything else runs as a regular juggle command, e.g. "update 14 --priority high"
+or "14 tag add urgent". Quote arguments containing spaces:
+  block 14 "waiting on API keys"
+
+Tab completes commands, ball IDs, and session IDs. History is kept in
+~/.juggle/shell_history. When stdin is not a terminal, commands are read one
+per line, which makes the shell usable for scripted triage:
+  juggle shell < triage.txt`
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Interactive juggle prompt (REPL)",
+	Long:  shellLongHelp,
+	Args:  cobra.NoArgs,
+	RunE:  runShell,
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+// shellSession holds the store handles shared by every command in a shell
+type shellSession struct {
+	cwd          string
+	store        *session.Store
+	sessionStore *session.SessionStore
+	out          io.Writer
+	globals      GlobalOptions // Global options the shell was started with
+}
+
+// shellShorthands lists the built-in shell commands, used for help and completion
+var shellShorthands = []string{"block", "done", "exit", "help", "ls", "pending", "quit", "sessions", "show", "start"}
+
+func newShellSession(out io.Writer) (*shellSession, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session store: %w", err)
+	}
+
+	return &shellSession{cwd: cwd, store: store, sessionStore: sessionStore, out: out, globals: GlobalOpts}, nil
+}
+
+func runShell(cmd *cobra.Command, args []string) error {
+	sh, err := newShellSession(os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	stdinFd := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdinFd) {
+		return sh.runScript(os.Stdin)
+	}
+
+	history := loadShellHistory(shellHistoryPath())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, "juggle> ")
+	t.History = history
+	t.AutoCompleteCallback = sh.complete
+
+	fmt.Fprintln(sh.out, "juggle shell - type 'help' for commands, Ctrl+D to exit")
+	for {
+		// Raw mode only while reading, so commands (including the TUI) get a normal terminal
+		oldState, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal mode: %w", err)
+		}
+		line, readErr := t.ReadLine()
+		_ = term.Restore(stdinFd, oldState)
+
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read input: %w", readErr)
+		}
+
+		history.save()
+		if sh.runLine(line) {
+			return nil
+		}
+	}
+}
+
+// runScript runs shell commands read one per line from r
+func (sh *shellSession) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if sh.runLine(scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// runLine parses and runs a single line, printing any error.
+// Returns true if the shell should exit.
+func (sh *shellSession) runLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+
+	args, err := splitShellArgs(line)
+	if err != nil {
+		ReportError(usageErrorf("%v", err))
+		return false
+	}
+
+	exit, err := sh.execute(args)
+	if err != nil {
+		ReportError(err)
+	}
+	return exit
+}
+
+// execute runs a parsed shell command. Returns true if the shell should exit.
+func (sh *shellSession) execute(args []string) (bool, error) {
+	switch args[0] {
+	case "exit", "quit":
+		return true, nil
+	case "help", "?":
+		fmt.Fprintln(sh.out, shellLongHelp)
+		return false, nil
+	case "ls":
+		sessionID := ""
+		if len(args) > 1 {
+			sessionID = args[1]
+		}
+		return false, sh.listBalls(sessionID)
+	case "sessions":
+		return false, sh.listSessions()
+	case "show", "start", "block", "done", "complete", "pending":
+		if len(args) < 2 {
+			return false, usageErrorf("usage: %s <ball-id>", args[0])
+		}
+		ball, store, err := sh.findBall(args[1])
+		if err != nil {
+			return false, err
+		}
+		rest := args[2:]
+		switch args[0] {
+		case "show":
+			renderBallDetails(ball)
+			return false, nil
+		case "start":
+			return false, activateBall(ball, store)
+		case "block":
+			return false, setBallBlocked(ball, rest, store)
+		case "done", "complete":
+			return false, setBallComplete(ball, rest, store)
+		default:
+			return false, setBallState(ball, session.StatePending, rest, store)
+		}
+	case "shell":
+		return false, fmt.Errorf("already in juggle shell")
+	}
+
+	return false, sh.runCommand(args)
+}
+
+// runCommand runs args as a regular juggle command in this process.
+// Flags and global options are reset before it runs, so values left by an
+// earlier command, whether parsed from a flag or set by its RunE, don't leak
+// into this one.
+func (sh *shellSession) runCommand(args []string) error {
+	resetCommandFlags(rootCmd)
+	GlobalOpts = sh.globals
+	defer func() {
+		GlobalOpts = sh.globals
+		rootCmd.SetArgs(nil)
+	}()
+
+	rootCmd.SetArgs(args)
+	return executeRoot()
+}
+
+// resetCommandFlags restores every flag on cmd and its subcommands to its
+// default value, including the variables they're bound to
+func resetCommandFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetCommandFlags(sub)
+	}
+}
+
+// findBall resolves a ball ID against the shell's store, falling back to
+// cross-project lookup when --all is set
+func (sh *shellSession) findBall(id string) (*session.Ball, *session.Store, error) {
+	balls, err := sh.store.LoadBalls()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	matches := session.ResolveBallByPrefix(balls, id)
+	switch len(matches) {
+	case 0:
+		if GlobalOpts.AllProjects {
+			return findBallByID(id)
+		}
+		return nil, nil, notFoundErrorf("ball not found in current project: %s", id)
+	case 1:
+		return matches[0], sh.store, nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.ID
+		}
+		return nil, nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", id, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+// listBalls prints non-complete balls, optionally only those tagged with a session
+func (sh *shellSession) listBalls(sessionID string) error {
+	balls, err := sh.store.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	var shown []*session.Ball
+	for _, ball := range balls {
+		if ball.State == session.StateComplete {
+			continue
+		}
+		if sessionID != "" && !ballHasTag(ball, sessionID) {
+			continue
+		}
+		shown = append(shown, ball)
+	}
+
+	if len(shown) == 0 {
+		fmt.Fprintln(sh.out, "No balls")
+		return nil
+	}
+
+	minimalIDs := session.ComputeMinimalUniqueIDs(shown)
+	for _, ball := range shown {
+		title := ball.Title
+		if ball.BlockedReason != "" {
+			title += " (" + ball.BlockedReason + ")"
+		}
+		fmt.Fprintf(sh.out, "  [%s] %s  %s  %s\n",
+			padRight(minimalIDs[ball.ID], 4),
+			padRight(string(ball.State), 11),
+			padRight(string(ball.Priority), 6),
+			title,
+		)
+	}
+	return nil
+}
+
+// listSessions prints the project's session IDs with their descriptions
+func (sh *shellSession) listSessions() error {
+	sessions, err := sh.sessionStore.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		fmt.Fprintln(sh.out, "No sessions")
+		return nil
+	}
+	for _, sess := range sessions {
+		fmt.Fprintf(sh.out, "  %s  %s\n", padRight(sess.ID, 20), sess.Description)
+	}
+	return nil
+}
+
+// complete is the terminal's tab completion callback. It completes the word
+// before the cursor to the longest common prefix of the matching candidates.
+func (sh *shellSession) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	start := strings.LastIndex(line[:pos], " ") + 1
+	word := line[start:pos]
+	words := strings.Fields(line[:start])
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		candidates = sh.commandNames()
+	case words[0] == "ls" || words[len(words)-1] == "--session" || words[len(words)-1] == "-s":
+		candidates = sh.sessionIDs()
+	default:
+		candidates = append(sh.ballIDs(), sh.sessionIDs()...)
+	}
+
+	completion := completePrefix(word, candidates)
+	if completion == word {
+		return "", 0, false
+	}
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// commandNames returns shorthand and juggle command names for completion
+func (sh *shellSession) commandNames() []string {
+	names := append([]string{}, shellShorthands...)
+	for _, c := range rootCmd.Commands() {
+		if !c.Hidden {
+			names = append(names, c.Name())
+		}
+	}
+	return names
+}
+
+// ballIDs returns the short IDs of the project's balls for completion
+func (sh *shellSession) ballIDs() []string {
+	balls, err := sh.store.LoadBalls()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(balls))
+	for _, ball := range balls {
+		ids = append(ids, ball.ShortID())
+	}
+	return ids
+}
+
+// sessionIDs returns the project's session IDs for completion
+func (sh *shellSession) sessionIDs() []string {
+	sessions, err := sh.sessionStore.ListSessions()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(sessions))
+	for _, sess := range sessions {
+		ids = append(ids, sess.ID)
+	}
+	return ids
+}
+
+// completePrefix returns the longest common prefix of the candidates starting with word
+func completePrefix(word string, candidates []string) string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return word
+	}
+	sort.Strings(matches)
+
+	prefix := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(matches) == 1 {
+		prefix += " "
+	}
+	return prefix
+}
+
+// splitShellArgs splits a line into arguments, honoring single and double
+// quotes and backslash escapes
+func splitShellArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// shellHistory is a term.History backed by a file, most recent entry last on disk
+type shellHistory struct {
+	path    string
+	entries []string // Oldest first
+	dirty   bool
+}
+
+func shellHistoryPath() string {
+	opts := GetConfigOptions()
+	return filepath.Join(opts.ConfigHome, opts.JuggleDirName, "shell_history")
+}
+
+func loadShellHistory(path string) *shellHistory {
+	h := &shellHistory{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.trim()
+	return h
+}
+
+// Add records a new entry, skipping immediate repeats
+func (h *shellHistory) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	h.trim()
+	h.dirty = true
+}
+
+// Len returns the number of entries
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry; index 0 is the most recent
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+func (h *shellHistory) trim() {
+	if len(h.entries) > shellHistoryLimit {
+		h.entries = h.entries[len(h.entries)-shellHistoryLimit:]
+	}
+}
+
+// save writes the history file if it changed. Failures are ignored: losing
+// history shouldn't interrupt the shell.
+func (h *shellHistory) save() {
+	if !h.dirty {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return
+	}
+	if err := os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0644); err == nil {
+		h.dirty = false
+	}
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestSplitShellArgs(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"ls", []string{"ls"}},
+		{`block 14 "waiting on API keys"`, []string{"block", "14", "waiting on API keys"}},
+		{`done 14 'shipped it'`, []string{"done", "14", "shipped it"}},
+		{`update  14   --priority high`, []string{"update", "14", "--priority", "high"}},
+		{`block 14 say\ \"hi\"`, []string{"block", "14", `say "hi"`}},
+		{`block 14 ""`, []string{"block", "14", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitShellArgs(tt.line)
+		if err != nil {
+			t.Errorf("splitShellArgs(%q) error: %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitShellArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := splitShellArgs(`block 14 "unterminated`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestCompletePrefix(t *testing.T) {
+	candidates := []string{"sessions", "show", "start", "status"}
+
+	if got := completePrefix("sh", candidates); got != "show " {
+		t.Errorf("completePrefix(sh) = %q, want %q", got, "show ")
+	}
+	if got := completePrefix("st", candidates); got != "sta" {
+		t.Errorf("completePrefix(st) = %q, want %q", got, "sta")
+	}
+	if got := completePrefix("x", candidates); got != "x" {
+		t.Errorf("completePrefix(x) = %q, want %q", got, "x")
+	}
+}
+
+func TestShellHistory_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".juggle", "shell_history")
+
+	h := loadShellHistory(path)
+	h.Add("ls")
+	h.Add("ls")
+	h.Add("start 14")
+	h.save()
+
+	reloaded := loadShellHistory(path)
+	if reloaded.Len() != 2 {
+		t.Fatalf("expected 2 history entries (repeat skipped), got %d", reloaded.Len())
+	}
+	if reloaded.At(0) != "start 14" || reloaded.At(1) != "ls" {
+		t.Errorf("expected most recent entry first, got %q, %q", reloaded.At(0), reloaded.At(1))
+	}
+}
+
+func TestShellSession_ShorthandsAndCompletion(t *testing.T) {
+	tmpDir, cleanup := setupTestProject(t)
+	defer cleanup()
+
+	store, err := NewStoreForCommand(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	ball, err := session.NewBall(tmpDir, "Triage me", session.PriorityMedium)
+	if err != nil {
+		t.Fatalf("failed to create ball: %v", err)
+	}
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("failed to save ball: %v", err)
+	}
+
+	var out bytes.Buffer
+	sh, err := newShellSession(&out)
+	if err != nil {
+		t.Fatalf("failed to create shell session: %v", err)
+	}
+
+	if err := sh.runScript(strings.NewReader("# triage\nls\n")); err != nil {
+		t.Fatalf("runScript: %v", err)
+	}
+	if !strings.Contains(out.String(), "Triage me") {
+		t.Errorf("expected ls to list the ball, got: %s", out.String())
+	}
+
+	if exit, err := sh.execute([]string{"block", ball.ShortID(), "waiting on review"}); err != nil || exit {
+		t.Fatalf("block: exit=%v err=%v", exit, err)
+	}
+	reloaded, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("failed to reload ball: %v", err)
+	}
+	if reloaded.State != session.StateBlocked || reloaded.BlockedReason != "waiting on review" {
+		t.Errorf("expected ball blocked with reason, got %s (%s)", reloaded.State, reloaded.BlockedReason)
+	}
+
+	line, pos, ok := sh.complete("show "+ball.ShortID()[:1], 6, '\t')
+	if !ok || line != "show "+ball.ShortID()+" " || pos != len(line) {
+		t.Errorf("expected ball ID completion, got %q (%d, %v)", line, pos, ok)
+	}
+
+	if exit, _ := sh.execute([]string{"exit"}); !exit {
+		t.Error("expected exit to end the shell")
+	}
+}
@@ -3,7 +3,9 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ohare93/juggle/internal/session"
@@ -28,13 +30,14 @@ func runShow(cmd *cobra.Command, args []string) error {
 	ballID := args[0]
 
 	// Use findBallByID which respects --all flag
-	foundBall, _, err := findBallByID(ballID)
+	foundBall, store, err := findBallByID(ballID)
 	if err != nil {
 		if showJSONFlag {
 			return printJSONError(err)
 		}
 		return err
 	}
+	foundBall = syncBallCommits(store, foundBall)
 
 	if showJSONFlag {
 		return printBallJSON(foundBall)
@@ -54,12 +57,13 @@ func printBallJSON(ball *session.Ball) error {
 	return nil
 }
 
-// printJSONError outputs an error in JSON format
+// printJSONError outputs an error in JSON format. The returned error only
+// carries the exit code; it has already been printed.
 func printJSONError(err error) error {
-	errResp := map[string]string{"error": err.Error()}
-	data, _ := json.Marshal(errResp)
-	fmt.Println(string(data))
-	return nil // Return nil so the error is in JSON, not stderr
+	cliErr := ClassifyError(err)
+	writeJSONError(os.Stdout, cliErr)
+	cliErr.reported = true
+	return cliErr
 }
 
 func renderBallDetails(ball *session.Ball) {
@@ -72,12 +76,25 @@ func renderBallDetails(ball *session.Ball) {
 		fmt.Println(labelStyle.Render("Context:"), valueStyle.Render(ball.Context))
 	}
 	fmt.Println(labelStyle.Render("Title:"), valueStyle.Render(ball.Title))
+	if ball.NextAction != "" {
+		fmt.Println(labelStyle.Render("Next Action:"), valueStyle.Render(ball.NextAction))
+	}
 	fmt.Println(labelStyle.Render("Priority:"), valueStyle.Render(string(ball.Priority)))
 	fmt.Println(labelStyle.Render("State:"), valueStyle.Render(string(ball.State)))
+	if ball.Checkpoint != "" {
+		fmt.Println(labelStyle.Render("Checkpoint:"), valueStyle.Render(ball.Checkpoint))
+	}
 
 	if ball.BlockedReason != "" {
 		fmt.Println(labelStyle.Render("Blocked:"), valueStyle.Render(ball.BlockedReason))
 	}
+	if ball.IsEscalated() {
+		fmt.Println(labelStyle.Render("Escalated:"), valueStyle.Render(ball.Escalation.Label()))
+	}
+	if ball.IsRecurring() {
+		fmt.Println(labelStyle.Render("Recurs:"), valueStyle.Render(ball.Recurrence.Label()))
+		fmt.Println(labelStyle.Render("Due:"), valueStyle.Render(ball.Recurrence.DueLabel(time.Now())))
+	}
 
 	fmt.Println(labelStyle.Render("Started:"), valueStyle.Render(ball.StartedAt.Format("2006-01-02 15:04:05")))
 	fmt.Println(labelStyle.Render("Last Activity:"), valueStyle.Render(ball.LastActivity.Format("2006-01-02 15:04:05")))
@@ -87,14 +104,64 @@ func renderBallDetails(ball *session.Ball) {
 		fmt.Println(labelStyle.Render("Tags:"), valueStyle.Render(strings.Join(ball.Tags, ", ")))
 	}
 
+	if ball.Focused {
+		fmt.Println(labelStyle.Render("Focused:"), valueStyle.Render("yes (the next agent run works on it first)"))
+	}
+	if ball.Milestone != "" {
+		fmt.Println(labelStyle.Render("Milestone:"), valueStyle.Render(ball.Milestone))
+	}
+	if ball.Estimate != nil {
+		fmt.Println(labelStyle.Render("Estimate:"), valueStyle.Render(ball.Estimate.Label()))
+	}
+
 	if len(ball.DependsOn) > 0 {
-		fmt.Println(labelStyle.Render("Depends On:"), valueStyle.Render(strings.Join(ball.DependsOn, ", ")))
+		deps := loadDependencyIndex(ball.WorkingDir, []*session.Ball{ball})
+		fmt.Println(labelStyle.Render("Depends On:"), valueStyle.Render(deps.FormatDependencies(ball)))
+	}
+
+	if len(ball.WatchGlobs) > 0 {
+		fmt.Println(labelStyle.Render("Watching:"), valueStyle.Render(strings.Join(ball.WatchGlobs, ", ")))
+	}
+
+	if ball.Git != nil {
+		fmt.Println(labelStyle.Render("Git:"), valueStyle.Render(ball.Git.Label()))
+		if len(ball.Git.Commits) > 0 {
+			latest := ball.Git.Commits[0]
+			fmt.Println(labelStyle.Render("Latest Commit:"), valueStyle.Render(latest.Hash+" "+latest.Subject))
+		}
 	}
 
 	if len(ball.AcceptanceCriteria) > 0 {
 		fmt.Printf("\n%s\n", labelStyle.Render("Acceptance Criteria:"))
+		verified := ball.VerificationCurrent()
 		for i, ac := range ball.AcceptanceCriteria {
-			fmt.Printf("  %d. %s\n", i+1, ac)
+			if !verified {
+				fmt.Printf("  %d. %s\n", i+1, ac)
+				continue
+			}
+			assessment := ball.Verification.Assessments[i]
+			mark := "✗"
+			if assessment.Satisfied {
+				mark = "✓"
+			}
+			fmt.Printf("  %d. %s %s\n", i+1, mark, ac)
+			if assessment.Evidence != "" {
+				fmt.Printf("       %s\n", assessment.Evidence)
+			}
+		}
+	}
+	if ball.Verification != nil {
+		label := ball.Verification.Label()
+		if !ball.VerificationCurrent() {
+			label += ", criteria changed since"
+		}
+		fmt.Println(labelStyle.Render("Verified:"), valueStyle.Render(label))
+	}
+
+	if len(ball.Notes) > 0 {
+		fmt.Printf("\n%s\n", labelStyle.Render("Notes:"))
+		for _, note := range ball.Notes {
+			fmt.Printf("  %s  %s\n", note.CreatedAt.Format(noteTimeFormat), note.Text)
 		}
 	}
 
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	parseCheckStrict  bool
+	agentSignalReason string
+)
+
+var agentParseCheckCmd = &cobra.Command{
+	Use:   "parse-check <file>",
+	Short: "Show the promise signals found in saved agent output",
+	Long: `Parse saved agent output the way 'agent run' does and show which
+COMPLETE/CONTINUE/BLOCKED signals were found, where, and what the run would
+have done with them. Use - to read from stdin.
+
+Useful when an agent seemed to finish but the loop didn't notice. Detection
+follows the global signal settings (see 'juggle config signals').
+
+Examples:
+  juggle agent parse-check .juggle/sessions/my-feature/last_output.txt
+  pbpaste | juggle agent parse-check -
+  juggle agent parse-check output.txt --strict`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentParseCheck,
+}
+
+var agentSignalCmd = &cobra.Command{
+	Use:   "signal <session> <complete|blocked|continue>",
+	Short: "Decide a running agent loop's next step yourself",
+	Long: `Send a COMPLETE, BLOCKED or CONTINUE decision to the agent loop running on a
+session, for when you can see the work is done (or stuck) and the agent
+doesn't say so.
+
+The loop takes the signal at its next checkpoint, when the current iteration
+ends, and it overrides whatever the agent signaled there, without the usual
+checks that progress was updated:
+
+  complete   End the run as complete
+  blocked    End the run as blocked (with --reason)
+  continue   Ignore the agent's signal and start the next iteration
+
+The signal is logged to the session's progress and recorded in agent history.
+A signal the loop hasn't taken replaces the earlier one; signals left when no
+run is active are dropped when the next run starts. The TUI sends the same
+signals with ! on the selected session.
+
+Examples:
+  juggle agent signal my-feature complete
+  juggle agent signal my-feature blocked --reason "Waiting on the API team"
+  juggle agent signal all continue`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAgentSignal,
+}
+
+func init() {
+	agentParseCheckCmd.Flags().BoolVar(&parseCheckStrict, "strict", false, "Parse as if strict signal detection were configured")
+	agentCmd.AddCommand(agentParseCheckCmd)
+
+	agentSignalCmd.Flags().StringVar(&agentSignalReason, "reason", "", "Why the run is blocked (with blocked)")
+	agentCmd.AddCommand(agentSignalCmd)
+}
+
+func runAgentSignal(cmd *cobra.Command, args []string) error {
+	sessionID := args[0]
+	signal, err := session.ParseLoopSignal(args[1])
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+	if agentSignalReason != "" && signal != session.LoopSignalBlocked {
+		return validationErrorf("--reason can only be used with blocked")
+	}
+
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStore(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create session store: %w", err)
+	}
+	if sessionID != "all" {
+		if _, err := sessionStore.LoadSession(sessionID); err != nil {
+			return notFoundErrorf("session not found: %s", sessionID)
+		}
+	}
+	storageID := sessionStorageID(sessionID)
+
+	if locked, _ := sessionStore.IsLocked(storageID); !locked {
+		fmt.Fprintf(os.Stderr, "Warning: no agent is running on session %s; the signal applies only to a run already in progress\n", sessionID)
+	}
+
+	human := &session.HumanSignal{
+		SessionID: storageID,
+		Signal:    signal,
+		Reason:    agentSignalReason,
+		Source:    "cli",
+	}
+	if err := sessionStore.SendHumanSignal(human); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Sent %s to session %s\n", strings.ToUpper(string(signal)), sessionID)
+	fmt.Println("  The loop acts on it when the current iteration ends.")
+	return nil
+}
+
+// parseCheckReport is the JSON output of agent parse-check
+type parseCheckReport struct {
+	Strict        bool                   `json:"strict"`
+	Tags          []string               `json:"tags"`
+	Signals       []provider.SignalMatch `json:"signals"`
+	Complete      bool                   `json:"complete"`
+	Continue      bool                   `json:"continue"`
+	Blocked       bool                   `json:"blocked"`
+	BlockedReason string                 `json:"blocked_reason,omitempty"`
+	CommitMessage string                 `json:"commit_message,omitempty"`
+}
+
+func runAgentParseCheck(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return validationErrorf("failed to read agent output: %w", err)
+	}
+
+	opts := loadSignalOptions()
+	if parseCheckStrict {
+		opts.Strict = true
+	}
+
+	result := &provider.RunResult{Output: string(data)}
+	signals := provider.ApplySignals(result, opts)
+	report := parseCheckReport{
+		Strict:        opts.Strict,
+		Tags:          append([]string{provider.DefaultSignalTag}, opts.Tags...),
+		Signals:       signals,
+		Complete:      result.Complete,
+		Continue:      result.Continue,
+		Blocked:       result.Blocked,
+		BlockedReason: result.BlockedReason,
+		CommitMessage: result.CommitMessage,
+	}
+	if report.Signals == nil {
+		report.Signals = []provider.SignalMatch{}
+	}
+
+	if GlobalOpts.JSONOutput {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	mode := "tolerant"
+	if report.Strict {
+		mode = "strict"
+	}
+	fmt.Printf("Detection: %s, tags: %s\n\n", mode, strings.Join(report.Tags, ", "))
+
+	if len(signals) == 0 {
+		fmt.Println("No signals found.")
+		if !opts.Strict {
+			fmt.Println("\nThe agent must emit <promise>COMPLETE</promise>, <promise>CONTINUE</promise>")
+			fmt.Println("or <promise>BLOCKED: reason</promise> for the loop to act on its output.")
+		}
+	} else {
+		for _, signal := range signals {
+			fmt.Printf("line %d  %-8s  %-4s  %s\n", signal.Line, signal.Kind, signal.Form, strings.Join(strings.Fields(signal.Raw), " "))
+			if signal.Detail != "" {
+				fmt.Printf("          %s\n", signal.Detail)
+			}
+		}
+	}
+
+	fmt.Println()
+	// Same precedence as the agent loop
+	switch {
+	case report.Complete:
+		fmt.Println("Result: COMPLETE")
+	case report.Continue:
+		fmt.Println("Result: CONTINUE")
+	case report.Blocked:
+		fmt.Printf("Result: BLOCKED (%s)\n", report.BlockedReason)
+	default:
+		fmt.Println("Result: no signal (the loop would start another iteration)")
+	}
+	if report.CommitMessage != "" {
+		fmt.Printf("Commit message: %s\n", report.CommitMessage)
+	}
+	return nil
+}
+
+// loadSignalOptions returns the promise signal detection settings from the
+// global config, falling back to the defaults if it can't be read
+func loadSignalOptions() provider.SignalOptions {
+	signals, err := session.GetGlobalAgentSignalsWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load signal settings: %v\n", err)
+	}
+	return provider.SignalOptions{Strict: signals.Strict, Tags: signals.Tags}
+}
@@ -232,8 +232,11 @@ func showSessionSelector(store *session.Store, sessionStore *session.SessionStor
 	model := tui.InitialSplitModelWithWatcher(store, sessionStore, config, !GlobalOpts.AllProjects, w, "")
 
 	// Run the TUI
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	applyIconConfig()
+	restoreHooks := sendHooksToTUI(&model)
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithReportFocus())
 	finalModel, err := p.Run()
+	restoreHooks()
 	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
@@ -258,7 +261,7 @@ func createAndStartBall(store *session.Store, cwd, intent string) error {
 	// Validate and get priority
 	priority := priorityFlag
 	if !session.ValidatePriority(priority) {
-		return fmt.Errorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
+		return validationErrorf("invalid priority %q, must be one of: low, medium, high, urgent", priority)
 	}
 
 	// Get description from flag or prompt
@@ -292,7 +295,7 @@ func createAndStartBall(store *session.Store, cwd, intent string) error {
 	if modelSizeFlag != "" {
 		modelSize := session.ModelSize(modelSizeFlag)
 		if modelSize != session.ModelSizeSmall && modelSize != session.ModelSizeMedium && modelSize != session.ModelSizeLarge {
-			return fmt.Errorf("invalid model size %q, must be one of: small, medium, large", modelSizeFlag)
+			return validationErrorf("invalid model size %q, must be one of: small, medium, large", modelSizeFlag)
 		}
 		ball.ModelSize = modelSize
 	}
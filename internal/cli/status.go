@@ -14,8 +14,9 @@ import (
 )
 
 var (
-	filterTags     string
-	filterPriority string
+	filterTags      string
+	filterPriority  string
+	filterMilestone string
 )
 
 var statusCmd = &cobra.Command{
@@ -29,13 +30,15 @@ Examples:
   juggle status                    # Show current project only
   juggle status --all              # Show all discovered projects
   juggle status --tags feature     # Filter by tags
-  juggle status --priority high    # Filter by priority`,
+  juggle status --priority high    # Filter by priority
+  juggle status --milestone v1.2   # Filter by milestone`,
 	RunE:  runStatus,
 }
 
 func init() {
 	statusCmd.Flags().StringVar(&filterTags, "tags", "", "Filter by tags (comma-separated, OR logic)")
 	statusCmd.Flags().StringVar(&filterPriority, "priority", "", "Filter by priority (low|medium|high|urgent)")
+	statusCmd.Flags().StringVar(&filterMilestone, "milestone", "", "Filter by milestone")
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -78,6 +81,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load balls: %w", err)
 	}
 
+	// Surface conflicts the merge driver resolved automatically
+	renderMergeConflictWarning(projects)
+
 	// Filter to non-complete balls
 	activeBalls := make([]*session.Ball, 0)
 	for _, ball := range allBalls {
@@ -119,7 +125,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Apply priority filter if specified
 	if filterPriority != "" {
 		if !session.ValidatePriority(filterPriority) {
-			return fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", filterPriority)
+			return validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", filterPriority)
 		}
 
 		filtered := make([]*session.Ball, 0)
@@ -131,8 +137,19 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		activeBalls = filtered
 	}
 
+	// Apply milestone filter if specified
+	if filterMilestone != "" {
+		filtered := make([]*session.Ball, 0)
+		for _, ball := range activeBalls {
+			if strings.EqualFold(ball.Milestone, filterMilestone) {
+				filtered = append(filtered, ball)
+			}
+		}
+		activeBalls = filtered
+	}
+
 	if len(activeBalls) == 0 {
-		if filterTags != "" || filterPriority != "" {
+		if filterTags != "" || filterPriority != "" || filterMilestone != "" {
 			fmt.Println("No balls match the specified filters.")
 			if filterTags != "" {
 				fmt.Printf("  Tags: %s\n", filterTags)
@@ -140,6 +157,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			if filterPriority != "" {
 				fmt.Printf("  Priority: %s\n", filterPriority)
 			}
+			if filterMilestone != "" {
+				fmt.Printf("  Milestone: %s\n", filterMilestone)
+			}
 		} else {
 			fmt.Println("No active balls found.")
 			fmt.Println("\nStart a new session with: juggle start")
@@ -149,7 +169,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Show active filters
-	if filterTags != "" || filterPriority != "" {
+	if filterTags != "" || filterPriority != "" || filterMilestone != "" {
 		fmt.Println("Active filters:")
 		if filterTags != "" {
 			fmt.Printf("  Tags: %s\n", filterTags)
@@ -157,6 +177,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		if filterPriority != "" {
 			fmt.Printf("  Priority: %s\n", filterPriority)
 		}
+		if filterMilestone != "" {
+			fmt.Printf("  Milestone: %s\n", filterMilestone)
+		}
 		fmt.Println()
 	}
 
@@ -194,6 +217,23 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// renderMergeConflictWarning prints a warning for projects with merge
+// conflicts recorded by "juggle merge-driver"
+func renderMergeConflictWarning(projects []string) {
+	for _, projectDir := range projects {
+		projectStore, err := session.NewStoreWithConfig(projectDir, GetStoreConfig())
+		if err != nil {
+			continue
+		}
+		conflicts, err := projectStore.LoadMergeConflicts()
+		if err != nil || len(conflicts) == 0 {
+			continue
+		}
+		fmt.Println(StyleBlocked.Render(fmt.Sprintf("⚠ %d merge conflict(s) in %s need review", len(conflicts), projectDir)))
+		fmt.Printf("  Run: juggle merge-driver conflicts\n\n")
+	}
+}
+
 
 func renderGroupedSessions(ballsByProject map[string][]*session.Ball, cwd string, currentBallID string) {
 	// Use consistent styles from styles.go
@@ -215,7 +255,7 @@ func renderGroupedSessions(ballsByProject map[string][]*session.Ball, cwd string
 
 		// Project header
 		projectName := projectPath
-		if projectPath == cwd {
+		if session.SamePath(projectPath, cwd) {
 			projectName = projectName + " (current)"
 		}
 		fmt.Printf("\n%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Render(projectName))
@@ -293,6 +333,9 @@ func renderGroupedSessions(ballsByProject map[string][]*session.Ball, cwd string
 				criteriaCell + " " +
 				intentCell,
 			)
+			if ball.NextAction != "" {
+				fmt.Println(StyleDim.Render("    next: " + truncate(ball.NextAction, 80)))
+			}
 
 		}
 	}
@@ -21,6 +21,7 @@ var (
 	StyleProject   = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))                                       // Cyan
 	StyleDim       = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))                                        // Gray
 	StyleHighlight = lipgloss.NewStyle().Bold(true)                                                             // Bold
+	StyleMatch     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))                            // Yellow bold - search match
 	StyleHeader    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("8"))
 )
 
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/ohare93/juggle/internal/session"
@@ -10,13 +12,19 @@ import (
 
 var (
 	tagBallID string
+
+	tagApplyQuery  string
+	tagApplyAdd    []string
+	tagApplyRemove []string
+	tagApplyDryRun bool
 )
 
 var tagCmd = &cobra.Command{
 	Use:     "tag",
 	Aliases: []string{"tags"},
 	Short: "Manage tags for a session",
-	Long:  `Add, remove, and list tags for work sessions.`,
+	Long: `Add, remove, and list tags for work sessions, or retag many balls at
+once with 'tag apply'.`,
 }
 
 var tagAddCmd = &cobra.Command{
@@ -51,7 +59,39 @@ var tagListCmd = &cobra.Command{
 	RunE:  runTagList,
 }
 
+var tagApplyCmd = &cobra.Command{
+	Use:   "apply --query <query> [--add <tag>...] [--remove <tag>...]",
+	Short: "Add and remove tags on every ball matching a query",
+	Long: `Add and remove tags on every active ball matching a query, in one
+locked update per project. Since sessions are tags, this moves a batch of
+balls from one session to another in a single command.
+
+Query terms (all must match):
+  state:<state>          pending, in_progress, blocked, complete, researched
+  tag:<tag>              ball has the tag (session:<id> is the same)
+  priority:<priority>    low, medium, high, urgent
+  <word>                 title contains the word (case-insensitive)
+
+Separate values with commas to match any of them (state:pending,blocked),
+and prefix a term with - to negate it (-tag:wip).
+
+Use --dry-run to preview the changes without writing them.
+
+Examples:
+  juggle tag apply --query "state:pending tag:old-session" --add new-session --remove old-session
+  juggle tag apply --query "login -state:complete" --add auth --dry-run
+  juggle tag apply --all --query "tag:q3" --remove q3`,
+	Args: cobra.NoArgs,
+	RunE: runTagApply,
+}
+
 func init() {
+	tagApplyCmd.Flags().StringVarP(&tagApplyQuery, "query", "q", "", "Balls to retag (e.g. \"state:pending tag:old-session\")")
+	tagApplyCmd.Flags().StringSliceVar(&tagApplyAdd, "add", nil, "Tags to add (comma-separated or repeated)")
+	tagApplyCmd.Flags().StringSliceVar(&tagApplyRemove, "remove", nil, "Tags to remove (comma-separated or repeated)")
+	tagApplyCmd.Flags().BoolVar(&tagApplyDryRun, "dry-run", false, "Show what would change without writing")
+	tagApplyCmd.MarkFlagRequired("query")
+
 	// Add --ball flag to tag subcommands
 	tagAddCmd.Flags().StringVar(&tagBallID, "ball", "", "Target specific ball by ID")
 	tagRmCmd.Flags().StringVar(&tagBallID, "ball", "", "Target specific ball by ID")
@@ -59,6 +99,7 @@ func init() {
 	tagCmd.AddCommand(tagAddCmd)
 	tagCmd.AddCommand(tagRmCmd)
 	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagApplyCmd)
 }
 
 // getCurrentBallForTag finds the appropriate ball to operate on
@@ -240,3 +281,112 @@ func runTagList(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// tagApplyResult is the JSON output of tag apply for one project
+type tagApplyResult struct {
+	Project string              `json:"project"`
+	Changes []session.TagChange `json:"changes"`
+}
+
+func runTagApply(cmd *cobra.Command, args []string) error {
+	query, err := session.ParseBallQuery(tagApplyQuery)
+	if err != nil {
+		return validationErrorf("invalid query: %w", err)
+	}
+
+	add := cleanTagList(tagApplyAdd)
+	remove := cleanTagList(tagApplyRemove)
+	if len(add) == 0 && len(remove) == 0 {
+		return usageErrorf("nothing to do (use --add and/or --remove)")
+	}
+	for _, tag := range add {
+		for _, other := range remove {
+			if tag == other {
+				return validationErrorf("tag %q is in both --add and --remove", tag)
+			}
+		}
+	}
+
+	config, err := LoadConfigForCommand()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	projects, err := DiscoverProjectsForCommand(config, nil)
+	if err != nil {
+		return fmt.Errorf("failed to discover projects: %w", err)
+	}
+
+	results := make([]tagApplyResult, 0, len(projects))
+	total := 0
+	for _, project := range projects {
+		store, err := NewStoreForCommand(project)
+		if err != nil {
+			return fmt.Errorf("failed to initialize store for %s: %w", project, err)
+		}
+		changes, err := store.RetagBalls(query, add, remove, tagApplyDryRun)
+		if err != nil {
+			return fmt.Errorf("failed to retag balls in %s: %w", project, err)
+		}
+		if len(changes) > 0 {
+			results = append(results, tagApplyResult{Project: project, Changes: changes})
+			total += len(changes)
+		}
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if total == 0 {
+		fmt.Println("No matching balls need their tags changed.")
+		return nil
+	}
+
+	for _, result := range results {
+		if len(projects) > 1 {
+			fmt.Printf("%s:\n", filepath.Base(result.Project))
+		}
+		for _, change := range result.Changes {
+			var edits []string
+			for _, tag := range change.Removed {
+				edits = append(edits, "-"+tag)
+			}
+			for _, tag := range change.Added {
+				edits = append(edits, "+"+tag)
+			}
+			fmt.Printf("  %-12s %-40s %s\n", change.BallID, truncate(change.Title, 40), strings.Join(edits, " "))
+		}
+	}
+	fmt.Println()
+
+	plural := "ball"
+	if total != 1 {
+		plural = "balls"
+	}
+	if tagApplyDryRun {
+		fmt.Printf("Would retag %d %s (dry run, nothing written)\n", total, plural)
+	} else {
+		fmt.Printf("✓ Retagged %d %s\n", total, plural)
+	}
+	return nil
+}
+
+// cleanTagList trims tags and drops empty and repeated ones
+func cleanTagList(tags []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var sessionsTargetsNotifyFlag bool
+
+var sessionsTargetsCmd = &cobra.Command{
+	Use:   "targets",
+	Short: "Report progress toward session throughput targets",
+	Long: `Report how each session with a throughput target is doing: balls
+completed this period and in the previous two.
+
+A session is behind when it missed its target in two consecutive periods.
+Periods that started before the target was set are not judged.
+
+With --notify, target_notify_command from the global config is run once per
+period for each session that is behind, so the check can run from cron.
+The command receives JUGGLE_SESSION, JUGGLE_PROJECT, JUGGLE_TARGET and
+JUGGLE_TARGET_DONE (counts for the previous periods, most recent first).
+
+Set a target with: juggle sessions edit <id> --target 5/week
+
+Examples:
+  juggle sessions targets
+  juggle sessions targets --notify`,
+	Args: cobra.NoArgs,
+	RunE: runSessionsTargets,
+}
+
+func init() {
+	sessionsTargetsCmd.Flags().BoolVar(&sessionsTargetsNotifyFlag, "notify", false, "Run target_notify_command for sessions that fell behind")
+	sessionsCmd.AddCommand(sessionsTargetsCmd)
+}
+
+func runSessionsTargets(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	var notifyCommand string
+	if sessionsTargetsNotifyFlag {
+		config, err := session.LoadConfigWithOptions(GetConfigOptions())
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		notifyCommand = config.TargetNotifyCommand
+		if notifyCommand == "" {
+			return validationErrorf("--notify needs target_notify_command set in the global config")
+		}
+	}
+
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	balls, err := loadThroughputBalls(cwd)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	results := make([]*session.ThroughputProgress, 0)
+	for _, sess := range sessions {
+		if progress := session.SessionThroughput(sess, balls, now); progress != nil {
+			results = append(results, progress)
+		}
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(results) == 0 {
+		fmt.Println("No sessions have a throughput target.")
+		fmt.Println("\nSet one with: juggle sessions edit <id> --target 5/week")
+	} else {
+		for _, progress := range results {
+			status := ""
+			if progress.Behind {
+				status = "  BEHIND"
+			}
+			fmt.Printf("%s %s  %d/%d this %s%s\n",
+				padRight(progress.SessionID, 20), padRight(progress.Target.String(), 10),
+				progress.Current.Done, progress.Target.Count, progress.Target.Period, status)
+			for _, period := range progress.Previous {
+				fmt.Printf("  %s\n", formatTargetPeriod(progress.Target, period))
+			}
+		}
+	}
+
+	if notifyCommand == "" {
+		return nil
+	}
+	for _, progress := range results {
+		if !progress.NeedsAlert() {
+			continue
+		}
+		if err := notifyBehindTarget(notifyCommand, cwd, progress); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: target notify command failed for %s: %v\n", progress.SessionID, err)
+			continue
+		}
+		if err := sessionStore.MarkTargetAlerted(progress.SessionID, progress.Current.Start); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record alert for %s: %v\n", progress.SessionID, err)
+		}
+		if !GlobalOpts.JSONOutput {
+			fmt.Printf("🔔 Notified: %s is behind its target\n", progress.SessionID)
+		}
+	}
+	return nil
+}
+
+// loadThroughputBalls returns active and archived balls, since completed
+// balls still count toward targets after they're archived
+func loadThroughputBalls(projectDir string) ([]*session.Ball, error) {
+	store, err := NewStoreForCommand(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize ball store: %w", err)
+	}
+	balls, err := store.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+	return balls, nil
+}
+
+// formatTargetPeriod describes one past period of a target, e.g.
+// "week of Oct 5: 3/5 missed"
+func formatTargetPeriod(target *session.ThroughputTarget, period session.PeriodCount) string {
+	var label string
+	switch target.Period {
+	case session.PeriodWeek:
+		label = "week of " + period.Start.Format("Jan 2")
+	case session.PeriodMonth:
+		label = period.Start.Format("January 2006")
+	default:
+		label = period.Start.Format("Mon Jan 2")
+	}
+
+	status := "met"
+	switch {
+	case !period.Judged:
+		status = "(before target was set)"
+	case !period.Met(target):
+		status = "missed"
+	}
+	return fmt.Sprintf("%s: %d/%d %s", label, period.Done, target.Count, status)
+}
+
+// notifyBehindTarget runs the configured command for a session that fell
+// behind its target
+func notifyBehindTarget(command, projectDir string, progress *session.ThroughputProgress) error {
+	done := make([]string, 0, len(progress.Previous))
+	for _, period := range progress.Previous {
+		done = append(done, strconv.Itoa(period.Done))
+	}
+
+	notify := exec.Command("sh", "-c", command)
+	notify.Dir = projectDir
+	notify.Env = append(os.Environ(),
+		"JUGGLE_SESSION="+progress.SessionID,
+		"JUGGLE_PROJECT="+projectDir,
+		"JUGGLE_TARGET="+progress.Target.String(),
+		"JUGGLE_TARGET_DONE="+strings.Join(done, ","),
+	)
+	if out, err := notify.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, out)
+	}
+	return nil
+}
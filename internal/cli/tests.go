@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var testsRecordCmdFlag string
+
+var testsCmd = &cobra.Command{
+	Use:   "tests",
+	Short: "Track test results for balls",
+	Long: `Track the last test run for each ball.
+
+Results are usually recorded from hooks or CI jobs. The TUI shows the last
+result (pass/fail/unknown) with its timestamp, and completing a ball whose
+last recorded run failed is refused by default.
+
+The project's tests policy controls completion:
+  block   Refuse to complete balls with failing tests (default)
+  warn    Allow completion, but print a warning
+  off     Ignore recorded test results`,
+}
+
+var testsRecordCmd = &cobra.Command{
+	Use:   "record <ball-id> <pass|fail>",
+	Short: "Record a test result for a ball",
+	Long: `Record the result of a test run for a ball.
+
+Examples:
+  juggle tests record my-app-1 pass
+  juggle tests record my-app-1 fail --cmd "go test ./..."
+
+  # From a hook or CI job
+  go test ./... && juggle tests record $BALL pass --cmd "go test ./..." \
+    || juggle tests record $BALL fail --cmd "go test ./..."`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTestsRecord,
+}
+
+var testsPolicyCmd = &cobra.Command{
+	Use:   "policy [block|warn|off]",
+	Short: "Show or set the project's tests policy",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runTestsPolicy,
+}
+
+func init() {
+	testsRecordCmd.Flags().StringVar(&testsRecordCmdFlag, "cmd", "", "Command that produced the result")
+
+	testsCmd.AddCommand(testsRecordCmd)
+	testsCmd.AddCommand(testsPolicyCmd)
+	rootCmd.AddCommand(testsCmd)
+}
+
+func runTestsRecord(cmd *cobra.Command, args []string) error {
+	ballID, result := args[0], args[1]
+	if !session.ValidateTestsResult(result) {
+		return validationErrorf("invalid test result: %s (must be pass|fail)", result)
+	}
+
+	ball, store, err := findBallByID(ballID)
+	if err != nil {
+		return err
+	}
+
+	ball.RecordTests(session.TestsResult(result), testsRecordCmdFlag)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to save ball: %w", err)
+	}
+
+	fmt.Printf("✓ Recorded tests %s for %s\n", result, ball.ShortID())
+	return nil
+}
+
+func runTestsPolicy(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if len(args) == 0 {
+		policy, err := session.GetProjectTestsPolicy(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load tests policy: %w", err)
+		}
+		fmt.Printf("Tests policy: %s\n", policy)
+		return nil
+	}
+
+	if err := session.UpdateProjectTestsPolicy(cwd, args[0]); err != nil {
+		return fmt.Errorf("failed to save tests policy: %w", err)
+	}
+	fmt.Printf("Set tests policy: %s\n", args[0])
+	return nil
+}
+
+// checkTestsBeforeComplete enforces the project's tests policy before a ball is completed.
+// Returns an error if completion is refused; prints a warning if allowed despite failing tests.
+func checkTestsBeforeComplete(ball *session.Ball) error {
+	warning, err := session.CheckTestsBeforeComplete(ball)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	attachTranscriptRun       string
+	attachTranscriptIteration int
+	attachTranscriptFile      string
+	attachTranscriptNote      string
+)
+
+var attachTranscriptCmd = &cobra.Command{
+	Use:   "attach-transcript <ball-id>",
+	Short: "Attach an agent or chat transcript to a ball",
+	Long: `Attach a chunk of transcript to a ball, keeping the reasoning that
+produced a decision next to the work item.
+
+Agent transcripts come from the output of one iteration of an agent run.
+Run IDs are shown in the TUI's agent history (H); "latest" selects the most
+recent run. Without --iteration, the run's last iteration is used. Output is
+kept for the 20 most recent runs.
+
+Human chat can be attached from a file, or from stdin with --file -.
+
+Transcripts are stored compressed under .juggle/transcripts and can be read
+from the TUI's detail view (T).
+
+Examples:
+  juggle attach-transcript my-app-1 --from-run 1760000000000000000 --iteration 3
+  juggle attach-transcript my-app-1 --from-run latest --note "why we dropped the cache"
+  juggle attach-transcript my-app-1 --file design-chat.md
+  pbpaste | juggle attach-transcript my-app-1 --file -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAttachTranscript,
+}
+
+func init() {
+	attachTranscriptCmd.Flags().StringVar(&attachTranscriptRun, "from-run", "", "Agent run ID to attach output from (or \"latest\")")
+	attachTranscriptCmd.Flags().IntVar(&attachTranscriptIteration, "iteration", 0, "Iteration of the run to attach (default: last)")
+	attachTranscriptCmd.Flags().StringVar(&attachTranscriptFile, "file", "", "Attach text from a file (\"-\" for stdin)")
+	attachTranscriptCmd.Flags().StringVar(&attachTranscriptNote, "note", "", "Short note on why the transcript matters")
+	rootCmd.AddCommand(attachTranscriptCmd)
+}
+
+func runAttachTranscript(cmd *cobra.Command, args []string) error {
+	if (attachTranscriptRun == "") == (attachTranscriptFile == "") {
+		return validationErrorf("specify exactly one of --from-run or --file")
+	}
+	if attachTranscriptFile != "" && cmd.Flags().Changed("iteration") {
+		return validationErrorf("--iteration can only be used with --from-run")
+	}
+
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	transcript := session.Transcript{Note: attachTranscriptNote}
+	var content string
+	if attachTranscriptRun != "" {
+		content, err = loadRunTranscript(store.ProjectDir(), &transcript)
+	} else {
+		content, err = loadChatTranscript(&transcript)
+	}
+	if err != nil {
+		return err
+	}
+
+	transcript, err = store.SaveTranscript(ball.ID, content, transcript)
+	if err != nil {
+		return err
+	}
+	ball.AttachTranscript(transcript)
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to save ball: %w", err)
+	}
+
+	fmt.Printf("✓ Attached transcript (%s, %d lines) to %s\n", transcript.Label(), transcript.Lines, ball.ShortID())
+	return nil
+}
+
+// loadRunTranscript returns the output of the requested agent run iteration
+func loadRunTranscript(projectDir string, transcript *session.Transcript) (string, error) {
+	historyStore, err := session.NewAgentHistoryStore(projectDir)
+	if err != nil {
+		return "", err
+	}
+	record, err := historyStore.FindRecord(attachTranscriptRun)
+	if err != nil {
+		return "", validationErrorf("%v", err)
+	}
+
+	iteration := attachTranscriptIteration
+	if iteration == 0 {
+		iteration = record.Iterations
+	}
+	if iteration < 1 || iteration > record.Iterations {
+		return "", validationErrorf("invalid iteration %d (run %s has %d iterations)", iteration, record.ID, record.Iterations)
+	}
+
+	content, err := historyStore.LoadIterationOutput(record.ID, iteration)
+	if err != nil {
+		return "", err
+	}
+	transcript.Source = session.TranscriptSourceAgent
+	transcript.RunID = record.ID
+	transcript.Iteration = iteration
+	return content, nil
+}
+
+// loadChatTranscript returns chat text from --file or stdin
+func loadChatTranscript(transcript *session.Transcript) (string, error) {
+	var data []byte
+	var err error
+	if attachTranscriptFile == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(attachTranscriptFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read transcript: %w", err)
+	}
+	transcript.Source = session.TranscriptSourceChat
+	return string(data), nil
+}
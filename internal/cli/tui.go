@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ohare93/juggle/internal/session"
@@ -11,7 +12,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var tuiSessionFilter string
+var (
+	tuiSessionFilter string
+	tuiView          string
+	tuiOutputLog     string
+)
 
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
@@ -27,6 +32,18 @@ Use --all flag to show balls from all discovered projects:
 Use --session to start with a session pre-selected:
   juggle tui --session my-feature
 
+Use --view to choose the layout (split, list, or board):
+  juggle tui --view board
+
+Use --output-log to append everything shown in the agent output panel,
+status markers included, to a file as it arrives:
+  juggle tui --output-log /tmp/agent.log   (then: tail -f /tmp/agent.log)
+
+Without --view, the TUI starts in the layout set by "juggle config view set"
+(split by default). The selected session, panel, filters, sort order,
+columns and scroll positions are remembered per project and restored on the
+next launch.
+
 Navigation:
   Tab/h/l    Switch between panels (sessions → balls → todos)
   ↑/k        Move up within panel
@@ -46,6 +63,9 @@ State Management:
   c          Complete ball (→ complete, archives)
   b          Block ball (prompts for reason)
 
+Layout:
+  vv         Cycle layout (split → list → board)
+
 Search/Filter:
   /          Open search/filter for current panel
   Ctrl+U     Clear current filter
@@ -105,19 +125,45 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		w.Start()
 	}
 
+	applyIconConfig()
 	model := tui.InitialSplitModelWithWatcher(store, sessionStore, config, !GlobalOpts.AllProjects, w, tuiSessionFilter)
+	if err := restoreTUIState(&model, config, workingDir); err != nil {
+		return err
+	}
 
-	// Create program with alternate screen
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	// Show the configured provider's API health in the status bar
+	healthProvider := string(configuredProvider(workingDir))
+	configOpts := GetConfigOptions()
+	model.SetAPIHealthLoader(func() (session.APIHealthSummary, error) {
+		return session.GetAPIHealthWithOptions(configOpts, healthProvider, time.Now())
+	})
+
+	if tuiOutputLog != "" {
+		mirror, err := os.OpenFile(tuiOutputLog, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open output log: %w", err)
+		}
+		defer mirror.Close()
+		model.SetAgentOutputMirror(mirror)
+	}
+
+	// Create program with alternate screen, reporting focus for attention signals
+	restoreHooks := sendHooksToTUI(&model)
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithReportFocus())
 
 	// Run
 	finalModel, err := p.Run()
+	restoreHooks()
 	if err != nil {
 		return err
 	}
 
 	// Check if user requested to run agent after TUI exit
 	if tuiModel, ok := finalModel.(tui.Model); ok {
+		if err := session.SaveTUIStateWithOptions(GetConfigOptions(), workingDir, tuiModel.State()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save TUI state: %v\n", err)
+		}
+
 		if ballID := tuiModel.RunAgentForBall(); ballID != "" {
 			fmt.Printf("\nStarting agent for ball %s...\n", ballID)
 
@@ -142,5 +188,39 @@ func runTUI(cmd *cobra.Command, args []string) error {
 
 func init() {
 	tuiCmd.Flags().StringVar(&tuiSessionFilter, "session", "", "Start with session pre-selected")
+	tuiCmd.Flags().StringVar(&tuiView, "view", "", "Start in this layout: split, list, or board")
+	tuiCmd.Flags().StringVar(&tuiOutputLog, "output-log", "", "Append the agent output panel to this file as it arrives")
 	rootCmd.AddCommand(tuiCmd)
 }
+
+// restoreTUIState applies the state remembered from the last launch in
+// projectDir and the startup layout from --view or the default_view setting
+func restoreTUIState(model *tui.Model, config *session.Config, projectDir string) error {
+	if tuiView != "" && !session.ValidateView(tuiView) {
+		return validationErrorf("invalid view %q (must be %s, %s, or %s)", tuiView, session.ViewSplit, session.ViewList, session.ViewBoard)
+	}
+
+	state, err := session.LoadTUIStateWithOptions(GetConfigOptions(), projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load TUI state: %v\n", err)
+	}
+	model.RestoreState(state)
+
+	view := tuiView
+	if view == "" {
+		view = config.GetDefaultView()
+	}
+	if layout, ok := tui.ParseViewLayout(view); ok {
+		model.SetLayout(layout)
+	}
+	return nil
+}
+
+// applyIconConfig sets the TUI glyphs from the icon settings in global config
+func applyIconConfig() {
+	icons, err := session.GetGlobalIconsWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load icon config: %v\n", err)
+	}
+	tui.SetIcons(icons)
+}
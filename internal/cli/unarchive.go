@@ -103,14 +103,14 @@ func findArchivedBallByID(ballID string) (*session.Ball, *session.Store, error)
 	// Use prefix matching
 	matches := session.ResolveBallByPrefix(archivedBalls, ballID)
 	if len(matches) == 0 {
-		return nil, nil, fmt.Errorf("ball not found in archives: %s", ballID)
+		return nil, nil, notFoundErrorf("ball not found in archives: %s", ballID)
 	}
 	if len(matches) > 1 {
 		matchingIDs := make([]string, len(matches))
 		for i, m := range matches {
 			matchingIDs[i] = m.ID
 		}
-		return nil, nil, fmt.Errorf("ambiguous ID '%s' matches %d archived balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
+		return nil, nil, validationErrorf("ambiguous ID '%s' matches %d archived balls: %s", ballID, len(matches), strings.Join(matchingIDs, ", "))
 	}
 
 	ball := matches[0]
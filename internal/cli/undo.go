@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var undoList bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [count]",
+	Short: "Undo the last change to a ball",
+	Long: fmt.Sprintf(`Undo the most recent ball changes in this project.
+
+Creating, updating, deleting, archiving and unarchiving a ball are recorded
+in .juggle/undo.jsonl, which keeps the last %d changes. Completing a ball
+(update and archive) is undone in one step. Undone changes can be redone
+with 'juggle redo' until the next change is made.
+
+Examples:
+  juggle undo          # Undo the last change
+  juggle undo 3        # Undo the last 3 changes
+  juggle undo --list   # Show the undo history`, session.UndoLevels),
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo [count]",
+	Short: "Redo the last undone change to a ball",
+	Long: `Reapply changes undone with 'juggle undo', most recent first.
+
+Examples:
+  juggle redo
+  juggle redo 2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRedo,
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "Show the undo history instead of undoing")
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(redoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	store, err := newUndoStoreForCommand()
+	if err != nil {
+		return err
+	}
+	if undoList {
+		return printUndoHistory(store)
+	}
+	return stepUndo(store, args, "Undid", store.Undo)
+}
+
+func runRedo(cmd *cobra.Command, args []string) error {
+	store, err := newUndoStoreForCommand()
+	if err != nil {
+		return err
+	}
+	return stepUndo(store, args, "Redid", store.Redo)
+}
+
+// stepUndo calls step count times (from args, default 1), reporting each
+// change. Running out of history after at least one step is not an error.
+func stepUndo(store *session.Store, args []string, verb string, step func() (*session.UndoEntry, error)) error {
+	count := 1
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return usageErrorf("count must be a positive number, got %q", args[0])
+		}
+		count = n
+	}
+
+	for i := 0; i < count; i++ {
+		entry, err := step()
+		if errors.Is(err, session.ErrNothingToUndo) || errors.Is(err, session.ErrNothingToRedo) {
+			if i == 0 {
+				return validationErrorf("%v", err)
+			}
+			fmt.Printf("  (%v)\n", err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s %s of %s: %s\n", verb, entry.Action, StyleHighlight.Render(entry.BallID), entry.Title())
+	}
+	return nil
+}
+
+// printUndoHistory lists the undo history, most recent first
+func printUndoHistory(store *session.Store) error {
+	entries, err := store.UndoHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load undo history: %w", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No changes recorded yet.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WHEN\tACTION\tBALL\tTITLE\t")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		status := ""
+		if entry.Undone {
+			status = "(undone)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			entry.Timestamp.Format("2006-01-02 15:04:05"),
+			entry.Action,
+			entry.BallID,
+			truncate(entry.Title(), 50),
+			status,
+		)
+	}
+	return tw.Flush()
+}
+
+// newUndoStoreForCommand creates the store for the current project, whose
+// undo history the commands work on
+func newUndoStoreForCommand() (*session.Store, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return store, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/spf13/cobra"
@@ -22,6 +23,8 @@ var (
 	updateModelSize     string
 	updateAgentProvider string
 	updateModelOverride string
+	updateNextAction    string
+	updateCheckpoint    string
 	updateJSONFlag      bool
 	updateAddDep        []string
 	updateRemoveDep     []string
@@ -48,6 +51,8 @@ Examples:
   juggle update my-app-1 --model-size small
   juggle update my-app-1 --agent-provider opencode
   juggle update my-app-1 --model-override sonnet
+  juggle update my-app-1 --next-action "Reproduce with the failing fixture"
+  juggle update my-app-1 --checkpoint "tests written, implementation next"
   juggle update my-app-1 --add-dep other-ball-5
   juggle update my-app-1 --remove-dep other-ball-3
   juggle update my-app-1 --set-deps ball-1,ball-2`,
@@ -65,8 +70,10 @@ func init() {
 	updateCmd.Flags().StringVar(&updateBlockReason, "reason", "", "Blocked reason (required when setting state to blocked)")
 	updateCmd.Flags().StringVar(&updateOutput, "output", "", "Set research output/results")
 	updateCmd.Flags().StringVar(&updateModelSize, "model-size", "", "Set preferred model size (small|medium|large)")
-	updateCmd.Flags().StringVar(&updateAgentProvider, "agent-provider", "", "Set agent provider override (claude|opencode, empty to clear)")
+	updateCmd.Flags().StringVar(&updateAgentProvider, "agent-provider", "", "Set agent provider override (claude|opencode|http|ollama|shell, empty to clear)")
 	updateCmd.Flags().StringVar(&updateModelOverride, "model-override", "", "Set model override (opus|sonnet|haiku, empty to clear)")
+	updateCmd.Flags().StringVar(&updateNextAction, "next-action", "", "Set the immediate next step (empty to clear)")
+	updateCmd.Flags().StringVar(&updateCheckpoint, "checkpoint", "", "Record how far the work has got, to resume from (empty to clear)")
 	updateCmd.Flags().BoolVar(&updateJSONFlag, "json", false, "Output updated ball as JSON")
 	updateCmd.Flags().StringSliceVar(&updateAddDep, "add-dep", nil, "Add dependency (ball ID, can be specified multiple times)")
 	updateCmd.Flags().StringSliceVar(&updateRemoveDep, "remove-dep", nil, "Remove dependency (ball ID, can be specified multiple times)")
@@ -81,7 +88,7 @@ func init() {
 		return []string{"small", "medium", "large"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	updateCmd.RegisterFlagCompletionFunc("agent-provider", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"claude", "opencode"}, cobra.ShellCompDirectiveNoFileComp
+		return []string{"claude", "opencode", "http", "ollama", "shell"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	updateCmd.RegisterFlagCompletionFunc("model-override", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"opus", "sonnet", "haiku"}, cobra.ShellCompDirectiveNoFileComp
@@ -101,7 +108,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// If no flags provided (except --json), enter interactive mode
-	if updateIntent == "" && updatePriority == "" && updateState == "" && updateCriteria == nil && updateTags == "" && updateOutput == "" && updateModelSize == "" && updateAgentProvider == "" && updateModelOverride == "" && updateAddDep == nil && updateRemoveDep == nil && updateSetDeps == nil && !updateJSONFlag {
+	if updateIntent == "" && updatePriority == "" && updateState == "" && updateCriteria == nil && updateTags == "" && updateOutput == "" && updateModelSize == "" && updateAgentProvider == "" && updateModelOverride == "" && !cmd.Flags().Changed("next-action") && !cmd.Flags().Changed("checkpoint") && updateAddDep == nil && updateRemoveDep == nil && updateSetDeps == nil && !updateJSONFlag {
 		return runInteractiveUpdate(foundBall, foundStore)
 	}
 
@@ -118,7 +125,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	if updatePriority != "" {
 		if !session.ValidatePriority(updatePriority) {
-			err := fmt.Errorf("invalid priority: %s (must be low|medium|high|urgent)", updatePriority)
+			err := validationErrorf("invalid priority: %s (must be low|medium|high|urgent)", updatePriority)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -142,7 +149,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 		newState, ok := stateMap[updateState]
 		if !ok {
-			err := fmt.Errorf("invalid state: %s (must be pending|in_progress|blocked|complete|researched)", updateState)
+			err := validationErrorf("invalid state: %s (must be pending|in_progress|blocked|complete|researched)", updateState)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -178,6 +185,14 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 				fmt.Printf("✓ Updated state: researched\n")
 			}
 		} else {
+			if newState == session.StateComplete {
+				if err := checkTestsBeforeComplete(foundBall); err != nil {
+					if updateJSONFlag {
+						return printJSONError(err)
+					}
+					return err
+				}
+			}
 			if err := foundBall.SetState(newState); err != nil {
 				return err
 			}
@@ -211,7 +226,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	if updateModelSize != "" {
 		if !session.ValidateModelSize(updateModelSize) {
-			err := fmt.Errorf("invalid model size: %s (must be small|medium|large)", updateModelSize)
+			err := validationErrorf("invalid model size: %s (must be small|medium|large)", updateModelSize)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -226,7 +241,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	if cmd.Flags().Changed("agent-provider") {
 		if updateAgentProvider != "" && !session.ValidateAgentProvider(updateAgentProvider) {
-			err := fmt.Errorf("invalid agent provider: %s (must be claude|opencode)", updateAgentProvider)
+			err := validationErrorf("invalid agent provider: %s (must be claude|opencode|http|ollama|shell)", updateAgentProvider)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -245,7 +260,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	if cmd.Flags().Changed("model-override") {
 		if updateModelOverride != "" && !session.ValidateModelOverride(updateModelOverride) {
-			err := fmt.Errorf("invalid model override: %s (must be opus|sonnet|haiku)", updateModelOverride)
+			err := validationErrorf("invalid model override: %s (must be opus|sonnet|haiku)", updateModelOverride)
 			if updateJSONFlag {
 				return printJSONError(err)
 			}
@@ -262,6 +277,37 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if cmd.Flags().Changed("next-action") {
+		foundBall.SetNextAction(updateNextAction)
+		modified = true
+		if !updateJSONFlag {
+			if foundBall.NextAction == "" {
+				fmt.Printf("✓ Cleared next action\n")
+			} else {
+				fmt.Printf("✓ Updated next action: %s\n", foundBall.NextAction)
+			}
+		}
+	}
+
+	if cmd.Flags().Changed("checkpoint") {
+		if strings.TrimSpace(updateCheckpoint) != "" && (foundBall.State == session.StateComplete || foundBall.State == session.StateResearched) {
+			err := validationErrorf("ball %s is %s; checkpoints are for unfinished work", foundBall.ID, foundBall.State)
+			if updateJSONFlag {
+				return printJSONError(err)
+			}
+			return err
+		}
+		foundBall.SetCheckpoint(updateCheckpoint)
+		modified = true
+		if !updateJSONFlag {
+			if foundBall.Checkpoint == "" {
+				fmt.Printf("✓ Cleared checkpoint\n")
+			} else {
+				fmt.Printf("✓ Updated checkpoint: %s\n", foundBall.Checkpoint)
+			}
+		}
+	}
+
 	// Handle output separately (not tied to researched state)
 	if updateOutput != "" && updateState != "researched" {
 		foundBall.SetOutput(updateOutput)
@@ -354,6 +400,16 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	if modified {
+		// A completed recurring ball hands its schedule to a fresh instance
+		var next *session.Ball
+		if foundBall.State == session.StateComplete || foundBall.State == session.StateResearched {
+			if next, err = foundStore.Recur(foundBall, time.Now()); err != nil {
+				if updateJSONFlag {
+					return printJSONError(err)
+				}
+				return err
+			}
+		}
 		foundBall.UpdateActivity()
 		if err := foundStore.UpdateBall(foundBall); err != nil {
 			if updateJSONFlag {
@@ -365,6 +421,9 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			return printBallJSON(foundBall)
 		}
 		fmt.Printf("\n✓ Ball %s updated successfully\n", ballID)
+		if next != nil {
+			printNextInstance(next, time.Now())
+		}
 	} else if updateJSONFlag {
 		// Even with no modifications, output the ball in JSON mode
 		return printBallJSON(foundBall)
@@ -404,7 +463,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	input = strings.TrimSpace(input)
 	if input != "" {
 		if !session.ValidatePriority(input) {
-			return fmt.Errorf("invalid priority: %s", input)
+			return validationErrorf("invalid priority: %s", input)
 		}
 		ball.Priority = session.Priority(input)
 	}
@@ -415,7 +474,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	input = strings.TrimSpace(input)
 	if input != "" {
 		if !session.ValidateBallState(input) {
-			return fmt.Errorf("invalid state: %s", input)
+			return validationErrorf("invalid state: %s", input)
 		}
 		newState := session.BallState(input)
 		if newState == session.StateBlocked {
@@ -516,7 +575,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	input = strings.TrimSpace(input)
 	if input != "" {
 		if !session.ValidateModelSize(input) {
-			return fmt.Errorf("invalid model size: %s", input)
+			return validationErrorf("invalid model size: %s", input)
 		}
 		ball.SetModelSize(session.ModelSize(input))
 	}
@@ -526,7 +585,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 	if currentAgentProvider == "" {
 		currentAgentProvider = "unset"
 	}
-	fmt.Printf("Agent Provider [%s] (claude|opencode, 'clear' to remove): ", currentAgentProvider)
+	fmt.Printf("Agent Provider [%s] (claude|opencode|http|ollama|shell, 'clear' to remove): ", currentAgentProvider)
 	input, _ = reader.ReadString('\n')
 	input = strings.TrimSpace(input)
 	if input != "" && input != "-" {
@@ -534,7 +593,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 			ball.SetAgentProvider("")
 		} else {
 			if !session.ValidateAgentProvider(input) {
-				return fmt.Errorf("invalid agent provider: %s", input)
+				return validationErrorf("invalid agent provider: %s", input)
 			}
 			ball.SetAgentProvider(input)
 		}
@@ -553,7 +612,7 @@ func runInteractiveUpdate(ball *session.Ball, store *session.Store) error {
 			ball.SetModelOverride("")
 		} else {
 			if !session.ValidateModelOverride(input) {
-				return fmt.Errorf("invalid model override: %s", input)
+				return validationErrorf("invalid model override: %s", input)
 			}
 			ball.SetModelOverride(input)
 		}
@@ -619,14 +678,14 @@ func resolveDependencyIDsForUpdate(store *session.Store, ids []string, excludeID
 		// Use prefix matching
 		matches := session.ResolveBallByPrefix(balls, id)
 		if len(matches) == 0 {
-			return nil, fmt.Errorf("ball not found: %s", id)
+			return nil, notFoundErrorf("ball not found: %s", id)
 		}
 		if len(matches) > 1 {
 			matchingIDs := make([]string, len(matches))
 			for i, m := range matches {
 				matchingIDs[i] = m.ID
 			}
-			return nil, fmt.Errorf("ambiguous ID '%s' matches %d balls: %s", id, len(matches), strings.Join(matchingIDs, ", "))
+			return nil, validationErrorf("ambiguous ID '%s' matches %d balls: %s", id, len(matches), strings.Join(matchingIDs, ", "))
 		}
 		ball := matches[0]
 		if ball.ID == excludeID {
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/agent/provider"
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// verifyMaxDiffLines caps the diff sent to the agent; it reads the files
+// itself when the diff is cut short
+const verifyMaxDiffLines = 2000
+
+var (
+	verifyFrom     string
+	verifyTo       string
+	verifyProvider string
+	verifyModel    string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <ball-id>",
+	Short: "Ask the agent whether a ball's changes meet its acceptance criteria",
+	Long: `Ask the configured agent (in read-only plan mode) to check each of a ball's
+acceptance criteria against the changes made for it, and report which are
+satisfied, citing the files, lines and tests that show it.
+
+The result is stored on the ball, shown by 'juggle show' and the TUI, and
+replaced the next time the ball is verified. It's advice for reviewing the
+work or deciding to complete the ball; it doesn't change the ball's state.
+
+The changes checked are, by default, from the revision the ball was started
+at (juggle start records it) to the revision it was completed or blocked at,
+or to the working copy if it's still in progress. Without a starting
+revision, the uncommitted changes are checked. Use --from and --to to pick
+the revisions yourself.
+
+Examples:
+  juggle verify a1b2                        # Check the ball's changes
+  juggle verify a1b2 --from main            # Check everything since main
+  juggle verify a1b2 --from v1.2 --to v1.3  # Check a range of commits`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyFrom, "from", "", "Revision to diff from (default: where the ball was started)")
+	verifyCmd.Flags().StringVar(&verifyTo, "to", "", "Revision to diff to (default: where the ball was completed, or the working copy)")
+	verifyCmd.Flags().StringVar(&verifyProvider, "provider", "", "Agent provider to use (claude, opencode, http, ollama, shell). Default: from config or claude")
+	verifyCmd.Flags().StringVarP(&verifyModel, "model", "m", "", "Model to use (opus, sonnet, haiku)")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// acVerdictPattern matches one criterion's verdict emitted by the agent
+var acVerdictPattern = regexp.MustCompile(`(?s)<ac\s+([^>]*)>(.*?)</ac>`)
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+	if len(ball.AcceptanceCriteria) == 0 {
+		return validationErrorf("ball %s has no acceptance criteria to verify", ball.ShortID())
+	}
+
+	// Configure agent provider
+	globalProvider, err := session.GetGlobalAgentProviderWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global agent provider config: %v\n", err)
+	}
+	projectProvider, err := session.GetProjectAgentProvider(ball.WorkingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project agent provider config: %v\n", err)
+	}
+	providerType := provider.Detect(verifyProvider, projectProvider, globalProvider)
+	if !provider.IsAvailable(providerType) {
+		return fmt.Errorf("agent provider %q is not available (binary %q not found in PATH)",
+			providerType, provider.BinaryName(providerType))
+	}
+	agent.SetProvider(newAgentProvider(providerType))
+
+	// Configure model overrides
+	globalOverrides, err := session.GetGlobalModelOverridesWithOptions(GetConfigOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load global model overrides: %v\n", err)
+	}
+	projectOverrides, err := session.GetProjectModelOverrides(ball.WorkingDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load project model overrides: %v\n", err)
+	}
+	agent.SetModelOverrides(session.MergeModelOverrides(globalOverrides, projectOverrides))
+
+	verification, err := verifyBall(store, ball, verifyFrom, verifyTo, verifyModel)
+	if err != nil {
+		return err
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(verification, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printVerification(ball, verification)
+	return nil
+}
+
+// verifyBall runs the agent in read-only mode on the ball's criteria and
+// changes, and stores its assessments on the ball
+func verifyBall(store *session.Store, ball *session.Ball, from, to, model string) (*session.Verification, error) {
+	diff, diffRange, err := verifyDiff(ball, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(diff) == "" {
+		fmt.Fprintf(os.Stderr, "Warning: no changes found in %s; the agent will check the code as it is\n", diffRange)
+	}
+
+	fmt.Fprintf(os.Stderr, "Verifying %d criteria against %s...\n", len(ball.AcceptanceCriteria), diffRange)
+	result, err := agent.DefaultRunner.Run(agent.RunOptions{
+		Prompt:     generateVerifyPrompt(ball, diff, diffRange),
+		Mode:       agent.ModeHeadless,
+		Permission: agent.PermissionPlan,
+		Model:      model,
+		WorkingDir: ball.WorkingDir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("agent failed: %w", err)
+	}
+	if result.RateLimited {
+		return nil, fmt.Errorf("agent was rate limited, try again later")
+	}
+
+	verification := &session.Verification{
+		Assessments: parseVerifyOutput(result.Output, ball.AcceptanceCriteria),
+		DiffRange:   diffRange,
+		VerifiedAt:  time.Now(),
+	}
+	ball.Verification = verification
+	ball.UpdateActivity()
+	if err := store.UpdateBall(ball); err != nil {
+		return nil, fmt.Errorf("failed to update ball: %w", err)
+	}
+	return verification, nil
+}
+
+// verifyDiff returns the ball's changes and a description of the range:
+// from where it was started to where it was completed or blocked (or the
+// working copy), unless from or to are given
+func verifyDiff(ball *session.Ball, from, to string) (string, string, error) {
+	if from == "" {
+		from = ball.StartingRevision
+	}
+	if to == "" {
+		to = ball.RevisionID
+	}
+
+	fromLabel, toLabel := from, to
+	if fromLabel == "" {
+		fromLabel = "last commit"
+		if to != "" {
+			fromLabel = "parent"
+		}
+	}
+	if toLabel == "" {
+		toLabel = "working copy"
+	}
+	diffRange := fromLabel + ".." + toLabel
+
+	diff, err := getVCSBackendForBall(ball).Diff(ball.WorkingDir, from, to)
+	if err != nil {
+		return "", diffRange, fmt.Errorf("failed to diff %s: %w", diffRange, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	if len(lines) > verifyMaxDiffLines {
+		diff = strings.Join(lines[:verifyMaxDiffLines], "\n") +
+			fmt.Sprintf("\n... (diff truncated, %d more lines)\n", len(lines)-verifyMaxDiffLines)
+	}
+	return diff, diffRange, nil
+}
+
+// generateVerifyPrompt builds the verification prompt for a ball and its diff
+func generateVerifyPrompt(ball *session.Ball, diff, diffRange string) string {
+	var buf strings.Builder
+
+	buf.WriteString("<ball>\n")
+	writeBallForRefine(&buf, ball)
+	buf.WriteString("</ball>\n\n")
+
+	buf.WriteString("<criteria>\n")
+	for i, ac := range ball.AcceptanceCriteria {
+		buf.WriteString(fmt.Sprintf("%d. %s\n", i+1, ac))
+	}
+	buf.WriteString("</criteria>\n\n")
+
+	buf.WriteString(fmt.Sprintf("<diff range=%q>\n", diffRange))
+	if strings.TrimSpace(diff) == "" {
+		buf.WriteString("(no changes)\n")
+	} else {
+		buf.WriteString(diff)
+		if !strings.HasSuffix(diff, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString("</diff>\n\n")
+
+	buf.WriteString("<instructions>\n")
+	buf.WriteString(agent.GetVerifyPromptTemplate())
+	if !strings.HasSuffix(agent.GetVerifyPromptTemplate(), "\n") {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("</instructions>\n")
+
+	return buf.String()
+}
+
+// parseVerifyOutput extracts the agent's verdict on each criterion. Criteria
+// the agent didn't assess are recorded as unsatisfied.
+func parseVerifyOutput(output string, criteria []string) []session.ACAssessment {
+	assessments := make([]session.ACAssessment, len(criteria))
+	assessed := make([]bool, len(criteria))
+	for i, criterion := range criteria {
+		assessments[i] = session.ACAssessment{Criterion: criterion, Evidence: "Not assessed by the agent"}
+	}
+
+	for _, match := range acVerdictPattern.FindAllStringSubmatch(output, -1) {
+		attrs := make(map[string]string)
+		for _, attr := range estimateAttrPattern.FindAllStringSubmatch(match[1], -1) {
+			attrs[strings.ToLower(attr[1])] = strings.TrimSpace(attr[2])
+		}
+
+		index, err := strconv.Atoi(attrs["index"])
+		if err != nil || index < 1 || index > len(criteria) || assessed[index-1] {
+			continue
+		}
+		status := strings.ToLower(attrs["status"])
+		if status != "satisfied" && status != "unsatisfied" {
+			continue
+		}
+		assessed[index-1] = true
+		assessments[index-1].Satisfied = status == "satisfied"
+		assessments[index-1].Evidence = strings.Join(strings.Fields(match[2]), " ")
+	}
+	return assessments
+}
+
+// printVerification prints each criterion's verdict and a summary
+func printVerification(ball *session.Ball, v *session.Verification) {
+	fmt.Printf("Ball %s: %s\n", ball.ShortID(), ball.Title)
+	fmt.Printf("Changes: %s\n\n", v.DiffRange)
+	for i, a := range v.Assessments {
+		mark := "✗"
+		if a.Satisfied {
+			mark = "✓"
+		}
+		fmt.Printf("%s %d. %s\n", mark, i+1, a.Criterion)
+		if a.Evidence != "" {
+			fmt.Printf("     %s\n", a.Evidence)
+		}
+	}
+	fmt.Printf("\n%d/%d criteria satisfied\n", v.SatisfiedCount(), len(v.Assessments))
+}
+
+// VerifyBallForTest is an exported wrapper for testing
+func VerifyBallForTest(store *session.Store, ball *session.Ball, from, to string) (*session.Verification, error) {
+	return verifyBall(store, ball, from, to, "")
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Manage file watch globs for balls",
+	Long: `Attach project-relative file globs to a ball.
+
+While the TUI is running, changes to files matching a ball's globs mark the
+ball with a "code changed" indicator and log an activity entry, so you notice
+when the area a pending ball covers is being modified by other work. The
+indicator clears when the ball is opened for editing.
+
+Globs use '/' separators; "**" matches any number of directories.`,
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <ball-id> <glob> [<glob2> ...]",
+	Short: "Add watch globs to a ball",
+	Long: `Add one or more watch globs to a ball.
+
+Examples:
+  juggle watch add my-app-1 "src/auth/**"
+  juggle watch add my-app-1 "internal/**/*.go" go.mod`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runWatchAdd,
+}
+
+var watchRmCmd = &cobra.Command{
+	Use:   "rm <ball-id> <glob> [<glob2> ...]",
+	Short: "Remove watch globs from a ball",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runWatchRm,
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list <ball-id>",
+	Short: "List a ball's watch globs",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWatchList,
+}
+
+func init() {
+	watchCmd.AddCommand(watchAddCmd)
+	watchCmd.AddCommand(watchRmCmd)
+	watchCmd.AddCommand(watchListCmd)
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatchAdd(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, glob := range args[1:] {
+		if err := ball.AddWatchGlob(strings.TrimSpace(glob)); err != nil {
+			return err
+		}
+	}
+
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Watching for ball %s: %s\n", ball.ShortID(), strings.Join(ball.WatchGlobs, ", "))
+	return nil
+}
+
+func runWatchRm(cmd *cobra.Command, args []string) error {
+	ball, store, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, glob := range args[1:] {
+		if ball.RemoveWatchGlob(strings.TrimSpace(glob)) {
+			removed++
+		}
+	}
+	if removed == 0 {
+		return fmt.Errorf("ball %s is not watching any of: %s", ball.ShortID(), strings.Join(args[1:], ", "))
+	}
+
+	if err := store.UpdateBall(ball); err != nil {
+		return fmt.Errorf("failed to update ball: %w", err)
+	}
+
+	fmt.Printf("✓ Removed %d watch glob(s) from ball %s\n", removed, ball.ShortID())
+	return nil
+}
+
+func runWatchList(cmd *cobra.Command, args []string) error {
+	ball, _, err := findBallByID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(ball.WatchGlobs) == 0 {
+		fmt.Printf("Ball %s has no watch globs\n", ball.ShortID())
+		return nil
+	}
+	for _, glob := range ball.WatchGlobs {
+		fmt.Println(glob)
+	}
+	return nil
+}
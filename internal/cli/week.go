@@ -0,0 +1,401 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var weekFlag string
+
+var weekCmd = &cobra.Command{
+	Use:   "week",
+	Short: "Plan which balls to work on this week",
+	Long: `Plan a week of work: pick balls from any session into the week's bucket,
+compare the plan against your capacity, run the agent on just that week, and
+review planned vs. done when the week is over.
+
+A ball is planned for a week by tagging it "week-YYYY-wNN". That tag is also
+the ID of the week's agent session, so "juggle agent run week-2026-w42" works
+through exactly the planned balls.
+
+Capacity is measured in points from each ball's model size: small = 1,
+medium = 2, large = 3. Unsized balls count as medium.
+
+With no subcommand, shows the plan for the week.
+
+Examples:
+  juggle week                       # Show this week's plan
+  juggle week candidates            # Balls that could be planned
+  juggle week add a1b2 c3d4         # Plan balls for this week
+  juggle week capacity 12           # Set weekly capacity to 12 points
+  juggle week session               # Create the week's agent session
+  juggle week review --week 2026-W41`,
+	Args: cobra.NoArgs,
+	RunE: runWeekShow,
+}
+
+var weekCandidatesCmd = &cobra.Command{
+	Use:   "candidates",
+	Short: "List unfinished balls not yet planned for the week",
+	Args:  cobra.NoArgs,
+	RunE:  runWeekCandidates,
+}
+
+var weekAddCmd = &cobra.Command{
+	Use:   "add <ball-id> [<ball-id>...]",
+	Short: "Plan balls for the week",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runWeekAdd,
+}
+
+var weekRmCmd = &cobra.Command{
+	Use:   "rm <ball-id> [<ball-id>...]",
+	Short: "Remove balls from the week's plan",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runWeekRm,
+}
+
+var weekCapacityCmd = &cobra.Command{
+	Use:   "capacity [points]",
+	Short: "Show or set the project's weekly capacity in points",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runWeekCapacity,
+}
+
+var weekSessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Create the agent session for the week's plan",
+	Args:  cobra.NoArgs,
+	RunE:  runWeekSession,
+}
+
+var weekReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Report planned vs. done for the week",
+	Args:  cobra.NoArgs,
+	RunE:  runWeekReview,
+}
+
+func init() {
+	weekCmd.PersistentFlags().StringVar(&weekFlag, "week", "", "Week to plan or review, as YYYY-Www (default: current week)")
+
+	weekCmd.AddCommand(weekCandidatesCmd)
+	weekCmd.AddCommand(weekAddCmd)
+	weekCmd.AddCommand(weekRmCmd)
+	weekCmd.AddCommand(weekCapacityCmd)
+	weekCmd.AddCommand(weekSessionCmd)
+	weekCmd.AddCommand(weekReviewCmd)
+	rootCmd.AddCommand(weekCmd)
+}
+
+// resolveWeek returns the normalized week ID from --week, defaulting to the current week
+func resolveWeek() (string, error) {
+	if weekFlag == "" {
+		return session.WeekID(time.Now()), nil
+	}
+	start, err := session.ParseWeekID(weekFlag)
+	if err != nil {
+		return "", validationErrorf("%v", err)
+	}
+	return session.WeekID(start), nil
+}
+
+// loadWeekProject returns the current project's store and its active balls
+func loadWeekProject() (string, *session.Store, []*session.Ball, error) {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	store, err := NewStoreForCommand(cwd)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to load balls: %w", err)
+	}
+	return cwd, store, balls, nil
+}
+
+// weekPoints totals the points of balls, also returning how many are unsized
+func weekPoints(balls []*session.Ball) (points, unsized int) {
+	for _, ball := range balls {
+		points += session.BallPoints(ball)
+		if ball.ModelSize == session.ModelSizeBlank {
+			unsized++
+		}
+	}
+	return points, unsized
+}
+
+// otherTags returns a ball's tags other than week tags, for showing which sessions it belongs to
+func otherTags(ball *session.Ball) string {
+	var tags []string
+	for _, tag := range ball.Tags {
+		if !session.IsWeekTag(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return strings.Join(tags, ",")
+}
+
+// printWeekBall prints a ball as one line of a week listing
+func printWeekBall(ball *session.Ball) {
+	line := fmt.Sprintf("  %s %s %-11s %dpt  %s",
+		padRight(ball.ShortID(), 8), padRight(string(ball.Priority), 7), ball.State, session.BallPoints(ball), ball.Title)
+	if tags := otherTags(ball); tags != "" {
+		line += "  [" + tags + "]"
+	}
+	fmt.Println(line)
+}
+
+func runWeekShow(cmd *cobra.Command, args []string) error {
+	weekID, err := resolveWeek()
+	if err != nil {
+		return err
+	}
+	cwd, _, balls, err := loadWeekProject()
+	if err != nil {
+		return err
+	}
+	capacity, err := session.GetProjectWeekCapacity(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to load week capacity: %w", err)
+	}
+
+	planned := session.WeekBalls(balls, weekID)
+	points, unsized := weekPoints(planned)
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(map[string]any{
+			"week":           weekID,
+			"tag":            session.WeekTag(weekID),
+			"capacity":       capacity,
+			"planned_points": points,
+			"balls":          planned,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	start, _ := session.ParseWeekID(weekID)
+	fmt.Printf("Week %s (%s – %s)\n\n", weekID, start.Format("Jan 2"), start.AddDate(0, 0, 6).Format("Jan 2"))
+	if len(planned) == 0 {
+		fmt.Println("No balls planned. Pick some with: juggle week candidates")
+	}
+	for _, ball := range planned {
+		printWeekBall(ball)
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("Planned: %d points in %d ball(s)", points, len(planned))
+	if unsized > 0 {
+		summary += fmt.Sprintf(" (%d unsized, counted as medium)", unsized)
+	}
+	fmt.Println(summary)
+	switch {
+	case capacity == 0:
+		fmt.Println("Capacity: not set (juggle week capacity <points>)")
+	case points > capacity:
+		fmt.Printf("Capacity: %d points — over by %d\n", capacity, points-capacity)
+	default:
+		fmt.Printf("Capacity: %d points — %d left\n", capacity, capacity-points)
+	}
+	return nil
+}
+
+func runWeekCandidates(cmd *cobra.Command, args []string) error {
+	weekID, err := resolveWeek()
+	if err != nil {
+		return err
+	}
+	_, _, balls, err := loadWeekProject()
+	if err != nil {
+		return err
+	}
+
+	tag := session.WeekTag(weekID)
+	candidates := make([]*session.Ball, 0)
+	for _, ball := range balls {
+		if ball.State != session.StateComplete && ball.State != session.StateResearched && !ballHasTag(ball, tag) {
+			candidates = append(candidates, ball)
+		}
+	}
+	session.SortBallsByPriority(candidates)
+
+	if len(candidates) == 0 {
+		fmt.Printf("No candidates: every unfinished ball is planned for %s\n", weekID)
+		return nil
+	}
+	fmt.Printf("Candidates for %s:\n\n", weekID)
+	for _, ball := range candidates {
+		printWeekBall(ball)
+	}
+	fmt.Printf("\nPlan with: juggle week add <ball-id>...\n")
+	return nil
+}
+
+func runWeekAdd(cmd *cobra.Command, args []string) error {
+	return updateWeekPlan(args, true)
+}
+
+func runWeekRm(cmd *cobra.Command, args []string) error {
+	return updateWeekPlan(args, false)
+}
+
+// updateWeekPlan adds balls to or removes them from the week's plan
+func updateWeekPlan(ballIDs []string, add bool) error {
+	weekID, err := resolveWeek()
+	if err != nil {
+		return err
+	}
+	tag := session.WeekTag(weekID)
+
+	for _, id := range ballIDs {
+		ball, store, err := findBallByID(id)
+		if err != nil {
+			return err
+		}
+		if add {
+			if ballHasTag(ball, tag) {
+				fmt.Printf("Ball %s is already planned for %s\n", ball.ShortID(), weekID)
+				continue
+			}
+			ball.AddTag(tag)
+		} else if !ball.RemoveTag(tag) {
+			fmt.Printf("Ball %s is not planned for %s\n", ball.ShortID(), weekID)
+			continue
+		}
+		if err := store.UpdateBall(ball); err != nil {
+			return fmt.Errorf("failed to update ball: %w", err)
+		}
+		if add {
+			fmt.Printf("✓ Planned %s for %s (%dpt)\n", ball.ShortID(), weekID, session.BallPoints(ball))
+		} else {
+			fmt.Printf("✓ Removed %s from %s\n", ball.ShortID(), weekID)
+		}
+	}
+	return nil
+}
+
+func runWeekCapacity(cmd *cobra.Command, args []string) error {
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	if len(args) == 0 {
+		capacity, err := session.GetProjectWeekCapacity(cwd)
+		if err != nil {
+			return fmt.Errorf("failed to load week capacity: %w", err)
+		}
+		if capacity == 0 {
+			fmt.Println("Week capacity: not set")
+		} else {
+			fmt.Printf("Week capacity: %d points\n", capacity)
+		}
+		return nil
+	}
+
+	points, err := strconv.Atoi(args[0])
+	if err != nil || points < 0 {
+		return validationErrorf("invalid capacity: %s (must be a non-negative number of points)", args[0])
+	}
+	if err := session.UpdateProjectWeekCapacity(cwd, points); err != nil {
+		return fmt.Errorf("failed to save week capacity: %w", err)
+	}
+	fmt.Printf("Set week capacity: %d points\n", points)
+	return nil
+}
+
+func runWeekSession(cmd *cobra.Command, args []string) error {
+	weekID, err := resolveWeek()
+	if err != nil {
+		return err
+	}
+	cwd, err := GetWorkingDir()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	sessionStore, err := session.NewSessionStoreWithConfig(cwd, GetStoreConfig())
+	if err != nil {
+		return fmt.Errorf("failed to initialize session store: %w", err)
+	}
+
+	sess, created, err := sessionStore.EnsureWeekSession(weekID)
+	if err != nil {
+		return fmt.Errorf("failed to create week session: %w", err)
+	}
+	if created {
+		fmt.Printf("✓ Created session %s\n", sess.ID)
+	} else {
+		fmt.Printf("Session %s already exists\n", sess.ID)
+	}
+	fmt.Printf("Run the week's plan with: juggle agent run %s\n", sess.ID)
+	return nil
+}
+
+func runWeekReview(cmd *cobra.Command, args []string) error {
+	weekID, err := resolveWeek()
+	if err != nil {
+		return err
+	}
+	_, store, _, err := loadWeekProject()
+	if err != nil {
+		return err
+	}
+	balls, err := store.ListBalls(session.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+
+	review, err := session.ReviewWeek(balls, weekID)
+	if err != nil {
+		return validationErrorf("%v", err)
+	}
+
+	if GlobalOpts.JSONOutput {
+		data, err := json.MarshalIndent(review, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Week %s review (%s – %s)\n\n", review.Week, review.Start.Format("Jan 2"), review.End.AddDate(0, 0, -1).Format("Jan 2"))
+	percent := 0
+	if review.PlannedPoints > 0 {
+		percent = review.DonePoints * 100 / review.PlannedPoints
+	}
+	fmt.Printf("Planned: %d ball(s), %d points\n", len(review.Planned), review.PlannedPoints)
+	fmt.Printf("Done:    %d ball(s), %d points (%d%%)\n", len(review.Done), review.DonePoints, percent)
+
+	sections := []struct {
+		title string
+		balls []*session.Ball
+	}{
+		{"Done", review.Done},
+		{"Not done", review.NotDone},
+		{"Done but not planned", review.Unplanned},
+	}
+	for _, section := range sections {
+		if len(section.balls) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", section.title)
+		for _, ball := range section.balls {
+			printWeekBall(ball)
+		}
+	}
+	return nil
+}
@@ -546,7 +546,7 @@ func runWorktreeRunDeleteAlias(mainDir, name string) error {
 	}
 
 	if !projectConfig.DeleteRunAlias(name) {
-		return fmt.Errorf("alias %q not found", name)
+		return notFoundErrorf("alias %q not found", name)
 	}
 
 	if err := session.SaveProjectConfig(mainDir, projectConfig); err != nil {
@@ -859,7 +859,7 @@ func runWorktreeJump(cmd *cobra.Command, args []string) error {
 	// Try to parse as number first
 	if num, err := strconv.Atoi(target); err == nil {
 		if num < 1 || num > len(workspaces) {
-			return fmt.Errorf("invalid workspace number: %d (valid: 1-%d)", num, len(workspaces))
+			return validationErrorf("invalid workspace number: %d (valid: 1-%d)", num, len(workspaces))
 		}
 		targetPath = workspaces[num-1]
 	} else {
@@ -896,7 +896,7 @@ func runWorktreeJump(cmd *cobra.Command, args []string) error {
 					for i, m := range matches {
 						names[i] = filepath.Base(m)
 					}
-					return fmt.Errorf("ambiguous name %q matches: %s", target, strings.Join(names, ", "))
+					return validationErrorf("ambiguous name %q matches: %s", target, strings.Join(names, ", "))
 				}
 			}
 		}
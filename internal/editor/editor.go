@@ -0,0 +1,86 @@
+// Package editor opens files in the user's external editor.
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Command returns the command that opens path in the user's editor and
+// waits for it to close. $VISUAL is preferred over $EDITOR; either may
+// carry arguments and quoted paths with spaces, e.g.
+// "C:\Program Files\Notepad++\notepad++.exe" -multiInst. With neither set,
+// Windows opens the file with the program associated with its extension
+// (Notepad when there is none) and other systems use vi.
+func Command(path string) *exec.Cmd {
+	if parts := SplitCommand(configured()); len(parts) > 0 {
+		return exec.Command(parts[0], append(parts[1:], path)...)
+	}
+	if runtime.GOOS == "windows" {
+		if hasAssociation(filepath.Ext(path)) {
+			// start /wait blocks until the associated program exits; the
+			// empty argument is the window title start expects first
+			return exec.Command("cmd", "/c", "start", "", "/wait", path)
+		}
+		return exec.Command("notepad", path)
+	}
+	return exec.Command("vi", path)
+}
+
+// configured returns the editor set in the environment, if any
+func configured() string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(name)); editor != "" {
+			return editor
+		}
+	}
+	return ""
+}
+
+// hasAssociation reports whether Windows has a program associated with
+// files of the given extension
+func hasAssociation(ext string) bool {
+	if ext == "" {
+		return false
+	}
+	return exec.Command("cmd", "/c", "assoc", ext).Run() == nil
+}
+
+// SplitCommand splits an editor command line into the program and its
+// arguments. Double or single quotes group words with spaces. Backslashes
+// are kept as they are, so Windows paths need no escaping.
+func SplitCommand(command string) []string {
+	var parts []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				parts = append(parts, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		parts = append(parts, word.String())
+	}
+	return parts
+}
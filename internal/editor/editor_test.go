@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"vim", []string{"vim"}},
+		{"code --wait", []string{"code", "--wait"}},
+		{`"C:\Program Files\Notepad++\notepad++.exe" -multiInst -nosession`,
+			[]string{`C:\Program Files\Notepad++\notepad++.exe`, "-multiInst", "-nosession"}},
+		{`C:\tools\hx.exe`, []string{`C:\tools\hx.exe`}},
+		{"'/opt/my editor/bin/ed'  -w", []string{"/opt/my editor/bin/ed", "-w"}},
+		{`subl -n ""`, []string{"subl", "-n", ""}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitCommand(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", `"/opt/my editor/ed" -w`)
+	cmd := Command("notes.md")
+	if want := []string{"/opt/my editor/ed", "-w", "notes.md"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Expected %q, got %q", want, cmd.Args)
+	}
+
+	t.Setenv("VISUAL", "nano")
+	if cmd := Command("notes.md"); cmd.Args[0] != "nano" {
+		t.Errorf("Expected $VISUAL preferred, got %q", cmd.Args)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	cmd = Command("notes.md")
+	if runtime.GOOS == "windows" {
+		// The file's associated program through start /wait, or Notepad
+		if cmd.Args[0] != "cmd" && cmd.Args[0] != "notepad" {
+			t.Errorf("Expected the association or Notepad on Windows, got %q", cmd.Args)
+		}
+		return
+	}
+	if want := []string{"vi", "notes.md"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Expected vi, got %q", cmd.Args)
+	}
+}
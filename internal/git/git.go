@@ -0,0 +1,138 @@
+// Package git reads branches and commits from git repositories, for linking
+// balls to the work done on them.
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Field and record separators for git log output; they can't appear in
+// commit messages
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Commit is a commit read from git log.
+type Commit struct {
+	Hash    string    // Full commit hash
+	Subject string    // First line of the message
+	Message string    // Full message, subject included
+	Date    time.Time // Author date
+}
+
+// ShortHash returns the first 7 characters of the commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// run runs git in dir and returns its trimmed output.
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s: %w", args[0], strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsRepo returns true if dir is inside a git working tree.
+func IsRepo(dir string) bool {
+	output, err := run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && output == "true"
+}
+
+// CurrentBranch returns the name of the checked out branch. Returns an
+// error if HEAD is detached.
+func CurrentBranch(dir string) (string, error) {
+	branch, err := run(dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("no branch is checked out (detached HEAD?): %w", err)
+	}
+	return branch, nil
+}
+
+// BranchExists returns true if dir's repository has a local branch with the given name.
+func BranchExists(dir, branch string) bool {
+	_, err := run(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// Log returns the commits reachable from any ref, newest first. A non-zero
+// since skips commits authored before it.
+func Log(dir string, since time.Time) ([]Commit, error) {
+	args := []string{"log", "--all", "--format=%H" + fieldSep + "%aI" + fieldSep + "%s" + fieldSep + "%B" + recordSep}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	output, err := run(dir, args...)
+	if err != nil {
+		// A repository without commits has nothing to log
+		if strings.Contains(err.Error(), "does not have any commits") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(output, recordSep) {
+		fields := strings.SplitN(strings.TrimSpace(record), fieldSep, 4)
+		if len(fields) != 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[1])
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Date:    date,
+			Subject: fields[2],
+			Message: strings.TrimSpace(fields[3]),
+		})
+	}
+	return commits, nil
+}
+
+// References returns true if message mentions id as a whole word, so
+// "juggle: proj-42" references proj-42 but not proj-4.
+func References(message, id string) bool {
+	if id == "" {
+		return false
+	}
+	for start := 0; ; {
+		i := strings.Index(message[start:], id)
+		if i < 0 {
+			return false
+		}
+		i += start
+		end := i + len(id)
+		if (i == 0 || !isIDChar(message[i-1])) && (end == len(message) || !isIDChar(message[end])) {
+			return true
+		}
+		start = i + 1
+	}
+}
+
+// isIDChar reports whether c can be part of a ball ID
+func isIDChar(c byte) bool {
+	return c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// FindReferences returns, for each ID, the commits whose messages reference
+// it, in the order given. IDs without commits are left out.
+func FindReferences(commits []Commit, ids []string) map[string][]Commit {
+	refs := make(map[string][]Commit)
+	for _, commit := range commits {
+		for _, id := range ids {
+			if References(commit.Message, id) {
+				refs[id] = append(refs[id], commit)
+			}
+		}
+	}
+	return refs
+}
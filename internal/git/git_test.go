@@ -0,0 +1,88 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %s: %v", args, output, err)
+	}
+}
+
+func commitFile(t *testing.T, dir, name, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(message+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", message)
+}
+
+func TestLogAndFindReferences(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	if IsRepo(dir) {
+		t.Fatal("expected a plain directory not to be a repo")
+	}
+	gitCmd(t, dir, "init", "-q", "-b", "main")
+	gitCmd(t, dir, "config", "user.email", "test@test.com")
+	gitCmd(t, dir, "config", "user.name", "Test User")
+	if !IsRepo(dir) {
+		t.Fatal("expected a repo after git init")
+	}
+	if commits, err := Log(dir, time.Time{}); err != nil || len(commits) != 0 {
+		t.Fatalf("expected no commits in an empty repo, got %d (%v)", len(commits), err)
+	}
+
+	commitFile(t, dir, "a.txt", "Add login page\n\njuggle: proj-42")
+	gitCmd(t, dir, "checkout", "-q", "-b", "feature/auth")
+	commitFile(t, dir, "b.txt", "Fix proj-4 typo")
+	commitFile(t, dir, "c.txt", "Wire up auth (proj-42, proj-7)")
+	gitCmd(t, dir, "checkout", "-q", "main")
+
+	branch, err := CurrentBranch(dir)
+	if err != nil || branch != "main" {
+		t.Errorf("expected main, got %q (%v)", branch, err)
+	}
+	if !BranchExists(dir, "feature/auth") || BranchExists(dir, "no-such-branch") {
+		t.Error("unexpected BranchExists result")
+	}
+
+	// Commits on other branches are included
+	commits, err := Log(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("expected 3 commits, got %d", len(commits))
+	}
+
+	refs := FindReferences(commits, []string{"proj-42", "proj-4", "proj-7", "proj-9"})
+	if len(refs["proj-42"]) != 2 || refs["proj-42"][0].Subject != "Wire up auth (proj-42, proj-7)" {
+		t.Errorf("expected 2 commits for proj-42, newest first, got %+v", refs["proj-42"])
+	}
+	if len(refs["proj-4"]) != 1 || len(refs["proj-7"]) != 1 {
+		t.Errorf("expected whole-word matches only, got proj-4: %d, proj-7: %d", len(refs["proj-4"]), len(refs["proj-7"]))
+	}
+	if _, ok := refs["proj-9"]; ok {
+		t.Error("expected no entry for an unreferenced ID")
+	}
+	if commits[0].ShortHash() != commits[0].Hash[:7] || commits[0].Date.IsZero() {
+		t.Errorf("expected a short hash and date, got %+v", commits[0])
+	}
+
+	gitCmd(t, dir, "checkout", "-q", "--detach")
+	if _, err := CurrentBranch(dir); err == nil {
+		t.Error("expected an error for a detached HEAD")
+	}
+}
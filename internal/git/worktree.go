@@ -0,0 +1,148 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Head returns the hash of the commit checked out in dir.
+func Head(dir string) (string, error) {
+	return run(dir, "rev-parse", "HEAD")
+}
+
+// AddWorktree checks out branch in a new worktree at path, creating the
+// branch at base if it doesn't exist yet.
+func AddWorktree(dir, path, branch, base string) error {
+	if BranchExists(dir, branch) {
+		_, err := run(dir, "worktree", "add", path, branch)
+		return err
+	}
+	_, err := run(dir, "worktree", "add", "-b", branch, path, base)
+	return err
+}
+
+// RemoveWorktree removes the worktree at path, along with any changes in it.
+func RemoveWorktree(dir, path string) error {
+	_, err := run(dir, "worktree", "remove", "--force", path)
+	return err
+}
+
+// PruneWorktrees forgets worktrees whose directories were deleted without
+// git worktree remove, so their paths and branches can be used again.
+func PruneWorktrees(dir string) error {
+	_, err := run(dir, "worktree", "prune")
+	return err
+}
+
+// DeleteBranch deletes a local branch, merged or not.
+func DeleteBranch(dir, branch string) error {
+	_, err := run(dir, "branch", "-D", branch)
+	return err
+}
+
+// MergeBase returns the best common ancestor of two commits.
+func MergeBase(dir, a, b string) (string, error) {
+	return run(dir, "merge-base", a, b)
+}
+
+// CountCommits returns the number of commits reachable from to but not
+// from from.
+func CountCommits(dir, from, to string) (int, error) {
+	output, err := run(dir, "rev-list", "--count", from+".."+to)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(output)
+}
+
+// Diff returns the changes in dir's working tree since base, committed or
+// not, leaving out paths matched by the exclude pathspecs. With stat, it
+// returns the diffstat instead.
+func Diff(dir, base string, stat bool, exclude ...string) (string, error) {
+	args := []string{"diff"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	args = append(args, base, "--", ".")
+	for _, path := range exclude {
+		args = append(args, ":(exclude)"+path)
+	}
+	return run(dir, args...)
+}
+
+// UntrackedFiles returns the files in dir's working tree git doesn't track
+// and doesn't ignore, leaving out paths matched by the exclude pathspecs.
+func UntrackedFiles(dir string, exclude ...string) ([]string, error) {
+	args := []string{"ls-files", "--others", "--exclude-standard", "--", "."}
+	for _, path := range exclude {
+		args = append(args, ":(exclude)"+path)
+	}
+	output, err := run(dir, args...)
+	if err != nil || output == "" {
+		return nil, err
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// CommitAll commits every change in dir's working tree, leaving out paths
+// matched by the exclude pathspecs. It reports whether there was anything
+// to commit.
+func CommitAll(dir, message string, exclude ...string) (bool, error) {
+	args := []string{"add", "-A", "--", "."}
+	for _, path := range exclude {
+		args = append(args, ":(exclude)"+path)
+	}
+	if _, err := run(dir, args...); err != nil {
+		return false, err
+	}
+	if _, err := run(dir, "diff", "--cached", "--quiet"); err == nil {
+		return false, nil
+	}
+	if _, err := run(dir, "commit", "-q", "-m", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Merge merges branch into the branch checked out in dir with a merge
+// commit. On a conflict the merge is aborted, leaving dir as it was.
+func Merge(dir, branch, message string) error {
+	if _, err := run(dir, "merge", "--no-ff", "-m", message, branch); err != nil {
+		_, _ = run(dir, "merge", "--abort")
+		return err
+	}
+	return nil
+}
+
+// Exclude adds a pattern to the repository's info/exclude, which all its
+// worktrees share, unless it's there already.
+func Exclude(dir, pattern string) error {
+	commonDir, err := run(dir, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(commonDir, "info", "exclude")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		data = append(data, '\n')
+	}
+	data = append(data, pattern+"\n"...)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorktreeMergeAndConflict(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q", "-b", "main")
+	gitCmd(t, dir, "config", "user.email", "test@test.com")
+	gitCmd(t, dir, "config", "user.name", "Test User")
+	commitFile(t, dir, "a.txt", "initial")
+	base, err := Head(dir)
+	if err != nil {
+		t.Fatalf("Head failed: %v", err)
+	}
+
+	// Excluded twice, listed once
+	for range 2 {
+		if err := Exclude(dir, "/.juggle/link"); err != nil {
+			t.Fatalf("Exclude failed: %v", err)
+		}
+	}
+	exclude, _ := os.ReadFile(filepath.Join(dir, ".git", "info", "exclude"))
+	if strings.Count(string(exclude), "/.juggle/link") != 1 {
+		t.Errorf("expected the pattern once, got:\n%s", exclude)
+	}
+
+	worktree := filepath.Join(t.TempDir(), "wt")
+	if err := AddWorktree(dir, worktree, "agent", base); err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "a.txt"), []byte("agent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktree, "b.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stat, err := Diff(worktree, base, true); err != nil || !strings.Contains(stat, "a.txt") {
+		t.Errorf("expected a.txt in the diffstat, got %q (%v)", stat, err)
+	}
+	if untracked, err := UntrackedFiles(worktree); err != nil || len(untracked) != 1 || untracked[0] != "b.txt" {
+		t.Errorf("expected b.txt untracked, got %v (%v)", untracked, err)
+	}
+	if committed, err := CommitAll(worktree, "Agent work"); err != nil || !committed {
+		t.Fatalf("expected the changes to be committed, got %v (%v)", committed, err)
+	}
+	if committed, err := CommitAll(worktree, "Nothing"); err != nil || committed {
+		t.Errorf("expected nothing left to commit, got %v (%v)", committed, err)
+	}
+	if count, err := CountCommits(dir, "main", "agent"); err != nil || count != 1 {
+		t.Errorf("expected 1 commit ahead, got %d (%v)", count, err)
+	}
+
+	// A conflicting change on main aborts the merge
+	commitFile(t, dir, "a.txt", "main edit")
+	if err := Merge(dir, "agent", "Merge agent"); err == nil {
+		t.Fatal("expected the merge to conflict")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git", "MERGE_HEAD")); err == nil {
+		t.Error("expected the conflicting merge to be aborted")
+	}
+
+	if err := RemoveWorktree(dir, worktree); err != nil {
+		t.Fatalf("RemoveWorktree failed: %v", err)
+	}
+	if err := DeleteBranch(dir, "agent"); err != nil || BranchExists(dir, "agent") {
+		t.Errorf("expected the branch to be deleted (%v)", err)
+	}
+}
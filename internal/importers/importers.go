@@ -0,0 +1,155 @@
+// Package importers reads task dumps exported from other trackers (Todoist,
+// Trello and Jira) and maps them onto juggle's model: the project or board a
+// task lives in becomes a session, labels become tags, and the tracker's
+// priority and status become the ball's priority and state.
+//
+// Parsing is kept apart from writing so a caller can show the mapping (a
+// dry run) before any ball is created.
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// Source is a tracker whose JSON export can be imported
+type Source string
+
+const (
+	SourceTodoist Source = "todoist"
+	SourceTrello  Source = "trello"
+	SourceJira    Source = "jira"
+)
+
+// Sources lists the supported sources, for help and error messages
+var Sources = []Source{SourceTodoist, SourceTrello, SourceJira}
+
+// ParseSource returns the source with the given name (case-insensitive)
+func ParseSource(name string) (Source, error) {
+	for _, source := range Sources {
+		if strings.EqualFold(name, string(source)) {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unknown source: %s (must be todoist, trello, or jira)", name)
+}
+
+// Item is one external task, mapped onto juggle's fields
+type Item struct {
+	Ref                string            // Tracker reference, kept as a tag (e.g. "todoist#123", "PROJ-7")
+	Title              string            // Ball title
+	Context            string            // Task description
+	Session            string            // Session ID, derived from SessionName
+	SessionName        string            // Project or board name in the tracker
+	Tags               []string          // Labels and other groupings
+	Priority           session.Priority  // Mapped priority (medium when the tracker has none)
+	State              session.BallState // Mapped state
+	AcceptanceCriteria []string          // Subtasks or checklist items
+}
+
+// Parse reads a JSON export from source into items, in the export's order
+func Parse(source Source, data []byte) ([]Item, error) {
+	switch source {
+	case SourceTodoist:
+		return parseTodoist(data)
+	case SourceTrello:
+		return parseTrello(data)
+	case SourceJira:
+		return parseJira(data)
+	}
+	return nil, fmt.Errorf("unknown source: %s", source)
+}
+
+// SessionID turns a project or board name into a session ID, e.g.
+// "Website Redesign" → "website-redesign"
+func SessionID(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// priorityFromName maps a priority name used by a tracker, or a label like
+// "priority: high" or "High Priority", onto a juggle priority
+func priorityFromName(name string) (session.Priority, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "priority"), "priority")
+	name = strings.Trim(name, ":-/ ")
+	switch name {
+	case "highest", "critical", "blocker", "urgent", "p0", "p1":
+		return session.PriorityUrgent, true
+	case "high", "major", "p2":
+		return session.PriorityHigh, true
+	case "medium", "normal", "p3":
+		return session.PriorityMedium, true
+	case "low", "lowest", "minor", "trivial", "p4":
+		return session.PriorityLow, true
+	}
+	return "", false
+}
+
+// stateFromName maps a column or status name onto a juggle state
+func stateFromName(name string) (session.BallState, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "done", "complete", "completed", "closed", "resolved", "shipped", "finished":
+		return session.StateComplete, true
+	case "doing", "in progress", "in-progress", "in review", "review", "wip", "started":
+		return session.StateInProgress, true
+	case "blocked", "on hold", "waiting":
+		return session.StateBlocked, true
+	case "to do", "todo", "backlog", "open", "new", "selected for development":
+		return session.StatePending, true
+	}
+	return "", false
+}
+
+// addTag adds a label as a tag unless it's empty or already there
+func (item *Item) addTag(label string) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return
+	}
+	for _, tag := range item.Tags {
+		if tag == label {
+			return
+		}
+	}
+	item.Tags = append(item.Tags, label)
+}
+
+// setSession sets the item's session from a project or board name
+func (item *Item) setSession(name string) {
+	item.SessionName = strings.TrimSpace(name)
+	item.Session = SessionID(name)
+}
+
+// flexID is an ID that exports write as either a string or a number
+type flexID string
+
+func (id *flexID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = flexID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid ID %s", data)
+	}
+	*id = flexID(n.String())
+	return nil
+}
@@ -0,0 +1,145 @@
+package importers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestParseTodoist(t *testing.T) {
+	data := `{
+  "projects": [{"id": "100", "name": "Website Redesign"}],
+  "sections": [{"id": "7", "name": "Design"}],
+  "items": [
+    {"id": "1", "content": "Pick a palette", "description": "Warm tones", "project_id": "100", "section_id": "7", "priority": 4, "labels": ["ui"]},
+    {"id": "2", "content": "Shortlist three", "project_id": "100", "parent_id": "1", "priority": 1},
+    {"id": 3, "content": "Write copy", "project_id": "100", "priority": 1, "checked": true}
+  ]
+}`
+	items, err := Parse(SourceTodoist, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected the subtask folded into its parent, got %+v", items)
+	}
+	palette := items[0]
+	if palette.Ref != "todoist#1" || palette.Title != "Pick a palette" || palette.Context != "Warm tones" ||
+		palette.Session != "website-redesign" || palette.SessionName != "Website Redesign" ||
+		strings.Join(palette.Tags, ",") != "Design,ui" || palette.Priority != session.PriorityUrgent ||
+		palette.State != session.StatePending || strings.Join(palette.AcceptanceCriteria, "|") != "Shortlist three" {
+		t.Errorf("Unexpected mapping %+v", palette)
+	}
+	if copy := items[1]; copy.Ref != "todoist#3" || copy.State != session.StateComplete || copy.Priority != session.PriorityMedium {
+		t.Errorf("Unexpected mapping %+v", copy)
+	}
+
+	// The REST task list has no project names
+	items, err = Parse(SourceTodoist, []byte(`[{"id": "9", "content": "Call back", "project_id": "100", "is_completed": true}]`))
+	if err != nil || len(items) != 1 || items[0].Session != "" || items[0].State != session.StateComplete {
+		t.Errorf("Unexpected REST mapping %+v (%v)", items, err)
+	}
+}
+
+func TestParseTrello(t *testing.T) {
+	data := `{
+  "name": "Mobile App",
+  "lists": [
+    {"id": "l1", "name": "Backlog"},
+    {"id": "l2", "name": "Doing"},
+    {"id": "l3", "name": "Ideas"},
+    {"id": "l4", "name": "Old", "closed": true}
+  ],
+  "cards": [
+    {"id": "c1", "shortLink": "aB3", "name": "Push notifications", "desc": "iOS first", "idList": "l2",
+     "labels": [{"name": "High Priority", "color": "red"}, {"name": "ios", "color": "blue"}]},
+    {"id": "c2", "name": "Dark mode", "idList": "l3", "labels": [{"name": "", "color": "green"}]},
+    {"id": "c3", "name": "Archived card", "idList": "l1", "closed": true},
+    {"id": "c4", "name": "In an archived list", "idList": "l4"},
+    {"id": "c5", "name": "Ship beta", "idList": "l1", "dueComplete": true}
+  ],
+  "checklists": [
+    {"idCard": "c1", "checkItems": [{"name": "Android", "pos": 2}, {"name": "Register APNs", "pos": 1}]}
+  ]
+}`
+	items, err := Parse(SourceTrello, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("Expected archived cards left out, got %+v", items)
+	}
+	push := items[0]
+	if push.Ref != "trello#aB3" || push.Session != "mobile-app" || push.State != session.StateInProgress ||
+		push.Priority != session.PriorityHigh || strings.Join(push.Tags, ",") != "ios" ||
+		strings.Join(push.AcceptanceCriteria, "|") != "Register APNs|Android" {
+		t.Errorf("Unexpected mapping %+v", push)
+	}
+	if dark := items[1]; dark.Ref != "trello#c2" || strings.Join(dark.Tags, ",") != "Ideas" || dark.State != session.StatePending {
+		t.Errorf("Expected a non-state list as a tag, got %+v", dark)
+	}
+	if beta := items[2]; beta.State != session.StateComplete {
+		t.Errorf("Expected a completed due date to complete the card, got %+v", beta)
+	}
+
+	if _, err := Parse(SourceTrello, []byte(`{"name": "Not a board"}`)); err == nil {
+		t.Error("Expected an error for JSON without cards")
+	}
+}
+
+func TestParseJira(t *testing.T) {
+	data := `{"issues": [
+  {"key": "PAY-1", "fields": {
+    "summary": "Refunds", "labels": ["billing"], "components": [{"name": "api"}],
+    "description": {"type": "doc", "content": [
+      {"type": "paragraph", "content": [{"type": "text", "text": "Support partial refunds."}]},
+      {"type": "bulletList", "content": [{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Stripe only"}]}]}]}
+    ]},
+    "status": {"name": "In Development", "statusCategory": {"key": "indeterminate"}},
+    "priority": {"name": "Highest"},
+    "project": {"key": "PAY", "name": "Payments"}}},
+  {"key": "PAY-2", "fields": {"summary": "Add refund endpoint", "parent": {"key": "PAY-1"},
+    "status": {"name": "Done", "statusCategory": {"key": "done"}}, "project": {"key": "PAY", "name": "Payments"}}},
+  {"key": "PAY-3", "fields": {"summary": "Vendor contract", "description": "Waiting on legal",
+    "status": {"name": "Blocked", "statusCategory": {"key": "indeterminate"}},
+    "priority": {"name": "Low"}, "project": {"key": "PAY"}}}
+]}`
+	items, err := Parse(SourceJira, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected the sub-task folded into its parent, got %+v", items)
+	}
+	refunds := items[0]
+	if refunds.Ref != "PAY-1" || refunds.Session != "payments" || refunds.State != session.StateInProgress ||
+		refunds.Priority != session.PriorityUrgent || strings.Join(refunds.Tags, ",") != "billing,api" ||
+		refunds.Context != "Support partial refunds.\n- Stripe only" ||
+		strings.Join(refunds.AcceptanceCriteria, "|") != "Add refund endpoint" {
+		t.Errorf("Unexpected mapping %+v", refunds)
+	}
+	if vendor := items[1]; vendor.State != session.StateBlocked || vendor.Priority != session.PriorityLow ||
+		vendor.Context != "Waiting on legal" || vendor.Session != "pay" {
+		t.Errorf("Unexpected mapping %+v", vendor)
+	}
+}
+
+func TestParseSourceAndSessionID(t *testing.T) {
+	if source, err := ParseSource("Trello"); err != nil || source != SourceTrello {
+		t.Errorf("Expected trello, got %q (%v)", source, err)
+	}
+	if _, err := ParseSource("asana"); err == nil {
+		t.Error("Expected an error for an unknown source")
+	}
+	for name, want := range map[string]string{
+		"Website Redesign": "website-redesign",
+		"  Q3 / Launch!! ": "q3-launch",
+		"Inbox":            "inbox",
+		"---":              "",
+	} {
+		if got := SessionID(name); got != want {
+			t.Errorf("SessionID(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// jiraExport is the response of Jira's issue search API. A bare array of
+// issues is also accepted.
+type jiraExport struct {
+	Issues []jiraIssue `json:"issues"`
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"` // Plain text (API v2) or a document (API v3)
+		Labels      []string        `json:"labels"`
+		Status      struct {
+			Name           string `json:"name"`
+			StatusCategory struct {
+				Key string `json:"key"` // new, indeterminate, or done
+			} `json:"statusCategory"`
+		} `json:"status"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		Project struct {
+			Key  string `json:"key"`
+			Name string `json:"name"`
+		} `json:"project"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Parent *struct {
+			Key string `json:"key"`
+		} `json:"parent"`
+	} `json:"fields"`
+}
+
+// adfNode is a node of an Atlassian Document Format description
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+// parseJira maps projects onto sessions, labels and components onto tags,
+// and sub-tasks onto their parent's acceptance criteria. The issue key is
+// kept as a tag.
+func parseJira(data []byte) ([]Item, error) {
+	var export jiraExport
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &export.Issues); err != nil {
+			return nil, fmt.Errorf("failed to parse Jira export: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Jira export: %w", err)
+	}
+
+	keys := make(map[string]bool, len(export.Issues))
+	for _, issue := range export.Issues {
+		keys[issue.Key] = true
+	}
+
+	items := make([]Item, 0, len(export.Issues))
+	index := make(map[string]int, len(export.Issues))
+	var subtasks []jiraIssue
+	for _, issue := range export.Issues {
+		fields := issue.Fields
+		if fields.Parent != nil && keys[fields.Parent.Key] {
+			subtasks = append(subtasks, issue)
+			continue
+		}
+		item := Item{
+			Ref:      issue.Key,
+			Title:    strings.TrimSpace(fields.Summary),
+			Context:  jiraDescription(fields.Description),
+			Priority: session.PriorityMedium,
+			State:    jiraState(issue),
+		}
+		name := fields.Project.Name
+		if name == "" {
+			name = fields.Project.Key
+		}
+		item.setSession(name)
+		if fields.Priority != nil {
+			if priority, ok := priorityFromName(fields.Priority.Name); ok {
+				item.Priority = priority
+			}
+		}
+		for _, label := range fields.Labels {
+			item.addTag(label)
+		}
+		for _, component := range fields.Components {
+			item.addTag(component.Name)
+		}
+		index[issue.Key] = len(items)
+		items = append(items, item)
+	}
+	for _, subtask := range subtasks {
+		if i, ok := index[subtask.Fields.Parent.Key]; ok {
+			items[i].AcceptanceCriteria = append(items[i].AcceptanceCriteria, strings.TrimSpace(subtask.Fields.Summary))
+		}
+	}
+	return items, nil
+}
+
+// jiraState maps an issue's status onto a state, by name when it names one
+// (e.g. "Blocked") and otherwise by its status category
+func jiraState(issue jiraIssue) session.BallState {
+	status := issue.Fields.Status
+	if state, ok := stateFromName(status.Name); ok {
+		return state
+	}
+	switch status.StatusCategory.Key {
+	case "done":
+		return session.StateComplete
+	case "indeterminate":
+		return session.StateInProgress
+	}
+	return session.StatePending
+}
+
+// jiraDescription returns an issue description as plain text
+func jiraDescription(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return strings.TrimSpace(text)
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	writeADF(&b, doc)
+	return strings.TrimSpace(b.String())
+}
+
+// writeADF writes a document node's text, one line per block
+func writeADF(b *strings.Builder, node adfNode) {
+	switch node.Type {
+	case "text":
+		b.WriteString(node.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "listItem":
+		b.WriteString("- ")
+	}
+	for _, child := range node.Content {
+		writeADF(b, child)
+	}
+	switch node.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote":
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+}
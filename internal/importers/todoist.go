@@ -0,0 +1,113 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// todoistExport is a Todoist sync API dump. The REST API's task list (a bare
+// array) is also accepted; it has no project names, so its tasks get no
+// session.
+type todoistExport struct {
+	Projects []todoistNamed `json:"projects"`
+	Sections []todoistNamed `json:"sections"`
+	Items    []todoistTask  `json:"items"`
+	Tasks    []todoistTask  `json:"tasks"`
+}
+
+type todoistNamed struct {
+	ID   flexID `json:"id"`
+	Name string `json:"name"`
+}
+
+type todoistTask struct {
+	ID          flexID   `json:"id"`
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	ProjectID   flexID   `json:"project_id"`
+	SectionID   flexID   `json:"section_id"`
+	ParentID    flexID   `json:"parent_id"`
+	Priority    int      `json:"priority"` // 4 is the UI's p1, 1 is no priority
+	Labels      []string `json:"labels"`
+	Checked     bool     `json:"checked"`      // Sync API
+	IsCompleted bool     `json:"is_completed"` // REST API
+}
+
+// parseTodoist maps projects onto sessions, sections and labels onto tags,
+// and subtasks onto their parent's acceptance criteria
+func parseTodoist(data []byte) ([]Item, error) {
+	var export todoistExport
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &export.Tasks); err != nil {
+			return nil, fmt.Errorf("failed to parse Todoist export: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Todoist export: %w", err)
+	}
+	tasks := append(export.Items, export.Tasks...)
+
+	projects := make(map[flexID]string, len(export.Projects))
+	for _, project := range export.Projects {
+		projects[project.ID] = project.Name
+	}
+	sections := make(map[flexID]string, len(export.Sections))
+	for _, section := range export.Sections {
+		sections[section.ID] = section.Name
+	}
+	parents := make(map[flexID]bool, len(tasks))
+	for _, task := range tasks {
+		if task.ParentID == "" {
+			parents[task.ID] = true
+		}
+	}
+
+	items := make([]Item, 0, len(tasks))
+	index := make(map[flexID]int, len(tasks))
+	for _, task := range tasks {
+		// Subtasks of an imported task become its acceptance criteria
+		if task.ParentID != "" && parents[task.ParentID] {
+			continue
+		}
+		item := Item{
+			Ref:      "todoist#" + string(task.ID),
+			Title:    strings.TrimSpace(task.Content),
+			Context:  strings.TrimSpace(task.Description),
+			Priority: todoistPriority(task.Priority),
+			State:    session.StatePending,
+		}
+		if name, ok := projects[task.ProjectID]; ok {
+			item.setSession(name)
+		}
+		item.addTag(sections[task.SectionID])
+		for _, label := range task.Labels {
+			item.addTag(label)
+		}
+		if task.Checked || task.IsCompleted {
+			item.State = session.StateComplete
+		}
+		index[task.ID] = len(items)
+		items = append(items, item)
+	}
+	for _, task := range tasks {
+		if i, ok := index[task.ParentID]; ok && parents[task.ParentID] {
+			items[i].AcceptanceCriteria = append(items[i].AcceptanceCriteria, strings.TrimSpace(task.Content))
+		}
+	}
+	return items, nil
+}
+
+// todoistPriority maps Todoist's API priority (4 = p1 in the UI) onto a
+// juggle priority. Tasks without a priority stay medium.
+func todoistPriority(priority int) session.Priority {
+	switch priority {
+	case 4:
+		return session.PriorityUrgent
+	case 3:
+		return session.PriorityHigh
+	}
+	return session.PriorityMedium
+}
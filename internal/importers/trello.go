@@ -0,0 +1,114 @@
+package importers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// trelloExport is a board exported with Trello's "Export as JSON"
+type trelloExport struct {
+	Name       string            `json:"name"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloList struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Closed bool   `json:"closed"`
+}
+
+type trelloCard struct {
+	ID          string `json:"id"`
+	ShortLink   string `json:"shortLink"`
+	Name        string `json:"name"`
+	Desc        string `json:"desc"`
+	IDList      string `json:"idList"`
+	Closed      bool   `json:"closed"`
+	DueComplete bool   `json:"dueComplete"`
+	Labels      []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+}
+
+type trelloChecklist struct {
+	IDCard     string `json:"idCard"`
+	CheckItems []struct {
+		Name string  `json:"name"`
+		Pos  float64 `json:"pos"`
+	} `json:"checkItems"`
+}
+
+// parseTrello maps the board onto a session and labels onto tags. A list
+// named like a state (Doing, Done, Blocked, ...) sets the card's state; other
+// lists become tags. Labels named like a priority set the priority, and
+// checklist items become acceptance criteria. Archived cards and cards in
+// archived lists are left out.
+func parseTrello(data []byte) ([]Item, error) {
+	var export trelloExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Trello export: %w", err)
+	}
+	if export.Cards == nil {
+		return nil, fmt.Errorf("failed to parse Trello export: no cards found (export the board as JSON)")
+	}
+
+	lists := make(map[string]trelloList, len(export.Lists))
+	for _, list := range export.Lists {
+		lists[list.ID] = list
+	}
+	checklists := make(map[string][]string)
+	for _, checklist := range export.Checklists {
+		// Exports don't keep check items in board order
+		sort.SliceStable(checklist.CheckItems, func(i, j int) bool {
+			return checklist.CheckItems[i].Pos < checklist.CheckItems[j].Pos
+		})
+		for _, checkItem := range checklist.CheckItems {
+			checklists[checklist.IDCard] = append(checklists[checklist.IDCard], strings.TrimSpace(checkItem.Name))
+		}
+	}
+
+	items := make([]Item, 0, len(export.Cards))
+	for _, card := range export.Cards {
+		list := lists[card.IDList]
+		if card.Closed || list.Closed {
+			continue
+		}
+		ref := card.ShortLink
+		if ref == "" {
+			ref = card.ID
+		}
+		item := Item{
+			Ref:                "trello#" + ref,
+			Title:              strings.TrimSpace(card.Name),
+			Context:            strings.TrimSpace(card.Desc),
+			Priority:           session.PriorityMedium,
+			State:              session.StatePending,
+			AcceptanceCriteria: checklists[card.ID],
+		}
+		item.setSession(export.Name)
+		if state, ok := stateFromName(list.Name); ok {
+			item.State = state
+		} else {
+			item.addTag(list.Name)
+		}
+		if card.DueComplete {
+			item.State = session.StateComplete
+		}
+		for _, label := range card.Labels {
+			if priority, ok := priorityFromName(label.Name); ok {
+				item.Priority = priority
+				continue
+			}
+			item.addTag(label.Name)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,172 @@
+// Package intake turns emails into proposed balls for a project's triage
+// inbox. It parses messages, including the multipart and encoded ones mail
+// clients send, and fetches unseen mail from an IMAP mailbox.
+package intake
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// maxBodyLength caps the text kept from an email body, so a pasted log
+// doesn't swamp the ball's context
+const maxBodyLength = 8000
+
+// subjectPriority matches a leading priority tag in a subject, e.g.
+// "[urgent] Checkout is down"
+var subjectPriority = regexp.MustCompile(`^\s*\[(low|medium|high|urgent)\]\s*`)
+
+// replyPrefix matches the reply and forward prefixes mail clients add
+var replyPrefix = regexp.MustCompile(`(?i)^\s*((re|fwd?|aw|sv):\s*)+`)
+
+// Email is what intake keeps of an email message
+type Email struct {
+	Subject   string
+	From      string // Sender's address
+	MessageID string
+	Body      string // Plain text body, without the signature
+}
+
+// ParseEmail reads an RFC 5322 message. The body is the first text/plain
+// part, or the text/html part with its tags stripped if there's no plain
+// text.
+func ParseEmail(r io.Reader) (*Email, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	subject, err := decoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+	email := &Email{
+		Subject:   strings.TrimSpace(subject),
+		MessageID: strings.Trim(msg.Header.Get("Message-Id"), "<> "),
+	}
+	if from, err := msg.Header.AddressList("From"); err == nil && len(from) > 0 {
+		email.From = from[0].Address
+	} else {
+		email.From = msg.Header.Get("From")
+	}
+
+	body, err := textBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	email.Body = trimBody(body)
+	return email, nil
+}
+
+// IntakeItem returns the proposed ball an email becomes. A leading
+// "[priority]" tag in the subject sets its priority.
+func (e *Email) IntakeItem() (*session.IntakeItem, error) {
+	title := replyPrefix.ReplaceAllString(e.Subject, "")
+	priority := session.PriorityMedium
+	if m := subjectPriority.FindStringSubmatch(title); m != nil {
+		priority = session.Priority(m[1])
+		title = title[len(m[0]):]
+	}
+	if strings.TrimSpace(title) == "" {
+		title = "Email from " + e.From
+	}
+	return session.NewIntakeItem(title, e.Body, priority, session.IntakeSource{
+		Kind: session.IntakeEmail,
+		From: e.From,
+		Ref:  e.MessageID,
+	})
+}
+
+// textBody returns the text of a message body or MIME part
+func textBody(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		var html string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("invalid multipart email: %w", err)
+			}
+			text, err := textBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			switch {
+			case text == "":
+			case partType == "text/html":
+				if html == "" {
+					html = text
+				}
+			default:
+				return text, nil
+			}
+		}
+		return html, nil
+	}
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", nil // Attachments
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read email body: %w", err)
+	}
+	text := string(data)
+	if mediaType == "text/html" {
+		text = stripHTML(text)
+	}
+	return text, nil
+}
+
+// htmlTag matches an HTML tag, and htmlBreak the tags that end a line
+var (
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+	htmlBreak = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr)[^>]*>`)
+)
+
+// stripHTML reduces HTML to its text, keeping line breaks
+func stripHTML(html string) string {
+	text := htmlBreak.ReplaceAllString(html, "\n")
+	text = htmlTag.ReplaceAllString(text, "")
+	replacer := strings.NewReplacer("&nbsp;", " ", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'", "&amp;", "&")
+	return replacer.Replace(text)
+}
+
+// trimBody drops the signature and surrounding blank lines, and caps the
+// body's length
+func trimBody(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if i := strings.Index(body, "\n-- \n"); i >= 0 {
+		body = body[:i]
+	}
+	body = strings.TrimSpace(body)
+	if len(body) > maxBodyLength {
+		body = strings.TrimSpace(body[:maxBodyLength]) + "\n[truncated]"
+	}
+	return body
+}
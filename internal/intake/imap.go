@@ -0,0 +1,263 @@
+package intake
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// imapTimeout bounds each IMAP command, so a stalled server can't hang a poll
+const imapTimeout = 30 * time.Second
+
+// IMAPConfig says which mailbox to poll
+type IMAPConfig struct {
+	Addr      string // host:port of the server
+	User      string
+	Password  string
+	Mailbox   string // Default "INBOX"
+	PlainText bool   // Connect without TLS (only for local test servers)
+}
+
+// IMAPClient is a minimal IMAP4rev1 client: enough to log in, find unseen
+// messages, fetch them and mark them seen
+type IMAPClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	next   int // Number of the next command tag
+}
+
+// imapResponse is an untagged response line with its literal, if it had one
+type imapResponse struct {
+	line    string
+	literal []byte
+}
+
+// DialIMAP connects and logs in to the server, and selects the mailbox
+func DialIMAP(config IMAPConfig) (*IMAPClient, error) {
+	dialer := &net.Dialer{Timeout: imapTimeout}
+	var conn net.Conn
+	var err error
+	if config.PlainText {
+		conn, err = dialer.Dial("tcp", config.Addr)
+	} else {
+		host, _, _ := net.SplitHostPort(config.Addr)
+		conn, err = tls.DialWithDialer(dialer, "tcp", config.Addr, &tls.Config{ServerName: host})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", config.Addr, err)
+	}
+	client := newIMAPClient(conn)
+
+	conn.SetDeadline(time.Now().Add(imapTimeout))
+	greeting, err := client.reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected IMAP greeting: %s", strings.TrimSpace(greeting))
+	}
+
+	if _, err := client.command("LOGIN %s %s", quoteIMAP(config.User), quoteIMAP(config.Password)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("IMAP login failed: %w", err)
+	}
+	mailbox := config.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	if _, err := client.command("SELECT %s", quoteIMAP(mailbox)); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to select %s: %w", mailbox, err)
+	}
+	return client, nil
+}
+
+// newIMAPClient wraps a connection whose greeting hasn't been read yet
+func newIMAPClient(conn net.Conn) *IMAPClient {
+	return &IMAPClient{conn: conn, reader: bufio.NewReader(conn), next: 1}
+}
+
+// Unseen returns the UIDs of the mailbox's unseen messages
+func (c *IMAPClient) Unseen() ([]uint32, error) {
+	responses, err := c.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, err
+	}
+	var uids []uint32
+	for _, resp := range responses {
+		fields := strings.Fields(resp.line)
+		if len(fields) < 2 || fields[1] != "SEARCH" {
+			continue
+		}
+		for _, field := range fields[2:] {
+			uid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UID in search response: %q", field)
+			}
+			uids = append(uids, uint32(uid))
+		}
+	}
+	return uids, nil
+}
+
+// Fetch returns a message's full text, without marking it seen
+func (c *IMAPClient) Fetch(uid uint32) ([]byte, error) {
+	responses, err := c.command("UID FETCH %d BODY.PEEK[]", uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range responses {
+		if resp.literal != nil && strings.Contains(resp.line, "FETCH") {
+			return resp.literal, nil
+		}
+	}
+	return nil, fmt.Errorf("message %d not found", uid)
+}
+
+// MarkSeen flags a message as seen, so it isn't fetched again
+func (c *IMAPClient) MarkSeen(uid uint32) error {
+	_, err := c.command(`UID STORE %d +FLAGS.SILENT (\Seen)`, uid)
+	return err
+}
+
+// Close logs out and closes the connection
+func (c *IMAPClient) Close() error {
+	c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// command sends a tagged command and reads responses up to its completion,
+// returning the untagged ones. A NO or BAD completion is an error.
+func (c *IMAPClient) command(format string, args ...any) ([]imapResponse, error) {
+	tag := fmt.Sprintf("J%d", c.next)
+	c.next++
+	c.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, fmt.Errorf("failed to send IMAP command: %w", err)
+	}
+
+	var responses []imapResponse
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(resp.line, tag+" ") {
+			responses = append(responses, resp)
+			continue
+		}
+		status := strings.TrimPrefix(resp.line, tag+" ")
+		if !strings.HasPrefix(status, "OK") {
+			return nil, fmt.Errorf("server said: %s", status)
+		}
+		return responses, nil
+	}
+}
+
+// readResponse reads one response line, along with the literal it ends
+// with (e.g. "{1234}") and the rest of the line after the literal
+func (c *IMAPClient) readResponse() (imapResponse, error) {
+	var resp imapResponse
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return resp, fmt.Errorf("failed to read IMAP response: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		resp.line += line
+
+		size, ok := literalSize(line)
+		if !ok {
+			return resp, nil
+		}
+		literal := make([]byte, size)
+		if _, err := io.ReadFull(c.reader, literal); err != nil {
+			return resp, fmt.Errorf("failed to read IMAP literal: %w", err)
+		}
+		resp.literal = literal
+	}
+}
+
+// literalSize returns the size of the literal a line announces, if any
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	open := strings.LastIndex(line, "{")
+	if open < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(line[open+1 : len(line)-1])
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return size, true
+}
+
+// quoteIMAP quotes a string for an IMAP command
+func quoteIMAP(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// PollResult is what one poll of a mailbox did
+type PollResult struct {
+	Added   []*session.IntakeItem // Items added to the inbox
+	Repeats int                   // Messages already in the inbox
+	Skipped []string              // Messages that couldn't be read, and why
+}
+
+// Poll adds the mailbox's unseen messages to the inbox and marks them
+// seen. A message is only marked seen once it's in the inbox, so a failed
+// poll is retried next time; repeats are dropped by Message-ID.
+func Poll(config IMAPConfig, inbox *session.IntakeStore) (*PollResult, error) {
+	client, err := DialIMAP(config)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	uids, err := client.Unseen()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for unseen mail: %w", err)
+	}
+	result := &PollResult{}
+	for _, uid := range uids {
+		raw, err := client.Fetch(uid)
+		if err != nil {
+			return result, fmt.Errorf("failed to fetch message %d: %w", uid, err)
+		}
+		email, err := ParseEmail(bytes.NewReader(raw))
+		var item *session.IntakeItem
+		if err == nil {
+			item, err = email.IntakeItem()
+		}
+		if err != nil {
+			// Marked seen anyway, or it would be skipped on every poll
+			result.Skipped = append(result.Skipped, fmt.Sprintf("message %d: %v", uid, err))
+		} else {
+			added, err := inbox.AddIntake(item)
+			if err != nil {
+				return result, err
+			}
+			if added {
+				result.Added = append(result.Added, item)
+			} else {
+				result.Repeats++
+			}
+		}
+		if err := client.MarkSeen(uid); err != nil {
+			return result, fmt.Errorf("failed to mark message %d seen: %w", uid, err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,246 @@
+package intake
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		subject  string
+		from     string
+		body     string
+		title    string
+		priority session.Priority
+	}{
+		{
+			name: "plain text with signature",
+			message: "From: Ana <ana@example.com>\r\n" +
+				"Subject: [high] Search is slow\r\n" +
+				"Message-ID: <a1@example.com>\r\n" +
+				"\r\n" +
+				"Takes 10s for any query.\r\n-- \r\nAna\r\n",
+			subject:  "[high] Search is slow",
+			from:     "ana@example.com",
+			body:     "Takes 10s for any query.",
+			title:    "Search is slow",
+			priority: session.PriorityHigh,
+		},
+		{
+			name: "multipart prefers plain text",
+			message: "From: bo@example.com\r\n" +
+				"Subject: Fwd: Re: Login loop\r\n" +
+				"Content-Type: multipart/alternative; boundary=XYZ\r\n" +
+				"\r\n" +
+				"--XYZ\r\n" +
+				"Content-Type: text/html\r\n" +
+				"\r\n" +
+				"<p>HTML version</p>\r\n" +
+				"--XYZ\r\n" +
+				"Content-Type: text/plain; charset=utf-8\r\n" +
+				"Content-Transfer-Encoding: quoted-printable\r\n" +
+				"\r\n" +
+				"Caf=C3=A9 login redirects forever\r\n" +
+				"--XYZ--\r\n",
+			subject:  "Fwd: Re: Login loop",
+			from:     "bo@example.com",
+			body:     "Café login redirects forever",
+			title:    "Login loop",
+			priority: session.PriorityMedium,
+		},
+		{
+			name: "html only, base64, encoded subject",
+			message: "From: cy@example.com\r\n" +
+				"Subject: =?utf-8?q?Broken_=C3=BCpload?=\r\n" +
+				"Content-Type: text/html\r\n" +
+				"Content-Transfer-Encoding: base64\r\n" +
+				"\r\n" +
+				"PHA+VXBsb2FkcyAmYW1wOyBpbWFnZXM8L3A+PGRpdj5mYWlsPC9kaXY+\r\n",
+			subject:  "Broken üpload",
+			from:     "cy@example.com",
+			body:     "Uploads & images\nfail",
+			title:    "Broken üpload",
+			priority: session.PriorityMedium,
+		},
+		{
+			name:     "empty subject",
+			message:  "From: di@example.com\r\n\r\nSomething broke\r\n",
+			from:     "di@example.com",
+			body:     "Something broke",
+			title:    "Email from di@example.com",
+			priority: session.PriorityMedium,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, err := ParseEmail(strings.NewReader(tt.message))
+			if err != nil {
+				t.Fatalf("ParseEmail failed: %v", err)
+			}
+			if email.Subject != tt.subject || email.From != tt.from || email.Body != tt.body {
+				t.Errorf("Unexpected email: %+v", email)
+			}
+			item, err := email.IntakeItem()
+			if err != nil {
+				t.Fatalf("IntakeItem failed: %v", err)
+			}
+			if item.Title != tt.title || item.Priority != tt.priority {
+				t.Errorf("Expected %q (%s), got %q (%s)", tt.title, tt.priority, item.Title, item.Priority)
+			}
+			if item.Source.Kind != session.IntakeEmail || item.Source.From != tt.from {
+				t.Errorf("Unexpected source: %+v", item.Source)
+			}
+		})
+	}
+}
+
+func TestParseEmail_TruncatesLongBody(t *testing.T) {
+	message := "From: ana@example.com\r\nSubject: Log\r\n\r\n" + strings.Repeat("x", maxBodyLength+100)
+	email, err := ParseEmail(strings.NewReader(message))
+	if err != nil {
+		t.Fatalf("ParseEmail failed: %v", err)
+	}
+	if !strings.HasSuffix(email.Body, "[truncated]") || len(email.Body) > maxBodyLength+20 {
+		t.Errorf("Expected the body truncated, got %d bytes", len(email.Body))
+	}
+}
+
+// fakeIMAPServer serves the given messages (by UID) over plain text IMAP,
+// recording the UIDs marked seen
+type fakeIMAPServer struct {
+	listener net.Listener
+	messages map[uint32]string
+	seen     chan uint32
+}
+
+func newFakeIMAPServer(t *testing.T, messages map[uint32]string) *fakeIMAPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	server := &fakeIMAPServer{listener: listener, messages: messages, seen: make(chan uint32, len(messages))}
+	go server.serve()
+	return server
+}
+
+func (s *fakeIMAPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "* OK fake IMAP ready\r\n")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return
+		}
+		tag, command := fields[0], strings.Join(fields[1:], " ")
+		switch {
+		case strings.HasPrefix(command, "LOGIN"):
+			if command != `LOGIN "bugs" "p\"w"` {
+				fmt.Fprintf(conn, "%s NO bad credentials\r\n", tag)
+				continue
+			}
+		case strings.HasPrefix(command, "UID SEARCH UNSEEN"):
+			uids := make([]string, 0, len(s.messages))
+			for uid := uint32(1); uid <= uint32(len(s.messages)); uid++ {
+				uids = append(uids, fmt.Sprint(uid))
+			}
+			fmt.Fprintf(conn, "* SEARCH %s\r\n", strings.Join(uids, " "))
+		case strings.HasPrefix(command, "UID FETCH"):
+			var uid uint32
+			fmt.Sscanf(fields[3], "%d", &uid)
+			msg := s.messages[uid]
+			fmt.Fprintf(conn, "* %d FETCH (UID %d BODY[] {%d}\r\n%s)\r\n", uid, uid, len(msg), msg)
+		case strings.HasPrefix(command, "UID STORE"):
+			var uid uint32
+			fmt.Sscanf(fields[3], "%d", &uid)
+			s.seen <- uid
+		case command == "LOGOUT":
+			fmt.Fprint(conn, "* BYE\r\n")
+			fmt.Fprintf(conn, "%s OK LOGOUT completed\r\n", tag)
+			return
+		}
+		fmt.Fprintf(conn, "%s OK done\r\n", tag)
+	}
+}
+
+func TestPoll(t *testing.T) {
+	messages := map[uint32]string{
+		1: "From: ana@example.com\r\nSubject: [urgent] Checkout down\r\nMessage-ID: <m1@x>\r\n\r\nCard payments fail\r\n",
+		2: "not an email",
+		3: "From: bo@example.com\r\nSubject: Typo on pricing page\r\nMessage-ID: <m3@x>\r\n\r\n'Montly'\r\n",
+	}
+	server := newFakeIMAPServer(t, messages)
+
+	inbox, err := session.NewIntakeStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create intake store: %v", err)
+	}
+	// Already in the inbox from an earlier, interrupted poll
+	earlier, _ := session.NewIntakeItem("Typo on pricing page", "", "", session.IntakeSource{Kind: session.IntakeEmail, Ref: "m3@x"})
+	if _, err := inbox.AddIntake(earlier); err != nil {
+		t.Fatalf("Failed to add item: %v", err)
+	}
+
+	result, err := Poll(IMAPConfig{
+		Addr:      server.listener.Addr().String(),
+		User:      "bugs",
+		Password:  `p"w`,
+		PlainText: true,
+	}, inbox)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0].Title != "Checkout down" || result.Added[0].Priority != session.PriorityUrgent {
+		t.Errorf("Expected the new message added, got %+v", result.Added)
+	}
+	if result.Repeats != 1 || len(result.Skipped) != 1 {
+		t.Errorf("Expected one repeat and one skipped message, got %+v", result)
+	}
+
+	close(server.seen)
+	var seen []uint32
+	for uid := range server.seen {
+		seen = append(seen, uid)
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected every message marked seen, got %v", seen)
+	}
+
+	items, err := inbox.LoadIntake()
+	if err != nil {
+		t.Fatalf("Failed to load inbox: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected 2 items in the inbox, got %d", len(items))
+	}
+}
+
+func TestPoll_LoginFailure(t *testing.T) {
+	server := newFakeIMAPServer(t, nil)
+	inbox, err := session.NewIntakeStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create intake store: %v", err)
+	}
+	_, err = Poll(IMAPConfig{Addr: server.listener.Addr().String(), User: "bugs", Password: "wrong", PlainText: true}, inbox)
+	if err == nil || !strings.Contains(err.Error(), "login failed") {
+		t.Errorf("Expected a login failure, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package integration_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ohare93/juggle/internal/cli"
 	"github.com/ohare93/juggle/internal/session"
@@ -544,3 +547,115 @@ func TestLoadBallsForModelSelection_ExcludesCompleteBalls(t *testing.T) {
 		t.Errorf("Expected pending ball, got %s", balls[0].State)
 	}
 }
+
+func TestAgentDryRunShowsBudgetPreview(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "nightly", "Overnight work")
+	store := env.GetStore(t)
+	for _, title := range []string{"First ball", "Second ball", "Third ball"} {
+		ball := env.CreateBall(t, title, session.PriorityMedium)
+		ball.Tags = []string{"nightly"}
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "run", "nightly", "--dry-run", "-n", "5", "--timeout", "20m")
+	for _, want := range []string{
+		"=== Budget Preview ===",
+		"Actionable balls: 3",
+		"Max iterations: 5\n",
+		"Timeout per iteration: 20m0s",
+		"History: no past runs for this session",
+		"Longest possible: 1h 40m, plus rate limit waits",
+		"(Dry run - agent not started)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in dry run output:\n%s", want, output)
+		}
+	}
+
+	// A past run gives the time per iteration and an expected length
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+	record := session.NewAgentRunRecord("nightly", env.ProjectDir, time.Now().Add(-time.Hour))
+	record.SetComplete(4, 2, 0, 2)
+	record.EndedAt = record.StartedAt.Add(40 * time.Minute)
+	if err := historyStore.AppendRecord(record); err != nil {
+		t.Fatalf("Failed to append record: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "run", "nightly", "--dry-run", "-n", "5")
+	for _, want := range []string{
+		"Max iterations: 5 (expect about 5)",
+		"History: 10m and 0.5 balls completed per iteration (1 past run(s))",
+		"Expected time: about 50m",
+		"Longest possible: unbounded",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in dry run output:\n%s", want, output)
+		}
+	}
+}
+
+func TestAgentDryRunShowsPreLoopChecksAndRunner(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "nightly", "Overnight work")
+	sessionStore := env.GetSessionStore(t)
+	criteria := []session.ExitCriterion{{Text: "Flag file exists", Check: "test -f done.flag"}}
+	if err := sessionStore.UpdateSessionExitCriteria("nightly", criteria); err != nil {
+		t.Fatalf("Failed to set exit criteria: %v", err)
+	}
+	store := env.GetStore(t)
+	ball := env.CreateBall(t, "First ball", session.PriorityMedium)
+	ball.Tags = []string{"nightly"}
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "run", "nightly", "--dry-run", "--provider", "http", "--model", "opus")
+	for _, want := range []string{
+		"=== Pre-loop Checks ===",
+		"✓ Lock: free",
+		"✓ Brownout: none",
+		"✓ Provider: http",
+		"✓ Workable balls: 1 workable, 0 blocked",
+		"✗ Exit criteria: 0/1 met, COMPLETE is rejected until all are",
+		ball.ShortID() + " [pending] First ball",
+		"Command: POST https://api.anthropic.com/v1/messages (anthropic format, model claude-opus-4-5) with <prompt,",
+		"(Dry run - agent not started)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in dry run output:\n%s", want, output)
+		}
+	}
+
+	// Checks are run, but their results aren't recorded
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, "done.flag"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write flag file: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "run", "nightly", "--dry-run", "--provider", "http")
+	if !strings.Contains(output, "✓ Exit criteria: 1/1 met") {
+		t.Errorf("Expected the criterion to pass its check:\n%s", output)
+	}
+	sess, err := sessionStore.LoadSession("nightly")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if sess.ExitCriteria[0].Met {
+		t.Error("Expected the dry run not to record exit criteria results")
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "run", "missing", "--dry-run")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for an unknown session, got %d", exitCode)
+	}
+}
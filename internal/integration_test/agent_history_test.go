@@ -0,0 +1,143 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentRunLabelAndNote tests labeling a run at launch and annotating it
+// afterwards via juggle agent history
+func TestAgentRunLabelAndNote(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "test-session", "Label test")
+	ball := env.CreateBall(t, "Wire up auth", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(agent.NewMockRunner(&agent.RunResult{
+		Output:        "Stuck",
+		Blocked:       true,
+		BlockedReason: "needs credentials",
+	}))
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Label:         "attempt-2-after-prompt-fix",
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.RunID == "" {
+		t.Fatal("Expected the result to carry the run ID")
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "history")
+	if !strings.Contains(output, result.RunID) || !strings.Contains(output, "attempt-2-after-prompt-fix") {
+		t.Errorf("Expected the labeled run in history, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "history", "annotate", "latest", "Got further; needs credentials")
+	if !strings.Contains(output, "Updated run "+result.RunID) {
+		t.Errorf("Expected annotate confirmation, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "history")
+	if !strings.Contains(output, "Got further; needs credentials") {
+		t.Errorf("Expected the note in history, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "agent", "history", "annotate", result.RunID, "--label", "baseline")
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	record, err := historyStore.FindRecord(result.RunID)
+	if err != nil {
+		t.Fatalf("Failed to find run: %v", err)
+	}
+	if record.Label != "baseline" || record.Note != "Got further; needs credentials" {
+		t.Errorf("Expected relabeled run to keep its note, got label %q note %q", record.Label, record.Note)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "history", "annotate", "latest"); exitCode == 0 {
+		t.Error("Expected annotate without a note or --label to fail")
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "history", "annotate", "no-such-run", "note"); exitCode == 0 {
+		t.Error("Expected annotate of an unknown run to fail")
+	}
+}
+
+// TestAgentExportDataset tests that each iteration's prompt and outcome is
+// recorded and exported, paired with the agent's response
+func TestAgentExportDataset(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "test-session", "Dataset test")
+	ball := env.CreateBall(t, "Wire up auth", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.SetRunner(&outputProgressUpdatingMockRunner{
+		mock: agent.NewMockRunner(
+			&agent.RunResult{Output: "Did part one\n<promise>CONTINUE</promise>", Continue: true},
+			&agent.RunResult{Output: "Stuck", Blocked: true, BlockedReason: "needs credentials"},
+		),
+		sessionStore: env.GetSessionStore(t),
+		sessionID:    "test-session",
+	})
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 3,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(result.IterationOutcomes) != 2 || result.IterationOutcomes[0].Outcome != session.IterationContinued ||
+		result.IterationOutcomes[1].Outcome != session.IterationBlocked {
+		t.Fatalf("Unexpected iteration outcomes: %+v", result.IterationOutcomes)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "export-dataset", "--sessions", "test-session")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one line per iteration, got: %s", output)
+	}
+	var example session.DatasetExample
+	if err := json.Unmarshal([]byte(lines[1]), &example); err != nil {
+		t.Fatalf("Failed to parse example: %v\nLine: %s", err, lines[1])
+	}
+	if example.Outcome != session.IterationBlocked || example.Response != "Stuck" || example.RunResult != "blocked" ||
+		!strings.Contains(example.Prompt, "Wire up auth") {
+		t.Errorf("Unexpected example: %+v", example)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "export-dataset", "--format", "chat", "--outcome", "continued")
+	if strings.Count(strings.TrimSpace(output), "\n") != 0 || !strings.Contains(output, `"role":"assistant","content":"Did part one`) {
+		t.Errorf("Expected one chat example, got: %s", output)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "export-dataset", "--outcome", "great"); exitCode != 4 {
+		t.Errorf("Expected exit code 4 for an unknown outcome, got %d", exitCode)
+	}
+}
@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// streamingMockRunner writes each response's output to the run's output
+// streams the way a real provider does, then returns it
+type streamingMockRunner struct {
+	mock *agent.MockRunner
+}
+
+func (s *streamingMockRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	result, err := s.mock.Run(opts)
+	if opts.Stdout != nil {
+		fmt.Fprintln(opts.Stdout, result.Output)
+	}
+	if opts.Stderr != nil {
+		fmt.Fprintln(opts.Stderr, "warning from iteration", s.mock.NextIndex)
+	}
+	return result, err
+}
+
+// TestAgentLoop_StreamsOutputToLog tests that agent output is teed to a
+// scrubbed log under .juggle/agent-logs that the run's history points at
+func TestAgentLoop_StreamsOutputToLog(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	setupScrubTest(t, env)
+
+	agent.SetRunner(&streamingMockRunner{mock: agent.NewMockRunner(&agent.RunResult{
+		Output:        "Used sk-ant-REDACTED to test",
+		Blocked:       true,
+		BlockedReason: "needs a new token",
+	})})
+	defer agent.ResetRunner()
+
+	var result *cli.AgentResult
+	captureOutput(func() {
+		var err error
+		result, err = cli.RunAgentLoop(cli.AgentLoopConfig{
+			SessionID:     "test-session",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 1,
+		})
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+	})
+
+	logDir := filepath.Join(env.ProjectDir, ".juggle", "agent-logs")
+	if filepath.Dir(result.LogFile) != logDir || !strings.HasPrefix(filepath.Base(result.LogFile), "test-session-") {
+		t.Fatalf("Expected a session log under %s, got %q", logDir, result.LogFile)
+	}
+	data, err := os.ReadFile(result.LogFile)
+	if err != nil {
+		t.Fatalf("Failed to read agent log: %v", err)
+	}
+	log := string(data)
+	if strings.Contains(log, "sk-ant-") || !strings.Contains(log, "Used [REDACTED:api_keys] to test") {
+		t.Errorf("Expected scrubbed agent output in the log, got %q", log)
+	}
+	if !strings.Contains(log, "warning from iteration 1") {
+		t.Errorf("Expected stderr in the log, got %q", log)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 history record, got %d (%v)", len(records), err)
+	}
+	if records[0].LogFile != result.LogFile {
+		t.Errorf("Expected history to point at %s, got %q", result.LogFile, records[0].LogFile)
+	}
+}
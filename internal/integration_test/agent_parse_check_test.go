@@ -0,0 +1,79 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAgentParseCheck tests reporting the promise signals found in saved agent output
+func TestAgentParseCheck(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+
+	outputPath := filepath.Join(env.TempDir, "last_output.txt")
+	output := "All done.\n\n```\n<Promise>**complete**: feat: add parser</Promise>\n```\n"
+	if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+
+	result := runJuggleCommand(t, env.ProjectDir, "agent", "parse-check", outputPath)
+	if !strings.Contains(result, "line 4") || !strings.Contains(result, "Result: COMPLETE") {
+		t.Errorf("Expected COMPLETE found on line 4, got: %s", result)
+	}
+	if !strings.Contains(result, "Commit message: feat: add parser") {
+		t.Errorf("Expected commit message, got: %s", result)
+	}
+
+	// Strict detection only accepts the exact form
+	result = runJuggleCommand(t, env.ProjectDir, "agent", "parse-check", outputPath, "--strict")
+	if !strings.Contains(result, "No signals found") {
+		t.Errorf("Expected strict detection to find nothing, got: %s", result)
+	}
+
+	// Configured strict mode and extra tags are used by default
+	runJuggleCommand(t, env.ProjectDir, "config", "signals", "set", "--strict", "--tag", "signal")
+	result = runJuggleCommand(t, env.ProjectDir, "config", "signals", "show")
+	if !strings.Contains(result, "strict") || !strings.Contains(result, "promise, signal") {
+		t.Errorf("Expected strict mode with extra tag, got: %s", result)
+	}
+	if err := os.WriteFile(outputPath, []byte("<signal>BLOCKED: needs review</signal>\n"), 0644); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+
+	result = runJuggleCommand(t, env.ProjectDir, "--json", "agent", "parse-check", outputPath)
+	var report struct {
+		Strict        bool   `json:"strict"`
+		Blocked       bool   `json:"blocked"`
+		BlockedReason string `json:"blocked_reason"`
+		Signals       []struct {
+			Kind string `json:"kind"`
+			Line int    `json:"line"`
+			Form string `json:"form"`
+		} `json:"signals"`
+	}
+	if err := json.Unmarshal([]byte(result), &report); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, result)
+	}
+	if !report.Strict || !report.Blocked || report.BlockedReason != "needs review" {
+		t.Errorf("Expected strict BLOCKED report, got: %+v", report)
+	}
+	if len(report.Signals) != 1 || report.Signals[0].Kind != "BLOCKED" || report.Signals[0].Line != 1 || report.Signals[0].Form != "tag" {
+		t.Errorf("Expected one BLOCKED tag signal on line 1, got: %+v", report.Signals)
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "config", "signals", "set", "--tag", "not valid")
+	if exitCode == 0 {
+		t.Error("Expected an invalid tag to be rejected")
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "config", "signals", "clear")
+	result = runJuggleCommand(t, env.ProjectDir, "config", "signals", "show")
+	if !strings.Contains(result, "tolerant") {
+		t.Errorf("Expected tolerant mode after clear, got: %s", result)
+	}
+}
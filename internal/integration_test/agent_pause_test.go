@@ -0,0 +1,131 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentLoop_Pause tests that a pause requested mid-iteration lets the
+// iteration finish, then holds the loop until the pause is lifted
+func TestAgentLoop_Pause(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Pause test")
+	ball := env.CreateBall(t, "Wire up auth", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	sessionStore := env.GetSessionStore(t)
+
+	// Pause during the first iteration, and lift it once the loop holds
+	var held *session.AgentPause
+	resumed := make(chan struct{})
+	runner := &signalingRunner{
+		MockRunner: agent.NewMockRunner(
+			&agent.RunResult{Output: "Halfway"},
+			&agent.RunResult{Output: "Still working"},
+		),
+	}
+	runner.send = func() {
+		if runner.NextIndex > 0 {
+			return
+		}
+		if err := sessionStore.SaveAgentPause(&session.AgentPause{SessionID: "test-session", Source: "cli"}); err != nil {
+			t.Errorf("Failed to pause: %v", err)
+			return
+		}
+		go func() {
+			defer close(resumed)
+			for {
+				pause, _ := sessionStore.LoadAgentPause("test-session")
+				if pause != nil && pause.Holding() {
+					held = pause
+					_ = sessionStore.ClearAgentPause("test-session")
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		}()
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 2,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	<-resumed
+
+	if held == nil {
+		t.Fatal("Expected the loop to hold on the pause")
+	}
+	if runner.NextIndex != 2 || result.Iterations != 2 {
+		t.Errorf("Expected both iterations to run around the pause, got %d calls", runner.NextIndex)
+	}
+	if result.PausedTime <= 0 {
+		t.Errorf("Expected the time paused to be recorded, got %v", result.PausedTime)
+	}
+	progress, _ := sessionStore.LoadProgress("test-session")
+	if !strings.Contains(progress, "[HUMAN] Paused the agent run after iteration 1") ||
+		!strings.Contains(progress, "[HUMAN] Resumed the agent run") {
+		t.Errorf("Expected the pause to be logged to progress, got:\n%s", progress)
+	}
+}
+
+// TestAgentPauseCommand tests agent pause and calling it off with agent
+// resume while a run holds the session lock
+func TestAgentPauseCommand(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "nightly", "Overnight work")
+	sessionStore := env.GetSessionStore(t)
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "pause", "nightly")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 with no run going, got %d", exitCode)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "agent", "pause", "missing")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for an unknown session, got %d", exitCode)
+	}
+
+	lock, err := sessionStore.AcquireSessionLock("nightly")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	defer lock.Release()
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "pause", "nightly")
+	if !strings.Contains(output, "Pausing the agent run on session nightly") {
+		t.Errorf("Expected the run to be pausing, got:\n%s", output)
+	}
+	if pause, _ := sessionStore.LoadAgentPause("nightly"); pause == nil || pause.Holding() {
+		t.Fatalf("Expected a pause waiting on the current iteration, got %+v", pause)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "pause", "nightly")
+	if !strings.Contains(output, "already pausing") {
+		t.Errorf("Expected a second pause to be reported, got:\n%s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "resume", "nightly")
+	if !strings.Contains(output, "Called off the pause") {
+		t.Errorf("Expected resume to call off the pause, got:\n%s", output)
+	}
+	if pause, _ := sessionStore.LoadAgentPause("nightly"); pause != nil {
+		t.Errorf("Expected no pause after resuming, got %+v", pause)
+	}
+}
@@ -0,0 +1,127 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestPlanThenRun tests that approved balls from the planning run are created
+// and implemented, with both runs linked in agent history
+func TestPlanThenRun(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "retry", "Retry failed requests")
+
+	mock := agent.NewMockRunner(
+		&agent.RunResult{Output: `Read the HTTP client.
+<plan>
+1. Add a retry budget to the HTTP client
+   - Requests are retried at most 3 times
+2. Log each retry
+3. Rewrite the client in Rust
+</plan>`},
+		&agent.RunResult{Output: "Stuck", Blocked: true, BlockedReason: "needs a test server"},
+	)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	var proposed []cli.PlanStep
+	result, err := cli.RunPlanThenRun(cli.PlanThenRunConfig{
+		Loop: cli.AgentLoopConfig{
+			SessionID:     "retry",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 1,
+		},
+		Approve: func(steps []cli.PlanStep) []cli.PlanStep {
+			proposed = steps
+			return steps[:2]
+		},
+	})
+	if err != nil {
+		t.Fatalf("Plan-then-run failed: %v", err)
+	}
+
+	if len(proposed) != 3 || len(proposed[0].Criteria) != 1 {
+		t.Fatalf("Expected 3 proposed balls, the first with criteria, got %+v", proposed)
+	}
+	if len(mock.Calls) != 2 || mock.Calls[0].Permission != agent.PermissionPlan {
+		t.Fatalf("Expected a read-only planning call then an implementation call, got %d calls", len(mock.Calls))
+	}
+	if !strings.Contains(mock.Calls[0].Prompt, "Retry failed requests") {
+		t.Error("Expected the session in the planning prompt")
+	}
+
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 2 {
+		t.Fatalf("Expected only the 2 approved balls, got %d", len(balls))
+	}
+	for _, ball := range balls {
+		if len(ball.Tags) != 1 || ball.Tags[0] != "retry" || strings.Contains(ball.Title, "Rust") {
+			t.Errorf("Unexpected ball %q (tags %v)", ball.Title, ball.Tags)
+		}
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	plan, err := historyStore.FindRecord(result.PlanRunID)
+	if err != nil {
+		t.Fatalf("Expected the plan run in history: %v", err)
+	}
+	implement, err := historyStore.FindRecord(result.Run.RunID)
+	if err != nil {
+		t.Fatalf("Expected the implementation run in history: %v", err)
+	}
+	if plan.Phase != session.RunPhasePlan || plan.LinkedRunID != implement.ID || plan.BallsTotal != 3 {
+		t.Errorf("Expected the plan run linked to the implementation, got %+v", plan)
+	}
+	if implement.Phase != session.RunPhaseImplement || implement.LinkedRunID != plan.ID || implement.Label != "plan-then-run" {
+		t.Errorf("Expected the implementation run linked to the plan, got %+v", implement)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "history")
+	if !strings.Contains(output, "implemented by run "+implement.ID) {
+		t.Errorf("Expected the phases in history, got: %s", output)
+	}
+}
+
+// TestPlanThenRunNothingApproved tests that rejecting the whole plan creates
+// no balls and skips implementation
+func TestPlanThenRunNothingApproved(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "retry", "Retry failed requests")
+	mock := agent.NewMockRunner(&agent.RunResult{Output: "<plan>\n1. Add a retry budget\n</plan>"})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunPlanThenRun(cli.PlanThenRunConfig{
+		Loop:    cli.AgentLoopConfig{SessionID: "retry", ProjectDir: env.ProjectDir, MaxIterations: 1},
+		Approve: func([]cli.PlanStep) []cli.PlanStep { return nil },
+	})
+	if err != nil {
+		t.Fatalf("Plan-then-run failed: %v", err)
+	}
+	if result.Run != nil || len(mock.Calls) != 1 {
+		t.Errorf("Expected no implementation run, got %d agent calls", len(mock.Calls))
+	}
+	if balls, _ := env.GetStore(t).LoadBalls(); len(balls) != 0 {
+		t.Errorf("Expected no balls created, got %d", len(balls))
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "plan-then-run"); exitCode == 0 {
+		t.Error("Expected plan-then-run without --session to fail")
+	}
+}
@@ -0,0 +1,134 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentLoop_SessionShellProvider tests that a session's provider runs
+// the session's iterations, here a shell command given the prompt on stdin
+func TestAgentLoop_SessionShellProvider(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	promptFile := filepath.Join(env.TempDir, "prompt.txt")
+	err := session.UpdateGlobalShellAgentWithOptions(opts, func(shellAgent *session.ShellAgentConfig) {
+		shellAgent.Command = fmt.Sprintf(`cat > %q; echo "Blocked on fixtures" >> %q; echo "<promise>BLOCKED: shell ran</promise>"`,
+			promptFile, filepath.Join(env.ProjectDir, ".juggle", "sessions", "scripted", "progress.txt"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure shell provider: %v", err)
+	}
+
+	env.CreateSession(t, "scripted", "Scripted work")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "scripted", "--provider", "shell")
+	ball := env.CreateBall(t, "Regenerate fixtures", session.PriorityMedium)
+	ball.Tags = []string{"scripted"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.ResetRunner()
+	defer agent.ResetRunner()
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "scripted",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.Blocked || result.BlockedReason != "shell ran" {
+		t.Errorf("Expected the shell command's signal, got %+v", result)
+	}
+	prompt, err := os.ReadFile(promptFile)
+	if err != nil || !strings.Contains(string(prompt), "Regenerate fixtures") {
+		t.Errorf("Expected the prompt on the command's stdin, got %q (%v)", prompt, err)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "sessions", "edit", "scripted", "--provider", "gpt"); exitCode != 4 {
+		t.Errorf("Expected an unknown provider to be rejected, got %d", exitCode)
+	}
+}
+
+// TestAgentLoop_ModelSizeRoutesProvider tests that an iteration picking a
+// model size routed to another provider runs there, here a local Ollama
+func TestAgentLoop_ModelSizeRoutesProvider(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	var gotModel string
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &body)
+		gotModel, _ = body["model"].(string)
+		requests++
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Fixed the typo\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	setupConfigWithTestProject(t, env)
+	runJuggleCommand(t, env.ProjectDir, "config", "provider", "set", "shell")
+	shellRan := filepath.Join(env.TempDir, "shell-ran")
+	runJuggleCommand(t, env.ProjectDir, "config", "shell", "set", "--command", "touch "+shellRan)
+	runJuggleCommand(t, env.ProjectDir, "config", "ollama", "set", "--endpoint", server.URL, "--size", "small=tiny-coder")
+	runJuggleCommand(t, env.ProjectDir, "config", "provider", "set", "ollama", "--for", "small", "--project")
+
+	output := runJuggleCommand(t, env.ProjectDir, "config", "provider", "show")
+	if !strings.Contains(output, "Model Size Routes") || !strings.Contains(output, "ollama") {
+		t.Errorf("Expected the route in provider show, got: %s", output)
+	}
+
+	env.CreateSession(t, "chores", "Small chores")
+	ball := env.CreateBall(t, "Fix typo", session.PriorityMedium)
+	ball.Tags = []string{"chores"}
+	ball.ModelSize = session.ModelSizeSmall
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.ResetRunner()
+	defer agent.ResetRunner()
+	if _, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "chores",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if requests != 1 || gotModel != "tiny-coder" {
+		t.Errorf("Expected the small-model iteration sent to ollama's small model, got %d requests for %q", requests, gotModel)
+	}
+	if _, err := os.Stat(shellRan); err == nil {
+		t.Error("Expected the configured shell provider not to run")
+	}
+
+	// --provider beats the route
+	if _, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "chores",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		Provider:      "shell",
+	}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if _, err := os.Stat(shellRan); err != nil || requests != 1 {
+		t.Errorf("Expected --provider to override the route (%d requests, %v)", requests, err)
+	}
+}
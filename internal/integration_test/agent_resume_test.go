@@ -0,0 +1,134 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// checkpointRecordingRunner records the checkpoint saved before each call
+type checkpointRecordingRunner struct {
+	mock         *agent.MockRunner
+	sessionStore *session.SessionStore
+	sessionID    string
+	checkpoints  []*session.AgentCheckpoint
+}
+
+func (c *checkpointRecordingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	checkpoint, _ := c.sessionStore.LoadAgentCheckpoint(c.sessionID)
+	c.checkpoints = append(c.checkpoints, checkpoint)
+	return c.mock.Run(opts)
+}
+
+// TestAgentLoop_ResumeFromCheckpoint tests that a resumed run carries on
+// with the iterations the interrupted one had left, checkpointing as it goes
+func TestAgentLoop_ResumeFromCheckpoint(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for agent")
+	ball := env.CreateBall(t, "Test ball", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	// The run was killed during its third of four iterations
+	sessionStore := env.GetSessionStore(t)
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+	killed := &session.AgentCheckpoint{
+		SessionID:     "test-session",
+		Iteration:     2,
+		MaxIterations: 4,
+		WaitTime:      30 * time.Second,
+		LastSignal:    session.IterationContinued,
+		StartedAt:     started,
+	}
+
+	runner := &checkpointRecordingRunner{
+		mock: agent.NewMockRunner(
+			&agent.RunResult{Output: "Still working"},
+			&agent.RunResult{Output: "Still working"},
+		),
+		sessionStore: sessionStore,
+		sessionID:    "test-session",
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 4,
+		Resume:        killed,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if runner.mock.NextIndex != 2 || result.Iterations != 4 {
+		t.Fatalf("Expected the 2 remaining iterations to run, got %d calls ending at iteration %d", runner.mock.NextIndex, result.Iterations)
+	}
+	first, second := runner.checkpoints[0], runner.checkpoints[1]
+	if first == nil || first.Iteration != 2 || first.WaitTime != 30*time.Second || !first.StartedAt.Equal(started) {
+		t.Errorf("Expected the first checkpoint to carry on from the killed run, got %+v", first)
+	}
+	if second == nil || second.Iteration != 3 || second.LastSignal != session.IterationNoSignal {
+		t.Errorf("Expected the second checkpoint after iteration 3 with no signal, got %+v", second)
+	}
+
+	// A run that ends leaves no checkpoint
+	if checkpoint, _ := sessionStore.LoadAgentCheckpoint("test-session"); checkpoint != nil {
+		t.Errorf("Expected the checkpoint to be cleared when the run ended, got %+v", checkpoint)
+	}
+}
+
+// TestAgentResumeCommand tests agent resume without a checkpoint, with
+// --discard and with no iterations left
+func TestAgentResumeCommand(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "nightly", "Overnight work")
+	sessionStore := env.GetSessionStore(t)
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "resume", "nightly")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 without a checkpoint, got %d", exitCode)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "agent", "resume", "missing")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for an unknown session, got %d", exitCode)
+	}
+
+	checkpoint := &session.AgentCheckpoint{SessionID: "nightly", Iteration: 1, MaxIterations: 5}
+	if err := sessionStore.SaveAgentCheckpoint("nightly", checkpoint); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "resume", "nightly", "--discard")
+	if !strings.Contains(output, "Discarded the checkpoint") {
+		t.Errorf("Expected the checkpoint to be discarded, got:\n%s", output)
+	}
+	if c, _ := sessionStore.LoadAgentCheckpoint("nightly"); c != nil {
+		t.Errorf("Expected no checkpoint after --discard, got %+v", c)
+	}
+
+	checkpoint.Iteration = 5
+	if err := sessionStore.SaveAgentCheckpoint("nightly", checkpoint); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "resume", "nightly")
+	if !strings.Contains(output, "nothing to resume") {
+		t.Errorf("Expected nothing to resume with no iterations left, got:\n%s", output)
+	}
+	if c, _ := sessionStore.LoadAgentCheckpoint("nightly"); c != nil {
+		t.Errorf("Expected the used-up checkpoint to be cleared, got %+v", c)
+	}
+}
@@ -0,0 +1,148 @@
+package integration_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// editingRunner writes a file before each mock response, standing in for an
+// agent that changes the working tree
+type editingRunner struct {
+	*agent.MockRunner
+	path string
+}
+
+func (r *editingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	if err := os.WriteFile(r.path, []byte("agent edit\n"), 0644); err != nil {
+		return nil, err
+	}
+	return r.MockRunner.Run(opts)
+}
+
+// initGitProject turns the test project into a git repo with one commit
+func initGitProject(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@test.com"},
+		{"config", "user.name", "Test User"},
+		{"add", "-A"},
+		{"commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s: %v", args, output, err)
+		}
+	}
+}
+
+func setupSafeModeTest(t *testing.T, env *TestEnv, policy string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	skipIfNoClaudeCLI(t)
+
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	if err := session.UpdateGlobalSafeModeRevertWithOptions(opts, policy); err != nil {
+		t.Fatalf("Failed to set safe mode policy: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for agent")
+	ball := env.CreateBall(t, "Test ball", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	ball.State = session.StatePending
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	target := filepath.Join(env.ProjectDir, "main.go")
+	if err := os.WriteFile(target, []byte("original\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	initGitProject(t, env.ProjectDir)
+	return target
+}
+
+func TestAgentLoop_SafeModeAutoRevertsFailedRun(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	target := setupSafeModeTest(t, env, session.SafeModeRevertAuto)
+
+	runner := &editingRunner{
+		MockRunner: agent.NewMockRunner(&agent.RunResult{Output: "Working...", TimedOut: true}),
+		path:       target,
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		SafeMode:      true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+
+	if result.SafeMode == nil || result.SafeMode.Decision != session.SafeModeReverted {
+		t.Fatalf("Expected the timed out run to be reverted, got %+v", result.SafeMode)
+	}
+	if result.SafeMode.FailedSnapshot == "" {
+		t.Error("Expected the failed state to be kept as a snapshot")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "original\n" {
+		t.Errorf("Expected main.go to be reverted, got %q", data)
+	}
+
+	// The decision is logged in history
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	records, err := historyStore.LoadHistory()
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 history record, got %d (%v)", len(records), err)
+	}
+	if records[0].SafeMode == nil || records[0].SafeMode.Decision != session.SafeModeReverted {
+		t.Errorf("Expected history to record the revert, got %+v", records[0].SafeMode)
+	}
+}
+
+func TestAgentLoop_SafeModeKeepsSuccessfulRun(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+	target := setupSafeModeTest(t, env, session.SafeModeRevertAuto)
+
+	// Running out of iterations without a rejected signal isn't a failure
+	runner := &editingRunner{
+		MockRunner: agent.NewMockRunner(&agent.RunResult{Output: "Working..."}),
+		path:       target,
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+		SafeMode:      true,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.SafeMode == nil || result.SafeMode.Decision != session.SafeModeKept {
+		t.Fatalf("Expected the run's changes to be kept, got %+v", result.SafeMode)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "agent edit\n" {
+		t.Errorf("Expected main.go to keep the agent's edit, got %q", data)
+	}
+}
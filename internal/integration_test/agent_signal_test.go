@@ -0,0 +1,126 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// signalingRunner sends a human signal while the agent is working, then
+// returns the next mock response
+type signalingRunner struct {
+	*agent.MockRunner
+	send func()
+}
+
+func (r *signalingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	r.send()
+	return r.MockRunner.Run(opts)
+}
+
+// TestAgentHumanSignal tests a person ending a run mid-way with juggle agent signal
+func TestAgentHumanSignal(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "test-session", "Signal test")
+	ball := env.CreateBall(t, "Wire up auth", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "signal", "test-session", "stop")
+	if exitCode != 4 {
+		t.Errorf("Expected an unknown signal to be rejected, got %d: %s", exitCode, output)
+	}
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "agent", "signal", "test-session", "complete", "--reason", "done")
+	if exitCode != 4 {
+		t.Errorf("Expected --reason without blocked to be rejected, got %d: %s", exitCode, output)
+	}
+	if _, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "agent", "signal", "no-such-session", "complete"); exitCode == 0 {
+		t.Error("Expected a signal to an unknown session to fail")
+	}
+
+	// A signal left while nothing runs is dropped when the next run starts
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "signal", "test-session", "complete")
+	if !strings.Contains(output, "no agent is running") || !strings.Contains(output, "Sent COMPLETE") {
+		t.Errorf("Expected a warning and confirmation, got: %s", output)
+	}
+	agent.SetRunner(agent.NewMockRunner(&agent.RunResult{Output: "Working on it"}))
+	result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 1,
+	})
+	agent.ResetRunner()
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.Complete || result.HumanSignal != "" {
+		t.Errorf("Expected the stale signal to be ignored, got %+v", result)
+	}
+
+	// A signal sent mid-run ends it at the next checkpoint
+	runner := &signalingRunner{
+		MockRunner: agent.NewMockRunner(
+			&agent.RunResult{Output: "Working on it"},
+			&agent.RunResult{Output: "Still working"},
+		),
+	}
+	runner.send = func() {
+		if len(runner.Calls) == 1 {
+			runJuggleCommand(t, env.ProjectDir, "agent", "signal", "test-session", "blocked", "--reason", "waiting on design review")
+		}
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	result, err = cli.RunAgentLoop(cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(runner.Calls) != 2 {
+		t.Errorf("Expected the run to stop after the signaled iteration, got %d iterations", len(runner.Calls))
+	}
+	if !result.Blocked || result.BlockedReason != "waiting on design review" || result.HumanSignal != session.LoopSignalBlocked {
+		t.Errorf("Expected a human BLOCKED result, got %+v", result)
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	progress, err := sessionStore.LoadProgress("test-session")
+	if err != nil {
+		t.Fatalf("Failed to read progress: %v", err)
+	}
+	if !strings.Contains(progress, "[HUMAN] Signaled BLOCKED: waiting on design review") {
+		t.Errorf("Expected the signal in progress, got: %s", progress)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	record, err := historyStore.FindRecord(result.RunID)
+	if err != nil {
+		t.Fatalf("Failed to find run: %v", err)
+	}
+	if record.HumanSignal != session.LoopSignalBlocked {
+		t.Errorf("Expected the signal in history, got %q", record.HumanSignal)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "history")
+	if !strings.Contains(output, "blocked (human)") {
+		t.Errorf("Expected the run marked as decided by a human, got: %s", output)
+	}
+}
@@ -0,0 +1,62 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentStatus_ReportsAPIHealth tests that each agent call is recorded
+// toward its provider's API health and shown by agent status
+func TestAgentStatus_ReportsAPIHealth(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	err := session.UpdateGlobalShellAgentWithOptions(opts, func(shellAgent *session.ShellAgentConfig) {
+		shellAgent.Command = `cat > /dev/null; echo "<promise>BLOCKED: nothing to do</promise>"`
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure shell provider: %v", err)
+	}
+	env.CreateSession(t, "scripted", "Scripted work")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "scripted", "--provider", "shell")
+	ball := env.CreateBall(t, "Regenerate fixtures", session.PriorityMedium)
+	ball.Tags = []string{"scripted"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	agent.ResetRunner()
+	defer agent.ResetRunner()
+	output := captureOutput(func() {
+		if _, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+			SessionID:     "scripted",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 1,
+		}); err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+	})
+	if !strings.Contains(output, "📶 shell took") || !strings.Contains(output, "API healthy (1 calls, 0% errors") {
+		t.Errorf("Expected the call's health line in the output, got:\n%s", output)
+	}
+
+	health, err := session.GetAPIHealthWithOptions(opts, "shell", time.Now())
+	if err != nil || health.Calls != 1 || health.Status != session.APIHealthHealthy {
+		t.Errorf("Expected one healthy shell call recorded, got %+v (%v)", health, err)
+	}
+
+	status := runJuggleCommand(t, env.ProjectDir, "agent", "status")
+	if !strings.Contains(status, "claude *") || !strings.Contains(status, "unknown") {
+		t.Errorf("Expected the configured provider marked even without calls, got:\n%s", status)
+	}
+	if !strings.Contains(status, "shell") || !strings.Contains(status, "healthy") {
+		t.Errorf("Expected the shell provider's health, got:\n%s", status)
+	}
+}
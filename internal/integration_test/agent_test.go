@@ -2806,3 +2806,85 @@ func TestAgentLoop_MixedWithWorkableDoesRun(t *testing.T) {
 		t.Errorf("Expected 1 iteration, got %d", result.Iterations)
 	}
 }
+
+func TestAgentLoop_BrownoutPausesSession(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	globalConfig, err := session.LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	globalConfig.BrownoutThreshold = 2
+	globalConfig.BrownoutCooldownMinutes = 5
+	if err := globalConfig.SaveWithOptions(opts); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	env.CreateSession(t, "test-session", "Test session for agent")
+	sessionStore := env.GetSessionStore(t)
+
+	ball := env.CreateBall(t, "Test ball", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	ball.State = session.StatePending
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	crash := &agent.RunResult{Output: "API error", ExitCode: 1, Error: fmt.Errorf("claude exited")}
+	mock := agent.NewMockRunner(crash, crash, crash)
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	config := cli.AgentLoopConfig{
+		SessionID:     "test-session",
+		ProjectDir:    env.ProjectDir,
+		MaxIterations: 5,
+		IterDelay:     0,
+	}
+
+	result, err := cli.RunAgentLoop(config)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.Brownout == nil {
+		t.Fatal("Expected the run to brown out")
+	}
+	if len(mock.Calls) != 2 {
+		t.Errorf("Expected 2 runner calls before the breaker tripped, got %d", len(mock.Calls))
+	}
+	if result.Brownout.Errors != 2 || time.Until(result.Brownout.Until) < 4*time.Minute {
+		t.Errorf("Expected 2 errors and a 5 minute cool-down, got %+v", result.Brownout)
+	}
+	progress, _ := sessionStore.LoadProgress("test-session")
+	if !strings.Contains(progress, "[BROWNOUT]") {
+		t.Errorf("Expected progress to contain [BROWNOUT], got:\n%s", progress)
+	}
+
+	// Runs for the paused session don't start during the cool-down
+	result, err = cli.RunAgentLoop(config)
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if result.Brownout == nil || len(mock.Calls) != 2 {
+		t.Errorf("Expected paused session not to run, got %d runner calls", len(mock.Calls))
+	}
+
+	// --ignore-brownout resumes early, and a successful run clears the brownout
+	mock.SetResponses(&agent.RunResult{Output: "Working...", Continue: true})
+	mock.Calls = nil
+	config.IgnoreBrownout = true
+	config.MaxIterations = 1
+	if _, err := cli.RunAgentLoop(config); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(mock.Calls) != 1 {
+		t.Errorf("Expected --ignore-brownout to run the agent, got %d calls", len(mock.Calls))
+	}
+	if b, _ := sessionStore.LoadBrownout("test-session"); b != nil {
+		t.Errorf("Expected brownout to be cleared after a successful run, got %+v", b)
+	}
+}
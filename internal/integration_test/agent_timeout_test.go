@@ -0,0 +1,122 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentTimeoutSalvage tests that a timed out iteration's partial work is
+// recorded and handed to the next iteration's prompt
+func TestAgentTimeoutSalvage(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	err := session.UpdateGlobalShellAgentWithOptions(opts, func(shellAgent *session.ShellAgentConfig) {
+		shellAgent.Command = `cat > /dev/null`
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure shell provider: %v", err)
+	}
+	env.CreateSession(t, "auth", "Auth work")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "auth", "--provider", "shell")
+
+	store := env.GetStore(t)
+	sessionStore := env.GetSessionStore(t)
+	var balls []*session.Ball
+	for _, title := range []string{"Add login form", "Add logout button"} {
+		ball := env.CreateBall(t, title, session.PriorityMedium)
+		ball.Tags = []string{"auth"}
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+		balls = append(balls, ball)
+	}
+
+	// The agent completes the first ball and logs it, then runs out of time
+	partial := "Finished the login form\n<promise>CONTINUE: feat: add login form</promise>\nStarting on the logout button"
+	runner := &completingRunner{MockRunner: agent.NewMockRunner(&agent.RunResult{Output: partial, TimedOut: true})}
+	runner.complete = func() {
+		ball, err := store.GetBallByID(balls[0].ID)
+		if err != nil {
+			t.Fatalf("Failed to load ball: %v", err)
+		}
+		ball.MarkComplete("done")
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to complete ball: %v", err)
+		}
+		if err := sessionStore.AppendProgress("auth", "Login form done\n"); err != nil {
+			t.Fatalf("Failed to append progress: %v", err)
+		}
+	}
+	agent.SetRunner(runner)
+	defer agent.ResetRunner()
+
+	var result *cli.AgentResult
+	captureOutput(func() {
+		result, err = cli.RunAgentLoop(cli.AgentLoopConfig{
+			SessionID:     "auth",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 3,
+			Timeout:       5 * time.Minute,
+		})
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if !result.TimedOut || len(runner.Calls) != 1 {
+		t.Fatalf("Expected the run to stop after one timed out iteration, got timed out %v after %d call(s)", result.TimedOut, len(runner.Calls))
+	}
+	for _, want := range []string{
+		"Iteration 1 timed out after 5m0s",
+		"completing " + balls[0].ShortID() + " Add login form",
+		"logging 1 progress line(s)",
+		"signaling CONTINUE: feat: add login form",
+	} {
+		if !strings.Contains(result.TimeoutMessage, want) {
+			t.Errorf("Expected timeout message to contain %q, got %q", want, result.TimeoutMessage)
+		}
+	}
+
+	saved, err := os.ReadFile(filepath.Join(env.JuggleDir, "sessions", "auth", "last_output.txt"))
+	if err != nil || string(saved) != partial {
+		t.Errorf("Expected the partial output to be saved, got %q (%v)", saved, err)
+	}
+
+	// The next run's first prompt is told where the work was left
+	next := agent.NewMockRunner(&agent.RunResult{Output: "Done", Blocked: true, BlockedReason: "stop here"})
+	agent.SetRunner(next)
+	captureOutput(func() {
+		_, err = cli.RunAgentLoop(cli.AgentLoopConfig{
+			SessionID:     "auth",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: 1,
+		})
+	})
+	if err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	if len(next.Calls) != 1 {
+		t.Fatalf("Expected one call, got %d", len(next.Calls))
+	}
+	prompt := next.Calls[0].Prompt
+	for _, want := range []string{"<previous-iteration>", "cut off by its timeout", "Starting on the logout button"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q", want)
+		}
+	}
+
+	// Once an iteration finishes in time, the salvage is forgotten
+	salvage, err := sessionStore.LoadTimeoutSalvage("auth")
+	if err != nil || salvage != nil {
+		t.Errorf("Expected the salvage to be cleared, got %+v (%v)", salvage, err)
+	}
+}
@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %s: %v", args, output, err)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestAgentWorktree tests agent runs made with --worktree: the agent works
+// on its own branch in a worktree, which is then merged or discarded
+func TestAgentWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	// The shell agent writes a file where it runs and logs progress with juggle
+	setupConfigWithTestProject(t, env)
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	err := session.UpdateGlobalShellAgentWithOptions(opts, func(shellAgent *session.ShellAgentConfig) {
+		shellAgent.Command = `cat > /dev/null; echo "agent work" > feature.txt; echo "<promise>BLOCKED: needs review</promise>"`
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure shell provider: %v", err)
+	}
+	env.CreateSession(t, "scripted", "Scripted work")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "scripted", "--provider", "shell")
+	ball := env.CreateBall(t, "Add the feature", session.PriorityMedium)
+	ball.Tags = []string{"scripted"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	initGitProject(t, env.ProjectDir)
+	branch := gitOutput(t, env.ProjectDir, "symbolic-ref", "--short", "HEAD")
+
+	output, _ := runJuggleCommandWithError(t, env.ProjectDir, "agent", "run", "scripted", "--worktree", "-n", "1", "-y")
+	worktreeDir := filepath.Join(env.ProjectDir, ".juggle", "worktrees", "scripted")
+	if !strings.Contains(output, "Created worktree") {
+		t.Fatalf("Expected the worktree to be created, got:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(env.ProjectDir, "feature.txt")); err == nil {
+		t.Error("Expected the agent's changes to stay off the main working tree")
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreeDir, "feature.txt")); err != nil || string(data) != "agent work\n" {
+		t.Errorf("Expected the agent to work in the worktree, got %q (%v)", data, err)
+	}
+	if status := gitOutput(t, env.ProjectDir, "status", "--porcelain", "--untracked-files=all"); strings.Contains(status, "worktrees") || strings.Contains(status, "feature.txt") {
+		t.Errorf("Expected the worktree not to show in the main tree's status, got:\n%s", status)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "worktree", "diff", "scripted")
+	if !strings.Contains(output, "juggle/agent-scripted from "+branch) || !strings.Contains(output, "feature.txt") {
+		t.Errorf("Expected the diff to list the agent's file, got:\n%s", output)
+	}
+	if strings.Contains(output, "link") {
+		t.Errorf("Expected juggle's link file to be left out of the diff, got:\n%s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "worktree", "merge", "scripted")
+	if !strings.Contains(output, "Merged 1 commits from juggle/agent-scripted") {
+		t.Errorf("Expected the agent's work to be merged, got:\n%s", output)
+	}
+	if data, err := os.ReadFile(filepath.Join(env.ProjectDir, "feature.txt")); err != nil || string(data) != "agent work\n" {
+		t.Errorf("Expected the merged file in the main tree, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(worktreeDir); err == nil {
+		t.Error("Expected the worktree to be removed after merging")
+	}
+	if branches := gitOutput(t, env.ProjectDir, "branch", "--list", "juggle/*"); branches != "" {
+		t.Errorf("Expected the agent branch to be deleted, got %q", branches)
+	}
+
+	// A second run starts a fresh branch, which is discarded this time
+	runJuggleCommandWithError(t, env.ProjectDir, "agent", "run", "scripted", "--worktree", "-n", "1", "-y")
+	if _, err := os.Stat(worktreeDir); err != nil {
+		t.Fatalf("Expected a new worktree: %v", err)
+	}
+
+	// A worktree deleted by hand, which git still has registered, is made again
+	if err := os.RemoveAll(worktreeDir); err != nil {
+		t.Fatalf("Failed to delete the worktree: %v", err)
+	}
+	output, _ = runJuggleCommandWithError(t, env.ProjectDir, "agent", "run", "scripted", "--worktree", "-n", "1", "-y")
+	if !strings.Contains(output, "Created worktree") {
+		t.Fatalf("Expected the deleted worktree to be created again, got:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(worktreeDir, "feature.txt")); err != nil {
+		t.Errorf("Expected the agent to work in the recreated worktree: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "agent", "worktree", "discard", "scripted", "--yes")
+	if !strings.Contains(output, "Discarded the worktree and branch juggle/agent-scripted") {
+		t.Errorf("Expected the worktree to be discarded, got:\n%s", output)
+	}
+	if _, err := os.Stat(worktreeDir); err == nil {
+		t.Error("Expected the worktree to be removed")
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "worktree", "diff", "scripted")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 without a worktree, got %d", exitCode)
+	}
+}
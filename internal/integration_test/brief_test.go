@@ -0,0 +1,85 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestSessionBrief tests the briefing: goal, next balls in agent order,
+// blockers, and the last agent run
+func TestSessionBrief(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Ship the login flow")
+	store := env.GetStore(t)
+	low := env.CreateBall(t, "Password reset", session.PriorityLow)
+	urgent := env.CreateBall(t, "Login page", session.PriorityUrgent)
+	urgent.NextAction = "Wire up the form"
+	focused := env.CreateBall(t, "Remember me", session.PriorityMedium)
+	focused.Focused = true
+	waiting := env.CreateBall(t, "Logout", session.PriorityHigh)
+	waiting.DependsOn = []string{urgent.ID}
+	extra := env.CreateBall(t, "Rate limiting", session.PriorityMedium)
+	blocked := env.CreateBall(t, "SSO", session.PriorityHigh)
+	if err := blocked.SetBlocked("waiting for IdP credentials"); err != nil {
+		t.Fatalf("Failed to block ball: %v", err)
+	}
+	for _, ball := range []*session.Ball{low, urgent, focused, waiting, extra, blocked} {
+		ball.Tags = []string{"auth"}
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	run := session.NewAgentRunRecord("auth", env.ProjectDir, time.Now().Add(-time.Hour))
+	run.SetBlocked(3, "tests need a database", 1, 1, 6)
+	if err := historyStore.AppendRecord(run); err != nil {
+		t.Fatalf("Failed to record run: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "brief", "auth")
+	for _, want := range []string{
+		"Briefing: auth",
+		"Goal: Ship the login flow",
+		"Wire up the form",
+		"+2 more pending or in progress",
+		"waiting for IdP credentials",
+		"blocked: tests need a database (1/6 balls done)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in briefing, got:\n%s", want, output)
+		}
+	}
+	focusedIdx := strings.Index(output, "Remember me")
+	urgentIdx := strings.Index(output, "Login page")
+	extraIdx := strings.Index(output, "Rate limiting")
+	if focusedIdx == -1 || urgentIdx == -1 || extraIdx == -1 || focusedIdx > urgentIdx || urgentIdx > extraIdx {
+		t.Errorf("Expected focused, urgent, then medium-priority balls next, got:\n%s", output)
+	}
+	if strings.Contains(output, "Logout") || strings.Contains(output, "Password reset") {
+		t.Errorf("Expected only the top three ready balls next, got:\n%s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "brief", "auth", "--json")
+	var briefing session.Briefing
+	if err := json.Unmarshal([]byte(output), &briefing); err != nil {
+		t.Fatalf("Failed to parse JSON briefing: %v\n%s", err, output)
+	}
+	if len(briefing.Next) != 3 || briefing.Remaining != 5 || len(briefing.Blocked) != 1 || briefing.LastRun == nil {
+		t.Errorf("Unexpected JSON briefing: %+v", briefing)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "brief", "nope"); exitCode != 3 {
+		t.Errorf("Expected an unknown session to be not found, got %d", exitCode)
+	}
+}
@@ -0,0 +1,57 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestCheckpoint tests recording how far an in-progress ball has got, that
+// the agent prompt tells the agent to resume from it, and that it's dropped
+// once the ball is complete
+func TestCheckpoint(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+	ball := env.CreateBall(t, "Add rate limiting", session.PriorityHigh)
+	ball.Tags = []string{"feature"}
+	ball.State = session.StateInProgress
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--checkpoint", "  tests written  ")
+	if !strings.Contains(output, "Updated checkpoint: tests written") {
+		t.Errorf("Expected update confirmation, got: %s", output)
+	}
+	if reloaded := env.AssertBallExists(t, ball.ID); reloaded.Checkpoint != "tests written" {
+		t.Fatalf("Expected trimmed checkpoint, got %q", reloaded.Checkpoint)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "Checkpoint:") || !strings.Contains(output, "tests written") {
+		t.Errorf("Expected checkpoint in show output, got: %s", output)
+	}
+
+	prompt, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "feature", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Checkpoint: tests written (resume from here)") {
+		t.Errorf("Expected checkpoint in the ball block of the prompt, got:\n%s", prompt)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	if reloaded := env.AssertBallExists(t, ball.ID); reloaded.Checkpoint != "" {
+		t.Errorf("Expected checkpoint cleared on completion, got %q", reloaded.Checkpoint)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "update", ball.ID, "--checkpoint", "awaiting review")
+	if exitCode != 4 || !strings.Contains(output, "checkpoints are for unfinished work") {
+		t.Errorf("Expected a validation error checkpointing a complete ball, got exit %d: %s", exitCode, output)
+	}
+}
@@ -423,6 +423,55 @@ func TestProgressCommand(t *testing.T) {
 	}
 }
 
+// TestProgressCategories tests structured progress entries and filtering them,
+// alongside free-form progress written before entries were structured
+func TestProgressCategories(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	sessionStore := env.GetSessionStore(t)
+	env.CreateSession(t, "auth", "Auth work")
+	if err := sessionStore.AppendProgress("auth", "[2024-01-01 10:00:00] Blocked on juggle-92: no DB access\n"); err != nil {
+		t.Fatalf("Failed to append legacy progress: %v", err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "progress", "append", "auth", "Implemented login")
+	runJuggleCommand(t, env.ProjectDir, "progress", "append", "auth", "Use PKCE", "--category", "decision", "--author", "human")
+	output := runJuggleCommand(t, env.ProjectDir, "progress", "append", "auth", "Token endpoint 500s", "-c", "blocker", "--ball", "auth-3", "--json")
+	if !strings.Contains(output, `"category":"blocker"`) || !strings.Contains(output, `"author":"agent"`) || !strings.Contains(output, `"ball_id":"auth-3"`) {
+		t.Errorf("Expected the entry's category, author and ball in JSON, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "progress", "show", "auth", "--category", "blocker")
+	if !strings.Contains(output, "Blocked on juggle-92") || !strings.Contains(output, "[blocker] (agent) auth-3: Token endpoint 500s") {
+		t.Errorf("Expected the legacy and structured blockers, got: %s", output)
+	}
+	if strings.Contains(output, "Implemented login") || strings.Contains(output, "Use PKCE") {
+		t.Errorf("Expected only blockers, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "progress", "show", "auth", "--author", "human", "--json")
+	var entries []session.ProgressEntry
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if len(entries) != 1 || entries[0].Text != "Use PKCE" || entries[0].Category != session.ProgressCategoryDecision {
+		t.Errorf("Expected the human decision, got %+v", entries)
+	}
+
+	progress, err := sessionStore.LoadProgress("auth")
+	if err != nil {
+		t.Fatalf("Failed to load progress: %v", err)
+	}
+	if !strings.HasPrefix(progress, "[2024-01-01 10:00:00] Blocked on juggle-92") || strings.Count(progress, "\n") != 4 {
+		t.Errorf("Expected the legacy entry kept as written and one line per entry, got: %s", progress)
+	}
+
+	if _, code := runJuggleCommandWithError(t, env.ProjectDir, "progress", "show", "auth", "--category", "chore"); code != 4 {
+		t.Errorf("Expected exit code 4 for an invalid category, got %d", code)
+	}
+}
+
 // TestTagCommand tests tag operations
 func TestTagCommand(t *testing.T) {
 	env := SetupTestEnv(t)
@@ -976,6 +1025,42 @@ func TestSessionAliasContextCommand(t *testing.T) {
 	}
 }
 
+// TestSessionContextFromFile tests importing and appending session context
+// from files
+func TestSessionContextFromFile(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	runJuggleCommand(t, env.ProjectDir, "sessions", "create", "spec-import", "-m", "Spec import")
+	spec := filepath.Join(env.TempDir, "spec.md")
+	if err := os.WriteFile(spec, []byte("# Spec\n\nTokens expire after 15 minutes.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write spec: %v", err)
+	}
+	notes := filepath.Join(env.TempDir, "notes.md")
+	if err := os.WriteFile(notes, []byte("Logout revokes all tokens.\n"), 0644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "sessions", "context", "spec-import", "--from-file", spec)
+	if !strings.Contains(output, "Set context for session spec-import") {
+		t.Errorf("Expected the import to be reported, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "context", "spec-import", "--from-file", notes, "--append")
+	if !strings.Contains(output, "Appended context") {
+		t.Errorf("Expected the append to be reported, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "context", "spec-import")
+	if !strings.Contains(output, "Tokens expire after 15 minutes.\n\nLogout revokes all tokens.") {
+		t.Errorf("Expected the appended context, got: %s", output)
+	}
+
+	_, code := runJuggleCommandWithError(t, env.ProjectDir, "sessions", "context", "spec-import", "--from-file", spec, "--set", "text")
+	if code != 4 {
+		t.Errorf("Expected a validation error for two sources, got exit code %d", code)
+	}
+}
+
 // TestSessionAliasHelpShowsAlias tests that help text shows the alias
 func TestSessionAliasHelpShowsAlias(t *testing.T) {
 	juggleBinary := GetJuggleBinaryPath(t)
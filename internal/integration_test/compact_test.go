@@ -0,0 +1,103 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAdminCompact tests compacting balls.jsonl by hand and automatically
+func TestAdminCompact(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.GetStore(t)
+	ballsPath := filepath.Join(env.JuggleDir, "balls.jsonl")
+	line := `{"id":"test-project-1","title":"Same ball","state":"pending","priority":"low"}`
+	writeLines := func(lines ...string) {
+		t.Helper()
+		if err := os.WriteFile(ballsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write balls: %v", err)
+		}
+	}
+
+	writeLines(line, `{"id":"test-project-1",`)
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "admin", "compact")
+	if exitCode != 4 || !strings.Contains(output, "line 2") {
+		t.Errorf("Expected the invalid line to stop compaction, got %d: %s", exitCode, output)
+	}
+
+	writeLines(line, "", line)
+	output = runJuggleCommand(t, env.ProjectDir, "admin", "compact")
+	if !strings.Contains(output, "Compacted balls.jsonl") || !strings.Contains(output, "2 → 1 (1 superseded, 1 blank)") {
+		t.Errorf("Expected compaction stats, got: %s", output)
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil || len(balls) != 1 {
+		t.Fatalf("Expected one ball after compaction, got %d (%v)", len(balls), err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "admin", "compact", "threshold", "off")
+	if !strings.Contains(output, "turned off") {
+		t.Errorf("Expected automatic compaction turned off, got: %s", output)
+	}
+	if _, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "admin", "compact", "threshold", "lots"); exitCode != 4 {
+		t.Errorf("Expected an invalid size to be rejected, got %d", exitCode)
+	}
+
+	// Past the threshold, any command compacts the store first
+	runJuggleCommand(t, env.ProjectDir, "admin", "compact", "threshold", "100")
+	writeLines(line, line, line, line, line, line)
+	output = runJuggleCommand(t, env.ProjectDir, "list")
+	if !strings.Contains(output, "Compacted balls.jsonl") {
+		t.Errorf("Expected an automatic compaction, got: %s", output)
+	}
+	data, err := os.ReadFile(ballsPath)
+	if err != nil {
+		t.Fatalf("Failed to read balls: %v", err)
+	}
+	if strings.Count(string(data), "\n") != 1 {
+		t.Errorf("Expected one line left, got:\n%s", data)
+	}
+}
+
+// TestStoreFsck tests finding and repairing problems in balls.jsonl
+func TestStoreFsck(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.GetStore(t)
+	ballsPath := filepath.Join(env.JuggleDir, "balls.jsonl")
+	lines := []string{
+		`{"id":"test-project-1","title":"Kept","state":"pending","priority":"low"}`,
+		`{"id":"test-project-1","title":"Kept, updated","state":"pending","priority":"low"}`,
+		`{"id":"test-project-2","title":"Trunc`,
+	}
+	if err := os.WriteFile(ballsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write balls: %v", err)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "store", "fsck")
+	if exitCode != 4 || !strings.Contains(output, "duplicate_id") || !strings.Contains(output, "corrupt_line") {
+		t.Errorf("Expected both problems reported with exit code 4, got %d: %s", exitCode, output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "store", "fsck", "--repair")
+	if !strings.Contains(output, "Repaired 2 problem(s)") {
+		t.Errorf("Expected the problems repaired, got: %s", output)
+	}
+	if _, err := os.Stat(ballsPath + ".fsck.bak"); err != nil {
+		t.Errorf("Expected a backup of the old file: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "store", "fsck")
+	if !strings.Contains(output, "No problems found (1 balls, 1 lines)") {
+		t.Errorf("Expected a clean store, got: %s", output)
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil || len(balls) != 1 || balls[0].Title != "Kept, updated" {
+		t.Errorf("Expected the last line of the ball kept, got %+v (%v)", balls, err)
+	}
+}
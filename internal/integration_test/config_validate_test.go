@@ -0,0 +1,63 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConfigValidate checks `juggle config validate` passes a clean setup
+// and reports a broken project config by line, failing with exit code 4
+func TestConfigValidate(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+
+	output := runJuggleCommand(t, env.ProjectDir, "config", "validate")
+	if !strings.Contains(output, "Configuration is valid") {
+		t.Fatalf("Expected a valid configuration, got:\n%s", output)
+	}
+
+	configPath := filepath.Join(env.JuggleDir, "config.json")
+	config := "{\n  \"tests_policy\": \"sometimes\",\n  \"week_capacty\": 10\n}\n"
+	if err := os.MkdirAll(env.JuggleDir, 0755); err != nil {
+		t.Fatalf("Failed to create .juggle: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "config", "validate")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 for an invalid config, got %d:\n%s", exitCode, output)
+	}
+	for _, want := range []string{
+		configPath + `:2: tests_policy: invalid tests policy "sometimes"`,
+		configPath + ":3: week_capacty: unknown key",
+		`did you mean "week_capacity"?`,
+		"1 error(s), 1 warning(s)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "config", "validate", "--json")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 with --json, got %d", exitCode)
+	}
+	var issues []struct {
+		Line    int    `json:"line"`
+		Field   string `json:"field"`
+		Warning bool   `json:"warning"`
+	}
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("Expected only the issues as JSON, got %q: %v", output, err)
+	}
+	if len(issues) != 2 || issues[0].Field != "tests_policy" || issues[1].Line != 3 || !issues[1].Warning {
+		t.Errorf("Unexpected issues: %+v", issues)
+	}
+}
@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAgentDaemon tests the daemon launching runs for sessions with work
+func TestAgentDaemon(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	env.CreateSession(t, "backlog", "Backlog")
+	env.CreateSession(t, "idle", "Nothing to do")
+	store := env.GetStore(t)
+	for _, ball := range []struct{ title, session string }{
+		{"Login page", "auth"},
+		{"Account lockout", "auth"},
+		{"Fix typo", "backlog"},
+		{"Tidy imports", "backlog"},
+		{"Bump deps", "backlog"},
+	} {
+		b := env.CreateBall(t, ball.title, session.PriorityMedium)
+		b.Tags = []string{ball.session}
+		if err := store.UpdateBall(b); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	// Stand in for 'juggle agent run', printing the arguments it got
+	var launched [][]string
+	restore := cli.SetDaemonCommandForTest(func(projectDir string, args []string) (*exec.Cmd, error) {
+		launched = append(launched, args)
+		command := exec.Command("sh", "-c", `echo "$@"`, "sh")
+		command.Args = append(command.Args, args...)
+		command.Dir = projectDir
+		return command, nil
+	})
+	defer restore()
+
+	status, err := cli.RunAgentDaemonOnceForTest(env.ProjectDir, map[string]int{"backlog": 2}, 1)
+	if err != nil {
+		t.Fatalf("Daemon failed: %v", err)
+	}
+
+	var sessionRuns, ballRuns int
+	for _, args := range launched {
+		joined := strings.Join(args, " ")
+		switch {
+		case strings.HasPrefix(joined, "agent run auth --iterations 1") && !strings.Contains(joined, "--ball"):
+			sessionRuns++
+		case strings.HasPrefix(joined, "agent run backlog --iterations 1 --ball "):
+			ballRuns++
+		default:
+			t.Errorf("Unexpected run: %s", joined)
+		}
+	}
+	if sessionRuns != 1 || ballRuns != 2 {
+		t.Errorf("Expected one run for auth and two per-ball runs for backlog, got %v", launched)
+	}
+
+	if status == nil || !status.Stopped || len(status.Sessions) != 3 {
+		t.Fatalf("Expected a stopped daemon watching 3 sessions, got %+v", status)
+	}
+	backlog := status.Session("backlog")
+	if backlog.Limit != 2 || len(backlog.Workable) != 3 || len(backlog.Running) != 0 || backlog.Launches != 2 {
+		t.Errorf("Unexpected backlog status: %+v", backlog)
+	}
+	auth := status.Session("auth")
+	if auth.LastRun == nil || auth.LastRun.ExitCode != 0 || len(auth.Seen) != 2 {
+		t.Errorf("Expected auth's run finished with its balls seen, got %+v", auth)
+	}
+	log, err := os.ReadFile(auth.LastRun.LogFile)
+	if err != nil || !strings.Contains(string(log), "agent run auth") {
+		t.Errorf("Expected the run's output in its log, got %q (%v)", log, err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "agent", "daemon", "status")
+	for _, want := range []string{"Agent daemon: stopped", "auth", "on new work", "0/2", "exit 0 at"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in status, got: %s", want, output)
+		}
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "daemon", "--schedule", "auth"); exitCode != 4 {
+		t.Errorf("Expected a schedule without a session to be rejected, got %d", exitCode)
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "agent", "daemon", "--session", "nope"); exitCode != 3 {
+		t.Errorf("Expected an unknown session to be not found, got %d", exitCode)
+	}
+}
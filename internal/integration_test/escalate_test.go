@@ -0,0 +1,148 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestEscalate tests packaging a blocked ball for a human, keeping agents
+// off it, and resolving the escalation
+func TestEscalate(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+
+	ball := env.CreateBall(t, "Wire up payments", session.PriorityHigh)
+	ball.Tags = []string{"feature"}
+	ball.Context = "Stripe integration for checkout"
+	ball.AcceptanceCriteria = []string{"Charges succeed in test mode"}
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "escalate", ball.ID)
+	if exitCode == 0 || !strings.Contains(output, "only blocked balls can be escalated") {
+		t.Errorf("Expected escalating a pending ball to fail, got (%d): %s", exitCode, output)
+	}
+
+	if err := ball.SetBlocked("needs a Stripe test API key"); err != nil {
+		t.Fatalf("Failed to block ball: %v", err)
+	}
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	// Something to report under Attempts
+	if err := env.GetSessionStore(t).AppendProgress("feature", "Tried sandbox keys for "+ball.ID+", rejected\n"); err != nil {
+		t.Fatalf("Failed to append progress: %v", err)
+	}
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+	record := session.NewAgentRunRecord("feature", env.ProjectDir, time.Now().Add(-time.Hour))
+	record.SetBlocked(3, "missing STRIPE_KEY", 0, 1, 1)
+	if err := historyStore.AppendRecord(record); err != nil {
+		t.Fatalf("Failed to append history: %v", err)
+	}
+
+	marker := filepath.Join(t.TempDir(), "notified")
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	config, err := session.LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	config.EscalationNotifyCommand = `echo "$JUGGLE_BALL $JUGGLE_OWNER" > ` + marker + ` && head -1 >> ` + marker
+	if err := config.SaveWithOptions(opts); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "escalate", ball.ID, "--owner", "alice", "--notify")
+	if !strings.Contains(output, "Escalated ball") || !strings.Contains(output, "Notified") {
+		t.Errorf("Expected escalation summary, got: %s", output)
+	}
+
+	escalated := env.AssertBallExists(t, ball.ID)
+	if !escalated.IsEscalated() || escalated.Escalation.Owner != "alice" || !escalated.Escalation.Notified {
+		t.Fatalf("Expected ball to be escalated to alice and notified, got %+v", escalated.Escalation)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.ProjectDir, ".juggle", "escalations", escalated.Escalation.File))
+	if err != nil {
+		t.Fatalf("Expected escalation package: %v", err)
+	}
+	pkg := string(data)
+	for _, want := range []string{
+		"# Blocked: Wire up payments",
+		"> needs a Stripe test API key",
+		"Stripe integration for checkout",
+		"1. Charges succeed in test mode",
+		"session `feature`: blocked after 3 iteration(s) (missing STRIPE_KEY)",
+		"Tried sandbox keys for " + ball.ID,
+	} {
+		if !strings.Contains(pkg, want) {
+			t.Errorf("Expected package to contain %q, got:\n%s", want, pkg)
+		}
+	}
+
+	notified, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("Expected notify command to run: %v", err)
+	}
+	if want := ball.ID + " alice\n# Blocked: Wire up payments\n"; string(notified) != want {
+		t.Errorf("Expected notification %q, got %q", want, notified)
+	}
+
+	// Agents must not pick up the ball, even when it's targeted
+	if _, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "feature", false, ball.ShortID()); err == nil || !strings.Contains(err.Error(), "escalated") {
+		t.Errorf("Expected targeting an escalated ball to fail, got: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "escalate", ball.ID, "--resolve", "--note", "Key is in the team vault")
+	if !strings.Contains(output, "Resolved escalation") {
+		t.Errorf("Expected resolution message, got: %s", output)
+	}
+	resolved := env.AssertBallExists(t, ball.ID)
+	if resolved.IsEscalated() || resolved.State != session.StatePending {
+		t.Errorf("Expected resolved ball to be pending and not escalated, got %s %+v", resolved.State, resolved.Escalation)
+	}
+	if !strings.Contains(resolved.Context, "Escalation resolved: Key is in the team vault") {
+		t.Errorf("Expected resolution note in context, got: %s", resolved.Context)
+	}
+
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "escalate", ball.ID, "--resolve")
+	if exitCode == 0 || !strings.Contains(output, "is not escalated") {
+		t.Errorf("Expected resolving twice to fail, got (%d): %s", exitCode, output)
+	}
+}
+
+// TestEscalateGitHubIssue tests creating a GitHub issue from an escalation package
+func TestEscalateGitHubIssue(t *testing.T) {
+	originalRunner := cli.GhRunnerInstance
+	mockRunner := &MockGhRunner{Output: []byte("Creating issue in acme/app\n\nhttps://github.com/acme/app/issues/42\n")}
+	cli.GhRunnerInstance = mockRunner
+	defer func() { cli.GhRunnerInstance = originalRunner }()
+
+	ball := &session.Ball{ID: "app-a1b2c3d4", Title: "Wire up payments"}
+	url, err := cli.CreateEscalationIssue("acme/app", ball, "# Blocked: Wire up payments\n")
+	if err != nil {
+		t.Fatalf("CreateEscalationIssue failed: %v", err)
+	}
+	if url != "https://github.com/acme/app/issues/42" {
+		t.Errorf("Expected issue URL, got %q", url)
+	}
+
+	args := strings.Join(mockRunner.Args, " ")
+	if !strings.Contains(args, "issue create --repo acme/app --title Blocked: Wire up payments") {
+		t.Errorf("Unexpected gh args: %v", mockRunner.Args)
+	}
+}
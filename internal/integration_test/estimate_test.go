@@ -0,0 +1,192 @@
+package integration_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestEstimate_QueuesSuggestionsForSession tests that estimates for session balls land in the review queue
+func TestEstimate_QueuesSuggestionsForSession(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "feature", "Feature work")
+	store := env.GetStore(t)
+
+	ball1 := env.CreateBall(t, "Rename a variable", session.PriorityLow)
+	ball1.Tags = []string{"feature"}
+	if err := store.UpdateBall(ball1); err != nil {
+		t.Fatalf("Failed to update ball1: %v", err)
+	}
+	ball2 := env.CreateBall(t, "Redesign the storage layer", session.PriorityHigh)
+	ball2.Tags = []string{"feature"}
+	if err := store.UpdateBall(ball2); err != nil {
+		t.Fatalf("Failed to update ball2: %v", err)
+	}
+	outside := env.CreateBall(t, "Not in session", session.PriorityMedium)
+
+	output := fmt.Sprintf(`Looked at the code.
+<estimate ball="%s" complexity="low" model_size="small" iterations="1">Single rename</estimate>
+<estimate ball="%s" complexity="high" model_size="large" iterations="5">Touches every store method</estimate>
+<estimate ball="%s" complexity="low" model_size="small" iterations="1">Not requested</estimate>`, ball1.ID, ball2.ID, outside.ID)
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: output})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	estimates, err := cli.EstimateBallsForTest(env.ProjectDir, "feature")
+	if err != nil {
+		t.Fatalf("EstimateBalls failed: %v", err)
+	}
+	if len(estimates) != 2 {
+		t.Fatalf("Expected 2 estimates (unrequested ball ignored), got %d", len(estimates))
+	}
+
+	// Runner should be called read-only
+	if len(mock.Calls) != 1 {
+		t.Fatalf("Expected 1 runner call, got %d", len(mock.Calls))
+	}
+	if mock.Calls[0].Permission != agent.PermissionPlan {
+		t.Errorf("Expected plan permission, got %s", mock.Calls[0].Permission)
+	}
+	if !strings.Contains(mock.Calls[0].Prompt, ball1.ID) || strings.Contains(mock.Calls[0].Prompt, outside.ID) {
+		t.Error("Expected prompt to contain only the session's balls")
+	}
+
+	// Balls are untouched until accepted
+	env.AssertBallExists(t, ball1.ID)
+	reloaded, _ := store.GetBallByID(ball1.ID)
+	if reloaded.ModelSize != "" {
+		t.Errorf("Expected model size unchanged before review, got %s", reloaded.ModelSize)
+	}
+
+	estimateStore, err := session.NewEstimateStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create estimate store: %v", err)
+	}
+	queued, err := estimateStore.LoadEstimates()
+	if err != nil {
+		t.Fatalf("Failed to load estimates: %v", err)
+	}
+	if len(queued) != 2 {
+		t.Fatalf("Expected 2 queued estimates, got %d", len(queued))
+	}
+
+	// Accepting applies the model size and removes it from the queue
+	estimate, _ := estimateStore.GetEstimate(ball2.ID)
+	session.ApplyEstimate(reloaded, estimate)
+	if reloaded.ModelSize != session.ModelSizeLarge {
+		t.Errorf("Expected large model size after applying, got %s", reloaded.ModelSize)
+	}
+	if err := estimateStore.RemoveEstimate(ball2.ID); err != nil {
+		t.Fatalf("Failed to remove estimate: %v", err)
+	}
+	queued, _ = estimateStore.LoadEstimates()
+	if len(queued) != 1 || queued[0].BallID != ball1.ID {
+		t.Errorf("Expected only ball1's estimate to remain, got %v", queued)
+	}
+}
+
+// TestEstimate_ReplacesExistingEstimate tests that re-estimating a ball replaces its queued estimate
+func TestEstimate_ReplacesExistingEstimate(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	ball := env.CreateBall(t, "Fix the bug", session.PriorityMedium)
+
+	agent.SetRunner(agent.NewMockRunner(
+		&agent.RunResult{Output: fmt.Sprintf(`<estimate ball="%s" complexity="low" model_size="small">first</estimate>`, ball.ID)},
+		&agent.RunResult{Output: fmt.Sprintf(`<estimate ball="%s" complexity="medium" model_size="medium">second</estimate>`, ball.ID)},
+	))
+	defer agent.ResetRunner()
+
+	if _, err := cli.EstimateBallsForTest(env.ProjectDir, ball.ShortID()); err != nil {
+		t.Fatalf("First estimate failed: %v", err)
+	}
+	if _, err := cli.EstimateBallsForTest(env.ProjectDir, ball.ShortID()); err != nil {
+		t.Fatalf("Second estimate failed: %v", err)
+	}
+
+	estimateStore, _ := session.NewEstimateStore(env.ProjectDir)
+	queued, _ := estimateStore.LoadEstimates()
+	if len(queued) != 1 {
+		t.Fatalf("Expected 1 queued estimate, got %d", len(queued))
+	}
+	if queued[0].Rationale != "second" || queued[0].ModelSize != session.ModelSizeMedium {
+		t.Errorf("Expected second estimate to replace first, got %+v", queued[0])
+	}
+}
+
+// TestEstimate_ParseSkipsInvalidEntries tests parsing tolerates malformed estimate lines
+func TestEstimate_ParseSkipsInvalidEntries(t *testing.T) {
+	output := `<estimate ball="a-1" complexity="huge" model_size="small">bad complexity</estimate>
+<estimate complexity="low">missing ball</estimate>
+<estimate ball="a-2" complexity="Medium" model_size="gigantic" iterations="-3">bad size</estimate>`
+
+	estimates := cli.ParseEstimateOutputForTest(output)
+	if len(estimates) != 1 {
+		t.Fatalf("Expected 1 valid estimate, got %d", len(estimates))
+	}
+	e := estimates[0]
+	if e.BallID != "a-2" || e.Complexity != session.ComplexityMedium {
+		t.Errorf("Unexpected estimate: %+v", e)
+	}
+	if e.ModelSize != "" || e.Iterations != 0 {
+		t.Errorf("Expected invalid model size and iterations to be dropped, got %+v", e)
+	}
+}
+
+// TestEstimate_AcceptKeepsSizing tests that accepting an estimate keeps its
+// model size, complexity and iterations on the ball, adjusted by the flags
+func TestEstimate_AcceptKeepsSizing(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	store := env.GetStore(t)
+	asIs := env.CreateBall(t, "Rename a variable", session.PriorityLow)
+	adjusted := env.CreateBall(t, "Redesign the storage layer", session.PriorityHigh)
+
+	estimateStore, err := session.NewEstimateStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create estimate store: %v", err)
+	}
+	err = estimateStore.AddEstimates([]*session.Estimate{
+		{BallID: asIs.ID, Complexity: session.ComplexityLow, ModelSize: session.ModelSizeSmall, Iterations: 1},
+		{BallID: adjusted.ID, Complexity: session.ComplexityMedium, ModelSize: session.ModelSizeMedium, Iterations: 2},
+	})
+	if err != nil {
+		t.Fatalf("Failed to queue estimates: %v", err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "estimate", "accept", asIs.ID)
+	runJuggleCommand(t, env.ProjectDir, "estimate", "accept", adjusted.ID,
+		"--model-size", "large", "--complexity", "high", "--iterations", "5")
+
+	reloaded, _ := store.GetBallByID(asIs.ID)
+	if reloaded.ModelSize != session.ModelSizeSmall || reloaded.Estimate == nil ||
+		reloaded.Estimate.Complexity != session.ComplexityLow || reloaded.Estimate.Iterations != 1 {
+		t.Errorf("Expected small/low/1 on the ball, got %s %+v", reloaded.ModelSize, reloaded.Estimate)
+	}
+	reloaded, _ = store.GetBallByID(adjusted.ID)
+	if reloaded.ModelSize != session.ModelSizeLarge || reloaded.Estimate == nil ||
+		reloaded.Estimate.Complexity != session.ComplexityHigh || reloaded.Estimate.Iterations != 5 {
+		t.Errorf("Expected the adjusted large/high/5 on the ball, got %s %+v", reloaded.ModelSize, reloaded.Estimate)
+	}
+	if queued, _ := estimateStore.LoadEstimates(); len(queued) != 0 {
+		t.Errorf("Expected the queue to be empty after accepting, got %d", len(queued))
+	}
+
+	if err := estimateStore.AddEstimates([]*session.Estimate{{BallID: asIs.ID, Complexity: session.ComplexityLow}}); err != nil {
+		t.Fatalf("Failed to queue estimate: %v", err)
+	}
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "estimate", "accept", asIs.ID, "--complexity", "huge")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 for an invalid complexity, got %d", exitCode)
+	}
+}
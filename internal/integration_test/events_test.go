@@ -0,0 +1,104 @@
+package integration_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// overlayEvent is an event from `juggle events`
+type overlayEvent struct {
+	Type   string `json:"type"`
+	BallID string `json:"ball_id"`
+	Status struct {
+		InProgress []struct {
+			ID         string `json:"id"`
+			Checkpoint string `json:"checkpoint"`
+		} `json:"in_progress"`
+		Counts map[string]int `json:"counts"`
+	} `json:"status"`
+}
+
+// TestEventsSocketStream connects to `juggle events --socket-path` and checks a
+// client gets a snapshot, then ball changes with the in-progress status
+func TestEventsSocketStream(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Overlay ball", session.PriorityHigh)
+
+	// Unix socket paths are short, so keep it out of the test's temp dir
+	socketDir, err := os.MkdirTemp("", "jev")
+	if err != nil {
+		t.Fatalf("Failed to create socket dir: %v", err)
+	}
+	defer os.RemoveAll(socketDir)
+	socketPath := filepath.Join(socketDir, "events.sock")
+
+	runJuggleCommand(t, env.ProjectDir, "--version") // Builds the binary if needed
+	configHome := filepath.Join(env.ProjectDir, "..", "config")
+	cmd := exec.Command(GetJuggleBinaryPath(t), "--config-home", configHome, "events", "--socket-path", socketPath)
+	cmd.Dir = env.ProjectDir
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start juggle events: %v", err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	var conn net.Conn
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if conn, err = net.Dial("unix", socketPath); err == nil {
+			break
+		}
+	}
+	if conn == nil {
+		t.Fatalf("Failed to connect to the events socket: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	events := bufio.NewScanner(conn)
+	events.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	next := func() overlayEvent {
+		t.Helper()
+		if !events.Scan() {
+			t.Fatalf("Expected another event: %v", events.Err())
+		}
+		var event overlayEvent
+		if err := json.Unmarshal(events.Bytes(), &event); err != nil {
+			t.Fatalf("Expected a JSON event, got %q: %v", events.Text(), err)
+		}
+		return event
+	}
+
+	snapshot := next()
+	if snapshot.Type != "snapshot" || snapshot.Status.Counts["pending"] != 1 || len(snapshot.Status.InProgress) != 0 {
+		t.Fatalf("Expected a snapshot with one pending ball, got %+v", snapshot)
+	}
+
+	if _, code := runJuggleCommandWithError(t, env.ProjectDir, "events", "--socket-path", socketPath); code != 4 {
+		t.Errorf("Expected a validation error serving on a socket in use, got exit code %d", code)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ShortID(), "--state", "in_progress", "--checkpoint", "Halfway")
+	for {
+		event := next()
+		if event.Type != "ball_changed" || event.BallID != ball.ID {
+			continue
+		}
+		if len(event.Status.InProgress) != 1 || event.Status.InProgress[0].ID != ball.ID || event.Status.InProgress[0].Checkpoint != "Halfway" {
+			t.Errorf("Expected the ball in progress with its checkpoint, got %+v", event.Status)
+		}
+		break
+	}
+}
@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestExitCodes tests that failures exit with their documented codes
+func TestExitCodes(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Exit code ball", session.PriorityMedium)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantExit int
+	}{
+		{"ball not found", []string{"show", "does-not-exist"}, cli.ExitNotFound},
+		{"session not found", []string{"sessions", "show", "does-not-exist"}, cli.ExitNotFound},
+		{"invalid priority", []string{"update", ball.ID, "--priority", "extreme"}, cli.ExitValidation},
+		{"unknown flag", []string{"update", ball.ID, "--no-such-flag"}, cli.ExitUsage},
+		{"missing argument", []string{"show"}, cli.ExitUsage},
+		{"unknown operation", []string{ball.ID, "frobnicate"}, cli.ExitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, tt.args...)
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d (output: %s)", exitCode, tt.wantExit, output)
+			}
+			if !strings.Contains(output, "Error: ") {
+				t.Errorf("expected human-readable error, got: %s", output)
+			}
+		})
+	}
+}
+
+// TestExitCodes_FailingTestsGate tests that refusing completion is a validation error
+func TestExitCodes_FailingTestsGate(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Gated ball", session.PriorityMedium)
+
+	runJuggleCommand(t, env.ProjectDir, "tests", "record", ball.ID, "fail")
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	if exitCode != cli.ExitValidation {
+		t.Errorf("exit code = %d, want %d (output: %s)", exitCode, cli.ExitValidation, output)
+	}
+}
+
+// TestJSONErrorOutput tests that --json failures print a structured error and exit nonzero
+func TestJSONErrorOutput(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "JSON error ball", session.PriorityMedium)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode string
+		wantExit int
+	}{
+		{"global flag", []string{"--json", "show", "does-not-exist"}, cli.CodeNotFound, cli.ExitNotFound},
+		{"local flag", []string{"show", "does-not-exist", "--json"}, cli.CodeNotFound, cli.ExitNotFound},
+		{"update validation", []string{"update", ball.ID, "--priority", "extreme", "--json"}, cli.CodeValidation, cli.ExitValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, tt.args...)
+			if exitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d (output: %s)", exitCode, tt.wantExit, output)
+			}
+
+			var resp struct {
+				Error    string `json:"error"`
+				Code     string `json:"code"`
+				ExitCode int    `json:"exit_code"`
+				Hint     string `json:"hint"`
+			}
+			if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &resp); err != nil {
+				t.Fatalf("expected a single JSON error object, got: %s", output)
+			}
+			if resp.Code != tt.wantCode || resp.ExitCode != tt.wantExit || resp.Error == "" {
+				t.Errorf("unexpected error response: %+v", resp)
+			}
+		})
+	}
+}
+
+// TestJSONErrorOutput_Hint tests that hints are included in JSON errors
+func TestJSONErrorOutput_Hint(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+
+	output, _ := runJuggleCommandWithError(t, env.ProjectDir, "--json", "watch", "list", "does-not-exist")
+	if !strings.Contains(output, `"hint":"use --all to search all projects"`) {
+		t.Errorf("expected --all hint in JSON error, got: %s", output)
+	}
+}
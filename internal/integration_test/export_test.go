@@ -1909,3 +1909,137 @@ func writeBallForAgentTest(buf *strings.Builder, ball *session.Ball) {
 		buf.WriteString("Tags: " + strings.Join(ball.Tags, ", ") + "\n")
 	}
 }
+
+// TestExportTemplateFlag tests rendering an export through --template
+func TestExportTemplateFlag(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateBall(t, "Ship [beta] release", session.PriorityHigh)
+
+	templatePath := filepath.Join(env.TempDir, "report.tmpl")
+	if err := os.WriteFile(templatePath, []byte("{{range .Balls}}* {{md .Title}} ({{.Priority}})\n{{end}}"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "export", "--template", templatePath)
+	if !strings.Contains(output, `* Ship \[beta\] release (high)`) {
+		t.Errorf("Expected rendered template output, got: %s", output)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "export", "--template", templatePath, "--format", "csv"); exitCode != cli.ExitUsage {
+		t.Errorf("Expected usage exit code when combining --template and --format, got %d", exitCode)
+	}
+}
+
+// TestExportImportCSVRoundTrip tests bulk-editing balls through an exported
+// CSV and importing it back
+func TestExportImportCSVRoundTrip(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Auth work")
+	env.CreateSession(t, "billing", "Billing work")
+	store := env.GetStore(t)
+
+	login := env.CreateBall(t, "Add login, with SSO", session.PriorityMedium)
+	login.Tags = []string{"auth", "backend"}
+	if err := store.UpdateBall(login); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	weekly := env.CreateBall(t, "Rotate keys", session.PriorityLow)
+	recurrence, err := session.NewRecurrence("", "7d", time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create recurrence: %v", err)
+	}
+	weekly.Recurrence = recurrence
+	if err := store.UpdateBall(weekly); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	csvPath := filepath.Join(env.TempDir, "balls.csv")
+	runJuggleCommand(t, env.ProjectDir, "export", "--format", "csv", "--output", csvPath)
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+	if strings.Join(records[0], ",") != "id,title,state,priority,tags,due,session" || len(records) != 3 {
+		t.Fatalf("Unexpected export:\n%s", data)
+	}
+	if strings.Join(records[1], "|") != login.ID+"|Add login, with SSO|pending|medium|backend||auth" {
+		t.Errorf("Unexpected row for the login ball: %v", records[1])
+	}
+	if records[2][5] != weekly.Recurrence.DueAt.Local().Format("2006-01-02 15:04") {
+		t.Errorf("Expected the recurring ball's due date, got %v", records[2])
+	}
+
+	// Importing the unedited export changes nothing
+	output := runJuggleCommand(t, env.ProjectDir, "import", csvPath)
+	if !strings.Contains(output, "0 created, 0 updated, 2 unchanged") {
+		t.Errorf("Expected an unchanged round trip, got: %s", output)
+	}
+
+	// Edit in a "spreadsheet": move login to billing and block it, push the
+	// due date, and add a new ball
+	records[1][3] = "urgent"
+	records[1][2] = "blocked"
+	records[1][6] = "billing"
+	records[2][5] = "2030-01-15"
+	records = append(records, []string{"", "Write runbook", "", "high", "docs;ops", "", "auth"})
+	var buf strings.Builder
+	writer := csv.NewWriter(&buf)
+	writer.WriteAll(records)
+	if err := os.WriteFile(csvPath, []byte(buf.String()), 0644); err != nil {
+		t.Fatalf("Failed to write edited CSV: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "import", csvPath)
+	if !strings.Contains(output, "1 created, 2 updated, 0 unchanged") {
+		t.Errorf("Expected the edits applied, got: %s", output)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	byTitle := make(map[string]*session.Ball)
+	for _, ball := range balls {
+		byTitle[ball.Title] = ball
+	}
+	got := byTitle["Add login, with SSO"]
+	if got.Priority != session.PriorityUrgent || got.State != session.StateBlocked || strings.Join(got.Tags, ",") != "backend,billing" {
+		t.Errorf("Expected login moved to billing, urgent and blocked, got %s %s %v", got.Priority, got.State, got.Tags)
+	}
+	if due := byTitle["Rotate keys"].Recurrence.DueAt.Local(); due.Format("2006-01-02") != "2030-01-15" {
+		t.Errorf("Expected the due date pushed, got %v", due)
+	}
+	created := byTitle["Write runbook"]
+	if created == nil || created.Priority != session.PriorityHigh || created.State != session.StatePending || strings.Join(created.Tags, ",") != "docs,ops,auth" {
+		t.Errorf("Expected the new ball created, got %+v", created)
+	}
+
+	// TSV round-trips the same way
+	tsvPath := filepath.Join(env.TempDir, "balls.tsv")
+	runJuggleCommand(t, env.ProjectDir, "export", "--format", "tsv", "--output", tsvPath)
+	output = runJuggleCommand(t, env.ProjectDir, "import", tsvPath)
+	if !strings.Contains(output, "0 created, 0 updated, 3 unchanged") {
+		t.Errorf("Expected an unchanged TSV round trip, got: %s", output)
+	}
+
+	// A bad row leaves every ball as it was
+	if err := os.WriteFile(csvPath, []byte("id,title,priority\n"+login.ID+",Renamed,low\n,Another,whenever\n"), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "import", csvPath)
+	if exitCode != cli.ExitValidation || !strings.Contains(output, "line 3: invalid priority: whenever") {
+		t.Errorf("Expected a validation error for line 3, got %d: %s", exitCode, output)
+	}
+	if ball, _ := store.GetBallByID(login.ID); ball.Title != "Add login, with SSO" {
+		t.Errorf("Expected no balls written after a bad row, got title %q", ball.Title)
+	}
+}
@@ -0,0 +1,53 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestFocusedBalls tests that focused balls lead the agent prompt whatever
+// their priority, and that the flag clears when the ball is done
+func TestFocusedBalls(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+	store := env.GetStore(t)
+	urgent := env.CreateInProgressBall(t, "Fix outage", session.PriorityUrgent)
+	urgent.Tags = []string{"feature"}
+	focused := env.CreateBall(t, "Tidy README", session.PriorityLow)
+	focused.Tags = []string{"feature"}
+	focused.Focused = true
+	for _, ball := range []*session.Ball{urgent, focused} {
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	prompt, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "feature", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	if !strings.Contains(prompt, "Balls marked FOCUS were flagged by a human") {
+		t.Errorf("Expected focus emphasis in prompt, got:\n%s", prompt)
+	}
+	focusedIdx := strings.Index(prompt, "## "+focused.ID+" [pending] (priority: low) (FOCUS)")
+	urgentIdx := strings.Index(prompt, "## "+urgent.ID+" [")
+	if focusedIdx == -1 || urgentIdx == -1 || focusedIdx > urgentIdx {
+		t.Errorf("Expected the focused ball listed before the urgent one, got:\n%s", prompt)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "show", focused.ID)
+	if !strings.Contains(output, "Focused:") {
+		t.Errorf("Expected focus in show output, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "update", focused.ID, "--state", "complete")
+	if reloaded := env.AssertBallExists(t, focused.ID); reloaded.Focused {
+		t.Error("Expected focus cleared when the ball completed")
+	}
+}
@@ -0,0 +1,163 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestHooks tests configuring hooks and running them on ball and agent events
+func TestHooks(t *testing.T) {
+	skipIfNoClaudeCLI(t)
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "nightly", "Overnight run")
+	sessionStore := env.GetSessionStore(t)
+
+	var mu sync.Mutex
+	var posted []session.HookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload session.HookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		posted = append(posted, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	for _, args := range [][]string{
+		{"on_ball_done", "--command", "true"},
+		{"on_ball_complete"},
+		{"on_ball_complete", "--command", "true", "--url", server.URL},
+		{"on_ball_complete", "--url", "ftp://example.com"},
+	} {
+		if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, append([]string{"config", "hooks", "add"}, args...)...); exitCode != 4 {
+			t.Errorf("Expected hook %v to be rejected, got %d", args, exitCode)
+		}
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "config", "hooks", "add", "on_ball_complete", "--command", `cat >> hook-events.jsonl; echo >> hook-events.jsonl`)
+	runJuggleCommand(t, env.ProjectDir, "config", "hooks", "add", "on_agent_blocked", "--url", server.URL)
+	runJuggleCommand(t, env.ProjectDir, "config", "hooks", "add", "on_agent_finished", "--command", `echo "$JUGGLE_EVENT $JUGGLE_RUN_RESULT" >> hook-events.txt`)
+
+	output := runJuggleCommand(t, env.ProjectDir, "config", "hooks")
+	if !strings.Contains(output, "on_agent_blocked:") || !strings.Contains(output, "POST "+server.URL) {
+		t.Errorf("Expected the hooks listed, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "config", "hooks", "test", "on_agent_blocked")
+	if !strings.Contains(output, "✓ POST "+server.URL) || len(posted) != 1 || posted[0].Text != "juggle: test of the on_agent_blocked hook" {
+		t.Errorf("Expected a test payload posted, got %+v: %s", posted, output)
+	}
+
+	// Completing a ball runs on_ball_complete
+	ball := env.CreateBall(t, "Ship the release", session.PriorityHigh)
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	data, err := os.ReadFile(filepath.Join(env.ProjectDir, "hook-events.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected the hook to write its payload: %v", err)
+	}
+	var payload session.HookPayload
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &payload); err != nil {
+		t.Fatalf("Failed to parse payload: %v\n%s", err, data)
+	}
+	if payload.Event != session.HookBallComplete || payload.Ball == nil || payload.Ball.ID != ball.ID || !strings.Contains(payload.Text, "Ship the release") {
+		t.Errorf("Unexpected ball payload: %+v", payload)
+	}
+
+	// An agent run that ends blocked runs on_agent_blocked and on_agent_finished
+	blocked := env.CreateInProgressBall(t, "Rotate keys", session.PriorityMedium)
+	blocked.Tags = []string{"nightly"}
+	if err := env.GetStore(t).UpdateBall(blocked); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	agent.SetRunner(&outputProgressUpdatingMockRunner{
+		mock: agent.NewMockRunner(&agent.RunResult{
+			Output:        "<promise>BLOCKED: needs production keys</promise>",
+			Blocked:       true,
+			BlockedReason: "needs production keys",
+		}),
+		sessionStore: sessionStore,
+		sessionID:    "nightly",
+	})
+	defer agent.ResetRunner()
+	if _, err := cli.RunAgentLoop(cli.AgentLoopConfig{SessionID: "nightly", ProjectDir: env.ProjectDir, MaxIterations: 3}); err != nil {
+		t.Fatalf("Agent run failed: %v", err)
+	}
+	cli.WaitForHooksForTest()
+
+	mu.Lock()
+	if len(posted) != 2 || posted[1].Event != session.HookAgentBlocked || posted[1].Run == nil || posted[1].Run.BlockedReason != "needs production keys" {
+		t.Errorf("Expected on_agent_blocked posted with the run, got %+v", posted)
+	}
+	mu.Unlock()
+	data, err = os.ReadFile(filepath.Join(env.ProjectDir, "hook-events.txt"))
+	if err != nil || strings.TrimSpace(string(data)) != "on_agent_finished blocked" {
+		t.Errorf("Expected on_agent_finished run, got %q (%v)", data, err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "config", "hooks", "clear", "on_agent_blocked")
+	output = runJuggleCommand(t, env.ProjectDir, "config", "hooks")
+	if strings.Contains(output, "on_agent_blocked") || !strings.Contains(output, "on_ball_complete") {
+		t.Errorf("Expected only on_agent_blocked cleared, got: %s", output)
+	}
+}
+
+// TestHooks_RunInBackground tests that hooks don't hold up the ball update
+// that fired them, and that a hanging command hook is stopped
+func TestHooks_RunInBackground(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	runJuggleCommand(t, env.ProjectDir, "config", "hooks", "add", "on_ball_complete", "--command", "sleep 30")
+	defer cli.SetHookTimeoutForTest(200 * time.Millisecond)()
+
+	store := env.GetStore(t)
+	ball := env.CreateInProgressBall(t, "Ship the release", session.PriorityHigh)
+	ball.State = session.StateComplete
+	started := time.Now()
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("Expected the update not to wait for the hook, took %v", elapsed)
+	}
+
+	// The warning goes to stderr once the hook is stopped
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	previous := os.Stderr
+	os.Stderr = w
+	cli.WaitForHooksForTest()
+	os.Stderr = previous
+	w.Close()
+	var stderr bytes.Buffer
+	io.Copy(&stderr, r)
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Expected the hanging hook to be stopped, took %v", elapsed)
+	}
+	if !strings.Contains(stderr.String(), "on_ball_complete hook failed") || !strings.Contains(stderr.String(), "timed out") {
+		t.Errorf("Expected a timeout warning, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestImportTrelloDryRunThenImport previews a Trello board, then imports it
+func TestImportTrelloDryRunThenImport(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateBall(t, "Dark mode", session.PriorityLow)
+
+	board := `{
+  "name": "Mobile App",
+  "lists": [{"id": "l1", "name": "Doing"}, {"id": "l2", "name": "Blocked"}, {"id": "l3", "name": "Ideas"}],
+  "cards": [
+    {"id": "c1", "shortLink": "aB3", "name": "Push notifications", "idList": "l1", "labels": [{"name": "urgent"}, {"name": "ios"}]},
+    {"id": "c2", "shortLink": "cD4", "name": "App review", "idList": "l2"},
+    {"id": "c3", "shortLink": "eF5", "name": "Dark mode", "idList": "l3"}
+  ],
+  "checklists": [{"idCard": "c1", "checkItems": [{"name": "Register APNs", "pos": 1}]}]
+}`
+	path := filepath.Join(env.TempDir, "board.json")
+	if err := os.WriteFile(path, []byte(board), 0644); err != nil {
+		t.Fatalf("Failed to write board: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "import", path, "--source", "trello", "--dry-run")
+	for _, want := range []string{
+		"Would create session: mobile-app (from \"Mobile App\")",
+		"trello#aB3 - \"Push notifications\"",
+		"→ state in_progress, priority urgent, session mobile-app, tags ios, 1 acceptance criteria",
+		"Skipped: trello#eF5 - \"Dark mode\" (already exists)",
+		"Dry run: 2 would be imported, 1 skipped, 1 new session(s)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in dry run output:\n%s", want, output)
+		}
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil || len(balls) != 1 {
+		t.Fatalf("Expected the dry run to write nothing, got %d balls (%v)", len(balls), err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "import", path, "--source", "trello")
+	if !strings.Contains(output, "Import complete: 2 imported, 1 skipped, 1 new session(s)") {
+		t.Errorf("Unexpected import output:\n%s", output)
+	}
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if sess, err := sessionStore.LoadSession("mobile-app"); err != nil || sess.Description != "Mobile App" {
+		t.Errorf("Expected the board's session to be created, got %+v (%v)", sess, err)
+	}
+
+	balls, err = env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	byTitle := make(map[string]*session.Ball)
+	for _, ball := range balls {
+		byTitle[ball.Title] = ball
+	}
+	push := byTitle["Push notifications"]
+	if push == nil || push.State != session.StateInProgress || push.Priority != session.PriorityUrgent ||
+		strings.Join(push.Tags, ",") != "trello#aB3,ios,mobile-app" || len(push.AcceptanceCriteria) != 1 {
+		t.Errorf("Unexpected imported ball %+v", push)
+	}
+	review := byTitle["App review"]
+	if review == nil || review.State != session.StateBlocked || review.BlockedReason != "Blocked in trello" {
+		t.Errorf("Expected a blocked ball with a reason, got %+v", review)
+	}
+
+	// Importing again skips everything
+	output = runJuggleCommand(t, env.ProjectDir, "import", path, "--source", "trello")
+	if !strings.Contains(output, "0 imported, 3 skipped, 0 new session(s)") {
+		t.Errorf("Expected a repeat import to skip everything, got:\n%s", output)
+	}
+}
+
+// TestImportSourceErrors checks bad flags and unreadable exports
+func TestImportSourceErrors(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	path := filepath.Join(env.TempDir, "tasks.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to write export: %v", err)
+	}
+
+	if output, code := runJuggleCommandWithError(t, env.ProjectDir, "import", path, "--source", "asana"); code == 0 ||
+		!strings.Contains(output, "unknown source: asana") {
+		t.Errorf("Expected an unknown source error, got %d: %s", code, output)
+	}
+	if output, code := runJuggleCommandWithError(t, env.ProjectDir, "import", path, "--source", "jira"); code == 0 ||
+		!strings.Contains(output, "failed to parse Jira export") {
+		t.Errorf("Expected a parse error, got %d: %s", code, output)
+	}
+	if _, code := runJuggleCommandWithError(t, env.ProjectDir, "import", path, "--format", "csv", "--dry-run"); code == 0 {
+		t.Error("Expected --dry-run without --source to be rejected")
+	}
+}
@@ -0,0 +1,113 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestParsePlanMarkdown tests parsing a markdown plan with nested criteria
+func TestParsePlanMarkdown(t *testing.T) {
+	plan := `# Plan
+
+Some intro text that is not a step.
+
+1. **Add the config field**
+   - Persisted in config.json
+   - Shown by juggle config
+2. Wire up the CLI
+- [x] Write the docs
+`
+	steps := cli.ParsePlan(plan)
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d: %+v", len(steps), steps)
+	}
+	if steps[0].Title != "Add the config field" || len(steps[0].Criteria) != 2 || steps[0].Criteria[1] != "Shown by juggle config" {
+		t.Errorf("Unexpected first step: %+v", steps[0])
+	}
+	if steps[1].Title != "Wire up the CLI" || steps[1].Status != cli.PlanStepPending {
+		t.Errorf("Unexpected second step: %+v", steps[1])
+	}
+	if steps[2].Status != cli.PlanStepCompleted {
+		t.Errorf("Expected checked step to be completed, got %+v", steps[2])
+	}
+}
+
+// TestParsePlanClaudeTodos tests parsing Claude's rendered todo list and TodoWrite JSON
+func TestParsePlanClaudeTodos(t *testing.T) {
+	rendered := "⏺ Update Todos\n  ⎿  ☒ Read the existing parser\n     ◼ Add glyph support\n     ☐ Write tests\n"
+	steps := cli.ParsePlan(rendered)
+	if len(steps) != 3 {
+		t.Fatalf("Expected 3 steps, got %d: %+v", len(steps), steps)
+	}
+	want := []string{cli.PlanStepCompleted, cli.PlanStepInProgress, cli.PlanStepPending}
+	for i, step := range steps {
+		if step.Status != want[i] {
+			t.Errorf("Step %d: expected status %s, got %s", i+1, want[i], step.Status)
+		}
+	}
+	if steps[2].Title != "Write tests" {
+		t.Errorf("Expected title 'Write tests', got %q", steps[2].Title)
+	}
+
+	todos := `{"todos": [
+  {"content": "Fix the bug", "status": "completed", "activeForm": "Fixing the bug"},
+  {"content": "Ship it", "status": "pending", "priority": "high"}
+]}`
+	steps = cli.ParsePlan(todos)
+	if len(steps) != 2 || steps[0].Status != cli.PlanStepCompleted || steps[1].Priority != "high" {
+		t.Errorf("Unexpected TodoWrite steps: %+v", steps)
+	}
+}
+
+// TestImportPlan tests importing a plan with chained dependencies and deduplication
+func TestImportPlan(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+	existing := env.CreateBall(t, "Wire up the CLI", session.PriorityMedium)
+
+	planPath := filepath.Join(env.ProjectDir, "plan.md")
+	plan := "1. Add the config field\n   - Persisted in config.json\n2. wire up the CLI.\n3. Write the docs\n"
+	if err := os.WriteFile(planPath, []byte(plan), 0644); err != nil {
+		t.Fatalf("Failed to write plan: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "import", "plan", planPath, "--session", "feature")
+	if !strings.Contains(output, "2 imported, 1 skipped") {
+		t.Errorf("Expected 2 imported and 1 skipped, got: %s", output)
+	}
+
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	byTitle := make(map[string]*session.Ball)
+	for _, ball := range balls {
+		byTitle[ball.Title] = ball
+	}
+
+	first := byTitle["Add the config field"]
+	docs := byTitle["Write the docs"]
+	if first == nil || docs == nil {
+		t.Fatalf("Expected plan steps imported, got %v", byTitle)
+	}
+	if len(first.AcceptanceCriteria) != 1 || first.HasDependencies() || len(first.Tags) != 1 || first.Tags[0] != "feature" {
+		t.Errorf("Unexpected first ball: criteria=%v deps=%v tags=%v", first.AcceptanceCriteria, first.DependsOn, first.Tags)
+	}
+	// The duplicate step is skipped, and the next step depends on the existing ball
+	if len(docs.DependsOn) != 1 || docs.DependsOn[0] != existing.ID {
+		t.Errorf("Expected docs step to depend on existing ball %s, got %v", existing.ID, docs.DependsOn)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "import", "plan", planPath, "--session", "missing")
+	if exitCode == 0 || !strings.Contains(output, "missing") {
+		t.Errorf("Expected unknown session to fail, got (%d): %s", exitCode, output)
+	}
+}
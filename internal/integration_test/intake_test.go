@@ -0,0 +1,218 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+const intakeTestEmail = "From: Ana <ana@example.com>\r\n" +
+	"To: bugs@example.com\r\n" +
+	"Subject: Re: [urgent] Checkout is down\r\n" +
+	"Message-ID: <1234@mail.example.com>\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Paying by card fails with a 500.\r\n" +
+	"\r\n" +
+	"-- \r\n" +
+	"Ana\r\n"
+
+// runJuggleIntakeEmail runs `juggle intake email` with a message on stdin
+func runJuggleIntakeEmail(t *testing.T, env *TestEnv, message string) string {
+	t.Helper()
+
+	// Reuse runJuggleCommand to build the binary if needed
+	runJuggleCommand(t, env.ProjectDir, "--help")
+
+	configHome := filepath.Join(env.ProjectDir, "..", "config")
+	cmd := exec.Command(GetJuggleBinaryPath(t), "--config-home", configHome, "intake", "email")
+	cmd.Dir = env.ProjectDir
+	cmd.Stdin = strings.NewReader(message)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("juggle intake email failed: %v\nOutput: %s", err, output)
+	}
+	return string(output)
+}
+
+// loadInbox returns the items waiting in the project's triage inbox
+func loadInbox(t *testing.T, env *TestEnv) []*session.IntakeItem {
+	t.Helper()
+	inbox, err := session.NewIntakeStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create intake store: %v", err)
+	}
+	items, err := inbox.LoadIntake()
+	if err != nil {
+		t.Fatalf("Failed to load inbox: %v", err)
+	}
+	return items
+}
+
+// TestIntake_ServeEndpoints tests proposing balls by webhook and email over
+// the API, with the intake token limited to those endpoints
+func TestIntake_ServeEndpoints(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	handler, err := cli.NewServeIntakeHandlerForTest(env.ProjectDir, "secret", "drop-box")
+	if err != nil {
+		t.Fatalf("Failed to create API handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	sender := &apiClient{t: t, server: server, token: "drop-box"}
+	owner := &apiClient{t: t, server: server, token: "secret"}
+
+	webhook := map[string]any{
+		"title":    "Disk almost full on db-1",
+		"body":     "92% used",
+		"priority": "high",
+		"tags":     []string{"ops"},
+		"source":   "grafana",
+		"id":       "alert-17",
+	}
+	var resp struct {
+		Item  session.IntakeItem `json:"item"`
+		Added bool               `json:"added"`
+	}
+	sender.decode("POST", "/api/intake", webhook, http.StatusAccepted, &resp)
+	if !resp.Added || resp.Item.Priority != session.PriorityHigh || resp.Item.Source.Name != "grafana" {
+		t.Errorf("Unexpected webhook response: %+v", resp)
+	}
+
+	// A retried webhook is accepted but not added again
+	sender.decode("POST", "/api/intake", webhook, http.StatusAccepted, &resp)
+	if resp.Added {
+		t.Error("Expected the repeated webhook dropped")
+	}
+
+	req, err := http.NewRequest("POST", server.URL+"/api/intake/email?token=drop-box", strings.NewReader(intakeTestEmail))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "message/rfc822")
+	httpResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /api/intake/email failed: %v", err)
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusAccepted {
+		t.Errorf("Expected the email accepted with ?token=, got %d", httpResp.StatusCode)
+	}
+
+	if status, _ := sender.do("POST", "/api/intake", map[string]any{"body": "no title"}); status != http.StatusBadRequest {
+		t.Errorf("Expected a proposal without a title rejected, got %d", status)
+	}
+
+	// The intake token doesn't open the rest of the API
+	if status, _ := sender.do("GET", "/api/intake", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected the intake token refused for listing, got %d", status)
+	}
+	if status, _ := sender.do("POST", "/api/balls", map[string]any{"title": "Sneaky"}); status != http.StatusUnauthorized {
+		t.Errorf("Expected the intake token refused for creating balls, got %d", status)
+	}
+
+	var items []session.IntakeItem
+	owner.decode("GET", "/api/intake", nil, http.StatusOK, &items)
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items in the inbox, got %d", len(items))
+	}
+	email := items[1]
+	if email.Title != "Checkout is down" || email.Priority != session.PriorityUrgent {
+		t.Errorf("Expected the subject's title and priority, got %q (%s)", email.Title, email.Priority)
+	}
+	if email.Source.From != "ana@example.com" || email.Context != "Paying by card fails with a 500." {
+		t.Errorf("Expected the sender and body without signature, got %+v", email)
+	}
+
+	// Nothing is a ball until it's accepted
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 0 {
+		t.Errorf("Expected no balls before triage, got %d", len(balls))
+	}
+}
+
+// TestIntake_ServeDisabled tests that the intake endpoints need --intake
+func TestIntake_ServeDisabled(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	client := newAPIClient(t, env.ProjectDir, "")
+	if status, _ := client.do("POST", "/api/intake", map[string]any{"title": "Hello"}); status != http.StatusNotFound {
+		t.Errorf("Expected no intake endpoint without --intake, got %d", status)
+	}
+}
+
+// TestIntake_EmailAcceptReject tests triaging emailed proposals from the CLI
+func TestIntake_EmailAcceptReject(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "shop", "Shop fixes")
+
+	output := runJuggleIntakeEmail(t, env, intakeTestEmail)
+	if !strings.Contains(output, "Added") {
+		t.Errorf("Expected the email added, got: %s", output)
+	}
+	output = runJuggleIntakeEmail(t, env, intakeTestEmail)
+	if !strings.Contains(output, "Already in the inbox") {
+		t.Errorf("Expected the repeated email dropped, got: %s", output)
+	}
+	spam := strings.Replace(intakeTestEmail, "1234@mail", "5678@mail", 1)
+	spam = strings.Replace(spam, "[urgent] Checkout is down", "Cheap watches", 1)
+	runJuggleIntakeEmail(t, env, spam)
+
+	items := loadInbox(t, env)
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items in the inbox, got %d", len(items))
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "intake", "list")
+	if !strings.Contains(output, "Checkout is down") || !strings.Contains(output, "email from ana@example.com") {
+		t.Errorf("Expected the items and their sources listed, got: %s", output)
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "intake", "accept", items[0].ID, "--session", "missing")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for a missing session, got %d", exitCode)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "intake", "accept")
+	if exitCode != 2 {
+		t.Errorf("Expected exit code 2 without IDs or --all, got %d", exitCode)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "intake", "accept", items[0].ID, "--session", "shop")
+	runJuggleCommand(t, env.ProjectDir, "intake", "reject", items[1].ID)
+
+	if left := loadInbox(t, env); len(left) != 0 {
+		t.Errorf("Expected the inbox emptied, got %d items", len(left))
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 1 {
+		t.Fatalf("Expected only the accepted ball, got %d", len(balls))
+	}
+	ball := balls[0]
+	if ball.Title != "Checkout is down" || ball.Priority != session.PriorityUrgent || ball.State != session.StatePending {
+		t.Errorf("Unexpected ball: %+v", ball)
+	}
+	if !strings.Contains(ball.Context, "Paying by card fails") || !strings.Contains(ball.Context, "Reported via email from ana@example.com") {
+		t.Errorf("Expected the body and source in the context, got %q", ball.Context)
+	}
+	if len(ball.Tags) != 1 || ball.Tags[0] != "shop" {
+		t.Errorf("Expected the session tag, got %v", ball.Tags)
+	}
+}
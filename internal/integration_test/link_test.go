@@ -0,0 +1,93 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestLinkBallToGit tests linking a ball to a branch and collecting the commits that reference it
+func TestLinkBallToGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Add the login page", session.PriorityMedium)
+	other := env.CreateBall(t, "Unrelated", session.PriorityLow)
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "link", ball.ID)
+	if exitCode != 4 || !strings.Contains(output, "not a git repository") {
+		t.Errorf("Expected linking outside git to fail, got %d: %s", exitCode, output)
+	}
+
+	initGitProject(t, env.ProjectDir)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = env.ProjectDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s: %v", args, out, err)
+		}
+	}
+	commit := func(name, message string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(env.ProjectDir, name), []byte(message), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		git("add", name)
+		git("commit", "-q", "-m", message)
+	}
+	git("checkout", "-q", "-b", "feature/login")
+	commit("login.go", "Add the login form\n\njuggle: "+ball.ID)
+
+	output = runJuggleCommand(t, env.ProjectDir, "link", ball.ID)
+	if !strings.Contains(output, "linked to branch feature/login") || !strings.Contains(output, "1 commit(s) reference it") || !strings.Contains(output, "Add the login form") {
+		t.Errorf("Expected the branch and commit, got: %s", output)
+	}
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "link", other.ID, "--branch", "no-such-branch")
+	if exitCode != 3 {
+		t.Errorf("Expected an unknown branch to be rejected, got %d: %s", exitCode, output)
+	}
+
+	// New commits are collected when the ball is shown, from any branch
+	git("checkout", "-q", "-")
+	commit("style.css", "Style the login form ("+ball.ID+")")
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "feature/login, 2 commits") {
+		t.Errorf("Expected the branch and 2 commits in show, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID, "--json")
+	var shown session.Ball
+	if err := json.Unmarshal([]byte(output), &shown); err != nil {
+		t.Fatalf("Failed to parse show --json: %v\n%s", err, output)
+	}
+	if shown.Git == nil || shown.Git.Branch != "feature/login" || len(shown.Git.Commits) != 2 {
+		t.Errorf("Expected the git link in JSON, got %+v", shown.Git)
+	}
+
+	// Collecting commits isn't an undoable change: undo reverts the link itself
+	runJuggleCommand(t, env.ProjectDir, "undo")
+	loaded, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if loaded.Git != nil && loaded.Git.Branch != "" {
+		t.Errorf("Expected undo to remove the branch link, got %+v", loaded.Git)
+	}
+	runJuggleCommand(t, env.ProjectDir, "link", ball.ID, "--branch", "feature/login")
+
+	runJuggleCommand(t, env.ProjectDir, "link", ball.ID, "--clear")
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "no branch, 2 commits") {
+		t.Errorf("Expected the commits kept without a branch, got: %s", output)
+	}
+}
@@ -0,0 +1,69 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestLintTitles tests title rules: warnings on plan, lint titles, and --fix
+func TestLintTitles(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "lint", "titles")
+	if exitCode != 0 || !strings.Contains(output, "No title rules set") {
+		t.Fatalf("Expected no rules to pass with a hint, got %d: %s", exitCode, output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "config", "titles", "set",
+		"--max-length", "40", "--imperative", "--capitalize", "--forbid-prefix", "WIP")
+	output = runJuggleCommand(t, env.ProjectDir, "config", "titles")
+	if !strings.Contains(output, "40") || !strings.Contains(output, "WIP") {
+		t.Errorf("Expected the rules shown, got: %s", output)
+	}
+
+	// Breaking the rules only warns
+	output = runJuggleCommand(t, env.ProjectDir, "plan", "WIP: added login page.", "--non-interactive")
+	if !strings.Contains(output, `Warning: title starts with forbidden prefix "WIP"`) {
+		t.Errorf("Expected a forbidden prefix warning, got: %s", output)
+	}
+	env.CreateBall(t, "Add a title that is much too long to fit the limit", session.PriorityLow)
+	good := env.CreateBall(t, "Fix typo", session.PriorityLow)
+
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "lint", "titles")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4, got %d: %s", exitCode, output)
+	}
+	if !strings.Contains(output, "2 title(s) break the title rules") || strings.Contains(output, good.ShortID()+" ") {
+		t.Errorf("Expected the two bad titles reported, got: %s", output)
+	}
+
+	// --fix normalizes what it can; the long title is left to a person
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "lint", "titles", "--fix")
+	if exitCode != 4 || !strings.Contains(output, "1 title(s) fixed") {
+		t.Errorf("Expected one fix and one remaining title, got %d: %s", exitCode, output)
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	found := false
+	for _, ball := range balls {
+		if ball.Title == "Add login page" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the planned title normalized to %q", "Add login page")
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "config", "titles", "clear")
+	output = runJuggleCommand(t, env.ProjectDir, "lint", "titles")
+	if !strings.Contains(output, "No title rules set") {
+		t.Errorf("Expected the rules cleared, got: %s", output)
+	}
+}
@@ -0,0 +1,129 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestListPagination tests paging, column selection, and JSON lines for
+// juggle list
+func TestListPagination(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	for i := 1; i <= 5; i++ {
+		env.CreateBall(t, fmt.Sprintf("Task %d", i), session.PriorityMedium)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "list", "--limit", "2", "--page", "2")
+	if !strings.Contains(output, "Task 3") || !strings.Contains(output, "Task 4") || strings.Contains(output, "Task 2") || strings.Contains(output, "Task 5") {
+		t.Errorf("Expected tasks 3-4 on page 2, got: %s", output)
+	}
+	if !strings.Contains(output, "Showing 3-4 of 5 (next: --offset 4)") {
+		t.Errorf("Expected page footer, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "list", "--offset", "4", "--columns", "title,state")
+	if !strings.Contains(output, "TITLE") || !strings.Contains(output, "Task 5") || strings.Contains(output, "ID") {
+		t.Errorf("Expected only the title and state columns for task 5, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "list", "--jsonl", "--columns", "title", "--limit", "3")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 JSON lines, got: %s", output)
+	}
+	var row map[string]string
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil || row["title"] != "Task 1" || len(row) != 1 {
+		t.Errorf("Expected {\"title\":\"Task 1\"}, got %s (%v)", lines[0], err)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "list", "--page", "2", "--offset", "1"); exitCode == 0 {
+		t.Error("Expected --page with --offset to be rejected")
+	}
+	if output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "list", "--columns", "id,bogus"); exitCode == 0 || !strings.Contains(output, "unknown column") {
+		t.Errorf("Expected unknown column error, got (%d): %s", exitCode, output)
+	}
+}
+
+// TestArchiveList tests streaming archived balls a page at a time
+func TestArchiveList(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	store := env.GetStore(t)
+	for i := 1; i <= 4; i++ {
+		ball := env.CreateBall(t, fmt.Sprintf("Done %d", i), session.PriorityLow)
+		if err := ball.SetState(session.StateComplete); err != nil {
+			t.Fatalf("Failed to complete ball: %v", err)
+		}
+		if err := store.ArchiveBall(ball); err != nil {
+			t.Fatalf("Failed to archive ball: %v", err)
+		}
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "archive", "list", "--limit", "3")
+	if !strings.Contains(output, "Done 1") || !strings.Contains(output, "Done 3") || strings.Contains(output, "Done 4") {
+		t.Errorf("Expected the first 3 archived balls, got: %s", output)
+	}
+	if !strings.Contains(output, "Showing 1-3 (next: --offset 3)") {
+		t.Errorf("Expected page footer, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "archive", "list", "--jsonl", "--offset", "3")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 JSON line, got: %s", output)
+	}
+	var ball session.Ball
+	if err := json.Unmarshal([]byte(lines[0]), &ball); err != nil || ball.Title != "Done 4" || ball.State != session.StateComplete {
+		t.Errorf("Expected the full archived ball Done 4, got %s (%v)", lines[0], err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "archive", "list", "--page", "3", "--limit", "2")
+	if !strings.Contains(output, "No balls found") {
+		t.Errorf("Expected an empty page, got: %s", output)
+	}
+}
+
+// TestArchiveShowAndUnarchive tests inspecting an archived ball and restoring it
+func TestArchiveShowAndUnarchive(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	store := env.GetStore(t)
+	ball := env.CreateBall(t, "Finished work", session.PriorityMedium)
+	if err := ball.SetState(session.StateComplete); err != nil {
+		t.Fatalf("Failed to complete ball: %v", err)
+	}
+	if err := store.ArchiveBall(ball); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "archive", "show", ball.ID)
+	if !strings.Contains(output, ball.ID) || !strings.Contains(output, "Finished work") {
+		t.Errorf("Expected the archived ball's details, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "archive", "show", ball.ID, "--json")
+	var shown session.Ball
+	if err := json.Unmarshal([]byte(output), &shown); err != nil || shown.ID != ball.ID || shown.State != session.StateComplete {
+		t.Errorf("Expected the archived ball as JSON, got %s (%v)", output, err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "unarchive", ball.ID)
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "archive", "show", ball.ID)
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 once the ball is restored, got %d", exitCode)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "Finished work") {
+		t.Errorf("Expected the restored ball among active balls, got: %s", output)
+	}
+}
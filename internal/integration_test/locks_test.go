@@ -0,0 +1,82 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestLocks_ListAndBreak tests finding a crashed run's lock and breaking
+// it, while a running agent's lock is left alone
+func TestLocks_ListAndBreak(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "crashed", "Left behind")
+	env.CreateSession(t, "busy", "Agent running")
+
+	output := runJuggleCommand(t, env.ProjectDir, "locks", "list")
+	if !strings.Contains(output, "No session locks") {
+		t.Errorf("Expected no locks, got: %s", output)
+	}
+
+	// A run that died without cleaning up, from a PID that no longer exists
+	hostname, _ := os.Hostname()
+	crashedDir := filepath.Join(env.JuggleDir, "sessions", "crashed")
+	info := `{"pid":999999999,"hostname":"` + hostname + `","started_at":"2026-01-02T03:04:05Z"}`
+	if err := os.WriteFile(filepath.Join(crashedDir, "agent.lock"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(crashedDir, "agent.lock.info"), []byte(info), 0644); err != nil {
+		t.Fatalf("Failed to write lock info: %v", err)
+	}
+
+	// This test process stands in for a running agent
+	lock, err := env.GetSessionStore(t).AcquireSessionLock("busy")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	defer lock.Release()
+
+	output = runJuggleCommand(t, env.ProjectDir, "locks", "list")
+	for _, want := range []string{"crashed", "stale", "999999999", "not running", "busy", "active"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in locks list, got: %s", want, output)
+		}
+	}
+
+	var locks []session.SessionLockState
+	output = runJuggleCommand(t, env.ProjectDir, "locks", "list", "--json")
+	if err := json.Unmarshal([]byte(output), &locks); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, output)
+	}
+	if len(locks) != 2 || locks[0].SessionID != "busy" || locks[0].State != session.LockActive {
+		t.Errorf("Unexpected locks: %+v", locks)
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "locks", "break", "busy", "--yes")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 breaking a running agent's lock, got %d", exitCode)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "locks", "break", "nope", "--yes")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for a session without a lock, got %d", exitCode)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "locks", "break", "crashed", "--yes")
+	if !strings.Contains(output, "Broke the lock on session crashed") {
+		t.Errorf("Expected the lock broken, got: %s", output)
+	}
+	if _, err := os.Stat(filepath.Join(crashedDir, "agent.lock.info")); !os.IsNotExist(err) {
+		t.Error("Expected the lock info removed")
+	}
+	crashed, err := env.GetSessionStore(t).AcquireSessionLock("crashed")
+	if err != nil {
+		t.Fatalf("Expected the session lockable again, got %v", err)
+	}
+	crashed.Release()
+}
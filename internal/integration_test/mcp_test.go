@@ -0,0 +1,151 @@
+package integration_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// mcpReply is a JSON-RPC response from `juggle mcp`
+type mcpReply struct {
+	ID     int `json:"id"`
+	Result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Tools           []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	} `json:"result"`
+	Error *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+// runMCP sends messages to `juggle mcp` on stdin and returns its replies by
+// ID, failing if stdout holds anything but JSON-RPC responses
+func runMCP(t *testing.T, env *TestEnv, messages ...string) map[int]mcpReply {
+	t.Helper()
+	runJuggleCommand(t, env.ProjectDir, "--version") // Builds the binary if needed
+	cmd := exec.Command(GetJuggleBinaryPath(t), "--config-home", filepath.Join(env.ProjectDir, "..", "config"), "mcp")
+	cmd.Dir = env.ProjectDir
+	cmd.Stdin = strings.NewReader(strings.Join(messages, "\n") + "\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("juggle mcp failed: %v\nStderr: %s", err, stderr.String())
+	}
+
+	replies := make(map[int]mcpReply)
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var reply mcpReply
+		if err := json.Unmarshal(scanner.Bytes(), &reply); err != nil {
+			t.Fatalf("Expected only JSON-RPC on stdout, got %q: %v", scanner.Text(), err)
+		}
+		replies[reply.ID] = reply
+	}
+	return replies
+}
+
+// mcpCall builds a tools/call request
+func mcpCall(id int, tool string, args map[string]any) string {
+	data, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": tool, "arguments": args},
+	})
+	return string(data)
+}
+
+// TestMCPServerTools drives the MCP server through a client's handshake and
+// its tools for sessions, balls and progress
+func TestMCPServerTools(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	existing := env.CreateBall(t, "Existing ball", session.PriorityLow)
+
+	replies := runMCP(t, env,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		mcpCall(3, "create_session", map[string]any{"id": "auth", "description": "Authentication", "context": "Use OAuth"}),
+		mcpCall(4, "create_ball", map[string]any{"title": "Login form", "session": "auth", "priority": "high"}),
+		mcpCall(5, "update_ball", map[string]any{"id": existing.ShortID(), "state": "in_progress", "tags": []string{"auth"}}),
+		mcpCall(6, "append_progress", map[string]any{"session": "auth", "text": "Started on login"}),
+		mcpCall(7, "list_balls", map[string]any{"session": "auth", "states": []string{"in_progress"}}),
+		mcpCall(8, "update_ball", map[string]any{"id": existing.ID, "state": "blocked"}),
+		mcpCall(9, "show_ball", map[string]any{"id": "nope-99"}),
+		`{"jsonrpc":"2.0","id":10,"method":"resources/list"}`,
+		`not json`,
+	)
+
+	if got := replies[1].Result.ProtocolVersion; got != "2025-03-26" {
+		t.Errorf("Expected the client's protocol version, got %q", got)
+	}
+	var tools []string
+	for _, tool := range replies[2].Result.Tools {
+		tools = append(tools, tool.Name)
+	}
+	if got := strings.Join(tools, ","); got != "list_balls,show_ball,create_ball,update_ball,list_sessions,show_session,append_progress,create_session" {
+		t.Errorf("Unexpected tools: %s", got)
+	}
+	for id := 3; id <= 7; id++ {
+		if replies[id].Result.IsError || len(replies[id].Result.Content) == 0 {
+			t.Fatalf("Expected tool call %d to succeed, got %+v", id, replies[id])
+		}
+	}
+
+	var created session.Ball
+	if err := json.Unmarshal([]byte(replies[4].Result.Content[0].Text), &created); err != nil {
+		t.Fatalf("Failed to decode created ball: %v", err)
+	}
+	if created.Title != "Login form" || created.Priority != session.PriorityHigh || !created.HasTag("auth") {
+		t.Errorf("Unexpected created ball %+v", created)
+	}
+	if !strings.Contains(replies[6].Result.Content[0].Text, "Started on login") {
+		t.Errorf("Expected the progress log back, got %s", replies[6].Result.Content[0].Text)
+	}
+	var listed []session.Ball
+	if err := json.Unmarshal([]byte(replies[7].Result.Content[0].Text), &listed); err != nil {
+		t.Fatalf("Failed to decode ball list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != existing.ID {
+		t.Errorf("Expected only the in-progress auth ball, got %+v", listed)
+	}
+
+	if !replies[8].Result.IsError || !strings.Contains(replies[8].Result.Content[0].Text, "blocked_reason is required") {
+		t.Errorf("Expected a tool error blocking without a reason, got %+v", replies[8])
+	}
+	if !replies[9].Result.IsError {
+		t.Errorf("Expected a tool error for an unknown ball, got %+v", replies[9])
+	}
+	if replies[10].Error == nil || replies[10].Error.Code != -32601 {
+		t.Errorf("Expected method not found for resources/list, got %+v", replies[10])
+	}
+	if replies[0].Error == nil || replies[0].Error.Code != -32700 {
+		t.Errorf("Expected a parse error for invalid JSON, got %+v", replies[0])
+	}
+
+	sessionStore, err := session.NewSessionStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	sess, err := sessionStore.LoadSession("auth")
+	if err != nil || sess.Context != "Use OAuth" {
+		t.Errorf("Expected the auth session with its context, got %+v (%v)", sess, err)
+	}
+}
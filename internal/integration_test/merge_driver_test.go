@@ -0,0 +1,102 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestMergeDriver tests merging diverged balls.jsonl files and reviewing the recorded conflicts
+func TestMergeDriver(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Original title", session.PriorityMedium)
+
+	ballsPath := filepath.Join(env.ProjectDir, ".juggle", "balls.jsonl")
+	base, err := os.ReadFile(ballsPath)
+	if err != nil {
+		t.Fatalf("Failed to read balls: %v", err)
+	}
+
+	writeVersion := func(name string, edit func(b *session.Ball)) string {
+		var b session.Ball
+		if err := json.Unmarshal([]byte(strings.TrimSpace(string(base))), &b); err != nil {
+			t.Fatalf("Failed to parse ball: %v", err)
+		}
+		edit(&b)
+		data, _ := json.Marshal(&b)
+		path := filepath.Join(env.TempDir, name)
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		return path
+	}
+	basePath := filepath.Join(env.TempDir, "base")
+	if err := os.WriteFile(basePath, base, 0644); err != nil {
+		t.Fatalf("Failed to write base: %v", err)
+	}
+	oursPath := writeVersion("ours", func(b *session.Ball) {
+		b.Title = "Our title"
+		b.Tags = append(b.Tags, "ours")
+		b.LastActivity = b.LastActivity.Add(time.Hour)
+	})
+	theirsPath := writeVersion("theirs", func(b *session.Ball) {
+		b.Title = "Their title"
+		b.Tags = append(b.Tags, "theirs")
+		b.Priority = session.PriorityUrgent
+		b.LastActivity = b.LastActivity.Add(2 * time.Hour)
+	})
+
+	// Git passes the working tree file as %A and the repo-relative path as %P
+	oursData, _ := os.ReadFile(oursPath)
+	if err := os.WriteFile(ballsPath, oursData, 0644); err != nil {
+		t.Fatalf("Failed to write ours: %v", err)
+	}
+	output := runJuggleCommand(t, env.ProjectDir, "merge-driver", basePath, ballsPath, theirsPath, ".juggle/balls.jsonl")
+	if !strings.Contains(output, "1 conflict(s)") {
+		t.Errorf("Expected conflict summary, got: %s", output)
+	}
+
+	merged, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load merged ball: %v", err)
+	}
+	if merged.Title != "Their title" || merged.Priority != session.PriorityUrgent {
+		t.Errorf("Expected their newer title and priority, got %q / %s", merged.Title, merged.Priority)
+	}
+	if !ballHasTagName(merged, "ours") || !ballHasTagName(merged, "theirs") {
+		t.Errorf("Expected tags from both sides, got %v", merged.Tags)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "status")
+	if !strings.Contains(output, "1 merge conflict(s)") {
+		t.Errorf("Expected status to surface the conflict, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "merge-driver", "conflicts")
+	if !strings.Contains(output, ball.ID+".title (kept theirs)") || !strings.Contains(output, `"Our title"`) {
+		t.Errorf("Expected conflict details, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "merge-driver", "conflicts", "--clear")
+	output = runJuggleCommand(t, env.ProjectDir, "status")
+	if strings.Contains(output, "merge conflict") {
+		t.Errorf("Expected conflicts cleared, got: %s", output)
+	}
+}
+
+func ballHasTagName(ball *session.Ball, tag string) bool {
+	for _, t := range ball.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestMergeCommand tests folding a duplicate ball into the ball being kept
+func TestMergeCommand(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	keep := env.CreateBall(t, "Add login page", session.PriorityMedium)
+	dup := env.CreateBall(t, "Add the login page", session.PriorityMedium)
+	dependent := env.CreateBall(t, "Add logout", session.PriorityMedium)
+
+	store := env.GetStore(t)
+	dup.AcceptanceCriteria = []string{"Errors shown"}
+	dup.Tags = []string{"ui"}
+	if err := store.UpdateBall(dup); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	dependent.DependsOn = []string{dup.ID}
+	if err := store.UpdateBall(dependent); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "merge", keep.ID, dup.ID)
+	if !strings.Contains(output, "Merged") {
+		t.Errorf("Expected merge confirmation, got: %s", output)
+	}
+
+	if _, err := store.GetBallByID(dup.ID); err == nil {
+		t.Error("Expected duplicate ball to be deleted")
+	}
+	kept, err := store.GetBallByID(keep.ID)
+	if err != nil {
+		t.Fatalf("Failed to load kept ball: %v", err)
+	}
+	if len(kept.AcceptanceCriteria) != 1 || kept.AcceptanceCriteria[0] != "Errors shown" {
+		t.Errorf("Expected criteria to be merged, got %v", kept.AcceptanceCriteria)
+	}
+	if len(kept.Tags) != 1 || kept.Tags[0] != "ui" {
+		t.Errorf("Expected tags to be merged, got %v", kept.Tags)
+	}
+	reloaded, err := store.GetBallByID(dependent.ID)
+	if err != nil {
+		t.Fatalf("Failed to load dependent ball: %v", err)
+	}
+	if len(reloaded.DependsOn) != 1 || reloaded.DependsOn[0] != keep.ID {
+		t.Errorf("Expected dependency to move to kept ball, got %v", reloaded.DependsOn)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "merge", keep.ID, keep.ID); exitCode == 0 {
+		t.Error("Expected merging a ball into itself to fail")
+	}
+}
@@ -0,0 +1,82 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestMilestones tests creating milestones, assigning balls and reporting progress
+func TestMilestones(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	store := env.GetStore(t)
+
+	login := env.CreateBall(t, "Login page", session.PriorityHigh)
+	login.Tags = []string{"auth"}
+	lockout := env.CreateBall(t, "Account lockout", session.PriorityMedium)
+	lockout.Tags = []string{"auth"}
+	docs := env.CreateBall(t, "Release notes", session.PriorityLow)
+	other := env.CreateBall(t, "Later work", session.PriorityLow)
+	for _, ball := range []*session.Ball{login, lockout} {
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "milestone", "create", "v 1"); exitCode != 4 {
+		t.Errorf("Expected an invalid name to be rejected, got %d", exitCode)
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "milestone", "create", "v1.2", "--due", "soon"); exitCode != 4 {
+		t.Errorf("Expected an invalid due date to be rejected, got %d", exitCode)
+	}
+	output := runJuggleCommand(t, env.ProjectDir, "milestone", "create", "v1.2", "--due", "2099-11-30", "-d", "Auth release")
+	if !strings.Contains(output, "Created milestone v1.2 (due Nov 30, 2099)") {
+		t.Errorf("Expected the milestone created, got: %s", output)
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "milestone", "create", "V1.2"); exitCode != 4 {
+		t.Errorf("Expected a duplicate name to be rejected, got %d", exitCode)
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "milestone", "assign", "v9", login.ID); exitCode != 3 {
+		t.Errorf("Expected an unknown milestone to be not found, got %d", exitCode)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "milestone", "assign", "v1.2", login.ID, lockout.ID, docs.ID)
+	runJuggleCommand(t, env.ProjectDir, "update", login.ID, "--state", "complete")
+
+	output = runJuggleCommand(t, env.ProjectDir, "list", "--milestone", "v1.2", "--columns", "id,milestone")
+	if !strings.Contains(output, lockout.ID) || !strings.Contains(output, docs.ID) || strings.Contains(output, other.ID) {
+		t.Errorf("Expected only the milestone's active balls, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "milestone", "report", "v1.2")
+	for _, want := range []string{"1 of 3 ball(s) done (33%), 2 remaining", "auth", "1/2", "(no session)", "0/1", "Account lockout"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the report, got: %s", want, output)
+		}
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "--json", "milestone", "report")
+	var reports []session.MilestoneProgress
+	if err := json.Unmarshal([]byte(output), &reports); err != nil {
+		t.Fatalf("Failed to parse JSON report: %v\n%s", err, output)
+	}
+	if len(reports) != 1 || len(reports[0].Done) != 1 || reports[0].Done[0].ID != login.ID || len(reports[0].Remaining) != 2 {
+		t.Errorf("Expected the archived ball counted as done, got %+v", reports)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "milestone", "unassign", docs.ID)
+	output = runJuggleCommand(t, env.ProjectDir, "milestone")
+	if !strings.Contains(output, "1/2") {
+		t.Errorf("Expected the list to show 1/2 done, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "show", lockout.ID)
+	if !strings.Contains(output, "Milestone:") || !strings.Contains(output, "v1.2") {
+		t.Errorf("Expected show to include the milestone, got: %s", output)
+	}
+}
@@ -0,0 +1,54 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestNextAction tests setting a ball's next action via the CLI and that it
+// leads the ball's block in the agent prompt
+func TestNextAction(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+	ball := env.CreateBall(t, "Fix flaky login test", session.PriorityHigh)
+	ball.Tags = []string{"feature"}
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--next-action", "  Reproduce with -count=50  ")
+	if !strings.Contains(output, "Updated next action: Reproduce with -count=50") {
+		t.Errorf("Expected update confirmation, got: %s", output)
+	}
+	if reloaded := env.AssertBallExists(t, ball.ID); reloaded.NextAction != "Reproduce with -count=50" {
+		t.Fatalf("Expected trimmed next action, got %q", reloaded.NextAction)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "Next Action:") {
+		t.Errorf("Expected next action in show output, got: %s", output)
+	}
+
+	prompt, err := cli.GenerateAgentPromptForTest(env.ProjectDir, "feature", false, "")
+	if err != nil {
+		t.Fatalf("Failed to generate prompt: %v", err)
+	}
+	header := "## " + ball.ID + " ["
+	block := prompt[strings.Index(prompt, header):]
+	nextIdx := strings.Index(block, "Next Action: Reproduce with -count=50")
+	titleIdx := strings.Index(block, "Title: Fix flaky login test")
+	if nextIdx == -1 || titleIdx == -1 || nextIdx > titleIdx {
+		t.Errorf("Expected next action before the title in the ball block, got:\n%s", block)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--next-action", "")
+	if reloaded := env.AssertBallExists(t, ball.ID); reloaded.NextAction != "" {
+		t.Errorf("Expected next action cleared, got %q", reloaded.NextAction)
+	}
+}
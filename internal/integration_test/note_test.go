@@ -0,0 +1,59 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestNote tests adding and listing ball notes, and their inclusion in the
+// ball's agent prompt
+func TestNote(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Fix flaky retries", session.PriorityMedium)
+	other := env.CreateBall(t, "Unrelated work", session.PriorityLow)
+
+	output := runJuggleCommand(t, env.ProjectDir, "note", ball.ID)
+	if !strings.Contains(output, "No notes") {
+		t.Errorf("Expected no notes yet, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "note", ball.ID, "Tried exponential backoff, still flaky")
+	if !strings.Contains(output, "Added note") {
+		t.Errorf("Expected confirmation, got: %s", output)
+	}
+	runJuggleCommand(t, env.ProjectDir, "note", ball.ID, "Suspect", "the", "mock clock")
+
+	output = runJuggleCommand(t, env.ProjectDir, "note", ball.ID)
+	if !strings.Contains(output, "Tried exponential backoff") || !strings.Contains(output, "Suspect the mock clock") {
+		t.Errorf("Expected both notes listed, got: %s", output)
+	}
+
+	var notes []session.BallNote
+	output = runJuggleCommand(t, env.ProjectDir, "note", ball.ID, "--json")
+	if err := json.Unmarshal([]byte(output), &notes); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, output)
+	}
+	if len(notes) != 2 || notes[1].Text != "Suspect the mock clock" || notes[0].CreatedAt.IsZero() {
+		t.Errorf("Unexpected notes: %+v", notes)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "export", "--format", "agent", "--session", "all", "--ball", ball.ID)
+	if !strings.Contains(output, "Notes:") || !strings.Contains(output, "Suspect the mock clock") {
+		t.Errorf("Expected the notes in the agent prompt, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "export", "--format", "agent", "--session", "all", "--ball", other.ID)
+	if strings.Contains(output, "mock clock") {
+		t.Errorf("Expected no notes in another ball's prompt, got: %s", output)
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "note", ball.ID, "  ")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 for an empty note, got %d", exitCode)
+	}
+}
@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// completingRunner completes a ball the way an agent would, then returns
+// the next mock response
+type completingRunner struct {
+	*agent.MockRunner
+	complete func()
+}
+
+func (r *completingRunner) Run(opts agent.RunOptions) (*agent.RunResult, error) {
+	r.complete()
+	return r.MockRunner.Run(opts)
+}
+
+// TestCompletionOwnership tests that a ball tagged in two sessions is owned
+// by the session whose agent run completed it
+func TestCompletionOwnership(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	err := session.UpdateGlobalShellAgentWithOptions(opts, func(shellAgent *session.ShellAgentConfig) {
+		shellAgent.Command = `cat > /dev/null`
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure shell provider: %v", err)
+	}
+	env.CreateSession(t, "auth", "Auth work")
+	env.CreateSession(t, "frontend", "Frontend work")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "auth", "--provider", "shell")
+
+	store := env.GetStore(t)
+	shared := env.CreateBall(t, "Add login form", session.PriorityMedium)
+	shared.Tags = []string{"ui", "auth", "frontend"}
+	if err := store.UpdateBall(shared); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	runAuth := func(complete func(ball *session.Ball)) string {
+		runner := &completingRunner{MockRunner: agent.NewMockRunner(&agent.RunResult{Output: "Done"})}
+		runner.complete = func() {
+			ball, err := store.GetBallByID(shared.ID)
+			if err != nil {
+				t.Fatalf("Failed to load ball: %v", err)
+			}
+			complete(ball)
+		}
+		agent.SetRunner(runner)
+		defer agent.ResetRunner()
+		return captureOutput(func() {
+			if _, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+				SessionID:     "auth",
+				ProjectDir:    env.ProjectDir,
+				MaxIterations: 1,
+			}); err != nil {
+				t.Fatalf("Agent run failed: %v", err)
+			}
+		})
+	}
+
+	// Keep: the completion is recorded, the tags stay, and archived balls are claimed too
+	output := runAuth(func(ball *session.Ball) {
+		ball.MarkComplete("done")
+		if err := store.ArchiveBall(ball); err != nil {
+			t.Fatalf("Failed to archive ball: %v", err)
+		}
+	})
+	if !strings.Contains(output, "completed by auth, not counted as progress in frontend") {
+		t.Errorf("Expected an ownership note, got:\n%s", output)
+	}
+	archived, err := store.LoadArchivedBalls()
+	if err != nil || len(archived) != 1 {
+		t.Fatalf("Expected one archived ball, got %d (%v)", len(archived), err)
+	}
+	if archived[0].CompletedBySession != "auth" || len(archived[0].Tags) != 3 {
+		t.Errorf("Expected completion recorded and tags kept, got %q %v", archived[0].CompletedBySession, archived[0].Tags)
+	}
+
+	// Transfer: the other session's tag is removed
+	if _, err := store.UnarchiveBall(shared.ID); err != nil {
+		t.Fatalf("Failed to unarchive ball: %v", err)
+	}
+	runJuggleCommand(t, env.ProjectDir, "config", "ownership", "set", "transfer")
+	output = runAuth(func(ball *session.Ball) {
+		ball.MarkComplete("done")
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to complete ball: %v", err)
+		}
+	})
+	if !strings.Contains(output, "completed by auth, removed from frontend") {
+		t.Errorf("Expected a transfer note, got:\n%s", output)
+	}
+	ball, err := store.GetBallByID(shared.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if ball.CompletedBySession != "auth" || strings.Join(ball.Tags, ",") != "ui,auth" {
+		t.Errorf("Expected ball owned by auth without the frontend tag, got %q %v", ball.CompletedBySession, ball.Tags)
+	}
+
+	if output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "config", "ownership", "set", "steal"); exitCode != 4 {
+		t.Errorf("Expected an unknown policy to be rejected, got %d: %s", exitCode, output)
+	}
+}
@@ -0,0 +1,90 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestPlanAssist tests the agent proposing context and criteria for a new ball
+func TestPlanAssist(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	if err := session.UpdateProjectAcceptanceCriteria(env.ProjectDir, []string{"go test ./... passes"}); err != nil {
+		t.Fatalf("Failed to set project criteria: %v", err)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "plan", "--assist", "--non-interactive")
+	if exitCode != 4 || !strings.Contains(output, "intent is required") {
+		t.Errorf("Expected --assist without an intent to be rejected, got %d: %s", exitCode, output)
+	}
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "plan", "Lock out accounts", "--assist", "--edit")
+	if exitCode != 4 {
+		t.Errorf("Expected --assist with --edit to be rejected, got %d: %s", exitCode, output)
+	}
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: `Looked around.
+<context>Logins are handled in auth/login.go.
+Failed attempts aren't tracked.</context>
+<criterion>Five failed logins lock the account</criterion>
+<criterion>  A locked login returns 429  </criterion>
+<criterion>Failed logins are logged</criterion>
+<criterion>A test covers lockout</criterion>
+<criterion>failed logins are LOGGED</criterion>`})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	suggestion, err := cli.SuggestPlanForTest(env.ProjectDir, "Lock out accounts", "", []string{"A test covers lockout"})
+	if err != nil {
+		t.Fatalf("Suggest failed: %v", err)
+	}
+	if len(mock.Calls) != 1 || mock.Calls[0].Permission != agent.PermissionPlan {
+		t.Fatalf("Expected one read-only runner call, got %+v", mock.Calls)
+	}
+	prompt := mock.Calls[0].Prompt
+	if !strings.Contains(prompt, "Lock out accounts") || !strings.Contains(prompt, "1. A test covers lockout") || !strings.Contains(prompt, "- go test ./... passes") {
+		t.Errorf("Expected the intent and criteria in the prompt, got:\n%s", prompt)
+	}
+	if suggestion.Context != "Logins are handled in auth/login.go.\nFailed attempts aren't tracked." {
+		t.Errorf("Unexpected context: %q", suggestion.Context)
+	}
+	want := []string{"Five failed logins lock the account", "A locked login returns 429", "Failed logins are logged"}
+	if strings.Join(suggestion.Criteria, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected given and duplicate criteria dropped, got %q", suggestion.Criteria)
+	}
+
+	// Accept the context, edit the first criterion, reject the second,
+	// accept the third with a blank line, then add one
+	input := "a\ne\nTen failed logins lock the account\nr\n\nLockouts expire after 15 minutes\n\n"
+	context, criteria, ok := cli.ReviewPlanSuggestionForTest(strings.NewReader(input), "", []string{"A test covers lockout"}, suggestion)
+	if !ok {
+		t.Fatal("Expected the review to finish")
+	}
+	if context != suggestion.Context {
+		t.Errorf("Expected the suggested context, got %q", context)
+	}
+	want = []string{"A test covers lockout", "Ten failed logins lock the account", "Failed logins are logged", "Lockouts expire after 15 minutes"}
+	if strings.Join(criteria, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected criteria after review: %q", criteria)
+	}
+
+	// Rejecting the context keeps what was given; quitting cancels
+	context, _, _ = cli.ReviewPlanSuggestionForTest(strings.NewReader("r\n\n\n\n\n"), "Given context", nil, suggestion)
+	if context != "Given context" {
+		t.Errorf("Expected the given context kept, got %q", context)
+	}
+	if _, _, ok := cli.ReviewPlanSuggestionForTest(strings.NewReader("a\nq\n"), "", nil, suggestion); ok {
+		t.Error("Expected quitting to cancel the review")
+	}
+
+	mock = agent.NewMockRunner(&agent.RunResult{Output: "I couldn't find anything."})
+	agent.SetRunner(mock)
+	if _, err := cli.SuggestPlanForTest(env.ProjectDir, "Lock out accounts", "", nil); err == nil {
+		t.Error("Expected an error when the agent makes no suggestions")
+	}
+}
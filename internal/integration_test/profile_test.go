@@ -0,0 +1,48 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestProfileFlag tests that --profile records store loads to the profile log
+func TestProfileFlag(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateBall(t, "Profile me", session.PriorityMedium)
+
+	output := runJuggleCommand(t, env.ProjectDir, "--profile", "balls")
+	if !strings.Contains(output, "Profile written to") || !strings.Contains(output, "load_balls") {
+		t.Errorf("Expected profile summary, got: %s", output)
+	}
+
+	configHome := filepath.Join(env.ProjectDir, "..", "config")
+	data, err := os.ReadFile(filepath.Join(configHome, ".juggle", "profile.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to read profile log: %v", err)
+	}
+	if !strings.Contains(string(data), `"kind":"store"`) {
+		t.Errorf("Expected store timings in profile log, got: %s", data)
+	}
+}
+
+// TestProfileAddrRequiresProfile tests that --profile-addr is rejected alone
+func TestProfileAddrRequiresProfile(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "--profile-addr", "localhost:0", "balls")
+	if exitCode == 0 {
+		t.Fatalf("Expected error, got: %s", output)
+	}
+	if !strings.Contains(output, "--profile-addr requires --profile") {
+		t.Errorf("Unexpected error output: %s", output)
+	}
+}
@@ -0,0 +1,78 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestProjects_Registry tests registering, naming, disabling and moving
+// projects, and that --all only loads the enabled ones
+func TestProjects_Registry(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	other := filepath.Join(env.TempDir, "other")
+	if err := os.MkdirAll(other, 0755); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	runJuggleCommand(t, env.ProjectDir, "plan", "Local ball", "--non-interactive")
+	runJuggleCommand(t, other, "plan", "Other ball", "--non-interactive")
+
+	output := runJuggleCommand(t, env.ProjectDir, "projects", "add", other, "--name", "side-project")
+	if !strings.Contains(output, "Renamed project") {
+		t.Errorf("Expected the auto-registered project renamed, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "projects", "list")
+	for _, want := range []string{"side-project", "ok", "2 project(s) registered"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in projects list, got: %s", want, output)
+		}
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "--all", "list")
+	if !strings.Contains(output, "Other ball") {
+		t.Errorf("Expected the other project's ball with --all, got: %s", output)
+	}
+	runJuggleCommand(t, env.ProjectDir, "projects", "disable", "side-project")
+	output = runJuggleCommand(t, env.ProjectDir, "--all", "list")
+	if strings.Contains(output, "Other ball") || !strings.Contains(output, "Local ball") {
+		t.Errorf("Expected only the enabled project with --all, got: %s", output)
+	}
+	runJuggleCommand(t, env.ProjectDir, "projects", "enable", "side-project")
+
+	// The other project's directory is renamed
+	moved := filepath.Join(env.TempDir, "renamed")
+	if err := os.Rename(other, moved); err != nil {
+		t.Fatalf("Failed to rename project: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "projects")
+	if !strings.Contains(output, "missing") || !strings.Contains(output, "juggle projects move") {
+		t.Errorf("Expected the renamed project shown missing, got: %s", output)
+	}
+	runJuggleCommand(t, env.ProjectDir, "projects", "move", "side-project", moved)
+
+	var entries []struct {
+		Path        string `json:"path"`
+		DisplayName string `json:"display_name"`
+		Status      string `json:"status"`
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "projects", "--json")
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, output)
+	}
+	if len(entries) != 2 || entries[1].Path != moved || entries[1].DisplayName != "side-project" || entries[1].Status != "ok" {
+		t.Errorf("Unexpected projects after move: %+v", entries)
+	}
+
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "projects", "remove", "nope")
+	if exitCode != 3 {
+		t.Errorf("Expected exit code 3 for an unregistered project, got %d", exitCode)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "projects", "remove", "side-project")
+	if !strings.Contains(output, "Removed project: "+moved) {
+		t.Errorf("Expected the project removed, got: %s", output)
+	}
+}
@@ -0,0 +1,83 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestRecurringBalls tests scheduling a ball, completing it, and catching up a fired schedule
+func TestRecurringBalls(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	review := env.CreateBall(t, "Weekly review", session.PriorityMedium)
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "recur", "set", review.ID, "--every", "7")
+	if exitCode != 4 {
+		t.Errorf("Expected an invalid interval to be rejected, got %d: %s", exitCode, output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "recur", "set", review.ID, "--cron", "0 9 * * MON")
+	if !strings.Contains(output, "recurs cron 0 9 * * MON") || !strings.Contains(output, "Due: Mon") {
+		t.Errorf("Expected the schedule and due time, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "show", review.ID)
+	if !strings.Contains(output, "Recurs:") {
+		t.Errorf("Expected show to include the schedule, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "recur")
+	if !strings.Contains(output, "Weekly review") {
+		t.Errorf("Expected the ball listed, got: %s", output)
+	}
+
+	// Completing it creates the next instance
+	output = runJuggleCommand(t, env.ProjectDir, "update", review.ID, "--state", "complete")
+	if !strings.Contains(output, "Next instance:") {
+		t.Errorf("Expected a next instance, got: %s", output)
+	}
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	var next *session.Ball
+	for _, ball := range balls {
+		if ball.ID == review.ID && ball.IsRecurring() {
+			t.Errorf("Expected the completed ball to stop recurring")
+		}
+		if ball.ID != review.ID && ball.Title == "Weekly review" {
+			next = ball
+		}
+	}
+	if next == nil || next.State != session.StatePending || !next.IsRecurring() {
+		t.Fatalf("Expected a pending recurring instance, got %+v", next)
+	}
+	if next.Recurrence.DueAt.Weekday() != time.Monday || !next.Recurrence.DueAt.After(time.Now().Add(6*24*time.Hour)) {
+		t.Errorf("Expected the instance due the Monday after next, got %v", next.Recurrence.DueAt)
+	}
+
+	// An open ball whose schedule fired gets a fresh instance from recur run
+	store := env.GetStore(t)
+	backups := env.CreateBall(t, "Check backups", session.PriorityLow)
+	backups.Recurrence = &session.Recurrence{Every: "1d", DueAt: time.Now().Add(-50 * time.Hour)}
+	if err := store.UpdateBall(backups); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "recur", "run")
+	if strings.Count(output, "Next instance:") != 1 || !strings.Contains(output, "overdue") {
+		t.Errorf("Expected one overdue instance, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "recur", "run")
+	if !strings.Contains(output, "No recurring balls are due") {
+		t.Errorf("Expected nothing due on a second run, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "recur", "clear", next.ID)
+	output = runJuggleCommand(t, env.ProjectDir, "recur")
+	if strings.Contains(output, "Weekly review") || !strings.Contains(output, "Check backups") {
+		t.Errorf("Expected only the backups ball to recur, got: %s", output)
+	}
+}
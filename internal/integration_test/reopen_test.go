@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestReopenArchivedAndActiveBalls reopens an archived ball with a note and
+// an active completed ball with the ball command syntax
+func TestReopenArchivedAndActiveBalls(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	env.CreateSession(t, "frontend", "Frontend")
+	store := env.GetStore(t)
+
+	// Completed by the auth session's run, which took it out of frontend
+	login := env.CreateBall(t, "Login form", session.PriorityHigh)
+	login.Tags = []string{"auth", "frontend"}
+	login.MarkComplete("Shipped in v2")
+	login.ClaimCompletion("auth", []string{"auth", "frontend"}, true)
+	if err := store.UpdateBall(login); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if err := store.ArchiveBall(login); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "reopen", login.ShortID(), "--note", "Fails on Safari")
+	for _, want := range []string{"Reopened ball: " + login.ID, "Restored from the archive", "Back in sessions: frontend"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	reopened, err := store.GetBallByID(login.ID)
+	if err != nil {
+		t.Fatalf("Expected the ball back in the active balls: %v", err)
+	}
+	if reopened.State != session.StatePending || reopened.CompletedAt != nil || strings.Join(reopened.Tags, ",") != "auth,frontend" ||
+		!strings.Contains(reopened.Context, ": Fails on Safari\nCompletion note was: Shipped in v2") {
+		t.Errorf("Unexpected reopened ball %+v", reopened)
+	}
+	if archived, _ := store.LoadArchivedBalls(); len(archived) != 0 {
+		t.Errorf("Expected the archive to be empty, got %d balls", len(archived))
+	}
+
+	// A completed ball that was never archived is reopened in place
+	docs := env.CreateBall(t, "Write docs", session.PriorityLow)
+	docs.MarkComplete("")
+	if err := store.UpdateBall(docs); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, docs.ShortID(), "reopen", "Missing", "the", "API", "section")
+	if strings.Contains(output, "Restored from the archive") {
+		t.Errorf("Expected an active ball not to come from the archive:\n%s", output)
+	}
+	if got, _ := store.GetBallByID(docs.ID); got == nil || got.State != session.StatePending ||
+		!strings.Contains(got.Context, ": Missing the API section") {
+		t.Errorf("Unexpected reopened ball %+v", got)
+	}
+
+	if output, code := runJuggleCommandWithError(t, env.ProjectDir, "reopen", docs.ID); code != 4 ||
+		!strings.Contains(output, "is not complete") {
+		t.Errorf("Expected a validation error reopening a pending ball, got %d: %s", code, output)
+	}
+	if _, code := runJuggleCommandWithError(t, env.ProjectDir, "reopen", "nope-99"); code != 3 {
+		t.Errorf("Expected an unknown ball to be not found, got exit %d", code)
+	}
+}
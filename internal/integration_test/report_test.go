@@ -0,0 +1,100 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestReportSprint tests grouping a sprint's completed balls by session and
+// tag, telling agent from human completions and listing blockers
+func TestReportSprint(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	store := env.GetStore(t)
+	now := time.Now()
+
+	complete := func(ball *session.Ball, tags []string, created, completed time.Time) {
+		ball.Tags = tags
+		ball.StartedAt = created
+		ball.State = session.StateComplete
+		ball.CompletedAt = &completed
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+	login := env.CreateBall(t, "Login page", session.PriorityHigh)
+	complete(login, []string{"auth", "ui"}, now.Add(-74*time.Hour), now.Add(-48*time.Hour))
+	notes := env.CreateBall(t, "Release notes", session.PriorityLow)
+	complete(notes, []string{"docs"}, now.Add(-80*time.Hour), now.Add(-72*time.Hour))
+	old := env.CreateBall(t, "Old work", session.PriorityMedium)
+	complete(old, []string{"auth"}, now.AddDate(0, 0, -41), now.AddDate(0, 0, -40))
+
+	stuck := env.CreateBall(t, "Payment webhook", session.PriorityMedium)
+	stuck.State = session.StateBlocked
+	stuck.BlockedReason = "waiting on API keys"
+	if err := store.UpdateBall(stuck); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+	agentRun := session.NewAgentRunRecord("auth", env.ProjectDir, now.Add(-49*time.Hour))
+	agentRun.SetComplete(3, 1, 0, 1)
+	agentRun.EndedAt = now.Add(-47 * time.Hour)
+	blockedRun := session.NewAgentRunRecord("auth", env.ProjectDir, now.Add(-25*time.Hour))
+	blockedRun.SetBlocked(1, "needs design review", 0, 0, 1)
+	blockedRun.EndedAt = now.Add(-24 * time.Hour)
+	for _, record := range []*session.AgentRunRecord{agentRun, blockedRun} {
+		if err := historyStore.AppendRecord(record); err != nil {
+			t.Fatalf("Failed to append record: %v", err)
+		}
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "report", "sprint")
+	for _, want := range []string{
+		"# Sprint review:",
+		"**2** ball(s) completed: 1 by agents, 1 by hand",
+		"| auth | 1 | 1 | 0 | 1d 2h |",
+		"| (no session) | 1 | 0 | 1 | 8h |",
+		"## By tag",
+		"| ui | 1 | 1 | 0 |",
+		"`" + login.ShortID() + "` Login page _(high, by agent, 1d 2h)_",
+		"`" + notes.ShortID() + "` Release notes _(low, by human, 8h)_",
+		"Payment webhook: waiting on API keys _(still blocked",
+		"Agent run on auth: needs design review",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the report, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Old work") {
+		t.Errorf("Expected balls completed before the sprint left out, got:\n%s", output)
+	}
+
+	from := now.AddDate(0, 0, -45).Format("2006-01-02")
+	to := now.AddDate(0, 0, -30).Format("2006-01-02")
+	output = runJuggleCommand(t, env.ProjectDir, "--json", "report", "sprint", "--from", from, "--to", to)
+	var report session.SprintReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Failed to parse JSON report: %v\n%s", err, output)
+	}
+	if len(report.Completed) != 1 || report.Completed[0].ID != old.ID || report.CompletedBy[old.ID] != "human" || len(report.Blockers) != 0 {
+		t.Errorf("Expected only the old ball, completed by hand, got %+v", report)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "report", "sprint", "--from", "last week"); exitCode != 4 {
+		t.Errorf("Expected an invalid date to be rejected, got %d", exitCode)
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "report", "sprint", "--from", to, "--to", from); exitCode != 4 {
+		t.Errorf("Expected --from after --to to be rejected, got %d", exitCode)
+	}
+}
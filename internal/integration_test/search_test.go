@@ -0,0 +1,61 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestSearchFullText tests searching contexts, progress and archived balls
+// via the CLI, with the --state and --session filters
+func TestSearchFullText(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	store := env.GetStore(t)
+
+	active := env.CreateBall(t, "Add login page", session.PriorityMedium)
+	active.Context = "Follow the OAuth flow"
+	active.AddTag("auth")
+	if err := store.UpdateBall(active); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	done := env.CreateBall(t, "Spike oauth libraries", session.PriorityLow)
+	done.MarkComplete("")
+	if err := store.ArchiveBall(done); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+	env.CreateSession(t, "auth", "Authentication")
+	if err := env.GetSessionStore(t).AppendProgress("auth", "Decided on OAuth PKCE\n"); err != nil {
+		t.Fatalf("Failed to append progress: %v", err)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "search", "oauth")
+	for _, want := range []string{active.ID, done.ID, "archived", "session auth", "Follow the OAuth flow", "Decided on OAuth PKCE"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in search output, got: %s", want, output)
+		}
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "search", "oauth", "--state", "pending")
+	if !strings.Contains(output, active.ID) || strings.Contains(output, done.ID) || strings.Contains(output, "session auth") {
+		t.Errorf("Expected only the pending ball, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "--json", "search", "oauth", "--session", "auth")
+	var results []*session.SearchResult
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if len(results) != 2 || results[0].Ball == nil || results[0].Ball.ID != active.ID || results[1].Session == nil {
+		t.Errorf("Expected the session's ball and the session, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "search", "oauth", "--project", "no-such-project")
+	if strings.Contains(output, active.ID) {
+		t.Errorf("Expected no results in another project, got: %s", output)
+	}
+}
@@ -0,0 +1,370 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/profile"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// apiClient sends authenticated requests to a test API server
+type apiClient struct {
+	t      *testing.T
+	server *httptest.Server
+	token  string
+}
+
+// do sends a request and returns the status and body
+func (c *apiClient) do(method, path string, body any) (int, []byte) {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatalf("Failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, reader)
+	if err != nil {
+		c.t.Fatalf("Failed to build request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp.StatusCode, data
+}
+
+// decode sends a request, checks its status, and decodes the JSON response into v
+func (c *apiClient) decode(method, path string, body any, wantStatus int, v any) {
+	c.t.Helper()
+	status, data := c.do(method, path, body)
+	if status != wantStatus {
+		c.t.Fatalf("%s %s: expected %d, got %d: %s", method, path, wantStatus, status, data)
+	}
+	if v != nil {
+		if err := json.Unmarshal(data, v); err != nil {
+			c.t.Fatalf("%s %s: failed to decode %s: %v", method, path, data, err)
+		}
+	}
+}
+
+func newAPIClient(t *testing.T, projectDir, token string) *apiClient {
+	handler, err := cli.NewServeHandlerForTest(projectDir, token)
+	if err != nil {
+		t.Fatalf("Failed to create API handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &apiClient{t: t, server: server, token: token}
+}
+
+// TestServeBallsAndSessions tests listing, creating and updating balls and
+// reading and appending session progress over the API
+func TestServeBallsAndSessions(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	env.CreateBall(t, "Untagged chore", session.PriorityLow)
+	client := newAPIClient(t, env.ProjectDir, "secret")
+
+	anonymous := &apiClient{t: t, server: client.server}
+	if status, _ := anonymous.do("GET", "/api/balls", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected requests without the token to be refused, got %d", status)
+	}
+
+	var created session.Ball
+	client.decode("POST", "/api/balls", map[string]any{
+		"title":               "Add login page",
+		"priority":            "high",
+		"session":             "auth",
+		"tags":                []string{"ui"},
+		"acceptance_criteria": []string{"Form renders"},
+	}, http.StatusCreated, &created)
+	if created.ID == "" || created.Priority != session.PriorityHigh || strings.Join(created.Tags, ",") != "ui,auth" ||
+		created.State != session.StatePending || len(created.AcceptanceCriteria) != 1 {
+		t.Errorf("Unexpected created ball %+v", created)
+	}
+
+	var balls []session.Ball
+	client.decode("GET", "/api/balls?session=auth", nil, http.StatusOK, &balls)
+	if len(balls) != 1 || balls[0].ID != created.ID {
+		t.Errorf("Expected only the auth ball, got %+v", balls)
+	}
+	client.decode("GET", "/api/balls", nil, http.StatusOK, &balls)
+	if len(balls) != 2 {
+		t.Errorf("Expected both balls, got %d", len(balls))
+	}
+
+	var apiErr struct {
+		Code string `json:"code"`
+		Exit int    `json:"exit_code"`
+	}
+	client.decode("PATCH", "/api/balls/"+created.ShortID(), map[string]any{"state": "blocked"}, http.StatusBadRequest, &apiErr)
+	if apiErr.Code != "validation" || apiErr.Exit != 4 {
+		t.Errorf("Expected a validation error for blocking without a reason, got %+v", apiErr)
+	}
+	client.decode("POST", "/api/balls", map[string]any{"title": "Orphan", "session": "nope"}, http.StatusNotFound, &apiErr)
+	if apiErr.Code != "not_found" {
+		t.Errorf("Expected an unknown session to be not found, got %+v", apiErr)
+	}
+	if status, _ := client.do("PATCH", "/api/balls/"+created.ID, map[string]any{"colour": "red"}); status != http.StatusBadRequest {
+		t.Errorf("Expected unknown fields to be rejected, got %d", status)
+	}
+
+	var blocked session.Ball
+	client.decode("PATCH", "/api/balls/"+created.ShortID(), map[string]any{
+		"state": "blocked", "blocked_reason": "Waiting on design", "title": "Add the login page",
+	}, http.StatusOK, &blocked)
+	if blocked.State != session.StateBlocked || blocked.BlockedReason != "Waiting on design" || blocked.Title != "Add the login page" {
+		t.Errorf("Unexpected updated ball %+v", blocked)
+	}
+	stored, err := env.GetStore(t).GetBallByID(created.ID)
+	if err != nil || stored.State != session.StateBlocked {
+		t.Errorf("Expected the update to be saved, got %+v (%v)", stored, err)
+	}
+
+	var completed session.Ball
+	client.decode("PATCH", "/api/balls/"+created.ID, map[string]any{"state": "complete"}, http.StatusOK, &completed)
+	if completed.State != session.StateComplete || completed.CompletedAt == nil || completed.BlockedReason != "" {
+		t.Errorf("Expected a completed ball, got %+v", completed)
+	}
+
+	var progress map[string]string
+	client.decode("POST", "/api/sessions/auth/progress", map[string]any{"text": "Login page done"}, http.StatusOK, &progress)
+	client.decode("GET", "/api/sessions/auth/progress", nil, http.StatusOK, &progress)
+	if !strings.Contains(progress["progress"], "Login page done") {
+		t.Errorf("Expected the appended progress, got %+v", progress)
+	}
+
+	var detail struct {
+		ID       string         `json:"id"`
+		Progress string         `json:"progress"`
+		Balls    []session.Ball `json:"balls"`
+	}
+	client.decode("GET", "/api/sessions/auth", nil, http.StatusOK, &detail)
+	if detail.ID != "auth" || !strings.Contains(detail.Progress, "Login page done") || len(detail.Balls) != 1 {
+		t.Errorf("Unexpected session detail %+v", detail)
+	}
+	client.decode("GET", "/api/sessions/nope", nil, http.StatusNotFound, nil)
+
+	var sessions []session.JuggleSession
+	client.decode("GET", "/api/sessions", nil, http.StatusOK, &sessions)
+	if len(sessions) != 1 || sessions[0].ID != "auth" {
+		t.Errorf("Expected the auth session, got %+v", sessions)
+	}
+}
+
+// TestServeAgentRuns tests starting, streaming and cancelling agent runs
+func TestServeAgentRuns(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+
+	// Stand in for 'juggle agent run': echo the arguments, or hang for --ball runs
+	restore := cli.SetDaemonCommandForTest(func(projectDir string, args []string) (*exec.Cmd, error) {
+		script := `echo "$@"; echo "second line"`
+		if strings.Contains(strings.Join(args, " "), "--ball") {
+			script = `exec sleep 30`
+		}
+		command := exec.Command("sh", "-c", script, "sh")
+		command.Args = append(command.Args, args...)
+		command.Dir = projectDir
+		return command, nil
+	})
+	defer restore()
+	client := newAPIClient(t, env.ProjectDir, "")
+
+	var run struct {
+		ID        string `json:"id"`
+		Session   string `json:"session"`
+		ExitCode  int    `json:"exit_code"`
+		Cancelled bool   `json:"cancelled"`
+		EndedAt   string `json:"ended_at"`
+	}
+	client.decode("POST", "/api/agents", map[string]any{"session": "auth", "iterations": 2}, http.StatusAccepted, &run)
+	if run.ID != "1" || run.Session != "auth" {
+		t.Fatalf("Unexpected run %+v", run)
+	}
+
+	// The stream ends once the run has exited
+	status, events := client.do("GET", "/api/agents/1/events", nil)
+	if status != http.StatusOK {
+		t.Fatalf("Expected the event stream, got %d: %s", status, events)
+	}
+	for _, want := range []string{
+		"event: output\ndata: agent run auth --iterations 2 ",
+		"event: output\ndata: second line\n\n",
+		"event: exit\ndata: {",
+	} {
+		if !strings.Contains(string(events), want) {
+			t.Errorf("Expected %q in the stream:\n%s", want, events)
+		}
+	}
+	client.decode("GET", "/api/agents/1", nil, http.StatusOK, &run)
+	if run.EndedAt == "" || run.ExitCode != 0 {
+		t.Errorf("Expected the run to have exited cleanly, got %+v", run)
+	}
+
+	ball := env.CreateBall(t, "Login page", session.PriorityMedium)
+	client.decode("POST", "/api/agents", map[string]any{"session": "auth", "ball": ball.ShortID()}, http.StatusAccepted, &run)
+	client.decode("DELETE", "/api/agents/"+run.ID, nil, http.StatusAccepted, &run)
+	if !run.Cancelled {
+		t.Errorf("Expected the run to be cancelled, got %+v", run)
+	}
+	if _, events := client.do("GET", "/api/agents/"+run.ID+"/events", nil); !strings.Contains(string(events), "event: exit") {
+		t.Errorf("Expected the cancelled run to exit, got:\n%s", events)
+	}
+	client.decode("DELETE", "/api/agents/"+run.ID, nil, http.StatusBadRequest, nil)
+
+	var runs []map[string]any
+	client.decode("GET", "/api/agents", nil, http.StatusOK, &runs)
+	if len(runs) != 2 {
+		t.Errorf("Expected two runs, got %+v", runs)
+	}
+	client.decode("POST", "/api/agents", map[string]any{"session": "nope"}, http.StatusNotFound, nil)
+	client.decode("GET", "/api/agents/99", nil, http.StatusNotFound, nil)
+}
+
+// TestServeRefusesBrowserRequests tests that web pages can't drive the API:
+// cross-origin requests, non-JSON bodies and, without a token, non-loopback
+// Host headers are refused
+func TestServeRefusesBrowserRequests(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	client := newAPIClient(t, env.ProjectDir, "")
+	send := func(method, path, body string, header map[string]string) int {
+		t.Helper()
+		req, err := http.NewRequest(method, client.server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to build request: %v", err)
+		}
+		for key, value := range header {
+			if key == "Host" {
+				req.Host = value
+			} else {
+				req.Header.Set(key, value)
+			}
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	ball := `{"title": "Planted"}`
+
+	// A form or fetch() a page can send without a preflight
+	if status := send("POST", "/api/balls", ball, map[string]string{"Content-Type": "text/plain"}); status != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected a text/plain body refused, got %d", status)
+	}
+	if status := send("POST", "/api/agents", `{"session": "auth"}`, nil); status != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected a body without a content type refused, got %d", status)
+	}
+	jsonBody := map[string]string{"Content-Type": "application/json"}
+	crossOrigin := map[string]string{"Content-Type": "application/json", "Origin": "https://evil.example"}
+	if status := send("POST", "/api/balls", ball, crossOrigin); status != http.StatusForbidden {
+		t.Errorf("Expected a cross-origin request refused, got %d", status)
+	}
+	if status := send("GET", "/api/balls", "", map[string]string{"Origin": "https://evil.example"}); status != http.StatusForbidden {
+		t.Errorf("Expected a cross-origin read refused, got %d", status)
+	}
+
+	// A DNS rebinding page reaches the server under its own name
+	if status := send("GET", "/api/balls", "", map[string]string{"Host": "evil.example:7171"}); status != http.StatusForbidden {
+		t.Errorf("Expected a non-loopback Host refused, got %d", status)
+	}
+	for _, host := range []string{"localhost:7171", "127.0.0.1", "[::1]:7171"} {
+		if status := send("GET", "/api/balls", "", map[string]string{"Host": host}); status != http.StatusOK {
+			t.Errorf("Expected Host %s allowed, got %d", host, status)
+		}
+	}
+
+	balls, err := env.GetStore(t).LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 0 {
+		t.Errorf("Expected no balls created by refused requests, got %d", len(balls))
+	}
+
+	// Same-origin JSON requests still work
+	sameOrigin := map[string]string{"Content-Type": "application/json; charset=utf-8", "Origin": client.server.URL}
+	if status := send("POST", "/api/balls", ball, sameOrigin); status != http.StatusCreated {
+		t.Errorf("Expected a same-origin JSON request allowed, got %d", status)
+	}
+	if status := send("POST", "/api/balls", ball, jsonBody); status != http.StatusCreated {
+		t.Errorf("Expected a JSON request without an Origin allowed, got %d", status)
+	}
+
+	// With a token, other hosts are allowed
+	tokenClient := newAPIClient(t, env.ProjectDir, "secret")
+	req, _ := http.NewRequest("GET", tokenClient.server.URL+"/api/balls", nil)
+	req.Host = "juggle.internal:7171"
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /api/balls failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected any Host allowed with a token, got %d", resp.StatusCode)
+	}
+}
+
+// TestServeProfiling tests that the pprof endpoints are served on the API
+// with --profile, behind the token, and not otherwise
+func TestServeProfiling(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	client := newAPIClient(t, env.ProjectDir, "secret")
+	if status, _ := client.do("GET", "/debug/juggle/profile", nil); status != http.StatusNotFound {
+		t.Errorf("Expected no profiling endpoints without --profile, got %d", status)
+	}
+
+	if err := profile.Enable(filepath.Join(env.TempDir, "profile.jsonl")); err != nil {
+		t.Fatalf("Failed to enable profiling: %v", err)
+	}
+	defer profile.Close()
+	client = newAPIClient(t, env.ProjectDir, "secret")
+
+	var stats []profile.Stat
+	client.decode("GET", "/debug/juggle/profile", nil, http.StatusOK, &stats)
+	status, body := client.do("GET", "/debug/pprof/", nil)
+	if status != http.StatusOK || !strings.Contains(string(body), "goroutine") {
+		t.Errorf("Expected the pprof index, got %d: %.200s", status, body)
+	}
+
+	anonymous := &apiClient{t: t, server: client.server}
+	if status, _ := anonymous.do("GET", "/debug/pprof/", nil); status != http.StatusUnauthorized {
+		t.Errorf("Expected pprof to need the token, got %d", status)
+	}
+}
@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestSessionGoalCommand tests setting a goal and editing exit criteria
+func TestSessionGoalCommand(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+
+	runJuggleCommand(t, env.ProjectDir, "sessions", "goal", "auth", "--set", "Users can log in with OAuth")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "goal", "auth", "--add", "Flag file exists", "--check", "test -f done.flag")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "goal", "auth", "--add", "Docs updated", "--add", "Demoed")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "goal", "auth", "--met", "2", "--remove", "3")
+
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, "done.flag"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write flag file: %v", err)
+	}
+	output := runJuggleCommand(t, env.ProjectDir, "sessions", "goal", "auth", "--evaluate", "--json")
+	var resp cli.SessionGoalResponse
+	if err := json.Unmarshal([]byte(output), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if resp.Goal != "Users can log in with OAuth" || resp.Met != 2 || resp.Total != 2 {
+		t.Errorf("Expected the goal with 2/2 criteria met, got %+v", resp)
+	}
+	if resp.ExitCriteria[0].Check != "test -f done.flag" || resp.ExitCriteria[1].Text != "Docs updated" {
+		t.Errorf("Expected the checked criterion and Docs updated, got %+v", resp.ExitCriteria)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "show", "auth")
+	if !strings.Contains(output, "Goal:") || !strings.Contains(output, "(2/2 met)") {
+		t.Errorf("Expected sessions show to list the goal and criteria, got:\n%s", output)
+	}
+
+	// Checked criteria can't be marked met by hand
+	_, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "sessions", "goal", "auth", "--met", "1")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 for marking a checked criterion met, got %d", exitCode)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "sessions", "goal", "auth", "--remove", "5")
+	if exitCode != 4 {
+		t.Errorf("Expected exit code 4 for an unknown criterion, got %d", exitCode)
+	}
+}
+
+// TestAgentLoop_CompleteRejectedWhileExitCriteriaUnmet tests that COMPLETE
+// is only accepted once the session's exit criteria are met
+func TestAgentLoop_CompleteRejectedWhileExitCriteriaUnmet(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	env.CreateSession(t, "test-session", "Test session for agent")
+	sessionStore := env.GetSessionStore(t)
+	criteria := []session.ExitCriterion{{Text: "Flag file exists", Check: "test -f done.flag"}}
+	if err := sessionStore.UpdateSessionExitCriteria("test-session", criteria); err != nil {
+		t.Fatalf("Failed to set exit criteria: %v", err)
+	}
+
+	ball := env.CreateBall(t, "Test ball", session.PriorityMedium)
+	ball.Tags = []string{"test-session"}
+	store := env.GetStore(t)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	run := func(iterations int) (*cli.AgentResult, *agent.MockRunner) {
+		results := make([]*agent.RunResult, iterations)
+		for i := range results {
+			results[i] = &agent.RunResult{Output: "<promise>COMPLETE</promise>", Complete: true}
+		}
+		mock := agent.NewMockRunner(results...)
+		agent.SetRunner(&progressAndCompleteMockRunner{
+			mock:         mock,
+			sessionStore: sessionStore,
+			store:        store,
+			sessionID:    "test-session",
+		})
+		defer agent.ResetRunner()
+
+		result, err := cli.RunAgentLoop(cli.AgentLoopConfig{
+			SessionID:     "test-session",
+			ProjectDir:    env.ProjectDir,
+			MaxIterations: iterations,
+		})
+		if err != nil {
+			t.Fatalf("Agent run failed: %v", err)
+		}
+		return result, mock
+	}
+
+	result, mock := run(2)
+	if result.Complete || mock.NextIndex != 2 {
+		t.Errorf("Expected COMPLETE to be rejected while the criterion is unmet, got complete=%v after %d iterations", result.Complete, mock.NextIndex)
+	}
+	if result.ValidationFailures != 0 {
+		t.Errorf("Expected no progress validation failures, got %d", result.ValidationFailures)
+	}
+
+	// With every ball finished, the unmet goal needs a human
+	result, _ = run(1)
+	if result.Complete || !result.Blocked || result.Iterations != 0 {
+		t.Errorf("Expected a blocked run with no balls left and the criterion unmet, got %+v", result)
+	}
+
+	if err := os.WriteFile(filepath.Join(env.ProjectDir, "done.flag"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write flag file: %v", err)
+	}
+	ball.State = session.StatePending
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	result, mock = run(2)
+	if !result.Complete || mock.NextIndex != 1 {
+		t.Errorf("Expected COMPLETE to be accepted once the criterion is met, got complete=%v after %d iterations", result.Complete, mock.NextIndex)
+	}
+
+	sess, err := sessionStore.LoadSession("test-session")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if met, total := sess.GoalProgress(); met != 1 || total != 1 {
+		t.Errorf("Expected the check result to be recorded, got %d/%d", met, total)
+	}
+}
@@ -0,0 +1,99 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestSessionLifecycle tests archiving, pausing and resuming sessions
+func TestSessionLifecycle(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "auth", "Authentication")
+	env.CreateSession(t, "docs", "Documentation")
+	env.CreateSession(t, "old", "Finished work")
+	store := env.GetStore(t)
+	var done, open *session.Ball
+	for _, ball := range []struct {
+		title, session string
+		state          session.BallState
+	}{
+		{"Login page", "auth", session.StatePending},
+		{"Write guide", "docs", session.StatePending},
+		{"Shipped feature", "old", session.StateComplete},
+		{"Leftover idea", "old", session.StatePending},
+	} {
+		b := env.CreateBall(t, ball.title, session.PriorityMedium)
+		b.Tags = []string{ball.session}
+		b.State = ball.state
+		if err := store.UpdateBall(b); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+		if ball.session == "old" {
+			if ball.state == session.StateComplete {
+				done = b
+			} else {
+				open = b
+			}
+		}
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "sessions", "archive", "old", "--balls", "--json")
+	var resp cli.SessionStateResponse
+	if err := json.Unmarshal([]byte(output), &resp); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if resp.State != "archived" || len(resp.ArchivedBalls) != 1 || resp.ArchivedBalls[0] != done.ID {
+		t.Errorf("Expected old archived with its complete ball, got %+v", resp)
+	}
+	if ball, err := store.GetBallByID(open.ID); err != nil || ball == nil {
+		t.Errorf("Expected the open ball to stay active, got %v", err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "sessions", "pause", "docs")
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "list")
+	if strings.Contains(output, "old:") || !strings.Contains(output, "State: paused") || !strings.Contains(output, "1 archived session(s) hidden") {
+		t.Errorf("Expected old hidden and docs marked paused, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "list", "--archived")
+	if !strings.Contains(output, "old:") || !strings.Contains(output, "State: archived") {
+		t.Errorf("Expected --archived to list old, got: %s", output)
+	}
+
+	// Paused and archived sessions aren't scheduled by the daemon
+	var launched []string
+	restore := cli.SetDaemonCommandForTest(func(projectDir string, args []string) (*exec.Cmd, error) {
+		launched = append(launched, strings.Join(args, " "))
+		command := exec.Command("true")
+		command.Dir = projectDir
+		return command, nil
+	})
+	defer restore()
+	if _, err := cli.RunAgentDaemonOnceForTest(env.ProjectDir, nil, 1); err != nil {
+		t.Fatalf("Daemon failed: %v", err)
+	}
+	if len(launched) != 1 || !strings.HasPrefix(launched[0], "agent run auth ") {
+		t.Errorf("Expected only auth to be run, got %v", launched)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "sessions", "resume", "old")
+	sess, err := env.GetSessionStore(t).LoadSession("old")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if sess.CurrentState() != session.SessionStateActive {
+		t.Errorf("Expected old active again, got %s", sess.CurrentState())
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "sessions", "pause", "nope"); exitCode != 3 {
+		t.Errorf("Expected an unknown session to be not found, got %d", exitCode)
+	}
+}
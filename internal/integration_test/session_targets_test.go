@@ -0,0 +1,104 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestSessionTargets tests setting a throughput target and reporting progress toward it
+func TestSessionTargets(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateSession(t, "feature", "Feature work")
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "sessions", "edit", "feature", "--target", "5/year")
+	if exitCode == 0 || !strings.Contains(output, "period must be day, week, or month") {
+		t.Errorf("Expected invalid period to be rejected, got (%d): %s", exitCode, output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "edit", "feature", "--target", "2/week")
+	if !strings.Contains(output, "Updated target: 2/week") {
+		t.Errorf("Expected target to be set, got: %s", output)
+	}
+
+	ball := env.CreateBall(t, "Done this week", session.PriorityMedium)
+	ball.Tags = []string{"feature"}
+	ball.MarkComplete("")
+	if err := env.GetStore(t).UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "list")
+	if !strings.Contains(output, "Target: 1/2 this week") {
+		t.Errorf("Expected target progress in session list, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "targets")
+	if !strings.Contains(output, "1/2 this week") || !strings.Contains(output, "before target was set") {
+		t.Errorf("Expected targets report, got: %s", output)
+	}
+	if strings.Contains(output, "BEHIND") {
+		t.Errorf("Expected a new target not to be behind, got: %s", output)
+	}
+
+	// Backdate the target so the two empty weeks before this one are judged
+	sessionStore := env.GetSessionStore(t)
+	sess, err := sessionStore.LoadSession("feature")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	sess.Target.SetAt = time.Now().AddDate(0, 0, -30)
+	writeSessionFile(t, env, sess)
+
+	output = runJuggleCommand(t, env.ProjectDir, "sessions", "targets")
+	if !strings.Contains(output, "BEHIND") {
+		t.Errorf("Expected session to be behind, got: %s", output)
+	}
+
+	output, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "sessions", "targets", "--notify")
+	if exitCode == 0 || !strings.Contains(output, "target_notify_command") {
+		t.Errorf("Expected --notify without a command to fail, got (%d): %s", exitCode, output)
+	}
+
+	marker := filepath.Join(t.TempDir(), "notified")
+	opts := session.ConfigOptions{ConfigHome: env.ConfigHome, JuggleDirName: ".juggle"}
+	config, err := session.LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	config.TargetNotifyCommand = `echo "$JUGGLE_SESSION $JUGGLE_TARGET $JUGGLE_TARGET_DONE" >> ` + marker
+	if err := config.SaveWithOptions(opts); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "sessions", "targets", "--notify")
+	runJuggleCommand(t, env.ProjectDir, "sessions", "targets", "--notify")
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("Expected notify command to run: %v", err)
+	}
+	if string(data) != "feature 2/week 0,0\n" {
+		t.Errorf("Expected exactly one notification, got %q", data)
+	}
+}
+
+// writeSessionFile saves a session as-is, bypassing setters that stamp times
+func writeSessionFile(t *testing.T, env *TestEnv, sess *session.JuggleSession) {
+	t.Helper()
+	data, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal session: %v", err)
+	}
+	path := filepath.Join(env.ProjectDir, ".juggle", "sessions", sess.ID, "session.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write session: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package integration_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// runJuggleShellScript runs `juggle shell` with the given script on stdin
+func runJuggleShellScript(t *testing.T, env *TestEnv, script string) string {
+	t.Helper()
+
+	// Reuse runJuggleCommand to build the binary if needed
+	runJuggleCommand(t, env.ProjectDir, "--help")
+
+	configHome := filepath.Join(env.ProjectDir, "..", "config")
+	cmd := exec.Command(GetJuggleBinaryPath(t), "--config-home", configHome, "shell")
+	cmd.Dir = env.ProjectDir
+	cmd.Stdin = strings.NewReader(script)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("juggle shell failed: %v\nOutput: %s", err, output)
+	}
+	return string(output)
+}
+
+// TestShell_ScriptedTriage tests running shorthand and regular commands through juggle shell
+func TestShell_ScriptedTriage(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	first := env.CreateBall(t, "First task", session.PriorityLow)
+	second := env.CreateBall(t, "Second task", session.PriorityLow)
+
+	script := strings.Join([]string{
+		"ls",
+		"start " + first.ShortID(),
+		"update " + first.ShortID() + " --priority urgent",
+		// Flags from the previous command must not leak into this one
+		"update " + second.ShortID() + " --intent \"Second task, renamed\"",
+		`block ` + second.ShortID() + ` "waiting on design"`,
+		"nonsense-command",
+		"exit",
+		"ls",
+	}, "\n")
+
+	output := runJuggleShellScript(t, env, script)
+
+	if !strings.Contains(output, "First task") || !strings.Contains(output, "Second task") {
+		t.Errorf("Expected ls to list both balls, got: %s", output)
+	}
+	if !strings.Contains(output, "Error:") {
+		t.Errorf("Expected an error for the unknown command without exiting, got: %s", output)
+	}
+
+	store := env.GetStore(t)
+	firstBall, err := store.GetBallByID(first.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload first ball: %v", err)
+	}
+	if firstBall.State != session.StateInProgress || firstBall.Priority != session.PriorityUrgent {
+		t.Errorf("Expected first ball in_progress/urgent, got %s/%s", firstBall.State, firstBall.Priority)
+	}
+
+	secondBall, err := store.GetBallByID(second.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload second ball: %v", err)
+	}
+	if secondBall.Priority != session.PriorityLow {
+		t.Errorf("Expected --priority not to leak into the next command, got %s", secondBall.Priority)
+	}
+	if secondBall.Title != "Second task, renamed" {
+		t.Errorf("Expected second ball renamed, got %q", secondBall.Title)
+	}
+	if secondBall.State != session.StateBlocked || secondBall.BlockedReason != "waiting on design" {
+		t.Errorf("Expected second ball blocked, got %s (%s)", secondBall.State, secondBall.BlockedReason)
+	}
+}
+
+// TestShell_ResetsStateBetweenCommands tests a flag given to one command
+// doesn't carry over to the next command run in the same shell
+func TestShell_ResetsStateBetweenCommands(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	env.CreateBall(t, "Alpha task", session.PriorityLow)
+
+	output := runJuggleShellScript(t, env, "search Alpha --json\nsearch Alpha\n")
+
+	if !strings.Contains(output, `"title": "Alpha task"`) {
+		t.Errorf("Expected JSON output from the first search, got: %s", output)
+	}
+	if !strings.Contains(output, "Found 1 result(s)") {
+		t.Errorf("Expected plain output from the second search, got: %s", output)
+	}
+}
@@ -0,0 +1,83 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestTagApply tests bulk retagging balls that match a query
+func TestTagApply(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+
+	store := env.GetStore(t)
+	pending := env.CreateBall(t, "Pending in old session", session.PriorityMedium)
+	blocked := env.CreateBall(t, "Blocked in old session", session.PriorityMedium)
+	other := env.CreateBall(t, "Pending elsewhere", session.PriorityMedium)
+	pending.Tags = []string{"old-session"}
+	blocked.Tags = []string{"old-session"}
+	blocked.SetBlocked("waiting")
+	other.Tags = []string{"other"}
+	for _, ball := range []*session.Ball{pending, blocked, other} {
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	args := []string{"tag", "apply", "--query", "state:pending tag:old-session", "--add", "new-session", "--remove", "old-session"}
+
+	output := runJuggleCommand(t, env.ProjectDir, append(args, "--dry-run")...)
+	if !strings.Contains(output, pending.ID) || !strings.Contains(output, "-old-session +new-session") {
+		t.Errorf("Expected preview of the pending ball, got: %s", output)
+	}
+	if strings.Contains(output, blocked.ID) || strings.Contains(output, other.ID) {
+		t.Errorf("Expected only the pending old-session ball in preview, got: %s", output)
+	}
+	if !strings.Contains(output, "Would retag 1 ball (dry run") {
+		t.Errorf("Expected dry run summary, got: %s", output)
+	}
+	if ball, _ := env.GetStore(t).GetBallByID(pending.ID); strings.Join(ball.Tags, ",") != "old-session" {
+		t.Errorf("Expected dry run not to change tags, got %v", ball.Tags)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, append([]string{"--json"}, args...)...)
+	var results []struct {
+		Changes []session.TagChange `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(output), &results); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if len(results) != 1 || len(results[0].Changes) != 1 || results[0].Changes[0].BallID != pending.ID {
+		t.Errorf("Expected one change for the pending ball, got: %+v", results)
+	}
+
+	store = env.GetStore(t)
+	for id, want := range map[string]string{pending.ID: "new-session", blocked.ID: "old-session", other.ID: "other"} {
+		ball, err := store.GetBallByID(id)
+		if err != nil {
+			t.Fatalf("Failed to get ball: %v", err)
+		}
+		if strings.Join(ball.Tags, ",") != want {
+			t.Errorf("Ball %s tags = %v, want %s", id, ball.Tags, want)
+		}
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, args...)
+	if !strings.Contains(output, "No matching balls") {
+		t.Errorf("Expected nothing left to retag, got: %s", output)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "tag", "apply", "--query", "owner:me", "--add", "x")
+	if exitCode == 0 || !strings.Contains(output, "unknown query key") {
+		t.Errorf("Expected invalid query to be rejected, got (%d): %s", exitCode, output)
+	}
+	_, exitCode = runJuggleCommandWithError(t, env.ProjectDir, "tag", "apply", "--query", "tag:x")
+	if exitCode == 0 {
+		t.Error("Expected apply without --add or --remove to fail")
+	}
+}
@@ -0,0 +1,66 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestTestsRecord_BlocksCompletion tests that a failing recorded test run blocks completion until it passes
+func TestTestsRecord_BlocksCompletion(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Fix the parser", session.PriorityMedium)
+
+	runJuggleCommand(t, env.ProjectDir, "tests", "record", ball.ID, "fail", "--cmd", "go test ./...")
+
+	store := env.GetStore(t)
+	reloaded, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload ball: %v", err)
+	}
+	if reloaded.TestsState == nil || reloaded.TestsState.Result != session.TestsFail {
+		t.Fatalf("Expected failing tests state, got %+v", reloaded.TestsState)
+	}
+	if reloaded.TestsState.Command != "go test ./..." || reloaded.TestsState.RecordedAt.IsZero() {
+		t.Errorf("Expected command and timestamp to be recorded, got %+v", reloaded.TestsState)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	if exitCode == 0 {
+		t.Fatalf("Expected completion to be refused, got output: %s", output)
+	}
+	if !strings.Contains(output, "failing tests") {
+		t.Errorf("Expected failing tests error, got: %s", output)
+	}
+	env.AssertState(t, ball.ID, session.StatePending)
+
+	runJuggleCommand(t, env.ProjectDir, "tests", "record", ball.ID, "pass")
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	env.AssertState(t, ball.ID, session.StateComplete)
+}
+
+// TestTestsPolicy_WarnAllowsCompletion tests that the warn policy completes balls with failing tests
+func TestTestsPolicy_WarnAllowsCompletion(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Fix the parser", session.PriorityMedium)
+
+	runJuggleCommand(t, env.ProjectDir, "tests", "policy", "warn")
+	output := runJuggleCommand(t, env.ProjectDir, "tests", "policy")
+	if !strings.Contains(output, "Tests policy: warn") {
+		t.Errorf("Expected warn policy, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "tests", "record", ball.ID, "fail")
+	output = runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--state", "complete")
+	if !strings.Contains(output, "Warning:") {
+		t.Errorf("Expected warning about failing tests, got: %s", output)
+	}
+	env.AssertState(t, ball.ID, session.StateComplete)
+}
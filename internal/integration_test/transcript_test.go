@@ -0,0 +1,101 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestAttachTranscript_FromRun tests attaching an agent run iteration to a ball
+func TestAttachTranscript_FromRun(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Pick a cache strategy", session.PriorityMedium)
+
+	historyStore, err := session.NewAgentHistoryStore(env.ProjectDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+	record := session.NewAgentRunRecord("all", env.ProjectDir, time.Now())
+	record.SetMaxIterations(3, 0, 0, 1)
+	if err := historyStore.AppendRecord(record); err != nil {
+		t.Fatalf("Failed to append record: %v", err)
+	}
+	for i, output := range []string{"explored", "decided: no cache, reads are cheap", "wrapped up"} {
+		if err := historyStore.SaveIterationOutput(record.ID, i+1, output); err != nil {
+			t.Fatalf("Failed to save iteration output: %v", err)
+		}
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "attach-transcript", ball.ID, "--from-run", record.ID, "--iteration", "2", "--note", "cache decision")
+	if !strings.Contains(output, "Attached transcript") {
+		t.Errorf("Expected confirmation, got: %s", output)
+	}
+	runJuggleCommand(t, env.ProjectDir, "attach-transcript", ball.ID, "--from-run", "latest")
+
+	store := env.GetStore(t)
+	reloaded, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload ball: %v", err)
+	}
+	if len(reloaded.Transcripts) != 2 {
+		t.Fatalf("Expected 2 transcripts, got %+v", reloaded.Transcripts)
+	}
+	first := reloaded.Transcripts[0]
+	if first.Source != session.TranscriptSourceAgent || first.RunID != record.ID || first.Iteration != 2 || first.Note != "cache decision" {
+		t.Errorf("Unexpected transcript: %+v", first)
+	}
+	if !strings.HasSuffix(first.File, ".gz") {
+		t.Errorf("Expected compressed transcript file, got %s", first.File)
+	}
+	content, err := store.LoadTranscript(first)
+	if err != nil {
+		t.Fatalf("Failed to load transcript: %v", err)
+	}
+	if content != "decided: no cache, reads are cheap" {
+		t.Errorf("Unexpected transcript content: %q", content)
+	}
+	if reloaded.Transcripts[1].Iteration != 3 {
+		t.Errorf("Expected last iteration by default, got %d", reloaded.Transcripts[1].Iteration)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "attach-transcript", ball.ID, "--from-run", record.ID, "--iteration", "4")
+	if exitCode == 0 || !strings.Contains(output, "invalid iteration") {
+		t.Errorf("Expected invalid iteration error, got (%d): %s", exitCode, output)
+	}
+}
+
+// TestAttachTranscript_FromFile tests attaching human chat from a file
+func TestAttachTranscript_FromFile(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Pick a cache strategy", session.PriorityMedium)
+
+	chatPath := filepath.Join(env.TempDir, "chat.md")
+	if err := os.WriteFile(chatPath, []byte("me: cache?\nthem: no\n"), 0644); err != nil {
+		t.Fatalf("Failed to write chat: %v", err)
+	}
+	runJuggleCommand(t, env.ProjectDir, "attach-transcript", ball.ID, "--file", chatPath)
+
+	store := env.GetStore(t)
+	reloaded, err := store.GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload ball: %v", err)
+	}
+	if len(reloaded.Transcripts) != 1 || reloaded.Transcripts[0].Source != session.TranscriptSourceChat || reloaded.Transcripts[0].Lines != 2 {
+		t.Fatalf("Unexpected transcripts: %+v", reloaded.Transcripts)
+	}
+
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "attach-transcript", ball.ID)
+	if exitCode == 0 || !strings.Contains(output, "--from-run or --file") {
+		t.Errorf("Expected missing source error, got (%d): %s", exitCode, output)
+	}
+}
@@ -0,0 +1,56 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestUndoRedo tests undoing and redoing ball changes via the CLI
+func TestUndoRedo(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Write the docs", session.PriorityMedium)
+
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--priority", "urgent")
+	runJuggleCommand(t, env.ProjectDir, ball.ID, "complete", "Shipped")
+
+	output := runJuggleCommand(t, env.ProjectDir, "undo", "--list")
+	if !strings.Contains(output, "archive") || !strings.Contains(output, "update") {
+		t.Errorf("Expected the update and archive in the undo history, got: %s", output)
+	}
+
+	// Completing (update + archive) is undone in one step
+	output = runJuggleCommand(t, env.ProjectDir, "undo")
+	if !strings.Contains(output, "Undid archive of") {
+		t.Errorf("Expected undo confirmation, got: %s", output)
+	}
+	restored := env.AssertBallExists(t, ball.ID)
+	if restored.State != session.StatePending || restored.Priority != session.PriorityUrgent {
+		t.Errorf("Expected the pending urgent ball back, got %s/%s", restored.State, restored.Priority)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "undo")
+	if restored := env.AssertBallExists(t, ball.ID); restored.Priority != session.PriorityMedium {
+		t.Errorf("Expected priority back to medium, got %s", restored.Priority)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "redo", "5")
+	if !strings.Contains(output, "Redid update of") || !strings.Contains(output, "Redid archive of") || !strings.Contains(output, "nothing to redo") {
+		t.Errorf("Expected both changes redone, got: %s", output)
+	}
+	archived, err := env.GetStore(t).LoadArchivedBalls()
+	if err != nil || len(archived) != 1 || archived[0].Priority != session.PriorityUrgent {
+		t.Errorf("Expected the urgent ball archived again, got %v (%v)", archived, err)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "redo"); exitCode == 0 {
+		t.Error("Expected redo with nothing undone to fail")
+	}
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "undo", "zero"); exitCode == 0 {
+		t.Error("Expected a non-numeric count to be rejected")
+	}
+}
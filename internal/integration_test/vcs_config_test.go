@@ -3,6 +3,7 @@ package integration_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ohare93/juggle/internal/session"
@@ -62,15 +63,62 @@ func TestVCSConfig_JJPriorityInAutoDetect(t *testing.T) {
 	}
 }
 
-// TestVCSConfig_DefaultToGit tests that git is the default when nothing is detected
-func TestVCSConfig_DefaultToGit(t *testing.T) {
+// TestVCSConfig_DefaultToNone tests that a project outside any repository
+// is detected as not under version control
+func TestVCSConfig_DefaultToNone(t *testing.T) {
 	env := SetupTestEnv(t)
 	defer CleanupTestEnv(t, env)
 
 	// No VCS directories created
 	detected := vcs.AutoDetect(env.ProjectDir)
-	if detected != vcs.VCSTypeGit {
-		t.Errorf("expected git (default), got %s", detected)
+	if detected != vcs.VCSTypeNone {
+		t.Errorf("expected none, got %s", detected)
+	}
+}
+
+// TestVCSConfig_NonVersionedProject starts and blocks a ball in a project
+// without version control, which uses filesystem snapshots for revisions
+func TestVCSConfig_NonVersionedProject(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	appFile := filepath.Join(env.ProjectDir, "app.txt")
+	if err := os.WriteFile(appFile, []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ball := env.CreateBall(t, "Update the app", session.PriorityMedium)
+
+	output := runJuggleCommand(t, env.ProjectDir, "config", "vcs", "show")
+	if !strings.Contains(output, "effective: none") {
+		t.Errorf("Expected none to be the effective VCS:\n%s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, ball.ID)
+	if strings.Contains(output, "Warning") {
+		t.Errorf("Expected no VCS warnings starting a ball:\n%s", output)
+	}
+	if err := os.WriteFile(appFile, []byte("v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, ball.ID, "blocked", "Waiting on design")
+	if strings.Contains(output, "Warning") {
+		t.Errorf("Expected no VCS warnings blocking a ball:\n%s", output)
+	}
+
+	blocked, err := env.GetStore(t).GetBallByID(ball.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	if !strings.HasPrefix(blocked.StartingRevision, "rev-") || !strings.HasPrefix(blocked.RevisionID, "blocked-") {
+		t.Fatalf("Expected snapshot revisions, got %q and %q", blocked.StartingRevision, blocked.RevisionID)
+	}
+	if data, _ := os.ReadFile(appFile); string(data) != "v1\n" {
+		t.Errorf("Expected blocking to return to the starting files, got %q", data)
+	}
+	diff, err := vcs.NewFSBackend().Diff(env.ProjectDir, blocked.StartingRevision, blocked.RevisionID)
+	if err != nil || !strings.Contains(diff, "-v1\n+v2\n") {
+		t.Errorf("Expected the blocked work in its snapshot, got %v:\n%s", err, diff)
 	}
 }
 
@@ -0,0 +1,94 @@
+package integration_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/agent"
+	"github.com/ohare93/juggle/internal/cli"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestVerifyBall tests checking a ball's acceptance criteria against its diff
+func TestVerifyBall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	store := env.GetStore(t)
+
+	empty := env.CreateBall(t, "No criteria", session.PriorityLow)
+	output, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "verify", empty.ID)
+	if exitCode != 4 || !strings.Contains(output, "no acceptance criteria") {
+		t.Errorf("Expected a ball without criteria to be rejected, got %d: %s", exitCode, output)
+	}
+
+	target := filepath.Join(env.ProjectDir, "login.go")
+	if err := os.WriteFile(target, []byte("package auth\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	initGitProject(t, env.ProjectDir)
+	head, err := exec.Command("git", "-C", env.ProjectDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatalf("Failed to read HEAD: %v", err)
+	}
+
+	ball := env.CreateBall(t, "Reject bad passwords", session.PriorityMedium)
+	ball.AcceptanceCriteria = []string{"Login returns 401 on a bad password", "A test covers the bad password", "Failed logins are rate limited"}
+	ball.StartingRevision = strings.TrimSpace(string(head))
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("package auth\n\nfunc checkPassword() int { return 401 }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	mock := agent.NewMockRunner(&agent.RunResult{Output: `Checked the diff.
+<ac index="1" status="satisfied">login.go:3 returns 401</ac>
+<ac index="2" status="unsatisfied">No test file was added</ac>
+<ac index="7" status="satisfied">Not a criterion</ac>`})
+	agent.SetRunner(mock)
+	defer agent.ResetRunner()
+
+	verification, err := cli.VerifyBallForTest(store, ball, "", "")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	if len(mock.Calls) != 1 || mock.Calls[0].Permission != agent.PermissionPlan {
+		t.Fatalf("Expected one read-only runner call, got %+v", mock.Calls)
+	}
+	prompt := mock.Calls[0].Prompt
+	if !strings.Contains(prompt, "+func checkPassword() int { return 401 }") || !strings.Contains(prompt, "3. Failed logins are rate limited") {
+		t.Errorf("Expected the diff and criteria in the prompt, got:\n%s", prompt)
+	}
+	if !strings.Contains(verification.DiffRange, "..working copy") {
+		t.Errorf("Expected the working copy range, got %q", verification.DiffRange)
+	}
+
+	got := verification.Assessments
+	if len(got) != 3 || !got[0].Satisfied || got[0].Evidence != "login.go:3 returns 401" || got[1].Satisfied || got[2].Satisfied {
+		t.Fatalf("Unexpected assessments: %+v", got)
+	}
+	if got[2].Evidence != "Not assessed by the agent" {
+		t.Errorf("Expected the unassessed criterion noted, got %q", got[2].Evidence)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if !strings.Contains(output, "1. ✓ Login returns 401") || !strings.Contains(output, "No test file was added") || !strings.Contains(output, "1/3 criteria satisfied") {
+		t.Errorf("Expected the verdicts in show, got: %s", output)
+	}
+
+	// Editing the criteria makes the verification stale
+	runJuggleCommand(t, env.ProjectDir, "update", ball.ID, "--criteria", "Login returns 401 on a bad password")
+	output = runJuggleCommand(t, env.ProjectDir, "show", ball.ID)
+	if strings.Contains(output, "✓") || !strings.Contains(output, "criteria changed since") {
+		t.Errorf("Expected a stale verification, got: %s", output)
+	}
+}
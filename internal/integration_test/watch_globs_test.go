@@ -0,0 +1,39 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestWatchGlobs_AddListRemove tests managing a ball's watch globs via the CLI
+func TestWatchGlobs_AddListRemove(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	ball := env.CreateBall(t, "Rework auth", session.PriorityMedium)
+
+	runJuggleCommand(t, env.ProjectDir, "watch", "add", ball.ID, "src/auth/**", "go.mod")
+
+	reloaded := env.AssertBallExists(t, ball.ID)
+	if len(reloaded.WatchGlobs) != 2 || reloaded.WatchGlobs[0] != "src/auth/**" {
+		t.Fatalf("Expected watch globs to be saved, got %v", reloaded.WatchGlobs)
+	}
+
+	output := runJuggleCommand(t, env.ProjectDir, "watch", "list", ball.ID)
+	if !strings.Contains(output, "src/auth/**") || !strings.Contains(output, "go.mod") {
+		t.Errorf("Expected globs in list output, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "watch", "rm", ball.ID, "go.mod")
+	reloaded = env.AssertBallExists(t, ball.ID)
+	if len(reloaded.WatchGlobs) != 1 {
+		t.Errorf("Expected one watch glob after removal, got %v", reloaded.WatchGlobs)
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "watch", "add", ball.ID, "../elsewhere/**"); exitCode == 0 {
+		t.Error("Expected glob outside the project to be rejected")
+	}
+}
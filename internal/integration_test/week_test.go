@@ -0,0 +1,89 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// TestWeekPlanning tests planning balls into a week, capacity, the week session, and the review
+func TestWeekPlanning(t *testing.T) {
+	env := SetupTestEnv(t)
+	defer CleanupTestEnv(t, env)
+
+	setupConfigWithTestProject(t, env)
+	large := env.CreateBall(t, "Rewrite parser", session.PriorityHigh)
+	small := env.CreateBall(t, "Fix typo", session.PriorityLow)
+	env.CreateBall(t, "Not planned", session.PriorityMedium)
+
+	store := env.GetStore(t)
+	large.ModelSize = session.ModelSizeLarge
+	if err := store.UpdateBall(large); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	small.ModelSize = session.ModelSizeSmall
+	if err := store.UpdateBall(small); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	const week = "2026-W42"
+	tag := session.WeekTag(week)
+
+	runJuggleCommand(t, env.ProjectDir, "week", "add", large.ID, small.ID, "--week", week)
+	runJuggleCommand(t, env.ProjectDir, "week", "capacity", "3")
+
+	output := runJuggleCommand(t, env.ProjectDir, "week", "--week", week)
+	if !strings.Contains(output, "Planned: 4 points in 2 ball(s)") {
+		t.Errorf("Expected planned points in output, got: %s", output)
+	}
+	if !strings.Contains(output, "over by 1") {
+		t.Errorf("Expected over-capacity warning, got: %s", output)
+	}
+	if strings.Contains(output, "Not planned") {
+		t.Errorf("Expected unplanned ball to be excluded, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "week", "candidates", "--week", week)
+	if !strings.Contains(output, "Not planned") || strings.Contains(output, "Rewrite parser") {
+		t.Errorf("Expected only the unplanned ball as a candidate, got: %s", output)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "week", "session", "--week", week)
+	if !strings.Contains(output, "Created session "+tag) {
+		t.Errorf("Expected week session to be created, got: %s", output)
+	}
+	output = runJuggleCommand(t, env.ProjectDir, "week", "session", "--week", week)
+	if !strings.Contains(output, "already exists") {
+		t.Errorf("Expected existing week session to be reused, got: %s", output)
+	}
+
+	small.Tags = []string{tag}
+	small.SetState(session.StateComplete)
+	if err := store.UpdateBall(small); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	output = runJuggleCommand(t, env.ProjectDir, "week", "review", "--week", week)
+	if !strings.Contains(output, "Done:    1 ball(s), 1 points (25%)") {
+		t.Errorf("Expected done summary in review, got: %s", output)
+	}
+	if !strings.Contains(output, "Not done:") || !strings.Contains(output, "Rewrite parser") {
+		t.Errorf("Expected unfinished planned ball in review, got: %s", output)
+	}
+
+	runJuggleCommand(t, env.ProjectDir, "week", "rm", large.ID, "--week", week)
+	reloaded, err := store.GetBallByID(large.ID)
+	if err != nil {
+		t.Fatalf("Failed to load ball: %v", err)
+	}
+	for _, ballTag := range reloaded.Tags {
+		if ballTag == tag {
+			t.Errorf("Expected week tag to be removed, got tags %v", reloaded.Tags)
+		}
+	}
+
+	if _, exitCode := runJuggleCommandWithError(t, env.ProjectDir, "week", "--week", "2026-W99"); exitCode == 0 {
+		t.Error("Expected invalid week to fail")
+	}
+}
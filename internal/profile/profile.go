@@ -0,0 +1,204 @@
+// Package profile records how long juggle's hot paths take - TUI frame
+// renders, store loads, watcher event delivery and agent prompt builds - so
+// slowness on large stories can be diagnosed without ad-hoc instrumentation.
+//
+// Profiling is opt-in (juggle --profile) and local only: timings are written
+// to a JSONL log and optionally served alongside the net/http/pprof
+// endpoints. When profiling is disabled every hook is a cheap no-op.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kind groups timings by the part of juggle they measure
+type Kind string
+
+const (
+	KindRender  Kind = "render"  // TUI frame renders
+	KindStore   Kind = "store"   // Loading balls and sessions from disk
+	KindWatcher Kind = "watcher" // File change to TUI delivery latency
+	KindPrompt  Kind = "prompt"  // Building agent prompts
+)
+
+// Entry is one timing written to the profile log
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Kind       Kind      `json:"kind"`
+	Name       string    `json:"name"`
+	DurationMs float64   `json:"duration_ms"`
+}
+
+// Stat summarizes all timings recorded for a kind and name
+type Stat struct {
+	Kind  Kind          `json:"kind"`
+	Name  string        `json:"name"`
+	Count int           `json:"count"`
+	Total time.Duration `json:"total_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// Avg returns the mean duration of the recorded timings
+func (s Stat) Avg() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+type statKey struct {
+	kind Kind
+	name string
+}
+
+var (
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	logFile *os.File
+	encoder *json.Encoder
+	stats   map[statKey]*Stat
+)
+
+// Enable starts recording timings, appending them to the log at path
+func Enable(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open profile log: %w", err)
+	}
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = f
+	encoder = json.NewEncoder(f)
+	stats = make(map[statKey]*Stat)
+	enabled.Store(true)
+	return nil
+}
+
+// Enabled reports whether timings are being recorded
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Close stops recording and closes the profile log. Stats remain available.
+func Close() error {
+	enabled.Store(false)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if logFile == nil {
+		return nil
+	}
+	err := logFile.Close()
+	logFile = nil
+	encoder = nil
+	return err
+}
+
+// Start begins timing an operation and returns a func that records it, so
+// a whole function can be measured with defer profile.Start(kind, name)()
+func Start(kind Kind, name string) func() {
+	if !Enabled() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		Record(kind, name, time.Since(start))
+	}
+}
+
+// Record logs a timing measured by the caller
+func Record(kind Kind, name string, d time.Duration) {
+	if !Enabled() {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if encoder == nil {
+		return
+	}
+
+	key := statKey{kind, name}
+	stat, ok := stats[key]
+	if !ok {
+		stat = &Stat{Kind: kind, Name: name}
+		stats[key] = stat
+	}
+	stat.Count++
+	stat.Total += d
+	if d > stat.Max {
+		stat.Max = d
+	}
+
+	// A failed write shouldn't interrupt the operation being profiled
+	_ = encoder.Encode(Entry{
+		Time:       time.Now(),
+		Kind:       kind,
+		Name:       name,
+		DurationMs: float64(d) / float64(time.Millisecond),
+	})
+}
+
+// Stats returns a summary of the timings recorded since Enable, sorted by
+// kind then name
+func Stats() []Stat {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := make([]Stat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Handler returns the pprof endpoints under /debug/pprof/ plus a JSON
+// summary of recorded timings at /debug/juggle/profile
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/juggle/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Stats())
+	})
+	return mux
+}
+
+// Serve listens on addr and serves Handler in the background, returning
+// the address actually bound (useful when addr uses port 0)
+func Serve(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	go http.Serve(listener, Handler())
+	return listener.Addr().String(), nil
+}
@@ -0,0 +1,97 @@
+package profile
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordDisabledIsNoop(t *testing.T) {
+	Close()
+	Start(KindRender, "frame")()
+	Record(KindStore, "load_balls", time.Millisecond)
+	if Enabled() {
+		t.Fatal("Expected profiling to be disabled")
+	}
+}
+
+func TestRecordWritesLogAndStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".juggle", "profile.jsonl")
+	if err := Enable(path); err != nil {
+		t.Fatalf("Enable failed: %v", err)
+	}
+	defer Close()
+
+	Record(KindStore, "load_balls", 2*time.Millisecond)
+	Record(KindStore, "load_balls", 4*time.Millisecond)
+	Start(KindPrompt, "agent")()
+	if err := Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	// Recording after Close is dropped
+	Record(KindStore, "load_balls", time.Second)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	defer f.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 log entries, got %d", len(entries))
+	}
+	if entries[1].Kind != KindStore || entries[1].Name != "load_balls" || entries[1].DurationMs != 4 {
+		t.Errorf("Unexpected entry: %+v", entries[1])
+	}
+
+	stats := Stats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 stats, got %+v", stats)
+	}
+	// Sorted by kind: prompt before store
+	store := stats[1]
+	if store.Kind != KindStore || store.Count != 2 || store.Max != 4*time.Millisecond || store.Avg() != 3*time.Millisecond {
+		t.Errorf("Unexpected store stat: %+v", store)
+	}
+}
+
+func TestHandlerServesPprofAndStats(t *testing.T) {
+	if err := Enable(filepath.Join(t.TempDir(), "profile.jsonl")); err != nil {
+		t.Fatalf("Enable failed: %v", err)
+	}
+	defer Close()
+	Record(KindRender, "frame", time.Millisecond)
+
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	for path, want := range map[string]string{
+		"/debug/pprof/":         "goroutine",
+		"/debug/juggle/profile": `"name":"frame"`,
+	} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Errorf("GET %s: status %d, expected body containing %q", path, resp.StatusCode, want)
+		}
+	}
+}
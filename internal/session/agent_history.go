@@ -13,6 +13,12 @@ const (
 	historyFile = "agent_history.jsonl"
 )
 
+// Phases of an 'agent plan-then-run' workflow, recorded on each of its runs
+const (
+	RunPhasePlan      = "plan"      // Read-only run that proposed balls
+	RunPhaseImplement = "implement" // Run that worked on the approved balls
+)
+
 // AgentRunRecord stores information about a past agent run
 type AgentRunRecord struct {
 	ID             string        `json:"id"`              // Unique run ID (timestamp-based)
@@ -31,11 +37,53 @@ type AgentRunRecord struct {
 	TotalWaitTime  time.Duration `json:"total_wait_time"` // Time spent waiting for rate limits
 	OutputFile     string        `json:"output_file"`     // Path to last_output.txt
 	ProjectDir     string        `json:"project_dir"`     // Project directory where agent ran
+	Label          string        `json:"label,omitempty"` // Set at launch (agent run --label) to tell experiments apart
+	Note           string        `json:"note,omitempty"`  // Added after the run (agent history annotate)
+
+	// Set on the two runs of an 'agent plan-then-run' workflow, each linking
+	// to the other (the plan run has no link if nothing was approved)
+	Phase       string `json:"phase,omitempty"`
+	LinkedRunID string `json:"linked_run_id,omitempty"`
+
+	// Last loop signal a person sent mid-run (agent signal), which overrides
+	// the agent's own
+	HumanSignal LoopSignal `json:"human_signal,omitempty"`
+
+	// What safe mode did with the run's changes (agent run --safe only)
+	SafeMode *SafeModeOutcome `json:"safe_mode,omitempty"`
+
+	// Secrets scrubbed from prompts and saved output, by rule
+	Redactions ScrubReport `json:"redactions,omitempty"`
+
+	// Log the agent's output was streamed to under .juggle/agent-logs,
+	// covering every iteration (OutputFile only has the last)
+	LogFile string `json:"log_file,omitempty"`
+
+	// How each iteration ended, in order. Retried attempts aren't included.
+	IterationOutcomes []IterationOutcome `json:"iteration_outcomes,omitempty"`
+}
+
+// How an agent run's iteration ended, as recorded in IterationOutcome
+const (
+	IterationCompleted      = "completed"       // COMPLETE signal, accepted
+	IterationContinued      = "continued"       // CONTINUE signal, accepted
+	IterationBlocked        = "blocked"         // BLOCKED signal, accepted
+	IterationRejectedSignal = "rejected-signal" // A signal was given but rejected, e.g. progress wasn't updated
+	IterationNoSignal       = "no-signal"       // No signal was given
+	IterationTimeout        = "timeout"         // The iteration timed out
+	IterationHumanOverride  = "human-override"  // A person's signal (agent signal) replaced the agent's own
+)
+
+// IterationOutcome is how one iteration of an agent run ended
+type IterationOutcome struct {
+	Iteration int    `json:"iteration"`
+	Outcome   string `json:"outcome"`
+	Model     string `json:"model,omitempty"` // Model the iteration ran with
 }
 
 // NewAgentRunRecord creates a new agent run record with a unique ID
 func NewAgentRunRecord(sessionID, projectDir string, startTime time.Time) *AgentRunRecord {
-	id := fmt.Sprintf("%d", startTime.UnixNano())
+	id := AgentRunID(startTime)
 	return &AgentRunRecord{
 		ID:         id,
 		SessionID:  sessionID,
@@ -118,6 +166,36 @@ func (r *AgentRunRecord) SetError(iterations int, errMsg string, ballsComplete,
 	r.EndedAt = time.Now()
 }
 
+// PhaseSummary describes the run's part in a plan-then-run workflow, or
+// returns "" for ordinary runs
+func (r *AgentRunRecord) PhaseSummary() string {
+	switch {
+	case r.Phase == RunPhasePlan && r.LinkedRunID != "":
+		return "plan, implemented by run " + r.LinkedRunID
+	case r.Phase == RunPhaseImplement && r.LinkedRunID != "":
+		return "implement, planned by run " + r.LinkedRunID
+	}
+	return r.Phase
+}
+
+// Outcome describes how the run ended, e.g. "blocked: needs an API key
+// (2/5 balls done)"
+func (r *AgentRunRecord) Outcome() string {
+	outcome := r.Result
+	switch {
+	case r.BlockedReason != "":
+		outcome += ": " + r.BlockedReason
+	case r.ErrorMessage != "":
+		outcome += ": " + r.ErrorMessage
+	case r.TimeoutMessage != "":
+		outcome += ": " + r.TimeoutMessage
+	}
+	if r.BallsTotal > 0 {
+		outcome += fmt.Sprintf(" (%d/%d balls done)", r.BallsComplete, r.BallsTotal)
+	}
+	return outcome
+}
+
 // Duration returns the duration of the run
 func (r *AgentRunRecord) Duration() time.Duration {
 	if r.EndedAt.IsZero() {
@@ -187,6 +265,45 @@ func (s *AgentHistoryStore) AppendRecord(record *AgentRunRecord) error {
 	return nil
 }
 
+// UpdateRecord applies edit to the run with the given ID ("latest" selects
+// the most recent run) and rewrites the history file
+func (s *AgentHistoryStore) UpdateRecord(runID string, edit func(record *AgentRunRecord)) (*AgentRunRecord, error) {
+	target, err := s.FindRecord(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(s.historyFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	// Rewrite only the matching line so malformed or unknown records survive
+	var out []byte
+	for _, line := range splitLines(string(data)) {
+		if len(line) == 0 {
+			continue
+		}
+		var record AgentRunRecord
+		if err := json.Unmarshal([]byte(line), &record); err == nil && record.ID == target.ID {
+			edit(&record)
+			updated, err := json.Marshal(&record)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal record: %w", err)
+			}
+			line = string(updated)
+			target = &record
+		}
+		out = append(out, line...)
+		out = append(out, '\n')
+	}
+
+	if err := os.WriteFile(s.historyFilePath(), out, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write history file: %w", err)
+	}
+	return target, nil
+}
+
 // LoadHistory loads all agent run records from the history file
 func (s *AgentHistoryStore) LoadHistory() ([]*AgentRunRecord, error) {
 	filePath := s.historyFilePath()
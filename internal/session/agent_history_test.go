@@ -1,8 +1,10 @@
 package session
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -295,3 +297,195 @@ func TestAgentHistoryStore_HistoryFilePath(t *testing.T) {
 		t.Errorf("Expected path '%s', got '%s'", expectedPath, actualPath)
 	}
 }
+
+func TestAgentHistoryStore_IterationOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewAgentHistoryStore(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+
+	start := time.Now()
+	runID := AgentRunID(start)
+	if record := NewAgentRunRecord("session1", tmpDir, start); record.ID != runID {
+		t.Errorf("Expected record ID %s to match run ID %s", record.ID, runID)
+	}
+
+	// A retried iteration replaces the earlier attempt
+	if err := store.SaveIterationOutput(runID, 2, "first attempt"); err != nil {
+		t.Fatalf("Failed to save iteration output: %v", err)
+	}
+	if err := store.SaveIterationOutput(runID, 2, "second attempt"); err != nil {
+		t.Fatalf("Failed to save iteration output: %v", err)
+	}
+	got, err := store.LoadIterationOutput(runID, 2)
+	if err != nil {
+		t.Fatalf("Failed to load iteration output: %v", err)
+	}
+	if got != "second attempt" {
+		t.Errorf("Expected latest attempt, got %q", got)
+	}
+	if _, err := store.LoadIterationOutput(runID, 3); err == nil {
+		t.Error("Expected error for missing iteration")
+	}
+
+	// Pruning keeps the most recent runs only
+	for i := 1; i <= 3; i++ {
+		id := AgentRunID(start.Add(time.Duration(i) * time.Minute))
+		if err := store.SaveIterationOutput(id, 1, "output"); err != nil {
+			t.Fatalf("Failed to save iteration output: %v", err)
+		}
+	}
+	if err := store.PruneIterationOutputs(2); err != nil {
+		t.Fatalf("Failed to prune: %v", err)
+	}
+	if _, err := store.LoadIterationOutput(runID, 2); err == nil {
+		t.Error("Expected oldest run to be pruned")
+	}
+	if _, err := store.LoadIterationOutput(AgentRunID(start.Add(3*time.Minute)), 1); err != nil {
+		t.Errorf("Expected newest run to be kept: %v", err)
+	}
+}
+
+func TestAgentHistoryStore_UpdateRecord(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewAgentHistoryStore(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+
+	older := NewAgentRunRecord("session1", tmpDir, time.Now().Add(-2*time.Hour))
+	older.Label = "baseline"
+	older.SetComplete(2, 1, 0, 1)
+	store.AppendRecord(older)
+
+	newer := NewAgentRunRecord("session1", tmpDir, time.Now().Add(-1*time.Hour))
+	newer.Label = "attempt-2-after-prompt-fix"
+	newer.SetBlocked(4, "flaky test", 0, 1, 1)
+	store.AppendRecord(newer)
+
+	// Malformed lines are kept as-is
+	f, err := os.OpenFile(filepath.Join(tmpDir, ".juggle", "agent_history.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open history file: %v", err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	updated, err := store.UpdateRecord("latest", func(record *AgentRunRecord) {
+		record.Note = "Prompt fix helped"
+	})
+	if err != nil {
+		t.Fatalf("UpdateRecord failed: %v", err)
+	}
+	if updated.ID != newer.ID || updated.Note != "Prompt fix helped" {
+		t.Errorf("Expected the latest run to be noted, got %s %q", updated.ID, updated.Note)
+	}
+
+	records, err := store.LoadHistory()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(records) != 2 || records[0].Note != "Prompt fix helped" || records[0].Label != "attempt-2-after-prompt-fix" || records[1].Note != "" {
+		t.Errorf("Expected only the latest run noted with its label kept, got %+v", records)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, ".juggle", "agent_history.jsonl"))
+	if !strings.Contains(string(data), "not json") {
+		t.Error("Expected malformed line to survive the rewrite")
+	}
+
+	if _, err := store.UpdateRecord("missing", func(*AgentRunRecord) {}); err == nil {
+		t.Error("Expected error for unknown run ID")
+	}
+}
+
+func TestRunBudget(t *testing.T) {
+	start := time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC)
+	budget := RunBudget{ActionableBalls: 6, MaxIterations: 10, Timeout: 30 * time.Minute, IterDelay: 5 * time.Minute, PromptChars: 8001}
+
+	if budget.PromptTokens() != 2001 || budget.MaxPromptTokens() != 20010 {
+		t.Errorf("Unexpected token estimates %d/%d", budget.PromptTokens(), budget.MaxPromptTokens())
+	}
+	if budget.ExpectedIterations() != 6 || budget.ExpectedDuration() != 0 {
+		t.Errorf("Expected one iteration per ball and no duration without history, got %d %v",
+			budget.ExpectedIterations(), budget.ExpectedDuration())
+	}
+	if budget.MaxDuration() != 10*30*time.Minute+9*5*time.Minute {
+		t.Errorf("Unexpected max duration %v", budget.MaxDuration())
+	}
+
+	budget.UseHistory([]*AgentRunRecord{
+		// 4 iterations in 50m, of which 10m was rate limit waiting; 2 balls done
+		{StartedAt: start, EndedAt: start.Add(50 * time.Minute), Iterations: 4, BallsComplete: 2, TotalWaitTime: 10 * time.Minute},
+		// Never got through an iteration
+		{StartedAt: start, EndedAt: start.Add(time.Minute)},
+	})
+	if budget.HistoryRuns != 1 || budget.AvgIteration != 10*time.Minute || budget.AvgBallsPerIteration != 0.5 {
+		t.Errorf("Unexpected history averages %+v", budget)
+	}
+	// 6 balls at half a ball per iteration needs 12, capped at 10
+	if budget.ExpectedIterations() != 10 || budget.ExpectedDuration() != 10*10*time.Minute+9*5*time.Minute {
+		t.Errorf("Unexpected expectations %d %v", budget.ExpectedIterations(), budget.ExpectedDuration())
+	}
+
+	budget.Timeout = 0
+	if budget.MaxDuration() != 0 {
+		t.Errorf("Expected no bound without a timeout, got %v", budget.MaxDuration())
+	}
+}
+
+func TestAgentHistoryStore_BuildDataset(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewAgentHistoryStore(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	older := NewAgentRunRecord("auth", tmpDir, start)
+	older.SetBlocked(2, "needs keys", 0, 1, 1)
+	older.IterationOutcomes = []IterationOutcome{
+		{Iteration: 1, Outcome: IterationRejectedSignal, Model: "sonnet"},
+		{Iteration: 2, Outcome: IterationBlocked, Model: "opus"},
+	}
+	newer := NewAgentRunRecord("billing", tmpDir, start.Add(time.Minute))
+	newer.SetComplete(1, 1, 0, 1)
+	newer.IterationOutcomes = []IterationOutcome{{Iteration: 1, Outcome: IterationCompleted}}
+	// A run from before prompts were saved
+	legacy := NewAgentRunRecord("auth", tmpDir, start.Add(2*time.Minute))
+	legacy.SetComplete(1, 1, 0, 1)
+	legacy.IterationOutcomes = []IterationOutcome{{Iteration: 1, Outcome: IterationCompleted}}
+
+	for _, record := range []*AgentRunRecord{older, newer, legacy} {
+		if err := store.AppendRecord(record); err != nil {
+			t.Fatalf("Failed to append record: %v", err)
+		}
+		for _, outcome := range record.IterationOutcomes {
+			if record != legacy {
+				_ = store.SaveIterationPrompt(record.ID, outcome.Iteration, fmt.Sprintf("prompt %s %d", record.SessionID, outcome.Iteration))
+			}
+			_ = store.SaveIterationOutput(record.ID, outcome.Iteration, fmt.Sprintf("response %s %d", record.SessionID, outcome.Iteration))
+		}
+	}
+
+	examples, err := store.BuildDataset(DatasetFilter{})
+	if err != nil {
+		t.Fatalf("BuildDataset failed: %v", err)
+	}
+	if len(examples) != 3 {
+		t.Fatalf("Expected 3 examples without the legacy run, got %+v", examples)
+	}
+	first := examples[0]
+	if first.RunID != older.ID || first.Iteration != 1 || first.Prompt != "prompt auth 1" || first.Response != "response auth 1" ||
+		first.Outcome != IterationRejectedSignal || first.RunResult != "blocked" || first.Model != "sonnet" {
+		t.Errorf("Unexpected first example: %+v", first)
+	}
+	if examples[2].SessionID != "billing" {
+		t.Errorf("Expected oldest run first, got %+v", examples)
+	}
+
+	examples, _ = store.BuildDataset(DatasetFilter{Sessions: []string{"auth"}, Outcomes: []string{IterationBlocked}})
+	if len(examples) != 1 || examples[0].Iteration != 2 {
+		t.Errorf("Expected only the blocked auth iteration, got %+v", examples)
+	}
+}
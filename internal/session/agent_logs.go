@@ -0,0 +1,67 @@
+package session
+
+import "fmt"
+
+// AgentLogsConfig configures the logs agent output is streamed to under
+// .juggle/agent-logs. Zero fields use the agentlog defaults.
+type AgentLogsConfig struct {
+	MaxSizeMB     int `json:"max_size_mb,omitempty"`    // Size a log reaches before it's rotated
+	MaxFiles      int `json:"max_files,omitempty"`      // Rotated parts kept per run
+	RetentionDays int `json:"retention_days,omitempty"` // Days logs are kept
+}
+
+// SetAgentLogs validates and stores the agent log settings
+func (c *Config) SetAgentLogs(logs *AgentLogsConfig) error {
+	if logs.MaxSizeMB < 0 {
+		return fmt.Errorf("max size must not be negative, got %d", logs.MaxSizeMB)
+	}
+	if logs.MaxFiles < 0 {
+		return fmt.Errorf("max files must not be negative, got %d", logs.MaxFiles)
+	}
+	if logs.RetentionDays < 0 {
+		return fmt.Errorf("retention must not be negative, got %d", logs.RetentionDays)
+	}
+	c.AgentLogs = logs
+	return nil
+}
+
+// GetAgentLogs returns the agent log settings (never nil)
+func (c *Config) GetAgentLogs() *AgentLogsConfig {
+	if c.AgentLogs == nil {
+		return &AgentLogsConfig{}
+	}
+	return c.AgentLogs
+}
+
+// GetGlobalAgentLogsWithOptions returns the agent log settings from global config
+func GetGlobalAgentLogsWithOptions(opts ConfigOptions) (*AgentLogsConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &AgentLogsConfig{}, err
+	}
+	return config.GetAgentLogs(), nil
+}
+
+// UpdateGlobalAgentLogsWithOptions applies edit to the agent log settings in global config
+func UpdateGlobalAgentLogsWithOptions(opts ConfigOptions, edit func(logs *AgentLogsConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	logs := *config.GetAgentLogs()
+	edit(&logs)
+	if err := config.SetAgentLogs(&logs); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalAgentLogsWithOptions removes the agent log settings from global config
+func ClearGlobalAgentLogsWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.AgentLogs = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,81 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const agentPauseFile = "agent_pause.json"
+
+// AgentPause marks a session's agent loop as paused. The loop lets the
+// iteration in progress finish, then holds (keeping the session lock) until
+// the marker is removed, so a run can be stopped without killing the agent
+// mid-edit.
+type AgentPause struct {
+	SessionID   string    `json:"session_id"`
+	RequestedAt time.Time `json:"requested_at"`
+	Source      string    `json:"source,omitempty"`    // Where it was requested from ("cli" or "tui")
+	PausedAt    time.Time `json:"paused_at,omitempty"` // When the loop stopped to wait; zero while an iteration finishes
+}
+
+// Holding reports whether the loop has stopped and is waiting
+func (p *AgentPause) Holding() bool {
+	return !p.PausedAt.IsZero()
+}
+
+// agentPauseFilePath returns the path to a session's pause marker
+func (s *SessionStore) agentPauseFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), agentPauseFile)
+}
+
+// SaveAgentPause writes the pause marker for the session's agent loop
+func (s *SessionStore) SaveAgentPause(p *AgentPause) error {
+	if err := os.MkdirAll(s.sessionPath(p.SessionID), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if p.RequestedAt.IsZero() {
+		p.RequestedAt = time.Now()
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pause: %w", err)
+	}
+	// Write then rename, so the loop never reads half a marker
+	path := s.agentPauseFilePath(p.SessionID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write pause: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write pause: %w", err)
+	}
+	return nil
+}
+
+// LoadAgentPause returns the session's pause marker, or nil if its agent
+// loop isn't paused
+func (s *SessionStore) LoadAgentPause(id string) (*AgentPause, error) {
+	data, err := os.ReadFile(s.agentPauseFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pause: %w", err)
+	}
+	var p AgentPause
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse pause: %w", err)
+	}
+	return &p, nil
+}
+
+// ClearAgentPause removes the session's pause marker, letting a paused
+// loop carry on
+func (s *SessionStore) ClearAgentPause(id string) error {
+	if err := os.Remove(s.agentPauseFilePath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear pause: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// AgentSignalsConfig configures how COMPLETE/CONTINUE/BLOCKED promise
+// signals are detected in agent output. By default detection is tolerant:
+// tags are matched case-insensitively, markdown around them is ignored, and
+// a JSON form is accepted as well.
+type AgentSignalsConfig struct {
+	Strict bool     `json:"strict,omitempty"` // Only accept the exact <promise>KEYWORD</promise> form
+	Tags   []string `json:"tags,omitempty"`   // Extra tag names accepted alongside "promise"
+}
+
+// signalTagPattern matches tag names that can appear in <tag>...</tag>
+var signalTagPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// ValidateSignalTag checks if a signal tag name is valid
+func ValidateSignalTag(tag string) bool {
+	return signalTagPattern.MatchString(tag)
+}
+
+// SetAgentSignals validates and stores the signal detection settings
+func (c *Config) SetAgentSignals(signals *AgentSignalsConfig) error {
+	for _, tag := range signals.Tags {
+		if !ValidateSignalTag(tag) {
+			return fmt.Errorf("invalid signal tag %q (must start with a letter and contain only letters, digits, - and _)", tag)
+		}
+	}
+	if !signals.Strict && len(signals.Tags) == 0 {
+		c.AgentSignals = nil
+		return nil
+	}
+	c.AgentSignals = signals
+	return nil
+}
+
+// GetAgentSignals returns the signal detection settings (never nil)
+func (c *Config) GetAgentSignals() *AgentSignalsConfig {
+	if c.AgentSignals == nil {
+		return &AgentSignalsConfig{}
+	}
+	return c.AgentSignals
+}
+
+// GetGlobalAgentSignalsWithOptions returns the signal detection settings from global config
+func GetGlobalAgentSignalsWithOptions(opts ConfigOptions) (*AgentSignalsConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &AgentSignalsConfig{}, err
+	}
+	return config.GetAgentSignals(), nil
+}
+
+// UpdateGlobalAgentSignalsWithOptions applies edit to the signal detection settings in global config
+func UpdateGlobalAgentSignalsWithOptions(opts ConfigOptions, edit func(signals *AgentSignalsConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	signals := *config.GetAgentSignals()
+	signals.Tags = append([]string(nil), signals.Tags...)
+	edit(&signals)
+	if err := config.SetAgentSignals(&signals); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalAgentSignalsWithOptions removes the signal detection settings from global config
+func ClearGlobalAgentSignalsWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.AgentSignals = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,135 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const agentStateDir = "agent-state"
+
+// AgentCheckpoint is the state of an agent loop, saved before each
+// iteration so a run killed partway (laptop sleep, crash) can be resumed
+// with the iteration budget it had left. A run that ends removes it.
+type AgentCheckpoint struct {
+	SessionID     string           `json:"session_id"`
+	Iteration     int              `json:"iteration"` // Iterations finished
+	MaxIterations int              `json:"max_iterations"`
+	WaitTime      time.Duration    `json:"wait_time"`             // Rate limit and overload waits so far
+	LastSignal    string           `json:"last_signal,omitempty"` // Outcome of the last finished iteration
+	PID           int              `json:"pid"`
+	Hostname      string           `json:"hostname"`
+	StartedAt     time.Time        `json:"started_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	Settings      AgentRunSettings `json:"settings"`
+}
+
+// AgentRunSettings are the settings a checkpointed run was started with,
+// so resuming it runs the same way
+type AgentRunSettings struct {
+	Model            string        `json:"model,omitempty"`
+	Provider         string        `json:"provider,omitempty"`
+	Trust            bool          `json:"trust,omitempty"`
+	Interactive      bool          `json:"interactive,omitempty"`
+	Timeout          time.Duration `json:"timeout,omitempty"`
+	IterDelay        time.Duration `json:"iter_delay,omitempty"`
+	MaxWait          time.Duration `json:"max_wait,omitempty"`
+	Message          string        `json:"message,omitempty"`
+	IgnoreQuietHours bool          `json:"ignore_quiet_hours,omitempty"`
+	IgnoreBrownout   bool          `json:"ignore_brownout,omitempty"`
+	SafeMode         bool          `json:"safe_mode,omitempty"`
+	Label            string        `json:"label,omitempty"`
+	WorkDir          string        `json:"work_dir,omitempty"` // The agent worktree it worked in, if any
+}
+
+// Remaining returns how many iterations the run had left
+func (c *AgentCheckpoint) Remaining() int {
+	return max(c.MaxIterations-c.Iteration, 0)
+}
+
+// agentCheckpointPath returns the path of a session's checkpoint
+func (s *SessionStore) agentCheckpointPath(id string) string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, agentStateDir, id+".json")
+}
+
+// SaveAgentCheckpoint records the state of the agent loop running on
+// session id
+func (s *SessionStore) SaveAgentCheckpoint(id string, c *AgentCheckpoint) error {
+	path := s.agentCheckpointPath(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create agent state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent checkpoint: %w", err)
+	}
+	// Written whole and renamed into place, so a run killed mid-write
+	// leaves the previous checkpoint
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write agent checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write agent checkpoint: %w", err)
+	}
+	return nil
+}
+
+// LoadAgentCheckpoint returns session id's checkpoint, or nil if it has none
+func (s *SessionStore) LoadAgentCheckpoint(id string) (*AgentCheckpoint, error) {
+	data, err := os.ReadFile(s.agentCheckpointPath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent checkpoint: %w", err)
+	}
+	var c AgentCheckpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse agent checkpoint: %w", err)
+	}
+	return &c, nil
+}
+
+// ClearAgentCheckpoint removes session id's checkpoint
+func (s *SessionStore) ClearAgentCheckpoint(id string) error {
+	if err := os.Remove(s.agentCheckpointPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear agent checkpoint: %w", err)
+	}
+	return nil
+}
+
+// StaleAgentCheckpoints returns the checkpoints no running agent holds the
+// session lock for, left by runs that were killed, oldest first
+func (s *SessionStore) StaleAgentCheckpoints() ([]*AgentCheckpoint, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, agentStateDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent state directory: %w", err)
+	}
+	var stale []*AgentCheckpoint
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if locked, _ := s.IsLocked(id); locked {
+			continue
+		}
+		c, err := s.LoadAgentCheckpoint(id)
+		if err != nil || c == nil {
+			continue
+		}
+		stale = append(stale, c)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].UpdatedAt.Before(stale[j].UpdatedAt)
+	})
+	return stale, nil
+}
@@ -0,0 +1,84 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAgentCheckpoint_SaveLoadClear(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	if c, err := store.LoadAgentCheckpoint("auth"); err != nil || c != nil {
+		t.Fatalf("expected no checkpoint, got %+v, %v", c, err)
+	}
+
+	saved := &AgentCheckpoint{
+		SessionID:     "auth",
+		Iteration:     3,
+		MaxIterations: 10,
+		WaitTime:      90 * time.Second,
+		LastSignal:    IterationContinued,
+		Settings:      AgentRunSettings{Model: "opus", Timeout: 20 * time.Minute},
+	}
+	if err := store.SaveAgentCheckpoint("auth", saved); err != nil {
+		t.Fatalf("failed to save checkpoint: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(store.ProjectDir(), ".juggle", "agent-state", "*")); len(matches) != 1 {
+		t.Errorf("expected just the checkpoint file in agent-state, got %v", matches)
+	}
+
+	loaded, err := store.LoadAgentCheckpoint("auth")
+	if err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if loaded.Iteration != 3 || loaded.WaitTime != 90*time.Second || loaded.Settings.Timeout != 20*time.Minute {
+		t.Errorf("checkpoint didn't round-trip: %+v", loaded)
+	}
+	if loaded.Remaining() != 7 {
+		t.Errorf("Remaining() = %d, want 7", loaded.Remaining())
+	}
+
+	if err := store.ClearAgentCheckpoint("auth"); err != nil {
+		t.Fatalf("failed to clear checkpoint: %v", err)
+	}
+	if c, _ := store.LoadAgentCheckpoint("auth"); c != nil {
+		t.Errorf("expected the checkpoint to be cleared, got %+v", c)
+	}
+	if err := store.ClearAgentCheckpoint("auth"); err != nil {
+		t.Errorf("clearing a missing checkpoint should succeed, got %v", err)
+	}
+}
+
+func TestStaleAgentCheckpoints(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	for _, id := range []string{"running", "killed"} {
+		if _, err := store.CreateSession(id, id); err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		if err := store.SaveAgentCheckpoint(id, &AgentCheckpoint{SessionID: id, MaxIterations: 5}); err != nil {
+			t.Fatalf("failed to save checkpoint: %v", err)
+		}
+	}
+
+	// A checkpoint whose run still holds the session lock isn't stale
+	lock, err := store.AcquireSessionLock("running")
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	defer lock.Release()
+
+	stale, err := store.StaleAgentCheckpoints()
+	if err != nil {
+		t.Fatalf("StaleAgentCheckpoints: %v", err)
+	}
+	if len(stale) != 1 || stale[0].SessionID != "killed" {
+		t.Errorf("expected only the killed run's checkpoint, got %+v", stale)
+	}
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	agentWorktreeFile     = "agent_worktree.json"
+	agentWorktreesDir     = "worktrees"
+	agentWorktreeBranchNS = "juggle/agent-"
+)
+
+// AgentWorktree is the git worktree a session's agent runs work in, on a
+// branch of its own, so they never touch the main working tree. The work
+// is reviewed, then merged or discarded (see juggle agent worktree).
+type AgentWorktree struct {
+	SessionID  string    `json:"session_id"`
+	Path       string    `json:"path"`
+	Branch     string    `json:"branch"`
+	Base       string    `json:"base"`        // Branch it was created from, and is merged back into
+	BaseCommit string    `json:"base_commit"` // Commit it was created at
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AgentWorktreeBranch returns the branch a session's agent runs work on
+func AgentWorktreeBranch(sessionID string) string {
+	return agentWorktreeBranchNS + sessionID
+}
+
+// AgentWorktreePath returns where a session's agent worktree is created
+func (s *SessionStore) AgentWorktreePath(id string) string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, agentWorktreesDir, id)
+}
+
+// agentWorktreeFilePath returns the path to a session's worktree record
+func (s *SessionStore) agentWorktreeFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), agentWorktreeFile)
+}
+
+// SaveAgentWorktree records the worktree of a session's agent runs
+func (s *SessionStore) SaveAgentWorktree(w *AgentWorktree) error {
+	if err := os.MkdirAll(s.sessionPath(w.SessionID), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = time.Now()
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent worktree: %w", err)
+	}
+	if err := os.WriteFile(s.agentWorktreeFilePath(w.SessionID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write agent worktree: %w", err)
+	}
+	return nil
+}
+
+// LoadAgentWorktree returns the worktree of a session's agent runs, or nil
+// if it has none
+func (s *SessionStore) LoadAgentWorktree(id string) (*AgentWorktree, error) {
+	data, err := os.ReadFile(s.agentWorktreeFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent worktree: %w", err)
+	}
+	var w AgentWorktree
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse agent worktree: %w", err)
+	}
+	return &w, nil
+}
+
+// ClearAgentWorktree removes the record of a session's agent worktree
+func (s *SessionStore) ClearAgentWorktree(id string) error {
+	if err := os.Remove(s.agentWorktreeFilePath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear agent worktree: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// apiHealthFile stores recent agent call outcomes in the config home, keyed
+// by provider, so every project's runs feed the same picture of a provider
+const apiHealthFile = "api-health.json"
+
+const (
+	// APIHealthWindow is how far back calls count toward a provider's health
+	APIHealthWindow = 6 * time.Hour
+
+	// apiHealthMaxSamples caps the calls remembered per provider
+	apiHealthMaxSamples = 50
+
+	// apiHealthRecentLimit is how recently a 429 or 529 makes a provider
+	// unhealthy rather than degraded
+	apiHealthRecentLimit = 15 * time.Minute
+)
+
+// API health statuses, from best to worst
+const (
+	APIHealthUnknown   = "unknown"   // No calls in the window
+	APIHealthHealthy   = "healthy"   // Few errors and no recent limits
+	APIHealthDegraded  = "degraded"  // Some errors, or a 429/529 in the window
+	APIHealthUnhealthy = "unhealthy" // Mostly errors, or a 429/529 in the last few minutes
+)
+
+// APIHealthSample is the outcome of one agent call
+type APIHealthSample struct {
+	At          time.Time `json:"at"`
+	LatencyMS   int64     `json:"latency_ms"`
+	Error       bool      `json:"error,omitempty"`        // Crashed, timed out, rate limited or overloaded
+	RateLimited bool      `json:"rate_limited,omitempty"` // 429
+	Overloaded  bool      `json:"overloaded,omitempty"`   // 529, after the provider's own retries
+}
+
+// APIHealth is the recent call history of one provider
+type APIHealth struct {
+	Samples         []APIHealthSample `json:"samples"`
+	LastRateLimited time.Time         `json:"last_rate_limited,omitempty"`
+	LastOverloaded  time.Time         `json:"last_overloaded,omitempty"`
+}
+
+// APIHealthSummary is a provider's health over the window
+type APIHealthSummary struct {
+	Provider        string        `json:"provider"`
+	Status          string        `json:"status"`
+	Calls           int           `json:"calls"`
+	Errors          int           `json:"errors"`
+	ErrorRate       float64       `json:"error_rate"`
+	AvgLatency      time.Duration `json:"avg_latency_ns"`
+	LastRateLimited time.Time     `json:"last_rate_limited,omitempty"`
+	LastOverloaded  time.Time     `json:"last_overloaded,omitempty"`
+}
+
+// Record adds a call outcome, dropping the oldest calls past the cap
+func (h *APIHealth) Record(sample APIHealthSample) {
+	h.Samples = append(h.Samples, sample)
+	if len(h.Samples) > apiHealthMaxSamples {
+		h.Samples = h.Samples[len(h.Samples)-apiHealthMaxSamples:]
+	}
+	if sample.RateLimited && sample.At.After(h.LastRateLimited) {
+		h.LastRateLimited = sample.At
+	}
+	if sample.Overloaded && sample.At.After(h.LastOverloaded) {
+		h.LastOverloaded = sample.At
+	}
+}
+
+// Summary returns the provider's health over the window ending at now
+func (h *APIHealth) Summary(provider string, now time.Time) APIHealthSummary {
+	summary := APIHealthSummary{
+		Provider:        provider,
+		LastRateLimited: h.LastRateLimited,
+		LastOverloaded:  h.LastOverloaded,
+	}
+	var latency time.Duration
+	cutoff := now.Add(-APIHealthWindow)
+	for _, sample := range h.Samples {
+		if sample.At.Before(cutoff) {
+			continue
+		}
+		summary.Calls++
+		latency += time.Duration(sample.LatencyMS) * time.Millisecond
+		if sample.Error {
+			summary.Errors++
+		}
+	}
+	if summary.Calls > 0 {
+		summary.ErrorRate = float64(summary.Errors) / float64(summary.Calls)
+		summary.AvgLatency = latency / time.Duration(summary.Calls)
+	}
+
+	lastLimit := h.LastRateLimited
+	if h.LastOverloaded.After(lastLimit) {
+		lastLimit = h.LastOverloaded
+	}
+	switch {
+	case summary.Calls == 0:
+		summary.Status = APIHealthUnknown
+	case now.Sub(lastLimit) < apiHealthRecentLimit, summary.Calls > 1 && summary.ErrorRate >= 0.5:
+		summary.Status = APIHealthUnhealthy
+	case !lastLimit.Before(cutoff), summary.ErrorRate >= 0.2:
+		summary.Status = APIHealthDegraded
+	default:
+		summary.Status = APIHealthHealthy
+	}
+	return summary
+}
+
+// apiHealthPath returns the path of the API health file
+func apiHealthPath(opts ConfigOptions) (string, error) {
+	if opts.ConfigHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.ConfigHome = home
+	}
+	return filepath.Join(opts.ConfigHome, opts.JuggleDirName, apiHealthFile), nil
+}
+
+// loadAPIHealth reads every provider's call history
+func loadAPIHealth(path string) (map[string]*APIHealth, error) {
+	health := make(map[string]*APIHealth)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return health, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API health: %w", err)
+	}
+	if err := json.Unmarshal(data, &health); err != nil {
+		return nil, fmt.Errorf("failed to parse API health: %w", err)
+	}
+	return health, nil
+}
+
+// LoadAPIHealthWithOptions returns the health of every provider with calls
+// in the window ending at now, sorted by provider
+func LoadAPIHealthWithOptions(opts ConfigOptions, now time.Time) ([]APIHealthSummary, error) {
+	path, err := apiHealthPath(opts)
+	if err != nil {
+		return nil, err
+	}
+	health, err := loadAPIHealth(path)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]APIHealthSummary, 0, len(health))
+	for provider, h := range health {
+		if summary := h.Summary(provider, now); summary.Status != APIHealthUnknown {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Provider < summaries[j].Provider })
+	return summaries, nil
+}
+
+// GetAPIHealthWithOptions returns one provider's health over the window
+// ending at now (unknown if it has no calls)
+func GetAPIHealthWithOptions(opts ConfigOptions, provider string, now time.Time) (APIHealthSummary, error) {
+	path, err := apiHealthPath(opts)
+	if err != nil {
+		return APIHealthSummary{Provider: provider, Status: APIHealthUnknown}, err
+	}
+	health, err := loadAPIHealth(path)
+	if err != nil {
+		return APIHealthSummary{Provider: provider, Status: APIHealthUnknown}, err
+	}
+	h, ok := health[provider]
+	if !ok {
+		h = &APIHealth{}
+	}
+	return h.Summary(provider, now), nil
+}
+
+// RecordAPIHealthWithOptions adds a call outcome to a provider's history.
+// Runs in other projects may record at the same time, so the file is
+// locked while it's rewritten.
+func RecordAPIHealthWithOptions(opts ConfigOptions, provider string, sample APIHealthSample) error {
+	path, err := apiHealthPath(opts)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	fileLock := flock.New(path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer fileLock.Unlock()
+
+	health, err := loadAPIHealth(path)
+	if err != nil {
+		// A corrupted history only loses past calls; start over
+		health = make(map[string]*APIHealth)
+	}
+	h, ok := health[provider]
+	if !ok {
+		h = &APIHealth{}
+		health[provider] = h
+	}
+	h.Record(sample)
+
+	data, err := json.MarshalIndent(health, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API health: %w", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write API health: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace API health: %w", err)
+	}
+	return nil
+}
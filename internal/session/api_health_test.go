@@ -0,0 +1,78 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAPIHealthSummary(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	call := func(ago time.Duration, latency time.Duration, failed bool) APIHealthSample {
+		return APIHealthSample{At: now.Add(-ago), LatencyMS: latency.Milliseconds(), Error: failed}
+	}
+
+	h := &APIHealth{}
+	if got := h.Summary("claude", now).Status; got != APIHealthUnknown {
+		t.Errorf("Expected unknown without calls, got %s", got)
+	}
+
+	h.Record(call(7*time.Hour, time.Minute, true)) // Outside the window
+	h.Record(call(time.Hour, 2*time.Minute, false))
+	h.Record(call(30*time.Minute, 4*time.Minute, false))
+	summary := h.Summary("claude", now)
+	if summary.Status != APIHealthHealthy || summary.Calls != 2 || summary.Errors != 0 || summary.AvgLatency != 3*time.Minute {
+		t.Errorf("Expected 2 healthy calls averaging 3m, got %+v", summary)
+	}
+
+	// A 429 earlier in the window degrades the provider; a recent one makes it unhealthy
+	limited := call(2*time.Hour, time.Second, true)
+	limited.RateLimited = true
+	h.Record(limited)
+	if summary := h.Summary("claude", now); summary.Status != APIHealthDegraded || summary.LastRateLimited != limited.At {
+		t.Errorf("Expected degraded after a 429 in the window, got %+v", summary)
+	}
+	overloaded := call(5*time.Minute, time.Second, true)
+	overloaded.Overloaded = true
+	h.Record(overloaded)
+	if summary := h.Summary("claude", now); summary.Status != APIHealthUnhealthy || summary.Errors != 2 {
+		t.Errorf("Expected unhealthy after a recent 529, got %+v", summary)
+	}
+	if summary := h.Summary("claude", now.Add(time.Hour)); summary.Status != APIHealthUnhealthy {
+		t.Errorf("Expected unhealthy with half the calls failing, got %+v", summary)
+	}
+}
+
+func TestAPIHealthRecordCapsSamples(t *testing.T) {
+	h := &APIHealth{}
+	start := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < apiHealthMaxSamples+10; i++ {
+		h.Record(APIHealthSample{At: start.Add(time.Duration(i) * time.Second)})
+	}
+	if len(h.Samples) != apiHealthMaxSamples || !h.Samples[0].At.Equal(start.Add(10*time.Second)) {
+		t.Errorf("Expected the newest %d calls kept, got %d starting %v", apiHealthMaxSamples, len(h.Samples), h.Samples[0].At)
+	}
+}
+
+func TestRecordAPIHealthWithOptions(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+	now := time.Now()
+	if err := RecordAPIHealthWithOptions(opts, "claude", APIHealthSample{At: now, LatencyMS: 1000}); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	if err := RecordAPIHealthWithOptions(opts, "ollama", APIHealthSample{At: now, LatencyMS: 500, Error: true}); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+
+	summaries, err := LoadAPIHealthWithOptions(opts, now)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].Provider != "claude" || summaries[1].Provider != "ollama" || summaries[1].Errors != 1 {
+		t.Errorf("Expected claude and ollama summaries, got %+v", summaries)
+	}
+
+	summary, err := GetAPIHealthWithOptions(opts, "opencode", now)
+	if err != nil || summary.Status != APIHealthUnknown {
+		t.Errorf("Expected unknown health for a provider without calls, got %+v (%v)", summary, err)
+	}
+}
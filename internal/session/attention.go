@@ -0,0 +1,89 @@
+package session
+
+import "fmt"
+
+// Attention events the TUI can signal
+const (
+	AttentionFinished = "finished" // An agent run ended (complete or out of iterations)
+	AttentionBlocked  = "blocked"  // An agent run ended blocked or with an error
+	AttentionConfirm  = "confirm"  // A prompt is waiting for an answer
+)
+
+// AttentionEvents lists the valid attention events
+var AttentionEvents = []string{AttentionFinished, AttentionBlocked, AttentionConfirm}
+
+// AttentionConfig configures how the TUI gets your attention when an agent
+// finishes or blocks, or a prompt is waiting, while its terminal is unfocused
+type AttentionConfig struct {
+	Bell   bool     `json:"bell,omitempty"`   // Ring the terminal bell
+	Tmux   bool     `json:"tmux,omitempty"`   // Flag the tmux window and set its @juggle_attention option
+	Title  bool     `json:"title,omitempty"`  // Show the event in the terminal title
+	Events []string `json:"events,omitempty"` // Events signalled (default: all)
+	Always bool     `json:"always,omitempty"` // Signal even while the TUI is focused
+}
+
+// Enabled returns true if any signal is turned on
+func (a *AttentionConfig) Enabled() bool {
+	return a != nil && (a.Bell || a.Tmux || a.Title)
+}
+
+// Wants returns true if the event should be signalled
+func (a *AttentionConfig) Wants(event string) bool {
+	if !a.Enabled() {
+		return false
+	}
+	return len(a.Events) == 0 || containsString(a.Events, event)
+}
+
+// SetAttention validates and stores the attention settings
+func (c *Config) SetAttention(attention *AttentionConfig) error {
+	for _, event := range attention.Events {
+		if !containsString(AttentionEvents, event) {
+			return fmt.Errorf("invalid event %q (must be finished, blocked, or confirm)", event)
+		}
+	}
+	c.Attention = attention
+	return nil
+}
+
+// GetAttention returns the attention settings (never nil)
+func (c *Config) GetAttention() *AttentionConfig {
+	if c.Attention == nil {
+		return &AttentionConfig{}
+	}
+	return c.Attention
+}
+
+// GetGlobalAttentionWithOptions returns the attention settings from global config
+func GetGlobalAttentionWithOptions(opts ConfigOptions) (*AttentionConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &AttentionConfig{}, err
+	}
+	return config.GetAttention(), nil
+}
+
+// UpdateGlobalAttentionWithOptions applies edit to the attention settings in global config
+func UpdateGlobalAttentionWithOptions(opts ConfigOptions, edit func(attention *AttentionConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	attention := *config.GetAttention()
+	attention.Events = append([]string(nil), attention.Events...)
+	edit(&attention)
+	if err := config.SetAttention(&attention); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalAttentionWithOptions removes the attention settings from global config
+func ClearGlobalAttentionWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.Attention = nil
+	return config.SaveWithOptions(opts)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -77,6 +78,8 @@ type Ball struct {
 	WorkingDir         string      `json:"-"` // Computed from file location, not stored
 	Context            string      `json:"context,omitempty"` // Detailed description/background for the ball
 	Title              string      `json:"title"`             // Short title (50 char soft limit)
+	NextAction         string      `json:"next_action,omitempty"` // The immediate next step, a one-liner
+	Checkpoint         string      `json:"checkpoint,omitempty"`  // How far in-progress work has got (e.g., "tests written"); cleared on completion
 	AcceptanceCriteria []string    `json:"acceptance_criteria,omitempty"`
 	Priority           Priority    `json:"priority"`
 	State              BallState   `json:"state"`
@@ -90,10 +93,23 @@ type Ball struct {
 	Tags               []string    `json:"tags,omitempty"`
 	CompletionNote     string      `json:"completion_note,omitempty"`
 	ModelSize          ModelSize   `json:"model_size,omitempty"`
-	AgentProvider      string      `json:"agent_provider,omitempty"`  // Override: which agent provider to use (e.g., "claude", "opencode")
+	Estimate           *AcceptedEstimate `json:"estimate,omitempty"` // Complexity and iterations of the last accepted estimate (see `juggle estimate`)
+	AgentProvider      string      `json:"agent_provider,omitempty"`  // Override: which agent provider to use (e.g., "claude", "ollama")
 	ModelOverride      string      `json:"model_override,omitempty"` // Override: specific model to use (e.g., "opus", "sonnet", "haiku")
 	StartingRevision   string      `json:"starting_revision,omitempty"` // VCS revision/change ID when ball was started
 	RevisionID         string      `json:"revision_id,omitempty"`       // VCS revision/change ID when ball was blocked or completed
+	TestsState         *TestsState `json:"tests_state,omitempty"`       // Last recorded test run (see `juggle tests record`)
+	WatchGlobs         []string    `json:"watch_globs,omitempty"`       // Project-relative globs for files this ball covers (e.g., "src/auth/**")
+	Transcripts        []Transcript `json:"transcripts,omitempty"`      // Attached agent/chat transcripts (see `juggle attach-transcript`)
+	Notes              []BallNote   `json:"notes,omitempty"`            // Append-only work log (see `juggle note`)
+	Escalation         *Escalation  `json:"escalation,omitempty"`       // Set while a blocked ball waits on a human owner (see `juggle escalate`)
+	Recurrence         *Recurrence  `json:"recurrence,omitempty"`       // Schedule for a recurring ball (see `juggle recur`)
+	Verification       *Verification `json:"verification,omitempty"`    // Last check of the ACs against the ball's changes (see `juggle verify`)
+	Git                *GitLink      `json:"git,omitempty"`             // Linked branch and commits referencing the ball (see `juggle link`)
+	Milestone          string        `json:"milestone,omitempty"`       // Target release or milestone name (see `juggle milestone`)
+	Focused            bool          `json:"focused,omitempty"`         // Flagged by a human for the next agent run to work on first (TUI f)
+	CompletedBySession string        `json:"completed_by_session,omitempty"` // Session whose agent run completed the ball; only it counts the ball as progress
+	ReleasedSessions   []string      `json:"released_sessions,omitempty"`    // Session tags removed when another session's run completed the ball; reopening restores them
 }
 
 // NewBall creates a new ball with the given parameters in pending state
@@ -153,6 +169,19 @@ func (b *Ball) SetTitle(title string) {
 	b.UpdateActivity()
 }
 
+// SetNextAction sets the ball's next action. Use empty string to clear it.
+func (b *Ball) SetNextAction(action string) {
+	b.NextAction = strings.TrimSpace(action)
+	b.UpdateActivity()
+}
+
+// SetCheckpoint records how far the ball's work has got, so whoever picks
+// it up next resumes from there. Use empty string to clear it.
+func (b *Ball) SetCheckpoint(checkpoint string) {
+	b.Checkpoint = strings.TrimSpace(checkpoint)
+	b.UpdateActivity()
+}
+
 // IncrementUpdateCount increments the update counter
 func (b *Ball) IncrementUpdateCount() {
 	b.UpdateCount++
@@ -175,6 +204,13 @@ func (b *Ball) SetState(state BallState) error {
 	b.State = state
 	if state != StateBlocked {
 		b.BlockedReason = ""
+		b.Escalation = nil
+	}
+	if state == StateComplete || state == StateResearched {
+		b.Focused = false
+		b.Checkpoint = ""
+	} else {
+		b.CompletedBySession = ""
 	}
 	b.UpdateActivity()
 	return nil
@@ -187,6 +223,12 @@ func (b *Ball) ForceSetState(state BallState) {
 	b.State = state
 	if state != StateBlocked {
 		b.BlockedReason = ""
+		b.Escalation = nil
+	}
+	if state == StateComplete || state == StateResearched {
+		b.Focused = false
+	} else {
+		b.CompletedBySession = ""
 	}
 	b.UpdateActivity()
 }
@@ -207,6 +249,9 @@ func (b *Ball) SetBlocked(reason string) error {
 func (b *Ball) MarkComplete(note string) {
 	b.State = StateComplete
 	b.BlockedReason = ""
+	b.Escalation = nil
+	b.Focused = false
+	b.Checkpoint = ""
 	b.CompletionNote = note
 	now := time.Now()
 	b.CompletedAt = &now
@@ -217,6 +262,9 @@ func (b *Ball) MarkComplete(note string) {
 func (b *Ball) MarkResearched(output string) {
 	b.State = StateResearched
 	b.BlockedReason = ""
+	b.Escalation = nil
+	b.Focused = false
+	b.Checkpoint = ""
 	b.Output = output
 	now := time.Now()
 	b.CompletedAt = &now
@@ -288,6 +336,16 @@ func (b *Ball) RemoveTag(tag string) bool {
 	return false // Tag not found
 }
 
+// HasTag returns true if the ball has the tag
+func (b *Ball) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // IdleDuration returns how long since the last activity
 func (b *Ball) IdleDuration() time.Duration {
 	return time.Since(b.LastActivity)
@@ -299,7 +357,7 @@ func (b *Ball) IsInCurrentDir() bool {
 	if err != nil {
 		return false
 	}
-	return b.WorkingDir == cwd
+	return SamePath(b.WorkingDir, cwd)
 }
 
 // FolderName returns the base name of the working directory
@@ -530,10 +588,11 @@ func (b *Ball) SetModelSize(size ModelSize) {
 }
 
 // ValidateAgentProvider checks if an agent provider string is valid.
-// Valid providers are: "" (blank/unset), "claude", "opencode"
+// Valid providers are: "" (blank/unset), "claude", "opencode", "http",
+// "ollama", "shell"
 func ValidateAgentProvider(s string) bool {
 	switch s {
-	case "", "claude", "opencode":
+	case "", "claude", "opencode", "http", "ollama", "shell":
 		return true
 	default:
 		return false
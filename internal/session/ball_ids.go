@@ -0,0 +1,127 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// storeMetaFile holds project bookkeeping kept next to balls.jsonl
+const storeMetaFile = "meta.json"
+
+// maxBallIDAttempts bounds how many fresh IDs AppendBall tries before giving up
+const maxBallIDAttempts = 100
+
+// storeMeta is the project bookkeeping in .juggle/meta.json
+type storeMeta struct {
+	// RetiredBallIDs lists the IDs of deleted balls. Their progress notes and
+	// agent history outlive them, so the IDs are never issued again.
+	RetiredBallIDs []string `json:"retired_ball_ids,omitempty"`
+}
+
+// metaPath returns the path to the store metadata, next to balls.jsonl
+func (s *Store) metaPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), storeMetaFile)
+}
+
+// loadMeta reads the store metadata, which is empty until a ball is deleted
+func (s *Store) loadMeta() (*storeMeta, error) {
+	data, err := os.ReadFile(s.metaPath())
+	if os.IsNotExist(err) {
+		return &storeMeta{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store metadata: %w", err)
+	}
+	var meta storeMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse store metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+// saveMeta writes the store metadata atomically
+func (s *Store) saveMeta(meta *storeMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal store metadata: %w", err)
+	}
+	tmpPath := s.metaPath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write store metadata: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.metaPath()); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save store metadata: %w", err)
+	}
+	return nil
+}
+
+// retireBallID records that id belonged to a ball that no longer exists
+func (s *Store) retireBallID(id string) error {
+	_, unlock, err := acquireFileLock(s.metaPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	meta, err := s.loadMeta()
+	if err != nil {
+		return err
+	}
+	if containsString(meta.RetiredBallIDs, id) {
+		return nil
+	}
+	meta.RetiredBallIDs = append(meta.RetiredBallIDs, id)
+	return s.saveMeta(meta)
+}
+
+// usedBallIDs returns every ID the project has issued: active, archived,
+// and deleted balls
+func (s *Store) usedBallIDs() (map[string]bool, error) {
+	used := make(map[string]bool)
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+	for _, ball := range balls {
+		used[ball.ID] = true
+	}
+	err = s.EachArchivedBall(func(ball *Ball) bool {
+		used[ball.ID] = true
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	meta, err := s.loadMeta()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range meta.RetiredBallIDs {
+		used[id] = true
+	}
+	return used, nil
+}
+
+// ensureUnusedBallID gives ball a new ID if its ID was ever issued in this
+// project, so a reused ID can't make old progress notes and agent history
+// ambiguous
+func (s *Store) ensureUnusedBallID(ball *Ball) error {
+	used, err := s.usedBallIDs()
+	if err != nil {
+		return err
+	}
+	for attempt := 0; used[ball.ID]; attempt++ {
+		if attempt == maxBallIDAttempts {
+			return fmt.Errorf("failed to find an unused ID for ball %q", ball.Title)
+		}
+		id, err := generateID(s.projectDir)
+		if err != nil {
+			return err
+		}
+		ball.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"testing"
+)
+
+// appendWithID appends a new ball forced to reuse id, returning the ID it got
+func appendWithID(t *testing.T, store *Store, id string) string {
+	t.Helper()
+	ball, err := NewBall(store.ProjectDir(), "Reuses "+id, PriorityMedium)
+	if err != nil {
+		t.Fatalf("Failed to create ball: %v", err)
+	}
+	ball.ID = id
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+	return ball.ID
+}
+
+func TestStore_BallIDsNeverReused(t *testing.T) {
+	store := newUndoTestStore(t)
+	active := newUndoTestBall(t, store, "Active")
+	archived := newUndoTestBall(t, store, "Archived")
+	deleted := newUndoTestBall(t, store, "Deleted")
+
+	archived.State = StateComplete
+	if err := store.ArchiveBall(archived); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+	if err := store.DeleteBall(deleted.ID); err != nil {
+		t.Fatalf("Failed to delete ball: %v", err)
+	}
+
+	for _, id := range []string{active.ID, archived.ID, deleted.ID} {
+		if got := appendWithID(t, store, id); got == id {
+			t.Errorf("Expected a new ID instead of reusing %s", id)
+		}
+	}
+
+	meta, err := store.loadMeta()
+	if err != nil {
+		t.Fatalf("Failed to load store metadata: %v", err)
+	}
+	if len(meta.RetiredBallIDs) != 1 || meta.RetiredBallIDs[0] != deleted.ID {
+		t.Errorf("Expected %s retired, got %v", deleted.ID, meta.RetiredBallIDs)
+	}
+
+	// A fresh store on the same project still knows the deleted ID
+	reopened, err := NewStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	if got := appendWithID(t, reopened, deleted.ID); got == deleted.ID {
+		t.Errorf("Expected deleted ID %s not reused after reopening", deleted.ID)
+	}
+}
+
+func TestStore_BallIDKeptWhenUnused(t *testing.T) {
+	store := newUndoTestStore(t)
+	id := "project-0000beef"
+	if got := appendWithID(t, store, id); got != id {
+		t.Errorf("Expected unused ID %s kept, got %s", id, got)
+	}
+}
+
+func TestStore_UndoCreateRetiresBallID(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "Created by mistake")
+
+	if _, err := store.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got := appendWithID(t, store, ball.ID); got == ball.ID {
+		t.Errorf("Expected undone ball's ID %s not reused", ball.ID)
+	}
+}
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"strings"
+	"time"
+)
+
+// BallNote is an entry in a ball's append-only work log, kept apart from
+// session progress so discussion stays with the ball it belongs to
+type BallNote struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddNote appends a timestamped note to the ball's work log
+func (b *Ball) AddNote(text string) BallNote {
+	note := BallNote{Text: strings.TrimSpace(text), CreatedAt: time.Now()}
+	b.Notes = append(b.Notes, note)
+	b.UpdateActivity()
+	return note
+}
+
+// RecentNotes returns the ball's last n notes, oldest first
+func (b *Ball) RecentNotes(n int) []BallNote {
+	if n <= 0 || len(b.Notes) <= n {
+		return b.Notes
+	}
+	return b.Notes[len(b.Notes)-n:]
+}
@@ -1,6 +1,14 @@
 package session
 
-import "testing"
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestExtractTitleFirstSentence(t *testing.T) {
 	tests := []struct {
@@ -90,6 +98,27 @@ func TestSetTitle(t *testing.T) {
 	}
 }
 
+// TestSetCheckpoint tests that checkpoints are trimmed and dropped once the
+// ball is finished
+func TestSetCheckpoint(t *testing.T) {
+	ball := &Ball{State: StateInProgress}
+
+	ball.SetCheckpoint("  tests written  ")
+	if ball.Checkpoint != "tests written" {
+		t.Errorf("SetCheckpoint() should trim, got %q", ball.Checkpoint)
+	}
+
+	ball.SetState(StateBlocked)
+	if ball.Checkpoint != "tests written" {
+		t.Errorf("Blocking should keep the checkpoint, got %q", ball.Checkpoint)
+	}
+
+	ball.MarkComplete("")
+	if ball.Checkpoint != "" {
+		t.Errorf("Completing should clear the checkpoint, got %q", ball.Checkpoint)
+	}
+}
+
 func TestNewBallExtractsFirstSentence(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -102,3 +131,753 @@ func TestNewBallExtractsFirstSentence(t *testing.T) {
 		t.Errorf("NewBall() should extract first sentence, got %q", ball.Title)
 	}
 }
+
+// TestDependencyIndex_Resolve tests resolving dependencies against active and archived balls
+func TestDependencyIndex_Resolve(t *testing.T) {
+	active := &Ball{ID: "proj-1", State: StatePending}
+	done := &Ball{ID: "proj-2", State: StateComplete}
+	archived := &Ball{ID: "proj-3", State: StateComplete}
+	idx := NewDependencyIndex([]*Ball{active, done}, []*Ball{archived})
+
+	tests := []struct {
+		id        string
+		status    string
+		satisfied bool
+	}{
+		{"proj-1", "pending", false},
+		{"1", "pending", false},
+		{"proj-2", "complete", true},
+		{"proj-3", "complete (archived)", true},
+		{"3", "complete (archived)", true},
+		{"proj-99", "missing", true},
+	}
+	for _, tt := range tests {
+		dep := idx.Resolve(tt.id)
+		if dep.Status() != tt.status {
+			t.Errorf("Resolve(%q).Status() = %q, want %q", tt.id, dep.Status(), tt.status)
+		}
+		if dep.Satisfied() != tt.satisfied {
+			t.Errorf("Resolve(%q).Satisfied() = %v, want %v", tt.id, dep.Satisfied(), tt.satisfied)
+		}
+	}
+
+	ball := &Ball{ID: "proj-4", DependsOn: []string{"proj-2", "proj-3"}}
+	if !idx.DependenciesSatisfied(ball) {
+		t.Error("expected dependencies on complete and archived balls to be satisfied")
+	}
+	ball.DependsOn = append(ball.DependsOn, "proj-1")
+	if idx.DependenciesSatisfied(ball) {
+		t.Error("expected pending dependency to be unsatisfied")
+	}
+	if got, want := idx.FormatDependencies(ball), "proj-2 (complete), proj-3 (complete (archived)), proj-1 (pending)"; got != want {
+		t.Errorf("FormatDependencies() = %q, want %q", got, want)
+	}
+}
+
+func TestCheckTestsPolicy(t *testing.T) {
+	ball := &Ball{ID: "proj-1"}
+	if got := ball.TestsResult(); got != TestsUnknown {
+		t.Errorf("TestsResult() = %q, want %q", got, TestsUnknown)
+	}
+
+	ball.RecordTests(TestsFail, "go test ./...")
+	if !ball.TestsFailing() {
+		t.Fatal("expected ball to have failing tests")
+	}
+
+	if _, err := CheckTestsPolicy(TestsPolicyBlock, ball); err == nil {
+		t.Error("expected block policy to refuse completion")
+	}
+	warning, err := CheckTestsPolicy(TestsPolicyWarn, ball)
+	if err != nil || !strings.Contains(warning, "go test ./...") {
+		t.Errorf("expected warn policy to allow with warning, got %q, %v", warning, err)
+	}
+	if warning, err := CheckTestsPolicy(TestsPolicyOff, ball); err != nil || warning != "" {
+		t.Errorf("expected off policy to ignore results, got %q, %v", warning, err)
+	}
+
+	ball.RecordTests(TestsPass, "")
+	if _, err := CheckTestsPolicy(TestsPolicyBlock, ball); err != nil {
+		t.Errorf("expected passing tests to allow completion, got %v", err)
+	}
+}
+
+func TestCheckTestsBeforeComplete_ProjectPolicy(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ball := &Ball{ID: "proj-1", WorkingDir: dir}
+	ball.RecordTests(TestsFail, "")
+
+	if _, err := CheckTestsBeforeComplete(ball); err == nil {
+		t.Error("expected default policy to block completion")
+	}
+
+	if err := UpdateProjectTestsPolicy(dir, "warn"); err != nil {
+		t.Fatalf("UpdateProjectTestsPolicy: %v", err)
+	}
+	if warning, err := CheckTestsBeforeComplete(ball); err != nil || warning == "" {
+		t.Errorf("expected warn policy to allow with warning, got %q, %v", warning, err)
+	}
+
+	if err := UpdateProjectTestsPolicy(dir, "sometimes"); err == nil {
+		t.Error("expected invalid policy to be rejected")
+	}
+}
+
+func TestMatchWatchGlob(t *testing.T) {
+	tests := []struct {
+		glob    string
+		path    string
+		matches bool
+	}{
+		{"src/auth/**", "src/auth/login.go", true},
+		{"src/auth/**", "src/auth/oauth/google.go", true},
+		{"src/auth/**", "src/authz/policy.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/cli/root.go", true},
+		{"**/*.go", "README.md", false},
+		{"internal/*/root.go", "internal/cli/root.go", true},
+		{"internal/*/root.go", "internal/cli/sub/root.go", false},
+		{"go.mod", "go.mod", true},
+	}
+	for _, tt := range tests {
+		if got := MatchWatchGlob(tt.glob, tt.path); got != tt.matches {
+			t.Errorf("MatchWatchGlob(%q, %q) = %v, want %v", tt.glob, tt.path, got, tt.matches)
+		}
+	}
+
+	roots := map[string]string{
+		"src/auth/**":        "src/auth",
+		"**/*.go":            ".",
+		"internal/*/root.go": "internal",
+		"docs/README.md":     "docs",
+		"go.mod":             ".",
+	}
+	for glob, want := range roots {
+		if got := WatchGlobRoot(glob); got != want {
+			t.Errorf("WatchGlobRoot(%q) = %q, want %q", glob, got, want)
+		}
+	}
+}
+
+func TestBallWatchGlobs(t *testing.T) {
+	ball := &Ball{ID: "proj-1"}
+	if err := ball.AddWatchGlob("src/auth/**"); err != nil {
+		t.Fatalf("AddWatchGlob: %v", err)
+	}
+	if err := ball.AddWatchGlob("src/auth/**"); err != nil || len(ball.WatchGlobs) != 1 {
+		t.Errorf("expected duplicate glob to be ignored, got %v (%v)", ball.WatchGlobs, err)
+	}
+	for _, bad := range []string{"", "/etc/**", "../other/**", "src/[auth"} {
+		if err := ball.AddWatchGlob(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+
+	if glob, ok := ball.MatchesWatchPath("src/auth/login.go"); !ok || glob != "src/auth/**" {
+		t.Errorf("MatchesWatchPath() = %q, %v", glob, ok)
+	}
+	if !ball.RemoveWatchGlob("src/auth/**") || len(ball.WatchGlobs) != 0 {
+		t.Errorf("expected glob to be removed, got %v", ball.WatchGlobs)
+	}
+}
+
+func TestExtractCreatedBallIDs(t *testing.T) {
+	output := "Planning...\n✓ Planned ball added: proj-a1b2\nCreated ball: proj-c3d4.\n✓ Planned ball added: proj-a1b2\n"
+	got := ExtractCreatedBallIDs(output)
+	want := []string{"proj-a1b2", "proj-c3d4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ExtractCreatedBallIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	if got := TitleSimilarity("Add login page", "add Login page!"); got != 1 {
+		t.Errorf("expected identical words to score 1, got %v", got)
+	}
+	if got := TitleSimilarity("Add login page", "Fix billing export"); got != 0 {
+		t.Errorf("expected disjoint titles to score 0, got %v", got)
+	}
+	if got := TitleSimilarity("Add login page", "Add the login page"); got < DefaultDuplicateThreshold {
+		t.Errorf("expected near-duplicate to reach threshold, got %v", got)
+	}
+}
+
+func TestFindNearDuplicates(t *testing.T) {
+	now := time.Now()
+	existing := &Ball{ID: "proj-1", Title: "Add login page", WorkingDir: "/p", StartedAt: now.Add(-time.Hour)}
+	unrelated := &Ball{ID: "proj-2", Title: "Fix billing export", WorkingDir: "/p", StartedAt: now.Add(-time.Hour)}
+	dup := &Ball{ID: "proj-3", Title: "Add the login page", WorkingDir: "/p", StartedAt: now}
+	other := &Ball{ID: "proj-4", Title: "Add login page", WorkingDir: "/other", StartedAt: now}
+	newA := &Ball{ID: "proj-5", Title: "Write API docs", WorkingDir: "/p", StartedAt: now}
+	newB := &Ball{ID: "proj-6", Title: "Write the API docs", WorkingDir: "/p", StartedAt: now.Add(time.Minute)}
+	all := []*Ball{existing, unrelated, dup, other, newA, newB}
+
+	got := FindNearDuplicates([]*Ball{dup, other, newA, newB}, all, DefaultDuplicateThreshold)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 duplicates, got %d: %+v", len(got), got)
+	}
+	if got[0].Ball.ID != "proj-3" || got[0].Original.ID != "proj-1" {
+		t.Errorf("expected proj-3 to duplicate proj-1, got %s ≈ %s", got[0].Ball.ID, got[0].Original.ID)
+	}
+	// Between two new balls, the later one is the duplicate
+	if got[1].Ball.ID != "proj-6" || got[1].Original.ID != "proj-5" {
+		t.Errorf("expected proj-6 to duplicate proj-5, got %s ≈ %s", got[1].Ball.ID, got[1].Original.ID)
+	}
+}
+
+func TestMergeDuplicateBall(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	keep := &Ball{ID: "proj-1", Title: "Add login page", AcceptanceCriteria: []string{"Form renders"}, Tags: []string{"auth"}}
+	dup := &Ball{ID: "proj-2", Title: "Add the login page", AcceptanceCriteria: []string{"Form renders", "Errors shown"},
+		Tags: []string{"auth", "ui"}, DependsOn: []string{"proj-1", "proj-9"}}
+	dependent := &Ball{ID: "proj-3", Title: "Add logout", DependsOn: []string{"proj-2"}}
+	for _, b := range []*Ball{keep, dup, dependent} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("AppendBall: %v", err)
+		}
+	}
+
+	if err := store.MergeDuplicateBall(keep, dup); err != nil {
+		t.Fatalf("MergeDuplicateBall: %v", err)
+	}
+
+	if _, err := store.GetBallByID("proj-2"); err == nil {
+		t.Error("expected duplicate to be deleted")
+	}
+	merged, err := store.GetBallByID("proj-1")
+	if err != nil {
+		t.Fatalf("GetBallByID: %v", err)
+	}
+	if strings.Join(merged.AcceptanceCriteria, "|") != "Form renders|Errors shown" {
+		t.Errorf("unexpected criteria: %v", merged.AcceptanceCriteria)
+	}
+	if strings.Join(merged.Tags, ",") != "auth,ui" {
+		t.Errorf("unexpected tags: %v", merged.Tags)
+	}
+	if strings.Join(merged.DependsOn, ",") != "proj-9" {
+		t.Errorf("expected self-dependency to be dropped, got %v", merged.DependsOn)
+	}
+	updated, err := store.GetBallByID("proj-3")
+	if err != nil {
+		t.Fatalf("GetBallByID: %v", err)
+	}
+	if strings.Join(updated.DependsOn, ",") != "proj-1" {
+		t.Errorf("expected dependent to point at kept ball, got %v", updated.DependsOn)
+	}
+
+	if err := store.MergeDuplicateBall(merged, merged); err == nil {
+		t.Error("expected merging a ball into itself to fail")
+	}
+}
+
+func TestParseWeekID(t *testing.T) {
+	start, err := ParseWeekID("2026-w42")
+	if err != nil {
+		t.Fatalf("ParseWeekID: %v", err)
+	}
+	if start.Weekday() != time.Monday || start.Format("2006-01-02") != "2026-10-12" {
+		t.Errorf("expected Monday 2026-10-12, got %s (%s)", start.Format("2006-01-02"), start.Weekday())
+	}
+	if got := WeekID(start.AddDate(0, 0, 6)); got != "2026-W42" {
+		t.Errorf("WeekID(Sunday) = %q, want 2026-W42", got)
+	}
+	if got := WeekTag(WeekID(start)); got != "week-2026-w42" {
+		t.Errorf("WeekTag = %q", got)
+	}
+	for _, bad := range []string{"", "2026", "2026-W0", "2026-W54", "2025-W53"} {
+		if _, err := ParseWeekID(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestReviewWeek(t *testing.T) {
+	tag := WeekTag("2026-W42")
+	inWeek := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.Local)
+	lastWeek := inWeek.AddDate(0, 0, -7)
+
+	done := &Ball{ID: "p-1", State: StateComplete, Tags: []string{tag}, ModelSize: ModelSizeLarge, CompletedAt: &inWeek}
+	open := &Ball{ID: "p-2", State: StatePending, Tags: []string{tag}}
+	unplanned := &Ball{ID: "p-3", State: StateComplete, ModelSize: ModelSizeSmall, CompletedAt: &inWeek}
+	earlier := &Ball{ID: "p-4", State: StateComplete, CompletedAt: &lastWeek}
+
+	review, err := ReviewWeek([]*Ball{done, open, unplanned, earlier}, "2026-W42")
+	if err != nil {
+		t.Fatalf("ReviewWeek: %v", err)
+	}
+	if len(review.Planned) != 2 || review.PlannedPoints != 5 {
+		t.Errorf("expected 2 planned balls worth 5 points, got %d worth %d", len(review.Planned), review.PlannedPoints)
+	}
+	if len(review.Done) != 1 || review.DonePoints != 3 {
+		t.Errorf("expected 1 done ball worth 3 points, got %d worth %d", len(review.Done), review.DonePoints)
+	}
+	if len(review.NotDone) != 1 || review.NotDone[0].ID != "p-2" {
+		t.Errorf("expected p-2 not done, got %v", review.NotDone)
+	}
+	if len(review.Unplanned) != 1 || review.Unplanned[0].ID != "p-3" {
+		t.Errorf("expected p-3 as unplanned work, got %v", review.Unplanned)
+	}
+}
+
+func TestBrownoutState(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionStore: %v", err)
+	}
+	if b, err := store.LoadBrownout("s1"); err != nil || b != nil {
+		t.Fatalf("expected no brownout, got %+v (%v)", b, err)
+	}
+	if (*Brownout)(nil).Active(time.Now()) {
+		t.Error("nil brownout should not be active")
+	}
+
+	now := time.Now()
+	if err := store.RecordBrownout(&Brownout{SessionID: "s1", StartedAt: now, Until: now.Add(time.Minute), Errors: 3, LastError: "boom"}); err != nil {
+		t.Fatalf("RecordBrownout: %v", err)
+	}
+	b, err := store.LoadBrownout("s1")
+	if err != nil || b == nil {
+		t.Fatalf("LoadBrownout: %+v (%v)", b, err)
+	}
+	if !b.Active(now) || b.Active(now.Add(2*time.Minute)) {
+		t.Errorf("expected brownout active for one minute, got until %v", b.Until)
+	}
+	if b.Errors != 3 || b.LastError != "boom" {
+		t.Errorf("unexpected brownout %+v", b)
+	}
+
+	if err := store.ClearBrownout("s1"); err != nil {
+		t.Fatalf("ClearBrownout: %v", err)
+	}
+	if b, _ := store.LoadBrownout("s1"); b != nil {
+		t.Errorf("expected brownout cleared, got %+v", b)
+	}
+
+	config := DefaultConfig()
+	if config.GetBrownoutThreshold() != DefaultBrownoutThreshold || config.GetBrownoutCooldown() != DefaultBrownoutCooldownMinutes*time.Minute {
+		t.Error("expected brownout defaults when unset")
+	}
+}
+
+func TestMergeBallsJSONL(t *testing.T) {
+	line := func(b *Ball) string {
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return string(data) + "\n"
+	}
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ball := func(id, title string, tags ...string) *Ball {
+		return &Ball{ID: id, Title: title, Priority: PriorityMedium, State: StatePending, Tags: tags, StartedAt: t0, LastActivity: t0}
+	}
+
+	base := line(ball("p-1", "Shared", "a", "b")) + line(ball("p-2", "Deleted upstream")) + line(ball("p-3", "Both edit"))
+
+	ours1 := ball("p-1", "Shared", "a", "b", "ours")
+	ours3 := ball("p-3", "Ours title")
+	ours3.LastActivity = t0.Add(time.Hour)
+	ours := line(ours1) + line(ball("p-2", "Deleted upstream")) + line(ours3) + line(ball("p-4", "Ours new"))
+
+	theirs1 := ball("p-1", "Shared", "b", "theirs")
+	theirs1.Priority = PriorityUrgent
+	theirs3 := ball("p-3", "Theirs title")
+	theirs3.LastActivity = t0.Add(2 * time.Hour)
+	theirs := line(theirs1) + line(theirs3) + line(ball("p-5", "Theirs new"))
+
+	merged, conflicts, err := MergeBallsJSONL([]byte(base), []byte(ours), []byte(theirs))
+	if err != nil {
+		t.Fatalf("MergeBallsJSONL: %v", err)
+	}
+
+	balls := make(map[string]*Ball)
+	var order []string
+	for _, l := range strings.Split(strings.TrimSpace(string(merged)), "\n") {
+		var b Ball
+		if err := json.Unmarshal([]byte(l), &b); err != nil {
+			t.Fatalf("unmarshal %q: %v", l, err)
+		}
+		balls[b.ID] = &b
+		order = append(order, b.ID)
+	}
+
+	if got := strings.Join(order, ","); got != "p-1,p-3,p-4,p-5" {
+		t.Errorf("expected p-2 deleted and new balls kept in order, got %s", got)
+	}
+	if got := strings.Join(balls["p-1"].Tags, ","); got != "b,ours,theirs" {
+		t.Errorf("expected tag additions unioned and removals honoured, got %s", got)
+	}
+	if balls["p-1"].Priority != PriorityUrgent {
+		t.Errorf("expected one-sided priority change kept, got %s", balls["p-1"].Priority)
+	}
+	if balls["p-3"].Title != "Theirs title" || !balls["p-3"].LastActivity.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("expected latest activity to win the title conflict, got %+v", balls["p-3"])
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %+v", conflicts)
+	}
+	if c := conflicts[0]; c.ID != "p-3" || c.Field != "title" || c.Resolution != "theirs" {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+
+	// Deleting a ball the other side changed keeps the change and flags it
+	edited := ball("p-2", "Edited upstream")
+	_, conflicts, err = MergeBallsJSONL([]byte(base), []byte(line(ball("p-1", "Shared", "a", "b"))+line(ball("p-3", "Both edit"))), []byte(base+line(edited)))
+	if err != nil {
+		t.Fatalf("MergeBallsJSONL: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != "p-2" || conflicts[0].Field != "" {
+		t.Errorf("expected delete/modify conflict for p-2, got %+v", conflicts)
+	}
+}
+
+func TestParseBallQuery(t *testing.T) {
+	balls := []*Ball{
+		{ID: "proj-1", Title: "Fix login bug", State: StatePending, Priority: PriorityHigh, Tags: []string{"old-session"}},
+		{ID: "proj-2", Title: "Add logout", State: StateInProgress, Priority: PriorityLow, Tags: []string{"old-session", "wip"}},
+		{ID: "proj-3", Title: "Login docs", State: StateBlocked, Priority: PriorityMedium},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"state:pending tag:old-session", "proj-1"},
+		{"session:old-session", "proj-1,proj-2"},
+		{"state:pending,blocked", "proj-1,proj-3"},
+		{"tag:old-session -tag:wip", "proj-1"},
+		{"LOGIN", "proj-1,proj-3"},
+		{"priority:low", "proj-2"},
+		{"-state:pending login", "proj-3"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseBallQuery(tc.query)
+			if err != nil {
+				t.Fatalf("ParseBallQuery: %v", err)
+			}
+			var got []string
+			for _, ball := range balls {
+				if q.Matches(ball) {
+					got = append(got, ball.ID)
+				}
+			}
+			if strings.Join(got, ",") != tc.want {
+				t.Errorf("matched %v, want %s", got, tc.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "state:done", "priority:asap", "owner:me", "tag:"} {
+		if _, err := ParseBallQuery(invalid); err == nil {
+			t.Errorf("expected error for query %q", invalid)
+		}
+	}
+}
+
+func TestRetagBalls(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	for _, b := range []*Ball{
+		{ID: "proj-1", Title: "One", State: StatePending, Tags: []string{"old-session"}},
+		{ID: "proj-2", Title: "Two", State: StatePending, Tags: []string{"old-session", "new-session"}},
+		{ID: "proj-3", Title: "Three", State: StateComplete, Tags: []string{"old-session"}},
+	} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("AppendBall: %v", err)
+		}
+	}
+
+	q, err := ParseBallQuery("state:pending tag:old-session")
+	if err != nil {
+		t.Fatalf("ParseBallQuery: %v", err)
+	}
+
+	changes, err := store.RetagBalls(q, []string{"new-session"}, []string{"old-session"}, true)
+	if err != nil {
+		t.Fatalf("RetagBalls dry run: %v", err)
+	}
+	if len(changes) != 2 || strings.Join(changes[0].Added, ",") != "new-session" || len(changes[1].Added) != 0 {
+		t.Errorf("unexpected dry run changes: %+v", changes)
+	}
+	unchanged, _ := store.GetBallByID("proj-1")
+	if strings.Join(unchanged.Tags, ",") != "old-session" {
+		t.Errorf("expected dry run not to write, got tags %v", unchanged.Tags)
+	}
+
+	if _, err := store.RetagBalls(q, []string{"new-session"}, []string{"old-session"}, false); err != nil {
+		t.Fatalf("RetagBalls: %v", err)
+	}
+	for id, want := range map[string]string{"proj-1": "new-session", "proj-2": "new-session", "proj-3": "old-session"} {
+		ball, err := store.GetBallByID(id)
+		if err != nil {
+			t.Fatalf("GetBallByID: %v", err)
+		}
+		if strings.Join(ball.Tags, ",") != want {
+			t.Errorf("%s tags = %v, want %s", id, ball.Tags, want)
+		}
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	ball := &Ball{ID: "proj-a1b2c3d4", State: StatePending}
+	if err := ball.Escalate(Escalation{Owner: "alice"}); err == nil {
+		t.Error("expected escalating a pending ball to fail")
+	}
+
+	if err := ball.SetBlocked("needs credentials"); err != nil {
+		t.Fatalf("SetBlocked: %v", err)
+	}
+	if err := ball.Escalate(Escalation{Owner: "alice"}); err != nil {
+		t.Fatalf("Escalate: %v", err)
+	}
+	if !ball.IsEscalated() || ball.Escalation.EscalatedAt.IsZero() {
+		t.Fatalf("expected ball to be escalated with a timestamp, got %+v", ball.Escalation)
+	}
+
+	// Re-blocking with a new reason keeps the escalation
+	ball.SetBlocked("still needs credentials")
+	if !ball.IsEscalated() {
+		t.Error("expected escalation to survive a new blocked reason")
+	}
+
+	// Leaving the blocked state resolves it
+	ball.MarkComplete("")
+	if ball.IsEscalated() {
+		t.Error("expected completing the ball to clear its escalation")
+	}
+
+	ball.ForceSetState(StateBlocked)
+	ball.Escalate(Escalation{})
+	ball.Context = "Original context"
+	if err := ball.ResolveEscalation("use the vault key"); err != nil {
+		t.Fatalf("ResolveEscalation: %v", err)
+	}
+	if ball.IsEscalated() || ball.State != StatePending {
+		t.Errorf("expected resolved ball to be pending, got %s %+v", ball.State, ball.Escalation)
+	}
+	if ball.Context != "Original context\n\nEscalation resolved: use the vault key" {
+		t.Errorf("unexpected context: %q", ball.Context)
+	}
+	if err := ball.ResolveEscalation(""); err == nil {
+		t.Error("expected resolving an unescalated ball to fail")
+	}
+}
+
+func TestFocusClearsWhenDone(t *testing.T) {
+	ball := &Ball{ID: "proj-a1b2c3d4", State: StatePending, Focused: true}
+	if err := ball.SetBlocked("waiting on review"); err != nil {
+		t.Fatalf("SetBlocked: %v", err)
+	}
+	if !ball.Focused {
+		t.Error("expected focus to survive blocking")
+	}
+	ball.ForceSetState(StateResearched)
+	if ball.Focused {
+		t.Error("expected focus cleared when researched")
+	}
+
+	// However a ball gets done, saving it clears the flag
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	ball = &Ball{ID: "proj-b2c3d4e5", State: StateInProgress, Focused: true}
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall: %v", err)
+	}
+	ball.State = StateComplete
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("UpdateBall: %v", err)
+	}
+	if saved, _ := store.GetBallByID(ball.ID); saved == nil || saved.Focused {
+		t.Errorf("expected focus cleared on save, got %+v", saved)
+	}
+}
+
+func TestParkedReason(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	dep := &Ball{ID: "proj-00000001", State: StatePending}
+	escalated := &Ball{ID: "proj-00000002", State: StateBlocked, BlockedReason: "needs keys", Escalation: &Escalation{Owner: "ops"}}
+	tests := []struct {
+		name string
+		ball *Ball
+		want string
+	}{
+		{"actionable", &Ball{State: StatePending}, ""},
+		{"plain blocked", &Ball{State: StateBlocked, BlockedReason: "needs keys"}, ""},
+		{"escalated", escalated, ParkedEscalated},
+		{"blocked on dependency", &Ball{State: StateBlocked, BlockedReason: "Waiting for dependency"}, ParkedWaiting},
+		{"unmet dependency", &Ball{State: StatePending, DependsOn: []string{dep.ID}}, ParkedWaiting},
+		{"snoozed", &Ball{State: StatePending, Recurrence: &Recurrence{Every: "7d", DueAt: now.Add(time.Hour)}}, ParkedSnoozed},
+		{"due", &Ball{State: StatePending, Recurrence: &Recurrence{Every: "7d", DueAt: now.Add(-time.Hour)}}, ""},
+		{"in progress with unmet dependency", &Ball{State: StateInProgress, DependsOn: []string{dep.ID}}, ""},
+	}
+	deps := NewDependencyIndex([]*Ball{dep, escalated}, nil)
+	for _, tt := range tests {
+		if got := tt.ball.ParkedReason(now, deps); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	dep.State = StateComplete
+	if got := tests[4].ball.ParkedReason(now, NewDependencyIndex([]*Ball{dep}, nil)); got != "" {
+		t.Errorf("expected a ball with done dependencies to be actionable, got %q", got)
+	}
+}
+
+func TestClaimCompletion(t *testing.T) {
+	ball := &Ball{ID: "proj-00000001", State: StateComplete, Tags: []string{"backend", "auth", "frontend"}}
+	sessions := []string{"auth", "frontend", "billing"}
+
+	if removed := ball.ClaimCompletion("auth", sessions, false); removed != nil {
+		t.Errorf("expected keep to remove no tags, got %v", removed)
+	}
+	if ball.CompletedBySession != "auth" || len(ball.Tags) != 3 {
+		t.Errorf("expected completion recorded and tags kept, got %q %v", ball.CompletedBySession, ball.Tags)
+	}
+	if !ball.CountsTowardSession("auth") || ball.CountsTowardSession("frontend") || ball.CountsTowardSession("billing") {
+		t.Error("expected the ball to count only toward the completing session")
+	}
+
+	removed := ball.ClaimCompletion("auth", sessions, true)
+	if len(removed) != 1 || removed[0] != "frontend" {
+		t.Errorf("expected frontend tag removed, got %v", removed)
+	}
+	if len(ball.Tags) != 2 || ball.Tags[0] != "backend" || ball.Tags[1] != "auth" {
+		t.Errorf("expected non-session tags and the owner kept, got %v", ball.Tags)
+	}
+
+	// Reopening the ball forgets who completed it
+	ball.ForceSetState(StatePending)
+	if ball.CompletedBySession != "" {
+		t.Errorf("expected completion owner cleared on reopen, got %q", ball.CompletedBySession)
+	}
+}
+
+func TestListBallsIncludeArchived(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	for _, b := range []*Ball{
+		{ID: "proj-a1", Title: "Active", State: StatePending},
+		{ID: "proj-b2", Title: "Done", State: StateComplete},
+	} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("AppendBall: %v", err)
+		}
+	}
+	done, _ := store.GetBallByID("proj-b2")
+	if err := store.ArchiveBall(done); err != nil {
+		t.Fatalf("ArchiveBall: %v", err)
+	}
+
+	active, err := store.ListBalls(ListOptions{})
+	if err != nil || len(active) != 1 || active[0].ID != "proj-a1" {
+		t.Fatalf("Expected only the active ball, got %v (%v)", active, err)
+	}
+	all, err := store.ListBalls(ListOptions{IncludeArchived: true})
+	if err != nil || len(all) != 2 || all[0].ID != "proj-a1" || all[1].ID != "proj-b2" {
+		t.Fatalf("Expected active then archived balls, got %v (%v)", all, err)
+	}
+
+	if ball, archived, err := store.GetBallAnywhere("proj-b2"); err != nil || ball.Title != "Done" || !archived {
+		t.Errorf("Expected the archived ball, got %v archived=%v (%v)", ball, archived, err)
+	}
+	if ball, archived, err := store.GetBallAnywhere("a1"); err != nil || ball.ID != "proj-a1" || archived {
+		t.Errorf("Expected the active ball by short ID, got %v archived=%v (%v)", ball, archived, err)
+	}
+	if _, _, err := store.GetBallAnywhere("proj-zz"); !errors.Is(err, ErrBallNotFound) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestReopenBall(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "Login form")
+	ball.Context = "Email and password"
+	ball.Tags = []string{"auth", "frontend"}
+	ball.MarkComplete("shipped")
+	ball.ClaimCompletion("auth", []string{"auth", "frontend"}, true)
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if err := store.ArchiveBall(ball); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+
+	reopened, restored, archived, err := store.ReopenBall(ball.ShortID(), "Fails on Safari")
+	if err != nil {
+		t.Fatalf("ReopenBall failed: %v", err)
+	}
+	if !archived || len(restored) != 1 || restored[0] != "frontend" {
+		t.Errorf("Expected the frontend tag restored from the archive, got %v (archived %v)", restored, archived)
+	}
+	if reopened.State != StatePending || reopened.CompletedAt != nil || reopened.CompletedBySession != "" ||
+		reopened.CompletionNote != "" || len(reopened.ReleasedSessions) != 0 || strings.Join(reopened.Tags, ",") != "auth,frontend" {
+		t.Errorf("Unexpected reopened ball %+v", reopened)
+	}
+	if !strings.HasPrefix(reopened.Context, "Email and password\n\nReopened ") ||
+		!strings.Contains(reopened.Context, ": Fails on Safari\nCompletion note was: shipped") {
+		t.Errorf("Expected a reopen note in the context, got %q", reopened.Context)
+	}
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.State != StatePending {
+		t.Errorf("Expected the ball back in the active balls, got %+v", got)
+	}
+	if left, _ := store.LoadArchivedBalls(); len(left) != 0 {
+		t.Errorf("Expected the archive emptied, got %d balls", len(left))
+	}
+
+	if _, _, _, err := store.ReopenBall(ball.ID, ""); err == nil {
+		t.Error("Expected an error reopening a pending ball")
+	}
+
+	// An active complete ball is reopened in place
+	reopened.MarkComplete("")
+	if err := store.UpdateBall(reopened); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if _, _, archived, err := store.ReopenBall(ball.ID, ""); err != nil || archived {
+		t.Errorf("Expected an active ball reopened in place, got archived %v (%v)", archived, err)
+	}
+}
+
+func TestBallNotes(t *testing.T) {
+	ball := &Ball{ID: "test-1", Title: "Flaky retries"}
+	for _, text := range []string{"  note 1\n", "note 2", "note 3"} {
+		ball.AddNote(text)
+	}
+	if len(ball.Notes) != 3 || ball.Notes[0].Text != "note 1" || ball.Notes[0].CreatedAt.IsZero() {
+		t.Fatalf("Expected 3 trimmed, timestamped notes, got %+v", ball.Notes)
+	}
+	recent := ball.RecentNotes(2)
+	if len(recent) != 2 || recent[0].Text != "note 2" || recent[1].Text != "note 3" {
+		t.Errorf("Expected the latest 2 notes oldest first, got %+v", recent)
+	}
+	if len(ball.RecentNotes(0)) != 3 || len(ball.RecentNotes(5)) != 3 {
+		t.Error("Expected all notes when the limit is 0 or above the count")
+	}
+}
@@ -0,0 +1,511 @@
+package session
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreBackend is where a project keeps its active balls
+type StoreBackend string
+
+const (
+	// StoreBackendJSONL keeps active balls in .juggle/balls.jsonl, rewritten
+	// whole on every change. It's the default, and diffs and merges in git.
+	StoreBackendJSONL StoreBackend = "jsonl"
+
+	// StoreBackendBolt keeps active balls in a bbolt database at
+	// .juggle/balls.db, indexed by state, tag and last activity, so a change
+	// only writes the ball it touches
+	StoreBackendBolt StoreBackend = "bolt"
+)
+
+const ballsDBFile = "balls.db"
+
+// boltOpenTimeout bounds how long opening balls.db waits for another juggle
+// process to close it
+const boltOpenTimeout = 10 * time.Second
+
+var (
+	boltBallsBucket    = []byte("balls")       // Sequence number to ball JSON, in the order balls were added
+	boltIDsBucket      = []byte("ids")         // Ball ID to sequence number
+	boltStateBucket    = []byte("by_state")    // State, NUL, ball ID
+	boltTagBucket      = []byte("by_tag")      // Tag, NUL, ball ID
+	boltActivityBucket = []byte("by_activity") // Last activity, NUL, ball ID
+)
+
+// boltBuckets are all of balls.db's buckets
+var boltBuckets = [][]byte{boltBallsBucket, boltIDsBucket, boltStateBucket, boltTagBucket, boltActivityBucket}
+
+// ValidateStoreBackend returns an error unless backend is "", "jsonl" or "bolt"
+func ValidateStoreBackend(backend StoreBackend) error {
+	switch backend {
+	case "", StoreBackendJSONL, StoreBackendBolt:
+		return nil
+	}
+	return fmt.Errorf("invalid store backend %q (must be jsonl or bolt)", backend)
+}
+
+// GetStoreBackend returns the project's store backend
+func (c *ProjectConfig) GetStoreBackend() StoreBackend {
+	if c.StoreBackend == "" {
+		return StoreBackendJSONL
+	}
+	return c.StoreBackend
+}
+
+// readStoreBackend returns the backend selected in the project config of
+// storageDir. Like GetProjectCompactThreshold, it's read for every store, so
+// a missing config isn't created.
+func readStoreBackend(storageDir string) (StoreBackend, error) {
+	data, err := os.ReadFile(filepath.Join(storageDir, projectStorePath, "config.json"))
+	if os.IsNotExist(err) {
+		return StoreBackendJSONL, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read project config: %w", err)
+	}
+	var config ProjectConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", fmt.Errorf("failed to parse project config: %w", err)
+	}
+	if err := ValidateStoreBackend(config.StoreBackend); err != nil {
+		return "", err
+	}
+	return config.GetStoreBackend(), nil
+}
+
+// boltStore keeps a project's active balls in balls.db. The database is
+// opened for each operation rather than held open, since bbolt locks the
+// file and the TUI, agents and CLI share it.
+type boltStore struct {
+	path       string
+	projectDir string
+}
+
+// exists reports whether balls.db has been created
+func (b *boltStore) exists() bool {
+	_, err := os.Stat(b.path)
+	return err == nil
+}
+
+// view runs fn in a read-only transaction. Without a database there are no
+// balls, so fn isn't called.
+func (b *boltStore) view(fn func(tx *bolt.Tx) error) error {
+	if !b.exists() {
+		return nil
+	}
+	db, err := bolt.Open(b.path, 0644, &bolt.Options{Timeout: boltOpenTimeout, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to open balls database: %w", err)
+	}
+	defer db.Close()
+	return db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltBallsBucket) == nil {
+			return nil
+		}
+		return fn(tx)
+	})
+}
+
+// update runs fn in a read-write transaction, creating the database and its
+// buckets first if need be
+func (b *boltStore) update(fn func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(b.path, 0644, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open balls database: %w", err)
+	}
+	defer db.Close()
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// load returns every active ball, in the order they were added
+func (b *boltStore) load() ([]*Ball, error) {
+	balls := make([]*Ball, 0)
+	err := b.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBallsBucket).ForEach(func(k, v []byte) error {
+			ball, err := b.decode(v)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to parse ball %d: %v\n", binary.BigEndian.Uint64(k), err)
+				return nil
+			}
+			balls = append(balls, ball)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balls, nil
+}
+
+// get returns the ball with the given ID, or nil if there is none
+func (b *boltStore) get(id string) (*Ball, error) {
+	var ball *Ball
+	err := b.view(func(tx *bolt.Tx) error {
+		var err error
+		ball, err = b.getTx(tx, id)
+		return err
+	})
+	return ball, err
+}
+
+// getTx returns the ball with the given ID, or nil if there is none
+func (b *boltStore) getTx(tx *bolt.Tx, id string) (*Ball, error) {
+	key := tx.Bucket(boltIDsBucket).Get([]byte(id))
+	if key == nil {
+		return nil, nil
+	}
+	data := tx.Bucket(boltBallsBucket).Get(key)
+	if data == nil {
+		return nil, fmt.Errorf("balls database index refers to missing ball %s", id)
+	}
+	return b.decode(data)
+}
+
+// byIndex returns the balls an index lists under value, ordered by the
+// index: by ID for states and tags, oldest first for activity
+func (b *boltStore) byIndex(bucket []byte, value string) ([]*Ball, error) {
+	balls := make([]*Ball, 0)
+	err := b.view(func(tx *bolt.Tx) error {
+		prefix := indexKey(value, "")
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			ball, err := b.getTx(tx, string(k[len(prefix):]))
+			if err != nil {
+				return err
+			}
+			if ball != nil {
+				balls = append(balls, ball)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balls, nil
+}
+
+// put adds a ball, or replaces the one with its ID, and returns the ball it
+// replaced
+func (b *boltStore) put(tx *bolt.Tx, ball *Ball) (*Ball, error) {
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ball: %w", err)
+	}
+	ids := tx.Bucket(boltIDsBucket)
+	balls := tx.Bucket(boltBallsBucket)
+
+	var previous *Ball
+	key := ids.Get([]byte(ball.ID))
+	if key != nil {
+		key = append([]byte(nil), key...)
+		if previous, err = b.getTx(tx, ball.ID); err != nil {
+			return nil, err
+		}
+		if err := setIndexes(tx, previous, false); err != nil {
+			return nil, err
+		}
+	} else {
+		seq, err := balls.NextSequence()
+		if err != nil {
+			return nil, fmt.Errorf("failed to number ball: %w", err)
+		}
+		key = make([]byte, 8)
+		binary.BigEndian.PutUint64(key, seq)
+		if err := ids.Put([]byte(ball.ID), key); err != nil {
+			return nil, fmt.Errorf("failed to write ball ID: %w", err)
+		}
+	}
+
+	if err := balls.Put(key, data); err != nil {
+		return nil, fmt.Errorf("failed to write ball: %w", err)
+	}
+	if err := setIndexes(tx, ball, true); err != nil {
+		return nil, err
+	}
+	return previous, nil
+}
+
+// remove deletes the ball with the given ID and returns it, or nil if there
+// was none
+func (b *boltStore) remove(tx *bolt.Tx, id string) (*Ball, error) {
+	previous, err := b.getTx(tx, id)
+	if err != nil || previous == nil {
+		return nil, err
+	}
+	ids := tx.Bucket(boltIDsBucket)
+	if err := tx.Bucket(boltBallsBucket).Delete(ids.Get([]byte(id))); err != nil {
+		return nil, fmt.Errorf("failed to delete ball: %w", err)
+	}
+	if err := ids.Delete([]byte(id)); err != nil {
+		return nil, fmt.Errorf("failed to delete ball ID: %w", err)
+	}
+	return previous, setIndexes(tx, previous, false)
+}
+
+// replaceAll rewrites the database with exactly the given balls, in order
+func (b *boltStore) replaceAll(balls []*Ball) error {
+	return b.update(func(tx *bolt.Tx) error {
+		for _, name := range boltBuckets {
+			if err := tx.DeleteBucket(name); err != nil {
+				return fmt.Errorf("failed to clear bucket %s: %w", name, err)
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		for _, ball := range balls {
+			if _, err := b.put(tx, ball); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// decode parses a stored ball
+func (b *boltStore) decode(data []byte) (*Ball, error) {
+	ball, err := parseCompactLine(string(data))
+	if err != nil {
+		return nil, err
+	}
+	ball.WorkingDir = b.projectDir
+	return ball, nil
+}
+
+// setIndexes adds (or with add false, removes) a ball's index entries
+func setIndexes(tx *bolt.Tx, ball *Ball, add bool) error {
+	entries := map[string][][]byte{
+		string(boltStateBucket):    {indexKey(string(ball.State), ball.ID)},
+		string(boltActivityBucket): {indexKey(activityKey(ball.LastActivity), ball.ID)},
+	}
+	for _, tag := range ball.Tags {
+		entries[string(boltTagBucket)] = append(entries[string(boltTagBucket)], indexKey(tag, ball.ID))
+	}
+	for name, keys := range entries {
+		bucket := tx.Bucket([]byte(name))
+		for _, key := range keys {
+			var err error
+			if add {
+				err = bucket.Put(key, nil)
+			} else {
+				err = bucket.Delete(key)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to update %s index: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// indexKey returns the index key listing id under value
+func indexKey(value, id string) []byte {
+	return []byte(value + "\x00" + id)
+}
+
+// activityKey formats a last activity time so keys sort chronologically
+func activityKey(t time.Time) string {
+	return t.UTC().Format("20060102T150405.000000000")
+}
+
+// updateIndexedBall replaces a ball in balls.db and returns the ball it
+// replaced
+func (s *Store) updateIndexedBall(updated *Ball) (*Ball, error) {
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var previous *Ball
+	err = s.db.update(func(tx *bolt.Tx) error {
+		existing, err := s.db.getTx(tx, updated.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return NewBallNotFoundError(updated.ID)
+		}
+		previous, err = s.db.put(tx, updated)
+		return err
+	})
+	return previous, err
+}
+
+// deleteIndexedBall removes a ball from balls.db and returns it, or nil if
+// there was none
+func (s *Store) deleteIndexedBall(id string) (*Ball, error) {
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var deleted *Ball
+	err = s.db.update(func(tx *bolt.Tx) error {
+		deleted, err = s.db.remove(tx, id)
+		return err
+	})
+	return deleted, err
+}
+
+// GetBallsByTag returns the active balls with the given tag. With the bolt
+// backend this reads the tag index instead of every ball.
+func (s *Store) GetBallsByTag(tag string) ([]*Ball, error) {
+	if s.db != nil {
+		return s.db.byIndex(boltTagBucket, tag)
+	}
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+	tagged := make([]*Ball, 0)
+	for _, ball := range balls {
+		if ballHasTag(ball, tag) {
+			tagged = append(tagged, ball)
+		}
+	}
+	return tagged, nil
+}
+
+// GetBallsActiveSince returns the active balls with activity at or after
+// since, most recently active first. With the bolt backend this reads the
+// last activity index instead of every ball.
+func (s *Store) GetBallsActiveSince(since time.Time) ([]*Ball, error) {
+	var recent []*Ball
+	if s.db != nil {
+		recent = make([]*Ball, 0)
+		from := indexKey(activityKey(since), "")
+		err := s.db.view(func(tx *bolt.Tx) error {
+			c := tx.Bucket(boltActivityBucket).Cursor()
+			for k, _ := c.Seek(from); k != nil; k, _ = c.Next() {
+				id := k[bytes.IndexByte(k, 0)+1:]
+				ball, err := s.db.getTx(tx, string(id))
+				if err != nil {
+					return err
+				}
+				if ball != nil {
+					recent = append(recent, ball)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		balls, err := s.LoadBalls()
+		if err != nil {
+			return nil, err
+		}
+		recent = make([]*Ball, 0)
+		for _, ball := range balls {
+			if !ball.LastActivity.Before(since) {
+				recent = append(recent, ball)
+			}
+		}
+	}
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].LastActivity.After(recent[j].LastActivity)
+	})
+	return recent, nil
+}
+
+// StoreMigration describes a project's move to another store backend
+type StoreMigration struct {
+	From   StoreBackend `json:"from"`
+	To     StoreBackend `json:"to"`
+	Balls  int          `json:"balls"`            // Active balls converted
+	Backup string       `json:"backup,omitempty"` // Where the old file was moved, if there was one
+}
+
+// MigrateStore converts a project's active balls to the given backend and
+// selects it in the project config. The old file is moved aside with a .bak
+// suffix rather than deleted. The archive stays in JSONL either way.
+func MigrateStore(projectDir string, config StoreConfig, to StoreBackend) (*StoreMigration, error) {
+	if to == "" {
+		to = StoreBackendJSONL
+	}
+	if err := ValidateStoreBackend(to); err != nil {
+		return nil, err
+	}
+
+	config.Backend = to
+	target, err := NewStoreWithConfig(projectDir, config)
+	if err != nil {
+		return nil, err
+	}
+	config.Backend = StoreBackendJSONL
+	if to == StoreBackendJSONL {
+		config.Backend = StoreBackendBolt
+	}
+	source, err := NewStoreWithConfig(projectDir, config)
+	if err != nil {
+		return nil, err
+	}
+	storageDir, err := ResolveStorageDir(projectDir, config.JuggleDirName)
+	if err != nil {
+		storageDir = projectDir
+	}
+	from, err := readStoreBackend(storageDir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(target.ballsDataPath()); err == nil && from == to {
+		return nil, fmt.Errorf("project already uses the %s store backend", to)
+	}
+
+	// Both backends share the balls lock, so nothing changes mid-migration
+	_, unlock, err := acquireFileLock(target.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	migration := &StoreMigration{From: from, To: to}
+	sourcePath := source.ballsDataPath()
+	if _, err := os.Stat(sourcePath); err == nil {
+		balls, err := source.LoadBalls()
+		if err != nil {
+			return nil, err
+		}
+		if err := target.writeBallsUnlocked(balls); err != nil {
+			return nil, fmt.Errorf("failed to write balls: %w", err)
+		}
+		migration.Balls = len(balls)
+		migration.Backup = sourcePath + ".bak"
+		if err := os.Rename(sourcePath, migration.Backup); err != nil {
+			return nil, fmt.Errorf("failed to move %s aside: %w", filepath.Base(sourcePath), err)
+		}
+	}
+
+	projectConfig, err := LoadProjectConfig(storageDir)
+	if err != nil {
+		return nil, err
+	}
+	projectConfig.StoreBackend = to
+	if to == StoreBackendJSONL {
+		projectConfig.StoreBackend = ""
+	}
+	if err := SaveProjectConfig(storageDir, projectConfig); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ballIDs(balls []*Ball) []string {
+	ids := make([]string, len(balls))
+	for i, ball := range balls {
+		ids[i] = ball.ID
+	}
+	return ids
+}
+
+func TestMigrateStore(t *testing.T) {
+	store := newUndoTestStore(t)
+	first := newUndoTestBall(t, store, "First")
+	second := newUndoTestBall(t, store, "Second")
+	second.Tags = []string{"backend"}
+	second.State = StateInProgress
+	if err := store.UpdateBall(second); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	migration, err := MigrateStore(store.ProjectDir(), DefaultStoreConfig(), StoreBackendBolt)
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	if migration.From != StoreBackendJSONL || migration.To != StoreBackendBolt || migration.Balls != 2 {
+		t.Errorf("Unexpected migration: %+v", migration)
+	}
+	if _, err := os.Stat(migration.Backup); err != nil {
+		t.Errorf("Expected balls.jsonl kept at %s: %v", migration.Backup, err)
+	}
+	if _, err := MigrateStore(store.ProjectDir(), DefaultStoreConfig(), StoreBackendBolt); err == nil {
+		t.Error("Expected migrating to the current backend to fail")
+	}
+
+	// New stores pick the backend up from the project config
+	bolt, err := NewStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if bolt.db == nil {
+		t.Fatal("Expected the bolt backend selected")
+	}
+	balls, err := bolt.LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if ids := ballIDs(balls); len(ids) != 2 || ids[0] != first.ID || ids[1] != second.ID {
+		t.Errorf("Expected both balls in order, got %v", ids)
+	}
+	if balls[1].State != StateInProgress || balls[1].WorkingDir != store.ProjectDir() {
+		t.Errorf("Expected the ball intact, got %+v", balls[1])
+	}
+
+	migration, err = MigrateStore(store.ProjectDir(), DefaultStoreConfig(), StoreBackendJSONL)
+	if err != nil {
+		t.Fatalf("Migration back failed: %v", err)
+	}
+	if migration.Balls != 2 {
+		t.Errorf("Expected 2 balls migrated back, got %d", migration.Balls)
+	}
+	jsonl, err := NewStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if jsonl.db != nil {
+		t.Error("Expected the jsonl backend selected again")
+	}
+	if balls, err := jsonl.LoadBalls(); err != nil || len(balls) != 2 {
+		t.Errorf("Expected 2 balls back in balls.jsonl, got %d (%v)", len(balls), err)
+	}
+}
+
+func TestBoltStore_Operations(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStoreWithConfig(dir, StoreConfig{JuggleDirName: projectStorePath, Backend: StoreBackendBolt})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	if balls, err := store.LoadBalls(); err != nil || len(balls) != 0 {
+		t.Fatalf("Expected no balls before the database exists, got %d (%v)", len(balls), err)
+	}
+
+	now := time.Now()
+	old := newUndoTestBall(t, store, "Old")
+	old.LastActivity = now.Add(-48 * time.Hour)
+	old.Tags = []string{"docs"}
+	if err := store.UpdateBall(old); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	recent := newUndoTestBall(t, store, "Recent")
+	recent.LastActivity = now
+	recent.State = StateInProgress
+	recent.Tags = []string{"docs", "api"}
+	if err := store.UpdateBall(recent); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, projectStorePath, ballsFile)); !os.IsNotExist(err) {
+		t.Errorf("Expected no balls.jsonl with the bolt backend, got %v", err)
+	}
+
+	got, err := store.GetBallByID(recent.ID)
+	if err != nil || got.Title != "Recent" {
+		t.Errorf("Expected the ball by ID, got %+v (%v)", got, err)
+	}
+	if _, err := store.GetBallByID("missing"); err == nil {
+		t.Error("Expected a missing ball to be an error")
+	}
+	if err := store.UpdateBall(&Ball{ID: "missing", State: StatePending}); err == nil {
+		t.Error("Expected updating a missing ball to fail")
+	}
+
+	// The indexes follow updates
+	if balls, _ := store.GetBallsByState(StateInProgress); len(balls) != 1 || balls[0].ID != recent.ID {
+		t.Errorf("Expected the in progress ball, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.GetBallsByState(StatePending); len(balls) != 1 || balls[0].ID != old.ID {
+		t.Errorf("Expected the pending ball, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.GetBallsByTag("docs"); len(balls) != 2 {
+		t.Errorf("Expected both docs balls, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.GetBallsByTag("api"); len(balls) != 1 || balls[0].ID != recent.ID {
+		t.Errorf("Expected the api ball, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.GetBallsActiveSince(now.Add(-time.Hour)); len(balls) != 1 || balls[0].ID != recent.ID {
+		t.Errorf("Expected only the recent ball, got %v", ballIDs(balls))
+	}
+
+	recent.Tags = []string{"api"}
+	recent.State = StateBlocked
+	if err := store.UpdateBall(recent); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if balls, _ := store.GetBallsByTag("docs"); len(balls) != 1 || balls[0].ID != old.ID {
+		t.Errorf("Expected the removed tag unindexed, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.GetBallsByState(StateInProgress); len(balls) != 0 {
+		t.Errorf("Expected the old state unindexed, got %v", ballIDs(balls))
+	}
+
+	if err := store.DeleteBall(old.ID); err != nil {
+		t.Fatalf("Failed to delete ball: %v", err)
+	}
+	if balls, _ := store.GetBallsByTag("docs"); len(balls) != 0 {
+		t.Errorf("Expected the deleted ball unindexed, got %v", ballIDs(balls))
+	}
+	if balls, _ := store.LoadBalls(); len(balls) != 1 || balls[0].ID != recent.ID {
+		t.Errorf("Expected one ball left, got %v", ballIDs(balls))
+	}
+
+	if _, err := store.Compact(false); err == nil {
+		t.Error("Expected compaction refused with the bolt backend")
+	}
+}
+
+func TestNewStore_BoltBackendNeedsMigration(t *testing.T) {
+	store := newUndoTestStore(t)
+	newUndoTestBall(t, store, "Kept in balls.jsonl")
+
+	config, err := LoadProjectConfig(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to load project config: %v", err)
+	}
+	config.StoreBackend = StoreBackendBolt
+	if err := SaveProjectConfig(store.ProjectDir(), config); err != nil {
+		t.Fatalf("Failed to save project config: %v", err)
+	}
+
+	if _, err := NewStore(store.ProjectDir()); err == nil || !strings.Contains(err.Error(), "juggle migrate") {
+		t.Errorf("Expected selecting bolt by hand to point at juggle migrate, got %v", err)
+	}
+}
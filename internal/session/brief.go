@@ -0,0 +1,111 @@
+package session
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// BriefingNextCount is how many balls a briefing suggests working on next
+const BriefingNextCount = 3
+
+// BriefingIdleAfter is how long a session goes untouched before the TUI
+// opens its briefing when the session is selected
+const BriefingIdleAfter = 3 * 24 * time.Hour
+
+// Briefing is a short, human-oriented summary of a session for picking it
+// back up: what it's for, what to do next, what's stuck, and how the last
+// agent run went
+type Briefing struct {
+	SessionID    string          `json:"session_id"`
+	Goal         string          `json:"goal"`               // Session goal, description, or the first line of its context
+	Next         []*Ball         `json:"next"`               // Up to BriefingNextCount balls ready to work on, in agent order
+	Blocked      []*Ball         `json:"blocked"`            // Blocked balls
+	Remaining    int             `json:"remaining"`          // Pending and in-progress balls, ready or not
+	LastRun      *AgentRunRecord `json:"last_run,omitempty"` // Most recent agent run on the session
+	LastActivity time.Time       `json:"last_activity"`      // Latest ball activity, agent run, or session edit
+}
+
+// BuildBriefing summarises sess from the project's active balls and its
+// agent history (newest first, as LoadHistoryBySession returns it)
+func BuildBriefing(sess *JuggleSession, balls []*Ball, history []*AgentRunRecord) *Briefing {
+	briefing := &Briefing{
+		SessionID:    sess.ID,
+		Goal:         sessionGoal(sess),
+		Next:         make([]*Ball, 0),
+		Blocked:      make([]*Ball, 0),
+		LastActivity: sess.UpdatedAt,
+	}
+
+	deps := NewDependencyIndex(balls, nil)
+	ready := make([]*Ball, 0)
+	for _, ball := range balls {
+		if !ballHasTag(ball, sess.ID) {
+			continue
+		}
+		if ball.LastActivity.After(briefing.LastActivity) {
+			briefing.LastActivity = ball.LastActivity
+		}
+		switch ball.State {
+		case StateBlocked:
+			briefing.Blocked = append(briefing.Blocked, ball)
+		case StatePending, StateInProgress:
+			briefing.Remaining++
+			if deps.DependenciesSatisfied(ball) {
+				ready = append(ready, ball)
+			}
+		}
+	}
+
+	// Focused balls first, then in-progress before pending, then priority:
+	// the order the agent prompt lists them in
+	SortBallsByPriority(ready)
+	sort.SliceStable(ready, func(i, j int) bool {
+		if ready[i].Focused != ready[j].Focused {
+			return ready[i].Focused
+		}
+		return ready[i].State == StateInProgress && ready[j].State != StateInProgress
+	})
+	if len(ready) > BriefingNextCount {
+		ready = ready[:BriefingNextCount]
+	}
+	briefing.Next = ready
+	SortBallsByPriority(briefing.Blocked)
+
+	for _, record := range history {
+		if record.SessionID == sess.ID {
+			briefing.LastRun = record
+			break
+		}
+	}
+	if briefing.LastRun != nil && briefing.LastRun.EndedAt.After(briefing.LastActivity) {
+		briefing.LastActivity = briefing.LastRun.EndedAt
+	}
+
+	return briefing
+}
+
+// IdleFor returns how long the session has gone untouched at now
+func (b *Briefing) IdleFor(now time.Time) time.Duration {
+	if b.LastActivity.IsZero() {
+		return 0
+	}
+	return now.Sub(b.LastActivity)
+}
+
+// sessionGoal returns the session's goal or description, falling back to
+// the first non-empty line of its context
+func sessionGoal(sess *JuggleSession) string {
+	if sess.Goal != "" {
+		return sess.Goal
+	}
+	if goal := strings.TrimSpace(sess.Description); goal != "" {
+		return goal
+	}
+	for _, line := range strings.Split(sess.Context, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "#")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
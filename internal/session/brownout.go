@@ -0,0 +1,117 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	brownoutFile = "brownout.json"
+
+	// DefaultBrownoutThreshold is how many consecutive agent errors (crashes,
+	// not rate limits) trip the breaker and pause the session.
+	DefaultBrownoutThreshold = 3
+	// DefaultBrownoutCooldownMinutes is how long a browned-out session is paused.
+	DefaultBrownoutCooldownMinutes = 30
+)
+
+// Brownout records a session paused after repeated agent errors. While
+// Until is in the future, agent runs for the session refuse to start.
+type Brownout struct {
+	SessionID string    `json:"session_id"`
+	StartedAt time.Time `json:"started_at"`
+	Until     time.Time `json:"until"`
+	Errors    int       `json:"errors"`               // Consecutive errors that tripped the breaker
+	LastError string    `json:"last_error,omitempty"` // Error from the last failed run
+}
+
+// Active returns true if the session is still cooling down at now
+func (b *Brownout) Active(now time.Time) bool {
+	return b != nil && now.Before(b.Until)
+}
+
+// brownoutFilePath returns the path to a session's brownout state
+func (s *SessionStore) brownoutFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), brownoutFile)
+}
+
+// RecordBrownout pauses a session until b.Until
+func (s *SessionStore) RecordBrownout(b *Brownout) error {
+	if err := os.MkdirAll(s.sessionPath(b.SessionID), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal brownout: %w", err)
+	}
+	if err := os.WriteFile(s.brownoutFilePath(b.SessionID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write brownout: %w", err)
+	}
+	return nil
+}
+
+// LoadBrownout returns a session's last brownout, or nil if it has none.
+// Expired brownouts are returned too; use Active to check the cool-down.
+func (s *SessionStore) LoadBrownout(id string) (*Brownout, error) {
+	data, err := os.ReadFile(s.brownoutFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read brownout: %w", err)
+	}
+	var b Brownout
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse brownout: %w", err)
+	}
+	return &b, nil
+}
+
+// ClearBrownout ends a session's cool-down early
+func (s *SessionStore) ClearBrownout(id string) error {
+	if err := os.Remove(s.brownoutFilePath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear brownout: %w", err)
+	}
+	return nil
+}
+
+// GetBrownoutThreshold returns the consecutive error count that trips the breaker
+func (c *Config) GetBrownoutThreshold() int {
+	if c.BrownoutThreshold <= 0 {
+		return DefaultBrownoutThreshold
+	}
+	return c.BrownoutThreshold
+}
+
+// GetBrownoutCooldown returns how long a browned-out session is paused
+func (c *Config) GetBrownoutCooldown() time.Duration {
+	minutes := c.BrownoutCooldownMinutes
+	if minutes <= 0 {
+		minutes = DefaultBrownoutCooldownMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// BrownoutSettings holds the circuit breaker settings from global config
+type BrownoutSettings struct {
+	Threshold     int
+	Cooldown      time.Duration
+	NotifyCommand string // Shell command run when a session browns out (empty = none)
+}
+
+// GetGlobalBrownoutSettingsWithOptions returns the circuit breaker settings
+// from global config, falling back to defaults if the config can't be loaded
+func GetGlobalBrownoutSettingsWithOptions(opts ConfigOptions) (BrownoutSettings, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		config = DefaultConfig()
+	}
+	return BrownoutSettings{
+		Threshold:     config.GetBrownoutThreshold(),
+		Cooldown:      config.GetBrownoutCooldown(),
+		NotifyCommand: config.BrownoutNotifyCommand,
+	}, err
+}
@@ -0,0 +1,331 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const compactionFile = "compaction.json"
+
+// DefaultCompactThreshold is the balls.jsonl size past which the store is
+// compacted automatically, unless the project config sets another
+const DefaultCompactThreshold int64 = 1 << 20
+
+// compactRegrowth is how much balls.jsonl must grow past its size after the
+// last compaction before it's compacted automatically again, so a store that
+// is simply large isn't rewritten on every command
+const compactRegrowth = 1.25
+
+// CompactStats describes a compaction of balls.jsonl
+type CompactStats struct {
+	BytesBefore int64     `json:"bytes_before"`
+	BytesAfter  int64     `json:"bytes_after"`
+	LinesBefore int       `json:"lines_before"` // Non-blank lines
+	LinesAfter  int       `json:"lines_after"`
+	Duplicates  int       `json:"duplicates"`  // Superseded lines for a ball that appears again later
+	BlankLines  int       `json:"blank_lines"` // Blank lines dropped
+	Rewritten   bool      `json:"rewritten"`   // False for a dry run, or when the file was already compact
+	Automatic   bool      `json:"automatic,omitempty"`
+	CompactedAt time.Time `json:"compacted_at"`
+}
+
+// Saved returns how many bytes compaction removed
+func (c *CompactStats) Saved() int64 {
+	return c.BytesBefore - c.BytesAfter
+}
+
+// Compact rewrites balls.jsonl with one line per ball, sorted by ID. When a
+// ball appears on more than one line, the last line wins. Every line is
+// validated first, and nothing is written if any is invalid. The new file
+// is synced to disk before it replaces the old one. With dryRun, the stats
+// are computed but nothing is written or recorded.
+func (s *Store) Compact(dryRun bool) (*CompactStats, error) {
+	if s.db != nil {
+		return nil, fmt.Errorf("compaction only applies to the jsonl store backend; balls.db never accumulates stale lines")
+	}
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stats, err := s.compactUnlocked(dryRun)
+	if err != nil || dryRun {
+		return stats, err
+	}
+	return stats, s.saveCompaction(stats)
+}
+
+// CompactIfNeeded compacts balls.jsonl if it's past threshold and has grown
+// since it was last compacted. A threshold of 0 or less disables it, as does
+// the bolt backend. It returns nil stats when nothing was done.
+func (s *Store) CompactIfNeeded(threshold int64) (*CompactStats, error) {
+	if threshold <= 0 || s.db != nil {
+		return nil, nil
+	}
+	info, err := os.Stat(s.ballsPath)
+	if err != nil || info.Size() <= threshold {
+		return nil, nil
+	}
+	if last, err := s.LastCompaction(); err == nil && last != nil &&
+		float64(info.Size()) < float64(last.BytesAfter)*compactRegrowth {
+		return nil, nil
+	}
+
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	stats, err := s.compactUnlocked(false)
+	if err != nil {
+		return nil, err
+	}
+	stats.Automatic = true
+	return stats, s.saveCompaction(stats)
+}
+
+// compactUnlocked compacts balls.jsonl. Caller must hold the lock.
+func (s *Store) compactUnlocked(dryRun bool) (*CompactStats, error) {
+	stats := &CompactStats{CompactedAt: time.Now()}
+
+	data, err := os.ReadFile(s.ballsPath)
+	if os.IsNotExist(err) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balls file: %w", err)
+	}
+	stats.BytesBefore = int64(len(data))
+
+	byID := make(map[string]*Ball)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			stats.BlankLines++
+			continue
+		}
+		stats.LinesBefore++
+
+		ball, err := parseCompactLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if _, ok := byID[ball.ID]; ok {
+			stats.Duplicates++
+		}
+		byID[ball.ID] = ball
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading balls file: %w", err)
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		line, err := json.Marshal(byID[id])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal ball %s: %w", id, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	stats.BytesAfter = int64(buf.Len())
+	stats.LinesAfter = len(ids)
+
+	if dryRun || bytes.Equal(buf.Bytes(), data) {
+		return stats, nil
+	}
+	if err := writeFileSynced(s.ballsPath, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	stats.Rewritten = true
+	return stats, nil
+}
+
+// parseCompactLine parses and validates one line of balls.jsonl
+func parseCompactLine(line string) (*Ball, error) {
+	var ballData ballJSON
+	if err := json.Unmarshal([]byte(line), &ballData); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	ball := ballData.Ball
+	if ball.Title == "" && ballData.Intent != "" {
+		ball.Title = ballData.Intent
+	}
+
+	if ball.ID == "" {
+		return nil, fmt.Errorf("ball has no id")
+	}
+	if !ValidateBallState(string(ball.State)) {
+		return nil, fmt.Errorf("ball %s has invalid state %q", ball.ID, ball.State)
+	}
+	if ball.Priority != "" && !ValidatePriority(string(ball.Priority)) {
+		return nil, fmt.Errorf("ball %s has invalid priority %q", ball.ID, ball.Priority)
+	}
+	return &ball, nil
+}
+
+// writeFileSynced writes data to a temp file, syncs it, and renames it over
+// path, then syncs the directory so the rename survives a crash
+func writeFileSynced(path string, data []byte) error {
+	tempPath := path + ".tmp"
+	f, err := os.Create(tempPath)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// compactionPath returns the path of the last compaction's stats
+func (s *Store) compactionPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), compactionFile)
+}
+
+// LastCompaction returns the stats of the last compaction, or nil if the
+// store has never been compacted
+func (s *Store) LastCompaction() (*CompactStats, error) {
+	data, err := os.ReadFile(s.compactionPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compaction stats: %w", err)
+	}
+	var stats CompactStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse compaction stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// saveCompaction records stats as the last compaction
+func (s *Store) saveCompaction(stats *CompactStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal compaction stats: %w", err)
+	}
+	if err := os.WriteFile(s.compactionPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write compaction stats: %w", err)
+	}
+	return nil
+}
+
+// ParseByteSize parses a size like "1048576", "512K", "512KB" or "2M"
+func ParseByteSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	str = strings.TrimSuffix(str, "B")
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(str, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(str, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(str, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		str = str[:len(str)-1]
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (use bytes, or a number with K, M or G)", s)
+	}
+	return n * multiplier, nil
+}
+
+// FormatByteSize formats a size for display, e.g. "512 B", "12.3 KB", "2.0 MB"
+func FormatByteSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// GetCompactThreshold returns the balls.jsonl size past which the store is
+// compacted automatically, or 0 if automatic compaction is off
+func (c *ProjectConfig) GetCompactThreshold() int64 {
+	switch {
+	case c.CompactThreshold < 0:
+		return 0
+	case c.CompactThreshold == 0:
+		return DefaultCompactThreshold
+	default:
+		return c.CompactThreshold
+	}
+}
+
+// GetProjectCompactThreshold returns the project's automatic compaction
+// threshold. It's checked on every command, so unlike LoadProjectConfig it
+// doesn't create a missing config.
+func GetProjectCompactThreshold(projectDir string) (int64, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, projectStorePath, "config.json")); os.IsNotExist(err) {
+		return DefaultCompactThreshold, nil
+	}
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return 0, err
+	}
+	return config.GetCompactThreshold(), nil
+}
+
+// UpdateProjectCompactThreshold sets the project's automatic compaction
+// threshold in bytes; 0 turns automatic compaction off
+func UpdateProjectCompactThreshold(projectDir string, threshold int64) error {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if threshold == 0 {
+		config.CompactThreshold = -1
+	} else {
+		config.CompactThreshold = threshold
+	}
+	return SaveProjectConfig(projectDir, config)
+}
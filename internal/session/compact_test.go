@@ -0,0 +1,139 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCompactTestBalls(t *testing.T, store *Store, lines ...string) {
+	t.Helper()
+	path := filepath.Join(store.ProjectDir(), projectStorePath, ballsFile)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write balls: %v", err)
+	}
+}
+
+func readCompactTestBalls(t *testing.T, store *Store) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(store.ProjectDir(), projectStorePath, ballsFile))
+	if err != nil {
+		t.Fatalf("Failed to read balls: %v", err)
+	}
+	return string(data)
+}
+
+func TestStore_Compact(t *testing.T) {
+	store := newUndoTestStore(t)
+	writeCompactTestBalls(t, store,
+		`{"id":"p-2","title":"Second","state":"pending","priority":"low"}`,
+		``,
+		`{"id":"p-1","intent":"Legacy","state":"pending","priority":"medium"}`,
+		`{"id":"p-2","title":"Second, updated","state":"in_progress","priority":"low"}`,
+	)
+
+	stats, err := store.Compact(true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if stats.Rewritten || stats.Duplicates != 1 || stats.BlankLines != 1 || stats.LinesBefore != 3 || stats.LinesAfter != 2 {
+		t.Errorf("Unexpected dry run stats: %+v", stats)
+	}
+	if last, _ := store.LastCompaction(); last != nil {
+		t.Error("Expected a dry run not to be recorded")
+	}
+
+	stats, err = store.Compact(false)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if !stats.Rewritten || stats.BytesAfter == 0 {
+		t.Errorf("Expected the file rewritten, got %+v", stats)
+	}
+
+	balls, err := store.LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 2 || balls[0].ID != "p-1" || balls[0].Title != "Legacy" || balls[1].Title != "Second, updated" {
+		t.Errorf("Expected one line per ball, sorted, last line winning, got %+v", balls)
+	}
+	if last, err := store.LastCompaction(); err != nil || last == nil || last.BytesAfter != stats.BytesAfter {
+		t.Errorf("Expected the compaction recorded, got %+v (%v)", last, err)
+	}
+
+	stats, err = store.Compact(false)
+	if err != nil {
+		t.Fatalf("Second compact failed: %v", err)
+	}
+	if stats.Rewritten {
+		t.Error("Expected an already compact file to be left alone")
+	}
+}
+
+func TestStore_CompactRejectsInvalidLines(t *testing.T) {
+	store := newUndoTestStore(t)
+	lines := []string{
+		`{"id":"p-1","title":"Fine","state":"pending","priority":"low"}`,
+		`{"id":"p-2","title":"Odd","state":"sideways","priority":"low"}`,
+	}
+	writeCompactTestBalls(t, store, lines...)
+	before := readCompactTestBalls(t, store)
+
+	_, err := store.Compact(false)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("Expected the invalid line reported, got %v", err)
+	}
+	if readCompactTestBalls(t, store) != before {
+		t.Error("Expected the file untouched")
+	}
+
+	writeCompactTestBalls(t, store, lines[0], `{"id":"p-3",`)
+	if _, err := store.Compact(false); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("Expected malformed JSON rejected, got %v", err)
+	}
+}
+
+func TestStore_CompactIfNeeded(t *testing.T) {
+	store := newUndoTestStore(t)
+	line := `{"id":"p-1","title":"Same ball","state":"pending","priority":"low"}`
+	writeCompactTestBalls(t, store, line, line, line, line)
+	size := int64(len(readCompactTestBalls(t, store)))
+
+	if stats, err := store.CompactIfNeeded(0); stats != nil || err != nil {
+		t.Errorf("Expected a zero threshold to disable compaction, got %+v, %v", stats, err)
+	}
+	if stats, err := store.CompactIfNeeded(size); stats != nil || err != nil {
+		t.Errorf("Expected no compaction at the threshold, got %+v, %v", stats, err)
+	}
+
+	stats, err := store.CompactIfNeeded(size / 2)
+	if err != nil || stats == nil || !stats.Rewritten || !stats.Automatic || stats.Duplicates != 3 {
+		t.Fatalf("Expected an automatic compaction, got %+v, %v", stats, err)
+	}
+
+	// Still past the threshold, but it hasn't grown since
+	if stats, err := store.CompactIfNeeded(1); stats != nil || err != nil {
+		t.Errorf("Expected no compaction until the file grows, got %+v, %v", stats, err)
+	}
+	writeCompactTestBalls(t, store, line, line, line, line, line, line)
+	if stats, err := store.CompactIfNeeded(1); err != nil || stats == nil || !stats.Rewritten {
+		t.Errorf("Expected compaction once the file regrew, got %+v, %v", stats, err)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{"2048": 2048, "512K": 512 << 10, "512kb": 512 << 10, "2M": 2 << 20, "1G": 1 << 30}
+	for input, want := range tests {
+		got, err := ParseByteSize(input)
+		if err != nil || got != want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "big", "-1", "12X"} {
+		if _, err := ParseByteSize(input); err == nil {
+			t.Errorf("ParseByteSize(%q): expected an error", input)
+		}
+	}
+}
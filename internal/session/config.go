@@ -44,28 +44,76 @@ func DefaultConfigOptions() ConfigOptions {
 // Config holds global juggle configuration stored at ~/.juggle/config.json.
 //
 // Global configuration includes:
-//   - SearchPaths: directories to scan for juggle projects
+//   - Projects: the project registry cross-project views load (SearchPaths mirrors its paths)
 //   - IterationDelayMinutes/IterationDelayFuzz: pacing between agent runs
 //   - OverloadRetryMinutes: wait time after rate limit exhaustion
+//   - QuotaResetTimes/QuietHours: when usage quotas reset and when agents must not run
+//   - Brownout*: when repeated agent errors pause a session, and for how long
+//   - SafeModeRevert: whether safe-mode agent runs revert failures without asking
+//   - CompletionOwnership: whether a ball completed by one session's run leaves its other sessions
+//   - TargetNotifyCommand: shell command run when a session falls behind its throughput target
+//   - EscalationNotifyCommand: shell command run when a blocked ball is escalated to a human
+//   - IconSet/IconOverrides: glyphs used for states, priorities, and agent status
+//   - Attention: bell, tmux, and title signals when the TUI needs you
+//   - KeySequences: how long TUI two-key sequences wait, and the which-key popup
+//   - DefaultView: layout the TUI starts in (split/list/board)
+//   - SortOrders: custom ball sort orders for the TUI sort cycle and board --sort
 //   - VCS: preferred version control system (git/jj)
+//   - AgentProvider/HTTPAgent: which agent runs, and the API used by the http provider
+//   - ModelProviders: agent provider used for each model size, overriding AgentProvider
+//   - ShellAgent/OllamaAgent: settings for the shell and ollama providers
+//   - AgentSignals: how COMPLETE/CONTINUE/BLOCKED signals are detected in agent output
+//   - Scrub: secrets redacted from agent prompts and saved agent output
+//   - AgentLogs: rotation and retention of the agent output logs
 //
 // Unknown fields in the config file are preserved to prevent data loss
 // when older juggle versions read configs written by newer versions.
 //
 // Use LoadConfig() to read the config, and config.Save() to write changes.
 type Config struct {
-	SearchPaths []string `json:"search_paths"`
+	SearchPaths []string            `json:"search_paths"`       // Registered project paths, kept for older juggle versions
+	Projects    []RegisteredProject `json:"projects,omitempty"` // Project registry, with display names and enabled state
 	// Agent iteration delay settings
 	IterationDelayMinutes int `json:"iteration_delay_minutes,omitempty"` // Base delay between iterations in minutes
 	IterationDelayFuzz    int `json:"iteration_delay_fuzz,omitempty"`    // Random +/- variance in minutes
 	// Overload retry settings (for 529 errors after Claude's built-in retries exhaust)
 	OverloadRetryMinutes int `json:"overload_retry_minutes,omitempty"` // Minutes to wait before retrying after 529 overload exhaustion
+	// Agent schedule settings
+	QuotaResetTimes []string `json:"quota_reset_times,omitempty"` // Times of day the usage quota resets (e.g., "00:00 UTC")
+	QuietHours      []string `json:"quiet_hours,omitempty"`       // Daily windows when agents must not run (e.g., "09:00-17:00")
+	// Brownout (circuit breaker) settings for repeated agent errors
+	BrownoutThreshold       int    `json:"brownout_threshold,omitempty"`        // Consecutive errors before pausing a session
+	BrownoutCooldownMinutes int    `json:"brownout_cooldown_minutes,omitempty"` // Minutes a browned-out session stays paused
+	BrownoutNotifyCommand   string `json:"brownout_notify_command,omitempty"`   // Shell command run when a session browns out
+	// Safe mode (agent run --safe) settings
+	SafeModeRevert string `json:"safe_mode_revert,omitempty"` // Failed safe-mode runs: "ask" (default) or "auto" revert
+	// Balls completed by an agent run while tagged in several sessions
+	CompletionOwnership string `json:"completion_ownership,omitempty"` // "keep" (default) or "transfer": remove other sessions' tags
+	// Session throughput targets
+	TargetNotifyCommand string `json:"target_notify_command,omitempty"` // Shell command run when a session falls behind its target
+	// Blocked ball escalation
+	EscalationNotifyCommand string `json:"escalation_notify_command,omitempty"` // Shell command run by juggle escalate --notify
 	// VCS settings
 	VCS string `json:"vcs,omitempty"` // Version control system: "git" or "jj"
 
 	// Agent provider settings
-	AgentProvider  string            `json:"agent_provider,omitempty"`  // Agent CLI: "claude" or "opencode"
-	ModelOverrides map[string]string `json:"model_overrides,omitempty"` // Custom model mappings (e.g., "opus": "anthropic/claude-opus-5")
+	AgentProvider  string              `json:"agent_provider,omitempty"`  // Agent CLI: "claude" or "opencode", "http" for a direct API, "ollama", or "shell"
+	ModelOverrides map[string]string   `json:"model_overrides,omitempty"` // Custom model mappings (e.g., "opus": "anthropic/claude-opus-5")
+	ModelProviders map[string]string   `json:"model_providers,omitempty"` // Provider per model size (e.g., "small": "ollama")
+	HTTPAgent      *HTTPAgentConfig    `json:"http_agent,omitempty"`      // API settings for the "http" provider
+	ShellAgent     *ShellAgentConfig   `json:"shell_agent,omitempty"`     // Command run by the "shell" provider
+	OllamaAgent    *OllamaAgentConfig  `json:"ollama_agent,omitempty"`    // Server and models for the "ollama" provider
+	AgentSignals   *AgentSignalsConfig `json:"agent_signals,omitempty"`   // Promise signal detection in agent output
+	Scrub          *ScrubConfig        `json:"scrub,omitempty"`           // Secret redaction in prompts and saved output (off when nil)
+	AgentLogs      *AgentLogsConfig    `json:"agent_logs,omitempty"`      // Rotation and retention of .juggle/agent-logs
+
+	// Display settings
+	IconSet       string             `json:"icon_set,omitempty"`       // Icon set: "unicode" (default) or "ascii"
+	IconOverrides map[string]string  `json:"icon_overrides,omitempty"` // Per-icon glyph overrides (e.g., "state.pending": "-")
+	DefaultView   string             `json:"default_view,omitempty"`   // TUI startup layout: "split" (default), "list", or "board"
+	SortOrders    []NamedSortOrder   `json:"sort_orders,omitempty"`    // Custom sort orders, appended to the built-in sort cycle
+	Attention     *AttentionConfig   `json:"attention,omitempty"`      // Signals when an agent finishes or blocks while the TUI is unfocused
+	KeySequences  *KeySequenceConfig `json:"key_sequences,omitempty"`  // Two-key sequence timeout and which-key popup
 
 	// UnknownFields stores any fields from the config file that aren't recognized.
 	// These are preserved when saving to avoid data loss.
@@ -74,13 +122,36 @@ type Config struct {
 
 // knownConfigFields lists the field names we recognize in config JSON
 var knownConfigFields = map[string]bool{
-	"search_paths":            true,
-	"iteration_delay_minutes": true,
-	"iteration_delay_fuzz":    true,
-	"overload_retry_minutes":  true,
-	"vcs":                     true,
-	"agent_provider":          true,
-	"model_overrides":         true,
+	"search_paths":              true,
+	"projects":                  true,
+	"iteration_delay_minutes":   true,
+	"iteration_delay_fuzz":      true,
+	"overload_retry_minutes":    true,
+	"quota_reset_times":         true,
+	"quiet_hours":               true,
+	"brownout_threshold":        true,
+	"brownout_cooldown_minutes": true,
+	"brownout_notify_command":   true,
+	"safe_mode_revert":          true,
+	"completion_ownership":      true,
+	"target_notify_command":     true,
+	"escalation_notify_command": true,
+	"vcs":                       true,
+	"agent_provider":            true,
+	"model_overrides":           true,
+	"model_providers":           true,
+	"http_agent":                true,
+	"shell_agent":               true,
+	"ollama_agent":              true,
+	"agent_signals":             true,
+	"scrub":                     true,
+	"agent_logs":                true,
+	"icon_set":                  true,
+	"icon_overrides":            true,
+	"default_view":              true,
+	"attention":                 true,
+	"key_sequences":             true,
+	"sort_orders":               true,
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling to capture unknown fields
@@ -100,12 +171,36 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 
 	// Copy known fields
 	c.SearchPaths = alias.SearchPaths
+	c.Projects = alias.Projects
+	c.syncProjects()
 	c.IterationDelayMinutes = alias.IterationDelayMinutes
 	c.IterationDelayFuzz = alias.IterationDelayFuzz
 	c.OverloadRetryMinutes = alias.OverloadRetryMinutes
+	c.QuotaResetTimes = alias.QuotaResetTimes
+	c.QuietHours = alias.QuietHours
+	c.BrownoutThreshold = alias.BrownoutThreshold
+	c.BrownoutCooldownMinutes = alias.BrownoutCooldownMinutes
+	c.BrownoutNotifyCommand = alias.BrownoutNotifyCommand
+	c.SafeModeRevert = alias.SafeModeRevert
+	c.CompletionOwnership = alias.CompletionOwnership
+	c.TargetNotifyCommand = alias.TargetNotifyCommand
+	c.EscalationNotifyCommand = alias.EscalationNotifyCommand
 	c.VCS = alias.VCS
 	c.AgentProvider = alias.AgentProvider
 	c.ModelOverrides = alias.ModelOverrides
+	c.ModelProviders = alias.ModelProviders
+	c.HTTPAgent = alias.HTTPAgent
+	c.ShellAgent = alias.ShellAgent
+	c.OllamaAgent = alias.OllamaAgent
+	c.AgentSignals = alias.AgentSignals
+	c.Scrub = alias.Scrub
+	c.AgentLogs = alias.AgentLogs
+	c.IconSet = alias.IconSet
+	c.IconOverrides = alias.IconOverrides
+	c.DefaultView = alias.DefaultView
+	c.SortOrders = alias.SortOrders
+	c.Attention = alias.Attention
+	c.KeySequences = alias.KeySequences
 
 	// Extract unknown fields
 	c.UnknownFields = make(map[string]interface{})
@@ -127,7 +222,15 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 	}
 
 	// Add known fields (they take precedence over unknown fields with same name)
-	result["search_paths"] = c.SearchPaths
+	projects := c.RegisteredProjects()
+	searchPaths := make([]string, len(projects))
+	for i, project := range projects {
+		searchPaths[i] = project.Path
+	}
+	result["search_paths"] = searchPaths
+	if len(projects) > 0 {
+		result["projects"] = projects
+	}
 	if c.IterationDelayMinutes != 0 {
 		result["iteration_delay_minutes"] = c.IterationDelayMinutes
 	}
@@ -137,6 +240,33 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 	if c.OverloadRetryMinutes != 0 {
 		result["overload_retry_minutes"] = c.OverloadRetryMinutes
 	}
+	if len(c.QuotaResetTimes) > 0 {
+		result["quota_reset_times"] = c.QuotaResetTimes
+	}
+	if len(c.QuietHours) > 0 {
+		result["quiet_hours"] = c.QuietHours
+	}
+	if c.BrownoutThreshold != 0 {
+		result["brownout_threshold"] = c.BrownoutThreshold
+	}
+	if c.BrownoutCooldownMinutes != 0 {
+		result["brownout_cooldown_minutes"] = c.BrownoutCooldownMinutes
+	}
+	if c.BrownoutNotifyCommand != "" {
+		result["brownout_notify_command"] = c.BrownoutNotifyCommand
+	}
+	if c.SafeModeRevert != "" {
+		result["safe_mode_revert"] = c.SafeModeRevert
+	}
+	if c.CompletionOwnership != "" {
+		result["completion_ownership"] = c.CompletionOwnership
+	}
+	if c.TargetNotifyCommand != "" {
+		result["target_notify_command"] = c.TargetNotifyCommand
+	}
+	if c.EscalationNotifyCommand != "" {
+		result["escalation_notify_command"] = c.EscalationNotifyCommand
+	}
 	if c.VCS != "" {
 		result["vcs"] = c.VCS
 	}
@@ -146,6 +276,45 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 	if len(c.ModelOverrides) > 0 {
 		result["model_overrides"] = c.ModelOverrides
 	}
+	if len(c.ModelProviders) > 0 {
+		result["model_providers"] = c.ModelProviders
+	}
+	if c.HTTPAgent != nil {
+		result["http_agent"] = c.HTTPAgent
+	}
+	if c.ShellAgent != nil {
+		result["shell_agent"] = c.ShellAgent
+	}
+	if c.OllamaAgent != nil {
+		result["ollama_agent"] = c.OllamaAgent
+	}
+	if c.AgentSignals != nil {
+		result["agent_signals"] = c.AgentSignals
+	}
+	if c.Scrub != nil {
+		result["scrub"] = c.Scrub
+	}
+	if c.AgentLogs != nil {
+		result["agent_logs"] = c.AgentLogs
+	}
+	if c.IconSet != "" {
+		result["icon_set"] = c.IconSet
+	}
+	if len(c.IconOverrides) > 0 {
+		result["icon_overrides"] = c.IconOverrides
+	}
+	if c.DefaultView != "" {
+		result["default_view"] = c.DefaultView
+	}
+	if len(c.SortOrders) > 0 {
+		result["sort_orders"] = c.SortOrders
+	}
+	if c.Attention != nil {
+		result["attention"] = c.Attention
+	}
+	if c.KeySequences != nil {
+		result["key_sequences"] = c.KeySequences
+	}
 
 	return json.Marshal(result)
 }
@@ -253,28 +422,6 @@ func (c *Config) SaveWithOptions(opts ConfigOptions) error {
 	return nil
 }
 
-// AddSearchPath adds a new search path if it doesn't already exist
-func (c *Config) AddSearchPath(path string) bool {
-	for _, existing := range c.SearchPaths {
-		if existing == path {
-			return false // Already exists
-		}
-	}
-	c.SearchPaths = append(c.SearchPaths, path)
-	return true
-}
-
-// RemoveSearchPath removes a search path
-func (c *Config) RemoveSearchPath(path string) bool {
-	for i, existing := range c.SearchPaths {
-		if existing == path {
-			c.SearchPaths = append(c.SearchPaths[:i], c.SearchPaths[i+1:]...)
-			return true
-		}
-	}
-	return false
-}
-
 // SetIterationDelay sets the delay between agent iterations.
 // delayMinutes is the base delay in minutes, fuzz is the +/- variance in minutes.
 func (c *Config) SetIterationDelay(delayMinutes, fuzz int) {
@@ -302,8 +449,8 @@ func (c *Config) ClearIterationDelay() {
 // SetVCS sets the global VCS preference.
 // Valid values are "git", "jj", or "" (empty for auto-detect).
 func (c *Config) SetVCS(vcs string) error {
-	if vcs != "" && vcs != "git" && vcs != "jj" {
-		return fmt.Errorf("invalid VCS type: %s (must be 'git' or 'jj')", vcs)
+	if vcs != "" && vcs != "git" && vcs != "jj" && vcs != "none" {
+		return fmt.Errorf("invalid VCS type: %s (must be 'git', 'jj' or 'none')", vcs)
 	}
 	c.VCS = vcs
 	return nil
@@ -319,7 +466,7 @@ func (c *Config) ClearVCS() {
 	c.VCS = ""
 }
 
-// EnsureProjectInSearchPaths ensures a project directory is in the search paths
+// EnsureProjectInSearchPaths ensures a project directory is in the project registry
 // This is called when creating balls to automatically track the project
 func EnsureProjectInSearchPaths(projectDir string) error {
 	config, err := LoadConfig()
@@ -327,8 +474,8 @@ func EnsureProjectInSearchPaths(projectDir string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Add the path if not already present
-	if config.AddSearchPath(projectDir) {
+	// Register the project if it isn't already, leaving a disabled one disabled
+	if config.RegisterProject(projectDir, "") {
 		if err := config.Save(); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
@@ -345,16 +492,28 @@ func EnsureProjectInSearchPaths(projectDir string) error {
 //   - VCS: project-specific VCS preference (overrides global)
 //   - AgentProvider: project-specific agent CLI (overrides global)
 //   - ModelOverrides: project-specific model mappings (merged with global)
+//   - ModelProviders: project-specific provider per model size (merged with global)
 //   - RunAliases: named command aliases for `juggle worktree run`
+//   - TitleRules: conventions ball titles are checked against
+//   - CompactThreshold: balls.jsonl size that triggers automatic compaction
+//   - Hooks: commands and URLs run on ball and agent events
 //
 // These settings apply to all balls and sessions within the project.
 type ProjectConfig struct {
-	DefaultAcceptanceCriteria []string          `json:"default_acceptance_criteria,omitempty"` // Repo-level ACs applied to all sessions
-	ACTemplates               []string          `json:"ac_templates,omitempty"`                // Optional AC templates shown during ball creation
-	VCS                       string            `json:"vcs,omitempty"`                         // Version control system: "git" or "jj"
-	AgentProvider             string            `json:"agent_provider,omitempty"`              // Agent CLI: "claude" or "opencode"
-	ModelOverrides            map[string]string `json:"model_overrides,omitempty"`             // Custom model mappings
-	RunAliases                map[string]string `json:"run_aliases,omitempty"`                 // Named command aliases for worktree run
+	DefaultAcceptanceCriteria []string             `json:"default_acceptance_criteria,omitempty"` // Repo-level ACs applied to all sessions
+	ACTemplates               []string             `json:"ac_templates,omitempty"`                // Optional AC templates shown during ball creation
+	VCS                       string               `json:"vcs,omitempty"`                         // Version control system: "git" or "jj"
+	AgentProvider             string               `json:"agent_provider,omitempty"`              // Agent CLI: "claude" or "opencode"
+	AgentWorktree             bool                 `json:"agent_worktree,omitempty"`              // Run agents in a git worktree per session (see `juggle agent worktree`)
+	ModelOverrides            map[string]string    `json:"model_overrides,omitempty"`             // Custom model mappings
+	ModelProviders            map[string]string    `json:"model_providers,omitempty"`             // Provider per model size
+	RunAliases                map[string]string    `json:"run_aliases,omitempty"`                 // Named command aliases for worktree run
+	TestsPolicy               string               `json:"tests_policy,omitempty"`                // What to do when completing a ball with failing tests: block, warn, off
+	WeekCapacity              int                  `json:"week_capacity,omitempty"`               // Points of work to plan per week (see `juggle week`)
+	TitleRules                *TitleRules          `json:"title_rules,omitempty"`                 // Conventions ball titles are warned about (see `juggle lint titles`)
+	CompactThreshold          int64                `json:"compact_threshold,omitempty"`           // balls.jsonl size in bytes past which it's compacted automatically; 0 is the default, negative is off
+	StoreBackend              StoreBackend         `json:"store_backend,omitempty"`               // Where active balls are kept: "jsonl" (default) or "bolt" (see `juggle migrate`)
+	Hooks                     map[HookEvent][]Hook `json:"hooks,omitempty"`                       // Commands and URLs run on ball and agent events (see `juggle config hooks`)
 }
 
 // DefaultProjectConfig returns a new project config with initial values
@@ -438,8 +597,8 @@ func (c *ProjectConfig) GetACTemplates() []string {
 // SetVCS sets the project VCS preference.
 // Valid values are "git", "jj", or "" (empty for inherit from global/auto-detect).
 func (c *ProjectConfig) SetVCS(vcs string) error {
-	if vcs != "" && vcs != "git" && vcs != "jj" {
-		return fmt.Errorf("invalid VCS type: %s (must be 'git' or 'jj')", vcs)
+	if vcs != "" && vcs != "git" && vcs != "jj" && vcs != "none" {
+		return fmt.Errorf("invalid VCS type: %s (must be 'git', 'jj' or 'none')", vcs)
 	}
 	c.VCS = vcs
 	return nil
@@ -709,10 +868,10 @@ func ClearProjectVCS(projectDir string) error {
 }
 
 // SetAgentProvider sets the global agent provider preference.
-// Valid values are "claude", "opencode", or "" (empty for default).
+// Valid values are "claude", "opencode", "http", "ollama", "shell", or "" (empty for default).
 func (c *Config) SetAgentProvider(provider string) error {
-	if provider != "" && provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid agent provider: %s (must be 'claude' or 'opencode')", provider)
+	if !ValidateAgentProvider(provider) {
+		return fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'http', 'ollama', or 'shell')", provider)
 	}
 	c.AgentProvider = provider
 	return nil
@@ -818,8 +977,8 @@ func GetGlobalModelOverridesWithOptions(opts ConfigOptions) (map[string]string,
 
 // SetAgentProvider for ProjectConfig sets the project agent provider preference.
 func (c *ProjectConfig) SetAgentProvider(provider string) error {
-	if provider != "" && provider != "claude" && provider != "opencode" {
-		return fmt.Errorf("invalid agent provider: %s (must be 'claude' or 'opencode')", provider)
+	if !ValidateAgentProvider(provider) {
+		return fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'http', 'ollama', or 'shell')", provider)
 	}
 	c.AgentProvider = provider
 	return nil
@@ -910,3 +1069,100 @@ func MergeModelOverrides(global, project map[string]string) map[string]string {
 
 	return result
 }
+
+// ModelProviderSize returns the model size a size or canonical model name
+// (haiku, sonnet, opus) routes by, or "" if it isn't one
+func ModelProviderSize(model string) string {
+	switch model {
+	case "small", "haiku":
+		return string(ModelSizeSmall)
+	case "medium", "sonnet":
+		return string(ModelSizeMedium)
+	case "large", "opus":
+		return string(ModelSizeLarge)
+	default:
+		return ""
+	}
+}
+
+// SetModelProvider routes a model size to a provider ("" removes the route)
+func (c *Config) SetModelProvider(size, provider string) error {
+	routes, err := setModelProvider(c.ModelProviders, size, provider)
+	if err != nil {
+		return err
+	}
+	c.ModelProviders = routes
+	return nil
+}
+
+// SetModelProvider routes a model size to a provider in the project ("" removes the route)
+func (c *ProjectConfig) SetModelProvider(size, provider string) error {
+	routes, err := setModelProvider(c.ModelProviders, size, provider)
+	if err != nil {
+		return err
+	}
+	c.ModelProviders = routes
+	return nil
+}
+
+// setModelProvider validates and applies a route, returning the new map
+func setModelProvider(routes map[string]string, size, provider string) (map[string]string, error) {
+	key := ModelProviderSize(size)
+	if key == "" {
+		return nil, fmt.Errorf("invalid model size: %s (must be small, medium, or large)", size)
+	}
+	if !ValidateAgentProvider(provider) {
+		return nil, fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'http', 'ollama', or 'shell')", provider)
+	}
+	if provider == "" {
+		delete(routes, key)
+		if len(routes) == 0 {
+			return nil, nil
+		}
+		return routes, nil
+	}
+	if routes == nil {
+		routes = make(map[string]string)
+	}
+	routes[key] = provider
+	return routes, nil
+}
+
+// UpdateGlobalModelProviderWithOptions routes a model size to a provider in
+// global config ("" removes the route)
+func UpdateGlobalModelProviderWithOptions(opts ConfigOptions, size, provider string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetModelProvider(size, provider); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// UpdateProjectModelProvider routes a model size to a provider in project
+// config ("" removes the route)
+func UpdateProjectModelProvider(projectDir, size, provider string) error {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := config.SetModelProvider(size, provider); err != nil {
+		return err
+	}
+	return SaveProjectConfig(projectDir, config)
+}
+
+// ResolveModelProvider returns the provider routed for a model (a size or
+// canonical name): the project's route, else the global one, else ""
+func ResolveModelProvider(model string, global, project map[string]string) string {
+	size := ModelProviderSize(model)
+	if size == "" {
+		return ""
+	}
+	if provider := project[size]; provider != "" {
+		return provider
+	}
+	return global[size]
+}
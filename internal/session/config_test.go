@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestProjectConfig_SetDefaultAcceptanceCriteria tests setting repo-level ACs
@@ -272,3 +273,559 @@ func TestProjectConfig_RunAliases_Persistence(t *testing.T) {
 		t.Errorf("expected 'go test -v ./...', got %q", alias)
 	}
 }
+
+// TestNextQuotaReset tests finding the next configured quota reset
+func TestNextQuotaReset(t *testing.T) {
+	now := time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	next, ok := NextQuotaReset(now, []string{"00:00 UTC", "16:00 UTC"})
+	if !ok {
+		t.Fatal("expected a reset time")
+	}
+	if want := time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected %v, got %v", want, next)
+	}
+
+	// Reset already passed today rolls over to tomorrow
+	next, _ = NextQuotaReset(now, []string{"09:00 UTC"})
+	if want := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Errorf("expected %v, got %v", want, next)
+	}
+
+	if _, ok := NextQuotaReset(now, nil); ok {
+		t.Error("expected no reset when none configured")
+	}
+}
+
+// TestQuietHoursEnd tests detecting quiet windows, including ones that wrap midnight
+func TestQuietHoursEnd(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		windows []string
+		quiet   bool
+		end     time.Time
+	}{
+		{"inside same-day window", day(10, 0), []string{"09:00-17:00 UTC"}, true, day(17, 0)},
+		{"at window end", day(17, 0), []string{"09:00-17:00 UTC"}, false, time.Time{}},
+		{"before window", day(8, 59), []string{"09:00-17:00 UTC"}, false, time.Time{}},
+		{"wrapping window late", day(23, 0), []string{"22:00-06:00 UTC"}, true, day(30, 0)},
+		{"wrapping window early", day(5, 0), []string{"22:00-06:00 UTC"}, true, day(6, 0)},
+		{"chained windows", day(10, 0), []string{"09:00-12:00 UTC", "12:00-13:30 UTC"}, true, day(13, 30)},
+		{"no windows", day(10, 0), nil, false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := QuietHoursEnd(tt.now, tt.windows)
+			if quiet != tt.quiet {
+				t.Fatalf("expected quiet=%v, got %v", tt.quiet, quiet)
+			}
+			if quiet && !end.Equal(tt.end) {
+				t.Errorf("expected end %v, got %v", tt.end, end)
+			}
+		})
+	}
+}
+
+// TestConfig_ScheduleValidation tests that invalid schedule values are rejected
+func TestConfig_ScheduleValidation(t *testing.T) {
+	config := DefaultConfig()
+
+	if err := config.SetQuotaResetTimes([]string{"25:00"}); err == nil {
+		t.Error("expected error for invalid hour")
+	}
+	if err := config.SetQuotaResetTimes([]string{"00:00 Not/AZone"}); err == nil {
+		t.Error("expected error for invalid zone")
+	}
+	if err := config.SetQuotaResetTimes([]string{"9:30pm"}); err == nil {
+		t.Error("expected error for trailing am/pm")
+	}
+	if err := config.SetQuotaResetTimes([]string{"10:00abc"}); err == nil {
+		t.Error("expected error for trailing text")
+	}
+	if err := config.SetQuotaResetTimes([]string{"9:30"}); err != nil {
+		t.Errorf("expected single-digit hour to be valid, got: %v", err)
+	}
+	if err := config.SetQuietHours([]string{"09:00-17:00x"}); err == nil {
+		t.Error("expected error for trailing text in a window")
+	}
+	if err := config.SetQuietHours([]string{"09:00"}); err == nil {
+		t.Error("expected error for window without end")
+	}
+	if err := config.SetQuietHours([]string{"09:00-09:00"}); err == nil {
+		t.Error("expected error for empty window")
+	}
+	if err := config.SetQuietHours([]string{"22:00-06:00 Europe/Oslo"}); err != nil {
+		t.Errorf("expected valid window, got: %v", err)
+	}
+}
+
+// TestConfig_SchedulePersistence tests that schedule settings round-trip through the config file
+func TestConfig_SchedulePersistence(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	if err := UpdateGlobalQuotaResetTimesWithOptions(opts, []string{"00:00 UTC"}); err != nil {
+		t.Fatalf("failed to set quota resets: %v", err)
+	}
+	if err := UpdateGlobalQuietHoursWithOptions(opts, []string{"09:00-17:00"}); err != nil {
+		t.Fatalf("failed to set quiet hours: %v", err)
+	}
+
+	resets, quiet, err := GetGlobalScheduleWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load schedule: %v", err)
+	}
+	if len(resets) != 1 || resets[0] != "00:00 UTC" {
+		t.Errorf("unexpected quota resets: %v", resets)
+	}
+	if len(quiet) != 1 || quiet[0] != "09:00-17:00" {
+		t.Errorf("unexpected quiet hours: %v", quiet)
+	}
+
+	if err := ClearGlobalScheduleWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear schedule: %v", err)
+	}
+	resets, quiet, _ = GetGlobalScheduleWithOptions(opts)
+	if len(resets) != 0 || len(quiet) != 0 {
+		t.Errorf("expected schedule cleared, got resets=%v quiet=%v", resets, quiet)
+	}
+}
+
+// TestResolveIcons tests icon set selection and per-icon overrides
+func TestResolveIcons(t *testing.T) {
+	if icons := ResolveIcons(nil); icons.State(StatePending) != "○" {
+		t.Errorf("expected Unicode icons by default, got %q", icons.State(StatePending))
+	}
+
+	config := DefaultConfig()
+	if err := config.SetIconSet("emoji"); err == nil {
+		t.Error("expected error for unknown icon set")
+	}
+	if err := config.SetIconSet(IconSetASCII); err != nil {
+		t.Fatalf("failed to set icon set: %v", err)
+	}
+	if err := config.SetIconOverride("state.pending", "-"); err != nil {
+		t.Fatalf("failed to set override: %v", err)
+	}
+	if err := config.SetIconOverride("state.bogus", "-"); err == nil {
+		t.Error("expected error for unknown icon key")
+	}
+
+	icons := ResolveIcons(config)
+	if icons.State(StatePending) != "-" {
+		t.Errorf("expected override for pending, got %q", icons.State(StatePending))
+	}
+	if icons.State(StateInProgress) != "*" {
+		t.Errorf("expected ASCII icon for in_progress, got %q", icons.State(StateInProgress))
+	}
+
+	// Every ASCII icon must be plain ASCII so alignment doesn't depend on fonts
+	ascii := ASCIIIcons()
+	for _, key := range IconKeys() {
+		for _, r := range ascii.Get(key) {
+			if r > 127 {
+				t.Errorf("ASCII icon %s contains non-ASCII rune %q", key, r)
+			}
+		}
+	}
+}
+
+// TestTUIStateRoundTrip tests remembering TUI state per project and the default view setting
+func TestTUIStateRoundTrip(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	state, err := LoadTUIStateWithOptions(opts, "/projects/a")
+	if err != nil || state != nil {
+		t.Fatalf("expected no state before first save, got %+v (%v)", state, err)
+	}
+
+	saved := &TUIState{SessionID: "feature", ActivePanel: "sessions", Sort: "priority", Cursor: 3}
+	if err := SaveTUIStateWithOptions(opts, "/projects/a", saved); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	if err := SaveTUIStateWithOptions(opts, "/projects/b", &TUIState{SessionID: "other"}); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+
+	state, err = LoadTUIStateWithOptions(opts, "/projects/a")
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	if state == nil || state.SessionID != "feature" || state.Sort != "priority" || state.Cursor != 3 {
+		t.Errorf("expected project a state restored, got %+v", state)
+	}
+
+	config := DefaultConfig()
+	if config.GetDefaultView() != ViewSplit {
+		t.Errorf("expected split view by default, got %q", config.GetDefaultView())
+	}
+	if err := config.SetDefaultView("grid"); err == nil {
+		t.Error("expected error for unknown view")
+	}
+	if err := UpdateGlobalDefaultViewWithOptions(opts, ViewBoard); err != nil {
+		t.Fatalf("failed to set default view: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.GetDefaultView() != ViewBoard {
+		t.Errorf("expected board view persisted, got %q", loaded.GetDefaultView())
+	}
+}
+
+// TestSafeModeRevertConfig tests the safe-mode revert policy setting
+func TestSafeModeRevertConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if config.GetSafeModeRevert() != SafeModeRevertAsk {
+		t.Errorf("expected ask by default, got %q", config.GetSafeModeRevert())
+	}
+	if err := config.SetSafeModeRevert("always"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+
+	if err := UpdateGlobalSafeModeRevertWithOptions(opts, SafeModeRevertAuto); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.GetSafeModeRevert() != SafeModeRevertAuto {
+		t.Errorf("expected auto persisted, got %q", loaded.GetSafeModeRevert())
+	}
+
+	if err := ClearGlobalSafeModeRevertWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear policy: %v", err)
+	}
+	loaded, err = LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.SafeModeRevert != "" {
+		t.Errorf("expected policy cleared, got %q", loaded.SafeModeRevert)
+	}
+}
+
+// TestCompletionOwnershipConfig tests the completion ownership policy setting
+func TestCompletionOwnershipConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if config.GetCompletionOwnership() != CompletionOwnershipKeep {
+		t.Errorf("expected keep by default, got %q", config.GetCompletionOwnership())
+	}
+	if err := config.SetCompletionOwnership("steal"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+
+	if err := UpdateGlobalCompletionOwnershipWithOptions(opts, CompletionOwnershipTransfer); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.GetCompletionOwnership() != CompletionOwnershipTransfer {
+		t.Errorf("expected transfer persisted, got %q", loaded.GetCompletionOwnership())
+	}
+
+	if err := ClearGlobalCompletionOwnershipWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear policy: %v", err)
+	}
+	loaded, err = LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.CompletionOwnership != "" {
+		t.Errorf("expected policy cleared, got %q", loaded.CompletionOwnership)
+	}
+}
+
+func TestAgentSignalsConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if signals := config.GetAgentSignals(); signals.Strict || len(signals.Tags) != 0 {
+		t.Errorf("expected tolerant defaults, got %+v", signals)
+	}
+	if err := config.SetAgentSignals(&AgentSignalsConfig{Tags: []string{"not a tag"}}); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+
+	err := UpdateGlobalAgentSignalsWithOptions(opts, func(signals *AgentSignalsConfig) {
+		signals.Strict = true
+		signals.Tags = append(signals.Tags, "signal")
+	})
+	if err != nil {
+		t.Fatalf("failed to update signals: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	signals := loaded.GetAgentSignals()
+	if !signals.Strict || len(signals.Tags) != 1 || signals.Tags[0] != "signal" {
+		t.Errorf("expected strict with tag signal persisted, got %+v", signals)
+	}
+
+	if err := ClearGlobalAgentSignalsWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear signals: %v", err)
+	}
+	loaded, err = LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.AgentSignals != nil {
+		t.Errorf("expected signals cleared, got %+v", loaded.AgentSignals)
+	}
+}
+
+func TestSortExpr(t *testing.T) {
+	if _, err := ParseSortExpr("priority desc, due_date asc"); err == nil {
+		t.Error("expected error for unknown field due_date")
+	}
+	if _, err := ParseSortExpr("priority sideways"); err == nil {
+		t.Error("expected error for invalid direction")
+	}
+	expr, err := ParseSortExpr("Priority DESC, created_at asc,age desc")
+	if err != nil {
+		t.Fatalf("failed to parse sort expression: %v", err)
+	}
+	if got := expr.String(); got != "priority desc, created, age desc" {
+		t.Errorf("expected canonical expression, got %q", got)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := &Ball{ID: "p-1", Priority: PriorityHigh, StartedAt: base}
+	young := &Ball{ID: "p-2", Priority: PriorityHigh, StartedAt: base.Add(time.Hour)}
+	urgent := &Ball{ID: "p-3", Priority: PriorityUrgent, StartedAt: base.Add(2 * time.Hour)}
+
+	expr, _ = ParseSortExpr("priority desc, age desc")
+	if expr.Compare(urgent, old) >= 0 {
+		t.Error("expected urgent ball before high priority ball")
+	}
+	if expr.Compare(old, young) >= 0 {
+		t.Error("expected older ball first on equal priority")
+	}
+	if expr.Compare(old, old) != 0 {
+		t.Error("expected equal balls to compare equal")
+	}
+	if CompareBallIDs("p-9", "p-10") >= 0 {
+		t.Error("expected numeric ID suffixes to compare numerically")
+	}
+}
+
+func TestSortOrdersConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if err := config.SetSortOrder("my triage", "priority desc"); err == nil {
+		t.Error("expected error for name with a space")
+	}
+	if err := config.SetSortOrder("triage", "due_date"); err == nil {
+		t.Error("expected error for invalid expression")
+	}
+
+	if err := UpdateGlobalSortOrderWithOptions(opts, "triage", "priority desc,age desc"); err != nil {
+		t.Fatalf("failed to add sort order: %v", err)
+	}
+	if err := UpdateGlobalSortOrderWithOptions(opts, "stale", "activity"); err != nil {
+		t.Fatalf("failed to add sort order: %v", err)
+	}
+	if err := UpdateGlobalSortOrderWithOptions(opts, "Triage", "priority desc"); err != nil {
+		t.Fatalf("failed to replace sort order: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	want := []NamedSortOrder{{Name: "triage", Expr: "priority desc"}, {Name: "stale", Expr: "activity"}}
+	if len(loaded.SortOrders) != len(want) || loaded.SortOrders[0] != want[0] || loaded.SortOrders[1] != want[1] {
+		t.Errorf("expected sort orders %+v, got %+v", want, loaded.SortOrders)
+	}
+
+	if err := RemoveGlobalSortOrderWithOptions(opts, "missing"); err == nil {
+		t.Error("expected error removing unknown sort order")
+	}
+	if err := RemoveGlobalSortOrderWithOptions(opts, "triage"); err != nil {
+		t.Fatalf("failed to remove sort order: %v", err)
+	}
+	if err := ClearGlobalSortOrdersWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear sort orders: %v", err)
+	}
+	loaded, err = LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if len(loaded.SortOrders) != 0 {
+		t.Errorf("expected sort orders cleared, got %+v", loaded.SortOrders)
+	}
+}
+
+func TestScrubber(t *testing.T) {
+	if scrubber, err := NewScrubber(nil); err != nil || scrubber.Scrub("sk-ant-REDACTED", nil) != "sk-ant-REDACTED" {
+		t.Error("expected no scrubbing when unconfigured")
+	}
+	if _, err := NewScrubber(&ScrubConfig{Rules: []string{"phone_numbers"}}); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+	if _, err := NewScrubber(&ScrubConfig{Patterns: []string{"("}}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+
+	scrubber, err := NewScrubber(&ScrubConfig{Patterns: []string{`ACME-[0-9]{6}`}})
+	if err != nil {
+		t.Fatalf("failed to create scrubber: %v", err)
+	}
+	report := ScrubReport{}
+	text := "Use sk-ant-REDACTED, ask bob@example.com, set API_KEY=hunter2hunter2 for ACME-123456"
+	got := scrubber.Scrub(text, report)
+	want := "Use [REDACTED:api_keys], ask [REDACTED:emails], set API_KEY=[REDACTED:api_keys] for [REDACTED:pattern]"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if report.Total() != 4 || report[ScrubRuleAPIKeys] != 2 || report["ACME-[0-9]{6}"] != 1 {
+		t.Errorf("unexpected report %v", report)
+	}
+	if report.String() != "1 ACME-[0-9]{6}, 2 api_keys, 1 emails" {
+		t.Errorf("unexpected report summary %q", report.String())
+	}
+
+	// Only the selected rules apply
+	scrubber, _ = NewScrubber(&ScrubConfig{Rules: []string{ScrubRuleAPIKeys}})
+	if got := scrubber.Scrub("mail bob@example.com", nil); got != "mail bob@example.com" {
+		t.Errorf("expected emails kept with api_keys only, got %q", got)
+	}
+}
+
+func TestScrubConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	err := UpdateGlobalScrubWithOptions(opts, func(scrub *ScrubConfig) {
+		scrub.Rules = []string{"bogus"}
+	})
+	if err == nil {
+		t.Error("expected error for unknown rule")
+	}
+
+	if err := UpdateGlobalScrubWithOptions(opts, func(scrub *ScrubConfig) {}); err != nil {
+		t.Fatalf("failed to enable scrubbing: %v", err)
+	}
+	err = UpdateGlobalScrubWithOptions(opts, func(scrub *ScrubConfig) {
+		scrub.Patterns = append(scrub.Patterns, `ACME-[0-9]+`)
+	})
+	if err != nil {
+		t.Fatalf("failed to add pattern: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.Scrub == nil || len(loaded.Scrub.Rules) != 0 || len(loaded.Scrub.Patterns) != 1 {
+		t.Errorf("expected scrubbing on with one pattern, got %+v", loaded.Scrub)
+	}
+
+	if err := ClearGlobalScrubWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear scrub settings: %v", err)
+	}
+	loaded, err = LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.Scrub != nil {
+		t.Errorf("expected scrubbing off, got %+v", loaded.Scrub)
+	}
+}
+
+func TestModelProvidersConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if err := config.SetModelProvider("tiny", "ollama"); err == nil {
+		t.Error("expected error for invalid size")
+	}
+	if err := config.SetModelProvider("small", "gpt"); err == nil {
+		t.Error("expected error for invalid provider")
+	}
+
+	if err := UpdateGlobalModelProviderWithOptions(opts, "haiku", "ollama"); err != nil {
+		t.Fatalf("failed to route small models: %v", err)
+	}
+	if err := UpdateGlobalModelProviderWithOptions(opts, "large", "claude"); err != nil {
+		t.Fatalf("failed to route large models: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if loaded.ModelProviders["small"] != "ollama" || loaded.ModelProviders["large"] != "claude" {
+		t.Errorf("expected routes persisted by size, got %v", loaded.ModelProviders)
+	}
+
+	project := map[string]string{"large": "shell"}
+	if got := ResolveModelProvider("opus", loaded.ModelProviders, project); got != "shell" {
+		t.Errorf("expected the project route to win, got %q", got)
+	}
+	if got := ResolveModelProvider("small", loaded.ModelProviders, project); got != "ollama" {
+		t.Errorf("expected the global route, got %q", got)
+	}
+	if got := ResolveModelProvider("gpt-4o", loaded.ModelProviders, project); got != "" {
+		t.Errorf("expected no route for an explicit model, got %q", got)
+	}
+
+	if err := loaded.SetModelProvider("small", ""); err != nil {
+		t.Fatalf("failed to remove route: %v", err)
+	}
+	if err := loaded.SetModelProvider("large", ""); err != nil {
+		t.Fatalf("failed to remove route: %v", err)
+	}
+	if loaded.ModelProviders != nil {
+		t.Errorf("expected routes cleared, got %v", loaded.ModelProviders)
+	}
+}
+
+func TestKeySequencesConfig(t *testing.T) {
+	opts := ConfigOptions{ConfigHome: t.TempDir(), JuggleDirName: ".juggle"}
+
+	config := DefaultConfig()
+	if got := config.GetKeySequences().Timeout(); got != DefaultKeySequenceTimeout {
+		t.Errorf("expected the default timeout, got %v", got)
+	}
+	if err := config.SetKeySequences(&KeySequenceConfig{TimeoutMs: -5}); err == nil {
+		t.Error("expected error for a negative timeout")
+	}
+	if err := config.SetKeySequences(&KeySequenceConfig{TimeoutMs: 120000}); err == nil {
+		t.Error("expected error for a timeout over a minute")
+	}
+
+	err := UpdateGlobalKeySequencesWithOptions(opts, func(keys *KeySequenceConfig) {
+		keys.TimeoutMs = 3000
+		keys.WhichKey = true
+	})
+	if err != nil {
+		t.Fatalf("failed to save key sequences: %v", err)
+	}
+	loaded, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	keys := loaded.GetKeySequences()
+	if keys.Timeout() != 3*time.Second || !keys.WhichKey {
+		t.Errorf("expected 3s with which-key persisted, got %+v", keys)
+	}
+
+	if err := ClearGlobalKeySequencesWithOptions(opts); err != nil {
+		t.Fatalf("failed to clear key sequences: %v", err)
+	}
+	if loaded, _ = LoadConfigWithOptions(opts); loaded.KeySequences != nil {
+		t.Errorf("expected key sequences cleared, got %+v", loaded.KeySequences)
+	}
+}
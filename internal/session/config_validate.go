@@ -0,0 +1,620 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigIssue is a problem found in a config file. Errors are settings
+// juggle rejects or misreads; warnings are settings it ignores or that
+// point at something missing.
+type ConfigIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Field   string `json:"field,omitempty"` // JSON path, e.g. "http_agent.format" or "hooks.on_ball_complete[0]"
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	Warning bool   `json:"warning,omitempty"`
+}
+
+// String formats the issue as file:line: field: message
+func (i ConfigIssue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location += ":" + strconv.Itoa(i.Line)
+	}
+	if i.Field != "" {
+		return fmt.Sprintf("%s: %s: %s", location, i.Field, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", location, i.Message)
+}
+
+// ConfigFiles are the layers of configuration validated together. Paths
+// that are empty or don't exist are skipped.
+type ConfigFiles struct {
+	Global     string   // ~/.juggle/config.json
+	Project    string   // <project>/.juggle/config.json
+	Sessions   []string // <project>/.juggle/sessions/<id>/session.json
+	ProjectDir string   // Where relative commands in any layer run from
+}
+
+// configFile is one config file being validated
+type configFile struct {
+	path   string
+	data   []byte
+	lines  map[string]int // Line of each key and array element, by JSON path
+	issues []ConfigIssue
+}
+
+// ValidateConfig checks every layer of configuration for unknown keys,
+// values of the wrong type, invalid values, and missing directories and
+// commands, and then for settings that only break in combination
+func ValidateConfig(files ConfigFiles) []ConfigIssue {
+	globalFile := readConfigFile(files.Global)
+	var global *Config
+	if globalFile != nil {
+		var config Config
+		if globalFile.decode(&config, reflect.TypeOf(config)) {
+			globalFile.checkGlobal(&config, files.ProjectDir)
+			global = &config
+		}
+	}
+
+	projectFile := readConfigFile(files.Project)
+	var project *ProjectConfig
+	if projectFile != nil {
+		var config ProjectConfig
+		if projectFile.decode(&config, reflect.TypeOf(config)) {
+			projectFile.checkProject(&config, files.ProjectDir)
+			project = &config
+		}
+	}
+
+	var sessionFiles []*configFile
+	var sessions []*JuggleSession
+	for _, path := range files.Sessions {
+		file := readConfigFile(path)
+		if file == nil {
+			continue
+		}
+		sessionFiles = append(sessionFiles, file)
+		var sess JuggleSession
+		if file.decode(&sess, reflect.TypeOf(sess)) {
+			file.checkSession(&sess)
+			sessions = append(sessions, &sess)
+		} else {
+			sessions = append(sessions, nil)
+		}
+	}
+
+	// The shell provider can be picked in any layer, but its command is
+	// only set globally
+	shellMissing := global == nil || global.ShellAgent == nil || strings.TrimSpace(global.ShellAgent.Command) == ""
+	checkShell := func(file *configFile, field, provider string) {
+		if provider == "shell" && shellMissing {
+			file.add(field, `selects the "shell" provider but no shell_agent.command is set in the global config`,
+				"set it with: juggle config shell set --command <command>")
+		}
+	}
+	if global != nil {
+		checkShell(globalFile, "agent_provider", global.AgentProvider)
+		for _, size := range sortedKeys(global.ModelProviders) {
+			checkShell(globalFile, "model_providers."+size, global.ModelProviders[size])
+		}
+	}
+	if project != nil {
+		checkShell(projectFile, "agent_provider", project.AgentProvider)
+		for _, size := range sortedKeys(project.ModelProviders) {
+			checkShell(projectFile, "model_providers."+size, project.ModelProviders[size])
+		}
+	}
+	for i, sess := range sessions {
+		if sess != nil {
+			checkShell(sessionFiles[i], "agent_provider", sess.AgentProvider)
+		}
+	}
+
+	var issues []ConfigIssue
+	for _, file := range append([]*configFile{globalFile, projectFile}, sessionFiles...) {
+		if file != nil {
+			sort.SliceStable(file.issues, func(i, j int) bool { return file.issues[i].Line < file.issues[j].Line })
+			issues = append(issues, file.issues...)
+		}
+	}
+	return issues
+}
+
+// readConfigFile reads a config file for validation, or returns nil if
+// there's no such file. Read errors are reported as an issue.
+func readConfigFile(path string) *configFile {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	file := &configFile{path: path, data: data}
+	if err != nil {
+		file.add("", fmt.Sprintf("can't be read: %v", err), "")
+	}
+	return file
+}
+
+// decode parses the file into v, reporting syntax errors, unknown keys and
+// values of the wrong type. It returns false if v couldn't be filled in.
+func (f *configFile) decode(v any, t reflect.Type) bool {
+	if len(f.issues) > 0 {
+		return false
+	}
+	var raw any
+	if err := json.Unmarshal(f.data, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			f.issues = append(f.issues, ConfigIssue{File: f.path, Line: f.lineAt(syntaxErr.Offset), Message: "invalid JSON: " + syntaxErr.Error(),
+				Hint: "fix the syntax; juggle can't read any of this file until then"})
+		} else {
+			f.add("", "invalid JSON: "+err.Error(), "")
+		}
+		return false
+	}
+	if _, ok := raw.(map[string]any); !ok {
+		f.add("", "must be a JSON object", "")
+		return false
+	}
+	f.lines = jsonKeyLines(f.data)
+	f.checkKeys(raw, t, "")
+
+	if err := json.Unmarshal(f.data, v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			f.issues = append(f.issues, ConfigIssue{File: f.path, Line: f.lineAt(typeErr.Offset), Field: typeErr.Field,
+				Message: fmt.Sprintf("must be %s, not %s", describeJSONType(typeErr.Type), typeErr.Value)})
+		} else {
+			f.add("", err.Error(), "")
+		}
+		return false
+	}
+	return true
+}
+
+// checkKeys warns about keys the type has no field for, suggesting the
+// closest known key
+func (f *configFile) checkKeys(raw any, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		items, ok := raw.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			f.checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, key := range sortedKeys(object) {
+			f.checkKeys(object[key], t.Elem(), joinConfigPath(path, key))
+		}
+	case reflect.Struct:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return // e.g. time.Time, which is a string in JSON
+		}
+		fields := make(map[string]reflect.Type)
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+		for _, key := range sortedKeys(object) {
+			field := joinConfigPath(path, key)
+			fieldType, known := fields[key]
+			if known {
+				f.checkKeys(object[key], fieldType, field)
+				continue
+			}
+			hint := "remove it; juggle ignores it"
+			if suggestion := closestKey(key, fields); suggestion != "" {
+				hint = fmt.Sprintf("did you mean %q?", suggestion)
+			}
+			f.warn(field, "unknown key", hint)
+		}
+	}
+}
+
+// checkGlobal validates the values of the global config
+func (f *configFile) checkGlobal(c *Config, projectDir string) {
+	scratch := DefaultConfig()
+	for i, project := range c.RegisteredProjects() {
+		if project.Status() == ProjectMissing {
+			f.warn(fmt.Sprintf("projects[%d]", i), fmt.Sprintf("directory %s doesn't exist", project.Path),
+				"if it moved: juggle projects move <old> <new>; otherwise: juggle config paths prune")
+		}
+	}
+	f.checkNonNegative("iteration_delay_minutes", c.IterationDelayMinutes)
+	f.checkNonNegative("iteration_delay_fuzz", c.IterationDelayFuzz)
+	f.checkNonNegative("overload_retry_minutes", c.OverloadRetryMinutes)
+	f.checkNonNegative("brownout_threshold", c.BrownoutThreshold)
+	f.checkNonNegative("brownout_cooldown_minutes", c.BrownoutCooldownMinutes)
+	for i, reset := range c.QuotaResetTimes {
+		f.check(fmt.Sprintf("quota_reset_times[%d]", i), ValidateQuotaResetTime(reset))
+	}
+	for i, window := range c.QuietHours {
+		f.check(fmt.Sprintf("quiet_hours[%d]", i), ValidateQuietHours(window))
+	}
+	f.checkCommand("brownout_notify_command", c.BrownoutNotifyCommand, projectDir)
+	f.checkCommand("target_notify_command", c.TargetNotifyCommand, projectDir)
+	f.checkCommand("escalation_notify_command", c.EscalationNotifyCommand, projectDir)
+	if c.SafeModeRevert != "" {
+		f.check("safe_mode_revert", scratch.SetSafeModeRevert(c.SafeModeRevert))
+	}
+	if c.CompletionOwnership != "" {
+		f.check("completion_ownership", scratch.SetCompletionOwnership(c.CompletionOwnership))
+	}
+	f.check("vcs", scratch.SetVCS(c.VCS))
+	f.check("agent_provider", scratch.SetAgentProvider(c.AgentProvider))
+	f.checkModelOverrides(c.ModelOverrides)
+	for _, size := range sortedKeys(c.ModelProviders) {
+		f.check("model_providers."+size, scratch.SetModelProvider(size, c.ModelProviders[size]))
+	}
+	if c.HTTPAgent != nil {
+		f.check("http_agent", scratch.SetHTTPAgent(c.HTTPAgent))
+	}
+	if c.ShellAgent != nil {
+		f.check("shell_agent", scratch.SetShellAgent(c.ShellAgent))
+		f.checkCommand("shell_agent.command", c.ShellAgent.Command, projectDir)
+	}
+	if c.OllamaAgent != nil {
+		f.check("ollama_agent", scratch.SetOllamaAgent(c.OllamaAgent))
+	}
+	if c.AgentSignals != nil {
+		f.check("agent_signals", scratch.SetAgentSignals(c.AgentSignals))
+	}
+	if c.Scrub != nil {
+		f.check("scrub", c.Scrub.Validate())
+	}
+	if c.AgentLogs != nil {
+		f.check("agent_logs", scratch.SetAgentLogs(c.AgentLogs))
+	}
+	if c.IconSet != "" {
+		f.check("icon_set", scratch.SetIconSet(c.IconSet))
+	}
+	for _, key := range sortedKeys(c.IconOverrides) {
+		f.check("icon_overrides."+key, scratch.SetIconOverride(key, c.IconOverrides[key]))
+	}
+	if c.DefaultView != "" {
+		f.check("default_view", scratch.SetDefaultView(c.DefaultView))
+	}
+	for i, order := range c.SortOrders {
+		field := fmt.Sprintf("sort_orders[%d]", i)
+		if scratch.FindSortOrder(order.Name) != nil {
+			f.warn(field, fmt.Sprintf("duplicate sort order %q; only the first is used", order.Name), "")
+			continue
+		}
+		f.check(field, scratch.SetSortOrder(order.Name, order.Expr))
+	}
+	if c.Attention != nil {
+		f.check("attention", scratch.SetAttention(c.Attention))
+	}
+	if c.KeySequences != nil {
+		f.check("key_sequences", scratch.SetKeySequences(c.KeySequences))
+	}
+}
+
+// checkProject validates the values of a project config
+func (f *configFile) checkProject(c *ProjectConfig, projectDir string) {
+	scratch := DefaultProjectConfig()
+	f.checkList("default_acceptance_criteria", c.DefaultAcceptanceCriteria)
+	f.checkList("ac_templates", c.ACTemplates)
+	f.check("vcs", scratch.SetVCS(c.VCS))
+	f.check("agent_provider", scratch.SetAgentProvider(c.AgentProvider))
+	f.checkModelOverrides(c.ModelOverrides)
+	for _, size := range sortedKeys(c.ModelProviders) {
+		f.check("model_providers."+size, scratch.SetModelProvider(size, c.ModelProviders[size]))
+	}
+	for _, name := range sortedKeys(c.RunAliases) {
+		if strings.TrimSpace(c.RunAliases[name]) == "" {
+			f.add("run_aliases."+name, "command is empty", "")
+		}
+	}
+	if c.TestsPolicy != "" {
+		f.check("tests_policy", scratch.SetTestsPolicy(c.TestsPolicy))
+	}
+	f.check("week_capacity", scratch.SetWeekCapacity(c.WeekCapacity))
+	f.check("store_backend", ValidateStoreBackend(c.StoreBackend))
+	if c.TitleRules != nil {
+		f.checkNonNegative("title_rules.max_length", c.TitleRules.MaxLength)
+		f.checkList("title_rules.forbidden_prefixes", c.TitleRules.ForbiddenPrefixes)
+	}
+	for _, event := range sortedKeys(c.Hooks) {
+		field := "hooks." + string(event)
+		if err := ValidateHookEvent(event); err != nil {
+			f.check(field, err)
+			continue
+		}
+		for i, hook := range c.Hooks[event] {
+			hookField := fmt.Sprintf("%s[%d]", field, i)
+			f.check(hookField, hook.Validate())
+			f.checkCommand(hookField+".command", hook.Command, projectDir)
+		}
+	}
+}
+
+// checkSession validates the settings a session overrides
+func (f *configFile) checkSession(s *JuggleSession) {
+	if s.DefaultModel != "" && !ValidateModelSize(string(s.DefaultModel)) {
+		f.add("default_model", fmt.Sprintf("invalid model size %q (must be small, medium, or large)", s.DefaultModel), "")
+	}
+	if !ValidateAgentProvider(s.AgentProvider) {
+		f.add("agent_provider", fmt.Sprintf("invalid agent provider %q (must be claude, opencode, http, ollama, or shell)", s.AgentProvider), "")
+	}
+	f.checkList("acceptance_criteria", s.AcceptanceCriteria)
+	if s.Target != nil {
+		if s.Target.Count <= 0 {
+			f.add("target.count", "must be a positive number of balls", "")
+		}
+		if _, err := ParseThroughputTarget(fmt.Sprintf("1/%s", s.Target.Period)); err != nil {
+			f.add("target.period", fmt.Sprintf("invalid period %q (must be day, week, or month)", s.Target.Period), "")
+		}
+	}
+}
+
+// checkModelOverrides warns about overrides for models juggle never picks
+func (f *configFile) checkModelOverrides(overrides map[string]string) {
+	for _, model := range sortedKeys(overrides) {
+		field := "model_overrides." + model
+		if model == "" || !ValidateModelOverride(model) {
+			f.warn(field, "not a model juggle picks (opus, sonnet, or haiku), so it's never used", "")
+		} else if strings.TrimSpace(overrides[model]) == "" {
+			f.add(field, "override is empty", "")
+		}
+	}
+}
+
+// checkList reports empty entries and warns about duplicates
+func (f *configFile) checkList(field string, items []string) {
+	seen := make(map[string]bool)
+	for i, item := range items {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		switch {
+		case strings.TrimSpace(item) == "":
+			f.add(itemField, "is empty", "")
+		case seen[item]:
+			f.warn(itemField, fmt.Sprintf("duplicate of %q", item), "")
+		}
+		seen[item] = true
+	}
+}
+
+// checkNonNegative reports a negative number
+func (f *configFile) checkNonNegative(field string, n int) {
+	if n < 0 {
+		f.add(field, fmt.Sprintf("must not be negative, got %d", n), "")
+	}
+}
+
+// checkCommand warns when the program a shell command starts with can't be
+// found. Commands starting with shell syntax aren't checked, and relative
+// paths only when the directory they run in is known.
+func (f *configFile) checkCommand(field, command, dir string) {
+	words := strings.Fields(command)
+	if len(words) == 0 || strings.ContainsAny(words[0], "$`=(){};|&<>'\"") {
+		return
+	}
+	program := words[0]
+	if !strings.Contains(program, "/") {
+		if _, err := exec.LookPath(program); err != nil {
+			f.warn(field, fmt.Sprintf("command %q not found on PATH", program), "install it or use its full path")
+		}
+		return
+	}
+	if !filepath.IsAbs(program) {
+		if dir == "" {
+			return
+		}
+		program = filepath.Join(dir, program)
+	}
+	if _, err := os.Stat(program); err != nil {
+		f.warn(field, fmt.Sprintf("%s doesn't exist", words[0]), "")
+	}
+}
+
+// check reports err, if any, as an error in field
+func (f *configFile) check(field string, err error) {
+	if err != nil {
+		f.add(field, err.Error(), "")
+	}
+}
+
+// add reports an error in field. An empty field means the whole file.
+func (f *configFile) add(field, message, hint string) {
+	f.issues = append(f.issues, ConfigIssue{File: f.path, Line: f.fieldLine(field), Field: field, Message: message, Hint: hint})
+}
+
+// warn reports a warning in field
+func (f *configFile) warn(field, message, hint string) {
+	f.issues = append(f.issues, ConfigIssue{File: f.path, Line: f.fieldLine(field), Field: field, Message: message, Hint: hint, Warning: true})
+}
+
+// fieldLine returns the line field is on, or the line of its closest
+// parent that was found
+func (f *configFile) fieldLine(field string) int {
+	for field != "" {
+		if line, ok := f.lines[field]; ok {
+			return line
+		}
+		cut := strings.LastIndexAny(field, ".[")
+		if cut < 0 {
+			break
+		}
+		field = field[:cut]
+	}
+	return 0
+}
+
+// lineAt returns the line a byte offset is on
+func (f *configFile) lineAt(offset int64) int {
+	if offset > int64(len(f.data)) {
+		offset = int64(len(f.data))
+	}
+	return bytes.Count(f.data[:offset], []byte("\n")) + 1
+}
+
+// jsonKeyLines maps the JSON path of every object key and array element in
+// data to the line it starts on
+func jsonKeyLines(data []byte) map[string]int {
+	type frame struct {
+		path  string
+		array bool
+		index int
+		key   string // Key of the value being read, in an object
+	}
+	lines := make(map[string]int)
+	lineAt := func(offset int64) int { return bytes.Count(data[:offset], []byte("\n")) + 1 }
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var stack []*frame
+	expectKey := false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return lines
+		}
+		offset := decoder.InputOffset()
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.index++
+				expectKey = !parent.array
+			}
+			continue
+		}
+		if top != nil && !top.array && expectKey {
+			top.key = token.(string)
+			lines[joinConfigPath(top.path, top.key)] = lineAt(offset)
+			expectKey = false
+			continue
+		}
+
+		path := ""
+		if top != nil {
+			if top.array {
+				path = fmt.Sprintf("%s[%d]", top.path, top.index)
+				lines[path] = lineAt(offset)
+			} else {
+				path = joinConfigPath(top.path, top.key)
+			}
+		}
+		if delim, ok := token.(json.Delim); ok {
+			stack = append(stack, &frame{path: path, array: delim == '['})
+			expectKey = delim == '{'
+			continue
+		}
+		if top != nil {
+			top.index++
+			expectKey = !top.array
+		}
+	}
+}
+
+// joinConfigPath appends a key to a JSON path
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closestKey returns the known key that key is most likely a typo of, or
+// "" if none is close
+func closestKey(key string, known map[string]reflect.Type) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(s))
+	}
+	best, bestDistance := "", 3
+	for candidate := range known {
+		if normalize(candidate) == normalize(key) {
+			return candidate
+		}
+		if d := editDistance(key, candidate); d < bestDistance || d == bestDistance && candidate < best {
+			best, bestDistance = candidate, d
+		}
+	}
+	if bestDistance > 2 {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+		}
+		prev = current
+	}
+	return prev[len(b)]
+}
+
+// describeJSONType names the JSON type a Go type is read from
+func describeJSONType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Map, reflect.Struct, reflect.Pointer:
+		return "an object"
+	}
+	return t.String()
+}
+
+// sortedKeys returns a map's keys in order
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
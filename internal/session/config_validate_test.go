@@ -0,0 +1,94 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateConfig checks each layer's issues and where they're reported
+func TestValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	global := write("global.json", `{
+  "search_paths": ["`+dir+`"],
+  "agent_provider": "shell",
+  "http_agent": {
+    "format": "grpc",
+    "max_token": 100
+  },
+  "icon_sett": "ascii",
+  "sort_orders": [
+    {"name": "hot", "expr": "priority desc"},
+    {"name": "cold", "expr": "temperature asc"}
+  ]
+}`)
+	project := write("project.json", `{
+  "tests_policy": "maybe",
+  "hooks": {
+    "on_ball_complete": [{"command": "./missing-hook.sh"}],
+    "on_lunch": [{"url": "https://example.com"}]
+  }
+}`)
+	sess := write("session.json", `{"id": "auth", "default_model": 3}`)
+
+	issues := ValidateConfig(ConfigFiles{Global: global, Project: project, Sessions: []string{sess}, ProjectDir: dir})
+
+	want := []struct {
+		file    string
+		line    int
+		field   string
+		message string
+		warning bool
+	}{
+		{global, 3, "agent_provider", `selects the "shell" provider`, false},
+		{global, 4, "http_agent", `invalid format "grpc"`, false},
+		{global, 6, "http_agent.max_token", "unknown key", true},
+		{global, 8, "icon_sett", "unknown key", true},
+		{global, 11, "sort_orders[1]", `unknown sort field "temperature"`, false},
+		{project, 2, "tests_policy", `invalid tests policy "maybe"`, false},
+		{project, 4, "hooks.on_ball_complete[0].command", "./missing-hook.sh doesn't exist", true},
+		{project, 5, "hooks.on_lunch", `unknown hook event "on_lunch"`, false},
+		{sess, 1, "default_model", "must be a string", false},
+	}
+	if len(issues) != len(want) {
+		for _, issue := range issues {
+			t.Log(issue)
+		}
+		t.Fatalf("expected %d issues, got %d", len(want), len(issues))
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.File != w.file || got.Line != w.line || got.Field != w.field || !strings.Contains(got.Message, w.message) || got.Warning != w.warning {
+			t.Errorf("issue %d: expected %s:%d %s %q (warning %v), got %s (warning %v)", i, w.file, w.line, w.field, w.message, w.warning, got, got.Warning)
+		}
+	}
+	if issues[2].Hint != `did you mean "max_tokens"?` || issues[3].Hint != `did you mean "icon_set"?` {
+		t.Errorf("expected suggestions for the misspelled keys, got %q and %q", issues[2].Hint, issues[3].Hint)
+	}
+}
+
+// TestValidateConfig_SyntaxError checks a file that isn't valid JSON is
+// reported at the line of the mistake, and nothing else is
+func TestValidateConfig_SyntaxError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{\n  \"vcs\": \"git\",\n  \"agent_provider\": claude\n}"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	issues := ValidateConfig(ConfigFiles{Global: path})
+	if len(issues) != 1 || issues[0].Line != 3 || !strings.Contains(issues[0].Message, "invalid JSON") {
+		t.Fatalf("expected one syntax error on line 3, got %v", issues)
+	}
+	if issues := ValidateConfig(ConfigFiles{Global: filepath.Join(t.TempDir(), "missing.json")}); len(issues) != 0 {
+		t.Errorf("expected missing files to be skipped, got %v", issues)
+	}
+}
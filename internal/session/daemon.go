@@ -0,0 +1,267 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const daemonStatusFile = "daemon.json"
+
+// DaemonStatus is what `juggle agent daemon` is doing, saved after every
+// pass so `juggle agent daemon status` can show it
+type DaemonStatus struct {
+	PID       int                    `json:"pid"`
+	Hostname  string                 `json:"hostname"`
+	StartedAt time.Time              `json:"started_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Interval  string                 `json:"interval"`         // How often sessions are checked for work
+	Listen    string                 `json:"listen,omitempty"` // Address of the HTTP status endpoint
+	Stopped   bool                   `json:"stopped"`          // The daemon exited cleanly
+	Sessions  []*DaemonSessionStatus `json:"sessions"`
+}
+
+// Running returns true if the daemon is still running. It can only tell for
+// a daemon on this host; one elsewhere counts as running until it stops.
+func (d *DaemonStatus) Running() bool {
+	if d.Stopped {
+		return false
+	}
+	if hostname, _ := os.Hostname(); hostname != d.Hostname {
+		return true
+	}
+	return isProcessRunning(d.PID)
+}
+
+// Session returns the status of the given session, or nil
+func (d *DaemonStatus) Session(id string) *DaemonSessionStatus {
+	for _, s := range d.Sessions {
+		if s.Session == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// DaemonRun is one agent run the daemon launched
+type DaemonRun struct {
+	PID       int        `json:"pid"`
+	BallID    string     `json:"ball_id,omitempty"` // Set for per-ball runs; empty runs the whole session
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	ExitCode  int        `json:"exit_code"`
+	Error     string     `json:"error,omitempty"`
+	LogFile   string     `json:"log_file"`
+}
+
+// DaemonSessionStatus is the daemon's view of one session. A session with a
+// schedule gets runs when the schedule fires and it has workable balls; one
+// without gets a run when a ball becomes workable that no run has seen yet.
+type DaemonSessionStatus struct {
+	Session  string       `json:"session"`
+	Schedule string       `json:"schedule,omitempty"` // Cron expression or interval, e.g. "0 2 * * *" or "6h"
+	Limit    int          `json:"limit"`              // Most runs at once; above 1, each run works on one ball
+	Workable []string     `json:"workable"`           // IDs of pending and in-progress balls
+	Seen     []string     `json:"seen,omitempty"`     // Workable balls a finished run left behind; only others are new work
+	Running  []*DaemonRun `json:"running"`
+	NextRun  *time.Time   `json:"next_run,omitempty"` // When the schedule next fires
+	LastRun  *DaemonRun   `json:"last_run,omitempty"`
+	Launches int          `json:"launches"`
+}
+
+// NewDaemonSessionStatus returns the status of a session the daemon starts
+// watching. schedule is a five-field cron expression or an interval like
+// "6h" (empty launches runs when work appears).
+func NewDaemonSessionStatus(sessionID, schedule string, limit int, now time.Time) (*DaemonSessionStatus, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("invalid limit %d for session %s (must be 1 or more)", limit, sessionID)
+	}
+	s := &DaemonSessionStatus{Session: sessionID, Schedule: schedule, Limit: limit, Workable: []string{}, Running: []*DaemonRun{}}
+	if schedule != "" {
+		if err := s.recurrence().Validate(); err != nil {
+			return nil, fmt.Errorf("invalid schedule for session %s: %w", sessionID, err)
+		}
+		next, err := s.recurrence().Next(now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule for session %s: %w", sessionID, err)
+		}
+		s.NextRun = &next
+	}
+	return s, nil
+}
+
+// recurrence returns the schedule as a Recurrence: an expression with spaces
+// is cron, anything else an interval
+func (s *DaemonSessionStatus) recurrence() *Recurrence {
+	if strings.Contains(strings.TrimSpace(s.Schedule), " ") {
+		return &Recurrence{Cron: s.Schedule}
+	}
+	return &Recurrence{Every: s.Schedule}
+}
+
+// SetWorkable records the session's workable balls. Balls that stopped being
+// workable are forgotten, so one that becomes workable again is new work.
+func (s *DaemonSessionStatus) SetWorkable(ballIDs []string) {
+	s.Workable = ballIDs
+	seen := make([]string, 0, len(s.Seen))
+	for _, id := range s.Seen {
+		if containsString(ballIDs, id) {
+			seen = append(seen, id)
+		}
+	}
+	s.Seen = seen
+}
+
+// Plan returns the runs to launch now: "" for a run of the whole session,
+// or the IDs of balls to run one by one when the limit is above 1. A fired
+// schedule is advanced past now.
+func (s *DaemonSessionStatus) Plan(now time.Time) []string {
+	free := s.Limit - len(s.Running)
+	if free <= 0 {
+		return nil
+	}
+
+	candidates := make([]string, 0)
+	for _, id := range s.Workable {
+		if !s.isRunning(id) {
+			candidates = append(candidates, id)
+		}
+	}
+
+	if s.Schedule != "" {
+		if s.NextRun == nil || now.Before(*s.NextRun) {
+			return nil
+		}
+		if next, err := s.recurrence().Next(now); err == nil {
+			s.NextRun = &next
+		}
+	} else {
+		fresh := make([]string, 0, len(candidates))
+		for _, id := range candidates {
+			if !containsString(s.Seen, id) {
+				fresh = append(fresh, id)
+			}
+		}
+		candidates = fresh
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	if s.Limit == 1 {
+		return []string{""}
+	}
+	if len(candidates) > free {
+		candidates = candidates[:free]
+	}
+	return candidates
+}
+
+// isRunning returns true if a run for the ball (or the whole session) is going
+func (s *DaemonSessionStatus) isRunning(ballID string) bool {
+	for _, run := range s.Running {
+		if run.BallID == "" || run.BallID == ballID {
+			return true
+		}
+	}
+	return false
+}
+
+// Started records a launched run
+func (s *DaemonSessionStatus) Started(run *DaemonRun) {
+	s.Running = append(s.Running, run)
+	s.Launches++
+}
+
+// Finished records that the run with the given PID ended. Whatever it left
+// workable is seen: it won't be launched again until the schedule fires or
+// it stops and starts being workable.
+func (s *DaemonSessionStatus) Finished(pid int, endedAt time.Time, exitCode int, runErr error) {
+	for i, run := range s.Running {
+		if run.PID != pid {
+			continue
+		}
+		run.EndedAt = &endedAt
+		run.ExitCode = exitCode
+		if runErr != nil {
+			run.Error = runErr.Error()
+		}
+		s.Running = append(s.Running[:i], s.Running[i+1:]...)
+		s.LastRun = run
+
+		left := s.Workable
+		if run.BallID != "" {
+			left = []string{run.BallID}
+		}
+		for _, id := range left {
+			if containsString(s.Workable, id) && !containsString(s.Seen, id) {
+				s.Seen = append(s.Seen, id)
+			}
+		}
+		return
+	}
+}
+
+// DaemonWorkableBalls returns the IDs of the session's pending and
+// in-progress balls, the ones an agent run would work on. "all" is every
+// ball.
+func DaemonWorkableBalls(balls []*Ball, sessionID string) []string {
+	ids := make([]string, 0)
+	for _, ball := range balls {
+		if ball.State != StatePending && ball.State != StateInProgress {
+			continue
+		}
+		if sessionID != "all" && !ballHasTag(ball, sessionID) {
+			continue
+		}
+		ids = append(ids, ball.ID)
+	}
+	return ids
+}
+
+// daemonStatusPath returns the path of the daemon's status file
+func (s *Store) daemonStatusPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), daemonStatusFile)
+}
+
+// DaemonLogDir returns the directory the daemon's run logs are written to
+func (s *Store) DaemonLogDir() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), "daemon")
+}
+
+// LoadDaemonStatus returns the status the daemon last saved, or nil if no
+// daemon has run in this project
+func (s *Store) LoadDaemonStatus() (*DaemonStatus, error) {
+	data, err := os.ReadFile(s.daemonStatusPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read daemon status: %w", err)
+	}
+	var status DaemonStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse daemon status: %w", err)
+	}
+	return &status, nil
+}
+
+// SaveDaemonStatus atomically replaces the daemon's status file
+func (s *Store) SaveDaemonStatus(status *DaemonStatus) error {
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal daemon status: %w", err)
+	}
+	tempPath := s.daemonStatusPath() + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write daemon status: %w", err)
+	}
+	if err := os.Rename(tempPath, s.daemonStatusPath()); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace daemon status: %w", err)
+	}
+	return nil
+}
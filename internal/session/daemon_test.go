@@ -0,0 +1,119 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDaemonSessionStatus_NewWork(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	status, err := NewDaemonSessionStatus("auth", "", 1, now)
+	if err != nil {
+		t.Fatalf("Failed to create status: %v", err)
+	}
+
+	if got := status.Plan(now); got != nil {
+		t.Errorf("Expected nothing to run without work, got %v", got)
+	}
+	status.SetWorkable([]string{"a", "b"})
+	if got := status.Plan(now); !reflect.DeepEqual(got, []string{""}) {
+		t.Fatalf("Expected a whole-session run, got %v", got)
+	}
+	status.Started(&DaemonRun{PID: 10, StartedAt: now})
+	if got := status.Plan(now); got != nil {
+		t.Errorf("Expected no second run while one is going, got %v", got)
+	}
+
+	// The run leaves "b" behind; it isn't new work
+	status.SetWorkable([]string{"b"})
+	status.Finished(10, now, 0, nil)
+	if status.LastRun == nil || status.LastRun.PID != 10 || len(status.Running) != 0 {
+		t.Errorf("Expected the run recorded as finished, got %+v", status)
+	}
+	if got := status.Plan(now); got != nil {
+		t.Errorf("Expected leftover work to wait, got %v", got)
+	}
+
+	status.SetWorkable([]string{"b", "c"})
+	if got := status.Plan(now); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("Expected a new ball to start a run, got %v", got)
+	}
+
+	// A ball that stops being workable and comes back is new again
+	status.SetWorkable([]string{})
+	status.SetWorkable([]string{"b"})
+	if got := status.Plan(now); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("Expected a ball that became workable again to start a run, got %v", got)
+	}
+}
+
+func TestDaemonSessionStatus_PerBallLimit(t *testing.T) {
+	now := time.Now()
+	status, err := NewDaemonSessionStatus("backlog", "", 2, now)
+	if err != nil {
+		t.Fatalf("Failed to create status: %v", err)
+	}
+	status.SetWorkable([]string{"a", "b", "c"})
+	if got := status.Plan(now); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("Expected one run per ball up to the limit, got %v", got)
+	}
+	status.Started(&DaemonRun{PID: 1, BallID: "a"})
+	status.Started(&DaemonRun{PID: 2, BallID: "b"})
+	if got := status.Plan(now); got != nil {
+		t.Errorf("Expected no runs past the limit, got %v", got)
+	}
+
+	status.Finished(1, now, 1, nil)
+	if got := status.Plan(now); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("Expected the freed slot to go to the waiting ball, got %v", got)
+	}
+	if !containsString(status.Seen, "a") {
+		t.Errorf("Expected the finished ball seen, got %v", status.Seen)
+	}
+}
+
+func TestDaemonSessionStatus_Schedule(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.Local)
+	if _, err := NewDaemonSessionStatus("nightly", "whenever", 1, now); err == nil {
+		t.Error("Expected an invalid schedule to be rejected")
+	}
+	if _, err := NewDaemonSessionStatus("nightly", "", 0, now); err == nil {
+		t.Error("Expected a zero limit to be rejected")
+	}
+
+	status, err := NewDaemonSessionStatus("nightly", "0 2 * * *", 1, now)
+	if err != nil {
+		t.Fatalf("Failed to create status: %v", err)
+	}
+	if want := time.Date(2026, 10, 16, 2, 0, 0, 0, time.Local); status.NextRun == nil || !status.NextRun.Equal(want) {
+		t.Fatalf("Expected the next run at %v, got %v", want, status.NextRun)
+	}
+	status.SetWorkable([]string{"a"})
+	if got := status.Plan(now); got != nil {
+		t.Errorf("Expected new work to wait for the schedule, got %v", got)
+	}
+
+	fired := time.Date(2026, 10, 16, 2, 0, 30, 0, time.Local)
+	if got := status.Plan(fired); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("Expected a run when the schedule fires, got %v", got)
+	}
+	if want := time.Date(2026, 10, 17, 2, 0, 0, 0, time.Local); !status.NextRun.Equal(want) {
+		t.Errorf("Expected the schedule advanced to %v, got %v", want, status.NextRun)
+	}
+}
+
+func TestDaemonWorkableBalls(t *testing.T) {
+	balls := []*Ball{
+		{ID: "a", State: StatePending, Tags: []string{"auth"}},
+		{ID: "b", State: StateInProgress, Tags: []string{"auth"}},
+		{ID: "c", State: StateBlocked, Tags: []string{"auth"}},
+		{ID: "d", State: StatePending, Tags: []string{"docs"}},
+	}
+	if got := DaemonWorkableBalls(balls, "auth"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected auth's pending and in-progress balls, got %v", got)
+	}
+	if got := DaemonWorkableBalls(balls, "all"); !reflect.DeepEqual(got, []string{"a", "b", "d"}) {
+		t.Errorf("Expected every workable ball for all, got %v", got)
+	}
+}
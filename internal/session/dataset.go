@@ -0,0 +1,94 @@
+package session
+
+// DatasetExample is one iteration of an agent run: the prompt it was given,
+// the agent's response, and how the iteration and its run ended
+type DatasetExample struct {
+	RunID     string `json:"run_id"`
+	SessionID string `json:"session_id"`
+	Iteration int    `json:"iteration"`
+	Model     string `json:"model,omitempty"`
+	Prompt    string `json:"prompt"`
+	Response  string `json:"response"`
+	Outcome   string `json:"outcome"`    // How the iteration ended (IterationCompleted, ...)
+	RunResult string `json:"run_result"` // How the whole run ended
+	Label     string `json:"label,omitempty"`
+}
+
+// DatasetFilter selects the iterations exported by BuildDataset. Empty
+// fields match everything.
+type DatasetFilter struct {
+	Sessions []string // Session IDs
+	Outcomes []string // Iteration outcomes
+	Label    string   // Run label
+}
+
+func (f DatasetFilter) matchesRun(record *AgentRunRecord) bool {
+	if f.Label != "" && record.Label != f.Label {
+		return false
+	}
+	if len(f.Sessions) == 0 {
+		return true
+	}
+	for _, id := range f.Sessions {
+		if record.SessionID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (f DatasetFilter) matchesOutcome(outcome string) bool {
+	if len(f.Outcomes) == 0 {
+		return true
+	}
+	for _, o := range f.Outcomes {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildDataset pairs the saved prompt and response of each iteration of the
+// recorded runs, oldest run first. Only iterations with both saved and an
+// outcome recorded are included: runs from before prompts were saved, and
+// runs whose output was pruned, are skipped.
+func (s *AgentHistoryStore) BuildDataset(filter DatasetFilter) ([]DatasetExample, error) {
+	records, err := s.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make([]DatasetExample, 0)
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if !filter.matchesRun(record) {
+			continue
+		}
+		for _, outcome := range record.IterationOutcomes {
+			if !filter.matchesOutcome(outcome.Outcome) {
+				continue
+			}
+			prompt, err := s.LoadIterationPrompt(record.ID, outcome.Iteration)
+			if err != nil {
+				continue
+			}
+			response, err := s.LoadIterationOutput(record.ID, outcome.Iteration)
+			if err != nil {
+				continue
+			}
+			examples = append(examples, DatasetExample{
+				RunID:     record.ID,
+				SessionID: record.SessionID,
+				Iteration: outcome.Iteration,
+				Model:     outcome.Model,
+				Prompt:    prompt,
+				Response:  response,
+				Outcome:   outcome.Outcome,
+				RunResult: record.Result,
+				Label:     record.Label,
+			})
+		}
+	}
+	return examples, nil
+}
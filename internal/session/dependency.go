@@ -0,0 +1,123 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyIndex resolves dependency IDs against both active and archived balls.
+// Archived balls are always complete, so a dependency on an archived ball is
+// satisfied rather than a dangling reference.
+type DependencyIndex struct {
+	active   map[string]*Ball
+	archived map[string]*Ball
+}
+
+// ResolvedDependency is a single dependency ID resolved against a DependencyIndex
+type ResolvedDependency struct {
+	ID       string
+	Ball     *Ball // nil if the ID matches no active or archived ball
+	Archived bool
+}
+
+// NewDependencyIndex builds an index over active and archived balls.
+// Balls are indexed by full ID and by short ID; active balls win over archived
+// ones when a short ID is ambiguous.
+func NewDependencyIndex(active, archived []*Ball) *DependencyIndex {
+	idx := &DependencyIndex{
+		active:   make(map[string]*Ball, len(active)*2),
+		archived: make(map[string]*Ball, len(archived)*2),
+	}
+	for _, ball := range active {
+		idx.active[ball.ID] = ball
+		if _, exists := idx.active[ball.ShortID()]; !exists {
+			idx.active[ball.ShortID()] = ball
+		}
+	}
+	for _, ball := range archived {
+		idx.archived[ball.ID] = ball
+		if _, exists := idx.archived[ball.ShortID()]; !exists {
+			idx.archived[ball.ShortID()] = ball
+		}
+	}
+	return idx
+}
+
+// LoadDependencyIndex builds a dependency index from this store's active and archived balls
+func (s *Store) LoadDependencyIndex() (*DependencyIndex, error) {
+	var active, archived []*Ball
+	err := s.EachBall(ListOptions{IncludeArchived: true}, func(ball *Ball, isArchived bool) bool {
+		if isArchived {
+			archived = append(archived, ball)
+		} else {
+			active = append(active, ball)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewDependencyIndex(active, archived), nil
+}
+
+// Resolve looks up a dependency ID, preferring active balls over the archive
+func (idx *DependencyIndex) Resolve(id string) ResolvedDependency {
+	if idx != nil {
+		if ball, ok := idx.active[id]; ok {
+			return ResolvedDependency{ID: id, Ball: ball}
+		}
+		if ball, ok := idx.archived[id]; ok {
+			return ResolvedDependency{ID: id, Ball: ball, Archived: true}
+		}
+	}
+	return ResolvedDependency{ID: id}
+}
+
+// Found returns true if the dependency matched an active or archived ball
+func (d ResolvedDependency) Found() bool {
+	return d.Ball != nil
+}
+
+// Satisfied returns true if the dependency no longer holds up dependent balls.
+// Archived, complete, and researched balls are satisfied. Dependencies that
+// match no ball are also treated as satisfied so a stale reference can't
+// block work forever.
+func (d ResolvedDependency) Satisfied() bool {
+	if d.Ball == nil || d.Archived {
+		return true
+	}
+	return d.Ball.State == StateComplete || d.Ball.State == StateResearched
+}
+
+// Status returns a display label for the dependency's state,
+// e.g. "pending", "complete (archived)", or "missing"
+func (d ResolvedDependency) Status() string {
+	switch {
+	case d.Ball == nil:
+		return "missing"
+	case d.Archived:
+		return "complete (archived)"
+	default:
+		return string(d.Ball.State)
+	}
+}
+
+// DependenciesSatisfied returns true if all of a ball's dependencies are satisfied
+func (idx *DependencyIndex) DependenciesSatisfied(ball *Ball) bool {
+	for _, depID := range ball.DependsOn {
+		if !idx.Resolve(depID).Satisfied() {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatDependencies returns a ball's dependencies with their resolved status,
+// e.g. "abc-1 (pending), abc-2 (complete (archived))"
+func (idx *DependencyIndex) FormatDependencies(ball *Ball) string {
+	parts := make([]string, 0, len(ball.DependsOn))
+	for _, depID := range ball.DependsOn {
+		parts = append(parts, fmt.Sprintf("%s (%s)", depID, idx.Resolve(depID).Status()))
+	}
+	return strings.Join(parts, ", ")
+}
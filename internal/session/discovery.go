@@ -3,19 +3,18 @@ package session
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+
+	"github.com/ohare93/juggle/internal/profile"
 )
 
-// DiscoverProjects finds all directories containing .juggle folders
-// DiscoverProjects finds all directories containing .juggle folders
+// DiscoverProjects returns the enabled registered projects that have a
+// .juggle folder. Projects whose directory moved or disappeared are skipped.
 func DiscoverProjects(config *Config) ([]string, error) {
 	projects := make([]string, 0)
 
-	for _, path := range config.SearchPaths {
-		// Check if path exists and has .juggle directory
-		jugglePath := filepath.Join(path, ".juggle")
-		if _, err := os.Stat(jugglePath); err == nil {
-			projects = append(projects, path)
+	for _, project := range config.RegisteredProjects() {
+		if project.Status() == ProjectOK {
+			projects = append(projects, CleanPath(project.Path))
 		}
 	}
 
@@ -47,6 +46,8 @@ func LoadAllSessions(projectPaths []string) ([]*JuggleSession, error) {
 
 // LoadAllBalls loads balls from all discovered projects
 func LoadAllBalls(projectPaths []string) ([]*Ball, error) {
+	defer profile.Start(profile.KindStore, "load_all_balls")()
+
 	allBalls := make([]*Ball, 0)
 
 	for _, projectPath := range projectPaths {
@@ -139,13 +140,13 @@ func LoadBallsBySession(projectPaths []string, sessionID string) ([]*Ball, error
 
 // ProjectInfo holds information about a project and its balls
 type ProjectInfo struct {
-	Path            string
-	Name            string
-	TotalBalls      int
-	PendingBalls    int
-	InProgressBalls int
-	BlockedBalls    int
-	CompleteBalls   int
+	Path            string `json:"path"`
+	Name            string `json:"name"`
+	TotalBalls      int    `json:"total"`
+	PendingBalls    int    `json:"pending"`
+	InProgressBalls int    `json:"in_progress"`
+	BlockedBalls    int    `json:"blocked"`
+	CompleteBalls   int    `json:"complete"`
 }
 
 // GetProjectsInfo returns information about all projects
@@ -170,7 +171,7 @@ func GetProjectsInfo(config *Config) ([]*ProjectInfo, error) {
 
 		info := &ProjectInfo{
 			Path:       projectPath,
-			Name:       filepath.Base(projectPath),
+			Name:       config.ProjectName(projectPath),
 			TotalBalls: len(balls),
 		}
 
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DefaultDuplicateThreshold is the title similarity at or above which two
+// balls are reported as likely duplicates.
+const DefaultDuplicateThreshold = 0.75
+
+// createdBallPattern matches the lines juggle prints when a ball is created
+// ("✓ Planned ball added: <id>", "Created ball: <id>").
+var createdBallPattern = regexp.MustCompile(`(?i)(?:planned ball added|created ball):?\s+([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// DuplicateCandidate is a ball that looks like a near-duplicate of another.
+type DuplicateCandidate struct {
+	Ball       *Ball   // The newer ball, proposed for merging away
+	Original   *Ball   // The existing ball it duplicates, which is kept
+	Similarity float64 // Title similarity in [0, 1]
+}
+
+// ExtractCreatedBallIDs returns the IDs of balls reported as created in
+// agent output, in order of first appearance.
+func ExtractCreatedBallIDs(output string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, match := range createdBallPattern.FindAllStringSubmatch(output, -1) {
+		id := strings.TrimRight(match[1], ".")
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// titleWords returns the lowercased alphanumeric words of a title
+func titleWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// TitleSimilarity returns how alike two titles are, from 0 (no words in
+// common) to 1 (the same words, ignoring case, punctuation, and order).
+func TitleSimilarity(a, b string) float64 {
+	wordsA, wordsB := titleWords(a), titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	setA := make(map[string]bool, len(wordsA))
+	for _, w := range wordsA {
+		setA[w] = true
+	}
+	setB := make(map[string]bool, len(wordsB))
+	for _, w := range wordsB {
+		setB[w] = true
+	}
+
+	shared := 0
+	for w := range setA {
+		if setB[w] {
+			shared++
+		}
+	}
+	union := len(setA) + len(setB) - shared
+	return float64(shared) / float64(union)
+}
+
+// FindNearDuplicates cross-checks newly created balls against all balls
+// (including each other) and returns the created balls whose titles are at
+// least threshold similar to another ball in the same project. Each created
+// ball is reported at most once, paired with its closest match; when two
+// created balls match, the one created first is treated as the original.
+func FindNearDuplicates(created, all []*Ball, threshold float64) []DuplicateCandidate {
+	var candidates []DuplicateCandidate
+	reported := make(map[string]bool)
+
+	for _, ball := range created {
+		var best *Ball
+		bestScore := 0.0
+		for _, other := range all {
+			if other.ID == ball.ID || other.WorkingDir != ball.WorkingDir || reported[other.ID] {
+				continue
+			}
+			score := TitleSimilarity(ball.Title, other.Title)
+			if score > bestScore {
+				best, bestScore = other, score
+			}
+		}
+		if best == nil || bestScore < threshold {
+			continue
+		}
+
+		dup, orig := ball, best
+		if isCreatedBall(created, best) && best.StartedAt.After(ball.StartedAt) {
+			dup, orig = best, ball
+		}
+		if reported[dup.ID] {
+			continue
+		}
+		reported[dup.ID] = true
+		candidates = append(candidates, DuplicateCandidate{Ball: dup, Original: orig, Similarity: bestScore})
+	}
+	return candidates
+}
+
+func isCreatedBall(created []*Ball, ball *Ball) bool {
+	for _, b := range created {
+		if b.ID == ball.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeDuplicateBall folds dup into keep and deletes dup. Acceptance criteria,
+// tags, dependencies, and watch globs missing from keep are appended, and
+// other balls that depended on dup are pointed at keep instead. Both balls
+// must belong to this store's project.
+func (s *Store) MergeDuplicateBall(keep, dup *Ball) error {
+	if keep.ID == dup.ID {
+		return fmt.Errorf("cannot merge ball %s into itself", keep.ID)
+	}
+
+	keep.AcceptanceCriteria = appendMissing(keep.AcceptanceCriteria, dup.AcceptanceCriteria)
+	keep.Tags = appendMissing(keep.Tags, dup.Tags)
+	keep.WatchGlobs = appendMissing(keep.WatchGlobs, dup.WatchGlobs)
+	for _, dep := range dup.DependsOn {
+		if dep != keep.ID {
+			keep.DependsOn = appendMissing(keep.DependsOn, []string{dep})
+		}
+	}
+	if keep.Context == "" {
+		keep.Context = dup.Context
+	}
+
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	for _, ball := range balls {
+		if ball.ID == keep.ID || ball.ID == dup.ID || !ball.RemoveDependency(dup.ID) {
+			continue
+		}
+		ball.AddDependency(keep.ID)
+		if err := s.UpdateBall(ball); err != nil {
+			return fmt.Errorf("failed to update ball %s: %w", ball.ID, err)
+		}
+	}
+
+	keep.UpdateActivity()
+	if err := s.UpdateBall(keep); err != nil {
+		return fmt.Errorf("failed to update ball %s: %w", keep.ID, err)
+	}
+	if err := s.DeleteBall(dup.ID); err != nil {
+		return fmt.Errorf("failed to delete ball %s: %w", dup.ID, err)
+	}
+	return nil
+}
+
+// appendMissing appends the values of add not already present in list
+func appendMissing(list, add []string) []string {
+	for _, v := range add {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
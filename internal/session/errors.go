@@ -22,6 +22,9 @@ var (
 
 	// ErrBallLocked is returned when a ball is already locked by another process.
 	ErrBallLocked = errors.New("ball locked")
+
+	// ErrSessionNotFound is returned when a session cannot be found by ID.
+	ErrSessionNotFound = errors.New("session not found")
 )
 
 // BallNotFoundError provides detailed information about a ball lookup failure.
@@ -60,6 +63,24 @@ func NewBallNotFoundPrefixError(prefix string) *BallNotFoundError {
 	return &BallNotFoundError{ID: prefix, IsPrefix: true}
 }
 
+// SessionNotFoundError provides detailed information about a session lookup failure.
+type SessionNotFoundError struct {
+	ID string // The session ID that was not found
+}
+
+func (e *SessionNotFoundError) Error() string {
+	return fmt.Sprintf("session not found: %s", e.ID)
+}
+
+func (e *SessionNotFoundError) Is(target error) bool {
+	return target == ErrSessionNotFound
+}
+
+// NewSessionNotFoundError creates a new SessionNotFoundError.
+func NewSessionNotFoundError(id string) *SessionNotFoundError {
+	return &SessionNotFoundError{ID: id}
+}
+
 // InvalidStateError provides detailed information about an invalid state error.
 type InvalidStateError struct {
 	State    string // The invalid state value
@@ -124,6 +145,13 @@ func (e *SessionLockedError) Error() string {
 	} else {
 		msg = fmt.Sprintf("session %s is already locked by another agent", e.SessionID)
 	}
+	if e.ProcessRunning != nil && !*e.ProcessRunning {
+		name := e.SessionID
+		if name == "_all" {
+			name = "all"
+		}
+		return msg + "\nBreak the stale lock with: juggle locks break " + name
+	}
 	return msg + "\nUse --ignore-lock to bypass (use with caution)"
 }
 
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const escalationsDir = "escalations" // Escalation packages, under .juggle
+
+// Escalation records that a blocked ball was handed to a human owner.
+// Agents skip escalated balls until the escalation is resolved, which
+// happens when the ball leaves the blocked state.
+type Escalation struct {
+	EscalatedAt time.Time `json:"escalated_at"`
+	Owner       string    `json:"owner,omitempty"`     // Who is expected to unblock the ball
+	File        string    `json:"file"`                // Markdown package under .juggle/escalations
+	IssueURL    string    `json:"issue_url,omitempty"` // Issue created for the escalation, if any
+	Notified    bool      `json:"notified,omitempty"`  // escalation_notify_command ran successfully
+}
+
+// Label returns a short description of the escalation, e.g.
+// "to alice on Oct 15 (https://github.com/o/r/issues/7)"
+func (e *Escalation) Label() string {
+	label := ""
+	if e.Owner != "" {
+		label = "to " + e.Owner + " "
+	}
+	label += "on " + e.EscalatedAt.Format("Jan 2")
+	if e.IssueURL != "" {
+		label += " (" + e.IssueURL + ")"
+	}
+	return label
+}
+
+// IsEscalated returns true if the ball is waiting on a human owner
+func (b *Ball) IsEscalated() bool {
+	return b.Escalation != nil
+}
+
+// Escalate marks a blocked ball as escalated
+func (b *Ball) Escalate(e Escalation) error {
+	if b.State != StateBlocked {
+		return fmt.Errorf("only blocked balls can be escalated (ball is %s)", b.State)
+	}
+	if e.EscalatedAt.IsZero() {
+		e.EscalatedAt = time.Now()
+	}
+	b.Escalation = &e
+	b.UpdateActivity()
+	return nil
+}
+
+// ResolveEscalation clears the escalation and returns the ball to pending
+// so agents can pick it up again. A non-empty note (typically the owner's
+// answer) is appended to the ball's context.
+func (b *Ball) ResolveEscalation(note string) error {
+	if !b.IsEscalated() {
+		return fmt.Errorf("ball %s is not escalated", b.ShortID())
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		resolution := "Escalation resolved: " + note
+		if b.Context != "" {
+			resolution = b.Context + "\n\n" + resolution
+		}
+		b.Context = resolution
+	}
+	return b.SetState(StatePending)
+}
+
+// escalationsPath returns the store's escalations directory
+func (s *Store) escalationsPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), escalationsDir)
+}
+
+// SaveEscalation writes a ball's escalation package, replacing any earlier
+// one, and returns the file name to record in the Escalation
+func (s *Store) SaveEscalation(ballID, markdown string) (string, error) {
+	name := ballID + ".md"
+	if err := os.MkdirAll(s.escalationsPath(), 0755); err != nil {
+		return "", fmt.Errorf("failed to create escalations directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.escalationsPath(), name), []byte(markdown), 0644); err != nil {
+		return "", fmt.Errorf("failed to save escalation: %w", err)
+	}
+	return name, nil
+}
+
+// EscalationPath returns the full path of an escalation package
+func (s *Store) EscalationPath(e *Escalation) string {
+	return filepath.Join(s.escalationsPath(), filepath.Base(e.File))
+}
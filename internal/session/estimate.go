@@ -0,0 +1,237 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	estimatesFile = "estimates.jsonl"
+)
+
+// Complexity levels an estimate can assign to a ball
+const (
+	ComplexityLow    = "low"
+	ComplexityMedium = "medium"
+	ComplexityHigh   = "high"
+)
+
+// Estimate is an agent-suggested sizing for a ball, waiting in the review queue
+// until it is accepted (applied to the ball) or rejected.
+type Estimate struct {
+	BallID     string    `json:"ball_id"`
+	Complexity string    `json:"complexity"`           // "low", "medium", "high"
+	ModelSize  ModelSize `json:"model_size,omitempty"` // Suggested model size for the ball
+	Iterations int       `json:"iterations,omitempty"` // Suggested number of agent iterations
+	Rationale  string    `json:"rationale,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AcceptedEstimate is the sizing kept on a ball once its estimate is accepted.
+// The suggested model size goes to the ball's ModelSize instead.
+type AcceptedEstimate struct {
+	Complexity string    `json:"complexity,omitempty"`
+	Iterations int       `json:"iterations,omitempty"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
+
+// Label returns a one-line description of the accepted estimate
+func (e *AcceptedEstimate) Label() string {
+	label := e.Complexity
+	if label == "" {
+		label = "unknown"
+	}
+	if e.Iterations > 0 {
+		label += fmt.Sprintf(" complexity, %d iterations", e.Iterations)
+	} else {
+		label += " complexity"
+	}
+	return label
+}
+
+// ValidateComplexity checks if a complexity string is valid
+func ValidateComplexity(c string) bool {
+	switch c {
+	case ComplexityLow, ComplexityMedium, ComplexityHigh:
+		return true
+	}
+	return false
+}
+
+// EstimateStore manages the estimate review queue for a project
+type EstimateStore struct {
+	projectDir string
+	config     StoreConfig
+}
+
+// NewEstimateStore creates a new estimate store for the given project directory
+func NewEstimateStore(projectDir string) (*EstimateStore, error) {
+	return NewEstimateStoreWithConfig(projectDir, DefaultStoreConfig())
+}
+
+// NewEstimateStoreWithConfig creates a new estimate store with custom configuration
+func NewEstimateStoreWithConfig(projectDir string, config StoreConfig) (*EstimateStore, error) {
+	if projectDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		projectDir = cwd
+	}
+
+	// Resolve to main repo if this is a worktree
+	storageDir, err := ResolveStorageDir(projectDir, config.JuggleDirName)
+	if err != nil {
+		storageDir = projectDir
+	}
+
+	return &EstimateStore{
+		projectDir: storageDir,
+		config:     config,
+	}, nil
+}
+
+// estimatesFilePath returns the path to the estimates file
+func (s *EstimateStore) estimatesFilePath() string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, estimatesFile)
+}
+
+// LoadEstimates loads all pending estimates, ordered by ball ID
+func (s *EstimateStore) LoadEstimates() ([]*Estimate, error) {
+	data, err := os.ReadFile(s.estimatesFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Estimate{}, nil
+		}
+		return nil, fmt.Errorf("failed to read estimates file: %w", err)
+	}
+
+	estimates := make([]*Estimate, 0)
+	for _, line := range splitLines(string(data)) {
+		if len(line) == 0 {
+			continue
+		}
+		var estimate Estimate
+		if err := json.Unmarshal([]byte(line), &estimate); err != nil {
+			// Skip malformed records
+			continue
+		}
+		estimates = append(estimates, &estimate)
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].BallID < estimates[j].BallID
+	})
+
+	return estimates, nil
+}
+
+// GetEstimate returns the pending estimate for a ball, or nil if there is none
+func (s *EstimateStore) GetEstimate(ballID string) (*Estimate, error) {
+	estimates, err := s.LoadEstimates()
+	if err != nil {
+		return nil, err
+	}
+	for _, estimate := range estimates {
+		if estimate.BallID == ballID {
+			return estimate, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddEstimates adds estimates to the review queue, replacing any existing
+// estimate for the same ball
+func (s *EstimateStore) AddEstimates(newEstimates []*Estimate) error {
+	estimates, err := s.LoadEstimates()
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*Estimate, len(estimates)+len(newEstimates))
+	for _, estimate := range estimates {
+		byID[estimate.BallID] = estimate
+	}
+	for _, estimate := range newEstimates {
+		byID[estimate.BallID] = estimate
+	}
+
+	merged := make([]*Estimate, 0, len(byID))
+	for _, estimate := range byID {
+		merged = append(merged, estimate)
+	}
+	return s.writeEstimates(merged)
+}
+
+// RemoveEstimate removes a ball's estimate from the review queue
+func (s *EstimateStore) RemoveEstimate(ballID string) error {
+	estimates, err := s.LoadEstimates()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*Estimate, 0, len(estimates))
+	found := false
+	for _, estimate := range estimates {
+		if estimate.BallID == ballID {
+			found = true
+			continue
+		}
+		remaining = append(remaining, estimate)
+	}
+	if !found {
+		return fmt.Errorf("no pending estimate for ball %s", ballID)
+	}
+	return s.writeEstimates(remaining)
+}
+
+// writeEstimates atomically rewrites the estimates file
+func (s *EstimateStore) writeEstimates(estimates []*Estimate) error {
+	juggleDir := filepath.Join(s.projectDir, s.config.JuggleDirName)
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		return fmt.Errorf("failed to create juggle directory: %w", err)
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].BallID < estimates[j].BallID
+	})
+
+	var data []byte
+	for _, estimate := range estimates {
+		line, err := json.Marshal(estimate)
+		if err != nil {
+			return fmt.Errorf("failed to marshal estimate: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	tempPath := s.estimatesFilePath() + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write estimates file: %w", err)
+	}
+	if err := os.Rename(tempPath, s.estimatesFilePath()); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace estimates file: %w", err)
+	}
+	return nil
+}
+
+// ApplyEstimate applies an estimate's suggestions to a ball: the model size
+// is set on the ball, and the complexity and iterations are kept as its
+// accepted estimate
+func ApplyEstimate(ball *Ball, estimate *Estimate) {
+	if estimate.ModelSize != "" {
+		ball.ModelSize = estimate.ModelSize
+	}
+	ball.Estimate = &AcceptedEstimate{
+		Complexity: estimate.Complexity,
+		Iterations: estimate.Iterations,
+		AcceptedAt: time.Now(),
+	}
+	ball.UpdateActivity()
+}
@@ -0,0 +1,230 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fsckBackupSuffix names the copy of balls.jsonl kept by a repair
+const fsckBackupSuffix = ".fsck.bak"
+
+// Kinds of problems Fsck finds
+const (
+	FsckCorruptLine = "corrupt_line"         // A line that isn't a valid ball, e.g. truncated by a crash
+	FsckDuplicateID = "duplicate_id"         // A ball on more than one line
+	FsckOrphanedTag = "orphaned_session_tag" // A tag naming a session that no longer exists
+)
+
+// FsckIssue is one problem found by Fsck
+type FsckIssue struct {
+	Kind   string `json:"kind"`
+	Line   int    `json:"line,omitempty"` // Line in balls.jsonl, for corrupt lines and duplicates
+	BallID string `json:"ball_id,omitempty"`
+	Tag    string `json:"tag,omitempty"` // The orphaned session tag
+	Detail string `json:"detail"`
+}
+
+// FsckReport is what Fsck found, and did if asked to repair
+type FsckReport struct {
+	Lines    int         `json:"lines"` // Non-blank lines checked
+	Balls    int         `json:"balls"`
+	Issues   []FsckIssue `json:"issues"`
+	Repaired bool        `json:"repaired"`
+	Backup   string      `json:"backup,omitempty"` // balls.jsonl as it was before the repair
+}
+
+// Fsck checks balls.jsonl for corrupt lines and balls on more than one line,
+// and the balls for tags naming sessions that have been deleted. A tag
+// counts as a former session's if agent history has runs on it, or its
+// session directory is left without a session file.
+//
+// With repair, corrupt lines are dropped, the last line of a duplicated
+// ball wins (as with compaction), and orphaned tags are removed. The file
+// is copied to balls.jsonl.fsck.bak first. With the bolt backend, which
+// can't hold corrupt or duplicate records, only tags are checked.
+func (s *Store) Fsck(repair bool) (*FsckReport, error) {
+	report := &FsckReport{Issues: []FsckIssue{}}
+	former, err := s.formerSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.db != nil {
+		balls, err := s.db.load()
+		if err != nil {
+			return nil, err
+		}
+		report.Balls = len(balls)
+		var changed []*Ball
+		for _, ball := range balls {
+			if fsckOrphanedTags(report, ball, 0, former) {
+				changed = append(changed, ball)
+			}
+		}
+		if !repair || len(changed) == 0 {
+			return report, nil
+		}
+		for _, ball := range changed {
+			if err := s.UpdateBall(ball); err != nil {
+				return nil, err
+			}
+		}
+		report.Repaired = true
+		return report, nil
+	}
+
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(s.ballsPath)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read balls file: %w", err)
+	}
+
+	// Balls in file order, keeping the last line of each and where it was
+	type fsckLine struct {
+		ball *Ball
+		line int
+	}
+	var lines []*fsckLine
+	byID := make(map[string]*fsckLine)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		report.Lines++
+
+		ball, err := parseCompactLine(text)
+		if err != nil {
+			report.Issues = append(report.Issues, FsckIssue{Kind: FsckCorruptLine, Line: lineNum, Detail: err.Error()})
+			continue
+		}
+		if prev, ok := byID[ball.ID]; ok {
+			report.Issues = append(report.Issues, FsckIssue{
+				Kind:   FsckDuplicateID,
+				Line:   lineNum,
+				BallID: ball.ID,
+				Detail: fmt.Sprintf("also on line %d; the later line wins", prev.line),
+			})
+			prev.ball = nil
+		}
+		entry := &fsckLine{ball: ball, line: lineNum}
+		byID[ball.ID] = entry
+		lines = append(lines, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading balls file: %w", err)
+	}
+	report.Balls = len(byID)
+
+	for _, entry := range lines {
+		if entry.ball != nil {
+			fsckOrphanedTags(report, entry.ball, entry.line, former)
+		}
+	}
+	if !repair || len(report.Issues) == 0 {
+		return report, nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range lines {
+		if entry.ball == nil {
+			continue
+		}
+		line, err := json.Marshal(entry.ball)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal ball %s: %w", entry.ball.ID, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	report.Backup = s.ballsPath + fsckBackupSuffix
+	if err := writeFileSynced(report.Backup, data); err != nil {
+		return nil, fmt.Errorf("failed to back up balls file: %w", err)
+	}
+	if err := writeFileSynced(s.ballsPath, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	report.Repaired = true
+	return report, nil
+}
+
+// fsckOrphanedTags reports the ball's tags naming former sessions, and
+// removes them from the ball. It returns whether there were any.
+func fsckOrphanedTags(report *FsckReport, ball *Ball, line int, former map[string]bool) bool {
+	found := false
+	for _, tag := range append([]string(nil), ball.Tags...) {
+		if !former[tag] {
+			continue
+		}
+		report.Issues = append(report.Issues, FsckIssue{
+			Kind:   FsckOrphanedTag,
+			Line:   line,
+			BallID: ball.ID,
+			Tag:    tag,
+			Detail: fmt.Sprintf("session %q no longer exists", tag),
+		})
+		ball.RemoveTag(tag)
+		found = true
+	}
+	return found
+}
+
+// formerSessions returns the IDs of sessions that existed once but don't
+// now: those agent history has runs on, and session directories left
+// without a session file
+func (s *Store) formerSessions() (map[string]bool, error) {
+	sessions, err := NewSessionStoreWithConfig(s.projectDir, s.config)
+	if err != nil {
+		return nil, err
+	}
+	former := make(map[string]bool)
+
+	history, err := NewAgentHistoryStoreWithConfig(s.projectDir, s.config)
+	if err != nil {
+		return nil, err
+	}
+	records, err := history.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		former[record.SessionID] = true
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			former[entry.Name()] = true
+		}
+	}
+
+	for id := range former {
+		_, err := sessions.LoadSession(id)
+		var notFound *SessionNotFoundError
+		if id == "" || id == "_all" || !errors.As(err, &notFound) {
+			delete(former, id)
+		}
+	}
+	return former, nil
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_Fsck(t *testing.T) {
+	store := newUndoTestStore(t)
+	sessions, err := NewSessionStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if _, err := sessions.CreateSession("live", "Still here"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	// A deleted session the agent ran on
+	history, err := NewAgentHistoryStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create history store: %v", err)
+	}
+	if err := history.AppendRecord(NewAgentRunRecord("gone", store.ProjectDir(), time.Now())); err != nil {
+		t.Fatalf("Failed to append record: %v", err)
+	}
+
+	writeCompactTestBalls(t, store,
+		`{"id":"p-1","title":"First","state":"pending","priority":"low","tags":["live","gone","docs"]}`,
+		`{"id":"p-2","title":"Second","state":"pending","priority":"low"}`,
+		`{"id":"p-2","title":"Second, updated","state":"in_progress","priority":"low"}`,
+		`{"id":"p-3","title":"Cut sh`,
+	)
+	before := readCompactTestBalls(t, store)
+
+	report, err := store.Fsck(false)
+	if err != nil {
+		t.Fatalf("Fsck failed: %v", err)
+	}
+	if report.Lines != 4 || report.Balls != 2 || report.Repaired {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	kinds := make(map[string]FsckIssue)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind] = issue
+	}
+	if len(report.Issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %+v", report.Issues)
+	}
+	if issue := kinds[FsckCorruptLine]; issue.Line != 4 {
+		t.Errorf("Expected the truncated line reported, got %+v", issue)
+	}
+	if issue := kinds[FsckDuplicateID]; issue.Line != 3 || issue.BallID != "p-2" {
+		t.Errorf("Expected the duplicate reported, got %+v", issue)
+	}
+	if issue := kinds[FsckOrphanedTag]; issue.BallID != "p-1" || issue.Tag != "gone" {
+		t.Errorf("Expected only the deleted session's tag reported, got %+v", issue)
+	}
+	if readCompactTestBalls(t, store) != before {
+		t.Error("Expected the file untouched without repair")
+	}
+
+	report, err = store.Fsck(true)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+	if !report.Repaired {
+		t.Error("Expected the store repaired")
+	}
+	if backup, err := os.ReadFile(report.Backup); err != nil || string(backup) != before {
+		t.Errorf("Expected the old file backed up, got %v", err)
+	}
+	balls, err := store.LoadBalls()
+	if err != nil {
+		t.Fatalf("Failed to load balls: %v", err)
+	}
+	if len(balls) != 2 || balls[1].Title != "Second, updated" {
+		t.Fatalf("Expected the corrupt line dropped and the last duplicate kept, got %+v", balls)
+	}
+	if strings.Join(balls[0].Tags, ",") != "live,docs" {
+		t.Errorf("Expected the orphaned tag removed, got %v", balls[0].Tags)
+	}
+
+	report, err = store.Fsck(false)
+	if err != nil || len(report.Issues) != 0 {
+		t.Errorf("Expected no issues after repair, got %+v (%v)", report, err)
+	}
+}
+
+func TestStore_FsckSessionDirWithoutFile(t *testing.T) {
+	store := newUndoTestStore(t)
+	// Left behind with only its progress log
+	dir := filepath.Join(store.ProjectDir(), projectStorePath, sessionsDir, "half")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create session dir: %v", err)
+	}
+	writeCompactTestBalls(t, store, `{"id":"p-1","title":"Tagged","state":"pending","priority":"low","tags":["half"]}`)
+
+	report, err := store.Fsck(false)
+	if err != nil {
+		t.Fatalf("Fsck failed: %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != FsckOrphanedTag || report.Issues[0].Tag != "half" {
+		t.Errorf("Expected the tag of the broken session reported, got %+v", report.Issues)
+	}
+}
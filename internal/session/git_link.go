@@ -0,0 +1,150 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ohare93/juggle/internal/git"
+)
+
+// GitLink ties a ball to its work in git: the branch bound with `juggle
+// link`, and the commits whose messages reference the ball's ID
+type GitLink struct {
+	Branch  string         `json:"branch,omitempty"`
+	Commits []LinkedCommit `json:"commits,omitempty"` // Newest first
+}
+
+// LinkedCommit is a commit whose message references a ball
+type LinkedCommit struct {
+	Hash    string    `json:"hash"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+}
+
+// Label returns a one-line summary, e.g. "feature/auth, 3 commits"
+func (l *GitLink) Label() string {
+	branch := l.Branch
+	if branch == "" {
+		branch = "no branch"
+	}
+	switch len(l.Commits) {
+	case 0:
+		return branch + ", no commits"
+	case 1:
+		return branch + ", 1 commit"
+	}
+	return fmt.Sprintf("%s, %d commits", branch, len(l.Commits))
+}
+
+// LinkBranch binds the ball to a git branch
+func (b *Ball) LinkBranch(branch string) {
+	if b.Git == nil {
+		b.Git = &GitLink{}
+	}
+	b.Git.Branch = branch
+	b.UpdateActivity()
+}
+
+// UnlinkBranch removes the ball's branch, keeping its commits
+func (b *Ball) UnlinkBranch() {
+	if b.Git == nil {
+		return
+	}
+	b.Git.Branch = ""
+	if len(b.Git.Commits) == 0 {
+		b.Git = nil
+	}
+	b.UpdateActivity()
+}
+
+// setCommits records the ball's commits, returning true if they changed
+func (b *Ball) setCommits(commits []git.Commit) bool {
+	var current []LinkedCommit
+	if b.Git != nil {
+		current = b.Git.Commits
+	}
+	if len(current) == len(commits) {
+		same := true
+		for i := range commits {
+			if current[i].Hash != commits[i].ShortHash() {
+				same = false
+				break
+			}
+		}
+		if same {
+			return false
+		}
+	}
+
+	if b.Git == nil {
+		b.Git = &GitLink{}
+	}
+	b.Git.Commits = nil
+	for _, c := range commits {
+		b.Git.Commits = append(b.Git.Commits, LinkedCommit{Hash: c.ShortHash(), Subject: c.Subject, Date: c.Date})
+	}
+	if b.Git.Branch == "" && len(b.Git.Commits) == 0 {
+		b.Git = nil
+	}
+	return true
+}
+
+// SyncGitCommits records on each ball the commits, on any branch, whose
+// messages reference its ID (e.g. "juggle: proj-42"). Returns the balls
+// whose commits changed. The commits come from git, so the change isn't
+// recorded for undo. Does nothing outside a git repository.
+func (s *Store) SyncGitCommits() ([]*Ball, error) {
+	if !git.IsRepo(s.projectDir) {
+		return nil, nil
+	}
+
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+	if len(balls) == 0 {
+		return nil, nil
+	}
+
+	// Commits can't reference a ball before it existed; allow for clock skew
+	since := balls[0].StartedAt
+	ids := make([]string, 0, len(balls))
+	for _, ball := range balls {
+		ids = append(ids, ball.ID)
+		if ball.StartedAt.Before(since) {
+			since = ball.StartedAt
+		}
+	}
+	if !since.IsZero() {
+		since = since.Add(-24 * time.Hour)
+	}
+	commits, err := git.Log(s.projectDir, since)
+	if err != nil {
+		return nil, err
+	}
+	refs := git.FindReferences(commits, ids)
+
+	// Reload under the lock, so changes made while git ran aren't lost
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if balls, err = s.LoadBalls(); err != nil {
+		return nil, err
+	}
+
+	var changed []*Ball
+	for _, ball := range balls {
+		if ball.setCommits(refs[ball.ID]) {
+			changed = append(changed, ball)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	if err := s.writeBallsUnlocked(balls); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
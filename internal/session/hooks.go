@@ -0,0 +1,190 @@
+package session
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HookEvent names an event that project hooks can run on
+type HookEvent string
+
+const (
+	HookBallComplete  HookEvent = "on_ball_complete"  // A ball was marked complete
+	HookBallBlocked   HookEvent = "on_ball_blocked"   // A ball was marked blocked
+	HookAgentBlocked  HookEvent = "on_agent_blocked"  // An agent run ended blocked
+	HookAgentFinished HookEvent = "on_agent_finished" // An agent run ended, however it ended
+)
+
+// HookEvents lists every event hooks can run on
+var HookEvents = []HookEvent{HookBallComplete, HookBallBlocked, HookAgentBlocked, HookAgentFinished}
+
+// ValidateHookEvent returns an error if event isn't a known hook event
+func ValidateHookEvent(event HookEvent) error {
+	for _, known := range HookEvents {
+		if event == known {
+			return nil
+		}
+	}
+	names := make([]string, len(HookEvents))
+	for i, known := range HookEvents {
+		names[i] = string(known)
+	}
+	return fmt.Errorf("unknown hook event %q (must be one of %s)", event, strings.Join(names, ", "))
+}
+
+// Hook is one action run on an event: a shell command that gets the event
+// payload as JSON on stdin, or a URL the payload is POSTed to
+type Hook struct {
+	Command string `json:"command,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// Validate checks that the hook has exactly one of a command or an http(s) URL
+func (h Hook) Validate() error {
+	switch {
+	case h.Command == "" && h.URL == "":
+		return fmt.Errorf("hook needs a command or a URL")
+	case h.Command != "" && h.URL != "":
+		return fmt.Errorf("hook has both a command and a URL; add them as separate hooks")
+	case h.URL != "":
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid hook URL %q (must be http or https)", h.URL)
+		}
+	}
+	return nil
+}
+
+// String describes the hook for listings
+func (h Hook) String() string {
+	if h.URL != "" {
+		return "POST " + h.URL
+	}
+	return "run: " + h.Command
+}
+
+// HookPayload is the JSON sent to hooks
+type HookPayload struct {
+	Event   HookEvent       `json:"event"`
+	Text    string          `json:"text"` // One-line summary; Slack incoming webhooks post it as the message
+	Project string          `json:"project"`
+	Time    time.Time       `json:"time"`
+	Ball    *Ball           `json:"ball,omitempty"` // Set for ball events
+	Run     *AgentRunRecord `json:"run,omitempty"`  // Set for agent events
+}
+
+// NewBallHookPayload builds the payload for a ball event
+func NewBallHookPayload(event HookEvent, projectDir string, ball *Ball) *HookPayload {
+	var text string
+	switch event {
+	case HookBallBlocked:
+		text = fmt.Sprintf("juggle: %s blocked: %s", ball.ShortID(), ball.Title)
+		if ball.BlockedReason != "" {
+			text += " (" + ball.BlockedReason + ")"
+		}
+	default:
+		text = fmt.Sprintf("juggle: %s complete: %s", ball.ShortID(), ball.Title)
+	}
+	return &HookPayload{Event: event, Text: text, Project: projectDir, Time: time.Now(), Ball: ball}
+}
+
+// NewAgentHookPayload builds the payload for an agent event
+func NewAgentHookPayload(event HookEvent, run *AgentRunRecord) *HookPayload {
+	text := fmt.Sprintf("juggle: agent run on %s ended %s after %d iteration(s), %d/%d balls complete",
+		run.SessionID, run.Result, run.Iterations, run.BallsComplete, run.BallsTotal)
+	if run.BlockedReason != "" {
+		text += " (blocked: " + run.BlockedReason + ")"
+	}
+	return &HookPayload{Event: event, Text: text, Project: run.ProjectDir, Time: time.Now(), Run: run}
+}
+
+// BallHookEvent returns the event a ball's state change fires, if any
+func BallHookEvent(before, after *Ball) (HookEvent, bool) {
+	if before != nil && before.State == after.State {
+		return "", false
+	}
+	switch after.State {
+	case StateComplete:
+		return HookBallComplete, true
+	case StateBlocked:
+		return HookBallBlocked, true
+	}
+	return "", false
+}
+
+// stateChangeHandler is called after a ball's state changes in any store
+var stateChangeHandler func(projectDir string, before, after *Ball)
+
+// SetStateChangeHandler sets the function called after Store.UpdateBall
+// changes a ball's state (nil removes it). It's called on the store's write
+// path, so it must only queue work: the CLI queues the ball's hooks with it.
+func SetStateChangeHandler(fn func(projectDir string, before, after *Ball)) {
+	stateChangeHandler = fn
+}
+
+// GetProjectHooks returns the hooks configured for event. A project without
+// a config file has none (and doesn't get one).
+func GetProjectHooks(projectDir string, event HookEvent) ([]Hook, error) {
+	if _, err := os.Stat(filepath.Join(projectDir, projectStorePath, "config.json")); os.IsNotExist(err) {
+		return nil, nil
+	}
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Hooks[event], nil
+}
+
+// GetAllProjectHooks returns every configured hook, by event
+func GetAllProjectHooks(projectDir string) (map[HookEvent][]Hook, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return config.Hooks, nil
+}
+
+// AddProjectHook adds a hook for event in project config
+func AddProjectHook(projectDir string, event HookEvent, hook Hook) error {
+	if err := ValidateHookEvent(event); err != nil {
+		return err
+	}
+	if err := hook.Validate(); err != nil {
+		return err
+	}
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if config.Hooks == nil {
+		config.Hooks = make(map[HookEvent][]Hook)
+	}
+	config.Hooks[event] = append(config.Hooks[event], hook)
+	return SaveProjectConfig(projectDir, config)
+}
+
+// ClearProjectHooks removes the hooks for event, or every hook if event is ""
+func ClearProjectHooks(projectDir string, event HookEvent) error {
+	if event != "" {
+		if err := ValidateHookEvent(event); err != nil {
+			return err
+		}
+	}
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if event == "" {
+		config.Hooks = nil
+	} else {
+		delete(config.Hooks, event)
+		if len(config.Hooks) == 0 {
+			config.Hooks = nil
+		}
+	}
+	return SaveProjectConfig(projectDir, config)
+}
@@ -0,0 +1,37 @@
+package session
+
+import "testing"
+
+func TestHookValidate(t *testing.T) {
+	valid := []Hook{{Command: "notify-send done"}, {URL: "https://hooks.slack.com/services/T/B/X"}}
+	for _, hook := range valid {
+		if err := hook.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", hook, err)
+		}
+	}
+	invalid := []Hook{{}, {Command: "true", URL: "https://example.com"}, {URL: "example.com/hook"}, {URL: "file:///tmp/x"}}
+	for _, hook := range invalid {
+		if err := hook.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", hook)
+		}
+	}
+}
+
+func TestBallHookEvent(t *testing.T) {
+	tests := []struct {
+		before, after BallState
+		want          HookEvent
+		ok            bool
+	}{
+		{StateInProgress, StateComplete, HookBallComplete, true},
+		{StatePending, StateBlocked, HookBallBlocked, true},
+		{StateBlocked, StateBlocked, "", false},
+		{StateBlocked, StateInProgress, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := BallHookEvent(&Ball{State: tt.before}, &Ball{State: tt.after})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("BallHookEvent(%s → %s) = %q, %v; want %q, %v", tt.before, tt.after, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// HTTPAgentConfig configures the "http" agent provider, which calls an LLM
+// HTTP API directly instead of running an agent CLI. Header values may
+// reference environment variables ($VAR) so API keys stay out of the file.
+type HTTPAgentConfig struct {
+	Endpoint  string            `json:"endpoint,omitempty"`   // API URL (default: the format's public API)
+	Format    string            `json:"format,omitempty"`     // "anthropic" (default) or "openai"
+	Model     string            `json:"model,omitempty"`      // Model used when the run doesn't pick one
+	Headers   map[string]string `json:"headers,omitempty"`    // Extra request headers
+	MaxTokens int               `json:"max_tokens,omitempty"` // Maximum tokens per response
+}
+
+// ValidateHTTPAgentFormat checks if an HTTP agent API format is valid
+func ValidateHTTPAgentFormat(format string) bool {
+	return format == "" || format == "anthropic" || format == "openai"
+}
+
+// SetHTTPAgent validates and stores the HTTP agent settings
+func (c *Config) SetHTTPAgent(agent *HTTPAgentConfig) error {
+	if agent.Endpoint != "" {
+		u, err := url.Parse(agent.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q (must be an http or https URL)", agent.Endpoint)
+		}
+	}
+	if !ValidateHTTPAgentFormat(agent.Format) {
+		return fmt.Errorf("invalid format %q (must be anthropic or openai)", agent.Format)
+	}
+	if agent.MaxTokens < 0 {
+		return fmt.Errorf("max tokens cannot be negative")
+	}
+	for key := range agent.Headers {
+		if strings.TrimSpace(key) == "" || strings.ContainsAny(key, ": \t") {
+			return fmt.Errorf("invalid header name %q", key)
+		}
+	}
+	c.HTTPAgent = agent
+	return nil
+}
+
+// GetHTTPAgent returns the HTTP agent settings (never nil)
+func (c *Config) GetHTTPAgent() *HTTPAgentConfig {
+	if c.HTTPAgent == nil {
+		return &HTTPAgentConfig{}
+	}
+	return c.HTTPAgent
+}
+
+// GetGlobalHTTPAgentWithOptions returns the HTTP agent settings from global config
+func GetGlobalHTTPAgentWithOptions(opts ConfigOptions) (*HTTPAgentConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &HTTPAgentConfig{}, err
+	}
+	return config.GetHTTPAgent(), nil
+}
+
+// UpdateGlobalHTTPAgentWithOptions applies edit to the HTTP agent settings in global config
+func UpdateGlobalHTTPAgentWithOptions(opts ConfigOptions, edit func(agent *HTTPAgentConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	agent := *config.GetHTTPAgent()
+	if agent.Headers != nil {
+		headers := make(map[string]string, len(agent.Headers))
+		for k, v := range agent.Headers {
+			headers[k] = v
+		}
+		agent.Headers = headers
+	}
+	edit(&agent)
+	if err := config.SetHTTPAgent(&agent); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalHTTPAgentWithOptions removes the HTTP agent settings from global config
+func ClearGlobalHTTPAgentWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.HTTPAgent = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,109 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const humanSignalFile = "human_signal.json"
+
+// LoopSignal is a decision about an agent loop: the same COMPLETE, BLOCKED
+// and CONTINUE an agent can signal
+type LoopSignal string
+
+const (
+	LoopSignalComplete LoopSignal = "complete"
+	LoopSignalBlocked  LoopSignal = "blocked"
+	LoopSignalContinue LoopSignal = "continue"
+)
+
+// ParseLoopSignal parses a signal name, ignoring case
+func ParseLoopSignal(s string) (LoopSignal, error) {
+	switch signal := LoopSignal(strings.ToLower(strings.TrimSpace(s))); signal {
+	case LoopSignalComplete, LoopSignalBlocked, LoopSignalContinue:
+		return signal, nil
+	}
+	return "", fmt.Errorf("invalid signal %q (must be complete, blocked or continue)", s)
+}
+
+// HumanSignal is a loop decision a person sent to a running agent. The
+// loop takes it at its next checkpoint, after the current iteration, and
+// it overrides whatever the agent signaled.
+type HumanSignal struct {
+	SessionID string     `json:"session_id"`
+	Signal    LoopSignal `json:"signal"`
+	Reason    string     `json:"reason,omitempty"` // Why the run is blocked (blocked only)
+	SentAt    time.Time  `json:"sent_at"`
+	Source    string     `json:"source,omitempty"` // Where it was sent from ("cli" or "tui")
+}
+
+// humanSignalFilePath returns the path to a session's pending human signal
+func (s *SessionStore) humanSignalFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), humanSignalFile)
+}
+
+// SendHumanSignal leaves a signal for the session's agent loop, replacing
+// any signal it hasn't taken yet
+func (s *SessionStore) SendHumanSignal(h *HumanSignal) error {
+	if err := os.MkdirAll(s.sessionPath(h.SessionID), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if h.SentAt.IsZero() {
+		h.SentAt = time.Now()
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal signal: %w", err)
+	}
+	// Write then rename, so the loop never reads half a signal
+	path := s.humanSignalFilePath(h.SessionID)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write signal: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write signal: %w", err)
+	}
+	return nil
+}
+
+// LoadHumanSignal returns the session's pending human signal, or nil if
+// there is none
+func (s *SessionStore) LoadHumanSignal(id string) (*HumanSignal, error) {
+	data, err := os.ReadFile(s.humanSignalFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signal: %w", err)
+	}
+	var h HumanSignal
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("failed to parse signal: %w", err)
+	}
+	return &h, nil
+}
+
+// TakeHumanSignal returns the session's pending human signal and removes
+// it, or returns nil if there is none
+func (s *SessionStore) TakeHumanSignal(id string) (*HumanSignal, error) {
+	h, err := s.LoadHumanSignal(id)
+	if err != nil || h == nil {
+		return h, err
+	}
+	if err := s.ClearHumanSignal(id); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// ClearHumanSignal removes the session's pending human signal
+func (s *SessionStore) ClearHumanSignal(id string) error {
+	if err := os.Remove(s.humanSignalFilePath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear signal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Icon set names
+const (
+	IconSetUnicode = "unicode" // Default glyphs
+	IconSetASCII   = "ascii"   // ASCII-only fallback for terminals/fonts that render Unicode as tofu
+)
+
+// Icons holds the glyphs used to display ball states, priorities, and agent status.
+// Every icon in the ASCII set is a single printable ASCII character (or short
+// ASCII string) so column alignment doesn't depend on font support.
+type Icons struct {
+	StatePending    string
+	StateInProgress string
+	StateComplete   string
+	StateBlocked    string
+	StateResearched string
+	StateUnknown    string
+
+	PriorityLow    string
+	PriorityMedium string
+	PriorityHigh   string
+	PriorityUrgent string
+
+	AgentRunning   string // Marks the session an agent is running on
+	AgentComplete  string // Agent run results (history view)
+	AgentBlocked   string
+	AgentTimeout   string
+	AgentMaxIter   string
+	AgentRateLimit string
+	AgentError     string // Also used for cancelled runs
+
+	Output      string // Ball has research output
+	Dependency  string // Ball has dependencies
+	CodeChanged string // A file matching the ball's watch globs changed
+	Focus       string // Ball is flagged for the next agent run to work on first
+	AllSessions string // "All" pseudo-session
+	Untagged    string // "Untagged" pseudo-session
+	Dashboard   string // "Dashboard" pseudo-session
+	Paused      string // Paused session
+	Archived    string // Archived session
+	Goal        string // Session goal progress
+	Checked     string // Selected checkbox
+}
+
+// iconKeys maps config override keys to icon fields
+var iconKeys = map[string]func(*Icons) *string{
+	"state.pending":     func(i *Icons) *string { return &i.StatePending },
+	"state.in_progress": func(i *Icons) *string { return &i.StateInProgress },
+	"state.complete":    func(i *Icons) *string { return &i.StateComplete },
+	"state.blocked":     func(i *Icons) *string { return &i.StateBlocked },
+	"state.researched":  func(i *Icons) *string { return &i.StateResearched },
+	"state.unknown":     func(i *Icons) *string { return &i.StateUnknown },
+	"priority.low":      func(i *Icons) *string { return &i.PriorityLow },
+	"priority.medium":   func(i *Icons) *string { return &i.PriorityMedium },
+	"priority.high":     func(i *Icons) *string { return &i.PriorityHigh },
+	"priority.urgent":   func(i *Icons) *string { return &i.PriorityUrgent },
+	"agent.running":     func(i *Icons) *string { return &i.AgentRunning },
+	"agent.complete":    func(i *Icons) *string { return &i.AgentComplete },
+	"agent.blocked":     func(i *Icons) *string { return &i.AgentBlocked },
+	"agent.timeout":     func(i *Icons) *string { return &i.AgentTimeout },
+	"agent.max_iter":    func(i *Icons) *string { return &i.AgentMaxIter },
+	"agent.rate_limit":  func(i *Icons) *string { return &i.AgentRateLimit },
+	"agent.error":       func(i *Icons) *string { return &i.AgentError },
+	"output":            func(i *Icons) *string { return &i.Output },
+	"dependency":        func(i *Icons) *string { return &i.Dependency },
+	"code_changed":      func(i *Icons) *string { return &i.CodeChanged },
+	"focus":             func(i *Icons) *string { return &i.Focus },
+	"session.all":       func(i *Icons) *string { return &i.AllSessions },
+	"session.untagged":  func(i *Icons) *string { return &i.Untagged },
+	"session.dashboard": func(i *Icons) *string { return &i.Dashboard },
+	"session.paused":    func(i *Icons) *string { return &i.Paused },
+	"session.archived":  func(i *Icons) *string { return &i.Archived },
+	"session.goal":      func(i *Icons) *string { return &i.Goal },
+	"checked":           func(i *Icons) *string { return &i.Checked },
+}
+
+// UnicodeIcons returns the default Unicode icon set
+func UnicodeIcons() Icons {
+	return Icons{
+		StatePending:    "○",
+		StateInProgress: "●",
+		StateComplete:   "✓",
+		StateBlocked:    "✗",
+		StateResearched: "?",
+		StateUnknown:    "?",
+		PriorityLow:     "l",
+		PriorityMedium:  "m",
+		PriorityHigh:    "h",
+		PriorityUrgent:  "u",
+		AgentRunning:    "▶",
+		AgentComplete:   "✓",
+		AgentBlocked:    "⊘",
+		AgentTimeout:    "⏱",
+		AgentMaxIter:    "⟳",
+		AgentRateLimit:  "⚠",
+		AgentError:      "✗",
+		Output:          "📋",
+		Dependency:      "→",
+		CodeChanged:     "Δ",
+		Focus:           "◎",
+		AllSessions:     "★",
+		Untagged:        "○",
+		Dashboard:       "▦",
+		Paused:          "⏸",
+		Archived:        "▣",
+		Goal:            "⚑",
+		Checked:         "✓",
+	}
+}
+
+// ASCIIIcons returns the ASCII-only fallback icon set
+func ASCIIIcons() Icons {
+	return Icons{
+		StatePending:    "o",
+		StateInProgress: "*",
+		StateComplete:   "+",
+		StateBlocked:    "x",
+		StateResearched: "?",
+		StateUnknown:    "?",
+		PriorityLow:     "l",
+		PriorityMedium:  "m",
+		PriorityHigh:    "h",
+		PriorityUrgent:  "u",
+		AgentRunning:    ">",
+		AgentComplete:   "+",
+		AgentBlocked:    "x",
+		AgentTimeout:    "T",
+		AgentMaxIter:    "~",
+		AgentRateLimit:  "!",
+		AgentError:      "x",
+		Output:          "out",
+		Dependency:      "->",
+		CodeChanged:     "chg",
+		Focus:           "foc",
+		AllSessions:     "*",
+		Untagged:        "o",
+		Dashboard:       "#",
+		Paused:          "=",
+		Archived:        "arc",
+		Goal:            "goal",
+		Checked:         "x",
+	}
+}
+
+// ValidateIconSet checks if an icon set name is valid
+func ValidateIconSet(name string) bool {
+	return name == IconSetUnicode || name == IconSetASCII
+}
+
+// IconKeys returns the valid icon override keys, sorted
+func IconKeys() []string {
+	keys := make([]string, 0, len(iconKeys))
+	for key := range iconKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateIconKey checks if an icon override key is valid
+func ValidateIconKey(key string) error {
+	if _, ok := iconKeys[key]; !ok {
+		return fmt.Errorf("unknown icon %q (valid: %s)", key, strings.Join(IconKeys(), ", "))
+	}
+	return nil
+}
+
+// ResolveIcons returns the icon set named in config with any overrides applied.
+// A nil config or unknown set name falls back to the Unicode set.
+func ResolveIcons(config *Config) Icons {
+	icons := UnicodeIcons()
+	if config == nil {
+		return icons
+	}
+	if config.IconSet == IconSetASCII {
+		icons = ASCIIIcons()
+	}
+	for key, glyph := range config.IconOverrides {
+		if field, ok := iconKeys[key]; ok && glyph != "" {
+			*field(&icons) = glyph
+		}
+	}
+	return icons
+}
+
+// Get returns the icon for an override key (e.g., "state.pending")
+func (i Icons) Get(key string) string {
+	if field, ok := iconKeys[key]; ok {
+		return *field(&i)
+	}
+	return ""
+}
+
+// State returns the icon for a ball state
+func (i Icons) State(state BallState) string {
+	switch state {
+	case StatePending:
+		return i.StatePending
+	case StateInProgress:
+		return i.StateInProgress
+	case StateComplete:
+		return i.StateComplete
+	case StateBlocked:
+		return i.StateBlocked
+	case StateResearched:
+		return i.StateResearched
+	default:
+		return i.StateUnknown
+	}
+}
+
+// Priority returns the icon for a ball priority
+func (i Icons) Priority(priority Priority) string {
+	switch priority {
+	case PriorityLow:
+		return i.PriorityLow
+	case PriorityMedium:
+		return i.PriorityMedium
+	case PriorityHigh:
+		return i.PriorityHigh
+	case PriorityUrgent:
+		return i.PriorityUrgent
+	default:
+		return string(priority)
+	}
+}
+
+// SetIconSet sets the named icon set (unicode or ascii).
+func (c *Config) SetIconSet(name string) error {
+	if !ValidateIconSet(name) {
+		return fmt.Errorf("invalid icon set %q (must be %s or %s)", name, IconSetUnicode, IconSetASCII)
+	}
+	c.IconSet = name
+	return nil
+}
+
+// SetIconOverride overrides a single icon glyph.
+func (c *Config) SetIconOverride(key, glyph string) error {
+	if err := ValidateIconKey(key); err != nil {
+		return err
+	}
+	if glyph == "" {
+		return fmt.Errorf("icon glyph cannot be empty")
+	}
+	if c.IconOverrides == nil {
+		c.IconOverrides = make(map[string]string)
+	}
+	c.IconOverrides[key] = glyph
+	return nil
+}
+
+// ClearIcons resets the icon set and removes all overrides.
+func (c *Config) ClearIcons() {
+	c.IconSet = ""
+	c.IconOverrides = nil
+}
+
+// GetGlobalIconsWithOptions returns the resolved icons from global config
+func GetGlobalIconsWithOptions(opts ConfigOptions) (Icons, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return UnicodeIcons(), err
+	}
+	return ResolveIcons(config), nil
+}
+
+// UpdateGlobalIconSetWithOptions updates the icon set in global config
+func UpdateGlobalIconSetWithOptions(opts ConfigOptions, name string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetIconSet(name); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// UpdateGlobalIconOverrideWithOptions sets a single icon override in global config
+func UpdateGlobalIconOverrideWithOptions(opts ConfigOptions, key, glyph string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetIconOverride(key, glyph); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalIconsWithOptions removes the icon settings from global config
+func ClearGlobalIconsWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.ClearIcons()
+	return config.SaveWithOptions(opts)
+}
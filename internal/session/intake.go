@@ -0,0 +1,271 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const intakeFile = "inbox.jsonl"
+
+// Kinds of intake sources
+const (
+	IntakeWebhook = "webhook"
+	IntakeEmail   = "email"
+)
+
+// IntakeSource records where a proposed ball came from
+type IntakeSource struct {
+	Kind string `json:"kind"`           // "webhook" or "email"
+	Name string `json:"name,omitempty"` // Sending system for webhooks (e.g., "grafana")
+	From string `json:"from,omitempty"` // Sender of an email, or reporter named in a webhook
+	Ref  string `json:"ref,omitempty"`  // Message-ID or the sender's own ID; repeats are dropped
+}
+
+// String describes the source for humans, e.g. "email from ana@example.com"
+func (s IntakeSource) String() string {
+	desc := s.Kind
+	if s.Name != "" {
+		desc += " (" + s.Name + ")"
+	}
+	if s.From != "" {
+		desc += " from " + s.From
+	}
+	return desc
+}
+
+// IntakeItem is a ball proposed by an email or webhook, waiting in the
+// triage inbox until it's accepted (added as a ball) or rejected
+type IntakeItem struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	Context    string       `json:"context,omitempty"`
+	Priority   Priority     `json:"priority,omitempty"`
+	Tags       []string     `json:"tags,omitempty"`
+	Source     IntakeSource `json:"source"`
+	ReceivedAt time.Time    `json:"received_at"`
+}
+
+// NewIntakeItem returns a proposed ball with a fresh ID, checking its title
+// and priority
+func NewIntakeItem(title, context string, priority Priority, source IntakeSource) (*IntakeItem, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	if priority == "" {
+		priority = PriorityMedium
+	}
+	if !ValidatePriority(string(priority)) {
+		return nil, fmt.Errorf("invalid priority: %s (must be low, medium, high, or urgent)", priority)
+	}
+	return &IntakeItem{
+		ID:         uuid.New().String()[:8],
+		Title:      title,
+		Context:    strings.TrimSpace(context),
+		Priority:   priority,
+		Source:     source,
+		ReceivedAt: time.Now(),
+	}, nil
+}
+
+// NewBall returns the pending ball an accepted item becomes. The source is
+// recorded at the end of the ball's context.
+func (item *IntakeItem) NewBall(projectDir string) (*Ball, error) {
+	ball, err := NewBall(projectDir, item.Title, item.Priority)
+	if err != nil {
+		return nil, err
+	}
+	ball.Context = item.Context
+	if ball.Context != "" {
+		ball.Context += "\n\n"
+	}
+	ball.Context += fmt.Sprintf("Reported via %s on %s.", item.Source, item.ReceivedAt.Format("2006-01-02 15:04"))
+	for _, tag := range item.Tags {
+		ball.AddTag(tag)
+	}
+	return ball, nil
+}
+
+// IntakeStore manages the triage inbox of proposed balls for a project
+type IntakeStore struct {
+	projectDir string
+	config     StoreConfig
+}
+
+// NewIntakeStore creates a new intake store for the given project directory
+func NewIntakeStore(projectDir string) (*IntakeStore, error) {
+	return NewIntakeStoreWithConfig(projectDir, DefaultStoreConfig())
+}
+
+// NewIntakeStoreWithConfig creates a new intake store with custom configuration
+func NewIntakeStoreWithConfig(projectDir string, config StoreConfig) (*IntakeStore, error) {
+	if projectDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		projectDir = cwd
+	}
+
+	// Resolve to main repo if this is a worktree
+	storageDir, err := ResolveStorageDir(projectDir, config.JuggleDirName)
+	if err != nil {
+		storageDir = projectDir
+	}
+
+	return &IntakeStore{
+		projectDir: storageDir,
+		config:     config,
+	}, nil
+}
+
+// intakeFilePath returns the path to the inbox file
+func (s *IntakeStore) intakeFilePath() string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, intakeFile)
+}
+
+// LoadIntake loads the items in the inbox, oldest first
+func (s *IntakeStore) LoadIntake() ([]*IntakeItem, error) {
+	data, err := os.ReadFile(s.intakeFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*IntakeItem{}, nil
+		}
+		return nil, fmt.Errorf("failed to read inbox file: %w", err)
+	}
+
+	items := make([]*IntakeItem, 0)
+	for _, line := range splitLines(string(data)) {
+		if len(line) == 0 {
+			continue
+		}
+		var item IntakeItem
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			// Skip malformed records
+			continue
+		}
+		items = append(items, &item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ReceivedAt.Before(items[j].ReceivedAt)
+	})
+	return items, nil
+}
+
+// GetIntake returns the inbox item with the given ID or ID prefix
+func (s *IntakeStore) GetIntake(id string) (*IntakeItem, error) {
+	items, err := s.LoadIntake()
+	if err != nil {
+		return nil, err
+	}
+	var matches []*IntakeItem
+	for _, item := range items {
+		if item.ID == id {
+			return item, nil
+		}
+		if strings.HasPrefix(item.ID, id) {
+			matches = append(matches, item)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no inbox item %s", id)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("inbox item prefix %s is ambiguous", id)
+}
+
+// AddIntake adds an item to the inbox. An item whose source Ref is already
+// in the inbox (a retried webhook, or an email fetched twice) isn't added
+// again; added reports whether it was.
+func (s *IntakeStore) AddIntake(item *IntakeItem) (added bool, err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	items, err := s.LoadIntake()
+	if err != nil {
+		return false, err
+	}
+	if item.Source.Ref != "" {
+		for _, existing := range items {
+			if existing.Source.Kind == item.Source.Kind && existing.Source.Ref == item.Source.Ref {
+				return false, nil
+			}
+		}
+	}
+	return true, s.writeIntake(append(items, item))
+}
+
+// RemoveIntake removes an item from the inbox
+func (s *IntakeStore) RemoveIntake(id string) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	items, err := s.LoadIntake()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*IntakeItem, 0, len(items))
+	found := false
+	for _, item := range items {
+		if item.ID == id {
+			found = true
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	if !found {
+		return fmt.Errorf("no inbox item %s", id)
+	}
+	return s.writeIntake(remaining)
+}
+
+// lock locks the inbox file, creating the juggle directory for the lock
+// file if this is the project's first use of it
+func (s *IntakeStore) lock() (func(), error) {
+	juggleDir := filepath.Join(s.projectDir, s.config.JuggleDirName)
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create juggle directory: %w", err)
+	}
+	_, unlock, err := acquireFileLock(s.intakeFilePath())
+	return unlock, err
+}
+
+// writeIntake atomically rewrites the inbox file; the caller holds the lock
+func (s *IntakeStore) writeIntake(items []*IntakeItem) error {
+	var data []byte
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal inbox item: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	tempPath := s.intakeFilePath() + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write inbox file: %w", err)
+	}
+	if err := os.Rename(tempPath, s.intakeFilePath()); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace inbox file: %w", err)
+	}
+	return nil
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/ohare93/juggle/internal/profile"
 )
 
 const (
@@ -22,7 +24,7 @@ const (
 //   - Balls are linked to sessions via tags matching the session ID
 //   - Session context persists across agent iterations
 //   - Session-level acceptance criteria apply to all linked balls
-//   - Default model size can be set for all balls in the session
+//   - Default model size and agent provider can be set for all balls in the session
 //
 // Sessions are stored in .juggle/sessions/<id>/session.json with
 // accompanying progress.txt for logging agent activity.
@@ -34,11 +36,17 @@ const (
 type JuggleSession struct {
 	ID                 string    `json:"id"`                         // Session ID (same as tag)
 	Description        string    `json:"description"`                // Human-readable description
+	Goal               string    `json:"goal,omitempty"`             // What the session is meant to achieve
+	ExitCriteria       []ExitCriterion `json:"exit_criteria,omitempty"` // Conditions that must hold before the goal is reached
 	Context            string    `json:"context"`                    // Rich context for agent memory
 	DefaultModel       ModelSize `json:"default_model,omitempty"`    // Default model size for balls in this session
+	AgentProvider      string    `json:"agent_provider,omitempty"`   // Agent provider for this session's runs (overrides config)
 	AcceptanceCriteria []string  `json:"acceptance_criteria,omitempty"` // Session-level ACs applied to all balls
+	Target             *ThroughputTarget `json:"target,omitempty"`  // Balls to complete per period
+	State              SessionState `json:"state,omitempty"`      // Lifecycle state, empty = active
 	CreatedAt          time.Time `json:"created_at"`
 	UpdatedAt          time.Time `json:"updated_at"`
+	ProjectDir         string    `json:"-"` // Project the session was loaded from, not stored
 }
 
 // NewJuggleSession creates a new session with the given ID and description
@@ -71,6 +79,23 @@ func (s *JuggleSession) SetDefaultModel(model ModelSize) {
 	s.UpdatedAt = time.Now()
 }
 
+// SetAgentProvider updates the session's agent provider ("" uses the configured one)
+func (s *JuggleSession) SetAgentProvider(provider string) {
+	s.AgentProvider = provider
+	s.UpdatedAt = time.Now()
+}
+
+// SetTarget sets or clears (nil) the session's throughput target. The
+// target's SetAt is stamped so earlier periods aren't judged against it.
+func (s *JuggleSession) SetTarget(target *ThroughputTarget) {
+	if target != nil {
+		target.SetAt = time.Now()
+		target.AlertedFor = nil
+	}
+	s.Target = target
+	s.UpdatedAt = time.Now()
+}
+
 // SetAcceptanceCriteria sets the session-level acceptance criteria
 func (s *JuggleSession) SetAcceptanceCriteria(criteria []string) {
 	s.AcceptanceCriteria = criteria
@@ -160,6 +185,7 @@ func (s *SessionStore) CreateSession(id, description string) (*JuggleSession, er
 
 	// Create session
 	session := NewJuggleSession(id, description)
+	session.ProjectDir = s.projectDir
 
 	// Write session JSON
 	if err := s.saveSession(session); err != nil {
@@ -186,7 +212,7 @@ func (s *SessionStore) LoadSession(id string) (*JuggleSession, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("session %s not found", id)
+			return nil, NewSessionNotFoundError(id)
 		}
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
@@ -195,12 +221,15 @@ func (s *SessionStore) LoadSession(id string) (*JuggleSession, error) {
 	if err := json.Unmarshal(data, &session); err != nil {
 		return nil, fmt.Errorf("failed to parse session file: %w", err)
 	}
+	session.ProjectDir = s.projectDir
 
 	return &session, nil
 }
 
 // ListSessions discovers all sessions in the project
 func (s *SessionStore) ListSessions() ([]*JuggleSession, error) {
+	defer profile.Start(profile.KindStore, "list_sessions")()
+
 	sessionsPath := filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir)
 
 	// If sessions directory doesn't exist, return empty list
@@ -275,6 +304,53 @@ func (s *SessionStore) UpdateSessionDefaultModel(id string, model ModelSize) err
 	return s.saveSession(session)
 }
 
+// UpdateSessionAgentProvider updates the agent provider for a session
+func (s *SessionStore) UpdateSessionAgentProvider(id, provider string) error {
+	if !ValidateAgentProvider(provider) {
+		return fmt.Errorf("invalid agent provider: %s (must be 'claude', 'opencode', 'http', 'ollama', or 'shell')", provider)
+	}
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetAgentProvider(provider)
+	return s.saveSession(session)
+}
+
+// UpdateSessionGoal updates a session's goal
+func (s *SessionStore) UpdateSessionGoal(id, goal string) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetGoal(goal)
+	return s.saveSession(session)
+}
+
+// UpdateSessionExitCriteria replaces a session's exit criteria
+func (s *SessionStore) UpdateSessionExitCriteria(id string, criteria []ExitCriterion) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetExitCriteria(criteria)
+	return s.saveSession(session)
+}
+
+// UpdateSessionState moves a session to a lifecycle state
+func (s *SessionStore) UpdateSessionState(id string, state SessionState) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetState(state)
+	return s.saveSession(session)
+}
+
 // DeleteSession removes a session and its directory
 func (s *SessionStore) DeleteSession(id string) error {
 	// Verify session exists
@@ -290,7 +366,29 @@ func (s *SessionStore) DeleteSession(id string) error {
 	return nil
 }
 
-// AppendProgress appends content to a session's progress file
+// AppendProgressEntry appends a structured entry to a session's progress
+// log. A zero timestamp is set to now and an empty category to work.
+func (s *SessionStore) AppendProgressEntry(id string, entry ProgressEntry) error {
+	entry.Text = strings.TrimSpace(NormalizeNewlines(entry.Text))
+	if entry.Text == "" {
+		return fmt.Errorf("progress text cannot be empty")
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if entry.Category == "" {
+		entry.Category = ProgressCategoryWork
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal progress entry: %w", err)
+	}
+	return s.AppendProgress(id, string(data)+"\n")
+}
+
+// AppendProgress appends raw content to a session's progress file. Prefer
+// AppendProgressEntry; free-form text is read back as legacy entries.
 func (s *SessionStore) AppendProgress(id, content string) error {
 	// Verify session exists (skip for "_all" virtual session)
 	if id != "_all" {
@@ -322,19 +420,29 @@ func (s *SessionStore) AppendProgress(id, content string) error {
 	}
 	defer f.Close()
 
-	if _, err := f.WriteString(content); err != nil {
+	if _, err := f.WriteString(NormalizeNewlines(content)); err != nil {
 		return fmt.Errorf("failed to write to progress file: %w", err)
 	}
 
 	return nil
 }
 
-// LoadProgress reads the contents of a session's progress file
+// LoadProgress returns a session's progress log as text, one entry per line
 func (s *SessionStore) LoadProgress(id string) (string, error) {
+	entries, err := s.LoadProgressEntries(id)
+	if err != nil {
+		return "", err
+	}
+	return FormatProgress(entries), nil
+}
+
+// LoadProgressEntries reads the entries of a session's progress file,
+// oldest first
+func (s *SessionStore) LoadProgressEntries(id string) ([]ProgressEntry, error) {
 	// Verify session exists (skip for "_all" virtual session)
 	if id != "_all" {
 		if _, err := s.LoadSession(id); err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
@@ -343,12 +451,14 @@ func (s *SessionStore) LoadProgress(id string) (string, error) {
 	data, err := os.ReadFile(progressPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil // Empty progress is valid
+			return nil, nil // Empty progress is valid
 		}
-		return "", fmt.Errorf("failed to read progress file: %w", err)
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
 	}
 
-	return string(data), nil
+	// Progress edited on Windows or checked out with core.autocrlf may
+	// have CRLF line endings; ParseProgress normalizes them
+	return ParseProgress(string(data)), nil
 }
 
 // ClearProgress truncates a session's progress file to empty
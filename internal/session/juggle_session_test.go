@@ -513,6 +513,32 @@ func TestSessionStore_AppendAndLoadProgress(t *testing.T) {
 	}
 }
 
+func TestSessionStore_LoadProgress_CRLF(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.CreateSession("my-session", "desc"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	// Written by a Windows editor or checked out with core.autocrlf
+	if err := os.WriteFile(store.progressFilePath("my-session"), []byte("First line\r\nSecond line\r\n"), 0644); err != nil {
+		t.Fatalf("failed to write progress: %v", err)
+	}
+	if err := store.AppendProgress("my-session", "Third line\r\n"); err != nil {
+		t.Fatalf("failed to append progress: %v", err)
+	}
+
+	progress, err := store.LoadProgress("my-session")
+	if err != nil {
+		t.Fatalf("failed to load progress: %v", err)
+	}
+	if expected := "First line\nSecond line\nThird line\n"; progress != expected {
+		t.Errorf("expected progress %q, got %q", expected, progress)
+	}
+}
+
 func TestSessionStore_AppendProgress_SessionNotFound(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "juggle-test-*")
 	if err != nil {
@@ -1678,3 +1704,173 @@ func TestLoadAllSessions_NoProjects(t *testing.T) {
 		t.Errorf("expected 0 sessions with no projects, got %d", len(sessions))
 	}
 }
+
+func TestParseThroughputTarget(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		errors bool
+	}{
+		{"5/week", "5/week", false},
+		{"5 balls/week", "5/week", false},
+		{"3 per day", "3/day", false},
+		{"10/m", "10/month", false},
+		{"0/week", "", true},
+		{"5/year", "", true},
+		{"five", "", true},
+	}
+	for _, tt := range tests {
+		target, err := ParseThroughputTarget(tt.input)
+		if tt.errors {
+			if err == nil {
+				t.Errorf("ParseThroughputTarget(%q): expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseThroughputTarget(%q): %v", tt.input, err)
+			continue
+		}
+		if target.String() != tt.want {
+			t.Errorf("ParseThroughputTarget(%q) = %q, want %q", tt.input, target.String(), tt.want)
+		}
+	}
+}
+
+func TestSessionThroughput(t *testing.T) {
+	// Wednesday; the week started Monday Oct 12
+	now := time.Date(2026, time.October, 14, 12, 0, 0, 0, time.Local)
+	completed := func(id string, at time.Time, tags ...string) *Ball {
+		return &Ball{ID: id, State: StateComplete, CompletedAt: &at, Tags: tags}
+	}
+
+	sess := NewJuggleSession("feature", "")
+	if SessionThroughput(sess, nil, now) != nil {
+		t.Fatal("expected no progress without a target")
+	}
+	sess.Target = &ThroughputTarget{Count: 2, Period: PeriodWeek, SetAt: now.AddDate(0, 0, -30)}
+
+	balls := []*Ball{
+		completed("a", now.AddDate(0, 0, -1), "feature"),  // This week
+		completed("b", now.AddDate(0, 0, -8), "feature"),  // Last week
+		completed("c", now.AddDate(0, 0, -15), "feature"), // Two weeks ago
+		completed("d", now.AddDate(0, 0, -15), "other"),   // Other session
+		{ID: "e", State: StatePending, Tags: []string{"feature"}},
+	}
+
+	progress := SessionThroughput(sess, balls, now)
+	if progress.Current.Done != 1 || !progress.Current.Start.Equal(time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected 1 done this week starting Oct 12, got %+v", progress.Current)
+	}
+	if len(progress.Previous) != 2 || progress.Previous[0].Done != 1 || progress.Previous[1].Done != 1 {
+		t.Errorf("expected 1 done in each previous week, got %+v", progress.Previous)
+	}
+	if !progress.Behind || !progress.NeedsAlert() {
+		t.Error("expected session to be behind after missing two weeks")
+	}
+
+	// Alerting once per period
+	alerted := progress.Current.Start
+	sess.Target.AlertedFor = &alerted
+	if SessionThroughput(sess, balls, now).NeedsAlert() {
+		t.Error("expected no second alert in the same period")
+	}
+
+	// Meeting the target in either period clears it
+	balls = append(balls, completed("f", now.AddDate(0, 0, -8), "feature"))
+	if SessionThroughput(sess, balls, now).Behind {
+		t.Error("expected session not behind after meeting last week's target")
+	}
+
+	// Periods before the target was set aren't judged
+	sess.Target.SetAt = now.AddDate(0, 0, -10)
+	balls = balls[:len(balls)-1]
+	if SessionThroughput(sess, balls, now).Behind {
+		t.Error("expected periods before the target was set not to count")
+	}
+
+	// Balls another session's run completed count only there
+	shared := completed("g", now.AddDate(0, 0, -1), "feature", "other")
+	shared.CompletedBySession = "other"
+	if done := SessionThroughput(sess, append(balls, shared), now).Current.Done; done != 1 {
+		t.Errorf("expected a ball completed by another session not to count, got %d done", done)
+	}
+}
+
+func TestSessionStore_UpdateSessionState(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	sess, err := store.CreateSession("my-session", "desc")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if sess.CurrentState() != SessionStateActive || !sess.Schedulable() {
+		t.Errorf("expected a new session to be active, got %q", sess.CurrentState())
+	}
+
+	for _, state := range []SessionState{SessionStatePaused, SessionStateArchived, SessionStateActive} {
+		if err := store.UpdateSessionState("my-session", state); err != nil {
+			t.Fatalf("failed to set state %s: %v", state, err)
+		}
+		loaded, err := store.LoadSession("my-session")
+		if err != nil {
+			t.Fatalf("failed to load session: %v", err)
+		}
+		if loaded.CurrentState() != state || loaded.Schedulable() != (state == SessionStateActive) {
+			t.Errorf("expected state %s, got %s", state, loaded.CurrentState())
+		}
+		if state == SessionStateActive && loaded.State != "" {
+			t.Errorf("expected active not to be stored, got %q", loaded.State)
+		}
+	}
+
+	if _, err := ParseSessionState("done"); err == nil {
+		t.Error("expected an error for an unknown state")
+	}
+}
+
+func TestSessionStore_UpdateSessionGoal(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if _, err := store.CreateSession("my-session", "desc"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	criteria := []ExitCriterion{
+		{Text: "Tests pass", Check: "go test ./..."},
+		{Text: "Docs updated", Met: true},
+		{Text: "Demoed"},
+	}
+	if err := store.UpdateSessionGoal("my-session", "  Ship OAuth login "); err != nil {
+		t.Fatalf("failed to set goal: %v", err)
+	}
+	if err := store.UpdateSessionExitCriteria("my-session", criteria); err != nil {
+		t.Fatalf("failed to set exit criteria: %v", err)
+	}
+	loaded, err := store.LoadSession("my-session")
+	if err != nil {
+		t.Fatalf("failed to load session: %v", err)
+	}
+	if loaded.Goal != "Ship OAuth login" || !loaded.HasGoal() {
+		t.Errorf("expected the trimmed goal, got %q", loaded.Goal)
+	}
+	if met, total := loaded.GoalProgress(); met != 1 || total != 3 {
+		t.Errorf("expected 1/3 criteria met, got %d/%d", met, total)
+	}
+
+	// Checks decide their criterion; the rest keep the state they were marked with
+	evaluated := EvaluateExitCriteria(loaded.ExitCriteria, func(check string) bool { return check == "go test ./..." })
+	if unmet := UnmetExitCriteria(evaluated); len(unmet) != 1 || unmet[0].Text != "Demoed" {
+		t.Errorf("expected only Demoed unmet, got %+v", unmet)
+	}
+	if loaded.ExitCriteria[0].Met {
+		t.Error("expected EvaluateExitCriteria not to modify its input")
+	}
+	if got, want := evaluated[0].String(), "[x] Tests pass (check: go test ./...)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultKeySequenceTimeout is how long the TUI waits for the second key of
+// a two-key sequence (s, t, v, ...) before cancelling it
+const DefaultKeySequenceTimeout = 1500 * time.Millisecond
+
+// maxKeySequenceTimeoutMs caps the timeout, so a typo can't leave the TUI
+// waiting on a stray key for minutes
+const maxKeySequenceTimeoutMs = 60000
+
+// KeySequenceConfig configures the TUI's two-key sequences
+type KeySequenceConfig struct {
+	TimeoutMs int  `json:"timeout_ms,omitempty"` // Milliseconds before a pending sequence cancels (default 1500)
+	WhichKey  bool `json:"which_key,omitempty"`  // List the second keys in a popup while a sequence is pending
+}
+
+// Timeout returns how long a pending sequence waits for its second key
+func (k *KeySequenceConfig) Timeout() time.Duration {
+	if k == nil || k.TimeoutMs == 0 {
+		return DefaultKeySequenceTimeout
+	}
+	return time.Duration(k.TimeoutMs) * time.Millisecond
+}
+
+// SetKeySequences validates and stores the key sequence settings
+func (c *Config) SetKeySequences(keys *KeySequenceConfig) error {
+	if keys.TimeoutMs < 0 || keys.TimeoutMs > maxKeySequenceTimeoutMs {
+		return fmt.Errorf("invalid timeout %dms (must be between 1 and %d ms, or 0 for the default)", keys.TimeoutMs, maxKeySequenceTimeoutMs)
+	}
+	c.KeySequences = keys
+	return nil
+}
+
+// GetKeySequences returns the key sequence settings (never nil)
+func (c *Config) GetKeySequences() *KeySequenceConfig {
+	if c.KeySequences == nil {
+		return &KeySequenceConfig{}
+	}
+	return c.KeySequences
+}
+
+// UpdateGlobalKeySequencesWithOptions applies edit to the key sequence
+// settings in global config
+func UpdateGlobalKeySequencesWithOptions(opts ConfigOptions, edit func(keys *KeySequenceConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	keys := *config.GetKeySequences()
+	edit(&keys)
+	if err := config.SetKeySequences(&keys); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalKeySequencesWithOptions restores the default key sequence
+// settings in global config
+func ClearGlobalKeySequencesWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.KeySequences = nil
+	return config.SaveWithOptions(opts)
+}
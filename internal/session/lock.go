@@ -162,6 +162,100 @@ func readLockInfo(lockPath string) (*LockInfo, error) {
 	return &info, nil
 }
 
+// States of a session lock, see SessionLockState
+const (
+	LockActive  = "active"  // Held by a process still running on this host
+	LockStale   = "stale"   // Left behind: not held, or its process is gone
+	LockUnknown = "unknown" // Held from another host, or by an unknown process
+)
+
+// SessionLockState describes a session's lock files and who holds them
+type SessionLockState struct {
+	SessionID      string    `json:"session_id"` // "_all" for 'agent run all'
+	PID            int       `json:"pid,omitempty"`
+	Hostname       string    `json:"hostname,omitempty"`
+	StartedAt      time.Time `json:"started_at,omitempty"`
+	Held           bool      `json:"held"`                      // Whether the OS-level lock is held
+	ProcessRunning *bool     `json:"process_running,omitempty"` // Whether PID is running; nil unless it's on this host
+	State          string    `json:"state"`
+}
+
+// SessionLockState returns the state of a session's lock, or nil if it
+// has no lock files
+func (s *SessionStore) SessionLockState(sessionID string) (*SessionLockState, error) {
+	lockPath := filepath.Join(s.sessionPath(sessionID), lockFile)
+	lockInfoPath := filepath.Join(s.sessionPath(sessionID), lockInfoFile)
+	_, lockErr := os.Stat(lockPath)
+	_, infoErr := os.Stat(lockInfoPath)
+	if os.IsNotExist(lockErr) && os.IsNotExist(infoErr) {
+		return nil, nil
+	}
+
+	state := &SessionLockState{SessionID: sessionID}
+	state.Held, _ = s.IsLocked(sessionID)
+	if info, err := readLockInfo(lockInfoPath); err == nil {
+		state.PID = info.PID
+		state.Hostname = info.Hostname
+		state.StartedAt = info.StartedAt
+		currentHostname, _ := os.Hostname()
+		if info.Hostname == currentHostname && info.PID > 0 {
+			running := isProcessRunning(info.PID)
+			state.ProcessRunning = &running
+		}
+	}
+
+	switch {
+	case !state.Held:
+		state.State = LockStale
+	case state.ProcessRunning == nil:
+		state.State = LockUnknown
+	case *state.ProcessRunning:
+		state.State = LockActive
+	default:
+		// Still held, e.g. by a child that outlived the crashed run
+		state.State = LockStale
+	}
+	return state, nil
+}
+
+// ListSessionLocks returns the state of every session lock in the project,
+// by session ID
+func (s *SessionStore) ListSessionLocks() ([]*SessionLockState, error) {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir))
+	if os.IsNotExist(err) {
+		return []*SessionLockState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	locks := make([]*SessionLockState, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		state, err := s.SessionLockState(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if state != nil {
+			locks = append(locks, state)
+		}
+	}
+	return locks, nil
+}
+
+// BreakSessionLock removes a session's lock files, so the next run can
+// take the lock even if whatever held it never lets go. A process still
+// holding the old lock keeps it, but no longer blocks anyone.
+func (s *SessionStore) BreakSessionLock(sessionID string) error {
+	for _, name := range []string{lockFile, lockInfoFile} {
+		if err := os.Remove(filepath.Join(s.sessionPath(sessionID), name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // BallLock represents a lock on a specific ball to prevent concurrent agent runs
 type BallLock struct {
 	ballID       string
@@ -393,6 +393,82 @@ func TestLockInfoContainsHostname(t *testing.T) {
 // Ball Lock Tests
 // ============================================================================
 
+func TestListSessionLocks(t *testing.T) {
+	store, err := NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	for _, id := range []string{"running", "crashed", "remote", "idle"} {
+		if _, err := store.CreateSession(id, ""); err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+	}
+
+	running, err := store.AcquireSessionLock("running")
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	defer running.Release()
+
+	// A crashed run's files, no longer locked
+	hostname, _ := os.Hostname()
+	crashedDir := store.sessionPath("crashed")
+	if err := os.WriteFile(filepath.Join(crashedDir, lockFile), nil, 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+	info := `{"pid":999999999,"hostname":"` + hostname + `","started_at":"2026-01-02T03:04:05Z"}`
+	if err := os.WriteFile(filepath.Join(crashedDir, lockInfoFile), []byte(info), 0644); err != nil {
+		t.Fatalf("failed to write lock info: %v", err)
+	}
+
+	// Held, but claimed by another host whose process can't be checked
+	remote, err := store.AcquireSessionLock("remote")
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	defer remote.Release()
+	info = `{"pid":42,"hostname":"elsewhere","started_at":"2026-01-02T03:04:05Z"}`
+	if err := os.WriteFile(filepath.Join(store.sessionPath("remote"), lockInfoFile), []byte(info), 0644); err != nil {
+		t.Fatalf("failed to write lock info: %v", err)
+	}
+
+	locks, err := store.ListSessionLocks()
+	if err != nil {
+		t.Fatalf("ListSessionLocks failed: %v", err)
+	}
+	states := make(map[string]*SessionLockState)
+	for _, lock := range locks {
+		states[lock.SessionID] = lock
+	}
+	if len(locks) != 3 || states["idle"] != nil {
+		t.Fatalf("expected the 3 locked sessions, got %d", len(locks))
+	}
+	if got := states["running"]; got.State != LockActive || !got.Held || got.PID != os.Getpid() {
+		t.Errorf("expected the running lock active, got %+v", got)
+	}
+	if got := states["crashed"]; got.State != LockStale || got.Held || got.ProcessRunning == nil || *got.ProcessRunning {
+		t.Errorf("expected the crashed lock stale, got %+v", got)
+	}
+	if got := states["remote"]; got.State != LockUnknown || !got.Held || got.ProcessRunning != nil {
+		t.Errorf("expected the remote lock unknown, got %+v", got)
+	}
+
+	if err := store.BreakSessionLock("crashed"); err != nil {
+		t.Fatalf("BreakSessionLock failed: %v", err)
+	}
+	if state, _ := store.SessionLockState("crashed"); state != nil {
+		t.Errorf("expected no lock after breaking it, got %+v", state)
+	}
+	if err := store.BreakSessionLock("idle"); err != nil {
+		t.Errorf("expected breaking a missing lock to succeed, got %v", err)
+	}
+	lock, err := store.AcquireSessionLock("crashed")
+	if err != nil {
+		t.Fatalf("expected the session lockable after breaking, got %v", err)
+	}
+	lock.Release()
+}
+
 func TestAcquireBallLock_Success(t *testing.T) {
 	// Create temp directory
 	tmpDir, err := os.MkdirTemp("", "ball-lock-test-*")
@@ -0,0 +1,463 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// mergeConflictsFile records field conflicts found by the merge driver
+const mergeConflictsFile = "conflicts.jsonl"
+
+// mergeSetFields are list fields merged as sets: additions from either side
+// are kept and removals from either side are honoured.
+var mergeSetFields = map[string]bool{
+	"tags":        true,
+	"depends_on":  true,
+	"watch_globs": true,
+}
+
+// MergeConflict is a field both sides of a merge changed to different
+// values. The merge keeps the value from the side with the latest activity
+// and records the conflict so it can be reviewed.
+type MergeConflict struct {
+	File       string          `json:"file"`
+	ID         string          `json:"id"`               // Ball or session ID
+	Field      string          `json:"field"`            // JSON field name, or "" when one side deleted the record
+	Base       json.RawMessage `json:"base,omitempty"`   // Common ancestor value
+	Ours       json.RawMessage `json:"ours,omitempty"`   // Value on the current branch
+	Theirs     json.RawMessage `json:"theirs,omitempty"` // Value on the branch being merged
+	Resolution string          `json:"resolution"`       // "ours" or "theirs"
+	DetectedAt time.Time       `json:"detected_at"`
+}
+
+// the record type used while merging; keeps unknown fields intact
+type mergeRecord map[string]json.RawMessage
+
+// MergeBallsJSONL performs a three-way merge of balls.jsonl contents. Balls
+// are matched by ID and merged field by field (see mergeRecords). The
+// result keeps the order of ours, followed by balls only theirs added.
+func MergeBallsJSONL(base, ours, theirs []byte) ([]byte, []MergeConflict, error) {
+	baseBalls, _, err := parseMergeJSONL(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse base: %w", err)
+	}
+	ourBalls, ourOrder, err := parseMergeJSONL(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse ours: %w", err)
+	}
+	theirBalls, theirOrder, err := parseMergeJSONL(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse theirs: %w", err)
+	}
+
+	order := append([]string{}, ourOrder...)
+	for _, id := range theirOrder {
+		if _, ok := ourBalls[id]; !ok {
+			order = append(order, id)
+		}
+	}
+
+	var conflicts []MergeConflict
+	var buf bytes.Buffer
+	for _, id := range order {
+		merged, recConflicts := mergeRecords(id, baseBalls[id], ourBalls[id], theirBalls[id], "last_activity")
+		conflicts = append(conflicts, recConflicts...)
+		if merged == nil {
+			continue
+		}
+
+		// Round-trip through Ball so the line matches what the store writes
+		raw, err := json.Marshal(merged)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal ball %s: %w", id, err)
+		}
+		var ball Ball
+		if err := json.Unmarshal(raw, &ball); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode merged ball %s: %w", id, err)
+		}
+		line, err := json.Marshal(&ball)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal ball %s: %w", id, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), conflicts, nil
+}
+
+// MergeSessionJSON performs a three-way merge of a session.json file.
+// Fields are merged like balls, using updated_at as the activity time.
+func MergeSessionJSON(base, ours, theirs []byte) ([]byte, []MergeConflict, error) {
+	parse := func(data []byte, side string) (mergeRecord, error) {
+		if len(bytes.TrimSpace(data)) == 0 {
+			return nil, nil
+		}
+		var rec mergeRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", side, err)
+		}
+		return rec, nil
+	}
+	baseRec, err := parse(base, "base")
+	if err != nil {
+		return nil, nil, err
+	}
+	ourRec, err := parse(ours, "ours")
+	if err != nil {
+		return nil, nil, err
+	}
+	theirRec, err := parse(theirs, "theirs")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := recordID(ourRec)
+	if id == "" {
+		id = recordID(theirRec)
+	}
+	merged, conflicts := mergeRecords(id, baseRec, ourRec, theirRec, "updated_at")
+	if merged == nil {
+		return []byte{}, conflicts, nil
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal session %s: %w", id, err)
+	}
+	var sess JuggleSession
+	if err := json.Unmarshal(raw, &sess); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode merged session %s: %w", id, err)
+	}
+	data, err := json.MarshalIndent(&sess, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal session %s: %w", id, err)
+	}
+	return data, conflicts, nil
+}
+
+// MergeProgress merges a session's append-only progress log: ours is kept
+// as-is and lines theirs appended since base are added after it.
+func MergeProgress(base, ours, theirs []byte) []byte {
+	base, ours, theirs = normalizeNewlineBytes(base), normalizeNewlineBytes(ours), normalizeNewlineBytes(theirs)
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(base), "\n") {
+		seen[line] = true
+	}
+	for _, line := range strings.Split(string(ours), "\n") {
+		seen[line] = true
+	}
+
+	var buf bytes.Buffer
+	buf.Write(ours)
+	if len(ours) > 0 && !bytes.HasSuffix(ours, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	for _, line := range strings.Split(string(theirs), "\n") {
+		if line == "" || seen[line] {
+			continue
+		}
+		seen[line] = true
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// mergeRecords merges one record three ways. For each field: if only one
+// side changed it, that change wins; if both changed it to different
+// values, set fields are unioned, activityKey takes the latest time, and
+// anything else takes the side with the latest activity and is recorded as
+// a conflict. A record deleted on one side stays deleted unless the other
+// side modified it, in which case the modified record is kept (and
+// flagged). Returns nil if the record is deleted.
+func mergeRecords(id string, base, ours, theirs mergeRecord, activityKey string) (mergeRecord, []MergeConflict) {
+	now := time.Now()
+	switch {
+	case ours == nil && theirs == nil:
+		return nil, nil
+	case ours == nil || theirs == nil:
+		kept, side := theirs, "theirs"
+		if theirs == nil {
+			kept, side = ours, "ours"
+		}
+		if base == nil {
+			return kept, nil // Added on one side
+		}
+		if recordsEqual(kept, base) {
+			return nil, nil // Deleted on one side, untouched on the other
+		}
+		conflict := MergeConflict{ID: id, Resolution: side, DetectedAt: now}
+		if side == "ours" {
+			conflict.Ours = mustMarshalRecord(kept)
+		} else {
+			conflict.Theirs = mustMarshalRecord(kept)
+		}
+		return kept, []MergeConflict{conflict}
+	}
+
+	oursNewer := !activityTime(theirs[activityKey]).After(activityTime(ours[activityKey]))
+	newerSide := "theirs"
+	if oursNewer {
+		newerSide = "ours"
+	}
+
+	keys := make([]string, 0, len(ours)+len(theirs))
+	seen := make(map[string]bool)
+	for _, rec := range []mergeRecord{ours, theirs, base} {
+		for k := range rec {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys) // Stable conflict order
+
+	merged := make(mergeRecord, len(keys))
+	var conflicts []MergeConflict
+	for _, key := range keys {
+		o, a, b := base[key], ours[key], theirs[key]
+		var value json.RawMessage
+		switch {
+		case rawEqual(a, b):
+			value = a
+		case rawEqual(a, o):
+			value = b
+		case rawEqual(b, o):
+			value = a
+		case key == activityKey:
+			value = b
+			if oursNewer {
+				value = a
+			}
+		case mergeSetFields[key]:
+			value = mergeStringSets(o, a, b)
+		case key == "update_count":
+			value = a
+			if activityCount(b) > activityCount(a) {
+				value = b
+			}
+		default:
+			value = b
+			if oursNewer {
+				value = a
+			}
+			conflicts = append(conflicts, MergeConflict{
+				ID:         id,
+				Field:      key,
+				Base:       o,
+				Ours:       a,
+				Theirs:     b,
+				Resolution: newerSide,
+				DetectedAt: now,
+			})
+		}
+		if value != nil {
+			merged[key] = value
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeStringSets merges string lists as sets, keeping ours' order
+func mergeStringSets(base, ours, theirs json.RawMessage) json.RawMessage {
+	var o, a, b []string
+	_ = json.Unmarshal(base, &o)
+	_ = json.Unmarshal(ours, &a)
+	_ = json.Unmarshal(theirs, &b)
+
+	inBase := make(map[string]bool, len(o))
+	for _, v := range o {
+		inBase[v] = true
+	}
+	inOurs := make(map[string]bool, len(a))
+	for _, v := range a {
+		inOurs[v] = true
+	}
+	inTheirs := make(map[string]bool, len(b))
+	for _, v := range b {
+		inTheirs[v] = true
+	}
+
+	result := make([]string, 0, len(a)+len(b))
+	added := make(map[string]bool)
+	for _, v := range append(append([]string{}, a...), b...) {
+		if added[v] {
+			continue
+		}
+		// Dropped by a side that had it in base: the removal wins
+		if inBase[v] && (!inOurs[v] || !inTheirs[v]) {
+			continue
+		}
+		added[v] = true
+		result = append(result, v)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(result)
+	return data
+}
+
+// parseMergeJSONL parses JSONL records keyed by ID, returning IDs in file order
+func parseMergeJSONL(data []byte) (map[string]mergeRecord, []string, error) {
+	records := make(map[string]mergeRecord)
+	order := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec mergeRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		id := recordID(rec)
+		if id == "" {
+			return nil, nil, fmt.Errorf("line %d: record has no id", lineNum)
+		}
+		if _, dup := records[id]; !dup {
+			order = append(order, id)
+		}
+		records[id] = rec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return records, order, nil
+}
+
+func recordID(rec mergeRecord) string {
+	var id string
+	_ = json.Unmarshal(rec["id"], &id)
+	return id
+}
+
+func recordsEqual(a, b mergeRecord) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !rawEqual(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// rawEqual compares JSON values ignoring formatting; absent equals null
+func rawEqual(a, b json.RawMessage) bool {
+	normalize := func(v json.RawMessage) string {
+		if len(v) == 0 {
+			return "null"
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, v); err != nil {
+			return string(v)
+		}
+		return buf.String()
+	}
+	return normalize(a) == normalize(b)
+}
+
+func activityTime(v json.RawMessage) time.Time {
+	var t time.Time
+	_ = json.Unmarshal(v, &t)
+	return t
+}
+
+func activityCount(v json.RawMessage) int {
+	var n int
+	_ = json.Unmarshal(v, &n)
+	return n
+}
+
+func mustMarshalRecord(rec mergeRecord) json.RawMessage {
+	data, _ := json.Marshal(rec)
+	return data
+}
+
+// AppendMergeConflicts records conflicts in juggleDir's conflicts file
+func AppendMergeConflicts(juggleDir string, conflicts []MergeConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", juggleDir, err)
+	}
+	path := filepath.Join(juggleDir, mergeConflictsFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open conflicts file: %w", err)
+	}
+	defer f.Close()
+
+	for _, c := range conflicts {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return fmt.Errorf("failed to marshal conflict: %w", err)
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write conflict: %w", err)
+		}
+	}
+	return nil
+}
+
+// mergeConflictsPath returns the path to the store's conflicts file
+func (s *Store) mergeConflictsPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), mergeConflictsFile)
+}
+
+// LoadMergeConflicts returns conflicts recorded by the merge driver that
+// haven't been cleared yet
+func (s *Store) LoadMergeConflicts() ([]MergeConflict, error) {
+	data, err := os.ReadFile(s.mergeConflictsPath())
+	if os.IsNotExist(err) {
+		return []MergeConflict{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conflicts file: %w", err)
+	}
+
+	conflicts := make([]MergeConflict, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var c MergeConflict
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			continue // Skip corrupted lines
+		}
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, nil
+}
+
+// ClearMergeConflicts marks all recorded conflicts as reviewed
+func (s *Store) ClearMergeConflicts() error {
+	if err := os.Remove(s.mergeConflictsPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear conflicts file: %w", err)
+	}
+	return nil
+}
+
+// normalizeNewlineBytes is NormalizeNewlines for file contents
+func normalizeNewlineBytes(data []byte) []byte {
+	if !bytes.Contains(data, []byte("\r")) {
+		return data
+	}
+	return []byte(NormalizeNewlines(string(data)))
+}
@@ -0,0 +1,276 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const milestonesFile = "milestones.jsonl"
+
+// milestoneNamePattern is what a milestone name may contain, e.g. "v1.2" or "beta-launch"
+var milestoneNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Milestone is a target release or milestone that balls are assigned to
+type Milestone struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Due         *time.Time `json:"due,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ValidateMilestoneName checks that a milestone name is a single word of
+// letters, digits, dots, dashes and underscores
+func ValidateMilestoneName(name string) error {
+	if !milestoneNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid milestone name %q (use letters, digits, '.', '-' and '_', e.g. v1.2)", name)
+	}
+	return nil
+}
+
+// MilestoneStore manages a project's milestones
+type MilestoneStore struct {
+	projectDir string
+	config     StoreConfig
+}
+
+// NewMilestoneStore creates a new milestone store for the given project directory
+func NewMilestoneStore(projectDir string) (*MilestoneStore, error) {
+	return NewMilestoneStoreWithConfig(projectDir, DefaultStoreConfig())
+}
+
+// NewMilestoneStoreWithConfig creates a new milestone store with custom configuration
+func NewMilestoneStoreWithConfig(projectDir string, config StoreConfig) (*MilestoneStore, error) {
+	if projectDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		projectDir = cwd
+	}
+
+	// Resolve to main repo if this is a worktree
+	storageDir, err := ResolveStorageDir(projectDir, config.JuggleDirName)
+	if err != nil {
+		storageDir = projectDir
+	}
+
+	return &MilestoneStore{
+		projectDir: storageDir,
+		config:     config,
+	}, nil
+}
+
+// milestonesFilePath returns the path to the milestones file
+func (s *MilestoneStore) milestonesFilePath() string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, milestonesFile)
+}
+
+// LoadMilestones loads all milestones, soonest due first; milestones
+// without a due date follow, by name
+func (s *MilestoneStore) LoadMilestones() ([]*Milestone, error) {
+	data, err := os.ReadFile(s.milestonesFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Milestone{}, nil
+		}
+		return nil, fmt.Errorf("failed to read milestones file: %w", err)
+	}
+
+	milestones := make([]*Milestone, 0)
+	for _, line := range splitLines(string(data)) {
+		if len(line) == 0 {
+			continue
+		}
+		var milestone Milestone
+		if err := json.Unmarshal([]byte(line), &milestone); err != nil {
+			// Skip malformed records
+			continue
+		}
+		milestones = append(milestones, &milestone)
+	}
+
+	sortMilestones(milestones)
+	return milestones, nil
+}
+
+// GetMilestone returns the milestone with the given name (case-insensitive),
+// or nil if there is none
+func (s *MilestoneStore) GetMilestone(name string) (*Milestone, error) {
+	milestones, err := s.LoadMilestones()
+	if err != nil {
+		return nil, err
+	}
+	for _, milestone := range milestones {
+		if strings.EqualFold(milestone.Name, name) {
+			return milestone, nil
+		}
+	}
+	return nil, nil
+}
+
+// AddMilestone adds a milestone. It's an error if one with the same name exists.
+func (s *MilestoneStore) AddMilestone(milestone *Milestone) error {
+	if err := ValidateMilestoneName(milestone.Name); err != nil {
+		return err
+	}
+	milestones, err := s.LoadMilestones()
+	if err != nil {
+		return err
+	}
+	for _, existing := range milestones {
+		if strings.EqualFold(existing.Name, milestone.Name) {
+			return fmt.Errorf("milestone %s already exists", existing.Name)
+		}
+	}
+	return s.writeMilestones(append(milestones, milestone))
+}
+
+// writeMilestones atomically rewrites the milestones file
+func (s *MilestoneStore) writeMilestones(milestones []*Milestone) error {
+	juggleDir := filepath.Join(s.projectDir, s.config.JuggleDirName)
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		return fmt.Errorf("failed to create juggle directory: %w", err)
+	}
+
+	sortMilestones(milestones)
+
+	var data []byte
+	for _, milestone := range milestones {
+		line, err := json.Marshal(milestone)
+		if err != nil {
+			return fmt.Errorf("failed to marshal milestone: %w", err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+
+	tempPath := s.milestonesFilePath() + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write milestones file: %w", err)
+	}
+	if err := os.Rename(tempPath, s.milestonesFilePath()); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to replace milestones file: %w", err)
+	}
+	return nil
+}
+
+// sortMilestones orders milestones soonest due first, then by name
+func sortMilestones(milestones []*Milestone) {
+	sort.SliceStable(milestones, func(i, j int) bool {
+		di, dj := milestones[i].Due, milestones[j].Due
+		switch {
+		case di != nil && dj != nil && !di.Equal(*dj):
+			return di.Before(*dj)
+		case di != nil && dj == nil:
+			return true
+		case di == nil && dj != nil:
+			return false
+		}
+		return milestones[i].Name < milestones[j].Name
+	})
+}
+
+// MilestoneSessionProgress counts a milestone's balls in one session
+type MilestoneSessionProgress struct {
+	Session string `json:"session"` // Session ID, or "" for balls in no session
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+}
+
+// MilestoneProgress reports how far along a milestone is
+type MilestoneProgress struct {
+	Milestone *Milestone                  `json:"milestone"`
+	Done      []*Ball                     `json:"done"`      // Complete or researched balls
+	Remaining []*Ball                     `json:"remaining"` // Everything else
+	Sessions  []*MilestoneSessionProgress `json:"sessions"`
+}
+
+// Total returns how many balls are assigned to the milestone
+func (p *MilestoneProgress) Total() int {
+	return len(p.Done) + len(p.Remaining)
+}
+
+// Percent returns the share of the milestone's balls that are done, 0-100
+func (p *MilestoneProgress) Percent() int {
+	if p.Total() == 0 {
+		return 0
+	}
+	return len(p.Done) * 100 / p.Total()
+}
+
+// ReportMilestone builds the progress of a milestone from active and
+// archived balls. sessionIDs are the sessions to break progress down by; a
+// ball in several sessions counts toward each.
+func ReportMilestone(milestone *Milestone, balls []*Ball, sessionIDs []string) *MilestoneProgress {
+	progress := &MilestoneProgress{
+		Milestone: milestone,
+		Done:      make([]*Ball, 0),
+		Remaining: make([]*Ball, 0),
+		Sessions:  make([]*MilestoneSessionProgress, 0),
+	}
+
+	bySession := make(map[string]*MilestoneSessionProgress)
+	count := func(sessionID string, done bool) {
+		sp, ok := bySession[sessionID]
+		if !ok {
+			sp = &MilestoneSessionProgress{Session: sessionID}
+			bySession[sessionID] = sp
+			progress.Sessions = append(progress.Sessions, sp)
+		}
+		sp.Total++
+		if done {
+			sp.Done++
+		}
+	}
+
+	for _, ball := range balls {
+		if !strings.EqualFold(ball.Milestone, milestone.Name) {
+			continue
+		}
+		done := ball.State == StateComplete || ball.State == StateResearched
+		if done {
+			progress.Done = append(progress.Done, ball)
+		} else {
+			progress.Remaining = append(progress.Remaining, ball)
+		}
+
+		inSession := false
+		for _, id := range sessionIDs {
+			if ballHasTag(ball, id) {
+				count(id, done)
+				inSession = true
+			}
+		}
+		if !inSession {
+			count("", done)
+		}
+	}
+
+	SortBallsByPriority(progress.Done)
+	SortBallsByPriority(progress.Remaining)
+	sort.SliceStable(progress.Sessions, func(i, j int) bool {
+		si, sj := progress.Sessions[i].Session, progress.Sessions[j].Session
+		if (si == "") != (sj == "") {
+			return sj == "" // Balls in no session last
+		}
+		return si < sj
+	})
+	return progress
+}
+
+// ballHasTag returns true if the ball has the given tag
+func ballHasTag(ball *Ball, tag string) bool {
+	for _, t := range ball.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// OllamaAgentConfig configures the "ollama" agent provider, which runs local
+// models through an Ollama server's OpenAI-compatible API
+type OllamaAgentConfig struct {
+	Endpoint string            `json:"endpoint,omitempty"` // Chat completions URL (default: http://localhost:11434/v1/chat/completions)
+	Model    string            `json:"model,omitempty"`    // Model used for sizes without an entry in Models
+	Models   map[string]string `json:"models,omitempty"`   // Model per size: "small", "medium", "large"
+}
+
+// SetOllamaAgent validates and stores the Ollama agent settings
+func (c *Config) SetOllamaAgent(agent *OllamaAgentConfig) error {
+	if agent.Endpoint != "" {
+		u, err := url.Parse(agent.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q (must be an http or https URL)", agent.Endpoint)
+		}
+	}
+	for size, model := range agent.Models {
+		if size != string(ModelSizeSmall) && size != string(ModelSizeMedium) && size != string(ModelSizeLarge) {
+			return fmt.Errorf("invalid model size %q (must be small, medium, or large)", size)
+		}
+		if strings.TrimSpace(model) == "" {
+			return fmt.Errorf("model for size %s cannot be empty", size)
+		}
+	}
+	c.OllamaAgent = agent
+	return nil
+}
+
+// GetOllamaAgent returns the Ollama agent settings (never nil)
+func (c *Config) GetOllamaAgent() *OllamaAgentConfig {
+	if c.OllamaAgent == nil {
+		return &OllamaAgentConfig{}
+	}
+	return c.OllamaAgent
+}
+
+// GetGlobalOllamaAgentWithOptions returns the Ollama agent settings from global config
+func GetGlobalOllamaAgentWithOptions(opts ConfigOptions) (*OllamaAgentConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &OllamaAgentConfig{}, err
+	}
+	return config.GetOllamaAgent(), nil
+}
+
+// UpdateGlobalOllamaAgentWithOptions applies edit to the Ollama agent settings in global config
+func UpdateGlobalOllamaAgentWithOptions(opts ConfigOptions, edit func(agent *OllamaAgentConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	agent := *config.GetOllamaAgent()
+	if agent.Models != nil {
+		models := make(map[string]string, len(agent.Models))
+		for k, v := range agent.Models {
+			models[k] = v
+		}
+		agent.Models = models
+	}
+	edit(&agent)
+	if err := config.SetOllamaAgent(&agent); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalOllamaAgentWithOptions removes the Ollama agent settings from global config
+func ClearGlobalOllamaAgentWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.OllamaAgent = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,96 @@
+package session
+
+import "fmt"
+
+// Completion ownership policies, set with completion_ownership in the
+// global config. Either way, an agent run that completes a ball records
+// its session on the ball, and only that session counts the ball as
+// progress.
+const (
+	CompletionOwnershipKeep     = "keep"     // Other sessions keep their tags on the ball (default)
+	CompletionOwnershipTransfer = "transfer" // Other sessions' tags are removed from the ball
+)
+
+// ValidateCompletionOwnership checks if a completion ownership policy is valid
+func ValidateCompletionOwnership(policy string) bool {
+	return policy == CompletionOwnershipKeep || policy == CompletionOwnershipTransfer
+}
+
+// SetCompletionOwnership sets what happens to a completed ball's other sessions
+func (c *Config) SetCompletionOwnership(policy string) error {
+	if !ValidateCompletionOwnership(policy) {
+		return fmt.Errorf("invalid completion ownership policy %q (must be %s or %s)", policy, CompletionOwnershipKeep, CompletionOwnershipTransfer)
+	}
+	c.CompletionOwnership = policy
+	return nil
+}
+
+// GetCompletionOwnership returns the completion ownership policy, defaulting to keep
+func (c *Config) GetCompletionOwnership() string {
+	if c.CompletionOwnership == "" {
+		return CompletionOwnershipKeep
+	}
+	return c.CompletionOwnership
+}
+
+// UpdateGlobalCompletionOwnershipWithOptions sets the completion ownership policy in global config
+func UpdateGlobalCompletionOwnershipWithOptions(opts ConfigOptions, policy string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetCompletionOwnership(policy); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalCompletionOwnershipWithOptions removes the completion ownership policy from global config
+func ClearGlobalCompletionOwnershipWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.CompletionOwnership = ""
+	return config.SaveWithOptions(opts)
+}
+
+// ClaimCompletion records that sessionID's agent run completed the ball.
+// With transfer, the ball's tags naming any other of sessionIDs are
+// removed and remembered in ReleasedSessions; the removed tags are
+// returned.
+func (b *Ball) ClaimCompletion(sessionID string, sessionIDs []string, transfer bool) []string {
+	b.CompletedBySession = sessionID
+	if !transfer {
+		return nil
+	}
+
+	others := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		if id != sessionID {
+			others[id] = true
+		}
+	}
+	var removed []string
+	kept := make([]string, 0, len(b.Tags))
+	for _, tag := range b.Tags {
+		if others[tag] {
+			removed = append(removed, tag)
+		} else {
+			kept = append(kept, tag)
+		}
+	}
+	b.Tags = kept
+	b.ReleasedSessions = append(b.ReleasedSessions, removed...)
+	return removed
+}
+
+// CountsTowardSession reports whether the ball counts as the session's
+// progress: it's tagged with the session, and no other session's run
+// completed it
+func (b *Ball) CountsTowardSession(sessionID string) bool {
+	if b.CompletedBySession != "" && b.CompletedBySession != sessionID {
+		return false
+	}
+	return b.HasTag(sessionID)
+}
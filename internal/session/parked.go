@@ -0,0 +1,41 @@
+package session
+
+import "time"
+
+// Reasons a ball is parked: open, but set aside until something outside
+// the agent loop changes, so it's kept out of the list of actionable work
+const (
+	ParkedSnoozed   = "snoozed"   // Recurring instance that isn't due yet
+	ParkedEscalated = "escalated" // Blocked and handed to a human owner (juggle escalate)
+	ParkedWaiting   = "waiting"   // Waiting on dependencies that aren't done
+)
+
+// waitingBlockedReason is the TUI's preset blocked reason for a ball held
+// up by other work
+const waitingBlockedReason = "Waiting for dependency"
+
+// ParkedReason returns why the ball is parked at now, or "" if it's
+// actionable. deps resolves dependencies (see NewDependencyIndex); nil skips
+// the dependency check.
+func (b *Ball) ParkedReason(now time.Time, deps *DependencyIndex) string {
+	switch b.State {
+	case StateComplete, StateResearched:
+		return ""
+	case StateBlocked:
+		if b.IsEscalated() {
+			return ParkedEscalated
+		}
+		if b.BlockedReason == waitingBlockedReason {
+			return ParkedWaiting
+		}
+		return ""
+	case StatePending:
+		if b.IsRecurring() && b.Recurrence.DueAt.After(now) {
+			return ParkedSnoozed
+		}
+		if deps != nil && !deps.DependenciesSatisfied(b) {
+			return ParkedWaiting
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// windowsPaths reports whether paths follow Windows rules: backslash or
+// slash separators, drive letters, and case-insensitive names
+var windowsPaths = runtime.GOOS == "windows"
+
+// CleanPath returns path in the form juggle stores and compares project
+// directories in. On Windows, slashes become backslashes and the drive
+// letter is upper-cased, so c:/src/app and C:\src\app are the same project.
+func CleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if windowsPaths {
+		path = strings.ReplaceAll(path, "/", `\`)
+		if hasDriveLetter(path) {
+			path = strings.ToUpper(path[:1]) + path[1:]
+		}
+	}
+	return filepath.Clean(path)
+}
+
+// SamePath reports whether a and b name the same directory. Windows paths
+// compare case-insensitively.
+func SamePath(a, b string) bool {
+	return pathKey(a) == pathKey(b)
+}
+
+// pathKey returns a key for path that is equal for paths naming the same
+// directory, for maps and lookups
+func pathKey(path string) string {
+	path = CleanPath(path)
+	if windowsPaths {
+		return strings.ToLower(path)
+	}
+	return path
+}
+
+// hasDriveLetter reports whether path starts with a drive letter like C:
+func hasDriveLetter(path string) bool {
+	if len(path) < 2 || path[1] != ':' {
+		return false
+	}
+	c := path[0]
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// NormalizeNewlines converts CRLF and lone CR line endings to LF, for text
+// written by Windows editors or checked out with core.autocrlf
+func NormalizeNewlines(text string) string {
+	if !strings.Contains(text, "\r") {
+		return text
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// withWindowsPaths makes path helpers follow Windows rules for the test
+func withWindowsPaths(t *testing.T) {
+	t.Helper()
+	old := windowsPaths
+	windowsPaths = true
+	t.Cleanup(func() { windowsPaths = old })
+}
+
+func TestWindowsPathRules(t *testing.T) {
+	withWindowsPaths(t)
+
+	if got := CleanPath("c:/src/app"); got != `C:\src\app` {
+		t.Errorf("Expected backslashes and an upper-case drive letter, got %q", got)
+	}
+	for _, pair := range [][2]string{
+		{`C:\src\app`, "c:/src/app"},
+		{`C:\Src\App`, `c:\src\app`},
+		{`\\server\share\app`, "//server/share/app"},
+	} {
+		if !SamePath(pair[0], pair[1]) {
+			t.Errorf("Expected %q and %q to be the same path", pair[0], pair[1])
+		}
+	}
+	if SamePath(`C:\src\app`, `D:\src\app`) {
+		t.Error("Expected paths on different drives to differ")
+	}
+
+	// A project registered with one spelling is found with another
+	var config Config
+	if !config.RegisterProject("c:/src/app", "app") || config.RegisterProject(`C:\SRC\APP`, "") {
+		t.Fatal("Expected the project registered once")
+	}
+	if config.Projects[0].Path != `C:\src\app` || config.ProjectName(`C:\Src\App`) != "app" {
+		t.Errorf("Unexpected registry %+v", config.Projects)
+	}
+	if !config.UnregisterProject(`c:\src\app`) || len(config.Projects) != 0 {
+		t.Errorf("Expected the project unregistered, got %+v", config.Projects)
+	}
+}
+
+func TestSamePathOnThisPlatform(t *testing.T) {
+	dir := t.TempDir()
+	if !SamePath(dir, dir+string(filepath.Separator)) || !SamePath(dir, filepath.Join(dir, "sub", "..")) {
+		t.Errorf("Expected unclean spellings of %q to match", dir)
+	}
+	if runtime.GOOS != "windows" {
+		if SamePath("/src/App", "/src/app") {
+			t.Error("Expected case-sensitive paths off Windows")
+		}
+		return
+	}
+
+	// Windows: cwd and stored paths may differ in drive letter case and separators
+	mixed := filepath.ToSlash(dir)
+	mixed = string(mixed[0]^0x20) + mixed[1:]
+	if !SamePath(dir, mixed) {
+		t.Errorf("Expected %q and %q to be the same path", dir, mixed)
+	}
+	store, err := NewStore(mixed)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if store.ProjectDir() != CleanPath(dir) {
+		t.Errorf("Expected the store's project dir cleaned, got %q", store.ProjectDir())
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".juggle")); err != nil {
+		t.Errorf("Expected the store under %q: %v", dir, err)
+	}
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	for in, want := range map[string]string{
+		"a\r\nb\r\n": "a\nb\n",
+		"a\rb":       "a\nb",
+		"a\nb":       "a\nb",
+	} {
+		if got := NormalizeNewlines(in); got != want {
+			t.Errorf("NormalizeNewlines(%q) = %q, want %q", in, got, want)
+		}
+	}
+	merged := MergeProgress([]byte("one\r\n"), []byte("one\r\ntwo\r\n"), []byte("one\nthree\n"))
+	if string(merged) != "one\ntwo\nthree\n" {
+		t.Errorf("Expected CRLF progress merged by line, got %q", merged)
+	}
+}
@@ -0,0 +1,219 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ProgressAuthor is who wrote a progress entry
+type ProgressAuthor string
+
+const (
+	ProgressAuthorHuman ProgressAuthor = "human"
+	ProgressAuthorAgent ProgressAuthor = "agent"
+)
+
+// ProgressCategory is the kind of a progress entry
+type ProgressCategory string
+
+const (
+	ProgressCategoryWork     ProgressCategory = "work"     // Something was done
+	ProgressCategoryDecision ProgressCategory = "decision" // A choice was made, and why
+	ProgressCategoryBlocker  ProgressCategory = "blocker"  // Something is stopping the work
+)
+
+// progressTimeFormat is how entry timestamps are shown, and how free-form
+// entries written before structured progress were stamped
+const progressTimeFormat = "2006-01-02 15:04:05"
+
+// ProgressEntry is one record in a session's progress log. Entries are
+// stored one JSON object per line in progress.txt.
+type ProgressEntry struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Author    ProgressAuthor   `json:"author,omitempty"`
+	Category  ProgressCategory `json:"category"`
+	BallID    string           `json:"ball_id,omitempty"`
+	Text      string           `json:"text"`
+
+	// legacy marks entries parsed from free-form text, which are shown as
+	// they were written
+	legacy bool
+}
+
+// ValidProgressCategories lists the progress categories, in display order
+func ValidProgressCategories() []ProgressCategory {
+	return []ProgressCategory{ProgressCategoryWork, ProgressCategoryDecision, ProgressCategoryBlocker}
+}
+
+// ParseProgressCategory parses a category name, case-insensitively
+func ParseProgressCategory(s string) (ProgressCategory, error) {
+	for _, category := range ValidProgressCategories() {
+		if strings.EqualFold(s, string(category)) {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("invalid category %q: must be work, decision or blocker", s)
+}
+
+// ParseProgressAuthor parses an author name, case-insensitively
+func ParseProgressAuthor(s string) (ProgressAuthor, error) {
+	for _, author := range []ProgressAuthor{ProgressAuthorHuman, ProgressAuthorAgent} {
+		if strings.EqualFold(s, string(author)) {
+			return author, nil
+		}
+	}
+	return "", fmt.Errorf("invalid author %q: must be human or agent", s)
+}
+
+// String renders the entry as a line of the progress log:
+// [2006-01-02 15:04:05] [blocker] (agent) my-app-3: text
+func (e ProgressEntry) String() string {
+	if e.legacy {
+		if e.Timestamp.IsZero() {
+			return e.Text
+		}
+		return "[" + e.Timestamp.Format(progressTimeFormat) + "] " + e.Text
+	}
+	var b strings.Builder
+	b.WriteString("[" + e.Timestamp.Local().Format(progressTimeFormat) + "] [" + string(e.Category) + "] ")
+	if e.Author != "" {
+		b.WriteString("(" + string(e.Author) + ") ")
+	}
+	if e.BallID != "" {
+		b.WriteString(e.BallID + ": ")
+	}
+	b.WriteString(e.Text)
+	return b.String()
+}
+
+// FormatProgress renders entries as the text of a progress log, one entry
+// per line
+func FormatProgress(entries []ProgressEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.String() + "\n")
+	}
+	return b.String()
+}
+
+// legacyTimestamp matches the stamp free-form entries started with before
+// progress was structured
+var legacyTimestamp = regexp.MustCompile(`^\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\] ?`)
+
+// legacyBlockerPrefixes start free-form entries that reported a blocker,
+// including the agent loop's own [TIMEOUT], [CRASH] and similar notes
+var legacyBlockerPrefixes = []string{"blocked", "[blocked", "[timeout]", "[crash]", "[brownout]", "[rate_limit]", "[overload_529]"}
+
+// legacyTags start the agent loop's free-form notes, which begin a new
+// entry even without a timestamp
+var legacyTags = []string{"[TIMEOUT]", "[CRASH]", "[BROWNOUT]", "[RATE_LIMIT]", "[OVERLOAD_529]", "[QUIET_HOURS]", "[HUMAN]"}
+
+// ParseProgress parses a progress log. Lines holding a JSON record are
+// structured entries. Anything else is free-form text from before progress
+// was structured, or appended by hand: a line stamped [2006-01-02 15:04:05]
+// starts a new work entry and unstamped lines continue the entry before it.
+// Free-form entries reporting a blocker are categorized as blockers.
+func ParseProgress(data string) []ProgressEntry {
+	data = strings.TrimSuffix(NormalizeNewlines(data), "\n")
+	if data == "" {
+		return nil
+	}
+
+	var entries []ProgressEntry
+	for _, line := range strings.Split(data, "\n") {
+		if entry, ok := parseProgressRecord(line); ok {
+			entries = append(entries, entry)
+			continue
+		}
+
+		stamp := legacyTimestamp.FindStringSubmatch(line)
+		last := len(entries) - 1
+		if stamp == nil && !hasLegacyTag(line) && last >= 0 && entries[last].legacy {
+			entries[last].Text += "\n" + line
+			continue
+		}
+
+		entry := ProgressEntry{Category: ProgressCategoryWork, Text: line, legacy: true}
+		if stamp != nil {
+			if t, err := time.ParseInLocation(progressTimeFormat, stamp[1], time.Local); err == nil {
+				entry.Timestamp, entry.Text = t, line[len(stamp[0]):]
+			}
+		}
+		lower := strings.ToLower(entry.Text)
+		for _, prefix := range legacyBlockerPrefixes {
+			if strings.HasPrefix(lower, prefix) {
+				entry.Category = ProgressCategoryBlocker
+				break
+			}
+		}
+		if strings.HasPrefix(entry.Text, "[HUMAN]") {
+			entry.Author = ProgressAuthorHuman
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseProgressRecord parses a line holding a structured entry
+func parseProgressRecord(line string) (ProgressEntry, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return ProgressEntry{}, false
+	}
+	var entry ProgressEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil || entry.Text == "" {
+		return ProgressEntry{}, false
+	}
+	if entry.Category == "" {
+		entry.Category = ProgressCategoryWork
+	}
+	return entry, true
+}
+
+// hasLegacyTag reports whether line starts with one of the agent loop's
+// free-form note tags
+func hasLegacyTag(line string) bool {
+	for _, tag := range legacyTags {
+		if strings.HasPrefix(line, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProgressFilter selects progress entries. Empty fields match everything.
+type ProgressFilter struct {
+	Category ProgressCategory
+	Author   ProgressAuthor
+	BallID   string // Matches the entry's ball, or free-form text mentioning it
+}
+
+// Matches reports whether entry passes the filter
+func (f ProgressFilter) Matches(entry ProgressEntry) bool {
+	if f.Category != "" && entry.Category != f.Category {
+		return false
+	}
+	if f.Author != "" && entry.Author != f.Author {
+		return false
+	}
+	if f.BallID != "" {
+		if entry.BallID != "" {
+			return strings.EqualFold(entry.BallID, f.BallID)
+		}
+		return strings.Contains(entry.Text, f.BallID)
+	}
+	return true
+}
+
+// FilterProgress returns the entries that pass the filter
+func FilterProgress(entries []ProgressEntry, filter ProgressFilter) []ProgressEntry {
+	var matched []ProgressEntry
+	for _, entry := range entries {
+		if filter.Matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
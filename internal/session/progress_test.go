@@ -0,0 +1,85 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProgressMixesLegacyAndStructured(t *testing.T) {
+	data := "[2024-01-01 10:00:00] Started auth\n" +
+		"  with notes on a second line\n" +
+		"[TIMEOUT] Iteration 3 timed out\n" +
+		`{"timestamp":"2024-01-02T09:00:00Z","author":"human","category":"decision","ball_id":"app-3","text":"Use PKCE"}` + "\n" +
+		"echoed by hand\n" +
+		"[2024-01-03 08:00:00] Blocked on app-4: no DB\n"
+
+	entries := ParseProgress(data)
+	if len(entries) != 5 {
+		t.Fatalf("expected 5 entries, got %d: %+v", len(entries), entries)
+	}
+
+	if entries[0].Text != "Started auth\n  with notes on a second line" || entries[0].Category != ProgressCategoryWork {
+		t.Errorf("expected unstamped lines to continue a legacy entry, got %+v", entries[0])
+	}
+	if entries[1].Category != ProgressCategoryBlocker {
+		t.Errorf("expected [TIMEOUT] to start a blocker entry, got %+v", entries[1])
+	}
+	if entries[2].Author != ProgressAuthorHuman || entries[2].Category != ProgressCategoryDecision || entries[2].BallID != "app-3" {
+		t.Errorf("expected the structured record, got %+v", entries[2])
+	}
+	if entries[3].Text != "echoed by hand" || !entries[3].Timestamp.IsZero() {
+		t.Errorf("expected a free-form line after a record to start its own entry, got %+v", entries[3])
+	}
+	if entries[4].Category != ProgressCategoryBlocker || entries[4].Timestamp.IsZero() {
+		t.Errorf("expected a stamped legacy blocker, got %+v", entries[4])
+	}
+
+	// Legacy entries render as they were written
+	rendered := FormatProgress(entries)
+	if !strings.HasPrefix(rendered, "[2024-01-01 10:00:00] Started auth\n  with notes on a second line\n[TIMEOUT] Iteration 3 timed out\n") {
+		t.Errorf("expected legacy text kept as written, got %q", rendered)
+	}
+}
+
+func TestProgressEntryString(t *testing.T) {
+	entry := ProgressEntry{
+		Timestamp: time.Date(2024, 1, 2, 9, 0, 0, 0, time.Local),
+		Author:    ProgressAuthorAgent,
+		Category:  ProgressCategoryBlocker,
+		BallID:    "app-3",
+		Text:      "No DB access",
+	}
+	if got, want := entry.String(), "[2024-01-02 09:00:00] [blocker] (agent) app-3: No DB access"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFilterProgress(t *testing.T) {
+	entries := ParseProgress("Worked on app-4\n" +
+		`{"timestamp":"2024-01-02T09:00:00Z","author":"agent","category":"blocker","ball_id":"app-3","text":"Stuck"}` + "\n" +
+		`{"timestamp":"2024-01-02T10:00:00Z","author":"human","category":"decision","text":"Drop app-3"}` + "\n")
+
+	if got := FilterProgress(entries, ProgressFilter{Category: ProgressCategoryBlocker}); len(got) != 1 || got[0].Text != "Stuck" {
+		t.Errorf("expected the blocker, got %+v", got)
+	}
+	if got := FilterProgress(entries, ProgressFilter{Author: ProgressAuthorHuman}); len(got) != 1 || got[0].Text != "Drop app-3" {
+		t.Errorf("expected the human entry, got %+v", got)
+	}
+	// A ball filter matches the entry's ball, or free-form text mentioning it
+	if got := FilterProgress(entries, ProgressFilter{BallID: "app-3"}); len(got) != 2 {
+		t.Errorf("expected 2 entries for app-3, got %+v", got)
+	}
+	if got := FilterProgress(entries, ProgressFilter{BallID: "app-4"}); len(got) != 1 || got[0].Text != "Worked on app-4" {
+		t.Errorf("expected the legacy entry mentioning app-4, got %+v", got)
+	}
+}
+
+func TestParseProgressCategory(t *testing.T) {
+	if category, err := ParseProgressCategory("Blocker"); err != nil || category != ProgressCategoryBlocker {
+		t.Errorf("expected blocker, got %q, %v", category, err)
+	}
+	if _, err := ParseProgressCategory("chore"); err == nil {
+		t.Error("expected an error for an unknown category")
+	}
+}
@@ -0,0 +1,262 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ohare93/juggle/internal/profile"
+)
+
+// ProjectCache is a read-through cache of the balls, archived balls and
+// sessions of many projects, for views that show every project at once.
+// A project's files are only re-read when their size or modification time
+// has changed, or after Invalidate (e.g. on a watcher event), so reloading
+// costs a few stats per project rather than parsing every JSONL file.
+//
+// Loads return a shallow copy of each cached ball and session, so callers
+// can set fields without changing the cache. A nil cache reads every
+// project on each load.
+type ProjectCache struct {
+	mu       sync.Mutex
+	projects map[string]*cachedProject
+}
+
+// cachedProject holds one project's stores and its last loaded data
+type cachedProject struct {
+	store        *Store
+	sessionStore *SessionStore
+
+	ballsStamp    fileStamp
+	balls         []*Ball
+	archivedStamp fileStamp
+	archived      []*Ball
+	sessionsStamp string
+	sessions      []*JuggleSession
+
+	milestones      *MilestoneStore
+	milestonesStamp fileStamp
+	tally           *projectTally // Aggregates of the balls for LoadSummaries, nil until computed
+}
+
+// fileStamp identifies a version of a file. The zero value never matches a
+// loaded file, so a fresh entry is always read.
+type fileStamp struct {
+	loaded  bool
+	exists  bool
+	size    int64
+	modTime int64 // UnixNano, as time.Time doesn't compare reliably with ==
+}
+
+// racyWindow is how recently a file may have changed for its stamp to be
+// untrusted: another write within the filesystem's timestamp granularity
+// could leave the size and modification time the same
+const racyWindow = time.Second
+
+// statFile returns the current stamp of the file at path
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{loaded: true}
+	}
+	return fileStamp{loaded: true, exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// cacheable returns the stamp to keep with data read from the file: itself,
+// or the zero stamp (forcing a re-read next time) if the file changed too
+// recently to be sure a later write would change the stamp
+func (f fileStamp) cacheable() fileStamp {
+	if f.exists && time.Since(time.Unix(0, f.modTime)) < racyWindow {
+		return fileStamp{}
+	}
+	return f
+}
+
+// NewProjectCache creates an empty cache
+func NewProjectCache() *ProjectCache {
+	return &ProjectCache{projects: make(map[string]*cachedProject)}
+}
+
+// Invalidate drops the cached data of a project, so its next load re-reads
+// its files
+func (c *ProjectCache) Invalidate(projectDir string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.projects, projectDir)
+}
+
+// project returns the cache entry for a project, creating its stores on
+// first use. Caller must hold the lock.
+func (c *ProjectCache) project(projectDir string) (*cachedProject, error) {
+	if p, ok := c.projects[projectDir]; ok {
+		return p, nil
+	}
+	store, err := NewStore(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	sessionStore, err := NewSessionStore(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	milestones, err := NewMilestoneStore(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	p := &cachedProject{store: store, sessionStore: sessionStore, milestones: milestones}
+	c.projects[projectDir] = p
+	return p, nil
+}
+
+// LoadBalls loads the active balls of every project, like LoadAllBalls
+func (c *ProjectCache) LoadBalls(projectPaths []string) ([]*Ball, error) {
+	if c == nil {
+		return LoadAllBalls(projectPaths)
+	}
+	defer profile.Start(profile.KindStore, "load_all_balls_cached")()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	allBalls := make([]*Ball, 0)
+	for _, projectPath := range projectPaths {
+		p, err := c.project(projectPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create store for %s: %v\n", projectPath, err)
+			continue
+		}
+
+		if err := p.refreshBalls(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load balls from %s: %v\n", projectPath, err)
+			continue
+		}
+		allBalls = appendBallCopies(allBalls, p.balls)
+	}
+
+	return allBalls, nil
+}
+
+// LoadArchivedBalls loads the archived balls of every project, like the
+// package-level LoadArchivedBalls, skipping archives that can't be read
+func (c *ProjectCache) LoadArchivedBalls(projectPaths []string) ([]*Ball, error) {
+	if c == nil {
+		return LoadArchivedBalls(projectPaths)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	archivedBalls := make([]*Ball, 0)
+	for _, projectPath := range projectPaths {
+		p, err := c.project(projectPath)
+		if err != nil {
+			continue
+		}
+
+		if stamp := statFile(p.store.archivePath); stamp != p.archivedStamp {
+			balls, err := p.store.LoadArchivedBalls()
+			if err != nil {
+				continue
+			}
+			p.archived, p.archivedStamp = balls, stamp.cacheable()
+		}
+		archivedBalls = appendBallCopies(archivedBalls, p.archived)
+	}
+
+	return archivedBalls, nil
+}
+
+// LoadSessions loads the sessions of every project, like LoadAllSessions
+func (c *ProjectCache) LoadSessions(projectPaths []string) ([]*JuggleSession, error) {
+	if c == nil {
+		return LoadAllSessions(projectPaths)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	allSessions := make([]*JuggleSession, 0)
+	for _, projectPath := range projectPaths {
+		p, err := c.project(projectPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create session store for %s: %v\n", projectPath, err)
+			continue
+		}
+
+		if err := p.refreshSessions(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load sessions from %s: %v\n", projectPath, err)
+			continue
+		}
+		for _, sess := range p.sessions {
+			copied := *sess
+			allSessions = append(allSessions, &copied)
+		}
+	}
+
+	return allSessions, nil
+}
+
+// refreshBalls re-reads the project's active balls if their file changed
+func (p *cachedProject) refreshBalls() error {
+	stamp := statFile(p.store.ballsDataPath())
+	if stamp == p.ballsStamp {
+		return nil
+	}
+	balls, err := p.store.LoadBalls()
+	if err != nil {
+		return err
+	}
+	p.balls, p.ballsStamp = balls, stamp.cacheable()
+	p.tally = nil
+	return nil
+}
+
+// refreshSessions re-reads the project's sessions if any session changed
+func (p *cachedProject) refreshSessions() error {
+	stamp := p.sessionStore.sessionsStamp()
+	if stamp != "" && stamp == p.sessionsStamp {
+		return nil
+	}
+	sessions, err := p.sessionStore.ListSessions()
+	if err != nil {
+		return err
+	}
+	p.sessions, p.sessionsStamp = sessions, stamp
+	return nil
+}
+
+// sessionsStamp identifies the current version of every session file. It
+// returns "" (never trusted as unchanged) if the sessions directory can't
+// be read or a session changed too recently to tell later writes apart.
+func (s *SessionStore) sessionsStamp() string {
+	entries, err := os.ReadDir(filepath.Join(s.projectDir, s.config.JuggleDirName, sessionsDir))
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("sessions")
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		stamp := statFile(s.sessionFilePath(entry.Name()))
+		if stamp.cacheable() != stamp {
+			return ""
+		}
+		fmt.Fprintf(&b, "|%s:%d:%d", entry.Name(), stamp.size, stamp.modTime)
+	}
+	return b.String()
+}
+
+// appendBallCopies appends a shallow copy of each ball to dst
+func appendBallCopies(dst, balls []*Ball) []*Ball {
+	for _, ball := range balls {
+		copied := *ball
+		dst = append(dst, &copied)
+	}
+	return dst
+}
@@ -0,0 +1,186 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backdate sets a file's modification time to a fixed time in the past, out
+// of the racy window
+func backdate(t *testing.T, path string) {
+	t.Helper()
+	old := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to backdate %s: %v", path, err)
+	}
+}
+
+func TestProjectCache_ReusesUnchangedProjects(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	ball, _ := NewBall(store.ProjectDir(), "Cached", PriorityMedium)
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+	backdate(t, store.ballsPath)
+
+	cache := NewProjectCache()
+	projects := []string{store.ProjectDir()}
+	balls, err := cache.LoadBalls(projects)
+	if err != nil || len(balls) != 1 || balls[0].Title != "Cached" {
+		t.Fatalf("Expected the ball to load, got %v (%v)", balls, err)
+	}
+
+	// Loads hand out copies, so changing one doesn't change the cache
+	balls[0].Title = "Changed in memory"
+
+	// Rewrite the file with the same size and modification time: the cache
+	// can't tell, so it keeps serving what it read
+	data, _ := os.ReadFile(store.ballsPath)
+	edited := strings.Replace(string(data), `"Cached"`, `"Edited"`, 1)
+	if err := os.WriteFile(store.ballsPath, []byte(edited), 0644); err != nil {
+		t.Fatalf("Failed to rewrite balls: %v", err)
+	}
+	backdate(t, store.ballsPath)
+	balls, _ = cache.LoadBalls(projects)
+	if len(balls) != 1 || balls[0].Title != "Cached" {
+		t.Fatalf("Expected the cached ball, got %+v", balls)
+	}
+
+	// Invalidating (as watcher events do) forces a re-read
+	cache.Invalidate(store.ProjectDir())
+	balls, _ = cache.LoadBalls(projects)
+	if len(balls) != 1 || balls[0].Title != "Edited" {
+		t.Errorf("Expected the ball to be re-read after Invalidate, got %+v", balls)
+	}
+}
+
+func TestProjectCache_ReloadsChangedProjects(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	sessionStore, err := NewSessionStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	cache := NewProjectCache()
+	projects := []string{store.ProjectDir()}
+
+	if balls, _ := cache.LoadBalls(projects); len(balls) != 0 {
+		t.Fatalf("Expected no balls, got %d", len(balls))
+	}
+	ball, _ := NewBall(store.ProjectDir(), "New", PriorityMedium)
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+	if balls, _ := cache.LoadBalls(projects); len(balls) != 1 {
+		t.Fatalf("Expected the new ball, got %d balls", len(balls))
+	}
+
+	ball.MarkComplete("")
+	if err := store.ArchiveBall(ball); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+	if balls, _ := cache.LoadBalls(projects); len(balls) != 0 {
+		t.Errorf("Expected the archived ball gone from active balls, got %d", len(balls))
+	}
+	if archived, _ := cache.LoadArchivedBalls(projects); len(archived) != 1 {
+		t.Errorf("Expected 1 archived ball, got %d", len(archived))
+	}
+
+	if _, err := sessionStore.CreateSession("feature", "First"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if sessions, _ := cache.LoadSessions(projects); len(sessions) != 1 || sessions[0].Description != "First" {
+		t.Fatalf("Expected the new session, got %+v", sessions)
+	}
+	if err := sessionStore.UpdateSessionDescription("feature", "Second"); err != nil {
+		t.Fatalf("Failed to update session: %v", err)
+	}
+	if sessions, _ := cache.LoadSessions(projects); len(sessions) != 1 || sessions[0].Description != "Second" {
+		t.Errorf("Expected the updated session, got %+v", sessions)
+	}
+}
+
+func TestProjectCache_LoadSummaries(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	sessionStore, err := NewSessionStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("release", "Release work"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	milestones, err := NewMilestoneStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create milestone store: %v", err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-24 * time.Hour)
+	if err := milestones.AddMilestone(&Milestone{Name: "v1.0", Due: &due}); err != nil {
+		t.Fatalf("Failed to add milestone: %v", err)
+	}
+
+	late, _ := NewBall(store.ProjectDir(), "Late", PriorityMedium)
+	late.Milestone = "V1.0"
+	running, _ := NewBall(store.ProjectDir(), "Running", PriorityMedium)
+	running.State = StateInProgress
+	running.Milestone = "v1.0"
+	running.LastActivity = now
+	done, _ := NewBall(store.ProjectDir(), "Done", PriorityMedium)
+	done.Milestone = "v1.0"
+	done.MarkComplete("")
+	for _, ball := range []*Ball{late, running, done} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+
+	cache := NewProjectCache()
+	projects := []string{store.ProjectDir()}
+	summaries, err := cache.LoadSummaries(projects, now)
+	if err != nil || len(summaries) != 1 {
+		t.Fatalf("Expected one summary, got %v (%v)", summaries, err)
+	}
+	summary := summaries[0]
+	if summary.States[StatePending] != 1 || summary.States[StateInProgress] != 1 || summary.States[StateComplete] != 1 {
+		t.Errorf("Unexpected state counts: %v", summary.States)
+	}
+	if summary.Open() != 2 || summary.Overdue != 2 {
+		t.Errorf("Expected 2 open balls, both overdue, got %d and %d", summary.Open(), summary.Overdue)
+	}
+	if len(summary.RunningAgents) != 0 {
+		t.Errorf("Expected no running agents, got %v", summary.RunningAgents)
+	}
+
+	// Overdue is counted against the time of each load, from the cached tally
+	if summaries, _ := cache.LoadSummaries(projects, due.Add(-time.Hour)); summaries[0].Overdue != 0 {
+		t.Errorf("Expected nothing overdue before the due date, got %d", summaries[0].Overdue)
+	}
+
+	// Moving the milestone is picked up without the balls changing
+	later := now.Add(24 * time.Hour)
+	if err := milestones.writeMilestones([]*Milestone{{Name: "v1.0", Due: &later}}); err != nil {
+		t.Fatalf("Failed to update milestone: %v", err)
+	}
+	if summaries, _ := cache.LoadSummaries(projects, now); summaries[0].Overdue != 0 {
+		t.Errorf("Expected the moved milestone to clear overdue balls, got %d", summaries[0].Overdue)
+	}
+
+	lock, err := sessionStore.AcquireSessionLock("release")
+	if err != nil {
+		t.Fatalf("Failed to acquire lock: %v", err)
+	}
+	defer lock.Release()
+	if summaries, _ := cache.LoadSummaries(projects, now); strings.Join(summaries[0].RunningAgents, ",") != "release" {
+		t.Errorf("Expected the locked session's agent reported, got %v", summaries[0].RunningAgents)
+	}
+}
@@ -0,0 +1,161 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RegisteredProject is a project in the registry kept in the global config.
+// Cross-project views (--all, the TUI's all-projects mode) only load
+// registered projects that are enabled.
+type RegisteredProject struct {
+	Path     string `json:"path"`
+	Name     string `json:"name,omitempty"`     // Display name, defaults to the directory name
+	Disabled bool   `json:"disabled,omitempty"` // Left out of cross-project views, but kept registered
+}
+
+// DisplayName returns the project's name as shown in listings
+func (p RegisteredProject) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return filepath.Base(p.Path)
+}
+
+// Project registry statuses, as reported by RegisteredProject.Status
+const (
+	ProjectOK       = "ok"
+	ProjectDisabled = "disabled"
+	ProjectMissing  = "missing"   // The directory is gone, e.g. moved or renamed
+	ProjectNoJuggle = "no-juggle" // The directory exists but has no .juggle
+)
+
+// Status returns whether the project can be loaded, and if not why
+func (p RegisteredProject) Status() string {
+	if p.Disabled {
+		return ProjectDisabled
+	}
+	if info, err := os.Stat(p.Path); err != nil || !info.IsDir() {
+		return ProjectMissing
+	}
+	if _, err := os.Stat(filepath.Join(p.Path, ".juggle")); err != nil {
+		return ProjectNoJuggle
+	}
+	return ProjectOK
+}
+
+// RegisteredProjects returns the project registry, in registration order.
+// Paths only listed in search_paths, e.g. by an older juggle, are included
+// as enabled projects.
+func (c *Config) RegisteredProjects() []RegisteredProject {
+	projects := make([]RegisteredProject, 0, len(c.Projects)+len(c.SearchPaths))
+	seen := make(map[string]bool)
+	for _, project := range c.Projects {
+		key := pathKey(project.Path)
+		if !seen[key] {
+			seen[key] = true
+			projects = append(projects, project)
+		}
+	}
+	for _, path := range c.SearchPaths {
+		key := pathKey(path)
+		if !seen[key] {
+			seen[key] = true
+			projects = append(projects, RegisteredProject{Path: path})
+		}
+	}
+	return projects
+}
+
+// syncProjects folds search_paths into the registry, and makes search_paths
+// list the registered paths again so older juggle versions still find them
+func (c *Config) syncProjects() {
+	c.Projects = c.RegisteredProjects()
+	c.mirrorSearchPaths()
+}
+
+// mirrorSearchPaths sets search_paths to the registered paths
+func (c *Config) mirrorSearchPaths() {
+	c.SearchPaths = make([]string, len(c.Projects))
+	for i, project := range c.Projects {
+		c.SearchPaths[i] = project.Path
+	}
+}
+
+// FindProject returns the registered project at path, or nil
+func (c *Config) FindProject(path string) *RegisteredProject {
+	c.syncProjects()
+	for i := range c.Projects {
+		if SamePath(c.Projects[i].Path, path) {
+			return &c.Projects[i]
+		}
+	}
+	return nil
+}
+
+// ProjectName returns the display name of the project at path, registered
+// or not
+func (c *Config) ProjectName(path string) string {
+	for _, project := range c.RegisteredProjects() {
+		if SamePath(project.Path, path) {
+			return project.DisplayName()
+		}
+	}
+	return filepath.Base(path)
+}
+
+// RegisterProject adds a project to the registry. It returns false if the
+// path is already registered, leaving it as it was.
+func (c *Config) RegisterProject(path, name string) bool {
+	if c.FindProject(path) != nil {
+		return false
+	}
+	path = CleanPath(path)
+	c.Projects = append(c.Projects, RegisteredProject{Path: path, Name: name})
+	c.SearchPaths = append(c.SearchPaths, path)
+	return true
+}
+
+// UnregisterProject removes a project from the registry
+func (c *Config) UnregisterProject(path string) bool {
+	if c.FindProject(path) == nil {
+		return false
+	}
+	kept := c.Projects[:0]
+	for _, project := range c.Projects {
+		if !SamePath(project.Path, path) {
+			kept = append(kept, project)
+		}
+	}
+	c.Projects = kept
+	c.mirrorSearchPaths()
+	return true
+}
+
+// MoveProject points a registered project at its new directory, keeping its
+// name and enabled state, e.g. after the directory was moved or renamed
+func (c *Config) MoveProject(oldPath, newPath string) error {
+	if !SamePath(oldPath, newPath) && c.FindProject(newPath) != nil {
+		return fmt.Errorf("project already registered: %s", newPath)
+	}
+	project := c.FindProject(oldPath)
+	if project == nil {
+		return fmt.Errorf("project not registered: %s", oldPath)
+	}
+	project.Path = CleanPath(newPath)
+	c.mirrorSearchPaths()
+	return nil
+}
+
+// AddSearchPath registers a project, returning false if it already is.
+// Kept for callers from before the registry; new code uses RegisterProject.
+func (c *Config) AddSearchPath(path string) bool {
+	return c.RegisterProject(path, "")
+}
+
+// RemoveSearchPath removes a project from the registry.
+// Kept for callers from before the registry; new code uses UnregisterProject.
+func (c *Config) RemoveSearchPath(path string) bool {
+	return c.UnregisterProject(path)
+}
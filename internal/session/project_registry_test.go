@@ -0,0 +1,88 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectRegistry(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	web := filepath.Join(root, "web")
+	for _, dir := range []string{api, web} {
+		if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+			t.Fatalf("Failed to create project: %v", err)
+		}
+	}
+
+	// A config from before the registry only has search_paths
+	var config Config
+	if err := json.Unmarshal([]byte(`{"search_paths": ["`+api+`"]}`), &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if len(config.Projects) != 1 || config.Projects[0].Path != api {
+		t.Fatalf("Expected search_paths migrated to the registry, got %+v", config.Projects)
+	}
+
+	if !config.RegisterProject(web, "frontend") || config.RegisterProject(web, "other") {
+		t.Fatal("Expected web registered once")
+	}
+	if name := config.ProjectName(web); name != "frontend" {
+		t.Errorf("Expected the display name, got %q", name)
+	}
+	if name := config.ProjectName(api); name != "api" {
+		t.Errorf("Expected the directory name, got %q", name)
+	}
+
+	config.FindProject(api).Disabled = true
+	projects, _ := DiscoverProjects(&config)
+	if len(projects) != 1 || projects[0] != web {
+		t.Errorf("Expected the disabled project skipped, got %v", projects)
+	}
+
+	// The web project's directory is renamed
+	moved := filepath.Join(root, "web-app")
+	if err := os.Rename(web, moved); err != nil {
+		t.Fatalf("Failed to rename project: %v", err)
+	}
+	if status := config.FindProject(web).Status(); status != ProjectMissing {
+		t.Errorf("Expected the renamed project missing, got %s", status)
+	}
+	if projects, _ := DiscoverProjects(&config); len(projects) != 0 {
+		t.Errorf("Expected the missing project skipped, got %v", projects)
+	}
+	if err := config.MoveProject(web, api); err == nil {
+		t.Error("Expected moving onto a registered project to fail")
+	}
+	if err := config.MoveProject(web, moved); err != nil {
+		t.Fatalf("MoveProject failed: %v", err)
+	}
+	if project := config.FindProject(moved); project == nil || project.Name != "frontend" {
+		t.Errorf("Expected the name kept after moving, got %+v", project)
+	}
+	if projects, _ := DiscoverProjects(&config); len(projects) != 1 || projects[0] != moved {
+		t.Errorf("Expected the moved project loaded, got %v", projects)
+	}
+
+	// The registry round-trips, with search_paths kept for older versions
+	data, err := json.Marshal(&config)
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	var reloaded Config
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if len(reloaded.Projects) != 2 || !reloaded.Projects[0].Disabled || reloaded.Projects[1].Name != "frontend" {
+		t.Errorf("Unexpected registry after reload: %+v", reloaded.Projects)
+	}
+	if len(reloaded.SearchPaths) != 2 || reloaded.SearchPaths[1] != moved {
+		t.Errorf("Expected search_paths to mirror the registry, got %v", reloaded.SearchPaths)
+	}
+
+	if !reloaded.UnregisterProject(api) || reloaded.FindProject(api) != nil || len(reloaded.SearchPaths) != 1 {
+		t.Errorf("Expected api unregistered, got %+v", reloaded.Projects)
+	}
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/profile"
+)
+
+// ProjectSummary is one project's row on the all-projects dashboard
+type ProjectSummary struct {
+	ProjectDir    string            `json:"project_dir"`
+	States        map[BallState]int `json:"states"`         // Active balls by state
+	Overdue       int               `json:"overdue"`        // Open balls past their recurrence's or milestone's due date
+	RunningAgents []string          `json:"running_agents"` // Sessions an agent run holds the lock of ("_all" for 'agent run all')
+	LastActivity  time.Time         `json:"last_activity"`  // Latest change to a ball, archiving included
+}
+
+// Name returns the project's display name
+func (s *ProjectSummary) Name() string {
+	return filepath.Base(s.ProjectDir)
+}
+
+// Open returns the number of balls not yet complete or researched
+func (s *ProjectSummary) Open() int {
+	return s.States[StatePending] + s.States[StateInProgress] + s.States[StateBlocked]
+}
+
+// projectTally is what a summary keeps from a project's balls and
+// milestones, recomputed only when they're re-read. Overdue balls depend on
+// the time, so their due dates are kept rather than a count.
+type projectTally struct {
+	states       map[BallState]int
+	lastActivity time.Time
+	dueAt        []time.Time // Earliest due date of each open ball that has one
+}
+
+// LoadSummaries returns a summary of each project, in the order given. The
+// counts come from the cached balls and are only recomputed when a
+// project's balls or milestones change; running agents are checked on each
+// load, from the session locks. A nil cache reads every project afresh.
+func (c *ProjectCache) LoadSummaries(projectPaths []string, now time.Time) ([]*ProjectSummary, error) {
+	if c == nil {
+		return NewProjectCache().LoadSummaries(projectPaths, now)
+	}
+	defer profile.Start(profile.KindStore, "load_project_summaries")()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	summaries := make([]*ProjectSummary, 0, len(projectPaths))
+	for _, projectPath := range projectPaths {
+		p, err := c.project(projectPath)
+		if err != nil {
+			continue
+		}
+		tally, err := p.loadTally()
+		if err != nil {
+			continue
+		}
+
+		summary := &ProjectSummary{
+			ProjectDir:    p.store.ProjectDir(),
+			States:        make(map[BallState]int, len(tally.states)),
+			RunningAgents: []string{},
+			LastActivity:  tally.lastActivity,
+		}
+		for state, count := range tally.states {
+			summary.States[state] = count
+		}
+		for _, due := range tally.dueAt {
+			if due.Before(now) {
+				summary.Overdue++
+			}
+		}
+		if archived := statFile(p.store.archivePath); archived.exists {
+			if modTime := time.Unix(0, archived.modTime); modTime.After(summary.LastActivity) {
+				summary.LastActivity = modTime
+			}
+		}
+
+		if err := p.refreshSessions(); err == nil {
+			ids := []string{"_all"}
+			for _, sess := range p.sessions {
+				ids = append(ids, sess.ID)
+			}
+			for _, id := range ids {
+				if locked, _ := p.sessionStore.IsLocked(id); locked {
+					summary.RunningAgents = append(summary.RunningAgents, id)
+				}
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// loadTally returns the project's tally, recomputing it if its balls or
+// milestones changed since it was last computed. Caller must hold the lock.
+func (p *cachedProject) loadTally() (*projectTally, error) {
+	if err := p.refreshBalls(); err != nil {
+		return nil, err
+	}
+	stamp := statFile(p.milestones.milestonesFilePath())
+	if p.tally != nil && stamp == p.milestonesStamp {
+		return p.tally, nil
+	}
+	milestones, err := p.milestones.LoadMilestones()
+	if err != nil {
+		return nil, err
+	}
+	p.milestonesStamp = stamp.cacheable()
+
+	due := make(map[string]time.Time)
+	for _, milestone := range milestones {
+		if milestone.Due != nil {
+			due[strings.ToLower(milestone.Name)] = *milestone.Due
+		}
+	}
+	tally := &projectTally{states: make(map[BallState]int)}
+	for _, ball := range p.balls {
+		tally.states[ball.State]++
+		if ball.LastActivity.After(tally.lastActivity) {
+			tally.lastActivity = ball.LastActivity
+		}
+		if ball.State == StateComplete || ball.State == StateResearched {
+			continue
+		}
+		var dueAt time.Time
+		if ball.Recurrence != nil {
+			dueAt = ball.Recurrence.DueAt
+		}
+		if milestoneDue, ok := due[strings.ToLower(ball.Milestone)]; ok && ball.Milestone != "" &&
+			(dueAt.IsZero() || milestoneDue.Before(dueAt)) {
+			dueAt = milestoneDue
+		}
+		if !dueAt.IsZero() {
+			tally.dueAt = append(tally.dueAt, dueAt)
+		}
+	}
+	p.tally = tally
+	return tally, nil
+}
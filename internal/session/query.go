@@ -0,0 +1,177 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BallQuery is a parsed ball filter such as "state:pending tag:old-session".
+//
+// A query is a list of space-separated terms, all of which must match:
+//   - state:<state>        ball state (pending, in_progress, blocked, complete, researched)
+//   - tag:<tag>            ball has the tag; session:<id> is the same, since sessions are tags
+//   - priority:<priority>  ball priority (low, medium, high, urgent)
+//   - any other word       case-insensitive match against the title
+//
+// A term may list values separated by commas to match any of them
+// (state:pending,blocked), and a leading - negates it (-tag:wip).
+type BallQuery struct {
+	terms []queryTerm
+}
+
+// queryTerm is one term of a BallQuery
+type queryTerm struct {
+	key    string   // "state", "tag", "priority", or "" for title text
+	values []string // Any one must match
+	negate bool
+}
+
+// ParseBallQuery parses a query string into a BallQuery
+func ParseBallQuery(query string) (*BallQuery, error) {
+	q := &BallQuery{}
+	for _, field := range strings.Fields(query) {
+		term := queryTerm{}
+		if strings.HasPrefix(field, "-") && len(field) > 1 {
+			term.negate = true
+			field = field[1:]
+		}
+
+		key, value, hasKey := strings.Cut(field, ":")
+		if !hasKey {
+			term.values = []string{strings.ToLower(field)}
+			q.terms = append(q.terms, term)
+			continue
+		}
+
+		term.key = strings.ToLower(key)
+		if term.key == "session" {
+			term.key = "tag"
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				term.values = append(term.values, v)
+			}
+		}
+		if len(term.values) == 0 {
+			return nil, fmt.Errorf("query term %q has no value", field)
+		}
+
+		for _, v := range term.values {
+			switch term.key {
+			case "state":
+				if !ValidateBallState(v) {
+					return nil, fmt.Errorf("invalid state in query: %s (must be pending|in_progress|blocked|complete|researched)", v)
+				}
+			case "priority":
+				if !ValidatePriority(v) {
+					return nil, fmt.Errorf("invalid priority in query: %s (must be low|medium|high|urgent)", v)
+				}
+			case "tag":
+			default:
+				return nil, fmt.Errorf("unknown query key %q (use state, tag, session, or priority)", key)
+			}
+		}
+		q.terms = append(q.terms, term)
+	}
+
+	if len(q.terms) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+	return q, nil
+}
+
+// Matches returns true if the ball matches every term of the query
+func (q *BallQuery) Matches(ball *Ball) bool {
+	for _, term := range q.terms {
+		if term.matches(ball) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if the ball matches any of the term's values
+func (t queryTerm) matches(ball *Ball) bool {
+	for _, v := range t.values {
+		switch t.key {
+		case "state":
+			if string(ball.State) == v {
+				return true
+			}
+		case "priority":
+			if string(ball.Priority) == v {
+				return true
+			}
+		case "tag":
+			for _, tag := range ball.Tags {
+				if tag == v {
+					return true
+				}
+			}
+		default:
+			if strings.Contains(strings.ToLower(ball.Title), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TagChange describes how a bulk tag operation changes one ball's tags
+type TagChange struct {
+	BallID  string   `json:"ball_id"`
+	Title   string   `json:"title"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Tags    []string `json:"tags"` // Tags after the change
+}
+
+// RetagBalls adds and removes tags on every ball matching query, in a single
+// locked rewrite of the balls file. With dryRun the changes are computed but
+// not written. Balls whose tags wouldn't change are left out of the result.
+func (s *Store) RetagBalls(query *BallQuery, add, remove []string, dryRun bool) ([]TagChange, error) {
+	_, unlock, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]TagChange, 0)
+	for _, ball := range balls {
+		if !query.Matches(ball) {
+			continue
+		}
+
+		change := TagChange{BallID: ball.ID, Title: ball.Title}
+		for _, tag := range remove {
+			if ball.RemoveTag(tag) {
+				change.Removed = append(change.Removed, tag)
+			}
+		}
+		for _, tag := range add {
+			before := len(ball.Tags)
+			ball.AddTag(tag)
+			if len(ball.Tags) > before {
+				change.Added = append(change.Added, tag)
+			}
+		}
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		change.Tags = append([]string{}, ball.Tags...)
+		changes = append(changes, change)
+	}
+
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+	if err := s.writeBallsUnlocked(balls); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
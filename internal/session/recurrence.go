@@ -0,0 +1,410 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecurrenceCatchUp bounds how many missed occurrences are skipped when
+// catching a schedule up to the present
+const maxRecurrenceCatchUp = 10000
+
+// Recurrence is a ball's schedule. The ball is one instance of a recurring
+// task: when it's completed, or when its schedule fires again while it's
+// still open, a fresh pending instance carrying the schedule is created and
+// this one stops recurring. Exactly one of Cron and Every is set.
+type Recurrence struct {
+	Cron  string    `json:"cron,omitempty"`  // Five-field cron expression, e.g. "0 9 * * MON"
+	Every string    `json:"every,omitempty"` // Fixed interval, e.g. "7d", "2w", "12h"
+	DueAt time.Time `json:"due_at"`          // When this instance is due
+}
+
+// NewRecurrence returns a recurrence for a cron expression or an interval,
+// due at its first occurrence after now
+func NewRecurrence(cron, every string, now time.Time) (*Recurrence, error) {
+	r := &Recurrence{Cron: strings.TrimSpace(cron), Every: strings.TrimSpace(every)}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	due, err := r.Next(now)
+	if err != nil {
+		return nil, err
+	}
+	r.DueAt = due
+	return r, nil
+}
+
+// Validate checks that exactly one schedule is set and that it parses
+func (r *Recurrence) Validate() error {
+	switch {
+	case r.Cron == "" && r.Every == "":
+		return fmt.Errorf("a recurrence needs a cron expression or an interval")
+	case r.Cron != "" && r.Every != "":
+		return fmt.Errorf("a recurrence takes a cron expression or an interval, not both")
+	case r.Cron != "":
+		_, err := parseCron(r.Cron)
+		return err
+	default:
+		_, err := parseEvery(r.Every)
+		return err
+	}
+}
+
+// Label returns the schedule as written, e.g. "every 7d" or "cron 0 9 * * MON"
+func (r *Recurrence) Label() string {
+	if r.Cron != "" {
+		return "cron " + r.Cron
+	}
+	return "every " + r.Every
+}
+
+// Next returns the first occurrence of the schedule strictly after t
+func (r *Recurrence) Next(t time.Time) (time.Time, error) {
+	if r.Cron != "" {
+		schedule, err := parseCron(r.Cron)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return schedule.next(t)
+	}
+	interval, err := parseEvery(r.Every)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(interval), nil
+}
+
+// Fired returns the latest occurrence after DueAt that is not after now, or
+// the zero time if the schedule hasn't fired again since this instance was
+// due
+func (r *Recurrence) Fired(now time.Time) (time.Time, error) {
+	if r.DueAt.IsZero() {
+		return time.Time{}, nil
+	}
+	var fired time.Time
+	due := r.DueAt
+	for i := 0; i < maxRecurrenceCatchUp; i++ {
+		next, err := r.Next(due)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.After(now) {
+			break
+		}
+		fired, due = next, next
+	}
+	return fired, nil
+}
+
+// DueLabel describes when the instance is due relative to now, e.g.
+// "Mon Oct 19 09:00 (in 3d)" or "Wed Oct 14 09:00 (overdue 1d)"
+func (r *Recurrence) DueLabel(now time.Time) string {
+	label := r.DueAt.Local().Format("Mon Jan 2 15:04")
+	if r.DueAt.After(now) {
+		return label + " (in " + roughDuration(r.DueAt.Sub(now)) + ")"
+	}
+	return label + " (overdue " + roughDuration(now.Sub(r.DueAt)) + ")"
+}
+
+// roughDuration formats a duration in its largest whole unit: "45m", "5h", "3d"
+func roughDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// IsRecurring returns true if the ball carries a schedule
+func (b *Ball) IsRecurring() bool {
+	return b.Recurrence != nil
+}
+
+// NextInstance returns a fresh pending copy of a recurring ball, carrying
+// its schedule and due at dueAt, and stops the ball itself from recurring.
+// The copy keeps the ball's description and agent hints but not its
+// progress: no state, dependencies, next action, output or test results.
+func (b *Ball) NextInstance(dueAt time.Time) (*Ball, error) {
+	if !b.IsRecurring() {
+		return nil, fmt.Errorf("ball %s is not recurring", b.ShortID())
+	}
+	next, err := NewBall(b.WorkingDir, b.Title, b.Priority)
+	if err != nil {
+		return nil, err
+	}
+	next.Title = b.Title
+	next.Context = b.Context
+	next.AcceptanceCriteria = append([]string(nil), b.AcceptanceCriteria...)
+	next.Tags = append([]string{}, b.Tags...)
+	next.ModelSize = b.ModelSize
+	next.AgentProvider = b.AgentProvider
+	next.ModelOverride = b.ModelOverride
+	next.WatchGlobs = append([]string(nil), b.WatchGlobs...)
+
+	recurrence := *b.Recurrence
+	recurrence.DueAt = dueAt
+	next.Recurrence = &recurrence
+
+	b.Recurrence = nil
+	b.UpdateActivity()
+	return next, nil
+}
+
+// Recur materializes the next instance of a recurring ball that was just
+// completed, due at the first occurrence after both its due time and now.
+// The new instance is appended to the store; the caller saves the completed
+// ball, which no longer recurs. Returns nil if the ball isn't recurring.
+func (s *Store) Recur(ball *Ball, now time.Time) (*Ball, error) {
+	if !ball.IsRecurring() {
+		return nil, nil
+	}
+	from := now
+	if ball.Recurrence.DueAt.After(now) {
+		from = ball.Recurrence.DueAt
+	}
+	due, err := ball.Recurrence.Next(from)
+	if err != nil {
+		return nil, err
+	}
+	recurrence := ball.Recurrence
+	next, err := ball.NextInstance(due)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AppendBall(next); err != nil {
+		ball.Recurrence = recurrence
+		return nil, fmt.Errorf("failed to create next instance of %s: %w", ball.ShortID(), err)
+	}
+	return next, nil
+}
+
+// MaterializeRecurrences creates the next instance of every recurring ball
+// that is complete but not yet archived, or whose schedule has fired again
+// while it's still open. Returns the new instances.
+func (s *Store) MaterializeRecurrences(now time.Time) ([]*Ball, error) {
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return nil, err
+	}
+
+	var created []*Ball
+	for _, ball := range balls {
+		if !ball.IsRecurring() {
+			continue
+		}
+		var next *Ball
+		if ball.State == StateComplete || ball.State == StateResearched {
+			if next, err = s.Recur(ball, now); err != nil {
+				return created, err
+			}
+		} else {
+			fired, err := ball.Recurrence.Fired(now)
+			if err != nil {
+				return created, fmt.Errorf("ball %s: %w", ball.ShortID(), err)
+			}
+			if fired.IsZero() {
+				continue
+			}
+			if next, err = ball.NextInstance(fired); err != nil {
+				return created, err
+			}
+			if err := s.AppendBall(next); err != nil {
+				return created, fmt.Errorf("failed to create next instance of %s: %w", ball.ShortID(), err)
+			}
+		}
+		if err := s.UpdateBall(ball); err != nil {
+			return created, fmt.Errorf("failed to update ball %s: %w", ball.ShortID(), err)
+		}
+		created = append(created, next)
+	}
+	return created, nil
+}
+
+// parseEvery parses an interval such as "7d", "2w", "12h" or "90m"
+func parseEvery(s string) (time.Duration, error) {
+	var interval time.Duration
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q (expected e.g. 7d, 2w, 12h)", s)
+		}
+		interval = time.Duration(days) * 24 * time.Hour
+	} else if n, ok := strings.CutSuffix(s, "w"); ok {
+		weeks, err := strconv.Atoi(n)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q (expected e.g. 7d, 2w, 12h)", s)
+		}
+		interval = time.Duration(weeks) * 7 * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q (expected e.g. 7d, 2w, 12h)", s)
+		}
+		interval = d
+	}
+	if interval < time.Minute {
+		return 0, fmt.Errorf("invalid interval %q (must be at least a minute)", s)
+	}
+	return interval, nil
+}
+
+// cronSchedule is a parsed five-field cron expression: minute, hour, day of
+// month, month and day of week
+type cronSchedule struct {
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool
+	months   [13]bool
+	weekdays [7]bool
+	// As in cron, when both day fields are restricted a day matches either
+	anyDay     bool
+	anyWeekday bool
+}
+
+// cronField describes the range and names of one cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // Names for min, min+1, ... (months and weekdays)
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 6, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// parseCron parses a five-field cron expression. Fields take *, numbers,
+// names (JAN, MON), ranges (1-5), lists (1,15) and steps (*/15); 7 is
+// accepted for Sunday.
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q (expected 5 fields: minute hour day month weekday)", expr)
+	}
+
+	s := &cronSchedule{anyDay: fields[2] == "*", anyWeekday: fields[4] == "*"}
+	targets := []func(int){
+		func(v int) { s.minutes[v] = true },
+		func(v int) { s.hours[v] = true },
+		func(v int) { s.days[v] = true },
+		func(v int) { s.months[v] = true },
+		func(v int) { s.weekdays[v%7] = true },
+	}
+	for i, field := range fields {
+		spec := cronFields[i]
+		if i == 4 {
+			spec.max = 7 // Sunday as 7
+		}
+		if err := parseCronField(field, spec, targets[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+	}
+	return s, nil
+}
+
+// parseCronField parses one comma-separated cron field, calling set for
+// each value it covers
+func parseCronField(field string, spec cronField, set func(int)) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step %q in %s", stepPart, spec.name)
+			}
+			step = n
+		}
+
+		lo, hi := spec.min, spec.max
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loPart, spec); err != nil {
+				return err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = parseCronValue(hiPart, spec); err != nil {
+					return err
+				}
+			} else if hasStep {
+				hi = spec.max // "5/15" means from 5 to the end in steps of 15
+			}
+			if hi < lo {
+				return fmt.Errorf("invalid range %q in %s", rangePart, spec.name)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set(v)
+		}
+	}
+	return nil
+}
+
+// parseCronValue parses a number or name within a cron field's range
+func parseCronValue(s string, spec cronField) (int, error) {
+	for i, name := range spec.names {
+		if strings.EqualFold(s, name) {
+			return spec.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < spec.min || v > spec.max {
+		return 0, fmt.Errorf("invalid %s %q (expected %d-%d)", spec.name, s, spec.min, spec.max)
+	}
+	return v, nil
+}
+
+// matchesDay returns true if the schedule runs on t's date
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	if !s.months[t.Month()] {
+		return false
+	}
+	day, weekday := s.days[t.Day()], s.weekdays[t.Weekday()]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// next returns the first minute strictly after t the schedule matches, in
+// t's location
+func (s *cronSchedule) next(t time.Time) (time.Time, error) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every valid schedule matches within a leap-year cycle
+	for i := 0; i < 366*5; i++ {
+		if s.matchesDay(t) {
+			for hour := t.Hour(); hour < 24; hour++ {
+				if !s.hours[hour] {
+					continue
+				}
+				minute := 0
+				if hour == t.Hour() {
+					minute = t.Minute()
+				}
+				for ; minute < 60; minute++ {
+					if s.minutes[minute] {
+						return time.Date(t.Year(), t.Month(), t.Day(), hour, minute, 0, 0, t.Location()), nil
+					}
+				}
+			}
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Time{}, fmt.Errorf("cron schedule never runs")
+}
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrence_Next(t *testing.T) {
+	// Thursday, 2026-10-15 10:30 UTC
+	now := time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		cron, every string
+		want        time.Time
+	}{
+		{cron: "0 9 * * MON", want: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		{cron: "*/15 * * * *", want: time.Date(2026, 10, 15, 10, 45, 0, 0, time.UTC)},
+		{cron: "30 10 15 * *", want: time.Date(2026, 11, 15, 10, 30, 0, 0, time.UTC)},
+		{cron: "0 0 1 jan *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{cron: "0 12 * * 1-5", want: time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)},
+		{cron: "0 8 * * 7", want: time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 20th or a Saturday)
+		{cron: "0 0 20 * SAT", want: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{every: "7d", want: now.Add(7 * 24 * time.Hour)},
+		{every: "2w", want: now.Add(14 * 24 * time.Hour)},
+		{every: "12h", want: now.Add(12 * time.Hour)},
+	}
+	for _, tt := range tests {
+		r := &Recurrence{Cron: tt.cron, Every: tt.every}
+		got, err := r.Next(now)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", r.Label(), err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: expected %v, got %v", r.Label(), tt.want, got)
+		}
+	}
+
+	for _, r := range []*Recurrence{
+		{},
+		{Cron: "0 9 * * MON", Every: "7d"},
+		{Cron: "0 9 * *"},
+		{Cron: "60 * * * *"},
+		{Cron: "0 0 31 FEB *"},
+		{Every: "7"},
+		{Every: "30s"},
+	} {
+		if err := r.Validate(); err == nil {
+			if _, err := r.Next(now); err == nil {
+				t.Errorf("Expected %+v to be rejected", r)
+			}
+		}
+	}
+}
+
+func TestMaterializeRecurrences(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	now := time.Date(2026, 10, 15, 10, 30, 0, 0, time.UTC)
+
+	// Completed early: the next instance is due a week after this one
+	done, _ := NewBall(store.ProjectDir(), "Weekly review", PriorityMedium)
+	done.Tags = []string{"ops"}
+	done.Recurrence = &Recurrence{Every: "7d", DueAt: now.Add(24 * time.Hour)}
+	done.MarkComplete("")
+
+	// Still open two weeks past due: the schedule fired, catch up to now
+	missed, _ := NewBall(store.ProjectDir(), "Rotate keys", PriorityHigh)
+	missed.Recurrence = &Recurrence{Every: "7d", DueAt: now.Add(-15 * 24 * time.Hour)}
+
+	// Open and not yet due again: nothing to do
+	open, _ := NewBall(store.ProjectDir(), "Check backups", PriorityLow)
+	open.Recurrence = &Recurrence{Cron: "0 9 * * MON", DueAt: now.Add(-time.Hour)}
+
+	for _, ball := range []*Ball{done, missed, open} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+
+	created, err := store.MaterializeRecurrences(now)
+	if err != nil {
+		t.Fatalf("MaterializeRecurrences failed: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("Expected 2 new instances, got %d", len(created))
+	}
+	byTitle := map[string]*Ball{}
+	for _, ball := range created {
+		byTitle[ball.Title] = ball
+	}
+
+	review := byTitle["Weekly review"]
+	if review == nil || review.State != StatePending || !review.Recurrence.DueAt.Equal(now.Add(8*24*time.Hour)) {
+		t.Errorf("Expected a pending review due in 8 days, got %+v", review)
+	}
+	if review != nil && (len(review.Tags) != 1 || review.ID == done.ID) {
+		t.Errorf("Expected a fresh ball with the tags copied, got %+v", review)
+	}
+	keys := byTitle["Rotate keys"]
+	if keys == nil || !keys.Recurrence.DueAt.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("Expected keys due at the latest missed occurrence, got %+v", keys)
+	}
+
+	balls, _ := store.LoadBalls()
+	if len(balls) != 5 {
+		t.Fatalf("Expected 5 balls, got %d", len(balls))
+	}
+	for _, ball := range balls {
+		if (ball.ID == done.ID || ball.ID == missed.ID) && ball.IsRecurring() {
+			t.Errorf("Expected %s to stop recurring", ball.Title)
+		}
+		if ball.ID == open.ID && !ball.IsRecurring() {
+			t.Errorf("Expected %s to keep recurring", ball.Title)
+		}
+	}
+
+	// Running again creates nothing new
+	if created, _ := store.MaterializeRecurrences(now); len(created) != 0 {
+		t.Errorf("Expected no new instances on a second run, got %d", len(created))
+	}
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reopen returns a complete or researched ball to pending, for work that
+// has regressed. The completion is cleared, the session tags removed when
+// another session's run completed it are restored, and a line recording
+// the reopen (with the note, if any) is appended to its context. The
+// restored session tags are returned.
+func (b *Ball) Reopen(note string, now time.Time) ([]string, error) {
+	if b.State != StateComplete && b.State != StateResearched {
+		return nil, fmt.Errorf("ball %s is not complete (state: %s)", b.ShortID(), b.State)
+	}
+
+	line := "Reopened " + now.Format("2006-01-02")
+	if b.CompletedAt != nil {
+		line += fmt.Sprintf(" (completed %s)", b.CompletedAt.Format("2006-01-02"))
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		line += ": " + note
+	}
+	if b.CompletionNote != "" {
+		line += "\nCompletion note was: " + b.CompletionNote
+	}
+	if b.Context != "" {
+		line = b.Context + "\n\n" + line
+	}
+	b.Context = line
+
+	var restored []string
+	for _, tag := range b.ReleasedSessions {
+		if !b.HasTag(tag) {
+			b.AddTag(tag)
+			restored = append(restored, tag)
+		}
+	}
+	b.ReleasedSessions = nil
+
+	b.CompletedAt = nil
+	b.CompletionNote = ""
+	if err := b.SetState(StatePending); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// ReopenBall reopens a complete or researched ball by full or short ID,
+// restoring it from the archive if it was archived. It returns the
+// reopened ball, the session tags restored to it, and whether it came
+// from the archive.
+func (s *Store) ReopenBall(id, note string) (*Ball, []string, bool, error) {
+	ball, archived, err := s.GetBallAnywhere(id)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var restored []string
+	reopen := func(b *Ball) error {
+		var err error
+		restored, err = b.Reopen(note, time.Now())
+		return err
+	}
+	if archived {
+		ball, err = s.unarchiveBall(ball.ID, reopen)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return ball, restored, true, nil
+	}
+
+	if err := reopen(ball); err != nil {
+		return nil, nil, false, err
+	}
+	if err := s.UpdateBall(ball); err != nil {
+		return nil, nil, false, fmt.Errorf("failed to update ball: %w", err)
+	}
+	return ball, restored, false, nil
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"math"
+	"time"
+)
+
+// charsPerToken is the rough size of a prompt token, for estimates only
+const charsPerToken = 4
+
+// RunBudget is the envelope of an agent run: how much work it has, how
+// long it may take, and how big its prompt is. It's shown before a run is
+// launched so its settings can be adjusted first.
+type RunBudget struct {
+	ActionableBalls int           // Balls the agent can work on
+	MaxIterations   int           // Iterations the run may use
+	Timeout         time.Duration // Per iteration; 0 means none
+	IterDelay       time.Duration // Wait between iterations
+	PromptChars     int           // Size of the first iteration's prompt
+
+	// From the session's past runs with at least one iteration
+	HistoryRuns          int
+	AvgIteration         time.Duration // Wall time per iteration, excluding rate limit waits
+	AvgBallsPerIteration float64       // Balls completed per iteration
+}
+
+// UseHistory fills in the averages from the session's past agent runs.
+// Runs that never got through an iteration are left out.
+func (b *RunBudget) UseHistory(records []*AgentRunRecord) {
+	var runs, iterations, balls int
+	var elapsed time.Duration
+	for _, record := range records {
+		if record.Iterations <= 0 || record.EndedAt.IsZero() {
+			continue
+		}
+		runTime := record.EndedAt.Sub(record.StartedAt) - record.TotalWaitTime
+		if runTime <= 0 {
+			continue
+		}
+		runs++
+		iterations += record.Iterations
+		balls += record.BallsComplete
+		elapsed += runTime
+	}
+	b.HistoryRuns = runs
+	if iterations == 0 {
+		b.AvgIteration = 0
+		b.AvgBallsPerIteration = 0
+		return
+	}
+	b.AvgIteration = elapsed / time.Duration(iterations)
+	b.AvgBallsPerIteration = float64(balls) / float64(iterations)
+}
+
+// PromptTokens approximates the prompt's size in tokens
+func (b RunBudget) PromptTokens() int {
+	return (b.PromptChars + charsPerToken - 1) / charsPerToken
+}
+
+// ExpectedIterations estimates how many iterations the run will use: the
+// actionable balls at the session's historical completion rate (one per
+// iteration without history), capped at MaxIterations
+func (b RunBudget) ExpectedIterations() int {
+	expected := b.ActionableBalls
+	if b.AvgBallsPerIteration > 0 {
+		expected = int(math.Ceil(float64(b.ActionableBalls) / b.AvgBallsPerIteration))
+	}
+	return max(min(expected, b.MaxIterations), 1)
+}
+
+// ExpectedDuration estimates the run's length from the session's average
+// iteration time, or 0 without history
+func (b RunBudget) ExpectedDuration() time.Duration {
+	if b.AvgIteration == 0 {
+		return 0
+	}
+	iterations := b.ExpectedIterations()
+	return time.Duration(iterations)*b.AvgIteration + time.Duration(iterations-1)*b.IterDelay
+}
+
+// MaxDuration is the longest the run can take when every iteration hits
+// the timeout, not counting rate limit waits; 0 means unbounded
+func (b RunBudget) MaxDuration() time.Duration {
+	if b.Timeout == 0 || b.MaxIterations <= 0 {
+		return 0
+	}
+	return time.Duration(b.MaxIterations)*b.Timeout + time.Duration(b.MaxIterations-1)*b.IterDelay
+}
+
+// MaxPromptTokens approximates the prompt tokens sent over the whole run,
+// if every iteration's prompt is the size of the first
+func (b RunBudget) MaxPromptTokens() int {
+	return b.PromptTokens() * max(b.MaxIterations, 1)
+}
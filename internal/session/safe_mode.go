@@ -0,0 +1,70 @@
+package session
+
+import "fmt"
+
+// Safe-mode revert policies, set with safe_mode_revert in the global config
+const (
+	SafeModeRevertAsk  = "ask"  // Ask before reverting a failed run (default)
+	SafeModeRevertAuto = "auto" // Revert failed runs without asking
+)
+
+// Safe-mode decisions recorded in agent history
+const (
+	SafeModeKept         = "kept"          // The run succeeded; its changes were kept
+	SafeModeReverted     = "reverted"      // The run failed and was reverted to the snapshot
+	SafeModeDeclined     = "declined"      // The run failed but the revert was declined
+	SafeModeRevertFailed = "revert_failed" // The run failed and reverting it failed
+)
+
+// SafeModeOutcome records what safe mode did at the end of an agent run
+type SafeModeOutcome struct {
+	Snapshot       string `json:"snapshot"`                  // VCS snapshot taken before the run
+	Failure        string `json:"failure,omitempty"`         // Why the run counted as failed
+	Decision       string `json:"decision"`                  // kept, reverted, declined, or revert_failed
+	FailedSnapshot string `json:"failed_snapshot,omitempty"` // State just before reverting, kept for recovery
+	Error          string `json:"error,omitempty"`           // Why reverting failed
+}
+
+// ValidateSafeModeRevert checks if a safe-mode revert policy is valid
+func ValidateSafeModeRevert(policy string) bool {
+	return policy == SafeModeRevertAsk || policy == SafeModeRevertAuto
+}
+
+// SetSafeModeRevert sets what safe mode does when a run fails
+func (c *Config) SetSafeModeRevert(policy string) error {
+	if !ValidateSafeModeRevert(policy) {
+		return fmt.Errorf("invalid safe mode revert policy %q (must be %s or %s)", policy, SafeModeRevertAsk, SafeModeRevertAuto)
+	}
+	c.SafeModeRevert = policy
+	return nil
+}
+
+// GetSafeModeRevert returns the safe-mode revert policy, defaulting to ask
+func (c *Config) GetSafeModeRevert() string {
+	if c.SafeModeRevert == "" {
+		return SafeModeRevertAsk
+	}
+	return c.SafeModeRevert
+}
+
+// UpdateGlobalSafeModeRevertWithOptions sets the safe-mode revert policy in global config
+func UpdateGlobalSafeModeRevertWithOptions(opts ConfigOptions, policy string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetSafeModeRevert(policy); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalSafeModeRevertWithOptions removes the safe-mode revert policy from global config
+func ClearGlobalSafeModeRevertWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.SafeModeRevert = ""
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,250 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockTime is a time of day in a specific location, parsed from "HH:MM" or "HH:MM <TZ>"
+type clockTime struct {
+	hour   int
+	minute int
+	loc    *time.Location
+}
+
+// parseClockTime parses a time of day such as "00:00", "9:30", or "00:00 UTC".
+// The optional zone is an IANA location name; without it the local zone is used.
+func parseClockTime(s string) (clockTime, error) {
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return clockTime{}, fmt.Errorf("invalid time %q (expected HH:MM or HH:MM <zone>)", s)
+	}
+
+	loc := time.Local
+	if len(fields) == 2 {
+		l, err := time.LoadLocation(fields[1])
+		if err != nil {
+			return clockTime{}, fmt.Errorf("invalid time zone %q: %w", fields[1], err)
+		}
+		loc = l
+	}
+
+	// Parsed strictly, so trailing text such as "9:30pm" is refused rather
+	// than dropped
+	parsed, err := time.Parse("15:04", fields[0])
+	if err != nil {
+		return clockTime{}, fmt.Errorf("invalid time %q (expected HH:MM, hour 0-23, minute 0-59)", fields[0])
+	}
+
+	return clockTime{hour: parsed.Hour(), minute: parsed.Minute(), loc: loc}, nil
+}
+
+// on returns this time of day on the same calendar date as t (in the clock's location)
+func (c clockTime) on(t time.Time) time.Time {
+	local := t.In(c.loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), c.hour, c.minute, 0, 0, c.loc)
+}
+
+// next returns the first occurrence of this time of day strictly after t
+func (c clockTime) next(t time.Time) time.Time {
+	candidate := c.on(t)
+	if !candidate.After(t) {
+		candidate = c.on(t.In(c.loc).AddDate(0, 0, 1))
+	}
+	return candidate
+}
+
+// quietWindow is a daily window during which agents should not run
+type quietWindow struct {
+	start clockTime
+	end   clockTime
+}
+
+// parseQuietWindow parses a window such as "09:00-17:00" or "22:00-06:00 Europe/Oslo".
+// Windows that end before they start wrap past midnight.
+func parseQuietWindow(s string) (quietWindow, error) {
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return quietWindow{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM or HH:MM-HH:MM <zone>)", s)
+	}
+
+	parts := strings.Split(fields[0], "-")
+	if len(parts) != 2 {
+		return quietWindow{}, fmt.Errorf("invalid quiet hours %q (expected HH:MM-HH:MM)", s)
+	}
+
+	zone := ""
+	if len(fields) == 2 {
+		zone = " " + fields[1]
+	}
+	start, err := parseClockTime(parts[0] + zone)
+	if err != nil {
+		return quietWindow{}, err
+	}
+	end, err := parseClockTime(parts[1] + zone)
+	if err != nil {
+		return quietWindow{}, err
+	}
+	if start.hour == end.hour && start.minute == end.minute {
+		return quietWindow{}, fmt.Errorf("invalid quiet hours %q (start and end are the same)", s)
+	}
+
+	return quietWindow{start: start, end: end}, nil
+}
+
+// endIfActive returns when the window ends if t falls inside it
+func (w quietWindow) endIfActive(t time.Time) (time.Time, bool) {
+	start := w.start.on(t)
+	end := w.end.on(t)
+
+	if end.After(start) {
+		// Same-day window, e.g. 09:00-17:00
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+		return time.Time{}, false
+	}
+
+	// Window wraps midnight, e.g. 22:00-06:00
+	if !t.Before(start) {
+		return w.end.next(t), true
+	}
+	if t.Before(end) {
+		return end, true
+	}
+	return time.Time{}, false
+}
+
+// ValidateQuotaResetTime checks that a quota reset time (e.g. "00:00 UTC") is valid
+func ValidateQuotaResetTime(s string) error {
+	_, err := parseClockTime(s)
+	return err
+}
+
+// ValidateQuietHours checks that a quiet hours window (e.g. "09:00-17:00") is valid
+func ValidateQuietHours(s string) error {
+	_, err := parseQuietWindow(s)
+	return err
+}
+
+// NextQuotaReset returns the earliest configured quota reset after now.
+// Returns false if no reset times are configured or none are valid.
+func NextQuotaReset(now time.Time, resets []string) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for _, r := range resets {
+		c, err := parseClockTime(r)
+		if err != nil {
+			continue
+		}
+		next := c.next(now)
+		if !found || next.Before(earliest) {
+			earliest = next
+			found = true
+		}
+	}
+	return earliest, found
+}
+
+// QuietHoursEnd returns when the current quiet period ends if now falls inside any
+// configured quiet window. Overlapping or back-to-back windows are merged.
+func QuietHoursEnd(now time.Time, windows []string) (time.Time, bool) {
+	parsed := make([]quietWindow, 0, len(windows))
+	for _, w := range windows {
+		qw, err := parseQuietWindow(w)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, qw)
+	}
+
+	current := now
+	active := false
+	// Follow chained windows (bounded so a misconfiguration covering the whole day can't loop forever)
+	for i := 0; i < len(parsed)+1; i++ {
+		extended := false
+		for _, qw := range parsed {
+			if end, ok := qw.endIfActive(current); ok && end.After(current) {
+				current = end
+				active = true
+				extended = true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return current, active
+}
+
+// SetQuotaResetTimes sets the known times of day when the usage quota resets.
+func (c *Config) SetQuotaResetTimes(resets []string) error {
+	for _, r := range resets {
+		if err := ValidateQuotaResetTime(r); err != nil {
+			return err
+		}
+	}
+	c.QuotaResetTimes = resets
+	return nil
+}
+
+// SetQuietHours sets the daily windows during which agents should not run.
+func (c *Config) SetQuietHours(windows []string) error {
+	for _, w := range windows {
+		if err := ValidateQuietHours(w); err != nil {
+			return err
+		}
+	}
+	c.QuietHours = windows
+	return nil
+}
+
+// ClearSchedule removes quota reset times and quiet hours.
+func (c *Config) ClearSchedule() {
+	c.QuotaResetTimes = nil
+	c.QuietHours = nil
+}
+
+// GetGlobalScheduleWithOptions returns the quota reset times and quiet hours from global config
+func GetGlobalScheduleWithOptions(opts ConfigOptions) (resets, quietHours []string, err error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config.QuotaResetTimes, config.QuietHours, nil
+}
+
+// UpdateGlobalQuotaResetTimesWithOptions updates the quota reset times in global config
+func UpdateGlobalQuotaResetTimesWithOptions(opts ConfigOptions, resets []string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetQuotaResetTimes(resets); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// UpdateGlobalQuietHoursWithOptions updates the quiet hours in global config
+func UpdateGlobalQuietHoursWithOptions(opts ConfigOptions, windows []string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetQuietHours(windows); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalScheduleWithOptions removes quota reset times and quiet hours from global config
+func ClearGlobalScheduleWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.ClearSchedule()
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,210 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in scrub rules
+const (
+	ScrubRuleAPIKeys = "api_keys"
+	ScrubRuleEmails  = "emails"
+)
+
+// ScrubConfig configures redaction of secrets from agent prompts and from
+// the agent output juggle saves. Scrubbing is off unless this is set.
+type ScrubConfig struct {
+	Rules    []string `json:"rules,omitempty"`    // Built-in rules to apply (empty = all of them)
+	Patterns []string `json:"patterns,omitempty"` // Extra regexes to redact
+}
+
+// scrubSecretGroup names the submatch to redact when a pattern only
+// partly matches a secret (e.g. the value in "API_KEY=...")
+const scrubSecretGroup = "secret"
+
+// builtinScrubPatterns lists the regexes behind each built-in rule
+var builtinScrubPatterns = map[string][]string{
+	ScrubRuleAPIKeys: {
+		`sk-(?:ant-)?[A-Za-z0-9_-]{20,}`, // Anthropic/OpenAI
+		`\bgh[pousr]_[A-Za-z0-9]{36,}`,   // GitHub tokens
+		`\bgithub_pat_[A-Za-z0-9_]{22,}`, // GitHub fine-grained tokens
+		`\bAKIA[0-9A-Z]{16}\b`,           // AWS access key IDs
+		`\bxox[abprs]-[A-Za-z0-9-]{10,}`, // Slack tokens
+		`\bAIza[0-9A-Za-z_-]{35}`,        // Google API keys
+		`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)-----END [A-Z ]*PRIVATE KEY-----`,
+		`(?i)\b(?:api[_-]?key|secret|token|password|passwd)["']?\s*[:=]\s*["']?(?P<secret>[^\s"'\[][^\s"']{7,})`,
+	},
+	ScrubRuleEmails: {
+		`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`,
+	},
+}
+
+// ScrubRules returns the names of the built-in scrub rules
+func ScrubRules() []string {
+	return []string{ScrubRuleAPIKeys, ScrubRuleEmails}
+}
+
+// ScrubReport counts redactions per rule. Custom patterns are reported
+// under the pattern itself. The redacted text is never recorded.
+type ScrubReport map[string]int
+
+// Total returns the number of redactions across all rules
+func (r ScrubReport) Total() int {
+	total := 0
+	for _, n := range r {
+		total += n
+	}
+	return total
+}
+
+// String summarizes the report, e.g. "2 api_keys, 1 emails"
+func (r ScrubReport) String() string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%d %s", r[name], name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Merge adds the counts of another report
+func (r ScrubReport) Merge(other ScrubReport) {
+	for name, n := range other {
+		r[name] += n
+	}
+}
+
+type scrubRule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// Scrubber redacts secrets from text. A nil Scrubber leaves text unchanged.
+type Scrubber struct {
+	rules []scrubRule
+}
+
+// NewScrubber compiles the configured rules. Returns nil when scrubbing is
+// not configured.
+func NewScrubber(config *ScrubConfig) (*Scrubber, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	rules := config.Rules
+	if len(rules) == 0 {
+		rules = ScrubRules()
+	}
+	s := &Scrubber{}
+	for _, name := range rules {
+		for _, pattern := range builtinScrubPatterns[name] {
+			s.rules = append(s.rules, scrubRule{name: name, re: regexp.MustCompile(pattern)})
+		}
+	}
+	for _, pattern := range config.Patterns {
+		s.rules = append(s.rules, scrubRule{name: pattern, re: regexp.MustCompile(pattern)})
+	}
+	return s, nil
+}
+
+// Validate checks the rule names and custom patterns
+func (c *ScrubConfig) Validate() error {
+	for _, name := range c.Rules {
+		if _, ok := builtinScrubPatterns[name]; !ok {
+			return fmt.Errorf("unknown scrub rule %q (valid: %s)", name, strings.Join(ScrubRules(), ", "))
+		}
+	}
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid scrub pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Scrub returns text with every match replaced by a [REDACTED:<rule>]
+// marker, counting redactions in report (which may be nil)
+func (s *Scrubber) Scrub(text string, report ScrubReport) string {
+	if s == nil {
+		return text
+	}
+	for _, rule := range s.rules {
+		text = rule.scrub(text, report)
+	}
+	return text
+}
+
+func (r scrubRule) scrub(text string, report ScrubReport) string {
+	matches := r.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	label := r.name
+	if _, ok := builtinScrubPatterns[label]; !ok {
+		label = "pattern"
+	}
+	group := r.re.SubexpIndex(scrubSecretGroup)
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 && m[2*group] >= 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString("[REDACTED:" + label + "]")
+		last = end
+	}
+	b.WriteString(text[last:])
+	if report != nil {
+		report[r.name] += len(matches)
+	}
+	return b.String()
+}
+
+// SetScrub validates and stores the scrub settings
+func (c *Config) SetScrub(scrub *ScrubConfig) error {
+	if err := scrub.Validate(); err != nil {
+		return err
+	}
+	c.Scrub = scrub
+	return nil
+}
+
+// UpdateGlobalScrubWithOptions applies edit to the scrub settings in global
+// config, enabling scrubbing if it was off
+func UpdateGlobalScrubWithOptions(opts ConfigOptions, edit func(scrub *ScrubConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	scrub := ScrubConfig{}
+	if config.Scrub != nil {
+		scrub.Rules = append([]string(nil), config.Scrub.Rules...)
+		scrub.Patterns = append([]string(nil), config.Scrub.Patterns...)
+	}
+	edit(&scrub)
+	if err := config.SetScrub(&scrub); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalScrubWithOptions turns scrubbing off in global config
+func ClearGlobalScrubWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.Scrub = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,260 @@
+package session
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fields a search match can be found in
+const (
+	SearchFieldID          = "id"
+	SearchFieldTitle       = "title"
+	SearchFieldContext     = "context"
+	SearchFieldCriteria    = "acceptance"
+	SearchFieldTags        = "tags"
+	SearchFieldNextAction  = "next"
+	SearchFieldBlocked     = "blocked"
+	SearchFieldCompletion  = "completion"
+	SearchFieldDescription = "description"
+	SearchFieldProgress    = "progress"
+)
+
+// searchSnippetWidth is the longest snippet shown around a match
+const searchSnippetWidth = 100
+
+// SearchQuery describes a full-text search across projects. Text is matched
+// case-insensitively. An empty Text lists the balls that pass the filters:
+// incomplete ones, or any in States (archived too, for complete).
+type SearchQuery struct {
+	Text    string
+	States  []BallState // Only balls in these states (empty = any); excludes sessions
+	Session string      // Only balls in this session, and the session itself
+	Project string      // Only projects whose directory name or path matches
+}
+
+// SearchMatch is one place the text was found, as a one-line snippet with
+// the byte range of the match in it
+type SearchMatch struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+}
+
+// SearchResult is a ball or a session with the places the text was found.
+// Exactly one of Ball and Session is set.
+type SearchResult struct {
+	ProjectDir string         `json:"project_dir"`
+	Ball       *Ball          `json:"ball,omitempty"`
+	Archived   bool           `json:"archived,omitempty"`
+	Session    *JuggleSession `json:"session,omitempty"`
+	Matches    []SearchMatch  `json:"matches,omitempty"`
+}
+
+// Search finds the balls (active and archived) and sessions in the given
+// projects that contain the query text. Results are grouped by project:
+// active balls, then archived balls, then sessions.
+func Search(projectPaths []string, q SearchQuery) ([]*SearchResult, error) {
+	results := make([]*SearchResult, 0)
+	for _, projectPath := range projectPaths {
+		if !q.matchesProject(projectPath) {
+			continue
+		}
+		store, err := NewStore(projectPath)
+		if err != nil {
+			continue
+		}
+
+		// Without text, the archive is only listed when asked for
+		opts := ListOptions{IncludeArchived: q.Text != "" || q.wantsState(StateComplete) && len(q.States) > 0}
+		if err := store.EachBall(opts, func(ball *Ball, archived bool) bool {
+			if result := q.searchBall(ball, archived); result != nil {
+				results = append(results, result)
+			}
+			return true
+		}); err != nil {
+			return nil, err
+		}
+
+		if q.Text == "" || len(q.States) > 0 {
+			continue
+		}
+		sessionStore, err := NewSessionStore(projectPath)
+		if err != nil {
+			continue
+		}
+		sessions, err := sessionStore.ListSessions()
+		if err != nil {
+			continue
+		}
+		for _, sess := range sessions {
+			if q.Session != "" && sess.ID != q.Session {
+				continue
+			}
+			progress, _ := sessionStore.LoadProgress(sess.ID)
+			if result := q.searchSession(sess, progress); result != nil {
+				result.ProjectDir = projectPath
+				results = append(results, result)
+			}
+		}
+	}
+	return results, nil
+}
+
+// matchesProject returns true if the project passes the Project filter
+func (q SearchQuery) matchesProject(projectDir string) bool {
+	if q.Project == "" {
+		return true
+	}
+	return filepath.Base(projectDir) == q.Project || filepath.Clean(projectDir) == filepath.Clean(q.Project)
+}
+
+// wantsState returns true if balls in state pass the States filter
+func (q SearchQuery) wantsState(state BallState) bool {
+	if len(q.States) == 0 {
+		return true
+	}
+	for _, s := range q.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// searchBall returns the ball's matches, or nil if it doesn't match
+func (q SearchQuery) searchBall(ball *Ball, archived bool) *SearchResult {
+	if !q.wantsState(ball.State) || q.Text == "" && len(q.States) == 0 && ball.State == StateComplete {
+		return nil
+	}
+	if q.Session != "" && !containsString(ball.Tags, q.Session) {
+		return nil
+	}
+	result := &SearchResult{ProjectDir: ball.WorkingDir, Ball: ball, Archived: archived}
+	if q.Text == "" {
+		return result
+	}
+
+	result.addMatches(q.Text, SearchFieldID, ball.ID)
+	result.addMatches(q.Text, SearchFieldTitle, ball.Title)
+	result.addMatches(q.Text, SearchFieldNextAction, ball.NextAction)
+	result.addMatches(q.Text, SearchFieldContext, ball.Context)
+	for _, ac := range ball.AcceptanceCriteria {
+		result.addMatches(q.Text, SearchFieldCriteria, ac)
+	}
+	result.addMatches(q.Text, SearchFieldTags, strings.Join(ball.Tags, ", "))
+	result.addMatches(q.Text, SearchFieldBlocked, ball.BlockedReason)
+	result.addMatches(q.Text, SearchFieldCompletion, ball.CompletionNote)
+	if len(result.Matches) == 0 {
+		return nil
+	}
+	return result
+}
+
+// searchSession returns the session's matches, or nil if it doesn't match
+func (q SearchQuery) searchSession(sess *JuggleSession, progress string) *SearchResult {
+	result := &SearchResult{Session: sess}
+	result.addMatches(q.Text, SearchFieldID, sess.ID)
+	result.addMatches(q.Text, SearchFieldDescription, sess.Description)
+	result.addMatches(q.Text, SearchFieldContext, sess.Context)
+	for _, ac := range sess.AcceptanceCriteria {
+		result.addMatches(q.Text, SearchFieldCriteria, ac)
+	}
+	result.addMatches(q.Text, SearchFieldProgress, progress)
+	if len(result.Matches) == 0 {
+		return nil
+	}
+	return result
+}
+
+// addMatches adds a match for each line of text containing the query
+func (r *SearchResult) addMatches(query, field, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		start, end := IndexFold(line, query)
+		if start < 0 {
+			continue
+		}
+		snippet, start, end := searchSnippet(line, start, end)
+		r.Matches = append(r.Matches, SearchMatch{Field: field, Snippet: snippet, Start: start, End: end})
+	}
+}
+
+// searchSnippet trims a long line to a window around the match, marking
+// cut ends with "…", and returns the match's range in the snippet
+func searchSnippet(line string, start, end int) (string, int, int) {
+	if len(line) <= searchSnippetWidth {
+		return line, start, end
+	}
+	from := start - (searchSnippetWidth-(end-start))/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + searchSnippetWidth
+	if to < end {
+		to = end
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+	for from > 0 && !utf8.RuneStart(line[from]) {
+		from--
+	}
+	for to < len(line) && !utf8.RuneStart(line[to]) {
+		to++
+	}
+
+	snippet := line[from:to]
+	start, end = start-from, end-from
+	if from > 0 {
+		snippet = "…" + snippet
+		start += len("…")
+		end += len("…")
+	}
+	if to < len(line) {
+		snippet += "…"
+	}
+	return snippet, start, end
+}
+
+// IndexFold returns the byte range of the first case-insensitive match of
+// substr in s, or -1, -1 if there is none
+func IndexFold(s, substr string) (int, int) {
+	if substr == "" {
+		return -1, -1
+	}
+	for i := range s {
+		if end, ok := hasPrefixFold(s[i:], substr); ok {
+			return i, i + end
+		}
+	}
+	return -1, -1
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case, and
+// how many bytes of s the prefix covers
+func hasPrefixFold(s, prefix string) (int, bool) {
+	n := 0
+	for _, pr := range prefix {
+		if n >= len(s) {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRuneInString(s[n:])
+		if sr != pr && !strings.EqualFold(string(sr), string(pr)) {
+			return 0, false
+		}
+		n += size
+	}
+	return n, true
+}
+
+// containsString returns true if list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
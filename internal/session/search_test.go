@@ -0,0 +1,90 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch_FindsBallsSessionsAndArchives(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	sessionStore, err := NewSessionStore(store.ProjectDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+
+	active, _ := NewBall(store.ProjectDir(), "Add login page", PriorityMedium)
+	active.Context = "Uses the OAuth flow\nfrom the API gateway"
+	active.AddTag("auth")
+	other, _ := NewBall(store.ProjectDir(), "Unrelated", PriorityLow)
+	archived, _ := NewBall(store.ProjectDir(), "Old oauth spike", PriorityLow)
+	for _, ball := range []*Ball{active, other, archived} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+	archived.MarkComplete("")
+	if err := store.ArchiveBall(archived); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+	if _, err := sessionStore.CreateSession("auth", "Authentication"); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if err := sessionStore.AppendProgress("auth", "Switched to OAUTH tokens\n"); err != nil {
+		t.Fatalf("Failed to append progress: %v", err)
+	}
+
+	projects := []string{store.ProjectDir()}
+	results, err := Search(projects, SearchQuery{Text: "oauth"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	// Active balls come first, then the archive, then sessions
+	if results[0].Ball == nil || results[0].Ball.ID != active.ID {
+		t.Fatalf("Expected the active ball first, got %+v", results[0])
+	}
+	match := results[0].Matches[0]
+	if match.Field != SearchFieldContext || match.Snippet != "Uses the OAuth flow" || match.Snippet[match.Start:match.End] != "OAuth" {
+		t.Errorf("Expected the context line matched, got %+v", match)
+	}
+	if results[1].Ball == nil || results[1].Ball.ID != archived.ID || !results[1].Archived {
+		t.Errorf("Expected the archived ball second, got %+v", results[1])
+	}
+	if results[2].Session == nil || results[2].Session.ID != "auth" || results[2].Matches[0].Field != SearchFieldProgress {
+		t.Errorf("Expected the session's progress matched last, got %+v", results[2])
+	}
+
+	// States narrows to balls; Session to the session's balls
+	results, _ = Search(projects, SearchQuery{Text: "oauth", States: []BallState{StatePending}})
+	if len(results) != 1 || results[0].Ball.ID != active.ID {
+		t.Errorf("Expected only the pending ball, got %d results", len(results))
+	}
+	results, _ = Search(projects, SearchQuery{Text: "oauth", Session: "auth"})
+	if len(results) != 2 || results[0].Ball.ID != active.ID || results[1].Session == nil {
+		t.Errorf("Expected the tagged ball and the session, got %d results", len(results))
+	}
+	if results, _ = Search(projects, SearchQuery{Text: "oauth", Project: "elsewhere"}); len(results) != 0 {
+		t.Errorf("Expected no results in another project, got %d", len(results))
+	}
+}
+
+func TestSearchSnippet_TrimsLongLines(t *testing.T) {
+	line := strings.Repeat("é", 80) + " needle " + strings.Repeat("x", 120)
+	start, end := IndexFold(line, "NEEDLE")
+	snippet, start, end := searchSnippet(line, start, end)
+	if snippet[start:end] != "needle" {
+		t.Fatalf("Expected the match range in the snippet, got %q", snippet[start:end])
+	}
+	if !strings.HasPrefix(snippet, "…") || !strings.HasSuffix(snippet, "…") {
+		t.Errorf("Expected both cut ends marked, got %q", snippet)
+	}
+	if !strings.Contains(snippet, "é") || len([]rune(snippet)) > searchSnippetWidth+2 {
+		t.Errorf("Expected a rune-safe snippet of about %d runes, got %q", searchSnippetWidth, snippet)
+	}
+}
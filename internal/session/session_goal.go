@@ -0,0 +1,82 @@
+package session
+
+import (
+	"strings"
+	"time"
+)
+
+// ExitCriterion is a condition that must hold before a session's goal is
+// reached. Criteria with a check are met when the check command exits 0;
+// the rest are marked met by hand.
+type ExitCriterion struct {
+	Text  string `json:"text"`
+	Check string `json:"check,omitempty"` // Shell command run in the project directory
+	Met   bool   `json:"met,omitempty"`   // Marked met, or passed its last check
+}
+
+// String renders the criterion as a checklist item, e.g.
+// "[x] Tests pass (check: go test ./...)"
+func (c ExitCriterion) String() string {
+	mark := "[ ]"
+	if c.Met {
+		mark = "[x]"
+	}
+	s := mark + " " + c.Text
+	if c.Check != "" {
+		s += " (check: " + c.Check + ")"
+	}
+	return s
+}
+
+// SetGoal updates the session goal
+func (s *JuggleSession) SetGoal(goal string) {
+	s.Goal = strings.TrimSpace(goal)
+	s.UpdatedAt = time.Now()
+}
+
+// SetExitCriteria replaces the session's exit criteria
+func (s *JuggleSession) SetExitCriteria(criteria []ExitCriterion) {
+	s.ExitCriteria = criteria
+	s.UpdatedAt = time.Now()
+}
+
+// HasGoal reports whether the session has a goal or exit criteria
+func (s *JuggleSession) HasGoal() bool {
+	return s.Goal != "" || len(s.ExitCriteria) > 0
+}
+
+// GoalProgress counts the session's met exit criteria, as of their last
+// evaluation
+func (s *JuggleSession) GoalProgress() (met, total int) {
+	for _, criterion := range s.ExitCriteria {
+		if criterion.Met {
+			met++
+		}
+	}
+	return met, len(s.ExitCriteria)
+}
+
+// UnmetExitCriteria returns the criteria that aren't met
+func UnmetExitCriteria(criteria []ExitCriterion) []ExitCriterion {
+	var unmet []ExitCriterion
+	for _, criterion := range criteria {
+		if !criterion.Met {
+			unmet = append(unmet, criterion)
+		}
+	}
+	return unmet
+}
+
+// EvaluateExitCriteria returns a copy of criteria with each check run
+// through run, which reports whether the command passed. Criteria without
+// a check keep the state they were marked with.
+func EvaluateExitCriteria(criteria []ExitCriterion, run func(check string) bool) []ExitCriterion {
+	evaluated := make([]ExitCriterion, len(criteria))
+	for i, criterion := range criteria {
+		if criterion.Check != "" {
+			criterion.Met = run(criterion.Check)
+		}
+		evaluated[i] = criterion
+	}
+	return evaluated
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SessionState is where a session is in its lifecycle
+type SessionState string
+
+const (
+	SessionStateActive   SessionState = "active"   // Worked on and scheduled
+	SessionStatePaused   SessionState = "paused"   // Kept in view, but not scheduled by the agent daemon
+	SessionStateArchived SessionState = "archived" // Done with: hidden by default and not scheduled
+)
+
+// ParseSessionState parses a session state name, case-insensitively
+func ParseSessionState(s string) (SessionState, error) {
+	for _, state := range []SessionState{SessionStateActive, SessionStatePaused, SessionStateArchived} {
+		if strings.EqualFold(s, string(state)) {
+			return state, nil
+		}
+	}
+	return "", fmt.Errorf("invalid session state %q: must be active, paused or archived", s)
+}
+
+// CurrentState returns the session's state. Sessions saved before states
+// existed are active.
+func (s *JuggleSession) CurrentState() SessionState {
+	if s.State == "" {
+		return SessionStateActive
+	}
+	return s.State
+}
+
+// SetState moves the session to state
+func (s *JuggleSession) SetState(state SessionState) {
+	if state == SessionStateActive {
+		state = "" // Active is the default and isn't stored
+	}
+	s.State = state
+	s.UpdatedAt = time.Now()
+}
+
+// IsArchived reports whether the session is archived
+func (s *JuggleSession) IsArchived() bool {
+	return s.CurrentState() == SessionStateArchived
+}
+
+// Schedulable reports whether the agent daemon may start runs for the
+// session; paused and archived sessions are left alone
+func (s *JuggleSession) Schedulable() bool {
+	return s.CurrentState() == SessionStateActive
+}
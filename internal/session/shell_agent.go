@@ -0,0 +1,71 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShellAgentConfig configures the "shell" agent provider, which runs any
+// command as the agent. The command gets the prompt on stdin.
+type ShellAgentConfig struct {
+	Command string `json:"command,omitempty"` // Run with sh -c in the project directory
+	Signals string `json:"signals,omitempty"` // "promise" (default) or "exit_code"
+}
+
+// ValidateShellAgentSignals checks if a shell agent signal mode is valid
+func ValidateShellAgentSignals(signals string) bool {
+	return signals == "" || signals == "promise" || signals == "exit_code"
+}
+
+// SetShellAgent validates and stores the shell agent settings
+func (c *Config) SetShellAgent(agent *ShellAgentConfig) error {
+	if strings.TrimSpace(agent.Command) == "" {
+		return fmt.Errorf("shell agent command cannot be empty")
+	}
+	if !ValidateShellAgentSignals(agent.Signals) {
+		return fmt.Errorf("invalid signals %q (must be promise or exit_code)", agent.Signals)
+	}
+	c.ShellAgent = agent
+	return nil
+}
+
+// GetShellAgent returns the shell agent settings (never nil)
+func (c *Config) GetShellAgent() *ShellAgentConfig {
+	if c.ShellAgent == nil {
+		return &ShellAgentConfig{}
+	}
+	return c.ShellAgent
+}
+
+// GetGlobalShellAgentWithOptions returns the shell agent settings from global config
+func GetGlobalShellAgentWithOptions(opts ConfigOptions) (*ShellAgentConfig, error) {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return &ShellAgentConfig{}, err
+	}
+	return config.GetShellAgent(), nil
+}
+
+// UpdateGlobalShellAgentWithOptions applies edit to the shell agent settings in global config
+func UpdateGlobalShellAgentWithOptions(opts ConfigOptions, edit func(agent *ShellAgentConfig)) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	agent := *config.GetShellAgent()
+	edit(&agent)
+	if err := config.SetShellAgent(&agent); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalShellAgentWithOptions removes the shell agent settings from global config
+func ClearGlobalShellAgentWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.ShellAgent = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,282 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortKey is one "field [asc|desc]" term of a sort expression
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// SortExpr is an ordered list of sort keys, e.g. "priority desc, age desc".
+// Later keys break ties between balls that compare equal on earlier ones.
+type SortExpr []SortKey
+
+// NamedSortOrder is a custom sort order defined in config. It shows up in
+// the TUI sort cycle and is accepted by "board --sort" under its name.
+type NamedSortOrder struct {
+	Name string `json:"name"`
+	Expr string `json:"expr"` // Sort expression, e.g. "priority desc, age desc"
+}
+
+// sortFieldAliases maps accepted field names to their canonical name
+var sortFieldAliases = map[string]string{
+	"id":            "id",
+	"title":         "title",
+	"state":         "state",
+	"priority":      "priority",
+	"activity":      "activity",
+	"last_activity": "activity",
+	"created":       "created",
+	"created_at":    "created",
+	"age":           "age",
+	"updates":       "updates",
+	"update_count":  "updates",
+	"size":          "size",
+	"model_size":    "size",
+	"deps":          "deps",
+	"depends_on":    "deps",
+}
+
+// SortFields returns the canonical field names usable in sort expressions
+func SortFields() []string {
+	return []string{"id", "title", "state", "priority", "activity", "created", "age", "updates", "size", "deps"}
+}
+
+// ParseSortExpr parses a comma-separated list of "field [asc|desc]" terms.
+// Fields sort ascending unless "desc" is given. "age" is the time since the
+// ball was created, so "age desc" puts the oldest balls first.
+func ParseSortExpr(s string) (SortExpr, error) {
+	var expr SortExpr
+	for _, term := range strings.Split(s, ",") {
+		words := strings.Fields(strings.ToLower(term))
+		if len(words) == 0 {
+			continue
+		}
+		field, ok := sortFieldAliases[words[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q (valid: %s)", words[0], strings.Join(SortFields(), ", "))
+		}
+		key := SortKey{Field: field}
+		switch {
+		case len(words) == 1, words[1] == "asc":
+		case words[1] == "desc":
+			key.Desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort direction %q for %s (must be asc or desc)", words[1], field)
+		}
+		if len(words) > 2 {
+			return nil, fmt.Errorf("invalid sort term %q (expected \"field [asc|desc]\")", strings.TrimSpace(term))
+		}
+		expr = append(expr, key)
+	}
+	if len(expr) == 0 {
+		return nil, fmt.Errorf("sort expression is empty")
+	}
+	return expr, nil
+}
+
+// String returns the expression in canonical form
+func (e SortExpr) String() string {
+	terms := make([]string, len(e))
+	for i, key := range e {
+		terms[i] = key.Field
+		if key.Desc {
+			terms[i] += " desc"
+		}
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Compare returns -1, 0, or 1 depending on whether a sorts before, equal
+// to, or after b under the expression
+func (e SortExpr) Compare(a, b *Ball) int {
+	for _, key := range e {
+		c := compareSortField(key.Field, a, b)
+		if key.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareSortField compares two balls on a single field, ascending
+func compareSortField(field string, a, b *Ball) int {
+	switch field {
+	case "id":
+		return CompareBallIDs(a.ID, b.ID)
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "state":
+		return compareInts(stateSortRank(a.State), stateSortRank(b.State))
+	case "priority":
+		return compareInts(a.PriorityWeight(), b.PriorityWeight())
+	case "activity":
+		return compareTimes(a.LastActivity, b.LastActivity)
+	case "created":
+		return compareTimes(a.StartedAt, b.StartedAt)
+	case "age":
+		// Older balls have a larger age
+		return compareTimes(b.StartedAt, a.StartedAt)
+	case "updates":
+		return compareInts(a.UpdateCount, b.UpdateCount)
+	case "size":
+		return compareInts(modelSizeSortRank(a.ModelSize), modelSizeSortRank(b.ModelSize))
+	case "deps":
+		return compareInts(len(a.DependsOn), len(b.DependsOn))
+	}
+	return 0
+}
+
+// stateSortRank orders states by lifecycle: active work before finished work
+func stateSortRank(state BallState) int {
+	switch state {
+	case StateInProgress:
+		return 0
+	case StatePending:
+		return 1
+	case StateBlocked:
+		return 2
+	case StateComplete:
+		return 3
+	case StateResearched:
+		return 4
+	}
+	return 5
+}
+
+// modelSizeSortRank orders model sizes from unset to large
+func modelSizeSortRank(size ModelSize) int {
+	switch size {
+	case ModelSizeSmall:
+		return 1
+	case ModelSizeMedium:
+		return 2
+	case ModelSizeLarge:
+		return 3
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// CompareBallIDs compares two ball IDs, ordering numeric suffixes
+// ("project-9" before "project-10") numerically
+func CompareBallIDs(id1, id2 string) int {
+	num1, num2 := ballIDNumber(id1), ballIDNumber(id2)
+	if num1 != -1 && num2 != -1 && num1 != num2 {
+		return compareInts(num1, num2)
+	}
+	return strings.Compare(id1, id2)
+}
+
+// ballIDNumber returns the numeric suffix of a ball ID, or -1 if it has none
+func ballIDNumber(id string) int {
+	lastHyphen := strings.LastIndex(id, "-")
+	if lastHyphen >= 0 && lastHyphen < len(id)-1 {
+		if num, err := strconv.Atoi(id[lastHyphen+1:]); err == nil {
+			return num
+		}
+	}
+	return -1
+}
+
+// FindSortOrder returns the custom sort order with the given name, or nil
+func (c *Config) FindSortOrder(name string) *NamedSortOrder {
+	for i := range c.SortOrders {
+		if strings.EqualFold(c.SortOrders[i].Name, name) {
+			return &c.SortOrders[i]
+		}
+	}
+	return nil
+}
+
+// SetSortOrder adds a custom sort order, or replaces the expression of an
+// existing one with the same name
+func (c *Config) SetSortOrder(name, expr string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, " ,") {
+		return fmt.Errorf("invalid sort order name %q (must be non-empty without spaces or commas)", name)
+	}
+	parsed, err := ParseSortExpr(expr)
+	if err != nil {
+		return err
+	}
+	if existing := c.FindSortOrder(name); existing != nil {
+		existing.Expr = parsed.String()
+		return nil
+	}
+	c.SortOrders = append(c.SortOrders, NamedSortOrder{Name: name, Expr: parsed.String()})
+	return nil
+}
+
+// RemoveSortOrder removes a custom sort order by name
+func (c *Config) RemoveSortOrder(name string) error {
+	for i, order := range c.SortOrders {
+		if strings.EqualFold(order.Name, name) {
+			c.SortOrders = append(c.SortOrders[:i], c.SortOrders[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no custom sort order named %q", name)
+}
+
+// UpdateGlobalSortOrderWithOptions adds or replaces a custom sort order in global config
+func UpdateGlobalSortOrderWithOptions(opts ConfigOptions, name, expr string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetSortOrder(name, expr); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// RemoveGlobalSortOrderWithOptions removes a custom sort order from global config
+func RemoveGlobalSortOrderWithOptions(opts ConfigOptions, name string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.RemoveSortOrder(name); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalSortOrdersWithOptions removes every custom sort order from global config
+func ClearGlobalSortOrdersWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.SortOrders = nil
+	return config.SaveWithOptions(opts)
+}
@@ -0,0 +1,164 @@
+package session
+
+import (
+	"sort"
+	"time"
+)
+
+// SprintGroup is what one session or tag got done in a sprint
+type SprintGroup struct {
+	Name     string        `json:"name"`     // Session ID or tag, or "" for balls in no session
+	Balls    []*Ball       `json:"balls"`    // Completed balls, by priority
+	ByAgent  int           `json:"by_agent"` // Balls an agent run completed; the rest were completed by hand
+	Duration time.Duration `json:"duration"` // Total time from creating the balls to completing them
+}
+
+// SprintBlocker is something that held work up during a sprint: a ball
+// that's still blocked, or an agent run that ended blocked
+type SprintBlocker struct {
+	Ball    *Ball     `json:"ball,omitempty"`
+	Session string    `json:"session,omitempty"` // Session of the blocked agent run
+	Reason  string    `json:"reason"`
+	At      time.Time `json:"at"`
+}
+
+// SprintReport groups the balls completed between two dates for a sprint
+// review
+type SprintReport struct {
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"` // Exclusive
+	Completed   []*Ball           `json:"completed"`
+	CompletedBy map[string]string `json:"completed_by"` // Ball ID to "agent" or "human"
+	ByAgent     int               `json:"by_agent"`
+	Duration    time.Duration     `json:"duration"`
+	Sessions    []*SprintGroup    `json:"sessions"`
+	Tags        []*SprintGroup    `json:"tags"` // Tags other than session IDs
+	Blockers    []*SprintBlocker  `json:"blockers"`
+}
+
+// add counts a completed ball toward a group
+func (g *SprintGroup) add(ball *Ball, byAgent bool) {
+	g.Balls = append(g.Balls, ball)
+	g.Duration += ball.CompletedAt.Sub(ball.StartedAt)
+	if byAgent {
+		g.ByAgent++
+	}
+}
+
+// ReportSprint builds the sprint review for [from, to) from active and
+// archived balls and the project's agent runs. sessionIDs are the sessions
+// to group by; a ball in several sessions or with several tags counts
+// toward each. A ball counts as completed by an agent if a run claimed it
+// or it was completed while a run on one of its sessions was going.
+func ReportSprint(from, to time.Time, balls []*Ball, sessionIDs []string, runs []*AgentRunRecord) *SprintReport {
+	report := &SprintReport{
+		From:        from,
+		To:          to,
+		Completed:   make([]*Ball, 0),
+		CompletedBy: make(map[string]string),
+		Sessions:    make([]*SprintGroup, 0),
+		Tags:        make([]*SprintGroup, 0),
+		Blockers:    make([]*SprintBlocker, 0),
+	}
+	within := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	isSession := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		isSession[id] = true
+	}
+	bySession := make(map[string]*SprintGroup)
+	byTag := make(map[string]*SprintGroup)
+	group := func(groups map[string]*SprintGroup, list *[]*SprintGroup, name string) *SprintGroup {
+		g, ok := groups[name]
+		if !ok {
+			g = &SprintGroup{Name: name, Balls: make([]*Ball, 0)}
+			groups[name] = g
+			*list = append(*list, g)
+		}
+		return g
+	}
+
+	for _, ball := range balls {
+		if ball.State == StateBlocked && within(ball.LastActivity) {
+			report.Blockers = append(report.Blockers, &SprintBlocker{Ball: ball, Reason: ball.BlockedReason, At: ball.LastActivity})
+		}
+		if (ball.State != StateComplete && ball.State != StateResearched) || ball.CompletedAt == nil || !within(*ball.CompletedAt) {
+			continue
+		}
+
+		byAgent := completedByAgent(ball, runs)
+		report.Completed = append(report.Completed, ball)
+		report.Duration += ball.CompletedAt.Sub(ball.StartedAt)
+		report.CompletedBy[ball.ID] = "human"
+		if byAgent {
+			report.CompletedBy[ball.ID] = "agent"
+			report.ByAgent++
+		}
+
+		inSession := false
+		for _, tag := range ball.Tags {
+			if isSession[tag] {
+				group(bySession, &report.Sessions, tag).add(ball, byAgent)
+				inSession = true
+			} else {
+				group(byTag, &report.Tags, tag).add(ball, byAgent)
+			}
+		}
+		if !inSession {
+			group(bySession, &report.Sessions, "").add(ball, byAgent)
+		}
+	}
+
+	for _, run := range runs {
+		if run.Result == "blocked" && within(run.EndedAt) {
+			report.Blockers = append(report.Blockers, &SprintBlocker{Session: run.SessionID, Reason: run.BlockedReason, At: run.EndedAt})
+		}
+	}
+
+	SortBallsByPriority(report.Completed)
+	for _, groups := range [][]*SprintGroup{report.Sessions, report.Tags} {
+		for _, g := range groups {
+			SortBallsByPriority(g.Balls)
+		}
+		sort.SliceStable(groups, func(i, j int) bool {
+			gi, gj := groups[i], groups[j]
+			if (gi.Name == "") != (gj.Name == "") {
+				return gj.Name == "" // Balls in no session last
+			}
+			if len(gi.Balls) != len(gj.Balls) {
+				return len(gi.Balls) > len(gj.Balls)
+			}
+			return gi.Name < gj.Name
+		})
+	}
+	sort.SliceStable(report.Blockers, func(i, j int) bool {
+		return report.Blockers[i].At.Before(report.Blockers[j].At)
+	})
+	return report
+}
+
+// completedByAgent returns true if an agent run completed the ball
+func completedByAgent(ball *Ball, runs []*AgentRunRecord) bool {
+	if ball.CompletedBySession != "" {
+		return true
+	}
+	for _, run := range runs {
+		if ball.CompletedAt.Before(run.StartedAt) || ball.CompletedAt.After(run.EndedAt) {
+			continue
+		}
+		if run.SessionID == "all" || ballHasTag(ball, run.SessionID) {
+			return true
+		}
+	}
+	return false
+}
+
+// AverageDuration returns how long the group's balls took on average
+func (g *SprintGroup) AverageDuration() time.Duration {
+	if len(g.Balls) == 0 {
+		return 0
+	}
+	return g.Duration / time.Duration(len(g.Balls))
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/gofrs/flock"
+	"github.com/ohare93/juggle/internal/profile"
+	bolt "go.etcd.io/bbolt"
 )
 
 const (
@@ -21,7 +23,8 @@ const (
 
 // StoreConfig holds configurable options for Store.
 type StoreConfig struct {
-	JuggleDirName string // Name of the juggle directory (default: ".juggle")
+	JuggleDirName string       // Name of the juggle directory (default: ".juggle")
+	Backend       StoreBackend // Where active balls are kept (default: the project config's store_backend)
 }
 
 // DefaultStoreConfig returns the default store configuration.
@@ -35,7 +38,8 @@ func DefaultStoreConfig() StoreConfig {
 //
 // Store manages balls stored in JSONL format at .juggle/balls.jsonl (active)
 // and .juggle/archive/balls.jsonl (completed). It provides thread-safe
-// CRUD operations using file locking.
+// CRUD operations using file locking. Projects with the "bolt" store backend
+// keep their active balls in .juggle/balls.db instead (see StoreBackend).
 //
 // Key features:
 //   - JSONL format for append-friendly version control
@@ -53,6 +57,7 @@ type Store struct {
 	ballsPath   string
 	archivePath string
 	config      StoreConfig
+	db          *boltStore // Active balls with the bolt backend, nil with jsonl
 }
 
 // ProjectDir returns the project directory for this store
@@ -75,6 +80,7 @@ func NewStoreWithConfig(projectDir string, config StoreConfig) (*Store, error) {
 		}
 		projectDir = cwd
 	}
+	projectDir = CleanPath(projectDir)
 
 	// Resolve to main repo if this is a worktree
 	storageDir, err := ResolveStorageDir(projectDir, config.JuggleDirName)
@@ -97,12 +103,38 @@ func NewStoreWithConfig(projectDir string, config StoreConfig) (*Store, error) {
 		return nil, fmt.Errorf("failed to create archive directory: %w", err)
 	}
 
-	return &Store{
+	store := &Store{
 		projectDir:  projectDir,
 		ballsPath:   ballsPath,
 		archivePath: archivePath,
 		config:      config,
-	}, nil
+	}
+
+	backend := config.Backend
+	if backend == "" {
+		if backend, err = readStoreBackend(storageDir); err != nil {
+			return nil, err
+		}
+	}
+	if backend == StoreBackendBolt {
+		store.db = &boltStore{path: filepath.Join(storePath, ballsDBFile), projectDir: projectDir}
+		// Switching the config by hand would hide every ball in balls.jsonl
+		if config.Backend == "" && !store.db.exists() {
+			if _, err := os.Stat(ballsPath); err == nil {
+				return nil, fmt.Errorf("store_backend is bolt but %s doesn't exist yet; run 'juggle migrate --to bolt' to convert balls.jsonl", ballsDBFile)
+			}
+		}
+	}
+	return store, nil
+}
+
+// ballsDataPath returns the file the active balls are kept in, balls.db or
+// balls.jsonl
+func (s *Store) ballsDataPath() string {
+	if s.db != nil {
+		return s.db.path
+	}
+	return s.ballsPath
 }
 
 // acquireFileLock acquires an exclusive lock on a file
@@ -123,13 +155,9 @@ func acquireFileLock(path string) (*flock.Flock, func(), error) {
 	return fileLock, cleanup, nil
 }
 
-// AppendBall adds a new ball to the JSONL file
+// AppendBall adds a new ball to the JSONL file. IDs are never reused: if the
+// ball's ID belongs to an active, archived, or deleted ball, it gets a new one.
 func (s *Store) AppendBall(ball *Ball) error {
-	data, err := json.Marshal(ball)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ball: %w", err)
-	}
-
 	// Acquire file lock
 	_, unlock, err := acquireFileLock(s.ballsPath)
 	if err != nil {
@@ -137,6 +165,25 @@ func (s *Store) AppendBall(ball *Ball) error {
 	}
 	defer unlock()
 
+	if err := s.ensureUnusedBallID(ball); err != nil {
+		return err
+	}
+	if s.db != nil {
+		err := s.db.update(func(tx *bolt.Tx) error {
+			_, err := s.db.put(tx, ball)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		s.recordUndo(UndoEntry{Action: UndoActionCreate, BallID: ball.ID, After: ball, AfterIn: undoInActive})
+		return nil
+	}
+	data, err := json.Marshal(ball)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ball: %w", err)
+	}
+
 	// Open file in append mode
 	f, err := os.OpenFile(s.ballsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -152,6 +199,7 @@ func (s *Store) AppendBall(ball *Ball) error {
 		return fmt.Errorf("failed to write newline: %w", err)
 	}
 
+	s.recordUndo(UndoEntry{Action: UndoActionCreate, BallID: ball.ID, After: ball, AfterIn: undoInActive})
 	return nil
 }
 
@@ -164,6 +212,12 @@ type ballJSON struct {
 
 // LoadBalls reads all balls from the JSONL file
 func (s *Store) LoadBalls() ([]*Ball, error) {
+	defer profile.Start(profile.KindStore, "load_balls")()
+
+	if s.db != nil {
+		return s.db.load()
+	}
+
 	// If file doesn't exist, return empty slice
 	if _, err := os.Stat(s.ballsPath); os.IsNotExist(err) {
 		return []*Ball{}, nil
@@ -213,18 +267,34 @@ func (s *Store) LoadBalls() ([]*Ball, error) {
 
 // LoadArchivedBalls reads all balls from the archive JSONL file
 func (s *Store) LoadArchivedBalls() ([]*Ball, error) {
-	// If file doesn't exist, return empty slice
+	defer profile.Start(profile.KindStore, "load_archived_balls")()
+
+	balls := make([]*Ball, 0)
+	err := s.EachArchivedBall(func(ball *Ball) bool {
+		balls = append(balls, ball)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balls, nil
+}
+
+// EachArchivedBall streams the archive in file order (oldest first), calling
+// fn for each ball until it returns false. Unlike LoadArchivedBalls, only one
+// ball is held in memory at a time.
+func (s *Store) EachArchivedBall(fn func(ball *Ball) bool) error {
+	// If file doesn't exist, there is nothing to stream
 	if _, err := os.Stat(s.archivePath); os.IsNotExist(err) {
-		return []*Ball{}, nil
+		return nil
 	}
 
 	f, err := os.Open(s.archivePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open archive file: %w", err)
+		return fmt.Errorf("failed to open archive file: %w", err)
 	}
 	defer f.Close()
 
-	balls := make([]*Ball, 0)
 	scanner := bufio.NewScanner(f)
 
 	for scanner.Scan() {
@@ -250,57 +320,165 @@ func (s *Store) LoadArchivedBalls() ([]*Ball, error) {
 		// Set WorkingDir from store location (not stored in JSON)
 		ball.WorkingDir = s.projectDir
 
-		balls = append(balls, &ball)
+		if !fn(&ball) {
+			return nil
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading archive file: %w", err)
+		return fmt.Errorf("error reading archive file: %w", err)
 	}
 
-	return balls, nil
+	return nil
 }
 
-// UpdateBall updates an existing ball by rewriting the JSONL file
-func (s *Store) UpdateBall(updated *Ball) error {
+// ListOptions controls which balls ListBalls and EachBall return
+type ListOptions struct {
+	IncludeArchived bool // Also return archived balls, after the active ones
+}
+
+// EachBall calls fn for each active ball and then, with IncludeArchived, for
+// each archived ball in archive order, until fn returns false. archived tells
+// fn which of the two the ball came from.
+func (s *Store) EachBall(opts ListOptions, fn func(ball *Ball, archived bool) bool) error {
 	balls, err := s.LoadBalls()
 	if err != nil {
 		return err
 	}
+	for _, ball := range balls {
+		if !fn(ball, false) {
+			return nil
+		}
+	}
+	if !opts.IncludeArchived {
+		return nil
+	}
+	return s.EachArchivedBall(func(ball *Ball) bool {
+		return fn(ball, true)
+	})
+}
 
-	// Find and update the ball
-	found := false
-	for i, ball := range balls {
-		if ball.ID == updated.ID {
-			balls[i] = updated
-			found = true
-			break
+// ListBalls returns the active balls and, with IncludeArchived, the archived
+// balls after them. Reports that count completed work should include the
+// archive, since balls are usually archived soon after they're done.
+func (s *Store) ListBalls(opts ListOptions) ([]*Ball, error) {
+	balls := make([]*Ball, 0)
+	err := s.EachBall(opts, func(ball *Ball, archived bool) bool {
+		balls = append(balls, ball)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return balls, nil
+}
+
+// GetBallAnywhere finds a ball by full or short ID in the active balls or the
+// archive, and reports whether it was archived. A full ID match wins over a
+// short one, and otherwise active balls win over archived ones.
+func (s *Store) GetBallAnywhere(id string) (*Ball, bool, error) {
+	var found *Ball
+	var foundArchived bool
+	err := s.EachBall(ListOptions{IncludeArchived: true}, func(ball *Ball, archived bool) bool {
+		if ball.ID == id {
+			found, foundArchived = ball, archived
+			return false
 		}
+		// A short ID match is kept unless a better one turns up
+		if found == nil && ball.ShortID() == id {
+			found, foundArchived = ball, archived
+		}
+		return true
+	})
+	if err != nil {
+		return nil, false, err
 	}
+	if found == nil {
+		return nil, false, NewBallNotFoundError(id)
+	}
+	return found, foundArchived, nil
+}
 
-	if !found {
-		return NewBallNotFoundError(updated.ID)
+// UpdateBall updates an existing ball by rewriting the JSONL file, or with
+// the bolt backend, just the ball's record
+func (s *Store) UpdateBall(updated *Ball) error {
+	// Focus only lasts until the ball is done, however it got there
+	if updated.State == StateComplete || updated.State == StateResearched {
+		updated.Focused = false
 	}
 
-	// Rewrite entire file
-	return s.writeBalls(balls)
+	var previous *Ball
+	if s.db != nil {
+		var err error
+		if previous, err = s.updateIndexedBall(updated); err != nil {
+			return err
+		}
+	} else {
+		balls, err := s.LoadBalls()
+		if err != nil {
+			return err
+		}
+
+		// Find and update the ball
+		for i, ball := range balls {
+			if ball.ID == updated.ID {
+				previous = ball
+				balls[i] = updated
+				break
+			}
+		}
+
+		if previous == nil {
+			return NewBallNotFoundError(updated.ID)
+		}
+
+		// Rewrite entire file
+		if err := s.writeBalls(balls); err != nil {
+			return err
+		}
+	}
+	s.recordUndo(UndoEntry{Action: UndoActionUpdate, BallID: updated.ID, Before: previous, BeforeIn: undoInActive, After: updated, AfterIn: undoInActive})
+	if stateChangeHandler != nil && previous.State != updated.State {
+		stateChangeHandler(s.projectDir, previous, updated)
+	}
+	return nil
 }
 
 // DeleteBall removes a ball from the JSONL file
 func (s *Store) DeleteBall(id string) error {
-	balls, err := s.LoadBalls()
-	if err != nil {
-		return err
-	}
+	var deleted *Ball
+	if s.db != nil {
+		var err error
+		if deleted, err = s.deleteIndexedBall(id); err != nil {
+			return err
+		}
+	} else {
+		balls, err := s.LoadBalls()
+		if err != nil {
+			return err
+		}
 
-	// Filter out the ball to delete
-	filtered := make([]*Ball, 0, len(balls))
-	for _, ball := range balls {
-		if ball.ID != id {
-			filtered = append(filtered, ball)
+		// Filter out the ball to delete
+		filtered := make([]*Ball, 0, len(balls))
+		for _, ball := range balls {
+			if ball.ID != id {
+				filtered = append(filtered, ball)
+			} else {
+				deleted = ball
+			}
 		}
-	}
 
-	return s.writeBalls(filtered)
+		if err := s.writeBalls(filtered); err != nil {
+			return err
+		}
+	}
+	if deleted != nil {
+		if err := s.retireBallID(id); err != nil {
+			return err
+		}
+		s.recordUndo(UndoEntry{Action: UndoActionDelete, BallID: id, Before: deleted, BeforeIn: undoInActive})
+	}
+	return nil
 }
 
 // ArchiveBall moves a ball to the archive.
@@ -333,17 +511,17 @@ func (s *Store) ArchiveBall(ball *Ball) error {
 	}
 
 	// Find and remove the ball from active list
-	found := false
+	var previous *Ball
 	filtered := make([]*Ball, 0, len(balls))
 	for _, b := range balls {
 		if b.ID != ball.ID {
 			filtered = append(filtered, b)
 		} else {
-			found = true
+			previous = b
 		}
 	}
 
-	if !found {
+	if previous == nil {
 		return NewBallNotFoundError(ball.ID)
 	}
 
@@ -364,24 +542,17 @@ func (s *Store) ArchiveBall(ball *Ball) error {
 		return fmt.Errorf("failed to remove ball from active: %w", err)
 	}
 
+	s.recordUndo(UndoEntry{Action: UndoActionArchive, BallID: ball.ID, Before: previous, BeforeIn: undoInActive, After: ball, AfterIn: undoInArchive})
 	return nil
 }
 
 // GetInProgressBalls returns all balls currently in progress in this project
 func (s *Store) GetInProgressBalls() ([]*Ball, error) {
-	balls, err := s.LoadBalls()
+	inProgress, err := s.GetBallsByState(StateInProgress)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter for in_progress balls
-	inProgress := make([]*Ball, 0)
-	for _, ball := range balls {
-		if ball.State == StateInProgress {
-			inProgress = append(inProgress, ball)
-		}
-	}
-
 	// Sort by most recently active first
 	sort.Slice(inProgress, func(i, j int) bool {
 		return inProgress[i].LastActivity.After(inProgress[j].LastActivity)
@@ -392,6 +563,10 @@ func (s *Store) GetInProgressBalls() ([]*Ball, error) {
 
 // GetBallsByState returns all balls with the given state
 func (s *Store) GetBallsByState(state BallState) ([]*Ball, error) {
+	if s.db != nil {
+		return s.db.byIndex(boltStateBucket, string(state))
+	}
+
 	all, err := s.LoadBalls()
 	if err != nil {
 		return nil, err
@@ -409,6 +584,17 @@ func (s *Store) GetBallsByState(state BallState) ([]*Ball, error) {
 
 // GetBallByID finds a ball by its ID
 func (s *Store) GetBallByID(id string) (*Ball, error) {
+	if s.db != nil {
+		ball, err := s.db.get(id)
+		if err != nil {
+			return nil, err
+		}
+		if ball == nil {
+			return nil, NewBallNotFoundError(id)
+		}
+		return ball, nil
+	}
+
 	balls, err := s.LoadBalls()
 	if err != nil {
 		return nil, err
@@ -536,6 +722,10 @@ func (s *Store) writeBalls(balls []*Ball) error {
 // writeBallsUnlocked rewrites the entire balls.jsonl file without acquiring a lock.
 // Caller must hold the lock.
 func (s *Store) writeBallsUnlocked(balls []*Ball) error {
+	if s.db != nil {
+		return s.db.replaceAll(balls)
+	}
+
 	// Write to temp file first
 	tempPath := s.ballsPath + ".tmp"
 	f, err := os.Create(tempPath)
@@ -582,6 +772,20 @@ func (s *Store) writeBallsUnlocked(balls []*Ball) error {
 // This operation is atomic: both files are locked, and changes are applied
 // atomically using temp file + rename pattern.
 func (s *Store) UnarchiveBall(ballID string) (*Ball, error) {
+	return s.unarchiveBall(ballID, func(ball *Ball) error {
+		// Change state to pending using new state model
+		ball.State = StatePending
+		ball.BlockedReason = ""
+		ball.CompletedAt = nil
+		ball.CompletionNote = ""
+		ball.CompletedBySession = ""
+		return nil
+	})
+}
+
+// unarchiveBall moves a ball from the archive to the active balls, applying
+// restore to it first. Nothing is written if restore fails.
+func (s *Store) unarchiveBall(ballID string, restore func(ball *Ball) error) (*Ball, error) {
 	// Acquire locks on both files to ensure atomic operation
 	_, unlockBalls, err := acquireFileLock(s.ballsPath)
 	if err != nil {
@@ -615,11 +819,12 @@ func (s *Store) UnarchiveBall(ballID string) (*Ball, error) {
 		return nil, NewBallNotFoundError(ballID)
 	}
 
-	// Change state to pending using new state model
-	ball.State = StatePending
-	ball.BlockedReason = ""
-	ball.CompletedAt = nil
-	ball.CompletionNote = ""
+	// Copy the archived state for the undo history before changing it
+	previous := *ball
+
+	if err := restore(ball); err != nil {
+		return nil, err
+	}
 
 	// Load current balls
 	balls, err := s.LoadBalls()
@@ -652,9 +857,38 @@ func (s *Store) UnarchiveBall(ballID string) (*Ball, error) {
 		return nil, fmt.Errorf("failed to add ball to active: %w", err)
 	}
 
+	s.recordUndo(UndoEntry{Action: UndoActionUnarchive, BallID: ball.ID, Before: &previous, BeforeIn: undoInArchive, After: ball, AfterIn: undoInActive})
 	return ball, nil
 }
 
+// UpdateArchivedBall updates a ball in the archive, leaving it archived
+func (s *Store) UpdateArchivedBall(updated *Ball) error {
+	_, unlock, err := acquireFileLock(s.archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to lock archive file: %w", err)
+	}
+	defer unlock()
+
+	archived, err := s.LoadArchivedBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	found := false
+	for i, ball := range archived {
+		if ball.ID == updated.ID {
+			archived[i] = updated
+			found = true
+			break
+		}
+	}
+	if !found {
+		return NewBallNotFoundError(updated.ID)
+	}
+
+	return s.writeArchivedBallsUnlocked(archived)
+}
+
 // writeArchivedBalls rewrites the entire archive/balls.jsonl file
 func (s *Store) writeArchivedBalls(balls []*Ball) error {
 	// Acquire file lock
@@ -0,0 +1,158 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TestsResult is the outcome of the last recorded test run for a ball
+type TestsResult string
+
+const (
+	TestsPass    TestsResult = "pass"
+	TestsFail    TestsResult = "fail"
+	TestsUnknown TestsResult = "unknown" // No result recorded yet
+)
+
+// TestsState records the last test run for a ball, as reported by
+// `juggle tests record` (typically from a hook or CI job).
+type TestsState struct {
+	Result     TestsResult `json:"result"`
+	Command    string      `json:"command,omitempty"` // Command that produced the result
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+// TestsPolicy controls what happens when completing a ball whose last test run failed
+type TestsPolicy string
+
+const (
+	TestsPolicyBlock TestsPolicy = "block" // Refuse to complete (default)
+	TestsPolicyWarn  TestsPolicy = "warn"  // Complete, but print a warning
+	TestsPolicyOff   TestsPolicy = "off"   // Ignore test results
+)
+
+// ValidateTestsResult checks if a recorded test result is valid
+func ValidateTestsResult(r string) bool {
+	return r == string(TestsPass) || r == string(TestsFail)
+}
+
+// ValidateTestsPolicy checks if a tests policy is valid
+func ValidateTestsPolicy(p string) bool {
+	switch TestsPolicy(p) {
+	case TestsPolicyBlock, TestsPolicyWarn, TestsPolicyOff:
+		return true
+	}
+	return false
+}
+
+// RecordTests records the result of a test run on the ball
+func (b *Ball) RecordTests(result TestsResult, command string) {
+	b.TestsState = &TestsState{
+		Result:     result,
+		Command:    command,
+		RecordedAt: time.Now(),
+	}
+	b.UpdateActivity()
+}
+
+// TestsResult returns the last recorded test result, or TestsUnknown if none
+func (b *Ball) TestsResult() TestsResult {
+	if b.TestsState == nil || b.TestsState.Result == "" {
+		return TestsUnknown
+	}
+	return b.TestsState.Result
+}
+
+// TestsFailing returns true if the last recorded test run failed
+func (b *Ball) TestsFailing() bool {
+	return b.TestsResult() == TestsFail
+}
+
+// CheckTestsPolicy checks whether a ball may be completed under the given policy.
+// Returns an error if completion must be refused, or a non-empty warning if
+// completion is allowed despite failing tests.
+func CheckTestsPolicy(policy TestsPolicy, ball *Ball) (warning string, err error) {
+	if policy == TestsPolicyOff || !ball.TestsFailing() {
+		return "", nil
+	}
+
+	detail := "recorded " + ball.TestsState.RecordedAt.Format("2006-01-02 15:04")
+	if ball.TestsState.Command != "" {
+		detail = ball.TestsState.Command + ", " + detail
+	}
+	msg := fmt.Sprintf("ball %s has failing tests (%s)", ball.ShortID(), detail)
+
+	if policy == TestsPolicyWarn {
+		return msg, nil
+	}
+	return "", &TestsFailingError{BallID: ball.ShortID(), Detail: msg}
+}
+
+// ErrTestsFailing is returned when completion is refused because of failing tests.
+var ErrTestsFailing = errors.New("tests failing")
+
+// TestsFailingError is returned when the tests policy refuses completion.
+type TestsFailingError struct {
+	BallID string // Short ID of the ball
+	Detail string // Description of the failing run
+}
+
+func (e *TestsFailingError) Error() string {
+	return fmt.Sprintf("%s; record a passing run with 'juggle tests record %s pass' or set tests_policy to warn/off", e.Detail, e.BallID)
+}
+
+func (e *TestsFailingError) Is(target error) bool {
+	return target == ErrTestsFailing
+}
+
+// GetTestsPolicy returns the tests policy, defaulting to block
+func (c *ProjectConfig) GetTestsPolicy() TestsPolicy {
+	if c.TestsPolicy == "" {
+		return TestsPolicyBlock
+	}
+	return TestsPolicy(c.TestsPolicy)
+}
+
+// SetTestsPolicy sets the tests policy
+func (c *ProjectConfig) SetTestsPolicy(policy string) error {
+	if !ValidateTestsPolicy(policy) {
+		return fmt.Errorf("invalid tests policy %q (must be block, warn, or off)", policy)
+	}
+	c.TestsPolicy = policy
+	return nil
+}
+
+// GetProjectTestsPolicy returns the tests policy from project config
+func GetProjectTestsPolicy(projectDir string) (TestsPolicy, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return TestsPolicyBlock, err
+	}
+	return config.GetTestsPolicy(), nil
+}
+
+// UpdateProjectTestsPolicy updates the tests policy in project config
+func UpdateProjectTestsPolicy(projectDir, policy string) error {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := config.SetTestsPolicy(policy); err != nil {
+		return err
+	}
+	return SaveProjectConfig(projectDir, config)
+}
+
+// CheckTestsBeforeComplete checks a ball against its project's tests policy before completion.
+// See CheckTestsPolicy for the meaning of the return values.
+func CheckTestsBeforeComplete(ball *Ball) (warning string, err error) {
+	if !ball.TestsFailing() {
+		return "", nil
+	}
+	policy, loadErr := GetProjectTestsPolicy(ball.WorkingDir)
+	if loadErr != nil {
+		policy = TestsPolicyBlock
+	}
+	return CheckTestsPolicy(policy, ball)
+}
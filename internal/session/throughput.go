@@ -0,0 +1,219 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Throughput target periods
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// behindPeriods is how many consecutive missed periods make a session behind
+const behindPeriods = 2
+
+// ThroughputTarget is a session's goal for how many balls to complete per
+// period, e.g. 5 balls/week. Only periods that start after the target was
+// set are judged, so a new target doesn't start out behind.
+type ThroughputTarget struct {
+	Count      int        `json:"count"`                 // Balls to complete per period
+	Period     string     `json:"period"`                // day, week, or month
+	SetAt      time.Time  `json:"set_at"`                // When the target was set
+	AlertedFor *time.Time `json:"alerted_for,omitempty"` // Start of the period the last behind alert was sent in
+}
+
+// ParseThroughputTarget parses a target such as "5/week", "5 balls/week" or
+// "3 per day". Periods may be abbreviated to d, w, or m.
+func ParseThroughputTarget(s string) (*ThroughputTarget, error) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	normalized = strings.Replace(normalized, " per ", "/", 1)
+
+	countPart, periodPart, ok := strings.Cut(normalized, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid target %q (expected e.g. 5/week)", s)
+	}
+	countPart = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(countPart), "balls"), "ball"))
+	count, err := strconv.Atoi(countPart)
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("invalid target %q: count must be a positive number", s)
+	}
+
+	var period string
+	switch strings.TrimSpace(periodPart) {
+	case "d", "day":
+		period = PeriodDay
+	case "w", "wk", "week":
+		period = PeriodWeek
+	case "m", "mo", "month":
+		period = PeriodMonth
+	default:
+		return nil, fmt.Errorf("invalid target %q: period must be day, week, or month", s)
+	}
+	return &ThroughputTarget{Count: count, Period: period}, nil
+}
+
+// String returns the target in the form accepted by ParseThroughputTarget
+func (t *ThroughputTarget) String() string {
+	return fmt.Sprintf("%d/%s", t.Count, t.Period)
+}
+
+// PeriodStart returns the start of the period containing now. Weeks start
+// on Monday, matching juggle week.
+func (t *ThroughputTarget) PeriodStart(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch t.Period {
+	case PeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func (t *ThroughputTarget) nextPeriod(start time.Time) time.Time {
+	switch t.Period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// previousPeriod returns the start of the period before the one starting at start
+func (t *ThroughputTarget) previousPeriod(start time.Time) time.Time {
+	switch t.Period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, -7)
+	case PeriodMonth:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+// PeriodCount is how many balls were completed in one period
+type PeriodCount struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Done   int       `json:"done"`
+	Judged bool      `json:"judged"` // False for periods that started before the target was set
+}
+
+// Met returns true if the period reached the target count
+func (p PeriodCount) Met(target *ThroughputTarget) bool {
+	return p.Done >= target.Count
+}
+
+// ThroughputProgress compares a session's completed balls with its target
+type ThroughputProgress struct {
+	SessionID string            `json:"session_id"`
+	Target    *ThroughputTarget `json:"target"`
+	Current   PeriodCount       `json:"current"`
+	Previous  []PeriodCount     `json:"previous"` // Most recent first
+	Behind    bool              `json:"behind"`   // Missed the target in the last two judged periods
+}
+
+// SessionThroughput returns a session's progress toward its throughput
+// target, counting balls tagged with the session that were completed or
+// researched in each period. Pass archived balls too, since completed balls
+// are often archived. Returns nil if the session has no target.
+func SessionThroughput(sess *JuggleSession, balls []*Ball, now time.Time) *ThroughputProgress {
+	target := sess.Target
+	if target == nil || target.Count < 1 {
+		return nil
+	}
+
+	progress := &ThroughputProgress{SessionID: sess.ID, Target: target}
+	start := target.PeriodStart(now)
+	progress.Current = countPeriod(sess.ID, balls, start, target.nextPeriod(start))
+	progress.Current.Judged = !start.Before(target.SetAt)
+
+	progress.Behind = true
+	for i := 0; i < behindPeriods; i++ {
+		end := start
+		start = target.previousPeriod(start)
+		period := countPeriod(sess.ID, balls, start, end)
+		period.Judged = !start.Before(target.SetAt)
+		progress.Previous = append(progress.Previous, period)
+		if !period.Judged || period.Met(target) {
+			progress.Behind = false
+		}
+	}
+	return progress
+}
+
+// countPeriod counts the session's balls completed in [start, end)
+func countPeriod(sessionID string, balls []*Ball, start, end time.Time) PeriodCount {
+	period := PeriodCount{Start: start, End: end}
+	seen := make(map[string]bool)
+	for _, ball := range balls {
+		if ball.CompletedAt == nil || seen[ball.ID] {
+			continue
+		}
+		if ball.State != StateComplete && ball.State != StateResearched {
+			continue
+		}
+		if ball.CompletedAt.Before(start) || !ball.CompletedAt.Before(end) {
+			continue
+		}
+		if ball.CountsTowardSession(sessionID) {
+			seen[ball.ID] = true
+			period.Done++
+		}
+	}
+	return period
+}
+
+// NeedsAlert returns true if the session is behind and no alert has been
+// sent yet for the current period
+func (p *ThroughputProgress) NeedsAlert() bool {
+	if !p.Behind {
+		return false
+	}
+	alerted := p.Target.AlertedFor
+	return alerted == nil || !alerted.Equal(p.Current.Start)
+}
+
+// Summary returns progress for display, e.g. "2/5 this week"
+func (p *ThroughputProgress) Summary() string {
+	summary := fmt.Sprintf("%d/%d this %s", p.Current.Done, p.Target.Count, p.Target.Period)
+	if p.Behind {
+		summary += fmt.Sprintf(" (behind: missed the last %d %ss)", behindPeriods, p.Target.Period)
+	}
+	return summary
+}
+
+// UpdateSessionTarget sets or clears (nil) a session's throughput target
+func (s *SessionStore) UpdateSessionTarget(id string, target *ThroughputTarget) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+
+	session.SetTarget(target)
+	return s.saveSession(session)
+}
+
+// MarkTargetAlerted records that a behind alert was sent for the period
+// starting at periodStart, so it is only sent once per period
+func (s *SessionStore) MarkTargetAlerted(id string, periodStart time.Time) error {
+	session, err := s.LoadSession(id)
+	if err != nil {
+		return err
+	}
+	if session.Target == nil {
+		return nil
+	}
+
+	session.Target.AlertedFor = &periodStart
+	return s.saveSession(session)
+}
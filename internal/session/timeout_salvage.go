@@ -0,0 +1,96 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const timeoutSalvageFile = "timeout_salvage.json"
+
+// TimeoutSalvage records what an agent iteration got done before it hit
+// its timeout, so the next iteration is told where the work was left
+// instead of starting blind. It's kept until an iteration finishes in time.
+type TimeoutSalvage struct {
+	SessionID       string        `json:"session_id"`
+	Iteration       int           `json:"iteration"`
+	Timeout         time.Duration `json:"timeout"`
+	TimedOutAt      time.Time     `json:"timed_out_at"`
+	CompletedBalls  []string      `json:"completed_balls,omitempty"`  // "<short ID> <title>" of balls completed before the timeout
+	ProgressEntries []string      `json:"progress_entries,omitempty"` // Progress lines logged before the timeout
+	Signal          string        `json:"signal,omitempty"`           // Signal found in the partial output, e.g. "CONTINUE: feat: add login"
+	OutputTail      string        `json:"output_tail,omitempty"`      // End of the partial output, scrubbed
+}
+
+// Summary describes the timed out iteration in one line, e.g. "Iteration 2
+// timed out after 5m0s, after completing a1b2c3d4 Add login form"
+func (t *TimeoutSalvage) Summary() string {
+	summary := fmt.Sprintf("Iteration %d timed out", t.Iteration)
+	if t.Timeout > 0 {
+		summary += fmt.Sprintf(" after %v", t.Timeout)
+	}
+
+	var done []string
+	if len(t.CompletedBalls) > 0 {
+		done = append(done, "completing "+strings.Join(t.CompletedBalls, ", "))
+	}
+	if n := len(t.ProgressEntries); n > 0 {
+		done = append(done, fmt.Sprintf("logging %d progress line(s)", n))
+	}
+	if t.Signal != "" {
+		done = append(done, "signaling "+t.Signal)
+	}
+	if len(done) == 0 {
+		return summary + ", before completing anything"
+	}
+	return summary + ", after " + strings.Join(done, " and ")
+}
+
+// timeoutSalvageFilePath returns the path to a session's timeout salvage
+func (s *SessionStore) timeoutSalvageFilePath(id string) string {
+	return filepath.Join(s.sessionPath(id), timeoutSalvageFile)
+}
+
+// SaveTimeoutSalvage records what a timed out iteration got done
+func (s *SessionStore) SaveTimeoutSalvage(t *TimeoutSalvage) error {
+	if err := os.MkdirAll(s.sessionPath(t.SessionID), 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timeout salvage: %w", err)
+	}
+	if err := os.WriteFile(s.timeoutSalvageFilePath(t.SessionID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write timeout salvage: %w", err)
+	}
+	return nil
+}
+
+// LoadTimeoutSalvage returns what the session's last timed out iteration
+// got done, or nil if its last iteration didn't time out
+func (s *SessionStore) LoadTimeoutSalvage(id string) (*TimeoutSalvage, error) {
+	data, err := os.ReadFile(s.timeoutSalvageFilePath(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timeout salvage: %w", err)
+	}
+	var t TimeoutSalvage
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse timeout salvage: %w", err)
+	}
+	return &t, nil
+}
+
+// ClearTimeoutSalvage forgets the last timed out iteration, once one has
+// finished in time
+func (s *SessionStore) ClearTimeoutSalvage(id string) error {
+	if err := os.Remove(s.timeoutSalvageFilePath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear timeout salvage: %w", err)
+	}
+	return nil
+}
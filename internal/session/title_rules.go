@@ -0,0 +1,196 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TitleRules are a project's optional conventions for ball titles. Titles
+// that break them are warned about, never refused; 'juggle lint titles
+// --fix' normalizes existing balls.
+type TitleRules struct {
+	MaxLength         int      `json:"max_length,omitempty"`         // Longest title, in characters (0 = no limit)
+	Imperative        bool     `json:"imperative,omitempty"`         // Start with a verb in the imperative ("Add", not "Added" or "Adding")
+	Capitalize        bool     `json:"capitalize,omitempty"`         // Start with a capital letter and don't end with a period
+	ForbiddenPrefixes []string `json:"forbidden_prefixes,omitempty"` // Prefixes titles may not start with, ignoring case (e.g. "WIP", "TODO:")
+}
+
+// IsZero returns true if no rule is set
+func (r *TitleRules) IsZero() bool {
+	return r == nil || r.MaxLength == 0 && !r.Imperative && !r.Capitalize && len(r.ForbiddenPrefixes) == 0
+}
+
+// imperativeVerbs are the verbs the imperative heuristic recognizes, in
+// their base form. Titles starting with another form of one of them
+// ("Added", "Fixes", "Updating") break the Imperative rule.
+var imperativeVerbs = map[string]bool{
+	"add": true, "allow": true, "build": true, "bump": true, "cache": true,
+	"change": true, "check": true, "clean": true, "clarify": true, "configure": true,
+	"convert": true, "create": true, "debug": true, "delete": true, "deprecate": true,
+	"detect": true, "disable": true, "document": true, "drop": true, "enable": true,
+	"ensure": true, "expose": true, "extract": true, "fix": true, "handle": true,
+	"hide": true, "implement": true, "improve": true, "introduce": true, "investigate": true,
+	"load": true, "log": true, "make": true, "merge": true, "migrate": true,
+	"move": true, "optimize": true, "parse": true, "prevent": true, "refactor": true,
+	"reduce": true, "remove": true, "rename": true, "replace": true, "research": true,
+	"restore": true, "rewrite": true, "run": true, "save": true, "set": true,
+	"show": true, "simplify": true, "speed": true, "split": true, "stop": true,
+	"support": true, "test": true, "track": true, "update": true, "upgrade": true,
+	"use": true, "validate": true, "verify": true, "wire": true, "write": true,
+}
+
+// CheckTitle returns a warning for each rule the title breaks, or nil
+func (r *TitleRules) CheckTitle(title string) []string {
+	if r.IsZero() {
+		return nil
+	}
+	var warnings []string
+	if prefix := r.forbiddenPrefix(title); prefix != "" {
+		warnings = append(warnings, fmt.Sprintf("starts with forbidden prefix %q", prefix))
+	}
+	if r.Capitalize {
+		if first, _ := utf8.DecodeRuneInString(title); unicode.IsLower(first) {
+			warnings = append(warnings, "should start with a capital letter")
+		}
+		if strings.HasSuffix(title, ".") && !strings.HasSuffix(title, "...") {
+			warnings = append(warnings, "should not end with a period")
+		}
+	}
+	if r.Imperative {
+		if word, verb := nonImperativeVerb(title); verb != "" {
+			warnings = append(warnings, fmt.Sprintf("use the imperative mood (%q, not %q)", capitalizeFirst(verb), word))
+		}
+	}
+	if n := utf8.RuneCountInString(title); r.MaxLength > 0 && n > r.MaxLength {
+		warnings = append(warnings, fmt.Sprintf("is %d characters long (max %d)", n, r.MaxLength))
+	}
+	return warnings
+}
+
+// FixTitle returns the title normalized to the rules: forbidden prefixes
+// stripped, the leading verb put in the imperative, and the first letter
+// capitalized without a trailing period. Titles over MaxLength are left
+// for a person to shorten.
+func (r *TitleRules) FixTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	if r.IsZero() {
+		return title
+	}
+	for prefix := r.forbiddenPrefix(title); prefix != ""; prefix = r.forbiddenPrefix(title) {
+		title = strings.TrimLeft(title[len(prefix):], " :-–—")
+	}
+	if r.Imperative {
+		if word, verb := nonImperativeVerb(title); verb != "" {
+			if unicode.IsUpper([]rune(word)[0]) {
+				verb = capitalizeFirst(verb)
+			}
+			title = verb + title[len(word):]
+		}
+	}
+	if r.Capitalize {
+		title = capitalizeFirst(strings.TrimRight(title, "."))
+	}
+	return title
+}
+
+// forbiddenPrefix returns the text of the forbidden prefix the title
+// starts with (as written in the title), or ""
+func (r *TitleRules) forbiddenPrefix(title string) string {
+	for _, prefix := range r.ForbiddenPrefixes {
+		if prefix == "" || len(title) < len(prefix) || !strings.EqualFold(title[:len(prefix)], prefix) {
+			continue
+		}
+		// A word prefix ("WIP") must end at a word boundary, so "WIPE" is fine
+		rest := title[len(prefix):]
+		last, _ := utf8.DecodeLastRuneInString(prefix)
+		next, _ := utf8.DecodeRuneInString(rest)
+		if rest != "" && isWordRune(last) && isWordRune(next) {
+			continue
+		}
+		return title[:len(prefix)]
+	}
+	return ""
+}
+
+// nonImperativeVerb returns the title's first word and its imperative form
+// if the word is another form of a known verb, or "", "" otherwise
+func nonImperativeVerb(title string) (word, verb string) {
+	word, _, _ = strings.Cut(title, " ")
+	word = strings.TrimRightFunc(word, func(r rune) bool { return !isWordRune(r) })
+	lower := strings.ToLower(word)
+	if lower == "" || imperativeVerbs[lower] {
+		return "", ""
+	}
+	for _, base := range verbBaseCandidates(lower) {
+		if imperativeVerbs[base] {
+			return word, base
+		}
+	}
+	return "", ""
+}
+
+// verbBaseCandidates returns the possible base forms of an inflected verb
+// ("fixes" → "fix", "added" → "add", "making" → "make", "stopped" → "stop")
+func verbBaseCandidates(word string) []string {
+	var candidates []string
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		candidates = append(candidates, stem, stem+"e")
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] {
+			candidates = append(candidates, stem[:n-1]) // stopped → stop
+		}
+		if strings.HasSuffix(stem, "i") && suffix != "ing" {
+			candidates = append(candidates, strings.TrimSuffix(stem, "i")+"y") // simplified → simplify
+		}
+	}
+	return candidates
+}
+
+// isWordRune returns true for letters and digits
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// capitalizeFirst upper-cases the first letter of s
+func capitalizeFirst(s string) string {
+	first, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(first)) + s[size:]
+}
+
+// GetProjectTitleRules returns the title rules from project config, or nil
+// if none are set
+func GetProjectTitleRules(projectDir string) (*TitleRules, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if config.TitleRules.IsZero() {
+		return nil, nil
+	}
+	return config.TitleRules, nil
+}
+
+// UpdateProjectTitleRules sets the title rules in project config (nil or
+// empty rules clear them)
+func UpdateProjectTitleRules(projectDir string, rules *TitleRules) error {
+	if rules != nil && rules.MaxLength < 0 {
+		return fmt.Errorf("invalid max length %d (must be 0 or more)", rules.MaxLength)
+	}
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	config.TitleRules = rules
+	if rules.IsZero() {
+		config.TitleRules = nil
+	}
+	return SaveProjectConfig(projectDir, config)
+}
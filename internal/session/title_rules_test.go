@@ -0,0 +1,77 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTitleRules_CheckTitle(t *testing.T) {
+	rules := &TitleRules{
+		MaxLength:         30,
+		Imperative:        true,
+		Capitalize:        true,
+		ForbiddenPrefixes: []string{"WIP", "TODO:"},
+	}
+
+	tests := []struct {
+		title string
+		want  []string // Substrings of the expected warnings, in order
+	}{
+		{"Add retry budget", nil},
+		{"Wipe the cache", nil}, // "WIP" only matches a whole word
+		{"Processing queue cleanup", nil},
+		{"wip: added retry budget.", []string{`prefix "wip"`, "capital letter", "period"}},
+		{"Fixes the login redirect", []string{`"Fix", not "Fixes"`}},
+		{"Simplified config loading", []string{`"Simplify", not "Simplified"`}},
+		{"Stopping agents on quota", []string{`"Stop", not "Stopping"`}},
+		{"Update the docs for every single command", []string{"is 40 characters long (max 30)"}},
+	}
+	for _, tt := range tests {
+		got := rules.CheckTitle(tt.title)
+		if len(got) != len(tt.want) {
+			t.Errorf("CheckTitle(%q) = %q, want %d warning(s)", tt.title, got, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.Contains(got[i], want) {
+				t.Errorf("CheckTitle(%q)[%d] = %q, want it to contain %q", tt.title, i, got[i], want)
+			}
+		}
+	}
+
+	var none *TitleRules
+	if got := none.CheckTitle("wip: anything."); got != nil {
+		t.Errorf("Expected no warnings without rules, got %q", got)
+	}
+}
+
+func TestTitleRules_FixTitle(t *testing.T) {
+	rules := &TitleRules{
+		MaxLength:         10,
+		Imperative:        true,
+		Capitalize:        true,
+		ForbiddenPrefixes: []string{"WIP", "[bug]"},
+	}
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"wip: added  retry budget.", "Add retry budget"},
+		{"[BUG] - WIP fixing the login redirect", "Fix the login redirect"},
+		{"making the board faster", "Make the board faster"},
+		{"Update docs", "Update docs"},
+	}
+	for _, tt := range tests {
+		got := rules.FixTitle(tt.title)
+		if got != tt.want {
+			t.Errorf("FixTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+		// Only the length rule, which needs a person, can still be broken
+		for _, warning := range rules.CheckTitle(got) {
+			if !strings.Contains(warning, "characters long") {
+				t.Errorf("FixTitle(%q) = %q still breaks a rule: %s", tt.title, got, warning)
+			}
+		}
+	}
+}
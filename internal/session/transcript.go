@@ -0,0 +1,239 @@
+package session
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	transcriptsDir = "transcripts" // Ball transcript attachments, under .juggle
+	runsDir        = "runs"        // Per-iteration agent prompts and output, under .juggle
+
+	// KeptRunOutputs is how many agent runs keep their iteration output
+	// available for attaching; older runs are pruned
+	KeptRunOutputs = 20
+)
+
+// Transcript sources
+const (
+	TranscriptSourceAgent = "agent" // An iteration of an agent run
+	TranscriptSourceChat  = "chat"  // Text supplied by a human (file or stdin)
+)
+
+// Transcript references a chunk of agent or chat transcript attached to a
+// ball. The text itself is stored gzip-compressed under .juggle/transcripts
+// so the ball record stays small.
+type Transcript struct {
+	File       string    `json:"file"`   // File name under .juggle/transcripts
+	Source     string    `json:"source"` // "agent" or "chat"
+	RunID      string    `json:"run_id,omitempty"`
+	Iteration  int       `json:"iteration,omitempty"`
+	Note       string    `json:"note,omitempty"` // Why this transcript matters
+	Lines      int       `json:"lines"`
+	AttachedAt time.Time `json:"attached_at"`
+}
+
+// Label returns a short description of where the transcript came from
+func (t Transcript) Label() string {
+	label := "chat"
+	if t.Source == TranscriptSourceAgent {
+		label = fmt.Sprintf("run %s, iteration %d", t.RunID, t.Iteration)
+	}
+	if t.Note != "" {
+		label += ": " + t.Note
+	}
+	return label
+}
+
+// AttachTranscript records a transcript attachment on the ball
+func (b *Ball) AttachTranscript(t Transcript) {
+	b.Transcripts = append(b.Transcripts, t)
+	b.UpdateActivity()
+}
+
+// transcriptsPath returns the store's transcripts directory
+func (s *Store) transcriptsPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), transcriptsDir)
+}
+
+// SaveTranscript compresses content into the transcripts directory and
+// returns the transcript to attach to the ball
+func (s *Store) SaveTranscript(ballID, content string, t Transcript) (Transcript, error) {
+	if strings.TrimSpace(content) == "" {
+		return t, fmt.Errorf("transcript is empty")
+	}
+	if t.AttachedAt.IsZero() {
+		t.AttachedAt = time.Now()
+	}
+	t.File = fmt.Sprintf("%s-%d.txt.gz", ballID, t.AttachedAt.UnixNano())
+	t.Lines = strings.Count(strings.TrimRight(content, "\n"), "\n") + 1
+
+	if err := writeGzipFile(filepath.Join(s.transcriptsPath(), t.File), content); err != nil {
+		return t, fmt.Errorf("failed to save transcript: %w", err)
+	}
+	return t, nil
+}
+
+// LoadTranscript returns the text of an attached transcript
+func (s *Store) LoadTranscript(t Transcript) (string, error) {
+	content, err := readGzipFile(filepath.Join(s.transcriptsPath(), filepath.Base(t.File)))
+	if err != nil {
+		return "", fmt.Errorf("failed to load transcript %s: %w", t.File, err)
+	}
+	return content, nil
+}
+
+// AgentRunID returns the ID of the agent run started at startTime
+func AgentRunID(startTime time.Time) string {
+	return fmt.Sprintf("%d", startTime.UnixNano())
+}
+
+// runDir returns the directory holding a run's iteration output
+func (s *AgentHistoryStore) runDir(runID string) string {
+	return filepath.Join(s.projectDir, s.config.JuggleDirName, runsDir, filepath.Base(runID))
+}
+
+// SaveIterationOutput stores the output of one iteration of an agent run,
+// replacing output saved for an earlier attempt at the same iteration
+func (s *AgentHistoryStore) SaveIterationOutput(runID string, iteration int, output string) error {
+	path := filepath.Join(s.runDir(runID), fmt.Sprintf("iteration-%d.txt.gz", iteration))
+	if err := writeGzipFile(path, output); err != nil {
+		return fmt.Errorf("failed to save iteration output: %w", err)
+	}
+	return nil
+}
+
+// SaveIterationPrompt stores the prompt one iteration of an agent run was
+// given, replacing the prompt of an earlier attempt at the same iteration
+func (s *AgentHistoryStore) SaveIterationPrompt(runID string, iteration int, prompt string) error {
+	path := filepath.Join(s.runDir(runID), fmt.Sprintf("prompt-%d.txt.gz", iteration))
+	if err := writeGzipFile(path, prompt); err != nil {
+		return fmt.Errorf("failed to save iteration prompt: %w", err)
+	}
+	return nil
+}
+
+// LoadIterationPrompt returns the prompt of one iteration of an agent run
+func (s *AgentHistoryStore) LoadIterationPrompt(runID string, iteration int) (string, error) {
+	path := filepath.Join(s.runDir(runID), fmt.Sprintf("prompt-%d.txt.gz", iteration))
+	content, err := readGzipFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no prompt saved for run %s iteration %d", runID, iteration)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load iteration prompt: %w", err)
+	}
+	return content, nil
+}
+
+// LoadIterationOutput returns the output of one iteration of an agent run
+func (s *AgentHistoryStore) LoadIterationOutput(runID string, iteration int) (string, error) {
+	path := filepath.Join(s.runDir(runID), fmt.Sprintf("iteration-%d.txt.gz", iteration))
+	content, err := readGzipFile(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no output saved for run %s iteration %d", runID, iteration)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load iteration output: %w", err)
+	}
+	return content, nil
+}
+
+// FindRecord returns the run with the given ID. "latest" selects the most
+// recent run.
+func (s *AgentHistoryStore) FindRecord(runID string) (*AgentRunRecord, error) {
+	records, err := s.LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+	if runID == "latest" {
+		if len(records) == 0 {
+			return nil, fmt.Errorf("no agent runs recorded")
+		}
+		return records[0], nil
+	}
+	for _, record := range records {
+		if record.ID == runID {
+			return record, nil
+		}
+	}
+	return nil, fmt.Errorf("agent run not found: %s", runID)
+}
+
+// PruneIterationOutputs removes iteration output for all but the keep most
+// recent runs
+func (s *AgentHistoryStore) PruneIterationOutputs(keep int) error {
+	dir := filepath.Join(s.projectDir, s.config.JuggleDirName, runsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read runs directory: %w", err)
+	}
+
+	// Run IDs are start timestamps, so newest sorts last numerically
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if _, err := strconv.ParseInt(entry.Name(), 10, 64); entry.IsDir() && err == nil {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.ParseInt(ids[i], 10, 64)
+		b, _ := strconv.ParseInt(ids[j], 10, 64)
+		return a < b
+	})
+	for len(ids) > keep {
+		if err := os.RemoveAll(filepath.Join(dir, ids[0])); err != nil {
+			return fmt.Errorf("failed to prune run output: %w", err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// writeGzipFile writes gzip-compressed content, creating parent directories
+func writeGzipFile(path, content string) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(content)); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// readGzipFile reads and decompresses a file written by writeGzipFile
+func readGzipFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TUI view layouts
+const (
+	ViewSplit = "split" // Sessions, balls, and bottom pane (default)
+	ViewList  = "list"  // Balls panel at full width, sessions panel hidden
+	ViewBoard = "board" // Balls grouped into one column per state
+)
+
+// tuiStateFile stores remembered TUI state in the config home, keyed by
+// project directory, so it never ends up in a git-shared .juggle store
+const tuiStateFile = "tui-state.json"
+
+// TUIState is the TUI state remembered between launches for a project.
+// The layout isn't remembered: it comes from --view or the default_view
+// setting so the configured startup view always applies.
+type TUIState struct {
+	SessionID      string          `json:"session_id,omitempty"`
+	ActivePanel    string          `json:"active_panel,omitempty"` // "sessions", "balls", or "activity"
+	BottomPane     string          `json:"bottom_pane,omitempty"`  // "activity", "detail", or "split"
+	Sort           string          `json:"sort,omitempty"`         // Sort name as accepted by "board --sort"
+	FilterStates   map[string]bool `json:"filter_states,omitempty"`
+	Columns        []string        `json:"columns,omitempty"` // Visible optional columns
+	Cursor         int             `json:"cursor,omitempty"`
+	BallsScroll    int             `json:"balls_scroll,omitempty"`
+	ActivityScroll int             `json:"activity_scroll,omitempty"`
+	DetailScroll   int             `json:"detail_scroll,omitempty"`
+
+	// Projects collapsed in all-projects mode (directories)
+	CollapsedProjects []string `json:"collapsed_projects,omitempty"`
+}
+
+// ValidateView checks if a view layout name is valid
+func ValidateView(name string) bool {
+	return name == ViewSplit || name == ViewList || name == ViewBoard
+}
+
+// SetDefaultView sets the layout the TUI starts in
+func (c *Config) SetDefaultView(name string) error {
+	if !ValidateView(name) {
+		return fmt.Errorf("invalid view %q (must be %s, %s, or %s)", name, ViewSplit, ViewList, ViewBoard)
+	}
+	c.DefaultView = name
+	return nil
+}
+
+// GetDefaultView returns the configured startup layout, defaulting to split
+func (c *Config) GetDefaultView() string {
+	if c.DefaultView == "" {
+		return ViewSplit
+	}
+	return c.DefaultView
+}
+
+// UpdateGlobalDefaultViewWithOptions sets the startup layout in global config
+func UpdateGlobalDefaultViewWithOptions(opts ConfigOptions, name string) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	if err := config.SetDefaultView(name); err != nil {
+		return err
+	}
+	return config.SaveWithOptions(opts)
+}
+
+// ClearGlobalDefaultViewWithOptions removes the startup layout from global config
+func ClearGlobalDefaultViewWithOptions(opts ConfigOptions) error {
+	config, err := LoadConfigWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	config.DefaultView = ""
+	return config.SaveWithOptions(opts)
+}
+
+// tuiStatePath returns the path of the remembered TUI state file
+func tuiStatePath(opts ConfigOptions) (string, error) {
+	if opts.ConfigHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		opts.ConfigHome = home
+	}
+	return filepath.Join(opts.ConfigHome, opts.JuggleDirName, tuiStateFile), nil
+}
+
+// loadTUIStates reads every project's remembered TUI state
+func loadTUIStates(path string) (map[string]*TUIState, error) {
+	states := make(map[string]*TUIState)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TUI state: %w", err)
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse TUI state: %w", err)
+	}
+	return states, nil
+}
+
+// LoadTUIStateWithOptions returns the TUI state remembered for projectDir,
+// or nil if the TUI hasn't been used there yet
+func LoadTUIStateWithOptions(opts ConfigOptions, projectDir string) (*TUIState, error) {
+	path, err := tuiStatePath(opts)
+	if err != nil {
+		return nil, err
+	}
+	states, err := loadTUIStates(path)
+	if err != nil {
+		return nil, err
+	}
+	return states[projectDir], nil
+}
+
+// SaveTUIStateWithOptions remembers the TUI state for projectDir
+func SaveTUIStateWithOptions(opts ConfigOptions, projectDir string, state *TUIState) error {
+	path, err := tuiStatePath(opts)
+	if err != nil {
+		return err
+	}
+	states, err := loadTUIStates(path)
+	if err != nil {
+		// A corrupted state file only loses remembered positions; start over
+		states = make(map[string]*TUIState)
+	}
+	states[projectDir] = state
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal TUI state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write TUI state: %w", err)
+	}
+	return nil
+}
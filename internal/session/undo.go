@@ -0,0 +1,286 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const undoFile = "undo.jsonl"
+
+// UndoLevels is how many ball changes are kept in .juggle/undo.jsonl
+const UndoLevels = 100
+
+// Undo actions, named after the store operation that was recorded
+const (
+	UndoActionCreate    = "create"
+	UndoActionUpdate    = "update"
+	UndoActionDelete    = "delete"
+	UndoActionArchive   = "archive"
+	UndoActionUnarchive = "unarchive"
+)
+
+// Where a ball lives before or after a change
+const (
+	undoInActive  = "active"
+	undoInArchive = "archive"
+)
+
+var (
+	// ErrNothingToUndo is returned by Undo when the undo history is empty.
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	// ErrNothingToRedo is returned by Redo when no change has been undone.
+	ErrNothingToRedo = errors.New("nothing to redo")
+)
+
+// UndoEntry records one change to a ball: its state before and after, and
+// whether it was in the active or archived balls. A nil Before means the
+// change created the ball; a nil After means it deleted it.
+type UndoEntry struct {
+	Action    string    `json:"action"`
+	BallID    string    `json:"ball_id"`
+	Before    *Ball     `json:"before,omitempty"`
+	BeforeIn  string    `json:"before_in,omitempty"`
+	After     *Ball     `json:"after,omitempty"`
+	AfterIn   string    `json:"after_in,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Undone    bool      `json:"undone,omitempty"` // Undone entries are the redo history
+}
+
+// Title returns the ball's title from whichever side of the change has it
+func (e *UndoEntry) Title() string {
+	if e.After != nil {
+		return e.After.Title
+	}
+	if e.Before != nil {
+		return e.Before.Title
+	}
+	return ""
+}
+
+// undoPath returns the path to the undo history, next to balls.jsonl
+func (s *Store) undoPath() string {
+	return filepath.Join(filepath.Dir(s.ballsPath), undoFile)
+}
+
+// recordUndo adds a change to the undo history. A new change discards the
+// redo history, and only the last UndoLevels changes are kept. Archiving a
+// ball straight after updating it (as completing does) is recorded as one
+// change. Undo history is best-effort, so callers ignore its errors rather
+// than fail the change itself.
+func (s *Store) recordUndo(entry UndoEntry) error {
+	_, unlock, err := acquireFileLock(s.undoPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := s.loadUndoEntries()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if !e.Undone {
+			kept = append(kept, e)
+		}
+	}
+	entries = kept
+
+	entry.Timestamp = time.Now()
+	if n := len(entries); n > 0 && entry.Action == UndoActionArchive {
+		if last := entries[n-1]; last.Action == UndoActionUpdate && last.BallID == entry.BallID {
+			entry.Before = last.Before
+			entries = entries[:n-1]
+		}
+	}
+	entries = append(entries, &entry)
+	if len(entries) > UndoLevels {
+		entries = entries[len(entries)-UndoLevels:]
+	}
+	return s.writeUndoEntries(entries)
+}
+
+// UndoHistory returns the recorded changes, oldest first. Entries marked
+// Undone can be redone.
+func (s *Store) UndoHistory() ([]*UndoEntry, error) {
+	return s.loadUndoEntries()
+}
+
+// Undo reverts the most recent change that hasn't been undone, and
+// returns it. Returns ErrNothingToUndo if there is none.
+func (s *Store) Undo() (*UndoEntry, error) {
+	return s.stepUndoHistory(true)
+}
+
+// Redo reapplies the most recently undone change, and returns it. Returns
+// ErrNothingToRedo if there is none.
+func (s *Store) Redo() (*UndoEntry, error) {
+	return s.stepUndoHistory(false)
+}
+
+// stepUndoHistory undoes or redoes one entry. Both ball files and the undo
+// history are locked so the change and its Undone mark land together.
+func (s *Store) stepUndoHistory(undo bool) (*UndoEntry, error) {
+	_, unlockBalls, err := acquireFileLock(s.ballsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock balls file: %w", err)
+	}
+	defer unlockBalls()
+
+	_, unlockArchive, err := acquireFileLock(s.archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock archive file: %w", err)
+	}
+	defer unlockArchive()
+
+	_, unlockUndo, err := acquireFileLock(s.undoPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock undo history: %w", err)
+	}
+	defer unlockUndo()
+
+	entries, err := s.loadUndoEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Undone entries always trail the rest, so the next entry to undo is
+	// the last one not undone, and the next to redo is the first undone.
+	var entry *UndoEntry
+	if undo {
+		for i := len(entries) - 1; i >= 0 && entry == nil; i-- {
+			if !entries[i].Undone {
+				entry = entries[i]
+			}
+		}
+		if entry == nil {
+			return nil, ErrNothingToUndo
+		}
+		err = s.placeBallUnlocked(entry.BallID, entry.Before, entry.BeforeIn)
+	} else {
+		for i := 0; i < len(entries) && entry == nil; i++ {
+			if entries[i].Undone {
+				entry = entries[i]
+			}
+		}
+		if entry == nil {
+			return nil, ErrNothingToRedo
+		}
+		err = s.placeBallUnlocked(entry.BallID, entry.After, entry.AfterIn)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Undone = undo
+	if err := s.writeUndoEntries(entries); err != nil {
+		return nil, fmt.Errorf("failed to update undo history: %w", err)
+	}
+	return entry, nil
+}
+
+// placeBallUnlocked puts ball (or nothing, if nil) in place of the ball with
+// the given ID: in the active balls or the archive, as where says. The ball
+// keeps its position if it's already there. Caller must hold both locks.
+func (s *Store) placeBallUnlocked(id string, ball *Ball, where string) error {
+	balls, err := s.LoadBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load balls: %w", err)
+	}
+	archived, err := s.LoadArchivedBalls()
+	if err != nil {
+		return fmt.Errorf("failed to load archived balls: %w", err)
+	}
+
+	place := func(list []*Ball, put bool) []*Ball {
+		out := make([]*Ball, 0, len(list)+1)
+		placed := false
+		for _, b := range list {
+			if b.ID != id {
+				out = append(out, b)
+			} else if put && !placed {
+				out = append(out, ball)
+				placed = true
+			}
+		}
+		if put && !placed {
+			out = append(out, ball)
+		}
+		return out
+	}
+	balls = place(balls, ball != nil && where != undoInArchive)
+	archived = place(archived, ball != nil && where == undoInArchive)
+
+	if err := s.writeArchivedBallsUnlocked(archived); err != nil {
+		return fmt.Errorf("failed to update archive: %w", err)
+	}
+	if err := s.writeBallsUnlocked(balls); err != nil {
+		return fmt.Errorf("failed to update balls: %w", err)
+	}
+	if ball == nil {
+		// Undoing a create removes the ball, but its ID was still issued
+		return s.retireBallID(id)
+	}
+	return nil
+}
+
+// loadUndoEntries reads the undo history, skipping malformed lines
+func (s *Store) loadUndoEntries() ([]*UndoEntry, error) {
+	f, err := os.Open(s.undoPath())
+	if os.IsNotExist(err) {
+		return []*UndoEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open undo history: %w", err)
+	}
+	defer f.Close()
+
+	entries := make([]*UndoEntry, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry UndoEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading undo history: %w", err)
+	}
+	return entries, nil
+}
+
+// writeUndoEntries rewrites the undo history. Caller must hold its lock.
+func (s *Store) writeUndoEntries(entries []*UndoEntry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal undo entry: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+
+	tempPath := s.undoPath() + ".tmp"
+	if err := os.WriteFile(tempPath, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	if err := os.Rename(tempPath, s.undoPath()); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename undo history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func newUndoTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	return store
+}
+
+func newUndoTestBall(t *testing.T, store *Store, title string) *Ball {
+	t.Helper()
+	ball, err := NewBall(store.ProjectDir(), title, PriorityMedium)
+	if err != nil {
+		t.Fatalf("Failed to create ball: %v", err)
+	}
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+	return ball
+}
+
+func TestStore_UndoRedoUpdate(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "Original")
+
+	ball.Title = "Renamed"
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	entry, err := store.Undo()
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if entry.Action != UndoActionUpdate || entry.BallID != ball.ID {
+		t.Errorf("Expected to undo the update of %s, got %s of %s", ball.ID, entry.Action, entry.BallID)
+	}
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.Title != "Original" {
+		t.Fatalf("Expected title restored to Original, got %+v", got)
+	}
+
+	if _, err := store.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.Title != "Renamed" {
+		t.Fatalf("Expected title Renamed after redo, got %+v", got)
+	}
+	if _, err := store.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected ErrNothingToRedo, got %v", err)
+	}
+}
+
+func TestStore_UndoDeleteAndCreate(t *testing.T) {
+	store := newUndoTestStore(t)
+	first := newUndoTestBall(t, store, "First")
+	second := newUndoTestBall(t, store, "Second")
+
+	if err := store.DeleteBall(first.ID); err != nil {
+		t.Fatalf("Failed to delete ball: %v", err)
+	}
+	if _, err := store.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	balls, _ := store.LoadBalls()
+	if len(balls) != 2 {
+		t.Fatalf("Expected deleted ball restored, got %d balls", len(balls))
+	}
+
+	// Undoing the creation of the second ball removes it
+	entry, err := store.Undo()
+	if err != nil || entry.Action != UndoActionCreate || entry.BallID != second.ID {
+		t.Fatalf("Expected to undo creating %s, got %+v (%v)", second.ID, entry, err)
+	}
+	if got, _ := store.GetBallByID(second.ID); got != nil {
+		t.Error("Expected created ball to be removed")
+	}
+}
+
+func TestStore_UndoCompleteAndArchive(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "Finish me")
+
+	ball.MarkComplete("done")
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	if err := store.ArchiveBall(ball); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+
+	// Completing and archiving is one change
+	entry, err := store.Undo()
+	if err != nil || entry.Action != UndoActionArchive {
+		t.Fatalf("Expected to undo the archive, got %+v (%v)", entry, err)
+	}
+	got, _ := store.GetBallByID(ball.ID)
+	if got == nil || got.State != StatePending {
+		t.Fatalf("Expected ball back in active balls as pending, got %+v", got)
+	}
+	if archived, _ := store.LoadArchivedBalls(); len(archived) != 0 {
+		t.Errorf("Expected empty archive, got %d balls", len(archived))
+	}
+
+	if _, err := store.Redo(); err != nil {
+		t.Fatalf("Redo failed: %v", err)
+	}
+	if archived, _ := store.LoadArchivedBalls(); len(archived) != 1 || archived[0].State != StateComplete {
+		t.Errorf("Expected ball archived as complete after redo, got %+v", archived)
+	}
+}
+
+func TestStore_NewChangeClearsRedo(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "One")
+
+	if _, err := store.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	newUndoTestBall(t, store, "Two")
+	if _, err := store.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected a new change to clear redo history, got %v", err)
+	}
+	if got, _ := store.GetBallByID(ball.ID); got != nil {
+		t.Error("Expected the undone ball to stay removed")
+	}
+}
+
+func TestStore_UndoHistoryLimit(t *testing.T) {
+	store := newUndoTestStore(t)
+	ball := newUndoTestBall(t, store, "Counter")
+	for i := 0; i < UndoLevels+5; i++ {
+		ball.Title = fmt.Sprintf("Counter %d", i)
+		if err := store.UpdateBall(ball); err != nil {
+			t.Fatalf("Failed to update ball: %v", err)
+		}
+	}
+
+	entries, err := store.UndoHistory()
+	if err != nil {
+		t.Fatalf("Failed to load undo history: %v", err)
+	}
+	if len(entries) != UndoLevels {
+		t.Errorf("Expected %d entries, got %d", UndoLevels, len(entries))
+	}
+	for i := 0; i < UndoLevels; i++ {
+		if _, err := store.Undo(); err != nil {
+			t.Fatalf("Undo %d failed: %v", i+1, err)
+		}
+	}
+	if _, err := store.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// ACAssessment is the verdict on one acceptance criterion from `juggle verify`
+type ACAssessment struct {
+	Criterion string `json:"criterion"`          // The criterion as it read when assessed
+	Satisfied bool   `json:"satisfied"`          // Whether the changes meet it
+	Evidence  string `json:"evidence,omitempty"` // What shows it: files, lines, tests, or what's missing
+}
+
+// Verification records an agent's read-only check of a ball's acceptance
+// criteria against its changes. It's advisory: it informs a human review
+// or the decision to complete, but doesn't change the ball's state.
+type Verification struct {
+	Assessments []ACAssessment `json:"assessments"`
+	DiffRange   string         `json:"diff_range,omitempty"` // The changes that were checked, e.g. "a1b2c3..working copy"
+	VerifiedAt  time.Time      `json:"verified_at"`
+}
+
+// SatisfiedCount returns how many criteria were assessed as satisfied
+func (v *Verification) SatisfiedCount() int {
+	n := 0
+	for _, a := range v.Assessments {
+		if a.Satisfied {
+			n++
+		}
+	}
+	return n
+}
+
+// AllSatisfied returns true if every criterion was assessed as satisfied
+func (v *Verification) AllSatisfied() bool {
+	return len(v.Assessments) > 0 && v.SatisfiedCount() == len(v.Assessments)
+}
+
+// Label returns a one-line summary, e.g. "2/3 criteria satisfied (2026-01-02 15:04)"
+func (v *Verification) Label() string {
+	return fmt.Sprintf("%d/%d criteria satisfied (%s)", v.SatisfiedCount(), len(v.Assessments), v.VerifiedAt.Format("2006-01-02 15:04"))
+}
+
+// VerificationCurrent returns true if the ball has a verification and its
+// acceptance criteria haven't changed since
+func (b *Ball) VerificationCurrent() bool {
+	if b.Verification == nil || len(b.Verification.Assessments) != len(b.AcceptanceCriteria) {
+		return false
+	}
+	for i, a := range b.Verification.Assessments {
+		if a.Criterion != b.AcceptanceCriteria[i] {
+			return false
+		}
+	}
+	return true
+}
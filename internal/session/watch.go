@@ -0,0 +1,116 @@
+package session
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ValidateWatchGlob checks that a watch glob is a valid project-relative pattern.
+// Patterns use '/' separators; "**" matches any number of path segments.
+func ValidateWatchGlob(glob string) error {
+	if strings.TrimSpace(glob) == "" {
+		return fmt.Errorf("watch glob cannot be empty")
+	}
+	if path.IsAbs(glob) || filepath.IsAbs(glob) {
+		return fmt.Errorf("watch glob %q must be relative to the project root", glob)
+	}
+	for _, segment := range strings.Split(glob, "/") {
+		if segment == ".." {
+			return fmt.Errorf("watch glob %q cannot reference parent directories", glob)
+		}
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid watch glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// MatchWatchGlob reports whether a project-relative path matches a watch glob.
+// A trailing "/**" also matches the directory itself, so "src/auth/**" matches
+// every file under src/auth.
+func MatchWatchGlob(glob, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	return matchSegments(strings.Split(glob, "/"), strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// WatchGlobRoot returns the directory prefix of a glob before its first
+// wildcard segment, e.g. "src/auth" for "src/auth/**/*.go" and "." for "**/*.go".
+// Only this directory needs to be watched for the glob to match.
+func WatchGlobRoot(glob string) string {
+	var root []string
+	segments := strings.Split(glob, "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "*?[\\") || i == len(segments)-1 {
+			break
+		}
+		root = append(root, segment)
+	}
+	if len(root) == 0 {
+		return "."
+	}
+	return strings.Join(root, "/")
+}
+
+// AddWatchGlob adds a watch glob to the ball
+func (b *Ball) AddWatchGlob(glob string) error {
+	if err := ValidateWatchGlob(glob); err != nil {
+		return err
+	}
+	for _, g := range b.WatchGlobs {
+		if g == glob {
+			return nil // Already watching
+		}
+	}
+	b.WatchGlobs = append(b.WatchGlobs, glob)
+	b.UpdateActivity()
+	return nil
+}
+
+// RemoveWatchGlob removes a watch glob from the ball
+func (b *Ball) RemoveWatchGlob(glob string) bool {
+	for i, g := range b.WatchGlobs {
+		if g == glob {
+			b.WatchGlobs = append(b.WatchGlobs[:i], b.WatchGlobs[i+1:]...)
+			b.UpdateActivity()
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesWatchPath returns the first watch glob matching a project-relative path
+func (b *Ball) MatchesWatchPath(relPath string) (string, bool) {
+	for _, glob := range b.WatchGlobs {
+		if MatchWatchGlob(glob, relPath) {
+			return glob, true
+		}
+	}
+	return "", false
+}
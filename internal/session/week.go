@@ -0,0 +1,205 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// weekTagPrefix prefixes the tag that puts a ball in a week's plan. The tag
+// doubles as the ID of the week's agent session, so the session picks up
+// exactly the balls planned for that week.
+const weekTagPrefix = "week-"
+
+// WeekID returns the ISO week identifier for t, e.g. "2026-W42"
+func WeekID(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// ParseWeekID parses an ISO week identifier ("2026-W42", case-insensitive)
+// and returns the Monday that starts the week, at midnight local time.
+func ParseWeekID(id string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(strings.ToUpper(id), "%d-W%d", &year, &week); err != nil || week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid week %q (expected YYYY-Www, e.g. 2026-W42)", id)
+	}
+
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
+	offset := (int(jan4.Weekday()) + 6) % 7 // Days since Monday
+	start := jan4.AddDate(0, 0, -offset+(week-1)*7)
+
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid week %q: %d has no week %d", id, year, week)
+	}
+	return start, nil
+}
+
+// WeekTag returns the tag (and session ID) for a normalized week ID
+// (see WeekID), e.g. "week-2026-w42"
+func WeekTag(weekID string) string {
+	return weekTagPrefix + strings.ToLower(weekID)
+}
+
+// IsWeekTag returns true if tag plans a ball for a week
+func IsWeekTag(tag string) bool {
+	return strings.HasPrefix(tag, weekTagPrefix)
+}
+
+// BallPoints returns the capacity points a ball counts for when planning:
+// small = 1, medium = 2, large = 3. Unsized balls count as medium.
+func BallPoints(ball *Ball) int {
+	switch ball.ModelSize {
+	case ModelSizeSmall:
+		return 1
+	case ModelSizeLarge:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// WeekReview compares a week's plan with what got done
+type WeekReview struct {
+	Week          string    `json:"week"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Planned       []*Ball   `json:"planned"`
+	Done          []*Ball   `json:"done"`           // Planned balls that are complete
+	NotDone       []*Ball   `json:"not_done"`       // Planned balls that are not complete
+	Unplanned     []*Ball   `json:"unplanned_done"` // Balls completed during the week that weren't planned
+	PlannedPoints int       `json:"planned_points"`
+	DonePoints    int       `json:"done_points"`
+}
+
+// WeekBalls returns the balls planned for a week, ordered by priority then ID
+func WeekBalls(balls []*Ball, weekID string) []*Ball {
+	tag := WeekTag(weekID)
+	planned := make([]*Ball, 0)
+	for _, ball := range balls {
+		for _, t := range ball.Tags {
+			if t == tag {
+				planned = append(planned, ball)
+				break
+			}
+		}
+	}
+	SortBallsByPriority(planned)
+	return planned
+}
+
+// ReviewWeek builds the planned-vs-done report for a week from active and
+// archived balls.
+func ReviewWeek(balls []*Ball, weekID string) (*WeekReview, error) {
+	start, err := ParseWeekID(weekID)
+	if err != nil {
+		return nil, err
+	}
+	review := &WeekReview{
+		Week:      WeekID(start),
+		Start:     start,
+		End:       start.AddDate(0, 0, 7),
+		Planned:   WeekBalls(balls, WeekID(start)),
+		Done:      make([]*Ball, 0),
+		NotDone:   make([]*Ball, 0),
+		Unplanned: make([]*Ball, 0),
+	}
+
+	planned := make(map[string]bool, len(review.Planned))
+	for _, ball := range review.Planned {
+		planned[ball.ID] = true
+		review.PlannedPoints += BallPoints(ball)
+		if ball.State == StateComplete {
+			review.Done = append(review.Done, ball)
+			review.DonePoints += BallPoints(ball)
+		} else {
+			review.NotDone = append(review.NotDone, ball)
+		}
+	}
+
+	for _, ball := range balls {
+		if planned[ball.ID] || ball.State != StateComplete || ball.CompletedAt == nil {
+			continue
+		}
+		if !ball.CompletedAt.Before(review.Start) && ball.CompletedAt.Before(review.End) {
+			review.Unplanned = append(review.Unplanned, ball)
+		}
+	}
+	SortBallsByPriority(review.Unplanned)
+
+	return review, nil
+}
+
+// SortBallsByPriority sorts balls by priority (urgent first), then ID
+func SortBallsByPriority(balls []*Ball) {
+	sort.SliceStable(balls, func(i, j int) bool {
+		pi, pj := balls[i].PriorityWeight(), balls[j].PriorityWeight()
+		if pi != pj {
+			return pi > pj
+		}
+		return balls[i].ID < balls[j].ID
+	})
+}
+
+// GetWeekCapacity returns the weekly capacity in points (0 = not set)
+func (c *ProjectConfig) GetWeekCapacity() int {
+	return c.WeekCapacity
+}
+
+// SetWeekCapacity sets the weekly capacity in points
+func (c *ProjectConfig) SetWeekCapacity(points int) error {
+	if points < 0 {
+		return fmt.Errorf("invalid week capacity %d (must be a non-negative number of points)", points)
+	}
+	c.WeekCapacity = points
+	return nil
+}
+
+// GetProjectWeekCapacity returns the weekly capacity from project config
+func GetProjectWeekCapacity(projectDir string) (int, error) {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return 0, err
+	}
+	return config.GetWeekCapacity(), nil
+}
+
+// UpdateProjectWeekCapacity updates the weekly capacity in project config
+func UpdateProjectWeekCapacity(projectDir string, points int) error {
+	config, err := LoadProjectConfig(projectDir)
+	if err != nil {
+		return err
+	}
+	if err := config.SetWeekCapacity(points); err != nil {
+		return err
+	}
+	return SaveProjectConfig(projectDir, config)
+}
+
+// EnsureWeekSession creates the agent session for a week if it doesn't exist.
+// Returns the session and whether it was created.
+func (s *SessionStore) EnsureWeekSession(weekID string) (*JuggleSession, bool, error) {
+	start, err := ParseWeekID(weekID)
+	if err != nil {
+		return nil, false, err
+	}
+	id := WeekTag(WeekID(start))
+	sess, err := s.LoadSession(id)
+	if err == nil {
+		return sess, false, nil
+	}
+	if !errors.Is(err, ErrSessionNotFound) {
+		return nil, false, err
+	}
+
+	end := start.AddDate(0, 0, 6)
+	description := fmt.Sprintf("Week %s plan (%s – %s)", WeekID(start), start.Format("Jan 2"), end.Format("Jan 2"))
+	sess, err = s.CreateSession(id, description)
+	if err != nil {
+		return nil, false, err
+	}
+	return sess, true, nil
+}
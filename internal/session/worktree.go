@@ -29,6 +29,7 @@ func ResolveStorageDir(dir string, juggleDirName string) (string, error) {
 		}
 		dir = cwd
 	}
+	dir = CleanPath(dir)
 
 	if juggleDirName == "" {
 		juggleDirName = projectStorePath
@@ -45,8 +46,9 @@ func ResolveStorageDir(dir string, juggleDirName string) (string, error) {
 		return "", fmt.Errorf("failed to read link file: %w", err)
 	}
 
-	// Link file contains the main repo path
-	mainRepoPath := strings.TrimSpace(string(data))
+	// Link file contains the main repo path, possibly written on another
+	// platform or with a differently cased drive letter
+	mainRepoPath := CleanPath(strings.TrimSpace(string(data)))
 	if mainRepoPath == "" {
 		return dir, nil
 	}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// agentEdit records which detail fields of a ball changed on disk while an
+// agent was running, so the detail pane can mark and highlight them
+type agentEdit struct {
+	fields []string // Detail field keys, in detailFieldOrder
+	at     time.Time
+}
+
+// changed returns true if the field key is among the edited fields
+func (e *agentEdit) changed(field string) bool {
+	if e == nil {
+		return false
+	}
+	for _, f := range e.fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ballFieldChanges returns the detail fields that differ between two
+// versions of a ball, in detail pane order
+func ballFieldChanges(old, updated *session.Ball) []string {
+	var fields []string
+	add := func(field string, changed bool) {
+		if changed {
+			fields = append(fields, field)
+		}
+	}
+	add("state", old.State != updated.State || old.BlockedReason != updated.BlockedReason ||
+		old.Checkpoint != updated.Checkpoint || !reflect.DeepEqual(old.Escalation, updated.Escalation))
+	add("priority", old.Priority != updated.Priority)
+	add("title", old.Title != updated.Title)
+	add("next", old.NextAction != updated.NextAction)
+	add("context", old.Context != updated.Context)
+	add("tags", !reflect.DeepEqual(old.Tags, updated.Tags))
+	add("depends", !reflect.DeepEqual(old.DependsOn, updated.DependsOn))
+	add("tests", !reflect.DeepEqual(old.TestsState, updated.TestsState))
+	add("transcripts", len(old.Transcripts) != len(updated.Transcripts))
+	add("notes", len(old.Notes) != len(updated.Notes))
+	add("criteria", !reflect.DeepEqual(old.AcceptanceCriteria, updated.AcceptanceCriteria))
+	add("output", old.Output != updated.Output)
+	return fields
+}
+
+// detailFieldOrder is the order fields appear in the detail pane
+var detailFieldOrder = []string{"state", "priority", "title", "next", "context", "tags", "depends", "tests", "transcripts", "notes", "criteria", "output"}
+
+// mergeFields returns the union of two field lists in detail pane order
+func mergeFields(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, f := range append(append([]string{}, a...), b...) {
+		seen[f] = true
+	}
+	merged := make([]string, 0, len(seen))
+	for _, f := range detailFieldOrder {
+		if seen[f] {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// recordAgentEdits compares reloaded balls against the previous versions
+// and remembers which fields the agent changed. The selected ball's changes
+// are also logged to the activity panel.
+func (m *Model) recordAgentEdits(previous []*session.Ball, selectedID string) {
+	at := time.Now()
+	if m.nowFunc != nil {
+		at = m.nowFunc()
+	}
+	byID := make(map[string]*session.Ball, len(previous))
+	for _, ball := range previous {
+		byID[ball.ID] = ball
+	}
+
+	for _, ball := range m.balls {
+		old, ok := byID[ball.ID]
+		if !ok {
+			continue // New balls are handled by duplicate detection
+		}
+		fields := ballFieldChanges(old, ball)
+		if len(fields) == 0 {
+			continue
+		}
+		if m.agentEdits == nil {
+			m.agentEdits = make(map[string]*agentEdit)
+		}
+		if earlier := m.agentEdits[ball.ID]; earlier != nil {
+			// Keep highlighting what earlier iterations of the run changed
+			fields = mergeFields(earlier.fields, fields)
+		}
+		m.agentEdits[ball.ID] = &agentEdit{fields: fields, at: at}
+		if ball.ID == selectedID {
+			m.addActivity(fmt.Sprintf("Updated by agent: %s (%s)", ball.ShortID(), strings.Join(fields, ", ")))
+		}
+	}
+}
+
+// selectedBallID returns the ID of the ball under the cursor, or "" if none
+func (m *Model) selectedBallID() string {
+	balls := m.filterBallsForSession()
+	if m.cursor < len(balls) {
+		return balls[m.cursor].ID
+	}
+	return ""
+}
+
+// reselectBall moves the cursor to the ball with the given ID, so a reload
+// that reorders the list keeps the same ball selected. Returns false if the
+// ball is no longer visible.
+func (m *Model) reselectBall(id string) bool {
+	if id == "" {
+		return false
+	}
+	balls := m.filterBallsForSession()
+	for i, ball := range balls {
+		if ball.ID == id {
+			m.cursor = i
+			m.adjustBallsScrollOffset(balls)
+			return true
+		}
+	}
+	return false
+}
+
+// agentEditMarker returns the "updated by agent" note shown in the detail
+// pane title, or "" if the agent hasn't changed the ball
+func (m Model) agentEditMarker(ball *session.Ball) string {
+	edit := m.agentEdits[ball.ID]
+	if edit == nil {
+		return ""
+	}
+	return fmt.Sprintf("✎ updated by agent %s: %s", formatTime(edit.at), strings.Join(edit.fields, ", "))
+}
@@ -43,7 +43,7 @@ func (m Model) handleAgentCancelConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.message = "Agent cancelled"
 
 		// Reload balls to reflect any changes made before cancellation
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 
 	case "n", "N", "esc", "q":
 		// Don't cancel
@@ -0,0 +1,297 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultLaunchIterations is the iteration limit the launch form starts
+// with, matching agent run's --iterations default
+const defaultLaunchIterations = 10
+
+// Agent launch form fields, in display order
+const (
+	launchFieldIterations = iota
+	launchFieldTimeout
+	launchFieldPermissions
+	launchFieldModel
+	launchFieldDelay
+	launchFieldCount
+)
+
+// launchModels are the --model choices; "" picks by ball size (opus for
+// large balls, sonnet for the rest)
+var launchModels = []string{"", "opus", "sonnet", "haiku"}
+
+// agentLaunchOptions are the settings an agent run started from the TUI
+// is given, passed to "juggle agent run" as flags
+type agentLaunchOptions struct {
+	SessionID  string        // Session to run, "all" for every ball
+	ProjectDir string        // Project the session belongs to (empty = current)
+	Iterations int           // --iterations
+	Timeout    time.Duration // --timeout per iteration, 0 = none
+	Trust      bool          // --trust: skip permission prompts
+	Model      string        // --model, empty to pick by ball size
+	Delay      int           // --delay between iterations in minutes
+	Fuzz       int           // --fuzz, kept from config
+	Resume     bool          // Resume the session's interrupted run with its own settings (agent resume)
+}
+
+// args returns the agent run command line for the options
+func (o agentLaunchOptions) args() []string {
+	if o.Resume {
+		return []string{"agent", "resume", o.SessionID}
+	}
+	args := []string{"agent", "run", o.SessionID, "--yes",
+		"--iterations", strconv.Itoa(o.Iterations),
+		"--delay", strconv.Itoa(o.Delay),
+		"--fuzz", strconv.Itoa(o.Fuzz)}
+	if o.Timeout > 0 {
+		args = append(args, "--timeout", o.Timeout.String())
+	}
+	if o.Trust {
+		args = append(args, "--trust")
+	}
+	if o.Model != "" {
+		args = append(args, "--model", o.Model)
+	}
+	return args
+}
+
+// agentLaunchForm is the form shown before launching an agent, pre-filled
+// from config. Numeric fields are edited as text and checked on launch.
+type agentLaunchForm struct {
+	opts       agentLaunchOptions
+	field      int    // Highlighted field
+	iterations string // Text of the iterations field
+	timeout    string // Text of the timeout field, e.g. "30m"; empty = none
+	delay      string // Text of the delay field, in minutes
+	err        string // Why the last launch attempt was refused
+}
+
+// handleAgentLaunchStart opens the launch form for the selected session
+func (m Model) handleAgentLaunchStart() (tea.Model, tea.Cmd) {
+	if m.agentStatus.Running {
+		m.message = "An agent is already running (X to cancel)"
+		return m, nil
+	}
+	sess := m.selectedSession
+	if sess == nil || sess.ID == PseudoSessionUntagged || sess.ID == PseudoSessionDashboard {
+		m.message = "Select a session to launch an agent on"
+		return m, nil
+	}
+
+	opts := agentLaunchOptions{SessionID: sess.ID, ProjectDir: sess.ProjectDir, Iterations: defaultLaunchIterations}
+	if sess.ID == PseudoSessionAll {
+		opts.SessionID = "all"
+		opts.ProjectDir = ""
+	}
+	if m.config != nil {
+		opts.Delay, opts.Fuzz = m.config.GetIterationDelay()
+	}
+	m.launchForm = &agentLaunchForm{
+		opts:       opts,
+		iterations: strconv.Itoa(opts.Iterations),
+		delay:      strconv.Itoa(opts.Delay),
+	}
+	m.mode = agentLaunchView
+	return m, nil
+}
+
+// handleAgentLaunchKey handles keys in the launch form: j/k move between
+// fields, typing edits the text fields, ←/→ or Space change the choices,
+// Enter launches and Esc cancels
+func (m Model) handleAgentLaunchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := m.launchForm
+	if form == nil {
+		m.mode = splitView
+		return m, nil
+	}
+
+	key := msg.String()
+	switch key {
+	case "esc", "q":
+		m.launchForm = nil
+		m.mode = splitView
+		m.message = "Agent launch cancelled"
+		return m, nil
+	case "enter":
+		return m.launchAgentFromForm()
+	case "down", "j", "tab":
+		form.field = (form.field + 1) % launchFieldCount
+		return m, nil
+	case "up", "k", "shift+tab":
+		form.field = (form.field - 1 + launchFieldCount) % launchFieldCount
+		return m, nil
+	}
+
+	switch form.field {
+	case launchFieldPermissions:
+		if key == "left" || key == "right" || key == " " {
+			form.opts.Trust = !form.opts.Trust
+		}
+	case launchFieldModel:
+		step := 0
+		switch key {
+		case "right", " ":
+			step = 1
+		case "left":
+			step = -1
+		}
+		i := slices.Index(launchModels, form.opts.Model)
+		form.opts.Model = launchModels[(i+step+len(launchModels))%len(launchModels)]
+	default:
+		text := form.textField()
+		switch {
+		case key == "backspace":
+			if len(*text) > 0 {
+				*text = (*text)[:len(*text)-1]
+			}
+		case len(msg.Runes) > 0:
+			*text += string(msg.Runes)
+		}
+	}
+	form.err = ""
+	return m, nil
+}
+
+// textField returns the text of the highlighted text field
+func (f *agentLaunchForm) textField() *string {
+	switch f.field {
+	case launchFieldTimeout:
+		return &f.timeout
+	case launchFieldDelay:
+		return &f.delay
+	default:
+		return &f.iterations
+	}
+}
+
+// parse checks the text fields and stores them in the options
+func (f *agentLaunchForm) parse() error {
+	iterations, err := strconv.Atoi(strings.TrimSpace(f.iterations))
+	if err != nil || iterations < 1 {
+		return fmt.Errorf("iterations must be a whole number of at least 1")
+	}
+	var timeout time.Duration
+	if text := strings.TrimSpace(f.timeout); text != "" && text != "0" {
+		if timeout, err = time.ParseDuration(text); err != nil || timeout < 0 {
+			return fmt.Errorf("timeout must be a duration like 30m or 1h, or empty for none")
+		}
+	}
+	delay, err := strconv.Atoi(strings.TrimSpace(f.delay))
+	if err != nil || delay < 0 {
+		return fmt.Errorf("delay must be a whole number of minutes")
+	}
+	f.opts.Iterations, f.opts.Timeout, f.opts.Delay = iterations, timeout, delay
+	return nil
+}
+
+// launchAgentFromForm starts the agent with the form's settings, streaming
+// its output to the agent output panel
+func (m Model) launchAgentFromForm() (tea.Model, tea.Cmd) {
+	form := m.launchForm
+	if err := form.parse(); err != nil {
+		form.err = err.Error()
+		return m, nil
+	}
+
+	m.launchForm = nil
+	return m.launchAgent(form.opts)
+}
+
+// launchAgent starts an agent run, streaming its output to the agent
+// output panel
+func (m Model) launchAgent(opts agentLaunchOptions) (tea.Model, tea.Cmd) {
+	m.mode = splitView
+	m.clearAgentOutput()
+	m.agentOutputVisible = true
+	m.agentOutputCh = make(chan agentOutputMsg, 100)
+	m.agentStatus = AgentStatus{SessionID: opts.SessionID, MaxIterations: opts.Iterations}
+	m.message = "Starting agent..."
+	m.addActivity(fmt.Sprintf("Launching agent for session %s: %s", opts.SessionID, opts.summary()))
+	return m, launchAgentWithOutputCmd(opts, m.agentOutputCh)
+}
+
+// summary describes the options for the activity log
+func (o agentLaunchOptions) summary() string {
+	if o.Resume {
+		return fmt.Sprintf("resuming its interrupted run (%d iterations)", o.Iterations)
+	}
+	parts := []string{fmt.Sprintf("%d iterations", o.Iterations)}
+	if o.Timeout > 0 {
+		parts = append(parts, o.Timeout.String()+" timeout")
+	}
+	if o.Trust {
+		parts = append(parts, "trusted")
+	}
+	if o.Model != "" {
+		parts = append(parts, o.Model)
+	}
+	if o.Delay > 0 {
+		parts = append(parts, fmt.Sprintf("%dm delay", o.Delay))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderAgentLaunchView renders the launch form
+func (m Model) renderAgentLaunchView() string {
+	form := m.launchForm
+	if form == nil {
+		return ""
+	}
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")). // Cyan
+		Render("Launch Agent")
+	b.WriteString(title + "\n\n")
+	b.WriteString(fmt.Sprintf("Session: %s\n\n", form.opts.SessionID))
+
+	timeout := form.timeout
+	if strings.TrimSpace(timeout) == "" {
+		timeout = "none"
+	}
+	permissions := "ask before editing and running commands"
+	if form.opts.Trust {
+		permissions = "trust - skip permission prompts (dangerous!)"
+	}
+	model := "by ball size (opus for large, sonnet otherwise)"
+	if form.opts.Model != "" {
+		model = form.opts.Model
+	}
+	delay := form.delay + " min"
+	if form.opts.Fuzz > 0 {
+		delay += fmt.Sprintf(" (±%d)", form.opts.Fuzz)
+	}
+
+	rows := []struct{ label, value string }{
+		{"Max iterations", form.iterations},
+		{"Timeout / iteration", timeout},
+		{"Permissions", "◂ " + permissions + " ▸"},
+		{"Model", "◂ " + model + " ▸"},
+		{"Delay between", delay},
+	}
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+	for i, row := range rows {
+		line := fmt.Sprintf("%-20s %s", row.label+":", row.value)
+		if i == form.field {
+			b.WriteString(selectedStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if form.err != "" {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(form.err) + "\n")
+	}
+	b.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render("j/k = field | type to edit | ←/→ = change | Enter = launch | Esc = cancel"))
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// agentPauseMsg reports a pause requested or called off for a session's
+// agent loop
+type agentPauseMsg struct {
+	sessionID string
+	paused    bool // Pause requested, rather than resumed
+	holding   bool // The loop had already stopped to wait (resume only)
+	err       error
+}
+
+// handleAgentPauseToggle pauses the selected session's running agent once
+// its current iteration ends, or resumes it if it's paused
+func (m Model) handleAgentPauseToggle() (tea.Model, tea.Cmd) {
+	sess := m.selectedSession
+	if sess == nil || sess.ID == PseudoSessionUntagged || sess.ID == PseudoSessionDashboard || m.sessionStore == nil {
+		m.message = "Select a session to pause its agent"
+		return m, nil
+	}
+	store, storageID, err := m.signalStore(sess)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+	if locked, _ := store.IsLocked(storageID); !locked {
+		m.message = "No agent is running for session: " + sess.ID
+		return m, nil
+	}
+	return m, toggleAgentPause(store, storageID, sess.ID)
+}
+
+// toggleAgentPause leaves a pause marker for the agent loop, or removes the
+// one that's there
+func toggleAgentPause(store *session.SessionStore, storageID, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		pause, err := store.LoadAgentPause(storageID)
+		if err != nil {
+			return agentPauseMsg{sessionID: sessionID, err: err}
+		}
+		if pause != nil {
+			err := store.ClearAgentPause(storageID)
+			return agentPauseMsg{sessionID: sessionID, holding: pause.Holding(), err: err}
+		}
+		err = store.SaveAgentPause(&session.AgentPause{SessionID: storageID, Source: "tui"})
+		return agentPauseMsg{sessionID: sessionID, paused: true, err: err}
+	}
+}
+
+// handleAgentPauseToggled reports the pause that was requested or lifted
+func (m Model) handleAgentPauseToggled(msg agentPauseMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message = "Error pausing agent: " + msg.err.Error()
+		return m, nil
+	}
+	switch {
+	case msg.paused:
+		m.message = fmt.Sprintf("Pausing the agent on %s after its current iteration - p again to resume", msg.sessionID)
+		m.addActivity("Paused agent for session: " + msg.sessionID)
+	case msg.holding:
+		m.message = "Resumed the agent on " + msg.sessionID
+		m.addActivity("Resumed agent for session: " + msg.sessionID)
+	default:
+		m.message = fmt.Sprintf("Called off the pause - the agent on %s keeps going", msg.sessionID)
+		m.addActivity("Called off the pause of agent for session: " + msg.sessionID)
+	}
+	return m, nil
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// offerAgentResume opens the resume prompt for agent runs that were killed
+// partway, unless something else has the screen or an agent is running
+func (m Model) offerAgentResume(checkpoints []*session.AgentCheckpoint) (tea.Model, tea.Cmd) {
+	if len(checkpoints) == 0 || m.mode != splitView || m.agentStatus.Running {
+		return m, nil
+	}
+	for _, checkpoint := range checkpoints {
+		m.addActivity(fmt.Sprintf("Agent run on %s was interrupted after iteration %d/%d",
+			checkpoint.SessionID, checkpoint.Iteration, checkpoint.MaxIterations))
+	}
+	m.staleCheckpoints = checkpoints
+	m.mode = confirmAgentResume
+	return m, m.attention(session.AttentionConfirm, "interrupted agent run to resume")
+}
+
+// handleAgentResumeKey handles the resume prompt, one interrupted run at a
+// time: y resumes it, n leaves it for later, d discards its checkpoint
+func (m Model) handleAgentResumeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(m.staleCheckpoints) == 0 {
+		m.mode = splitView
+		return m, nil
+	}
+	current := m.staleCheckpoints[0]
+
+	switch msg.String() {
+	case "y", "Y", "enter":
+		m.staleCheckpoints = nil
+		return m.launchAgent(agentLaunchOptions{
+			SessionID:  current.SessionID,
+			ProjectDir: m.sessionStore.ProjectDir(),
+			Iterations: current.MaxIterations,
+			Resume:     true,
+		})
+
+	case "d", "D":
+		storageID := current.SessionID
+		if storageID == "all" {
+			storageID = "_all"
+		}
+		if err := m.sessionStore.ClearAgentCheckpoint(storageID); err != nil {
+			m.message = "Error: " + err.Error()
+		} else {
+			m.message = "Discarded the interrupted run on " + current.SessionID
+			m.addActivity(m.message)
+		}
+		return m.nextAgentResume()
+
+	case "n", "N":
+		m.message = "Resume later with: juggle agent resume " + current.SessionID
+		return m.nextAgentResume()
+
+	case "esc", "q":
+		m.staleCheckpoints = nil
+		m.mode = splitView
+		m.message = "Interrupted runs kept (juggle agent resume <session>)"
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// nextAgentResume moves to the next interrupted run, returning to the
+// split view when none are left
+func (m Model) nextAgentResume() (tea.Model, tea.Cmd) {
+	m.staleCheckpoints = m.staleCheckpoints[1:]
+	if len(m.staleCheckpoints) == 0 {
+		m.mode = splitView
+	}
+	return m, nil
+}
+
+// renderAgentResumeConfirm renders the prompt for resuming an interrupted
+// agent run
+func (m Model) renderAgentResumeConfirm() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("3")). // Yellow
+		Render("Interrupted Agent Run")
+	b.WriteString(title + "\n\n")
+
+	if len(m.staleCheckpoints) == 0 {
+		return b.String()
+	}
+	checkpoint := m.staleCheckpoints[0]
+
+	info := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")). // Gray
+		Render("An agent run stopped partway without finishing, e.g. the machine slept or juggle crashed.")
+	b.WriteString(info + "\n\n")
+
+	b.WriteString(fmt.Sprintf("Session:         %s\n", checkpoint.SessionID))
+	b.WriteString(fmt.Sprintf("Progress:        %d/%d iterations (%d left)\n",
+		checkpoint.Iteration, checkpoint.MaxIterations, checkpoint.Remaining()))
+	if checkpoint.LastSignal != "" {
+		b.WriteString(fmt.Sprintf("Last iteration:  %s\n", checkpoint.LastSignal))
+	}
+	b.WriteString(fmt.Sprintf("Last checkpoint: %s\n", checkpoint.UpdatedAt.Format("2006-01-02 15:04")))
+	if len(m.staleCheckpoints) > 1 {
+		b.WriteString(fmt.Sprintf("\n%d more after this\n", len(m.staleCheckpoints)-1))
+	}
+	b.WriteString("\n")
+
+	prompt := lipgloss.NewStyle().
+		Bold(true).
+		Render("Resume it where it left off? [y/N]")
+	b.WriteString(prompt + "\n\n")
+
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Render("y = resume with its own settings | n = not now | d = discard | Esc = skip all")
+	b.WriteString(help)
+
+	return b.String()
+}
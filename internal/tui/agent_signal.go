@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// humanSignalSentMsg reports a signal left for a session's agent loop
+type humanSignalSentMsg struct {
+	sessionID string
+	signal    session.LoopSignal
+	err       error
+}
+
+// signalStore returns the session store and storage ID the agent loop for
+// sess uses ("all" runs are stored under "_all" in the current project)
+func (m Model) signalStore(sess *session.JuggleSession) (*session.SessionStore, string, error) {
+	if sess.ID == PseudoSessionAll {
+		return m.sessionStore, "_all", nil
+	}
+	if sess.ProjectDir == "" || m.store == nil || sess.ProjectDir == m.store.ProjectDir() {
+		return m.sessionStore, sess.ID, nil
+	}
+	store, err := session.NewSessionStore(sess.ProjectDir)
+	return store, sess.ID, err
+}
+
+// sendHumanSignal leaves a signal for the agent loop; it acts on it when
+// the current iteration ends
+func sendHumanSignal(store *session.SessionStore, storageID, sessionID string, signal session.LoopSignal, reason string) tea.Cmd {
+	return func() tea.Msg {
+		err := store.SendHumanSignal(&session.HumanSignal{
+			SessionID: storageID,
+			Signal:    signal,
+			Reason:    reason,
+			Source:    "tui",
+		})
+		return humanSignalSentMsg{sessionID: sessionID, signal: signal, err: err}
+	}
+}
+
+// handleAgentSignalStart opens the signal prompt for the selected session,
+// if an agent is running on it
+func (m Model) handleAgentSignalStart() (tea.Model, tea.Cmd) {
+	sess := m.selectedSession
+	if sess == nil || sess.ID == PseudoSessionUntagged || sess.ID == PseudoSessionDashboard || m.sessionStore == nil {
+		m.message = "Select a session to signal its agent"
+		return m, nil
+	}
+	store, storageID, err := m.signalStore(sess)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+	if locked, _ := store.IsLocked(storageID); !locked {
+		m.message = "No agent is running for session: " + sess.ID
+		return m, nil
+	}
+
+	m.signalSession = sess
+	m.signalReasonEditing = false
+	m.mode = agentSignalView
+	return m, nil
+}
+
+// handleAgentSignalKey handles the signal prompt: c, b and n pick the
+// signal, and BLOCKED asks for an optional reason before it's sent
+func (m Model) handleAgentSignalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	sess := m.signalSession
+	if sess == nil {
+		m.mode = splitView
+		return m, nil
+	}
+	store, storageID, err := m.signalStore(sess)
+	if err != nil {
+		m.mode = splitView
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+
+	if m.signalReasonEditing {
+		switch msg.String() {
+		case "esc":
+			m.signalReasonEditing = false
+			m.textInput.Blur()
+			return m, nil
+		case "enter":
+			reason := strings.TrimSpace(m.textInput.Value())
+			m.signalReasonEditing = false
+			m.textInput.Blur()
+			m.mode = splitView
+			return m, sendHumanSignal(store, storageID, sess.ID, session.LoopSignalBlocked, reason)
+		}
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "c", "C":
+		m.mode = splitView
+		return m, sendHumanSignal(store, storageID, sess.ID, session.LoopSignalComplete, "")
+	case "n", "N":
+		m.mode = splitView
+		return m, sendHumanSignal(store, storageID, sess.ID, session.LoopSignalContinue, "")
+	case "b", "B":
+		m.signalReasonEditing = true
+		m.textInput.Reset()
+		m.textInput.Placeholder = "Why is the run blocked? (optional)"
+		m.textInput.Focus()
+		return m, nil
+	case "esc", "q":
+		m.mode = splitView
+		m.message = "No signal sent"
+		return m, nil
+	}
+	return m, nil
+}
+
+// handleHumanSignalSent reports the signal that was left for the loop
+func (m Model) handleHumanSignalSent(msg humanSignalSentMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message = "Error sending signal: " + msg.err.Error()
+		return m, nil
+	}
+	name := strings.ToUpper(string(msg.signal))
+	m.message = fmt.Sprintf("Sent %s to %s - the agent acts on it when the iteration ends", name, msg.sessionID)
+	m.addActivity(fmt.Sprintf("Signaled %s to agent for session: %s", name, msg.sessionID))
+	return m, nil
+}
+
+// renderAgentSignalView renders the signal prompt
+func (m Model) renderAgentSignalView() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")). // Cyan
+		Render("Signal Agent")
+	b.WriteString(title + "\n\n")
+
+	if m.signalSession != nil {
+		b.WriteString(fmt.Sprintf("Session: %s\n", m.signalSession.ID))
+	}
+	info := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")). // Gray
+		Render("The agent finishes its current iteration, then the loop follows your signal instead of its own.")
+	b.WriteString(info + "\n\n")
+
+	if m.signalReasonEditing {
+		b.WriteString("Reason for BLOCKED:\n")
+		b.WriteString(m.textInput.View() + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Faint(true).Render("Enter = send BLOCKED | Esc = back"))
+		return b.String()
+	}
+
+	b.WriteString("  c  COMPLETE - end the run\n")
+	b.WriteString("  b  BLOCKED  - stop the run as blocked\n")
+	b.WriteString("  n  CONTINUE - keep going, ignoring the agent's signal\n\n")
+	b.WriteString(lipgloss.NewStyle().Faint(true).Render("c/b/n = send | Esc = cancel"))
+	return b.String()
+}
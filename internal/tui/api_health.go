@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// apiHealthMarker starts the line the agent loop prints after each agent
+// call, once the call has been recorded toward the provider's health
+const apiHealthMarker = "📶"
+
+// SetAPIHealthLoader sets how the status bar's API health indicator is
+// loaded, and loads it. It's reloaded after each agent call and run.
+func (m *Model) SetAPIHealthLoader(load func() (session.APIHealthSummary, error)) {
+	m.apiHealthLoader = load
+	m.refreshAPIHealth()
+}
+
+// refreshAPIHealth reloads the API health indicator, keeping the last one
+// if loading fails
+func (m *Model) refreshAPIHealth() {
+	if m.apiHealthLoader == nil {
+		return
+	}
+	summary, err := m.apiHealthLoader()
+	if err != nil {
+		return
+	}
+	m.apiHealth = &summary
+}
+
+// apiHealthIndicator returns the status bar indicator for the provider's
+// API health, or "" before it has any recent calls
+func (m Model) apiHealthIndicator() string {
+	if m.apiHealth == nil || m.apiHealth.Status == session.APIHealthUnknown {
+		return ""
+	}
+	health := m.apiHealth
+	if health.Status == session.APIHealthHealthy {
+		return fmt.Sprintf("[API %s ok]", health.Provider)
+	}
+
+	details := []string{fmt.Sprintf("%.0f%% err", health.ErrorRate*100)}
+	now := m.now()
+	if last := health.LastRateLimited; !last.IsZero() && now.Sub(last) < session.APIHealthWindow {
+		details = append(details, "429 "+formatDuration(now.Sub(last).Truncate(time.Second))+" ago")
+	}
+	if last := health.LastOverloaded; !last.IsZero() && now.Sub(last) < session.APIHealthWindow {
+		details = append(details, "529 "+formatDuration(now.Sub(last).Truncate(time.Second))+" ago")
+	}
+	return fmt.Sprintf("[API %s %s: %s]", health.Provider, health.Status, strings.Join(details, ", "))
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// attentionTitle is the terminal title restored when the TUI is focused again
+const attentionTitle = "juggle"
+
+// attention returns the command signalling event, or nil if it isn't
+// configured or the TUI is focused (unless signals are always on). The
+// renderer owns stdout, so bells are written to stderr, the same terminal.
+func (m *Model) attention(event, text string) tea.Cmd {
+	var attention *session.AttentionConfig
+	if m.config != nil {
+		attention = m.config.Attention
+	}
+	if !attention.Wants(event) || (!m.blurred && !attention.Always) {
+		return nil
+	}
+
+	out := m.attentionOut
+	if out == nil {
+		out = os.Stderr
+	}
+	text = "juggle: " + text
+	m.attentionPending = true
+
+	var cmds []tea.Cmd
+	if attention.Bell || attention.Tmux {
+		// In tmux, a bell flags the window in the status line
+		pane := os.Getenv("TMUX_PANE")
+		useTmux := attention.Tmux && os.Getenv("TMUX") != ""
+		cmds = append(cmds, func() tea.Msg {
+			fmt.Fprint(out, "\a")
+			if useTmux {
+				_ = tmuxSetAttention(pane, text)
+			}
+			return nil
+		})
+	}
+	if attention.Title {
+		cmds = append(cmds, tea.SetWindowTitle(text))
+	}
+	return tea.Batch(cmds...)
+}
+
+// clearAttention undoes what attention left behind once the TUI is focused
+func (m *Model) clearAttention() tea.Cmd {
+	if !m.attentionPending || m.config == nil || m.config.Attention == nil {
+		return nil
+	}
+	m.attentionPending = false
+
+	var cmds []tea.Cmd
+	if m.config.Attention.Tmux && os.Getenv("TMUX") != "" {
+		pane := os.Getenv("TMUX_PANE")
+		cmds = append(cmds, func() tea.Msg {
+			_ = tmuxSetAttention(pane, "")
+			return nil
+		})
+	}
+	if m.config.Attention.Title {
+		cmds = append(cmds, tea.SetWindowTitle(attentionTitle))
+	}
+	return tea.Batch(cmds...)
+}
+
+// tmuxSetAttention sets the @juggle_attention window option, which status
+// line formats can show (#{@juggle_attention}); empty text unsets it
+func tmuxSetAttention(pane, text string) error {
+	args := []string{"set-option", "-w"}
+	if pane != "" {
+		args = append(args, "-t", pane)
+	}
+	if text == "" {
+		args = append(args, "-u", "@juggle_attention")
+	} else {
+		args = append(args, "@juggle_attention", text)
+	}
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdout = io.Discard
+	cmd.Stderr = io.Discard
+	return cmd.Run()
+}
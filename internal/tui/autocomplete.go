@@ -2,6 +2,7 @@ package tui
 
 import (
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -44,7 +45,7 @@ func (a *AutocompleteState) UpdateFromText(text string, cursorPos int) bool {
 			break
 		}
 		// Stop at whitespace - @ must be directly followed by query
-		if text[i] == ' ' || text[i] == '\t' || text[i] == '\n' {
+		if isAutocompleteSpace(text[i]) {
 			break
 		}
 	}
@@ -62,7 +63,7 @@ func (a *AutocompleteState) UpdateFromText(text string, cursorPos int) bool {
 	query := text[lastAt+1 : cursorPos]
 
 	// Don't activate if @ is preceded by non-whitespace (unless at start)
-	if lastAt > 0 && text[lastAt-1] != ' ' && text[lastAt-1] != '\t' && text[lastAt-1] != '\n' {
+	if lastAt > 0 && !isAutocompleteSpace(text[lastAt-1]) {
 		if a.Active {
 			a.Reset()
 			return true
@@ -138,13 +139,21 @@ func (a *AutocompleteState) Deactivate() {
 	a.Active = false
 }
 
+// isAutocompleteSpace reports whether c ends an @ query. \r is included for
+// text pasted with Windows line endings.
+func isAutocompleteSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
 // findMatchingFiles searches for files matching the query in the repo
 func findMatchingFiles(repoRoot, query string, maxResults int) []string {
 	if repoRoot == "" {
 		return nil
 	}
 
-	query = strings.ToLower(query)
+	// Suggestions use forward slashes on every platform, so a query typed
+	// with backslashes on Windows matches them too
+	query = strings.ToLower(strings.ReplaceAll(query, `\`, "/"))
 	var matches []string
 
 	// Walk the repository, excluding common non-relevant directories
@@ -177,6 +186,7 @@ func findMatchingFiles(repoRoot, query string, maxResults int) []string {
 		if err != nil {
 			return nil
 		}
+		relPath = filepath.ToSlash(relPath)
 
 		// Check if path matches query (case-insensitive substring match)
 		if query == "" || strings.Contains(strings.ToLower(relPath), query) {
@@ -202,8 +212,8 @@ func findMatchingFiles(repoRoot, query string, maxResults int) []string {
 		mj := strings.ToLower(matches[j])
 
 		// Prefix matches first
-		iPrefixMatch := strings.HasPrefix(mi, query) || strings.HasPrefix(filepath.Base(mi), query)
-		jPrefixMatch := strings.HasPrefix(mj, query) || strings.HasPrefix(filepath.Base(mj), query)
+		iPrefixMatch := strings.HasPrefix(mi, query) || strings.HasPrefix(path.Base(mi), query)
+		jPrefixMatch := strings.HasPrefix(mj, query) || strings.HasPrefix(path.Base(mj), query)
 		if iPrefixMatch != jPrefixMatch {
 			return iPrefixMatch
 		}
@@ -344,3 +344,29 @@ func TestAutocompleteAtPrecededByWhitespace(t *testing.T) {
 		t.Error("@ in middle of word should NOT activate autocomplete")
 	}
 }
+
+func TestAutocompleteSuggestionsUseForwardSlashes(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "src", "auth"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "src", "auth", "login.go"), []byte("package auth"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Typed with either separator, as on Windows
+	for _, query := range []string{"src/auth", `src\auth`} {
+		results := findMatchingFiles(tmpDir, query, 10)
+		if len(results) != 1 || results[0] != "src/auth/login.go" {
+			t.Errorf("Query %q: expected src/auth/login.go, got %v", query, results)
+		}
+	}
+
+	// A CR from pasted Windows text ends the query like other whitespace
+	ac := NewAutocompleteState(tmpDir)
+	text := "See\r@login"
+	ac.UpdateFromText(text, len(text))
+	if !ac.Active || ac.Query != "login" {
+		t.Errorf("Expected @ after \\r to activate with query login, got active=%v query=%q", ac.Active, ac.Query)
+	}
+}
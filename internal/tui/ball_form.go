@@ -17,11 +17,26 @@ func (m Model) finalizeBallCreation() (tea.Model, tea.Cmd) {
 		m.pendingNewAC = ""
 	}
 
+	// Create a session typed into "create new…" but not submitted with Enter
+	sessionCreated := false
+	if m.pendingNewSession != "" && m.pendingBallSession == len(m.formSessions())+1 {
+		if err := m.createFormSession(); err != nil {
+			m.message = "Error creating session: " + err.Error()
+			return m, nil
+		}
+		sessionCreated = true
+	}
+
 	// Auto-generate title from context if title is empty but context has content
 	if m.pendingBallIntent == "" && m.pendingBallContext != "" {
 		m.pendingBallIntent = generateTitlePlaceholderFromContext(m.pendingBallContext)
 	}
 
+	// Title rules only warn; the ball is saved either way
+	for _, warning := range m.titleRules.CheckTitle(m.pendingBallIntent) {
+		m.addActivity("Title " + warning)
+	}
+
 	// Map priority index to Priority constant
 	priorities := []session.Priority{session.PriorityLow, session.PriorityMedium, session.PriorityHigh, session.PriorityUrgent}
 	priority := priorities[m.pendingBallPriority]
@@ -51,17 +66,8 @@ func (m Model) finalizeBallCreation() (tea.Model, tea.Cmd) {
 	}
 
 	// Add session tag if selected in form (0 = none, 1+ = session index)
-	if m.pendingBallSession > 0 {
-		// Get real sessions (excluding pseudo-sessions)
-		realSessions := []*session.JuggleSession{}
-		for _, sess := range m.sessions {
-			if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-				realSessions = append(realSessions, sess)
-			}
-		}
-		if m.pendingBallSession-1 < len(realSessions) {
-			tags = append(tags, realSessions[m.pendingBallSession-1].ID)
-		}
+	if sessionID := m.formSessionID(); sessionID != "" {
+		tags = append(tags, sessionID)
 	}
 
 	// Handle blocking reason
@@ -187,7 +193,10 @@ func (m Model) finalizeBallCreation() (tea.Model, tea.Cmd) {
 	m.textInput.Blur()
 	m.mode = splitView
 
-	return m, loadBalls(m.store, m.config, m.localOnly)
+	if sessionCreated {
+		return m, tea.Batch(loadBalls(m.store, m.projectCache, m.config, m.localOnly), loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly))
+	}
+	return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 }
 
 // clearPendingBallState clears all pending ball creation/editing state
@@ -205,6 +214,7 @@ func (m *Model) clearPendingBallState() {
 	m.pendingBallDependsOn = nil
 	m.pendingBallBlockingReason = 0 // Reset to blank
 	m.pendingBallCustomReason = ""
+	m.pendingNewSession = ""
 	m.pendingBallFormField = 0
 	m.pendingACEditIndex = -1
 	m.dependencySelectBalls = nil
@@ -220,6 +230,65 @@ func (m *Model) clearPendingBallState() {
 	m.sessionLevelACs = nil
 }
 
+// formSessions returns the sessions offered in the ball form's session
+// field, excluding pseudo-sessions
+func (m Model) formSessions() []*session.JuggleSession {
+	sessions := []*session.JuggleSession{}
+	for _, sess := range m.sessions {
+		if !isPseudoSession(sess.ID) {
+			sessions = append(sessions, sess)
+		}
+	}
+	return sessions
+}
+
+// formSessionID returns the ID of the session selected in the ball form,
+// or "" for (none) and "create new…"
+func (m Model) formSessionID() string {
+	sessions := m.formSessions()
+	if m.pendingBallSession > 0 && m.pendingBallSession-1 < len(sessions) {
+		return sessions[m.pendingBallSession-1].ID
+	}
+	return ""
+}
+
+// selectFormSession selects the session with the given ID in the ball form
+func (m *Model) selectFormSession(id string) {
+	for i, sess := range m.formSessions() {
+		if sess.ID == id {
+			m.pendingBallSession = i + 1
+			return
+		}
+	}
+	m.pendingBallSession = 0
+}
+
+// createFormSession creates the session typed into the session field's
+// "create new…" option and selects it for the ball being edited
+func (m *Model) createFormSession() error {
+	if m.sessionStore == nil {
+		return fmt.Errorf("session store not available")
+	}
+	id, description, _ := strings.Cut(m.pendingNewSession, ":")
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	sess, err := m.sessionStore.CreateSession(id, strings.TrimSpace(description))
+	if err != nil {
+		return err
+	}
+	// Show the session straight away; the reload this triggers keeps the
+	// selection by ID
+	m.sessions = append(m.sessions, sess)
+	m.selectFormSession(sess.ID)
+	m.pendingNewSession = ""
+	m.addActivity("Created session: " + sess.ID)
+	m.message = "Created session: " + sess.ID
+	return nil
+}
+
 // generateTitlePlaceholderFromContext generates a title placeholder from context content.
 // Returns the first 50 characters trimmed at a word boundary, or empty string if no context.
 func generateTitlePlaceholderFromContext(context string) string {
@@ -262,11 +331,11 @@ func adjustContextTextareaHeight(m *Model) {
 	// Count wrapped lines
 	wrappedLines := 0
 	for _, line := range strings.Split(content, "\n") {
-		if len(line) == 0 {
+		if width := displayWidth(line); width == 0 {
 			wrappedLines++
-		} else if len(line) > wrapWidth {
+		} else if width > wrapWidth {
 			// Long line wraps to multiple display lines
-			wrappedLines += (len(line) / wrapWidth) + 1
+			wrappedLines += (width / wrapWidth) + 1
 		} else {
 			wrappedLines++
 		}
@@ -313,13 +382,9 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	numPriorityOptions := 4        // low, medium, high, urgent
 	numBlockingReasonOptions := 5  // (blank), Human needed, Waiting for dependency, Needs research, (custom)
 
-	// Count real sessions (excluding pseudo-sessions)
-	numSessionOptions := 1 // Start with "(none)"
-	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-			numSessionOptions++
-		}
-	}
+	// Session options: (none), each real session, then "create new…"
+	numSessionOptions := len(m.formSessions()) + 2
+	createSessionOption := numSessionOptions - 1
 
 	// Calculate the maximum field index (Run now is the last field)
 	maxFieldIndex := fieldRunNow
@@ -330,6 +395,10 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if field == fieldBlockingReason && m.pendingBallBlockingReason == 4 {
 			return true
 		}
+		// Session field is text input while "create new…" is selected
+		if field == fieldSession && m.pendingBallSession == createSessionOption {
+			return true
+		}
 		return field == fieldContext || field == fieldIntent || field == fieldTags ||
 			(field >= fieldACStart && field <= fieldACEnd)
 	}
@@ -373,6 +442,9 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if m.pendingBallFormField == fieldBlockingReason && m.pendingBallBlockingReason == 4 {
 				// Custom blocking reason text
 				m.pendingBallCustomReason = value
+			} else if m.pendingBallFormField == fieldSession && m.pendingBallSession == createSessionOption {
+				// New session being typed
+				m.pendingNewSession = value
 			} else if isACField(m.pendingBallFormField) {
 				// AC field
 				acIndex := m.pendingBallFormField - fieldACStart
@@ -414,7 +486,7 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Helper to load field value into text input when entering field
 	loadFieldValue := func(field int) {
 		// Recalculate indices since ACs may have changed
-		acEnd, tagsField, _, _, _, _, _, blockingReasonField, _, _, _ := recalcFieldIndices()
+		acEnd, tagsField, sessionField, _, _, _, _, blockingReasonField, _, _, _ := recalcFieldIndices()
 
 		m.textInput.Reset()
 		switch field {
@@ -451,6 +523,11 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.textInput.SetValue(m.pendingBallCustomReason)
 				m.textInput.Placeholder = "Enter custom blocking reason"
 				m.textInput.Focus()
+			} else if field == sessionField && m.pendingBallSession == createSessionOption {
+				// Creating a new session - show text input
+				m.textInput.SetValue(m.pendingNewSession)
+				m.textInput.Placeholder = "new-session-id: optional description (Enter = create)"
+				m.textInput.Focus()
 			} else if field >= fieldACStart && field <= acEnd {
 				acIndex := field - fieldACStart
 				if acIndex < len(m.pendingAcceptanceCriteria) {
@@ -575,6 +652,17 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else if m.pendingBallFormField == fieldDependsOn {
 			// Open dependency selector
 			return m.openDependencySelector()
+		} else if m.pendingBallFormField == fieldSession && m.pendingBallSession == createSessionOption {
+			// Create the typed session, select it, and move on
+			saveCurrentFieldValue()
+			if err := m.createFormSession(); err != nil {
+				m.message = "Error creating session: " + err.Error()
+				return m, nil
+			}
+			m.loadACTemplatesAndRepoACs()
+			m.pendingBallFormField++
+			loadFieldValue(m.pendingBallFormField)
+			return m, loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly)
 		} else if isACField(m.pendingBallFormField) {
 			acIndex := m.pendingBallFormField - fieldACStart
 			value := strings.TrimSpace(m.textInput.Value())
@@ -736,12 +824,15 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Arrow key left only cycles selection left for selection fields
 		_, _, sessionField, modelSizeField, agentProviderField, modelOverrideField, priorityField, blockingReasonField, _, _, _ := recalcFieldIndices()
 		if m.pendingBallFormField == sessionField {
+			saveCurrentFieldValue()
 			m.pendingBallSession--
 			if m.pendingBallSession < 0 {
 				m.pendingBallSession = numSessionOptions - 1
 			}
 			// Reload ACs when session changes
 			m.loadACTemplatesAndRepoACs()
+			// Load text input if switching to/from "create new…"
+			loadFieldValue(m.pendingBallFormField)
 		} else if m.pendingBallFormField == modelSizeField {
 			m.pendingBallModelSize--
 			if m.pendingBallModelSize < 0 {
@@ -782,12 +873,15 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.pendingBallFormField = runNowField
 			return m, nil
 		} else if m.pendingBallFormField == sessionField {
+			saveCurrentFieldValue()
 			m.pendingBallSession++
 			if m.pendingBallSession >= numSessionOptions {
 				m.pendingBallSession = 0
 			}
 			// Reload ACs when session changes
 			m.loadACTemplatesAndRepoACs()
+			// Load text input if switching to/from "create new…"
+			loadFieldValue(m.pendingBallFormField)
 		} else if m.pendingBallFormField == modelSizeField {
 			m.pendingBallModelSize++
 			if m.pendingBallModelSize >= numModelSizeOptions {
@@ -876,8 +970,18 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		// Tab always moves to next field
 		// For selection fields, also toggle to next option before moving
+		var cmd tea.Cmd
 		_, _, sessionField, modelSizeField, agentProviderField, modelOverrideField, priorityField, blockingReasonField, _, _, _ := recalcFieldIndices()
-		if m.pendingBallFormField == sessionField {
+		if m.pendingBallFormField == sessionField && m.pendingBallSession == createSessionOption && strings.TrimSpace(m.textInput.Value()) != "" {
+			// Create the typed session before moving on
+			saveCurrentFieldValue()
+			if err := m.createFormSession(); err != nil {
+				m.message = "Error creating session: " + err.Error()
+				return m, nil
+			}
+			m.loadACTemplatesAndRepoACs()
+			cmd = loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly)
+		} else if m.pendingBallFormField == sessionField {
 			// Toggle to next session option
 			m.pendingBallSession++
 			if m.pendingBallSession >= numSessionOptions {
@@ -931,7 +1035,7 @@ func (m Model) handleUnifiedBallFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		loadFieldValue(m.pendingBallFormField)
-		return m, nil
+		return m, cmd
 
 	case "backspace", "delete":
 		// Allow deletion in text fields
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ballSessionIDs returns the sessions a ball belongs to, resolved from its tags
+func ballSessionIDs(ball *session.Ball, sessions []*session.JuggleSession) []string {
+	tags := make(map[string]bool, len(ball.Tags))
+	for _, tag := range ball.Tags {
+		tags[tag] = true
+	}
+	ids := make([]string, 0)
+	for _, sess := range sessions {
+		if !isPseudoSession(sess.ID) && tags[sess.ID] {
+			ids = append(ids, sess.ID)
+		}
+	}
+	return ids
+}
+
+// handleBallSessionsStart opens the session membership view for the highlighted ball
+func (m Model) handleBallSessionsStart() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	if len(balls) == 0 || m.cursor >= len(balls) {
+		m.message = "No ball selected"
+		return m, nil
+	}
+
+	items := make([]*session.JuggleSession, 0, len(m.sessions))
+	for _, sess := range m.sessions {
+		if !isPseudoSession(sess.ID) {
+			items = append(items, sess)
+		}
+	}
+	if len(items) == 0 {
+		m.message = "No sessions - press 'a' in the sessions panel to create one"
+		return m, nil
+	}
+
+	ball := balls[m.cursor]
+	m.editingBall = ball
+	m.sessionSelectItems = items
+	m.sessionSelectIndex = 0
+	// Start on the first session the ball belongs to
+	for i, sess := range items {
+		if ballHasSessionTag(ball, sess.ID) {
+			m.sessionSelectIndex = i
+			break
+		}
+	}
+	m.message = ""
+	m.mode = ballSessionsView
+	return m, nil
+}
+
+// handleBallSessionsKey handles keyboard input in the session membership view
+func (m Model) handleBallSessionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "S":
+		m.mode = splitView
+		m.editingBall = nil
+		m.sessionSelectItems = nil
+		m.message = ""
+		return m, nil
+
+	case "up", "k":
+		if m.sessionSelectIndex > 0 {
+			m.sessionSelectIndex--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.sessionSelectIndex < len(m.sessionSelectItems)-1 {
+			m.sessionSelectIndex++
+		}
+		return m, nil
+
+	case " ", "x":
+		return m.toggleBallSession()
+
+	case "enter":
+		return m.jumpToBallSession()
+	}
+	return m, nil
+}
+
+// toggleBallSession adds the ball to or removes it from the focused session
+func (m Model) toggleBallSession() (tea.Model, tea.Cmd) {
+	if m.editingBall == nil || m.sessionSelectIndex >= len(m.sessionSelectItems) {
+		return m, nil
+	}
+	ball := m.editingBall
+	sessionID := m.sessionSelectItems[m.sessionSelectIndex].ID
+
+	store, err := session.NewStore(ball.WorkingDir)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+
+	if ball.RemoveTag(sessionID) {
+		m.message = "Removed from session: " + sessionID
+		m.addActivity("Removed " + ball.ID + " from session: " + sessionID)
+	} else {
+		ball.AddTag(sessionID)
+		m.message = "Added to session: " + sessionID
+		m.addActivity("Added " + ball.ID + " to session: " + sessionID)
+	}
+	return m, updateBall(store, ball)
+}
+
+// jumpToBallSession selects the focused session in the split view, keeping
+// the ball highlighted when it is visible there
+func (m Model) jumpToBallSession() (tea.Model, tea.Cmd) {
+	if m.editingBall == nil || m.sessionSelectIndex >= len(m.sessionSelectItems) {
+		return m, nil
+	}
+	ball := m.editingBall
+	target := m.sessionSelectItems[m.sessionSelectIndex]
+
+	m.mode = splitView
+	m.editingBall = nil
+	m.sessionSelectItems = nil
+
+	// Clear any panel filter, which could hide the session or the ball
+	m.panelSearchQuery = ""
+	m.panelSearchActive = false
+
+	found := -1
+	for i, sess := range m.filterSessions() {
+		if sess.ID == target.ID {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		m.message = "Session not shown: " + target.ID
+		return m, nil
+	}
+
+	m.sessionCursor = found
+	m.selectedSession = target
+	m.selectedBalls = make(map[string]bool)
+	m.ballsScrollOffset = 0
+	m.cursor = 0
+	for i, b := range m.filterBallsForSession() {
+		if b.ID == ball.ID {
+			m.cursor = i
+			break
+		}
+	}
+	m.activePanel = BallsPanel
+	m.addActivity("Selected session: " + target.ID)
+	return m, nil
+}
+
+// ballHasSessionTag returns true if the ball is tagged with the session ID
+func ballHasSessionTag(ball *session.Ball, sessionID string) bool {
+	for _, tag := range ball.Tags {
+		if tag == sessionID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ballView caches the balls panel's balls between changes. With a few
+// thousand balls across projects, parking, searching and sorting them again
+// on every keypress and render made the split view sluggish, so the result
+// is kept until the balls or the view's settings change. Models hold it by
+// pointer so the copies bubbletea passes around share it.
+type ballView struct {
+	key        ballViewKey
+	valid      bool
+	searched   []*session.Ball   // searchBallsForSession's result, nil until asked for
+	parked     int               // Parked balls in the session, set with searched
+	sorted     []*session.Ball   // filterBallsForSession's result, nil until asked for
+	minimalIDs map[string]string // Minimal unique IDs of sorted, nil until asked for
+}
+
+// ballViewKey is everything the balls panel's balls depend on. The balls
+// and sessions themselves are stood for by ballsGen, which changes
+// whenever they're reloaded or refiltered.
+type ballViewKey struct {
+	generation int
+	balls      int // len(filteredBalls), for balls assigned without applyFilters
+	sessions   int
+	session    string
+	showParked bool
+	search     string
+	sortOrder  SortOrder
+	customSort *CustomSort
+	layout     ViewLayout
+	localOnly  bool
+	collapsed  int
+	minute     time.Time // Snoozes run out with the clock
+}
+
+// currentBallView returns the balls panel's cache, emptied first if
+// anything it depends on changed, or nil for models built without one
+func (m *Model) currentBallView() *ballView {
+	if m.ballCache == nil {
+		return nil
+	}
+	key := ballViewKey{
+		generation: m.ballsGen,
+		balls:      len(m.filteredBalls),
+		sessions:   len(m.sessions),
+		showParked: m.showParked,
+		sortOrder:  m.sortOrder,
+		customSort: m.customSort,
+		layout:     m.layout,
+		localOnly:  m.localOnly,
+		collapsed:  len(m.collapsedProjects),
+		minute:     m.now().Truncate(time.Minute),
+	}
+	if m.selectedSession != nil {
+		key.session = m.selectedSession.ID
+	}
+	if m.panelSearchActive {
+		key.search = m.panelSearchQuery
+	}
+	if !m.ballCache.valid || m.ballCache.key != key {
+		*m.ballCache = ballView{key: key, valid: true}
+	}
+	return m.ballCache
+}
+
+// ballsChanged marks the balls panel's cache stale after the loaded balls
+// or sessions change
+func (m *Model) ballsChanged() {
+	m.ballsGen++
+}
+
+// minimalBallIDs returns the minimal unique IDs of the balls panel's balls
+func (m *Model) minimalBallIDs() map[string]string {
+	balls := m.filterBallsForSession()
+	cache := m.currentBallView()
+	if cache == nil {
+		return session.ComputeMinimalUniqueIDs(balls)
+	}
+	if cache.minimalIDs == nil {
+		cache.minimalIDs = session.ComputeMinimalUniqueIDs(balls)
+	}
+	return cache.minimalIDs
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// briefingHistoryMsg carries a session's agent history for its briefing.
+// auto is set when the briefing was asked for by selecting the session, so
+// it's only shown if the session has been idle a while.
+type briefingHistoryMsg struct {
+	sess    *session.JuggleSession
+	history []*session.AgentRunRecord
+	auto    bool
+	err     error
+}
+
+// loadBriefingHistory loads the agent runs on sess from its project
+func loadBriefingHistory(sess *session.JuggleSession, projectDir string, auto bool) tea.Cmd {
+	return func() tea.Msg {
+		historyStore, err := session.NewAgentHistoryStore(projectDir)
+		if err != nil {
+			return briefingHistoryMsg{sess: sess, auto: auto, err: err}
+		}
+		history, err := historyStore.LoadHistoryBySession(sess.ID)
+		return briefingHistoryMsg{sess: sess, history: history, auto: auto, err: err}
+	}
+}
+
+// briefingCmd returns the command loading sess's briefing, or nil for the
+// pseudo-sessions, which have no goal or agent history of their own
+func (m Model) briefingCmd(sess *session.JuggleSession, auto bool) tea.Cmd {
+	if sess == nil || isPseudoSession(sess.ID) {
+		return nil
+	}
+	projectDir := sess.ProjectDir
+	if projectDir == "" && m.store != nil {
+		projectDir = m.store.ProjectDir()
+	}
+	if projectDir == "" {
+		return nil
+	}
+	return loadBriefingHistory(sess, projectDir, auto)
+}
+
+// handleBriefingStart opens the briefing for the highlighted session
+func (m Model) handleBriefingStart() (tea.Model, tea.Cmd) {
+	sessions := m.filterSessions()
+	if m.sessionCursor >= len(sessions) {
+		return m, nil
+	}
+	cmd := m.briefingCmd(sessions[m.sessionCursor], false)
+	if cmd == nil {
+		m.message = "Select a session to see its briefing"
+		return m, nil
+	}
+	return m, cmd
+}
+
+// handleBriefingHistory builds the briefing and shows it, unless it was
+// asked for by selecting a session that's been worked on recently
+func (m Model) handleBriefingHistory(msg briefingHistoryMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		if !msg.auto {
+			m.message = "Error loading briefing: " + msg.err.Error()
+		}
+		return m, nil
+	}
+
+	briefing := session.BuildBriefing(msg.sess, m.balls, msg.history)
+	if msg.auto && briefing.IdleFor(m.now()) < session.BriefingIdleAfter {
+		return m, nil
+	}
+	m.briefing = briefing
+	m.mode = briefingView
+	return m, nil
+}
+
+// handleBriefingKey closes the briefing
+func (m Model) handleBriefingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter", "b":
+		m.mode = splitView
+		m.briefing = nil
+	}
+	return m, nil
+}
+
+// now returns the current time, overridable in tests
+func (m Model) now() time.Time {
+	if m.nowFunc != nil {
+		return m.nowFunc()
+	}
+	return time.Now()
+}
+
+// renderBriefingView renders the briefing for a session
+func (m Model) renderBriefingView() string {
+	briefing := m.briefing
+	if briefing == nil {
+		return ""
+	}
+	var b strings.Builder
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")) // Cyan
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))              // Gray
+
+	header := "Briefing: " + briefing.SessionID
+	if idle := briefing.IdleFor(m.now()); idle >= time.Minute {
+		header += dimStyle.Render(" (last touched " + formatDuration(idle) + " ago)")
+	}
+	b.WriteString(labelStyle.Render(header) + "\n")
+	if briefing.Goal != "" {
+		b.WriteString("Goal: " + briefing.Goal + "\n")
+	}
+
+	b.WriteString("\n" + labelStyle.Render("Next up") + "\n")
+	if len(briefing.Next) == 0 {
+		b.WriteString(dimStyle.Render("  (nothing ready to work on)") + "\n")
+	}
+	for _, ball := range briefing.Next {
+		line := fmt.Sprintf("  %s  %-7s %-11s %s", ball.ShortID(), ball.Priority, ball.State, ball.Title)
+		if ball.Focused {
+			line += " (focus)"
+		}
+		b.WriteString(line + "\n")
+		if ball.NextAction != "" {
+			b.WriteString(dimStyle.Render("    → "+ball.NextAction) + "\n")
+		}
+	}
+	if more := briefing.Remaining - len(briefing.Next); more > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  +%d more pending or in progress", more)) + "\n")
+	}
+
+	if len(briefing.Blocked) > 0 {
+		b.WriteString("\n" + labelStyle.Render("Blocked") + "\n")
+		for _, ball := range briefing.Blocked {
+			b.WriteString(fmt.Sprintf("  %s  %s\n", ball.ShortID(), ball.Title))
+			if ball.BlockedReason != "" {
+				b.WriteString(dimStyle.Render("    "+ball.BlockedReason) + "\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	if run := briefing.LastRun; run != nil {
+		b.WriteString(fmt.Sprintf("Last agent run: %s, %s\n", run.StartedAt.Format("2006-01-02 15:04"), run.Outcome()))
+	} else {
+		b.WriteString("Last agent run: " + dimStyle.Render("none") + "\n")
+	}
+
+	b.WriteString("\n" + lipgloss.NewStyle().Faint(true).Render("Esc/Enter = close"))
+	return b.String()
+}
@@ -11,18 +11,22 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agentlog"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/watcher"
 )
 
 type ballsLoadedMsg struct {
-	balls []*session.Ball
-	err   error
+	balls    []*session.Ball
+	archived []*session.Ball
+	err      error
 }
 
-func loadBalls(store *session.Store, config *session.Config, localOnly bool) tea.Cmd {
+// loadBalls loads the balls to show. In all-projects mode, the cache (which
+// may be nil) skips re-reading projects whose files haven't changed.
+func loadBalls(store *session.Store, cache *session.ProjectCache, config *session.Config, localOnly bool) tea.Cmd {
 	return func() tea.Msg {
-		var balls []*session.Ball
+		var balls, archived []*session.Ball
 
 		if localOnly {
 			// Load only from current project
@@ -31,6 +35,8 @@ func loadBalls(store *session.Store, config *session.Config, localOnly bool) tea
 				return ballsLoadedMsg{err: err}
 			}
 			balls = localBalls
+			// Archived balls are only needed to resolve dependencies, so errors are ignored
+			archived, _ = store.LoadArchivedBalls()
 		} else {
 			// Load from all discovered projects
 			projects, err := session.DiscoverProjects(config)
@@ -38,19 +44,21 @@ func loadBalls(store *session.Store, config *session.Config, localOnly bool) tea
 				return ballsLoadedMsg{err: err}
 			}
 
-			balls, err = session.LoadAllBalls(projects)
+			balls, err = cache.LoadBalls(projects)
 			if err != nil {
 				return ballsLoadedMsg{err: err}
 			}
+			archived, _ = cache.LoadArchivedBalls(projects)
 		}
 
-		return ballsLoadedMsg{balls: balls}
+		return ballsLoadedMsg{balls: balls, archived: archived}
 	}
 }
 
 type ballUpdatedMsg struct {
-	ball *session.Ball
-	err  error
+	ball    *session.Ball
+	warning string // Shown instead of the success message (see withWarning)
+	err     error
 }
 
 func updateBall(store *session.Store, ball *session.Ball) tea.Cmd {
@@ -63,13 +71,41 @@ func updateBall(store *session.Store, ball *session.Ball) tea.Cmd {
 }
 
 type ballArchivedMsg struct {
-	ball *session.Ball
-	err  error
+	ball    *session.Ball
+	next    *session.Ball // Next instance of a recurring ball, if one was created
+	warning string        // Shown instead of the success message (see withWarning)
+	err     error
 }
 
-// updateAndArchiveBall updates the ball and then archives it
+// withWarning attaches a warning, such as failing tests under the warn
+// tests policy, to the result of a ball update or archive, so the success
+// message doesn't replace it
+func withWarning(cmd tea.Cmd, warning string) tea.Cmd {
+	if warning == "" {
+		return cmd
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case ballUpdatedMsg:
+			msg.warning = warning
+			return msg
+		case ballArchivedMsg:
+			msg.warning = warning
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+// updateAndArchiveBall updates the ball and then archives it. A recurring
+// ball first hands its schedule to a fresh instance.
 func updateAndArchiveBall(store *session.Store, ball *session.Ball) tea.Cmd {
 	return func() tea.Msg {
+		next, err := store.Recur(ball, time.Now())
+		if err != nil {
+			return ballArchivedMsg{err: err}
+		}
 		// First update the ball to persist state changes
 		if err := store.UpdateBall(ball); err != nil {
 			return ballArchivedMsg{err: err}
@@ -78,7 +114,7 @@ func updateAndArchiveBall(store *session.Store, ball *session.Ball) tea.Cmd {
 		if err := store.ArchiveBall(ball); err != nil {
 			return ballArchivedMsg{err: err}
 		}
-		return ballArchivedMsg{ball: ball}
+		return ballArchivedMsg{ball: ball, next: next}
 	}
 }
 
@@ -93,13 +129,113 @@ func archiveBall(store *session.Store, ball *session.Ball) tea.Cmd {
 	}
 }
 
+type ballReopenedMsg struct {
+	ball     *session.Ball
+	restored []string // Session tags added back to the ball
+	archived bool     // The ball was restored from the archive
+	err      error
+}
+
+// reopenBall returns a completed ball to pending, restoring it from the
+// archive if needed
+func reopenBall(store *session.Store, ballID string) tea.Cmd {
+	return func() tea.Msg {
+		ball, restored, archived, err := store.ReopenBall(ballID, "")
+		return ballReopenedMsg{ball: ball, restored: restored, archived: archived, err: err}
+	}
+}
+
+type undoneMsg struct {
+	entry *session.UndoEntry
+	redo  bool
+	err   error
+}
+
+// undoBallChange undoes, or redoes, the last ball change in the store's project
+func undoBallChange(store *session.Store, redo bool) tea.Cmd {
+	return func() tea.Msg {
+		step := store.Undo
+		if redo {
+			step = store.Redo
+		}
+		entry, err := step()
+		return undoneMsg{entry: entry, redo: redo, err: err}
+	}
+}
+
+type recurrencesMaterializedMsg struct {
+	created []*session.Ball
+	err     error
+}
+
+// materializeRecurrences creates the due instances of recurring balls in the
+// shown projects
+func materializeRecurrences(store *session.Store, config *session.Config, localOnly bool) tea.Cmd {
+	return func() tea.Msg {
+		projects := []string{store.ProjectDir()}
+		if !localOnly {
+			var err error
+			if projects, err = session.DiscoverProjects(config); err != nil {
+				return recurrencesMaterializedMsg{err: err}
+			}
+		}
+		var created []*session.Ball
+		for _, projectDir := range projects {
+			projectStore, err := session.NewStore(projectDir)
+			if err != nil {
+				return recurrencesMaterializedMsg{created: created, err: err}
+			}
+			balls, err := projectStore.MaterializeRecurrences(time.Now())
+			created = append(created, balls...)
+			if err != nil {
+				return recurrencesMaterializedMsg{created: created, err: err}
+			}
+		}
+		return recurrencesMaterializedMsg{created: created}
+	}
+}
+
+type gitCommitsSyncedMsg struct {
+	changed []*session.Ball
+	err     error
+}
+
+// syncGitCommits collects the commits that reference balls in the shown
+// projects (see juggle link)
+func syncGitCommits(store *session.Store, config *session.Config, localOnly bool) tea.Cmd {
+	return func() tea.Msg {
+		projects := []string{store.ProjectDir()}
+		if !localOnly {
+			var err error
+			if projects, err = session.DiscoverProjects(config); err != nil {
+				return gitCommitsSyncedMsg{err: err}
+			}
+		}
+		var changed []*session.Ball
+		for _, projectDir := range projects {
+			projectStore, err := session.NewStore(projectDir)
+			if err != nil {
+				return gitCommitsSyncedMsg{changed: changed, err: err}
+			}
+			balls, err := projectStore.SyncGitCommits()
+			changed = append(changed, balls...)
+			if err != nil {
+				return gitCommitsSyncedMsg{changed: changed, err: err}
+			}
+		}
+		return gitCommitsSyncedMsg{changed: changed}
+	}
+}
+
 // Sessions loading for split view
 type sessionsLoadedMsg struct {
 	sessions []*session.JuggleSession
 	err      error
 }
 
-func loadSessions(sessionStore *session.SessionStore, config *session.Config, localOnly bool) tea.Cmd {
+// loadSessions loads the sessions to show, using the cache (which may be
+// nil) like loadBalls
+func loadSessions(sessionStore *session.SessionStore, cache *session.ProjectCache, config *session.Config, localOnly bool) tea.Cmd {
 	return func() tea.Msg {
 		var sessions []*session.JuggleSession
 
@@ -120,7 +256,7 @@ func loadSessions(sessionStore *session.SessionStore, config *session.Config, lo
 				return sessionsLoadedMsg{err: err}
 			}
 
-			sessions, err = session.LoadAllSessions(projects)
+			sessions, err = cache.LoadSessions(projects)
 			if err != nil {
 				return sessionsLoadedMsg{err: err}
 			}
@@ -139,6 +275,13 @@ type watcherErrorMsg struct {
 	err error
 }
 
+// watchFilesErrorMsg reports a failure to watch a ball's source files.
+// Unlike watcherErrorMsg it doesn't come from the event listener, so it
+// must not start another one.
+type watchFilesErrorMsg struct {
+	err error
+}
+
 // listenForWatcherEvents creates a command that listens for watcher events
 func listenForWatcherEvents(w *watcher.Watcher) tea.Cmd {
 	return func() tea.Msg {
@@ -151,6 +294,78 @@ func listenForWatcherEvents(w *watcher.Watcher) tea.Cmd {
 	}
 }
 
+// ballsChangedMsg carries fresh copies of the balls a watcher event named,
+// to be patched into the list without reloading every project
+type ballsChangedMsg struct {
+	balls []*session.Ball
+	err   error
+}
+
+// loadChangedBalls reloads a project's balls and returns the ones with the
+// given IDs
+func loadChangedBalls(cache *session.ProjectCache, projectDir string, ids []string) tea.Cmd {
+	return func() tea.Msg {
+		projectBalls, err := cache.LoadBalls([]string{projectDir})
+		if err != nil {
+			return ballsChangedMsg{err: err}
+		}
+		wanted := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		changed := make([]*session.Ball, 0, len(ids))
+		for _, ball := range projectBalls {
+			if wanted[ball.ID] {
+				changed = append(changed, ball)
+			}
+		}
+		return ballsChangedMsg{balls: changed}
+	}
+}
+
+// patchBalls returns balls with each changed ball replacing the one with its
+// ID, and balls it didn't have appended
+func patchBalls(balls, changed []*session.Ball) []*session.Ball {
+	byID := make(map[string]*session.Ball, len(changed))
+	for _, ball := range changed {
+		byID[ball.ID] = ball
+	}
+	patched := make([]*session.Ball, 0, len(balls)+len(changed))
+	for _, ball := range balls {
+		if updated, ok := byID[ball.ID]; ok {
+			patched = append(patched, updated)
+			delete(byID, ball.ID)
+			continue
+		}
+		patched = append(patched, ball)
+	}
+	for _, ball := range changed {
+		if _, added := byID[ball.ID]; added {
+			patched = append(patched, ball)
+		}
+	}
+	return patched
+}
+
+// watchBallFiles watches the source directories covered by balls' watch globs
+func watchBallFiles(w *watcher.Watcher, balls []*session.Ball) tea.Cmd {
+	return func() tea.Msg {
+		for _, ball := range balls {
+			if len(ball.WatchGlobs) == 0 || ball.WorkingDir == "" {
+				continue
+			}
+			roots := make([]string, 0, len(ball.WatchGlobs))
+			for _, glob := range ball.WatchGlobs {
+				roots = append(roots, session.WatchGlobRoot(glob))
+			}
+			if err := w.WatchFiles(ball.WorkingDir, roots); err != nil {
+				return watchFilesErrorMsg{err: err}
+			}
+		}
+		return nil
+	}
+}
+
 // Agent-related messages
 type agentStartedMsg struct {
 	sessionID string
@@ -186,8 +401,9 @@ type agentCancelledMsg struct {
 
 // agentProcessStartedMsg is sent when agent process is started, providing reference for cancellation
 type agentProcessStartedMsg struct {
-	process   *AgentProcess
-	sessionID string
+	process       *AgentProcess
+	sessionID     string
+	maxIterations int
 }
 
 // AgentStatus tracks the state of a running agent
@@ -270,6 +486,23 @@ func (p *AgentProcess) Wait() error {
 	return p.waitErr
 }
 
+// staleCheckpointsMsg carries the checkpoints of interrupted agent runs
+type staleCheckpointsMsg struct {
+	checkpoints []*session.AgentCheckpoint
+}
+
+// loadStaleCheckpoints finds agent runs in the project that were killed
+// partway, which agent resume can carry on
+func loadStaleCheckpoints(sessionStore *session.SessionStore) tea.Cmd {
+	return func() tea.Msg {
+		checkpoints, err := sessionStore.StaleAgentCheckpoints()
+		if err != nil {
+			return nil
+		}
+		return staleCheckpointsMsg{checkpoints: checkpoints}
+	}
+}
+
 // launchAgentCmd creates a command that runs the agent for a session
 func launchAgentCmd(sessionID string) tea.Cmd {
 	return func() tea.Msg {
@@ -308,9 +541,11 @@ func launchAgentCmd(sessionID string) tea.Cmd {
 
 // launchAgentWithOutputCmd creates a command that runs the agent and streams output
 // It returns the process reference via agentProcessStartedMsg for cancellation support
-func launchAgentWithOutputCmd(sessionID string, outputCh chan<- agentOutputMsg) tea.Cmd {
+func launchAgentWithOutputCmd(opts agentLaunchOptions, outputCh chan<- agentOutputMsg) tea.Cmd {
+	sessionID := opts.SessionID
 	return func() tea.Msg {
-		cmd := exec.Command("juggle", "agent", "run", sessionID)
+		cmd := exec.Command("juggle", opts.args()...)
+		cmd.Dir = opts.ProjectDir
 
 		// Create pipes for stdout and stderr
 		stdout, err := cmd.StdoutPipe()
@@ -381,7 +616,7 @@ func launchAgentWithOutputCmd(sessionID string, outputCh chan<- agentOutputMsg)
 			}
 		}()
 
-		return agentProcessStartedMsg{process: process, sessionID: sessionID}
+		return agentProcessStartedMsg{process: process, sessionID: sessionID, maxIterations: opts.Iterations}
 	}
 }
 
@@ -455,20 +690,30 @@ func loadAgentHistory(projectDir string) tea.Cmd {
 	}
 }
 
-// historyOutputLoadedMsg is sent when last_output.txt content is loaded
+// historyOutputLoadedMsg is sent when a history record's output is loaded:
+// its streamed log if it has one, otherwise last_output.txt
 type historyOutputLoadedMsg struct {
 	content string
+	log     *agentlog.Reader
 	err     error
 }
 
-// loadHistoryOutput creates a command to load the output file for a history record
-func loadHistoryOutput(outputFile string) tea.Cmd {
+// loadHistoryOutput creates a command to load the output of a history
+// record. The run's log is indexed rather than read, so pages of it are
+// read from disk as they're shown.
+func loadHistoryOutput(record *session.AgentRunRecord) tea.Cmd {
 	return func() tea.Msg {
-		if outputFile == "" {
+		if record.LogFile != "" {
+			if log, err := agentlog.Open(record.LogFile); err == nil {
+				return historyOutputLoadedMsg{log: log}
+			}
+			// Pruned or moved; fall back to the last iteration's output
+		}
+		if record.OutputFile == "" {
 			return historyOutputLoadedMsg{content: "(no output file)", err: nil}
 		}
 
-		data, err := readFile(outputFile)
+		data, err := readFile(record.OutputFile)
 		if err != nil {
 			return historyOutputLoadedMsg{content: "", err: err}
 		}
@@ -481,3 +726,21 @@ func loadHistoryOutput(outputFile string) tea.Cmd {
 func readFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
+
+// transcriptLoadedMsg is sent when an attached transcript is loaded
+type transcriptLoadedMsg struct {
+	content string
+	err     error
+}
+
+// loadTranscript creates a command to load one of a ball's attached transcripts
+func loadTranscript(ball *session.Ball, index int) tea.Cmd {
+	return func() tea.Msg {
+		store, err := session.NewStore(ball.WorkingDir)
+		if err != nil {
+			return transcriptLoadedMsg{err: err}
+		}
+		content, err := store.LoadTranscript(ball.Transcripts[index])
+		return transcriptLoadedMsg{content: content, err: err}
+	}
+}
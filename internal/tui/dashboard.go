@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// dashboardLoadedMsg carries the project summaries for the dashboard
+type dashboardLoadedMsg struct {
+	summaries []*session.ProjectSummary
+	err       error
+}
+
+// loadDashboard summarizes every enabled registered project, most recently active
+// first. The cache only re-reads projects whose balls or milestones changed.
+func loadDashboard(cache *session.ProjectCache, config *session.Config, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		projects, err := session.DiscoverProjects(config)
+		if err != nil {
+			return dashboardLoadedMsg{err: err}
+		}
+		summaries, err := cache.LoadSummaries(projects, now)
+		if err != nil {
+			return dashboardLoadedMsg{err: err}
+		}
+		sort.SliceStable(summaries, func(i, j int) bool {
+			return summaries[i].LastActivity.After(summaries[j].LastActivity)
+		})
+		return dashboardLoadedMsg{summaries: summaries}
+	}
+}
+
+// refreshDashboard returns the command reloading the dashboard, or nil
+// outside all-projects mode, where there is no dashboard
+func (m Model) refreshDashboard() tea.Cmd {
+	if m.localOnly {
+		return nil
+	}
+	return loadDashboard(m.projectCache, m.config, m.now())
+}
+
+// renderDashboardPanel renders the dashboard in place of the balls panel:
+// one row per project with its balls by state, overdue balls, running
+// agents and last activity
+func (m Model) renderDashboardPanel(width, height int) string {
+	var b strings.Builder
+
+	titleStyle := panelTitleStyle
+	if m.activePanel == BallsPanel {
+		titleStyle = activePanelTitleStyle
+	}
+	stats := fmt.Sprintf("%d projects", len(m.dashboard))
+	title := titleStyle.Render(truncate("Dashboard", width-displayWidth(stats)-4))
+	statsRendered := lipgloss.NewStyle().Faint(true).Render(stats)
+	padding := width - lipgloss.Width(title) - lipgloss.Width(statsRendered) - 1
+	if padding < 1 {
+		padding = 1
+	}
+	b.WriteString(title + strings.Repeat(" ", padding) + statsRendered + "\n")
+	b.WriteString(strings.Repeat("─", width) + "\n")
+
+	if len(m.dashboard) == 0 {
+		b.WriteString(helpStyle.Render("  No projects") + "\n")
+		return b.String()
+	}
+
+	overdueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	agentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+
+	// Fixed-width counts on the right, the project name takes the rest
+	const countWidth, agentsWidth, activityWidth = 5, 8, 9
+	nameWidth := width - 2 - 5*(countWidth+1) - (agentsWidth + 1) - activityWidth
+	if nameWidth < 8 {
+		nameWidth = 8
+	}
+	header := "  " + fitWidth("PROJECT", nameWidth)
+	for _, label := range []string{icons.StatePending, icons.StateInProgress, icons.StateBlocked, icons.StateComplete, "LATE"} {
+		header += " " + fitWidth(label, countWidth)
+	}
+	header += " " + fitWidth("AGENTS", agentsWidth) + " " + "ACTIVE"
+	b.WriteString(helpStyle.Render(truncate(header, width)) + "\n")
+
+	now := m.now()
+	rows := height - 5
+	if rows < 1 {
+		rows = 1
+	}
+	for i, summary := range m.dashboard {
+		if i == rows && len(m.dashboard) > rows+1 {
+			b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more projects", len(m.dashboard)-rows)) + "\n")
+			break
+		}
+		line := "  " + fitWidth(m.projectName(summary.ProjectDir), nameWidth)
+		for _, state := range []session.BallState{session.StatePending, session.StateInProgress, session.StateBlocked, session.StateComplete} {
+			line += " " + fitWidth(fmt.Sprint(summary.States[state]), countWidth)
+		}
+		overdue := fitWidth(fmt.Sprint(summary.Overdue), countWidth)
+		if summary.Overdue > 0 {
+			overdue = overdueStyle.Render(overdue)
+		}
+		line += " " + overdue
+
+		agents := fitWidth("-", agentsWidth)
+		if n := len(summary.RunningAgents); n > 0 {
+			agents = agentStyle.Render(fitWidth(fmt.Sprintf("%s %d", icons.AgentRunning, n), agentsWidth))
+		}
+		line += " " + agents + " " + formatLastActivity(summary.LastActivity, now)
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// formatLastActivity formats how long ago a project was last active, as a
+// date once it's more than a couple of days
+func formatLastActivity(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	ago := now.Sub(t)
+	switch {
+	case ago < time.Minute:
+		return "now"
+	case ago < time.Hour:
+		return fmt.Sprintf("%dm ago", int(ago.Minutes()))
+	case ago < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(ago.Hours()))
+	case t.Year() == now.Year():
+		return t.Format("Jan 2")
+	default:
+		return t.Format("2006-01")
+	}
+}
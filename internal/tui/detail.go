@@ -21,12 +21,25 @@ func renderBallDetail(ball *session.Ball) string {
 		b.WriteString(renderField("Context", ball.Context))
 	}
 	b.WriteString(renderField("Title", ball.Title))
+	if ball.NextAction != "" {
+		b.WriteString(renderField("Next Action", ball.NextAction))
+	}
 	b.WriteString(renderField("Priority", string(ball.Priority)))
 	b.WriteString(renderField("State", formatState(ball)))
+	if ball.Checkpoint != "" {
+		b.WriteString(renderField("Checkpoint", ball.Checkpoint))
+	}
 	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
 		reasonStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Italic(true)
 		b.WriteString(renderField("Blocked Reason", reasonStyle.Render(ball.BlockedReason)))
 	}
+	if ball.IsEscalated() {
+		b.WriteString(renderField("Escalated", ball.Escalation.Label()))
+	}
+	if ball.IsRecurring() {
+		b.WriteString(renderField("Recurs", ball.Recurrence.Label()))
+		b.WriteString(renderField("Due", ball.Recurrence.DueLabel(time.Now())))
+	}
 	b.WriteString(renderField("Working Dir", ball.WorkingDir))
 
 	// Timestamps
@@ -41,15 +54,24 @@ func renderBallDetail(ball *session.Ball) string {
 	if len(ball.Tags) > 0 {
 		b.WriteString(renderField("Tags", strings.Join(ball.Tags, ", ")))
 	}
+	if ball.Milestone != "" {
+		b.WriteString(renderField("Milestone", ball.Milestone))
+	}
+	if ball.Git != nil {
+		b.WriteString(renderField("Git", ball.Git.Label()))
+	}
 
 	// Acceptance Criteria
 	if len(ball.AcceptanceCriteria) > 0 {
 		b.WriteString("\n" + lipgloss.NewStyle().Bold(true).Render("Acceptance Criteria:") + "\n")
 		for i, ac := range ball.AcceptanceCriteria {
-			acLine := fmt.Sprintf("  %d. %s", i+1, ac)
+			acLine := fmt.Sprintf("  %d. %s%s", i+1, acVerdictMark(ball, i), ac)
 			b.WriteString(acLine + "\n")
 		}
 	}
+	if ball.Verification != nil {
+		b.WriteString(renderField("Verified", verificationLabel(ball)))
+	}
 
 	// Output/Research Results
 	if ball.Output != "" {
@@ -94,3 +116,24 @@ func pluralize(n int) string {
 	}
 	return "s"
 }
+
+// acVerdictMark returns a ✓ or ✗ for the criterion at index i from the
+// ball's last verification, or "" if it wasn't verified as it reads now
+func acVerdictMark(ball *session.Ball, i int) string {
+	if !ball.VerificationCurrent() {
+		return ""
+	}
+	if ball.Verification.Assessments[i].Satisfied {
+		return "✓ "
+	}
+	return "✗ "
+}
+
+// verificationLabel summarizes the ball's last verification
+func verificationLabel(ball *session.Ball) string {
+	label := ball.Verification.Label()
+	if !ball.VerificationCurrent() {
+		label += ", criteria changed since"
+	}
+	return label
+}
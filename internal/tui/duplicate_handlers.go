@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// snapshotBallIDs records the balls that exist when an agent starts, so the
+// balls it creates can be checked for duplicates when it finishes
+func (m *Model) snapshotBallIDs() {
+	m.agentKnownBallIDs = make(map[string]bool, len(m.balls))
+	for _, ball := range m.balls {
+		m.agentKnownBallIDs[ball.ID] = true
+	}
+	m.agentReportedBalls = nil
+}
+
+// detectAgentDuplicates cross-checks balls created during the last agent run
+// against all loaded balls and opens the merge prompt if any look duplicated,
+// returning the attention signal for the prompt
+func (m *Model) detectAgentDuplicates() tea.Cmd {
+	known := m.agentKnownBallIDs
+	m.agentKnownBallIDs = nil
+	if known == nil {
+		return nil
+	}
+
+	isCreated := make(map[string]bool)
+	for _, ball := range m.balls {
+		if !known[ball.ID] {
+			isCreated[ball.ID] = true
+		}
+	}
+	// Reported IDs may be short IDs or prefixes
+	for _, id := range m.agentReportedBalls {
+		if matches := session.ResolveBallByPrefix(m.balls, id); len(matches) == 1 {
+			isCreated[matches[0].ID] = true
+		}
+	}
+	m.agentReportedBalls = nil
+
+	var created []*session.Ball
+	for _, ball := range m.balls {
+		if isCreated[ball.ID] {
+			created = append(created, ball)
+		}
+	}
+
+	duplicates := session.FindNearDuplicates(created, m.balls, session.DefaultDuplicateThreshold)
+	if len(duplicates) == 0 {
+		return nil
+	}
+	for _, d := range duplicates {
+		m.addActivity(fmt.Sprintf("Possible duplicate: %s ≈ %s", d.Ball.ShortID(), d.Original.ShortID()))
+	}
+	m.pendingDuplicates = duplicates
+	m.mode = confirmDuplicateMerge
+	return m.attention(session.AttentionConfirm, "possible duplicate balls to review")
+}
+
+// handleDuplicateMergeKey handles the duplicate merge prompt, one duplicate at a time
+func (m Model) handleDuplicateMergeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if len(m.pendingDuplicates) == 0 {
+		m.mode = splitView
+		return m, nil
+	}
+	current := m.pendingDuplicates[0]
+
+	switch msg.String() {
+	case "m", "M", "y", "Y":
+		store, err := session.NewStore(current.Original.WorkingDir)
+		if err != nil {
+			m.message = "Error: " + err.Error()
+			return m.nextDuplicate()
+		}
+		if err := store.MergeDuplicateBall(current.Original, current.Ball); err != nil {
+			m.message = "Error merging balls: " + err.Error()
+			m.addActivity("Error merging balls: " + err.Error())
+			return m.nextDuplicate()
+		}
+		m.addActivity(fmt.Sprintf("Merged %s into %s", current.Ball.ShortID(), current.Original.ShortID()))
+		m.message = fmt.Sprintf("Merged %s into %s", current.Ball.ShortID(), current.Original.ShortID())
+		return m.nextDuplicate()
+
+	case "n", "N", "s", "S":
+		m.message = "Kept " + current.Ball.ShortID()
+		return m.nextDuplicate()
+
+	case "esc", "q":
+		m.pendingDuplicates = nil
+		m.mode = splitView
+		m.message = "Duplicates kept"
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+	}
+
+	return m, nil
+}
+
+// nextDuplicate moves to the next pending duplicate, returning to the split
+// view when none are left
+func (m Model) nextDuplicate() (tea.Model, tea.Cmd) {
+	m.pendingDuplicates = m.pendingDuplicates[1:]
+	if len(m.pendingDuplicates) > 0 {
+		return m, nil
+	}
+	m.mode = splitView
+	// Reload balls to reflect any merges
+	return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+}
@@ -3,10 +3,10 @@ package tui
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/editor"
 	"github.com/ohare93/juggle/internal/session"
 	"gopkg.in/yaml.v3"
 )
@@ -162,12 +162,6 @@ type editorResultMsg struct {
 
 // openEditorCmd creates a tea.Cmd that opens an external editor for ball editing
 func openEditorCmd(ball *session.Ball) tea.Cmd {
-	// Get editor from environment
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vi" // Default fallback
-	}
-
 	// Generate YAML content
 	yamlContent, err := ballToYAML(ball)
 	if err != nil {
@@ -198,17 +192,8 @@ func openEditorCmd(ball *session.Ball) tea.Cmd {
 	// Get the original content for comparison
 	originalContent := yamlContent
 
-	// Create the editor command
-	editorParts := strings.Fields(editor)
-	var editorCmd *exec.Cmd
-	if len(editorParts) > 1 {
-		editorCmd = exec.Command(editorParts[0], append(editorParts[1:], tmpPath)...)
-	} else {
-		editorCmd = exec.Command(editor, tmpPath)
-	}
-
 	// Use tea.ExecProcess to properly handle terminal suspension
-	return tea.ExecProcess(editorCmd, func(err error) tea.Msg {
+	return tea.ExecProcess(editor.Command(tmpPath), func(err error) tea.Msg {
 		defer os.Remove(tmpPath)
 
 		if err != nil {
@@ -221,14 +206,16 @@ func openEditorCmd(ball *session.Ball) tea.Cmd {
 			return editorResultMsg{ball: ball, err: fmt.Errorf("failed to read edited file: %w", err)}
 		}
 
-		// Check if content was modified
-		if string(editedContent) == originalContent {
+		// Check if content was modified, ignoring line endings a Windows
+		// editor may have changed
+		edited := session.NormalizeNewlines(string(editedContent))
+		if edited == originalContent {
 			return editorResultMsg{ball: ball, cancelled: true}
 		}
 
 		return editorResultMsg{
 			ball:       ball,
-			editedYAML: string(editedContent),
+			editedYAML: edited,
 		}
 	})
 }
@@ -0,0 +1,290 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// searchResultsMsg carries the results of a global search
+type searchResultsMsg struct {
+	query   string
+	results []*session.SearchResult
+	err     error
+}
+
+// runGlobalSearch searches balls, sessions, progress and archives in the
+// shown projects
+func runGlobalSearch(store *session.Store, config *session.Config, localOnly bool, input string) tea.Cmd {
+	return func() tea.Msg {
+		projects := []string{store.ProjectDir()}
+		if !localOnly {
+			var err error
+			if projects, err = session.DiscoverProjects(config); err != nil {
+				return searchResultsMsg{query: input, err: err}
+			}
+		}
+		results, err := session.Search(projects, parseSearchInput(input))
+		return searchResultsMsg{query: input, results: results, err: err}
+	}
+}
+
+// parseSearchInput reads filters written as state:, session: and project:
+// words (state takes a comma-separated list); the other words are the text
+func parseSearchInput(input string) session.SearchQuery {
+	var q session.SearchQuery
+	var words []string
+	for _, word := range strings.Fields(input) {
+		key, value, ok := strings.Cut(word, ":")
+		switch {
+		case ok && key == "state" && value != "":
+			for _, state := range strings.Split(value, ",") {
+				q.States = append(q.States, session.BallState(state))
+			}
+		case ok && key == "session" && value != "":
+			q.Session = value
+		case ok && key == "project" && value != "":
+			q.Project = value
+		default:
+			words = append(words, word)
+		}
+	}
+	q.Text = strings.Join(words, " ")
+	return q
+}
+
+// handleGlobalSearchStart opens the search view, keeping the last results
+func (m Model) handleGlobalSearchStart() (tea.Model, tea.Cmd) {
+	m.textInput.Reset()
+	m.textInput.Placeholder = "text, state:blocked, session:id, project:name"
+	m.textInput.SetValue(m.searchQuery)
+	m.textInput.Focus()
+	m.message = ""
+	m.mode = globalSearchView
+	return m, nil
+}
+
+// handleGlobalSearchKey handles keyboard input in the search view. Typing
+// edits the query, Enter searches (or, once the query has been searched,
+// jumps to the highlighted result) and the arrow keys move between results.
+func (m Model) handleGlobalSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = splitView
+		m.textInput.Blur()
+		m.message = ""
+		return m, nil
+
+	case "up", "ctrl+p":
+		if m.searchCursor > 0 {
+			m.searchCursor--
+		}
+		return m, nil
+
+	case "down", "ctrl+n":
+		if m.searchCursor < len(m.searchResults)-1 {
+			m.searchCursor++
+		}
+		return m, nil
+
+	case "ctrl+r":
+		return m.reopenSearchResult()
+
+	case "enter":
+		input := strings.TrimSpace(m.textInput.Value())
+		if input == "" {
+			return m, nil
+		}
+		if input == m.searchQuery && len(m.searchResults) > 0 {
+			return m.jumpToSearchResult()
+		}
+		m.message = "Searching..."
+		return m, runGlobalSearch(m.store, m.config, m.localOnly, input)
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// handleSearchResults shows the results of a search
+func (m Model) handleSearchResults(msg searchResultsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message = "Search failed: " + msg.err.Error()
+		return m, nil
+	}
+	m.searchQuery = msg.query
+	m.searchResults = msg.results
+	m.searchCursor = 0
+	m.message = fmt.Sprintf("%d result(s) - Enter jumps to the highlighted one", len(msg.results))
+	if len(msg.results) == 0 {
+		m.message = "No results"
+	}
+	m.addActivity(fmt.Sprintf("Searched %q: %d result(s)", msg.query, len(msg.results)))
+	return m, nil
+}
+
+// jumpToSearchResult selects the highlighted result in the split view: a
+// ball under the All session, or a session in the sessions panel
+func (m Model) jumpToSearchResult() (tea.Model, tea.Cmd) {
+	if m.searchCursor >= len(m.searchResults) {
+		return m, nil
+	}
+	result := m.searchResults[m.searchCursor]
+	if result.Archived {
+		m.message = "Archived ball - Ctrl+R reopens it: " + result.Ball.ID
+		return m, nil
+	}
+
+	m.mode = splitView
+	m.textInput.Blur()
+
+	// Clear any panel filter, which could hide the target
+	m.panelSearchQuery = ""
+	m.panelSearchActive = false
+
+	targetSession := PseudoSessionAll
+	if result.Session != nil {
+		targetSession = result.Session.ID
+	}
+	found := false
+	for i, sess := range m.filterSessions() {
+		if sess.ID == targetSession && (result.Session == nil || sess.ProjectDir == result.Session.ProjectDir) {
+			m.sessionCursor = i
+			m.selectedSession = sess
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.message = "Session not shown: " + targetSession
+		return m, nil
+	}
+	m.selectedBalls = make(map[string]bool)
+	m.ballsScrollOffset = 0
+	m.cursor = 0
+
+	if result.Session != nil {
+		m.activePanel = SessionsPanel
+		m.message = "Session: " + result.Session.ID
+		return m, nil
+	}
+
+	balls := m.filterBallsForSession()
+	for i, ball := range balls {
+		if ball.ID == result.Ball.ID {
+			m.cursor = i
+			m.adjustBallsScrollOffset(balls)
+			m.activePanel = BallsPanel
+			m.message = "Ball: " + ball.ID
+			return m, nil
+		}
+	}
+	m.activePanel = BallsPanel
+	m.message = "Ball not shown (check the state filters): " + result.Ball.ID
+	return m, nil
+}
+
+// reopenSearchResult reopens the highlighted ball if it's complete or
+// researched, restoring it from the archive if needed
+func (m Model) reopenSearchResult() (tea.Model, tea.Cmd) {
+	if m.searchCursor >= len(m.searchResults) {
+		return m, nil
+	}
+	ball := m.searchResults[m.searchCursor].Ball
+	if ball == nil || (ball.State != session.StateComplete && ball.State != session.StateResearched) {
+		m.message = "Can only reopen completed balls"
+		return m, nil
+	}
+	store, err := session.NewStore(ball.WorkingDir)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+	m.message = "Reopening " + ball.ID + "..."
+	return m, reopenBall(store, ball.ID)
+}
+
+var searchMatchStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+
+// renderGlobalSearchView renders the search input and results, each with
+// its first matching line highlighted
+func (m Model) renderGlobalSearchView() string {
+	var b strings.Builder
+
+	scope := "all projects"
+	if m.localOnly {
+		scope = "this project"
+	}
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")).
+		Render("Search " + scope)
+	b.WriteString(title + "\n\n")
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Width(60)
+	b.WriteString(inputStyle.Render(m.textInput.View()) + "\n\n")
+
+	// Each result takes two lines; keep the cursor in view
+	visible := max((m.height-12)/2, 3)
+	start := 0
+	if m.searchCursor >= visible {
+		start = m.searchCursor - visible + 1
+	}
+	end := min(start+visible, len(m.searchResults))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("240")).
+		Foreground(lipgloss.Color("15"))
+	dimStyle := lipgloss.NewStyle().Faint(true)
+
+	for i := start; i < end; i++ {
+		result := m.searchResults[i]
+		var line string
+		if ball := result.Ball; ball != nil {
+			state := string(ball.State)
+			if result.Archived {
+				state += ", archived"
+			}
+			line = fmt.Sprintf("%s (%s) %s", ball.ID, state, truncate(ball.Title, 60))
+		} else {
+			line = fmt.Sprintf("session %s %s", result.Session.ID, truncate(result.Session.Description, 60))
+		}
+		if !m.localOnly {
+			line += " [" + filepath.Base(result.ProjectDir) + "]"
+		}
+
+		if i == m.searchCursor {
+			b.WriteString("> " + selectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+		if len(result.Matches) > 0 {
+			match := result.Matches[0]
+			snippet := match.Snippet[:match.Start] + searchMatchStyle.Render(match.Snippet[match.Start:match.End]) + match.Snippet[match.End:]
+			more := ""
+			if len(result.Matches) > 1 {
+				more = dimStyle.Render(fmt.Sprintf(" (+%d)", len(result.Matches)-1))
+			}
+			b.WriteString("    " + dimStyle.Render(match.Field+":") + " " + snippet + more + "\n")
+		}
+	}
+	if remaining := len(m.searchResults) - end; remaining > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more results", remaining)) + "\n")
+	}
+
+	if m.message != "" {
+		b.WriteString("\n" + messageStyle.Render(m.message) + "\n")
+	}
+	b.WriteString("\n" + helpStyle.Render("Enter = search / jump | Ctrl+R = reopen | ↑/↓ = select | Esc = close"))
+	return b.String()
+}
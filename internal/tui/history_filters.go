@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// historyRange limits the history view to runs started recently
+type historyRange int
+
+const (
+	historyRangeAll   historyRange = iota // Every loaded run
+	historyRangeDay                       // Started in the last 24 hours
+	historyRangeWeek                      // Started in the last 7 days
+	historyRangeMonth                     // Started in the last 30 days
+)
+
+// historySort orders the history view
+type historySort int
+
+const (
+	historySortNewest     historySort = iota // Most recent first (default)
+	historySortDuration                      // Longest runs first
+	historySortIterations                    // Most iterations first
+	historySortBalls                         // Most balls completed first
+)
+
+// historyResults lists run results in the order the result filter cycles
+// through them
+var historyResults = []string{"complete", "blocked", "max_iterations", "timeout", "rate_limit", "cancelled", "error"}
+
+// label returns the range as shown in the filter line
+func (r historyRange) label() string {
+	switch r {
+	case historyRangeDay:
+		return "last 24h"
+	case historyRangeWeek:
+		return "last 7 days"
+	case historyRangeMonth:
+		return "last 30 days"
+	default:
+		return "all time"
+	}
+}
+
+// since returns the earliest start time the range includes (zero = any)
+func (r historyRange) since(now time.Time) time.Time {
+	switch r {
+	case historyRangeDay:
+		return now.Add(-24 * time.Hour)
+	case historyRangeWeek:
+		return now.AddDate(0, 0, -7)
+	case historyRangeMonth:
+		return now.AddDate(0, 0, -30)
+	default:
+		return time.Time{}
+	}
+}
+
+// label returns the sort order as shown in the filter line
+func (s historySort) label() string {
+	switch s {
+	case historySortDuration:
+		return "longest"
+	case historySortIterations:
+		return "most iterations"
+	case historySortBalls:
+		return "most balls done"
+	default:
+		return "newest"
+	}
+}
+
+// cycleHistoryValue returns the value after current in values, wrapping
+// back to "" (no filter) after the last one
+func cycleHistoryValue(values []string, current string) string {
+	for i, value := range values {
+		if value == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	if current == "" && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// historySessions returns the sessions with loaded runs, sorted
+func (m Model) historySessions() []string {
+	seen := make(map[string]bool)
+	sessions := make([]string, 0)
+	for _, record := range m.historyAll {
+		if !seen[record.SessionID] {
+			seen[record.SessionID] = true
+			sessions = append(sessions, record.SessionID)
+		}
+	}
+	sort.Strings(sessions)
+	return sessions
+}
+
+// historyResultsPresent returns the results of loaded runs, in filter order
+func (m Model) historyResultsPresent() []string {
+	present := make(map[string]bool)
+	for _, record := range m.historyAll {
+		present[record.Result] = true
+	}
+	results := make([]string, 0)
+	for _, result := range historyResults {
+		if present[result] {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// historyFiltered returns true if any history filter is on
+func (m Model) historyFiltered() bool {
+	return m.historySession != "" || m.historyResult != "" || m.historyRange != historyRangeAll
+}
+
+// applyHistoryView rebuilds agentHistory from every loaded run with the
+// filters and sort order applied, keeping the cursor on the same run
+func (m *Model) applyHistoryView() {
+	var selectedID string
+	if m.historyCursor < len(m.agentHistory) {
+		selectedID = m.agentHistory[m.historyCursor].ID
+	}
+
+	since := m.historyRange.since(m.now())
+	records := make([]*session.AgentRunRecord, 0, len(m.historyAll))
+	for _, record := range m.historyAll {
+		if m.historySession != "" && record.SessionID != m.historySession {
+			continue
+		}
+		if m.historyResult != "" && record.Result != m.historyResult {
+			continue
+		}
+		if !since.IsZero() && record.StartedAt.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		switch m.historySort {
+		case historySortDuration:
+			if a.Duration() != b.Duration() {
+				return a.Duration() > b.Duration()
+			}
+		case historySortIterations:
+			if a.Iterations != b.Iterations {
+				return a.Iterations > b.Iterations
+			}
+		case historySortBalls:
+			if a.BallsComplete != b.BallsComplete {
+				return a.BallsComplete > b.BallsComplete
+			}
+		}
+		return a.StartedAt.After(b.StartedAt)
+	})
+	m.agentHistory = records
+
+	m.historyCursor = 0
+	for i, record := range records {
+		if record.ID == selectedID {
+			m.historyCursor = i
+			break
+		}
+	}
+	visibleLines := 15
+	m.historyScrollOffset = 0
+	if m.historyCursor >= visibleLines {
+		m.historyScrollOffset = m.historyCursor - visibleLines + 1
+	}
+}
+
+// historyFilterLine describes the filters and sort order in effect
+func (m Model) historyFilterLine() string {
+	parts := []string{"session: all", "result: all", m.historyRange.label(), "sort: " + m.historySort.label()}
+	if m.historySession != "" {
+		parts[0] = "session: " + m.historySession
+	}
+	if m.historyResult != "" {
+		parts[1] = "result: " + m.historyResult
+	}
+	return strings.Join(parts, " · ")
+}
+
+// historySummary aggregates the runs in the history view, e.g.
+// "12 runs: 7 complete, 3 blocked, 2 other | 4h12m total, 3.2 iterations avg | 18/25 balls done"
+func (m Model) historySummary() string {
+	records := m.agentHistory
+	if len(records) == 0 {
+		return ""
+	}
+
+	var complete, blocked, iterations, ballsDone, ballsTotal int
+	var total time.Duration
+	for _, record := range records {
+		switch record.Result {
+		case "complete":
+			complete++
+		case "blocked":
+			blocked++
+		}
+		iterations += record.Iterations
+		ballsDone += record.BallsComplete
+		ballsTotal += record.BallsTotal
+		total += record.Duration()
+	}
+
+	runs := "runs"
+	if len(records) == 1 {
+		runs = "run"
+	}
+	counts := fmt.Sprintf("%d %s: %d complete, %d blocked", len(records), runs, complete, blocked)
+	if other := len(records) - complete - blocked; other > 0 {
+		counts += fmt.Sprintf(", %d other", other)
+	}
+	avg := float64(iterations) / float64(len(records))
+	return fmt.Sprintf("%s | %s total, %.1f iterations avg | %d/%d balls done",
+		counts, formatDuration(total), avg, ballsDone, ballsTotal)
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -97,12 +99,44 @@ func (m Model) handleHistoryViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "s":
+		// Cycle the session filter through sessions with runs
+		m.historySession = cycleHistoryValue(m.historySessions(), m.historySession)
+		m.applyHistoryView()
+		return m, nil
+
+	case "r":
+		// Cycle the result filter through results of loaded runs
+		m.historyResult = cycleHistoryValue(m.historyResultsPresent(), m.historyResult)
+		m.applyHistoryView()
+		return m, nil
+
+	case "d":
+		// Cycle the date range: all → 24h → 7 days → 30 days
+		m.historyRange = (m.historyRange + 1) % (historyRangeMonth + 1)
+		m.applyHistoryView()
+		return m, nil
+
+	case "o":
+		// Cycle the sort order: newest → duration → iterations → balls done
+		m.historySort = (m.historySort + 1) % (historySortBalls + 1)
+		m.applyHistoryView()
+		return m, nil
+
+	case "c":
+		// Clear the filters (the sort order stays)
+		m.historySession = ""
+		m.historyResult = ""
+		m.historyRange = historyRangeAll
+		m.applyHistoryView()
+		return m, nil
+
 	case "enter", " ":
 		// View output file for selected record
 		if len(m.agentHistory) > 0 && m.historyCursor < len(m.agentHistory) {
 			record := m.agentHistory[m.historyCursor]
 			m.addActivity("Loading output for run: " + record.ID)
-			return m, loadHistoryOutput(record.OutputFile)
+			return m, loadHistoryOutput(record)
 		}
 		return m, nil
 	}
@@ -114,11 +148,19 @@ func (m Model) handleHistoryViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleHistoryOutputViewKey handles keyboard input in history output view
 func (m Model) handleHistoryOutputViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.outputSelection != nil {
+		return m.handleOutputSelectionKey(msg)
+	}
+
 	switch msg.String() {
+	case "V":
+		// Select lines to create a ball from
+		return m.handleStartOutputSelection()
+
 	case "q", "esc", "b":
 		// Return to history view
 		m.mode = historyView
-		m.historyOutput = ""
+		m.closeHistoryOutput()
 		return m, nil
 
 	case "up", "k":
@@ -157,9 +199,9 @@ func (m Model) handleHistoryOutputViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "G":
-		// Go to bottom (set to large value, will be clamped in render)
+		// Go to bottom (clamped in render)
 		m.lastKey = ""
-		m.historyOutputOffset = 10000
+		m.historyOutputOffset = m.historyOutputLines()
 		return m, nil
 	}
 
@@ -167,3 +209,49 @@ func (m Model) handleHistoryOutputViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.lastKey = ""
 	return m, nil
 }
+
+// historyOutputVisibleLines returns the number of output lines the history
+// output view shows at once
+func (m Model) historyOutputVisibleLines() int {
+	visibleLines := m.height - 6 // Account for header, footer
+	if visibleLines < 5 {
+		visibleLines = 5
+	}
+	return visibleLines
+}
+
+// historyOutputLines returns the number of lines in the output being viewed
+func (m Model) historyOutputLines() int {
+	if m.historyLog != nil {
+		return m.historyLog.Lines()
+	}
+	return len(strings.Split(m.historyOutput, "\n"))
+}
+
+// historyOutputPage returns the lines of the output being viewed from
+// offset on, reading them from the run's log when there is one
+func (m Model) historyOutputPage(offset, count int) []string {
+	if m.historyLog != nil {
+		lines, err := m.historyLog.Page(offset, count)
+		if err != nil {
+			return []string{"Error reading log: " + err.Error()}
+		}
+		return lines
+	}
+	lines := strings.Split(m.historyOutput, "\n")
+	end := offset + count
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return lines[offset:end]
+}
+
+// closeHistoryOutput drops the output being viewed, closing its log
+func (m *Model) closeHistoryOutput() {
+	if m.historyLog != nil {
+		_ = m.historyLog.Close()
+		m.historyLog = nil
+	}
+	m.historyOutput = ""
+	m.outputSelection = nil
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HookRun runs the hooks a ball or agent event fired, returning the
+// failures
+type HookRun func() []error
+
+// hookRunMsg carries hooks fired while the TUI is open, to be run off the
+// update loop
+type hookRunMsg struct {
+	run HookRun
+}
+
+// hooksRanMsg reports the hooks of one event that failed
+type hooksRanMsg struct {
+	failures []error
+}
+
+// SetHookRuns sets where hooks fired while the TUI is open arrive. They're
+// run as commands, with failures shown in the message bar and activity log
+// rather than on the terminal under the TUI.
+func (m *Model) SetHookRuns(runs <-chan HookRun) {
+	m.hookRuns = runs
+}
+
+// listenForHookRuns waits for the next hooks to run
+func listenForHookRuns(runs <-chan HookRun) tea.Cmd {
+	return func() tea.Msg {
+		return hookRunMsg{run: <-runs}
+	}
+}
+
+// runHooksCmd runs hooks in the background
+func runHooksCmd(run HookRun) tea.Cmd {
+	return func() tea.Msg {
+		return hooksRanMsg{failures: run()}
+	}
+}
+
+// handleHookRun runs the hooks and waits for the next
+func (m Model) handleHookRun(msg hookRunMsg) (tea.Model, tea.Cmd) {
+	return m, tea.Batch(runHooksCmd(msg.run), listenForHookRuns(m.hookRuns))
+}
+
+// handleHooksRan shows the hooks that failed
+func (m Model) handleHooksRan(msg hooksRanMsg) (tea.Model, tea.Cmd) {
+	for _, err := range msg.failures {
+		m.addActivity("Hook failed: " + err.Error())
+		m.message = "Hook failed: " + err.Error()
+	}
+	return m, nil
+}
@@ -0,0 +1,12 @@
+package tui
+
+import "github.com/ohare93/juggle/internal/session"
+
+// icons are the glyphs used for states, priorities, and agent status.
+// Defaults to the Unicode set; the CLI sets it from global config before starting the TUI.
+var icons = session.UnicodeIcons()
+
+// SetIcons sets the glyphs used for states, priorities, and agent status
+func SetIcons(i session.Icons) {
+	icons = i
+}
@@ -36,7 +36,7 @@ func (m Model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // handleInputSubmit handles submitting the input value
 func (m Model) handleInputSubmit() (tea.Model, tea.Cmd) {
 	value := strings.TrimSpace(m.textInput.Value())
-	if value == "" {
+	if value == "" && m.mode != inputNextActionView && m.mode != inputCheckpointView { // Empty clears these
 		m.message = "Value cannot be empty"
 		return m, nil
 	}
@@ -52,6 +52,10 @@ func (m Model) handleInputSubmit() (tea.Model, tea.Cmd) {
 		return m.submitBlockedInput(value)
 	case inputTagView:
 		return m.submitTagInput(value)
+	case inputNextActionView:
+		return m.submitNextActionInput(value)
+	case inputCheckpointView:
+		return m.submitCheckpointInput(value)
 	}
 
 	m.mode = splitView
@@ -94,7 +98,7 @@ func (m Model) submitSessionInput(value string) (tea.Model, tea.Cmd) {
 
 	m.editingSession = nil // Clear the editing session
 	m.mode = splitView
-	return m, loadSessions(m.sessionStore, m.config, m.localOnly)
+	return m, loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly)
 }
 
 // submitBallInput handles ball title edit submission
@@ -165,6 +169,58 @@ func (m Model) submitBlockedInput(value string) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// submitNextActionInput sets or clears the next action of the ball being edited
+func (m Model) submitNextActionInput(value string) (tea.Model, tea.Cmd) {
+	ball := m.editingBall
+	m.editingBall = nil
+	m.mode = splitView
+	if ball == nil {
+		return m, nil
+	}
+
+	ball.SetNextAction(value)
+	store, err := session.NewStore(ball.WorkingDir)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+
+	if value == "" {
+		m.addActivity("Cleared next action: " + ball.ID)
+		m.message = "Cleared next action: " + ball.ID
+	} else {
+		m.addActivity("Next action for " + ball.ID + ": " + truncate(value, 30))
+		m.message = "Set next action: " + ball.ID
+	}
+	return m, updateBall(store, ball)
+}
+
+// submitCheckpointInput sets or clears the checkpoint of the ball being edited
+func (m Model) submitCheckpointInput(value string) (tea.Model, tea.Cmd) {
+	ball := m.editingBall
+	m.editingBall = nil
+	m.mode = splitView
+	if ball == nil {
+		return m, nil
+	}
+
+	ball.SetCheckpoint(value)
+	store, err := session.NewStore(ball.WorkingDir)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+
+	if value == "" {
+		m.addActivity("Cleared checkpoint: " + ball.ID)
+		m.message = "Cleared checkpoint: " + ball.ID
+	} else {
+		m.addActivity("Checkpoint for " + ball.ID + ": " + truncate(value, 30))
+		m.message = "Set checkpoint: " + ball.ID
+	}
+	return m, updateBall(store, ball)
+}
+
 // handleSessionSelectorKey handles keyboard input in session selector mode
 func (m Model) handleSessionSelectorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// keySequenceTimeoutMsg cancels the pending two-key sequence it was armed
+// for, if the second key hasn't come yet
+type keySequenceTimeoutMsg struct {
+	id int
+}
+
+// keySequences returns the two-key sequence settings (never nil)
+func (m Model) keySequences() *session.KeySequenceConfig {
+	if m.config == nil {
+		return &session.KeySequenceConfig{}
+	}
+	return m.config.GetKeySequences()
+}
+
+// startKeySequence leaves key pending as the first of a two-key sequence
+func (m *Model) startKeySequence(key string) tea.Cmd {
+	m.pendingKeySequence = key
+	m.message = "" // Footer lists the second keys
+	return m.armKeySequenceTimeout()
+}
+
+// armKeySequenceTimeout cancels the pending sequence (or the first g of gg)
+// if no key follows in time, so a stray key doesn't leave the TUI waiting.
+// Each sequence gets its own ID, so an older sequence's timeout can't cut a
+// newer one short.
+func (m *Model) armKeySequenceTimeout() tea.Cmd {
+	m.keySequenceID++
+	id := m.keySequenceID
+	return tea.Tick(m.keySequences().Timeout(), func(time.Time) tea.Msg {
+		return keySequenceTimeoutMsg{id: id}
+	})
+}
+
+// handleKeySequenceTimeout cancels the sequence a timeout was armed for
+func (m Model) handleKeySequenceTimeout(msg keySequenceTimeoutMsg) (tea.Model, tea.Cmd) {
+	if msg.id != m.keySequenceID {
+		return m, nil
+	}
+	m.pendingKeySequence = ""
+	if m.lastKey == "g" {
+		m.lastKey = ""
+	}
+	return m, nil
+}
+
+// showWhichKey reports whether the which-key popup replaces the bottom pane
+func (m Model) showWhichKey() bool {
+	if !m.keySequences().WhichKey {
+		return false
+	}
+	_, ok := sequenceSection(m.pendingKeySequence)
+	return ok
+}
+
+// renderWhichKey renders the pending sequence's second keys and what they
+// do, in as many columns as fit
+func (m Model) renderWhichKey(width, height int) string {
+	section, ok := sequenceSection(m.pendingKeySequence)
+	if !ok {
+		return ""
+	}
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+
+	var b strings.Builder
+	b.WriteString(activePanelTitleStyle.Render(fmt.Sprintf("%s + key: %s", section.leader, section.label)) + "\n")
+
+	cellWidth := 0
+	for _, binding := range section.bindings {
+		if w := displayWidth(binding.key) + 2 + displayWidth(binding.desc); w > cellWidth {
+			cellWidth = w
+		}
+	}
+	cellWidth += 3
+	if cellWidth > width {
+		cellWidth = width
+	}
+	rows := height - 2 // Title and the Esc line
+	if rows < 1 {
+		rows = 1
+	}
+	columns := (len(section.bindings) + rows - 1) / rows
+	if maxColumns := width / cellWidth; columns > maxColumns && maxColumns > 0 {
+		columns = maxColumns
+		rows = (len(section.bindings) + columns - 1) / columns
+	}
+
+	for row := 0; row < rows; row++ {
+		var line strings.Builder
+		for col := 0; col < columns; col++ {
+			i := col*rows + row
+			if i >= len(section.bindings) {
+				break
+			}
+			binding := section.bindings[i]
+			cell := keyStyle.Render(binding.key) + "  " + truncate(binding.desc, cellWidth-displayWidth(binding.key)-5)
+			line.WriteString(cell + strings.Repeat(" ", max(0, cellWidth-lipgloss.Width(cell))))
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	b.WriteString(helpStyle.Render("Esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,329 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// footerPanels is a set of panels a keybinding is hinted in on the footer
+type footerPanels uint8
+
+const (
+	inSessions footerPanels = 1 << SessionsPanel
+	inBalls    footerPanels = 1 << BallsPanel
+	inActivity footerPanels = 1 << ActivityPanel
+	inAll                   = inSessions | inBalls | inActivity
+)
+
+// has returns true if the set includes panel
+func (f footerPanels) has(panel Panel) bool {
+	return f&(1<<panel) != 0
+}
+
+// keyBinding is one entry in the action registry. The help view (?) lists
+// every binding; the footer shows the ones with a hint for the active panel.
+type keyBinding struct {
+	key    string             // Keys as shown in the help view, e.g. "j / ↓"
+	desc   string             // Description for the help view
+	hint   string             // Footer hint as "key:label" (label only for sequence keys); empty = help view only
+	footer footerPanels       // Panels whose footer shows the hint
+	when   func(m Model) bool // Only hint when true (nil = always)
+}
+
+// keySection groups bindings under a help view heading. A section with a
+// leader is a two-key sequence: its bindings are the second keys, and the
+// footer shows them once the leader has been pressed.
+type keySection struct {
+	title      string
+	leader     string       // First key of a two-key sequence (e.g. "s"), empty for plain keys
+	leaderDesc string       // Help view description of the leader key
+	label      string       // Footer label for the leader, e.g. "state"
+	footer     footerPanels // Panels whose footer hints the leader
+	bindings   []keyBinding
+}
+
+// agentOutputShown reports whether the agent output panel is visible
+func agentOutputShown(m Model) bool { return m.agentOutputVisible }
+
+// agentRunning reports whether an agent is running
+func agentRunning(m Model) bool { return m.agentStatus.Running }
+
+// allProjects reports whether balls from all projects are shown
+func allProjects(m Model) bool { return !m.localOnly }
+
+// keySections is the action registry for split view, in help view order
+var keySections = []keySection{
+	{
+		title: "Navigation",
+		bindings: []keyBinding{
+			{key: "Tab / l", desc: "Next panel (Sessions → Balls → Activity)", hint: "Tab:panels", footer: inActivity},
+			{key: "Shift+Tab / h", desc: "Previous panel"},
+			{key: "j / ↓", desc: "Move down / Scroll down"},
+			{key: "k / ↑", desc: "Move up / Scroll up"},
+			{key: "Enter", desc: "Select item / Expand"},
+			{key: "Space", desc: "Go back (in Balls panel)"},
+			{key: "Esc", desc: "Back / Deselect / Close"},
+		},
+	},
+	{
+		title: "Sessions Panel",
+		bindings: []keyBinding{
+			{key: "j/k", desc: "Navigate sessions (auto-selects)", hint: "j/k:nav", footer: inSessions},
+			{key: "Enter", desc: "Select session and go to balls panel", hint: "Enter:select", footer: inSessions},
+			{key: "a", desc: "Add new session", hint: "a:add", footer: inSessions},
+			{key: "e", desc: "Edit session description", hint: "e:edit", footer: inSessions},
+			{key: "d", desc: "Delete session (with confirmation)", hint: "d:del", footer: inSessions},
+			{key: "b", desc: "Briefing: goal, next balls, blockers, last agent run (shown on Enter after a few idle days)"},
+			{key: "w", desc: "Show/hide archived sessions"},
+			{key: "/", desc: "Filter sessions", hint: "/:filter", footer: inSessions},
+			{key: "Ctrl+U", desc: "Clear filter"},
+		},
+	},
+	{
+		title:      "Balls Panel - State Changes (s + key)",
+		leader:     "s",
+		leaderDesc: "Start two-key state change sequence:",
+		label:      "state",
+		footer:     inBalls,
+		bindings: []keyBinding{
+			{key: "c", desc: "Complete ball (→ complete, archives)", hint: "complete"},
+			{key: "s", desc: "Start ball (→ in_progress)", hint: "start"},
+			{key: "b", desc: "Block ball (prompts for reason)", hint: "block"},
+			{key: "p", desc: "Set to pending", hint: "pending"},
+			{key: "a", desc: "Archive completed ball", hint: "archive"},
+			{key: "r", desc: "Reopen completed ball (→ pending, restores session tags)", hint: "reopen"},
+		},
+	},
+	{
+		title:      "Balls Panel - Toggle Filters (t + key)",
+		leader:     "t",
+		leaderDesc: "Start two-key toggle filter sequence:",
+		label:      "filter",
+		footer:     inBalls,
+		bindings: []keyBinding{
+			{key: "c", desc: "Toggle complete balls visibility", hint: "complete"},
+			{key: "b", desc: "Toggle blocked balls visibility", hint: "blocked"},
+			{key: "i", desc: "Toggle in_progress balls visibility", hint: "in_progress"},
+			{key: "p", desc: "Toggle pending balls visibility", hint: "pending"},
+			{key: "m", desc: "Cycle milestone filter (all → each milestone)", hint: "milestone"},
+			{key: "a", desc: "Show all states", hint: "all"},
+		},
+	},
+	{
+		title: "Balls Panel - Other Actions",
+		bindings: []keyBinding{
+			{key: "j/k", desc: "Navigate balls", hint: "j/k:nav", footer: inBalls},
+			{key: "a", desc: "Add new ball (tagged to current session)", hint: "a:add", footer: inBalls},
+			{key: "A", desc: "Add followup ball (depends on selected ball)"},
+			{key: "e", desc: "Edit ball", hint: "e:edit", footer: inBalls},
+			{key: "E", desc: "Edit ball in $EDITOR (YAML format)", hint: "E:editor", footer: inBalls},
+			{key: "d", desc: "Delete ball (with confirmation)", hint: "d:del", footer: inBalls},
+			{key: "u", desc: "Undo last ball change in this project"},
+			{key: "U", desc: "Redo last undone ball change"},
+			{key: "y", desc: "Copy ball ID to clipboard"},
+			{key: "f", desc: "Focus: the next agent run works on the ball first"},
+			{key: "w", desc: "Toggle the parked tab (snoozed, escalated and waiting balls)"},
+			{key: "[ / ]", desc: "Switch session (previous / next)", hint: "[/]:session", footer: inBalls},
+			{key: "o", desc: "Toggle sort order (ID↑ → ID↓ → Priority → Activity)", hint: "o:sort", footer: inBalls},
+			{key: "/", desc: "Filter balls"},
+			{key: "Ctrl+U", desc: "Clear filter"},
+			{key: "Backspace", desc: "Remove ball from current session", hint: "⌫:unsess", footer: inBalls},
+			{key: "S", desc: "Ball sessions: jump to one, or add/remove membership"},
+			{key: "T", desc: "Read transcripts attached to the ball"},
+		},
+	},
+	{
+		title: "Activity Log Panel",
+		bindings: []keyBinding{
+			{key: "j/k", desc: "Scroll one line", hint: "j/k:scroll", footer: inActivity},
+			{key: "Ctrl+D", desc: "Page down (half screen)", hint: "Ctrl+d/u:page", footer: inActivity},
+			{key: "Ctrl+U", desc: "Page up (half screen)"},
+			{key: "gg", desc: "Go to top", hint: "gg:top", footer: inActivity},
+			{key: "G", desc: "Go to bottom", hint: "G:bottom", footer: inActivity},
+		},
+	},
+	{
+		title:      "Balls Panel - View Columns (v + key)",
+		leader:     "v",
+		leaderDesc: "Start two-key view columns sequence:",
+		label:      "columns",
+		footer:     inBalls,
+		bindings: []keyBinding{
+			{key: "p", desc: "Toggle priority column visibility", hint: "priority"},
+			{key: "t", desc: "Toggle tags column visibility", hint: "tags"},
+			{key: "m", desc: "Toggle model size column visibility", hint: "model"},
+			{key: "s", desc: "Toggle tests state column visibility", hint: "tests"},
+			{key: "a", desc: "Toggle all optional columns on/off", hint: "all"},
+			{key: "v", desc: "Cycle layout: split → list → board", hint: "layout"},
+		},
+	},
+	{
+		title:      "Balls Panel - Move to Session (m + digit)",
+		leader:     "m",
+		leaderDesc: "Start two-key move ball sequence:",
+		label:      "move",
+		footer:     inBalls,
+		bindings: []keyBinding{
+			{key: "1-9,0", desc: "Move ball to session 1-9 or 10 (replaces all sessions)", hint: "session"},
+		},
+	},
+	{
+		title:      "Balls Panel - Add to Session (M + digit)",
+		leader:     "M",
+		leaderDesc: "Start two-key append session sequence:",
+		label:      "add to",
+		bindings: []keyBinding{
+			{key: "1-9,0", desc: "Add ball to session 1-9 or 10 (keeps existing sessions)", hint: "session"},
+		},
+	},
+	{
+		title:      "Balls Panel - Next Action (n + key)",
+		leader:     "n",
+		leaderDesc: "Start two-key next action sequence:",
+		label:      "next",
+		footer:     inBalls,
+		bindings: []keyBinding{
+			{key: "a", desc: "Set the ball's next action (empty clears it)", hint: "action"},
+			{key: "c", desc: "Set the ball's checkpoint (empty clears it)", hint: "checkpoint"},
+		},
+	},
+	{
+		title: "View Options",
+		bindings: []keyBinding{
+			{key: "i", desc: "Cycle bottom pane (activity → detail → split → activity)"},
+			{key: "O", desc: "Toggle agent output panel (shows live agent stdout)", hint: "O:output", footer: inSessions | inActivity},
+			{key: "P", desc: "Toggle project scope (local ↔ all projects)", hint: "P:scope", footer: inSessions},
+			{key: "C", desc: "Collapse/expand project groups (all projects)", hint: "C:collapse", footer: inSessions | inBalls, when: allProjects},
+			{key: "F", desc: "Search balls, sessions, progress and archives in the shown projects"},
+			{key: "R", desc: "Refresh / Reload data"},
+			{key: "?", desc: "Toggle this help", hint: "?:help", footer: inAll},
+		},
+	},
+	{
+		title: "Agent Control",
+		bindings: []keyBinding{
+			{key: "X", desc: "Cancel running agent (with confirmation)", hint: "X:cancel", footer: inAll, when: agentRunning},
+			{key: "E", desc: "Expand/shrink agent output panel", hint: "E:expand", footer: inAll, when: agentOutputShown},
+			{key: "z", desc: "Fold/unfold iteration at top of agent output", hint: "z/Z:fold", footer: inAll, when: agentOutputShown},
+			{key: "Z", desc: "Fold/unfold all agent output iterations"},
+			{key: "V", desc: "Select agent output lines (j/k), then a/Enter to create a ball from them"},
+			{key: "L", desc: "Launch an agent on the selected session (review iterations, timeout, model first)"},
+			{key: "!", desc: "Signal the selected session's running agent (complete/blocked/continue)"},
+			{key: "p", desc: "Pause the selected session's running agent after its current iteration (p again resumes)"},
+			{key: "H", desc: "View agent run history", hint: "H:history", footer: inSessions | inActivity},
+		},
+	},
+	{
+		title: "Bottom Pane Modes",
+		bindings: []keyBinding{
+			{key: "[Act]", desc: "Activity log - shows recent actions"},
+			{key: "[Detail]", desc: "Ball details - shows full ball info with ACs"},
+			{key: "[Split]", desc: "Split view - shows both details and activity"},
+		},
+	},
+	{
+		title: "Input Dialogs",
+		bindings: []keyBinding{
+			{key: "Enter", desc: "Submit / Confirm"},
+			{key: "Esc", desc: "Cancel"},
+		},
+	},
+	{
+		title: "Delete Confirmation",
+		bindings: []keyBinding{
+			{key: "y", desc: "Confirm delete"},
+			{key: "n / Esc", desc: "Cancel delete"},
+		},
+	},
+	{
+		title: "Quit",
+		bindings: []keyBinding{
+			{key: "q / Ctrl+C", desc: "Quit application", hint: "q:quit", footer: inSessions | inActivity},
+		},
+	},
+}
+
+// sequenceSection returns the section whose leader is key, if any
+func sequenceSection(key string) (keySection, bool) {
+	for _, section := range keySections {
+		if section.leader != "" && section.leader == key {
+			return section, true
+		}
+	}
+	return keySection{}, false
+}
+
+// helpLines renders the registry as help view lines
+func helpLines() []string {
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")).Width(15)
+
+	var lines []string
+	for _, section := range keySections {
+		lines = append(lines, titleStyle.Render(section.title))
+		if section.leader != "" {
+			lines = append(lines, "  "+keyStyle.Render(section.leader)+"  "+section.leaderDesc)
+		}
+		for _, binding := range section.bindings {
+			key, desc := binding.key, binding.desc
+			if section.leader != "" {
+				key, desc = "  "+section.leader+key, "  "+desc
+			}
+			lines = append(lines, "  "+keyStyle.Render(key)+"  "+desc)
+		}
+		lines = append(lines, "") // Empty line between sections
+	}
+	return lines
+}
+
+// footerHints returns the hints for the active panel. Hints only shown in
+// this panel come first, then ones shared with other panels.
+func (m Model) footerHints() []string {
+	var own, shared []string
+	add := func(hint string, panels footerPanels) {
+		if panels == 1<<m.activePanel {
+			own = append(own, hint)
+		} else {
+			shared = append(shared, hint)
+		}
+	}
+
+	for _, section := range keySections {
+		if section.leader != "" {
+			if !section.footer.has(m.activePanel) {
+				continue
+			}
+			keys := make([]string, 0, len(section.bindings))
+			for _, binding := range section.bindings {
+				keys = append(keys, binding.key)
+			}
+			add(section.leader+"+"+strings.Join(keys, "/")+":"+section.label, section.footer)
+			continue
+		}
+
+		for _, binding := range section.bindings {
+			if binding.hint == "" || !binding.footer.has(m.activePanel) {
+				continue
+			}
+			if binding.when != nil && !binding.when(m) {
+				continue
+			}
+			add(binding.hint, binding.footer)
+		}
+	}
+	return append(own, shared...)
+}
+
+// sequenceHints returns the footer shown while a two-key sequence is
+// pending: each second key with what it does
+func (m Model) sequenceHints() []string {
+	section, ok := sequenceSection(m.pendingKeySequence)
+	if !ok {
+		return nil
+	}
+	hints := []string{section.leader + "+ " + section.label + ":"}
+	for _, binding := range section.bindings {
+		hints = append(hints, binding.key+":"+binding.hint)
+	}
+	return append(hints, "Esc:cancel")
+}
@@ -19,19 +19,12 @@ func renderBallList(balls []*session.Ball, cursor int, width int) string {
 
 	for i, ball := range balls {
 		// Format ball info
-		title := truncate(ball.Title, 40)
-		stateStr := formatState(ball)
-		tagsStr := strings.Join(ball.Tags, ", ")
-		if len(tagsStr) > 20 {
-			tagsStr = truncate(tagsStr, 20)
-		}
-
-		line := fmt.Sprintf("%-15s %-40s %-20s %-10s %s",
-			truncateID(ball.ID, 15),
-			title,
-			stateStr,
+		line := fmt.Sprintf("%s %s %s %-10s %s",
+			fitWidth(truncateID(ball.ID, 15), 15),
+			fitWidth(ball.Title, 40),
+			fitWidth(formatState(ball), 20),
 			ball.Priority,
-			tagsStr,
+			truncate(strings.Join(ball.Tags, ", "), 20),
 		)
 
 		// Color code by state and priority
@@ -50,11 +43,20 @@ func renderBallList(balls []*session.Ball, cursor int, width int) string {
 	return output.String()
 }
 
+// stateBadge is the ball's state, with its checkpoint as a sub-badge while
+// it's in progress
+func stateBadge(ball *session.Ball) string {
+	if ball.State == session.StateInProgress && ball.Checkpoint != "" {
+		return string(ball.State) + " [" + ball.Checkpoint + "]"
+	}
+	return string(ball.State)
+}
+
 func formatState(ball *session.Ball) string {
-	stateStr := string(ball.State)
+	stateStr := stateBadge(ball)
 	// Add output marker if ball has output
 	if ball.HasOutput() {
-		stateStr += " [📋]"
+		stateStr += " [" + icons.Output + "]"
 	}
 	return stateStr
 }
@@ -81,17 +83,37 @@ func styleBallByState(ball *session.Ball, line string) string {
 	return lipgloss.NewStyle().Foreground(color).Render(line)
 }
 
-func truncate(s string, maxLen int) string {
-	if maxLen <= 0 {
-		return ""
+// testsLabel returns the short label for a tests result (pass, fail, or ?)
+func testsLabel(result session.TestsResult) string {
+	if result == session.TestsUnknown {
+		return "?"
 	}
-	if len(s) <= maxLen {
-		return s
+	return string(result)
+}
+
+// formatTestsState describes a ball's last recorded test run,
+// e.g. "fail (go test ./..., 2006-01-02 15:04)" or "unknown"
+func formatTestsState(ball *session.Ball) string {
+	if ball.TestsState == nil || ball.TestsState.Result == "" {
+		return string(session.TestsUnknown)
+	}
+	detail := ball.TestsState.RecordedAt.Format("2006-01-02 15:04")
+	if ball.TestsState.Command != "" {
+		detail = ball.TestsState.Command + ", " + detail
 	}
-	if maxLen <= 3 {
-		return s[:maxLen]
+	return fmt.Sprintf("%s (%s)", ball.TestsState.Result, detail)
+}
+
+// styleTestsResult colors text by tests result: green for pass, red for fail
+func styleTestsResult(result session.TestsResult, text string) string {
+	switch result {
+	case session.TestsPass:
+		return lipgloss.NewStyle().Foreground(completeColor).Render(text)
+	case session.TestsFail:
+		return lipgloss.NewStyle().Foreground(droppedColor).Render(text)
+	default:
+		return text
 	}
-	return s[:maxLen-3] + "..."
 }
 
 func truncateID(id string, maxLen int) string {
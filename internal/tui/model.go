@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"io"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agentlog"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/watcher"
 )
@@ -22,6 +26,8 @@ const (
 	inputBallView              // Add/edit ball (for title field)
 	inputBlockedView           // Prompt for blocked reason
 	inputTagView               // Add/remove tags
+	inputNextActionView        // Set the next action of a ball
+	inputCheckpointView        // Set the checkpoint of a ball
 	sessionSelectorView        // Session selector for tagging balls
 	dependencySelectorView     // Dependency selector for ball creation/editing
 	confirmSplitDelete         // Delete confirmation in split view
@@ -29,6 +35,15 @@ const (
 	confirmAgentCancel         // Agent cancel confirmation
 	unifiedBallFormView        // Unified ball creation form - all fields in one view
 	historyOutputView          // Viewing last_output.txt from history
+	confirmDuplicateMerge      // Merge prompt for duplicate balls created by the agent
+	ballSessionsView           // Session membership for the highlighted ball
+	transcriptView             // Reading a transcript attached to the highlighted ball
+	projectGroupsView          // Collapse/expand project groups in all-projects mode
+	globalSearchView           // Full-text search across balls, sessions and archives
+	agentSignalView            // Send COMPLETE/BLOCKED/CONTINUE to a session's running agent
+	briefingView               // Briefing for picking a session back up
+	agentLaunchView            // Agent settings form shown before launching an agent
+	confirmAgentResume         // Offer to resume agent runs that were interrupted
 )
 
 // InputAction represents what action triggered the input mode
@@ -77,14 +92,22 @@ const (
 	SortByLastActivityASC                  // Sort by last activity ascending (oldest activity first)
 	SortByCreatedAtDESC                    // Sort by creation time descending (newest first)
 	SortByCreatedAtASC                     // Sort by creation time ascending (oldest first)
+	SortByCustom                           // Sort by a custom sort expression (see Model.customSort)
 )
 
 // Special pseudo-session IDs
 const (
-	PseudoSessionAll      = "__all__"
-	PseudoSessionUntagged = "__untagged__"
+	PseudoSessionAll       = "__all__"
+	PseudoSessionUntagged  = "__untagged__"
+	PseudoSessionDashboard = "__dashboard__" // Per-project summaries, in all-projects mode only
 )
 
+// isPseudoSession reports whether id is one of the pseudo-sessions rather
+// than a real session
+func isPseudoSession(id string) bool {
+	return id == PseudoSessionAll || id == PseudoSessionUntagged || id == PseudoSessionDashboard
+}
+
 // ActivityEntry represents a log entry in the activity log
 type ActivityEntry struct {
 	Time    time.Time
@@ -93,23 +116,44 @@ type ActivityEntry struct {
 
 // AgentOutputEntry represents a line of agent output
 type AgentOutputEntry struct {
-	Time    time.Time
-	Line    string
-	IsError bool // true if this is stderr output
+	Time      time.Time
+	Line      string
+	IsError   bool // true if this is stderr output
+	Section   int  // Iteration section this line belongs to (0 = before the first iteration header)
+	IsHeader  bool // true if this line is an "Iteration N/M" header
+}
+
+// agentOutputLine is a line shown in the agent output panel after folding is applied
+type agentOutputLine struct {
+	entry  AgentOutputEntry
+	folded bool // Header of a collapsed iteration
+	hidden int  // Number of lines hidden under a collapsed header
 }
 
+// iterationHeaderPattern matches the iteration header printed by the agent loop
+var iterationHeaderPattern = regexp.MustCompile(`^═+ Iteration (\d+)/(\d+) ═+$`)
+
 type Model struct {
 	store         *session.Store
 	sessionStore  *session.SessionStore
 	config        *session.Config
 	localOnly     bool // restrict to local project only
+	projectCache  *session.ProjectCache // Avoids re-reading unchanged projects in all-projects mode
 	balls         []*session.Ball
 	filteredBalls []*session.Ball
+	archivedBalls []*session.Ball // For resolving dependencies on archived balls
+	ballsGen      int             // Bumped when balls or sessions are reloaded or refiltered
+	ballCache     *ballView       // Balls panel's filtered and sorted balls (nil = not cached)
 
 	// Session state (for split view)
 	sessions        []*session.JuggleSession
 	selectedSession *session.JuggleSession
 	sessionCursor   int
+	dashboard       []*session.ProjectSummary // Per-project summaries for the dashboard, see loadDashboard
+
+	// Project groups in all-projects mode
+	collapsedProjects  map[string]bool // Project dirs whose balls and sessions are hidden
+	projectSelectIndex int             // Cursor in the project list
 
 	// View state
 	mode   viewMode
@@ -120,12 +164,14 @@ type Model struct {
 
 	// Panel state (for split view)
 	activePanel Panel
+	layout      ViewLayout // Split, list, or board arrangement of the panels
 
 	// Activity log
 	activityLog        []ActivityEntry
 	activityLogOffset  int    // Scroll offset for activity log
 	lastKey            string // Last key pressed (for gg detection)
 	pendingKeySequence string // Pending key for two-key sequences (s, t, etc.)
+	keySequenceID      int    // Identifies the latest sequence's timeout (see armKeySequenceTimeout)
 	helpScrollOffset   int    // Scroll offset for help view
 	ballsScrollOffset  int    // Scroll offset for balls panel viewport
 	detailScrollOffset int    // Scroll offset for ball detail panel
@@ -134,17 +180,24 @@ type Model struct {
 	bottomPaneMode BottomPaneMode
 
 	// Sort order for balls
-	sortOrder SortOrder
+	sortOrder  SortOrder
+	customSort *CustomSort // Expression used when sortOrder is SortByCustom
 
 	// Column visibility for balls panel
 	showPriorityColumn  bool // Show priority column in balls list
 	showTagsColumn      bool // Show tags column in balls list
 	showModelSizeColumn bool // Show model size column in balls list
+	showTestsColumn     bool // Show tests state column in balls list
 
 	// Filter state
 	filterStates         map[string]bool // State visibility toggles
 	filterPriority       string
-	searchQuery          string
+	filterMilestone      string          // Only show balls in this milestone ("" = all)
+	showParked           bool            // Show the parked tab (snoozed, escalated, waiting) instead of actionable balls
+	showArchivedSessions bool            // Show archived sessions in the sessions panel
+	searchQuery          string                  // Last query run in the global search view
+	searchResults        []*session.SearchResult // Results of searchQuery
+	searchCursor         int                     // Highlighted search result
 	initialSessionID     string // Pre-select session by ID (from --session flag)
 	panelSearchQuery     string // Current search/filter query within a panel
 	panelSearchActive    bool   // Whether search/filter is active
@@ -183,6 +236,7 @@ type Model struct {
 	pendingBallDependsOn       []string // Selected dependency ball IDs
 	pendingBallBlockingReason  int      // Index in blocking reason options (0=blank, 1=Human needed, 2=Waiting for dependency, 3=Needs research, 4=custom)
 	pendingBallCustomReason    string   // Custom blocking reason text (when pendingBallBlockingReason == 4)
+	pendingNewSession          string   // "<id>[: description]" typed into the session field's "create new…" option
 	pendingBallFormField       int      // Current field in form (0=context, 1=title, 2+=ACs, then tags, session, model_size, priority, blocking_reason, depends_on, save)
 	pendingAcceptanceCriteria  []string // Acceptance criteria being collected
 	pendingACEditIndex         int      // Index of AC being edited (-1 = adding new, >= 0 = editing existing)
@@ -194,6 +248,7 @@ type Model struct {
 	acTemplateCursor      int      // Current cursor position in templates list (-1 = not on templates)
 	repoLevelACs          []string // Repo-level ACs shown as reminders (not stored on ball)
 	sessionLevelACs       []string // Session-level ACs shown as reminders (not stored on ball)
+	titleRules            *session.TitleRules // Project title conventions, warned about in the form
 
 	// File autocomplete state for ball form
 	fileAutocomplete *AutocompleteState // File path autocomplete suggestions
@@ -205,6 +260,7 @@ type Model struct {
 
 	// File watcher
 	fileWatcher *watcher.Watcher
+	codeChanged map[string]string // Ball ID -> last changed file matching its watch globs
 
 	// Agent state
 	agentStatus AgentStatus // Status of running agent
@@ -215,20 +271,70 @@ type Model struct {
 	agentOutput         []AgentOutputEntry // Buffer of agent output lines
 	agentOutputOffset   int                // Scroll offset for agent output panel
 	agentOutputCh       chan agentOutputMsg // Channel for receiving agent output
+	agentOutputSection  int                // Number of iteration headers seen in the output
+	agentOutputFolds    map[int]bool       // Iteration sections whose output is collapsed
+	agentOutputMirror   io.Writer          // Panel lines are also written here as they arrive (tui --output-log)
+	outputSelection     *outputSelection   // Lines selected (V) in the agent output panel or history output view
+
+	// API health of the configured provider, shown in the status bar
+	apiHealth       *session.APIHealthSummary
+	apiHealthLoader func() (session.APIHealthSummary, error)
+
+	// Hooks fired while the TUI is open, run as commands (see SetHookRuns)
+	hookRuns <-chan HookRun
 
 	// Agent process tracking for cancellation
 	agentProcess *AgentProcess // Reference to running agent process for cancellation
 
+	// Human signal to a session's running agent loop
+	signalSession       *session.JuggleSession // Session the signal prompt is for
+	signalReasonEditing bool                   // Typing the reason for a BLOCKED signal
+
+	// Agent settings form shown before launching an agent from the TUI
+	launchForm *agentLaunchForm
+
+	// Checkpoints of interrupted agent runs awaiting a resume decision
+	staleCheckpoints []*session.AgentCheckpoint
+
+	// Briefing shown for a session (b, or selecting an idle session)
+	briefing *session.Briefing
+
+	// Duplicate detection for balls created by the agent
+	agentKnownBallIDs   map[string]bool              // Ball IDs that existed when the agent started (nil = no run to check)
+	agentReportedBalls  []string                     // Ball IDs the agent reported creating
+	checkDuplicates     bool                         // Check for duplicates on the next balls reload
+	pendingDuplicates   []session.DuplicateCandidate // Duplicates awaiting a merge/skip decision
+
+	// Ball changes made on disk by the agent, highlighted in the detail pane
+	agentEdits        map[string]*agentEdit // Ball ID -> fields the last agent run changed
+	agentEditsPending bool                  // Record edits on the next reload (agent just stopped)
+
+	// Attention signals (bell, tmux, title) while the terminal is unfocused
+	blurred          bool      // The terminal reported losing focus
+	attentionPending bool      // A signal was sent and should be cleared on focus
+	attentionOut     io.Writer // Where bells are written (nil = stderr)
+
 	// Exit action - signals to caller what to do after TUI exits
 	runAgentForBall string // Ball ID to run agent for after TUI exits (empty = no action)
 
 	// Agent history state
-	agentHistory        []*session.AgentRunRecord // Loaded agent run history
+	agentHistory        []*session.AgentRunRecord // Runs shown in the history view (filtered and sorted)
+	historyAll          []*session.AgentRunRecord // Every loaded run
+	historySession      string                    // Only show runs on this session ("" = all)
+	historyResult       string                    // Only show runs with this result ("" = all)
+	historyRange        historyRange              // Only show runs started within this range
+	historySort         historySort               // Order of the history view
 	historyCursor       int                       // Current selection in history view
 	historyScrollOffset int                       // Scroll offset for history view
 	historyOutput       string                    // Content of selected history's output file
+	historyLog          *agentlog.Reader          // Selected run's streamed log, paged from disk (nil = use historyOutput)
 	historyOutputOffset int                       // Scroll offset for output view
 
+	// Attached transcript state (editingBall holds the ball being read)
+	transcriptIndex   int    // Which of the ball's transcripts is shown
+	transcriptContent string // Text of the shown transcript
+	transcriptOffset  int    // Scroll offset for transcript view
+
 	// Time provider for testability
 	nowFunc func() time.Time // Can be overridden in tests
 }
@@ -260,6 +366,8 @@ func InitialSplitModelWithWatcher(store *session.Store, sessionStore *session.Se
 		sessionStore:     sessionStore,
 		config:           config,
 		localOnly:        localOnly,
+		projectCache:     session.NewProjectCache(),
+		ballCache:        &ballView{},
 		mode:             splitView,
 		activePanel:      BallsPanel,
 		initialSessionID: initialSessionID,
@@ -273,6 +381,7 @@ func InitialSplitModelWithWatcher(store *session.Store, sessionStore *session.Se
 		showPriorityColumn:  false,
 		showTagsColumn:      false,
 		showModelSizeColumn: false,
+		showTestsColumn:     false,
 		cursor:              0,
 		selectedBalls:       make(map[string]bool),
 		sessionCursor:       0,
@@ -286,13 +395,28 @@ func InitialSplitModelWithWatcher(store *session.Store, sessionStore *session.Se
 
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
-		loadBalls(m.store, m.config, m.localOnly),
-		loadSessions(m.sessionStore, m.config, m.localOnly),
+		loadBalls(m.store, m.projectCache, m.config, m.localOnly),
+		loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly),
+	}
+	// Create instances of recurring balls that came due while juggle was
+	// closed, then collect commits referencing balls (both rewrite balls)
+	if m.store != nil {
+		cmds = append(cmds, tea.Sequence(
+			materializeRecurrences(m.store, m.config, m.localOnly),
+			syncGitCommits(m.store, m.config, m.localOnly),
+		))
+	}
+	// Offer to resume agent runs that were killed while juggle was closed
+	if m.sessionStore != nil {
+		cmds = append(cmds, loadStaleCheckpoints(m.sessionStore))
 	}
 	// Start file watcher if available
 	if m.fileWatcher != nil {
 		cmds = append(cmds, listenForWatcherEvents(m.fileWatcher))
 	}
+	if m.hookRuns != nil {
+		cmds = append(cmds, listenForHookRuns(m.hookRuns))
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -337,12 +461,28 @@ func (m Model) RunAgentForBall() string {
 	return m.runAgentForBall
 }
 
-// addAgentOutput adds a line to the agent output buffer
+// addAgentOutput adds a line to the agent output buffer.
+// Iteration headers start a new section; the previous iteration is collapsed
+// automatically so long runs stay navigable.
 func (m *Model) addAgentOutput(line string, isError bool) {
 	entry := AgentOutputEntry{
-		Time:    time.Now(),
-		Line:    line,
+		Time:      time.Now(),
+		Line:      line,
 		IsError: isError,
+		Section: m.agentOutputSection,
+	}
+	if iter, maxIter, ok := parseIterationHeader(line); ok {
+		if m.agentOutputFolds == nil {
+			m.agentOutputFolds = make(map[int]bool)
+		}
+		if m.agentOutputSection > 0 {
+			m.agentOutputFolds[m.agentOutputSection] = true
+		}
+		m.agentOutputSection++
+		entry.Section = m.agentOutputSection
+		entry.IsHeader = true
+		m.agentStatus.Iteration = iter
+		m.agentStatus.MaxIterations = maxIter
 	}
 	// Keep last 500 lines
 	if len(m.agentOutput) >= 500 {
@@ -351,23 +491,87 @@ func (m *Model) addAgentOutput(line string, isError bool) {
 		if m.agentOutputOffset > 0 {
 			m.agentOutputOffset--
 		}
+		if sel := m.outputSelection; sel != nil && m.mode != historyOutputView {
+			sel.anchor = max(sel.anchor-1, 0)
+			sel.cursor = max(sel.cursor-1, 0)
+		}
 	}
 	m.agentOutput = append(m.agentOutput, entry)
+	m.mirrorAgentOutput(entry)
 
 	// Auto-scroll to bottom when new output arrives
 	m.agentOutputOffset = m.getAgentOutputMaxOffset()
 }
 
+// SetAgentOutputMirror writes every line added to the agent output panel,
+// status markers included, to w as it arrives, so a TUI-launched run can be
+// followed with tail -f or collected by other tools
+func (m *Model) SetAgentOutputMirror(w io.Writer) {
+	m.agentOutputMirror = w
+}
+
+// mirrorAgentOutput writes entry to the output mirror, formatted as the
+// panel shows it. Mirroring stops at the first write error.
+func (m *Model) mirrorAgentOutput(entry AgentOutputEntry) {
+	if m.agentOutputMirror == nil {
+		return
+	}
+	line := entry.Time.Format("15:04:05") + " " + entry.Line + "\n"
+	if _, err := io.WriteString(m.agentOutputMirror, line); err != nil {
+		m.agentOutputMirror = nil
+		m.addActivity("Stopped mirroring agent output: " + err.Error())
+	}
+}
+
 // clearAgentOutput clears the agent output buffer
 func (m *Model) clearAgentOutput() {
 	m.agentOutput = make([]AgentOutputEntry, 0)
 	m.agentOutputOffset = 0
+	m.agentOutputSection = 0
+	m.agentOutputFolds = make(map[int]bool)
+}
+
+// parseIterationHeader extracts the iteration numbers from an "Iteration N/M" header line
+func parseIterationHeader(line string) (int, int, bool) {
+	match := iterationHeaderPattern.FindStringSubmatch(line)
+	if match == nil {
+		return 0, 0, false
+	}
+	iter, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	maxIter, err := strconv.Atoi(match[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return iter, maxIter, true
+}
+
+// visibleAgentOutputLines returns the agent output with collapsed iterations folded
+// down to their header line
+func (m Model) visibleAgentOutputLines() []agentOutputLine {
+	lines := make([]agentOutputLine, 0, len(m.agentOutput))
+	for i := 0; i < len(m.agentOutput); i++ {
+		entry := m.agentOutput[i]
+		if entry.IsHeader && m.agentOutputFolds[entry.Section] {
+			hidden := 0
+			for i+1 < len(m.agentOutput) && !m.agentOutput[i+1].IsHeader && m.agentOutput[i+1].Section == entry.Section {
+				hidden++
+				i++
+			}
+			lines = append(lines, agentOutputLine{entry: entry, folded: true, hidden: hidden})
+			continue
+		}
+		lines = append(lines, agentOutputLine{entry: entry})
+	}
+	return lines
 }
 
 // getAgentOutputMaxOffset returns the maximum scroll offset for the agent output panel
 func (m Model) getAgentOutputMaxOffset() int {
 	visibleLines := m.getAgentOutputVisibleLines()
-	maxOffset := len(m.agentOutput) - visibleLines
+	maxOffset := len(m.visibleAgentOutputLines()) - visibleLines
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -415,6 +619,9 @@ func (m *Model) getBallsForSession() []*session.Ball {
 			}
 		}
 		return untaggedBalls
+	case PseudoSessionDashboard:
+		// The dashboard shows project summaries instead of balls
+		return []*session.Ball{}
 	default:
 		// Regular session - return balls with matching tag
 		sessionBalls := make([]*session.Ball, 0)
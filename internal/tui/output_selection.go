@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// outputSelection is a visual line selection in the agent output panel or
+// the history output viewer, turned into a new ball's context
+type outputSelection struct {
+	anchor int // Line the selection started on
+	cursor int // Line the selection extends to, moved with j/k
+}
+
+// bounds returns the selected lines as a half-open range
+func (s *outputSelection) bounds() (start, end int) {
+	if s.anchor <= s.cursor {
+		return s.anchor, s.cursor + 1
+	}
+	return s.cursor, s.anchor + 1
+}
+
+// contains reports whether line i is selected
+func (s *outputSelection) contains(i int) bool {
+	start, end := s.bounds()
+	return i >= start && i < end
+}
+
+// scrollStart returns the first line to show so the cursor stays among
+// the visible lines
+func (s *outputSelection) scrollStart(offset, visible int) int {
+	if visible < 1 {
+		visible = 1
+	}
+	if s.cursor < offset {
+		return s.cursor
+	}
+	if s.cursor >= offset+visible {
+		return s.cursor - visible + 1
+	}
+	return offset
+}
+
+var outputSelectionStyle = lipgloss.NewStyle().Reverse(true)
+
+// handleStartOutputSelection starts selecting lines at the top of the
+// agent output panel or history output viewer
+func (m Model) handleStartOutputSelection() (tea.Model, tea.Cmd) {
+	total, offset := m.outputSelectionLines()
+	if total == 0 {
+		m.message = "No output to select"
+		return m, nil
+	}
+	if offset >= total {
+		offset = total - 1
+	}
+	m.outputSelection = &outputSelection{anchor: offset, cursor: offset}
+	m.message = "Select lines with j/k, a/Enter to create a ball from them, Esc to cancel"
+	return m, nil
+}
+
+// outputSelectionLines returns the number of lines that can be selected in
+// the output being viewed, and its scroll offset
+func (m Model) outputSelectionLines() (total, offset int) {
+	if m.mode == historyOutputView {
+		return m.historyOutputLines(), m.historyOutputOffset
+	}
+	return len(m.visibleAgentOutputLines()), m.agentOutputOffset
+}
+
+// handleOutputSelectionKey handles keys while lines are being selected
+func (m Model) handleOutputSelectionKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	sel := m.outputSelection
+	total, _ := m.outputSelectionLines()
+	move := func(delta int) {
+		sel.cursor += delta
+		if sel.cursor >= total {
+			sel.cursor = total - 1
+		}
+		if sel.cursor < 0 {
+			sel.cursor = 0
+		}
+		if m.mode == historyOutputView {
+			m.historyOutputOffset = sel.scrollStart(m.historyOutputOffset, m.historyOutputVisibleLines())
+		} else {
+			m.agentOutputOffset = sel.scrollStart(m.agentOutputOffset, m.getAgentOutputVisibleLines())
+		}
+	}
+
+	switch msg.String() {
+	case "esc", "V", "q":
+		m.outputSelection = nil
+		m.message = "Selection cancelled"
+	case "down", "j":
+		move(1)
+	case "up", "k":
+		move(-1)
+	case "ctrl+d":
+		move(15)
+	case "ctrl+u":
+		move(-15)
+	case "G":
+		move(total)
+	case "a", "enter":
+		return m.handleCreateBallFromSelection()
+	}
+	return m, nil
+}
+
+// selectedOutputText returns the text of the selected lines. A collapsed
+// iteration in the agent output panel contributes all of its lines.
+func (m Model) selectedOutputText() string {
+	start, end := m.outputSelection.bounds()
+	if m.mode == historyOutputView {
+		return strings.Join(m.historyOutputPage(start, end-start), "\n")
+	}
+
+	lines := m.visibleAgentOutputLines()
+	if end > len(lines) {
+		end = len(lines)
+	}
+	var text []string
+	for _, line := range lines[start:end] {
+		text = append(text, line.entry.Line)
+		if !line.folded {
+			continue
+		}
+		for _, entry := range m.agentOutput {
+			if entry.Section == line.entry.Section && !entry.IsHeader {
+				text = append(text, entry.Line)
+			}
+		}
+	}
+	return strings.Join(text, "\n")
+}
+
+// handleCreateBallFromSelection opens the new ball form with the selected
+// lines as its context, in the session whose output they came from
+func (m Model) handleCreateBallFromSelection() (tea.Model, tea.Cmd) {
+	text := strings.TrimRight(m.selectedOutputText(), "\n")
+	start, end := m.outputSelection.bounds()
+	m.outputSelection = nil
+	if strings.TrimSpace(text) == "" {
+		m.message = "Selected lines are empty"
+		return m, nil
+	}
+
+	source, sessionID := "agent output", m.agentStatus.SessionID
+	if m.mode == historyOutputView {
+		source, sessionID = "agent run output", ""
+		if m.historyCursor < len(m.agentHistory) {
+			record := m.agentHistory[m.historyCursor]
+			source = "agent run " + record.ID
+			sessionID = record.SessionID
+		}
+		m.closeHistoryOutput()
+	}
+	if sessionID == "" && m.selectedSession != nil {
+		sessionID = m.selectedSession.ID
+	}
+	context := fmt.Sprintf("From %s (lines %d-%d):\n```\n%s\n```", source, start+1, end, text)
+
+	m.clearPendingBallState()
+	m.pendingAcceptanceCriteria = []string{}
+	if m.store != nil {
+		m.fileAutocomplete = NewAutocompleteState(m.store.ProjectDir())
+	}
+	m.loadACTemplatesAndRepoACs()
+	m.selectFormSession(sessionID)
+	m.pendingBallContext = context
+	m.pendingBallFormField = 0 // Start at context field
+	m.textInput.Reset()
+	m.textInput.Blur()
+	m.textInput.Placeholder = "Background context for this task"
+	m.contextInput.SetValue(context)
+	m.contextInput.Focus()
+	m.mode = unifiedBallFormView
+	m.addActivity(fmt.Sprintf("Creating ball from %d selected output lines...", end-start))
+	return m, nil
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// splitParked separates parked balls (snoozed, escalated, or waiting on
+// dependencies) from the actionable ones, keeping the order of balls
+func (m *Model) splitParked(balls []*session.Ball) (actionable, parked []*session.Ball) {
+	deps := session.NewDependencyIndex(m.balls, nil)
+	now := m.now()
+	actionable = make([]*session.Ball, 0, len(balls))
+	parked = make([]*session.Ball, 0)
+	for _, ball := range balls {
+		if ball.ParkedReason(now, deps) != "" {
+			parked = append(parked, ball)
+		} else {
+			actionable = append(actionable, ball)
+		}
+	}
+	return actionable, parked
+}
+
+// parkedCount returns how many balls in the current session are parked
+func (m *Model) parkedCount() int {
+	if cache := m.currentBallView(); cache != nil {
+		m.searchBallsForSession()
+		return cache.parked
+	}
+	_, parked := m.splitParked(m.getBallsForSession())
+	return len(parked)
+}
+
+// parkedBadge returns the balls panel title suffix for the parked tab: the
+// parked count on the main list, or a reminder of how to get back
+func (m *Model) parkedBadge() string {
+	if m.showParked {
+		return " [parked, w: back]"
+	}
+	if count := m.parkedCount(); count > 0 {
+		return fmt.Sprintf(" [%d parked]", count)
+	}
+	return ""
+}
+
+// handleToggleParked switches the balls panel between actionable balls and
+// the parked tab
+func (m Model) handleToggleParked() (tea.Model, tea.Cmd) {
+	m.showParked = !m.showParked
+	m.cursor = 0
+	m.ballsScrollOffset = 0
+	m.selectedBalls = make(map[string]bool)
+	if m.showParked {
+		m.message = fmt.Sprintf("Parked balls: %d (w to go back)", len(m.filterBallsForSession()))
+	} else {
+		m.message = "Actionable balls"
+	}
+	return m, nil
+}
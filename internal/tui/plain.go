@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// PlainBoardOptions controls what the plain-text board shows
+type PlainBoardOptions struct {
+	SessionID    string      // Only show this session (empty = every session)
+	ShowComplete bool        // Include complete balls (hidden by default, like the TUI)
+	SortOrder    SortOrder   // Sort order applied to each session's balls
+	CustomSort   *CustomSort // Expression used when SortOrder is SortByCustom
+}
+
+// sortOrderNames maps the --sort flag values to sort orders
+var sortOrderNames = map[string]SortOrder{
+	"id":           SortByIDASC,
+	"id-desc":      SortByIDDESC,
+	"priority":     SortByPriorityDESC,
+	"priority-asc": SortByPriorityASC,
+	"activity":     SortByLastActivityDESC,
+	"activity-asc": SortByLastActivityASC,
+	"created":      SortByCreatedAtDESC,
+	"created-asc":  SortByCreatedAtASC,
+}
+
+// ParseSortOrder converts a sort name (e.g. "priority", "id-desc") to a SortOrder
+func ParseSortOrder(name string) (SortOrder, error) {
+	if name == "" {
+		return SortByIDASC, nil
+	}
+	order, ok := sortOrderNames[strings.ToLower(name)]
+	if !ok {
+		return SortByIDASC, fmt.Errorf("invalid sort order %q (valid: id, id-desc, priority, priority-asc, activity, activity-asc, created, created-asc)", name)
+	}
+	return order, nil
+}
+
+// CustomSort is a sort expression picked by name from config, or given
+// inline (in which case the name is the expression itself)
+type CustomSort struct {
+	Name string
+	Expr session.SortExpr
+}
+
+// customSortOrders returns the valid custom sort orders from config
+func customSortOrders(config *session.Config) []*CustomSort {
+	if config == nil {
+		return nil
+	}
+	var orders []*CustomSort
+	for _, order := range config.SortOrders {
+		expr, err := session.ParseSortExpr(order.Expr)
+		if err != nil {
+			continue
+		}
+		orders = append(orders, &CustomSort{Name: order.Name, Expr: expr})
+	}
+	return orders
+}
+
+// ResolveSort converts a --sort value to a sort order. Built-in names are
+// tried first, then custom sort orders from config, and finally the value is
+// parsed as a sort expression (e.g. "priority desc, age desc").
+func ResolveSort(name string, config *session.Config) (SortOrder, *CustomSort, error) {
+	if order, err := ParseSortOrder(name); err == nil {
+		return order, nil, nil
+	}
+	for _, custom := range customSortOrders(config) {
+		if strings.EqualFold(custom.Name, name) {
+			return SortByCustom, custom, nil
+		}
+	}
+	expr, err := session.ParseSortExpr(name)
+	if err != nil {
+		return SortByIDASC, nil, fmt.Errorf("invalid sort %q: not a built-in or configured sort order, and %w", name, err)
+	}
+	return SortByCustom, &CustomSort{Name: expr.String(), Expr: expr}, nil
+}
+
+// nextCustomSort returns the custom sort order after the current one in the
+// sort cycle, or nil when the cycle should wrap back to the built-in orders
+func (m Model) nextCustomSort() *CustomSort {
+	orders := customSortOrders(m.config)
+	if m.sortOrder != SortByCustom || m.customSort == nil {
+		if len(orders) > 0 {
+			return orders[0]
+		}
+		return nil
+	}
+	for i, order := range orders {
+		if order.Name == m.customSort.Name && i+1 < len(orders) {
+			return orders[i+1]
+		}
+	}
+	return nil
+}
+
+// RenderPlainBoard renders the split view's sessions and balls as aligned plain text.
+// Balls and sessions are loaded and filtered through the same code paths the TUI uses,
+// so the output always matches what the split view would show.
+func RenderPlainBoard(store *session.Store, sessionStore *session.SessionStore, config *session.Config, localOnly bool, opts PlainBoardOptions) (string, error) {
+	m := InitialSplitModel(store, sessionStore, config, localOnly)
+	m.sortOrder = opts.SortOrder
+	m.customSort = opts.CustomSort
+	if opts.ShowComplete {
+		m.filterStates["complete"] = true
+	}
+
+	ballsMsg := loadBalls(store, nil, config, localOnly)().(ballsLoadedMsg)
+	if ballsMsg.err != nil {
+		return "", fmt.Errorf("failed to load balls: %w", ballsMsg.err)
+	}
+	sessionsMsg := loadSessions(sessionStore, nil, config, localOnly)().(sessionsLoadedMsg)
+	if sessionsMsg.err != nil {
+		return "", fmt.Errorf("failed to load sessions: %w", sessionsMsg.err)
+	}
+	m.balls = ballsMsg.balls
+	m.sessions = sessionsMsg.sessions
+	m.applyFilters()
+
+	// The dashboard summarizes projects rather than listing balls
+	sessions := make([]*session.JuggleSession, 0, len(m.sessions)+2)
+	for _, sess := range m.filterSessions() {
+		if sess.ID != PseudoSessionDashboard {
+			sessions = append(sessions, sess)
+		}
+	}
+	if opts.SessionID != "" {
+		var selected []*session.JuggleSession
+		for _, sess := range sessions {
+			if sess.ID == opts.SessionID || plainSessionName(sess.ID) == opts.SessionID {
+				selected = append(selected, sess)
+			}
+		}
+		if len(selected) == 0 {
+			return "", fmt.Errorf("session %s not found", opts.SessionID)
+		}
+		sessions = selected
+	}
+
+	return m.renderPlainBoard(sessions), nil
+}
+
+// renderPlainBoard writes the sessions summary followed by one ball table per session
+func (m Model) renderPlainBoard(sessions []*session.JuggleSession) string {
+	var b strings.Builder
+
+	b.WriteString("Sessions\n")
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, sess := range sessions {
+		fmt.Fprintf(tw, "  %s\t%d\n", plainSessionName(sess.ID), m.countBallsForSession(sess.ID))
+	}
+	tw.Flush()
+
+	for _, sess := range sessions {
+		m.selectedSession = sess
+		balls := m.filterBallsForSession()
+
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Balls: %s%s  %s\n", plainSessionName(sess.ID), m.sortIndicator(), m.buildBallsStats(balls))
+
+		if len(balls) == 0 {
+			b.WriteString("  No balls\n")
+			continue
+		}
+
+		sameProject := allBallsSameProject(balls)
+		minimalIDs := session.ComputeMinimalUniqueIDs(balls)
+
+		tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  ID\tSTATE\tPRIORITY\tTITLE")
+		for _, ball := range balls {
+			idDisplay := ball.ID
+			if sameProject {
+				if minID, ok := minimalIDs[ball.ID]; ok {
+					idDisplay = minID
+				} else {
+					idDisplay = ball.ShortID()
+				}
+			}
+
+			title := ball.Title
+			if ball.NextAction != "" {
+				title = fmt.Sprintf("%s (next: %s)", title, ball.NextAction)
+			}
+			if ball.State == session.StateBlocked && ball.BlockedReason != "" {
+				title = fmt.Sprintf("%s (blocked: %s)", title, ball.BlockedReason)
+			}
+			if ball.HasDependencies() {
+				title = fmt.Sprintf("%s (depends on: %s)", title, strings.Join(ball.DependsOn, ", "))
+			}
+
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", idDisplay, ball.State, ball.Priority, title)
+		}
+		tw.Flush()
+	}
+
+	return b.String()
+}
+
+// plainSessionName returns the display name for a session without decorative icons
+func plainSessionName(id string) string {
+	switch id {
+	case PseudoSessionAll:
+		return "All"
+	case PseudoSessionUntagged:
+		return "Untagged"
+	}
+	return id
+}
@@ -0,0 +1,433 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// projectGroup is one project's section of the balls panel in all-projects mode
+type projectGroup struct {
+	dir       string
+	balls     []*session.Ball // Balls in the current view, including collapsed ones
+	collapsed bool
+}
+
+// projectName returns a project's display name: its registered name, or
+// else its directory name
+func (m *Model) projectName(dir string) string {
+	if m.config == nil {
+		return filepath.Base(dir)
+	}
+	return m.config.ProjectName(dir)
+}
+
+var projectHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("13"))
+
+// groupByProject returns true when balls and sessions are shown grouped by
+// project: in all-projects mode with more than one project loaded, outside
+// the board layout
+func (m *Model) groupByProject() bool {
+	if m.localOnly || m.layout == LayoutBoard {
+		return false
+	}
+	return len(m.projectDirs()) > 1
+}
+
+// projectDirs returns every loaded project, ordered by name
+func (m *Model) projectDirs() []string {
+	seen := make(map[string]bool)
+	dirs := make([]string, 0)
+	add := func(dir string) {
+		if dir != "" && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, ball := range m.balls {
+		add(ball.WorkingDir)
+	}
+	for _, sess := range m.sessions {
+		add(sess.ProjectDir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		a, b := m.projectName(dirs[i]), m.projectName(dirs[j])
+		if a != b {
+			return a < b
+		}
+		return dirs[i] < dirs[j]
+	})
+	return dirs
+}
+
+// projectGroups returns the projects with balls in the current view, in
+// display order
+func (m *Model) projectGroups() []projectGroup {
+	byDir := make(map[string][]*session.Ball)
+	for _, ball := range m.searchBallsForSession() {
+		byDir[ball.WorkingDir] = append(byDir[ball.WorkingDir], ball)
+	}
+	groups := make([]projectGroup, 0, len(byDir))
+	for _, dir := range m.projectDirs() {
+		if balls, ok := byDir[dir]; ok {
+			groups = append(groups, projectGroup{dir: dir, balls: balls, collapsed: m.collapsedProjects[dir]})
+		}
+	}
+	return groups
+}
+
+// groupBallsByProject orders sorted balls by project, keeping the sort
+// order within each project, and drops balls of collapsed projects
+func (m *Model) groupBallsByProject(balls []*session.Ball) []*session.Ball {
+	order := make(map[string]int)
+	for i, dir := range m.projectDirs() {
+		order[dir] = i
+	}
+	result := make([]*session.Ball, 0, len(balls))
+	for _, ball := range balls {
+		if !m.collapsedProjects[ball.WorkingDir] {
+			result = append(result, ball)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return order[result[i].WorkingDir] < order[result[j].WorkingDir]
+	})
+	return result
+}
+
+// groupSessionsByProject orders real sessions by project and drops
+// sessions of collapsed projects. Pseudo-sessions stay first.
+func (m *Model) groupSessionsByProject(sessions []*session.JuggleSession) []*session.JuggleSession {
+	order := make(map[string]int)
+	for i, dir := range m.projectDirs() {
+		order[dir] = i
+	}
+	result := make([]*session.JuggleSession, 0, len(sessions))
+	for _, sess := range sessions {
+		if isPseudoSession(sess.ID) || !m.collapsedProjects[sess.ProjectDir] {
+			result = append(result, sess)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return sessionGroupOrder(result[i], order) < sessionGroupOrder(result[j], order)
+	})
+	return result
+}
+
+// sessionGroupOrder sorts pseudo-sessions before every project
+func sessionGroupOrder(sess *session.JuggleSession, order map[string]int) int {
+	if isPseudoSession(sess.ID) {
+		return -1
+	}
+	return order[sess.ProjectDir]
+}
+
+// renderProjectHeader renders a project's header line with its ball counts
+func (m Model) renderProjectHeader(group projectGroup, width int) string {
+	marker := "▾"
+	if group.collapsed {
+		marker = "▸"
+	}
+	line := fmt.Sprintf("%s %s (%d)  %s", marker, m.projectName(group.dir), len(group.balls), m.buildBallsStats(group.balls))
+	return projectHeaderStyle.Render(truncate(line, width-2))
+}
+
+// sessionProjectHeaders returns the project header lines to render before
+// each session, plus trailing headers at index len(sessions). sessions must
+// come from filterSessions. Collapsed projects always get a header so they
+// can be found again; expanded ones only when they have sessions.
+func (m Model) sessionProjectHeaders(sessions []*session.JuggleSession, width int) [][]string {
+	headers := make([][]string, len(sessions)+1)
+	if !m.groupByProject() {
+		return headers
+	}
+
+	// Count active balls per project, like the session counts
+	counts := make(map[string]int)
+	for _, ball := range m.filteredBalls {
+		if ball.State != session.StateComplete && ball.State != session.StateResearched {
+			counts[ball.WorkingDir]++
+		}
+	}
+	header := func(dir string) string {
+		marker := "▾"
+		if m.collapsedProjects[dir] {
+			marker = "▸"
+		}
+		return projectHeaderStyle.Render(truncate(fmt.Sprintf("%s %s (%d)", marker, m.projectName(dir), counts[dir]), width-2))
+	}
+
+	dirs := m.projectDirs()
+	next := 0
+	current := ""
+	for i, sess := range sessions {
+		if isPseudoSession(sess.ID) || sess.ProjectDir == current {
+			continue
+		}
+		current = sess.ProjectDir
+		for next < len(dirs) && dirs[next] != sess.ProjectDir {
+			if m.collapsedProjects[dirs[next]] {
+				headers[i] = append(headers[i], header(dirs[next]))
+			}
+			next++
+		}
+		if next < len(dirs) {
+			headers[i] = append(headers[i], header(dirs[next]))
+			next++
+		}
+	}
+	for ; next < len(dirs); next++ {
+		if m.collapsedProjects[dirs[next]] {
+			headers[len(sessions)] = append(headers[len(sessions)], header(dirs[next]))
+		}
+	}
+	return headers
+}
+
+// renderGroupedBalls renders the balls panel rows with a header per project.
+// balls must come from filterBallsForSession, which orders them by project
+// and leaves out collapsed projects. The window starts at the scroll offset
+// and moves down if needed to keep the cursor visible.
+func (m Model) renderGroupedBalls(balls []*session.Ball, idDisplay func(*session.Ball) string, width, height int) string {
+	type row struct {
+		header *projectGroup
+		ball   int // Index into balls, -1 for headers
+	}
+	rows := make([]row, 0, len(balls))
+	groups := m.projectGroups()
+	next := 0
+	for i := range groups {
+		rows = append(rows, row{header: &groups[i], ball: -1})
+		if groups[i].collapsed {
+			continue
+		}
+		for next < len(balls) && balls[next].WorkingDir == groups[i].dir {
+			rows = append(rows, row{ball: next})
+			next++
+		}
+	}
+
+	// Start at the scroll offset's ball, including the headers above it
+	// when it is the first ball of its project
+	start, cursorRow := 0, 0
+	for i, r := range rows {
+		if r.ball == m.ballsScrollOffset {
+			start = i
+			for start > 0 && rows[start-1].header != nil {
+				start--
+			}
+		}
+		if r.ball == m.cursor {
+			cursorRow = i
+		}
+	}
+
+	visible := height
+	if start > 0 {
+		visible-- // Top scroll indicator
+	}
+	if visible < 1 {
+		visible = 1
+	}
+	if cursorRow >= start+visible {
+		start = cursorRow - visible + 1
+	}
+	if cursorRow < start {
+		start = cursorRow
+	}
+	end := start + visible
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	var b strings.Builder
+	if start > 0 && m.activePanel == BallsPanel {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↑ %d more rows above", start)) + "\n")
+	}
+	for _, r := range rows[start:end] {
+		if r.header != nil {
+			b.WriteString(m.renderProjectHeader(*r.header, width) + "\n")
+			continue
+		}
+		ball := balls[r.ball]
+		b.WriteString(m.renderBallLine(ball, idDisplay(ball), r.ball == m.cursor && m.activePanel == BallsPanel, width) + "\n")
+	}
+	if remaining := len(rows) - end; remaining > 0 && m.activePanel == BallsPanel {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more rows below", remaining)) + "\n")
+	}
+	return b.String()
+}
+
+// handleProjectGroupsStart opens the project list for collapsing and
+// expanding project groups
+func (m Model) handleProjectGroupsStart() (tea.Model, tea.Cmd) {
+	if !m.groupByProject() {
+		m.message = "Project groups are shown in all-projects mode (P) with more than one project"
+		return m, nil
+	}
+	m.projectSelectIndex = 0
+	m.message = ""
+	m.mode = projectGroupsView
+	return m, nil
+}
+
+// handleProjectGroupsKey handles keyboard input in the project list
+func (m Model) handleProjectGroupsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	dirs := m.projectDirs()
+	switch msg.String() {
+	case "esc", "q", "C":
+		m.mode = splitView
+		m.message = ""
+		return m, nil
+
+	case "up", "k":
+		if m.projectSelectIndex > 0 {
+			m.projectSelectIndex--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.projectSelectIndex < len(dirs)-1 {
+			m.projectSelectIndex++
+		}
+		return m, nil
+
+	case " ", "x":
+		if m.projectSelectIndex < len(dirs) {
+			m.setProjectCollapsed(dirs[m.projectSelectIndex], !m.collapsedProjects[dirs[m.projectSelectIndex]])
+		}
+		return m, nil
+
+	case "enter":
+		if m.projectSelectIndex < len(dirs) {
+			m.jumpToProject(dirs[m.projectSelectIndex])
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// setProjectCollapsed collapses or expands a project's balls and sessions,
+// keeping the highlighted ball and selected session when still visible
+func (m *Model) setProjectCollapsed(dir string, collapsed bool) {
+	var highlighted *session.Ball
+	if balls := m.filterBallsForSession(); m.cursor < len(balls) {
+		highlighted = balls[m.cursor]
+	}
+
+	if m.collapsedProjects == nil {
+		m.collapsedProjects = make(map[string]bool)
+	}
+	if collapsed {
+		m.collapsedProjects[dir] = true
+		m.message = "Collapsed project: " + m.projectName(dir)
+	} else {
+		delete(m.collapsedProjects, dir)
+		m.message = "Expanded project: " + m.projectName(dir)
+	}
+	m.addActivity(m.message)
+
+	balls := m.filterBallsForSession()
+	m.cursor = 0
+	for i, ball := range balls {
+		if ball == highlighted {
+			m.cursor = i
+			break
+		}
+	}
+	m.adjustBallsScrollOffset(balls)
+
+	sessions := m.filterSessions()
+	if m.sessionCursor >= len(sessions) {
+		m.sessionCursor = max(len(sessions)-1, 0)
+	}
+	for i, sess := range sessions {
+		if sess == m.selectedSession {
+			m.sessionCursor = i
+			break
+		}
+	}
+}
+
+// jumpToProject expands a project and moves the cursor to its first ball
+func (m *Model) jumpToProject(dir string) {
+	m.setProjectCollapsed(dir, false)
+	m.mode = splitView
+	m.activePanel = BallsPanel
+	balls := m.filterBallsForSession()
+	for i, ball := range balls {
+		if ball.WorkingDir == dir {
+			m.cursor = i
+			m.adjustBallsScrollOffset(balls)
+			m.message = "Project: " + m.projectName(dir)
+			return
+		}
+	}
+	m.message = "No balls from " + m.projectName(dir) + " in this session"
+}
+
+// renderProjectGroupsView renders the project list with collapse state and
+// per-project counts
+func (m Model) renderProjectGroupsView() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")).
+		Render("Projects")
+	b.WriteString(title + "\n\n")
+
+	balls := make(map[string][]*session.Ball)
+	for _, ball := range m.filteredBalls {
+		balls[ball.WorkingDir] = append(balls[ball.WorkingDir], ball)
+	}
+	sessions := make(map[string]int)
+	for _, sess := range m.sessions {
+		sessions[sess.ProjectDir]++
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("240")).
+		Foreground(lipgloss.Color("15"))
+	collapsedStyle := lipgloss.NewStyle().Faint(true)
+	normalStyle := lipgloss.NewStyle()
+
+	for i, dir := range m.projectDirs() {
+		cursor := "  "
+		if i == m.projectSelectIndex {
+			cursor = "> "
+		}
+		marker := "▾ "
+		if m.collapsedProjects[dir] {
+			marker = "▸ "
+		}
+		line := fmt.Sprintf("%s%s%s %3d balls  %2d sessions  %s", cursor, marker, fitWidth(m.projectName(dir), 20),
+			len(balls[dir]), sessions[dir], m.buildBallsStats(balls[dir]))
+
+		if i == m.projectSelectIndex {
+			b.WriteString(selectedStyle.Render(line) + "\n")
+		} else if m.collapsedProjects[dir] {
+			b.WriteString(collapsedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(normalStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	if m.message != "" {
+		b.WriteString(messageStyle.Render(m.message) + "\n\n")
+	}
+
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Render("j/k = navigate | Space = collapse/expand | Enter = jump to project | Esc = close")
+	b.WriteString(help)
+
+	return b.String()
+}
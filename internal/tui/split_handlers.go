@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -34,12 +35,15 @@ func (m Model) handleStateKeySequence(key string) (tea.Model, tea.Cmd) {
 	case "a":
 		// sa = Archive completed ball
 		return m.handleSplitArchiveBall()
+	case "r":
+		// sr = Reopen completed ball
+		return m.handleSplitReopenBall()
 	case "esc":
 		// Cancel sequence
 		m.message = ""
 		return m, nil
 	default:
-		m.message = "Unknown state: " + key + " (use c/s/b/p/a)"
+		m.message = "Unknown state: " + key + " (use c/s/b/p/a/r)"
 		return m, nil
 	}
 }
@@ -100,12 +104,22 @@ func (m Model) handleToggleKeySequence(key string) (tea.Model, tea.Cmd) {
 		m.filterStates["complete"] = true
 		m.addActivity("Showing all states")
 		m.message = "All states visible"
+	case "m":
+		// tm = Cycle the milestone filter
+		m.filterMilestone = m.nextMilestoneFilter()
+		if m.filterMilestone == "" {
+			m.addActivity("Showing balls in any milestone")
+			m.message = "Milestone: all"
+		} else {
+			m.addActivity("Showing balls in milestone " + m.filterMilestone)
+			m.message = "Milestone: " + m.filterMilestone
+		}
 	case "esc":
 		// Cancel sequence
 		m.message = ""
 		needsFilterUpdate = false
 	default:
-		m.message = "Unknown toggle: " + key + " (use c/b/i/p/h/a)"
+		m.message = "Unknown toggle: " + key + " (use c/b/i/p/m/a)"
 		needsFilterUpdate = false
 	}
 
@@ -159,14 +173,26 @@ func (m Model) handleViewColumnKeySequence(key string) (tea.Model, tea.Cmd) {
 			m.message = "Model size column: hidden"
 		}
 		return m, nil
+	case "s":
+		// vs = Toggle tests state column visibility
+		m.showTestsColumn = !m.showTestsColumn
+		if m.showTestsColumn {
+			m.addActivity("Showing tests column")
+			m.message = "Tests column: visible"
+		} else {
+			m.addActivity("Hiding tests column")
+			m.message = "Tests column: hidden"
+		}
+		return m, nil
 	case "a":
 		// va = Toggle all columns visibility
-		allVisible := m.showPriorityColumn && m.showTagsColumn && m.showModelSizeColumn
+		allVisible := m.showPriorityColumn && m.showTagsColumn && m.showModelSizeColumn && m.showTestsColumn
 		if allVisible {
 			// Hide all
 			m.showPriorityColumn = false
 			m.showTagsColumn = false
 			m.showModelSizeColumn = false
+			m.showTestsColumn = false
 			m.addActivity("Hiding all optional columns")
 			m.message = "All columns: hidden"
 		} else {
@@ -174,16 +200,20 @@ func (m Model) handleViewColumnKeySequence(key string) (tea.Model, tea.Cmd) {
 			m.showPriorityColumn = true
 			m.showTagsColumn = true
 			m.showModelSizeColumn = true
+			m.showTestsColumn = true
 			m.addActivity("Showing all optional columns")
 			m.message = "All columns: visible"
 		}
 		return m, nil
+	case "v":
+		// vv = Cycle layout (split → list → board)
+		return m.handleCycleLayout()
 	case "esc":
 		// Cancel sequence
 		m.message = ""
 		return m, nil
 	default:
-		m.message = "Unknown view column: " + key + " (use p/t/m/a)"
+		m.message = "Unknown view column: " + key + " (use p/t/m/s/a/v)"
 		return m, nil
 	}
 }
@@ -296,6 +326,82 @@ func (m Model) handleSplitArchiveBall() (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleSplitReopenBall returns completed ball(s) to pending
+// Supports multi-select: if balls are selected, reopens all selected completed balls.
+func (m Model) handleSplitReopenBall() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	var ballsToReopen []*session.Ball
+
+	if len(m.selectedBalls) > 0 {
+		for _, ball := range balls {
+			if m.selectedBalls[ball.ID] && (ball.State == session.StateComplete || ball.State == session.StateResearched) {
+				ballsToReopen = append(ballsToReopen, ball)
+			}
+		}
+		if len(ballsToReopen) == 0 {
+			m.message = "No completed balls selected (only completed balls can be reopened)"
+			m.selectedBalls = make(map[string]bool)
+			return m, nil
+		}
+	} else {
+		if len(balls) == 0 || m.cursor >= len(balls) {
+			return m, nil
+		}
+		ball := balls[m.cursor]
+		if ball.State != session.StateComplete && ball.State != session.StateResearched {
+			m.message = "Can only reopen completed balls"
+			return m, nil
+		}
+		ballsToReopen = append(ballsToReopen, ball)
+	}
+
+	var cmds []tea.Cmd
+	for _, ball := range ballsToReopen {
+		store, err := session.NewStore(ball.WorkingDir)
+		if err != nil {
+			m.message = "Error: " + err.Error()
+			return m, nil
+		}
+		cmds = append(cmds, reopenBall(store, ball.ID))
+	}
+
+	if len(ballsToReopen) == 1 {
+		m.addActivity("Reopening ball: " + ballsToReopen[0].ID)
+	} else {
+		m.addActivity(fmt.Sprintf("Reopening %d balls", len(ballsToReopen)))
+	}
+
+	// Clear multi-select after operation
+	m.selectedBalls = make(map[string]bool)
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleBallReopened reports a reopened ball and reloads the balls. A
+// search result for the ball is updated so Enter can jump to it.
+func (m Model) handleBallReopened(msg ballReopenedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.message = "Error: " + msg.err.Error()
+		m.addActivity("Error reopening: " + msg.err.Error())
+		return m, nil
+	}
+	m.message = "Reopened " + msg.ball.ID
+	if msg.archived {
+		m.message += " (restored from archive)"
+	}
+	m.addActivity("Reopened ball: " + msg.ball.ID)
+	if len(msg.restored) > 0 {
+		m.addActivity("Back in sessions: " + strings.Join(msg.restored, ", "))
+	}
+	for _, result := range m.searchResults {
+		if result.Ball != nil && result.Ball.ID == msg.ball.ID {
+			result.Ball = msg.ball
+			result.Archived = false
+		}
+	}
+	return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+}
+
 // handleSplitViewNavUp handles up navigation in split view
 func (m Model) handleSplitViewNavUp() (tea.Model, tea.Cmd) {
 	m.lastKey = "" // Clear gg state
@@ -431,6 +537,30 @@ func (m Model) handleToggleBottomPane() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleToggleArchivedSessions shows or hides archived sessions in the
+// sessions panel
+func (m Model) handleToggleArchivedSessions() (tea.Model, tea.Cmd) {
+	m.showArchivedSessions = !m.showArchivedSessions
+	archived := 0
+	for _, sess := range m.sessions {
+		if sess.IsArchived() {
+			archived++
+		}
+	}
+	if m.showArchivedSessions {
+		m.message = fmt.Sprintf("Showing %d archived session(s) (w to hide)", archived)
+	} else {
+		m.message = "Archived sessions hidden"
+		if m.selectedSession != nil && m.selectedSession.IsArchived() {
+			m.sessionCursor = 0
+		}
+	}
+	if sessions := m.filterSessions(); m.sessionCursor >= len(sessions) {
+		m.sessionCursor = max(len(sessions)-1, 0)
+	}
+	return m, nil
+}
+
 // handleToggleLocalOnly toggles between local project only and all projects
 func (m Model) handleToggleLocalOnly() (tea.Model, tea.Cmd) {
 	// Remember the currently selected session ID before reloading
@@ -453,14 +583,15 @@ func (m Model) handleToggleLocalOnly() (tea.Model, tea.Cmd) {
 
 	// Reload both balls and sessions with new scope
 	return m, tea.Batch(
-		loadBalls(m.store, m.config, m.localOnly),
-		loadSessions(m.sessionStore, m.config, m.localOnly),
+		loadBalls(m.store, m.projectCache, m.config, m.localOnly),
+		loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly),
 	)
 }
 
 // handleToggleSortOrder cycles through sort orders for balls.
 // Each sort method has Desc then Asc before moving to the next method.
-// Order: ID Desc → ID Asc → Priority Desc → Priority Asc → Activity Desc → Activity Asc → Created Desc → Created Asc → (loop)
+// Order: ID Desc → ID Asc → Priority Desc → Priority Asc → Activity Desc → Activity Asc → Created Desc → Created Asc →
+// custom sort orders from config, in config order → (loop)
 func (m Model) handleToggleSortOrder() (tea.Model, tea.Cmd) {
 	// Cycle through sort orders: each method has Desc then Asc
 	switch m.sortOrder {
@@ -492,8 +623,16 @@ func (m Model) handleToggleSortOrder() (tea.Model, tea.Cmd) {
 		m.sortOrder = SortByCreatedAtASC
 		m.addActivity("Sort: Created ascending")
 		m.message = "Sort: Created ascending (oldest first)"
-	case SortByCreatedAtASC:
+	case SortByCreatedAtASC, SortByCustom:
+		if next := m.nextCustomSort(); next != nil {
+			m.sortOrder = SortByCustom
+			m.customSort = next
+			m.addActivity("Sort: " + next.Name)
+			m.message = fmt.Sprintf("Sort: %s (%s)", next.Name, next.Expr)
+			break
+		}
 		m.sortOrder = SortByIDASC
+		m.customSort = nil
 		m.addActivity("Sort: ID ascending")
 		m.message = "Sort: ID ascending"
 	}
@@ -505,7 +644,7 @@ func (m Model) handleToggleAgentOutput() (tea.Model, tea.Cmd) {
 	m.agentOutputVisible = !m.agentOutputVisible
 	if m.agentOutputVisible {
 		m.addActivity("Agent output panel shown")
-		m.message = "Agent output visible (O to hide, E to expand)"
+		m.message = "Agent output visible (O to hide, E to expand, z/Z to fold iterations)"
 	} else {
 		m.addActivity("Agent output panel hidden")
 		m.message = "Agent output hidden (O to show)"
@@ -581,6 +720,72 @@ func (m Model) handleAgentOutputGoToBottom() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleAgentOutputToggleFold collapses or expands the iteration at the top of the agent output panel
+func (m Model) handleAgentOutputToggleFold() (tea.Model, tea.Cmd) {
+	lines := m.visibleAgentOutputLines()
+	if len(lines) == 0 || m.agentOutputOffset >= len(lines) {
+		return m, nil
+	}
+	section := lines[m.agentOutputOffset].entry.Section
+	if section == 0 {
+		m.message = "No iteration at cursor"
+		return m, nil
+	}
+	if m.agentOutputFolds == nil {
+		m.agentOutputFolds = make(map[int]bool)
+	}
+	m.agentOutputFolds[section] = !m.agentOutputFolds[section]
+	if m.agentOutputFolds[section] {
+		m.message = "Iteration collapsed (z to expand)"
+	} else {
+		m.message = "Iteration expanded (z to collapse)"
+	}
+	m.agentOutputOffset = m.agentOutputHeaderOffset(section)
+	return m, nil
+}
+
+// handleAgentOutputToggleAllFolds collapses every iteration, or expands them all if already collapsed
+func (m Model) handleAgentOutputToggleAllFolds() (tea.Model, tea.Cmd) {
+	collapse := false
+	for _, entry := range m.agentOutput {
+		if entry.IsHeader && !m.agentOutputFolds[entry.Section] {
+			collapse = true
+			break
+		}
+	}
+	m.agentOutputFolds = make(map[int]bool)
+	if collapse {
+		for _, entry := range m.agentOutput {
+			if entry.IsHeader {
+				m.agentOutputFolds[entry.Section] = true
+			}
+		}
+		m.message = "All iterations collapsed (Z to expand)"
+	} else {
+		m.message = "All iterations expanded (Z to collapse)"
+	}
+	maxOffset := m.getAgentOutputMaxOffset()
+	if m.agentOutputOffset > maxOffset {
+		m.agentOutputOffset = maxOffset
+	}
+	return m, nil
+}
+
+// agentOutputHeaderOffset returns the scroll offset of a section's header line, clamped to the max offset
+func (m Model) agentOutputHeaderOffset(section int) int {
+	offset := 0
+	for i, line := range m.visibleAgentOutputLines() {
+		if line.entry.IsHeader && line.entry.Section == section {
+			offset = i
+			break
+		}
+	}
+	if maxOffset := m.getAgentOutputMaxOffset(); offset > maxOffset {
+		offset = maxOffset
+	}
+	return offset
+}
+
 // getActivityLogMaxOffset calculates the maximum scroll offset for activity log
 func (m Model) getActivityLogMaxOffset() int {
 	visibleLines := bottomPanelRows - 3 // Account for title and borders
@@ -736,6 +941,8 @@ func (m Model) handleSplitViewEnter() (tea.Model, tea.Cmd) {
 			m.selectedBalls = make(map[string]bool) // Clear multi-select on session change
 			m.activePanel = BallsPanel
 			m.addActivity("Selected session: " + m.selectedSession.ID)
+			// Brief the user on a session they haven't touched in a while
+			return m, m.briefingCmd(m.selectedSession, true)
 		}
 	case BallsPanel:
 		// Open ball in edit mode (same as 'e' key)
@@ -822,7 +1029,16 @@ func (m Model) handleSplitCompleteBall() (tea.Model, tea.Cmd) {
 	}
 
 	var cmds []tea.Cmd
+	var warnings []string
 	for _, ball := range ballsToComplete {
+		warning, err := session.CheckTestsBeforeComplete(ball)
+		if err != nil {
+			m.message = "Error: " + err.Error()
+			return m, nil
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
 		if err := ball.SetState(session.StateComplete); err != nil {
 			m.message = "Error: " + err.Error()
 			return m, nil
@@ -834,7 +1050,7 @@ func (m Model) handleSplitCompleteBall() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		// Update and archive the completed ball
-		cmds = append(cmds, updateAndArchiveBall(store, ball))
+		cmds = append(cmds, withWarning(updateAndArchiveBall(store, ball), warning))
 	}
 
 	if len(ballsToComplete) == 1 {
@@ -842,6 +1058,13 @@ func (m Model) handleSplitCompleteBall() (tea.Model, tea.Cmd) {
 	} else {
 		m.addActivity(fmt.Sprintf("Completing %d balls", len(ballsToComplete)))
 	}
+	// Under the warn tests policy, failing tests don't stop completion
+	for _, warning := range warnings {
+		m.addActivity("Warning: " + warning)
+	}
+	if len(warnings) > 0 {
+		m.message = "Warning: " + warnings[0]
+	}
 
 	// Clear multi-select after operation
 	m.selectedBalls = make(map[string]bool)
@@ -849,6 +1072,123 @@ func (m Model) handleSplitCompleteBall() (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// handleToggleFocus flags the selected ball(s) for the next agent run to
+// work on first, or clears the flag if they all have it. Supports multi-select.
+func (m Model) handleToggleFocus() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	var ballsToFocus []*session.Ball
+
+	if len(m.selectedBalls) > 0 {
+		for _, ball := range balls {
+			if m.selectedBalls[ball.ID] {
+				ballsToFocus = append(ballsToFocus, ball)
+			}
+		}
+	} else {
+		if len(balls) == 0 || m.cursor >= len(balls) {
+			return m, nil
+		}
+		ballsToFocus = append(ballsToFocus, balls[m.cursor])
+	}
+
+	focus := false
+	for _, ball := range ballsToFocus {
+		if ball.State == session.StateComplete || ball.State == session.StateResearched {
+			m.message = "Can't focus a finished ball: " + ball.ID
+			return m, nil
+		}
+		if !ball.Focused {
+			focus = true
+		}
+	}
+	if len(ballsToFocus) == 0 {
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	for _, ball := range ballsToFocus {
+		ball.Focused = focus
+		store, err := session.NewStore(ball.WorkingDir)
+		if err != nil {
+			m.message = "Error: " + err.Error()
+			return m, nil
+		}
+		cmds = append(cmds, updateBall(store, ball))
+	}
+
+	verb := "Focused"
+	if !focus {
+		verb = "Unfocused"
+	}
+	if len(ballsToFocus) == 1 {
+		m.addActivity(verb + " ball: " + ballsToFocus[0].ID)
+	} else {
+		m.addActivity(fmt.Sprintf("%s %d balls", verb, len(ballsToFocus)))
+	}
+	m.selectedBalls = make(map[string]bool)
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleNextActionKeySequence handles the second key in a next action sequence (n+key)
+func (m Model) handleNextActionKeySequence(key string) (tea.Model, tea.Cmd) {
+	m.message = ""
+
+	switch key {
+	case "a":
+		// na = Set next action
+		return m.handleSplitNextAction()
+	case "c":
+		// nc = Set checkpoint
+		return m.handleSplitCheckpoint()
+	default:
+		m.message = "Unknown next action key: " + key + " (use a/c)"
+		return m, nil
+	}
+}
+
+// handleSplitNextAction prompts for the next action of the ball under the
+// cursor, prefilled with the current one
+func (m Model) handleSplitNextAction() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	if len(balls) == 0 || m.cursor >= len(balls) {
+		return m, nil
+	}
+	ball := balls[m.cursor]
+
+	m.editingBall = ball
+	m.textInput.Reset()
+	m.textInput.SetValue(ball.NextAction)
+	m.textInput.Focus()
+	m.textInput.Placeholder = "Next action (e.g., reproduce with the failing fixture)"
+	m.inputTarget = "next_action"
+	m.mode = inputNextActionView
+	return m, nil
+}
+
+// handleSplitCheckpoint prompts for the checkpoint of the ball under the
+// cursor, prefilled with the current one. Finished balls have none.
+func (m Model) handleSplitCheckpoint() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	if len(balls) == 0 || m.cursor >= len(balls) {
+		return m, nil
+	}
+	ball := balls[m.cursor]
+	if ball.State == session.StateComplete || ball.State == session.StateResearched {
+		m.message = "Ball is " + string(ball.State) + "; checkpoints are for unfinished work"
+		return m, nil
+	}
+
+	m.editingBall = ball
+	m.textInput.Reset()
+	m.textInput.SetValue(ball.Checkpoint)
+	m.textInput.Focus()
+	m.textInput.Placeholder = "Checkpoint (e.g., tests written, implementation next)"
+	m.inputTarget = "checkpoint"
+	m.mode = inputCheckpointView
+	return m, nil
+}
+
 // handleSplitBlockBall prompts for a blocked reason
 // Supports multi-select: if balls are selected, the reason will apply to all selected balls.
 func (m Model) handleSplitBlockBall() (tea.Model, tea.Cmd) {
@@ -913,6 +1253,17 @@ func (m *Model) handleStartBall() (tea.Model, tea.Cmd) {
 func (m *Model) handleCompleteBall() (tea.Model, tea.Cmd) {
 	ball := m.filteredBalls[m.cursor]
 
+	// Respect the project's tests policy
+	warning, err := session.CheckTestsBeforeComplete(ball)
+	if err != nil {
+		m.message = "Error: " + err.Error()
+		return m, nil
+	}
+	if warning != "" {
+		m.message = "Warning: " + warning
+		m.addActivity("Warning: " + warning)
+	}
+
 	// Update state to complete
 	if err := ball.SetState(session.StateComplete); err != nil {
 		m.message = "Error: " + err.Error()
@@ -926,7 +1277,7 @@ func (m *Model) handleCompleteBall() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	// Update and archive the completed ball
-	return m, updateAndArchiveBall(store, ball)
+	return m, withWarning(updateAndArchiveBall(store, ball), warning)
 }
 
 func (m *Model) handleDropBall() (tea.Model, tea.Cmd) {
@@ -983,16 +1334,42 @@ func (m *Model) handleStateFilter(key string) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) applyFilters() {
+	m.ballsChanged()
 	m.filteredBalls = make([]*session.Ball, 0)
 
 	for _, ball := range m.balls {
 		// Check if this ball's state is visible
-		if m.filterStates[string(ball.State)] {
-			m.filteredBalls = append(m.filteredBalls, ball)
+		if !m.filterStates[string(ball.State)] {
+			continue
 		}
+		if m.filterMilestone != "" && ball.Milestone != m.filterMilestone {
+			continue
+		}
+		m.filteredBalls = append(m.filteredBalls, ball)
 	}
 }
 
+// nextMilestoneFilter returns the milestone after the current filter, in
+// name order among the loaded balls' milestones, wrapping back to "" (all)
+func (m *Model) nextMilestoneFilter() string {
+	seen := make(map[string]bool)
+	var milestones []string
+	for _, ball := range m.balls {
+		if ball.Milestone != "" && !seen[ball.Milestone] {
+			seen[ball.Milestone] = true
+			milestones = append(milestones, ball.Milestone)
+		}
+	}
+	sort.Strings(milestones)
+
+	for _, name := range milestones {
+		if name > m.filterMilestone {
+			return name
+		}
+	}
+	return ""
+}
+
 func (m *Model) handleCycleState() (tea.Model, tea.Cmd) {
 	ball := m.filteredBalls[m.cursor]
 
@@ -1012,6 +1389,15 @@ func (m *Model) handleCycleState() (tea.Model, tea.Cmd) {
 		nextState = session.StatePending
 	}
 
+	var warning string
+	if nextState == session.StateComplete {
+		var err error
+		if warning, err = session.CheckTestsBeforeComplete(ball); err != nil {
+			m.message = "Error: " + err.Error()
+			return m, nil
+		}
+	}
+
 	if err := ball.SetState(nextState); err != nil {
 		m.message = "Error: " + err.Error()
 		return m, nil
@@ -1024,7 +1410,11 @@ func (m *Model) handleCycleState() (tea.Model, tea.Cmd) {
 	}
 
 	m.message = "Cycled to: " + formatState(ball)
-	return m, updateBall(store, ball)
+	if warning != "" {
+		m.message = "Warning: " + warning
+		m.addActivity("Warning: " + warning)
+	}
+	return m, withWarning(updateBall(store, ball), warning)
 }
 
 func (m *Model) handleSetReady() (tea.Model, tea.Cmd) {
@@ -1076,7 +1466,8 @@ func (m *Model) handleCyclePriority() (tea.Model, tea.Cmd) {
 	return m, updateBall(store, ball)
 }
 
-// loadACTemplatesAndRepoACs loads AC templates and repo/session level ACs for the ball form
+// loadACTemplatesAndRepoACs loads AC templates, repo/session level ACs and
+// the title rules for the ball form
 func (m *Model) loadACTemplatesAndRepoACs() {
 	if m.store == nil {
 		return
@@ -1103,9 +1494,12 @@ func (m *Model) loadACTemplatesAndRepoACs() {
 		m.repoLevelACs = nil
 	}
 
+	// Load title rules (nil when none are set)
+	m.titleRules, _ = session.GetProjectTitleRules(projectDir)
+
 	// Load session-level ACs if a session is selected
 	m.sessionLevelACs = nil
-	if m.selectedSession != nil && m.selectedSession.ID != PseudoSessionAll && m.selectedSession.ID != PseudoSessionUntagged {
+	if m.selectedSession != nil && !isPseudoSession(m.selectedSession.ID) {
 		m.sessionLevelACs = m.selectedSession.AcceptanceCriteria
 	}
 }
@@ -1140,11 +1534,11 @@ func (m Model) handleSplitAddItem() (tea.Model, tea.Cmd) {
 		m.loadACTemplatesAndRepoACs()
 		// Default session to currently selected one (if a real session is selected)
 		m.pendingBallSession = 0 // Start with (none)
-		if m.selectedSession != nil && m.selectedSession.ID != PseudoSessionAll && m.selectedSession.ID != PseudoSessionUntagged {
+		if m.selectedSession != nil && !isPseudoSession(m.selectedSession.ID) {
 			// Find the index of the selected session in real sessions
 			realSessionIdx := 0
 			for _, sess := range m.sessions {
-				if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+				if isPseudoSession(sess.ID) {
 					continue
 				}
 				realSessionIdx++
@@ -1201,11 +1595,11 @@ func (m Model) handleSplitAddFollowup() (tea.Model, tea.Cmd) {
 
 	// Default session to currently selected one (if a real session is selected)
 	m.pendingBallSession = 0 // Start with (none)
-	if m.selectedSession != nil && m.selectedSession.ID != PseudoSessionAll && m.selectedSession.ID != PseudoSessionUntagged {
+	if m.selectedSession != nil && !isPseudoSession(m.selectedSession.ID) {
 		// Find the index of the selected session in real sessions
 		realSessionIdx := 0
 		for _, sess := range m.sessions {
-			if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+			if isPseudoSession(sess.ID) {
 				continue
 			}
 			realSessionIdx++
@@ -1242,7 +1636,7 @@ func (m Model) handleSplitEditItem() (tea.Model, tea.Cmd) {
 		}
 		sess := sessions[m.sessionCursor]
 		// Prevent editing pseudo-sessions
-		if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+		if isPseudoSession(sess.ID) {
 			m.message = "Cannot edit built-in session"
 			return m, nil
 		}
@@ -1267,6 +1661,7 @@ func (m Model) handleSplitEditItem() (tea.Model, tea.Cmd) {
 		m.selectedBalls = make(map[string]bool)
 		ball := balls[m.cursor]
 		m.editingBall = ball
+		m.clearCodeChanged(ball.ID)
 
 		// Initialize file autocomplete for @ mentions
 		if m.store != nil {
@@ -1337,7 +1732,7 @@ func (m Model) handleSplitEditItem() (tea.Model, tea.Cmd) {
 		for _, tag := range ball.Tags {
 			realSessionIdx := 0
 			for _, sess := range m.sessions {
-				if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+				if isPseudoSession(sess.ID) {
 					continue
 				}
 				realSessionIdx++
@@ -1397,6 +1792,7 @@ func (m Model) handleBallEditInEditor() (tea.Model, tea.Cmd) {
 	ball := balls[m.cursor]
 	m.editingBall = ball
 	m.inputAction = actionEdit
+	m.clearCodeChanged(ball.ID)
 	m.addActivity("Opening editor for: " + ball.ID)
 	return m, openEditorCmd(ball)
 }
@@ -1575,7 +1971,7 @@ func (m Model) handleRemoveCurrentSessionFromBall() (tea.Model, tea.Cmd) {
 		m.message = "No session selected"
 		return m, nil
 	}
-	if m.selectedSession.ID == PseudoSessionAll || m.selectedSession.ID == PseudoSessionUntagged {
+	if isPseudoSession(m.selectedSession.ID) {
 		m.message = "Select a real session to remove"
 		return m, nil
 	}
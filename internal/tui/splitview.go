@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ohare93/juggle/internal/session"
@@ -16,6 +17,7 @@ const (
 	bottomPanelRowsExpanded = 15   // Expanded height for agent output panel
 	minLeftWidth            = 20
 	minRightWidth           = 40
+	detailPaneNotes         = 5 // Latest ball notes shown in the detail pane
 )
 
 // Panel styles
@@ -83,13 +85,24 @@ func (m Model) renderSplitView() string {
 		leftWidth = m.width - rightWidth - 3
 	}
 
+	// The list layout hides the sessions panel and gives the balls the full width
+	if m.layout == LayoutList {
+		rightWidth = m.width
+	}
+
 	// Render each panel
-	sessionsPanel := m.renderSessionsPanel(leftWidth-2, mainHeight-2)
+	var sessionsPanel string
+	if m.layout != LayoutList {
+		sessionsPanel = m.renderSessionsPanel(leftWidth-2, mainHeight-2)
+	}
 	ballsPanel := m.renderBallsPanel(rightWidth-2, mainHeight-2)
 
 	// Render bottom panel based on mode
 	var bottomPanel string
-	if m.agentOutputVisible {
+	if m.showWhichKey() {
+		// The pending sequence's second keys take over the bottom pane
+		bottomPanel = m.renderWhichKey(m.width-2, effectiveBottomRows-2)
+	} else if m.agentOutputVisible {
 		// Agent output panel takes over the bottom pane when visible
 		bottomPanel = m.renderAgentOutputPanel(m.width-2, effectiveBottomRows-2)
 	} else {
@@ -130,6 +143,9 @@ func (m Model) renderSplitView() string {
 		sessionsBorder.Render(sessionsPanel),
 		ballsBorder.Render(ballsPanel),
 	)
+	if m.layout == LayoutList {
+		topRow = ballsBorder.Render(ballsPanel)
+	}
 
 	// Status bar
 	statusBar := m.renderStatusBar()
@@ -155,6 +171,9 @@ func (m Model) renderSessionsPanel(width, height int) string {
 
 	// Title with filter indicator
 	title := "Sessions"
+	if m.showArchivedSessions {
+		title += " +archived"
+	}
 	if m.panelSearchActive && m.activePanel == SessionsPanel {
 		title = fmt.Sprintf("Sessions [%s]", m.panelSearchQuery)
 	}
@@ -177,12 +196,12 @@ func (m Model) renderSessionsPanel(width, height int) string {
 		// Calculate available lines for sessions
 		availableLines := height - 3 // Account for title and separator
 
+		// In all-projects mode, real sessions are grouped under project headers
+		headers := m.sessionProjectHeaders(sessions, width)
+
+		var lines []string
 		for i, sess := range sessions {
-			if i >= availableLines {
-				remaining := len(sessions) - availableLines
-				b.WriteString(helpStyle.Render(fmt.Sprintf("  ... +%d more", remaining)))
-				break
-			}
+			lines = append(lines, headers[i]...)
 
 			// Count balls for this session
 			ballCount := m.countBallsForSession(sess.ID)
@@ -190,9 +209,11 @@ func (m Model) renderSessionsPanel(width, height int) string {
 			// Format display name for pseudo-sessions
 			displayName := sess.ID
 			if sess.ID == PseudoSessionAll {
-				displayName = "★ All"
+				displayName = icons.AllSessions + " All"
 			} else if sess.ID == PseudoSessionUntagged {
-				displayName = "○ Untagged"
+				displayName = icons.Untagged + " Untagged"
+			} else if sess.ID == PseudoSessionDashboard {
+				displayName = icons.Dashboard + " Dashboard"
 			}
 
 			// Check if agent is running for this session
@@ -209,17 +230,22 @@ func (m Model) renderSessionsPanel(width, height int) string {
 			if agentRunningForSession {
 				// Replace the space after shortcut with agent indicator
 				if len(shortcutPrefix) >= 2 {
-					prefix = string(shortcutPrefix[0]) + "▶"
+					prefix = string(shortcutPrefix[0]) + icons.AgentRunning
 				} else {
-					prefix = "▶ "
+					prefix = icons.AgentRunning + " "
 				}
 			}
 
-			line := fmt.Sprintf("%s%-*s (%d)",
+			// Progress toward the session's exit criteria and throughput
+			// target, if it has them, after its state when it isn't active
+			target := sessionStateLabel(sess) + sessionGoalLabel(sess) + m.sessionTargetLabel(sess)
+			nameWidth := width - 8 - displayWidth(target) // Adjusted for prefix width
+
+			line := fmt.Sprintf("%s%s (%d)%s",
 				prefix,
-				width-8, // Adjusted for prefix width
-				truncate(displayName, width-8),
+				fitWidth(displayName, nameWidth),
 				ballCount,
+				target,
 			)
 
 			if i == m.sessionCursor && m.activePanel == SessionsPanel {
@@ -229,23 +255,33 @@ func (m Model) renderSessionsPanel(width, height int) string {
 						Bold(true).
 						Foreground(lipgloss.Color("3")). // Yellow for running
 						Background(lipgloss.Color("237"))
-					b.WriteString(runningSelectedStyle.Render(line) + "\n")
+					lines = append(lines, runningSelectedStyle.Render(line))
 				} else {
-					b.WriteString(selectedSessionItemStyle.Render(line) + "\n")
+					lines = append(lines, selectedSessionItemStyle.Render(line))
 				}
 			} else if agentRunningForSession {
 				// Highlight running agent session distinctly
 				runningStyle := lipgloss.NewStyle().
 					Bold(true).
 					Foreground(lipgloss.Color("3")) // Yellow for running
-				b.WriteString(runningStyle.Render(line) + "\n")
+				lines = append(lines, runningStyle.Render(line))
 			} else if m.selectedSession != nil && m.selectedSession.ID == sess.ID {
 				// Highlight selected session even when not in sessions panel
-				b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render(line) + "\n")
+				lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render(line))
 			} else {
-				b.WriteString(sessionItemStyle.Render(line) + "\n")
+				lines = append(lines, sessionItemStyle.Render(line))
 			}
 		}
+		lines = append(lines, headers[len(sessions)]...)
+
+		for i, line := range lines {
+			if i >= availableLines {
+				remaining := len(lines) - availableLines
+				b.WriteString(helpStyle.Render(fmt.Sprintf("  ... +%d more", remaining)))
+				break
+			}
+			b.WriteString(line + "\n")
+		}
 	}
 
 	return b.String()
@@ -253,6 +289,10 @@ func (m Model) renderSessionsPanel(width, height int) string {
 
 // renderBallsPanel renders the right panel with balls and optionally todos
 func (m Model) renderBallsPanel(width, height int) string {
+	if m.selectedSession != nil && m.selectedSession.ID == PseudoSessionDashboard {
+		return m.renderDashboardPanel(width, height)
+	}
+
 	var b strings.Builder
 
 	// Get filtered balls for current session
@@ -274,26 +314,12 @@ func (m Model) renderBallsPanel(width, height int) string {
 		title = "Balls: (none selected)"
 	}
 	// Add sort indicator
-	sortIndicator := ""
-	switch m.sortOrder {
-	case SortByIDASC:
-		sortIndicator = " [↑ID]"
-	case SortByIDDESC:
-		sortIndicator = " [↓ID]"
-	case SortByPriorityDESC:
-		sortIndicator = " [↓Pri]"
-	case SortByPriorityASC:
-		sortIndicator = " [↑Pri]"
-	case SortByLastActivityDESC:
-		sortIndicator = " [↓Act]"
-	case SortByLastActivityASC:
-		sortIndicator = " [↑Act]"
-	case SortByCreatedAtDESC:
-		sortIndicator = " [↓New]"
-	case SortByCreatedAtASC:
-		sortIndicator = " [↑New]"
-	}
+	sortIndicator := m.sortIndicator()
 	title += sortIndicator
+	if m.filterMilestone != "" {
+		title += " ◆ " + m.filterMilestone
+	}
+	title += m.parkedBadge()
 	if m.panelSearchActive && m.activePanel == BallsPanel {
 		title = fmt.Sprintf("%s [%s]", title, m.panelSearchQuery)
 	}
@@ -303,7 +329,7 @@ func (m Model) renderBallsPanel(width, height int) string {
 
 	// Render title row with stats on the right
 	if m.activePanel == BallsPanel {
-		titleRendered := activePanelTitleStyle.Render(truncate(title, width-len(statsStr)-4))
+		titleRendered := activePanelTitleStyle.Render(truncate(title, width-displayWidth(statsStr)-4))
 		statsRendered := lipgloss.NewStyle().Faint(true).Render(statsStr)
 		// Calculate padding to right-align stats
 		titleLen := lipgloss.Width(titleRendered)
@@ -314,7 +340,7 @@ func (m Model) renderBallsPanel(width, height int) string {
 		}
 		b.WriteString(titleRendered + strings.Repeat(" ", padding) + statsRendered + "\n")
 	} else {
-		titleRendered := panelTitleStyle.Render(truncate(title, width-len(statsStr)-4))
+		titleRendered := panelTitleStyle.Render(truncate(title, width-displayWidth(statsStr)-4))
 		statsRendered := lipgloss.NewStyle().Faint(true).Render(statsStr)
 		titleLen := lipgloss.Width(titleRendered)
 		statsLen := lipgloss.Width(statsRendered)
@@ -326,10 +352,12 @@ func (m Model) renderBallsPanel(width, height int) string {
 	}
 	b.WriteString(strings.Repeat("─", width) + "\n")
 
-	if len(balls) == 0 {
+	if len(balls) == 0 && !(m.groupByProject() && len(m.projectGroups()) > 0) {
 		if m.panelSearchActive {
 			b.WriteString(helpStyle.Render("  No matching balls\n"))
 			b.WriteString(helpStyle.Render("  Ctrl+U to clear filter"))
+		} else if m.showParked {
+			b.WriteString(helpStyle.Render("  No parked balls"))
 		} else {
 			b.WriteString(helpStyle.Render("  No balls"))
 			if m.selectedSession != nil {
@@ -340,17 +368,39 @@ func (m Model) renderBallsPanel(width, height int) string {
 		return b.String()
 	}
 
+	if m.layout == LayoutBoard {
+		b.WriteString(m.renderBoardColumns(balls, width, height-2))
+		return b.String()
+	}
+
 	// Calculate available height for balls
 	ballsHeight := height - 4
 	if ballsHeight < 1 {
 		ballsHeight = 1
 	}
 
-	// Determine if all balls are from the same project (to shorten IDs)
-	sameProject := allBallsSameProject(balls)
+	// Determine if all balls are from the same project (to shorten IDs).
+	// Grouped balls are shortened too: the project header names the project.
+	grouped := m.groupByProject()
+	sameProject := grouped || allBallsSameProject(balls)
 
 	// Compute minimal unique IDs for display
-	minimalIDs := session.ComputeMinimalUniqueIDs(balls)
+	minimalIDs := m.minimalBallIDs()
+	idDisplay := func(ball *session.Ball) string {
+		if !sameProject {
+			return ball.ID
+		}
+		// Use minimal unique ID computed for this view
+		if minID, ok := minimalIDs[ball.ID]; ok {
+			return minID
+		}
+		return ball.ShortID()
+	}
+
+	if grouped {
+		b.WriteString(m.renderGroupedBalls(balls, idDisplay, width, ballsHeight))
+		return b.String()
+	}
 
 	// Calculate visible range using scroll offset
 	startIdx := m.ballsScrollOffset
@@ -379,125 +429,231 @@ func (m Model) renderBallsPanel(width, height int) string {
 
 	// Render balls list
 	for i := startIdx; i < endIdx; i++ {
-		ball := balls[i]
+		b.WriteString(m.renderBallLine(balls[i], idDisplay(balls[i]), i == m.cursor && m.activePanel == BallsPanel, width) + "\n")
+	}
 
-		stateIcon := getStateIcon(ball.State)
-		var line string
+	// Show scroll indicator at bottom if more entries
+	remaining := len(balls) - endIdx
+	if remaining > 0 && m.activePanel == BallsPanel {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more items below", remaining)) + "\n")
+	}
 
-		// Build ID display - show minimal unique ID if all balls from same project
-		idDisplay := ball.ID
-		if sameProject {
-			// Use minimal unique ID computed for this view
-			if minID, ok := minimalIDs[ball.ID]; ok {
-				idDisplay = minID
-			} else {
-				idDisplay = ball.ShortID()
-			}
-		}
+	return b.String()
+}
 
-		// Build optional column suffixes based on visibility settings
-		prioritySuffix := ""
-		if m.showPriorityColumn {
-			prioritySuffix = fmt.Sprintf(" [%s]", string(ball.Priority)[0:1]) // First letter: l/m/h/u
-		}
+// renderBallLine renders one ball row of the balls panel
+func (m Model) renderBallLine(ball *session.Ball, idDisplay string, selected bool, width int) string {
+	stateIcon := getStateIcon(ball.State)
+	var line string
 
-		tagsSuffix := ""
-		if m.showTagsColumn && len(ball.Tags) > 0 {
-			// Filter out session names from tags
-			displayTags := filterSessionTags(ball.Tags, m.sessions)
-			if len(displayTags) > 0 {
-				tagsStr := strings.Join(displayTags, ",")
-				if len(tagsStr) > 15 {
-					tagsStr = tagsStr[:12] + "..."
-				}
-				tagsSuffix = fmt.Sprintf(" [%s]", tagsStr)
-			}
-		}
+	// Build optional column suffixes based on visibility settings
+	prioritySuffix := ""
+	if m.showPriorityColumn {
+		prioritySuffix = fmt.Sprintf(" [%s]", icons.Priority(ball.Priority)) // Default: first letter l/m/h/u
+	}
 
-		// Build model size suffix if set and visible
-		modelSizeSuffix := ""
-		if m.showModelSizeColumn && ball.ModelSize != "" {
-			switch ball.ModelSize {
-			case session.ModelSizeSmall:
-				modelSizeSuffix = " [M:S]"
-			case session.ModelSizeMedium:
-				modelSizeSuffix = " [M:M]"
-			case session.ModelSizeLarge:
-				modelSizeSuffix = " [M:L]"
-			}
+	tagsSuffix := ""
+	if m.showTagsColumn && len(ball.Tags) > 0 {
+		// Filter out session names from tags
+		displayTags := filterSessionTags(ball.Tags, m.sessions)
+		if len(displayTags) > 0 {
+			tagsStr := strings.Join(displayTags, ",")
+			tagsSuffix = fmt.Sprintf(" [%s]", truncate(tagsStr, 15))
 		}
+	}
 
-		// Add output marker if ball has output
-		outputMarker := ""
-		if ball.HasOutput() {
-			outputMarker = " [📋]"
+	// Build model size suffix if set and visible
+	modelSizeSuffix := ""
+	if m.showModelSizeColumn && ball.ModelSize != "" {
+		switch ball.ModelSize {
+		case session.ModelSizeSmall:
+			modelSizeSuffix = " [M:S]"
+		case session.ModelSizeMedium:
+			modelSizeSuffix = " [M:M]"
+		case session.ModelSizeLarge:
+			modelSizeSuffix = " [M:L]"
 		}
+	}
+
+	// Build tests suffix if visible
+	testsSuffix := ""
+	if m.showTestsColumn {
+		testsSuffix = " [T:" + testsLabel(ball.TestsResult()) + "]"
+	}
 
-		// Add dependency marker if ball has dependencies
-		depMarker := ""
-		if ball.HasDependencies() {
-			depMarker = " [→]"
+	// Add output marker if ball has output
+	outputMarker := ""
+	if ball.HasOutput() {
+		outputMarker = " [" + icons.Output + "]"
+	}
+
+	// Add dependency marker if ball has dependencies
+	depMarker := ""
+	if ball.HasDependencies() {
+		depMarker = " [" + icons.Dependency + "]"
+	}
+
+	// Add code changed marker if a watched file changed since the TUI started
+	if _, changed := m.codeChanged[ball.ID]; changed {
+		depMarker += " [" + icons.CodeChanged + "]"
+	}
+
+	// Add focus marker if a human flagged the ball for the next agent run
+	if ball.Focused {
+		depMarker += " [" + icons.Focus + "]"
+	}
+
+	// ID prefix (shown before intent)
+	idPrefix := fmt.Sprintf("[%s] ", idDisplay)
+
+	// Calculate total suffix length for width calculation
+	suffixLen := displayWidth(prioritySuffix + tagsSuffix + modelSizeSuffix + testsSuffix + outputMarker + depMarker)
+
+	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
+		// Show blocked reason inline for blocked balls
+		intent := truncate(ball.Title, width-25-displayWidth(idPrefix)-suffixLen)
+		reason := truncate(ball.BlockedReason, width-displayWidth(intent)-15-displayWidth(idPrefix)-suffixLen)
+		line = fmt.Sprintf("%s %s%s [%s]%s%s%s%s%s%s",
+			stateIcon,
+			idPrefix,
+			intent,
+			reason,
+			prioritySuffix,
+			tagsSuffix,
+			modelSizeSuffix,
+			testsSuffix,
+			outputMarker,
+			depMarker,
+		)
+	} else {
+		// The next action follows the title so the immediate step is visible
+		intent := ball.Title
+		if ball.NextAction != "" {
+			intent += " -> " + ball.NextAction
+		}
+		state := string(ball.State)
+		availWidth := width - 15 - displayWidth(idPrefix) - suffixLen
+		if ball.Checkpoint != "" && ball.State == session.StateInProgress {
+			// The checkpoint badge takes its room from the title
+			state = truncate(stateBadge(ball), 30)
+			availWidth = width - 6 - displayWidth(state) - displayWidth(idPrefix) - suffixLen
 		}
+		line = fmt.Sprintf("%s %s%s %s%s%s%s%s%s%s",
+			stateIcon,
+			idPrefix,
+			fitWidth(intent, availWidth),
+			state,
+			prioritySuffix,
+			tagsSuffix,
+			modelSizeSuffix,
+			testsSuffix,
+			outputMarker,
+			depMarker,
+		)
+	}
+	line = styleBallByState(ball, truncate(line, width-2))
+
+	// Check if this ball is multi-selected
+	isMultiSelected := m.selectedBalls[ball.ID]
+
+	if selected {
+		// Cursor position - show with selected style
+		return selectedBallStyle.Render(line)
+	} else if isMultiSelected {
+		// Multi-selected but not at cursor - show with multi-select style
+		return multiSelectedBallStyle.Render(line)
+	}
+	return ballStyle.Render(line)
+}
 
-		// ID prefix (shown before intent)
-		idPrefix := fmt.Sprintf("[%s] ", idDisplay)
-
-		// Calculate total suffix length for width calculation
-		suffixLen := len(prioritySuffix) + len(tagsSuffix) + len(modelSizeSuffix) + len(outputMarker) + len(depMarker)
-
-		if ball.State == session.StateBlocked && ball.BlockedReason != "" {
-			// Show blocked reason inline for blocked balls
-			intent := truncate(ball.Title, width-25-len(idPrefix)-suffixLen)
-			reason := truncate(ball.BlockedReason, width-len(intent)-15-len(idPrefix)-suffixLen)
-			line = fmt.Sprintf("%s %s%s [%s]%s%s%s%s%s",
-				stateIcon,
-				idPrefix,
-				intent,
-				reason,
-				prioritySuffix,
-				tagsSuffix,
-				modelSizeSuffix,
-				outputMarker,
-				depMarker,
-			)
-		} else {
-			availWidth := width - 15 - len(idPrefix) - suffixLen
-			line = fmt.Sprintf("%s %s%-*s %s%s%s%s%s%s",
-				stateIcon,
-				idPrefix,
-				availWidth,
-				truncate(ball.Title, availWidth),
-				string(ball.State),
-				prioritySuffix,
-				tagsSuffix,
-				modelSizeSuffix,
-				outputMarker,
-				depMarker,
-			)
+// renderBoardColumns renders balls as one column per state. balls must be
+// grouped by column (see sortBallsForBoard); the column holding the cursor
+// scrolls to keep it visible.
+func (m Model) renderBoardColumns(balls []*session.Ball, width, height int) string {
+	type column struct {
+		state session.BallState
+		start int // Index of the column's first ball in balls
+		balls []*session.Ball
+	}
+	var columns []column
+	for i, ball := range balls {
+		if len(columns) == 0 || columns[len(columns)-1].state != ball.State {
+			columns = append(columns, column{state: ball.State, start: i})
 		}
-		line = styleBallByState(ball, truncate(line, width-2))
+		columns[len(columns)-1].balls = append(columns[len(columns)-1].balls, ball)
+	}
 
-		// Check if this ball is multi-selected
-		isMultiSelected := m.selectedBalls[ball.ID]
+	colWidth := width / len(columns)
+	rows := height - 2 // Column header and separator
+	if rows < 1 {
+		rows = 1
+	}
+	minimalIDs := m.minimalBallIDs()
 
-		if i == m.cursor && m.activePanel == BallsPanel {
-			// Cursor position - show with selected style
-			b.WriteString(selectedBallStyle.Render(line) + "\n")
-		} else if isMultiSelected {
-			// Multi-selected but not at cursor - show with multi-select style
-			b.WriteString(multiSelectedBallStyle.Render(line) + "\n")
-		} else {
-			b.WriteString(ballStyle.Render(line) + "\n")
+	rendered := make([]string, 0, len(columns))
+	for _, col := range columns {
+		var c strings.Builder
+		header := fmt.Sprintf("%s %s (%d)", getStateIcon(col.state), col.state, len(col.balls))
+		c.WriteString(lipgloss.NewStyle().Bold(true).Render(truncate(header, colWidth-1)) + "\n")
+		c.WriteString(strings.Repeat("─", colWidth-1) + "\n")
+
+		offset := 0
+		if cursor := m.cursor - col.start; cursor >= rows && cursor < len(col.balls) {
+			offset = cursor - rows + 1
 		}
+		for i := offset; i < len(col.balls) && i < offset+rows; i++ {
+			ball := col.balls[i]
+			id := ball.ShortID()
+			if minID, ok := minimalIDs[ball.ID]; ok {
+				id = minID
+			}
+			line := styleBallByState(ball, truncate(fmt.Sprintf("[%s] %s", id, ball.Title), colWidth-3))
+			switch {
+			case col.start+i == m.cursor && m.activePanel == BallsPanel:
+				line = selectedBallStyle.Render(line)
+			case m.selectedBalls[ball.ID]:
+				line = multiSelectedBallStyle.Render(line)
+			default:
+				line = ballStyle.Render(line)
+			}
+			c.WriteString(line + "\n")
+		}
+		rendered = append(rendered, lipgloss.NewStyle().Width(colWidth).Render(c.String()))
 	}
 
-	// Show scroll indicator at bottom if more entries
-	remaining := len(balls) - endIdx
-	if remaining > 0 && m.activePanel == BallsPanel {
-		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more items below", remaining)) + "\n")
-	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
 
-	return b.String()
+// sortIndicator returns the short label for the current sort order shown in the balls panel title
+func (m Model) sortIndicator() string {
+	switch m.sortOrder {
+	case SortByIDASC:
+		return " [↑ID]"
+	case SortByIDDESC:
+		return " [↓ID]"
+	case SortByPriorityDESC:
+		return " [↓Pri]"
+	case SortByPriorityASC:
+		return " [↑Pri]"
+	case SortByLastActivityDESC:
+		return " [↓Act]"
+	case SortByLastActivityASC:
+		return " [↑Act]"
+	case SortByCreatedAtDESC:
+		return " [↓New]"
+	case SortByCreatedAtASC:
+		return " [↑New]"
+	case SortByCustom:
+		if m.customSort != nil {
+			// The first key's direction, as for the built-in orders
+			arrow := "↑"
+			if len(m.customSort.Expr) > 0 && m.customSort.Expr[0].Desc {
+				arrow = "↓"
+			}
+			return " [" + arrow + m.customSort.Name + "]"
+		}
+	}
+	return ""
 }
 
 // renderActivityPanel renders the bottom activity log panel
@@ -569,10 +725,16 @@ func (m Model) renderBallDetailPanel(width, height int) string {
 	// Title with scroll indicator
 	title := "Ball Details"
 	if m.activePanel == ActivityPanel {
-		b.WriteString(activePanelTitleStyle.Render(title) + "\n")
+		b.WriteString(activePanelTitleStyle.Render(title))
 	} else {
-		b.WriteString(panelTitleStyle.Render(title) + "\n")
+		b.WriteString(panelTitleStyle.Render(title))
+	}
+	if ball != nil {
+		if marker := m.agentEditMarker(ball); marker != "" {
+			b.WriteString("  " + helpStyle.Render(truncate(marker, width-displayWidth(title)-4)))
+		}
 	}
+	b.WriteString("\n")
 
 	if ball == nil {
 		b.WriteString(helpStyle.Render("  No ball selected - navigate to a ball to see details"))
@@ -637,49 +799,147 @@ func (m Model) buildBallDetailLines(ball *session.Ball, width int) []string {
 	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")).Width(labelWidth)
 	valueStyle := lipgloss.NewStyle()
 
+	// Fields the agent changed get a highlighted label
+	edit := m.agentEdits[ball.ID]
+	editedStyle := labelStyle.Foreground(lipgloss.Color("11"))
+	fieldLabel := func(field, text string) string {
+		if edit.changed(field) {
+			return editedStyle.Render(text)
+		}
+		return labelStyle.Render(text)
+	}
+
 	// Row 1: ID and State
 	idLabel := labelStyle.Render("ID:")
 	idValue := ball.ID
-	stateLabel := labelStyle.Render("State:")
-	stateValue := string(ball.State)
+	stateLabel := fieldLabel("state", "State:")
+	stateValue := stateBadge(ball)
 	if ball.State == session.StateBlocked && ball.BlockedReason != "" {
 		stateValue += " (" + truncate(ball.BlockedReason, 30) + ")"
 	}
 	lines = append(lines, fmt.Sprintf("  %s %s    %s %s", idLabel, valueStyle.Render(idValue), stateLabel, styleBallByState(ball, stateValue)))
 
+	// Escalation (if waiting on a human owner)
+	if ball.IsEscalated() {
+		escalatedLabel := fieldLabel("state", "Escalated:")
+		lines = append(lines, fmt.Sprintf("  %s %s", escalatedLabel, valueStyle.Render(truncate(ball.Escalation.Label(), width-20))))
+	}
+
+	// Recurrence (schedule and when this instance is due)
+	if ball.IsRecurring() {
+		recursLabel := labelStyle.Render("Recurs:")
+		recursValue := ball.Recurrence.Label() + ", due " + ball.Recurrence.DueLabel(time.Now())
+		lines = append(lines, fmt.Sprintf("  %s %s", recursLabel, valueStyle.Render(truncate(recursValue, width-20))))
+	}
+
 	// Row 2: Priority and Title
-	priorityLabel := labelStyle.Render("Priority:")
+	priorityLabel := fieldLabel("priority", "Priority:")
 	priorityValue := string(ball.Priority)
-	titleLabel := labelStyle.Render("Title:")
+	titleLabel := fieldLabel("title", "Title:")
 	titleValue := truncate(ball.Title, width-50)
 	lines = append(lines, fmt.Sprintf("  %s %s    %s %s", priorityLabel, valueStyle.Render(priorityValue), titleLabel, valueStyle.Render(titleValue)))
 
+	// Next action (if set)
+	if ball.NextAction != "" {
+		nextLabel := fieldLabel("next", "Next:")
+		lines = append(lines, fmt.Sprintf("  %s %s", nextLabel, valueStyle.Bold(true).Render(truncate(ball.NextAction, width-20))))
+	}
+
 	// Row 3: Tags (filtered to exclude session names)
-	tagsLabel := labelStyle.Render("Tags:")
+	tagsLabel := fieldLabel("tags", "Tags:")
 	tagsValue := "(none)"
 	if len(ball.Tags) > 0 {
 		displayTags := filterSessionTags(ball.Tags, m.sessions)
 		if len(displayTags) > 0 {
-			tagsValue = strings.Join(displayTags, ", ")
-			if len(tagsValue) > 40 {
-				tagsValue = truncate(tagsValue, 40)
-			}
+			tagsValue = truncate(strings.Join(displayTags, ", "), 40)
 		}
 	}
 	lines = append(lines, fmt.Sprintf("  %s %s", tagsLabel, valueStyle.Render(tagsValue)))
 
+	// Row 3b: Sessions (resolved from tags)
+	sessionsLabel := fieldLabel("tags", "Sessions:")
+	sessionsValue := "(none)"
+	if ids := ballSessionIDs(ball, m.sessions); len(ids) > 0 {
+		sessionsValue = truncate(strings.Join(ids, ", "), width-40)
+	}
+	lines = append(lines, fmt.Sprintf("  %s %s  %s", sessionsLabel, valueStyle.Render(sessionsValue), helpStyle.Render("(S: jump/add/remove)")))
+
+	// Flagged for the next agent run (f)
+	if ball.Focused {
+		focusLabel := labelStyle.Render("Focus:")
+		lines = append(lines, fmt.Sprintf("  %s %s  %s", focusLabel, valueStyle.Render("next agent run works on this first"), helpStyle.Render("(f: clear)")))
+	}
+
+	// Target release (juggle milestone)
+	if ball.Milestone != "" {
+		milestoneLabel := labelStyle.Render("Milestone:")
+		lines = append(lines, fmt.Sprintf("  %s %s", milestoneLabel, valueStyle.Render(truncate(ball.Milestone, width-20))))
+	}
+
 	// Row 4: Dependencies (if present)
 	if len(ball.DependsOn) > 0 {
-		depsLabel := labelStyle.Render("Depends On:")
-		depsValue := strings.Join(ball.DependsOn, ", ")
-		if len(depsValue) > width-20 {
-			depsValue = truncate(depsValue, width-20)
-		}
+		depsLabel := fieldLabel("depends", "Depends On:")
+		depsValue := truncate(session.NewDependencyIndex(m.balls, m.archivedBalls).FormatDependencies(ball), width-20)
 		lines = append(lines, fmt.Sprintf("  %s %s", depsLabel, valueStyle.Render(depsValue)))
 	}
 
+	// Watched files (if present)
+	if len(ball.WatchGlobs) > 0 {
+		watchLabel := labelStyle.Render("Watching:")
+		watchValue := strings.Join(ball.WatchGlobs, ", ")
+		if changedPath, changed := m.codeChanged[ball.ID]; changed {
+			watchValue += " (code changed: " + changedPath + ")"
+		}
+		watchValue = truncate(watchValue, width-20)
+		lines = append(lines, fmt.Sprintf("  %s %s", watchLabel, valueStyle.Render(watchValue)))
+	}
+
+	// Linked branch and commits referencing the ball (juggle link)
+	if ball.Git != nil {
+		gitLabel := labelStyle.Render("Git:")
+		lines = append(lines, fmt.Sprintf("  %s %s", gitLabel, valueStyle.Render(truncate(ball.Git.Label(), width-20))))
+	}
+
+	// Row 5: Last recorded test run
+	testsLabelText := fieldLabel("tests", "Tests:")
+	lines = append(lines, fmt.Sprintf("  %s %s", testsLabelText, styleTestsResult(ball.TestsResult(), formatTestsState(ball))))
+
+	// Last check of the criteria against the ball's changes (juggle verify)
+	if ball.Verification != nil {
+		verifiedLabel := fieldLabel("criteria", "Verified:")
+		lines = append(lines, fmt.Sprintf("  %s %s", verifiedLabel, valueStyle.Render(truncate(verificationLabel(ball), width-20))))
+	}
+
+	// Attached transcripts (if present), newest last like the T view
+	if len(ball.Transcripts) > 0 {
+		transcriptsLabel := fieldLabel("transcripts", "Transcripts:")
+		lines = append(lines, fmt.Sprintf("  %s %d attached  %s", transcriptsLabel, len(ball.Transcripts), helpStyle.Render("(T: read)")))
+		transcriptStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+		for i, t := range ball.Transcripts {
+			line := fmt.Sprintf("    %d. %s (%d lines)", i+1, t.Label(), t.Lines)
+			lines = append(lines, transcriptStyle.Render(truncate(line, width-4)))
+		}
+	}
+
+	// Work log notes (juggle note), latest last
+	if len(ball.Notes) > 0 {
+		notesLabel := fieldLabel("notes", "Notes:")
+		notes := ball.RecentNotes(detailPaneNotes)
+		count := fmt.Sprintf("%d", len(ball.Notes))
+		if len(notes) < len(ball.Notes) {
+			count = fmt.Sprintf("%d, latest %d shown", len(ball.Notes), len(notes))
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", notesLabel, valueStyle.Render(count)))
+		noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+		for _, note := range notes {
+			text := strings.ReplaceAll(note.Text, "\n", " ")
+			line := fmt.Sprintf("    %s  %s", note.CreatedAt.Format("Jan 2 15:04"), text)
+			lines = append(lines, noteStyle.Render(truncate(line, width-4)))
+		}
+	}
+
 	// Acceptance Criteria section
-	acLabel := labelStyle.Render("Criteria:")
+	acLabel := fieldLabel("criteria", "Criteria:")
 	if len(ball.AcceptanceCriteria) == 0 {
 		lines = append(lines, fmt.Sprintf("  %s %s", acLabel, valueStyle.Render("(none)")))
 	} else {
@@ -687,14 +947,15 @@ func (m Model) buildBallDetailLines(ball *session.Ball, width int) []string {
 		// Add each acceptance criterion
 		acStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 		for i, ac := range ball.AcceptanceCriteria {
-			acLine := fmt.Sprintf("    %d. %s", i+1, truncate(ac, width-10))
+			mark := acVerdictMark(ball, i)
+			acLine := fmt.Sprintf("    %d. %s%s", i+1, mark, truncate(ac, width-10-displayWidth(mark)))
 			lines = append(lines, acStyle.Render(acLine))
 		}
 	}
 
 	// Output section if present
 	if ball.HasOutput() {
-		outputLabel := labelStyle.Render("Output:")
+		outputLabel := fieldLabel("output", "Output:")
 		lines = append(lines, fmt.Sprintf("  %s", outputLabel))
 		outputStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10")) // Green for output
 		// Split output into lines and add each
@@ -757,7 +1018,7 @@ func (m Model) renderBallDetailPanelCompact(width, height int) string {
 	valueStyle := lipgloss.NewStyle()
 
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("ID:"), valueStyle.Render(ball.ID)))
-	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("State:"), styleBallByState(ball, string(ball.State))))
+	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("State:"), styleBallByState(ball, stateBadge(ball))))
 
 	// Show first 2-3 ACs that fit
 	if len(ball.AcceptanceCriteria) > 0 {
@@ -814,32 +1075,13 @@ func (m Model) renderActivityPanelCompact(width, height int) string {
 
 // renderStatusBar renders the bottom status bar with keybindings
 func (m Model) renderStatusBar() string {
-	var hints []string
-
-	// Build contextual hints based on active panel
-	// Show the most relevant keybinds for each panel context
-	switch m.activePanel {
-	case SessionsPanel:
-		hints = []string{
-			"j/k:nav", "Enter:select", "a:add", "A:agent",
-			"e:edit", "d:del", "/:filter", "P:scope",
-			"O:output", "H:history", "?:help", "q:quit",
-		}
-	case BallsPanel:
-		hints = []string{
-			"j/k:nav", "s+c/s/b/p:state", "t+c/b/i/p:filter",
-			"a:add", "e:edit", "E:editor", "d:del", "v+p/t/s/m:columns",
-			"[/]:session", "m/M+#:move", "⌫:unsess", "o:sort", "?:help",
-		}
-	case ActivityPanel:
-		hints = []string{
-			"j/k:scroll", "Ctrl+d/u:page", "gg:top", "G:bottom",
-			"Tab:panels", "O:output", "H:history", "?:help", "q:quit",
-		}
+	// Hints come from the same registry as the help view. While a two-key
+	// sequence is pending, show its second keys instead.
+	hints := m.sequenceHints()
+	if hints == nil {
+		hints = m.footerHints()
 	}
 
-	status := strings.Join(hints, " | ")
-
 	// Add bottom pane mode indicator
 	var modeIndicator string
 	if m.agentOutputVisible {
@@ -866,7 +1108,10 @@ func (m Model) renderStatusBar() string {
 	} else {
 		scopeIndicator = "[All]"
 	}
-	status = modeIndicator + " " + scopeIndicator + " " + status
+	prefix := modeIndicator + " " + scopeIndicator + " "
+	if health := m.apiHealthIndicator(); health != "" {
+		prefix += health + " "
+	}
 
 	// Add agent status indicator if running
 	if m.agentStatus.Running {
@@ -874,20 +1119,66 @@ func (m Model) renderStatusBar() string {
 			m.agentStatus.SessionID,
 			m.agentStatus.Iteration,
 			m.agentStatus.MaxIterations)
-		status = agentIndicator + " " + status
+		prefix = agentIndicator + " " + prefix
 	}
 
 	// Add filter indicator if active
 	if m.panelSearchActive {
-		status = fmt.Sprintf("[Filter: %s Ctrl+U:clear] %s", m.panelSearchQuery, status)
+		prefix = fmt.Sprintf("[Filter: %s Ctrl+U:clear] %s", m.panelSearchQuery, prefix)
 	}
 
 	// Add message if present
 	if m.message != "" {
-		status = messageStyle.Render(m.message) + "  " + status
+		prefix = messageStyle.Render(m.message) + "  " + prefix
 	}
 
-	return helpStyle.Render(status)
+	return helpStyle.Render(prefix + strings.Join(hints, " | "))
+}
+
+// sessionTargetLabel returns a compact label for progress toward a session's
+// throughput target, e.g. " 2/5w", with a trailing "!" when the session is
+// behind. Empty if the session has no target.
+func (m Model) sessionTargetLabel(sess *session.JuggleSession) string {
+	if sess.Target == nil {
+		return ""
+	}
+	balls := make([]*session.Ball, 0, len(m.balls)+len(m.archivedBalls))
+	balls = append(balls, m.balls...)
+	balls = append(balls, m.archivedBalls...)
+	progress := session.SessionThroughput(sess, balls, time.Now())
+	if progress == nil {
+		return ""
+	}
+	label := fmt.Sprintf(" %d/%d%s", progress.Current.Done, progress.Target.Count, progress.Target.Period[:1])
+	if progress.Behind {
+		label += "!"
+	}
+	return label
+}
+
+// sessionStateLabel marks paused and archived sessions in the sessions panel
+func sessionStateLabel(sess *session.JuggleSession) string {
+	switch sess.CurrentState() {
+	case session.SessionStatePaused:
+		return " " + icons.Paused
+	case session.SessionStateArchived:
+		return " " + icons.Archived
+	}
+	return ""
+}
+
+// sessionGoalLabel returns a compact label for a session's met exit
+// criteria, e.g. " ⚑1/3", or " ⚑✓" once all are met. Empty if the session
+// has no exit criteria.
+func sessionGoalLabel(sess *session.JuggleSession) string {
+	met, total := sess.GoalProgress()
+	if total == 0 {
+		return ""
+	}
+	if met == total {
+		return " " + icons.Goal + icons.Checked
+	}
+	return fmt.Sprintf(" %s%d/%d", icons.Goal, met, total)
 }
 
 // countBallsForSession counts non-completed balls that belong to a session.
@@ -931,6 +1222,9 @@ func (m Model) countBallsForSession(sessionID string) int {
 			}
 		}
 		return count
+	case PseudoSessionDashboard:
+		// Count the projects summarized
+		return len(m.dashboard)
 	default:
 		// Regular session - count balls with matching tag
 		count := 0
@@ -957,7 +1251,7 @@ func (m Model) buildRealSessionShortcuts(sessions []*session.JuggleSession) map[
 	realIdx := 0
 	for _, sess := range sessions {
 		// Skip pseudo-sessions
-		if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+		if isPseudoSession(sess.ID) {
 			continue
 		}
 		if realIdx < 10 {
@@ -974,7 +1268,7 @@ func (m Model) buildRealSessionShortcuts(sessions []*session.JuggleSession) map[
 func getRealSessions(sessions []*session.JuggleSession) []*session.JuggleSession {
 	result := make([]*session.JuggleSession, 0)
 	for _, sess := range sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
+		if !isPseudoSession(sess.ID) {
 			result = append(result, sess)
 		}
 	}
@@ -986,7 +1280,7 @@ func getRealSessions(sessions []*session.JuggleSession) []*session.JuggleSession
 func filterSessionTags(tags []string, sessions []*session.JuggleSession) []string {
 	sessionIDs := make(map[string]bool)
 	for _, s := range sessions {
-		if s.ID != PseudoSessionAll && s.ID != PseudoSessionUntagged {
+		if !isPseudoSession(s.ID) {
 			sessionIDs[s.ID] = true
 		}
 	}
@@ -1019,18 +1313,7 @@ func allBallsSameProject(balls []*session.Ball) bool {
 
 // getStateIcon returns an icon for the ball state
 func getStateIcon(state session.BallState) string {
-	switch state {
-	case session.StatePending:
-		return "○"
-	case session.StateInProgress:
-		return "●"
-	case session.StateComplete:
-		return "✓"
-	case session.StateBlocked:
-		return "✗"
-	default:
-		return "?"
-	}
+	return icons.State(state)
 }
 
 // buildBallsStats builds a compact stats string showing ball counts by state
@@ -1072,13 +1355,16 @@ func (m Model) renderAgentOutputPanel(width, height int) string {
 			m.agentStatus.MaxIterations)
 	}
 
+	// Collapsed iterations are folded down to their header line
+	lines := m.visibleAgentOutputLines()
+
 	// Show scroll position if there's content
-	if len(m.agentOutput) > 0 {
+	if len(lines) > 0 {
 		visibleLines := height - 2 // Account for title and border
 		if visibleLines < 1 {
 			visibleLines = 1
 		}
-		title = fmt.Sprintf("%s [%d/%d]", title, m.agentOutputOffset+1, len(m.agentOutput))
+		title = fmt.Sprintf("%s [%d/%d]", title, m.agentOutputOffset+1, len(lines))
 	}
 
 	titleStyled := lipgloss.NewStyle().
@@ -1104,11 +1390,15 @@ func (m Model) renderAgentOutputPanel(width, height int) string {
 	}
 
 	startIdx := m.agentOutputOffset
+	if m.outputSelection != nil {
+		// Keep the selection's cursor on screen, with room for both scroll indicators
+		startIdx = m.outputSelection.scrollStart(startIdx, visibleLines-2)
+	}
 	needTopIndicator := startIdx > 0
 
 	// Pre-calculate if we'll need scroll indicators
 	tentativeEndIdx := startIdx + visibleLines
-	needBottomIndicator := tentativeEndIdx < len(m.agentOutput)
+	needBottomIndicator := tentativeEndIdx < len(lines)
 
 	// Reduce visible lines for scroll indicators
 	if needTopIndicator {
@@ -1122,8 +1412,8 @@ func (m Model) renderAgentOutputPanel(width, height int) string {
 	}
 
 	endIdx := startIdx + visibleLines
-	if endIdx > len(m.agentOutput) {
-		endIdx = len(m.agentOutput)
+	if endIdx > len(lines) {
+		endIdx = len(lines)
 	}
 
 	// Show scroll indicator at top if not at beginning
@@ -1134,13 +1424,28 @@ func (m Model) renderAgentOutputPanel(width, height int) string {
 	// Render visible lines
 	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
 	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9")) // Red for errors
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
 
 	for i := startIdx; i < endIdx; i++ {
-		entry := m.agentOutput[i]
+		entry := lines[i].entry
 		timeStr := entry.Time.Format("15:04:05")
 		line := fmt.Sprintf("  %s %s", timeStr, truncate(entry.Line, width-12))
 
-		if entry.IsError {
+		if entry.IsHeader {
+			iter, maxIter, _ := parseIterationHeader(entry.Line)
+			if lines[i].folded {
+				line = fmt.Sprintf("  %s ▸ Iteration %d/%d (%d lines hidden, z to expand)", timeStr, iter, maxIter, lines[i].hidden)
+			} else {
+				line = fmt.Sprintf("  %s ▾ Iteration %d/%d", timeStr, iter, maxIter)
+			}
+			if m.outputSelection != nil && m.outputSelection.contains(i) {
+				b.WriteString(outputSelectionStyle.Render(truncate(line, width)) + "\n")
+			} else {
+				b.WriteString(headerStyle.Render(truncate(line, width)) + "\n")
+			}
+		} else if m.outputSelection != nil && m.outputSelection.contains(i) {
+			b.WriteString(outputSelectionStyle.Render(line) + "\n")
+		} else if entry.IsError {
 			b.WriteString(errorStyle.Render(line) + "\n")
 		} else {
 			b.WriteString(normalStyle.Render(line) + "\n")
@@ -1148,7 +1453,7 @@ func (m Model) renderAgentOutputPanel(width, height int) string {
 	}
 
 	// Show scroll indicator at bottom if more content
-	remaining := len(m.agentOutput) - endIdx
+	remaining := len(lines) - endIdx
 	if remaining > 0 {
 		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more lines below (j/k to scroll)", remaining)))
 	}
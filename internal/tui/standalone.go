@@ -28,12 +28,24 @@ type StandaloneBallModel struct {
 	pendingBallTags           string   // Comma-separated tags
 	pendingBallSession        int      // Index in session options (0=none, 1+ = session index)
 	pendingBallModelSize      int      // Index in model size options (0=default, 1=small, 2=medium, 3=large)
+	pendingBallAgentProvider  int      // Index in agent provider options (0=default, 1=claude, 2=opencode)
+	pendingBallModelOverride  int      // Index in model override options (0=default, 1=opus, 2=sonnet, 3=haiku)
 	pendingBallDependsOn      []string // Selected dependency ball IDs
 	pendingBallBlockingReason int      // Index in blocking reason options (0=blank, 1=Human needed, 2=Waiting for dependency, 3=Needs research, 4=custom)
 	pendingBallCustomReason   string   // Custom blocking reason text (when pendingBallBlockingReason == 4)
 	pendingBallFormField      int      // Current field in form
 	pendingAcceptanceCriteria []string // Acceptance criteria being collected
 	pendingNewAC              string   // Content of the "new AC" field, preserved during navigation
+	pendingNewSession         string   // "id: description" typed into the session field's "create new…" option
+	pendingSessionID          string   // Session to select once sessions load (from --session)
+
+	// AC templates to pick from, and ACs applied to every ball in the
+	// project or selected session
+	acTemplates        []string
+	acTemplateSelected []bool
+	acTemplateCursor   int // Template under the cursor, -1 when not on the templates
+	repoLevelACs       []string
+	sessionLevelACs    []string
 
 	// File autocomplete state
 	fileAutocomplete *AutocompleteState
@@ -80,7 +92,7 @@ func NewStandaloneBallModel(store *session.Store, sessionStore *session.SessionS
 	ta.ShowLineNumbers = false
 	ta.Focus() // Context field is first, so focus it
 
-	return StandaloneBallModel{
+	m := StandaloneBallModel{
 		store:               store,
 		sessionStore:        sessionStore,
 		textInput:           ti,
@@ -88,6 +100,8 @@ func NewStandaloneBallModel(store *session.Store, sessionStore *session.SessionS
 		pendingBallPriority: 1, // Default to medium
 		fileAutocomplete:    NewAutocompleteState(store.ProjectDir()),
 	}
+	m.loadACTemplatesAndRepoACs()
+	return m
 }
 
 // PrePopulate sets initial values for the form fields from flags
@@ -127,11 +141,8 @@ func (m *StandaloneBallModel) PrePopulate(intent, context string, tags []string,
 	// Set acceptance criteria
 	m.pendingAcceptanceCriteria = acceptanceCriteria
 
-	// Store session ID to match later when sessions are loaded
-	if sessionID != "" {
-		// We'll match this when sessions are loaded
-		m.pendingBallTags = sessionID // Temporarily store - will fix when sessions load
-	}
+	// Selected once sessions are loaded
+	m.pendingSessionID = sessionID
 
 	m.pendingBallDependsOn = dependsOn
 	adjustStandaloneContextHeight(m)
@@ -170,6 +181,18 @@ func (m StandaloneBallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.sessions = msg.sessions
 		}
+		if m.pendingSessionID != "" {
+			// A session that doesn't exist is kept as a tag, as with
+			// --non-interactive
+			if !m.selectFormSession(m.pendingSessionID) {
+				if m.pendingBallTags != "" {
+					m.pendingBallTags += ", "
+				}
+				m.pendingBallTags += m.pendingSessionID
+			}
+			m.pendingSessionID = ""
+			m.loadSessionACs()
+		}
 		return m, nil
 
 	case tea.WindowSizeMsg:
@@ -189,7 +212,7 @@ func (m StandaloneBallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Field indices are dynamic due to variable AC count
-	// Order: Context(0), Title(1), ACs(2 to 2+len(ACs)), Tags, Session, ModelSize, Priority, BlockingReason, DependsOn, Save
+	// Order: Context(0), Title(1), ACs(2 to 2+len(ACs)), Tags, Session, ModelSize, AgentProvider, ModelOverride, Priority, BlockingReason, DependsOn, Save, RunNow
 	const (
 		fieldContext = 0
 		fieldIntent  = 1
@@ -199,21 +222,22 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	fieldTags := fieldACEnd + 1
 	fieldSession := fieldTags + 1
 	fieldModelSize := fieldSession + 1
-	fieldPriority := fieldModelSize + 1
+	fieldAgentProvider := fieldModelSize + 1
+	fieldModelOverride := fieldAgentProvider + 1
+	fieldPriority := fieldModelOverride + 1
 	fieldBlockingReason := fieldPriority + 1
 	fieldDependsOn := fieldBlockingReason + 1
 	fieldSave := fieldDependsOn + 1
 	fieldRunNow := fieldSave + 1
 
 	numModelSizeOptions := 4
+	numAgentProviderOptions := 3
+	numModelOverrideOptions := 4
 	numPriorityOptions := 4
 	numBlockingReasonOptions := 5
-	numSessionOptions := 1
-	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-			numSessionOptions++
-		}
-	}
+	// Session options: (none), each real session, then "create new…"
+	numSessionOptions := len(m.formSessions()) + 2
+	createSessionOption := numSessionOptions - 1
 	maxFieldIndex := fieldRunNow
 
 	isTextInputField := func(field int) bool {
@@ -221,6 +245,10 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		if field == fieldBlockingReason && m.pendingBallBlockingReason == 4 {
 			return true
 		}
+		// Session field is text input while "create new…" is selected
+		if field == fieldSession && m.pendingBallSession == createSessionOption {
+			return true
+		}
 		return field == fieldContext || field == fieldIntent || field == fieldTags ||
 			(field >= fieldACStart && field <= fieldACEnd)
 	}
@@ -257,6 +285,9 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			} else if m.pendingBallFormField == fieldBlockingReason && m.pendingBallBlockingReason == 4 {
 				// Custom blocking reason text
 				m.pendingBallCustomReason = value
+			} else if m.pendingBallFormField == fieldSession && m.pendingBallSession == createSessionOption {
+				// New session being typed
+				m.pendingNewSession = value
 			} else if isACField(m.pendingBallFormField) {
 				acIndex := m.pendingBallFormField - fieldACStart
 				if acIndex < len(m.pendingAcceptanceCriteria) {
@@ -276,20 +307,23 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		}
 	}
 
-	recalcFieldIndices := func() (int, int, int, int, int, int, int, int) {
+	recalcFieldIndices := func() (int, int, int, int, int, int, int, int, int, int, int) {
 		newFieldACEnd := fieldACStart + len(m.pendingAcceptanceCriteria)
 		newFieldTags := newFieldACEnd + 1
 		newFieldSession := newFieldTags + 1
 		newFieldModelSize := newFieldSession + 1
-		newFieldPriority := newFieldModelSize + 1
+		newFieldAgentProvider := newFieldModelSize + 1
+		newFieldModelOverride := newFieldAgentProvider + 1
+		newFieldPriority := newFieldModelOverride + 1
 		newFieldBlockingReason := newFieldPriority + 1
 		newFieldDependsOn := newFieldBlockingReason + 1
 		newFieldSave := newFieldDependsOn + 1
-		return newFieldACEnd, newFieldTags, newFieldSession, newFieldModelSize, newFieldPriority, newFieldBlockingReason, newFieldDependsOn, newFieldSave
+		newFieldRunNow := newFieldSave + 1
+		return newFieldACEnd, newFieldTags, newFieldSession, newFieldModelSize, newFieldAgentProvider, newFieldModelOverride, newFieldPriority, newFieldBlockingReason, newFieldDependsOn, newFieldSave, newFieldRunNow
 	}
 
 	loadFieldValue := func(field int) {
-		acEnd, tagsField, _, _, _, blockingReasonField, _, _ := recalcFieldIndices()
+		acEnd, tagsField, sessionField, _, _, _, _, blockingReasonField, _, _, _ := recalcFieldIndices()
 
 		m.textInput.Reset()
 		switch field {
@@ -314,6 +348,11 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 				m.textInput.SetValue(m.pendingBallCustomReason)
 				m.textInput.Placeholder = "Enter custom blocking reason"
 				m.textInput.Focus()
+			} else if field == sessionField && m.pendingBallSession == createSessionOption {
+				// Creating a new session - show text input
+				m.textInput.SetValue(m.pendingNewSession)
+				m.textInput.Placeholder = "new-session-id: optional description (Enter = create)"
+				m.textInput.Focus()
 			} else if field >= fieldACStart && field <= acEnd {
 				acIndex := field - fieldACStart
 				if acIndex < len(m.pendingAcceptanceCriteria) {
@@ -331,6 +370,17 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		}
 	}
 
+	// saveBall validates the form and creates the ball
+	saveBall := func(runNow bool) (tea.Model, tea.Cmd) {
+		saveCurrentFieldValue()
+		// Title can be empty if context has content (will auto-generate placeholder)
+		if m.pendingBallIntent == "" && m.pendingBallContext == "" {
+			m.message = "Title is required (or add context to auto-generate)"
+			return m, nil
+		}
+		return m.finalizeBallCreation(runNow)
+	}
+
 	switch msg.String() {
 	case "esc":
 		m.done = true
@@ -339,15 +389,25 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 
 	case "ctrl+enter", "ctrl+s":
 		// Create the ball (ctrl+s is more reliable across terminals)
-		saveCurrentFieldValue()
-		// Title can be empty if context has content (will auto-generate placeholder)
-		if m.pendingBallIntent == "" && m.pendingBallContext == "" {
-			m.message = "Title is required (or add context to auto-generate)"
+		return saveBall(false)
+
+	case "enter":
+		// Add the template under the cursor to the ACs
+		if m.acTemplateCursor >= 0 && m.acTemplateCursor < len(m.acTemplates) {
+			template := m.acTemplates[m.acTemplateCursor]
+			if !m.acTemplateSelected[m.acTemplateCursor] {
+				m.pendingAcceptanceCriteria = append(m.pendingAcceptanceCriteria, template)
+				m.acTemplateSelected[m.acTemplateCursor] = true
+				m.message = "Added template: " + truncate(template, 30)
+			} else {
+				m.message = "Template already added"
+			}
+			if m.acTemplateCursor < len(m.acTemplates)-1 {
+				m.acTemplateCursor++
+			}
 			return m, nil
 		}
-		return m.finalizeBallCreation()
 
-	case "enter":
 		if m.pendingBallFormField == fieldContext {
 			var cmd tea.Cmd
 			m.contextInput, cmd = m.contextInput.Update(msg)
@@ -356,142 +416,30 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.pendingBallContext = m.contextInput.Value()
 			return m, cmd
 		} else if m.pendingBallFormField == fieldSave {
-			// Save button - finalize ball creation
-			saveCurrentFieldValue()
-			// Title can be empty if context has content (will auto-generate placeholder)
-			if m.pendingBallIntent == "" && m.pendingBallContext == "" {
-				m.message = "Title is required (or add context to auto-generate)"
-				return m, nil
-			}
-			return m.finalizeBallCreation()
+			return saveBall(false)
 		} else if m.pendingBallFormField == fieldRunNow {
-			// Run now button - save ball and run agent immediately
-			// Note: Standalone mode only creates new balls, no editing
+			// Run now button - save ball and run agent after the form exits
+			return saveBall(true)
+		} else if m.pendingBallFormField == fieldDependsOn {
+			return m.openDependencySelector()
+		} else if m.pendingBallFormField == fieldSession && m.pendingBallSession == createSessionOption {
+			// Create the typed session, select it, and move on
 			saveCurrentFieldValue()
-			// Validate required fields
-			if m.pendingBallIntent == "" && m.pendingBallContext == "" {
-				m.message = "Title is required (or add context to auto-generate)"
+			if err := m.createFormSession(); err != nil {
+				m.message = "Error creating session: " + err.Error()
 				return m, nil
 			}
-
-			// Create the ball first
-			// We need to duplicate the creation logic here to get the ball ID
-			// Map priority index to Priority constant
-			priorities := []session.Priority{session.PriorityLow, session.PriorityMedium, session.PriorityHigh, session.PriorityUrgent}
-			priority := priorities[m.pendingBallPriority]
-
-			// Auto-generate title from context if title is empty but context has content
-			if m.pendingBallIntent == "" && m.pendingBallContext != "" {
-				m.pendingBallIntent = generateTitlePlaceholderFromContext(m.pendingBallContext)
-			}
-
-			ball, err := session.NewBall(m.store.ProjectDir(), m.pendingBallIntent, priority)
-			if err != nil {
-				m.err = err
-				m.done = true
-				return m, tea.Quit
-			}
-
-			// Set ball fields
-			ball.State = session.StatePending
-			ball.Context = m.pendingBallContext
-
-			// Map model size index to ModelSize constant
-			modelSizes := []session.ModelSize{session.ModelSizeBlank, session.ModelSizeSmall, session.ModelSizeMedium, session.ModelSizeLarge}
-			ball.ModelSize = modelSizes[m.pendingBallModelSize]
-
-			// Build tags list
-			var tags []string
-			if m.pendingBallTags != "" {
-				tagList := strings.Split(m.pendingBallTags, ",")
-				for _, tag := range tagList {
-					tag = strings.TrimSpace(tag)
-					if tag != "" {
-						tags = append(tags, tag)
-					}
-				}
-			}
-
-			// Add session tag if selected (0 = none, 1+ = session index)
-			if m.pendingBallSession > 0 {
-				realSessions := []*session.JuggleSession{}
-				for _, sess := range m.sessions {
-					if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-						realSessions = append(realSessions, sess)
-					}
-				}
-				if m.pendingBallSession-1 < len(realSessions) {
-					tags = append(tags, realSessions[m.pendingBallSession-1].ID)
-				}
-			}
-			ball.Tags = tags
-
-			// Set acceptance criteria
-			if len(m.pendingAcceptanceCriteria) > 0 {
-				ball.SetAcceptanceCriteria(m.pendingAcceptanceCriteria)
-			}
-
-			// Set dependencies
-			if len(m.pendingBallDependsOn) > 0 {
-				ball.SetDependencies(m.pendingBallDependsOn)
-			}
-
-			// Handle blocking reason
-			var blockedReason string
-			if m.pendingBallBlockingReason == 1 {
-				blockedReason = "Human needed"
-				// Add human-needed tag
-				hasHumanNeededTag := false
-				for _, tag := range tags {
-					if tag == "human-needed" {
-						hasHumanNeededTag = true
-						break
-					}
-				}
-				if !hasHumanNeededTag {
-					ball.Tags = append(ball.Tags, "human-needed")
-				}
-			} else if m.pendingBallBlockingReason == 2 {
-				blockedReason = "Waiting for dependency"
-			} else if m.pendingBallBlockingReason == 3 {
-				blockedReason = "Needs research"
-			} else if m.pendingBallBlockingReason == 4 {
-				blockedReason = m.pendingBallCustomReason
-			}
-			ball.BlockedReason = blockedReason
-			if blockedReason != "" {
-				ball.State = session.StateBlocked
-			}
-
-			// Save the ball
-			err = m.store.AppendBall(ball)
-			if err != nil {
-				m.err = err
-				m.done = true
-				return m, tea.Quit
-			}
-
-			// Signal to run agent after TUI exits
-			m.result = ball
-			m.done = true
-			m.runAgentForBall = ball.ID
-
-			return m, tea.Quit
-		} else if m.pendingBallFormField == fieldDependsOn {
-			return m.openDependencySelector()
+			m.loadSessionACs()
+			m.pendingBallFormField++
+			loadFieldValue(m.pendingBallFormField)
+			return m, nil
 		} else if isACField(m.pendingBallFormField) {
 			acIndex := m.pendingBallFormField - fieldACStart
 			value := strings.TrimSpace(m.textInput.Value())
 
 			if acIndex == len(m.pendingAcceptanceCriteria) {
 				if value == "" {
-					saveCurrentFieldValue()
-					// Title can be empty if context has content (will auto-generate placeholder)
-					if m.pendingBallIntent == "" && m.pendingBallContext == "" {
-						m.message = "Title is required (or add context to auto-generate)"
-						return m, nil
-					}
-					return m.finalizeBallCreation()
+					return saveBall(false)
 				} else {
 					m.pendingAcceptanceCriteria = append(m.pendingAcceptanceCriteria, value)
 					m.pendingNewAC = "" // Clear preserved content since it was added
@@ -502,10 +450,9 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			} else {
 				saveCurrentFieldValue()
 				m.pendingBallFormField++
-				newACEnd, _, _, _, _, _, _, newSave := recalcFieldIndices()
+				newACEnd, newFieldTags, _, _, _, _, _, _, _, newSave, _ := recalcFieldIndices()
 				maxFieldIndex = newSave
 				if m.pendingBallFormField > newACEnd {
-					_, newFieldTags, _, _, _, _, _, _ := recalcFieldIndices()
 					m.pendingBallFormField = newFieldTags
 				}
 				loadFieldValue(m.pendingBallFormField)
@@ -513,8 +460,8 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		} else {
 			saveCurrentFieldValue()
 			m.pendingBallFormField++
-			_, _, _, _, _, _, _, newSave := recalcFieldIndices()
-			maxFieldIndex = newSave
+			_, _, _, _, _, _, _, _, _, _, newRunNow := recalcFieldIndices()
+			maxFieldIndex = newRunNow
 			if m.pendingBallFormField > maxFieldIndex {
 				m.pendingBallFormField = maxFieldIndex
 			}
@@ -527,10 +474,15 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.fileAutocomplete.SelectPrev()
 			return m, nil
 		}
+		// Move up through the templates, back to the new AC field from the first
+		if m.acTemplateCursor >= 0 && len(m.acTemplates) > 0 {
+			m.acTemplateCursor--
+			return m, nil
+		}
 		saveCurrentFieldValue()
 		m.pendingBallFormField--
-		_, _, _, _, _, _, _, newSave := recalcFieldIndices()
-		maxFieldIndex = newSave
+		_, _, _, _, _, _, _, _, _, _, newRunNow := recalcFieldIndices()
+		maxFieldIndex = newRunNow
 		if m.pendingBallFormField < 0 {
 			m.pendingBallFormField = maxFieldIndex
 		}
@@ -542,10 +494,26 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.fileAutocomplete.SelectNext()
 			return m, nil
 		}
+		newACEnd, newFieldTags, _, _, _, _, _, _, _, _, newRunNow := recalcFieldIndices()
+		// Move down through the templates, on to Tags from the last
+		if m.acTemplateCursor >= 0 && len(m.acTemplates) > 0 {
+			m.acTemplateCursor++
+			if m.acTemplateCursor >= len(m.acTemplates) {
+				m.acTemplateCursor = -1
+				saveCurrentFieldValue()
+				m.pendingBallFormField = newFieldTags
+				loadFieldValue(m.pendingBallFormField)
+			}
+			return m, nil
+		}
 		saveCurrentFieldValue()
+		if m.pendingBallFormField == newACEnd && len(m.acTemplates) > 0 {
+			// From the new AC field, go through the templates first
+			m.acTemplateCursor = 0
+			return m, nil
+		}
 		m.pendingBallFormField++
-		_, _, _, _, _, _, _, newSave := recalcFieldIndices()
-		maxFieldIndex = newSave
+		maxFieldIndex = newRunNow
 		if m.pendingBallFormField > maxFieldIndex {
 			m.pendingBallFormField = 0
 		}
@@ -568,22 +536,44 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.fileAutocomplete.Reset()
 			return m, nil
 		}
+		m.acTemplateCursor = -1
 
 		// Tab always moves to next field
 		// For selection fields, also toggle to next option before moving
-		_, _, sessionField, modelSizeField, priorityField, blockingReasonField, _, _ := recalcFieldIndices()
-		if m.pendingBallFormField == sessionField {
+		_, _, sessionField, modelSizeField, agentProviderField, modelOverrideField, priorityField, blockingReasonField, _, _, _ := recalcFieldIndices()
+		if m.pendingBallFormField == sessionField && m.pendingBallSession == createSessionOption && strings.TrimSpace(m.textInput.Value()) != "" {
+			// Create the typed session before moving on
+			saveCurrentFieldValue()
+			if err := m.createFormSession(); err != nil {
+				m.message = "Error creating session: " + err.Error()
+				return m, nil
+			}
+			m.loadSessionACs()
+		} else if m.pendingBallFormField == sessionField {
 			// Toggle to next session option
 			m.pendingBallSession++
 			if m.pendingBallSession >= numSessionOptions {
 				m.pendingBallSession = 0
 			}
+			m.loadSessionACs()
 		} else if m.pendingBallFormField == modelSizeField {
 			// Toggle to next model size option
 			m.pendingBallModelSize++
 			if m.pendingBallModelSize >= numModelSizeOptions {
 				m.pendingBallModelSize = 0
 			}
+		} else if m.pendingBallFormField == agentProviderField {
+			// Toggle to next agent provider option
+			m.pendingBallAgentProvider++
+			if m.pendingBallAgentProvider >= numAgentProviderOptions {
+				m.pendingBallAgentProvider = 0
+			}
+		} else if m.pendingBallFormField == modelOverrideField {
+			// Toggle to next model override option
+			m.pendingBallModelOverride++
+			if m.pendingBallModelOverride >= numModelOverrideOptions {
+				m.pendingBallModelOverride = 0
+			}
 		} else if m.pendingBallFormField == priorityField {
 			// Toggle to next priority option
 			m.pendingBallPriority++
@@ -601,12 +591,12 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			saveCurrentFieldValue()
 		}
 		// Move to next field
-		newACEnd, newFieldTags, _, _, _, _, _, newSave := recalcFieldIndices()
+		newACEnd, newFieldTags, _, _, _, _, _, _, _, _, newRunNow := recalcFieldIndices()
 		if m.pendingBallFormField == newACEnd {
 			m.pendingBallFormField = newFieldTags
 		} else {
 			m.pendingBallFormField++
-			maxFieldIndex = newSave
+			maxFieldIndex = newRunNow
 			if m.pendingBallFormField > maxFieldIndex {
 				m.pendingBallFormField = 0
 			}
@@ -621,33 +611,41 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		// Fall through to handle space in text input
 
 	case "left", "right":
-		if m.pendingBallFormField == fieldSession {
-			if msg.String() == "left" && m.pendingBallSession > 0 {
-				m.pendingBallSession--
-			} else if msg.String() == "right" && m.pendingBallSession < numSessionOptions-1 {
-				m.pendingBallSession++
+		step := 1
+		if msg.String() == "left" {
+			step = -1
+		}
+		_, _, sessionField, modelSizeField, agentProviderField, modelOverrideField, priorityField, blockingReasonField, _, saveField, runNowField := recalcFieldIndices()
+		switch m.pendingBallFormField {
+		case saveField, runNowField:
+			// Move between the Save and Run now buttons
+			if step > 0 {
+				m.pendingBallFormField = runNowField
+			} else {
+				m.pendingBallFormField = saveField
 			}
 			return m, nil
-		} else if m.pendingBallFormField == fieldModelSize {
-			if msg.String() == "left" && m.pendingBallModelSize > 0 {
-				m.pendingBallModelSize--
-			} else if msg.String() == "right" && m.pendingBallModelSize < numModelSizeOptions-1 {
-				m.pendingBallModelSize++
-			}
+		case sessionField:
+			saveCurrentFieldValue()
+			m.pendingBallSession = cycleOption(m.pendingBallSession, step, numSessionOptions)
+			m.loadSessionACs()
+			// Load text input if switching to/from "create new…"
+			loadFieldValue(m.pendingBallFormField)
 			return m, nil
-		} else if m.pendingBallFormField == fieldPriority {
-			if msg.String() == "left" && m.pendingBallPriority > 0 {
-				m.pendingBallPriority--
-			} else if msg.String() == "right" && m.pendingBallPriority < numPriorityOptions-1 {
-				m.pendingBallPriority++
-			}
+		case modelSizeField:
+			m.pendingBallModelSize = cycleOption(m.pendingBallModelSize, step, numModelSizeOptions)
 			return m, nil
-		} else if m.pendingBallFormField == fieldBlockingReason {
-			if msg.String() == "left" && m.pendingBallBlockingReason > 0 {
-				m.pendingBallBlockingReason--
-			} else if msg.String() == "right" && m.pendingBallBlockingReason < numBlockingReasonOptions-1 {
-				m.pendingBallBlockingReason++
-			}
+		case agentProviderField:
+			m.pendingBallAgentProvider = cycleOption(m.pendingBallAgentProvider, step, numAgentProviderOptions)
+			return m, nil
+		case modelOverrideField:
+			m.pendingBallModelOverride = cycleOption(m.pendingBallModelOverride, step, numModelOverrideOptions)
+			return m, nil
+		case priorityField:
+			m.pendingBallPriority = cycleOption(m.pendingBallPriority, step, numPriorityOptions)
+			return m, nil
+		case blockingReasonField:
+			m.pendingBallBlockingReason = cycleOption(m.pendingBallBlockingReason, step, numBlockingReasonOptions)
 			// Load text input if switching to/from custom mode
 			loadFieldValue(m.pendingBallFormField)
 			return m, nil
@@ -672,6 +670,11 @@ func (m StandaloneBallModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	return m, nil
 }
 
+// cycleOption moves a selection index by step, wrapping around n options
+func cycleOption(index, step, n int) int {
+	return ((index+step)%n + n) % n
+}
+
 func (m StandaloneBallModel) openDependencySelector() (tea.Model, tea.Cmd) {
 	balls, err := m.store.LoadBalls()
 	if err != nil {
@@ -753,19 +756,40 @@ func (m StandaloneBallModel) handleDependencySelectorKey(msg tea.KeyMsg) (tea.Mo
 	return m, nil
 }
 
-func (m StandaloneBallModel) finalizeBallCreation() (tea.Model, tea.Cmd) {
+// finalizeBallCreation creates the ball and exits. With runNow set, the
+// caller is also asked to run the agent on it.
+func (m StandaloneBallModel) finalizeBallCreation(runNow bool) (tea.Model, tea.Cmd) {
 	// Include any preserved new AC content that wasn't added via Enter
 	if m.pendingNewAC != "" {
 		m.pendingAcceptanceCriteria = append(m.pendingAcceptanceCriteria, m.pendingNewAC)
 		m.pendingNewAC = ""
 	}
 
+	// Create a session typed into "create new…" but not submitted with Enter
+	if m.pendingNewSession != "" && m.pendingBallSession == len(m.formSessions())+1 {
+		if err := m.createFormSession(); err != nil {
+			m.message = "Error creating session: " + err.Error()
+			return m, nil
+		}
+	}
+
+	// Auto-generate title from context if title is empty but context has content
+	if m.pendingBallIntent == "" && m.pendingBallContext != "" {
+		m.pendingBallIntent = generateTitlePlaceholderFromContext(m.pendingBallContext)
+	}
+
 	priorities := []session.Priority{session.PriorityLow, session.PriorityMedium, session.PriorityHigh, session.PriorityUrgent}
 	priority := priorities[m.pendingBallPriority]
 
 	modelSizes := []session.ModelSize{session.ModelSizeBlank, session.ModelSizeSmall, session.ModelSizeMedium, session.ModelSizeLarge}
 	modelSize := modelSizes[m.pendingBallModelSize]
 
+	agentProviders := []string{"", "claude", "opencode"}
+	agentProvider := agentProviders[m.pendingBallAgentProvider]
+
+	modelOverrides := []string{"", "opus", "sonnet", "haiku"}
+	modelOverride := modelOverrides[m.pendingBallModelOverride]
+
 	var tags []string
 	if m.pendingBallTags != "" {
 		tagList := strings.Split(m.pendingBallTags, ",")
@@ -777,16 +801,8 @@ func (m StandaloneBallModel) finalizeBallCreation() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if m.pendingBallSession > 0 {
-		realSessions := []*session.JuggleSession{}
-		for _, sess := range m.sessions {
-			if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-				realSessions = append(realSessions, sess)
-			}
-		}
-		if m.pendingBallSession-1 < len(realSessions) {
-			tags = append(tags, realSessions[m.pendingBallSession-1].ID)
-		}
+	if sessionID := m.formSessionID(); sessionID != "" {
+		tags = append(tags, sessionID)
 	}
 
 	// Handle blocking reason
@@ -825,7 +841,12 @@ func (m StandaloneBallModel) finalizeBallCreation() (tea.Model, tea.Cmd) {
 	ball.Context = m.pendingBallContext
 	ball.Tags = tags
 	ball.ModelSize = modelSize
+	ball.AgentProvider = agentProvider
+	ball.ModelOverride = modelOverride
 	ball.BlockedReason = blockedReason
+	if runNow && blockedReason != "" {
+		ball.State = session.StateBlocked
+	}
 
 	if len(m.pendingAcceptanceCriteria) > 0 {
 		ball.SetAcceptanceCriteria(m.pendingAcceptanceCriteria)
@@ -844,9 +865,96 @@ func (m StandaloneBallModel) finalizeBallCreation() (tea.Model, tea.Cmd) {
 
 	m.result = ball
 	m.done = true
+	if runNow {
+		// Signal to run agent after TUI exits
+		m.runAgentForBall = ball.ID
+	}
 	return m, tea.Quit
 }
 
+// formSessions returns the sessions offered in the session field,
+// excluding pseudo-sessions
+func (m StandaloneBallModel) formSessions() []*session.JuggleSession {
+	sessions := []*session.JuggleSession{}
+	for _, sess := range m.sessions {
+		if !isPseudoSession(sess.ID) {
+			sessions = append(sessions, sess)
+		}
+	}
+	return sessions
+}
+
+// formSessionID returns the ID of the selected session, or "" for (none)
+// and "create new…"
+func (m StandaloneBallModel) formSessionID() string {
+	sessions := m.formSessions()
+	if m.pendingBallSession > 0 && m.pendingBallSession-1 < len(sessions) {
+		return sessions[m.pendingBallSession-1].ID
+	}
+	return ""
+}
+
+// selectFormSession selects the session with the given ID, reporting
+// whether it exists
+func (m *StandaloneBallModel) selectFormSession(id string) bool {
+	for i, sess := range m.formSessions() {
+		if sess.ID == id {
+			m.pendingBallSession = i + 1
+			return true
+		}
+	}
+	m.pendingBallSession = 0
+	return false
+}
+
+// createFormSession creates the session typed into the session field's
+// "create new…" option and selects it
+func (m *StandaloneBallModel) createFormSession() error {
+	if m.sessionStore == nil {
+		return fmt.Errorf("session store not available")
+	}
+	id, description, _ := strings.Cut(m.pendingNewSession, ":")
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	sess, err := m.sessionStore.CreateSession(id, strings.TrimSpace(description))
+	if err != nil {
+		return err
+	}
+	m.sessions = append(m.sessions, sess)
+	m.selectFormSession(sess.ID)
+	m.pendingNewSession = ""
+	m.message = "Created session: " + sess.ID
+	return nil
+}
+
+// loadACTemplatesAndRepoACs loads the project's AC templates and
+// repo-level ACs for the form
+func (m *StandaloneBallModel) loadACTemplatesAndRepoACs() {
+	projectDir := m.store.ProjectDir()
+
+	m.acTemplates = nil
+	m.acTemplateSelected = nil
+	m.acTemplateCursor = -1
+	if templates, err := session.GetProjectACTemplates(projectDir); err == nil && len(templates) > 0 {
+		m.acTemplates = templates
+		m.acTemplateSelected = make([]bool, len(templates))
+	}
+
+	m.repoLevelACs, _ = session.GetProjectAcceptanceCriteria(projectDir)
+}
+
+// loadSessionACs shows the selected session's ACs as auto-applied
+func (m *StandaloneBallModel) loadSessionACs() {
+	m.sessionLevelACs = nil
+	sessions := m.formSessions()
+	if m.pendingBallSession > 0 && m.pendingBallSession-1 < len(sessions) {
+		m.sessionLevelACs = sessions[m.pendingBallSession-1].AcceptanceCriteria
+	}
+}
+
 func (m StandaloneBallModel) View() string {
 	if m.inDependencySelector {
 		return m.renderDependencySelector()
@@ -872,18 +980,19 @@ func (m StandaloneBallModel) renderForm() string {
 	fieldTags := fieldACEnd + 1
 	fieldSession := fieldTags + 1
 	fieldModelSize := fieldSession + 1
-	fieldPriority := fieldModelSize + 1
+	fieldAgentProvider := fieldModelSize + 1
+	fieldModelOverride := fieldAgentProvider + 1
+	fieldPriority := fieldModelOverride + 1
 	fieldBlockingReason := fieldPriority + 1
 	fieldDependsOn := fieldBlockingReason + 1
 	fieldSave := fieldDependsOn + 1
 	fieldRunNow := fieldSave + 1
 
 	sessionOptions := []string{"(none)"}
-	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
-			sessionOptions = append(sessionOptions, sess.ID)
-		}
+	for _, sess := range m.formSessions() {
+		sessionOptions = append(sessionOptions, sess.ID)
 	}
+	sessionOptions = append(sessionOptions, "+ create new…")
 
 	activeFieldStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
 	normalStyle := lipgloss.NewStyle()
@@ -1016,6 +1125,41 @@ func (m StandaloneBallModel) renderForm() string {
 		}
 		b.WriteString("\n")
 	}
+
+	// AC templates to pick from (if any)
+	if len(m.acTemplates) > 0 {
+		templateLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+		b.WriteString(templateLabelStyle.Render("  Templates (↓ to navigate, Enter to add):") + "\n")
+		for i, template := range m.acTemplates {
+			cursor := "  "
+			if m.acTemplateCursor == i {
+				cursor = "> "
+			}
+			checkbox := "[ ]"
+			templateStyle := optionNormalStyle
+			if i < len(m.acTemplateSelected) && m.acTemplateSelected[i] {
+				checkbox = "[" + icons.Checked + "]"
+				templateStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+			}
+			if m.acTemplateCursor == i {
+				templateStyle = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("240")).Foreground(lipgloss.Color("15"))
+			}
+			b.WriteString(templateStyle.Render(cursor+checkbox+" "+truncate(template, 53)) + "\n")
+		}
+	}
+
+	// Repo/session level ACs as reminders (if any)
+	if len(m.repoLevelACs) > 0 || len(m.sessionLevelACs) > 0 {
+		reminderLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+		reminderACStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		b.WriteString(reminderLabelStyle.Render("  Auto-applied (not stored on ball):") + "\n")
+		for _, ac := range m.repoLevelACs {
+			b.WriteString(reminderACStyle.Render("    [repo] "+truncate(ac, 50)) + "\n")
+		}
+		for _, ac := range m.sessionLevelACs {
+			b.WriteString(reminderACStyle.Render("    [session] "+truncate(ac, 47)) + "\n")
+		}
+	}
 	b.WriteString("\n")
 
 	// Tags field
@@ -1041,19 +1185,11 @@ func (m StandaloneBallModel) renderForm() string {
 		labelStyle = activeFieldStyle
 	}
 	b.WriteString(labelStyle.Render("Session: "))
-	for i, opt := range sessionOptions {
-		if i > 0 {
-			b.WriteString(" ")
-		}
-		if i == m.pendingBallSession {
-			if m.pendingBallFormField == fieldSession {
-				b.WriteString(optionSelectedStyle.Render(" " + opt + " "))
-			} else {
-				b.WriteString(lipgloss.NewStyle().Bold(true).Render(opt))
-			}
-		} else {
-			b.WriteString(optionNormalStyle.Render(opt))
-		}
+	if m.pendingBallFormField == fieldSession && m.pendingBallSession == len(sessionOptions)-1 {
+		// Typing the ID of a new session
+		b.WriteString(m.textInput.View())
+	} else {
+		b.WriteString(renderStandaloneOptions(sessionOptions, m.pendingBallSession, m.pendingBallFormField == fieldSession))
 	}
 	b.WriteString("\n")
 
@@ -1064,20 +1200,27 @@ func (m StandaloneBallModel) renderForm() string {
 		labelStyle = activeFieldStyle
 	}
 	b.WriteString(labelStyle.Render("Model Size: "))
-	for i, opt := range modelSizeOptions {
-		if i > 0 {
-			b.WriteString(" ")
-		}
-		if i == m.pendingBallModelSize {
-			if m.pendingBallFormField == fieldModelSize {
-				b.WriteString(optionSelectedStyle.Render(" " + opt + " "))
-			} else {
-				b.WriteString(lipgloss.NewStyle().Bold(true).Render(opt))
-			}
-		} else {
-			b.WriteString(optionNormalStyle.Render(opt))
-		}
+	b.WriteString(renderStandaloneOptions(modelSizeOptions, m.pendingBallModelSize, m.pendingBallFormField == fieldModelSize))
+	b.WriteString("\n")
+
+	// Agent Provider field
+	agentProviderOptions := []string{"(default)", "claude", "opencode"}
+	labelStyle = normalStyle
+	if m.pendingBallFormField == fieldAgentProvider {
+		labelStyle = activeFieldStyle
 	}
+	b.WriteString(labelStyle.Render("Agent Provider: "))
+	b.WriteString(renderStandaloneOptions(agentProviderOptions, m.pendingBallAgentProvider, m.pendingBallFormField == fieldAgentProvider))
+	b.WriteString("\n")
+
+	// Model Override field
+	modelOverrideOptions := []string{"(default)", "opus", "sonnet", "haiku"}
+	labelStyle = normalStyle
+	if m.pendingBallFormField == fieldModelOverride {
+		labelStyle = activeFieldStyle
+	}
+	b.WriteString(labelStyle.Render("Model Override: "))
+	b.WriteString(renderStandaloneOptions(modelOverrideOptions, m.pendingBallModelOverride, m.pendingBallFormField == fieldModelOverride))
 	b.WriteString("\n")
 
 	// Priority field
@@ -1199,6 +1342,30 @@ func (m StandaloneBallModel) renderForm() string {
 	return b.String()
 }
 
+// renderStandaloneOptions renders a selection field's options, highlighting
+// the selected one
+func renderStandaloneOptions(options []string, selected int, focused bool) string {
+	optionSelectedStyle := lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("6")).Foreground(lipgloss.Color("0"))
+	optionNormalStyle := lipgloss.NewStyle().Faint(true)
+
+	var b strings.Builder
+	for i, opt := range options {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		if i == selected {
+			if focused {
+				b.WriteString(optionSelectedStyle.Render(" " + opt + " "))
+			} else {
+				b.WriteString(lipgloss.NewStyle().Bold(true).Render(opt))
+			}
+		} else {
+			b.WriteString(optionNormalStyle.Render(opt))
+		}
+	}
+	return b.String()
+}
+
 func (m StandaloneBallModel) renderDependencySelector() string {
 	var b strings.Builder
 
@@ -1221,13 +1388,11 @@ func (m StandaloneBallModel) renderDependencySelector() string {
 
 		check := "[ ]"
 		if isSelected {
-			check = checkStyle.Render("[✓]")
+			check = checkStyle.Render("[" + icons.Checked + "]")
 		}
 
 		line := fmt.Sprintf("%s %s: %s", check, ball.ID, ball.Title)
-		if len(line) > 70 {
-			line = line[:67] + "..."
-		}
+		line = truncate(line, 70)
 
 		if isCursor {
 			b.WriteString(selectedStyle.Render(" " + line + " "))
@@ -1299,8 +1464,8 @@ func adjustStandaloneContextHeight(m *StandaloneBallModel) {
 
 	wrappedLines := 0
 	for _, line := range strings.Split(content, "\n") {
-		if len(line) > 58 {
-			wrappedLines += (len(line) / 58) + 1
+		if width := displayWidth(line); width > 58 {
+			wrappedLines += (width / 58) + 1
 		} else {
 			wrappedLines++
 		}
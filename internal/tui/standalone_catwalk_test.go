@@ -184,6 +184,82 @@ func TestStandaloneBallFormACPlaceholder(t *testing.T) {
 	catwalk.RunModel(t, "testdata/standalone_ball_form_ac_placeholder", model)
 }
 
+// TestStandaloneBallFormSessionsAndTemplates tests the parts of the form
+// shared with the TUI: --session is selected once sessions load, a new
+// session can be created from the session field, and AC templates are added
+// from under the criteria
+func TestStandaloneBallFormSessionsAndTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := session.NewStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	sessionStore, err := session.NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	auth, err := sessionStore.CreateSession("auth", "Authentication")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	if err := session.UpdateProjectACTemplates(tmpDir, []string{"Tests pass"}); err != nil {
+		t.Fatalf("failed to set AC templates: %v", err)
+	}
+
+	model := NewStandaloneBallModel(store, sessionStore)
+	model.PrePopulate("Add lockout", "", []string{"security"}, "auth", "high", "", nil, nil)
+	newModel, _ := model.Update(loadedSessionsForStandaloneMsg{sessions: []*session.JuggleSession{auth}})
+	m := newModel.(StandaloneBallModel)
+	if m.formSessionID() != "auth" || m.pendingBallTags != "security" {
+		t.Fatalf("Expected auth selected and tags kept, got session %q tags %q", m.formSessionID(), m.pendingBallTags)
+	}
+
+	// Down from the new AC field goes through the templates
+	m.pendingBallFormField = 2
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = newModel.(StandaloneBallModel)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(StandaloneBallModel)
+	if len(m.pendingAcceptanceCriteria) != 1 || m.pendingAcceptanceCriteria[0] != "Tests pass" {
+		t.Fatalf("Expected the template added as an AC, got %v", m.pendingAcceptanceCriteria)
+	}
+
+	// "+ create new…" is the last session option; typing there creates it
+	m.acTemplateCursor = -1
+	m.pendingBallFormField = 5 // Session, after one AC
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	m = newModel.(StandaloneBallModel)
+	for _, r := range "billing: Payments" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(StandaloneBallModel)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(StandaloneBallModel)
+
+	result := m.Result()
+	if result.Err != nil || result.Ball == nil {
+		t.Fatalf("Expected a ball, got %+v", result)
+	}
+	if !result.Ball.HasTag("billing") || result.Ball.HasTag("auth") || result.Ball.Priority != session.PriorityHigh {
+		t.Errorf("Unexpected ball %+v", result.Ball)
+	}
+	if sess, err := sessionStore.LoadSession("billing"); err != nil || sess.Description != "Payments" {
+		t.Errorf("Expected the billing session created, got %+v (%v)", sess, err)
+	}
+}
+
+// TestStandaloneBallFormUnknownSession tests that a --session that doesn't
+// exist is kept as a tag, as with --non-interactive
+func TestStandaloneBallFormUnknownSession(t *testing.T) {
+	model := createTestStandaloneBallModel(t)
+	model.PrePopulate("Task", "", []string{"a"}, "missing", "", "", nil, nil)
+	newModel, _ := model.Update(loadedSessionsForStandaloneMsg{})
+	m := newModel.(StandaloneBallModel)
+	if m.pendingBallTags != "a, missing" || m.formSessionID() != "" {
+		t.Errorf("Expected the session kept as a tag, got tags %q session %q", m.pendingBallTags, m.formSessionID())
+	}
+}
+
 // createTestSplitViewModel creates a Model configured for split view testing.
 func createTestSplitViewModel(t *testing.T) Model {
 	t.Helper()
@@ -1236,6 +1312,8 @@ func TestHistoryViewSelectedDetails(t *testing.T) {
 			BallsTotal:    3,
 			TotalWaitTime: 30 * time.Second,
 			OutputFile:    "/tmp/juggle/frontend-tasks/last_output.txt",
+			Label:         "attempt-2-after-prompt-fix",
+			Note:          "Got further; needs credentials",
 		},
 		{
 			ID:            "1736775671000000000",
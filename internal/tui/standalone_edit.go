@@ -181,7 +181,7 @@ func (m StandaloneEditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessions = msg.sessions
 			// Find the session that matches the ball's tags
 			for i, sess := range m.sessions {
-				if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+				if isPseudoSession(sess.ID) {
 					continue
 				}
 				for _, tag := range m.ball.Tags {
@@ -189,7 +189,7 @@ func (m StandaloneEditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						// Account for "(none)" being index 0
 						realIdx := 1
 						for _, s := range m.sessions {
-							if s.ID == PseudoSessionAll || s.ID == PseudoSessionUntagged {
+							if isPseudoSession(s.ID) {
 								continue
 							}
 							if s.ID == sess.ID {
@@ -243,7 +243,7 @@ func (m StandaloneEditModel) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	numBlockingReasonOptions := 5
 	numSessionOptions := 1
 	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
+		if !isPseudoSession(sess.ID) {
 			numSessionOptions++
 		}
 	}
@@ -701,7 +701,7 @@ func (m StandaloneEditModel) finalizeBallEdit() (tea.Model, tea.Cmd) {
 	if m.pendingBallSession > 0 {
 		realSessions := []*session.JuggleSession{}
 		for _, sess := range m.sessions {
-			if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
+			if !isPseudoSession(sess.ID) {
 				realSessions = append(realSessions, sess)
 			}
 		}
@@ -801,7 +801,7 @@ func (m StandaloneEditModel) renderForm() string {
 
 	sessionOptions := []string{"(none)"}
 	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
+		if !isPseudoSession(sess.ID) {
 			sessionOptions = append(sessionOptions, sess.ID)
 		}
 	}
@@ -1134,13 +1134,11 @@ func (m StandaloneEditModel) renderDependencySelector() string {
 
 		check := "[ ]"
 		if isSelected {
-			check = checkStyle.Render("[✓]")
+			check = checkStyle.Render("[" + icons.Checked + "]")
 		}
 
 		line := fmt.Sprintf("%s %s: %s", check, ball.ID, ball.Title)
-		if len(line) > 70 {
-			line = line[:67] + "..."
-		}
+		line = truncate(line, 70)
 
 		if isCursor {
 			b.WriteString(selectedStyle.Render(" " + line + " "))
@@ -1211,8 +1209,8 @@ func adjustStandaloneEditContextHeight(m *StandaloneEditModel) {
 
 	wrappedLines := 0
 	for _, line := range strings.Split(content, "\n") {
-		if len(line) > 58 {
-			wrappedLines += (len(line) / 58) + 1
+		if width := displayWidth(line); width > 58 {
+			wrappedLines += (width / 58) + 1
 		} else {
 			wrappedLines++
 		}
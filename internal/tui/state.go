@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/session"
+)
+
+// ViewLayout controls how the split view arranges the balls
+type ViewLayout int
+
+const (
+	LayoutSplit ViewLayout = iota // Sessions panel beside the balls panel (default)
+	LayoutList                    // Balls panel at full width, sessions panel hidden
+	LayoutBoard                   // Balls grouped into one column per state
+)
+
+var layoutNames = map[ViewLayout]string{
+	LayoutSplit: session.ViewSplit,
+	LayoutList:  session.ViewList,
+	LayoutBoard: session.ViewBoard,
+}
+
+var panelNames = map[Panel]string{
+	SessionsPanel: "sessions",
+	BallsPanel:    "balls",
+	ActivityPanel: "activity",
+}
+
+var bottomPaneNames = map[BottomPaneMode]string{
+	BottomPaneActivity: "activity",
+	BottomPaneDetail:   "detail",
+	BottomPaneSplit:    "split",
+}
+
+// boardColumns are the ball states shown as board columns, in order
+var boardColumns = []session.BallState{
+	session.StatePending,
+	session.StateInProgress,
+	session.StateBlocked,
+	session.StateResearched,
+	session.StateComplete,
+}
+
+// ParseViewLayout converts a view name (split, list, board) to a ViewLayout
+func ParseViewLayout(name string) (ViewLayout, bool) {
+	for layout, n := range layoutNames {
+		if n == name {
+			return layout, true
+		}
+	}
+	return LayoutSplit, false
+}
+
+// SetLayout sets how the split view arranges the panels
+func (m *Model) SetLayout(layout ViewLayout) {
+	m.layout = layout
+	if layout == LayoutList && m.activePanel == SessionsPanel {
+		m.activePanel = BallsPanel
+	}
+}
+
+// State returns the TUI state to remember for the next launch
+func (m Model) State() *session.TUIState {
+	state := &session.TUIState{
+		SessionID:      m.SelectedSessionID(),
+		ActivePanel:    panelNames[m.activePanel],
+		BottomPane:     bottomPaneNames[m.bottomPaneMode],
+		FilterStates:   make(map[string]bool, len(m.filterStates)),
+		Cursor:         m.cursor,
+		BallsScroll:    m.ballsScrollOffset,
+		ActivityScroll: m.activityLogOffset,
+		DetailScroll:   m.detailScrollOffset,
+	}
+	for name, order := range sortOrderNames {
+		if order == m.sortOrder {
+			state.Sort = name
+			break
+		}
+	}
+	if m.sortOrder == SortByCustom && m.customSort != nil {
+		state.Sort = m.customSort.Name
+	}
+	for k, v := range m.filterStates {
+		state.FilterStates[k] = v
+	}
+	columns := []struct {
+		name    string
+		visible bool
+	}{
+		{"priority", m.showPriorityColumn},
+		{"tags", m.showTagsColumn},
+		{"model_size", m.showModelSizeColumn},
+		{"tests", m.showTestsColumn},
+	}
+	for _, c := range columns {
+		if c.visible {
+			state.Columns = append(state.Columns, c.name)
+		}
+	}
+	for dir, collapsed := range m.collapsedProjects {
+		if collapsed {
+			state.CollapsedProjects = append(state.CollapsedProjects, dir)
+		}
+	}
+	sort.Strings(state.CollapsedProjects)
+	return state
+}
+
+// RestoreState applies remembered TUI state. The session is left alone
+// when one was already requested (e.g. by --session). Out-of-range cursor
+// positions are reset once balls are loaded.
+func (m *Model) RestoreState(state *session.TUIState) {
+	if state == nil {
+		return
+	}
+	if m.initialSessionID == "" {
+		m.initialSessionID = state.SessionID
+	}
+	for panel, name := range panelNames {
+		if name == state.ActivePanel {
+			m.activePanel = panel
+		}
+	}
+	for mode, name := range bottomPaneNames {
+		if name == state.BottomPane {
+			m.bottomPaneMode = mode
+		}
+	}
+	if order, custom, err := ResolveSort(state.Sort, m.config); err == nil {
+		m.sortOrder = order
+		m.customSort = custom
+	}
+	for k, v := range state.FilterStates {
+		if _, ok := m.filterStates[k]; ok {
+			m.filterStates[k] = v
+		}
+	}
+	if state.Columns != nil {
+		visible := make(map[string]bool, len(state.Columns))
+		for _, c := range state.Columns {
+			visible[c] = true
+		}
+		m.showPriorityColumn = visible["priority"]
+		m.showTagsColumn = visible["tags"]
+		m.showModelSizeColumn = visible["model_size"]
+		m.showTestsColumn = visible["tests"]
+	}
+	if len(state.CollapsedProjects) > 0 {
+		m.collapsedProjects = make(map[string]bool, len(state.CollapsedProjects))
+		for _, dir := range state.CollapsedProjects {
+			m.collapsedProjects[dir] = true
+		}
+	}
+	m.cursor = state.Cursor
+	m.ballsScrollOffset = state.BallsScroll
+	m.activityLogOffset = state.ActivityScroll
+	m.detailScrollOffset = state.DetailScroll
+}
+
+// handleCycleLayout switches between the split, list, and board layouts
+func (m Model) handleCycleLayout() (tea.Model, tea.Cmd) {
+	m.SetLayout((m.layout + 1) % 3)
+	m.cursor = 0
+	m.ballsScrollOffset = 0
+	m.message = "View: " + layoutNames[m.layout]
+	m.addActivity("Switched to " + layoutNames[m.layout] + " view")
+	return m, nil
+}
+
+// sortBallsForBoard groups balls by board column, keeping the sort order
+// within each column so cursor movement follows the columns left to right
+func sortBallsForBoard(balls []*session.Ball) {
+	column := func(state session.BallState) int {
+		for i, s := range boardColumns {
+			if s == state {
+				return i
+			}
+		}
+		return len(boardColumns)
+	}
+	sort.SliceStable(balls, func(i, j int) bool {
+		return column(balls[i].State) < column(balls[j].State)
+	})
+}
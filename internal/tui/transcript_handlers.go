@@ -0,0 +1,98 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleTranscriptStart opens the most recent transcript attached to the
+// highlighted ball
+func (m Model) handleTranscriptStart() (tea.Model, tea.Cmd) {
+	balls := m.filterBallsForSession()
+	if len(balls) == 0 || m.cursor >= len(balls) {
+		m.message = "No ball selected"
+		return m, nil
+	}
+	ball := balls[m.cursor]
+	if len(ball.Transcripts) == 0 {
+		m.message = "No transcripts attached - use 'juggle attach-transcript " + ball.ShortID() + "'"
+		return m, nil
+	}
+
+	m.editingBall = ball
+	m.transcriptIndex = len(ball.Transcripts) - 1
+	m.transcriptContent = "Loading..."
+	m.transcriptOffset = 0
+	m.message = ""
+	m.mode = transcriptView
+	return m, loadTranscript(ball, m.transcriptIndex)
+}
+
+// handleTranscriptViewKey handles keyboard input in the transcript view
+func (m Model) handleTranscriptViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "T":
+		m.mode = splitView
+		m.editingBall = nil
+		m.transcriptContent = ""
+		return m, nil
+
+	case "up", "k":
+		if m.transcriptOffset > 0 {
+			m.transcriptOffset--
+		}
+		return m, nil
+
+	case "down", "j":
+		m.transcriptOffset++
+		return m, nil
+
+	case "ctrl+d":
+		m.transcriptOffset += 15
+		return m, nil
+
+	case "ctrl+u":
+		m.transcriptOffset -= 15
+		if m.transcriptOffset < 0 {
+			m.transcriptOffset = 0
+		}
+		return m, nil
+
+	case "g":
+		// Handle gg for go to top
+		if m.lastKey == "g" {
+			m.lastKey = ""
+			m.transcriptOffset = 0
+			return m, nil
+		}
+		m.lastKey = "g"
+		return m, nil
+
+	case "G":
+		// Go to bottom (set to large value, will be clamped in render)
+		m.lastKey = ""
+		m.transcriptOffset = 10000
+		return m, nil
+
+	case "n", "p":
+		// Switch to the next/previous attached transcript
+		m.lastKey = ""
+		if m.editingBall == nil {
+			return m, nil
+		}
+		index := m.transcriptIndex + 1
+		if msg.String() == "p" {
+			index = m.transcriptIndex - 1
+		}
+		if index < 0 || index >= len(m.editingBall.Transcripts) {
+			return m, nil
+		}
+		m.transcriptIndex = index
+		m.transcriptContent = "Loading..."
+		m.transcriptOffset = 0
+		return m, loadTranscript(m.editingBall, index)
+	}
+
+	// Reset gg detection for any other key
+	m.lastKey = ""
+	return m, nil
+}
@@ -10,7 +10,9 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/agentlog"
 	"github.com/ohare93/juggle/internal/session"
+	"github.com/ohare93/juggle/internal/watcher"
 )
 
 // Testing Note: When creating a Model for tests that render views,
@@ -68,6 +70,10 @@ func TestTruncate(t *testing.T) {
 		{"exactly10!", 10, "exactly10!"},
 		{"this is way too long", 10, "this is..."},
 		{"", 5, ""},
+		{"修复登录页面的布局问题", 10, "修复登..."},
+		{"修复登录页面", 12, "修复登录页面"},
+		{"café déjà vu", 12, "café déjà vu"},
+		{"deploy 👩‍💻👩‍💻👩‍💻", 12, "deploy 👩‍💻..."},
 	}
 
 	for _, tt := range tests {
@@ -203,6 +209,36 @@ func TestApplyFiltersAll(t *testing.T) {
 	}
 }
 
+func TestMilestoneFilterCycles(t *testing.T) {
+	model := Model{
+		balls: []*session.Ball{
+			{ID: "1", State: session.StatePending, Milestone: "v2.0"},
+			{ID: "2", State: session.StatePending, Milestone: "v1.2"},
+			{ID: "3", State: session.StatePending},
+			{ID: "4", State: session.StateInProgress, Milestone: "v1.2"},
+		},
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"complete":    false,
+			"blocked":     false,
+		},
+		activityLog: make([]ActivityEntry, 0),
+	}
+
+	want := []struct {
+		milestone string
+		count     int
+	}{{"v1.2", 2}, {"v2.0", 1}, {"", 4}}
+	for _, w := range want {
+		newModel, _ := model.handleToggleKeySequence("m")
+		model = newModel.(Model)
+		if model.filterMilestone != w.milestone || len(model.filteredBalls) != w.count {
+			t.Errorf("Expected milestone %q with %d balls, got %q with %d", w.milestone, w.count, model.filterMilestone, len(model.filteredBalls))
+		}
+	}
+}
+
 func TestFilterToggleBehavior(t *testing.T) {
 	model := Model{
 		filterStates: map[string]bool{
@@ -762,6 +798,7 @@ func TestFilterSessions(t *testing.T) {
 
 	t.Run("no filter returns all sessions with pseudo-sessions", func(t *testing.T) {
 		model := Model{
+			localOnly:         true,
 			sessions:          sessions,
 			panelSearchActive: false,
 		}
@@ -1721,6 +1758,7 @@ func TestFilterAppliedShowsCorrectMessage(t *testing.T) {
 // Test filterSessions returns all sessions when filter cleared
 func TestFilterSessionsReturnsAllWhenCleared(t *testing.T) {
 	model := Model{
+		localOnly:         true,
 		panelSearchQuery:  "",
 		panelSearchActive: false,
 		sessions: []*session.JuggleSession{
@@ -1772,6 +1810,52 @@ func TestFilterBallsReturnsAllWhenCleared(t *testing.T) {
 	}
 }
 
+// Test the balls panel's balls are cached until the balls or view settings change
+func TestFilterBallsForSessionCached(t *testing.T) {
+	ids := func(balls []*session.Ball) []string {
+		result := make([]string, len(balls))
+		for i, ball := range balls {
+			result[i] = ball.ID
+		}
+		return result
+	}
+	model := InitialSplitModel(nil, nil, nil, true)
+	model.selectedSession = &session.JuggleSession{ID: PseudoSessionAll}
+	model.sortOrder = SortByPriorityDESC
+	model.balls = []*session.Ball{
+		{ID: "ball-1", Title: "Low", Priority: session.PriorityLow, State: session.StatePending},
+		{ID: "ball-2", Title: "Urgent", Priority: session.PriorityUrgent, State: session.StatePending},
+		{ID: "ball-3", Title: "Medium", Priority: session.PriorityMedium, State: session.StatePending},
+	}
+	model.applyFilters()
+
+	first := model.filterBallsForSession()
+	if len(first) != 3 || first[0].ID != "ball-2" {
+		t.Fatalf("Expected 3 balls, urgent first, got %v", ids(first))
+	}
+	if again := model.filterBallsForSession(); &again[0] != &first[0] {
+		t.Error("Expected the sorted balls to be reused until something changes")
+	}
+
+	model.sortOrder = SortByIDASC
+	if balls := model.filterBallsForSession(); balls[0].ID != "ball-1" {
+		t.Errorf("Expected a new sort order to resort, got %v", ids(balls))
+	}
+
+	model.panelSearchActive = true
+	model.panelSearchQuery = "med"
+	if balls := model.filterBallsForSession(); len(balls) != 1 || balls[0].ID != "ball-3" {
+		t.Errorf("Expected the search to filter, got %v", ids(balls))
+	}
+	model.panelSearchActive = false
+
+	model.balls = append(model.balls, &session.Ball{ID: "ball-4", Title: "New", Priority: session.PriorityHigh, State: session.StatePending})
+	model.applyFilters()
+	if balls := model.filterBallsForSession(); len(balls) != 4 || balls[3].ID != "ball-4" {
+		t.Errorf("Expected reloaded balls to be picked up, got %v", ids(balls))
+	}
+}
+
 // Test activity log gets entry when filter is cleared via empty input
 func TestActivityLogUpdatedOnFilterClear(t *testing.T) {
 	model := Model{
@@ -1996,6 +2080,86 @@ func TestToggleSortOrder(t *testing.T) {
 	}
 }
 
+// TestTUIStateRestore tests that remembered state survives a State/RestoreState round trip
+func TestTUIStateRestore(t *testing.T) {
+	model := InitialSplitModel(nil, nil, nil, true)
+	model.activePanel = ActivityPanel
+	model.bottomPaneMode = BottomPaneDetail
+	model.sortOrder = SortByPriorityDESC
+	model.filterStates["complete"] = true
+	model.showTagsColumn = true
+	model.cursor = 4
+	model.ballsScrollOffset = 2
+	model.selectedSession = &session.JuggleSession{ID: "feature"}
+
+	restored := InitialSplitModel(nil, nil, nil, true)
+	restored.RestoreState(model.State())
+
+	if restored.initialSessionID != "feature" {
+		t.Errorf("Expected session feature to be pre-selected, got %q", restored.initialSessionID)
+	}
+	if restored.activePanel != ActivityPanel || restored.bottomPaneMode != BottomPaneDetail {
+		t.Errorf("Expected panel and bottom pane restored, got %v / %v", restored.activePanel, restored.bottomPaneMode)
+	}
+	if restored.sortOrder != SortByPriorityDESC {
+		t.Errorf("Expected priority sort restored, got %v", restored.sortOrder)
+	}
+	if !restored.filterStates["complete"] || !restored.showTagsColumn || restored.showPriorityColumn {
+		t.Errorf("Expected filters and columns restored, got %v tags=%v priority=%v", restored.filterStates, restored.showTagsColumn, restored.showPriorityColumn)
+	}
+	if restored.cursor != 4 || restored.ballsScrollOffset != 2 {
+		t.Errorf("Expected cursor and scroll restored, got %d / %d", restored.cursor, restored.ballsScrollOffset)
+	}
+
+	// --session wins over the remembered session
+	flagged := InitialSplitModelWithWatcher(nil, nil, nil, true, nil, "from-flag")
+	flagged.RestoreState(model.State())
+	if flagged.initialSessionID != "from-flag" {
+		t.Errorf("Expected --session to win, got %q", flagged.initialSessionID)
+	}
+}
+
+// TestLayoutCycle tests cycling layouts and board grouping of balls
+func TestLayoutCycle(t *testing.T) {
+	model := InitialSplitModel(nil, nil, nil, true)
+	model.activePanel = SessionsPanel
+
+	newModel, _ := model.handleCycleLayout()
+	m := newModel.(Model)
+	if m.layout != LayoutList {
+		t.Fatalf("Expected list layout, got %v", m.layout)
+	}
+	if m.activePanel != BallsPanel {
+		t.Errorf("Expected hidden sessions panel to lose focus, got %v", m.activePanel)
+	}
+
+	newModel, _ = m.handleCycleLayout()
+	m = newModel.(Model)
+	if m.layout != LayoutBoard {
+		t.Fatalf("Expected board layout, got %v", m.layout)
+	}
+
+	m.balls = []*session.Ball{
+		{ID: "p-1", State: session.StateBlocked},
+		{ID: "p-2", State: session.StatePending},
+		{ID: "p-3", State: session.StateInProgress},
+		{ID: "p-4", State: session.StatePending},
+	}
+	m.applyFilters()
+	var ids []string
+	for _, ball := range m.filterBallsForSession() {
+		ids = append(ids, ball.ID)
+	}
+	if got := strings.Join(ids, ","); got != "p-2,p-4,p-3,p-1" {
+		t.Errorf("Expected balls grouped by board column, got %s", got)
+	}
+
+	newModel, _ = m.handleCycleLayout()
+	if layout := newModel.(Model).layout; layout != LayoutSplit {
+		t.Errorf("Expected cycle back to split, got %v", layout)
+	}
+}
+
 // Test sorting balls by ID ascending
 func TestSortBallsByIDAscending(t *testing.T) {
 	balls := []*session.Ball{
@@ -2208,6 +2372,118 @@ func TestSortBallsByLastActivityAsc(t *testing.T) {
 	}
 }
 
+// Test balls equal on the sort order get the same order whatever order they
+// arrive in: priority, then focused, then ID
+func TestSortBallsTiebreaks(t *testing.T) {
+	same := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	balls := []*session.Ball{
+		{ID: "juggle-5", Priority: session.PriorityLow, LastActivity: same},
+		{ID: "juggle-4", Priority: session.PriorityHigh, LastActivity: same},
+		{ID: "juggle-3", Priority: session.PriorityHigh, LastActivity: same, Focused: true},
+		{ID: "juggle-2", Priority: session.PriorityHigh, LastActivity: same},
+		{ID: "juggle-1", Priority: session.PriorityMedium, LastActivity: same.Add(time.Hour)},
+	}
+	expectedOrder := []string{"juggle-1", "juggle-3", "juggle-2", "juggle-4", "juggle-5"}
+
+	model := Model{sortOrder: SortByLastActivityDESC}
+	for _, order := range [][]int{{0, 1, 2, 3, 4}, {4, 3, 2, 1, 0}, {2, 0, 4, 1, 3}} {
+		shuffled := make([]*session.Ball, len(balls))
+		for i, j := range order {
+			shuffled[i] = balls[j]
+		}
+		model.sortBalls(shuffled)
+		for i, ball := range shuffled {
+			if ball.ID != expectedOrder[i] {
+				t.Errorf("Input order %v: expected ball at index %d to be %q, got %q", order, i, expectedOrder[i], ball.ID)
+			}
+		}
+	}
+}
+
+// Test custom sort expressions from config
+func TestSortBallsByCustomExpression(t *testing.T) {
+	baseTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	balls := []*session.Ball{
+		{ID: "juggle-1", Priority: session.PriorityHigh, StartedAt: baseTime.Add(2 * time.Hour)},
+		{ID: "juggle-2", Priority: session.PriorityUrgent, StartedAt: baseTime.Add(3 * time.Hour)},
+		{ID: "juggle-3", Priority: session.PriorityHigh, StartedAt: baseTime.Add(1 * time.Hour)},
+		{ID: "juggle-4", Priority: session.PriorityLow, StartedAt: baseTime},
+	}
+
+	config := &session.Config{SortOrders: []session.NamedSortOrder{{Name: "triage", Expr: "priority desc, age desc"}}}
+	order, custom, err := ResolveSort("triage", config)
+	if err != nil || order != SortByCustom {
+		t.Fatalf("Expected triage to resolve to a custom sort, got %v %v", order, err)
+	}
+
+	model := Model{sortOrder: order, customSort: custom}
+	model.sortBalls(balls)
+
+	// Urgent first, then the older of the two high priority balls
+	expectedOrder := []string{"juggle-2", "juggle-3", "juggle-1", "juggle-4"}
+	for i, ball := range balls {
+		if ball.ID != expectedOrder[i] {
+			t.Errorf("Expected ball at index %d to be %q, got %q", i, expectedOrder[i], ball.ID)
+		}
+	}
+	if got := model.sortIndicator(); got != " [↓triage]" {
+		t.Errorf("Expected sort indicator [↓triage], got %q", got)
+	}
+
+	// Inline expressions resolve too; unknown fields are rejected
+	if _, custom, err := ResolveSort("state, title", nil); err != nil || custom.Name != "state, title" {
+		t.Errorf("Expected inline expression to resolve, got %+v %v", custom, err)
+	}
+	if _, _, err := ResolveSort("due_date asc", config); err == nil {
+		t.Error("Expected error for unknown sort field")
+	}
+}
+
+// Test the sort cycle visits custom sort orders after the built-in ones
+func TestToggleSortOrderCustom(t *testing.T) {
+	config := &session.Config{SortOrders: []session.NamedSortOrder{
+		{Name: "triage", Expr: "priority desc, age desc"},
+		{Name: "stale", Expr: "activity"},
+	}}
+	model := Model{
+		config:      config,
+		sortOrder:   SortByCreatedAtASC,
+		activityLog: make([]ActivityEntry, 0),
+	}
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		newModel, _ := model.handleToggleSortOrder()
+		model = newModel.(Model)
+		name := "id"
+		if model.sortOrder == SortByCustom {
+			name = model.customSort.Name
+		}
+		names = append(names, name)
+	}
+	if strings.Join(names, ",") != "triage,stale,id" {
+		t.Errorf("Expected cycle triage,stale,id, got %v", names)
+	}
+	if model.customSort != nil {
+		t.Error("Expected custom sort cleared after wrapping to ID ascending")
+	}
+
+	// The custom sort is remembered by name across launches
+	newModel, _ := model.handleToggleSortOrder() // ID descending
+	model = newModel.(Model)
+	model.sortOrder = SortByCustom
+	model.customSort = customSortOrders(config)[1]
+	state := model.State()
+	if state.Sort != "stale" {
+		t.Errorf("Expected state sort stale, got %q", state.Sort)
+	}
+	restored := Model{config: config, filterStates: map[string]bool{}}
+	restored.RestoreState(state)
+	if restored.sortOrder != SortByCustom || restored.customSort == nil || restored.customSort.Name != "stale" {
+		t.Errorf("Expected stale sort restored, got %v %+v", restored.sortOrder, restored.customSort)
+	}
+}
+
 // Test filterBallsForSession applies sorting
 func TestFilterBallsForSessionAppliesSorting(t *testing.T) {
 	balls := []*session.Ball{
@@ -3338,7 +3614,7 @@ func TestStatusBarSessionsPanel(t *testing.T) {
 	statusBar := model.renderStatusBar()
 
 	// Should contain session-specific keybinds
-	expectedKeys := []string{"j/k:nav", "Enter:select", "a:add", "A:agent", "e:edit", "d:del", "/:filter", "P:scope", "?:help", "q:quit"}
+	expectedKeys := []string{"j/k:nav", "Enter:select", "a:add", "e:edit", "d:del", "/:filter", "P:scope", "?:help", "q:quit"}
 	for _, key := range expectedKeys {
 		if !strings.Contains(statusBar, key) {
 			t.Errorf("Expected status bar to contain '%s', got: %s", key, statusBar)
@@ -3364,7 +3640,7 @@ func TestStatusBarBallsPanel(t *testing.T) {
 	statusBar := model.renderStatusBar()
 
 	// Should contain ball-specific keybinds including two-key state change and filter keys
-	expectedKeys := []string{"j/k:nav", "s+c/s/b/p:state", "t+c/b/i/p:filter", "a:add", "e:edit", "d:del", "v+p/t/s/m:columns", "[/]:session", "o:sort", "?:help"}
+	expectedKeys := []string{"j/k:nav", "s+c/s/b/p/a/r:state", "t+c/b/i/p/m/a:filter", "a:add", "e:edit", "d:del", "v+p/t/m/s/a/v:columns", "[/]:session", "o:sort", "?:help"}
 	for _, key := range expectedKeys {
 		if !strings.Contains(statusBar, key) {
 			t.Errorf("Expected status bar to contain '%s', got: %s", key, statusBar)
@@ -3555,7 +3831,7 @@ func TestHelpViewContainsViewOptionsBindings(t *testing.T) {
 	model := Model{
 		mode:   splitHelpView,
 		width:  120,
-		height: 80, // Increased to show all content
+		height: 100, // Increased to show all content
 	}
 
 	helpView := model.renderSplitHelpView()
@@ -3787,6 +4063,7 @@ func TestEnterOnSessionWithDifferentCursor(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:     true,
 		mode:          splitView,
 		activePanel:   SessionsPanel,
 		sessionCursor: 3, // Fourth in filtered list = session2 (after __all__, __untagged__, session1)
@@ -3931,6 +4208,7 @@ func TestBracketLeftSwitchesToPrevSession(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:       true,
 		mode:            splitView,
 		activePanel:     BallsPanel,
 		sessionCursor:   4,           // At session3 (index 4 in filtered list)
@@ -3978,6 +4256,7 @@ func TestBracketRightSwitchesToNextSession(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:       true,
 		mode:            splitView,
 		activePanel:     BallsPanel,
 		sessionCursor:   2,           // At session1 (index 2 in filtered list)
@@ -4062,6 +4341,7 @@ func TestBracketRightAtBoundary(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:       true,
 		mode:            splitView,
 		activePanel:     BallsPanel,
 		sessionCursor:   3,           // At session2 (last, index 3 in filtered list)
@@ -4142,6 +4422,7 @@ func TestSessionSwitchUpdatesActivity(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:       true,
 		mode:            splitView,
 		activePanel:     BallsPanel,
 		sessionCursor:   2,           // At session1 (index 2 in filtered list)
@@ -4258,6 +4539,7 @@ func TestCompleteEnterTabWorkflow(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:     true,
 		mode:          splitView,
 		activePanel:   SessionsPanel,
 		sessionCursor: 2, // session1 (index 2 in filtered list)
@@ -4320,6 +4602,7 @@ func TestCompleteSessionSwitchWorkflow(t *testing.T) {
 	}
 
 	model := Model{
+		localOnly:       true,
 		mode:            splitView,
 		activePanel:     BallsPanel,
 		sessionCursor:   3,           // session2 (index 3 in filtered list)
@@ -5572,7 +5855,7 @@ func TestHelpViewContainsAgentCancelKeybind(t *testing.T) {
 	model := Model{
 		mode:   splitHelpView,
 		width:  120,
-		height: 100, // Large enough to show all content
+		height: 110, // Large enough to show all content
 	}
 
 	helpView := model.renderSplitHelpView()
@@ -5922,6 +6205,60 @@ func TestHistoryOutputViewClose(t *testing.T) {
 	}
 }
 
+func TestHistoryOutputViewPagesAgentLog(t *testing.T) {
+	dir := t.TempDir()
+	logWriter, err := agentlog.Create(dir, "auth", time.Now(), agentlog.Options{})
+	if err != nil {
+		t.Fatalf("Failed to create agent log: %v", err)
+	}
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(logWriter, "log line %d\n", i)
+	}
+	logWriter.Close()
+
+	// The log is preferred over last_output.txt
+	record := &session.AgentRunRecord{ID: "1", SessionID: "auth", LogFile: logWriter.Path(), OutputFile: filepath.Join(dir, "missing.txt")}
+	msg := loadHistoryOutput(record)()
+	model := Model{mode: historyView, height: 30, agentHistory: []*session.AgentRunRecord{record}}
+	newModel, _ := model.Update(msg)
+	m := newModel.(Model)
+	if m.mode != historyOutputView || m.historyLog == nil {
+		t.Fatalf("Expected the run's log opened in the output view, got mode %v", m.mode)
+	}
+
+	// G jumps to the end of the log, past the old fixed bottom offset
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'G'}})
+	m = newModel.(Model)
+	view := m.renderHistoryOutputView()
+	if !strings.Contains(view, "log line 4999") || strings.Contains(view, "log line 100\n") {
+		t.Errorf("Expected the last page of the log, got:\n%s", view)
+	}
+	if !strings.Contains(view, "lines above") {
+		t.Error("Expected a scroll indicator for the lines above")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = newModel.(Model)
+	if m.historyLog != nil {
+		t.Error("Expected the log closed when leaving the output view")
+	}
+}
+
+// A pruned log falls back to the last iteration's output
+func TestHistoryOutputFallsBackWithoutLog(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "last_output.txt")
+	if err := os.WriteFile(outputFile, []byte("last iteration"), 0644); err != nil {
+		t.Fatalf("Failed to write output: %v", err)
+	}
+	record := &session.AgentRunRecord{ID: "1", LogFile: filepath.Join(dir, "pruned.log"), OutputFile: outputFile}
+
+	msg, ok := loadHistoryOutput(record)().(historyOutputLoadedMsg)
+	if !ok || msg.log != nil || msg.content != "last iteration" {
+		t.Errorf("Expected last_output.txt content, got %+v", msg)
+	}
+}
+
 func TestHistoryLoadedMsgHandler(t *testing.T) {
 	model := Model{
 		mode: splitView,
@@ -6575,6 +6912,32 @@ func TestAgentFinishedMsgClosesChannel(t *testing.T) {
 	}
 }
 
+// Test the agent output panel is mirrored to the output log, status markers included
+func TestAgentOutputMirror(t *testing.T) {
+	var mirror strings.Builder
+	model := Model{
+		mode:        splitView,
+		activePanel: BallsPanel,
+		agentStatus: AgentStatus{Running: true},
+	}
+	model.SetAgentOutputMirror(&mirror)
+
+	newModel, _ := model.Update(agentOutputMsg{line: "Working on auth-1"})
+	newModel, _ = newModel.(Model).Update(agentOutputMsg{line: "permission denied", isError: true})
+	newModel, _ = newModel.(Model).Update(agentFinishedMsg{sessionID: "test", complete: true})
+	m := newModel.(Model)
+
+	lines := strings.Split(strings.TrimSuffix(mirror.String(), "\n"), "\n")
+	if len(lines) != len(m.agentOutput) {
+		t.Fatalf("Expected every panel line mirrored, got %q", mirror.String())
+	}
+	for i, want := range []string{"Working on auth-1", "permission denied", "=== Agent completed ==="} {
+		if !strings.HasSuffix(lines[i], " "+want) {
+			t.Errorf("Expected mirrored line %d to end with %q, got %q", i, want, lines[i])
+		}
+	}
+}
+
 // Test agentCancelledMsg closes and nils output channel
 func TestAgentCancelledMsgClosesChannel(t *testing.T) {
 	ch := make(chan agentOutputMsg, 1)
@@ -6631,9 +6994,65 @@ func TestTwoKeySequence_S_StartsPendingSequence(t *testing.T) {
 		t.Errorf("Expected pendingKeySequence to be 's', got '%s'", m.pendingKeySequence)
 	}
 
-	// Check that message shows hint
-	if !strings.Contains(m.message, "State change") {
-		t.Errorf("Expected message to contain 'State change', got '%s'", m.message)
+	// Check that the footer lists the second keys
+	statusBar := m.renderStatusBar()
+	for _, hint := range []string{"s+ state:", "c:complete", "s:start", "b:block", "p:pending", "a:archive", "Esc:cancel"} {
+		if !strings.Contains(statusBar, hint) {
+			t.Errorf("Expected status bar to contain '%s', got: %s", hint, statusBar)
+		}
+	}
+}
+
+// Test a pending sequence is cancelled if the second key doesn't come in time
+func TestTwoKeySequence_TimesOut(t *testing.T) {
+	model := InitialSplitModel(nil, nil, &session.Config{KeySequences: &session.KeySequenceConfig{TimeoutMs: 1}}, true)
+	model.activePanel = BallsPanel
+
+	newModel, cmd := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m := newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected starting a sequence to arm its timeout")
+	}
+	timeout, ok := cmd().(keySequenceTimeoutMsg)
+	if !ok {
+		t.Fatalf("Expected a keySequenceTimeoutMsg, got %T", cmd())
+	}
+
+	// A timeout armed for an earlier sequence leaves a newer one alone
+	newModel, _ = m.Update(keySequenceTimeoutMsg{id: timeout.id - 1})
+	if m = newModel.(Model); m.pendingKeySequence != "s" {
+		t.Errorf("Expected a stale timeout to be ignored, got pending %q", m.pendingKeySequence)
+	}
+
+	newModel, _ = m.Update(timeout)
+	if m = newModel.(Model); m.pendingKeySequence != "" {
+		t.Errorf("Expected the sequence to time out, got pending %q", m.pendingKeySequence)
+	}
+	if statusBar := m.renderStatusBar(); strings.Contains(statusBar, "s+ state:") {
+		t.Errorf("Expected the footer back to normal, got: %s", statusBar)
+	}
+}
+
+// Test the which-key popup lists the second keys in the bottom pane
+func TestTwoKeySequence_WhichKey(t *testing.T) {
+	config := &session.Config{}
+	model := InitialSplitModel(nil, nil, config, true)
+	model.activePanel = BallsPanel
+	model.width = 120
+	model.height = 40
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m := newModel.(Model)
+	if strings.Contains(m.renderSplitView(), "Toggle blocked balls visibility") {
+		t.Error("Expected no popup unless which-key is turned on")
+	}
+
+	config.KeySequences = &session.KeySequenceConfig{WhichKey: true}
+	view := m.renderSplitView()
+	for _, want := range []string{"t + key: filter", "Toggle blocked balls visibility", "Cycle milestone filter", "Esc cancel"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the popup to contain %q, got:\n%s", want, view)
+		}
 	}
 }
 
@@ -6650,9 +7069,106 @@ func TestTwoKeySequence_T_StartsPendingSequence(t *testing.T) {
 		t.Errorf("Expected pendingKeySequence to be 't', got '%s'", m.pendingKeySequence)
 	}
 
-	// Check that message shows hint
-	if !strings.Contains(m.message, "Toggle filter") {
-		t.Errorf("Expected message to contain 'Toggle filter', got '%s'", m.message)
+	// Check that the footer lists the second keys
+	statusBar := m.renderStatusBar()
+	for _, hint := range []string{"t+ filter:", "c:complete", "i:in_progress", "a:all"} {
+		if !strings.Contains(statusBar, hint) {
+			t.Errorf("Expected status bar to contain '%s', got: %s", hint, statusBar)
+		}
+	}
+}
+
+// Test 'na' sets the next action of the highlighted ball, and an empty
+// value clears it
+func TestTwoKeySequence_NA_SetsNextAction(t *testing.T) {
+	ball := &session.Ball{ID: "test-1", Title: "Fix login", State: session.StatePending, Priority: session.PriorityMedium, WorkingDir: t.TempDir()}
+	model := Model{
+		mode:          splitView,
+		activePanel:   BallsPanel,
+		filteredBalls: []*session.Ball{ball},
+		textInput:     textinput.New(),
+		activityLog:   make([]ActivityEntry, 0),
+		filterStates:  map[string]bool{"pending": true},
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m := newModel.(Model)
+	if m.pendingKeySequence != "n" {
+		t.Fatalf("Expected pendingKeySequence 'n', got '%s'", m.pendingKeySequence)
+	}
+	newModel, _ = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m = newModel.(Model)
+	if m.mode != inputNextActionView || m.editingBall != ball {
+		t.Fatalf("Expected next action input for %s, got mode %v", ball.ID, m.mode)
+	}
+
+	m.textInput.SetValue("Reproduce with the failing fixture")
+	newModel, cmd := m.handleInputKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || cmd == nil {
+		t.Errorf("Expected to return to split view with a save command, got mode %v", m.mode)
+	}
+	if ball.NextAction != "Reproduce with the failing fixture" {
+		t.Errorf("Expected next action set, got %q", ball.NextAction)
+	}
+	if line := m.renderBallLine(ball, "1", false, 100); !strings.Contains(line, "-> Reproduce") {
+		t.Errorf("Expected next action in ball line, got: %s", line)
+	}
+
+	// The input is prefilled with the current next action
+	newModel, _ = m.handleSplitNextAction()
+	m = newModel.(Model)
+	if m.textInput.Value() != ball.NextAction {
+		t.Errorf("Expected input prefilled with %q, got %q", ball.NextAction, m.textInput.Value())
+	}
+	m.textInput.SetValue("")
+	newModel, _ = m.handleInputKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if ball.NextAction != "" || m.message != "Cleared next action: test-1" {
+		t.Errorf("Expected next action cleared, got %q (%s)", ball.NextAction, m.message)
+	}
+}
+
+// Test 'nc' sets the checkpoint of the highlighted ball, shown as a badge on
+// its state, and is refused for finished balls
+func TestTwoKeySequence_NC_SetsCheckpoint(t *testing.T) {
+	ball := &session.Ball{ID: "test-1", Title: "Fix login", State: session.StateInProgress, Priority: session.PriorityMedium, WorkingDir: t.TempDir()}
+	done := &session.Ball{ID: "test-2", Title: "Done", State: session.StateComplete, Priority: session.PriorityMedium, WorkingDir: t.TempDir()}
+	model := Model{
+		mode:          splitView,
+		activePanel:   BallsPanel,
+		filteredBalls: []*session.Ball{ball, done},
+		textInput:     textinput.New(),
+		activityLog:   make([]ActivityEntry, 0),
+		filterStates:  map[string]bool{"in_progress": true, "complete": true},
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m := newModel.(Model)
+	newModel, _ = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = newModel.(Model)
+	if m.mode != inputCheckpointView || m.editingBall != ball {
+		t.Fatalf("Expected checkpoint input for %s, got mode %v", ball.ID, m.mode)
+	}
+
+	m.textInput.SetValue("tests written")
+	newModel, cmd := m.handleInputKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || cmd == nil {
+		t.Errorf("Expected to return to split view with a save command, got mode %v", m.mode)
+	}
+	if ball.Checkpoint != "tests written" {
+		t.Errorf("Expected checkpoint set, got %q", ball.Checkpoint)
+	}
+	if line := m.renderBallLine(ball, "1", false, 100); !strings.Contains(line, "in_progress [tests written]") {
+		t.Errorf("Expected checkpoint badge in ball line, got: %s", line)
+	}
+
+	m.cursor = 1
+	newModel, _ = m.handleSplitCheckpoint()
+	m = newModel.(Model)
+	if m.mode != splitView || !strings.Contains(m.message, "checkpoints are for unfinished work") {
+		t.Errorf("Expected completed ball refused, got mode %v (%s)", m.mode, m.message)
 	}
 }
 
@@ -7412,7 +7928,7 @@ func TestViewColumnToggle_UnknownKey(t *testing.T) {
 	newModel, _ := model.handleViewColumnKeySequence("x")
 	m := newModel.(Model)
 
-	if m.message != "Unknown view column: x (use p/t/m/a)" {
+	if m.message != "Unknown view column: x (use p/t/m/s/a/v)" {
 		t.Errorf("Expected error message, got '%s'", m.message)
 	}
 }
@@ -7842,57 +8358,143 @@ func TestUnifiedBallFormSessionSelection(t *testing.T) {
 		t.Errorf("Expected session to be 0 (none) after left, got %d", m.pendingBallSession)
 	}
 
-	// Test wrap around (3 options: none, session-1, session-2)
+	// Test wrap around (4 options: none, session-1, session-2, create new…)
 	m.pendingBallSession = 0 // (none)
 	newModel, _ = m.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyLeft})
 	m = newModel.(Model)
-	if m.pendingBallSession != 2 {
-		t.Errorf("Expected session to wrap to 2 (session-2) after left from none, got %d", m.pendingBallSession)
+	if m.pendingBallSession != 3 {
+		t.Errorf("Expected session to wrap to 3 (create new…) after left from none, got %d", m.pendingBallSession)
 	}
 }
 
-// Test adding acceptance criteria in unified form
-// Field order: Context(0), Title(1), ACs(2+), Tags, Session, ModelSize, DependsOn
-func TestUnifiedBallFormAddAC(t *testing.T) {
+// Test creating a session inline from the unified ball form's session field
+func TestUnifiedBallFormCreateSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := session.NewStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	sessionStore, err := session.NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	existing, err := sessionStore.CreateSession("session-1", "")
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
 	ti := textinput.New()
 	ti.CharLimit = 256
 	ti.Width = 40
-	ti.Focus()
 
 	model := Model{
 		mode:                      unifiedBallFormView,
-		pendingBallIntent:         "Test intent",
+		store:                     store,
+		sessionStore:              sessionStore,
+		pendingBallIntent:         "Test",
 		pendingBallPriority:       1,
-		pendingBallTags:           "",
-		pendingBallSession:        0,
-		pendingBallFormField:      2, // On the "new AC" field (index 2 = first AC position, after context and title)
+		pendingBallFormField:      4, // on session field (with no ACs)
 		pendingAcceptanceCriteria: []string{},
 		textInput:                 ti,
-		sessions:                  []*session.JuggleSession{},
+		sessions:                  []*session.JuggleSession{existing},
 		activityLog:               make([]ActivityEntry, 0),
 	}
-	model.textInput.SetValue("New acceptance criterion")
 
-	// Press enter to add the AC
-	newModel, _ := model.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyEnter})
+	// Left from (none) wraps to "create new…", which takes text input
+	newModel, _ := model.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyLeft})
 	m := newModel.(Model)
-
-	if len(m.pendingAcceptanceCriteria) != 1 {
-		t.Errorf("Expected 1 AC after adding, got %d", len(m.pendingAcceptanceCriteria))
+	if m.pendingBallSession != 2 || !m.textInput.Focused() {
+		t.Fatalf("Expected focused text input on create option, got session %d", m.pendingBallSession)
 	}
-	if m.pendingAcceptanceCriteria[0] != "New acceptance criterion" {
-		t.Errorf("Expected AC to be 'New acceptance criterion', got '%s'", m.pendingAcceptanceCriteria[0])
+	if !strings.Contains(m.View(), "new-session-id") {
+		t.Errorf("Expected new session prompt in form view")
 	}
 
-	// Should stay on new AC field for adding more (after adding 1 AC, new AC is at index 3)
-	if m.pendingBallFormField != 3 {
-		t.Errorf("Expected to stay on new AC field (3), got %d", m.pendingBallFormField)
+	m.textInput.SetValue("new-work: Inline created")
+	newModel, cmd := m.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Error("Expected sessions reload after creating session")
 	}
-}
-
-// Test editing existing AC in unified form
-// Field order: Context(0), Title(1), ACs(2+), Tags, Session, ModelSize, DependsOn
-func TestUnifiedBallFormEditAC(t *testing.T) {
+	if m.formSessionID() != "new-work" {
+		t.Errorf("Expected new session selected, got %q (index %d)", m.formSessionID(), m.pendingBallSession)
+	}
+	if m.pendingBallFormField != 5 {
+		t.Errorf("Expected to move to next field (5), got %d", m.pendingBallFormField)
+	}
+	created, err := sessionStore.LoadSession("new-work")
+	if err != nil {
+		t.Fatalf("Expected session on disk: %v", err)
+	}
+	if created.Description != "Inline created" {
+		t.Errorf("Expected description 'Inline created', got %q", created.Description)
+	}
+
+	// Reloading sessions reorders them but keeps the selection
+	sessions, err := sessionStore.ListSessions()
+	if err != nil {
+		t.Fatalf("failed to list sessions: %v", err)
+	}
+	newModel, _ = m.Update(sessionsLoadedMsg{sessions: sessions})
+	m = newModel.(Model)
+	if m.formSessionID() != "new-work" {
+		t.Errorf("Expected selection kept after reload, got %q", m.formSessionID())
+	}
+
+	// Saving tags the ball with the new session
+	newModel, _ = m.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	balls, err := store.LoadBalls()
+	if err != nil || len(balls) != 1 {
+		t.Fatalf("Expected 1 ball, got %d (err %v)", len(balls), err)
+	}
+	if len(balls[0].Tags) != 1 || balls[0].Tags[0] != "new-work" {
+		t.Errorf("Expected ball tagged with new session, got %v", balls[0].Tags)
+	}
+}
+
+// Test adding acceptance criteria in unified form
+// Field order: Context(0), Title(1), ACs(2+), Tags, Session, ModelSize, DependsOn
+func TestUnifiedBallFormAddAC(t *testing.T) {
+	ti := textinput.New()
+	ti.CharLimit = 256
+	ti.Width = 40
+	ti.Focus()
+
+	model := Model{
+		mode:                      unifiedBallFormView,
+		pendingBallIntent:         "Test intent",
+		pendingBallPriority:       1,
+		pendingBallTags:           "",
+		pendingBallSession:        0,
+		pendingBallFormField:      2, // On the "new AC" field (index 2 = first AC position, after context and title)
+		pendingAcceptanceCriteria: []string{},
+		textInput:                 ti,
+		sessions:                  []*session.JuggleSession{},
+		activityLog:               make([]ActivityEntry, 0),
+	}
+	model.textInput.SetValue("New acceptance criterion")
+
+	// Press enter to add the AC
+	newModel, _ := model.handleUnifiedBallFormKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m := newModel.(Model)
+
+	if len(m.pendingAcceptanceCriteria) != 1 {
+		t.Errorf("Expected 1 AC after adding, got %d", len(m.pendingAcceptanceCriteria))
+	}
+	if m.pendingAcceptanceCriteria[0] != "New acceptance criterion" {
+		t.Errorf("Expected AC to be 'New acceptance criterion', got '%s'", m.pendingAcceptanceCriteria[0])
+	}
+
+	// Should stay on new AC field for adding more (after adding 1 AC, new AC is at index 3)
+	if m.pendingBallFormField != 3 {
+		t.Errorf("Expected to stay on new AC field (3), got %d", m.pendingBallFormField)
+	}
+}
+
+// Test editing existing AC in unified form
+// Field order: Context(0), Title(1), ACs(2+), Tags, Session, ModelSize, DependsOn
+func TestUnifiedBallFormEditAC(t *testing.T) {
 	ti := textinput.New()
 	ti.CharLimit = 256
 	ti.Width = 40
@@ -9006,6 +9608,12 @@ func TestWrapText(t *testing.T) {
 			maxWidth: 60,
 			expected: "",
 		},
+		{
+			name:     "CJK text breaks between characters",
+			text:     "修复登录页面的布局问题 and more",
+			maxWidth: 10,
+			expected: "修复登录页\n面的布局问\n题 and\nmore",
+		},
 	}
 
 	for _, tt := range tests {
@@ -9144,6 +9752,59 @@ func TestCopyBallID_SplitView(t *testing.T) {
 	}
 }
 
+// TestToggleFocus tests that 'f' flags the ball for the next agent run and saves it
+func TestToggleFocus(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	ball, _ := session.NewBall(projectDir, "Tidy README", session.PriorityLow)
+	done, _ := session.NewBall(projectDir, "Shipped", session.PriorityLow)
+	done.MarkComplete("")
+	for _, b := range []*session.Ball{ball, done} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+	balls := []*session.Ball{ball, done}
+
+	model := Model{
+		mode:          splitView,
+		activePanel:   BallsPanel,
+		balls:         balls,
+		filteredBalls: balls,
+		activityLog:   make([]ActivityEntry, 0),
+		filterStates:  map[string]bool{"pending": true, "in_progress": true, "blocked": true, "complete": true},
+	}
+	model.reselectBall(ball.ID)
+
+	newModel, cmd := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m := newModel.(Model)
+	if !ball.Focused || cmd == nil {
+		t.Fatal("Expected the ball focused and saved")
+	}
+	cmd()
+	if saved, err := store.GetBallByID(ball.ID); err != nil || !saved.Focused {
+		t.Errorf("Expected focus saved, got %+v (%v)", saved, err)
+	}
+	if !strings.Contains(m.renderBallDetailPanel(100, 30), "next agent run works on this first") {
+		t.Error("Expected focus shown in the detail pane")
+	}
+
+	newModel, _ = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if ball.Focused {
+		t.Error("Expected a second f to clear focus")
+	}
+
+	m = newModel.(Model)
+	m.reselectBall(done.ID)
+	newModel, _ = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if done.Focused || !strings.HasPrefix(newModel.(Model).message, "Can't focus a finished ball") {
+		t.Errorf("Expected finished balls not to be focused, got %q", newModel.(Model).message)
+	}
+}
+
 // TestCopyBallID_SplitView_NoBall tests that 'y' shows error when no ball selected
 func TestCopyBallID_SplitView_NoBall(t *testing.T) {
 	model := Model{
@@ -10106,3 +10767,2124 @@ func TestEditBallClearBlockingReasonAlwaysSetsInProgress(t *testing.T) {
 		t.Errorf("expected BlockedReason to be empty, got '%s'", updatedBall.BlockedReason)
 	}
 }
+
+// TestRenderPlainBoard verifies the plain-text board uses the split view's filtering and sorting
+func TestRenderPlainBoard(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := session.NewStore(tmpDir)
+	sessionStore, _ := session.NewSessionStore(tmpDir)
+
+	if _, err := sessionStore.CreateSession("feature", "Feature work"); err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	low, _ := session.NewBall(tmpDir, "Low priority task", session.PriorityLow)
+	low.Tags = []string{"feature"}
+	urgent, _ := session.NewBall(tmpDir, "Urgent task", session.PriorityUrgent)
+	urgent.Tags = []string{"feature"}
+	blocked, _ := session.NewBall(tmpDir, "Blocked task", session.PriorityMedium)
+	blocked.SetBlocked("waiting on review")
+	done, _ := session.NewBall(tmpDir, "Finished task", session.PriorityHigh)
+	done.State = session.StateComplete
+	for _, b := range []*session.Ball{low, urgent, blocked, done} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("failed to append ball: %v", err)
+		}
+	}
+
+	output, err := RenderPlainBoard(store, sessionStore, nil, true, PlainBoardOptions{SortOrder: SortByPriorityDESC})
+	if err != nil {
+		t.Fatalf("RenderPlainBoard failed: %v", err)
+	}
+
+	for _, want := range []string{"Sessions", "Balls: All [↓Pri]", "Balls: Untagged", "Balls: feature", "(blocked: waiting on review)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Finished task") {
+		t.Errorf("complete balls should be hidden by default, got:\n%s", output)
+	}
+	if strings.Contains(output, "★") {
+		t.Errorf("plain output should not contain decorative icons, got:\n%s", output)
+	}
+
+	featureSection := output[strings.Index(output, "Balls: feature"):]
+	if strings.Index(featureSection, "Urgent task") > strings.Index(featureSection, "Low priority task") {
+		t.Errorf("expected urgent ball before low ball in priority sort, got:\n%s", featureSection)
+	}
+
+	// Single session with complete balls included
+	output, err = RenderPlainBoard(store, sessionStore, nil, true, PlainBoardOptions{SessionID: "Untagged", ShowComplete: true})
+	if err != nil {
+		t.Fatalf("RenderPlainBoard failed: %v", err)
+	}
+	if !strings.Contains(output, "Finished task") {
+		t.Errorf("expected complete ball with ShowComplete, got:\n%s", output)
+	}
+	if strings.Contains(output, "Balls: feature") {
+		t.Errorf("expected only the Untagged session, got:\n%s", output)
+	}
+
+	if _, err := RenderPlainBoard(store, sessionStore, nil, true, PlainBoardOptions{SessionID: "missing"}); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	order, err := ParseSortOrder("priority")
+	if err != nil || order != SortByPriorityDESC {
+		t.Errorf("expected SortByPriorityDESC, got %v (err %v)", order, err)
+	}
+	if _, err := ParseSortOrder("bogus"); err == nil {
+		t.Error("expected error for invalid sort order")
+	}
+}
+
+// TestAgentOutputIterationFolding verifies iteration headers create collapsible sections
+func TestAgentOutputIterationFolding(t *testing.T) {
+	model := Model{
+		agentOutputVisible: true,
+		width:              100,
+		height:             40,
+		activityLog:        make([]ActivityEntry, 0),
+	}
+
+	model.addAgentOutput("starting up", false)
+	model.addAgentOutput("════ Iteration 1/3 ════", false)
+	model.addAgentOutput("first line", false)
+	model.addAgentOutput("second line", false)
+	model.addAgentOutput("════ Iteration 2/3 ════", false)
+	model.addAgentOutput("working", false)
+
+	if model.agentStatus.Iteration != 2 || model.agentStatus.MaxIterations != 3 {
+		t.Errorf("expected agent status 2/3 from headers, got %d/%d", model.agentStatus.Iteration, model.agentStatus.MaxIterations)
+	}
+
+	// Iteration 1 is complete and should be auto-collapsed
+	lines := model.visibleAgentOutputLines()
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 visible lines (preamble, folded header, header, body), got %d", len(lines))
+	}
+	if !lines[1].folded || lines[1].hidden != 2 {
+		t.Errorf("expected iteration 1 folded with 2 hidden lines, got folded=%v hidden=%d", lines[1].folded, lines[1].hidden)
+	}
+	if lines[2].folded {
+		t.Error("expected current iteration to stay expanded")
+	}
+
+	view := model.renderAgentOutputPanel(100, 20)
+	if !strings.Contains(view, "▸ Iteration 1/3 (2 lines hidden") {
+		t.Errorf("expected folded header in view, got:\n%s", view)
+	}
+	if strings.Contains(view, "first line") {
+		t.Errorf("folded lines should not be rendered, got:\n%s", view)
+	}
+
+	// z on the folded header expands it
+	model.agentOutputOffset = 1
+	newModel, _ := model.handleAgentOutputToggleFold()
+	model = newModel.(Model)
+	if len(model.visibleAgentOutputLines()) != 6 {
+		t.Errorf("expected all 6 lines after expanding, got %d", len(model.visibleAgentOutputLines()))
+	}
+
+	// Z collapses everything, then expands everything
+	newModel, _ = model.handleAgentOutputToggleAllFolds()
+	model = newModel.(Model)
+	if len(model.visibleAgentOutputLines()) != 3 {
+		t.Errorf("expected 3 lines with all iterations collapsed, got %d", len(model.visibleAgentOutputLines()))
+	}
+	newModel, _ = model.handleAgentOutputToggleAllFolds()
+	model = newModel.(Model)
+	if len(model.visibleAgentOutputLines()) != 6 {
+		t.Errorf("expected 6 lines with all iterations expanded, got %d", len(model.visibleAgentOutputLines()))
+	}
+}
+
+// TestBallsPanelASCIIIcons verifies the balls panel uses the configured icon set
+func TestBallsPanelASCIIIcons(t *testing.T) {
+	SetIcons(session.ASCIIIcons())
+	defer SetIcons(session.UnicodeIcons())
+
+	balls := []*session.Ball{
+		{ID: "test-1", Title: "First ball", State: session.StatePending, Tags: []string{"test-session"}, DependsOn: []string{"test-3"}},
+		{ID: "test-2", Title: "Second ball", State: session.StateInProgress, Tags: []string{"test-session"}},
+		{ID: "test-3", Title: "Third ball", State: session.StateComplete, Tags: []string{"test-session"}},
+	}
+
+	model := Model{
+		mode:            splitView,
+		activePanel:     BallsPanel,
+		balls:           balls,
+		filteredBalls:   balls,
+		selectedSession: &session.JuggleSession{ID: "test-session"},
+		width:           120,
+		height:          40,
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+
+	content := model.renderBallsPanel(80, 20)
+
+	for _, want := range []string{"o [1] First ball", "* [2] Second ball", "[->]"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected balls panel to contain %q, got:\n%s", want, content)
+		}
+	}
+	for _, glyph := range []string{"○", "●", "→"} {
+		if strings.Contains(content, glyph) {
+			t.Errorf("expected no Unicode glyph %q with ASCII icons, got:\n%s", glyph, content)
+		}
+	}
+}
+
+// Test vs toggles the tests column and renders last recorded results
+func TestViewColumnToggle_Tests(t *testing.T) {
+	balls := []*session.Ball{
+		{ID: "test-1", Title: "Passing ball", State: session.StatePending, Tags: []string{"test-session"},
+			TestsState: &session.TestsState{Result: session.TestsPass, RecordedAt: time.Now()}},
+		{ID: "test-2", Title: "Failing ball", State: session.StatePending, Tags: []string{"test-session"},
+			TestsState: &session.TestsState{Result: session.TestsFail, RecordedAt: time.Now()}},
+		{ID: "test-3", Title: "Untested ball", State: session.StatePending, Tags: []string{"test-session"}},
+	}
+
+	model := Model{
+		mode:            splitView,
+		activePanel:     BallsPanel,
+		balls:           balls,
+		filteredBalls:   balls,
+		selectedSession: &session.JuggleSession{ID: "test-session"},
+		width:           120,
+		height:          40,
+		activityLog:     make([]ActivityEntry, 0),
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+
+	if strings.Contains(model.renderBallsPanel(100, 20), "[T:") {
+		t.Error("Expected tests column to be hidden by default")
+	}
+
+	newModel, _ := model.handleViewColumnKeySequence("s")
+	m := newModel.(Model)
+
+	if !m.showTestsColumn {
+		t.Fatal("Expected showTestsColumn to be true after vs")
+	}
+	if m.message != "Tests column: visible" {
+		t.Errorf("Expected message 'Tests column: visible', got '%s'", m.message)
+	}
+
+	content := m.renderBallsPanel(100, 20)
+	for _, want := range []string{"[T:pass]", "[T:fail]", "[T:?]"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected balls panel to contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+// Test the detail panel shows the last recorded test run
+func TestBallDetailShowsTestsState(t *testing.T) {
+	recorded := time.Date(2026, 1, 2, 15, 4, 0, 0, time.Local)
+	ball := &session.Ball{
+		ID:         "test-1",
+		Title:      "Failing ball",
+		State:      session.StateInProgress,
+		TestsState: &session.TestsState{Result: session.TestsFail, Command: "go test ./...", RecordedAt: recorded},
+	}
+	model := Model{balls: []*session.Ball{ball}}
+
+	content := strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	if !strings.Contains(content, "fail (go test ./..., 2026-01-02 15:04)") {
+		t.Errorf("Expected detail panel to show failing tests, got:\n%s", content)
+	}
+
+	ball.TestsState = nil
+	content = strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	if !strings.Contains(content, "unknown") {
+		t.Errorf("Expected detail panel to show unknown tests state, got:\n%s", content)
+	}
+}
+
+// Test completing a ball with failing tests is refused under the default policy
+func TestCompleteBallBlockedByFailingTests(t *testing.T) {
+	ball := &session.Ball{
+		ID:         "test-1",
+		Title:      "Failing ball",
+		State:      session.StateInProgress,
+		WorkingDir: t.TempDir(),
+		TestsState: &session.TestsState{Result: session.TestsFail, RecordedAt: time.Now()},
+	}
+	model := &Model{
+		balls:         []*session.Ball{ball},
+		filteredBalls: []*session.Ball{ball},
+		activityLog:   make([]ActivityEntry, 0),
+	}
+
+	newModel, _ := model.handleCompleteBall()
+	m := newModel.(*Model)
+
+	if ball.State != session.StateInProgress {
+		t.Errorf("Expected ball to stay in_progress, got %s", ball.State)
+	}
+	if !strings.Contains(m.message, "failing tests") {
+		t.Errorf("Expected failing tests error, got '%s'", m.message)
+	}
+}
+
+// Test completing a ball with failing tests under the warn policy shows the warning
+func TestCompleteBallWarnsOnFailingTests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".juggle"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.UpdateProjectTestsPolicy(dir, "warn"); err != nil {
+		t.Fatalf("UpdateProjectTestsPolicy: %v", err)
+	}
+	ball := &session.Ball{
+		ID:         "test-1",
+		Title:      "Failing ball",
+		State:      session.StateInProgress,
+		WorkingDir: dir,
+		TestsState: &session.TestsState{Result: session.TestsFail, RecordedAt: time.Now()},
+	}
+	model := &Model{
+		balls:         []*session.Ball{ball},
+		filteredBalls: []*session.Ball{ball},
+		activityLog:   make([]ActivityEntry, 0),
+	}
+
+	newModel, _ := model.handleCompleteBall()
+	m := newModel.(*Model)
+
+	if ball.State != session.StateComplete {
+		t.Errorf("Expected ball to be complete, got %s", ball.State)
+	}
+	if !strings.HasPrefix(m.message, "Warning: ") {
+		t.Errorf("Expected warning message, got '%s'", m.message)
+	}
+	warning := strings.TrimPrefix(m.message, "Warning: ")
+	found := false
+	for _, entry := range m.activityLog {
+		if entry.Message == "Warning: "+warning {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected warning in the activity log")
+	}
+
+	// The archive result keeps the warning rather than replacing it
+	updated, _ := m.Update(ballArchivedMsg{ball: ball, warning: warning})
+	if got := updated.(Model).message; got != "Warning: "+warning {
+		t.Errorf("Expected warning to survive the archive, got '%s'", got)
+	}
+}
+
+// Test file changes matching a ball's watch globs flag the ball and log activity once
+func TestFilesChangedMarksWatchedBalls(t *testing.T) {
+	projectDir := t.TempDir()
+	watched := &session.Ball{ID: "test-1", Title: "Auth rework", State: session.StatePending, WorkingDir: projectDir,
+		Tags: []string{"test-session"}, WatchGlobs: []string{"src/auth/**"}}
+	other := &session.Ball{ID: "test-2", Title: "Docs", State: session.StatePending, WorkingDir: projectDir,
+		Tags: []string{"test-session"}, WatchGlobs: []string{"docs/**"}}
+	balls := []*session.Ball{watched, other}
+
+	model := Model{
+		mode:            splitView,
+		activePanel:     BallsPanel,
+		balls:           balls,
+		filteredBalls:   balls,
+		selectedSession: &session.JuggleSession{ID: "test-session"},
+		width:           120,
+		height:          40,
+		textInput:       textinput.New(),
+		contextInput:    newContextTextarea(),
+		activityLog:     make([]ActivityEntry, 0),
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+
+	event := watcher.Event{
+		Type:       watcher.FilesChanged,
+		Path:       filepath.Join(projectDir, "src", "auth", "login.go"),
+		ProjectDir: projectDir,
+	}
+	newModel, _ := model.handleWatcherEvent(event)
+	newModel, _ = newModel.(Model).handleWatcherEvent(event)
+	m := newModel.(Model)
+
+	if m.codeChanged["test-1"] != "src/auth/login.go" {
+		t.Errorf("Expected test-1 to be flagged, got %v", m.codeChanged)
+	}
+	if _, flagged := m.codeChanged["test-2"]; flagged {
+		t.Error("Expected test-2 not to be flagged")
+	}
+
+	logged := 0
+	for _, entry := range m.activityLog {
+		if strings.Contains(entry.Message, "Code changed for") {
+			logged++
+		}
+	}
+	if logged != 1 {
+		t.Errorf("Expected one code changed activity entry, got %d", logged)
+	}
+
+	content := m.renderBallsPanel(100, 20)
+	if !strings.Contains(content, "[Δ]") {
+		t.Errorf("Expected code changed marker in balls panel, got:\n%s", content)
+	}
+
+	// Opening the ball to edit it dismisses the flag
+	for i, ball := range m.filterBallsForSession() {
+		if ball.ID == "test-1" {
+			m.cursor = i
+		}
+	}
+	newModel, _ = m.handleSplitEditItem()
+	m = newModel.(Model)
+	if _, flagged := m.codeChanged["test-1"]; flagged {
+		t.Error("Expected editing test-1 to clear its code changed flag")
+	}
+}
+
+// Test a balls.jsonl change that names its balls patches just those rows
+func TestBallsChangedPatchesRows(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	var balls []*session.Ball
+	for _, title := range []string{"First", "Second", "Third"} {
+		ball, err := session.NewBall(projectDir, title, session.PriorityMedium)
+		if err != nil {
+			t.Fatalf("Failed to create ball: %v", err)
+		}
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+		balls = append(balls, ball)
+	}
+	first, second, third := balls[0], balls[1], balls[2]
+
+	model := Model{
+		mode:          splitView,
+		activePanel:   BallsPanel,
+		balls:         balls,
+		store:         store,
+		activityLog:   make([]ActivityEntry, 0),
+		width:         120,
+		height:        40,
+		filterStates:  map[string]bool{"pending": true, "in_progress": true, "blocked": true, "complete": true},
+		filteredBalls: balls,
+	}
+	model.reselectBall(third.ID)
+
+	// Another process changes the second ball and adds a fourth
+	changed := *second
+	changed.Title = "Second, renamed"
+	if err := store.UpdateBall(&changed); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+	added, _ := session.NewBall(projectDir, "Fourth", session.PriorityMedium)
+	if err := store.AppendBall(added); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+
+	newModel, cmd := model.handleWatcherEvent(watcher.Event{
+		Type:    watcher.BallsChanged,
+		Path:    filepath.Join(projectDir, ".juggle", "balls.jsonl"),
+		BallIDs: []string{changed.ID, added.ID},
+		Diffed:  true,
+	})
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		msg = batch[0]()
+	}
+	patch, ok := msg.(ballsChangedMsg)
+	if !ok || patch.err != nil || len(patch.balls) != 2 {
+		t.Fatalf("Expected the two changed balls to be loaded, got %+v", patch)
+	}
+
+	newModel, _ = newModel.(Model).Update(patch)
+	m := newModel.(Model)
+	if len(m.balls) != 4 {
+		t.Fatalf("Expected the new ball to be added, got %d balls", len(m.balls))
+	}
+	for _, ball := range m.balls {
+		switch ball.ID {
+		case changed.ID:
+			if ball.Title != "Second, renamed" {
+				t.Errorf("Expected the changed ball to be replaced, got %q", ball.Title)
+			}
+		case first.ID, third.ID:
+			if ball != first && ball != third {
+				t.Errorf("Expected unchanged ball %s to be kept as it was", ball.ID)
+			}
+		case added.ID:
+		default:
+			t.Errorf("Unexpected ball %s", ball.ID)
+		}
+	}
+	if m.selectedBallID() != third.ID {
+		t.Errorf("Expected the selection to stay on Third, got %q", m.selectedBallID())
+	}
+}
+
+// Test that balls created by an agent run that duplicate existing balls open the merge prompt
+func TestAgentCreatedDuplicatesOfferMerge(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	existing := &session.Ball{ID: "test-1", Title: "Add login page", State: session.StatePending, WorkingDir: projectDir,
+		StartedAt: time.Now().Add(-time.Hour), AcceptanceCriteria: []string{"Form renders"}}
+	if err := store.AppendBall(existing); err != nil {
+		t.Fatalf("AppendBall: %v", err)
+	}
+
+	model := Model{
+		mode:        splitView,
+		activePanel: BallsPanel,
+		store:       store,
+		balls:       []*session.Ball{existing},
+		activityLog: make([]ActivityEntry, 0),
+		width:       120,
+		height:      40,
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+	model.snapshotBallIDs()
+
+	// The agent creates a near-duplicate and an unrelated ball
+	dup := &session.Ball{ID: "test-2", Title: "Add the login page", State: session.StatePending, WorkingDir: projectDir,
+		StartedAt: time.Now(), AcceptanceCriteria: []string{"Errors shown"}}
+	unrelated := &session.Ball{ID: "test-3", Title: "Fix billing export", State: session.StatePending, WorkingDir: projectDir,
+		StartedAt: time.Now()}
+	for _, b := range []*session.Ball{dup, unrelated} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("AppendBall: %v", err)
+		}
+	}
+
+	newModel, _ := model.Update(agentOutputMsg{line: "✓ Planned ball added: test-2"})
+	newModel, _ = newModel.(Model).Update(agentFinishedMsg{sessionID: "test", complete: true})
+	newModel, _ = newModel.(Model).Update(ballsLoadedMsg{balls: []*session.Ball{existing, dup, unrelated}})
+	m := newModel.(Model)
+
+	if m.mode != confirmDuplicateMerge {
+		t.Fatalf("Expected merge prompt, got mode %v", m.mode)
+	}
+	if len(m.pendingDuplicates) != 1 || m.pendingDuplicates[0].Ball.ID != "test-2" {
+		t.Fatalf("Expected test-2 to be flagged, got %+v", m.pendingDuplicates)
+	}
+	if view := m.View(); !strings.Contains(view, "Merge 2 into 1?") {
+		t.Errorf("Expected merge prompt in view, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m = newModel.(Model)
+	if m.mode != splitView {
+		t.Errorf("Expected split view after merging the last duplicate, got mode %v", m.mode)
+	}
+	if _, err := store.GetBallByID("test-2"); err == nil {
+		t.Error("Expected duplicate to be deleted")
+	}
+	kept, err := store.GetBallByID("test-1")
+	if err != nil {
+		t.Fatalf("GetBallByID: %v", err)
+	}
+	if len(kept.AcceptanceCriteria) != 2 {
+		t.Errorf("Expected criteria to be merged, got %v", kept.AcceptanceCriteria)
+	}
+}
+
+// Test the detail view lists a ball's sessions and manages membership inline
+func TestBallSessionsFromDetailView(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	ball := &session.Ball{ID: "test-1", Title: "Add login page", State: session.StatePending, WorkingDir: projectDir,
+		Tags: []string{"auth", "ui"}}
+	other := &session.Ball{ID: "test-2", Title: "Fix billing", State: session.StatePending, WorkingDir: projectDir}
+	for _, b := range []*session.Ball{ball, other} {
+		if err := store.AppendBall(b); err != nil {
+			t.Fatalf("AppendBall: %v", err)
+		}
+	}
+
+	model := Model{
+		mode:           splitView,
+		activePanel:    ActivityPanel,
+		bottomPaneMode: BottomPaneDetail,
+		store:          store,
+		balls:          []*session.Ball{ball, other},
+		sessions: []*session.JuggleSession{
+			{ID: "auth", Description: "Authentication"},
+			{ID: "billing"},
+		},
+		activityLog:   make([]ActivityEntry, 0),
+		selectedBalls: make(map[string]bool),
+		width:         120,
+		height:        40,
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+	model.applyFilters()
+
+	lines := strings.Join(model.buildBallDetailLines(ball, 120), "\n")
+	if !strings.Contains(lines, "Sessions:") || !strings.Contains(lines, "auth") {
+		t.Errorf("Expected sessions row listing auth, got:\n%s", lines)
+	}
+
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'S'}})
+	m := newModel.(Model)
+	if m.mode != ballSessionsView {
+		t.Fatalf("Expected ball sessions view, got mode %v", m.mode)
+	}
+	if m.sessionSelectIndex != 0 {
+		t.Errorf("Expected cursor on first member session, got %d", m.sessionSelectIndex)
+	}
+
+	// Add the ball to billing inline
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(Model)
+	if !ballHasSessionTag(ball, "billing") {
+		t.Errorf("Expected ball to be added to billing, got tags %v", ball.Tags)
+	}
+	if view := m.View(); !strings.Contains(view, "Ball Sessions") || !strings.Contains(view, "Added to session: billing") {
+		t.Errorf("Expected membership change in view, got:\n%s", view)
+	}
+
+	// Remove it from auth
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(Model)
+	if ballHasSessionTag(ball, "auth") {
+		t.Errorf("Expected ball to be removed from auth, got tags %v", ball.Tags)
+	}
+
+	// Jump to billing
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || m.activePanel != BallsPanel {
+		t.Fatalf("Expected split view with balls panel focused, got mode %v panel %v", m.mode, m.activePanel)
+	}
+	if m.selectedSession == nil || m.selectedSession.ID != "billing" {
+		t.Fatalf("Expected billing to be selected, got %+v", m.selectedSession)
+	}
+	balls := m.filterBallsForSession()
+	if m.cursor >= len(balls) || balls[m.cursor].ID != "test-1" {
+		t.Errorf("Expected cursor to stay on test-1")
+	}
+}
+
+// Test attached transcripts are listed in the detail view and readable with T
+func TestTranscriptFromDetailView(t *testing.T) {
+	projectDir := t.TempDir()
+	store, err := session.NewStore(projectDir)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	ball := &session.Ball{ID: "test-1", Title: "Pick a cache strategy", State: session.StatePending, WorkingDir: projectDir}
+	for i, text := range []string{"first chat", "decided: no cache"} {
+		transcript, err := store.SaveTranscript(ball.ID, text, session.Transcript{
+			Source:     session.TranscriptSourceChat,
+			Note:       fmt.Sprintf("note %d", i+1),
+			AttachedAt: time.Now().Add(time.Duration(i) * time.Second),
+		})
+		if err != nil {
+			t.Fatalf("SaveTranscript: %v", err)
+		}
+		ball.AttachTranscript(transcript)
+	}
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("AppendBall: %v", err)
+	}
+
+	model := Model{
+		mode:           splitView,
+		activePanel:    ActivityPanel,
+		bottomPaneMode: BottomPaneDetail,
+		store:          store,
+		balls:          []*session.Ball{ball},
+		activityLog:    make([]ActivityEntry, 0),
+		selectedBalls:  make(map[string]bool),
+		width:          120,
+		height:         40,
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+	model.applyFilters()
+
+	lines := strings.Join(model.buildBallDetailLines(ball, 120), "\n")
+	if !strings.Contains(lines, "Transcripts:") || !strings.Contains(lines, "chat: note 2") {
+		t.Errorf("Expected transcripts listed, got:\n%s", lines)
+	}
+
+	newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	m := newModel.(Model)
+	if m.mode != transcriptView || m.transcriptIndex != 1 {
+		t.Fatalf("Expected transcript view on newest transcript, got mode %v index %d", m.mode, m.transcriptIndex)
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if m.transcriptContent != "decided: no cache" {
+		t.Errorf("Expected newest transcript content, got %q", m.transcriptContent)
+	}
+
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = newModel.(Model)
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if m.transcriptIndex != 0 || m.transcriptContent != "first chat" {
+		t.Errorf("Expected previous transcript, got index %d content %q", m.transcriptIndex, m.transcriptContent)
+	}
+	if view := m.View(); !strings.Contains(view, "Transcript 1/2") {
+		t.Errorf("Expected transcript header, got:\n%s", view)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if newModel.(Model).mode != splitView {
+		t.Errorf("Expected Esc to return to split view")
+	}
+}
+
+// Test all-projects mode groups balls and sessions under project headers
+// with collapse/expand per project
+func TestProjectGroupsInAllProjectsMode(t *testing.T) {
+	alpha, beta := "/work/alpha", "/work/beta"
+	balls := []*session.Ball{
+		{ID: "beta-1", Title: "Beta pending", State: session.StatePending, WorkingDir: beta},
+		{ID: "alpha-1", Title: "Alpha pending", State: session.StatePending, WorkingDir: alpha},
+		{ID: "beta-2", Title: "Beta blocked", State: session.StateBlocked, WorkingDir: beta},
+		{ID: "alpha-2", Title: "Alpha done", State: session.StateComplete, WorkingDir: alpha},
+	}
+	model := Model{
+		mode:          splitView,
+		activePanel:   BallsPanel,
+		balls:         balls,
+		sessions:      []*session.JuggleSession{{ID: "beta-work", ProjectDir: beta}, {ID: "alpha-work", ProjectDir: alpha}},
+		activityLog:   make([]ActivityEntry, 0),
+		selectedBalls: make(map[string]bool),
+		width:         120,
+		height:        40,
+		sortOrder:     SortByIDASC,
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+	model.selectedSession = &session.JuggleSession{ID: PseudoSessionAll}
+	model.applyFilters()
+
+	ids := func(m Model) []string {
+		var result []string
+		for _, b := range m.filterBallsForSession() {
+			result = append(result, b.ID)
+		}
+		return result
+	}
+	if got := strings.Join(ids(model), ","); got != "alpha-1,alpha-2,beta-1,beta-2" {
+		t.Errorf("Expected balls grouped by project, got %s", got)
+	}
+	sessions := model.filterSessions()
+	if sessions[3].ID != "alpha-work" || sessions[4].ID != "beta-work" {
+		t.Errorf("Expected sessions grouped by project after the pseudo-sessions, got %s, %s", sessions[3].ID, sessions[4].ID)
+	}
+
+	view := model.View()
+	for _, want := range []string{"▾ alpha (2)  P:1 I:0 B:0 C:1", "▾ beta (2)  P:1 I:0 B:1 C:0", "▾ alpha (1)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in view:\n%s", want, view)
+		}
+	}
+
+	// Collapse alpha from the project list
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	m := newModel.(Model)
+	if m.mode != projectGroupsView {
+		t.Fatalf("Expected project groups view, got mode %v", m.mode)
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = newModel.(Model)
+	if !m.collapsedProjects[alpha] {
+		t.Fatalf("Expected alpha to be collapsed")
+	}
+	if got := strings.Join(ids(m), ","); got != "beta-1,beta-2" {
+		t.Errorf("Expected collapsed project's balls hidden, got %s", got)
+	}
+	for _, sess := range m.filterSessions() {
+		if sess.ID == "alpha-work" {
+			t.Errorf("Expected collapsed project's sessions hidden")
+		}
+	}
+	if state := m.State(); len(state.CollapsedProjects) != 1 || state.CollapsedProjects[0] != alpha {
+		t.Errorf("Expected collapsed project remembered, got %v", state.CollapsedProjects)
+	}
+
+	// Enter expands the project and jumps to its first ball
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || m.collapsedProjects[alpha] {
+		t.Fatalf("Expected alpha expanded and back in split view")
+	}
+	if balls := m.filterBallsForSession(); balls[m.cursor].ID != "alpha-1" {
+		t.Errorf("Expected cursor on alpha's first ball, got %s", balls[m.cursor].ID)
+	}
+
+	// Local mode shows the flat list
+	m.localOnly = true
+	if m.groupByProject() {
+		t.Errorf("Expected no project groups in local mode")
+	}
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}})
+	if newModel.(Model).mode != splitView {
+		t.Errorf("Expected C to do nothing in local mode")
+	}
+}
+
+func TestDashboardInAllProjectsMode(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	quiet, busy := t.TempDir(), t.TempDir()
+	for dir, titles := range map[string][]string{quiet: {"Old"}, busy: {"New", "Newer"}} {
+		store, err := session.NewStore(dir)
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		for i, title := range titles {
+			ball, _ := session.NewBall(dir, title, session.PriorityMedium)
+			ball.LastActivity = now.Add(-72 * time.Hour)
+			if dir == busy {
+				ball.LastActivity = now.Add(-time.Duration(i+1) * time.Hour)
+			}
+			if err := store.AppendBall(ball); err != nil {
+				t.Fatalf("Failed to append ball: %v", err)
+			}
+		}
+	}
+
+	model := Model{
+		mode:         splitView,
+		activePanel:  SessionsPanel,
+		config:       &session.Config{SearchPaths: []string{quiet, busy}},
+		projectCache: session.NewProjectCache(),
+		activityLog:  make([]ActivityEntry, 0),
+		width:        120,
+		height:       40,
+		nowFunc:      func() time.Time { return now },
+	}
+	sessions := model.filterSessions()
+	if len(sessions) != 3 || sessions[2].ID != PseudoSessionDashboard {
+		t.Fatalf("Expected the dashboard after the other pseudo-sessions, got %d sessions", len(sessions))
+	}
+
+	newModel, _ := model.Update(model.refreshDashboard()())
+	model = newModel.(Model)
+	if len(model.dashboard) != 2 || model.dashboard[0].ProjectDir != busy {
+		t.Fatalf("Expected both projects, most recently active first, got %+v", model.dashboard)
+	}
+	if model.dashboard[0].States[session.StatePending] != 2 {
+		t.Errorf("Expected 2 pending balls in the busy project, got %v", model.dashboard[0].States)
+	}
+
+	model.sessionCursor = 2
+	model.selectedSession = sessions[2]
+	if balls := model.getBallsForSession(); len(balls) != 0 {
+		t.Errorf("Expected no balls for the dashboard, got %d", len(balls))
+	}
+	view := model.View()
+	for _, want := range []string{"Dashboard", "2 projects", filepath.Base(busy), filepath.Base(quiet), "1h ago", "Oct 12"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in view:\n%s", want, view)
+		}
+	}
+
+	// Local mode has no dashboard
+	model.localOnly = true
+	for _, sess := range model.filterSessions() {
+		if sess.ID == PseudoSessionDashboard {
+			t.Error("Expected no dashboard in local mode")
+		}
+	}
+	if model.refreshDashboard() != nil {
+		t.Error("Expected no dashboard load in local mode")
+	}
+}
+
+// Test the footer and help view are driven by the same keybinding registry
+func TestKeySectionsDriveFooterAndHelp(t *testing.T) {
+	help := strings.Join(helpLines(), "\n")
+
+	for _, section := range keySections {
+		if !strings.Contains(help, section.title) {
+			t.Errorf("Expected help view to contain section %q", section.title)
+		}
+		if section.leader == "" {
+			continue
+		}
+
+		model := InitialSplitModel(nil, nil, nil, true)
+		model.activePanel = BallsPanel
+		newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(section.leader)})
+		m := newModel.(Model)
+		if m.pendingKeySequence != section.leader {
+			t.Fatalf("Expected %q to start a key sequence, got %q", section.leader, m.pendingKeySequence)
+		}
+
+		statusBar := m.renderStatusBar()
+		for _, binding := range section.bindings {
+			if !strings.Contains(help, section.leader+binding.key) {
+				t.Errorf("Expected help view to list %q", section.leader+binding.key)
+			}
+			hint := binding.key + ":" + binding.hint
+			if !strings.Contains(statusBar, hint) {
+				t.Errorf("Expected footer after %q to contain %q, got: %s", section.leader, hint, statusBar)
+			}
+		}
+	}
+}
+
+// Test conditional footer hints only appear when they apply
+func TestFooterConditionalHints(t *testing.T) {
+	model := Model{mode: splitView, activePanel: BallsPanel, localOnly: true}
+	for _, hint := range []string{"X:cancel", "C:collapse", "z/Z:fold"} {
+		if strings.Contains(model.renderStatusBar(), hint) {
+			t.Errorf("Expected footer not to contain %q by default", hint)
+		}
+	}
+
+	model.localOnly = false
+	model.agentStatus.Running = true
+	model.agentOutputVisible = true
+	statusBar := model.renderStatusBar()
+	for _, hint := range []string{"X:cancel", "C:collapse", "z/Z:fold"} {
+		if !strings.Contains(statusBar, hint) {
+			t.Errorf("Expected footer to contain %q, got: %s", hint, statusBar)
+		}
+	}
+}
+
+func TestAgentEditsRefreshDetailPane(t *testing.T) {
+	first := &session.Ball{ID: "test-1", Title: "Set up database", State: session.StateInProgress, Priority: session.PriorityMedium}
+	second := &session.Ball{ID: "test-2", Title: "Add login page", State: session.StatePending, Priority: session.PriorityMedium,
+		AcceptanceCriteria: []string{"Form renders"}}
+
+	model := Model{
+		mode:        splitView,
+		activePanel: BallsPanel,
+		balls:       []*session.Ball{first, second},
+		activityLog: make([]ActivityEntry, 0),
+		width:       120,
+		height:      40,
+		agentStatus: AgentStatus{Running: true, SessionID: "test"},
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    false,
+		},
+	}
+	model.applyFilters()
+	model.cursor = 1 // test-2 selected
+
+	// The agent completes test-1 (hidden by the filter) and starts test-2
+	reloadedFirst := *first
+	reloadedFirst.MarkComplete("")
+	reloadedSecond := *second
+	reloadedSecond.State = session.StateInProgress
+	reloadedSecond.AcceptanceCriteria = []string{"Form renders", "Errors shown"}
+
+	newModel, _ := model.Update(ballsLoadedMsg{balls: []*session.Ball{&reloadedFirst, &reloadedSecond}})
+	m := newModel.(Model)
+
+	if m.selectedBallID() != "test-2" {
+		t.Fatalf("Expected test-2 to stay selected after reload, got %q (cursor %d)", m.selectedBallID(), m.cursor)
+	}
+	edit := m.agentEdits["test-2"]
+	if edit == nil || strings.Join(edit.fields, ",") != "state,criteria" {
+		t.Fatalf("Expected state and criteria to be recorded as agent edits, got %+v", edit)
+	}
+	if !strings.Contains(m.activityLog[len(m.activityLog)-2].Message, "Updated by agent: 2 (state, criteria)") {
+		t.Errorf("Expected activity entry for the selected ball, got %+v", m.activityLog)
+	}
+
+	panel := m.renderBallDetailPanel(100, 30)
+	if !strings.Contains(panel, "✎ updated by agent") || !strings.Contains(panel, "Errors shown") {
+		t.Errorf("Expected refreshed detail pane with agent marker, got:\n%s", panel)
+	}
+
+	// Edits from later iterations accumulate; the final reload after the
+	// agent stops is still recorded
+	newModel, _ = m.Update(agentFinishedMsg{sessionID: "test", complete: true})
+	finalSecond := reloadedSecond
+	finalSecond.Title = "Add login page with validation"
+	newModel, _ = newModel.(Model).Update(ballsLoadedMsg{balls: []*session.Ball{&reloadedFirst, &finalSecond}})
+	m = newModel.(Model)
+	if edit := m.agentEdits["test-2"]; edit == nil || strings.Join(edit.fields, ",") != "state,title,criteria" {
+		t.Errorf("Expected accumulated edits, got %+v", edit)
+	}
+
+	// Reloads while no agent is running aren't attributed to the agent
+	unrelated := finalSecond
+	unrelated.Priority = session.PriorityHigh
+	newModel, _ = m.Update(ballsLoadedMsg{balls: []*session.Ball{&reloadedFirst, &unrelated}})
+	m = newModel.(Model)
+	if m.agentEdits["test-2"].changed("priority") {
+		t.Error("Expected edits made with no agent running not to be highlighted")
+	}
+
+	// A new run starts with a clean slate
+	newModel, _ = m.Update(agentProcessStartedMsg{sessionID: "test"})
+	if edits := newModel.(Model).agentEdits; len(edits) != 0 {
+		t.Errorf("Expected agent edits cleared when a new run starts, got %+v", edits)
+	}
+}
+
+// Test u undoes the last ball change and U redoes it
+func TestUndoRedoKeys(t *testing.T) {
+	store, err := session.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	ball, _ := session.NewBall(store.ProjectDir(), "Original", session.PriorityMedium)
+	if err := store.AppendBall(ball); err != nil {
+		t.Fatalf("Failed to append ball: %v", err)
+	}
+	ball.Title = "Renamed"
+	if err := store.UpdateBall(ball); err != nil {
+		t.Fatalf("Failed to update ball: %v", err)
+	}
+
+	model := Model{
+		mode:        splitView,
+		activePanel: BallsPanel,
+		store:       store,
+		localOnly:   true,
+		activityLog: make([]ActivityEntry, 0),
+	}
+
+	_, cmd := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if cmd == nil {
+		t.Fatal("Expected an undo command")
+	}
+	newModel, _ := model.Update(cmd())
+	m := newModel.(Model)
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.Title != "Original" {
+		t.Fatalf("Expected title restored by undo, got %+v", got)
+	}
+	if m.message != "Undid update of "+ball.ID {
+		t.Errorf("Expected undo message, got %q", m.message)
+	}
+
+	_, cmd = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	m.Update(cmd())
+	if got, _ := store.GetBallByID(ball.ID); got == nil || got.Title != "Renamed" {
+		t.Fatalf("Expected title reapplied by redo, got %+v", got)
+	}
+
+	_, cmd = m.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	newModel, _ = m.Update(cmd())
+	if msg := newModel.(Model).message; msg != "Nothing to redo" {
+		t.Errorf("Expected nothing to redo, got %q", msg)
+	}
+}
+
+func TestGlobalSearchJumpsToBall(t *testing.T) {
+	store, err := session.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	first, _ := session.NewBall(store.ProjectDir(), "First", session.PriorityMedium)
+	second, _ := session.NewBall(store.ProjectDir(), "Second", session.PriorityMedium)
+	second.Context = "Needs the retry budget"
+	balls := []*session.Ball{first, second}
+	for _, ball := range balls {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+
+	model := Model{
+		mode:          splitView,
+		activePanel:   SessionsPanel,
+		store:         store,
+		localOnly:     true,
+		balls:         balls,
+		filteredBalls: balls,
+		selectedBalls: make(map[string]bool),
+		textInput:     textinput.New(),
+		activityLog:   make([]ActivityEntry, 0),
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m := newModel.(Model)
+	if m.mode != globalSearchView {
+		t.Fatalf("Expected the global search view, got mode %v", m.mode)
+	}
+	m.textInput.SetValue("retry state:pending")
+	_, cmd := m.handleGlobalSearchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a search command")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(m.searchResults) != 1 || m.searchResults[0].Ball.ID != second.ID {
+		t.Fatalf("Expected the second ball found, got %d results", len(m.searchResults))
+	}
+	if view := m.renderGlobalSearchView(); !strings.Contains(view, second.ID) || !strings.Contains(view, "retry") {
+		t.Errorf("Expected the result and its match rendered, got:\n%s", view)
+	}
+
+	// Enter again on the same query jumps to the result
+	newModel, _ = m.handleGlobalSearchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || m.activePanel != BallsPanel {
+		t.Fatalf("Expected the balls panel, got mode %v panel %v", m.mode, m.activePanel)
+	}
+	if m.selectedSession == nil || m.selectedSession.ID != PseudoSessionAll {
+		t.Fatalf("Expected the All session selected, got %+v", m.selectedSession)
+	}
+	if shown := m.filterBallsForSession(); m.cursor >= len(shown) || shown[m.cursor].ID != second.ID {
+		t.Errorf("Expected the second ball highlighted, got cursor %d", m.cursor)
+	}
+}
+
+func TestReopenBallFromBallsPanelAndSearch(t *testing.T) {
+	store, err := session.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	done, _ := session.NewBall(store.ProjectDir(), "Login form", session.PriorityMedium)
+	done.MarkComplete("shipped")
+	archived, _ := session.NewBall(store.ProjectDir(), "Retry budget", session.PriorityMedium)
+	archived.MarkComplete("")
+	for _, ball := range []*session.Ball{done, archived} {
+		if err := store.AppendBall(ball); err != nil {
+			t.Fatalf("Failed to append ball: %v", err)
+		}
+	}
+	if err := store.ArchiveBall(archived); err != nil {
+		t.Fatalf("Failed to archive ball: %v", err)
+	}
+
+	model := Model{
+		mode:            splitView,
+		activePanel:     BallsPanel,
+		store:           store,
+		localOnly:       true,
+		balls:           []*session.Ball{done},
+		filteredBalls:   []*session.Ball{done},
+		selectedSession: &session.JuggleSession{ID: PseudoSessionAll},
+		selectedBalls:   make(map[string]bool),
+		textInput:       textinput.New(),
+		activityLog:     make([]ActivityEntry, 0),
+	}
+
+	// sr reopens the highlighted completed ball
+	_, cmd := model.handleStateKeySequence("r")
+	if cmd == nil {
+		t.Fatal("Expected a reopen command")
+	}
+	newModel, _ := model.Update(cmd())
+	if msg := newModel.(Model).message; msg != "Reopened "+done.ID {
+		t.Errorf("Expected reopen message, got %q", msg)
+	}
+	if got, _ := store.GetBallByID(done.ID); got == nil || got.State != session.StatePending || !strings.Contains(got.Context, "Reopened ") {
+		t.Errorf("Expected the ball reopened with a note, got %+v", got)
+	}
+
+	// Ctrl+R reopens an archived search result
+	model.mode = globalSearchView
+	model.searchResults = []*session.SearchResult{{ProjectDir: store.ProjectDir(), Ball: archived, Archived: true}}
+	newModel, cmd = model.handleGlobalSearchKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if cmd == nil {
+		t.Fatalf("Expected a reopen command, got message %q", newModel.(Model).message)
+	}
+	newModel, _ = newModel.(Model).Update(cmd())
+	m := newModel.(Model)
+	if m.message != "Reopened "+archived.ID+" (restored from archive)" || m.searchResults[0].Archived {
+		t.Errorf("Expected the archived result restored, got %q", m.message)
+	}
+	if got, _ := store.GetBallByID(archived.ID); got == nil || got.State != session.StatePending {
+		t.Errorf("Expected the archived ball back in the active balls, got %+v", got)
+	}
+
+	// Balls that aren't complete can't be reopened
+	if _, cmd := m.reopenSearchResult(); cmd != nil {
+		t.Error("Expected no command for a pending ball")
+	}
+}
+
+func TestParseSearchInput(t *testing.T) {
+	q := parseSearchInput("retry budget state:pending,blocked session:api project:juggle")
+	if q.Text != "retry budget" || q.Session != "api" || q.Project != "juggle" {
+		t.Errorf("Unexpected query %+v", q)
+	}
+	if len(q.States) != 2 || q.States[0] != session.StatePending || q.States[1] != session.StateBlocked {
+		t.Errorf("Expected pending and blocked states, got %v", q.States)
+	}
+}
+
+// Test that the unified ball form warns about titles breaking the title rules
+func TestUnifiedBallFormWarnsAboutTitleRules(t *testing.T) {
+	ti := textinput.New()
+	ti.SetValue("Added the login page.")
+
+	model := Model{
+		mode:                 unifiedBallFormView,
+		pendingBallFormField: 1, // Title
+		textInput:            ti,
+		sessions:             []*session.JuggleSession{},
+		titleRules:           &session.TitleRules{Imperative: true, Capitalize: true},
+		width:                80,
+		height:               40,
+	}
+
+	view := model.renderUnifiedBallFormView()
+	if !strings.Contains(view, `Title use the imperative mood ("Add", not "Added")`) {
+		t.Error("Expected an imperative mood warning")
+	}
+	if !strings.Contains(view, "Title should not end with a period") {
+		t.Error("Expected a trailing period warning")
+	}
+
+	model.textInput.SetValue("Add the login page")
+	if view := model.renderUnifiedBallFormView(); strings.Contains(view, "⚠ Title") {
+		t.Error("Expected no warnings for a title following the rules")
+	}
+}
+
+// TestBallDetailShowsRecurrence verifies that a recurring ball's detail shows its schedule and due time
+func TestBallDetailShowsRecurrence(t *testing.T) {
+	ball := &session.Ball{
+		ID:         "test-1",
+		Title:      "Weekly review",
+		State:      session.StatePending,
+		Priority:   session.PriorityMedium,
+		Recurrence: &session.Recurrence{Every: "7d", DueAt: time.Now().Add(-2 * time.Hour)},
+	}
+	model := Model{mode: splitView, width: 120, height: 40}
+
+	found := false
+	for _, line := range model.buildBallDetailLines(ball, 100) {
+		if strings.Contains(line, "Recurs:") && strings.Contains(line, "every 7d") && strings.Contains(line, "(overdue 2h)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a Recurs line with the schedule and due time")
+	}
+}
+
+// TestAgentPauseToggle verifies that p pauses the selected session's running
+// agent, and p again lifts the pause
+func TestAgentPauseToggle(t *testing.T) {
+	sessionStore, err := session.NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	sess, err := sessionStore.CreateSession("auth", "Auth work")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	model := Model{
+		mode:            splitView,
+		activePanel:     SessionsPanel,
+		sessionStore:    sessionStore,
+		selectedSession: sess,
+		activityLog:     make([]ActivityEntry, 0),
+	}
+	pressP := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}
+
+	newModel, cmd := model.handleSplitViewKey(pressP)
+	if m := newModel.(Model); cmd != nil || !strings.Contains(m.message, "No agent is running") {
+		t.Fatalf("Expected no pause without a running agent, got %q", m.message)
+	}
+
+	lock, err := sessionStore.AcquireSessionLock("auth")
+	if err != nil {
+		t.Fatalf("Failed to lock session: %v", err)
+	}
+	defer lock.Release()
+
+	newModel, cmd = model.handleSplitViewKey(pressP)
+	newModel, _ = newModel.(Model).Update(cmd())
+	m := newModel.(Model)
+	if !strings.Contains(m.message, "Pausing the agent on auth") {
+		t.Errorf("Expected the pause to be confirmed, got %q", m.message)
+	}
+	pause, err := sessionStore.LoadAgentPause("auth")
+	if err != nil || pause == nil || pause.Source != "tui" {
+		t.Fatalf("Expected a pause from the TUI, got %+v (%v)", pause, err)
+	}
+
+	// Once the loop holds, p resumes it
+	pause.PausedAt = time.Now()
+	if err := sessionStore.SaveAgentPause(pause); err != nil {
+		t.Fatalf("Failed to mark the pause held: %v", err)
+	}
+	newModel, cmd = m.handleSplitViewKey(pressP)
+	newModel, _ = newModel.(Model).Update(cmd())
+	if m := newModel.(Model); m.message != "Resumed the agent on auth" {
+		t.Errorf("Expected the agent to be resumed, got %q", m.message)
+	}
+	if pause, _ := sessionStore.LoadAgentPause("auth"); pause != nil {
+		t.Errorf("Expected the pause to be lifted, got %+v", pause)
+	}
+}
+
+// TestAgentSignalSendsBlocked verifies that ! signals the selected session's running agent
+func TestAgentSignalSendsBlocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionStore, err := session.NewSessionStore(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to create session store: %v", err)
+	}
+	sess, err := sessionStore.CreateSession("auth", "Auth work")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	model := Model{
+		mode:            splitView,
+		activePanel:     SessionsPanel,
+		sessionStore:    sessionStore,
+		selectedSession: sess,
+		textInput:       textinput.New(),
+		activityLog:     make([]ActivityEntry, 0),
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+	if m := newModel.(Model); m.mode != splitView || !strings.Contains(m.message, "No agent is running") {
+		t.Fatalf("Expected no prompt without a running agent, got mode %v: %q", m.mode, m.message)
+	}
+
+	lock, err := sessionStore.AcquireSessionLock("auth")
+	if err != nil {
+		t.Fatalf("Failed to lock session: %v", err)
+	}
+	defer lock.Release()
+
+	newModel, _ = model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'!'}})
+	m := newModel.(Model)
+	if m.mode != agentSignalView {
+		t.Fatalf("Expected the signal prompt, got mode %v", m.mode)
+	}
+	newModel, _ = m.handleAgentSignalKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m = newModel.(Model)
+	if !m.signalReasonEditing {
+		t.Fatal("Expected BLOCKED to ask for a reason")
+	}
+	m.textInput.SetValue("needs a decision")
+	newModel, cmd := m.handleAgentSignalKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || cmd == nil {
+		t.Fatal("Expected Enter to send the signal")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if !strings.Contains(m.message, "Sent BLOCKED to auth") {
+		t.Errorf("Expected a confirmation, got %q", m.message)
+	}
+
+	signal, err := sessionStore.LoadHumanSignal("auth")
+	if err != nil || signal == nil {
+		t.Fatalf("Expected a pending signal, got %v (%v)", signal, err)
+	}
+	if signal.Signal != session.LoopSignalBlocked || signal.Reason != "needs a decision" || signal.Source != "tui" {
+		t.Errorf("Unexpected signal: %+v", signal)
+	}
+}
+
+// TestBallDetailShowsVerification verifies that a ball's detail marks each criterion with its verdict
+func TestBallDetailShowsVerification(t *testing.T) {
+	ball := &session.Ball{
+		ID:                 "test-1",
+		Title:              "Reject bad passwords",
+		State:              session.StateInProgress,
+		Priority:           session.PriorityMedium,
+		AcceptanceCriteria: []string{"Returns 401", "Has a test"},
+		Verification: &session.Verification{
+			Assessments: []session.ACAssessment{
+				{Criterion: "Returns 401", Satisfied: true},
+				{Criterion: "Has a test", Satisfied: false},
+			},
+			VerifiedAt: time.Now(),
+		},
+	}
+	model := Model{mode: splitView, width: 120, height: 40}
+
+	joined := strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	for _, want := range []string{"1/2 criteria satisfied", "1. ✓ Returns 401", "2. ✗ Has a test"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected %q in the detail", want)
+		}
+	}
+
+	ball.AcceptanceCriteria = []string{"Returns 401"}
+	joined = strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	if strings.Contains(joined, "✓") || !strings.Contains(joined, "criteria changed since") {
+		t.Error("Expected a stale verification without verdict marks")
+	}
+}
+
+// TestBallDetailShowsGitLink verifies that a linked ball's detail shows its branch and commit count
+func TestBallDetailShowsMilestone(t *testing.T) {
+	ball := &session.Ball{
+		ID:        "test-1",
+		Title:     "Add the login page",
+		State:     session.StatePending,
+		Priority:  session.PriorityMedium,
+		Milestone: "v1.2",
+	}
+	model := Model{mode: splitView, width: 120, height: 40}
+
+	joined := strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	if !strings.Contains(joined, "Milestone:") || !strings.Contains(joined, "v1.2") {
+		t.Error("Expected a Milestone line")
+	}
+}
+
+func TestBallDetailShowsGitLink(t *testing.T) {
+	ball := &session.Ball{
+		ID:       "test-1",
+		Title:    "Add the login page",
+		State:    session.StateInProgress,
+		Priority: session.PriorityMedium,
+		Git: &session.GitLink{
+			Branch:  "feature/login",
+			Commits: []session.LinkedCommit{{Hash: "abc1234", Subject: "Add the login form"}, {Hash: "def5678", Subject: "Style it"}},
+		},
+	}
+	model := Model{mode: splitView, width: 120, height: 40}
+
+	joined := strings.Join(model.buildBallDetailLines(ball, 100), "\n")
+	if !strings.Contains(joined, "Git:") || !strings.Contains(joined, "feature/login, 2 commits") {
+		t.Error("Expected a Git line with the branch and commit count")
+	}
+}
+
+// Test attention signals fire only while the TUI is unfocused and are
+// cleared on focus
+func TestAttentionSignals(t *testing.T) {
+	var out strings.Builder
+	model := Model{
+		mode:         splitView,
+		config:       &session.Config{Attention: &session.AttentionConfig{Bell: true, Title: true, Events: []string{session.AttentionBlocked}}},
+		attentionOut: &out,
+	}
+
+	if cmd := model.attention(session.AttentionBlocked, "agent blocked on test"); cmd != nil {
+		t.Error("Expected no signal while focused")
+	}
+
+	newModel, _ := model.Update(tea.BlurMsg{})
+	model = newModel.(Model)
+	if cmd := model.attention(session.AttentionFinished, "agent complete on test"); cmd != nil {
+		t.Error("Expected no signal for an event that isn't configured")
+	}
+
+	cmd := model.attention(session.AttentionBlocked, "agent blocked on test")
+	if cmd == nil {
+		t.Fatal("Expected a signal while unfocused")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Expected bell and title commands, got %#v", batch)
+	}
+	batch[0]()
+	if out.String() != "\a" {
+		t.Errorf("Expected a bell, got %q", out.String())
+	}
+	if title := fmt.Sprint(batch[1]()); title != "juggle: agent blocked on test" {
+		t.Errorf("Expected the event in the title, got %q", title)
+	}
+	if !model.attentionPending {
+		t.Error("Expected the signal to be pending")
+	}
+
+	newModel, cmd = model.Update(tea.FocusMsg{})
+	model = newModel.(Model)
+	if model.blurred || model.attentionPending {
+		t.Error("Expected focus to clear the pending signal")
+	}
+	if cmd == nil || fmt.Sprint(cmd()) != "juggle" {
+		t.Error("Expected focus to restore the title")
+	}
+}
+
+// Test the briefing opens on b, opens on Enter only for idle sessions, and
+// closes on Esc
+func TestSessionBriefing(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	sess := &session.JuggleSession{ID: "auth", Description: "Ship login", ProjectDir: t.TempDir(), UpdatedAt: now.Add(-10 * 24 * time.Hour)}
+	next := &session.Ball{ID: "p-1", Title: "Login page", State: session.StatePending, Priority: session.PriorityHigh, Tags: []string{"auth"}, NextAction: "wire the form"}
+	started := &session.Ball{ID: "p-2", Title: "Session cookie", State: session.StateInProgress, Priority: session.PriorityLow, Tags: []string{"auth"}}
+	waiting := &session.Ball{ID: "p-3", Title: "Logout", State: session.StatePending, Priority: session.PriorityUrgent, Tags: []string{"auth"}, DependsOn: []string{"p-1"}}
+	blocked := &session.Ball{ID: "p-4", Title: "SSO", State: session.StateBlocked, Priority: session.PriorityMedium, Tags: []string{"auth"}, BlockedReason: "needs IdP access"}
+	other := &session.Ball{ID: "p-5", Title: "Unrelated", State: session.StatePending, Priority: session.PriorityUrgent, Tags: []string{"docs"}}
+	for _, ball := range []*session.Ball{next, started, waiting, blocked, other} {
+		ball.LastActivity = now.Add(-5 * 24 * time.Hour)
+	}
+
+	model := Model{
+		localOnly:   true,
+		mode:        splitView,
+		activePanel: SessionsPanel,
+		sessions:    []*session.JuggleSession{sess},
+		balls:       []*session.Ball{next, started, waiting, blocked, other},
+		nowFunc:     func() time.Time { return now },
+	}
+	model.sessionCursor = 2 // After the All and Untagged pseudo-sessions
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if cmd == nil {
+		t.Fatal("Expected b to load the briefing")
+	}
+	msg, ok := cmd().(briefingHistoryMsg)
+	if !ok || msg.auto || msg.err != nil {
+		t.Fatalf("Expected a manual briefing load, got %+v", msg)
+	}
+
+	newModel, _ := model.Update(msg)
+	m := newModel.(Model)
+	if m.mode != briefingView || m.briefing == nil {
+		t.Fatal("Expected the briefing shown")
+	}
+	if len(m.briefing.Next) != 2 || m.briefing.Next[0] != started || m.briefing.Next[1] != next {
+		t.Errorf("Expected the in-progress ball then the ready one next, got %+v", m.briefing.Next)
+	}
+	view := m.renderBriefingView()
+	for _, want := range []string{"Briefing: auth", "Goal: Ship login", "Login page", "wire the form", "+1 more", "SSO", "needs IdP access", "Last agent run: none"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in briefing, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Unrelated") {
+		t.Error("Expected balls from other sessions left out")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(Model); m.mode != splitView || m.briefing != nil {
+		t.Error("Expected Esc to close the briefing")
+	}
+
+	// Selecting a session briefs only if it has been idle a while
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected Enter to check for a briefing")
+	}
+	msg = cmd().(briefingHistoryMsg)
+	if !msg.auto {
+		t.Error("Expected an automatic briefing load")
+	}
+	if newModel, _ = model.Update(msg); newModel.(Model).mode != briefingView {
+		t.Error("Expected the briefing shown for a session idle for days")
+	}
+	next.LastActivity = now.Add(-time.Hour)
+	if newModel, _ = model.Update(msg); newModel.(Model).mode != splitView {
+		t.Error("Expected no briefing for a session worked on recently")
+	}
+}
+
+// Test history view filters (session, result, date) and sort orders, and
+// that the summary covers only the shown runs
+func TestHistoryViewFiltersAndSort(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	run := func(id, sessionID, result string, age, duration time.Duration, iterations, done int) *session.AgentRunRecord {
+		start := now.Add(-age)
+		return &session.AgentRunRecord{
+			ID: id, SessionID: sessionID, Result: result, StartedAt: start, EndedAt: start.Add(duration),
+			Iterations: iterations, BallsComplete: done, BallsTotal: 5,
+		}
+	}
+	records := []*session.AgentRunRecord{
+		run("recent", "auth", "complete", time.Hour, 10*time.Minute, 2, 3),
+		run("long", "api", "blocked", 3*24*time.Hour, time.Hour, 8, 1),
+		run("old", "auth", "max_iterations", 20*24*time.Hour, 30*time.Minute, 10, 4),
+	}
+
+	model := Model{mode: splitView, nowFunc: func() time.Time { return now }}
+	newModel, _ := model.Update(historyLoadedMsg{history: records})
+	m := newModel.(Model)
+	press := func(key string) {
+		t.Helper()
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = newModel.(Model)
+	}
+	ids := func() string {
+		var out []string
+		for _, record := range m.agentHistory {
+			out = append(out, record.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := ids(); got != "recent,long,old" {
+		t.Errorf("Expected newest first, got %s", got)
+	}
+	if summary := m.historySummary(); summary != "3 runs: 1 complete, 1 blocked, 1 other | 1h40m total, 6.7 iterations avg | 8/15 balls done" {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+
+	press("s") // api
+	press("s") // auth
+	if got := ids(); got != "recent,old" {
+		t.Errorf("Expected auth runs only, got %s", got)
+	}
+	press("r") // complete, the first result among loaded runs
+	if got := ids(); got != "recent" {
+		t.Errorf("Expected complete auth runs only, got %s", got)
+	}
+	press("c")
+	if got := ids(); got != "recent,long,old" || m.historyFiltered() {
+		t.Errorf("Expected clear to show every run, got %s", got)
+	}
+
+	press("d") // last 24h
+	if got := ids(); got != "recent" {
+		t.Errorf("Expected runs from the last day, got %s", got)
+	}
+	press("d") // last 7 days
+	if got := ids(); got != "recent,long" {
+		t.Errorf("Expected runs from the last week, got %s", got)
+	}
+	press("d") // last 30 days
+	press("d") // all time
+
+	press("j") // Select "long", which should stay selected through sorting
+	press("o")
+	if got := ids(); got != "long,old,recent" {
+		t.Errorf("Expected longest first, got %s", got)
+	}
+	if m.agentHistory[m.historyCursor].ID != "long" {
+		t.Errorf("Expected the selected run to stay selected, got %s", m.agentHistory[m.historyCursor].ID)
+	}
+	press("o")
+	if got := ids(); got != "old,long,recent" {
+		t.Errorf("Expected most iterations first, got %s", got)
+	}
+	press("o")
+	if got := ids(); got != "old,recent,long" {
+		t.Errorf("Expected most balls done first, got %s", got)
+	}
+
+	press("r")
+	press("r") // blocked
+	press("s") // api
+	press("s") // auth: no blocked runs
+	if len(m.agentHistory) != 0 || !strings.Contains(m.renderHistoryView(), "No runs match the filters") {
+		t.Error("Expected an empty filtered view to say so")
+	}
+}
+
+// TestParkedTab verifies snoozed, escalated and waiting balls move to the
+// parked tab, with a count badge on the main list
+func TestParkedTab(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	balls := []*session.Ball{
+		{ID: "test-1", Title: "Actionable", State: session.StatePending, Tags: []string{"test-session"}},
+		{ID: "test-2", Title: "Waiting", State: session.StatePending, Tags: []string{"test-session"}, DependsOn: []string{"test-1"}},
+		{ID: "test-3", Title: "Escalated", State: session.StateBlocked, BlockedReason: "needs keys", Escalation: &session.Escalation{Owner: "ops"}, Tags: []string{"test-session"}},
+		{ID: "test-4", Title: "Snoozed", State: session.StatePending, Recurrence: &session.Recurrence{Every: "7d", DueAt: now.Add(time.Hour)}, Tags: []string{"test-session"}},
+		{ID: "test-5", Title: "Stuck", State: session.StateBlocked, BlockedReason: "flaky test", Tags: []string{"test-session"}},
+	}
+	model := Model{
+		mode:            splitView,
+		activePanel:     BallsPanel,
+		balls:           balls,
+		filteredBalls:   balls,
+		selectedSession: &session.JuggleSession{ID: "test-session"},
+		width:           120,
+		height:          40,
+		nowFunc:         func() time.Time { return now },
+		filterStates: map[string]bool{
+			"pending":     true,
+			"in_progress": true,
+			"blocked":     true,
+			"complete":    true,
+		},
+	}
+
+	ids := func(m Model) string {
+		var out []string
+		for _, ball := range m.filterBallsForSession() {
+			out = append(out, ball.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := ids(model); got != "test-1,test-5" {
+		t.Errorf("Expected only actionable balls on the main list, got %s", got)
+	}
+	if content := model.renderBallsPanel(80, 20); !strings.Contains(content, "[3 parked]") {
+		t.Errorf("Expected a parked count badge, got:\n%s", content)
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	model = newModel.(Model)
+	if got := ids(model); got != "test-2,test-3,test-4" {
+		t.Errorf("Expected parked balls on the parked tab, got %s", got)
+	}
+	if content := model.renderBallsPanel(80, 20); !strings.Contains(content, "[parked, w: back]") {
+		t.Errorf("Expected the parked tab title, got:\n%s", content)
+	}
+
+	newModel, _ = model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	model = newModel.(Model)
+	if model.showParked {
+		t.Error("Expected w to return to the main list")
+	}
+}
+
+// TestAPIHealthIndicator verifies the status bar shows the provider's API
+// health and reloads it when the agent loop reports a call
+func TestAPIHealthIndicator(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	health := session.APIHealthSummary{Provider: "claude", Status: session.APIHealthHealthy, Calls: 3}
+	model := Model{
+		mode:         splitView,
+		width:        200,
+		height:       40,
+		nowFunc:      func() time.Time { return now },
+		agentStatus:  AgentStatus{Running: true, SessionID: "auth", MaxIterations: 5},
+		filterStates: map[string]bool{"pending": true},
+	}
+	if strings.Contains(model.renderStatusBar(), "[API") {
+		t.Error("Expected no indicator without a loader")
+	}
+
+	model.SetAPIHealthLoader(func() (session.APIHealthSummary, error) { return health, nil })
+	if got := model.renderStatusBar(); !strings.Contains(got, "[API claude ok]") {
+		t.Errorf("Expected a healthy indicator, got %q", got)
+	}
+
+	health = session.APIHealthSummary{Provider: "claude", Status: session.APIHealthUnhealthy, Calls: 4, Errors: 2, ErrorRate: 0.5,
+		LastRateLimited: now.Add(-3 * time.Minute)}
+	newModel, _ := model.Update(agentOutputMsg{line: "Working on auth"})
+	model = newModel.(Model)
+	if got := model.renderStatusBar(); !strings.Contains(got, "[API claude ok]") {
+		t.Errorf("Expected agent output to leave the indicator alone, got %q", got)
+	}
+	newModel, _ = model.Update(agentOutputMsg{line: "📶 claude took 2m0s · API unhealthy (4 calls, 50% errors, avg 1m0s)"})
+	model = newModel.(Model)
+	if got := model.renderStatusBar(); !strings.Contains(got, "[API claude unhealthy: 50% err, 429 3m0s ago]") {
+		t.Errorf("Expected the indicator reloaded after an agent call, got %q", got)
+	}
+}
+
+func TestWidthHelpers(t *testing.T) {
+	if got := displayWidth("登录 ok"); got != 7 {
+		t.Errorf("Expected CJK characters to take two cells, got width %d", got)
+	}
+	if got := displayWidth("\x1b[1mbold\x1b[0m"); got != 4 {
+		t.Errorf("Expected ANSI escapes to take no cells, got width %d", got)
+	}
+	if got := fitWidth("登录", 6); got != "登录  " {
+		t.Errorf("Expected padding by cells, got %q", got)
+	}
+	if got := fitWidth("修复登录页面", 7); displayWidth(got) != 7 {
+		t.Errorf("Expected exactly 7 cells, got %q (%d)", got, displayWidth(got))
+	}
+}
+
+// TestBallLineWideCharacters tests that CJK and emoji titles take the same
+// number of cells as ASCII ones, so the panel border stays aligned
+func TestBallLineWideCharacters(t *testing.T) {
+	m := Model{showTagsColumn: true, showPriorityColumn: true}
+	balls := []*session.Ball{
+		{ID: "proj-1", Title: "Fix the login page layout issue", State: session.StatePending, Priority: session.PriorityMedium, Tags: []string{"frontend"}},
+		{ID: "proj-2", Title: "修复登录页面的布局问题", State: session.StatePending, Priority: session.PriorityMedium, Tags: []string{"前端", "界面"}},
+		{ID: "proj-3", Title: "Ship it 🚀👩‍💻", State: session.StatePending, Priority: session.PriorityMedium},
+		{ID: "proj-4", Title: strings.Repeat("很长的标题", 20), State: session.StateBlocked, BlockedReason: "等待设计评审", Priority: session.PriorityMedium},
+	}
+	for _, width := range []int{60, 100} {
+		for _, ball := range balls {
+			line := m.renderBallLine(ball, "1", false, width)
+			if got := displayWidth(line); got > width {
+				t.Errorf("Ball line for %q is %d cells wide, wider than %d: %q", ball.Title, got, width, line)
+			}
+		}
+		ascii := displayWidth(m.renderBallLine(balls[0], "1", false, width))
+		for _, ball := range balls[1:3] {
+			if got := displayWidth(m.renderBallLine(ball, "1", false, width)); got != ascii {
+				t.Errorf("Expected %q to line up with ASCII titles (%d cells), got %d", ball.Title, ascii, got)
+			}
+		}
+	}
+}
+
+func TestCreateBallFromAgentOutputSelection(t *testing.T) {
+	model := Model{
+		mode:               splitView,
+		agentOutputVisible: true,
+		width:              100,
+		height:             40,
+		activityLog:        make([]ActivityEntry, 0),
+		textInput:          textinput.New(),
+		contextInput:       newContextTextarea(),
+		sessions:           []*session.JuggleSession{{ID: "docs"}, {ID: "auth"}},
+	}
+	model.agentStatus.SessionID = "auth"
+
+	model.addAgentOutput("starting up", false)
+	model.addAgentOutput("════ Iteration 1/2 ════", false)
+	model.addAgentOutput("panic: nil map", false)
+	model.addAgentOutput("════ Iteration 2/2 ════", false)
+	model.addAgentOutput("TODO: handle expired tokens", false)
+	model.agentOutputOffset = 1 // The collapsed first iteration
+
+	press := func(key string) {
+		newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		model = newModel.(Model)
+	}
+	press("V")
+	if model.outputSelection == nil {
+		t.Fatal("Expected V to start a selection")
+	}
+	press("j")
+	press("j")
+	press("j") // Clamped at the last line
+	if start, end := model.outputSelection.bounds(); start != 1 || end != 4 {
+		t.Errorf("Expected lines 1-3 selected, got %d-%d", start, end)
+	}
+	view := model.renderAgentOutputPanel(100, 20)
+	if !strings.Contains(view, "TODO: handle expired tokens") {
+		t.Errorf("Expected the selection shown, got:\n%s", view)
+	}
+
+	press("a")
+	if model.mode != unifiedBallFormView || model.outputSelection != nil {
+		t.Fatalf("Expected the ball form opened, got mode %v", model.mode)
+	}
+	context := model.contextInput.Value()
+	for _, want := range []string{"panic: nil map", "TODO: handle expired tokens", "```"} {
+		if !strings.Contains(context, want) {
+			t.Errorf("Expected %q in the context, got:\n%s", want, context)
+		}
+	}
+	if strings.Contains(context, "starting up") {
+		t.Errorf("Expected unselected lines left out, got:\n%s", context)
+	}
+	if model.formSessionID() != "auth" {
+		t.Errorf("Expected the agent's session selected, got %q", model.formSessionID())
+	}
+}
+
+func TestCreateBallFromHistoryOutputSelection(t *testing.T) {
+	model := Model{
+		mode:          historyOutputView,
+		height:        40,
+		activityLog:   make([]ActivityEntry, 0),
+		textInput:     textinput.New(),
+		contextInput:  newContextTextarea(),
+		historyOutput: "build ok\nFAIL: TestLogin\n    expected 200, got 500\ndone",
+		agentHistory:  []*session.AgentRunRecord{{ID: "1760000000", SessionID: "auth"}},
+		sessions:      []*session.JuggleSession{{ID: "auth"}},
+	}
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.Update(msg)
+		model = newModel.(Model)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	press(tea.KeyMsg{Type: tea.KeyEscape})
+	if model.outputSelection != nil || model.mode != historyOutputView {
+		t.Fatal("Expected Esc to cancel the selection and stay in the output view")
+	}
+
+	model.historyOutputOffset = 1
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'V'}})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if view := model.renderHistoryOutputView(); !strings.Contains(view, "a/Enter = create ball") {
+		t.Errorf("Expected the selection help, got:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.mode != unifiedBallFormView {
+		t.Fatalf("Expected the ball form opened, got mode %v", model.mode)
+	}
+	want := "From agent run 1760000000 (lines 2-3):\n```\nFAIL: TestLogin\n    expected 200, got 500\n```"
+	if got := model.contextInput.Value(); got != want {
+		t.Errorf("Expected context %q, got %q", want, got)
+	}
+	if model.formSessionID() != "auth" || model.historyOutput != "" {
+		t.Errorf("Expected the run's session selected and the output closed, got %q", model.formSessionID())
+	}
+}
+
+// TestAgentLaunchForm verifies that L opens a launch form pre-filled from config and that its
+// settings become agent run flags
+func TestAgentLaunchForm(t *testing.T) {
+	sess := &session.JuggleSession{ID: "auth", ProjectDir: "/src/app"}
+	model := Model{
+		mode:            splitView,
+		activePanel:     SessionsPanel,
+		selectedSession: sess,
+		config:          &session.Config{IterationDelayMinutes: 5, IterationDelayFuzz: 2},
+		textInput:       textinput.New(),
+		activityLog:     make([]ActivityEntry, 0),
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	m := newModel.(Model)
+	if m.mode != agentLaunchView || m.launchForm == nil {
+		t.Fatalf("Expected the launch form, got mode %v", m.mode)
+	}
+	if m.launchForm.iterations != "10" || m.launchForm.delay != "5" || m.launchForm.opts.Fuzz != 2 {
+		t.Errorf("Expected the form pre-filled from config, got %+v", m.launchForm)
+	}
+
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := m.handleAgentLaunchKey(msg)
+		m = newModel.(Model)
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(tea.KeyMsg{Type: tea.KeyBackspace})
+	press(runes("3"))
+	press(runes("j"))
+	press(runes("30m"))
+	press(runes("j"))
+	press(tea.KeyMsg{Type: tea.KeyRight}) // Trust
+	press(runes("j"))
+	press(tea.KeyMsg{Type: tea.KeyLeft}) // Auto wraps to haiku
+
+	newModel, cmd := m.handleAgentLaunchKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.mode != splitView || cmd == nil || !m.agentOutputVisible || m.agentOutputCh == nil {
+		t.Fatal("Expected Enter to launch the agent with its output shown")
+	}
+	if m.agentStatus.MaxIterations != 3 {
+		t.Errorf("Expected 3 max iterations, got %d", m.agentStatus.MaxIterations)
+	}
+
+	opts := agentLaunchOptions{SessionID: "auth", Iterations: 3, Timeout: 30 * time.Minute, Trust: true, Model: "haiku", Delay: 5, Fuzz: 2}
+	want := "agent run auth --yes --iterations 3 --delay 5 --fuzz 2 --timeout 30m0s --trust --model haiku"
+	if got := strings.Join(opts.args(), " "); got != want {
+		t.Errorf("args() = %q, want %q", got, want)
+	}
+}
+
+// TestAgentLaunchFormValidation verifies that bad settings keep the form open with an error
+func TestAgentLaunchFormValidation(t *testing.T) {
+	for _, form := range []agentLaunchForm{
+		{iterations: "0", delay: "0"},
+		{iterations: "5", timeout: "soon", delay: "0"},
+		{iterations: "5", delay: "-1"},
+	} {
+		model := Model{mode: agentLaunchView, launchForm: &form, activityLog: make([]ActivityEntry, 0)}
+		newModel, cmd := model.handleAgentLaunchKey(tea.KeyMsg{Type: tea.KeyEnter})
+		m := newModel.(Model)
+		if m.mode != agentLaunchView || cmd != nil || m.launchForm.err == "" {
+			t.Errorf("Expected %+v to be refused, got mode %v", form, m.mode)
+		}
+	}
+
+	model := Model{mode: splitView, activePanel: SessionsPanel, selectedSession: &session.JuggleSession{ID: "auth"},
+		agentStatus: AgentStatus{Running: true}}
+	newModel, _ := model.handleAgentLaunchStart()
+	if m := newModel.(Model); m.mode != splitView || !strings.Contains(m.message, "already running") {
+		t.Errorf("Expected no form while an agent is running, got mode %v", m.mode)
+	}
+}
+
+// TestAgentResumePrompt verifies that interrupted agent runs found at
+// startup are offered for resuming, one at a time
+func TestAgentResumePrompt(t *testing.T) {
+	store, err := session.NewSessionStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create session store: %v", err)
+	}
+	for _, id := range []string{"auth", "docs"} {
+		if err := store.SaveAgentCheckpoint(id, &session.AgentCheckpoint{SessionID: id, Iteration: 2, MaxIterations: 5}); err != nil {
+			t.Fatalf("failed to save checkpoint: %v", err)
+		}
+	}
+	checkpoints, err := store.StaleAgentCheckpoints()
+	if err != nil || len(checkpoints) != 2 {
+		t.Fatalf("expected 2 stale checkpoints, got %d (%v)", len(checkpoints), err)
+	}
+
+	model := Model{mode: splitView, sessionStore: store, activityLog: make([]ActivityEntry, 0)}
+	newModel, _ := model.Update(staleCheckpointsMsg{checkpoints: checkpoints})
+	m := newModel.(Model)
+	if m.mode != confirmAgentResume {
+		t.Fatalf("Expected the resume prompt, got mode %v", m.mode)
+	}
+	if view := m.renderAgentResumeConfirm(); !strings.Contains(view, "2/5 iterations (3 left)") {
+		t.Errorf("Expected the run's progress in the prompt, got:\n%s", view)
+	}
+
+	// d discards the first run's checkpoint and moves on to the next
+	newModel, _ = m.handleAgentResumeKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = newModel.(Model)
+	if m.mode != confirmAgentResume || len(m.staleCheckpoints) != 1 {
+		t.Fatalf("Expected the second run to be offered, got mode %v", m.mode)
+	}
+	if c, _ := store.LoadAgentCheckpoint(checkpoints[0].SessionID); c != nil {
+		t.Errorf("Expected the discarded checkpoint to be removed")
+	}
+
+	// y resumes it with agent resume
+	newModel, cmd := m.handleAgentResumeKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = newModel.(Model)
+	if m.mode != splitView || cmd == nil || !m.agentOutputVisible || m.agentStatus.MaxIterations != 5 {
+		t.Fatalf("Expected y to resume the run, got mode %v", m.mode)
+	}
+	opts := agentLaunchOptions{SessionID: "docs", Resume: true}
+	if got := strings.Join(opts.args(), " "); got != "agent resume docs" {
+		t.Errorf("args() = %q, want %q", got, "agent resume docs")
+	}
+
+	// Nothing is offered while an agent is running
+	model = Model{mode: splitView, agentStatus: AgentStatus{Running: true}, activityLog: make([]ActivityEntry, 0)}
+	newModel, _ = model.Update(staleCheckpointsMsg{checkpoints: checkpoints})
+	if m := newModel.(Model); m.mode != splitView {
+		t.Errorf("Expected no prompt while an agent is running, got mode %v", m.mode)
+	}
+}
+
+// TestArchivedSessionsHiddenByDefault verifies that archived sessions are left out of the
+// sessions panel until w shows them, and that paused sessions are marked
+func TestArchivedSessionsHiddenByDefault(t *testing.T) {
+	active := &session.JuggleSession{ID: "auth"}
+	paused := &session.JuggleSession{ID: "docs", State: session.SessionStatePaused}
+	archived := &session.JuggleSession{ID: "old", State: session.SessionStateArchived}
+	model := Model{
+		mode:        splitView,
+		activePanel: SessionsPanel,
+		localOnly:   true,
+		sessions:    []*session.JuggleSession{active, paused, archived},
+		activityLog: make([]ActivityEntry, 0),
+		width:       120,
+		height:      40,
+	}
+
+	ids := func(m Model) []string {
+		var ids []string
+		for _, sess := range m.filterSessions() {
+			ids = append(ids, sess.ID)
+		}
+		return ids
+	}
+	if got := strings.Join(ids(model), ","); got != PseudoSessionAll+","+PseudoSessionUntagged+",auth,docs" {
+		t.Errorf("Expected the archived session hidden, got %s", got)
+	}
+	if panel := model.renderSessionsPanel(40, 20); !strings.Contains(panel, "docs") || !strings.Contains(panel, icons.Paused) {
+		t.Errorf("Expected the paused session marked, got:\n%s", panel)
+	}
+
+	newModel, _ := model.handleSplitViewKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	m := newModel.(Model)
+	if !m.showArchivedSessions || !strings.HasSuffix(strings.Join(ids(m), ","), ",old") {
+		t.Errorf("Expected w to show the archived session, got %v", ids(m))
+	}
+	if panel := m.renderSessionsPanel(40, 20); !strings.Contains(panel, "Sessions +archived") || !strings.Contains(panel, icons.Archived) {
+		t.Errorf("Expected the archived session marked, got:\n%s", panel)
+	}
+}
+
+func TestSessionGoalLabel(t *testing.T) {
+	partial := &session.JuggleSession{ID: "auth", ExitCriteria: []session.ExitCriterion{
+		{Text: "Tests pass", Met: true},
+		{Text: "Docs updated"},
+	}}
+	reached := &session.JuggleSession{ID: "docs", ExitCriteria: []session.ExitCriterion{{Text: "Published", Met: true}}}
+
+	if got, want := sessionGoalLabel(partial), " "+icons.Goal+"1/2"; got != want {
+		t.Errorf("sessionGoalLabel() = %q, want %q", got, want)
+	}
+	if got, want := sessionGoalLabel(reached), " "+icons.Goal+icons.Checked; got != want {
+		t.Errorf("sessionGoalLabel() = %q, want %q", got, want)
+	}
+	if got := sessionGoalLabel(&session.JuggleSession{ID: "old", Goal: "No criteria"}); got != "" {
+		t.Errorf("Expected no label without exit criteria, got %q", got)
+	}
+
+	model := Model{
+		mode:        splitView,
+		activePanel: SessionsPanel,
+		localOnly:   true,
+		sessions:    []*session.JuggleSession{partial, reached},
+		activityLog: make([]ActivityEntry, 0),
+		width:       120,
+		height:      40,
+	}
+	if panel := model.renderSessionsPanel(40, 20); !strings.Contains(panel, icons.Goal+"1/2") {
+		t.Errorf("Expected the goal indicator on the session row, got:\n%s", panel)
+	}
+}
+
+// Test hooks fired while the TUI is open run as commands and show failures
+// in the message bar and activity log
+func TestHookRunsShowFailures(t *testing.T) {
+	runs := make(chan HookRun, 1)
+	model := Model{
+		mode:        splitView,
+		activityLog: make([]ActivityEntry, 0),
+	}
+	model.SetHookRuns(runs)
+	if model.Init() == nil {
+		t.Fatal("Expected Init to listen for hook runs")
+	}
+
+	ran := false
+	run := HookRun(func() []error {
+		ran = true
+		return []error{fmt.Errorf("on_ball_complete hook failed (sh -c exit 1): exit status 1")}
+	})
+	newModel, cmd := model.Update(hookRunMsg{run: run})
+	if cmd == nil {
+		t.Fatal("Expected a command running the hooks")
+	}
+	if ran {
+		t.Error("Expected the hooks not to run on the update loop")
+	}
+	msg := runHooksCmd(run)()
+	if !ran {
+		t.Error("Expected the hooks to run in the command")
+	}
+
+	newModel, _ = newModel.(Model).Update(msg)
+	m := newModel.(Model)
+	if !strings.Contains(m.message, "Hook failed: on_ball_complete hook failed") {
+		t.Errorf("Expected the failure in the message bar, got %q", m.message)
+	}
+	if len(m.activityLog) != 1 || !strings.Contains(m.activityLog[0].Message, "Hook failed") {
+		t.Errorf("Expected the failure in the activity log, got %+v", m.activityLog)
+	}
+}
@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbletea"
+	"github.com/ohare93/juggle/internal/profile"
 	"github.com/ohare93/juggle/internal/session"
 	"github.com/ohare93/juggle/internal/watcher"
 )
@@ -18,6 +23,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case tea.BlurMsg:
+		m.blurred = true
+		return m, nil
+
+	case tea.FocusMsg:
+		m.blurred = false
+		return m, m.clearAttention()
+
+	case keySequenceTimeoutMsg:
+		return m.handleKeySequenceTimeout(msg)
+
 	case tea.KeyMsg:
 		// Handle unified ball form view (all fields in one view)
 		if m.mode == unifiedBallFormView {
@@ -25,7 +41,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Handle input modes (text entry)
-		if m.mode == inputSessionView || m.mode == inputBallView || m.mode == inputBlockedView || m.mode == inputTagView {
+		if m.mode == inputSessionView || m.mode == inputBallView || m.mode == inputBlockedView || m.mode == inputTagView || m.mode == inputNextActionView || m.mode == inputCheckpointView {
 			return m.handleInputKey(msg)
 		}
 
@@ -34,6 +50,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleSessionSelectorKey(msg)
 		}
 
+		// Handle ball session membership view
+		if m.mode == ballSessionsView {
+			return m.handleBallSessionsKey(msg)
+		}
+
+		// Handle project groups view
+		if m.mode == projectGroupsView {
+			return m.handleProjectGroupsKey(msg)
+		}
+
+		// Handle global search view
+		if m.mode == globalSearchView {
+			return m.handleGlobalSearchKey(msg)
+		}
+
+		// Handle agent signal prompt
+		if m.mode == agentSignalView {
+			return m.handleAgentSignalKey(msg)
+		}
+
+		// Handle agent launch form
+		if m.mode == agentLaunchView {
+			return m.handleAgentLaunchKey(msg)
+		}
+
+		// Handle session briefing
+		if m.mode == briefingView {
+			return m.handleBriefingKey(msg)
+		}
+
+		// Handle attached transcript view
+		if m.mode == transcriptView {
+			return m.handleTranscriptViewKey(msg)
+		}
+
 		// Handle dependency selector mode
 		if m.mode == dependencySelectorView {
 			return m.handleDependencySelectorKey(msg)
@@ -54,6 +105,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleAgentCancelConfirm(msg)
 		}
 
+		// Handle duplicate merge prompt
+		if m.mode == confirmDuplicateMerge {
+			return m.handleDuplicateMergeKey(msg)
+		}
+
+		// Handle interrupted agent run resume prompt
+		if m.mode == confirmAgentResume {
+			return m.handleAgentResumeKey(msg)
+		}
+
 		// Handle split help view
 		if m.mode == splitHelpView {
 			return m.handleSplitHelpKey(msg)
@@ -79,21 +140,74 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
+		selectedID := m.selectedBallID()
+		previous := m.balls
 		m.balls = msg.balls
+		m.archivedBalls = msg.archived
 		m.applyFilters()
-		// Reset cursor if it's out of bounds
-		if m.cursor >= len(m.filteredBalls) {
+		if m.agentStatus.Running || m.agentEditsPending {
+			m.recordAgentEdits(previous, selectedID)
+			m.agentEditsPending = false
+		}
+		// Keep the same ball selected, or reset the cursor if it's out of bounds
+		if !m.reselectBall(selectedID) && m.cursor >= len(m.filteredBalls) {
 			m.cursor = 0
 		}
 		m.addActivity("Balls loaded")
-		return m, nil
+		var attend tea.Cmd
+		if m.checkDuplicates {
+			m.checkDuplicates = false
+			attend = m.detectAgentDuplicates()
+		}
+		if m.fileWatcher != nil {
+			return m, tea.Batch(attend, m.refreshDashboard(), watchBallFiles(m.fileWatcher, m.balls))
+		}
+		return m, tea.Batch(attend, m.refreshDashboard())
+
+	case ballsChangedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		selectedID := m.selectedBallID()
+		previous := m.balls
+		m.balls = patchBalls(m.balls, msg.balls)
+		m.applyFilters()
+		if m.agentStatus.Running || m.agentEditsPending {
+			m.recordAgentEdits(previous, selectedID)
+			m.agentEditsPending = false
+		}
+		if !m.reselectBall(selectedID) && m.cursor >= len(m.filteredBalls) {
+			m.cursor = 0
+		}
+		var attend tea.Cmd
+		if m.checkDuplicates {
+			m.checkDuplicates = false
+			attend = m.detectAgentDuplicates()
+		}
+		if m.fileWatcher != nil {
+			return m, tea.Batch(attend, m.refreshDashboard(), watchBallFiles(m.fileWatcher, msg.balls))
+		}
+		return m, tea.Batch(attend, m.refreshDashboard())
+
+	case staleCheckpointsMsg:
+		return m.offerAgentResume(msg.checkpoints)
 
 	case sessionsLoadedMsg:
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
 		}
+		// Keep the ball form's session selection by ID as the list changes
+		formSessionID := m.formSessionID()
+		formCreatingSession := m.pendingBallSession == len(m.formSessions())+1
 		m.sessions = msg.sessions
+		m.ballsChanged()
+		if formCreatingSession {
+			m.pendingBallSession = len(m.formSessions()) + 1
+		} else {
+			m.selectFormSession(formSessionID)
+		}
 		// Reset session cursor if out of bounds
 		if m.sessionCursor >= len(m.sessions) {
 			m.sessionCursor = 0
@@ -144,6 +258,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		m.addActivity("Sessions loaded")
+		return m, m.refreshDashboard()
+
+	case dashboardLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.dashboard = msg.summaries
 		return m, nil
 
 	case ballUpdatedMsg:
@@ -153,9 +275,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.message = "Ball updated successfully"
 			m.addActivity("Ball updated: " + msg.ball.ID)
+			if msg.warning != "" {
+				m.message = "Warning: " + msg.warning
+			}
 		}
 		// Reload balls
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 
 	case ballArchivedMsg:
 		if msg.err != nil {
@@ -164,13 +289,87 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.message = "Ball archived successfully"
 			m.addActivity("Archived ball: " + msg.ball.ID)
+			if msg.warning != "" {
+				m.message = "Warning: " + msg.warning
+			}
+			if msg.next != nil {
+				m.addActivity("Next instance: " + msg.next.ID + " (due " + msg.next.Recurrence.DueLabel(time.Now()) + ")")
+			}
 		}
 		// Reload balls
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+
+	case ballReopenedMsg:
+		return m.handleBallReopened(msg)
+
+	case recurrencesMaterializedMsg:
+		if msg.err != nil {
+			m.addActivity("Error creating recurring balls: " + msg.err.Error())
+		}
+		for _, ball := range msg.created {
+			m.addActivity("Recurring ball due: " + ball.ID)
+		}
+		if len(msg.created) == 0 {
+			return m, nil
+		}
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+
+	case gitCommitsSyncedMsg:
+		if msg.err != nil {
+			m.addActivity("Error reading git commits: " + msg.err.Error())
+		}
+		if len(msg.changed) == 0 {
+			return m, nil
+		}
+		m.addActivity(fmt.Sprintf("Linked new commits to %d ball(s)", len(msg.changed)))
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
+
+	case searchResultsMsg:
+		return m.handleSearchResults(msg)
+
+	case humanSignalSentMsg:
+		return m.handleHumanSignalSent(msg)
+
+	case agentPauseMsg:
+		return m.handleAgentPauseToggled(msg)
+
+	case briefingHistoryMsg:
+		return m.handleBriefingHistory(msg)
+
+	case undoneMsg:
+		switch {
+		case errors.Is(msg.err, session.ErrNothingToUndo):
+			m.message = "Nothing to undo"
+			return m, nil
+		case errors.Is(msg.err, session.ErrNothingToRedo):
+			m.message = "Nothing to redo"
+			return m, nil
+		case msg.err != nil:
+			m.message = "Error: " + msg.err.Error()
+			m.addActivity("Error: " + msg.err.Error())
+			return m, nil
+		}
+		verb := "Undid"
+		if msg.redo {
+			verb = "Redid"
+		}
+		m.message = fmt.Sprintf("%s %s of %s", verb, msg.entry.Action, msg.entry.BallID)
+		m.addActivity(m.message)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 
 	case watcherEventMsg:
 		return m.handleWatcherEvent(msg.event)
 
+	case hookRunMsg:
+		return m.handleHookRun(msg)
+
+	case hooksRanMsg:
+		return m.handleHooksRan(msg)
+
+	case watchFilesErrorMsg:
+		m.addActivity("Failed to watch ball files: " + msg.err.Error())
+		return m, nil
+
 	case watcherErrorMsg:
 		m.addActivity("Watcher error: " + msg.err.Error())
 		// Continue listening for more events
@@ -200,9 +399,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Running:       true,
 			SessionID:     msg.sessionID,
 			Iteration:     0,
-			MaxIterations: 10, // Default
+			MaxIterations: msg.maxIterations,
 		}
 		m.addActivity("Agent process started for session: " + msg.sessionID)
+		m.snapshotBallIDs()
+		m.agentEdits = nil
 		m.message = "Agent running... (X to cancel)"
 		// Start waiting for the process completion and continue listening for output
 		return m, tea.Batch(
@@ -221,8 +422,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.message = "Agent cancelled"
 		m.addActivity("Agent cancelled for session: " + msg.sessionID)
 		m.addAgentOutput("=== Agent cancelled by user ===", true)
+		m.agentEditsPending = true
 		// Reload balls to reflect any changes made before cancellation
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 
 	case agentIterationMsg:
 		m.agentStatus.Iteration = msg.iteration
@@ -232,35 +434,48 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case agentFinishedMsg:
 		m.agentStatus.Running = false
+		m.refreshAPIHealth()
 		m.agentProcess = nil // Clear process reference
 		// Close and nil out the output channel to prevent goroutine leaks
 		if m.agentOutputCh != nil {
 			close(m.agentOutputCh)
 			m.agentOutputCh = nil
 		}
+		var attend tea.Cmd
 		if msg.err != nil {
 			m.message = "Agent error: " + msg.err.Error()
 			m.addActivity("Agent error: " + msg.err.Error())
 			m.addAgentOutput("=== Agent Error: "+msg.err.Error()+" ===", true)
+			attend = m.attention(session.AttentionBlocked, "agent error on "+msg.sessionID)
 		} else if msg.complete {
 			m.message = "Agent complete!"
 			m.addActivity("Agent completed: " + msg.sessionID)
 			m.addAgentOutput("=== Agent completed ===", false)
+			attend = m.attention(session.AttentionFinished, "agent complete on "+msg.sessionID)
 		} else if msg.blocked {
 			m.message = "Agent blocked: " + msg.blockedReason
 			m.addActivity("Agent blocked: " + msg.blockedReason)
 			m.addAgentOutput("=== Agent blocked: "+msg.blockedReason+" ===", true)
+			attend = m.attention(session.AttentionBlocked, "agent blocked on "+msg.sessionID)
 		} else {
 			m.message = "Agent finished (max iterations)"
 			m.addActivity("Agent finished: max iterations reached")
 			m.addAgentOutput("=== Agent finished (max iterations) ===", false)
+			attend = m.attention(session.AttentionFinished, "agent finished on "+msg.sessionID)
 		}
+		// Check the balls the agent created for duplicates once they're reloaded
+		m.checkDuplicates = m.agentKnownBallIDs != nil
+		m.agentEditsPending = true
 		// Reload balls to reflect any changes
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, tea.Batch(attend, loadBalls(m.store, m.projectCache, m.config, m.localOnly))
 
 	case agentOutputMsg:
 		// Add the output line to our buffer
 		m.addAgentOutput(msg.line, msg.isError)
+		m.agentReportedBalls = append(m.agentReportedBalls, session.ExtractCreatedBallIDs(msg.line)...)
+		if strings.HasPrefix(msg.line, apiHealthMarker) {
+			m.refreshAPIHealth()
+		}
 		// Continue listening for more output if agent is still running
 		if m.agentStatus.Running && m.agentOutputCh != nil {
 			return m, listenForAgentOutput(m.agentOutputCh)
@@ -274,22 +489,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.mode = splitView
 			return m, nil
 		}
-		m.agentHistory = msg.history
-		m.historyCursor = 0
-		m.historyScrollOffset = 0
+		m.historyAll = msg.history
+		m.agentHistory = nil
+		m.applyHistoryView()
 		m.mode = historyView
 		m.addActivity("Loaded agent history: " + strconv.Itoa(len(msg.history)) + " runs")
 		return m, nil
 
 	case historyOutputLoadedMsg:
+		m.closeHistoryOutput()
 		if msg.err != nil {
 			m.historyOutput = "Error loading output: " + msg.err.Error()
 		} else {
 			m.historyOutput = msg.content
+			m.historyLog = msg.log
 		}
 		m.historyOutputOffset = 0
 		m.mode = historyOutputView
 		return m, nil
+
+	case transcriptLoadedMsg:
+		if msg.err != nil {
+			m.transcriptContent = "Error loading transcript: " + msg.err.Error()
+		} else {
+			m.transcriptContent = msg.content
+		}
+		m.transcriptOffset = 0
+		return m, nil
 	}
 
 	return m, nil
@@ -334,6 +560,14 @@ func (m Model) handleEditorResult(msg editorResultMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
+	// Selecting agent output lines to create a ball from
+	if m.outputSelection != nil {
+		if m.agentOutputVisible {
+			return m.handleOutputSelectionKey(msg)
+		}
+		m.outputSelection = nil
+	}
+
 	// Handle two-key sequences for state changes
 	if m.pendingKeySequence == "s" {
 		m.pendingKeySequence = ""
@@ -364,6 +598,12 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleMoveKeySequence(key, true) // true = append to sessions
 	}
 
+	// Handle two-key sequences for the next action (na)
+	if m.pendingKeySequence == "n" {
+		m.pendingKeySequence = ""
+		return m.handleNextActionKeySequence(key)
+	}
+
 	switch key {
 	case "ctrl+c", "q":
 		return m, tea.Quit
@@ -378,6 +618,9 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.activePanel = ActivityPanel
 		case ActivityPanel:
 			m.activePanel = SessionsPanel
+			if m.layout == LayoutList {
+				m.activePanel = BallsPanel // Sessions panel is hidden
+			}
 		}
 		return m, nil
 
@@ -389,6 +632,9 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.activePanel = ActivityPanel
 		case BallsPanel:
 			m.activePanel = SessionsPanel
+			if m.layout == LayoutList {
+				m.activePanel = ActivityPanel // Sessions panel is hidden
+			}
 		case ActivityPanel:
 			m.activePanel = BallsPanel
 		}
@@ -399,7 +645,9 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.message = ""
 		switch m.activePanel {
 		case BallsPanel:
-			m.activePanel = SessionsPanel
+			if m.layout != LayoutList {
+				m.activePanel = SessionsPanel
+			}
 		case ActivityPanel:
 			m.activePanel = BallsPanel
 			// From SessionsPanel, left does nothing (left edge)
@@ -494,7 +742,7 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m.handleAgentOutputGoToTop()
 			}
 			m.lastKey = "g"
-			return m, nil
+			return m, m.armKeySequenceTimeout()
 		}
 		// Handle gg for go to top of activity log
 		if m.activePanel == ActivityPanel {
@@ -503,7 +751,7 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m.handleActivityLogGoToTop()
 			}
 			m.lastKey = "g"
-			return m, nil
+			return m, m.armKeySequenceTimeout()
 		}
 		return m, nil
 
@@ -569,27 +817,35 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "s":
 		// Start two-key sequence for state changes (sc=complete, sb=blocked, ss=start, sp=pending, sa=archive)
 		if m.activePanel == BallsPanel {
-			m.pendingKeySequence = "s"
-			m.message = "s: State change... (c=complete, s=start, b=blocked, p=pending, a=archive)"
-			return m, nil
+			return m, m.startKeySequence("s")
 		}
 		return m, nil
 
 	case "t":
 		// Start two-key sequence for toggle filters (tc=complete, tb=blocked, ti=in_progress, tp=pending)
-		m.pendingKeySequence = "t"
-		m.message = "t: Toggle filter... (c=complete, b=blocked, i=in_progress, p=pending, a=all)"
-		return m, nil
+		return m, m.startKeySequence("t")
 
 	case "R":
 		// Refresh
 		m.message = "Reloading..."
 		m.addActivity("Refreshing data...")
 		return m, tea.Batch(
-			loadBalls(m.store, m.config, m.localOnly),
-			loadSessions(m.sessionStore, m.config, m.localOnly),
+			loadBalls(m.store, m.projectCache, m.config, m.localOnly),
+			loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly),
 		)
 
+	case "u":
+		// Undo the last ball change in this project
+		return m, undoBallChange(m.store, false)
+
+	case "U":
+		// Redo the last undone ball change
+		return m, undoBallChange(m.store, true)
+
+	case "F":
+		// Full-text search across balls, sessions and archives
+		return m.handleGlobalSearchStart()
+
 	case "?":
 		// Show comprehensive help view
 		m.helpScrollOffset = 0 // Reset scroll position
@@ -639,29 +895,48 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleToggleSortOrder()
 
 	case "v":
-		// Start two-key sequence for view column toggles (vp=priority, vt=tags, vs=tests, va=all)
+		// Start two-key sequence for view column toggles (vp=priority, vt=tags, vs=tests, va=all, vv=layout)
 		if m.activePanel == BallsPanel {
-			m.pendingKeySequence = "v"
-			m.message = "v: View columns... (p=priority, t=tags, s=tests, a=all)"
-			return m, nil
+			return m, m.startKeySequence("v")
 		}
 		return m, nil
 
 	case "m":
 		// Start two-key sequence for moving ball to session (m+1-9,0)
 		if m.activePanel == BallsPanel {
-			m.pendingKeySequence = "m"
-			m.message = "m: Move ball to session... (1-9,0 = session number)"
-			return m, nil
+			return m, m.startKeySequence("m")
 		}
 		return m, nil
 
 	case "M":
 		// Start two-key sequence for appending ball to session (M+1-9,0)
 		if m.activePanel == BallsPanel {
-			m.pendingKeySequence = "M"
-			m.message = "M: Add ball to session... (1-9,0 = session number, keeps existing)"
-			return m, nil
+			return m, m.startKeySequence("M")
+		}
+		return m, nil
+
+	case "n":
+		// Start two-key sequence for the next action (na=set)
+		if m.activePanel == BallsPanel {
+			return m, m.startKeySequence("n")
+		}
+		return m, nil
+
+	case "S":
+		// Show the highlighted ball's sessions (jump to one, add/remove membership)
+		if m.activePanel == BallsPanel || (m.activePanel == ActivityPanel && m.bottomPaneMode != BottomPaneActivity) {
+			return m.handleBallSessionsStart()
+		}
+		return m, nil
+
+	case "C":
+		// Collapse/expand project groups (all-projects mode)
+		return m.handleProjectGroupsStart()
+
+	case "T":
+		// Read transcripts attached to the highlighted ball
+		if m.activePanel == BallsPanel || (m.activePanel == ActivityPanel && m.bottomPaneMode != BottomPaneActivity) {
+			return m.handleTranscriptStart()
 		}
 		return m, nil
 
@@ -687,14 +962,72 @@ func (m Model) handleSplitViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "z":
+		// Fold/unfold the iteration at the top of the agent output panel
+		if m.agentOutputVisible {
+			return m.handleAgentOutputToggleFold()
+		}
+		return m, nil
+
+	case "Z":
+		// Fold/unfold every iteration in the agent output panel
+		if m.agentOutputVisible {
+			return m.handleAgentOutputToggleAllFolds()
+		}
+		return m, nil
+
+	case "V":
+		// Select agent output lines to create a ball from
+		if m.agentOutputVisible {
+			return m.handleStartOutputSelection()
+		}
+		return m, nil
+
 	case "X":
 		// Cancel running agent (with confirmation)
 		return m.handleCancelAgent()
 
+	case "!":
+		// Signal the selected session's running agent
+		return m.handleAgentSignalStart()
+
+	case "p":
+		// Pause the selected session's running agent after its iteration, or resume it
+		return m.handleAgentPauseToggle()
+
+	case "L":
+		// Launch an agent on the selected session, after reviewing its settings
+		return m.handleAgentLaunchStart()
+
+	case "b":
+		// Show the highlighted session's briefing
+		if m.activePanel == SessionsPanel {
+			return m.handleBriefingStart()
+		}
+		return m, nil
+
 	case "H":
 		// Show agent history view
 		return m.handleShowHistory()
 
+	case "w":
+		// Switch between actionable balls and the parked tab, or show
+		// archived sessions
+		if m.activePanel == BallsPanel {
+			return m.handleToggleParked()
+		}
+		if m.activePanel == SessionsPanel {
+			return m.handleToggleArchivedSessions()
+		}
+		return m, nil
+
+	case "f":
+		// Flag the ball for the next agent run to work on first
+		if m.activePanel == BallsPanel {
+			return m.handleToggleFocus()
+		}
+		return m, nil
+
 	case "y":
 		// Copy ball ID to clipboard (in balls panel)
 		if m.activePanel == BallsPanel {
@@ -724,7 +1057,7 @@ func (m Model) handleSplitDeletePrompt() (tea.Model, tea.Cmd) {
 		}
 		// Prevent deleting pseudo-sessions
 		sess := sessions[m.sessionCursor]
-		if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+		if isPseudoSession(sess.ID) {
 			m.message = "Cannot delete built-in session"
 			return m, nil
 		}
@@ -795,7 +1128,7 @@ func (m Model) executeSplitDelete() (tea.Model, tea.Cmd) {
 		}
 		sess := sessions[m.sessionCursor]
 		// Double-check we're not deleting a pseudo-session (shouldn't happen due to guard in handleSplitDeletePrompt)
-		if sess.ID == PseudoSessionAll || sess.ID == PseudoSessionUntagged {
+		if isPseudoSession(sess.ID) {
 			m.message = "Cannot delete built-in session"
 			m.mode = splitView
 			return m, nil
@@ -813,7 +1146,7 @@ func (m Model) executeSplitDelete() (tea.Model, tea.Cmd) {
 		if m.selectedSession != nil && m.selectedSession.ID == sess.ID {
 			m.selectedSession = nil
 		}
-		return m, loadSessions(m.sessionStore, m.config, m.localOnly)
+		return m, loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly)
 
 	case "delete_ball":
 		// Use pendingDeleteBalls if available, otherwise fall back to cursor
@@ -859,7 +1192,7 @@ func (m Model) executeSplitDelete() (tea.Model, tea.Cmd) {
 		m.pendingDeleteBalls = nil
 		m.selectedBalls = make(map[string]bool)
 		m.mode = splitView
-		return m, loadBalls(m.store, m.config, m.localOnly)
+		return m, loadBalls(m.store, m.projectCache, m.config, m.localOnly)
 	}
 
 	m.mode = splitView
@@ -923,18 +1256,30 @@ func (m Model) handlePanelSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // filterSessions returns sessions filtered by the panel search query
-// It prepends pseudo-sessions ("All" and "Untagged") at the top
+// It prepends pseudo-sessions ("All", "Untagged" and, in all-projects mode,
+// "Dashboard") at the top
 func (m *Model) filterSessions() []*session.JuggleSession {
 	// Create pseudo-sessions
 	pseudoSessions := []*session.JuggleSession{
 		{ID: PseudoSessionAll, Description: "All balls across all sessions"},
 		{ID: PseudoSessionUntagged, Description: "Balls with no session tags"},
 	}
+	if !m.localOnly {
+		pseudoSessions = append(pseudoSessions, &session.JuggleSession{ID: PseudoSessionDashboard, Description: "Summary of each project"})
+	}
 
-	// Combine pseudo-sessions with real sessions
+	// Combine pseudo-sessions with real sessions, leaving out archived
+	// sessions unless they were asked for
 	allSessions := make([]*session.JuggleSession, 0, len(pseudoSessions)+len(m.sessions))
 	allSessions = append(allSessions, pseudoSessions...)
-	allSessions = append(allSessions, m.sessions...)
+	for _, sess := range m.sessions {
+		if m.showArchivedSessions || !sess.IsArchived() {
+			allSessions = append(allSessions, sess)
+		}
+	}
+	if m.groupByProject() {
+		allSessions = m.groupSessionsByProject(allSessions)
+	}
 
 	if !m.panelSearchActive || m.panelSearchQuery == "" {
 		return allSessions
@@ -953,7 +1298,41 @@ func (m *Model) filterSessions() []*session.JuggleSession {
 
 // filterBallsForSession returns balls filtered by session and search query, sorted by current sort order
 func (m *Model) filterBallsForSession() []*session.Ball {
-	balls := m.getBallsForSession()
+	cache := m.currentBallView()
+	if cache != nil && cache.sorted != nil {
+		return cache.sorted
+	}
+
+	// Sort a copy: the search result may be cached too
+	searched := m.searchBallsForSession()
+	result := make([]*session.Ball, len(searched))
+	copy(result, searched)
+
+	// Apply sorting
+	m.sortBalls(result)
+	if m.layout == LayoutBoard {
+		sortBallsForBoard(result)
+	} else if m.groupByProject() {
+		result = m.groupBallsByProject(result)
+	}
+	if cache != nil {
+		cache.sorted = result
+	}
+	return result
+}
+
+// searchBallsForSession returns balls in the current session that match the
+// search query, unsorted. Parked balls are only returned on the parked tab.
+func (m *Model) searchBallsForSession() []*session.Ball {
+	cache := m.currentBallView()
+	if cache != nil && cache.searched != nil {
+		return cache.searched
+	}
+
+	balls, parked := m.splitParked(m.getBallsForSession())
+	if m.showParked {
+		balls = parked
+	}
 
 	var result []*session.Ball
 	if !m.panelSearchActive || m.panelSearchQuery == "" {
@@ -969,62 +1348,70 @@ func (m *Model) filterBallsForSession() []*session.Ball {
 		}
 		result = filtered
 	}
-
-	// Apply sorting
-	m.sortBalls(result)
+	if cache != nil {
+		cache.searched = result
+		cache.parked = len(parked)
+	}
 	return result
 }
 
-// sortBalls sorts a slice of balls according to the current sort order
+// sortBalls sorts a slice of balls according to the current sort order.
+// The sort is stable, and balls equal on the sort order are ordered by
+// compareBallsTiebreak, so equal balls don't swap places on every refresh.
 func (m *Model) sortBalls(balls []*session.Ball) {
+	var compare func(a, b *session.Ball) int
 	switch m.sortOrder {
 	case SortByIDASC:
-		sort.Slice(balls, func(i, j int) bool {
-			return compareBallIDs(balls[i].ID, balls[j].ID) < 0
-		})
+		compare = func(a, b *session.Ball) int { return compareBallIDs(a.ID, b.ID) }
 	case SortByIDDESC:
-		sort.Slice(balls, func(i, j int) bool {
-			return compareBallIDs(balls[i].ID, balls[j].ID) > 0
-		})
+		compare = func(a, b *session.Ball) int { return compareBallIDs(b.ID, a.ID) }
 	case SortByPriorityDESC:
-		sort.Slice(balls, func(i, j int) bool {
-			// Higher priority first
-			if balls[i].PriorityWeight() != balls[j].PriorityWeight() {
-				return balls[i].PriorityWeight() > balls[j].PriorityWeight()
-			}
-			// Then by ID ascending
-			return compareBallIDs(balls[i].ID, balls[j].ID) < 0
-		})
+		// Higher priority first
+		compare = func(a, b *session.Ball) int { return cmp.Compare(b.PriorityWeight(), a.PriorityWeight()) }
 	case SortByPriorityASC:
-		sort.Slice(balls, func(i, j int) bool {
-			// Lower priority first
-			if balls[i].PriorityWeight() != balls[j].PriorityWeight() {
-				return balls[i].PriorityWeight() < balls[j].PriorityWeight()
-			}
-			// Then by ID ascending
-			return compareBallIDs(balls[i].ID, balls[j].ID) < 0
-		})
+		// Lower priority first
+		compare = func(a, b *session.Ball) int { return cmp.Compare(a.PriorityWeight(), b.PriorityWeight()) }
 	case SortByLastActivityDESC:
-		sort.Slice(balls, func(i, j int) bool {
-			// More recent first
-			return balls[i].LastActivity.After(balls[j].LastActivity)
-		})
+		// More recent first
+		compare = func(a, b *session.Ball) int { return b.LastActivity.Compare(a.LastActivity) }
 	case SortByLastActivityASC:
-		sort.Slice(balls, func(i, j int) bool {
-			// Older activity first
-			return balls[i].LastActivity.Before(balls[j].LastActivity)
-		})
+		// Older activity first
+		compare = func(a, b *session.Ball) int { return a.LastActivity.Compare(b.LastActivity) }
 	case SortByCreatedAtDESC:
-		sort.Slice(balls, func(i, j int) bool {
-			// Newer creation time first (StartedAt is set at creation time)
-			return balls[i].StartedAt.After(balls[j].StartedAt)
-		})
+		// Newer creation time first (StartedAt is set at creation time)
+		compare = func(a, b *session.Ball) int { return b.StartedAt.Compare(a.StartedAt) }
 	case SortByCreatedAtASC:
-		sort.Slice(balls, func(i, j int) bool {
-			// Older creation time first
-			return balls[i].StartedAt.Before(balls[j].StartedAt)
-		})
+		// Older creation time first
+		compare = func(a, b *session.Ball) int { return a.StartedAt.Compare(b.StartedAt) }
+	case SortByCustom:
+		if m.customSort != nil {
+			compare = m.customSort.Expr.Compare
+		}
+	}
+	sort.SliceStable(balls, func(i, j int) bool {
+		if compare != nil {
+			if c := compare(balls[i], balls[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return compareBallsTiebreak(balls[i], balls[j]) < 0
+	})
+}
+
+// compareBallsTiebreak orders balls the sort order leaves equal: higher
+// priority first, then focused balls (the human's pick for the next run),
+// then by ID ascending
+func compareBallsTiebreak(a, b *session.Ball) int {
+	if c := cmp.Compare(b.PriorityWeight(), a.PriorityWeight()); c != 0 {
+		return c
 	}
+	if a.Focused != b.Focused {
+		if a.Focused {
+			return -1
+		}
+		return 1
+	}
+	return compareBallIDs(a.ID, b.ID)
 }
 
 // compareBallIDs compares two ball IDs numerically
@@ -1069,20 +1456,34 @@ func extractBallNumber(id string) int {
 
 // handleWatcherEvent handles file system change events
 func (m Model) handleWatcherEvent(event watcher.Event) (tea.Model, tea.Cmd) {
+	if !event.Time.IsZero() {
+		profile.Record(profile.KindWatcher, event.Type.String(), time.Since(event.Time))
+	}
+
 	var cmds []tea.Cmd
 
 	switch event.Type {
 	case watcher.BallsChanged:
-		m.addActivity("File changed: balls.jsonl - reloading...")
-		cmds = append(cmds, loadBalls(m.store, m.config, m.localOnly))
+		projectDir := juggleProjectDir(event.Path)
+		m.projectCache.Invalidate(projectDir)
+		// Only the changed balls need reloading; removals reload everything
+		// so dependencies and filters see them gone
+		if event.Diffed && len(event.RemovedBallIDs) == 0 && projectDir != "" {
+			m.addActivity(fmt.Sprintf("File changed: balls.jsonl - reloading %d ball(s)...", len(event.BallIDs)))
+			cmds = append(cmds, loadChangedBalls(m.projectCache, projectDir, event.BallIDs))
+		} else {
+			m.addActivity("File changed: balls.jsonl - reloading...")
+			cmds = append(cmds, loadBalls(m.store, m.projectCache, m.config, m.localOnly))
+		}
 
 	case watcher.SessionChanged:
 		msg := "Session file changed"
 		if event.SessionID != "" {
 			msg += ": " + event.SessionID
 		}
+		m.projectCache.Invalidate(juggleProjectDir(event.Path))
 		m.addActivity(msg + " - reloading...")
-		cmds = append(cmds, loadSessions(m.sessionStore, m.config, m.localOnly))
+		cmds = append(cmds, loadSessions(m.sessionStore, m.projectCache, m.config, m.localOnly))
 
 	case watcher.ProgressChanged:
 		msg := "Progress updated"
@@ -1092,6 +1493,9 @@ func (m Model) handleWatcherEvent(event watcher.Event) (tea.Model, tea.Cmd) {
 		m.addActivity(msg)
 		// Progress changes don't require reloading UI data,
 		// but log it for awareness
+
+	case watcher.FilesChanged:
+		m.markCodeChanged(event)
 	}
 
 	// Continue listening for more events
@@ -1102,6 +1506,50 @@ func (m Model) handleWatcherEvent(event watcher.Event) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// juggleProjectDir returns the project a file under its .juggle directory
+// belongs to, or "" if the path isn't under one
+func juggleProjectDir(path string) string {
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if filepath.Base(dir) == ".juggle" {
+			return filepath.Dir(dir)
+		}
+	}
+	return ""
+}
+
+// markCodeChanged flags balls whose watch globs match a changed source file.
+// Each ball is logged once, when it is first flagged.
+func (m *Model) markCodeChanged(event watcher.Event) {
+	relPath, err := filepath.Rel(event.ProjectDir, event.Path)
+	if err != nil {
+		return
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, ball := range m.balls {
+		if ball.WorkingDir != event.ProjectDir || ball.State == session.StateComplete {
+			continue
+		}
+		glob, ok := ball.MatchesWatchPath(relPath)
+		if !ok {
+			continue
+		}
+		if m.codeChanged == nil {
+			m.codeChanged = make(map[string]string)
+		}
+		if _, flagged := m.codeChanged[ball.ID]; !flagged {
+			m.addActivity(fmt.Sprintf("Code changed for %s: %s (watching %s)", ball.ShortID(), relPath, glob))
+		}
+		m.codeChanged[ball.ID] = relPath
+	}
+}
+
+// clearCodeChanged dismisses a ball's code-changed flag once it's opened for
+// editing, so it only marks changes the user hasn't looked at yet
+func (m *Model) clearCodeChanged(ballID string) {
+	delete(m.codeChanged, ballID)
+}
+
 // handleSplitHelpKey handles keyboard input in split help view
 func (m Model) handleSplitHelpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -6,10 +6,13 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ohare93/juggle/internal/profile"
 	"github.com/ohare93/juggle/internal/session"
 )
 
 func (m Model) View() string {
+	defer profile.Start(profile.KindRender, "frame")()
+
 	if m.err != nil {
 		return errorStyle.Render(fmt.Sprintf("Error: %v\n\nPress q to quit", m.err))
 	}
@@ -19,7 +22,7 @@ func (m Model) View() string {
 		return m.renderSplitView()
 	case splitHelpView:
 		return m.renderSplitHelpView()
-	case inputSessionView, inputBallView, inputBlockedView:
+	case inputSessionView, inputBallView, inputBlockedView, inputNextActionView, inputCheckpointView:
 		return m.renderInputView()
 	case unifiedBallFormView:
 		return m.renderUnifiedBallFormView()
@@ -27,12 +30,30 @@ func (m Model) View() string {
 		return m.renderTagView()
 	case sessionSelectorView:
 		return m.renderSessionSelectorView()
+	case ballSessionsView:
+		return m.renderBallSessionsView()
+	case transcriptView:
+		return m.renderTranscriptView()
+	case projectGroupsView:
+		return m.renderProjectGroupsView()
+	case globalSearchView:
+		return m.renderGlobalSearchView()
+	case agentSignalView:
+		return m.renderAgentSignalView()
+	case agentLaunchView:
+		return m.renderAgentLaunchView()
+	case briefingView:
+		return m.renderBriefingView()
 	case dependencySelectorView:
 		return m.renderDependencySelectorView()
 	case confirmSplitDelete:
 		return m.renderSplitConfirmDelete()
 	case confirmAgentCancel:
 		return m.renderAgentCancelConfirm()
+	case confirmDuplicateMerge:
+		return m.renderDuplicateMergeConfirm()
+	case confirmAgentResume:
+		return m.renderAgentResumeConfirm()
 	case panelSearchView:
 		return m.renderPanelSearchView()
 	case historyView:
@@ -65,6 +86,10 @@ func (m Model) renderInputView() string {
 		}
 	case inputBlockedView:
 		title = "Block Ball"
+	case inputNextActionView:
+		title = "Next Action"
+	case inputCheckpointView:
+		title = "Checkpoint"
 	}
 
 	titleStyled := lipgloss.NewStyle().
@@ -86,7 +111,7 @@ func (m Model) renderInputView() string {
 		if m.selectedSession != nil && m.inputAction == actionAdd {
 			b.WriteString(fmt.Sprintf("Session: %s\n\n", m.selectedSession.ID))
 		}
-	case inputBlockedView:
+	case inputBlockedView, inputNextActionView, inputCheckpointView:
 		if m.editingBall != nil {
 			b.WriteString(fmt.Sprintf("Ball: %s\n", m.editingBall.ID))
 			b.WriteString(fmt.Sprintf("Title: %s\n\n", m.editingBall.Title))
@@ -228,6 +253,46 @@ func (m Model) renderAgentCancelConfirm() string {
 	return b.String()
 }
 
+// renderDuplicateMergeConfirm renders the merge prompt for a duplicate ball created by the agent
+func (m Model) renderDuplicateMergeConfirm() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("3")). // Yellow
+		Render("Possible Duplicate Ball")
+	b.WriteString(title + "\n\n")
+
+	if len(m.pendingDuplicates) == 0 {
+		return b.String()
+	}
+	d := m.pendingDuplicates[0]
+
+	info := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")). // Gray
+		Render(fmt.Sprintf("The agent created a ball that looks like another (%.0f%% similar title).", d.Similarity*100))
+	b.WriteString(info + "\n\n")
+
+	b.WriteString(fmt.Sprintf("New:      %s  %s\n", d.Ball.ID, d.Ball.Title))
+	b.WriteString(fmt.Sprintf("Existing: %s  %s\n", d.Original.ID, d.Original.Title))
+	if len(m.pendingDuplicates) > 1 {
+		b.WriteString(fmt.Sprintf("\n%d more after this\n", len(m.pendingDuplicates)-1))
+	}
+	b.WriteString("\n")
+
+	prompt := lipgloss.NewStyle().
+		Bold(true).
+		Render(fmt.Sprintf("Merge %s into %s? [m/N]", d.Ball.ShortID(), d.Original.ShortID()))
+	b.WriteString(prompt + "\n\n")
+
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Render("m = merge (criteria, tags, and dependents move to the existing ball) | n = keep both | Esc = keep all")
+	b.WriteString(help)
+
+	return b.String()
+}
+
 // renderPanelSearchView renders the search/filter input dialog
 func (m Model) renderPanelSearchView() string {
 	var b strings.Builder
@@ -338,7 +403,7 @@ func (m Model) renderSessionSelectorView() string {
 			// Checkbox indicator
 			checkbox := "[ ] "
 			if m.sessionSelectActive != nil && m.sessionSelectActive[sess.ID] {
-				checkbox = "[✓] "
+				checkbox = "[" + icons.Checked + "] "
 			}
 
 			line := fmt.Sprintf("%s%s%s", cursor, checkbox, sess.ID)
@@ -388,6 +453,69 @@ func (m Model) renderSessionSelectorView() string {
 	return b.String()
 }
 
+// renderBallSessionsView renders the sessions the highlighted ball belongs to,
+// with every other session available to add it to
+func (m Model) renderBallSessionsView() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")).
+		Render("Ball Sessions")
+	b.WriteString(title + "\n\n")
+
+	if m.editingBall == nil {
+		return b.String()
+	}
+	b.WriteString(fmt.Sprintf("Ball: %s\n", m.editingBall.ID))
+	b.WriteString(fmt.Sprintf("Title: %s\n\n", m.editingBall.Title))
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("240")).
+		Foreground(lipgloss.Color("15"))
+	memberStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("2")) // Green for member sessions
+	normalStyle := lipgloss.NewStyle()
+
+	for i, sess := range m.sessionSelectItems {
+		cursor := "  "
+		if i == m.sessionSelectIndex {
+			cursor = "> "
+		}
+		member := ballHasSessionTag(m.editingBall, sess.ID)
+		checkbox := "[ ] "
+		if member {
+			checkbox = "[" + icons.Checked + "] "
+		}
+
+		line := fmt.Sprintf("%s%s%s", cursor, checkbox, sess.ID)
+		if sess.Description != "" {
+			line += fmt.Sprintf(" - %s", truncate(sess.Description, 35))
+		}
+
+		if i == m.sessionSelectIndex {
+			b.WriteString(selectedStyle.Render(line) + "\n")
+		} else if member {
+			b.WriteString(memberStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(normalStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString("\n")
+
+	if m.message != "" {
+		b.WriteString(messageStyle.Render(m.message) + "\n\n")
+	}
+
+	help := lipgloss.NewStyle().
+		Faint(true).
+		Render("j/k = navigate | Space = add/remove | Enter = jump to session | Esc = close")
+	b.WriteString(help)
+
+	return b.String()
+}
+
 // renderDependencySelectorView renders the dependency selection dialog
 func (m Model) renderDependencySelectorView() string {
 	var b strings.Builder
@@ -430,7 +558,7 @@ func (m Model) renderDependencySelectorView() string {
 			// Checkbox
 			checkbox := "[ ]"
 			if m.dependencySelectActive[ball.ID] {
-				checkbox = "[✓]"
+				checkbox = "[" + icons.Checked + "]"
 			}
 
 			// Ball info
@@ -569,160 +697,8 @@ func (m Model) renderSplitHelpView() string {
 	title := titleStyle.Render("Juggle TUI - Complete Keybindings Reference")
 	b.WriteString(title + "\n\n")
 
-	// Build all help sections - organized by category
-	sections := []struct {
-		title string
-		items []helpItem
-	}{
-		{
-			title: "Navigation",
-			items: []helpItem{
-				{"Tab / l", "Next panel (Sessions → Balls → Activity)"},
-				{"Shift+Tab / h", "Previous panel"},
-				{"j / ↓", "Move down / Scroll down"},
-				{"k / ↑", "Move up / Scroll up"},
-				{"Enter", "Select item / Expand"},
-				{"Space", "Go back (in Balls panel)"},
-				{"Esc", "Back / Deselect / Close"},
-			},
-		},
-		{
-			title: "Sessions Panel",
-			items: []helpItem{
-				{"j/k", "Navigate sessions (auto-selects)"},
-				{"Enter", "Select session and go to balls panel"},
-				{"a", "Add new session"},
-				{"e", "Edit session description"},
-				{"d", "Delete session (with confirmation)"},
-				{"/", "Filter sessions"},
-				{"Ctrl+U", "Clear filter"},
-			},
-		},
-		{
-			title: "Balls Panel - State Changes (s + key)",
-			items: []helpItem{
-				{"s", "Start two-key state change sequence:"},
-				{"  sc", "  Complete ball (→ complete, archives)"},
-				{"  ss", "  Start ball (→ in_progress)"},
-				{"  sb", "  Block ball (prompts for reason)"},
-				{"  sp", "  Set to pending"},
-				{"  sa", "  Archive completed ball"},
-			},
-		},
-		{
-			title: "Balls Panel - Toggle Filters (t + key)",
-			items: []helpItem{
-				{"t", "Start two-key toggle filter sequence:"},
-				{"  tc", "  Toggle complete balls visibility"},
-				{"  tb", "  Toggle blocked balls visibility"},
-				{"  ti", "  Toggle in_progress balls visibility"},
-				{"  tp", "  Toggle pending balls visibility"},
-				{"  ta", "  Show all states"},
-			},
-		},
-		{
-			title: "Balls Panel - Other Actions",
-			items: []helpItem{
-				{"j/k", "Navigate balls"},
-				{"a", "Add new ball (tagged to current session)"},
-				{"A", "Add followup ball (depends on selected ball)"},
-				{"e", "Edit ball in $EDITOR (YAML format)"},
-				{"d", "Delete ball (with confirmation)"},
-				{"[ / ]", "Switch session (previous / next)"},
-				{"o", "Toggle sort order (ID↑ → ID↓ → Priority → Activity)"},
-				{"/", "Filter balls"},
-				{"Ctrl+U", "Clear filter"},
-			},
-		},
-		{
-			title: "Activity Log Panel",
-			items: []helpItem{
-				{"j/k", "Scroll one line"},
-				{"Ctrl+D", "Page down (half screen)"},
-				{"Ctrl+U", "Page up (half screen)"},
-				{"gg", "Go to top"},
-				{"G", "Go to bottom"},
-			},
-		},
-		{
-			title: "Balls Panel - View Columns (v + key)",
-			items: []helpItem{
-				{"v", "Start two-key view columns sequence:"},
-				{"  vp", "  Toggle priority column visibility"},
-				{"  vt", "  Toggle tags column visibility"},
-				{"  vs", "  Toggle tests state column visibility"},
-				{"  va", "  Toggle all optional columns on/off"},
-			},
-		},
-		{
-			title: "Balls Panel - Session Shortcuts (m/M + digit)",
-			items: []helpItem{
-				{"m", "Start two-key move ball sequence:"},
-				{"  m1-m9,m0", "  Move ball to session 1-9 or 10 (replaces all sessions)"},
-				{"M", "Start two-key append session sequence:"},
-				{"  M1-M9,M0", "  Add ball to session 1-9 or 10 (keeps existing sessions)"},
-				{"Backspace", "Remove ball from current session"},
-			},
-		},
-		{
-			title: "View Options",
-			items: []helpItem{
-				{"i", "Cycle bottom pane (activity → detail → split → activity)"},
-				{"O", "Toggle agent output panel (shows live agent stdout)"},
-				{"P", "Toggle project scope (local ↔ all projects)"},
-				{"R", "Refresh / Reload data"},
-				{"?", "Toggle this help"},
-			},
-		},
-		{
-			title: "Agent Control",
-			items: []helpItem{
-				{"X", "Cancel running agent (with confirmation)"},
-				{"O", "Toggle agent output visibility"},
-				{"H", "View agent run history"},
-			},
-		},
-		{
-			title: "Bottom Pane Modes",
-			items: []helpItem{
-				{"[Act]", "Activity log - shows recent actions"},
-				{"[Detail]", "Ball details - shows full ball info with ACs"},
-				{"[Split]", "Split view - shows both details and activity"},
-			},
-		},
-		{
-			title: "Input Dialogs",
-			items: []helpItem{
-				{"Enter", "Submit / Confirm"},
-				{"Esc", "Cancel"},
-			},
-		},
-		{
-			title: "Delete Confirmation",
-			items: []helpItem{
-				{"y", "Confirm delete"},
-				{"n / Esc", "Cancel delete"},
-			},
-		},
-		{
-			title: "Quit",
-			items: []helpItem{
-				{"q / Ctrl+C", "Quit application"},
-			},
-		},
-	}
-
-	// Build content lines
-	var lines []string
-	for _, section := range sections {
-		lines = append(lines, titleStyle.Render(section.title))
-		for _, item := range section.items {
-			keyStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6")).Width(15)
-			line := fmt.Sprintf("  %s  %s", keyStyle.Render(item.key), item.desc)
-			lines = append(lines, line)
-		}
-		lines = append(lines, "") // Empty line between sections
-	}
+	// Build content lines from the action registry shared with the footer
+	lines := helpLines()
 
 	// Calculate visible area
 	availableHeight := m.height - 5 // Account for title and footer
@@ -785,16 +761,24 @@ func (m Model) renderHistoryView() string {
 		MarginBottom(1)
 	b.WriteString(titleStyle.Render("📜 Agent Run History") + "\n\n")
 
-	if len(m.agentHistory) == 0 {
+	if len(m.agentHistory) == 0 && !m.historyFiltered() {
 		b.WriteString("No agent runs recorded yet.\n\n")
 		b.WriteString(helpStyle.Render("Press H or Esc to return"))
 		return b.String()
 	}
 
+	// Filters and sort order in effect
+	b.WriteString(helpStyle.Render(m.historyFilterLine()) + "\n")
+	if len(m.agentHistory) == 0 {
+		b.WriteString("\nNo runs match the filters.\n\n")
+		b.WriteString(helpStyle.Render("c = clear filters | s/r/d = change filters | H/Esc = close"))
+		return b.String()
+	}
+
 	// Column headers
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("245"))
-	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-19s  %-15s  %-6s  %-14s  %-8s  %-7s\n",
-		"Date", "Session", "Iter", "Result", "Duration", "Balls")))
+	b.WriteString(headerStyle.Render(fmt.Sprintf("  %-19s  %-15s  %-6s  %-14s  %-8s  %-7s  %s\n",
+		"Date", "Session", "Iter", "Result", "Duration", "Balls", "Label")))
 	b.WriteString(strings.Repeat("─", 80) + "\n")
 
 	// Calculate visible area
@@ -826,10 +810,7 @@ func (m Model) renderHistoryView() string {
 		dateStr := record.StartedAt.Format("2006-01-02 15:04:05")
 
 		// Format session (truncate if needed)
-		sessionStr := record.SessionID
-		if len(sessionStr) > 15 {
-			sessionStr = sessionStr[:12] + "..."
-		}
+		sessionStr := truncate(record.SessionID, 15)
 
 		// Format iterations
 		iterStr := fmt.Sprintf("%d/%d", record.Iterations, record.MaxIterations)
@@ -844,8 +825,8 @@ func (m Model) renderHistoryView() string {
 		// Format balls
 		ballsStr := fmt.Sprintf("%d/%d", record.BallsComplete, record.BallsTotal)
 
-		line := fmt.Sprintf("%s%-19s  %-15s  %-6s  %-14s  %-8s  %-7s",
-			cursor, dateStr, sessionStr, iterStr, resultStr, durationStr, ballsStr)
+		line := fmt.Sprintf("%s%-19s  %s  %-6s  %s  %-8s  %-7s  %s",
+			cursor, dateStr, padRight(sessionStr, 15), iterStr, padRight(resultStr, 14), durationStr, ballsStr, truncate(record.Label, 30))
 		b.WriteString(lineStyle.Render(line) + "\n")
 	}
 
@@ -857,6 +838,10 @@ func (m Model) renderHistoryView() string {
 		b.WriteString(helpStyle.Render(fmt.Sprintf("  ↓ %d more below\n", len(m.agentHistory)-endIdx)))
 	}
 
+	// Aggregates for the shown runs
+	b.WriteString(strings.Repeat("─", 80) + "\n")
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("  "+m.historySummary()) + "\n")
+
 	b.WriteString("\n")
 
 	// Show details for selected record
@@ -865,6 +850,15 @@ func (m Model) renderHistoryView() string {
 		detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
 		b.WriteString(detailStyle.Render("─── Selected Run Details ───") + "\n")
 
+		if record.Label != "" {
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Label: %s\n", record.Label)))
+		}
+		if phase := record.PhaseSummary(); phase != "" {
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Phase: %s\n", phase)))
+		}
+		if record.Note != "" {
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Note: %s\n", record.Note)))
+		}
 		if record.BlockedReason != "" {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Blocked: %s\n", record.BlockedReason)))
 		}
@@ -874,9 +868,20 @@ func (m Model) renderHistoryView() string {
 		if record.ErrorMessage != "" {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Error: %s\n", record.ErrorMessage)))
 		}
+		if record.SafeMode != nil {
+			safeMode := "Safe Mode: " + record.SafeMode.Decision
+			if record.SafeMode.Failure != "" {
+				safeMode += " (" + record.SafeMode.Failure + ")"
+			}
+			b.WriteString(detailStyle.Render(safeMode + "\n"))
+		}
+		if record.Redactions.Total() > 0 {
+			b.WriteString(detailStyle.Render(fmt.Sprintf("Redacted: %s\n", record.Redactions)))
+		}
 		if record.TotalWaitTime > 0 {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Rate Limit Wait: %s\n", formatDuration(record.TotalWaitTime))))
 		}
+		b.WriteString(detailStyle.Render(fmt.Sprintf("Run ID: %s\n", record.ID)))
 		if record.OutputFile != "" {
 			b.WriteString(detailStyle.Render(fmt.Sprintf("Output: %s\n", record.OutputFile)))
 		}
@@ -885,7 +890,7 @@ func (m Model) renderHistoryView() string {
 	b.WriteString("\n")
 
 	// Help
-	help := lipgloss.NewStyle().Faint(true).Render("j/k = navigate | Enter = view output | H/Esc = close | gg/G = top/bottom")
+	help := lipgloss.NewStyle().Faint(true).Render("j/k/gg/G = navigate | Enter = view output | s/r/d = filter session/result/date | o = sort | c = clear | H/Esc = close")
 	b.WriteString(help)
 
 	return b.String()
@@ -895,19 +900,19 @@ func (m Model) renderHistoryView() string {
 func formatHistoryResult(result string) string {
 	switch result {
 	case "complete":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Render("✓ Complete")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Render(icons.AgentComplete + " Complete")
 	case "blocked":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render("⊘ Blocked")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render(icons.AgentBlocked + " Blocked")
 	case "timeout":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⏱ Timeout")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(icons.AgentTimeout + " Timeout")
 	case "max_iterations":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render("⟳ MaxIter")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Render(icons.AgentMaxIter + " MaxIter")
 	case "rate_limit":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ RateLimit")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(icons.AgentRateLimit + " RateLimit")
 	case "cancelled":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render("✗ Cancelled")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(icons.AgentError + " Cancelled")
 	case "error":
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("✗ Error")
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(icons.AgentError + " Error")
 	default:
 		return result
 	}
@@ -942,11 +947,78 @@ func (m Model) renderHistoryOutputView() string {
 	}
 	b.WriteString(strings.Repeat("─", 80) + "\n")
 
-	// Split content into lines
-	lines := strings.Split(m.historyOutput, "\n")
+	// Only the visible lines are read, so very large logs page quickly
+	total := m.historyOutputLines()
 
 	// Calculate visible area
-	visibleLines := m.height - 6 // Account for header, footer
+	visibleLines := m.historyOutputVisibleLines()
+
+	// Clamp offset
+	maxOffset := total - visibleLines
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	offset := m.historyOutputOffset
+	if offset > maxOffset {
+		offset = maxOffset
+	}
+	if m.outputSelection != nil {
+		offset = m.outputSelection.scrollStart(offset, visibleLines)
+	}
+
+	// Render visible lines, selected ones highlighted
+	lines := m.historyOutputPage(offset, visibleLines)
+	for i, line := range lines {
+		if m.outputSelection != nil && m.outputSelection.contains(offset+i) {
+			line = outputSelectionStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	endIdx := offset + len(lines)
+
+	// Scroll indicators
+	if offset > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("↑ %d lines above\n", offset)))
+	}
+	if endIdx < total {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("↓ %d lines below\n", total-endIdx)))
+	}
+
+	b.WriteString("\n")
+
+	// Help
+	helpText := "j/k = scroll | ctrl+d/u = page | gg/G = top/bottom | V = select lines | b/Esc = back to history"
+	if m.outputSelection != nil {
+		helpText = "j/k = extend selection | a/Enter = create ball from selection | Esc = cancel"
+	}
+	help := lipgloss.NewStyle().Faint(true).Render(helpText)
+	b.WriteString(help)
+
+	return b.String()
+}
+
+// renderTranscriptView renders a transcript attached to the highlighted ball
+func (m Model) renderTranscriptView() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("33")).
+		MarginBottom(1)
+
+	if m.editingBall == nil || m.transcriptIndex >= len(m.editingBall.Transcripts) {
+		return titleStyle.Render("📜 Transcript") + "\n"
+	}
+	transcript := m.editingBall.Transcripts[m.transcriptIndex]
+	b.WriteString(titleStyle.Render(fmt.Sprintf("📜 Transcript %d/%d: %s (%s)", m.transcriptIndex+1, len(m.editingBall.Transcripts),
+		m.editingBall.ShortID(), transcript.AttachedAt.Format("2006-01-02 15:04"))) + "\n")
+	b.WriteString(helpStyle.Render(truncate(transcript.Label(), 80)) + "\n")
+	b.WriteString(strings.Repeat("─", 80) + "\n")
+
+	lines := strings.Split(m.transcriptContent, "\n")
+
+	// Calculate visible area
+	visibleLines := m.height - 7 // Account for header, footer
 	if visibleLines < 5 {
 		visibleLines = 5
 	}
@@ -956,17 +1028,15 @@ func (m Model) renderHistoryOutputView() string {
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
-	offset := m.historyOutputOffset
+	offset := m.transcriptOffset
 	if offset > maxOffset {
 		offset = maxOffset
 	}
 
-	// Render visible lines
 	endIdx := offset + visibleLines
 	if endIdx > len(lines) {
 		endIdx = len(lines)
 	}
-
 	for i := offset; i < endIdx; i++ {
 		b.WriteString(lines[i] + "\n")
 	}
@@ -981,8 +1051,7 @@ func (m Model) renderHistoryOutputView() string {
 
 	b.WriteString("\n")
 
-	// Help
-	help := lipgloss.NewStyle().Faint(true).Render("j/k = scroll | ctrl+d/u = page | gg/G = top/bottom | b/Esc = back to history")
+	help := lipgloss.NewStyle().Faint(true).Render("j/k = scroll | ctrl+d/u = page | gg/G = top/bottom | n/p = next/prev transcript | Esc = close")
 	b.WriteString(help)
 
 	return b.String()
@@ -1069,10 +1138,11 @@ func (m Model) renderUnifiedBallFormView() string {
 	// Build sessions list for display
 	sessionOptions := []string{"(none)"}
 	for _, sess := range m.sessions {
-		if sess.ID != PseudoSessionAll && sess.ID != PseudoSessionUntagged {
+		if !isPseudoSession(sess.ID) {
 			sessionOptions = append(sessionOptions, sess.ID)
 		}
 	}
+	sessionOptions = append(sessionOptions, "+ create new…")
 
 	// Styles
 	activeFieldStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
@@ -1170,7 +1240,18 @@ func (m Model) renderUnifiedBallFormView() string {
 			}
 		}
 	}
-	b.WriteString("\n\n")
+	b.WriteString("\n")
+	// Warn about title rules the title breaks
+	formTitle := m.pendingBallIntent
+	if m.pendingBallFormField == fieldIntent {
+		formTitle = m.textInput.Value()
+	}
+	if formTitle != "" {
+		for _, warning := range m.titleRules.CheckTitle(formTitle) {
+			b.WriteString(warningStyle.Render("  ⚠ Title "+warning) + "\n")
+		}
+	}
+	b.WriteString("\n")
 
 	// --- Acceptance Criteria section (now after Title) ---
 	acLabel := normalStyle
@@ -1236,7 +1317,7 @@ func (m Model) renderUnifiedBallFormView() string {
 			checkbox := "[ ]"
 			templateStyle := optionNormalStyle
 			if m.acTemplateSelected != nil && i < len(m.acTemplateSelected) && m.acTemplateSelected[i] {
-				checkbox = "[✓]"
+				checkbox = "[" + icons.Checked + "]"
 				templateStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 			}
 			// Highlight current selection
@@ -1287,18 +1368,24 @@ func (m Model) renderUnifiedBallFormView() string {
 		labelStyle = activeFieldStyle
 	}
 	b.WriteString(labelStyle.Render("Session: "))
-	for j, opt := range sessionOptions {
-		if j > 0 {
-			b.WriteString(" | ")
-		}
-		if j == m.pendingBallSession {
-			if m.pendingBallFormField == fieldSession {
-				b.WriteString(optionSelectedStyle.Render(opt))
+	// Check if "create new…" (last option) is selected, with text input
+	if m.pendingBallFormField == fieldSession && m.pendingBallSession == len(sessionOptions)-1 {
+		// Show text input for the new session
+		b.WriteString(m.textInput.View())
+	} else {
+		for j, opt := range sessionOptions {
+			if j > 0 {
+				b.WriteString(" | ")
+			}
+			if j == m.pendingBallSession {
+				if m.pendingBallFormField == fieldSession {
+					b.WriteString(optionSelectedStyle.Render(opt))
+				} else {
+					b.WriteString(selectedStyle.Render(opt))
+				}
 			} else {
-				b.WriteString(selectedStyle.Render(opt))
+				b.WriteString(optionNormalStyle.Render(opt))
 			}
-		} else {
-			b.WriteString(optionNormalStyle.Render(opt))
 		}
 	}
 	b.WriteString("\n")
@@ -1487,18 +1574,27 @@ func (m Model) renderUnifiedBallFormView() string {
 	return b.String()
 }
 
-// wrapText wraps text to fit within maxWidth characters per line
+// wrapText wraps text to fit within maxWidth cells per line. Long words are
+// kept whole, except runs of CJK text, which has no spaces between words.
 func wrapText(text string, maxWidth int) string {
-	if len(text) <= maxWidth {
+	if displayWidth(text) <= maxWidth {
 		return text
 	}
 
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if displayWidth(word) > maxWidth && hasCJK(word) {
+			words = append(words, breakWidth(word, maxWidth)...)
+		} else {
+			words = append(words, word)
+		}
+	}
+
 	var result strings.Builder
-	words := strings.Fields(text)
 	lineLen := 0
 
 	for i, word := range words {
-		wordLen := len(word)
+		wordLen := displayWidth(word)
 		if i == 0 {
 			// First word
 			result.WriteString(word)
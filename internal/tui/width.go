@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Widths here are terminal cells, not bytes or runes: CJK characters and
+// most emoji take two cells, combining marks and ANSI escapes none, and an
+// emoji sequence joined by zero-width joiners counts as one grapheme.
+
+// displayWidth returns how many cells s takes in the terminal
+func displayWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// truncate shortens s to at most maxWidth cells, ending in "..." when cut.
+// Grapheme clusters are never split.
+func truncate(s string, maxWidth int) string {
+	if maxWidth <= 0 {
+		return ""
+	}
+	if displayWidth(s) <= maxWidth {
+		return s
+	}
+	if maxWidth <= 3 {
+		return ansi.Truncate(s, maxWidth, "")
+	}
+	return ansi.Truncate(s, maxWidth, "...")
+}
+
+// padRight pads s with spaces to width cells
+func padRight(s string, width int) string {
+	if gap := width - displayWidth(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// fitWidth truncates or pads s to exactly width cells, for table columns
+func fitWidth(s string, width int) string {
+	return padRight(truncate(s, width), width)
+}
+
+// breakWidth splits s into pieces of at most width cells, between graphemes
+func breakWidth(s string, width int) []string {
+	return strings.Split(ansi.Hardwrap(s, width, true), "\n")
+}
+
+// hasCJK reports whether s contains Chinese, Japanese or Korean characters,
+// which may be broken between any two of them when wrapping
+func hasCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
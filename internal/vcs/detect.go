@@ -9,8 +9,8 @@ import (
 // Priority (highest to lowest):
 //  1. Project config (if set and non-empty)
 //  2. Global config (if set and non-empty)
-//  3. Auto-detect: check for .jj directory first, then .git
-//  4. Default: git
+//  3. Auto-detect: the nearest .jj or .git, in the directory or a parent
+//  4. None: not under version control
 func Detect(projectDir string, projectVCS, globalVCS VCSType) VCSType {
 	// 1. Project config has highest priority
 	if projectVCS != "" {
@@ -26,20 +26,29 @@ func Detect(projectDir string, projectVCS, globalVCS VCSType) VCSType {
 	return AutoDetect(projectDir)
 }
 
-// AutoDetect checks the filesystem for VCS directories.
-// Returns VCSTypeJJ if .jj exists, VCSTypeGit if .git exists.
-// Defaults to VCSTypeGit if neither is found.
+// AutoDetect checks the directory and its parents for VCS directories.
+// Returns VCSTypeJJ if the nearest has .jj (a colocated repo has both),
+// VCSTypeGit if it has .git, and VCSTypeNone if there's neither.
 func AutoDetect(projectDir string) VCSType {
-	// Check for jj first (higher priority)
-	if _, err := os.Stat(filepath.Join(projectDir, ".jj")); err == nil {
-		return VCSTypeJJ
+	dir, err := filepath.Abs(projectDir)
+	if err != nil {
+		dir = projectDir
 	}
+	for {
+		// Check for jj first (higher priority)
+		if _, err := os.Stat(filepath.Join(dir, ".jj")); err == nil {
+			return VCSTypeJJ
+		}
 
-	// Check for git
-	if _, err := os.Stat(filepath.Join(projectDir, ".git")); err == nil {
-		return VCSTypeGit
-	}
+		// Check for git (.git is a file in worktrees and submodules)
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return VCSTypeGit
+		}
 
-	// Default to git
-	return VCSTypeGit
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return VCSTypeNone
+		}
+		dir = parent
+	}
 }
@@ -0,0 +1,553 @@
+package vcs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fsSnapshotDir is where FSBackend keeps snapshots, relative to the project.
+// It's inside .juggle so snapshots never snapshot themselves.
+const fsSnapshotDir = ".juggle/snapshots"
+
+// fsMaxFileSize is the largest file a snapshot records. Bigger files (build
+// artifacts, media) are left out, and left alone when restoring.
+const fsMaxFileSize = 16 << 20
+
+// fsMaxDiffCells caps the line-diff table; bigger files are diffed as a
+// whole-file replacement
+const fsMaxDiffCells = 4 << 20
+
+// ErrNotVersioned is returned for operations that need version control
+var ErrNotVersioned = errors.New("not under version control")
+
+// FSBackend implements VCS for directories without version control. It
+// never commits; snapshots and revisions are copies of the project's files
+// kept under .juggle/snapshots, stored once per distinct content.
+type FSBackend struct{}
+
+// NewFSBackend creates a new filesystem backend instance.
+func NewFSBackend() *FSBackend {
+	return &FSBackend{}
+}
+
+// fsEntry is a file recorded in a snapshot
+type fsEntry struct {
+	Hash string      `json:"hash"`
+	Mode fs.FileMode `json:"mode"`
+}
+
+// fsManifest maps the project-relative, slash-separated paths of a
+// snapshot's files to their content
+type fsManifest map[string]fsEntry
+
+// Type returns VCSTypeNone.
+func (f *FSBackend) Type() VCSType {
+	return VCSTypeNone
+}
+
+// Status reports that the directory isn't under version control.
+func (f *FSBackend) Status(projectDir string) (string, error) {
+	return "Not under version control", nil
+}
+
+// HasChanges returns false: without version control there's nothing to
+// compare against.
+func (f *FSBackend) HasChanges(projectDir string) (bool, error) {
+	return false, nil
+}
+
+// Commit leaves the changes in place and says so.
+func (f *FSBackend) Commit(projectDir, message string) (*CommitResult, error) {
+	return &CommitResult{
+		Success:      true,
+		StatusOutput: "Not under version control, changes left uncommitted",
+	}, nil
+}
+
+// GetLastCommitHash returns ErrNotVersioned.
+func (f *FSBackend) GetLastCommitHash(projectDir string) (string, error) {
+	return "", ErrNotVersioned
+}
+
+// DescribeWorkingCopy is a no-op without version control.
+func (f *FSBackend) DescribeWorkingCopy(projectDir, message string) error {
+	return nil
+}
+
+// IsolateAndReset snapshots the current files as blocked-<time> and restores
+// the target snapshot. Without a target the files are left as they are, as
+// there's no other state to go back to. Returns the blocked snapshot.
+func (f *FSBackend) IsolateAndReset(projectDir, targetRevision string) (string, error) {
+	if targetRevision == "" {
+		return "", fmt.Errorf("no starting snapshot to reset to (%w), work left in place", ErrNotVersioned)
+	}
+	if _, err := f.loadManifest(projectDir, targetRevision); err != nil {
+		return "", fmt.Errorf("target revision %q does not exist: %w", targetRevision, err)
+	}
+	blocked, err := f.Snapshot(projectDir, "blocked-"+fsTimestamp())
+	if err != nil {
+		return "", err
+	}
+	if err := f.RestoreSnapshot(projectDir, targetRevision, nil); err != nil {
+		return "", err
+	}
+	return blocked, nil
+}
+
+// GetCurrentRevision snapshots the current files, so the revision can be
+// diffed against or returned to later.
+func (f *FSBackend) GetCurrentRevision(projectDir string) (string, error) {
+	return f.Snapshot(projectDir, "rev-"+fsTimestamp())
+}
+
+// Snapshot records the project's files (except .juggle) under the given
+// name, which is also the returned snapshot ID.
+func (f *FSBackend) Snapshot(projectDir, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid snapshot name: %q", name)
+	}
+	objects := filepath.Join(projectDir, fsSnapshotDir, "objects")
+	if err := os.MkdirAll(objects, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	manifest := make(fsManifest)
+	err := walkProjectFiles(projectDir, func(path string, info fs.FileInfo) error {
+		data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(path)))
+		if err != nil {
+			return err
+		}
+		hash := hashContent(data)
+		object := filepath.Join(objects, hash)
+		if _, err := os.Stat(object); os.IsNotExist(err) {
+			if err := writeFileAtomic(object, data, 0644); err != nil {
+				return err
+			}
+		}
+		manifest[path] = fsEntry{Hash: hash, Mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot files: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(f.manifestPath(projectDir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return name, nil
+}
+
+// RestoreSnapshot puts the project's files back as they were in the
+// snapshot: changed files are rewritten and files created since are
+// removed. Paths under exclude, .juggle, and files too big to snapshot are
+// left as they are.
+func (f *FSBackend) RestoreSnapshot(projectDir, snapshot string, exclude []string) error {
+	manifest, err := f.loadManifest(projectDir, snapshot)
+	if err != nil {
+		return err
+	}
+	excluded := func(path string) bool {
+		for _, ex := range exclude {
+			ex = strings.Trim(filepath.ToSlash(ex), "/")
+			if path == ex || strings.HasPrefix(path, ex+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	current := make(map[string]bool)
+	err = walkProjectFiles(projectDir, func(path string, info fs.FileInfo) error {
+		current[path] = true
+		if _, ok := manifest[path]; ok || excluded(path) {
+			return nil
+		}
+		return os.Remove(filepath.Join(projectDir, filepath.FromSlash(path)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove new files: %w", err)
+	}
+
+	for path, entry := range manifest {
+		if excluded(path) {
+			continue
+		}
+		target := filepath.Join(projectDir, filepath.FromSlash(path))
+		if current[path] {
+			if data, err := os.ReadFile(target); err == nil && hashContent(data) == entry.Hash {
+				if err := os.Chmod(target, entry.Mode); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		data, err := os.ReadFile(f.objectPath(projectDir, entry.Hash))
+		if err != nil {
+			return fmt.Errorf("snapshot %s is missing %s: %w", snapshot, path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, entry.Mode); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		if err := os.Chmod(target, entry.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropSnapshot deletes a snapshot and the stored content no other snapshot
+// uses.
+func (f *FSBackend) DropSnapshot(projectDir, name string) error {
+	if err := os.Remove(f.manifestPath(projectDir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	dir := filepath.Join(projectDir, fsSnapshotDir)
+	manifests, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	for _, path := range manifests {
+		manifest, err := f.loadManifest(projectDir, strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			// Keep everything rather than lose content a snapshot needs
+			return nil
+		}
+		for _, entry := range manifest {
+			used[entry.Hash] = true
+		}
+	}
+	objects, err := os.ReadDir(filepath.Join(dir, "objects"))
+	if err != nil {
+		return nil
+	}
+	for _, object := range objects {
+		if !used[object.Name()] {
+			_ = os.Remove(filepath.Join(dir, "objects", object.Name()))
+		}
+	}
+	return nil
+}
+
+// Diff returns the changes between two snapshots in git diff format. An
+// empty to diffs the current files. Without version control there's no
+// parent to diff against, so from is required.
+func (f *FSBackend) Diff(projectDir, from, to string) (string, error) {
+	if from == "" {
+		return "", fmt.Errorf("no starting snapshot to diff from (%w)", ErrNotVersioned)
+	}
+	before, err := f.loadManifest(projectDir, from)
+	if err != nil {
+		return "", err
+	}
+	readBefore := func(path string) ([]byte, error) {
+		return os.ReadFile(f.objectPath(projectDir, before[path].Hash))
+	}
+
+	var after fsManifest
+	var readAfter func(path string) ([]byte, error)
+	if to == "" {
+		after = make(fsManifest)
+		err := walkProjectFiles(projectDir, func(path string, info fs.FileInfo) error {
+			data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(path)))
+			if err != nil {
+				return err
+			}
+			after[path] = fsEntry{Hash: hashContent(data), Mode: info.Mode().Perm()}
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to read files: %w", err)
+		}
+		readAfter = func(path string) ([]byte, error) {
+			return os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(path)))
+		}
+	} else {
+		if after, err = f.loadManifest(projectDir, to); err != nil {
+			return "", err
+		}
+		readAfter = func(path string) ([]byte, error) {
+			return os.ReadFile(f.objectPath(projectDir, after[path].Hash))
+		}
+	}
+
+	paths := make([]string, 0, len(before)+len(after))
+	for path := range before {
+		paths = append(paths, path)
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var buf strings.Builder
+	for _, path := range paths {
+		old, inBefore := before[path]
+		cur, inAfter := after[path]
+		if inBefore && inAfter && old.Hash == cur.Hash {
+			continue
+		}
+		var oldData, newData []byte
+		if inBefore {
+			if oldData, err = readBefore(path); err != nil {
+				return "", fmt.Errorf("snapshot %s is missing %s: %w", from, path, err)
+			}
+		}
+		if inAfter {
+			if newData, err = readAfter(path); err != nil {
+				return "", err
+			}
+		}
+
+		fmt.Fprintf(&buf, "diff --git a/%s b/%s\n", path, path)
+		oldName, newName := "a/"+path, "b/"+path
+		switch {
+		case !inBefore:
+			fmt.Fprintf(&buf, "new file mode 100%o\n", cur.Mode)
+			oldName = "/dev/null"
+		case !inAfter:
+			fmt.Fprintf(&buf, "deleted file mode 100%o\n", old.Mode)
+			newName = "/dev/null"
+		case old.Mode != cur.Mode:
+			fmt.Fprintf(&buf, "old mode 100%o\nnew mode 100%o\n", old.Mode, cur.Mode)
+		}
+		if isBinary(oldData) || isBinary(newData) {
+			fmt.Fprintf(&buf, "Binary files %s and %s differ\n", oldName, newName)
+			continue
+		}
+		fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+		writeUnifiedDiff(&buf, splitLines(string(oldData)), splitLines(string(newData)))
+	}
+	return buf.String(), nil
+}
+
+func (f *FSBackend) manifestPath(projectDir, name string) string {
+	return filepath.Join(projectDir, fsSnapshotDir, name+".json")
+}
+
+func (f *FSBackend) objectPath(projectDir, hash string) string {
+	return filepath.Join(projectDir, fsSnapshotDir, "objects", hash)
+}
+
+// loadManifest reads a snapshot's manifest
+func (f *FSBackend) loadManifest(projectDir, name string) (fsManifest, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("snapshot not found: %q", name)
+	}
+	data, err := os.ReadFile(f.manifestPath(projectDir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot not found: %s", name)
+		}
+		return nil, err
+	}
+	var manifest fsManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", name, err)
+	}
+	return manifest, nil
+}
+
+// walkProjectFiles calls fn with the slash-separated relative path of every
+// regular file a snapshot records: everything but .juggle directories,
+// .git and .jj, symlinks, and files over fsMaxFileSize
+func walkProjectFiles(projectDir string, fn func(path string, info fs.FileInfo) error) error {
+	return filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != projectDir && (d.Name() == ".juggle" || d.Name() == ".git" || d.Name() == ".jj") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > fsMaxFileSize {
+			return nil
+		}
+		rel, err := filepath.Rel(projectDir, path)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), info)
+	})
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fsTimestamp names revisions in the order they were taken
+func fsTimestamp() string {
+	return time.Now().Format("20060102-150405.000000000")
+}
+
+// isBinary reports whether data looks like a binary file, as git decides:
+// a NUL byte in the first 8000 bytes
+func isBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// splitLines splits text into lines, each keeping its newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed or '+' added,
+// with the number of old and new lines before it
+type diffOp struct {
+	kind       byte
+	line       string
+	oldN, newN int
+}
+
+// diffLines returns the edit script turning a into b, from their longest
+// common subsequence of lines
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > fsMaxDiffCells {
+		for i, line := range a {
+			ops = append(ops, diffOp{'-', line, i, 0})
+		}
+		for j, line := range b {
+			ops = append(ops, diffOp{'+', line, len(a), j})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[(i+1)*width+j] >= lcs[i*width+j+1]):
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeUnifiedDiff writes the hunks turning a into b, with three lines of
+// context
+func writeUnifiedDiff(buf *strings.Builder, a, b []string) {
+	const context = 3
+	ops := diffLines(a, b)
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		// Extend the hunk while changes are within two contexts of each other
+		first, last := start, start
+		for k := start + 1; k < len(ops) && k <= last+2*context; k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			}
+		}
+		from := max(first-context, 0)
+		to := min(last+context+1, len(ops))
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		oldStart, newStart := ops[from].oldN+1, ops[from].newN+1
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+		for _, op := range ops[from:to] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		start = to
+	}
+}
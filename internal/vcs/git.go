@@ -2,7 +2,9 @@ package vcs
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -249,3 +251,117 @@ func (g *GitBackend) GetCurrentRevision(projectDir string) (string, error) {
 
 	return result, nil
 }
+
+// snapshotRef returns the ref a named snapshot is kept under.
+func snapshotRef(name string) string {
+	return "refs/juggle/snapshots/" + name
+}
+
+// Snapshot commits the working tree (tracked and untracked, not ignored) using
+// a temporary index, so neither the real index nor the branch changes.
+// Returns the snapshot commit hash; its parent is the commit HEAD was on.
+func (g *GitBackend) Snapshot(projectDir, name string) (string, error) {
+	headCmd := exec.Command("git", "rev-parse", "--verify", "HEAD")
+	headCmd.Dir = projectDir
+	output, err := headCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot snapshot a repository without commits: %w", err)
+	}
+	head := strings.TrimSpace(string(output))
+
+	tmpDir, err := os.MkdirTemp("", "juggle-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	env := append(os.Environ(), "GIT_INDEX_FILE="+filepath.Join(tmpDir, "index"))
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = projectDir
+		cmd.Env = env
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("git %s failed: %s: %w", args[0], strings.TrimSpace(string(output)), err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	if _, err := run("read-tree", head); err != nil {
+		return "", err
+	}
+	if _, err := run("add", "-A"); err != nil {
+		return "", err
+	}
+	tree, err := run("write-tree")
+	if err != nil {
+		return "", err
+	}
+	commit, err := run("commit-tree", tree, "-p", head, "-m", "juggle snapshot "+name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := run("update-ref", snapshotRef(name), commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// RestoreSnapshot resets the current branch to the snapshot's parent and
+// restores the snapshot's files, removing untracked files created since.
+// Paths under exclude are left as they are.
+func (g *GitBackend) RestoreSnapshot(projectDir, snapshot string, exclude []string) error {
+	pathspec := []string{"--", "."}
+	for _, path := range exclude {
+		pathspec = append(pathspec, ":(exclude)"+path)
+	}
+
+	steps := [][]string{
+		// Move the branch back, keeping the working tree for now
+		{"reset", "-q", snapshot + "^"},
+		{"clean", "-fdq"},
+		{"restore", "--source=" + snapshot, "--worktree"},
+	}
+	for i, args := range steps {
+		if i > 0 {
+			args = append(args, pathspec...)
+		}
+		cmd := exec.Command("git", args...)
+		cmd.Dir = projectDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %s: %w", args[0], strings.TrimSpace(string(output)), err)
+		}
+	}
+	return nil
+}
+
+// DropSnapshot deletes the ref keeping a snapshot alive.
+func (g *GitBackend) DropSnapshot(projectDir, name string) error {
+	cmd := exec.Command("git", "update-ref", "-d", snapshotRef(name))
+	cmd.Dir = projectDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git update-ref failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// Diff returns the changes between two revisions, or between to and its
+// parent when from is empty. An empty to diffs the working tree.
+func (g *GitBackend) Diff(projectDir, from, to string) (string, error) {
+	args := []string{"diff", from, to, "--"}
+	switch {
+	case to == "" && from == "":
+		args = []string{"diff", "HEAD", "--"}
+	case to == "":
+		args = []string{"diff", from, "--"}
+	case from == "":
+		args = []string{"diff", to + "^", to, "--"}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
@@ -147,3 +147,59 @@ func (j *JJBackend) GetCurrentRevision(projectDir string) (string, error) {
 	}
 	return strings.TrimSpace(string(output)), nil
 }
+
+// Snapshot returns the commit ID of the working copy. jj records the working
+// copy on every command, so the ID is enough to restore it later.
+func (j *JJBackend) Snapshot(projectDir, name string) (string, error) {
+	cmd := exec.Command("jj", "log", "-r", "@", "--no-graph", "-T", "commit_id")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("jj log failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// RestoreSnapshot restores the snapshot's files into the working copy.
+// Commits made since are left in history.
+func (j *JJBackend) RestoreSnapshot(projectDir, snapshot string, exclude []string) error {
+	args := []string{"restore", "--from", snapshot}
+	if len(exclude) > 0 {
+		quoted := make([]string, len(exclude))
+		for i, path := range exclude {
+			quoted[i] = fmt.Sprintf("%q", path)
+		}
+		args = append(args, "~("+strings.Join(quoted, " | ")+")")
+	}
+	cmd := exec.Command("jj", args...)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("jj restore failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// DropSnapshot is a no-op for jj (snapshots are ordinary commits).
+func (j *JJBackend) DropSnapshot(projectDir, name string) error {
+	return nil
+}
+
+// Diff returns the changes between two revisions in git format, or the
+// changes in to when from is empty. An empty to is the working copy (@).
+func (j *JJBackend) Diff(projectDir, from, to string) (string, error) {
+	if to == "" {
+		to = "@"
+	}
+	args := []string{"diff", "--git", "-r", to}
+	if from != "" {
+		args = []string{"diff", "--git", "--from", from, "--to", to}
+	}
+	cmd := exec.Command("jj", args...)
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("jj diff failed: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return string(output), nil
+}
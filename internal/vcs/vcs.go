@@ -7,6 +7,10 @@ type VCSType string
 const (
 	VCSTypeJJ  VCSType = "jj"
 	VCSTypeGit VCSType = "git"
+
+	// VCSTypeNone is a directory without version control, handled with
+	// filesystem snapshots
+	VCSTypeNone VCSType = "none"
 )
 
 // String returns the string representation of VCSType.
@@ -16,7 +20,7 @@ func (v VCSType) String() string {
 
 // IsValid returns true if the VCSType is a known valid type.
 func (v VCSType) IsValid() bool {
-	return v == VCSTypeJJ || v == VCSTypeGit
+	return v == VCSTypeJJ || v == VCSTypeGit || v == VCSTypeNone
 }
 
 // CommitResult represents the outcome of a commit operation.
@@ -29,7 +33,7 @@ type CommitResult struct {
 
 // VCS defines the interface for version control operations.
 type VCS interface {
-	// Type returns the VCS type (jj or git)
+	// Type returns the VCS type (jj, git, or none)
 	Type() VCSType
 
 	// Status returns the current status output
@@ -61,6 +65,36 @@ type VCS interface {
 	// For jj: returns the change_id of the working copy
 	// For git: returns the current commit hash or branch name
 	GetCurrentRevision(projectDir string) (string, error)
+
+	// Snapshot records the working copy, including uncommitted and untracked
+	// changes, without modifying it. The name identifies the snapshot (e.g. an
+	// agent run ID). Returns an ID to pass to RestoreSnapshot.
+	// For jj: returns the working copy's commit ID (jj snapshots automatically)
+	// For git: commits the working tree outside the index and branch, kept
+	// under refs/juggle/snapshots/<name>
+	Snapshot(projectDir, name string) (string, error)
+
+	// RestoreSnapshot returns the working copy to a snapshot, leaving paths
+	// under exclude (e.g. the .juggle directory) untouched.
+	// For jj: restores the snapshot's files into the working copy; changes made
+	// since stay in the operation log and history
+	// For git: resets the current branch to the commit the snapshot was taken
+	// on and restores the snapshot's files (uncommitted)
+	RestoreSnapshot(projectDir, snapshot string, exclude []string) error
+
+	// DropSnapshot releases a snapshot that is no longer needed.
+	// For jj: this is a no-op
+	// For git: deletes refs/juggle/snapshots/<name>
+	DropSnapshot(projectDir, name string) error
+
+	// Diff returns the changes between two revisions in git diff format.
+	// If from is empty, to is diffed against its parent. If to is empty, the
+	// working copy is diffed, including uncommitted changes.
+	// For jj: runs "jj diff --git" (--from/--to, or -r when from is empty;
+	// the working copy is @)
+	// For git: runs "git diff from to" (to^ when from is empty, HEAD when
+	// both are empty; untracked files are not included)
+	Diff(projectDir, from, to string) (string, error)
 }
 
 // GetBackend returns the appropriate VCS backend for the given type.
@@ -70,13 +104,20 @@ func GetBackend(vcsType VCSType) VCS {
 		return NewJJBackend()
 	case VCSTypeGit:
 		return NewGitBackend()
+	case VCSTypeNone:
+		return NewFSBackend()
 	default:
 		return NewGitBackend() // Default to git
 	}
 }
 
 // GetBackendForProject returns the VCS backend for a project, using config resolution.
+// A project outside any repository gets the filesystem backend whatever the
+// config says, so a global git or jj preference doesn't fail mid-run there.
 func GetBackendForProject(projectDir string, projectVCS, globalVCS VCSType) VCS {
 	vcsType := Detect(projectDir, projectVCS, globalVCS)
+	if vcsType != VCSTypeNone && AutoDetect(projectDir) == VCSTypeNone {
+		vcsType = VCSTypeNone
+	}
 	return GetBackend(vcsType)
 }
@@ -1,6 +1,7 @@
 package vcs
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +16,7 @@ func TestVCSType_IsValid(t *testing.T) {
 	}{
 		{VCSTypeJJ, true},
 		{VCSTypeGit, true},
+		{VCSTypeNone, true},
 		{"svn", false},
 		{"", false},
 	}
@@ -67,12 +69,38 @@ func TestAutoDetect_JJPriority(t *testing.T) {
 	}
 }
 
-func TestAutoDetect_DefaultToGit(t *testing.T) {
+func TestAutoDetect_NoRepository(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	result := AutoDetect(tmpDir)
+	if result != VCSTypeNone {
+		t.Errorf("expected none, got %s", result)
+	}
+}
+
+func TestAutoDetect_ParentRepository(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectDir := filepath.Join(tmpDir, "services", "api")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result := AutoDetect(projectDir)
 	if result != VCSTypeGit {
-		t.Errorf("expected git (default), got %s", result)
+		t.Errorf("expected git from the parent directory, got %s", result)
+	}
+}
+
+func TestGetBackendForProject_NoRepositoryIgnoresConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A global git preference can't work outside a repository
+	backend := GetBackendForProject(tmpDir, "", VCSTypeGit)
+	if backend.Type() != VCSTypeNone {
+		t.Errorf("expected none outside a repository, got %s", backend.Type())
 	}
 }
 
@@ -132,6 +160,7 @@ func TestGetBackend(t *testing.T) {
 	}{
 		{VCSTypeJJ, VCSTypeJJ},
 		{VCSTypeGit, VCSTypeGit},
+		{VCSTypeNone, VCSTypeNone},
 		{"unknown", VCSTypeGit}, // defaults to git
 		{"", VCSTypeGit},        // defaults to git
 	}
@@ -623,6 +652,137 @@ func TestGitBackend_Commit_EmptyMessage(t *testing.T) {
 	}
 }
 
+func TestGitBackend_SnapshotAndRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			return "<missing>"
+		}
+		return string(data)
+	}
+
+	// Uncommitted and untracked work present before the run
+	write("README.md", "# Edited\n")
+	write("notes.txt", "untracked\n")
+	write(".juggle/balls.jsonl", "before\n")
+
+	backend := NewGitBackend()
+	headBefore, _ := backend.GetLastCommitHash(tmpDir)
+	snapshot, err := backend.Snapshot(tmpDir, "run-1")
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if hasChanges, _ := backend.HasChanges(tmpDir); !hasChanges {
+		t.Fatal("Snapshot should not touch the working tree")
+	}
+
+	// The run edits, commits, and creates files
+	write("README.md", "# Broken\n")
+	write("generated.go", "package x\n")
+	if result, err := backend.Commit(tmpDir, "agent work"); err != nil || !result.Success {
+		t.Fatalf("Commit failed: %v %+v", err, result)
+	}
+	write("scratch.txt", "leftover\n")
+	write(".juggle/balls.jsonl", "after\n")
+
+	if err := backend.RestoreSnapshot(tmpDir, snapshot, []string{".juggle"}); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+
+	if headAfter, _ := backend.GetLastCommitHash(tmpDir); headAfter != headBefore {
+		t.Errorf("expected HEAD back at %s, got %s", headBefore, headAfter)
+	}
+	for name, want := range map[string]string{
+		"README.md":           "# Edited\n",
+		"notes.txt":           "untracked\n",
+		"generated.go":        "<missing>",
+		"scratch.txt":         "<missing>",
+		".juggle/balls.jsonl": "after\n", // Excluded paths are left alone
+	} {
+		if got := read(name); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+
+	if err := backend.DropSnapshot(tmpDir, "run-1"); err != nil {
+		t.Fatalf("DropSnapshot failed: %v", err)
+	}
+	cmd := exec.Command("git", "rev-parse", "--verify", "refs/juggle/snapshots/run-1")
+	cmd.Dir = tmpDir
+	if err := cmd.Run(); err == nil {
+		t.Error("expected snapshot ref to be deleted")
+	}
+}
+
+func TestGitBackend_Snapshot_NoCommits(t *testing.T) {
+	tmpDir := t.TempDir()
+	cmd := exec.Command("git", "init")
+	cmd.Dir = tmpDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %s: %v", output, err)
+	}
+
+	if _, err := NewGitBackend().Snapshot(tmpDir, "run-1"); err == nil {
+		t.Error("expected error snapshotting a repository without commits")
+	}
+}
+
+func TestGitBackend_Diff(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupGitRepo(t, tmpDir)
+
+	backend := NewGitBackend()
+	start, _ := backend.GetLastCommitHash(tmpDir)
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Changed\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if result, err := backend.Commit(tmpDir, "change readme"); err != nil || !result.Success {
+		t.Fatalf("Commit failed: %v %+v", err, result)
+	}
+
+	for _, from := range []string{start, ""} {
+		diff, err := backend.Diff(tmpDir, from, "HEAD")
+		if err != nil {
+			t.Fatalf("Diff(%q) failed: %v", from, err)
+		}
+		if !strings.Contains(diff, "-# Test") || !strings.Contains(diff, "+# Changed") {
+			t.Errorf("Diff(%q) missing change, got:\n%s", from, diff)
+		}
+	}
+
+	if _, err := backend.Diff(tmpDir, "", "no-such-revision"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+
+	// An empty to diffs the uncommitted working tree
+	if err := os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Uncommitted\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	for _, from := range []string{start, ""} {
+		diff, err := backend.Diff(tmpDir, from, "")
+		if err != nil {
+			t.Fatalf("Diff(%q, working tree) failed: %v", from, err)
+		}
+		if !strings.Contains(diff, "+# Uncommitted") {
+			t.Errorf("Diff(%q, working tree) missing change, got:\n%s", from, diff)
+		}
+	}
+}
+
 // =============================================================================
 // JJ Backend Tests
 // =============================================================================
@@ -986,6 +1146,166 @@ func TestJJBackend_GetLastCommitHash(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Filesystem Backend Tests
+// =============================================================================
+
+// writeTestFiles writes files (relative path to content) under dir
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFSBackend_SnapshotDiffRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{
+		"main.go":             "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n",
+		"notes/todo.txt":      "one\ntwo\n",
+		".juggle/balls.jsonl": "{}\n",
+	})
+	backend := NewFSBackend()
+
+	start, err := backend.GetCurrentRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetCurrentRevision failed: %v", err)
+	}
+
+	// Agent work: edit a file, add one, delete one, and touch juggle's state
+	writeTestFiles(t, tmpDir, map[string]string{
+		"main.go":             "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n",
+		"lib/util.go":         "package lib\n",
+		".juggle/balls.jsonl": "{\"changed\":true}\n",
+	})
+	if err := os.Remove(filepath.Join(tmpDir, "notes", "todo.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := backend.Diff(tmpDir, start, "")
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	for _, want := range []string{
+		"diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1,5 +1,5 @@\n",
+		"-\tprintln(\"hi\")\n+\tprintln(\"hello\")\n",
+		"diff --git a/lib/util.go b/lib/util.go\nnew file mode 100644\n--- /dev/null\n+++ b/lib/util.go\n@@ -0,0 +1,1 @@\n+package lib\n",
+		"deleted file mode 100644\n--- a/notes/todo.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-one\n-two\n",
+	} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected %q in diff:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, ".juggle") {
+		t.Errorf("expected .juggle to be left out of the diff:\n%s", diff)
+	}
+
+	end, err := backend.Snapshot(tmpDir, "after")
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	if between, err := backend.Diff(tmpDir, start, end); err != nil || between != diff {
+		t.Errorf("expected the snapshots to diff like the working copy, got %v:\n%s", err, between)
+	}
+
+	if err := backend.RestoreSnapshot(tmpDir, start, []string{".juggle"}); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "main.go")); !strings.Contains(string(data), `"hi"`) {
+		t.Errorf("expected main.go restored, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "notes", "todo.txt")); string(data) != "one\ntwo\n" {
+		t.Errorf("expected todo.txt restored, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "lib", "util.go")); !os.IsNotExist(err) {
+		t.Errorf("expected the new file removed, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, ".juggle", "balls.jsonl")); !strings.Contains(string(data), "changed") {
+		t.Errorf("expected .juggle left alone, got %q", data)
+	}
+	if diff, err := backend.Diff(tmpDir, start, ""); err != nil || diff != "" {
+		t.Errorf("expected no changes after restoring, got %v:\n%s", err, diff)
+	}
+
+	// Dropping a snapshot keeps the content other snapshots still use
+	if err := backend.DropSnapshot(tmpDir, end); err != nil {
+		t.Fatalf("DropSnapshot failed: %v", err)
+	}
+	if _, err := backend.Diff(tmpDir, end, ""); err == nil {
+		t.Error("expected a dropped snapshot to be gone")
+	}
+	if err := backend.RestoreSnapshot(tmpDir, start, nil); err != nil {
+		t.Errorf("expected the remaining snapshot to restore, got %v", err)
+	}
+}
+
+func TestFSBackend_IsolateAndReset(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFiles(t, tmpDir, map[string]string{"app.txt": "v1\n"})
+	backend := NewFSBackend()
+
+	if _, err := backend.IsolateAndReset(tmpDir, ""); err == nil {
+		t.Error("expected an error without a starting snapshot")
+	}
+
+	start, err := backend.GetCurrentRevision(tmpDir)
+	if err != nil {
+		t.Fatalf("GetCurrentRevision failed: %v", err)
+	}
+	writeTestFiles(t, tmpDir, map[string]string{"app.txt": "v2\n"})
+
+	blocked, err := backend.IsolateAndReset(tmpDir, start)
+	if err != nil {
+		t.Fatalf("IsolateAndReset failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmpDir, "app.txt")); string(data) != "v1\n" {
+		t.Errorf("expected the starting content back, got %q", data)
+	}
+	diff, err := backend.Diff(tmpDir, start, blocked)
+	if err != nil || !strings.Contains(diff, "-v1\n+v2\n") {
+		t.Errorf("expected the blocked work kept in %s, got %v:\n%s", blocked, err, diff)
+	}
+}
+
+func TestFSBackend_CommitLeavesChanges(t *testing.T) {
+	result, err := NewFSBackend().Commit(t.TempDir(), "feat: something")
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if !result.Success || result.CommitHash != "" || !strings.Contains(result.StatusOutput, "uncommitted") {
+		t.Errorf("unexpected commit result %+v", result)
+	}
+}
+
+func TestUnifiedDiff_Hunks(t *testing.T) {
+	var old, cur []string
+	for i := 1; i <= 20; i++ {
+		line := fmt.Sprintf("line %d\n", i)
+		old = append(old, line)
+		if i == 2 {
+			line = "line two\n"
+		}
+		if i != 18 {
+			cur = append(cur, line)
+		}
+	}
+	cur = append(cur, "tail")
+
+	var buf strings.Builder
+	writeUnifiedDiff(&buf, old, cur)
+	want := "@@ -1,5 +1,5 @@\n line 1\n-line 2\n+line two\n line 3\n line 4\n line 5\n" +
+		"@@ -15,6 +15,6 @@\n line 15\n line 16\n line 17\n-line 18\n line 19\n line 20\n+tail\n\\ No newline at end of file\n"
+	if buf.String() != want {
+		t.Errorf("unexpected hunks:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
 // =============================================================================
 // Integration Tests
 // =============================================================================
@@ -997,3 +1317,7 @@ func TestVCS_InterfaceCompliance_Git(t *testing.T) {
 func TestVCS_InterfaceCompliance_JJ(t *testing.T) {
 	var _ VCS = (*JJBackend)(nil)
 }
+
+func TestVCS_InterfaceCompliance_FS(t *testing.T) {
+	var _ VCS = (*FSBackend)(nil)
+}
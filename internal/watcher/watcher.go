@@ -1,11 +1,16 @@
 package watcher
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -17,13 +22,46 @@ const (
 	BallsChanged EventType = iota
 	ProgressChanged
 	SessionChanged
+	FilesChanged // A project source file under a watched tree changed
+	AgentChanged // An agent run took or released a session's lock
 )
 
+// agentLockInfoFile is written when an agent run takes a session's lock,
+// and removed when it releases it
+const agentLockInfoFile = "agent.lock.info"
+
+// String returns a short name for the event type
+func (t EventType) String() string {
+	switch t {
+	case BallsChanged:
+		return "balls_changed"
+	case ProgressChanged:
+		return "progress_changed"
+	case SessionChanged:
+		return "session_changed"
+	case FilesChanged:
+		return "files_changed"
+	case AgentChanged:
+		return "agent_changed"
+	default:
+		return fmt.Sprintf("event_%d", int(t))
+	}
+}
+
 // Event represents a file change event
 type Event struct {
-	Type      EventType
-	Path      string
-	SessionID string // For progress changes, the session ID
+	Type       EventType
+	Path       string
+	SessionID  string    // For progress, session and agent changes, the session ID
+	ProjectDir string    // For file changes, the project root the file belongs to
+	Time       time.Time // When the change was seen, for latency profiling
+
+	// For balls changes, the balls added or changed and the balls removed
+	// since the file was last read. Diffed is false when the file couldn't
+	// be read, and all of its balls should be reloaded.
+	BallIDs        []string
+	RemovedBallIDs []string
+	Diffed         bool
 }
 
 // Watcher watches for file changes in juggle directories
@@ -34,6 +72,14 @@ type Watcher struct {
 	done    chan struct{}
 	mu      sync.Mutex
 	running bool
+
+	// Source trees watched for FilesChanged events
+	fileRoots   map[string]string // Watched tree root -> project dir
+	watchedDirs map[string]string // Watched directory -> project dir
+
+	// Hash of each ball's line in each watched balls.jsonl, to tell which
+	// balls a change touched
+	ballHashes map[string]map[string]uint64
 }
 
 // New creates a new file watcher
@@ -48,6 +94,10 @@ func New() (*Watcher, error) {
 		Events:  make(chan Event, 100),
 		Errors:  make(chan error, 10),
 		done:    make(chan struct{}),
+
+		fileRoots:   make(map[string]string),
+		watchedDirs: make(map[string]string),
+		ballHashes:  make(map[string]map[string]uint64),
 	}, nil
 }
 
@@ -64,6 +114,7 @@ func (w *Watcher) WatchProject(projectDir string) error {
 	if err := w.watcher.Add(juggleDir); err != nil {
 		return fmt.Errorf("failed to watch juggle directory: %w", err)
 	}
+	w.diffBalls(filepath.Join(juggleDir, "balls.jsonl"))
 
 	// Watch sessions directory if it exists
 	sessionsDir := filepath.Join(juggleDir, "sessions")
@@ -91,6 +142,71 @@ func (w *Watcher) WatchProject(projectDir string) error {
 	return nil
 }
 
+// skipDirs are directories never watched for source file changes
+var skipDirs = map[string]bool{
+	".git":         true,
+	".jj":          true,
+	".juggle":      true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// WatchFiles watches project source files under the given roots (relative to
+// projectDir) and emits FilesChanged events for them. fsnotify is not recursive,
+// so every directory under each root is added. Roots that are already watched
+// or don't exist are skipped.
+func (w *Watcher) WatchFiles(projectDir string, roots []string) error {
+	for _, root := range roots {
+		absRoot := filepath.Clean(filepath.Join(projectDir, root))
+
+		w.mu.Lock()
+		_, watched := w.fileRoots[absRoot]
+		w.mu.Unlock()
+		if watched {
+			continue
+		}
+		if info, err := os.Stat(absRoot); err != nil || !info.IsDir() {
+			continue
+		}
+
+		if err := w.addTree(projectDir, absRoot); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", root, err)
+		}
+
+		w.mu.Lock()
+		w.fileRoots[absRoot] = projectDir
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+// addTree adds a directory and all of its subdirectories to the watcher
+func (w *Watcher) addTree(projectDir, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != dir && skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+
+		w.mu.Lock()
+		_, watched := w.watchedDirs[path]
+		w.mu.Unlock()
+		if watched {
+			return nil
+		}
+
+		if err := w.watcher.Add(path); err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.watchedDirs[path] = projectDir
+		w.mu.Unlock()
+		return nil
+	})
+}
+
 // Start begins watching for file changes
 func (w *Watcher) Start() {
 	w.mu.Lock()
@@ -116,14 +232,17 @@ func (w *Watcher) eventLoop() {
 				return
 			}
 
-			// Filter for write and create events
-			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+			// Filter for write and create events, and for removed agent
+			// locks, which mean the run ended
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 &&
+				(event.Op&fsnotify.Remove == 0 || filepath.Base(event.Name) != agentLockInfoFile) {
 				continue
 			}
 
 			// Determine event type based on path
 			e := w.classifyEvent(event.Name)
 			if e != nil {
+				e.Time = time.Now()
 				// Non-blocking send
 				select {
 				case w.Events <- *e:
@@ -151,6 +270,25 @@ func (w *Watcher) classifyEvent(path string) *Event {
 
 	// Check for balls.jsonl
 	if base == "balls.jsonl" {
+		e := &Event{
+			Type: BallsChanged,
+			Path: path,
+		}
+		if changed, removed, ok := w.diffBalls(path); ok {
+			if len(changed) == 0 && len(removed) == 0 {
+				// Rewritten with the same balls
+				return nil
+			}
+			e.BallIDs = changed
+			e.RemovedBallIDs = removed
+			e.Diffed = true
+		}
+		return e
+	}
+
+	// balls.db (the bolt store backend) can't be diffed, so every ball is
+	// reloaded
+	if base == "balls.db" {
 		return &Event{
 			Type: BallsChanged,
 			Path: path,
@@ -184,9 +322,108 @@ func (w *Watcher) classifyEvent(path string) *Event {
 		}
 	}
 
+	// Check for an agent taking or releasing a session's lock
+	if base == agentLockInfoFile && strings.Contains(path, "sessions") {
+		return &Event{
+			Type:      AgentChanged,
+			Path:      path,
+			SessionID: filepath.Base(filepath.Dir(path)),
+		}
+	}
+
+	// New session directories need their own watch for their progress,
+	// session and lock files
+	if filepath.Base(filepath.Dir(path)) == "sessions" && filepath.Base(filepath.Dir(filepath.Dir(path))) == ".juggle" {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			_ = w.watcher.Add(path)
+			return nil
+		}
+	}
+
+	// Check for source file changes in a watched tree
+	if projectDir, ok := w.fileProject(path); ok {
+		// New directories need their own watch so nested changes are seen
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			if !skipDirs[base] {
+				_ = w.addTree(projectDir, path)
+			}
+			return nil
+		}
+		return &Event{
+			Type:       FilesChanged,
+			Path:       path,
+			ProjectDir: projectDir,
+		}
+	}
+
 	return nil
 }
 
+// diffBalls reads a balls.jsonl file and returns the IDs of balls whose line
+// is new or changed and of balls that are gone since it was last read. ok is
+// false if the file couldn't be read.
+func (w *Watcher) diffBalls(path string) (changed, removed []string, ok bool) {
+	hashes, err := hashBallLines(path)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	w.mu.Lock()
+	previous := w.ballHashes[path]
+	w.ballHashes[path] = hashes
+	w.mu.Unlock()
+
+	for id, hash := range hashes {
+		if old, seen := previous[id]; !seen || old != hash {
+			changed = append(changed, id)
+		}
+	}
+	for id := range previous {
+		if _, kept := hashes[id]; !kept {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return changed, removed, true
+}
+
+// hashBallLines returns a hash of each ball's line in a balls.jsonl file,
+// keyed by ball ID. Lines without an ID are skipped; if a ball appears more
+// than once, its last line wins.
+func hashBallLines(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]uint64)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var ball struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(line, &ball); err != nil || ball.ID == "" {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write(line)
+		hashes[ball.ID] = h.Sum64()
+	}
+	return hashes, nil
+}
+
+// fileProject returns the project dir for a path inside a watched source tree
+func (w *Watcher) fileProject(path string) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	projectDir, ok := w.watchedDirs[filepath.Dir(path)]
+	return projectDir, ok
+}
+
 // Stop stops the watcher
 func (w *Watcher) Stop() error {
 	w.mu.Lock()
@@ -3,6 +3,7 @@ package watcher
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -75,6 +76,47 @@ func TestClassifyEvent_BallsChanged(t *testing.T) {
 	}
 }
 
+func TestClassifyEvent_BallsDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	juggleDir := filepath.Join(tmpDir, ".juggle")
+	if err := os.MkdirAll(juggleDir, 0755); err != nil {
+		t.Fatalf("Failed to create juggle dir: %v", err)
+	}
+	ballsPath := filepath.Join(juggleDir, "balls.jsonl")
+	write := func(content string) {
+		if err := os.WriteFile(ballsPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write balls.jsonl: %v", err)
+		}
+	}
+	write("{\"id\":\"a\",\"state\":\"pending\"}\n{\"id\":\"b\",\"state\":\"pending\"}\n")
+
+	w, _ := New()
+	defer w.Close()
+	if err := w.WatchProject(tmpDir); err != nil {
+		t.Fatalf("Failed to watch project: %v", err)
+	}
+
+	// Rewriting the same balls is not a change
+	if event := w.classifyEvent(ballsPath); event != nil {
+		t.Errorf("Expected no event for unchanged balls, got %+v", event)
+	}
+
+	write("{\"id\":\"a\",\"state\":\"pending\"}\n{\"id\":\"b\",\"state\":\"complete\"}\n{\"id\":\"c\",\"state\":\"pending\"}\n")
+	event := w.classifyEvent(ballsPath)
+	if event == nil || !event.Diffed {
+		t.Fatalf("Expected a diffed event, got %+v", event)
+	}
+	if !reflect.DeepEqual(event.BallIDs, []string{"b", "c"}) || len(event.RemovedBallIDs) != 0 {
+		t.Errorf("Expected b and c changed, got %v (removed %v)", event.BallIDs, event.RemovedBallIDs)
+	}
+
+	write("{\"id\":\"c\",\"state\":\"pending\"}\n")
+	event = w.classifyEvent(ballsPath)
+	if event == nil || len(event.BallIDs) != 0 || !reflect.DeepEqual(event.RemovedBallIDs, []string{"a", "b"}) {
+		t.Errorf("Expected a and b removed, got %+v", event)
+	}
+}
+
 func TestClassifyEvent_ProgressChanged(t *testing.T) {
 	w, _ := New()
 	defer w.Close()
@@ -217,6 +259,63 @@ func TestWatcherProgressFileChange(t *testing.T) {
 	}
 }
 
+// TestWatcherAgentLockChange tests that agent runs taking and releasing a
+// session's lock are reported, including in sessions created after the
+// watch started
+func TestWatcherAgentLockChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	sessionsDir := filepath.Join(tmpDir, ".juggle", "sessions")
+	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
+		t.Fatalf("Failed to create sessions dir: %v", err)
+	}
+
+	w, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.WatchProject(tmpDir); err != nil {
+		t.Fatalf("Failed to watch project: %v", err)
+	}
+	w.Start()
+	time.Sleep(50 * time.Millisecond)
+
+	sessionDir := filepath.Join(sessionsDir, "new-session")
+	if err := os.Mkdir(sessionDir, 0755); err != nil {
+		t.Fatalf("Failed to create session dir: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	lockInfo := filepath.Join(sessionDir, agentLockInfoFile)
+	if err := os.WriteFile(lockInfo, []byte(`{"pid":1}`), 0644); err != nil {
+		t.Fatalf("Failed to write lock info: %v", err)
+	}
+	waitForAgentEvent(t, w, "new-session")
+
+	if err := os.Remove(lockInfo); err != nil {
+		t.Fatalf("Failed to remove lock info: %v", err)
+	}
+	waitForAgentEvent(t, w, "new-session")
+}
+
+// waitForAgentEvent waits for an AgentChanged event for a session, skipping
+// any others
+func waitForAgentEvent(t *testing.T, w *Watcher, sessionID string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event := <-w.Events:
+			if event.Type == AgentChanged && event.SessionID == sessionID {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for an agent event for %s", sessionID)
+		}
+	}
+}
+
 func TestWatcherStop(t *testing.T) {
 	w, err := New()
 	if err != nil {
@@ -234,3 +333,52 @@ func TestWatcherStop(t *testing.T) {
 		t.Errorf("Second stop should not error: %v", err)
 	}
 }
+
+func TestWatcherSourceFileChange(t *testing.T) {
+	tmpDir := t.TempDir()
+	authDir := filepath.Join(tmpDir, "src", "auth")
+	if err := os.MkdirAll(authDir, 0755); err != nil {
+		t.Fatalf("Failed to create src dir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "src", "node_modules"), 0755); err != nil {
+		t.Fatalf("Failed to create node_modules dir: %v", err)
+	}
+
+	w, err := New()
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer w.Close()
+
+	if err := w.WatchFiles(tmpDir, []string{"src", "missing"}); err != nil {
+		t.Fatalf("Failed to watch files: %v", err)
+	}
+	if _, ok := w.fileProject(filepath.Join(tmpDir, "src", "node_modules", "pkg.js")); ok {
+		t.Error("Expected node_modules to be skipped")
+	}
+
+	w.Start()
+
+	// Give the watcher time to start
+	time.Sleep(50 * time.Millisecond)
+
+	loginPath := filepath.Join(authDir, "login.go")
+	if err := os.WriteFile(loginPath, []byte("package auth"), 0644); err != nil {
+		t.Fatalf("Failed to write login.go: %v", err)
+	}
+
+	select {
+	case event := <-w.Events:
+		if event.Type != FilesChanged {
+			t.Errorf("Expected FilesChanged event, got %v", event.Type)
+		}
+		if event.Path != loginPath {
+			t.Errorf("Expected path %s, got %s", loginPath, event.Path)
+		}
+		if event.ProjectDir != tmpDir {
+			t.Errorf("Expected project dir %s, got %s", tmpDir, event.ProjectDir)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Timed out waiting for event")
+	}
+}